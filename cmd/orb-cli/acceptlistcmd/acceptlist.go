@@ -39,7 +39,7 @@ func GetCmd() *cobra.Command {
 		Long:         "Manages accept lists for 'Follow' and 'Invite' witness authorization handlers.",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return errors.New("expecting subcommand add, remove, or get")
+			return errors.New("expecting subcommand add, remove, get, or bootstrap")
 		},
 	}
 
@@ -47,6 +47,7 @@ func GetCmd() *cobra.Command {
 		newAddCmd(),
 		newRemoveCmd(),
 		newGetCmd(),
+		newBootstrapCmd(),
 	)
 
 	return cmd
@@ -0,0 +1,345 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package acceptlistcmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	fromFlagName  = "from"
+	fromFlagUsage = "The base URL of the peer Orb domain (for example, https://orb.domain1.com) whose followers and" +
+		" following are added to this node's accept list." +
+		" Alternatively, this can be set with the following environment variable: " + fromEnvKey
+	fromEnvKey = "ORB_CLI_FROM"
+
+	yesFlagName  = "yes"
+	yesFlagUsage = "If set to true then the proposed additions are applied without asking for confirmation." +
+		" Possible values [true] [false]. Defaults to false." +
+		" Alternatively, this can be set with the following environment variable: " + yesEnvKey
+	yesEnvKey = "ORB_CLI_YES"
+
+	selfRelation = "self"
+)
+
+type apClient interface {
+	ResolveActor(actorEndpoint string) (*vocab.ActorType, error)
+	GetCollection(collURI *url.URL) (*common.ActivityPubCollectionIterator, error)
+}
+
+type unresolvedActor struct {
+	actor string
+	err   error
+}
+
+func newBootstrapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Bootstraps an accept list from a peer's federation.",
+		Long: "Discovers the service actor of the given peer domain along with its followers and following and " +
+			"proposes adding the actors to this node's accept list. The proposed additions are printed and applied " +
+			"after confirmation (or immediately if --yes is set). Actors that can't be resolved are skipped and " +
+			"reported separately. For example: acceptlist bootstrap --from https://orb.domain1.com " +
+			"--url https://orb.domain2.com/acceptlist --type follow",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeBootstrap(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	cmd.Flags().StringP(typeFlagName, "", "", typeFlagUsage)
+	cmd.Flags().StringP(fromFlagName, "", "", fromFlagUsage)
+	cmd.Flags().StringP(yesFlagName, "", "", yesFlagUsage)
+
+	return cmd
+}
+
+func executeBootstrap(cmd *cobra.Command) error {
+	u, acceptType, from, yes, err := getBootstrapArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.NewActivityPubClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	peerActor, err := discoverServiceActor(cmd, from)
+	if err != nil {
+		return err
+	}
+
+	discovered, err := discoverFederation(client, peerActor)
+	if err != nil {
+		return err
+	}
+
+	resolved, unresolved := validateActors(client, discovered)
+
+	existing, err := getAcceptList(cmd, u, acceptType)
+	if err != nil {
+		return err
+	}
+
+	additions := difference(resolved, existing)
+
+	out := cmd.OutOrStdout()
+
+	printUnresolved(out, unresolved)
+
+	if len(additions) == 0 {
+		common.Println(out, "No new actors to add to the accept list.")
+
+		return nil
+	}
+
+	common.Printf(out, "Proposed additions to the '%s' accept list:\n", acceptType)
+
+	for _, actor := range additions {
+		common.Printf(out, "  %s\n", actor)
+	}
+
+	if !yes && !confirm(cmd.InOrStdin(), out) {
+		common.Println(out, "Accept list was not updated.")
+
+		return nil
+	}
+
+	reqBytes, err := json.Marshal([]acceptListRequest{{Type: acceptType, Add: additions}})
+	if err != nil {
+		return err
+	}
+
+	_, err = common.SendHTTPRequest(cmd, reqBytes, http.MethodPost, u)
+	if err != nil {
+		return err
+	}
+
+	common.Println(out, "Accept list has successfully been updated.")
+
+	return nil
+}
+
+func getBootstrapArgs(cmd *cobra.Command) (u, acceptType, from string, yes bool, err error) {
+	u, err = cmdutil.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+	if err != nil {
+		return "", "", "", false, err
+	}
+
+	_, err = url.Parse(u)
+	if err != nil {
+		return "", "", "", false, fmt.Errorf("invalid URL %s: %w", u, err)
+	}
+
+	acceptType, err = cmdutil.GetUserSetVarFromString(cmd, typeFlagName, typeEnvKey, false)
+	if err != nil {
+		return "", "", "", false, err
+	}
+
+	from, err = cmdutil.GetUserSetVarFromString(cmd, fromFlagName, fromEnvKey, false)
+	if err != nil {
+		return "", "", "", false, err
+	}
+
+	_, err = url.Parse(from)
+	if err != nil {
+		return "", "", "", false, fmt.Errorf("invalid %s URL %s: %w", fromFlagName, from, err)
+	}
+
+	yes, err = cmdutil.GetBool(cmd, yesFlagName, yesEnvKey, false)
+	if err != nil {
+		return "", "", "", false, fmt.Errorf("%s: %w", yesFlagName, err)
+	}
+
+	return u, acceptType, strings.TrimSuffix(from, "/"), yes, nil
+}
+
+// discoverServiceActor returns the ActivityPub service IRI of the given domain from its host-meta document.
+func discoverServiceActor(cmd *cobra.Command, domain string) (string, error) {
+	resp, err := common.SendHTTPRequest(cmd, nil, http.MethodGet, domain+restapi.HostMetaJSONEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("get host-meta from %s: %w", domain, err)
+	}
+
+	jrd := &restapi.JRD{}
+
+	err = json.Unmarshal(resp, jrd)
+	if err != nil {
+		return "", fmt.Errorf("unmarshal host-meta from %s: %w", domain, err)
+	}
+
+	for _, link := range jrd.Links {
+		if link.Rel == selfRelation && link.Type == restapi.ActivityJSONType && link.Href != "" {
+			return link.Href, nil
+		}
+	}
+
+	return "", fmt.Errorf("ActivityPub service not found in host-meta of %s", domain)
+}
+
+// discoverFederation returns the given peer actor along with the actors in its followers and following
+// collections. The returned actors are unique and sorted.
+func discoverFederation(client apClient, peerActor string) ([]string, error) {
+	actor, err := client.ResolveActor(peerActor)
+	if err != nil {
+		return nil, fmt.Errorf("resolve peer actor %s: %w", peerActor, err)
+	}
+
+	actors := map[string]struct{}{peerActor: {}}
+
+	for _, collURI := range []*url.URL{actor.Followers(), actor.Following()} {
+		if collURI == nil {
+			continue
+		}
+
+		err = collectActors(client, collURI, actors)
+		if err != nil {
+			return nil, fmt.Errorf("get collection %s: %w", collURI, err)
+		}
+	}
+
+	result := make([]string, 0, len(actors))
+
+	for a := range actors {
+		result = append(result, a)
+	}
+
+	sort.Strings(result)
+
+	return result, nil
+}
+
+func collectActors(client apClient, collURI *url.URL, actors map[string]struct{}) error {
+	it, err := client.GetCollection(collURI)
+	if err != nil {
+		return err
+	}
+
+	for {
+		item, err := it.Next()
+		if err != nil {
+			if errors.Is(err, orberrors.ErrContentNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		if item != nil {
+			actors[item.String()] = struct{}{}
+		}
+	}
+}
+
+// validateActors resolves each of the given actors and returns the actors that could be resolved
+// along with the actors that could not be resolved.
+func validateActors(client apClient, actors []string) ([]string, []*unresolvedActor) {
+	var (
+		resolved   []string
+		unresolved []*unresolvedActor
+	)
+
+	for _, actor := range actors {
+		_, err := client.ResolveActor(actor)
+		if err != nil {
+			unresolved = append(unresolved, &unresolvedActor{actor: actor, err: err})
+
+			continue
+		}
+
+		resolved = append(resolved, actor)
+	}
+
+	return resolved, unresolved
+}
+
+func getAcceptList(cmd *cobra.Command, u, acceptType string) ([]string, error) {
+	resp, err := common.SendHTTPRequest(cmd, nil, http.MethodGet, fmt.Sprintf("%s?type=%s", u, acceptType))
+	if err != nil {
+		return nil, fmt.Errorf("get accept list: %w", err)
+	}
+
+	al := &acceptList{}
+
+	err = json.Unmarshal(resp, al)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal accept list: %w", err)
+	}
+
+	return al.URLs, nil
+}
+
+func difference(actors, existing []string) []string {
+	existingMap := make(map[string]struct{}, len(existing))
+
+	for _, actor := range existing {
+		existingMap[actor] = struct{}{}
+	}
+
+	var result []string
+
+	for _, actor := range actors {
+		if _, ok := existingMap[actor]; !ok {
+			result = append(result, actor)
+		}
+	}
+
+	return result
+}
+
+func printUnresolved(out io.Writer, unresolved []*unresolvedActor) {
+	if len(unresolved) == 0 {
+		return
+	}
+
+	common.Println(out, "The following actors could not be resolved and were skipped:")
+
+	for _, u := range unresolved {
+		common.Printf(out, "  %s: %s\n", u.actor, u.err)
+	}
+}
+
+func confirm(in io.Reader, out io.Writer) bool {
+	common.Printf(out, "Apply these additions? [y/N]: ")
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+type acceptList struct {
+	Type string   `json:"type"`
+	URLs []string `json:"url"`
+}
@@ -0,0 +1,265 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package acceptlistcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	acceptListPath = "/acceptlist"
+	servicePath    = "/services/orb"
+)
+
+func TestBootstrapCmd(t *testing.T) {
+	t.Run("test missing from arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"bootstrap"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, typeArg("follow")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither from (command line flag) nor ORB_CLI_FROM (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid from arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"bootstrap"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, typeArg("follow")...)
+		args = append(args, fromArg(":invalid")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid from URL")
+	})
+
+	t.Run("test invalid yes arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"bootstrap"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, typeArg("follow")...)
+		args = append(args, fromArg("https://orb.domain1.com")...)
+		args = append(args, flag+yesFlagName, "maybe")
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "yes:")
+	})
+
+	t.Run("--yes -> success", func(t *testing.T) {
+		peer := newMockPeer(t)
+		defer peer.Close()
+
+		out, err := runBootstrap(t, peer, nil, flag+yesFlagName, "true")
+		require.NoError(t, err)
+
+		// The peer and its follower are added. The followed actor is already in the accept list and
+		// the missing actor can't be resolved.
+		require.Equal(t, []string{peer.actorIRI("orb"), peer.actorIRI("orb2")}, peer.added())
+
+		require.Contains(t, out, "could not be resolved and were skipped")
+		require.Contains(t, out, peer.actorIRI("missing"))
+		require.Contains(t, out, "Accept list has successfully been updated.")
+	})
+
+	t.Run("confirmed -> success", func(t *testing.T) {
+		peer := newMockPeer(t)
+		defer peer.Close()
+
+		out, err := runBootstrap(t, peer, strings.NewReader("y\n"))
+		require.NoError(t, err)
+		require.Contains(t, out, "Apply these additions?")
+		require.Len(t, peer.added(), 2)
+	})
+
+	t.Run("not confirmed -> not applied", func(t *testing.T) {
+		peer := newMockPeer(t)
+		defer peer.Close()
+
+		out, err := runBootstrap(t, peer, strings.NewReader("n\n"))
+		require.NoError(t, err)
+		require.Contains(t, out, "Proposed additions to the 'follow' accept list")
+		require.Contains(t, out, "Accept list was not updated.")
+		require.Empty(t, peer.added())
+	})
+
+	t.Run("no new actors", func(t *testing.T) {
+		peer := newMockPeer(t)
+		defer peer.Close()
+
+		peer.existing = append(peer.existing, peer.actorIRI("orb"), peer.actorIRI("orb2"))
+
+		out, err := runBootstrap(t, peer, nil, flag+yesFlagName, "true")
+		require.NoError(t, err)
+		require.Contains(t, out, "No new actors to add to the accept list.")
+		require.Empty(t, peer.added())
+	})
+
+	t.Run("host-meta without ActivityPub service", func(t *testing.T) {
+		peer := newMockPeer(t)
+		defer peer.Close()
+
+		peer.hostMeta = `{"links":[]}`
+
+		_, err := runBootstrap(t, peer, nil, flag+yesFlagName, "true")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ActivityPub service not found in host-meta")
+	})
+
+	t.Run("peer actor not resolvable", func(t *testing.T) {
+		peer := newMockPeer(t)
+		defer peer.Close()
+
+		peer.hostMeta = fmt.Sprintf(`{"links":[{"rel":"self","type":"application/activity+json","href":"%s"}]}`,
+			peer.actorIRI("missing"))
+
+		_, err := runBootstrap(t, peer, nil, flag+yesFlagName, "true")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve peer actor")
+	})
+}
+
+func runBootstrap(t *testing.T, peer *mockPeer, in io.Reader, extraArgs ...string) (string, error) {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+
+	cmd := GetCmd()
+	cmd.SetOut(out)
+
+	if in != nil {
+		cmd.SetIn(in)
+	}
+
+	args := []string{"bootstrap"}
+	args = append(args, urlArg(peer.URL+acceptListPath)...)
+	args = append(args, typeArg("follow")...)
+	args = append(args, fromArg(peer.URL)...)
+	args = append(args, extraArgs...)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func fromArg(value string) []string {
+	return []string{flag + fromFlagName, value}
+}
+
+// mockPeer serves the host-meta document, ActivityPub actors and collections of a peer domain along
+// with the accept list REST endpoint of this node.
+type mockPeer struct {
+	*httptest.Server
+
+	t        *testing.T
+	hostMeta string
+	existing []string
+
+	mutex    sync.Mutex
+	requests []acceptListRequest
+}
+
+func newMockPeer(t *testing.T) *mockPeer {
+	t.Helper()
+
+	p := &mockPeer{t: t}
+
+	p.Server = httptest.NewServer(http.HandlerFunc(p.handle))
+
+	p.hostMeta = fmt.Sprintf(`{"links":[{"rel":"self","type":"application/activity+json","href":"%s"}]}`,
+		p.actorIRI("orb"))
+	p.existing = []string{p.actorIRI("orb3")}
+
+	return p
+}
+
+func (p *mockPeer) actorIRI(name string) string {
+	return p.URL + "/services/" + name
+}
+
+func (p *mockPeer) added() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var added []string
+
+	for _, req := range p.requests {
+		require.Equal(p.t, "follow", req.Type)
+
+		added = append(added, req.Add...)
+	}
+
+	return added
+}
+
+func (p *mockPeer) handle(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/.well-known/host-meta.json":
+		p.write(w, p.hostMeta)
+	case r.URL.Path == acceptListPath && r.Method == http.MethodGet:
+		p.write(w, fmt.Sprintf(`{"type":"follow","url":["%s"]}`, strings.Join(p.existing, `","`)))
+	case r.URL.Path == acceptListPath && r.Method == http.MethodPost:
+		var reqs []acceptListRequest
+
+		require.NoError(p.t, json.NewDecoder(r.Body).Decode(&reqs))
+
+		p.mutex.Lock()
+		p.requests = append(p.requests, reqs...)
+		p.mutex.Unlock()
+	case r.URL.Path == servicePath+"/followers":
+		p.writeCollection(w, r, servicePath+"/followers", p.actorIRI("orb2"))
+	case r.URL.Path == servicePath+"/following":
+		p.writeCollection(w, r, servicePath+"/following", p.actorIRI("orb3"), p.actorIRI("missing"))
+	case r.URL.Path == servicePath, r.URL.Path == "/services/orb2", r.URL.Path == "/services/orb3":
+		p.write(w, fmt.Sprintf(`{"@context":"https://www.w3.org/ns/activitystreams","id":"%[1]s%[2]s",`+
+			`"type":"Service","followers":"%[1]s%[2]s/followers","following":"%[1]s%[2]s/following"}`,
+			p.URL, r.URL.Path))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (p *mockPeer) writeCollection(w http.ResponseWriter, r *http.Request, path string, items ...string) {
+	if r.URL.Query().Get("page") != "true" {
+		p.write(w, fmt.Sprintf(`{"@context":"https://www.w3.org/ns/activitystreams","id":"%[1]s%[2]s",`+
+			`"type":"Collection","totalItems":%[3]d,"first":"%[1]s%[2]s?page=true"}`, p.URL, path, len(items)))
+
+		return
+	}
+
+	p.write(w, fmt.Sprintf(`{"@context":"https://www.w3.org/ns/activitystreams","id":"%s%s?page=true",`+
+		`"type":"CollectionPage","totalItems":%d,"items":["%s"]}`, p.URL, path, len(items),
+		strings.Join(items, `","`)))
+}
+
+func (p *mockPeer) write(w http.ResponseWriter, body string) {
+	_, err := fmt.Fprint(w, body)
+	require.NoError(p.t, err)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorcmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	casURLFlagName  = "cas-url"
+	casURLEnvKey    = "ORB_CAS_URL"
+	casURLFlagUsage = "The URL of the CAS endpoint. For example, https://orb.domain1.com/cas." +
+		" Alternatively, this can be set with the following environment variable: " + casURLEnvKey
+
+	hashlinkFlagName  = "hashlink"
+	hashlinkEnvKey    = "ORB_CLI_HASHLINK"
+	hashlinkFlagUsage = "The hashlink of the anchor linkset." +
+		" Alternatively, this can be set with the following environment variable: " + hashlinkEnvKey
+)
+
+// GetCmd returns the Cobra anchors command.
+func GetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "anchors",
+		Short:        "Examines anchors stored in CAS and imports anchors.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("expecting subcommand: verify, list-dids or import")
+		},
+	}
+
+	cmd.AddCommand(
+		newVerifyCmd(),
+		newListDIDsCmd(),
+		newImportCmd(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,322 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorcmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/anchor/anchorimport"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+const (
+	urlFlagName  = "url"
+	urlEnvKey    = "ORB_CLI_URL"
+	urlFlagUsage = "The URL of the anchor import endpoint. For example, https://orb.domain1.com/anchors/import." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+
+	dirFlagName  = "dir"
+	dirEnvKey    = "ORB_CLI_ANCHOR_DIR"
+	dirFlagUsage = "The directory which contains the anchor linkset files to import (one anchor linkset per file)." +
+		" Alternatively, this can be set with the following environment variable: " + dirEnvKey
+)
+
+// importStatusFailed indicates that the anchor couldn't be imported due to an error (other than the anchor
+// being invalid), e.g. the server couldn't be reached. The import may be run again to retry.
+const importStatusFailed anchorimport.Status = "failed"
+
+var errImportFailed = errors.New("one or more anchors were rejected or failed to import")
+
+// anchorFile is an anchor linkset file to be imported.
+type anchorFile struct {
+	name    string
+	content []byte
+	hash    string
+	parents []string
+}
+
+// rejectedFile is a file which doesn't contain a valid anchor linkset.
+type rejectedFile struct {
+	name   string
+	reason string
+}
+
+type importStats struct {
+	imported int
+	skipped  int
+	rejected int
+	failed   int
+}
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Imports anchor linksets which were produced by another system.",
+		Long: "Loads the anchor linkset files in the given directory and posts each of them to the anchor import " +
+			"endpoint of a server (which must be enabled on the server). The server validates each anchor, stores " +
+			"it in its local CAS and processes it. An anchor is imported after any of its parents which are also " +
+			"in the directory. The result for each file (imported, skipped if the anchor is already present, " +
+			"rejected if the anchor is invalid or failed) is output. For example: anchors import " +
+			"--url https://orb.domain1.com/anchors/import --dir ./anchors",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeImport(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	cmd.Flags().StringP(dirFlagName, "", "", dirFlagUsage)
+
+	return cmd
+}
+
+func executeImport(cmd *cobra.Command) error {
+	importURL, dir, err := getImportArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := common.NewHTTPClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	files, rejected, err := loadAnchorFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+
+	stats := &importStats{rejected: len(rejected)}
+
+	for _, f := range rejected {
+		common.Printf(out, "%s: %s - %s\n", f.name, anchorimport.StatusRejected, f.reason)
+	}
+
+	headers := common.NewAuthTokenHeader(cmd)
+
+	for _, f := range orderByLineage(files) {
+		result := importAnchor(ctx, httpClient, headers, importURL, f)
+
+		switch result.Status {
+		case anchorimport.StatusImported:
+			stats.imported++
+
+			common.Printf(out, "%s: %s (%s)\n", f.name, result.Status, result.Hashlink)
+		case anchorimport.StatusSkipped:
+			stats.skipped++
+
+			common.Printf(out, "%s: %s (%s) - already present\n", f.name, result.Status, result.Hashlink)
+		case anchorimport.StatusRejected:
+			stats.rejected++
+
+			common.Printf(out, "%s: %s - %s\n", f.name, result.Status, result.Reason)
+		default:
+			stats.failed++
+
+			common.Printf(out, "%s: %s - %s\n", f.name, importStatusFailed, result.Reason)
+		}
+	}
+
+	common.Printf(out, "Imported %d anchor(s): %d skipped, %d rejected, %d failed\n",
+		stats.imported, stats.skipped, stats.rejected, stats.failed)
+
+	if stats.rejected > 0 || stats.failed > 0 {
+		return errImportFailed
+	}
+
+	return nil
+}
+
+func importAnchor(ctx context.Context, httpClient *http.Client, headers map[string]string, importURL string,
+	f *anchorFile,
+) *anchorimport.Result {
+	respBytes, err := common.SendRequestWithContext(ctx, httpClient, f.content, headers, http.MethodPost, importURL)
+	if err != nil {
+		return &anchorimport.Result{Status: importStatusFailed, Reason: err.Error()}
+	}
+
+	result := &anchorimport.Result{}
+
+	err = json.Unmarshal(respBytes, result)
+	if err != nil {
+		return &anchorimport.Result{Status: importStatusFailed, Reason: fmt.Sprintf("invalid response: %s", err)}
+	}
+
+	return result
+}
+
+// loadAnchorFiles loads the anchor linkset files in the given directory. The files which don't contain a valid
+// anchor linkset are returned separately along with the reason.
+func loadAnchorFiles(dir string) ([]*anchorFile, []*rejectedFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read directory %s: %w", dir, err)
+	}
+
+	var (
+		files    []*anchorFile
+		rejected []*rejectedFile
+	)
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		f, err := loadAnchorFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			rejected = append(rejected, &rejectedFile{name: entry.Name(), reason: err.Error()})
+
+			continue
+		}
+
+		f.name = entry.Name()
+
+		files = append(files, f)
+	}
+
+	return files, rejected, nil
+}
+
+func loadAnchorFile(path string) (*anchorFile, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	canonicalBytes, err := canonicalizer.MarshalCanonical(content)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize anchor: %w", err)
+	}
+
+	anchorLinkset := &linkset.Linkset{}
+
+	err = json.Unmarshal(canonicalBytes, anchorLinkset)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal anchor linkset: %w", err)
+	}
+
+	anchorLink := anchorLinkset.Link()
+	if anchorLink == nil {
+		return nil, errors.New("anchor linkset is empty")
+	}
+
+	hash, err := hashlink.New().CreateResourceHash(canonicalBytes)
+	if err != nil {
+		return nil, fmt.Errorf("create resource hash: %w", err)
+	}
+
+	return &anchorFile{
+		content: content,
+		hash:    hash,
+		parents: getParentHashes(anchorLink),
+	}, nil
+}
+
+// getParentHashes returns the resource hashes of the parents of the given anchor. Errors are ignored since the
+// parents are only used to order the import and the anchor is fully validated by the server.
+func getParentHashes(anchorLink *linkset.Link) []string {
+	if anchorLink.Related() == nil {
+		return nil
+	}
+
+	relatedLinkset, err := anchorLink.Related().Linkset()
+	if err != nil || relatedLinkset.Link() == nil {
+		return nil
+	}
+
+	var parents []string
+
+	for _, parentHL := range relatedLinkset.Link().Up() {
+		hash, err := hashlink.GetResourceHashFromHashLink(parentHL.String())
+		if err != nil {
+			continue
+		}
+
+		parents = append(parents, hash)
+	}
+
+	return parents
+}
+
+// orderByLineage orders the given anchors so that an anchor comes after any of its parents in the set. Otherwise,
+// the original order is preserved.
+func orderByLineage(files []*anchorFile) []*anchorFile {
+	byHash := make(map[string]*anchorFile, len(files))
+
+	for _, f := range files {
+		byHash[f.hash] = f
+	}
+
+	ordered := make([]*anchorFile, 0, len(files))
+	visited := make(map[*anchorFile]bool, len(files))
+
+	var visit func(f *anchorFile)
+
+	visit = func(f *anchorFile) {
+		if visited[f] {
+			return
+		}
+
+		visited[f] = true
+
+		for _, parentHash := range f.parents {
+			if parent, ok := byHash[parentHash]; ok {
+				visit(parent)
+			}
+		}
+
+		ordered = append(ordered, f)
+	}
+
+	for _, f := range files {
+		visit(f)
+	}
+
+	return ordered
+}
+
+func getImportArgs(cmd *cobra.Command) (importURL, dir string, err error) {
+	importURL, err = cmdutil.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	_, err = url.Parse(importURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid URL %s: %w", importURL, err)
+	}
+
+	dir, err = cmdutil.GetUserSetVarFromString(cmd, dirFlagName, dirEnvKey, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	return importURL, dir, nil
+}
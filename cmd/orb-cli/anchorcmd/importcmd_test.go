@@ -0,0 +1,278 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/anchorimport"
+	"github.com/trustbloc/orb/pkg/datauri"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+func TestImportCmd(t *testing.T) {
+	t.Run("missing url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"import", flag + dirFlagName, t.TempDir()})
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.")
+	})
+
+	t.Run("missing dir arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"import", flag + urlFlagName, "https://localhost:8080/anchors/import"})
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither dir (command line flag) nor ORB_CLI_ANCHOR_DIR (environment variable) have been set.")
+	})
+
+	t.Run("directory not found", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"import", flag + urlFlagName, "https://localhost:8080/anchors/import",
+			flag + dirFlagName, filepath.Join(t.TempDir(), "invalid")})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read directory")
+	})
+
+	t.Run("success -> parents imported first", func(t *testing.T) {
+		dir := t.TempDir()
+
+		parent := newTestAnchorLinkset(t)
+		child := newTestAnchorLinkset(t, parent)
+
+		// The child is named so that it's listed before the parent.
+		writeAnchorFile(t, dir, "1-child.json", child)
+		writeAnchorFile(t, dir, "2-parent.json", parent)
+
+		server := newTestImportServer(t, nil)
+
+		out, err := executeImportCmd(t, server.url(), dir)
+		require.NoError(t, err)
+		require.Contains(t, out, "1-child.json: imported ("+anchorHashlink(t, child)+")")
+		require.Contains(t, out, "2-parent.json: imported ("+anchorHashlink(t, parent)+")")
+		require.Contains(t, out, "Imported 2 anchor(s): 0 skipped, 0 rejected, 0 failed")
+
+		require.Equal(t, []string{anchorHashlink(t, parent), anchorHashlink(t, child)}, server.imported())
+	})
+
+	t.Run("skipped and rejected anchors", func(t *testing.T) {
+		dir := t.TempDir()
+
+		anchor1 := newTestAnchorLinkset(t)
+		anchor2 := newTestAnchorLinkset(t, anchor1)
+		anchor3 := newTestAnchorLinkset(t, anchor2)
+
+		writeAnchorFile(t, dir, "anchor1.json", anchor1)
+		writeAnchorFile(t, dir, "anchor2.json", anchor2)
+		writeAnchorFile(t, dir, "anchor3.json", anchor3)
+		writeAnchorFile(t, dir, "invalid.json", []byte(`{"linkset":[]}`))
+
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "subdir"), os.ModePerm))
+
+		server := newTestImportServer(t, map[string]anchorimport.Status{
+			anchorHashlink(t, anchor1): anchorimport.StatusSkipped,
+			anchorHashlink(t, anchor3): anchorimport.StatusRejected,
+		})
+
+		out, err := executeImportCmd(t, server.url(), dir)
+		require.ErrorIs(t, err, errImportFailed)
+		require.Contains(t, out, "anchor1.json: skipped ("+anchorHashlink(t, anchor1)+") - already present")
+		require.Contains(t, out, "anchor2.json: imported ("+anchorHashlink(t, anchor2)+")")
+		require.Contains(t, out, "anchor3.json: rejected - injected rejection")
+		require.Contains(t, out, "invalid.json: rejected - anchor linkset is empty")
+		require.NotContains(t, out, "subdir")
+		require.Contains(t, out, "Imported 1 anchor(s): 1 skipped, 2 rejected, 0 failed")
+	})
+
+	t.Run("server error -> failed", func(t *testing.T) {
+		dir := t.TempDir()
+
+		writeAnchorFile(t, dir, "anchor.json", newTestAnchorLinkset(t))
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		out, err := executeImportCmd(t, server.URL, dir)
+		require.ErrorIs(t, err, errImportFailed)
+		require.Contains(t, out, "anchor.json: failed - got unexpected response")
+		require.Contains(t, out, "Imported 0 anchor(s): 0 skipped, 0 rejected, 1 failed")
+	})
+
+	t.Run("invalid response -> failed", func(t *testing.T) {
+		dir := t.TempDir()
+
+		writeAnchorFile(t, dir, "anchor.json", newTestAnchorLinkset(t))
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte("{"))
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		out, err := executeImportCmd(t, server.URL, dir)
+		require.ErrorIs(t, err, errImportFailed)
+		require.Contains(t, out, "anchor.json: failed - invalid response")
+	})
+}
+
+func executeImportCmd(t *testing.T, importURL, dir string) (string, error) {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+
+	cmd := GetCmd()
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"import", flag + urlFlagName, importURL, flag + dirFlagName, dir})
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+type testImportServer struct {
+	server          *httptest.Server
+	statuses        map[string]anchorimport.Status
+	mutex           sync.Mutex
+	importedAnchors []string
+}
+
+// newTestImportServer returns a server which responds with the given status for an anchor (keyed by hashlink).
+// If no status is specified for an anchor then the anchor is imported.
+func newTestImportServer(t *testing.T, statuses map[string]anchorimport.Status) *testImportServer {
+	t.Helper()
+
+	s := &testImportServer{statuses: statuses}
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		anchorBytes, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		hl := anchorHashlink(t, anchorBytes)
+
+		result := &anchorimport.Result{Hashlink: hl, Status: anchorimport.StatusImported}
+
+		if status, ok := s.statuses[hl]; ok {
+			result.Status = status
+
+			if status == anchorimport.StatusRejected {
+				result.Reason = "injected rejection"
+			}
+		}
+
+		if result.Status == anchorimport.StatusImported {
+			s.mutex.Lock()
+			s.importedAnchors = append(s.importedAnchors, hl)
+			s.mutex.Unlock()
+		}
+
+		resultBytes, err := json.Marshal(result)
+		require.NoError(t, err)
+
+		_, err = w.Write(resultBytes)
+		require.NoError(t, err)
+	}))
+
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+func (s *testImportServer) url() string {
+	return s.server.URL
+}
+
+func (s *testImportServer) imported() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.importedAnchors
+}
+
+// newTestAnchorLinkset returns a new anchor linkset whose parents are the given anchor linksets.
+func newTestAnchorLinkset(t *testing.T, parents ...[]byte) []byte {
+	t.Helper()
+
+	author := vocab.MustParseURL("https://orb.domain1.com/services/orb")
+	profile := vocab.MustParseURL("https://w3id.org/orb#v0")
+
+	var (
+		items []*linkset.Item
+		up    []*url.URL
+	)
+
+	for _, parent := range parents {
+		parentHL := vocab.MustParseURL(anchorHashlink(t, parent))
+
+		items = append(items, linkset.NewItem(
+			vocab.MustParseURL("did:orb:uEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw:"+updateSuffix), parentHL))
+		up = append(up, parentHL)
+	}
+
+	if len(parents) == 0 {
+		items = append(items, linkset.NewItem(vocab.MustParseURL("did:orb:uAAA:"+createSuffix), nil))
+	}
+
+	contentBytes, err := json.Marshal(linkset.New(linkset.NewAnchorLink(
+		vocab.MustParseURL("hl:uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg"), author, profile, items)))
+	require.NoError(t, err)
+
+	anchor, originalRef, err := linkset.NewAnchorRef(contentBytes, datauri.MediaTypeDataURIJSON, linkset.TypeLinkset)
+	require.NoError(t, err)
+
+	relatedBytes, err := json.Marshal(linkset.New(linkset.NewRelatedLink(anchor, profile,
+		vocab.MustParseURL("https://orb.domain1.com/cas/"+anchor.String()), up...)))
+	require.NoError(t, err)
+
+	_, relatedRef, err := linkset.NewAnchorRef(relatedBytes, datauri.MediaTypeDataURIJSON, linkset.TypeLinkset)
+	require.NoError(t, err)
+
+	anchorLinksetBytes, err := json.Marshal(linkset.New(
+		linkset.NewLink(anchor, author, profile, originalRef, relatedRef, nil)),
+	)
+	require.NoError(t, err)
+
+	return anchorLinksetBytes
+}
+
+func anchorHashlink(t *testing.T, anchorLinksetBytes []byte) string {
+	t.Helper()
+
+	canonicalBytes, err := canonicalizer.MarshalCanonical(anchorLinksetBytes)
+	require.NoError(t, err)
+
+	hash, err := hashlink.New().CreateResourceHash(canonicalBytes)
+	require.NoError(t, err)
+
+	return hashlink.GetHashLinkFromResourceHash(hash)
+}
+
+func writeAnchorFile(t *testing.T, dir, name string, content []byte) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0o600))
+}
@@ -0,0 +1,376 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorcmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+const (
+	operationCreate     = "create"
+	operationUpdate     = "update"
+	operationRecover    = "recover"
+	operationDeactivate = "deactivate"
+	// operationUnknown is reported for a DID which has a previous anchor when the core index file of the anchor
+	// can't be read, since only the core index file distinguishes between an update, recover and deactivate.
+	operationUnknown = "update|recover|deactivate"
+)
+
+type anchoredDID struct {
+	DID       string `json:"did"`
+	Suffix    string `json:"suffix"`
+	Operation string `json:"operation"`
+	Previous  string `json:"previous,omitempty"`
+}
+
+type listDIDsResult struct {
+	Hashlink  string         `json:"hashlink"`
+	Source    string         `json:"source"`
+	CoreIndex string         `json:"coreIndex,omitempty"`
+	DIDs      []*anchoredDID `json:"dids"`
+	Warnings  []string       `json:"warnings,omitempty"`
+}
+
+// coreIndexFile contains the fields of a Sidetree core index file which are required in order to determine
+// the type of an operation.
+type coreIndexFile struct {
+	Operations *struct {
+		Recover    []operationReference `json:"recover,omitempty"`
+		Deactivate []operationReference `json:"deactivate,omitempty"`
+	} `json:"operations,omitempty"`
+}
+
+type operationReference struct {
+	DIDSuffix string `json:"didSuffix"`
+}
+
+func newListDIDsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-dids",
+		Short: "Lists the DIDs anchored by an anchor.",
+		Long: `Loads the anchor linkset and outputs the suffix, operation type and previous anchor of each DID ` +
+			`in the anchor. The anchor linkset is loaded from the given CAS URL (if any) and, if it's not found, from ` +
+			`the links in the hashlink. The type of an operation (other than create) is determined from the core ` +
+			`index file of the anchor. If the core index file can't be loaded then a warning is output and the ` +
+			`operation type is reported as "update|recover|deactivate". For example: anchors list-dids ` +
+			`--cas-url https://orb.domain1.com/cas --hashlink hl:uEiDuIicNljP8PoHJk6_aA7w1d4U3FAvDMfF7Dsh7fkw3Wg`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeListDIDs(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(casURLFlagName, "", "", casURLFlagUsage)
+	cmd.Flags().StringP(hashlinkFlagName, "", "", hashlinkFlagUsage)
+
+	return cmd
+}
+
+func executeListDIDs(cmd *cobra.Command) error {
+	casURL, hl, err := getListDIDsArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	r := &contentReader{cmd: cmd, casURL: casURL}
+
+	content, source, err := r.read(hl, nil)
+	if err != nil {
+		return fmt.Errorf("read anchor linkset: %w", err)
+	}
+
+	result, err := listDIDs(r, hl, source, content)
+	if err != nil {
+		return err
+	}
+
+	resultBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal result: %w", err)
+	}
+
+	common.Println(cmd.OutOrStdout(), string(resultBytes))
+
+	return nil
+}
+
+func listDIDs(r *contentReader, hl, source string, content []byte) (*listDIDsResult, error) {
+	anchorLinkset := &linkset.Linkset{}
+
+	if err := json.Unmarshal(content, anchorLinkset); err != nil {
+		return nil, fmt.Errorf("unmarshal anchor linkset: %w", err)
+	}
+
+	anchorLink := anchorLinkset.Link()
+	if anchorLink == nil {
+		return nil, errors.New("anchor linkset contains no links")
+	}
+
+	if anchorLink.Original() == nil {
+		return nil, errors.New("anchor linkset has no original content")
+	}
+
+	contentLinkset, err := anchorLink.Original().Linkset()
+	if err != nil {
+		return nil, fmt.Errorf("anchor linkset can't be fully read: invalid original content: %w", err)
+	}
+
+	contentLink := contentLinkset.Link()
+	if contentLink == nil {
+		return nil, errors.New("anchor linkset can't be fully read: original content contains no links")
+	}
+
+	result := &listDIDsResult{
+		Hashlink: hl,
+		Source:   source,
+		DIDs:     []*anchoredDID{},
+	}
+
+	var coreOps map[string]string
+
+	if contentLink.Anchor() != nil {
+		result.CoreIndex = contentLink.Anchor().String()
+
+		coreOps, err = r.readCoreOperations(result.CoreIndex, getVia(anchorLink))
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"The operation type of DIDs with a previous anchor can't be determined: %s", err))
+		}
+	}
+
+	for _, item := range contentLink.Items() {
+		if item.HRef() == nil {
+			return nil, errors.New("anchor linkset can't be fully read: item has no DID")
+		}
+
+		suffix, err := util.GetSuffix(item.HRef().String())
+		if err != nil {
+			return nil, fmt.Errorf("get suffix from DID [%s]: %w", item.HRef(), err)
+		}
+
+		did := &anchoredDID{
+			DID:       item.HRef().String(),
+			Suffix:    suffix,
+			Operation: getOperationType(suffix, item.Previous(), coreOps),
+		}
+
+		if item.Previous() != nil {
+			did.Previous = item.Previous().String()
+		}
+
+		result.DIDs = append(result.DIDs, did)
+	}
+
+	return result, nil
+}
+
+// getOperationType returns the type of the operation for the given suffix. An item without a previous anchor
+// is a create. Otherwise the operation is a recover or deactivate if it's listed as such in the core index file
+// and an update if it's not. If the core index file isn't available then the type can't be determined.
+func getOperationType(suffix string, previous *url.URL, coreOps map[string]string) string {
+	if previous == nil {
+		return operationCreate
+	}
+
+	if coreOps == nil {
+		return operationUnknown
+	}
+
+	if op, ok := coreOps[suffix]; ok {
+		return op
+	}
+
+	return operationUpdate
+}
+
+// getVia returns the "via" hashlink of the related linkset which contains the links to the core index file.
+func getVia(anchorLink *linkset.Link) *url.URL {
+	if anchorLink.Related() == nil {
+		return nil
+	}
+
+	relatedLinkset, err := anchorLink.Related().Linkset()
+	if err != nil {
+		return nil
+	}
+
+	return relatedLinkset.Link().Via()
+}
+
+// contentReader reads content from the CAS URL or, if the content isn't found there, from the links in the
+// metadata of the hashlink.
+type contentReader struct {
+	cmd    *cobra.Command
+	casURL string
+}
+
+// read reads the content for the given hashlink and returns the content along with the URL from which it
+// was read. The content is verified against the hash in the hashlink. If the content can't be read from
+// any of the sources then the returned error contains the reason for each source.
+func (r *contentReader) read(hl string, alternate *url.URL) ([]byte, string, error) {
+	sources, err := r.getSources(hl, alternate)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(sources) == 0 {
+		return nil, "", fmt.Errorf("no CAS URL was provided and hashlink [%s] contains no HTTP links", hl)
+	}
+
+	var failures []string
+
+	for _, source := range sources {
+		content, err := r.get(source)
+		if err == nil {
+			err = hashlink.VerifyResourceHash(hl, content)
+		}
+
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", source.url, err))
+
+			continue
+		}
+
+		return content, source.url, nil
+	}
+
+	return nil, "", fmt.Errorf("content for [%s] can't be read from any source: %s", hl, strings.Join(failures, "; "))
+}
+
+type contentSource struct {
+	url    string
+	remote bool
+}
+
+func (r *contentReader) getSources(hl string, alternate *url.URL) ([]*contentSource, error) {
+	hlInfo, err := hashlink.New().ParseHashLink(hl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hashlink: %w", err)
+	}
+
+	var sources []*contentSource
+
+	if r.casURL != "" {
+		sources = append(sources, &contentSource{
+			url: fmt.Sprintf("%s/%s", strings.TrimSuffix(r.casURL, "/"), hlInfo.ResourceHash),
+		})
+	}
+
+	links := hlInfo.Links
+
+	if alternate != nil {
+		alternateInfo, err := hashlink.New().ParseHashLink(alternate.String())
+		if err == nil && alternateInfo.ResourceHash == hlInfo.ResourceHash {
+			links = append(links, alternateInfo.Links...)
+		}
+	}
+
+	for _, link := range links {
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+			// Only HTTP links (i.e. WebCAS) are supported. IPFS links are skipped.
+			continue
+		}
+
+		sources = append(sources, &contentSource{url: link, remote: true})
+	}
+
+	return sources, nil
+}
+
+func (r *contentReader) get(source *contentSource) ([]byte, error) {
+	if !source.remote {
+		return common.SendHTTPRequest(r.cmd, nil, http.MethodGet, source.url)
+	}
+
+	httpClient, err := common.NewHTTPClient(r.cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// The auth token is only sent to the given CAS URL and not to other servers.
+	return common.SendRequest(httpClient, nil, nil, http.MethodGet, source.url)
+}
+
+// readCoreOperations reads the core index file and returns the recover and deactivate operations, keyed by suffix.
+func (r *contentReader) readCoreOperations(hl string, via *url.URL) (map[string]string, error) {
+	content, _, err := r.read(hl, via)
+	if err != nil {
+		return nil, fmt.Errorf("read core index file: %w", err)
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("decompress core index file [%s]: %w", hl, err)
+	}
+
+	coreIndexBytes, err := io.ReadAll(gr)
+	if err != nil {
+		return nil, fmt.Errorf("decompress core index file [%s]: %w", hl, err)
+	}
+
+	coreIndex := &coreIndexFile{}
+
+	if err := json.Unmarshal(coreIndexBytes, coreIndex); err != nil {
+		return nil, fmt.Errorf("unmarshal core index file [%s]: %w", hl, err)
+	}
+
+	ops := make(map[string]string)
+
+	if coreIndex.Operations == nil {
+		return ops, nil
+	}
+
+	for _, ref := range coreIndex.Operations.Recover {
+		ops[ref.DIDSuffix] = operationRecover
+	}
+
+	for _, ref := range coreIndex.Operations.Deactivate {
+		ops[ref.DIDSuffix] = operationDeactivate
+	}
+
+	return ops, nil
+}
+
+func getListDIDsArgs(cmd *cobra.Command) (casURL, hl string, err error) {
+	casURL, err = cmdutil.GetUserSetVarFromString(cmd, casURLFlagName, casURLEnvKey, true)
+	if err != nil {
+		return "", "", err
+	}
+
+	if casURL != "" {
+		_, err = url.Parse(casURL)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid CAS URL %s: %w", casURL, err)
+		}
+	}
+
+	hl, err = cmdutil.GetUserSetVarFromString(cmd, hashlinkFlagName, hashlinkEnvKey, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	return casURL, hl, nil
+}
@@ -0,0 +1,317 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorcmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/datauri"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+const (
+	createSuffix     = "EiAbvz2BZUmsqc2ZO5Fzhd04kCeuy31fzbZxH4Em_0RZ9Q"
+	updateSuffix     = "EiBdCxP8fh2R84KMAs9A1N3r_UCbMwFvgBIybrFY4dGYmw"
+	recoverSuffix    = "EiDB-Nh4kxP2UIzfXUVEfWnA4lRrg4kQEgZ0SNEo3pvBcA"
+	deactivateSuffix = "EiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg"
+	previousAnchor   = "hl:uEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw"
+)
+
+func TestListDIDsCmd(t *testing.T) {
+	t.Run("missing hashlink arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"list-dids", flag + casURLFlagName, "https://localhost:8080/cas"})
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither hashlink (command line flag) nor ORB_CLI_HASHLINK (environment variable) have been set.")
+	})
+
+	t.Run("invalid hashlink arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"list-dids", flag + casURLFlagName, "https://localhost:8080/cas",
+			flag + hashlinkFlagName, "uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid hashlink")
+	})
+
+	t.Run("no CAS URL and no links in hashlink", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"list-dids", flag + hashlinkFlagName, "hl:uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no CAS URL was provided")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		cas := newTestCAS(t)
+
+		hl := cas.addAnchor(t, true)
+
+		result, err := executeListDIDsCmd(t, cas.url(), hl)
+		require.NoError(t, err)
+		require.Equal(t, hl, result.Hashlink)
+		require.Equal(t, cas.url()+"/"+resourceHash(t, hl), result.Source)
+		require.NotEmpty(t, result.CoreIndex)
+		require.Empty(t, result.Warnings)
+		require.Len(t, result.DIDs, 4)
+
+		expected := []struct{ suffix, operation, previous string }{
+			{createSuffix, operationCreate, ""},
+			{updateSuffix, operationUpdate, previousAnchor},
+			{recoverSuffix, operationRecover, previousAnchor},
+			{deactivateSuffix, operationDeactivate, previousAnchor},
+		}
+
+		for i, e := range expected {
+			require.Equal(t, e.suffix, result.DIDs[i].Suffix)
+			require.Equal(t, e.operation, result.DIDs[i].Operation)
+			require.Equal(t, e.previous, result.DIDs[i].Previous)
+		}
+	})
+
+	t.Run("core index file not available", func(t *testing.T) {
+		cas := newTestCAS(t)
+
+		hl := cas.addAnchor(t, false)
+
+		result, err := executeListDIDsCmd(t, cas.url(), hl)
+		require.NoError(t, err)
+		require.Len(t, result.Warnings, 1)
+		require.Contains(t, result.Warnings[0], "can't be determined")
+		require.Len(t, result.DIDs, 4)
+		require.Equal(t, operationCreate, result.DIDs[0].Operation)
+
+		for _, did := range result.DIDs[1:] {
+			require.Equal(t, operationUnknown, did.Operation)
+		}
+	})
+
+	t.Run("anchor not available locally -> resolved from hashlink links", func(t *testing.T) {
+		remote := newTestCAS(t)
+		local := newTestCAS(t)
+
+		hl := remote.addAnchor(t, true)
+
+		info, err := hashlink.New().ParseHashLink(hl)
+		require.NoError(t, err)
+
+		hlWithLinks, err := hashlink.New().CreateHashLink(remote.content[info.ResourceHash],
+			[]string{"ipfs://bafkreiatkubvbkdidscmqynkyls3iqawdqvthi7e6mbky2amuw3inxsi3y",
+				remote.url() + "/" + info.ResourceHash})
+		require.NoError(t, err)
+
+		result, err := executeListDIDsCmd(t, local.url(), hlWithLinks)
+		require.NoError(t, err)
+		require.Equal(t, remote.url()+"/"+info.ResourceHash, result.Source)
+		require.Len(t, result.DIDs, 4)
+		require.Empty(t, result.Warnings)
+	})
+
+	t.Run("anchor not found -> error", func(t *testing.T) {
+		cas := newTestCAS(t)
+
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"list-dids", flag + casURLFlagName, cas.url(), flag + hashlinkFlagName,
+			"hl:uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can't be read from any source")
+	})
+
+	t.Run("content doesn't match hash -> error", func(t *testing.T) {
+		cas := newTestCAS(t)
+
+		hl := cas.addAnchor(t, true)
+
+		rh := resourceHash(t, hl)
+		cas.content[rh] = append(cas.content[rh], ' ')
+
+		_, err := executeListDIDsCmd(t, cas.url(), hl)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can't be read from any source")
+	})
+
+	t.Run("invalid original content -> error", func(t *testing.T) {
+		cas := newTestCAS(t)
+
+		content := []byte(`{"linkset":[{"anchor":"hl:uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg",` +
+			`"original":[{"href":"data:application/json,xxx","type":"application/linkset+json"}]}]}`)
+
+		hl := cas.add(t, content)
+
+		_, err := executeListDIDsCmd(t, cas.url(), hl)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor linkset can't be fully read")
+	})
+
+	t.Run("no original content -> error", func(t *testing.T) {
+		cas := newTestCAS(t)
+
+		hl := cas.add(t, []byte(`{"linkset":[{"anchor":"hl:uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg"}]}`))
+
+		_, err := executeListDIDsCmd(t, cas.url(), hl)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor linkset has no original content")
+	})
+}
+
+func executeListDIDsCmd(t *testing.T, casURL, hl string) (*listDIDsResult, error) {
+	t.Helper()
+
+	cmd := GetCmd()
+
+	out := bytes.NewBuffer(nil)
+	cmd.SetOut(out)
+
+	cmd.SetArgs([]string{"list-dids", flag + casURLFlagName, casURL, flag + hashlinkFlagName, hl})
+
+	err := cmd.Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &listDIDsResult{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), result))
+
+	return result, nil
+}
+
+type testCAS struct {
+	server  *httptest.Server
+	content map[string][]byte
+}
+
+func newTestCAS(t *testing.T) *testCAS {
+	t.Helper()
+
+	cas := &testCAS{content: make(map[string][]byte)}
+
+	cas.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := cas.content[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, err := w.Write(content)
+		require.NoError(t, err)
+	}))
+
+	t.Cleanup(cas.server.Close)
+
+	return cas
+}
+
+func (c *testCAS) url() string {
+	return c.server.URL
+}
+
+func (c *testCAS) add(t *testing.T, content []byte) string {
+	t.Helper()
+
+	hl, err := hashlink.New().CreateHashLink(content, nil)
+	require.NoError(t, err)
+
+	c.content[resourceHash(t, hl)] = content
+
+	return hl
+}
+
+// addAnchor adds an anchor linkset containing a create, update, recover and deactivate operation and
+// returns the hashlink of the anchor linkset. The core index file is only added if addCoreIndex is true.
+func (c *testCAS) addAnchor(t *testing.T, addCoreIndex bool) string {
+	t.Helper()
+
+	coreIndexBytes, err := json.Marshal(map[string]interface{}{
+		"operations": map[string]interface{}{
+			"create":     []interface{}{map[string]interface{}{"suffixData": map[string]interface{}{}}},
+			"recover":    []interface{}{map[string]interface{}{"didSuffix": recoverSuffix}},
+			"deactivate": []interface{}{map[string]interface{}{"didSuffix": deactivateSuffix}},
+		},
+	})
+	require.NoError(t, err)
+
+	compressed := bytes.NewBuffer(nil)
+
+	gw := gzip.NewWriter(compressed)
+	_, err = gw.Write(coreIndexBytes)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	coreIndexHL, err := hashlink.New().CreateHashLink(compressed.Bytes(), nil)
+	require.NoError(t, err)
+
+	// The "via" hashlink contains a link to the core index file in this CAS, as it would for an anchor
+	// created by another server.
+	viaHL, err := hashlink.New().CreateHashLink(compressed.Bytes(),
+		[]string{c.url() + "/" + resourceHash(t, coreIndexHL)})
+	require.NoError(t, err)
+
+	if addCoreIndex {
+		c.content[resourceHash(t, coreIndexHL)] = compressed.Bytes()
+	}
+
+	author := vocab.MustParseURL("https://orb.domain1.com/services/orb")
+	profile := vocab.MustParseURL("https://w3id.org/orb#v0")
+
+	contentBytes, err := json.Marshal(linkset.New(linkset.NewAnchorLink(
+		vocab.MustParseURL(coreIndexHL), author, profile,
+		[]*linkset.Item{
+			linkset.NewItem(vocab.MustParseURL("did:orb:uAAA:"+createSuffix), nil),
+			linkset.NewItem(vocab.MustParseURL("did:orb:uEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw:"+updateSuffix),
+				vocab.MustParseURL(previousAnchor)),
+			linkset.NewItem(vocab.MustParseURL("did:orb:uEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw:"+recoverSuffix),
+				vocab.MustParseURL(previousAnchor)),
+			linkset.NewItem(
+				vocab.MustParseURL("did:orb:uEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw:"+deactivateSuffix),
+				vocab.MustParseURL(previousAnchor)),
+		},
+	)))
+	require.NoError(t, err)
+
+	anchor, originalRef, err := linkset.NewAnchorRef(contentBytes, datauri.MediaTypeDataURIJSON, linkset.TypeLinkset)
+	require.NoError(t, err)
+
+	relatedBytes, err := json.Marshal(linkset.New(linkset.NewRelatedLink(anchor, profile,
+		vocab.MustParseURL(viaHL), vocab.MustParseURL(previousAnchor))))
+	require.NoError(t, err)
+
+	_, relatedRef, err := linkset.NewAnchorRef(relatedBytes, datauri.MediaTypeDataURIJSON, linkset.TypeLinkset)
+	require.NoError(t, err)
+
+	anchorLinksetBytes, err := json.Marshal(linkset.New(
+		linkset.NewLink(anchor, author, profile, originalRef, relatedRef, nil)),
+	)
+	require.NoError(t, err)
+
+	return c.add(t, anchorLinksetBytes)
+}
+
+func resourceHash(t *testing.T, hl string) string {
+	t.Helper()
+
+	rh, err := hashlink.GetResourceHashFromHashLink(hl)
+	require.NoError(t, err)
+
+	return rh
+}
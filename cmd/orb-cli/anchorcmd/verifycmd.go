@@ -0,0 +1,282 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	jsonld "github.com/piprate/json-gold/ld"
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator"
+	"github.com/trustbloc/orb/pkg/anchor/util"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+const (
+	// exitCodeHashMismatch is the exit code used when the anchor content doesn't match its hashlink,
+	// i.e. the stored anchor is corrupt.
+	exitCodeHashMismatch = 4
+	// exitCodeInconsistent is the exit code used when the anchor content matches its hashlink but
+	// the embedded credential is inconsistent with the anchor linkset.
+	exitCodeInconsistent = 5
+)
+
+const (
+	checkHash       = "hash"
+	checkLinkset    = "linkset"
+	checkCredential = "credential"
+	checkSubject    = "credentialSubject"
+)
+
+type checkStatus string
+
+const (
+	statusPassed  checkStatus = "passed"
+	statusFailed  checkStatus = "failed"
+	statusSkipped checkStatus = "skipped"
+)
+
+type failureType string
+
+const (
+	// failureHashCorruption indicates that the hash of the stored content doesn't match the hashlink.
+	failureHashCorruption failureType = "hash-corruption"
+	// failureSemanticInconsistency indicates that the content isn't corrupt but the anchor linkset
+	// or its credential are not consistent.
+	failureSemanticInconsistency failureType = "semantic-inconsistency"
+)
+
+type checkResult struct {
+	Name    string      `json:"name"`
+	Status  checkStatus `json:"status"`
+	Failure failureType `json:"failure,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+type verifyResult struct {
+	Hashlink string         `json:"hashlink"`
+	Valid    bool           `json:"valid"`
+	Checks   []*checkResult `json:"checks"`
+}
+
+type generatorRegistry interface {
+	Get(id *url.URL) (generator.Generator, error)
+}
+
+func newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verifies the integrity of an anchor stored in CAS.",
+		Long: `Loads the anchor linkset from CAS, verifies that its content matches the hashlink and that the ` +
+			`embedded verifiable credential is consistent with the anchor linkset. The result of each check is ` +
+			`output. A hash mismatch (corrupt content) results in exit code 4 and an inconsistent credential ` +
+			`results in exit code 5. For example: anchors verify --cas-url https://orb.domain1.com/cas ` +
+			`--hashlink hl:uEiDuIicNljP8PoHJk6_aA7w1d4U3FAvDMfF7Dsh7fkw3Wg`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeVerify(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(casURLFlagName, "", "", casURLFlagUsage)
+	cmd.Flags().StringP(hashlinkFlagName, "", "", hashlinkFlagUsage)
+
+	return cmd
+}
+
+func executeVerify(cmd *cobra.Command) error {
+	casURL, hl, err := getVerifyArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	resourceHash, err := hashlink.GetResourceHashFromHashLink(hl)
+	if err != nil {
+		return fmt.Errorf("invalid hashlink: %w", err)
+	}
+
+	content, err := common.SendHTTPRequest(cmd, nil, http.MethodGet,
+		fmt.Sprintf("%s/%s", strings.TrimSuffix(casURL, "/"), resourceHash))
+	if err != nil {
+		return fmt.Errorf("get anchor linkset from %s: %w", casURL, err)
+	}
+
+	docLoader, err := common.NewDocumentLoader()
+	if err != nil {
+		return fmt.Errorf("new document loader: %w", err)
+	}
+
+	result := verifyAnchor(hl, content, docLoader, generator.NewRegistry())
+
+	resultBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal verify result: %w", err)
+	}
+
+	common.Println(cmd.OutOrStdout(), string(resultBytes))
+
+	return result.exitError()
+}
+
+// verifyAnchor performs the same checks that are performed when an anchor is received from another server:
+// the content must match the hashlink and the credential subject must reference the anchor linkset. Once a
+// check fails, the remaining checks are skipped.
+func verifyAnchor(hl string, content []byte, docLoader jsonld.DocumentLoader,
+	registry generatorRegistry,
+) *verifyResult {
+	result := &verifyResult{Hashlink: hl}
+
+	var (
+		anchorLink *linkset.Link
+		vc         *verifiable.Credential
+	)
+
+	checks := []struct {
+		name    string
+		failure failureType
+		check   func() error
+	}{
+		{
+			name:    checkHash,
+			failure: failureHashCorruption,
+			check: func() error {
+				return hashlink.VerifyResourceHash(hl, content)
+			},
+		},
+		{
+			name:    checkLinkset,
+			failure: failureSemanticInconsistency,
+			check: func() error {
+				anchorLinkset := &linkset.Linkset{}
+
+				if err := json.Unmarshal(content, anchorLinkset); err != nil {
+					return fmt.Errorf("unmarshal anchor linkset: %w", err)
+				}
+
+				anchorLink = anchorLinkset.Link()
+				if anchorLink == nil {
+					return errors.New("anchor linkset contains no links")
+				}
+
+				return nil
+			},
+		},
+		{
+			name:    checkCredential,
+			failure: failureSemanticInconsistency,
+			check: func() error {
+				var err error
+
+				vc, err = util.VerifiableCredentialFromAnchorLink(anchorLink,
+					verifiable.WithDisabledProofCheck(),
+					verifiable.WithJSONLDDocumentLoader(docLoader),
+				)
+				if err != nil {
+					return fmt.Errorf("get verifiable credential from anchor link: %w", err)
+				}
+
+				return nil
+			},
+		},
+		{
+			name:    checkSubject,
+			failure: failureSemanticInconsistency,
+			check: func() error {
+				gen, err := registry.Get(anchorLink.Profile())
+				if err != nil {
+					return fmt.Errorf("resolve generator for profile [%s]: %w", anchorLink.Profile(), err)
+				}
+
+				contentBytes, err := anchorLink.Original().Content()
+				if err != nil {
+					return fmt.Errorf("get content from original: %w", err)
+				}
+
+				return gen.ValidateAnchorCredential(vc, contentBytes)
+			},
+		},
+	}
+
+	result.Valid = true
+
+	for _, c := range checks {
+		if !result.Valid {
+			result.Checks = append(result.Checks, &checkResult{Name: c.name, Status: statusSkipped})
+
+			continue
+		}
+
+		if err := c.check(); err != nil {
+			result.Valid = false
+			result.Checks = append(result.Checks, &checkResult{
+				Name:    c.name,
+				Status:  statusFailed,
+				Failure: c.failure,
+				Error:   err.Error(),
+			})
+
+			continue
+		}
+
+		result.Checks = append(result.Checks, &checkResult{Name: c.name, Status: statusPassed})
+	}
+
+	return result
+}
+
+func (r *verifyResult) exitError() error {
+	for _, c := range r.Checks {
+		if c.Status != statusFailed {
+			continue
+		}
+
+		if c.Failure == failureHashCorruption {
+			return &common.ExitError{
+				Code: exitCodeHashMismatch,
+				Err:  fmt.Errorf("anchor [%s] is corrupt: %s", r.Hashlink, c.Error),
+			}
+		}
+
+		return &common.ExitError{
+			Code: exitCodeInconsistent,
+			Err:  fmt.Errorf("anchor [%s] is inconsistent: %s check failed: %s", r.Hashlink, c.Name, c.Error),
+		}
+	}
+
+	return nil
+}
+
+func getVerifyArgs(cmd *cobra.Command) (casURL, hl string, err error) {
+	casURL, err = cmdutil.GetUserSetVarFromString(cmd, casURLFlagName, casURLEnvKey, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	_, err = url.Parse(casURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid CAS URL %s: %w", casURL, err)
+	}
+
+	hl, err = cmdutil.GetUserSetVarFromString(cmd, hashlinkFlagName, hashlinkEnvKey, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	return casURL, hl, nil
+}
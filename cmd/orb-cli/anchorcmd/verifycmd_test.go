@@ -0,0 +1,250 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+const flag = "--"
+
+func TestVerifyCmd(t *testing.T) {
+	content := compact(t, anchorLinkset)
+
+	hl, err := hashlink.New().CreateHashLink(content, nil)
+	require.NoError(t, err)
+
+	t.Run("missing cas-url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"verify"})
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither cas-url (command line flag) nor ORB_CAS_URL (environment variable) have been set.")
+	})
+
+	t.Run("invalid cas-url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"verify", flag + casURLFlagName, ":invalid", flag + hashlinkFlagName, hl})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing protocol scheme")
+	})
+
+	t.Run("missing hashlink arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"verify", flag + casURLFlagName, "https://localhost:8080/cas"})
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither hashlink (command line flag) nor ORB_CLI_HASHLINK (environment variable) have been set.")
+	})
+
+	t.Run("invalid hashlink arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"verify", flag + casURLFlagName, "https://localhost:8080/cas", flag + hashlinkFlagName,
+			"uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid hashlink")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		serv := newCASServer(t, hl, content)
+		defer serv.Close()
+
+		result, err := executeVerifyCmd(t, serv.URL, hl)
+		require.NoError(t, err)
+		require.True(t, result.Valid)
+		require.Equal(t, hl, result.Hashlink)
+		require.Len(t, result.Checks, 4)
+
+		for _, c := range result.Checks {
+			require.Equal(t, statusPassed, c.Status)
+		}
+	})
+
+	t.Run("hash mismatch -> corrupt", func(t *testing.T) {
+		serv := newCASServer(t, hl, append(content, ' '))
+		defer serv.Close()
+
+		result, err := executeVerifyCmd(t, serv.URL, hl)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is corrupt")
+
+		exitErr := &common.ExitError{}
+		require.True(t, errors.As(err, &exitErr))
+		require.Equal(t, exitCodeHashMismatch, exitErr.Code)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Checks, 4)
+		require.Equal(t, checkHash, result.Checks[0].Name)
+		require.Equal(t, statusFailed, result.Checks[0].Status)
+		require.Equal(t, failureHashCorruption, result.Checks[0].Failure)
+
+		for _, c := range result.Checks[1:] {
+			require.Equal(t, statusSkipped, c.Status)
+		}
+	})
+
+	t.Run("credential subject anchor mismatch -> inconsistent", func(t *testing.T) {
+		// Change the anchor in the credential subject so that it no longer references the original content.
+		inconsistentContent := []byte(strings.Replace(string(content),
+			"%22credentialSubject%22%3A%7B%22anchor%22%3A%22hl%3AuEiCKkDb0",
+			"%22credentialSubject%22%3A%7B%22anchor%22%3A%22hl%3AuEiCKkDb1", 1))
+
+		inconsistentHL, err := hashlink.New().CreateHashLink(inconsistentContent, nil)
+		require.NoError(t, err)
+
+		serv := newCASServer(t, inconsistentHL, inconsistentContent)
+		defer serv.Close()
+
+		result, err := executeVerifyCmd(t, serv.URL, inconsistentHL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is inconsistent")
+
+		exitErr := &common.ExitError{}
+		require.True(t, errors.As(err, &exitErr))
+		require.Equal(t, exitCodeInconsistent, exitErr.Code)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Checks, 4)
+		require.Equal(t, statusPassed, result.Checks[0].Status)
+		require.Equal(t, statusPassed, result.Checks[1].Status)
+		require.Equal(t, statusPassed, result.Checks[2].Status)
+		require.Equal(t, checkSubject, result.Checks[3].Name)
+		require.Equal(t, statusFailed, result.Checks[3].Status)
+		require.Equal(t, failureSemanticInconsistency, result.Checks[3].Failure)
+		require.Contains(t, result.Checks[3].Error, "anchor in the credential subject")
+	})
+
+	t.Run("invalid linkset -> inconsistent", func(t *testing.T) {
+		invalidContent := []byte(`{"linkset":[]}`)
+
+		invalidHL, err := hashlink.New().CreateHashLink(invalidContent, nil)
+		require.NoError(t, err)
+
+		serv := newCASServer(t, invalidHL, invalidContent)
+		defer serv.Close()
+
+		result, err := executeVerifyCmd(t, serv.URL, invalidHL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "linkset check failed")
+
+		require.Equal(t, statusPassed, result.Checks[0].Status)
+		require.Equal(t, statusFailed, result.Checks[1].Status)
+		require.Equal(t, failureSemanticInconsistency, result.Checks[1].Failure)
+		require.Equal(t, statusSkipped, result.Checks[2].Status)
+		require.Equal(t, statusSkipped, result.Checks[3].Status)
+	})
+
+	t.Run("anchor not found in CAS -> error", func(t *testing.T) {
+		serv := newCASServer(t, "hl:unknown", content)
+		defer serv.Close()
+
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"verify", flag + casURLFlagName, serv.URL, flag + hashlinkFlagName, hl})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get anchor linkset from")
+	})
+}
+
+func executeVerifyCmd(t *testing.T, casURL, hl string) (*verifyResult, error) {
+	t.Helper()
+
+	cmd := GetCmd()
+
+	out := bytes.NewBuffer(nil)
+	cmd.SetOut(out)
+
+	cmd.SetArgs([]string{"verify", flag + casURLFlagName, casURL, flag + hashlinkFlagName, hl})
+
+	err := cmd.Execute()
+
+	result := &verifyResult{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), result))
+
+	return result, err
+}
+
+func newCASServer(t *testing.T, hl string, content []byte) *httptest.Server {
+	t.Helper()
+
+	resourceHash, err := hashlink.GetResourceHashFromHashLink(hl)
+	require.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+resourceHash {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, err := w.Write(content)
+		require.NoError(t, err)
+	}))
+}
+
+func compact(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b := bytes.NewBuffer(nil)
+	require.NoError(t, json.Compact(b, []byte(s)))
+
+	return b.Bytes()
+}
+
+const anchorLinkset = `{
+  "linkset": [
+    {
+      "anchor": "hl:uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg",
+      "author": [
+        {
+          "href": "https://orb.domain1.com/services/orb"
+        }
+      ],
+      "original": [
+        {
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiCKkDb0aQhWNudvelroKLnBqEMORXuOeQqI_mYeVhGkpQ%22%2C%22author%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Forb.domain1.com%2Fservices%2Forb%22%7D%5D%2C%22item%22%3A%5B%7B%22href%22%3A%22did%3Aorb%3AuAAA%3AEiAbvz2BZUmsqc2ZO5Fzhd04kCeuy31fzbZxH4Em_0RZ9Q%22%7D%5D%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%7D%5D%7D",
+          "type": "application/linkset+json"
+        }
+      ],
+      "profile": [
+        {
+          "href": "https://w3id.org/orb#v0"
+        }
+      ],
+      "related": [
+        {
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg%22%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%2C%22via%22%3A%5B%7B%22href%22%3A%22hl%3AuEiCKkDb0aQhWNudvelroKLnBqEMORXuOeQqI_mYeVhGkpQ%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQ0trRGIwYVFoV051ZHZlbHJvS0xuQnFFTU9SWHVPZVFxSV9tWWVWaEdrcFF4QmlwZnM6Ly9iYWZrcmVpZWtzYTNwaTJpaWt5M29vMzMybGx1Y3Jvb2J2YmJxNHJsM3J6NHF2Y2g2bXlwZm1lbmV1dQ%22%7D%5D%7D%5D%7D",
+          "type": "application/linkset+json"
+        }
+      ],
+      "replies": [
+        {
+          "href": "data:application/json,%7B%22%40context%22%3A%5B%22https%3A%2F%2Fwww.w3.org%2F2018%2Fcredentials%2Fv1%22%2C%22https%3A%2F%2Fw3id.org%2Factivityanchors%2Fv1%22%2C%22https%3A%2F%2Fw3id.org%2Fsecurity%2Fsuites%2Fjws-2020%2Fv1%22%2C%22https%3A%2F%2Fw3id.org%2Fsecurity%2Fsuites%2Fed25519-2020%2Fv1%22%5D%2C%22credentialSubject%22%3A%7B%22anchor%22%3A%22hl%3AuEiCKkDb0aQhWNudvelroKLnBqEMORXuOeQqI_mYeVhGkpQ%22%2C%22href%22%3A%22hl%3AuEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg%22%2C%22profile%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%2C%22rel%22%3A%22linkset%22%2C%22type%22%3A%5B%22AnchorLink%22%5D%7D%2C%22id%22%3A%22https%3A%2F%2Forb2.domain1.com%2Fvc%2F19148c22-9088-4652-bcfa-fcea1279f072%22%2C%22issuanceDate%22%3A%222022-08-25T20%3A09%3A09.480315917Z%22%2C%22issuer%22%3A%22https%3A%2F%2Forb2.domain1.com%22%2C%22proof%22%3A%5B%7B%22created%22%3A%222022-08-25T20%3A09%3A09.52Z%22%2C%22domain%22%3A%22http%3A%2F%2Forb.vct%3A8077%2Fmaple2020%22%2C%22proofPurpose%22%3A%22assertionMethod%22%2C%22proofValue%22%3A%22zjJsKS1B4PrVfrQrsE6JRdWmDpjZosDvT4qk3b7wSpnVfaEk5w6iCu7PwXBQd7QzG9VEYkUTD9sUdCF7VfSEupV7%22%2C%22type%22%3A%22Ed25519Signature2020%22%2C%22verificationMethod%22%3A%22did%3Aweb%3Aorb.domain1.com%2375MDi94rVaJ69DRwHLwaCxBVg-wdEuBKwzgNgyoMbcc%22%7D%2C%7B%22created%22%3A%222022-08-25T20%3A09%3A09.715076709Z%22%2C%22domain%22%3A%22https%3A%2F%2Forb.domain2.com%22%2C%22proofPurpose%22%3A%22assertionMethod%22%2C%22proofValue%22%3A%22z5pJumaR6o4v7cZudXsBQx8NYh4SEJSFzBGNj92cAw7jEUqoTAypHsECGAiRU6TXqSeU2D5azChjXpmkcCNGsBwam%22%2C%22type%22%3A%22Ed25519Signature2020%22%2C%22verificationMethod%22%3A%22did%3Aweb%3Aorb.domain2.com%23LfX08Wr74EkPSoG7CoB3S4OuSrX3LM-_Yd0BvfSonLQ%22%7D%5D%2C%22type%22%3A%5B%22VerifiableCredential%22%2C%22AnchorCredential%22%5D%7D",
+          "type": "application/ld+json"
+        }
+      ]
+    }
+  ]
+}`
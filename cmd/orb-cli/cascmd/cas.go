@@ -0,0 +1,31 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cascmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+// GetCmd returns the Cobra CAS command.
+func GetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "cas",
+		Short:        "Manages the content of Content Addressable Storage (CAS).",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("expecting subcommand sync")
+		},
+	}
+
+	cmd.AddCommand(
+		newSyncCmd(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,542 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cascmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+const (
+	hashesURLFlagName  = "hashes-url"
+	hashesURLEnvKey    = "ORB_CLI_CAS_HASHES_URL"
+	hashesURLFlagUsage = "The URL of the source node's CAS hashes collection, " +
+		"e.g. https://orb1.domain.com/services/orb/cas-hashes. The endpoint must be enabled on the source node." +
+		" Alternatively, this can be set with the following environment variable: " + hashesURLEnvKey
+
+	sourceCASURLFlagName  = "source-cas-url"
+	sourceCASURLEnvKey    = "ORB_CLI_SOURCE_CAS_URL"
+	sourceCASURLFlagUsage = "The URL of the source node's WebCAS endpoint, e.g. https://orb1.domain.com/cas." +
+		" Alternatively, this can be set with the following environment variable: " + sourceCASURLEnvKey
+
+	destinationCASURLFlagName  = "destination-cas-url"
+	destinationCASURLEnvKey    = "ORB_CLI_DESTINATION_CAS_URL"
+	destinationCASURLFlagUsage = "The URL of the destination node's WebCAS endpoint, " +
+		"e.g. https://orb2.domain.com/cas. CAS writes must be enabled on the destination node." +
+		" Alternatively, this can be set with the following environment variable: " + destinationCASURLEnvKey
+
+	sourceAuthTokenFlagName  = "source-auth-token"
+	sourceAuthTokenEnvKey    = "ORB_CLI_SOURCE_AUTH_TOKEN" //nolint:gosec
+	sourceAuthTokenFlagUsage = "The auth token used for requests to the source node." +
+		" Alternatively, this can be set with the following environment variable: " + sourceAuthTokenEnvKey
+
+	destinationAuthTokenFlagName  = "destination-auth-token"
+	destinationAuthTokenEnvKey    = "ORB_CLI_DESTINATION_AUTH_TOKEN" //nolint:gosec
+	destinationAuthTokenFlagUsage = "The auth token used for requests to the destination node." +
+		" Alternatively, this can be set with the following environment variable: " + destinationAuthTokenEnvKey
+
+	concurrencyFlagName  = "concurrency"
+	concurrencyEnvKey    = "ORB_CLI_CONCURRENCY"
+	concurrencyFlagUsage = "The number of hashes which are copied concurrently. Defaults to 5." +
+		" Alternatively, this can be set with the following environment variable: " + concurrencyEnvKey
+
+	checkpointFileFlagName  = "checkpoint-file"
+	checkpointFileEnvKey    = "ORB_CLI_CHECKPOINT_FILE"
+	checkpointFileFlagUsage = "The path of a file in which the next page of hashes to sync is saved after each page " +
+		"is successfully synced. If the file exists then the sync resumes from the saved page. The file is removed " +
+		"once the sync completes without failures. If not set then the sync always starts from the first page." +
+		" Alternatively, this can be set with the following environment variable: " + checkpointFileEnvKey
+)
+
+const defaultConcurrency = 5
+
+var errSyncFailed = errors.New("one or more hashes failed to sync")
+
+func newSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Copies CAS content from a source node to a destination node.",
+		Long: "Copies the content held in the local CAS of a source node (as listed by the source's CAS hashes " +
+			"endpoint) to the WebCAS endpoint of a destination node, e.g. in order to warm a new mirror. Content " +
+			"which the destination already holds is skipped, so the sync may be run again to retry failed hashes.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeSync(cmd)
+		},
+	}
+
+	cmd.Flags().StringP(common.TLSSystemCertPoolFlagName, "", "", common.TLSSystemCertPoolFlagUsage)
+	cmd.Flags().StringArrayP(common.TLSCACertsFlagName, "", nil, common.TLSCACertsFlagUsage)
+	cmd.Flags().String(hashesURLFlagName, "", hashesURLFlagUsage)
+	cmd.Flags().String(sourceCASURLFlagName, "", sourceCASURLFlagUsage)
+	cmd.Flags().String(destinationCASURLFlagName, "", destinationCASURLFlagUsage)
+	cmd.Flags().String(sourceAuthTokenFlagName, "", sourceAuthTokenFlagUsage)
+	cmd.Flags().String(destinationAuthTokenFlagName, "", destinationAuthTokenFlagUsage)
+	cmd.Flags().String(concurrencyFlagName, "", concurrencyFlagUsage)
+	cmd.Flags().String(checkpointFileFlagName, "", checkpointFileFlagUsage)
+
+	return cmd
+}
+
+func executeSync(cmd *cobra.Command) error {
+	s, err := newSyncer(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	stats, err := s.sync(ctx)
+
+	common.Printf(cmd.OutOrStdout(), "Synced %d page(s): %d hash(es) processed, %d copied, %d already present, "+
+		"%d failed, %d bytes transferred\n", stats.pages, stats.processed, stats.copied, stats.skipped,
+		stats.failed, stats.bytes)
+
+	return err
+}
+
+type syncStats struct {
+	pages     int
+	processed int64
+	copied    int64
+	skipped   int64
+	failed    int64
+	bytes     int64
+}
+
+func (s *syncStats) add(other *syncStats) {
+	s.pages += other.pages
+	s.processed += other.processed
+	s.copied += other.copied
+	s.skipped += other.skipped
+	s.failed += other.failed
+	s.bytes += other.bytes
+}
+
+// syncer copies the CAS content listed in the source's CAS hashes collection to the destination, one page at
+// a time. The hashes in a page are copied concurrently.
+type syncer struct {
+	httpClient      *http.Client
+	hashesURL       string
+	sourceCASURL    string
+	destCASURL      string
+	sourceHeaders   map[string]string
+	destHeaders     map[string]string
+	concurrency     int
+	checkpointFile  string
+	progressOut     io.Writer
+	hl              *hashlink.HashLink
+	headUnsupported atomic.Bool
+}
+
+func newSyncer(cmd *cobra.Command) (*syncer, error) {
+	hashesURL, err := getURL(cmd, hashesURLFlagName, hashesURLEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceCASURL, err := getURL(cmd, sourceCASURLFlagName, sourceCASURLEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	destCASURL, err := getURL(cmd, destinationCASURLFlagName, destinationCASURLEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency, err := cmdutil.GetInt(cmd, concurrencyFlagName, concurrencyEnvKey, defaultConcurrency)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", concurrencyFlagName, err)
+	}
+
+	if concurrency <= 0 {
+		return nil, fmt.Errorf("%s: value must be greater than 0", concurrencyFlagName)
+	}
+
+	httpClient, err := common.NewHTTPClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syncer{
+		httpClient:    httpClient,
+		hashesURL:     hashesURL,
+		sourceCASURL:  strings.TrimSuffix(sourceCASURL, "/"),
+		destCASURL:    strings.TrimSuffix(destCASURL, "/"),
+		sourceHeaders: newAuthTokenHeader(cmd, sourceAuthTokenFlagName, sourceAuthTokenEnvKey),
+		destHeaders:   newAuthTokenHeader(cmd, destinationAuthTokenFlagName, destinationAuthTokenEnvKey),
+		concurrency:   concurrency,
+		checkpointFile: cmdutil.GetUserSetOptionalVarFromString(cmd, checkpointFileFlagName,
+			checkpointFileEnvKey),
+		progressOut: cmd.ErrOrStderr(),
+		hl:          hashlink.New(),
+	}, nil
+}
+
+// sync copies all of the pages of hashes, starting at the checkpointed page (if any). The checkpoint is only
+// advanced while all pages have synced without failures, so that a subsequent run retries from the first page
+// which had a failure.
+func (s *syncer) sync(ctx context.Context) (*syncStats, error) {
+	stats := &syncStats{}
+
+	pageURL, err := s.getStartPage(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	checkpointHeld := false
+
+	for pageURL != nil {
+		page, err := s.getPage(ctx, pageURL)
+		if err != nil {
+			return stats, fmt.Errorf("get page %s: %w", pageURL, err)
+		}
+
+		pageStats := s.syncPage(ctx, page.Items())
+
+		stats.add(pageStats)
+
+		common.Printf(s.progressOut, "Page %d: %d hash(es), %d copied, %d already present, %d failed, "+
+			"%d bytes transferred (total: %d hash(es), %d bytes)\n", stats.pages, pageStats.processed,
+			pageStats.copied, pageStats.skipped, pageStats.failed, pageStats.bytes, stats.processed, stats.bytes)
+
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		pageURL = page.Next()
+
+		if pageStats.failed > 0 {
+			checkpointHeld = true
+		}
+
+		if !checkpointHeld && pageURL != nil {
+			if err := s.saveCheckpoint(pageURL); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	if stats.failed > 0 {
+		return stats, errSyncFailed
+	}
+
+	return stats, s.removeCheckpoint()
+}
+
+func (s *syncer) getStartPage(ctx context.Context) (*url.URL, error) {
+	pageURL, err := s.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	if pageURL != nil {
+		common.Printf(s.progressOut, "Resuming sync from checkpoint %s\n", pageURL)
+
+		return pageURL, nil
+	}
+
+	respBytes, err := common.SendRequestWithContext(ctx, s.httpClient, nil, s.sourceHeaders, http.MethodGet,
+		s.hashesURL)
+	if err != nil {
+		return nil, fmt.Errorf("get CAS hashes collection: %w", err)
+	}
+
+	coll := &vocab.OrderedCollectionType{}
+
+	if err := json.Unmarshal(respBytes, coll); err != nil {
+		return nil, fmt.Errorf("unmarshal CAS hashes collection: %w", err)
+	}
+
+	return coll.First(), nil
+}
+
+func (s *syncer) getPage(ctx context.Context, pageURL *url.URL) (*vocab.OrderedCollectionPageType, error) {
+	respBytes, err := common.SendRequestWithContext(ctx, s.httpClient, nil, s.sourceHeaders, http.MethodGet,
+		pageURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	page := &vocab.OrderedCollectionPageType{}
+
+	if err := json.Unmarshal(respBytes, page); err != nil {
+		return nil, fmt.Errorf("unmarshal CAS hashes page: %w", err)
+	}
+
+	return page, nil
+}
+
+func (s *syncer) syncPage(ctx context.Context, items []*vocab.ObjectProperty) *syncStats {
+	var (
+		copied, skipped, failed, numBytes atomic.Int64
+		wg                                sync.WaitGroup
+	)
+
+	hashes := make(chan string)
+
+	wg.Add(s.concurrency)
+
+	for i := 0; i < s.concurrency; i++ {
+		go func() {
+			defer wg.Done()
+
+			for hash := range hashes {
+				n, err := s.syncHash(ctx, hash)
+
+				switch {
+				case err != nil:
+					failed.Add(1)
+
+					common.Printf(s.progressOut, "Failed to sync %s: %s\n", hash, err)
+				case n == 0:
+					skipped.Add(1)
+				default:
+					copied.Add(1)
+					numBytes.Add(int64(n))
+				}
+			}
+		}()
+	}
+
+	processed := 0
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+
+		processed++
+
+		if item.IRI() == nil {
+			failed.Add(1)
+
+			common.Println(s.progressOut, "Failed to sync item: item is not an IRI")
+
+			continue
+		}
+
+		hash, err := hashlink.GetResourceHashFromHashLink(item.IRI().String())
+		if err != nil {
+			failed.Add(1)
+
+			common.Printf(s.progressOut, "Failed to sync %s: %s\n", item.IRI(), err)
+
+			continue
+		}
+
+		hashes <- hash
+	}
+
+	close(hashes)
+
+	wg.Wait()
+
+	return &syncStats{
+		pages:     1,
+		processed: int64(processed),
+		copied:    copied.Load(),
+		skipped:   skipped.Load(),
+		failed:    failed.Load(),
+		bytes:     numBytes.Load(),
+	}
+}
+
+// syncHash copies the content for the given hash from the source to the destination and returns the number of
+// bytes copied. Zero is returned if the destination already holds the content.
+func (s *syncer) syncHash(ctx context.Context, hash string) (int, error) {
+	exists, err := s.existsAtDestination(ctx, hash)
+	if err != nil {
+		return 0, fmt.Errorf("check destination: %w", err)
+	}
+
+	if exists {
+		return 0, nil
+	}
+
+	content, err := common.SendRequestWithContext(ctx, s.httpClient, nil, s.sourceHeaders, http.MethodGet,
+		s.sourceCASURL+"/"+hash)
+	if err != nil {
+		return 0, fmt.Errorf("read from source: %w", err)
+	}
+
+	resourceHash, err := s.hl.CreateResourceHash(content)
+	if err != nil {
+		return 0, fmt.Errorf("create resource hash: %w", err)
+	}
+
+	if resourceHash != hash {
+		return 0, fmt.Errorf("hash of content read from source [%s] doesn't match", resourceHash)
+	}
+
+	_, err = common.SendRequestWithContext(ctx, s.httpClient, content, s.destHeaders, http.MethodPut,
+		s.destCASURL+"/"+hash)
+	if err != nil {
+		return 0, fmt.Errorf("write to destination: %w", err)
+	}
+
+	return len(content), nil
+}
+
+// existsAtDestination returns true if the destination holds the content for the given hash. A HEAD request is
+// used if the destination supports it, otherwise a GET request is used.
+func (s *syncer) existsAtDestination(ctx context.Context, hash string) (bool, error) {
+	u := s.destCASURL + "/" + hash
+
+	if !s.headUnsupported.Load() {
+		status, err := s.getStatus(ctx, http.MethodHead, u)
+		if err != nil {
+			return false, err
+		}
+
+		switch status {
+		case http.StatusOK:
+			return true, nil
+		case http.StatusNotFound:
+			return false, nil
+		case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			s.headUnsupported.Store(true)
+		default:
+			return false, fmt.Errorf("unexpected status from HEAD %s: %d", u, status)
+		}
+	}
+
+	status, err := s.getStatus(ctx, http.MethodGet, u)
+	if err != nil {
+		return false, err
+	}
+
+	switch status {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status from GET %s: %d", u, status)
+	}
+}
+
+func (s *syncer) getStatus(ctx context.Context, method, u string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, http.NoBody)
+	if err != nil {
+		return 0, fmt.Errorf("create http request: %w", err)
+	}
+
+	for k, v := range s.destHeaders {
+		req.Header.Add(k, v)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("send request: %w", err)
+	}
+
+	// The body is drained so that the connection may be reused.
+	_, err = io.Copy(io.Discard, resp.Body)
+
+	if errClose := resp.Body.Close(); errClose != nil && err == nil {
+		err = errClose
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("read response: %w", err)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (s *syncer) loadCheckpoint() (*url.URL, error) {
+	if s.checkpointFile == "" {
+		return nil, nil
+	}
+
+	contents, err := os.ReadFile(filepath.Clean(s.checkpointFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read checkpoint file: %w", err)
+	}
+
+	pageURL, err := url.Parse(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid page URL in checkpoint file: %w", err)
+	}
+
+	return pageURL, nil
+}
+
+func (s *syncer) saveCheckpoint(pageURL *url.URL) error {
+	if s.checkpointFile == "" {
+		return nil
+	}
+
+	if err := os.WriteFile(s.checkpointFile, []byte(pageURL.String()), 0o600); err != nil {
+		return fmt.Errorf("write checkpoint file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *syncer) removeCheckpoint() error {
+	if s.checkpointFile == "" {
+		return nil
+	}
+
+	if err := os.Remove(s.checkpointFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove checkpoint file: %w", err)
+	}
+
+	return nil
+}
+
+func getURL(cmd *cobra.Command, flagName, envKey string) (string, error) {
+	u, err := cmdutil.GetUserSetVarFromString(cmd, flagName, envKey, false)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = url.Parse(u)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %s: %w", u, err)
+	}
+
+	return u, nil
+}
+
+func newAuthTokenHeader(cmd *cobra.Command, flagName, envKey string) map[string]string {
+	headers := make(map[string]string)
+
+	authToken := cmdutil.GetUserSetOptionalVarFromString(cmd, flagName, envKey)
+	if authToken != "" {
+		headers["Authorization"] = "Bearer " + authToken
+	}
+
+	return headers
+}
@@ -0,0 +1,394 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cascmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	casstore "github.com/trustbloc/orb/pkg/store/cas"
+	"github.com/trustbloc/orb/pkg/webcas"
+)
+
+const pageSize = 3
+
+func TestCASCmd(t *testing.T) {
+	t.Run("test missing subcommand", func(t *testing.T) {
+		err := GetCmd().Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting subcommand sync")
+	})
+}
+
+func TestSyncCmd(t *testing.T) {
+	t.Run("Sync to destination which doesn't support HEAD", func(t *testing.T) {
+		source := newTestNode(t)
+		dest := newTestNode(t)
+
+		hashes := source.write(t, 7)
+		dest.write(t, 2)
+
+		out, _, err := executeSyncCmd(t, source, dest, "--"+concurrencyFlagName, "2")
+		require.NoError(t, err)
+		require.Contains(t, out, "Synced 3 page(s): 7 hash(es) processed, 5 copied, 2 already present, 0 failed")
+
+		dest.requireContains(t, hashes)
+
+		// All of the content is already present so nothing is copied.
+		out, _, err = executeSyncCmd(t, source, dest)
+		require.NoError(t, err)
+		require.Contains(t, out, "7 hash(es) processed, 0 copied, 7 already present, 0 failed, 0 bytes transferred")
+	})
+
+	t.Run("Sync to destination which supports HEAD", func(t *testing.T) {
+		source := newTestNode(t)
+		dest := newTestNode(t, withHead())
+
+		hashes := source.write(t, 4)
+
+		out, _, err := executeSyncCmd(t, source, dest)
+		require.NoError(t, err)
+		require.Contains(t, out, "4 hash(es) processed, 4 copied, 0 already present, 0 failed")
+
+		dest.requireContains(t, hashes)
+	})
+
+	t.Run("Resume from checkpoint", func(t *testing.T) {
+		source := newTestNode(t)
+		dest := newTestNode(t)
+
+		source.write(t, 5)
+
+		// Add a hash to the listing which isn't held by the source, so that it fails to sync.
+		content := []byte("content added later")
+
+		missingHash, err := hashlink.New().CreateResourceHash(content)
+		require.NoError(t, err)
+
+		source.lister.add(missingHash)
+
+		failedPageNum := sort.SearchStrings(source.lister.hashes, missingHash) / pageSize
+
+		checkpointFile := filepath.Join(t.TempDir(), "checkpoint")
+
+		_, errOut, err := executeSyncCmd(t, source, dest, "--"+checkpointFileFlagName, checkpointFile)
+		require.ErrorIs(t, err, errSyncFailed)
+		require.Contains(t, errOut, "Failed to sync "+missingHash)
+
+		if failedPageNum == 0 {
+			require.NoFileExists(t, checkpointFile)
+		} else {
+			checkpoint, err := os.ReadFile(checkpointFile) //nolint:gosec
+			require.NoError(t, err)
+			require.Equal(t, source.pageURL(failedPageNum), string(checkpoint))
+		}
+
+		_, err = source.cas.Write(content)
+		require.NoError(t, err)
+
+		out, errOut, err := executeSyncCmd(t, source, dest, "--"+checkpointFileFlagName, checkpointFile)
+		require.NoError(t, err)
+		require.Contains(t, out, "1 copied")
+
+		if failedPageNum > 0 {
+			require.Contains(t, errOut, "Resuming sync from checkpoint "+source.pageURL(failedPageNum))
+		}
+
+		require.NoFileExists(t, checkpointFile)
+
+		dest.requireContains(t, source.lister.hashes)
+	})
+
+	t.Run("Content doesn't match hash", func(t *testing.T) {
+		source := newTestNode(t)
+		dest := newTestNode(t)
+
+		source.write(t, 1)
+
+		// Serve content for the hash which doesn't match the hash.
+		badHash, err := hashlink.New().CreateResourceHash([]byte("bad"))
+		require.NoError(t, err)
+
+		require.NoError(t, source.store.Put(badHash, []byte("not bad")))
+
+		source.lister.add(badHash)
+
+		_, errOut, err := executeSyncCmd(t, source, dest)
+		require.ErrorIs(t, err, errSyncFailed)
+		require.Contains(t, errOut, "doesn't match")
+
+		_, err = dest.cas.Read(badHash)
+		require.Error(t, err)
+	})
+
+	t.Run("Empty collection", func(t *testing.T) {
+		out, _, err := executeSyncCmd(t, newTestNode(t), newTestNode(t))
+		require.NoError(t, err)
+		require.Contains(t, out, "0 hash(es) processed")
+	})
+
+	t.Run("Source error", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{
+			"sync",
+			"--" + hashesURLFlagName, "http://localhost:1/services/orb/cas-hashes",
+			"--" + sourceCASURLFlagName, "http://localhost:1/cas",
+			"--" + destinationCASURLFlagName, "http://localhost:1/cas",
+		})
+
+		cmd.SetOut(&bytes.Buffer{})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get CAS hashes collection")
+	})
+}
+
+func TestSyncCmd_Args(t *testing.T) {
+	t.Run("Missing hashes URL", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"sync"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), hashesURLFlagName)
+	})
+
+	t.Run("Missing destination URL", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{
+			"sync",
+			"--" + hashesURLFlagName, "https://orb1.domain.com/services/orb/cas-hashes",
+			"--" + sourceCASURLFlagName, "https://orb1.domain.com/cas",
+		})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), destinationCASURLFlagName)
+	})
+
+	t.Run("Invalid concurrency", func(t *testing.T) {
+		for _, v := range []string{"xxx", "0"} {
+			cmd := GetCmd()
+			cmd.SetArgs([]string{
+				"sync",
+				"--" + hashesURLFlagName, "https://orb1.domain.com/services/orb/cas-hashes",
+				"--" + sourceCASURLFlagName, "https://orb1.domain.com/cas",
+				"--" + destinationCASURLFlagName, "https://orb2.domain.com/cas",
+				"--" + concurrencyFlagName, v,
+			})
+
+			err := cmd.Execute()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), concurrencyFlagName)
+		}
+	})
+}
+
+func executeSyncCmd(t *testing.T, source, dest *testNode, args ...string) (string, string, error) {
+	t.Helper()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	cmd := GetCmd()
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(append([]string{
+		"sync",
+		"--" + hashesURLFlagName, source.serviceURL + resthandler.CASHashesPath,
+		"--" + sourceCASURLFlagName, source.url + webcas.DefaultPath,
+		"--" + destinationCASURLFlagName, dest.url + webcas.DefaultPath,
+	}, args...))
+
+	err := cmd.Execute()
+
+	return stdout.String(), stderr.String(), err
+}
+
+// testNode is an in-memory node which serves the WebCAS endpoint (GET and PUT) and the CAS hashes endpoint.
+type testNode struct {
+	url        string
+	serviceURL string
+	store      storage.Store
+	cas        *casstore.CAS
+	lister     *testHashLister
+}
+
+type testNodeOpts struct {
+	headSupported bool
+}
+
+func withHead() func(*testNodeOpts) {
+	return func(opts *testNodeOpts) {
+		opts.headSupported = true
+	}
+}
+
+func newTestNode(t *testing.T, opts ...func(*testNodeOpts)) *testNode {
+	t.Helper()
+
+	options := &testNodeOpts{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	router := mux.NewRouter()
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	provider := mem.NewProvider()
+
+	casClient, err := casstore.New(provider, server.URL+webcas.DefaultPath, nil, &orbmocks.MetricsProvider{}, 0)
+	require.NoError(t, err)
+
+	store, err := provider.OpenStore("cas")
+	require.NoError(t, err)
+
+	n := &testNode{
+		url:        server.URL,
+		serviceURL: server.URL + "/services/orb",
+		store:      store,
+		cas:        casClient,
+		lister:     &testHashLister{},
+	}
+
+	serviceIRI, err := url.Parse(n.serviceURL)
+	require.NoError(t, err)
+
+	apStore := memstore.New("")
+
+	reader := webcas.New(&resthandler.Config{}, apStore, &mocks.SignatureVerifier{}, casClient,
+		&apmocks.AuthTokenMgr{})
+
+	getMethods := []string{http.MethodGet}
+	if options.headSupported {
+		getMethods = append(getMethods, http.MethodHead)
+	}
+
+	router.HandleFunc(reader.Path(), reader.Handler()).Methods(getMethods...)
+
+	writer := webcas.NewWriter(&resthandler.Config{}, apStore, casClient, &apmocks.AuthTokenMgr{})
+
+	router.HandleFunc(writer.Path(), writer.Handler()).Methods(writer.Method())
+
+	hashes := resthandler.NewCASHashes(&resthandler.Config{
+		BasePath:           "/services/orb",
+		ObjectIRI:          serviceIRI,
+		ServiceEndpointURL: serviceIRI,
+		PageSize:           pageSize,
+	}, apStore, n.lister, &mocks.SignatureVerifier{}, &apmocks.AuthTokenMgr{})
+
+	router.HandleFunc(hashes.Path(), hashes.Handler()).Methods(hashes.Method())
+
+	return n
+}
+
+// write writes the given number of blobs to the node's CAS and returns their hashes.
+func (n *testNode) write(t *testing.T, num int) []string {
+	t.Helper()
+
+	var hashes []string
+
+	for i := 0; i < num; i++ {
+		hl, err := n.cas.Write([]byte(fmt.Sprintf("blob %d", i)))
+		require.NoError(t, err)
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		n.lister.add(rh)
+
+		hashes = append(hashes, rh)
+	}
+
+	return hashes
+}
+
+func (n *testNode) requireContains(t *testing.T, hashes []string) {
+	t.Helper()
+
+	for _, rh := range hashes {
+		_, err := n.cas.Read(rh)
+		require.NoError(t, err)
+	}
+}
+
+func (n *testNode) pageURL(pageNum int) string {
+	return fmt.Sprintf("%s%s?page=true&page-num=%d", n.serviceURL, resthandler.CASHashesPath, pageNum)
+}
+
+// testHashLister lists the resource hashes in ascending order.
+type testHashLister struct {
+	hashes []string
+}
+
+func (l *testHashLister) add(rh string) {
+	l.hashes = append(l.hashes, rh)
+
+	sort.Strings(l.hashes)
+}
+
+func (l *testHashLister) GetResourceHashes(opts ...storage.QueryOption) (casstore.ResourceHashIterator, error) {
+	options := &storage.QueryOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	start := options.InitialPageNum * options.PageSize
+	if start > len(l.hashes) {
+		start = len(l.hashes)
+	}
+
+	return &testHashIterator{hashes: l.hashes[start:], totalItems: len(l.hashes)}, nil
+}
+
+type testHashIterator struct {
+	hashes     []string
+	totalItems int
+	current    int
+}
+
+func (it *testHashIterator) TotalItems() (int, error) {
+	return it.totalItems, nil
+}
+
+func (it *testHashIterator) Next() (string, error) {
+	if it.current >= len(it.hashes) {
+		return "", casstore.ErrDataNotFound
+	}
+
+	rh := it.hashes[it.current]
+	it.current++
+
+	return rh, nil
+}
+
+func (it *testHashIterator) Close() error {
+	return nil
+}
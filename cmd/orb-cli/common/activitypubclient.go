@@ -35,7 +35,7 @@ func NewActivityPubClient(cmd *cobra.Command) (*ActivityPubClient, error) {
 		return nil, err
 	}
 
-	headers := newAuthTokenHeader(cmd)
+	headers := NewAuthTokenHeader(cmd)
 
 	overridesMap := make(map[string]string)
 
@@ -32,6 +32,7 @@ import (
 	jwk2 "github.com/hyperledger/aries-framework-go/pkg/doc/jose/jwk"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/trustbloc/logutil-go/pkg/log"
 	"github.com/trustbloc/sidetree-go/pkg/jws"
 	"github.com/trustbloc/sidetree-go/pkg/util/ecsigner"
@@ -41,6 +42,7 @@ import (
 
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
 	"github.com/trustbloc/orb/internal/pkg/tlsutil"
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
 )
 
 var logger = log.New("orb-cli")
@@ -63,6 +65,43 @@ const (
 	// TLSCACertsEnvKey defines the environment variable for the CA certs flag.
 	TLSCACertsEnvKey = "ORB_CLI_TLS_CACERTS"
 
+	// TLSCACertFlagName defines the flag for the CA bundle.
+	TLSCACertFlagName = "tls-cacert"
+	// TLSCACertFlagUsage defines the usage of the CA bundle flag.
+	TLSCACertFlagUsage = "Path to a PEM-encoded CA bundle (which may contain multiple certificates) used to" +
+		" verify the server's certificate." +
+		" Alternatively, this can be set with the following environment variable: " + TLSCACertEnvKey
+	// TLSCACertEnvKey defines the environment variable for the CA bundle flag.
+	TLSCACertEnvKey = "ORB_CLI_TLS_CACERT"
+
+	// TLSCertFlagName defines the flag for the client certificate.
+	TLSCertFlagName = "tls-cert"
+	// TLSCertFlagUsage defines the usage of the client certificate flag.
+	TLSCertFlagUsage = "Path to a PEM-encoded client certificate used for mutual TLS. If set then " +
+		TLSKeyFlagName + " must also be set." +
+		" Alternatively, this can be set with the following environment variable: " + TLSCertEnvKey
+	// TLSCertEnvKey defines the environment variable for the client certificate flag.
+	TLSCertEnvKey = "ORB_CLI_TLS_CERT"
+
+	// TLSKeyFlagName defines the flag for the client private key.
+	TLSKeyFlagName = "tls-key"
+	// TLSKeyFlagUsage defines the usage of the client private key flag.
+	TLSKeyFlagUsage = "Path to the PEM-encoded private key of the client certificate used for mutual TLS." +
+		" If set then " + TLSCertFlagName + " must also be set." +
+		" Alternatively, this can be set with the following environment variable: " + TLSKeyEnvKey
+	// TLSKeyEnvKey defines the environment variable for the client private key flag.
+	TLSKeyEnvKey = "ORB_CLI_TLS_KEY"
+
+	// TLSInsecureFlagName defines the flag for skipping verification of the server's certificate.
+	TLSInsecureFlagName = "tls-insecure"
+	// TLSInsecureFlagUsage defines the usage of the insecure flag.
+	TLSInsecureFlagUsage = "Skips verification of the server's certificate chain and host name." +
+		" WARNING: This is insecure and should only be used for testing." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + TLSInsecureEnvKey
+	// TLSInsecureEnvKey defines the environment variable for the insecure flag.
+	TLSInsecureEnvKey = "ORB_CLI_TLS_INSECURE"
+
 	// AuthTokenFlagName defines the flag for the authorization bearer token.
 	AuthTokenFlagName = "auth-token"
 	// AuthTokenFlagUsage defines the usage of the authorization bearer token flag.
@@ -261,6 +300,10 @@ func GetVDRPublicKeysFromFile(publicKeyFilePath string) (*docdid.Doc, error) { /
 		return nil, fmt.Errorf("failed to public key file '%s' : %w", publicKeyFilePath, err)
 	}
 
+	if err := ValidatePublicKeys(pkData); err != nil {
+		return nil, fmt.Errorf("invalid public key file '%s': %w", publicKeyFilePath, err)
+	}
+
 	var publicKeys []PublicKey
 	if err := json.Unmarshal(pkData, &publicKeys); err != nil {
 		return nil, err
@@ -268,11 +311,7 @@ func GetVDRPublicKeysFromFile(publicKeyFilePath string) (*docdid.Doc, error) { /
 
 	didDoc := &docdid.Doc{}
 
-	for _, v := range publicKeys {
-		if (v.JWKPath == "") == (v.B58Key == "") {
-			return nil, fmt.Errorf("public key needs exactly one of jwkPath and b58Key")
-		}
-
+	for i, v := range publicKeys {
 		var vm *docdid.VerificationMethod
 
 		if v.JWKPath != "" {
@@ -281,6 +320,11 @@ func GetVDRPublicKeysFromFile(publicKeyFilePath string) (*docdid.Doc, error) { /
 				return nil, fmt.Errorf("failed to read jwk file '%s' : %w", v.JWKPath, err)
 			}
 
+			if err := validateJWK(jwkData); err != nil {
+				return nil, fmt.Errorf("invalid public key file '%s': %w", publicKeyFilePath,
+					&ValidationError{Pointer: jsonPointer(i, "jwkPath"), Reason: fmt.Sprintf("%s: %s", v.JWKPath, err)})
+			}
+
 			var jwk jwk2.JWK
 			if errUnmarshal := jwk.UnmarshalJSON(jwkData); errUnmarshal != nil {
 				return nil, fmt.Errorf("failed to unmarshal to jwk: %w", errUnmarshal)
@@ -327,6 +371,10 @@ func GetServices(serviceFilePath string) ([]docdid.Service, error) {
 		return nil, fmt.Errorf("failed to service file '%s' : %w", serviceFilePath, err)
 	}
 
+	if err := ValidateServices(svcData); err != nil {
+		return nil, fmt.Errorf("invalid service file '%s': %w", serviceFilePath, err)
+	}
+
 	var services []docdid.Service
 	if err := json.Unmarshal(svcData, &services); err != nil {
 		return nil, err
@@ -339,21 +387,48 @@ func GetServices(serviceFilePath string) ([]docdid.Service, error) {
 func SendRequest(httpClient *http.Client, req []byte, headers map[string]string, method,
 	endpointURL string,
 ) ([]byte, error) {
+	return SendRequestWithContext(context.Background(), httpClient, req, headers, method, endpointURL)
+}
+
+// SendRequestWithContext sends the http request using the given context. The request is aborted
+// if the context is done before the response is received.
+func SendRequestWithContext(ctx context.Context, httpClient *http.Client, req []byte, headers map[string]string,
+	method, endpointURL string,
+) ([]byte, error) {
+	responseBytes, statusCode, err := SendRequestForStatus(ctx, httpClient, req, headers, method, endpointURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("got unexpected response from %s status '%d' body %s",
+			endpointURL, statusCode, responseBytes)
+	}
+
+	return responseBytes, nil
+}
+
+// SendRequestForStatus sends the http request using the given context and returns the response body along
+// with the status code. Unlike SendRequestWithContext, an error status is not treated as an error, i.e. an
+// error is returned only if the request couldn't be sent or the response couldn't be read.
+func SendRequestForStatus(ctx context.Context, httpClient *http.Client, req []byte, headers map[string]string,
+	method, endpointURL string,
+) ([]byte, int, error) {
 	var httpReq *http.Request
 
 	var err error
 
 	if len(req) == 0 {
-		httpReq, err = http.NewRequestWithContext(context.Background(),
+		httpReq, err = http.NewRequestWithContext(ctx,
 			method, endpointURL, http.NoBody)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create http request: %w", err)
+			return nil, 0, fmt.Errorf("failed to create http request: %w", err)
 		}
 	} else {
-		httpReq, err = http.NewRequestWithContext(context.Background(),
+		httpReq, err = http.NewRequestWithContext(ctx,
 			method, endpointURL, bytes.NewBuffer(req))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create http request: %w", err)
+			return nil, 0, fmt.Errorf("failed to create http request: %w", err)
 		}
 	}
 
@@ -363,22 +438,17 @@ func SendRequest(httpClient *http.Client, req []byte, headers map[string]string,
 
 	resp, err := httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, 0, fmt.Errorf("failed to send request: %w", err)
 	}
 
 	defer closeResponseBody(resp.Body)
 
 	responseBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response : %w", err)
+		return nil, 0, fmt.Errorf("failed to read response : %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("got unexpected response from %s status '%d' body %s",
-			endpointURL, resp.StatusCode, responseBytes)
-	}
-
-	return responseBytes, nil
+	return responseBytes, resp.StatusCode, nil
 }
 
 // SendHTTPRequest sends the given HTTP request using the options provided on the command-line.
@@ -388,7 +458,7 @@ func SendHTTPRequest(cmd *cobra.Command, reqBytes []byte, method, endpointURL st
 		return nil, err
 	}
 
-	return SendRequest(c, reqBytes, newAuthTokenHeader(cmd), method, endpointURL)
+	return SendRequestWithContext(commandContext(cmd), c, reqBytes, NewAuthTokenHeader(cmd), method, endpointURL)
 }
 
 func closeResponseBody(respBody io.Closer) {
@@ -397,46 +467,134 @@ func closeResponseBody(respBody io.Closer) {
 	}
 }
 
-// NewHTTPClient returns a new HTTP client using the arguments from the given command.
+// NewHTTPClient returns a new HTTP client using the arguments from the given command. The client sets the
+// User-Agent and request ID headers on each request.
 func NewHTTPClient(cmd *cobra.Command) (*http.Client, error) {
-	rootCAs, err := getRootCAs(cmd)
+	httpTransport, err := newHTTPTransport(cmd)
 	if err != nil {
 		return nil, err
 	}
 
 	return &http.Client{
-		Transport: &http.Transport{
-			ForceAttemptHTTP2: true,
-			TLSClientConfig: &tls.Config{
-				RootCAs:    rootCAs,
-				MinVersion: tls.VersionTLS12,
-			},
-		},
+		Transport: transport.NewHeaderInjector(httpTransport, getUserAgent(cmd)),
 	}, nil
 }
 
-func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
-	tlsSystemCertPoolString := cmdutil.GetUserSetOptionalVarFromString(cmd, TLSSystemCertPoolFlagName,
-		TLSSystemCertPoolEnvKey)
+func newHTTPTransport(cmd *cobra.Command) (*http.Transport, error) {
+	tlsConfig, err := NewTLSConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
 
-	tlsSystemCertPool := false
+	return &http.Transport{
+		ForceAttemptHTTP2: true,
+		TLSClientConfig:   tlsConfig,
+	}, nil
+}
 
-	if tlsSystemCertPoolString != "" {
-		var err error
-		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+// NewTLSConfig returns the TLS configuration (root CAs, client certificate, etc.) using the
+// arguments from the given command.
+func NewTLSConfig(cmd *cobra.Command) (*tls.Config, error) {
+	rootCAs, err := getRootCAs(cmd)
+	if err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	clientCerts, err := getClientCertificates(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	insecure, err := getBool(cmd, TLSInsecureFlagName, TLSInsecureEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if insecure {
+		logger.Warn("!!! WARNING: TLS certificate verification is disabled. Connections are vulnerable to " +
+			"man-in-the-middle attacks. This option should only be used for testing. !!!")
+	}
+
+	return &tls.Config{
+		RootCAs:            rootCAs,
+		Certificates:       clientCerts,
+		InsecureSkipVerify: insecure, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+	}, nil
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPool, err := getBool(cmd, TLSSystemCertPoolFlagName, TLSSystemCertPoolEnvKey)
+	if err != nil {
+		return nil, err
 	}
 
 	tlsCACerts := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, TLSCACertsFlagName,
 		TLSCACertsEnvKey)
 
-	return tlsutil.GetCertPool(tlsSystemCertPool, tlsCACerts)
+	rootCAs, err := tlsutil.GetCertPool(tlsSystemCertPool, tlsCACerts)
+	if err != nil {
+		return nil, err
+	}
+
+	caBundleFile := cmdutil.GetUserSetOptionalVarFromString(cmd, TLSCACertFlagName, TLSCACertEnvKey)
+	if caBundleFile == "" {
+		return rootCAs, nil
+	}
+
+	caBundle, err := os.ReadFile(filepath.Clean(caBundleFile))
+	if err != nil {
+		return nil, fmt.Errorf("read CA bundle [%s]: %w", caBundleFile, err)
+	}
+
+	if rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	if !rootCAs.AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("no certificates found in CA bundle [%s]", caBundleFile)
+	}
+
+	return rootCAs, nil
+}
+
+func getClientCertificates(cmd *cobra.Command) ([]tls.Certificate, error) {
+	certFile := cmdutil.GetUserSetOptionalVarFromString(cmd, TLSCertFlagName, TLSCertEnvKey)
+	keyFile := cmdutil.GetUserSetOptionalVarFromString(cmd, TLSKeyFlagName, TLSKeyEnvKey)
+
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("both client certificate (--%s) and key (--%s) must be specified",
+			TLSCertFlagName, TLSKeyFlagName)
+	}
+
+	cert, err := tls.LoadX509KeyPair(filepath.Clean(certFile), filepath.Clean(keyFile))
+	if err != nil {
+		return nil, fmt.Errorf("load client certificate: %w", err)
+	}
+
+	return []tls.Certificate{cert}, nil
 }
 
-func newAuthTokenHeader(cmd *cobra.Command) map[string]string {
+func getBool(cmd *cobra.Command, flagName, envKey string) (bool, error) {
+	value := cmdutil.GetUserSetOptionalVarFromString(cmd, flagName, envKey)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s [%s]: %w", flagName, value, err)
+	}
+
+	return b, nil
+}
+
+// NewAuthTokenHeader returns the authorization header for the auth token provided on the command-line (if any).
+func NewAuthTokenHeader(cmd *cobra.Command) map[string]string {
 	headers := make(map[string]string)
 
 	authToken := cmdutil.GetUserSetOptionalVarFromString(cmd, AuthTokenFlagName, AuthTokenEnvKey)
@@ -476,6 +634,15 @@ func AddCommonFlags(cmd *cobra.Command) {
 	cmd.Flags().StringArrayP(TargetOverrideFlagName, "", nil, TargetOverrideFlagUsage)
 }
 
+// AddTLSFlags adds the TLS flags (CA bundle, client certificate, etc.) to the given flag set. These flags
+// are registered as persistent flags on the root command so that they apply to all subcommands.
+func AddTLSFlags(flags *pflag.FlagSet) {
+	flags.StringP(TLSCACertFlagName, "", "", TLSCACertFlagUsage)
+	flags.StringP(TLSCertFlagName, "", "", TLSCertFlagUsage)
+	flags.StringP(TLSKeyFlagName, "", "", TLSKeyFlagUsage)
+	flags.StringP(TLSInsecureFlagName, "", "", TLSInsecureFlagUsage)
+}
+
 // Signer operation.
 type Signer struct {
 	signer             client.Signer
@@ -12,11 +12,16 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
@@ -430,6 +435,102 @@ func TestSendHTTPRequest(t *testing.T) {
 	})
 }
 
+func TestNewTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	caCert, caKey := newTestCertificate(t, nil, nil, "Test CA")
+	clientCert, clientKey := newTestCertificate(t, caCert, caKey, "Test Client")
+	serverCert, _ := newTestCertificate(t, caCert, caKey, "orb.domain1.com")
+
+	caFile := writeCertificate(t, dir, "ca.pem", caCert)
+	clientCertFile := writeCertificate(t, dir, "client.pem", clientCert)
+	clientKeyFile := writePrivateKey(t, dir, "client-key.pem", clientKey)
+
+	t.Run("success - CA bundle and client certificate", func(t *testing.T) {
+		cmd := newTLSFlagsCmd(t,
+			"--"+TLSCACertFlagName, caFile,
+			"--"+TLSCertFlagName, clientCertFile,
+			"--"+TLSKeyFlagName, clientKeyFile,
+		)
+
+		tlsConfig, err := NewTLSConfig(cmd)
+		require.NoError(t, err)
+		require.NotNil(t, tlsConfig.RootCAs)
+		require.False(t, tlsConfig.InsecureSkipVerify)
+
+		// The server certificate must be verifiable using the CA from the bundle.
+		_, err = serverCert.Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs})
+		require.NoError(t, err)
+
+		require.Len(t, tlsConfig.Certificates, 1)
+		require.Len(t, tlsConfig.Certificates[0].Certificate, 1)
+		require.Equal(t, clientCert.Raw, tlsConfig.Certificates[0].Certificate[0])
+	})
+
+	t.Run("success - HTTP client uses TLS config", func(t *testing.T) {
+		cmd := newTLSFlagsCmd(t, "--"+TLSCACertFlagName, caFile)
+
+		httpClient, err := NewHTTPClient(cmd)
+		require.NoError(t, err)
+		require.NotNil(t, httpClient)
+
+		transport, err := newHTTPTransport(cmd)
+		require.NoError(t, err)
+
+		_, err = serverCert.Verify(x509.VerifyOptions{Roots: transport.TLSClientConfig.RootCAs})
+		require.NoError(t, err)
+	})
+
+	t.Run("success - no TLS flags", func(t *testing.T) {
+		tlsConfig, err := NewTLSConfig(newTLSFlagsCmd(t))
+		require.NoError(t, err)
+		require.Empty(t, tlsConfig.Certificates)
+		require.False(t, tlsConfig.InsecureSkipVerify)
+
+		_, err = serverCert.Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs})
+		require.Error(t, err)
+	})
+
+	t.Run("success - insecure", func(t *testing.T) {
+		tlsConfig, err := NewTLSConfig(newTLSFlagsCmd(t, "--"+TLSInsecureFlagName, "true"))
+		require.NoError(t, err)
+		require.True(t, tlsConfig.InsecureSkipVerify)
+	})
+
+	t.Run("error - invalid insecure value", func(t *testing.T) {
+		_, err := NewTLSConfig(newTLSFlagsCmd(t, "--"+TLSInsecureFlagName, "xxx"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for "+TLSInsecureFlagName)
+	})
+
+	t.Run("error - CA bundle not found", func(t *testing.T) {
+		_, err := NewTLSConfig(newTLSFlagsCmd(t, "--"+TLSCACertFlagName, filepath.Join(dir, "invalid.pem")))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read CA bundle")
+	})
+
+	t.Run("error - no certificates in CA bundle", func(t *testing.T) {
+		_, err := NewTLSConfig(newTLSFlagsCmd(t, "--"+TLSCACertFlagName, clientKeyFile))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no certificates found in CA bundle")
+	})
+
+	t.Run("error - client key not specified", func(t *testing.T) {
+		_, err := NewTLSConfig(newTLSFlagsCmd(t, "--"+TLSCertFlagName, clientCertFile))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "both client certificate")
+	})
+
+	t.Run("error - invalid client key", func(t *testing.T) {
+		_, err := NewTLSConfig(newTLSFlagsCmd(t,
+			"--"+TLSCertFlagName, clientCertFile,
+			"--"+TLSKeyFlagName, caFile,
+		))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "load client certificate")
+	})
+}
+
 func TestGetVDRPublicKeys(t *testing.T) {
 	t.Run("test public key invalid path", func(t *testing.T) {
 		_, err := GetVDRPublicKeysFromFile("./wrongfile")
@@ -540,3 +641,74 @@ func newMockCmd(runFUnc func(cmd *cobra.Command, args []string) error) *cobra.Co
 
 	return cmd
 }
+
+func newTLSFlagsCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := newMockCmd(nil)
+
+	AddTLSFlags(cmd.Flags())
+
+	require.NoError(t, cmd.ParseFlags(args))
+
+	return cmd
+}
+
+func newTestCertificate(t *testing.T, issuer *x509.Certificate, issuerKey *ecdsa.PrivateKey,
+	commonName string,
+) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              []string{commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  issuer == nil,
+	}
+
+	if issuer == nil {
+		issuer = template
+		issuerKey = key
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(certBytes)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+func writeCertificate(t *testing.T, dir, name string, cert *x509.Certificate) string {
+	t.Helper()
+
+	return writePEM(t, dir, name, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func writePrivateKey(t *testing.T, dir, name string, key *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return writePEM(t, dir, name, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+}
+
+func writePEM(t *testing.T, dir, name string, block *pem.Block) string {
+	t.Helper()
+
+	file := filepath.Join(dir, name)
+
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(block), 0o600))
+
+	return file
+}
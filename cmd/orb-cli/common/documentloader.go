@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/cachedstore"
+	ariesmemstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/ld"
+	ldstore "github.com/hyperledger/aries-framework-go/pkg/store/ld"
+	jsonld "github.com/piprate/json-gold/ld"
+
+	"github.com/trustbloc/orb/internal/pkg/ldcontext"
+)
+
+// NewDocumentLoader returns a JSON-LD document loader, backed by in-memory storage, which is preloaded
+// with the Orb contexts.
+func NewDocumentLoader() (jsonld.DocumentLoader, error) {
+	ldStorageProvider := cachedstore.NewProvider(ariesmemstorage.NewProvider(), ariesmemstorage.NewProvider())
+
+	contextStore, err := ldstore.NewContextStore(ldStorageProvider)
+	if err != nil {
+		return nil, fmt.Errorf("create JSON-LD context store: %w", err)
+	}
+
+	remoteProviderStore, err := ldstore.NewRemoteProviderStore(ldStorageProvider)
+	if err != nil {
+		return nil, fmt.Errorf("create remote provider store: %w", err)
+	}
+
+	ldStore := &ldStoreProvider{
+		ContextStore:        contextStore,
+		RemoteProviderStore: remoteProviderStore,
+	}
+
+	docLoader, err := ld.NewDocumentLoader(ldStore, ld.WithExtraContexts(ldcontext.MustGetAll()...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Orb contexts: %w", err)
+	}
+
+	return docLoader, nil
+}
+
+type ldStoreProvider struct {
+	ContextStore        ldstore.ContextStore
+	RemoteProviderStore ldstore.RemoteProviderStore
+}
+
+func (p *ldStoreProvider) JSONLDContextStore() ldstore.ContextStore {
+	return p.ContextStore
+}
+
+func (p *ldStoreProvider) JSONLDRemoteProviderStore() ldstore.RemoteProviderStore {
+	return p.RemoteProviderStore
+}
@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+const (
+	// DIDNamespaceFlagName defines the flag for the DID namespace.
+	DIDNamespaceFlagName = "did-namespace"
+	// DIDNamespaceFlagUsage defines the usage of the DID namespace flag.
+	DIDNamespaceFlagUsage = "The namespace of the DIDs served by the Orb server, which must match the server's" +
+		" --did-namespace setting. Defaults to " + DefaultDIDNamespace + " if not set." +
+		" Alternatively, this can be set with the following environment variable: " + DIDNamespaceEnvKey
+	// DIDNamespaceEnvKey defines the environment variable for the DID namespace flag.
+	DIDNamespaceEnvKey = "ORB_CLI_DID_NAMESPACE"
+
+	// DefaultDIDNamespace is the default namespace of Orb DIDs.
+	DefaultDIDNamespace = "did:orb"
+)
+
+// AddDIDNamespaceFlag adds the DID namespace flag to the given flag set. The flag is registered as a persistent
+// flag on the root command so that it applies to all subcommands.
+func AddDIDNamespaceFlag(flags *pflag.FlagSet) {
+	flags.StringP(DIDNamespaceFlagName, "", "", DIDNamespaceFlagUsage)
+}
+
+// GetDIDNamespace returns the DID namespace set by the flag (or environment variable) or the default
+// namespace if not set.
+func GetDIDNamespace(cmd *cobra.Command) string {
+	namespace, err := cmdutil.GetUserSetVarFromString(cmd, DIDNamespaceFlagName, DIDNamespaceEnvKey, true)
+	if err != nil || namespace == "" {
+		return DefaultDIDNamespace
+	}
+
+	return namespace
+}
@@ -0,0 +1,42 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDIDNamespace(t *testing.T) {
+	newCmd := func(t *testing.T, args ...string) *cobra.Command {
+		t.Helper()
+
+		cmd := &cobra.Command{Use: "mock"}
+
+		AddDIDNamespaceFlag(cmd.Flags())
+
+		require.NoError(t, cmd.ParseFlags(args))
+
+		return cmd
+	}
+
+	t.Run("default", func(t *testing.T) {
+		require.Equal(t, DefaultDIDNamespace, GetDIDNamespace(newCmd(t)))
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		require.Equal(t, "did:ex", GetDIDNamespace(newCmd(t, "--"+DIDNamespaceFlagName, "did:ex")))
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(DIDNamespaceEnvKey, "did:ex2")
+
+		require.Equal(t, "did:ex2", GetDIDNamespace(newCmd(t)))
+	})
+}
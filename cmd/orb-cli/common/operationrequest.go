@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+const (
+	// OutputRequestFlagName defines the flag for writing the operation request instead of sending it.
+	OutputRequestFlagName = "output-request"
+	// OutputRequestFlagUsage defines the usage of the output request flag.
+	OutputRequestFlagUsage = "If set then the signed operation request is written to the given file" +
+		" (or to stdout if the value is '" + OutputRequestStdout + "') instead of being sent to the Sidetree" +
+		" operations endpoint. The request may then be submitted with the 'did submit' command." +
+		" Alternatively, this can be set with the following environment variable: " + OutputRequestEnvKey
+	// OutputRequestEnvKey defines the environment variable for the output request flag.
+	OutputRequestEnvKey = "ORB_CLI_OUTPUT_REQUEST"
+
+	// OutputRequestStdout is the value of the output request flag which writes the request to stdout.
+	OutputRequestStdout = "-"
+)
+
+// ErrRequestCaptured is returned by the RequestCapture transport after it captures an operation request.
+var ErrRequestCaptured = errors.New("operation request captured")
+
+// AddOutputRequestFlag adds the output request flag to the given command.
+func AddOutputRequestFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP(OutputRequestFlagName, "", "", OutputRequestFlagUsage)
+}
+
+// RequestCapture is an HTTP transport which captures the body of an operation request (POST) instead of
+// sending it. All other requests (discovery, resolution, etc.) are passed through to the underlying transport.
+type RequestCapture struct {
+	transport http.RoundTripper
+	dest      string
+
+	mutex   sync.Mutex
+	request []byte
+}
+
+// NewRequestCapture returns a RequestCapture if the output request flag is set on the given command,
+// otherwise nil is returned. The captured request is written to the destination specified by the flag.
+func NewRequestCapture(cmd *cobra.Command, transport http.RoundTripper) *RequestCapture {
+	dest := cmdutil.GetUserSetOptionalVarFromString(cmd, OutputRequestFlagName, OutputRequestEnvKey)
+	if dest == "" {
+		return nil
+	}
+
+	return &RequestCapture{
+		transport: transport,
+		dest:      dest,
+	}
+}
+
+// RoundTrip captures the body of a POST request and returns ErrRequestCaptured. Other requests are
+// sent using the underlying transport.
+func (c *RequestCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost {
+		return c.transport.RoundTrip(req)
+	}
+
+	var body []byte
+
+	if req.Body != nil {
+		defer closeResponseBody(req.Body)
+
+		var err error
+
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read operation request: %w", err)
+		}
+	}
+
+	c.mutex.Lock()
+	c.request = body
+	c.mutex.Unlock()
+
+	return nil, ErrRequestCaptured
+}
+
+// Write writes the captured operation request. The given error is the error returned from the DID operation
+// and is expected to be ErrRequestCaptured. Any other error means that the request could not be generated.
+func (c *RequestCapture) Write(out io.Writer, opErr error) error {
+	if opErr != nil && !errors.Is(opErr, ErrRequestCaptured) {
+		return fmt.Errorf("failed to generate operation request: %w", opErr)
+	}
+
+	c.mutex.Lock()
+	request := c.request
+	c.mutex.Unlock()
+
+	if len(request) == 0 {
+		return errors.New("operation request was not generated")
+	}
+
+	if c.dest == OutputRequestStdout {
+		Println(out, string(request))
+
+		return nil
+	}
+
+	err := os.WriteFile(c.dest, request, 0o600)
+	if err != nil {
+		return fmt.Errorf("write operation request to %s: %w", c.dest, err)
+	}
+
+	Printf(out, "Operation request written to %s\n", c.dest)
+
+	return nil
+}
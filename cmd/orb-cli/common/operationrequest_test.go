@@ -0,0 +1,126 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestCapture(t *testing.T) {
+	var posted bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posted = true
+		}
+
+		fmt.Fprint(w, "resolved")
+	}))
+	defer srv.Close()
+
+	t.Run("flag not set", func(t *testing.T) {
+		require.Nil(t, NewRequestCapture(newOutputRequestCmd(t), http.DefaultTransport))
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		capture := NewRequestCapture(newOutputRequestCmd(t, "--"+OutputRequestFlagName, OutputRequestStdout),
+			http.DefaultTransport)
+		require.NotNil(t, capture)
+
+		httpClient := &http.Client{Transport: capture}
+
+		// Requests other than POST are passed through.
+		resp, err := SendRequest(httpClient, nil, nil, http.MethodGet, srv.URL)
+		require.NoError(t, err)
+		require.Equal(t, "resolved", string(resp))
+
+		_, err = SendRequest(httpClient, []byte(`{"type":"create"}`), nil, http.MethodPost, srv.URL)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrRequestCaptured))
+		require.False(t, posted)
+
+		out := &bytes.Buffer{}
+
+		require.NoError(t, capture.Write(out, err))
+		require.Equal(t, "{\"type\":\"create\"}\n", out.String())
+	})
+
+	t.Run("file", func(t *testing.T) {
+		requestFile := filepath.Join(t.TempDir(), "request.json")
+
+		capture := NewRequestCapture(newOutputRequestCmd(t, "--"+OutputRequestFlagName, requestFile),
+			http.DefaultTransport)
+		require.NotNil(t, capture)
+
+		_, err := SendRequest(&http.Client{Transport: capture}, []byte(`{"type":"update"}`), nil,
+			http.MethodPost, srv.URL)
+		require.True(t, errors.Is(err, ErrRequestCaptured))
+
+		out := &bytes.Buffer{}
+
+		require.NoError(t, capture.Write(out, err))
+		require.Contains(t, out.String(), "Operation request written to "+requestFile)
+
+		b, err := os.ReadFile(requestFile)
+		require.NoError(t, err)
+		require.Equal(t, `{"type":"update"}`, string(b))
+	})
+
+	t.Run("write file error", func(t *testing.T) {
+		requestFile := filepath.Join(t.TempDir(), "missing", "request.json")
+
+		capture := NewRequestCapture(newOutputRequestCmd(t, "--"+OutputRequestFlagName, requestFile),
+			http.DefaultTransport)
+
+		_, err := SendRequest(&http.Client{Transport: capture}, []byte(`{}`), nil, http.MethodPost, srv.URL)
+		require.True(t, errors.Is(err, ErrRequestCaptured))
+
+		err = capture.Write(&bytes.Buffer{}, err)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "write operation request")
+	})
+
+	t.Run("operation error", func(t *testing.T) {
+		capture := NewRequestCapture(newOutputRequestCmd(t, "--"+OutputRequestFlagName, OutputRequestStdout),
+			http.DefaultTransport)
+
+		err := capture.Write(&bytes.Buffer{}, errors.New("injected error"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to generate operation request: injected error")
+	})
+
+	t.Run("request not generated", func(t *testing.T) {
+		capture := NewRequestCapture(newOutputRequestCmd(t, "--"+OutputRequestFlagName, OutputRequestStdout),
+			http.DefaultTransport)
+
+		err := capture.Write(&bytes.Buffer{}, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "operation request was not generated")
+	})
+}
+
+func newOutputRequestCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := &cobra.Command{}
+
+	AddOutputRequestFlag(cmd)
+
+	require.NoError(t, cmd.ParseFlags(args))
+
+	return cmd
+}
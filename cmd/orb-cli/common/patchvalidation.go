@@ -0,0 +1,533 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/doc"
+)
+
+const (
+	maxIDLength = 50
+
+	bls12381G2Key2020                 = "Bls12381G2Key2020"
+	ecdsaSecp256k1VerificationKey2019 = "EcdsaSecp256k1VerificationKey2019"
+	ed25519VerificationKey2020        = "Ed25519VerificationKey2020"
+	x25519KeyAgreementKey2019         = "X25519KeyAgreementKey2019"
+)
+
+var idRegex = regexp.MustCompile("^[A-Za-z0-9_-]+$")
+
+var (
+	keyTypes = map[string]bool{
+		bls12381G2Key2020:                 true,
+		doc.JWK2020Type:                   true,
+		doc.JWSVerificationKey2020:        true,
+		ecdsaSecp256k1VerificationKey2019: true,
+		doc.Ed25519VerificationKey2018:    true,
+		ed25519VerificationKey2020:        true,
+		x25519KeyAgreementKey2019:         true,
+	}
+
+	keyPurposes = map[string]bool{
+		doc.KeyPurposeAuthentication:       true,
+		doc.KeyPurposeAssertionMethod:      true,
+		doc.KeyPurposeKeyAgreement:         true,
+		doc.KeyPurposeCapabilityDelegation: true,
+		doc.KeyPurposeCapabilityInvocation: true,
+	}
+
+	publicKeyProperties = map[string]bool{
+		"id": true, "type": true, "purposes": true, "jwkPath": true, "b58Key": true,
+	}
+
+	// jwkCurves contains the supported curves for each JWK key type.
+	jwkCurves = map[string]map[string]bool{
+		"EC":  {"P-256": true, "P-384": true, "P-521": true, "secp256k1": true, "BLS12381_G2": true},
+		"OKP": {"Ed25519": true, "X25519": true},
+	}
+
+	// jsonPatchOperations contains the RFC 6902 operations along with the members that each one requires
+	// (in addition to 'op' and 'path').
+	jsonPatchOperations = map[string][]string{
+		"add":     {"value"},
+		"remove":  nil,
+		"replace": {"value"},
+		"move":    {"from"},
+		"copy":    {"from"},
+		"test":    {"value"},
+	}
+)
+
+// ValidationError describes an invalid element of a user-supplied patch file. Pointer is
+// a JSON pointer (RFC 6901) to the offending element.
+type ValidationError struct {
+	Pointer string
+	Reason  string
+}
+
+// Error returns the JSON pointer along with the reason.
+func (e *ValidationError) Error() string {
+	if e.Pointer == "" {
+		return e.Reason
+	}
+
+	return fmt.Sprintf("%s: %s", e.Pointer, e.Reason)
+}
+
+// ValidationErrors contains all of the validation errors of a patch file.
+type ValidationErrors []*ValidationError
+
+// Error returns all of the validation errors separated by a semicolon.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) addError(pointer, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{Pointer: pointer, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return v.errs
+}
+
+// ValidatePublicKeys validates the contents of a public key file (i.e. the keys to add to a DID).
+// All errors are returned in a ValidationErrors, each with a JSON pointer to the offending element.
+func ValidatePublicKeys(data []byte) error {
+	elements, err := unmarshalArray(data, "public keys")
+	if err != nil {
+		return err
+	}
+
+	v := &validator{}
+	ids := make(map[string]bool)
+
+	for i, element := range elements {
+		pointer := jsonPointer(i)
+
+		pk, ok := element.(map[string]interface{})
+		if !ok {
+			v.addError(pointer, "expecting a public key object")
+
+			continue
+		}
+
+		v.validateID(pointer, pk, ids)
+
+		if keyType, ok := v.validateString(pointer, pk, "type"); ok && !keyTypes[keyType] {
+			v.addError(jsonPointer(i, "type"), "unsupported key type '%s'", keyType)
+		}
+
+		v.validatePurposes(jsonPointer(i, "purposes"), pk)
+		v.validateKeyMaterial(pointer, pk)
+
+		v.validateProperties(pointer, pk)
+	}
+
+	return v.err()
+}
+
+// ValidateServices validates the contents of a service file (i.e. the services to add to a DID).
+// All errors are returned in a ValidationErrors, each with a JSON pointer to the offending element.
+func ValidateServices(data []byte) error {
+	elements, err := unmarshalArray(data, "services")
+	if err != nil {
+		return err
+	}
+
+	v := &validator{}
+	ids := make(map[string]bool)
+
+	for i, element := range elements {
+		pointer := jsonPointer(i)
+
+		svc, ok := element.(map[string]interface{})
+		if !ok {
+			v.addError(pointer, "expecting a service object")
+
+			continue
+		}
+
+		v.validateID(pointer, svc, ids)
+		v.validateServiceType(pointer, svc)
+		v.validateServiceEndpoint(pointer, svc)
+	}
+
+	return v.err()
+}
+
+// ValidateJSONPatch validates the contents of an RFC 6902 JSON patch file. Each operation must be well formed.
+// The document properties that may be modified are enforced by the Sidetree patch validator.
+// All errors are returned in a ValidationErrors, each with a JSON pointer to the offending operation.
+func ValidateJSONPatch(data []byte) error {
+	elements, err := unmarshalArray(data, "JSON patch operations")
+	if err != nil {
+		return err
+	}
+
+	if len(elements) == 0 {
+		return ValidationErrors{{Reason: "JSON patch must contain at least one operation"}}
+	}
+
+	v := &validator{}
+
+	for i, element := range elements {
+		pointer := jsonPointer(i)
+
+		op, ok := element.(map[string]interface{})
+		if !ok {
+			v.addError(pointer, "expecting a JSON patch operation object")
+
+			continue
+		}
+
+		opType, ok := v.validateString(pointer, op, "op")
+		if ok {
+			required, supported := jsonPatchOperations[opType]
+			if !supported {
+				v.addError(pointer+"/op", "unsupported operation '%s'", opType)
+			}
+
+			for _, member := range required {
+				if _, exists := op[member]; !exists {
+					v.addError(pointer, "missing '%s' for '%s' operation", member, opType)
+				}
+			}
+		}
+
+		if path, ok := op["path"].(string); ok {
+			v.validatePatchPath(pointer+"/path", path)
+		} else {
+			v.addError(pointer, "'path' must be a string")
+		}
+
+		if from, exists := op["from"]; exists && (opType == "move" || opType == "copy") {
+			if fromPath, ok := from.(string); ok {
+				v.validatePatchPath(pointer+"/from", fromPath)
+			} else {
+				v.addError(pointer+"/from", "'from' must be a string")
+			}
+		}
+	}
+
+	return v.err()
+}
+
+// validatePatchPath ensures that the given JSON patch path is a JSON pointer to a property of the document.
+func (v *validator) validatePatchPath(pointer, path string) {
+	if path == "" || path == "/" {
+		v.addError(pointer, "the whole document may not be replaced with a JSON patch")
+
+		return
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		v.addError(pointer, "path '%s' must start with '/'", path)
+	}
+}
+
+// validateJWK validates the JWK in the given bytes and returns the reason if invalid.
+func validateJWK(data []byte) error {
+	jwk := make(map[string]interface{})
+
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return fmt.Errorf("failed to unmarshal to jwk: %w", err)
+	}
+
+	kty, ok := jwk["kty"].(string)
+	if !ok || kty == "" {
+		return errors.New("missing 'kty'")
+	}
+
+	curves, ok := jwkCurves[kty]
+	if !ok {
+		return fmt.Errorf("unsupported 'kty' '%s'", kty)
+	}
+
+	crv, ok := jwk["crv"].(string)
+	if !ok || crv == "" {
+		return fmt.Errorf("missing 'crv' for %s key", kty)
+	}
+
+	if !curves[crv] {
+		return fmt.Errorf("unsupported 'crv' '%s' for %s key", crv, kty)
+	}
+
+	coordinates := []string{"x"}
+	if kty == "EC" {
+		coordinates = append(coordinates, "y")
+	}
+
+	for _, c := range coordinates {
+		value, ok := jwk[c].(string)
+		if !ok || value == "" {
+			return fmt.Errorf("missing '%s' for %s key", c, kty)
+		}
+
+		if _, err := base64.RawURLEncoding.DecodeString(value); err != nil {
+			return fmt.Errorf("'%s' is not base64url encoded", c)
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) validateID(pointer string, element map[string]interface{}, ids map[string]bool) {
+	id, ok := v.validateString(pointer, element, "id")
+	if !ok {
+		return
+	}
+
+	idPointer := pointer + "/id"
+
+	switch {
+	case len(id) > maxIDLength:
+		v.addError(idPointer, "id exceeds maximum length of %d", maxIDLength)
+	case !idRegex.MatchString(id):
+		v.addError(idPointer, "id '%s' must only contain characters [A-Za-z0-9_-]", id)
+	case ids[id]:
+		v.addError(idPointer, "duplicate id '%s'", id)
+	}
+
+	ids[id] = true
+}
+
+// validateProperties reports any unsupported properties of a public key, e.g. a misspelled 'purposes'.
+func (v *validator) validateProperties(pointer string, pk map[string]interface{}) {
+	var unsupported []string
+
+	for property := range pk {
+		if !publicKeyProperties[property] {
+			unsupported = append(unsupported, property)
+		}
+	}
+
+	sort.Strings(unsupported)
+
+	for _, property := range unsupported {
+		v.addError(pointer+"/"+escapeToken(property), "unsupported property '%s'", property)
+	}
+}
+
+func (v *validator) validateString(pointer string, element map[string]interface{}, property string) (string, bool) {
+	value, exists := element[property]
+	if !exists {
+		v.addError(pointer, "missing '%s'", property)
+
+		return "", false
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		v.addError(pointer+"/"+escapeToken(property), "'%s' must be a string", property)
+
+		return "", false
+	}
+
+	if str == "" {
+		v.addError(pointer+"/"+escapeToken(property), "'%s' must not be empty", property)
+
+		return "", false
+	}
+
+	return str, true
+}
+
+func (v *validator) validatePurposes(pointer string, pk map[string]interface{}) {
+	value, exists := pk["purposes"]
+	if !exists {
+		return
+	}
+
+	purposes, ok := value.([]interface{})
+	if !ok {
+		v.addError(pointer, "'purposes' must be an array of strings")
+
+		return
+	}
+
+	for j, p := range purposes {
+		purpose, ok := p.(string)
+		if !ok {
+			v.addError(pointer+"/"+strconv.Itoa(j), "purpose must be a string")
+
+			continue
+		}
+
+		if !keyPurposes[purpose] {
+			v.addError(pointer+"/"+strconv.Itoa(j), "public key purpose %s not supported", purpose)
+		}
+	}
+}
+
+func (v *validator) validateKeyMaterial(pointer string, pk map[string]interface{}) {
+	_, hasJWKPath := pk["jwkPath"]
+	_, hasB58Key := pk["b58Key"]
+
+	if hasJWKPath == hasB58Key {
+		v.addError(pointer, "public key needs exactly one of jwkPath and b58Key")
+
+		return
+	}
+
+	if hasJWKPath {
+		v.validateString(pointer, pk, "jwkPath")
+
+		return
+	}
+
+	if b58Key, ok := v.validateString(pointer, pk, "b58Key"); ok && len(base58.Decode(b58Key)) == 0 {
+		v.addError(pointer+"/b58Key", "'b58Key' is not base58 encoded")
+	}
+}
+
+func (v *validator) validateServiceType(svcPointer string, svc map[string]interface{}) {
+	value, exists := svc["type"]
+	if !exists {
+		v.addError(svcPointer, "missing 'type'")
+
+		return
+	}
+
+	pointer := svcPointer + "/type"
+
+	switch t := value.(type) {
+	case string:
+		if t == "" {
+			v.addError(pointer, "'type' must not be empty")
+		}
+	case []interface{}:
+		if len(t) == 0 {
+			v.addError(pointer, "'type' must not be empty")
+		}
+
+		for j, e := range t {
+			if s, ok := e.(string); !ok || s == "" {
+				v.addError(pointer+"/"+strconv.Itoa(j), "type must be a non-empty string")
+			}
+		}
+	default:
+		v.addError(pointer, "'type' must be a string or an array of strings")
+	}
+}
+
+func (v *validator) validateServiceEndpoint(svcPointer string, svc map[string]interface{}) {
+	value, exists := svc["serviceEndpoint"]
+	if !exists {
+		v.addError(svcPointer, "missing 'serviceEndpoint'")
+
+		return
+	}
+
+	pointer := svcPointer + "/serviceEndpoint"
+
+	switch endpoint := value.(type) {
+	case string:
+		v.validateURI(pointer, endpoint)
+	case []interface{}:
+		if len(endpoint) == 0 {
+			v.addError(pointer, "'serviceEndpoint' must not be empty")
+		}
+
+		for j, e := range endpoint {
+			switch ep := e.(type) {
+			case string:
+				v.validateURI(pointer+"/"+strconv.Itoa(j), ep)
+			case map[string]interface{}:
+				if uri, ok := ep["uri"].(string); ok {
+					v.validateURI(pointer+"/"+strconv.Itoa(j)+"/uri", uri)
+				}
+			default:
+				v.addError(pointer+"/"+strconv.Itoa(j), "service endpoint must be a URI or an object")
+			}
+		}
+	case map[string]interface{}:
+		// An object is allowed, e.g. a DIDComm v2 service endpoint.
+	default:
+		v.addError(pointer, "'serviceEndpoint' must be a URI, an object or an array")
+	}
+}
+
+func (v *validator) validateURI(pointer, uri string) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		v.addError(pointer, "invalid URI '%s': %s", uri, err)
+
+		return
+	}
+
+	if !u.IsAbs() {
+		v.addError(pointer, "URI '%s' must be absolute", uri)
+	}
+}
+
+func unmarshalArray(data []byte, name string) ([]interface{}, error) {
+	var doc interface{}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, ValidationErrors{{
+				Reason: fmt.Sprintf("invalid JSON at offset %d: %s", syntaxErr.Offset, err),
+			}}
+		}
+
+		return nil, ValidationErrors{{Reason: fmt.Sprintf("invalid JSON: %s", err)}}
+	}
+
+	elements, ok := doc.([]interface{})
+	if !ok {
+		return nil, ValidationErrors{{Reason: fmt.Sprintf("expecting an array of %s", name)}}
+	}
+
+	return elements, nil
+}
+
+// jsonPointer returns a JSON pointer (RFC 6901) for the given tokens.
+func jsonPointer(tokens ...interface{}) string {
+	var b strings.Builder
+
+	for _, token := range tokens {
+		b.WriteString("/")
+
+		switch t := token.(type) {
+		case int:
+			b.WriteString(strconv.Itoa(t))
+		default:
+			b.WriteString(escapeToken(fmt.Sprint(t)))
+		}
+	}
+
+	return b.String()
+}
+
+func escapeToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,330 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePublicKeys(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, ValidatePublicKeys([]byte(publicKeyData)))
+	})
+
+	tests := []struct {
+		name string
+		data string
+		errs []string
+	}{
+		{
+			name: "invalid JSON",
+			data: `[{"id":}]`,
+			errs: []string{"invalid JSON at offset 8: invalid character '}' looking for beginning of value"},
+		},
+		{
+			name: "not an array",
+			data: `{"id":"key1"}`,
+			errs: []string{"expecting an array of public keys"},
+		},
+		{
+			name: "not an object",
+			data: `["key1"]`,
+			errs: []string{"/0: expecting a public key object"},
+		},
+		{
+			name: "missing fields",
+			data: `[{}]`,
+			errs: []string{
+				"/0: missing 'id'",
+				"/0: missing 'type'",
+				"/0: public key needs exactly one of jwkPath and b58Key",
+			},
+		},
+		{
+			name: "invalid fields",
+			data: `[
+  {"id":"key1","type":"Ed25519VerificationKey2018","b58Key":"36d8RkFy2SdabnGzcZ3LcCSDA8NP5T4bsoADwuXtoN3B"},
+  {"id":"key1","type":"MyKey2023","purposes":["authentication","signing",1],"b58Key":"0OIl","purpose":"x"},
+  {"id":"key#3","type":5,"purposes":"authentication","jwkPath":""},
+  {"id":"","type":"JsonWebKey2020","jwkPath":"jwk.json","b58Key":"36d8RkFy2SdabnGzcZ3LcCSDA8NP5T4bsoADwuXtoN3B"}
+]`,
+			errs: []string{
+				"/1/id: duplicate id 'key1'",
+				"/1/type: unsupported key type 'MyKey2023'",
+				"/1/purposes/1: public key purpose signing not supported",
+				"/1/purposes/2: purpose must be a string",
+				"/1/b58Key: 'b58Key' is not base58 encoded",
+				"/1/purpose: unsupported property 'purpose'",
+				"/2/id: id 'key#3' must only contain characters [A-Za-z0-9_-]",
+				"/2/type: 'type' must be a string",
+				"/2/purposes: 'purposes' must be an array of strings",
+				"/2/jwkPath: 'jwkPath' must not be empty",
+				"/3/id: 'id' must not be empty",
+				"/3: public key needs exactly one of jwkPath and b58Key",
+			},
+		},
+		{
+			name: "ID too long",
+			data: `[{"id":"key1key1key1key1key1key1key1key1key1key1key1key1key1","type":"JsonWebKey2020","jwkPath":"jwk.json"}]`,
+			errs: []string{"/0/id: id exceeds maximum length of 50"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePublicKeys([]byte(tc.data))
+			require.Error(t, err)
+
+			requireValidationErrors(t, err, tc.errs...)
+		})
+	}
+}
+
+func TestValidateServices(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, ValidateServices([]byte(servicesData)))
+		require.NoError(t, ValidateServices([]byte(`[
+  {"id":"svc1","type":"type1","serviceEndpoint":"https://example.com"},
+  {"id":"svc2","type":["type1","type2"],"serviceEndpoint":["https://example.com",{"uri":"https://example.com"}]},
+  {"id":"svc3","type":"DIDCommMessaging","serviceEndpoint":{"uri":"https://example.com","accept":["didcomm/v2"]}}
+]`)))
+	})
+
+	tests := []struct {
+		name string
+		data string
+		errs []string
+	}{
+		{
+			name: "invalid JSON",
+			data: `wrong`,
+			errs: []string{"invalid JSON at offset 1: invalid character 'w' looking for beginning of value"},
+		},
+		{
+			name: "not an array",
+			data: `{"id":"svc1"}`,
+			errs: []string{"expecting an array of services"},
+		},
+		{
+			name: "not an object",
+			data: `[1]`,
+			errs: []string{"/0: expecting a service object"},
+		},
+		{
+			name: "missing fields",
+			data: `[{}]`,
+			errs: []string{
+				"/0: missing 'id'",
+				"/0: missing 'type'",
+				"/0: missing 'serviceEndpoint'",
+			},
+		},
+		{
+			name: "invalid fields",
+			data: `[
+  {"id":"svc1","type":"","serviceEndpoint":"example.com"},
+  {"id":"svc1","type":[],"serviceEndpoint":[]},
+  {"id":"svc3","type":["type1",""],"serviceEndpoint":[{"uri":"/path"},5,":invalid"]},
+  {"id":"svc4","type":5,"serviceEndpoint":true}
+]`,
+			errs: []string{
+				"/0/type: 'type' must not be empty",
+				"/0/serviceEndpoint: URI 'example.com' must be absolute",
+				"/1/id: duplicate id 'svc1'",
+				"/1/type: 'type' must not be empty",
+				"/1/serviceEndpoint: 'serviceEndpoint' must not be empty",
+				"/2/type/1: type must be a non-empty string",
+				"/2/serviceEndpoint/0/uri: URI '/path' must be absolute",
+				"/2/serviceEndpoint/1: service endpoint must be a URI or an object",
+				"/2/serviceEndpoint/2: invalid URI ':invalid': parse \":invalid\": missing protocol scheme",
+				"/3/type: 'type' must be a string or an array of strings",
+				"/3/serviceEndpoint: 'serviceEndpoint' must be a URI, an object or an array",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateServices([]byte(tc.data))
+			require.Error(t, err)
+
+			requireValidationErrors(t, err, tc.errs...)
+		})
+	}
+}
+
+func TestValidateJSONPatch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, ValidateJSONPatch([]byte(`[
+  {"op":"add","path":"/alsoKnownAs","value":["https://myblog.example/"]},
+  {"op":"replace","path":"/alsoKnownAs/0","value":"https://myblog.example/2"},
+  {"op":"test","path":"/alsoKnownAs/0","value":"https://myblog.example/2"},
+  {"op":"copy","from":"/alsoKnownAs/0","path":"/alsoKnownAs/-"},
+  {"op":"remove","path":"/alsoKnownAs/1"}
+]`)))
+	})
+
+	t.Run("success - properties are not restricted", func(t *testing.T) {
+		// The Sidetree patch validator determines which properties may be modified.
+		require.NoError(t, ValidateJSONPatch([]byte(`[
+  {"op":"add","path":"/customProperty","value":"value"},
+  {"op":"add","path":"/publicKeys/-","value":{}}
+]`)))
+	})
+
+	tests := []struct {
+		name string
+		data string
+		errs []string
+	}{
+		{
+			name: "not an array",
+			data: `{"op":"remove","path":"/alsoKnownAs"}`,
+			errs: []string{"expecting an array of JSON patch operations"},
+		},
+		{
+			name: "empty",
+			data: `[]`,
+			errs: []string{"JSON patch must contain at least one operation"},
+		},
+		{
+			name: "invalid operations",
+			data: `[
+  "remove",
+  {"path":"/alsoKnownAs"},
+  {"op":"delete","path":"/alsoKnownAs"},
+  {"op":"add","path":"/alsoKnownAs/-"},
+  {"op":"move","path":"/alsoKnownAs/0"},
+  {"op":"copy","from":5,"path":"/alsoKnownAs/0"},
+  {"op":"remove","path":5}
+]`,
+			errs: []string{
+				"/0: expecting a JSON patch operation object",
+				"/1: missing 'op'",
+				"/2/op: unsupported operation 'delete'",
+				"/3: missing 'value' for 'add' operation",
+				"/4: missing 'from' for 'move' operation",
+				"/5/from: 'from' must be a string",
+				"/6: 'path' must be a string",
+			},
+		},
+		{
+			name: "invalid paths",
+			data: `[
+  {"op":"replace","path":"","value":{}},
+  {"op":"remove","path":"alsoKnownAs"},
+  {"op":"move","from":"controller","path":"/alsoKnownAs/-"}
+]`,
+			errs: []string{
+				"/0/path: the whole document may not be replaced with a JSON patch",
+				"/1/path: path 'alsoKnownAs' must start with '/'",
+				"/2/from: path 'controller' must start with '/'",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateJSONPatch([]byte(tc.data))
+			require.Error(t, err)
+
+			requireValidationErrors(t, err, tc.errs...)
+		})
+	}
+}
+
+func TestValidateJWK(t *testing.T) {
+	require.NoError(t, validateJWK([]byte(jwk1Data)))
+	require.NoError(t, validateJWK([]byte(jwk2Data)))
+
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{data: `oops`, err: "failed to unmarshal to jwk"},
+		{data: `{"crv":"P-256"}`, err: "missing 'kty'"},
+		{data: `{"kty":"RSA"}`, err: "unsupported 'kty' 'RSA'"},
+		{data: `{"kty":"EC","x":"abc","y":"abc"}`, err: "missing 'crv' for EC key"},
+		{data: `{"kty":"EC","crv":"Ed25519","x":"abc","y":"abc"}`, err: "unsupported 'crv' 'Ed25519' for EC key"},
+		{data: `{"kty":"EC","crv":"P-256","x":"abc"}`, err: "missing 'y' for EC key"},
+		{data: `{"kty":"OKP","crv":"Ed25519"}`, err: "missing 'x' for OKP key"},
+		{data: `{"kty":"OKP","crv":"Ed25519","x":"a+b/"}`, err: "'x' is not base64url encoded"},
+	}
+
+	for _, tc := range tests {
+		err := validateJWK([]byte(tc.data))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), tc.err)
+	}
+}
+
+func TestGetVDRPublicKeys_InvalidJWK(t *testing.T) {
+	jwkFile, err := os.CreateTemp("", "*.json")
+	require.NoError(t, err)
+
+	_, err = jwkFile.WriteString(`{"kty":"EC","x":"bGM9aNufpKNPxlkyacU1hGhQXm_aC8hIzSVeKDpwjBw"}`)
+	require.NoError(t, err)
+
+	file, err := os.CreateTemp("", "*.json")
+	require.NoError(t, err)
+
+	_, err = fmt.Fprintf(file, `[{"id":"key1","type":"JsonWebKey2020","purposes":["authentication"],"jwkPath":"%s"}]`,
+		jwkFile.Name())
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, os.Remove(file.Name()))
+		require.NoError(t, os.Remove(jwkFile.Name()))
+	}()
+
+	_, err = GetVDRPublicKeysFromFile(file.Name())
+	require.Error(t, err)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, "/0/jwkPath", validationErr.Pointer)
+	require.Equal(t, jwkFile.Name()+": missing 'crv' for EC key", validationErr.Reason)
+}
+
+func TestGetServices_Invalid(t *testing.T) {
+	file, err := os.CreateTemp("", "*.json")
+	require.NoError(t, err)
+
+	_, err = file.WriteString(`[{"id":"svc1","type":"type1"}]`)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.Remove(file.Name())) }()
+
+	_, err = GetServices(file.Name())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "/0: missing 'serviceEndpoint'")
+}
+
+func TestJSONPointer(t *testing.T) {
+	require.Equal(t, "", jsonPointer())
+	require.Equal(t, "/1/publicKeyJwk", jsonPointer(1, "publicKeyJwk"))
+	require.Equal(t, "/0/a~1b~0c", jsonPointer(0, "a/b~c"))
+}
+
+func requireValidationErrors(t *testing.T, err error, expected ...string) {
+	t.Helper()
+
+	var validationErrs ValidationErrors
+	require.True(t, errors.As(err, &validationErrs))
+
+	actual := make([]string, len(validationErrs))
+
+	for i, e := range validationErrs {
+		actual[i] = e.Error()
+	}
+
+	require.Equal(t, expected, actual)
+}
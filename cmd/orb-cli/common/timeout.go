@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// TimeoutFlagName defines the flag for the command timeout.
+	TimeoutFlagName = "timeout"
+	// TimeoutFlagUsage defines the usage of the command timeout flag.
+	TimeoutFlagUsage = "The maximum amount of time that a command may run (e.g. 30s, 2m). If the timeout is exceeded" +
+		" then the command is aborted with an error. If not set then commands don't time out." +
+		" Alternatively, this can be set with the following environment variable: " + TimeoutEnvKey
+	// TimeoutEnvKey defines the environment variable for the command timeout flag.
+	TimeoutEnvKey = "ORB_CLI_TIMEOUT"
+)
+
+// AddTimeoutFlag adds the command timeout flag to the given flag set. The flag is registered as a persistent
+// flag on the root command so that it applies to all subcommands.
+func AddTimeoutFlag(flags *pflag.FlagSet) {
+	flags.StringP(TimeoutFlagName, "", "", TimeoutFlagUsage)
+}
+
+// ApplyTimeout wraps the RunE function of the given command and all of its subcommands so that the command
+// runs with a context whose deadline is set by the timeout flag. The command's context is available to the
+// command's operations via cmd.Context(). If the deadline is exceeded before the command completes then
+// the command returns a context.DeadlineExceeded error.
+func ApplyTimeout(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		ApplyTimeout(c)
+	}
+
+	if cmd.RunE == nil {
+		return
+	}
+
+	cmd.RunE = withTimeout(cmd.RunE)
+}
+
+func withTimeout(runE func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		timeout, err := GetDuration(cmd, TimeoutFlagName, TimeoutEnvKey, 0)
+		if err != nil {
+			return fmt.Errorf("%s: %w", TimeoutFlagName, err)
+		}
+
+		if timeout <= 0 {
+			return runE(cmd, args)
+		}
+
+		ctx, cancel := context.WithTimeout(commandContext(cmd), timeout)
+		defer cancel()
+
+		cmd.SetContext(ctx)
+
+		errChan := make(chan error, 1)
+
+		go func() {
+			errChan <- runE(cmd, args)
+		}()
+
+		select {
+		case err := <-errChan:
+			return err
+		case <-ctx.Done():
+			return fmt.Errorf("command did not complete within %s: %w", timeout, ctx.Err())
+		}
+	}
+}
+
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+
+	return context.Background()
+}
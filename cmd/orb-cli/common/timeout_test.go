@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTimeout(t *testing.T) {
+	t.Run("command exceeds timeout", func(t *testing.T) {
+		unblock := make(chan struct{})
+		defer close(unblock)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-unblock:
+			case <-r.Context().Done():
+			}
+		}))
+		defer srv.Close()
+
+		root := newTestRootCmd(func(cmd *cobra.Command, args []string) error {
+			_, err := SendHTTPRequest(cmd, nil, http.MethodGet, srv.URL)
+
+			return err
+		})
+
+		root.SetArgs([]string{"test", "--" + TimeoutFlagName, "50ms"})
+
+		start := time.Now()
+
+		err := root.Execute()
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Contains(t, err.Error(), "command did not complete within 50ms")
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("command ignores context", func(t *testing.T) {
+		unblock := make(chan struct{})
+		defer close(unblock)
+
+		root := newTestRootCmd(func(cmd *cobra.Command, args []string) error {
+			<-unblock
+
+			return nil
+		})
+
+		root.SetArgs([]string{"test", "--" + TimeoutFlagName, "10ms"})
+
+		err := root.Execute()
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("command completes within timeout", func(t *testing.T) {
+		var deadlineSet bool
+
+		root := newTestRootCmd(func(cmd *cobra.Command, args []string) error {
+			_, deadlineSet = cmd.Context().Deadline()
+
+			return nil
+		})
+
+		root.SetArgs([]string{"test", "--" + TimeoutFlagName, "1m"})
+
+		require.NoError(t, root.Execute())
+		require.True(t, deadlineSet)
+	})
+
+	t.Run("no timeout", func(t *testing.T) {
+		var deadlineSet bool
+
+		root := newTestRootCmd(func(cmd *cobra.Command, args []string) error {
+			_, deadlineSet = cmd.Context().Deadline()
+
+			return errors.New("injected error")
+		})
+
+		root.SetArgs([]string{"test"})
+
+		err := root.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected error")
+		require.False(t, deadlineSet)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		root := newTestRootCmd(func(cmd *cobra.Command, args []string) error {
+			return nil
+		})
+
+		root.SetArgs([]string{"test", "--" + TimeoutFlagName, "soon"})
+
+		err := root.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "timeout: invalid value [soon]")
+	})
+}
+
+func newTestRootCmd(runE func(cmd *cobra.Command, args []string) error) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "root",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	AddTimeoutFlag(root.PersistentFlags())
+
+	root.AddCommand(&cobra.Command{
+		Use:  "test",
+		RunE: runE,
+	})
+
+	ApplyTimeout(root)
+
+	return root
+}
@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+)
+
+const (
+	// UserAgentFlagName defines the flag for the User-Agent header.
+	UserAgentFlagName = "user-agent"
+	// UserAgentFlagUsage defines the usage of the User-Agent flag.
+	UserAgentFlagUsage = "The User-Agent header which is set on HTTP requests, for example orb-cli/1.0.0." +
+		" Defaults to orb-cli/<build version>. An X-Request-ID header is also set on each request so that the" +
+		" request may be found in the server logs." +
+		" Alternatively, this can be set with the following environment variable: " + UserAgentEnvKey
+	// UserAgentEnvKey defines the environment variable for the User-Agent flag.
+	UserAgentEnvKey = "ORB_CLI_USER_AGENT"
+
+	userAgentProduct = "orb-cli"
+)
+
+// BuildVersion contains the version of the CLI build. It's set at build time with
+// -ldflags "-X github.com/trustbloc/orb/cmd/orb-cli/common.BuildVersion=<version>" (see scripts/build-cli.sh).
+// If not set then the version of the main module from the build info is used.
+var BuildVersion string
+
+var readBuildInfo = debug.ReadBuildInfo
+
+// AddUserAgentFlag adds the User-Agent flag to the given flag set. The flag is registered as a persistent
+// flag on the root command so that it applies to all subcommands.
+func AddUserAgentFlag(flags *pflag.FlagSet) {
+	flags.StringP(UserAgentFlagName, "", "", UserAgentFlagUsage)
+}
+
+func getUserAgent(cmd *cobra.Command) string {
+	userAgent, err := cmdutil.GetUserSetVarFromString(cmd, UserAgentFlagName, UserAgentEnvKey, true)
+	if err != nil || userAgent == "" {
+		return transport.UserAgent(userAgentProduct, buildVersion())
+	}
+
+	return userAgent
+}
+
+// buildVersion returns the version set at build time or, if not set, the version of the main module from the
+// build info (which is set when the CLI is installed with 'go install <module>@<version>').
+func buildVersion() string {
+	if BuildVersion != "" {
+		return BuildVersion
+	}
+
+	info, ok := readBuildInfo()
+	if !ok || info.Main.Version == "" || info.Main.Version == "(devel)" {
+		return ""
+	}
+
+	return info.Main.Version
+}
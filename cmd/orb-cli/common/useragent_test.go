@@ -0,0 +1,126 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/requestid"
+)
+
+func TestNewHTTPClient_Headers(t *testing.T) {
+	var header http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header = req.Header.Clone()
+	}))
+	defer server.Close()
+
+	t.Run("default user agent", func(t *testing.T) {
+		_, err := SendRequestWithContext(context.Background(), newTestHTTPClient(t), nil, nil,
+			http.MethodGet, server.URL)
+		require.NoError(t, err)
+
+		require.Equal(t, userAgentProduct, header.Get("User-Agent"))
+		require.NotEmpty(t, header.Get(requestid.HeaderName))
+	})
+
+	t.Run("user agent from flag", func(t *testing.T) {
+		_, err := SendRequestWithContext(context.Background(),
+			newTestHTTPClient(t, "--"+UserAgentFlagName, "orb-cli/1.0.0"), nil, nil, http.MethodGet, server.URL)
+		require.NoError(t, err)
+
+		require.Equal(t, "orb-cli/1.0.0", header.Get("User-Agent"))
+	})
+
+	t.Run("user agent from environment", func(t *testing.T) {
+		t.Setenv(UserAgentEnvKey, "orb-cli/2.0.0")
+
+		_, err := SendRequestWithContext(context.Background(), newTestHTTPClient(t), nil, nil,
+			http.MethodGet, server.URL)
+		require.NoError(t, err)
+
+		require.Equal(t, "orb-cli/2.0.0", header.Get("User-Agent"))
+	})
+}
+
+func TestGetUserAgent_Version(t *testing.T) {
+	cmd := &cobra.Command{Use: "mock"}
+
+	AddUserAgentFlag(cmd.Flags())
+
+	t.Run("build version", func(t *testing.T) {
+		setBuildVersion(t, "1.2.3")
+
+		require.Equal(t, "orb-cli/1.2.3", getUserAgent(cmd))
+	})
+
+	t.Run("version from build info", func(t *testing.T) {
+		setReadBuildInfo(t, &debug.BuildInfo{Main: debug.Module{Version: "v1.0.0"}}, true)
+
+		require.Equal(t, "orb-cli/v1.0.0", getUserAgent(cmd))
+	})
+
+	t.Run("build version takes precedence over build info", func(t *testing.T) {
+		setBuildVersion(t, "1.2.3")
+		setReadBuildInfo(t, &debug.BuildInfo{Main: debug.Module{Version: "v1.0.0"}}, true)
+
+		require.Equal(t, "orb-cli/1.2.3", getUserAgent(cmd))
+	})
+
+	t.Run("development build", func(t *testing.T) {
+		setReadBuildInfo(t, &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}}, true)
+
+		require.Equal(t, "orb-cli", getUserAgent(cmd))
+	})
+
+	t.Run("no build info", func(t *testing.T) {
+		setReadBuildInfo(t, nil, false)
+
+		require.Equal(t, "orb-cli", getUserAgent(cmd))
+	})
+}
+
+func setBuildVersion(t *testing.T, version string) {
+	t.Helper()
+
+	prev := BuildVersion
+	BuildVersion = version
+
+	t.Cleanup(func() { BuildVersion = prev })
+}
+
+func setReadBuildInfo(t *testing.T, info *debug.BuildInfo, ok bool) {
+	t.Helper()
+
+	prev := readBuildInfo
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, ok }
+
+	t.Cleanup(func() { readBuildInfo = prev })
+}
+
+func newTestHTTPClient(t *testing.T, args ...string) *http.Client {
+	t.Helper()
+
+	cmd := &cobra.Command{Use: "mock"}
+
+	AddUserAgentFlag(cmd.Flags())
+
+	require.NoError(t, cmd.ParseFlags(args))
+
+	httpClient, err := NewHTTPClient(cmd)
+	require.NoError(t, err)
+
+	return httpClient
+}
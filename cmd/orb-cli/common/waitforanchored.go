@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/trustbloc/logutil-go/pkg/log"
+)
+
+const (
+	// ExitCodeWaitTimeout is the exit code used when a command times out while waiting for a DID to be anchored.
+	ExitCodeWaitTimeout = 3
+	// ExitCodeInterrupted is the exit code used when a command is interrupted (e.g. with Ctrl-C).
+	ExitCodeInterrupted = 130
+)
+
+// ExitError is returned by a command which must exit with a specific exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error returns the error message.
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// DIDResolver resolves a DID.
+type DIDResolver interface {
+	Read(did string, opts ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error)
+}
+
+// WaitForAnchored polls the resolver at the given interval until the given (interim) DID resolves to a
+// document with a canonical ID, i.e. the DID has been anchored, and returns the canonical ID. Resolution
+// errors are logged and polling continues until the context is done, in which case the context error
+// is returned.
+func WaitForAnchored(ctx context.Context, resolver DIDResolver, did string, interval time.Duration,
+	opts ...vdrapi.DIDMethodOption,
+) (string, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		docResolution, err := resolver.Read(did, opts...)
+		if err != nil {
+			logger.Debug("Error resolving DID while waiting for it to be anchored",
+				log.WithID(did), log.WithError(err))
+		} else if docResolution.DocumentMetadata != nil && docResolution.DocumentMetadata.CanonicalID != "" {
+			return docResolution.DocumentMetadata.CanonicalID, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return "", fmt.Errorf("wait for DID [%s] to be anchored: %w", did, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	interimDID   = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+	canonicalDID = "did:orb:uEiBYw:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+)
+
+func TestWaitForAnchored(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		resolver := &mockDIDResolver{anchorAfter: 2}
+
+		did, err := WaitForAnchored(context.Background(), resolver, interimDID, time.Millisecond)
+		require.NoError(t, err)
+		require.Equal(t, canonicalDID, did)
+		require.Equal(t, 3, resolver.calls)
+	})
+
+	t.Run("Resolution error -> retry", func(t *testing.T) {
+		resolver := &mockDIDResolver{anchorAfter: 1, err: errors.New("injected resolution error")}
+
+		did, err := WaitForAnchored(context.Background(), resolver, interimDID, time.Millisecond)
+		require.NoError(t, err)
+		require.Equal(t, canonicalDID, did)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		resolver := &mockDIDResolver{anchorAfter: 1000}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		did, err := WaitForAnchored(ctx, resolver, interimDID, time.Millisecond)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Contains(t, err.Error(), "wait for DID ["+interimDID+"] to be anchored")
+		require.Empty(t, did)
+	})
+}
+
+func TestExitError(t *testing.T) {
+	errExpected := errors.New("injected error")
+
+	err := &ExitError{Code: ExitCodeWaitTimeout, Err: errExpected}
+	require.EqualError(t, err, errExpected.Error())
+	require.True(t, errors.Is(err, errExpected))
+}
+
+// mockDIDResolver returns a document without a canonical ID until it has been called anchorAfter times.
+// If err is set then the error is returned (instead of an unanchored document).
+type mockDIDResolver struct {
+	anchorAfter int
+	err         error
+	calls       int
+}
+
+func (m *mockDIDResolver) Read(string, ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error) {
+	m.calls++
+
+	if m.calls <= m.anchorAfter {
+		if m.err != nil {
+			return nil, m.err
+		}
+
+		return &docdid.DocResolution{
+			DIDDocument:      &docdid.Doc{ID: interimDID},
+			DocumentMetadata: &docdid.DocumentMetadata{},
+		}, nil
+	}
+
+	return &docdid.DocResolution{
+		DIDDocument:      &docdid.Doc{ID: canonicalDID},
+		DocumentMetadata: &docdid.DocumentMetadata{CanonicalID: canonicalDID},
+	}, nil
+}
@@ -0,0 +1,341 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comparedidcmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/document/util"
+)
+
+const (
+	didFlagName  = "did"
+	didEnvKey    = "ORB_CLI_DID"
+	didFlagUsage = "The DID to resolve from each endpoint." +
+		" Alternatively, this can be set with the following environment variable: " + didEnvKey
+
+	endpointsFlagName  = "endpoints"
+	endpointsEnvKey    = "ORB_CLI_ENDPOINTS"
+	endpointsFlagUsage = "Comma-separated list of DID resolution endpoints to compare. For example: " +
+		"https://orb.domain1.com/sidetree/v1/identifiers,https://orb.domain2.com/sidetree/v1/identifiers." +
+		" Alternatively, this can be set with the following environment variable: " + endpointsEnvKey
+
+	timeoutFlagName  = "request-timeout"
+	timeoutEnvKey    = "ORB_CLI_REQUEST_TIMEOUT"
+	timeoutFlagUsage = "The timeout for resolving the DID from a single endpoint. For example, '10s' for a " +
+		"10 second timeout. An endpoint that can't be connected to or doesn't respond within this time is " +
+		"marked as unreachable and is excluded from the comparison." +
+		" Alternatively, this can be set with the following environment variable: " + timeoutEnvKey
+
+	defaultTimeout = 10 * time.Second
+)
+
+// GetCompareDIDCmd returns the Cobra compare DID command.
+func GetCompareDIDCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compares the resolved DID document across multiple resolution endpoints.",
+		Long: "Resolves the same DID from each of the given endpoints, canonicalizes the returned document and " +
+			"reports whether the endpoints agree. The hash of the canonical document and the canonical ID " +
+			"returned by each endpoint are printed and any divergence is highlighted. Endpoints that are slow " +
+			"or down are marked as unreachable and are excluded from the comparison. An endpoint that responds " +
+			"with an error status (e.g. the DID was not found) is reported as a failure. For example: did compare --did did:orb:uAAA:EiD... " +
+			"--endpoints https://orb.domain1.com/sidetree/v1/identifiers,https://orb.domain2.com/sidetree/v1/identifiers",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeCompare(cmd)
+		},
+	}
+
+	addFlags(cmd)
+
+	return cmd
+}
+
+type resolutionResult struct {
+	DIDDocument         json.RawMessage `json:"didDocument"`
+	DIDDocumentMetadata struct {
+		CanonicalID string `json:"canonicalId"`
+	} `json:"didDocumentMetadata"`
+}
+
+type endpointResult struct {
+	endpoint    string
+	hash        string
+	canonicalID string
+	unreachable bool
+	statusCode  int
+	err         error
+}
+
+func (r *endpointResult) notFound() bool {
+	return r.statusCode == http.StatusNotFound || r.statusCode == http.StatusGone
+}
+
+func (r *endpointResult) valid() bool {
+	return r.err == nil
+}
+
+func (r *endpointResult) key() string {
+	return r.hash + "|" + r.canonicalID
+}
+
+func executeCompare(cmd *cobra.Command) error {
+	did, endpoints, timeout, err := getArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := common.NewHTTPClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	httpClient.Timeout = timeout
+
+	results := resolveAll(httpClient, common.NewAuthTokenHeader(cmd), did, endpoints)
+
+	return printResults(cmd, did, results)
+}
+
+func getArgs(cmd *cobra.Command) (string, []string, time.Duration, error) {
+	did, err := cmdutil.GetUserSetVarFromString(cmd, didFlagName, didEnvKey, false)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	err = util.ValidateOrbDID(did, common.GetDIDNamespace(cmd))
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	endpoints, err := cmdutil.GetUserSetVarFromArrayString(cmd, endpointsFlagName, endpointsEnvKey, false)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	endpoints = splitEndpoints(endpoints)
+
+	if len(endpoints) < 2 { //nolint:gomnd
+		return "", nil, 0, fmt.Errorf("at least two endpoints are required in %s", endpointsFlagName)
+	}
+
+	timeout, err := common.GetDuration(cmd, timeoutFlagName, timeoutEnvKey, defaultTimeout)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	if timeout <= 0 {
+		return "", nil, 0, fmt.Errorf("%s must be greater than 0", timeoutFlagName)
+	}
+
+	return did, endpoints, timeout, nil
+}
+
+func splitEndpoints(values []string) []string {
+	var endpoints []string
+
+	for _, value := range values {
+		for _, endpoint := range strings.Split(value, ",") {
+			endpoint = strings.TrimSpace(endpoint)
+			if endpoint != "" {
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+
+	return endpoints
+}
+
+// resolveAll resolves the DID from all endpoints concurrently. The results are returned in the same
+// order as the given endpoints.
+func resolveAll(httpClient *http.Client, headers map[string]string, did string, endpoints []string) []*endpointResult {
+	results := make([]*endpointResult, len(endpoints))
+
+	var wg sync.WaitGroup
+
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+
+		go func(i int, endpoint string) {
+			defer wg.Done()
+
+			results[i] = resolve(httpClient, headers, did, endpoint)
+		}(i, endpoint)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func resolve(httpClient *http.Client, headers map[string]string, did, endpoint string) *endpointResult {
+	result := &endpointResult{endpoint: endpoint}
+
+	respBytes, statusCode, err := common.SendRequestForStatus(context.Background(), httpClient, nil, headers,
+		http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/"+did)
+	if err != nil {
+		// Only transport failures (including timeouts) mark the endpoint as unreachable.
+		result.unreachable = true
+		result.err = err
+
+		return result
+	}
+
+	if statusCode != http.StatusOK {
+		result.statusCode = statusCode
+		result.err = fmt.Errorf("status %d: %s", statusCode, strings.TrimSpace(string(respBytes)))
+
+		return result
+	}
+
+	result.hash, result.canonicalID, err = hashResolutionResult(respBytes)
+	if err != nil {
+		result.err = err
+	}
+
+	return result
+}
+
+func hashResolutionResult(respBytes []byte) (string, string, error) {
+	result := &resolutionResult{}
+
+	err := json.Unmarshal(respBytes, result)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid resolution result: %w", err)
+	}
+
+	if len(result.DIDDocument) == 0 {
+		return "", "", errors.New("invalid resolution result: missing DID document")
+	}
+
+	canonicalDoc, err := canonicalizer.MarshalCanonical(result.DIDDocument)
+	if err != nil {
+		return "", "", fmt.Errorf("canonicalize DID document: %w", err)
+	}
+
+	hash := sha256.Sum256(canonicalDoc)
+
+	return base64.RawURLEncoding.EncodeToString(hash[:]), result.DIDDocumentMetadata.CanonicalID, nil
+}
+
+func printResults(cmd *cobra.Command, did string, results []*endpointResult) error {
+	out := cmd.OutOrStdout()
+
+	reference, numValid := getReference(results)
+
+	common.Printf(out, "Resolved %s from %d endpoint(s), %d with a valid result\n", did, len(results), numValid)
+
+	var numFailed int
+
+	for _, r := range results {
+		if !r.unreachable && !r.valid() {
+			numFailed++
+		}
+
+		switch {
+		case r.unreachable:
+			common.Printf(out, "  %s: UNREACHABLE (%s)\n", r.endpoint, r.err)
+		case r.notFound():
+			common.Printf(out, "  %s: NOT FOUND (%s)\n", r.endpoint, r.err)
+		case r.statusCode != 0:
+			common.Printf(out, "  %s: ERROR (%s)\n", r.endpoint, r.err)
+		case !r.valid():
+			common.Printf(out, "  %s: INVALID RESPONSE (%s)\n", r.endpoint, r.err)
+		case r.key() == reference.key():
+			common.Printf(out, "  %s: hash=%s canonicalId=%s\n", r.endpoint, r.hash, r.canonicalID)
+		default:
+			common.Printf(out, "  %s: hash=%s canonicalId=%s DIVERGENT (%s)\n",
+				r.endpoint, r.hash, r.canonicalID, divergence(reference, r))
+		}
+	}
+
+	if numValid == 0 {
+		return errors.New("none of the endpoints returned a valid resolution result")
+	}
+
+	if numFailed > 0 {
+		common.Println(out, "Result: endpoints DISAGREE")
+
+		return fmt.Errorf("%d endpoint(s) failed to resolve the DID", numFailed)
+	}
+
+	for _, r := range results {
+		if r.valid() && r.key() != reference.key() {
+			common.Println(out, "Result: endpoints DISAGREE")
+
+			return errors.New("resolution results diverge across endpoints")
+		}
+	}
+
+	common.Println(out, "Result: all endpoints with a valid result agree")
+
+	return nil
+}
+
+// getReference returns the result shared by the most endpoints (ties are resolved in favour of the
+// endpoint listed first) along with the number of endpoints that returned a valid result.
+func getReference(results []*endpointResult) (*endpointResult, int) {
+	counts := make(map[string]int)
+
+	var (
+		reference *endpointResult
+		numValid  int
+	)
+
+	for _, r := range results {
+		if !r.valid() {
+			continue
+		}
+
+		numValid++
+
+		counts[r.key()]++
+
+		if reference == nil || counts[r.key()] > counts[reference.key()] {
+			reference = r
+		}
+	}
+
+	return reference, numValid
+}
+
+func divergence(reference, r *endpointResult) string {
+	var diffs []string
+
+	if r.canonicalID != reference.canonicalID {
+		diffs = append(diffs, fmt.Sprintf("canonical ID differs from [%s]", reference.canonicalID))
+	}
+
+	if r.hash != reference.hash {
+		diffs = append(diffs, fmt.Sprintf("document hash differs from [%s]", reference.hash))
+	}
+
+	return strings.Join(diffs, ", ")
+}
+
+func addFlags(cmd *cobra.Command) {
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(didFlagName, "", "", didFlagUsage)
+	cmd.Flags().StringArrayP(endpointsFlagName, "", nil, endpointsFlagUsage)
+	cmd.Flags().StringP(timeoutFlagName, "", "", timeoutFlagUsage)
+}
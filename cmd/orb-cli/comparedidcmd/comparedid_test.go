@@ -0,0 +1,240 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comparedidcmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	flag = "--"
+
+	testDID = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	canonicalID1 = "did:orb:uEiBvYQTF7Ny2ExlKNYMKhsMrb-VNVNVY4wvGH1RjHEntIg:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+	canonicalID2 = "did:orb:uEiDH5M9AuqA3nBzgP7wT5xdcPqBbyFXPJoVPEFKXhBQPkw:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	resolutionResultFmt = `{"didDocument":{"id":"%s","service":[{"id":"svc","type":"%s"}]},` +
+		`"didDocumentMetadata":{"canonicalId":"%s"}}`
+
+	// Same document as above but with the fields in a different order.
+	reorderedResolutionResultFmt = `{"didDocumentMetadata":{"canonicalId":"%s"},` +
+		`"didDocument":{"service":[{"type":"%s","id":"svc"}],"id":"%s"}}`
+)
+
+func TestCompareDIDCmd(t *testing.T) {
+	t.Run("missing DID", func(t *testing.T) {
+		cmd := GetCompareDIDCmd()
+		cmd.SetArgs(endpointsArg("https://orb1.com", "https://orb2.com"))
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither did (command line flag) nor ORB_CLI_DID (environment variable) have been set.")
+	})
+
+	t.Run("invalid DID", func(t *testing.T) {
+		cmd := GetCompareDIDCmd()
+		cmd.SetArgs(append(didArg("did:ex:123"), endpointsArg("https://orb1.com", "https://orb2.com")...))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting namespace [did:orb]")
+	})
+
+	t.Run("missing endpoints", func(t *testing.T) {
+		cmd := GetCompareDIDCmd()
+		cmd.SetArgs(didArg(testDID))
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither endpoints (command line flag) nor ORB_CLI_ENDPOINTS (environment variable) have been set.")
+	})
+
+	t.Run("not enough endpoints", func(t *testing.T) {
+		cmd := GetCompareDIDCmd()
+		cmd.SetArgs(append(didArg(testDID), endpointsArg("https://orb1.com")...))
+
+		err := cmd.Execute()
+		require.EqualError(t, err, "at least two endpoints are required in endpoints")
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		args := append(didArg(testDID), endpointsArg("https://orb1.com", "https://orb2.com")...)
+
+		cmd := GetCompareDIDCmd()
+		cmd.SetArgs(append(args, flag+timeoutFlagName, "xxx"))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value")
+
+		cmd = GetCompareDIDCmd()
+		cmd.SetArgs(append(args, flag+timeoutFlagName, "0s"))
+
+		err = cmd.Execute()
+		require.EqualError(t, err, "request-timeout must be greater than 0")
+	})
+
+	t.Run("endpoints agree", func(t *testing.T) {
+		serv1 := newResolutionServer(t, resolutionResultFmt, testDID, "type1", canonicalID1)
+		defer serv1.Close()
+
+		serv2 := newResolutionServer(t, reorderedResolutionResultFmt, canonicalID1, "type1", testDID)
+		defer serv2.Close()
+
+		out, err := execute(t, serv1.URL+","+serv2.URL+"/")
+		require.NoError(t, err)
+		require.Contains(t, out, "2 with a valid result")
+		require.Contains(t, out, "all endpoints with a valid result agree")
+		require.NotContains(t, out, "DIVERGENT")
+	})
+
+	t.Run("canonical ID differs", func(t *testing.T) {
+		serv1 := newResolutionServer(t, resolutionResultFmt, testDID, "type1", canonicalID1)
+		defer serv1.Close()
+
+		serv2 := newResolutionServer(t, resolutionResultFmt, testDID, "type1", canonicalID1)
+		defer serv2.Close()
+
+		serv3 := newResolutionServer(t, resolutionResultFmt, testDID, "type1", canonicalID2)
+		defer serv3.Close()
+
+		out, err := execute(t, serv1.URL, serv2.URL, serv3.URL)
+		require.EqualError(t, err, "resolution results diverge across endpoints")
+		require.Contains(t, out, "endpoints DISAGREE")
+		require.Contains(t, out, serv3.URL+": hash=")
+		require.Contains(t, out, fmt.Sprintf("canonical ID differs from [%s]", canonicalID1))
+		require.NotContains(t, out, "document hash differs")
+		require.Equal(t, 1, strings.Count(out, "DIVERGENT"))
+	})
+
+	t.Run("document differs", func(t *testing.T) {
+		serv1 := newResolutionServer(t, resolutionResultFmt, testDID, "type1", canonicalID1)
+		defer serv1.Close()
+
+		serv2 := newResolutionServer(t, resolutionResultFmt, testDID, "type2", canonicalID1)
+		defer serv2.Close()
+
+		out, err := execute(t, serv1.URL, serv2.URL)
+		require.EqualError(t, err, "resolution results diverge across endpoints")
+		require.Contains(t, out, serv2.URL+": hash=")
+		require.Contains(t, out, "document hash differs from")
+		require.NotContains(t, out, "canonical ID differs")
+	})
+
+	t.Run("unreachable endpoints", func(t *testing.T) {
+		serv1 := newResolutionServer(t, resolutionResultFmt, testDID, "type1", canonicalID1)
+		defer serv1.Close()
+
+		serv2 := newResolutionServer(t, resolutionResultFmt, testDID, "type1", canonicalID1)
+		defer serv2.Close()
+
+		downServ := newResolutionServer(t, resolutionResultFmt, testDID, "type1", canonicalID1)
+		downServ.Close()
+
+		slowServ := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(time.Second)
+		}))
+		defer slowServ.Close()
+
+		out, err := execute(t, serv1.URL, downServ.URL, slowServ.URL, serv2.URL, flag+timeoutFlagName, "100ms")
+		require.NoError(t, err)
+		require.Contains(t, out, "2 with a valid result")
+		require.Contains(t, out, downServ.URL+": UNREACHABLE")
+		require.Contains(t, out, slowServ.URL+": UNREACHABLE")
+		require.Contains(t, out, "all endpoints with a valid result agree")
+	})
+
+	t.Run("not found, error status and invalid endpoints", func(t *testing.T) {
+		serv1 := newResolutionServer(t, resolutionResultFmt, testDID, "type1", canonicalID1)
+		defer serv1.Close()
+
+		notFoundServ := httptest.NewServer(http.NotFoundHandler())
+		defer notFoundServ.Close()
+
+		errorServ := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}))
+		defer errorServ.Close()
+
+		invalidServ := newResolutionServer(t, "{}")
+		defer invalidServ.Close()
+
+		out, err := execute(t, serv1.URL, notFoundServ.URL, errorServ.URL, invalidServ.URL)
+		require.EqualError(t, err, "3 endpoint(s) failed to resolve the DID")
+		require.Contains(t, out, "1 with a valid result")
+		require.Contains(t, out, notFoundServ.URL+": NOT FOUND (status 404: 404 page not found)")
+		require.Contains(t, out, errorServ.URL+": ERROR (status 500: internal error)")
+		require.Contains(t, out, invalidServ.URL+": INVALID RESPONSE")
+		require.Contains(t, out, "endpoints DISAGREE")
+		require.NotContains(t, out, "UNREACHABLE")
+	})
+
+	t.Run("no valid results", func(t *testing.T) {
+		serv := httptest.NewServer(http.NotFoundHandler())
+		defer serv.Close()
+
+		invalidServ := newResolutionServer(t, "{")
+		defer invalidServ.Close()
+
+		_, err := execute(t, serv.URL, invalidServ.URL)
+		require.EqualError(t, err, "none of the endpoints returned a valid resolution result")
+	})
+}
+
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var endpoints, other []string
+
+	for i, arg := range args {
+		if strings.HasPrefix(arg, flag) {
+			other = args[i:]
+
+			break
+		}
+
+		endpoints = append(endpoints, arg)
+	}
+
+	out := &bytes.Buffer{}
+
+	cmd := GetCompareDIDCmd()
+	cmd.SetOut(out)
+	cmd.SetArgs(append(append(didArg(testDID), endpointsArg(endpoints...)...), other...))
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func newResolutionServer(t *testing.T, format string, args ...interface{}) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/"+testDID))
+
+		_, err := fmt.Fprintf(w, format, args...)
+		require.NoError(t, err)
+	}))
+}
+
+func didArg(did string) []string {
+	return []string{flag + didFlagName, did}
+}
+
+func endpointsArg(endpoints ...string) []string {
+	return []string{flag + endpointsFlagName, strings.Join(endpoints, ",")}
+}
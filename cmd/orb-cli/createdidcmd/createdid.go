@@ -6,11 +6,15 @@ SPDX-License-Identifier: Apache-2.0
 package createdidcmd
 
 import (
-	"crypto/tls"
-	"crypto/x509"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"strconv"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
@@ -21,7 +25,6 @@ import (
 
 	"github.com/trustbloc/orb/cmd/orb-cli/common"
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
-	"github.com/trustbloc/orb/internal/pkg/tlsutil"
 )
 
 const (
@@ -107,6 +110,36 @@ const (
 	didAlsoKnownAsFlagUsage = "Comma-separated list of also known as uris." +
 		" Alternatively, this can be set with the following environment variable: " + didAlsoKnownAsEnvKey
 	didAlsoKnownAsEnvKey = "ORB_CLI_DID_ALSO_KNOWN_AS"
+
+	waitFlagName  = "wait"
+	waitEnvKey    = "ORB_CLI_WAIT"
+	waitFlagUsage = "Set to 'true' to wait until the DID is anchored and then print the canonical DID." +
+		" If the DID is not anchored within the wait timeout then the interim DID is printed and the command" +
+		" exits with code 3. Defaults to false." +
+		" Alternatively, this can be set with the following environment variable: " + waitEnvKey
+
+	waitTimeoutFlagName  = "wait-timeout"
+	waitTimeoutEnvKey    = "ORB_CLI_WAIT_TIMEOUT"
+	waitTimeoutFlagUsage = "The maximum amount of time to wait for the DID to be anchored when " + waitFlagName +
+		" is set. Defaults to 2m." +
+		" Alternatively, this can be set with the following environment variable: " + waitTimeoutEnvKey
+
+	waitIntervalFlagName  = "wait-interval"
+	waitIntervalEnvKey    = "ORB_CLI_WAIT_INTERVAL"
+	waitIntervalFlagUsage = "The interval at which the DID is resolved when " + waitFlagName +
+		" is set. Defaults to 2s." +
+		" Alternatively, this can be set with the following environment variable: " + waitIntervalEnvKey
+
+	sidetreeURLResFlagName  = "sidetree-url-resolution"
+	sidetreeURLResFlagUsage = "Comma-Separated list of sidetree url resolution used when " + waitFlagName +
+		" is set." +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeURLResEnvKey
+	sidetreeURLResEnvKey = "ORB_CLI_SIDETREE_URL_Resolution"
+)
+
+const (
+	defaultWaitTimeout  = 2 * time.Minute
+	defaultWaitInterval = 2 * time.Second
 )
 
 // GetCreateDIDCmd returns the Cobra create did command.
@@ -125,7 +158,7 @@ func createDIDCmd() *cobra.Command {
 		Long:         "Create Orb DID",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			rootCAs, err := getRootCAs(cmd)
+			tlsConfig, err := common.NewTLSConfig(cmd)
 			if err != nil {
 				return err
 			}
@@ -138,7 +171,7 @@ func createDIDCmd() *cobra.Command {
 
 			httpClient := http.Client{Transport: &http.Transport{
 				ForceAttemptHTTP2: true,
-				TLSClientConfig:   &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12},
+				TLSClientConfig:   tlsConfig,
 			}}
 
 			kmsStoreURL := cmdutil.GetUserSetOptionalVarFromString(cmd, kmsStoreEndpointFlagName,
@@ -149,8 +182,20 @@ func createDIDCmd() *cobra.Command {
 				webKmsClient = webkms.New(kmsStoreURL, &httpClient)
 			}
 
+			waitArgs, err := getWaitArgs(cmd)
+			if err != nil {
+				return err
+			}
+
+			vdrHTTPClient := &httpClient
+
+			capture := common.NewRequestCapture(cmd, httpClient.Transport)
+			if capture != nil {
+				vdrHTTPClient = &http.Client{Transport: capture}
+			}
+
 			vdr, err := orb.New(nil, orb.WithAuthToken(sidetreeWriteToken), orb.WithDomain(domain),
-				orb.WithHTTPClient(&httpClient))
+				orb.WithHTTPClient(vdrHTTPClient))
 			if err != nil {
 				return err
 			}
@@ -161,6 +206,10 @@ func createDIDCmd() *cobra.Command {
 			}
 
 			docResolution, err := vdr.Create(didDoc, opts...)
+			if capture != nil {
+				return capture.Write(cmd.OutOrStdout(), err)
+			}
+
 			if err != nil {
 				return fmt.Errorf("failed to create did: %w", err)
 			}
@@ -172,11 +221,92 @@ func createDIDCmd() *cobra.Command {
 
 			fmt.Println(string(bytes))
 
-			return nil
+			if !waitArgs.enabled {
+				return nil
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return waitForAnchored(ctx, cmd.OutOrStdout(), vdr, docResolution.DIDDocument.ID, waitArgs,
+				resolveDIDOption(cmd)...)
 		},
 	}
 }
 
+type waitArgs struct {
+	enabled  bool
+	timeout  time.Duration
+	interval time.Duration
+}
+
+func getWaitArgs(cmd *cobra.Command) (*waitArgs, error) {
+	enabled, err := cmdutil.GetBool(cmd, waitFlagName, waitEnvKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", waitFlagName, err)
+	}
+
+	timeout, err := common.GetDuration(cmd, waitTimeoutFlagName, waitTimeoutEnvKey, defaultWaitTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", waitTimeoutFlagName, err)
+	}
+
+	interval, err := common.GetDuration(cmd, waitIntervalFlagName, waitIntervalEnvKey, defaultWaitInterval)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", waitIntervalFlagName, err)
+	}
+
+	return &waitArgs{
+		enabled:  enabled,
+		timeout:  timeout,
+		interval: interval,
+	}, nil
+}
+
+// waitForAnchored waits for the given interim DID to be anchored and prints the canonical DID. If the wait
+// times out or is interrupted then the interim DID is printed and an ExitError is returned with the
+// appropriate exit code.
+func waitForAnchored(ctx context.Context, out io.Writer, resolver common.DIDResolver, interimDID string,
+	args *waitArgs, opts ...vdrapi.DIDMethodOption,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, args.timeout)
+	defer cancel()
+
+	canonicalDID, err := common.WaitForAnchored(ctx, resolver, interimDID, args.interval, opts...)
+	if err == nil {
+		common.Println(out, canonicalDID)
+
+		return nil
+	}
+
+	common.Println(out, interimDID)
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &common.ExitError{
+			Code: common.ExitCodeWaitTimeout,
+			Err:  fmt.Errorf("timed out after %s waiting for DID to be anchored: %w", args.timeout, err),
+		}
+	}
+
+	return &common.ExitError{
+		Code: common.ExitCodeInterrupted,
+		Err:  fmt.Errorf("interrupted while waiting for DID to be anchored: %w", err),
+	}
+}
+
+func resolveDIDOption(cmd *cobra.Command) []vdrapi.DIDMethodOption {
+	var opts []vdrapi.DIDMethodOption
+
+	sidetreeURLRes := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLResFlagName,
+		sidetreeURLResEnvKey)
+
+	if len(sidetreeURLRes) > 0 {
+		opts = append(opts, vdrapi.WithOption(orb.ResolutionEndpointsOpt, sidetreeURLRes))
+	}
+
+	return opts
+}
+
 func getSidetreeURL(cmd *cobra.Command) []vdrapi.DIDMethodOption {
 	var opts []vdrapi.DIDMethodOption
 
@@ -283,27 +413,6 @@ func getPublicKeys(cmd *cobra.Command) (*did.Doc, error) {
 	return &did.Doc{}, nil
 }
 
-func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
-	tlsSystemCertPoolString := cmdutil.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
-		tlsSystemCertPoolEnvKey)
-
-	tlsSystemCertPool := false
-
-	if tlsSystemCertPoolString != "" {
-		var err error
-		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
-
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	tlsCACerts := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
-		tlsCACertsEnvKey)
-
-	return tlsutil.GetCertPool(tlsSystemCertPool, tlsCACerts)
-}
-
 func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(domainFlagName, "", "", domainFileFlagUsage)
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "",
@@ -322,4 +431,10 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().String(kmsStoreEndpointFlagName, "", kmsStoreEndpointFlagUsage)
 	startCmd.Flags().String(updateKeyIDFlagName, "", updateKeyIDFlagUsage)
 	startCmd.Flags().String(recoveryKeyIDFlagName, "", recoveryKeyIDFlagUsage)
+	startCmd.Flags().String(waitFlagName, "", waitFlagUsage)
+	startCmd.Flags().String(waitTimeoutFlagName, "", waitTimeoutFlagUsage)
+	startCmd.Flags().String(waitIntervalFlagName, "", waitIntervalFlagUsage)
+	startCmd.Flags().StringArrayP(sidetreeURLResFlagName, "", []string{}, sidetreeURLResFlagUsage)
+
+	common.AddOutputRequestFlag(startCmd)
 }
@@ -6,15 +6,25 @@ SPDX-License-Identifier: Apache-2.0
 package createdidcmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/model"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
 )
 
 const (
@@ -257,6 +267,146 @@ func TestCreateDID(t *testing.T) {
 
 		require.NoError(t, err)
 	})
+
+	t.Run("output request", func(t *testing.T) {
+		var posted bool
+
+		opsServ := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			posted = true
+		}))
+		defer opsServ.Close()
+
+		requestFile := filepath.Join(t.TempDir(), "request.json")
+
+		os.Clearenv()
+		cmd := GetCreateDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		var args []string
+		args = append(args, sidetreeURLArg(opsServ.URL)...)
+		args = append(args, didAnchorOrigin("origin")...)
+		args = append(args, recoveryKeyFileFlagNameArg(recoveryKeyFile.Name())...)
+		args = append(args, updateKeyFileFlagNameArg(updateKeyFile.Name())...)
+		args = append(args, servicesFileArg(servicesFile.Name())...)
+		args = append(args, publicKeyFileArg(publicKeyFile.Name())...)
+		args = append(args, flag+common.OutputRequestFlagName, requestFile)
+
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		require.False(t, posted)
+		require.Contains(t, out.String(), "Operation request written to "+requestFile)
+
+		reqBytes, err := os.ReadFile(requestFile)
+		require.NoError(t, err)
+
+		req := &model.CreateRequest{}
+		require.NoError(t, json.Unmarshal(reqBytes, req))
+		require.Equal(t, operation.TypeCreate, req.Operation)
+		require.NotNil(t, req.SuffixData)
+		require.Equal(t, "origin", req.SuffixData.AnchorOrigin)
+		require.NotNil(t, req.Delta)
+		require.NotEmpty(t, req.Delta.Patches)
+	})
+}
+
+func TestWaitArgs(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetCreateDIDCmd()
+
+		args, err := getWaitArgs(cmd)
+		require.NoError(t, err)
+		require.False(t, args.enabled)
+		require.Equal(t, defaultWaitTimeout, args.timeout)
+		require.Equal(t, defaultWaitInterval, args.interval)
+	})
+
+	t.Run("invalid wait flag", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetCreateDIDCmd()
+
+		require.NoError(t, cmd.Flags().Set(waitFlagName, "invalid"))
+
+		_, err := getWaitArgs(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), waitFlagName)
+	})
+
+	t.Run("invalid wait timeout", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetCreateDIDCmd()
+
+		var args []string
+		args = append(args, sidetreeURLArg("https://example.com")...)
+		args = append(args, flag+waitFlagName, "true")
+		args = append(args, flag+waitTimeoutFlagName, "invalid")
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "wait-timeout: invalid value [invalid]")
+	})
+
+	t.Run("invalid wait interval", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetCreateDIDCmd()
+
+		require.NoError(t, cmd.Flags().Set(waitIntervalFlagName, "invalid"))
+
+		_, err := getWaitArgs(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "wait-interval: invalid value [invalid]")
+	})
+}
+
+func TestWaitForAnchored(t *testing.T) {
+	const (
+		interimDID   = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+		canonicalDID = "did:orb:uEiBYw:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+	)
+
+	args := &waitArgs{enabled: true, timeout: 50 * time.Millisecond, interval: time.Millisecond}
+
+	t.Run("anchored", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := waitForAnchored(context.Background(), out, &mockDIDResolver{canonicalID: canonicalDID},
+			interimDID, args)
+		require.NoError(t, err)
+		require.Equal(t, canonicalDID+"\n", out.String())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := waitForAnchored(context.Background(), out, &mockDIDResolver{}, interimDID, args)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "timed out after 50ms waiting for DID to be anchored")
+		require.Equal(t, interimDID+"\n", out.String())
+
+		var exitErr *common.ExitError
+		require.True(t, errors.As(err, &exitErr))
+		require.Equal(t, common.ExitCodeWaitTimeout, exitErr.Code)
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := waitForAnchored(ctx, out, &mockDIDResolver{}, interimDID, args)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "interrupted while waiting for DID to be anchored")
+		require.Equal(t, interimDID+"\n", out.String())
+
+		var exitErr *common.ExitError
+		require.True(t, errors.As(err, &exitErr))
+		require.Equal(t, common.ExitCodeInterrupted, exitErr.Code)
+	})
 }
 
 func TestGetPublicKeys(t *testing.T) {
@@ -331,3 +481,14 @@ func didAnchorOrigin(value string) []string {
 func didAlsoKnownAsArg(value string) []string {
 	return []string{flag + didAlsoKnownAsFlagName, value}
 }
+
+type mockDIDResolver struct {
+	canonicalID string
+}
+
+func (m *mockDIDResolver) Read(didID string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return &did.DocResolution{
+		DIDDocument:      &did.Doc{ID: didID},
+		DocumentMetadata: &did.DocumentMetadata{CanonicalID: m.canonicalID},
+	}, nil
+}
@@ -7,11 +7,8 @@ package deactivatedidcmd
 
 import (
 	"crypto"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
 	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/api"
@@ -24,7 +21,7 @@ import (
 
 	"github.com/trustbloc/orb/cmd/orb-cli/common"
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
-	"github.com/trustbloc/orb/internal/pkg/tlsutil"
+	"github.com/trustbloc/orb/pkg/document/util"
 )
 
 const (
@@ -108,7 +105,7 @@ func deactivateDIDCmd() *cobra.Command {
 		Long:         "Deactivate orb DID",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			rootCAs, err := getRootCAs(cmd)
+			tlsConfig, err := common.NewTLSConfig(cmd)
 			if err != nil {
 				return err
 			}
@@ -119,6 +116,11 @@ func deactivateDIDCmd() *cobra.Command {
 				return err
 			}
 
+			err = util.ValidateOrbDID(didURI, common.GetDIDNamespace(cmd))
+			if err != nil {
+				return err
+			}
+
 			sidetreeWriteToken := cmdutil.GetUserSetOptionalVarFromString(cmd, sidetreeWriteTokenFlagName,
 				sidetreeWriteTokenEnvKey)
 
@@ -127,7 +129,7 @@ func deactivateDIDCmd() *cobra.Command {
 
 			httpClient := http.Client{Transport: &http.Transport{
 				ForceAttemptHTTP2: true,
-				TLSClientConfig:   &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12},
+				TLSClientConfig:   tlsConfig,
 			}}
 
 			kmsStoreURL := cmdutil.GetUserSetOptionalVarFromString(cmd, kmsStoreEndpointFlagName,
@@ -168,6 +170,13 @@ func deactivateDIDCmd() *cobra.Command {
 				}
 			}
 
+			vdrHTTPClient := &httpClient
+
+			capture := common.NewRequestCapture(cmd, httpClient.Transport)
+			if capture != nil {
+				vdrHTTPClient = &http.Client{Transport: capture}
+			}
+
 			vdr, err := orb.New(&keyRetriever{
 				signingKey:         signingKey,
 				signingKeyID:       signingKeyID,
@@ -175,12 +184,16 @@ func deactivateDIDCmd() *cobra.Command {
 				signingKeyPK:       signingKeyPK,
 			},
 				orb.WithAuthToken(sidetreeWriteToken), orb.WithDomain(domain),
-				orb.WithHTTPClient(&httpClient))
+				orb.WithHTTPClient(vdrHTTPClient))
 			if err != nil {
 				return err
 			}
 
 			err = vdr.Deactivate(didURI, deactivateDIDOption(cmd)...)
+			if capture != nil {
+				return capture.Write(cmd.OutOrStdout(), err)
+			}
+
 			if err != nil {
 				return fmt.Errorf("failed to deactivate did: %w", err)
 			}
@@ -216,27 +229,6 @@ func deactivateDIDOption(cmd *cobra.Command) []vdrapi.DIDMethodOption {
 	return getSidetreeURL(cmd)
 }
 
-func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
-	tlsSystemCertPoolString := cmdutil.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
-		tlsSystemCertPoolEnvKey)
-
-	tlsSystemCertPool := false
-
-	if tlsSystemCertPoolString != "" {
-		var err error
-		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
-
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	tlsCACerts := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
-		tlsCACertsEnvKey)
-
-	return tlsutil.GetCertPool(tlsSystemCertPool, tlsCACerts)
-}
-
 func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(didURIFlagName, "", "", didURIFlagUsage)
 	startCmd.Flags().StringP(domainFlagName, "", "", domainFileFlagUsage)
@@ -251,6 +243,8 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(signingKeyPasswordFlagName, "", "", signingKeyPasswordFlagUsage)
 	startCmd.Flags().String(kmsStoreEndpointFlagName, "", kmsStoreEndpointFlagUsage)
 	startCmd.Flags().String(signingKeyIDFlagName, "", signingKeyIDFlagUsage)
+
+	common.AddOutputRequestFlag(startCmd)
 }
 
 type keyRetriever struct {
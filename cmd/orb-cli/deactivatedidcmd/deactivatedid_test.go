@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package deactivatedidcmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +15,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/model"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
 )
 
 const (
@@ -27,6 +33,21 @@ Q7UGQKWNHeITH8NQlkmcySEKnaI9uyOkcb6TIvklapHCAF8cUf1kCHU10Eo0RTMI
 2tJs7NW6oA4ZNi/o3xYVKVQ1R0lrgQGv9zatOupVPtQ=
 -----END EC PRIVATE KEY-----
 `
+
+	didResolution = `{
+  "@context": "https://w3id.org/did-resolution/v1",
+  "didDocument": {
+    "@context": ["https://www.w3.org/ns/did/v1"],
+    "id": "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+  },
+  "didDocumentMetadata": {
+    "method": {
+      "published": true,
+      "recoveryCommitment": "EiBfOZdMtU6OBw8Pk879QtZ-2J-9FbbjSZyoaA_bqD4zhA",
+      "updateCommitment": "EiDOrcmPtfMHuwIWN6YoihdeIPxOKDHy3D6sdMXu_7CN0w"
+    }
+  }
+}`
 )
 
 func TestMissingArg(t *testing.T) {
@@ -44,6 +65,21 @@ func TestMissingArg(t *testing.T) {
 		require.Contains(t, err.Error(), "Neither did-uri (command line flag) nor "+
 			"ORB_CLI_DID_URI (environment variable) have been set.")
 	})
+
+	t.Run("test invalid did uri", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetDeactivateDIDCmd()
+
+		var args []string
+		args = append(args, domainArg()...)
+		args = append(args, flag+didURIFlagName, "did:ex:123")
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting namespace [did:orb]")
+	})
 }
 
 func TestKeyRetriever(t *testing.T) {
@@ -106,6 +142,52 @@ func TestDeactivateDID(t *testing.T) {
 	})
 }
 
+func TestDeactivateDIDOutputRequest(t *testing.T) {
+	var posted bool
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posted = true
+		}
+
+		w.Header().Set("Content-Type", "application/did+ld+json")
+		fmt.Fprint(w, didResolution)
+	}))
+	defer serv.Close()
+
+	privateKeyfile, err := os.CreateTemp("", "*.json")
+	require.NoError(t, err)
+
+	_, err = privateKeyfile.WriteString(privateKeyPEM)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.Remove(privateKeyfile.Name())) }()
+
+	os.Clearenv()
+	cmd := GetDeactivateDIDCmd()
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	var args []string
+	args = append(args, didURIArg()...)
+	args = append(args, sidetreeURLArg(serv.URL)...)
+	args = append(args, flag+sidetreeURLResFlagName, serv.URL)
+	args = append(args, signingKeyPasswordArg()...)
+	args = append(args, signingKeyFileFlagNameArg(privateKeyfile.Name())...)
+	args = append(args, flag+common.OutputRequestFlagName, common.OutputRequestStdout)
+
+	cmd.SetArgs(args)
+	require.NoError(t, cmd.Execute())
+	require.False(t, posted)
+
+	req := &model.DeactivateRequest{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), req))
+	require.Equal(t, operation.TypeDeactivate, req.Operation)
+	require.Equal(t, "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A", req.DidSuffix)
+	require.NotEmpty(t, req.SignedData)
+}
+
 func TestKeys(t *testing.T) {
 	t.Run("test error getting signing key", func(t *testing.T) {
 		os.Clearenv()
@@ -140,7 +222,7 @@ func domainArg() []string {
 }
 
 func didURIArg() []string {
-	return []string{flag + didURIFlagName, "did:ex:123"}
+	return []string{flag + didURIFlagName, "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"}
 }
 
 func sidetreeURLArg(value string) []string {
@@ -0,0 +1,311 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diffdidcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	idProperty                 = "id"
+	controllerProperty         = "controller"
+	verificationMethodProperty = "verificationMethod"
+	serviceProperty            = "service"
+	alsoKnownAsProperty        = "alsoKnownAs"
+)
+
+// verificationRelationships contains the verification relationships which are compared, in the order
+// in which they are printed.
+var verificationRelationships = []string{ //nolint:gochecknoglobals
+	"authentication",
+	"assertionMethod",
+	"keyAgreement",
+	"capabilityInvocation",
+	"capabilityDelegation",
+}
+
+type document map[string]interface{}
+
+// changedEntry contains the ID of an entry (verification method or service) that exists in both
+// documents along with the names of the properties that differ.
+type changedEntry struct {
+	id         string
+	properties []string
+}
+
+// entryDiff contains the differences between entries (verification methods or services) which
+// are identified by ID.
+type entryDiff struct {
+	added   []string
+	removed []string
+	changed []*changedEntry
+}
+
+func (d *entryDiff) empty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0 && len(d.changed) == 0
+}
+
+// setDiff contains the differences between two sets of values.
+type setDiff struct {
+	added   []string
+	removed []string
+}
+
+func (d *setDiff) empty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0
+}
+
+// documentDiff contains the semantic differences between two DID documents.
+type documentDiff struct {
+	verificationMethods *entryDiff
+	services            *entryDiff
+	alsoKnownAs         *setDiff
+	relationships       map[string]*setDiff
+}
+
+func (d *documentDiff) numChanges() int {
+	n := len(d.verificationMethods.added) + len(d.verificationMethods.removed) +
+		len(d.verificationMethods.changed) +
+		len(d.services.added) + len(d.services.removed) + len(d.services.changed) +
+		len(d.alsoKnownAs.added) + len(d.alsoKnownAs.removed)
+
+	for _, r := range d.relationships {
+		n += len(r.added) + len(r.removed)
+	}
+
+	return n
+}
+
+// parseDocument parses the given JSON which may either be a DID document or a DID resolution result
+// (in which case the DID document is extracted from the result).
+func parseDocument(docBytes []byte) (document, error) {
+	doc := make(document)
+
+	err := json.Unmarshal(docBytes, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DID document: %w", err)
+	}
+
+	if rawDoc, ok := doc["didDocument"]; ok {
+		didDoc, ok := rawDoc.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("invalid resolution result: didDocument is not an object")
+		}
+
+		return didDoc, nil
+	}
+
+	return doc, nil
+}
+
+func (doc document) id() string {
+	id, ok := doc[idProperty].(string)
+	if !ok {
+		return ""
+	}
+
+	return id
+}
+
+// diffDocuments returns the differences between the base document and the given document. The documents
+// are compared semantically so differences in property order or in the representation of IDs (relative
+// vs. absolute) aren't reported as changes.
+func diffDocuments(base, doc document) *documentDiff {
+	diff := &documentDiff{
+		verificationMethods: diffEntries(base, doc, verificationMethodProperty),
+		services:            diffEntries(base, doc, serviceProperty),
+		alsoKnownAs:         diffSets(stringValues(base[alsoKnownAsProperty]), stringValues(doc[alsoKnownAsProperty])),
+		relationships:       make(map[string]*setDiff),
+	}
+
+	for _, rel := range verificationRelationships {
+		diff.relationships[rel] = diffSets(references(base[rel]), references(doc[rel]))
+	}
+
+	return diff
+}
+
+func diffEntries(base, doc document, property string) *entryDiff {
+	baseEntries := entries(base, property)
+	docEntries := entries(doc, property)
+
+	diff := &entryDiff{}
+
+	for id, entry := range docEntries {
+		baseEntry, ok := baseEntries[id]
+		if !ok {
+			diff.added = append(diff.added, id)
+
+			continue
+		}
+
+		if properties := changedProperties(baseEntry, entry); len(properties) > 0 {
+			diff.changed = append(diff.changed, &changedEntry{id: id, properties: properties})
+		}
+	}
+
+	for id := range baseEntries {
+		if _, ok := docEntries[id]; !ok {
+			diff.removed = append(diff.removed, id)
+		}
+	}
+
+	sort.Strings(diff.added)
+	sort.Strings(diff.removed)
+	sort.Slice(diff.changed, func(i, j int) bool { return diff.changed[i].id < diff.changed[j].id })
+
+	return diff
+}
+
+// entries returns the entries of the given property (e.g. verificationMethod) mapped by ID. If the
+// entry's controller is the document itself then the controller is removed so that the entries of two
+// different DIDs may be compared.
+func entries(doc document, property string) map[string]map[string]interface{} {
+	values, ok := doc[property].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]map[string]interface{})
+
+	for i, v := range values {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		normalized := make(map[string]interface{})
+
+		for name, value := range entry {
+			normalized[name] = value
+		}
+
+		id, ok := normalized[idProperty].(string)
+		if !ok || id == "" {
+			id = fmt.Sprintf("[%d]", i)
+		}
+
+		delete(normalized, idProperty)
+
+		if controller, ok := normalized[controllerProperty].(string); ok && controller == doc.id() {
+			delete(normalized, controllerProperty)
+		}
+
+		result[localID(id)] = normalized
+	}
+
+	return result
+}
+
+func changedProperties(base, entry map[string]interface{}) []string {
+	var properties []string
+
+	for name, value := range entry {
+		if baseValue, ok := base[name]; !ok || !reflect.DeepEqual(baseValue, value) {
+			properties = append(properties, name)
+		}
+	}
+
+	for name := range base {
+		if _, ok := entry[name]; !ok {
+			properties = append(properties, name)
+		}
+	}
+
+	sort.Strings(properties)
+
+	return properties
+}
+
+func diffSets(base, values []string) *setDiff {
+	baseSet := make(map[string]struct{})
+
+	for _, v := range base {
+		baseSet[v] = struct{}{}
+	}
+
+	valueSet := make(map[string]struct{})
+
+	for _, v := range values {
+		valueSet[v] = struct{}{}
+	}
+
+	diff := &setDiff{}
+
+	for v := range valueSet {
+		if _, ok := baseSet[v]; !ok {
+			diff.added = append(diff.added, v)
+		}
+	}
+
+	for v := range baseSet {
+		if _, ok := valueSet[v]; !ok {
+			diff.removed = append(diff.removed, v)
+		}
+	}
+
+	sort.Strings(diff.added)
+	sort.Strings(diff.removed)
+
+	return diff
+}
+
+func stringValues(value interface{}) []string {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var result []string
+
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// references returns the IDs of the verification methods in a verification relationship. An entry may
+// either be a reference to a verification method or an embedded verification method.
+func references(value interface{}) []string {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var result []string
+
+	for _, v := range values {
+		switch ref := v.(type) {
+		case string:
+			result = append(result, localID(ref))
+		case map[string]interface{}:
+			if id, ok := ref[idProperty].(string); ok {
+				result = append(result, localID(id))
+			}
+		}
+	}
+
+	return result
+}
+
+// localID returns the fragment of the given ID (e.g. "key1" for "did:orb:xxx#key1" and "#key1") so that
+// relative and absolute IDs (and IDs from two different DIDs) may be compared.
+func localID(id string) string {
+	if i := strings.LastIndex(id, "#"); i >= 0 {
+		return id[i+1:]
+	}
+
+	return id
+}
@@ -0,0 +1,295 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diffdidcmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/discovery/endpoint/client"
+	"github.com/trustbloc/orb/pkg/document/util"
+)
+
+const (
+	didFlagName  = "did"
+	didEnvKey    = "ORB_CLI_DID"
+	didFlagUsage = "The DID to resolve." +
+		" Alternatively, this can be set with the following environment variable: " + didEnvKey
+
+	againstFlagName  = "against"
+	againstEnvKey    = "ORB_CLI_AGAINST"
+	againstFlagUsage = "The document to compare against. This may either be the path to a file containing a " +
+		"DID document (or a DID resolution result) or another DID, in which case the DID is resolved. " +
+		"Changes are reported relative to this document, i.e. '+' means that the item exists in the resolved " +
+		"DID but not in this document." +
+		" Alternatively, this can be set with the following environment variable: " + againstEnvKey
+
+	resolutionEndpointFlagName  = "resolution-endpoint"
+	resolutionEndpointEnvKey    = "ORB_CLI_RESOLUTION_ENDPOINT"
+	resolutionEndpointFlagUsage = "The DID resolution endpoint. For example: " +
+		"https://orb.domain1.com/sidetree/v1/identifiers. If not set then the resolution endpoint is " +
+		"discovered from the domain." +
+		" Alternatively, this can be set with the following environment variable: " + resolutionEndpointEnvKey
+
+	domainFlagName  = "domain"
+	domainEnvKey    = "ORB_CLI_DOMAIN"
+	domainFlagUsage = "The URL of the Orb domain (for example, https://orb.domain1.com) from which the " +
+		"resolution endpoint is discovered if " + resolutionEndpointFlagName + " is not set." +
+		" Alternatively, this can be set with the following environment variable: " + domainEnvKey
+
+	didPrefix = "did:"
+)
+
+// GetDiffDIDCmd returns the Cobra diff DID command.
+func GetDiffDIDCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Shows the differences between a resolved DID document and a local document or another DID.",
+		Long: "Resolves the DID and compares the DID document against a local DID document (or the document of " +
+			"another DID). The documents are compared semantically and the added, removed and changed " +
+			"verification methods, services, alsoKnownAs entries and verification relationships are printed. " +
+			"For example: did diff --did did:orb:uAAA:EiD... --against ./doc.json " +
+			"--domain https://orb.domain1.com",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeDiff(cmd)
+		},
+	}
+
+	addFlags(cmd)
+
+	return cmd
+}
+
+type diffArgs struct {
+	did                string
+	against            string
+	resolutionEndpoint string
+	domain             string
+}
+
+func executeDiff(cmd *cobra.Command) error {
+	args, err := getArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := common.NewHTTPClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	r := &resolver{
+		httpClient: httpClient,
+		headers:    common.NewAuthTokenHeader(cmd),
+		endpoint:   args.resolutionEndpoint,
+	}
+
+	if r.endpoint == "" {
+		endpoints, e := client.DiscoverEndpoints(args.domain, r)
+		if e != nil {
+			return fmt.Errorf("discover resolution endpoint of %s: %w", args.domain, e)
+		}
+
+		r.endpoint = endpoints.ResolutionEndpoint
+	}
+
+	doc, err := r.resolve(args.did)
+	if err != nil {
+		return err
+	}
+
+	base, err := loadAgainst(r, args.against)
+	if err != nil {
+		return err
+	}
+
+	printDiff(cmd.OutOrStdout(), args, diffDocuments(base, doc))
+
+	return nil
+}
+
+func getArgs(cmd *cobra.Command) (*diffArgs, error) {
+	did, err := cmdutil.GetUserSetVarFromString(cmd, didFlagName, didEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.ValidateOrbDID(did, common.GetDIDNamespace(cmd))
+	if err != nil {
+		return nil, err
+	}
+
+	against, err := cmdutil.GetUserSetVarFromString(cmd, againstFlagName, againstEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(against, didPrefix) {
+		err = util.ValidateOrbDID(against, common.GetDIDNamespace(cmd))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", againstFlagName, err)
+		}
+	}
+
+	resolutionEndpoint := cmdutil.GetUserSetOptionalVarFromString(cmd, resolutionEndpointFlagName,
+		resolutionEndpointEnvKey)
+
+	domain := cmdutil.GetUserSetOptionalVarFromString(cmd, domainFlagName, domainEnvKey)
+
+	if resolutionEndpoint == "" && domain == "" {
+		return nil, fmt.Errorf("either %s or %s must be set", resolutionEndpointFlagName, domainFlagName)
+	}
+
+	return &diffArgs{
+		did:                did,
+		against:            against,
+		resolutionEndpoint: strings.TrimSuffix(resolutionEndpoint, "/"),
+		domain:             domain,
+	}, nil
+}
+
+func loadAgainst(r *resolver, against string) (document, error) {
+	if strings.HasPrefix(against, didPrefix) {
+		return r.resolve(against)
+	}
+
+	docBytes, err := os.ReadFile(against) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("read document from file [%s]: %w", against, err)
+	}
+
+	doc, err := parseDocument(docBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse document from file [%s]: %w", against, err)
+	}
+
+	return doc, nil
+}
+
+// resolver resolves DIDs from a resolution endpoint. It also implements the HTTP client used
+// for endpoint discovery so that the auth token header is included in the discovery requests.
+type resolver struct {
+	httpClient *http.Client
+	headers    map[string]string
+	endpoint   string
+}
+
+func (r *resolver) Do(req *http.Request) (*http.Response, error) {
+	for k, v := range r.headers {
+		req.Header.Add(k, v)
+	}
+
+	return r.httpClient.Do(req)
+}
+
+func (r *resolver) resolve(did string) (document, error) {
+	respBytes, err := common.SendRequest(r.httpClient, nil, r.headers, http.MethodGet, r.endpoint+"/"+did)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID [%s]: %w", did, err)
+	}
+
+	doc, err := parseDocument(respBytes)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID [%s]: %w", did, err)
+	}
+
+	return doc, nil
+}
+
+func printDiff(out io.Writer, args *diffArgs, diff *documentDiff) {
+	common.Printf(out, "Comparing %s against %s\n", args.did, args.against)
+
+	numChanges := diff.numChanges()
+	if numChanges == 0 {
+		common.Println(out, "No differences found")
+
+		return
+	}
+
+	printEntryDiff(out, "Verification methods", diff.verificationMethods)
+	printEntryDiff(out, "Services", diff.services)
+
+	if !diff.alsoKnownAs.empty() {
+		common.Println(out, "Also known as:")
+
+		printSetDiff(out, diff.alsoKnownAs)
+	}
+
+	var relationshipsPrinted bool
+
+	for _, rel := range verificationRelationships {
+		relDiff := diff.relationships[rel]
+		if relDiff.empty() {
+			continue
+		}
+
+		if !relationshipsPrinted {
+			common.Println(out, "Verification relationships:")
+
+			relationshipsPrinted = true
+		}
+
+		common.Printf(out, "  %s:\n", rel)
+
+		for _, id := range relDiff.added {
+			common.Printf(out, "    + %s\n", id)
+		}
+
+		for _, id := range relDiff.removed {
+			common.Printf(out, "    - %s\n", id)
+		}
+	}
+
+	common.Printf(out, "Found %d difference(s)\n", numChanges)
+}
+
+func printEntryDiff(out io.Writer, title string, diff *entryDiff) {
+	if diff.empty() {
+		return
+	}
+
+	common.Printf(out, "%s:\n", title)
+
+	for _, id := range diff.added {
+		common.Printf(out, "  + %s\n", id)
+	}
+
+	for _, id := range diff.removed {
+		common.Printf(out, "  - %s\n", id)
+	}
+
+	for _, changed := range diff.changed {
+		common.Printf(out, "  ~ %s (changed: %s)\n", changed.id, strings.Join(changed.properties, ", "))
+	}
+}
+
+func printSetDiff(out io.Writer, diff *setDiff) {
+	for _, v := range diff.added {
+		common.Printf(out, "  + %s\n", v)
+	}
+
+	for _, v := range diff.removed {
+		common.Printf(out, "  - %s\n", v)
+	}
+}
+
+func addFlags(cmd *cobra.Command) {
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(didFlagName, "", "", didFlagUsage)
+	cmd.Flags().StringP(againstFlagName, "", "", againstFlagUsage)
+	cmd.Flags().StringP(resolutionEndpointFlagName, "", "", resolutionEndpointFlagUsage)
+	cmd.Flags().StringP(domainFlagName, "", "", domainFlagUsage)
+}
@@ -0,0 +1,278 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diffdidcmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/internal/testutil"
+)
+
+const (
+	flag = "--"
+
+	testDID  = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+	otherDID = "did:orb:uAAA:EiBvYQTF7Ny2ExlKNYMKhsMrb-VNVNVY4wvGH1RjHEntIg"
+
+	resolvedDocFmt = `{
+  "@context": ["https://www.w3.org/ns/did/v1"],
+  "id": "%[1]s",
+  "alsoKnownAs": ["https://myblog.example/"],
+  "verificationMethod": [
+    {"id": "%[1]s#key1", "type": "JsonWebKey2020", "controller": "%[1]s", "publicKeyJwk": {"kty": "OKP", "crv": "Ed25519", "x": "abc"}},
+    {"id": "%[1]s#key2", "type": "JsonWebKey2020", "controller": "%[1]s", "publicKeyJwk": {"kty": "OKP", "crv": "Ed25519", "x": "def"}}
+  ],
+  "authentication": ["%[1]s#key1"],
+  "assertionMethod": ["%[1]s#key1", "%[1]s#key2"],
+  "service": [{"id": "%[1]s#svc1", "type": "LinkedDomains", "serviceEndpoint": "https://example.com"}]
+}`
+
+	// Same document as the resolved document but with relative IDs and in a different order.
+	sameDoc = `{
+  "service": [{"serviceEndpoint": "https://example.com", "type": "LinkedDomains", "id": "#svc1"}],
+  "assertionMethod": ["#key2", "#key1"],
+  "authentication": ["#key1"],
+  "verificationMethod": [
+    {"publicKeyJwk": {"x": "def", "crv": "Ed25519", "kty": "OKP"}, "type": "JsonWebKey2020", "id": "#key2"},
+    {"publicKeyJwk": {"x": "abc", "crv": "Ed25519", "kty": "OKP"}, "type": "JsonWebKey2020", "id": "#key1"}
+  ],
+  "alsoKnownAs": ["https://myblog.example/"],
+  "id": "` + testDID + `"
+}`
+
+	changedDoc = `{
+  "didDocument": {
+    "id": "` + testDID + `",
+    "alsoKnownAs": ["https://other.example/"],
+    "verificationMethod": [
+      {"id": "#key1", "type": "JsonWebKey2020", "publicKeyJwk": {"kty": "OKP", "crv": "Ed25519", "x": "xyz"}},
+      {"id": "#key3", "type": "JsonWebKey2020", "publicKeyJwk": {"kty": "OKP", "crv": "Ed25519", "x": "ghi"}}
+    ],
+    "authentication": ["#key1", "#key3"],
+    "assertionMethod": ["#key1"],
+    "service": [{"id": "#svc1", "type": "LinkedDomains", "serviceEndpoint": "https://example.com"}]
+  }
+}`
+)
+
+func TestDiffDIDCmd(t *testing.T) {
+	t.Run("missing DID", func(t *testing.T) {
+		cmd := GetDiffDIDCmd()
+		cmd.SetArgs(nil)
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither did (command line flag) nor ORB_CLI_DID (environment variable) have been set.")
+	})
+
+	t.Run("invalid DID", func(t *testing.T) {
+		cmd := GetDiffDIDCmd()
+		cmd.SetArgs([]string{flag + didFlagName, "did:web:example.com"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("missing against", func(t *testing.T) {
+		cmd := GetDiffDIDCmd()
+		cmd.SetArgs([]string{flag + didFlagName, testDID})
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither against (command line flag) nor ORB_CLI_AGAINST (environment variable) have been set.")
+	})
+
+	t.Run("invalid against DID", func(t *testing.T) {
+		cmd := GetDiffDIDCmd()
+		cmd.SetArgs([]string{flag + didFlagName, testDID, flag + againstFlagName, "did:web:example.com"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), againstFlagName)
+	})
+
+	t.Run("missing endpoint and domain", func(t *testing.T) {
+		cmd := GetDiffDIDCmd()
+		cmd.SetArgs([]string{flag + didFlagName, testDID, flag + againstFlagName, "doc.json"})
+
+		err := cmd.Execute()
+		require.EqualError(t, err, "either resolution-endpoint or domain must be set")
+	})
+
+	t.Run("no differences", func(t *testing.T) {
+		serv := newResolutionServer(t)
+		defer serv.Close()
+
+		out, err := execute(t, flag+againstFlagName, writeFile(t, sameDoc),
+			flag+resolutionEndpointFlagName, serv.URL+"/sidetree/v1/identifiers/")
+		require.NoError(t, err)
+		require.Contains(t, out, "No differences found")
+	})
+
+	t.Run("differences against file", func(t *testing.T) {
+		serv := newResolutionServer(t)
+		defer serv.Close()
+
+		out, err := execute(t, flag+againstFlagName, writeFile(t, changedDoc),
+			flag+resolutionEndpointFlagName, serv.URL+"/sidetree/v1/identifiers")
+		require.NoError(t, err)
+
+		require.Contains(t, out, "Verification methods:\n"+
+			"  + key2\n"+
+			"  - key3\n"+
+			"  ~ key1 (changed: publicKeyJwk)\n")
+		require.NotContains(t, out, "Services:")
+		require.Contains(t, out, "Also known as:\n"+
+			"  + https://myblog.example/\n"+
+			"  - https://other.example/\n")
+		require.Contains(t, out, "Verification relationships:\n"+
+			"  authentication:\n"+
+			"    - key3\n"+
+			"  assertionMethod:\n"+
+			"    + key2\n")
+		require.Contains(t, out, "Found 7 difference(s)")
+	})
+
+	t.Run("differences against DID", func(t *testing.T) {
+		serv := newResolutionServer(t)
+		defer serv.Close()
+
+		out, err := execute(t, flag+againstFlagName, otherDID,
+			flag+resolutionEndpointFlagName, serv.URL+"/sidetree/v1/identifiers")
+		require.NoError(t, err)
+		require.Contains(t, out, "Comparing "+testDID+" against "+otherDID)
+		require.Contains(t, out, "Services:\n  ~ svc1 (changed: serviceEndpoint)\n")
+		require.Contains(t, out, "Found 1 difference(s)")
+	})
+
+	t.Run("discover resolution endpoint from domain", func(t *testing.T) {
+		serv := newResolutionServer(t)
+		defer serv.Close()
+
+		out, err := execute(t, flag+againstFlagName, writeFile(t, sameDoc), flag+domainFlagName, serv.URL)
+		require.NoError(t, err)
+		require.Contains(t, out, "No differences found")
+	})
+
+	t.Run("discovery error", func(t *testing.T) {
+		serv := httptest.NewServer(http.NotFoundHandler())
+		defer serv.Close()
+
+		_, err := execute(t, flag+againstFlagName, writeFile(t, sameDoc), flag+domainFlagName, serv.URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "discover resolution endpoint of "+serv.URL)
+	})
+
+	t.Run("resolve error", func(t *testing.T) {
+		serv := httptest.NewServer(http.NotFoundHandler())
+		defer serv.Close()
+
+		_, err := execute(t, flag+againstFlagName, writeFile(t, sameDoc),
+			flag+resolutionEndpointFlagName, serv.URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve DID ["+testDID+"]")
+	})
+
+	t.Run("file errors", func(t *testing.T) {
+		serv := newResolutionServer(t)
+		defer serv.Close()
+
+		_, err := execute(t, flag+againstFlagName, filepath.Join(t.TempDir(), "missing.json"),
+			flag+resolutionEndpointFlagName, serv.URL+"/sidetree/v1/identifiers")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read document from file")
+
+		_, err = execute(t, flag+againstFlagName, writeFile(t, "{"),
+			flag+resolutionEndpointFlagName, serv.URL+"/sidetree/v1/identifiers")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid DID document")
+
+		_, err = execute(t, flag+againstFlagName, writeFile(t, `{"didDocument":"xxx"}`),
+			flag+resolutionEndpointFlagName, serv.URL+"/sidetree/v1/identifiers")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "didDocument is not an object")
+	})
+}
+
+func TestDiffDocuments(t *testing.T) {
+	base, err := parseDocument([]byte(`{
+  "id": "did:orb:123",
+  "verificationMethod": [
+    {"id": "did:orb:123#key1", "controller": "did:orb:123", "type": "JsonWebKey2020"},
+    {"controller": "did:orb:other", "type": "JsonWebKey2020"}
+  ],
+  "keyAgreement": [{"id": "#key4", "type": "X25519KeyAgreementKey2019"}]
+}`))
+	require.NoError(t, err)
+
+	doc, err := parseDocument([]byte(`{
+  "id": "did:orb:456",
+  "verificationMethod": [
+    {"id": "#key1", "controller": "did:orb:456", "type": "Ed25519VerificationKey2018"},
+    {"controller": "did:orb:other", "type": "JsonWebKey2020"}
+  ],
+  "capabilityInvocation": ["did:orb:456#key1"]
+}`))
+	require.NoError(t, err)
+
+	diff := diffDocuments(base, doc)
+	require.Empty(t, diff.verificationMethods.added)
+	require.Empty(t, diff.verificationMethods.removed)
+	require.Len(t, diff.verificationMethods.changed, 1)
+	require.Equal(t, "key1", diff.verificationMethods.changed[0].id)
+	require.Equal(t, []string{"type"}, diff.verificationMethods.changed[0].properties)
+	require.Equal(t, []string{"key4"}, diff.relationships["keyAgreement"].removed)
+	require.Equal(t, []string{"key1"}, diff.relationships["capabilityInvocation"].added)
+	require.True(t, diff.services.empty())
+	require.True(t, diff.alsoKnownAs.empty())
+	require.Equal(t, 3, diff.numChanges())
+}
+
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+
+	cmd := GetDiffDIDCmd()
+	cmd.SetOut(out)
+	cmd.SetArgs(append([]string{flag + didFlagName, testDID}, args...))
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "doc.json")
+
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+	return file
+}
+
+// newResolutionServer returns a stub Orb server which resolves testDID and otherDID. The document
+// of otherDID differs from that of testDID by the endpoint of its service.
+func newResolutionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return testutil.NewResolutionServer(t, map[string]string{
+		testDID: fmt.Sprintf(`{"didDocument":%s}`, fmt.Sprintf(resolvedDocFmt, testDID)),
+		otherDID: fmt.Sprintf(`{"didDocument":%s}`,
+			strings.Replace(fmt.Sprintf(resolvedDocFmt, otherDID), "https://example.com", "https://other.com", 1)),
+	})
+}
@@ -0,0 +1,279 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package healthcheckcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+)
+
+const (
+	domainsFlagName  = "domains"
+	domainsEnvKey    = "ORB_CLI_DOMAINS"
+	domainsFlagUsage = "Comma-separated list of Orb domains to check. For example: " +
+		"https://orb.domain1.com,https://orb.domain2.com. If the scheme is omitted then https is assumed." +
+		" Alternatively, this can be set with the following environment variable: " + domainsEnvKey
+
+	timeoutFlagName  = "request-timeout"
+	timeoutEnvKey    = "ORB_CLI_REQUEST_TIMEOUT"
+	timeoutFlagUsage = "The timeout for each request to a domain. For example, '10s' for a " +
+		"10 second timeout. A domain that doesn't respond within this time is marked as unreachable." +
+		" Alternatively, this can be set with the following environment variable: " + timeoutEnvKey
+
+	defaultTimeout = 10 * time.Second
+
+	wellKnownPath = "/.well-known/did-orb"
+	webFingerPath = "/.well-known/webfinger"
+)
+
+// GetCmd returns the Cobra health check command.
+func GetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "health-check",
+		Short: "Checks the connectivity and endpoint discovery of a set of Orb domains.",
+		Long: "Performs endpoint discovery (using .well-known/did-orb and WebFinger) on each of the given domains " +
+			"and reports whether the domain is healthy along with its resolution and operation endpoints. " +
+			"The command fails if any of the domains is unreachable or if its endpoints can't be discovered. " +
+			"For example: health-check --domains https://orb.domain1.com,https://orb.domain2.com",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeHealthCheck(cmd)
+		},
+	}
+
+	addFlags(cmd)
+
+	return cmd
+}
+
+type domainResult struct {
+	domain             string
+	resolutionEndpoint string
+	operationEndpoint  string
+	unreachable        bool
+	err                error
+}
+
+func (r *domainResult) healthy() bool {
+	return r.err == nil
+}
+
+// requestError is returned when the request fails or the domain responds with an error status.
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+func executeHealthCheck(cmd *cobra.Command) error {
+	domains, timeout, err := getArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := common.NewHTTPClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	httpClient.Timeout = timeout
+
+	results := checkAll(httpClient, common.NewAuthTokenHeader(cmd), domains)
+
+	return printResults(cmd, results)
+}
+
+func getArgs(cmd *cobra.Command) ([]string, time.Duration, error) {
+	domains, err := cmdutil.GetUserSetVarFromArrayString(cmd, domainsFlagName, domainsEnvKey, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	domains = splitDomains(domains)
+
+	if len(domains) == 0 {
+		return nil, 0, fmt.Errorf("at least one domain is required in %s", domainsFlagName)
+	}
+
+	timeout, err := common.GetDuration(cmd, timeoutFlagName, timeoutEnvKey, defaultTimeout)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if timeout <= 0 {
+		return nil, 0, fmt.Errorf("%s must be greater than 0", timeoutFlagName)
+	}
+
+	return domains, timeout, nil
+}
+
+func splitDomains(values []string) []string {
+	var domains []string
+
+	for _, value := range values {
+		for _, domain := range strings.Split(value, ",") {
+			domain = strings.TrimSuffix(strings.TrimSpace(domain), "/")
+			if domain == "" {
+				continue
+			}
+
+			if !strings.Contains(domain, "://") {
+				domain = "https://" + domain
+			}
+
+			domains = append(domains, domain)
+		}
+	}
+
+	return domains
+}
+
+// checkAll checks all domains concurrently. The results are returned in the same order as the given domains.
+func checkAll(httpClient *http.Client, headers map[string]string, domains []string) []*domainResult {
+	results := make([]*domainResult, len(domains))
+
+	var wg sync.WaitGroup
+
+	for i, domain := range domains {
+		wg.Add(1)
+
+		go func(i int, domain string) {
+			defer wg.Done()
+
+			results[i] = check(httpClient, headers, domain)
+		}(i, domain)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func check(httpClient *http.Client, headers map[string]string, domain string) *domainResult {
+	result := &domainResult{domain: domain}
+
+	result.err = discover(httpClient, headers, result)
+
+	return result
+}
+
+// discover discovers the resolution and operation endpoints of the domain using .well-known/did-orb
+// and then resolves each of the endpoints using WebFinger. The domain is marked as unreachable if the
+// .well-known/did-orb request fails.
+func discover(httpClient *http.Client, headers map[string]string, result *domainResult) error {
+	wellKnown := &restapi.WellKnownResponse{}
+
+	err := get(httpClient, headers, result.domain+wellKnownPath, wellKnown)
+	if err != nil {
+		result.unreachable = errors.As(err, new(*requestError))
+
+		return err
+	}
+
+	if wellKnown.ResolutionEndpoint == "" || wellKnown.OperationEndpoint == "" {
+		return fmt.Errorf("missing resolution or operation endpoint in %s response", wellKnownPath)
+	}
+
+	result.resolutionEndpoint, err = webFinger(httpClient, headers, result.domain, wellKnown.ResolutionEndpoint)
+	if err != nil {
+		return fmt.Errorf("resolution endpoint: %w", err)
+	}
+
+	result.operationEndpoint, err = webFinger(httpClient, headers, result.domain, wellKnown.OperationEndpoint)
+	if err != nil {
+		return fmt.Errorf("operation endpoint: %w", err)
+	}
+
+	return nil
+}
+
+func webFinger(httpClient *http.Client, headers map[string]string, domain, resource string) (string, error) {
+	jrd := &restapi.JRD{}
+
+	err := get(httpClient, headers,
+		fmt.Sprintf("%s%s?resource=%s", domain, webFingerPath, url.QueryEscape(resource)), jrd)
+	if err != nil {
+		return "", err
+	}
+
+	if len(jrd.Links) == 0 || jrd.Links[0].Href == "" {
+		return "", fmt.Errorf("no links in WebFinger response for resource [%s]", resource)
+	}
+
+	return jrd.Links[0].Href, nil
+}
+
+func get(httpClient *http.Client, headers map[string]string, endpoint string, v interface{}) error {
+	respBytes, err := common.SendRequest(httpClient, nil, headers, http.MethodGet, endpoint)
+	if err != nil {
+		return &requestError{err: err}
+	}
+
+	err = json.Unmarshal(respBytes, v)
+	if err != nil {
+		return fmt.Errorf("invalid response from %s: %w", endpoint, err)
+	}
+
+	return nil
+}
+
+func printResults(cmd *cobra.Command, results []*domainResult) error {
+	out := cmd.OutOrStdout()
+
+	var numHealthy int
+
+	for _, r := range results {
+		if r.healthy() {
+			numHealthy++
+		}
+	}
+
+	common.Printf(out, "Checked %d domain(s), %d healthy\n", len(results), numHealthy)
+
+	for _, r := range results {
+		switch {
+		case r.unreachable:
+			common.Printf(out, "  %s: UNREACHABLE (%s)\n", r.domain, r.err)
+		case !r.healthy():
+			common.Printf(out, "  %s: DISCOVERY FAILED (%s)\n", r.domain, r.err)
+		default:
+			common.Printf(out, "  %s: OK resolution=%s operation=%s\n",
+				r.domain, r.resolutionEndpoint, r.operationEndpoint)
+		}
+	}
+
+	if numHealthy != len(results) {
+		return fmt.Errorf("%d of %d domain(s) are not healthy", len(results)-numHealthy, len(results))
+	}
+
+	return nil
+}
+
+func addFlags(cmd *cobra.Command) {
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringArrayP(domainsFlagName, "", nil, domainsFlagUsage)
+	cmd.Flags().StringP(timeoutFlagName, "", "", timeoutFlagUsage)
+}
@@ -0,0 +1,230 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package healthcheckcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+)
+
+const flag = "--"
+
+func TestHealthCheckCmd(t *testing.T) {
+	t.Run("missing domains", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs(nil)
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither domains (command line flag) nor ORB_CLI_DOMAINS (environment variable) have been set.")
+	})
+
+	t.Run("empty domains", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{flag + domainsFlagName, " , "})
+
+		err := cmd.Execute()
+		require.EqualError(t, err, "at least one domain is required in domains")
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{flag + domainsFlagName, "https://orb1.com", flag + timeoutFlagName, "xxx"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value")
+
+		cmd = GetCmd()
+		cmd.SetArgs([]string{flag + domainsFlagName, "https://orb1.com", flag + timeoutFlagName, "0s"})
+
+		err = cmd.Execute()
+		require.EqualError(t, err, "request-timeout must be greater than 0")
+	})
+
+	t.Run("all domains healthy", func(t *testing.T) {
+		serv1 := newDomainServer(t, nil)
+		defer serv1.Close()
+
+		serv2 := newDomainServer(t, nil)
+		defer serv2.Close()
+
+		out, err := execute(t, serv1.URL, serv2.URL+"/")
+		require.NoError(t, err)
+		require.Contains(t, out, "Checked 2 domain(s), 2 healthy")
+		require.Contains(t, out, serv1.URL+": OK resolution="+serv1.URL+"/sidetree/v1/identifiers"+
+			" operation="+serv1.URL+"/sidetree/v1/operations")
+		require.Contains(t, out, serv2.URL+": OK resolution="+serv2.URL+"/sidetree/v1/identifiers")
+	})
+
+	t.Run("unreachable domains", func(t *testing.T) {
+		serv := newDomainServer(t, nil)
+		defer serv.Close()
+
+		downServ := newDomainServer(t, nil)
+		downServ.Close()
+
+		slowServ := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(time.Second)
+		}))
+		defer slowServ.Close()
+
+		notFoundServ := httptest.NewServer(http.NotFoundHandler())
+		defer notFoundServ.Close()
+
+		out, err := execute(t, serv.URL, downServ.URL, slowServ.URL, notFoundServ.URL,
+			flag+timeoutFlagName, "100ms")
+		require.EqualError(t, err, "3 of 4 domain(s) are not healthy")
+		require.Contains(t, out, "Checked 4 domain(s), 1 healthy")
+		require.Contains(t, out, serv.URL+": OK")
+		require.Contains(t, out, downServ.URL+": UNREACHABLE")
+		require.Contains(t, out, slowServ.URL+": UNREACHABLE")
+		require.Contains(t, out, notFoundServ.URL+": UNREACHABLE")
+	})
+
+	t.Run("discovery failures", func(t *testing.T) {
+		invalidServ := newDomainServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+			if r.URL.Path == wellKnownPath {
+				_, err := w.Write([]byte("{"))
+				require.NoError(t, err)
+
+				return true
+			}
+
+			return false
+		})
+		defer invalidServ.Close()
+
+		missingEndpointServ := newDomainServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+			if r.URL.Path == wellKnownPath {
+				_, err := w.Write([]byte("{}"))
+				require.NoError(t, err)
+
+				return true
+			}
+
+			return false
+		})
+		defer missingEndpointServ.Close()
+
+		noWebFingerServ := newDomainServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+			if r.URL.Path == webFingerPath {
+				w.WriteHeader(http.StatusNotFound)
+
+				return true
+			}
+
+			return false
+		})
+		defer noWebFingerServ.Close()
+
+		noLinksServ := newDomainServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+			if r.URL.Path == webFingerPath && strings.Contains(r.URL.Query().Get("resource"), "operations") {
+				_, err := w.Write([]byte("{}"))
+				require.NoError(t, err)
+
+				return true
+			}
+
+			return false
+		})
+		defer noLinksServ.Close()
+
+		out, err := execute(t, invalidServ.URL, missingEndpointServ.URL, noWebFingerServ.URL, noLinksServ.URL)
+		require.EqualError(t, err, "4 of 4 domain(s) are not healthy")
+		require.Contains(t, out, invalidServ.URL+": DISCOVERY FAILED (invalid response from")
+		require.Contains(t, out, missingEndpointServ.URL+
+			": DISCOVERY FAILED (missing resolution or operation endpoint in /.well-known/did-orb response)")
+		require.Contains(t, out, noWebFingerServ.URL+": DISCOVERY FAILED (resolution endpoint:")
+		require.Contains(t, out, noLinksServ.URL+": DISCOVERY FAILED (operation endpoint: no links")
+	})
+}
+
+func TestSplitDomains(t *testing.T) {
+	require.Equal(t,
+		[]string{"https://orb.domain1.com", "http://orb.domain2.com", "https://orb.domain3.com"},
+		splitDomains([]string{"orb.domain1.com, http://orb.domain2.com/", "https://orb.domain3.com"}),
+	)
+}
+
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var domains, other []string
+
+	for i, arg := range args {
+		if strings.HasPrefix(arg, flag) {
+			other = args[i:]
+
+			break
+		}
+
+		domains = append(domains, arg)
+	}
+
+	out := &bytes.Buffer{}
+
+	cmd := GetCmd()
+	cmd.SetOut(out)
+	cmd.SetArgs(append([]string{flag + domainsFlagName, strings.Join(domains, ",")}, other...))
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+// newDomainServer returns a stub Orb server which supports .well-known/did-orb and WebFinger. The optional
+// override function may handle the request instead, in which case it returns true.
+func newDomainServer(t *testing.T, override func(w http.ResponseWriter, r *http.Request) bool) *httptest.Server {
+	t.Helper()
+
+	var serv *httptest.Server
+
+	serv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if override != nil && override(w, r) {
+			return
+		}
+
+		var resp interface{}
+
+		switch r.URL.Path {
+		case wellKnownPath:
+			resp = &restapi.WellKnownResponse{
+				ResolutionEndpoint: serv.URL + "/sidetree/v1/identifiers",
+				OperationEndpoint:  serv.URL + "/sidetree/v1/operations",
+			}
+		case webFingerPath:
+			resource := r.URL.Query().Get("resource")
+
+			resp = &restapi.JRD{
+				Subject: resource,
+				Links:   []restapi.Link{{Rel: "self", Href: resource}},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		respBytes, err := json.Marshal(resp)
+		require.NoError(t, err)
+
+		_, err = w.Write(respBytes)
+		require.NoError(t, err)
+	}))
+
+	return serv
+}
@@ -0,0 +1,317 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package historydidcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trustbloc/sidetree-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-go/pkg/document"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/discovery/endpoint/client"
+	"github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/orbclient/resolutionverifier"
+)
+
+const (
+	didFlagName  = "did"
+	didEnvKey    = "ORB_CLI_DID"
+	didFlagUsage = "The DID whose history is shown." +
+		" Alternatively, this can be set with the following environment variable: " + didEnvKey
+
+	resolutionEndpointFlagName  = "resolution-endpoint"
+	resolutionEndpointEnvKey    = "ORB_CLI_RESOLUTION_ENDPOINT"
+	resolutionEndpointFlagUsage = "The DID resolution endpoint. For example: " +
+		"https://orb.domain1.com/sidetree/v1/identifiers. If not set then the resolution endpoint is " +
+		"discovered from the domain." +
+		" Alternatively, this can be set with the following environment variable: " + resolutionEndpointEnvKey
+
+	domainFlagName  = "domain"
+	domainEnvKey    = "ORB_CLI_DOMAIN"
+	domainFlagUsage = "The URL of the Orb domain (for example, https://orb.domain1.com) from which the " +
+		"resolution endpoint is discovered if " + resolutionEndpointFlagName + " is not set." +
+		" Alternatively, this can be set with the following environment variable: " + domainEnvKey
+
+	verifyFlagName  = "verify"
+	verifyEnvKey    = "ORB_CLI_VERIFY"
+	verifyFlagUsage = "Set to 'true' to verify the commitment linkage between consecutive operations, i.e. that " +
+		"the reveal value of each update operation matches the update commitment of the previous state and that " +
+		"the reveal value of each recover/deactivate operation matches the recovery commitment of the previous " +
+		"state. Defaults to false." +
+		" Alternatively, this can be set with the following environment variable: " + verifyEnvKey
+)
+
+// errCommitmentLinkage is returned when the commitment linkage of one or more operations is broken.
+var errCommitmentLinkage = errors.New("commitment linkage verification failed")
+
+// GetHistoryDIDCmd returns the Cobra DID history command.
+func GetHistoryDIDCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Shows how a DID document evolved, operation by operation.",
+		Long: "Resolves the DID and replays the DID's operations offline, one at a time, printing the operation " +
+			"type, the anchor time and the document state after each operation (create, each update, recover " +
+			"and deactivate). The server must include published and unpublished operations in the resolution " +
+			"result. For example: did history --did did:orb:uEiA...:EiD... --domain https://orb.domain1.com --verify true",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeHistory(cmd)
+		},
+	}
+
+	addFlags(cmd)
+
+	return cmd
+}
+
+type historyArgs struct {
+	did                string
+	resolutionEndpoint string
+	domain             string
+	verify             bool
+}
+
+type replayer interface {
+	Replay(input *document.ResolutionResult) ([]*resolutionverifier.ReplayStep, error)
+}
+
+func executeHistory(cmd *cobra.Command) error {
+	args, err := getArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := common.NewHTTPClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	r := &resolver{
+		httpClient: httpClient,
+		headers:    common.NewAuthTokenHeader(cmd),
+		endpoint:   args.resolutionEndpoint,
+	}
+
+	if r.endpoint == "" {
+		endpoints, e := client.DiscoverEndpoints(args.domain, r)
+		if e != nil {
+			return fmt.Errorf("discover resolution endpoint of %s: %w", args.domain, e)
+		}
+
+		r.endpoint = endpoints.ResolutionEndpoint
+	}
+
+	rr, err := r.resolve(args.did)
+	if err != nil {
+		return err
+	}
+
+	verifier, err := resolutionverifier.New(common.GetDIDNamespace(cmd))
+	if err != nil {
+		return fmt.Errorf("create resolution verifier: %w", err)
+	}
+
+	return printHistory(cmd.OutOrStdout(), verifier, args, rr)
+}
+
+func getArgs(cmd *cobra.Command) (*historyArgs, error) {
+	did, err := cmdutil.GetUserSetVarFromString(cmd, didFlagName, didEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.ValidateOrbDID(did, common.GetDIDNamespace(cmd))
+	if err != nil {
+		return nil, err
+	}
+
+	resolutionEndpoint := cmdutil.GetUserSetOptionalVarFromString(cmd, resolutionEndpointFlagName,
+		resolutionEndpointEnvKey)
+
+	domain := cmdutil.GetUserSetOptionalVarFromString(cmd, domainFlagName, domainEnvKey)
+
+	if resolutionEndpoint == "" && domain == "" {
+		return nil, fmt.Errorf("either %s or %s must be set", resolutionEndpointFlagName, domainFlagName)
+	}
+
+	verify, err := cmdutil.GetBool(cmd, verifyFlagName, verifyEnvKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", verifyFlagName, err)
+	}
+
+	return &historyArgs{
+		did:                did,
+		resolutionEndpoint: strings.TrimSuffix(resolutionEndpoint, "/"),
+		domain:             domain,
+		verify:             verify,
+	}, nil
+}
+
+// printHistory replays the operations in the given resolution result and prints the document state after each
+// operation. If verification is enabled then the commitment linkage of each operation is also printed and an
+// error is returned if the linkage of any operation is broken.
+func printHistory(out io.Writer, r replayer, args *historyArgs, rr *document.ResolutionResult) error {
+	steps, err := r.Replay(rr)
+	if err != nil {
+		return fmt.Errorf("replay operations of DID [%s] (the server must be configured to include published "+
+			"and unpublished operations in the resolution result): %w", args.did, err)
+	}
+
+	common.Printf(out, "History of %s (%d operation(s))\n", args.did, len(steps))
+
+	var numBroken int
+
+	for i, step := range steps {
+		common.Printf(out, "\n#%d %s - %s\n", i+1, step.Operation.Type, anchorTime(step))
+
+		if args.verify && i > 0 {
+			if err := verifyCommitment(steps[i-1].Result, step.Operation); err != nil {
+				numBroken++
+
+				common.Printf(out, "Commitment linkage: BROKEN - %s\n", err)
+			} else {
+				common.Println(out, "Commitment linkage: OK")
+			}
+		}
+
+		if err := printState(out, step.Result); err != nil {
+			return err
+		}
+	}
+
+	if numBroken > 0 {
+		return fmt.Errorf("%d operation(s) of DID [%s]: %w", numBroken, args.did, errCommitmentLinkage)
+	}
+
+	return nil
+}
+
+func anchorTime(step *resolutionverifier.ReplayStep) string {
+	if !step.Published {
+		return "unpublished"
+	}
+
+	//nolint:gosec
+	return "anchored " + time.Unix(int64(step.Operation.TransactionTime), 0).UTC().Format(time.RFC3339)
+}
+
+func printState(out io.Writer, rm *protocol.ResolutionModel) error {
+	if rm.Deactivated {
+		common.Println(out, "Document (deactivated):")
+		common.Println(out, "{}")
+
+		return nil
+	}
+
+	docBytes, err := json.MarshalIndent(rm.Doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal document: %w", err)
+	}
+
+	common.Println(out, "Document:")
+	common.Println(out, string(docBytes))
+
+	return nil
+}
+
+// verifyCommitment verifies that the reveal value of the given operation matches the corresponding commitment
+// of the previous state, i.e. the update commitment for an update operation and the recovery commitment for
+// a recover or deactivate operation.
+func verifyCommitment(previous *protocol.ResolutionModel, op *operation.AnchoredOperation) error {
+	var expected string
+
+	switch op.Type {
+	case operation.TypeUpdate:
+		expected = previous.UpdateCommitment
+	case operation.TypeRecover, operation.TypeDeactivate:
+		expected = previous.RecoveryCommitment
+	default:
+		return fmt.Errorf("unexpected %s operation", op.Type)
+	}
+
+	if previous.Deactivated {
+		return fmt.Errorf("%s operation after deactivate", op.Type)
+	}
+
+	opRequest := &struct {
+		RevealValue string `json:"revealValue"`
+	}{}
+
+	if err := json.Unmarshal(op.OperationRequest, opRequest); err != nil {
+		return fmt.Errorf("unmarshal %s operation request: %w", op.Type, err)
+	}
+
+	if opRequest.RevealValue == "" {
+		return fmt.Errorf("%s operation request does not contain a reveal value", op.Type)
+	}
+
+	c, err := commitment.GetCommitmentFromRevealValue(opRequest.RevealValue)
+	if err != nil {
+		return fmt.Errorf("get commitment from reveal value: %w", err)
+	}
+
+	if c != expected {
+		return fmt.Errorf("commitment [%s] of reveal value doesn't match the previous commitment [%s]", c, expected)
+	}
+
+	return nil
+}
+
+// resolver resolves DIDs from a resolution endpoint. It also implements the HTTP client used
+// for endpoint discovery so that the auth token header is included in the discovery requests.
+type resolver struct {
+	httpClient *http.Client
+	headers    map[string]string
+	endpoint   string
+}
+
+func (r *resolver) Do(req *http.Request) (*http.Response, error) {
+	for k, v := range r.headers {
+		req.Header.Add(k, v)
+	}
+
+	return r.httpClient.Do(req)
+}
+
+func (r *resolver) resolve(did string) (*document.ResolutionResult, error) {
+	respBytes, err := common.SendRequest(r.httpClient, nil, r.headers, http.MethodGet, r.endpoint+"/"+did)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID [%s]: %w", did, err)
+	}
+
+	rr := &document.ResolutionResult{}
+
+	if err := json.Unmarshal(respBytes, rr); err != nil {
+		return nil, fmt.Errorf("unmarshal resolution result of DID [%s]: %w", did, err)
+	}
+
+	if rr.Document == nil {
+		return nil, fmt.Errorf("resolution result of DID [%s] does not contain a DID document", did)
+	}
+
+	return rr, nil
+}
+
+func addFlags(cmd *cobra.Command) {
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(didFlagName, "", "", didFlagUsage)
+	cmd.Flags().StringP(resolutionEndpointFlagName, "", "", resolutionEndpointFlagUsage)
+	cmd.Flags().StringP(domainFlagName, "", "", domainFlagUsage)
+	cmd.Flags().StringP(verifyFlagName, "", "", verifyFlagUsage)
+}
@@ -0,0 +1,276 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package historydidcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-go/pkg/document"
+	"github.com/trustbloc/sidetree-go/pkg/jws"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/internal/testutil"
+	"github.com/trustbloc/orb/pkg/orbclient/resolutionverifier"
+)
+
+const (
+	flag = "--"
+
+	testDID = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+)
+
+func TestHistoryDIDCmd(t *testing.T) {
+	t.Run("missing DID", func(t *testing.T) {
+		cmd := GetHistoryDIDCmd()
+		cmd.SetArgs(nil)
+
+		err := cmd.Execute()
+		require.EqualError(t, err,
+			"Neither did (command line flag) nor ORB_CLI_DID (environment variable) have been set.")
+	})
+
+	t.Run("invalid DID", func(t *testing.T) {
+		cmd := GetHistoryDIDCmd()
+		cmd.SetArgs([]string{flag + didFlagName, "did:web:example.com"})
+
+		require.Error(t, cmd.Execute())
+	})
+
+	t.Run("missing endpoint and domain", func(t *testing.T) {
+		cmd := GetHistoryDIDCmd()
+		cmd.SetArgs([]string{flag + didFlagName, testDID})
+
+		err := cmd.Execute()
+		require.EqualError(t, err, "either resolution-endpoint or domain must be set")
+	})
+
+	t.Run("invalid verify flag", func(t *testing.T) {
+		cmd := GetHistoryDIDCmd()
+		cmd.SetArgs([]string{flag + didFlagName, testDID, flag + domainFlagName, "https://orb.domain1.com",
+			flag + verifyFlagName, "xxx"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), verifyFlagName)
+	})
+
+	t.Run("resolution result without operations", func(t *testing.T) {
+		serv := newResolutionServer(t)
+		defer serv.Close()
+
+		cmd := GetHistoryDIDCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{flag + didFlagName, testDID, flag + domainFlagName, serv.URL})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the server must be configured to include published and unpublished operations")
+	})
+
+	t.Run("DID not found", func(t *testing.T) {
+		serv := newResolutionServer(t)
+		defer serv.Close()
+
+		cmd := GetHistoryDIDCmd()
+		cmd.SetArgs([]string{flag + didFlagName, "did:orb:uAAA:EiBvYQTF7Ny2ExlKNYMKhsMrb-VNVNVY4wvGH1RjHEntIg",
+			flag + resolutionEndpointFlagName, serv.URL + "/sidetree/v1/identifiers/"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve DID")
+	})
+
+	t.Run("discovery error", func(t *testing.T) {
+		cmd := GetHistoryDIDCmd()
+		cmd.SetArgs([]string{flag + didFlagName, testDID, flag + domainFlagName, "https://localhost:0"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "discover resolution endpoint")
+	})
+}
+
+func TestPrintHistory(t *testing.T) {
+	rv1, c1 := newCommitment(t, "key1")
+	rv2, c2 := newCommitment(t, "key2")
+	rv3, c3 := newCommitment(t, "key3")
+	_, c4 := newCommitment(t, "key4")
+
+	steps := []*resolutionverifier.ReplayStep{
+		{
+			Operation: &operation.AnchoredOperation{Type: operation.TypeCreate, TransactionTime: 1669386337},
+			Published: true,
+			Result: &protocol.ResolutionModel{
+				Doc:                document.Document{"publicKey": []interface{}{"key1"}},
+				UpdateCommitment:   c1,
+				RecoveryCommitment: c2,
+			},
+		},
+		{
+			Operation: newOperation(t, operation.TypeUpdate, rv1, 1669386457),
+			Published: true,
+			Result: &protocol.ResolutionModel{
+				Doc:                document.Document{"publicKey": []interface{}{"key1", "key2"}},
+				UpdateCommitment:   c3,
+				RecoveryCommitment: c2,
+			},
+		},
+		{
+			Operation: newOperation(t, operation.TypeRecover, rv2, 1669388377),
+			Published: true,
+			Result: &protocol.ResolutionModel{
+				Doc:                document.Document{"publicKey": []interface{}{"key3"}},
+				UpdateCommitment:   c4,
+				RecoveryCommitment: c3,
+			},
+		},
+		{
+			Operation: newOperation(t, operation.TypeDeactivate, rv3, 0),
+			Result: &protocol.ResolutionModel{
+				Doc:         document.Document{},
+				Deactivated: true,
+			},
+		},
+	}
+
+	args := &historyArgs{did: testDID, verify: true}
+
+	t.Run("success", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		require.NoError(t, printHistory(out, &mockReplayer{steps: steps}, args, &document.ResolutionResult{}))
+
+		require.Contains(t, out.String(), "History of "+testDID+" (4 operation(s))")
+		require.Contains(t, out.String(), "#1 create - anchored 2022-11-25T14:25:37Z")
+		require.Contains(t, out.String(), "#2 update - anchored 2022-11-25T14:27:37Z")
+		require.Contains(t, out.String(), "#3 recover - anchored 2022-11-25T14:59:37Z")
+		require.Contains(t, out.String(), "#4 deactivate - unpublished")
+		require.Contains(t, out.String(), `"key1",`)
+		require.Contains(t, out.String(), "Document (deactivated):\n{}")
+		require.Equal(t, 3, bytes.Count(out.Bytes(), []byte("Commitment linkage: OK")))
+	})
+
+	t.Run("success - no verification", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		require.NoError(t, printHistory(out, &mockReplayer{steps: steps}, &historyArgs{did: testDID},
+			&document.ResolutionResult{}))
+
+		require.NotContains(t, out.String(), "Commitment linkage")
+	})
+
+	t.Run("broken commitment linkage", func(t *testing.T) {
+		brokenSteps := []*resolutionverifier.ReplayStep{
+			steps[0],
+			{
+				// The reveal value of the update doesn't match the update commitment of the create.
+				Operation: newOperation(t, operation.TypeUpdate, rv2, 1669386457),
+				Published: true,
+				Result:    steps[1].Result,
+			},
+			{
+				Operation: &operation.AnchoredOperation{
+					Type: operation.TypeUpdate, OperationRequest: []byte(`{"type":"update"}`),
+				},
+				Result: steps[1].Result,
+			},
+			{
+				Operation: &operation.AnchoredOperation{
+					Type: operation.TypeUpdate, OperationRequest: []byte(`{"revealValue":"invalid"}`),
+				},
+				Result: steps[1].Result,
+			},
+			{
+				Operation: &operation.AnchoredOperation{Type: operation.TypeUpdate, OperationRequest: []byte(`[]`)},
+				Result:    steps[1].Result,
+			},
+			{
+				Operation: &operation.AnchoredOperation{Type: operation.TypeCreate},
+				Result:    steps[3].Result,
+			},
+			{
+				Operation: newOperation(t, operation.TypeRecover, rv2, 0),
+				Result:    steps[3].Result,
+			},
+		}
+
+		out := &bytes.Buffer{}
+
+		err := printHistory(out, &mockReplayer{steps: brokenSteps}, args, &document.ResolutionResult{})
+		require.ErrorIs(t, err, errCommitmentLinkage)
+		require.Contains(t, err.Error(), "6 operation(s)")
+
+		require.Contains(t, out.String(), "doesn't match the previous commitment ["+c1+"]")
+		require.Contains(t, out.String(), "does not contain a reveal value")
+		require.Contains(t, out.String(), "get commitment from reveal value")
+		require.Contains(t, out.String(), "unmarshal update operation request")
+		require.Contains(t, out.String(), "unexpected create operation")
+		require.Contains(t, out.String(), "recover operation after deactivate")
+	})
+
+	t.Run("replay error", func(t *testing.T) {
+		errExpected := errors.New("injected replay error")
+
+		err := printHistory(&bytes.Buffer{}, &mockReplayer{err: errExpected}, args, &document.ResolutionResult{})
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
+type mockReplayer struct {
+	steps []*resolutionverifier.ReplayStep
+	err   error
+}
+
+func (m *mockReplayer) Replay(*document.ResolutionResult) ([]*resolutionverifier.ReplayStep, error) {
+	return m.steps, m.err
+}
+
+func newCommitment(t *testing.T, x string) (string, string) {
+	t.Helper()
+
+	jwk := &jws.JWK{Kty: "EC", Crv: "P-256", X: x, Y: "y"}
+
+	rv, err := commitment.GetRevealValue(jwk, multihash.SHA2_256)
+	require.NoError(t, err)
+
+	c, err := commitment.GetCommitment(jwk, multihash.SHA2_256)
+	require.NoError(t, err)
+
+	return rv, c
+}
+
+func newOperation(t *testing.T, opType operation.Type, revealValue string, txnTime uint64) *operation.AnchoredOperation {
+	t.Helper()
+
+	opBytes, err := json.Marshal(map[string]interface{}{"type": opType, "revealValue": revealValue})
+	require.NoError(t, err)
+
+	return &operation.AnchoredOperation{
+		Type:             opType,
+		OperationRequest: opBytes,
+		TransactionTime:  txnTime,
+	}
+}
+
+// newResolutionServer returns a stub Orb server which resolves testDID. The resolution result doesn't
+// contain any operations.
+func newResolutionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return testutil.NewResolutionServer(t, map[string]string{
+		testDID: fmt.Sprintf(`{"didDocument":{"id":"%s"},"didDocumentMetadata":{"method":{}}}`, testDID),
+	})
+}
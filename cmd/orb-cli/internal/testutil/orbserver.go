@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+)
+
+const (
+	// ResolutionPath is the path of the resolution endpoint of the stub Orb server.
+	ResolutionPath = "/sidetree/v1/identifiers"
+	// OperationsPath is the path of the operations endpoint of the stub Orb server.
+	OperationsPath = "/sidetree/v1/operations"
+)
+
+// NewResolutionServer returns a stub Orb server which supports endpoint discovery (did-orb and WebFinger)
+// and resolves the given DIDs. The given map contains the resolution result (JSON) for each DID.
+// A 404 is returned for any other request.
+func NewResolutionServer(t *testing.T, resolutionResults map[string]string) *httptest.Server {
+	t.Helper()
+
+	var serv *httptest.Server
+
+	serv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			respBytes []byte
+			err       error
+		)
+
+		switch r.URL.Path {
+		case "/.well-known/did-orb":
+			respBytes, err = json.Marshal(&restapi.WellKnownResponse{
+				ResolutionEndpoint: serv.URL + ResolutionPath,
+				OperationEndpoint:  serv.URL + OperationsPath,
+			})
+		case "/.well-known/webfinger":
+			resource := r.URL.Query().Get("resource")
+
+			respBytes, err = json.Marshal(&restapi.JRD{
+				Subject: resource,
+				Links:   []restapi.Link{{Rel: "self", Href: resource}},
+			})
+		default:
+			did, ok := strings.CutPrefix(r.URL.Path, ResolutionPath+"/")
+			if !ok || resolutionResults[did] == "" {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			respBytes = []byte(resolutionResults[did])
+		}
+
+		require.NoError(t, err)
+
+		_, err = w.Write(respBytes)
+		require.NoError(t, err)
+	}))
+
+	return serv
+}
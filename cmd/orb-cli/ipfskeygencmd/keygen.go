@@ -8,22 +8,18 @@ package ipfskeygencmd
 import (
 	"bytes"
 	"crypto/rand"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/base64"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"os"
-	"strconv"
 
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/spf13/cobra"
 
 	"github.com/trustbloc/orb/cmd/orb-cli/common"
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
-	"github.com/trustbloc/orb/internal/pkg/tlsutil"
 )
 
 const (
@@ -75,7 +71,7 @@ func keyGenCmd() *cobra.Command { //nolint: funlen,gocyclo,cyclop,gocognit
 		Long:         "generate IPFS key ",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			rootCAs, err := getRootCAs(cmd)
+			tlsConfig, err := common.NewTLSConfig(cmd)
 			if err != nil {
 				return err
 			}
@@ -83,10 +79,7 @@ func keyGenCmd() *cobra.Command { //nolint: funlen,gocyclo,cyclop,gocognit
 			httpClient := &http.Client{
 				Transport: &http.Transport{
 					ForceAttemptHTTP2: true,
-					TLSClientConfig: &tls.Config{
-						RootCAs:    rootCAs,
-						MinVersion: tls.VersionTLS12,
-					},
+					TLSClientConfig:   tlsConfig,
 				},
 			}
 
@@ -210,27 +203,6 @@ func createMultipartFormData(fieldName, fileName string) (*bytes.Buffer, *multip
 	return &b, w, nil
 }
 
-func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
-	tlsSystemCertPoolString := cmdutil.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
-		tlsSystemCertPoolEnvKey)
-
-	tlsSystemCertPool := false
-
-	if tlsSystemCertPoolString != "" {
-		var err error
-		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
-
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	tlsCACerts := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
-		tlsCACertsEnvKey)
-
-	return tlsutil.GetCertPool(tlsSystemCertPool, tlsCACerts)
-}
-
 func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
 	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
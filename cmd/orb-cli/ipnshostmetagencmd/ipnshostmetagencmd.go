@@ -7,12 +7,9 @@ package ipnshostmetagencmd
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
 	shell "github.com/ipfs/go-ipfs-api"
@@ -20,7 +17,6 @@ import (
 
 	"github.com/trustbloc/orb/cmd/orb-cli/common"
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
-	"github.com/trustbloc/orb/internal/pkg/tlsutil"
 	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
 )
 
@@ -77,7 +73,7 @@ func hostMetaGenCmd() *cobra.Command { //nolint: funlen,cyclop
 		Long:         "generate IPNS host-meta document",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			rootCAs, err := getRootCAs(cmd)
+			tlsConfig, err := common.NewTLSConfig(cmd)
 			if err != nil {
 				return err
 			}
@@ -85,10 +81,7 @@ func hostMetaGenCmd() *cobra.Command { //nolint: funlen,cyclop
 			httpClient := &http.Client{
 				Transport: &http.Transport{
 					ForceAttemptHTTP2: true,
-					TLSClientConfig: &tls.Config{
-						RootCAs:    rootCAs,
-						MinVersion: tls.VersionTLS12,
-					},
+					TLSClientConfig:   tlsConfig,
 				},
 			}
 
@@ -176,27 +169,6 @@ func hostMetaGenCmd() *cobra.Command { //nolint: funlen,cyclop
 	}
 }
 
-func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
-	tlsSystemCertPoolString := cmdutil.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
-		tlsSystemCertPoolEnvKey)
-
-	tlsSystemCertPool := false
-
-	if tlsSystemCertPoolString != "" {
-		var err error
-		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
-
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	tlsCACerts := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
-		tlsCACertsEnvKey)
-
-	return tlsutil.GetCertPool(tlsSystemCertPool, tlsCACerts)
-}
-
 func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
 	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
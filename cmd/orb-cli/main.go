@@ -7,14 +7,24 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
+	"errors"
+	"os"
+
 	"github.com/spf13/cobra"
 	"github.com/trustbloc/logutil-go/pkg/log"
 
 	"github.com/trustbloc/orb/cmd/orb-cli/acceptlistcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/allowedoriginscmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/anchorcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/cascmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/cmd/orb-cli/comparedidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/createdidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/deactivatedidcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/diffdidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/followcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/healthcheckcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/historydidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/ipfskeygencmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/ipnshostmetagencmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/ipnshostmetauploadcmd"
@@ -23,6 +33,8 @@ import (
 	"github.com/trustbloc/orb/cmd/orb-cli/policycmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/recoverdidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/resolvedidcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/submitdidcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/undeliverablecmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/updatedidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/vctcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/witnesscmd"
@@ -45,6 +57,11 @@ func main() {
 		},
 	}
 
+	common.AddTLSFlags(rootCmd.PersistentFlags())
+	common.AddTimeoutFlag(rootCmd.PersistentFlags())
+	common.AddUserAgentFlag(rootCmd.PersistentFlags())
+	common.AddDIDNamespaceFlag(rootCmd.PersistentFlags())
+
 	ipfsCmd := &cobra.Command{
 		Use: "ipfs",
 		Run: func(cmd *cobra.Command, args []string) {
@@ -61,6 +78,10 @@ func main() {
 	didCmd.AddCommand(recoverdidcmd.GetRecoverDIDCmd())
 	didCmd.AddCommand(deactivatedidcmd.GetDeactivateDIDCmd())
 	didCmd.AddCommand(resolvedidcmd.GetResolveDIDCmd())
+	didCmd.AddCommand(comparedidcmd.GetCompareDIDCmd())
+	didCmd.AddCommand(diffdidcmd.GetDiffDIDCmd())
+	didCmd.AddCommand(historydidcmd.GetHistoryDIDCmd())
+	didCmd.AddCommand(submitdidcmd.GetSubmitDIDCmd())
 
 	rootCmd.AddCommand(didCmd)
 	rootCmd.AddCommand(ipfsCmd)
@@ -73,10 +94,24 @@ func main() {
 	rootCmd.AddCommand(logcmd.GetCmd())
 
 	rootCmd.AddCommand(vctcmd.GetCmd())
+	rootCmd.AddCommand(anchorcmd.GetCmd())
 
 	rootCmd.AddCommand(allowedoriginscmd.GetCmd())
+	rootCmd.AddCommand(undeliverablecmd.GetCmd())
+	rootCmd.AddCommand(cascmd.GetCmd())
+
+	rootCmd.AddCommand(healthcheckcmd.GetCmd())
+
+	common.ApplyTimeout(rootCmd)
 
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *common.ExitError
+		if errors.As(err, &exitErr) {
+			logger.Error("Failed to run orb-cli", log.WithError(err))
+
+			os.Exit(exitErr.Code)
+		}
+
 		logger.Fatal("Failed to run orb-cli", log.WithError(err))
 	}
 }
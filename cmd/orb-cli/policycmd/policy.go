@@ -31,13 +31,15 @@ func GetCmd() *cobra.Command {
 		Short:        "Manages the witness policy.",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return errors.New("expecting subcommand update or get")
+			return errors.New("expecting subcommand update, get, simulate or suggest")
 		},
 	}
 
 	cmd.AddCommand(
 		newUpdateCmd(),
 		newGetCmd(),
+		newSimulateCmd(),
+		newSuggestCmd(),
 	)
 
 	return cmd
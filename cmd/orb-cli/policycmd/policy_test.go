@@ -16,6 +16,6 @@ func TestPolicyCmd(t *testing.T) {
 	t.Run("test missing subcommand", func(t *testing.T) {
 		err := GetCmd().Execute()
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "expecting subcommand update or get")
+		require.Contains(t, err.Error(), "expecting subcommand update, get, simulate or suggest")
 	})
 }
@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+)
+
+const (
+	maxAnchorsFlagName  = "max-anchors"
+	maxAnchorsEnvKey    = "ORB_CLI_MAX_ANCHORS"
+	maxAnchorsFlagUsage = "The maximum number of recent anchors to evaluate the policy against." +
+		" If not set then the server default is used." +
+		" Alternatively, this can be set with the following environment variable: " + maxAnchorsEnvKey
+
+	maxAnchorsQueryParam = "max-anchors"
+)
+
+func newSimulateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Simulates a proposed witness policy against recent anchors.",
+		Long: `Evaluates a proposed witness policy against the witness proofs of recent anchors and reports ` +
+			`the percentage of anchors that would have satisfied the proposed policy compared with the current ` +
+			`policy. For example: policy simulate --policy "MinPercent(100,batch) AND OutOf(1,system)" ` +
+			`--url https://orb.domain1.com/policy/simulate`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeSimulate(cmd)
+		},
+	}
+
+	addSimulateFlags(cmd)
+
+	return cmd
+}
+
+func executeSimulate(cmd *cobra.Command) error {
+	u, proposedPolicy, err := getUpdateArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	maxAnchors, err := cmdutil.GetInt(cmd, maxAnchorsFlagName, maxAnchorsEnvKey, 0)
+	if err != nil {
+		return err
+	}
+
+	if maxAnchors < 0 {
+		return fmt.Errorf("%s must not be negative", maxAnchorsFlagName)
+	}
+
+	if maxAnchors > 0 {
+		u, err = withQueryParam(u, maxAnchorsQueryParam, strconv.Itoa(maxAnchors))
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := common.SendHTTPRequest(cmd, []byte(proposedPolicy), http.MethodPost, u)
+	if err != nil {
+		return err
+	}
+
+	result := &policy.SimulationResult{}
+
+	err = json.Unmarshal(resp, result)
+	if err != nil {
+		return fmt.Errorf("invalid simulation result: %w", err)
+	}
+
+	printSimulationResult(cmd, result)
+
+	return nil
+}
+
+func printSimulationResult(cmd *cobra.Command, result *policy.SimulationResult) {
+	out := cmd.OutOrStdout()
+
+	common.Printf(out, "Anchors sampled: %d, evaluated: %d, excluded (insufficient proof data): %d\n",
+		result.Sampled, result.Evaluated, result.Excluded)
+	common.Printf(out, "Current policy [%s]: %d of %d anchors satisfied (%.2f%%)\n",
+		result.CurrentPolicy, result.SatisfiedCurrent, result.Evaluated, result.CurrentPercent)
+	common.Printf(out, "Proposed policy [%s]: %d of %d anchors satisfied (%.2f%%)\n",
+		result.ProposedPolicy, result.SatisfiedProposed, result.Evaluated, result.ProposedPercent)
+}
+
+func addSimulateFlags(cmd *cobra.Command) {
+	addUpdateFlags(cmd)
+
+	cmd.Flags().StringP(maxAnchorsFlagName, "", "", maxAnchorsFlagUsage)
+}
+
+func withQueryParam(u, name, value string) (string, error) {
+	parsedURL, err := url.Parse(u)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %s: %w", u, err)
+	}
+
+	query := parsedURL.Query()
+	query.Set(name, value)
+
+	parsedURL.RawQuery = query.Encode()
+
+	return parsedURL.String(), nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+)
+
+func TestSimulateCmd(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"simulate"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test missing policy arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"simulate"}
+		args = append(args, urlArg("localhost:8080")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither policy (command line flag) nor ORB_CLI_POLICY (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid max-anchors arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"simulate"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, policyArg("OutOf(1,system)")...)
+		args = append(args, maxAnchorsArg("xxx")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for max-anchors")
+	})
+
+	t.Run("test negative max-anchors arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"simulate"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, policyArg("OutOf(1,system)")...)
+		args = append(args, maxAnchorsArg("-1")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "max-anchors must not be negative")
+	})
+
+	t.Run("simulate -> success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "50", r.URL.Query().Get(maxAnchorsQueryParam))
+
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, "OutOf(1,system)", string(reqBytes))
+
+			respBytes, err := json.Marshal(&policy.SimulationResult{
+				CurrentPolicy:     "MinPercent(100,batch) AND MinPercent(100,system)",
+				ProposedPolicy:    "OutOf(1,system)",
+				Sampled:           50,
+				Evaluated:         40,
+				Excluded:          10,
+				SatisfiedCurrent:  20,
+				SatisfiedProposed: 40,
+				CurrentPercent:    50,
+				ProposedPercent:   100,
+			})
+			require.NoError(t, err)
+
+			_, err = w.Write(respBytes)
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		out := &bytes.Buffer{}
+
+		cmd := GetCmd()
+		cmd.SetOut(out)
+
+		args := []string{"simulate"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, policyArg("OutOf(1,system)")...)
+		args = append(args, maxAnchorsArg("50")...)
+		args = append(args, authTokenArg("ADMIN_TOKEN")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "excluded (insufficient proof data): 10")
+		require.Contains(t, out.String(), "20 of 40 anchors satisfied (50.00%)")
+		require.Contains(t, out.String(), "40 of 40 anchors satisfied (100.00%)")
+	})
+
+	t.Run("simulate -> invalid response", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte("not-json"))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"simulate"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, policyArg("OutOf(1,system)")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid simulation result")
+	})
+}
+
+func maxAnchorsArg(value string) []string {
+	return []string{flag + maxAnchorsFlagName, value}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+)
+
+const (
+	minReliabilityFlagName  = "min-reliability"
+	minReliabilityEnvKey    = "ORB_CLI_MIN_RELIABILITY"
+	minReliabilityFlagUsage = "The minimum reliability (percentage of requested anchors that were signed) of a witness" +
+		" for the witness to be considered reliable, e.g. 95. If not set then the server default is used." +
+		" Alternatively, this can be set with the following environment variable: " + minReliabilityEnvKey
+
+	minReliabilityQueryParam = "min-reliability"
+
+	advisoryNotice = "ADVISORY: The suggested policy has NOT been applied. Review the witness statistics below " +
+		"(and preview the policy with 'policy simulate') before adopting it with 'policy update'."
+)
+
+func newSuggestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Suggests a witness policy from the observed reliability of witnesses.",
+		Long: `Analyzes the witness proofs of recent anchors to compute the reliability of each witness (the ` +
+			`percentage of anchors for which the witness was requested that it actually signed) and suggests a ` +
+			`witness policy which requires proofs from the reliable witnesses. The per-witness statistics are ` +
+			`output along with the suggestion. The suggestion is advisory and is not applied. For example: ` +
+			`policy suggest --min-reliability 95 --url https://orb.domain1.com/policy/suggest`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeSuggest(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	cmd.Flags().StringP(maxAnchorsFlagName, "", "", maxAnchorsFlagUsage)
+	cmd.Flags().StringP(minReliabilityFlagName, "", "", minReliabilityFlagUsage)
+
+	return cmd
+}
+
+func executeSuggest(cmd *cobra.Command) error {
+	u, err := getSuggestURL(cmd)
+	if err != nil {
+		return err
+	}
+
+	resp, err := common.SendHTTPRequest(cmd, nil, http.MethodGet, u)
+	if err != nil {
+		return err
+	}
+
+	result := &policy.PolicySuggestion{}
+
+	err = json.Unmarshal(resp, result)
+	if err != nil {
+		return fmt.Errorf("invalid policy suggestion: %w", err)
+	}
+
+	printPolicySuggestion(cmd.OutOrStdout(), result)
+
+	return nil
+}
+
+func getSuggestURL(cmd *cobra.Command) (string, error) {
+	u, err := cmdutil.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = url.Parse(u)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %s: %w", u, err)
+	}
+
+	maxAnchors, err := cmdutil.GetInt(cmd, maxAnchorsFlagName, maxAnchorsEnvKey, 0)
+	if err != nil {
+		return "", err
+	}
+
+	if maxAnchors < 0 {
+		return "", fmt.Errorf("%s must not be negative", maxAnchorsFlagName)
+	}
+
+	if maxAnchors > 0 {
+		u, err = withQueryParam(u, maxAnchorsQueryParam, strconv.Itoa(maxAnchors))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	minReliabilityStr := cmdutil.GetUserSetOptionalVarFromString(cmd, minReliabilityFlagName, minReliabilityEnvKey)
+	if minReliabilityStr == "" {
+		return u, nil
+	}
+
+	minReliability, err := strconv.ParseFloat(minReliabilityStr, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid value for %s [%s]: %w", minReliabilityFlagName, minReliabilityStr, err)
+	}
+
+	if !(minReliability >= 0 && minReliability <= 100) {
+		return "", fmt.Errorf("%s must be between 0 and 100", minReliabilityFlagName)
+	}
+
+	return withQueryParam(u, minReliabilityQueryParam, minReliabilityStr)
+}
+
+func printPolicySuggestion(out io.Writer, result *policy.PolicySuggestion) {
+	common.Println(out, advisoryNotice)
+	common.Println(out, "")
+
+	common.Printf(out, "Anchors sampled: %d, evaluated: %d, excluded (insufficient proof data): %d\n",
+		result.Sampled, result.Evaluated, result.Excluded)
+	common.Printf(out, "Minimum reliability: %.2f%%\n", result.MinReliability)
+
+	if len(result.Witnesses) == 0 {
+		common.Println(out, "No witness data was found in the recent anchors so no policy is suggested.")
+
+		return
+	}
+
+	common.Println(out, "")
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	common.Println(tw, "TYPE\tURI\tSIGNED\tREQUESTED\tRELIABILITY\tRELIABLE")
+
+	for _, w := range result.Witnesses {
+		common.Printf(tw, "%s\t%s\t%d\t%d\t%.2f%%\t%t\n", w.Type, w.URI, w.Signed, w.Requested,
+			w.Reliability, w.Reliable)
+	}
+
+	if err := tw.Flush(); err != nil {
+		panic(err)
+	}
+
+	common.Println(out, "")
+	common.Printf(out, "Suggested policy (advisory): %s\n", result.SuggestedPolicy)
+	common.Printf(out, "The suggested policy would have been satisfied by %d of %d anchors (%.2f%%)\n",
+		result.SatisfiedSuggested, result.Evaluated, result.SuggestedPercent)
+}
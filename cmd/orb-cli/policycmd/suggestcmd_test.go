@@ -0,0 +1,172 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+func TestSuggestCmd(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"suggest"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test negative max-anchors arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"suggest"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, maxAnchorsArg("-1")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "max-anchors must not be negative")
+	})
+
+	t.Run("test invalid min-reliability arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"suggest"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, minReliabilityArg("xxx")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for min-reliability")
+	})
+
+	t.Run("test out of range min-reliability arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"suggest"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, minReliabilityArg("101")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "min-reliability must be between 0 and 100")
+	})
+
+	t.Run("suggest -> success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodGet, r.Method)
+			require.Equal(t, "50", r.URL.Query().Get(maxAnchorsQueryParam))
+			require.Equal(t, "90", r.URL.Query().Get(minReliabilityQueryParam))
+
+			respBytes, err := json.Marshal(&policy.PolicySuggestion{
+				Advisory:           true,
+				SuggestedPolicy:    "OutOf(1,batch) AND OutOf(0,system)",
+				MinReliability:     90,
+				Sampled:            50,
+				Evaluated:          40,
+				Excluded:           10,
+				SatisfiedSuggested: 40,
+				SuggestedPercent:   100,
+				Witnesses: []*policy.WitnessReliability{
+					{
+						URI: "https://batch.com/service", Type: proof.WitnessTypeBatch,
+						Requested: 40, Signed: 40, Reliability: 100, Reliable: true,
+					},
+					{
+						URI: "https://system.com/service", Type: proof.WitnessTypeSystem,
+						Requested: 40, Signed: 20, Reliability: 50,
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			_, err = w.Write(respBytes)
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		out := &bytes.Buffer{}
+
+		cmd := GetCmd()
+		cmd.SetOut(out)
+
+		args := []string{"suggest"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, maxAnchorsArg("50")...)
+		args = append(args, minReliabilityArg("90")...)
+		args = append(args, authTokenArg("ADMIN_TOKEN")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), advisoryNotice)
+		require.Contains(t, out.String(), "excluded (insufficient proof data): 10")
+		require.Contains(t, out.String(), "Minimum reliability: 90.00%")
+		require.Regexp(t, `batch\s+https://batch.com/service\s+40\s+40\s+100.00%\s+true`, out.String())
+		require.Regexp(t, `system\s+https://system.com/service\s+20\s+40\s+50.00%\s+false`, out.String())
+		require.Contains(t, out.String(), "Suggested policy (advisory): OutOf(1,batch) AND OutOf(0,system)")
+		require.Contains(t, out.String(), "satisfied by 40 of 40 anchors (100.00%)")
+	})
+
+	t.Run("suggest -> no witness data", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(`{"advisory":true,"sampled":0}`))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		out := &bytes.Buffer{}
+
+		cmd := GetCmd()
+		cmd.SetOut(out)
+
+		args := []string{"suggest"}
+		args = append(args, urlArg(serv.URL)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "no policy is suggested")
+		require.NotContains(t, out.String(), "Suggested policy")
+	})
+
+	t.Run("suggest -> invalid response", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte("not-json"))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"suggest"}
+		args = append(args, urlArg(serv.URL)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid policy suggestion")
+	})
+}
+
+func minReliabilityArg(value string) []string {
+	return []string{flag + minReliabilityFlagName, value}
+}
@@ -7,11 +7,8 @@ package recoverdidcmd
 
 import (
 	"crypto"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
 	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/api"
@@ -25,7 +22,7 @@ import (
 
 	"github.com/trustbloc/orb/cmd/orb-cli/common"
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
-	"github.com/trustbloc/orb/internal/pkg/tlsutil"
+	"github.com/trustbloc/orb/pkg/document/util"
 )
 
 const (
@@ -164,7 +161,7 @@ func recoverDIDCmd() *cobra.Command { //nolint: funlen,gocognit,cyclop
 		Long:         "Recover orb DID",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			rootCAs, err := getRootCAs(cmd)
+			tlsConfig, err := common.NewTLSConfig(cmd)
 			if err != nil {
 				return err
 			}
@@ -175,6 +172,11 @@ func recoverDIDCmd() *cobra.Command { //nolint: funlen,gocognit,cyclop
 				return err
 			}
 
+			err = util.ValidateOrbDID(didURI, common.GetDIDNamespace(cmd))
+			if err != nil {
+				return err
+			}
+
 			sidetreeWriteToken := cmdutil.GetUserSetOptionalVarFromString(cmd, sidetreeWriteTokenFlagName,
 				sidetreeWriteTokenEnvKey)
 
@@ -185,7 +187,7 @@ func recoverDIDCmd() *cobra.Command { //nolint: funlen,gocognit,cyclop
 
 			httpClient := http.Client{Transport: &http.Transport{
 				ForceAttemptHTTP2: true,
-				TLSClientConfig:   &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12},
+				TLSClientConfig:   tlsConfig,
 			}}
 
 			kmsStoreURL := cmdutil.GetUserSetOptionalVarFromString(cmd, kmsStoreEndpointFlagName,
@@ -252,6 +254,13 @@ func recoverDIDCmd() *cobra.Command { //nolint: funlen,gocognit,cyclop
 				}
 			}
 
+			vdrHTTPClient := &httpClient
+
+			capture := common.NewRequestCapture(cmd, httpClient.Transport)
+			if capture != nil {
+				vdrHTTPClient = &http.Client{Transport: capture}
+			}
+
 			vdr, err := orb.New(&keyRetriever{
 				nextUpdateKey:      nextUpdateKey,
 				nextRecoveryKey:    nextRecoveryKey,
@@ -261,12 +270,16 @@ func recoverDIDCmd() *cobra.Command { //nolint: funlen,gocognit,cyclop
 				signingKeyPK:       signingKeyPK,
 			}, orb.WithAuthToken(sidetreeWriteToken),
 				orb.WithDomain(cmdutil.GetUserSetOptionalVarFromString(cmd, domainFlagName, domainFileEnvKey)),
-				orb.WithHTTPClient(&httpClient))
+				orb.WithHTTPClient(vdrHTTPClient))
 			if err != nil {
 				return err
 			}
 
 			err = vdr.Update(didDoc, opts...)
+			if capture != nil {
+				return capture.Write(cmd.OutOrStdout(), err)
+			}
+
 			if err != nil {
 				return fmt.Errorf("failed to recover did: %w", err)
 			}
@@ -363,27 +376,6 @@ func getPublicKeys(cmd *cobra.Command) (*ariesdid.Doc, error) {
 	return &ariesdid.Doc{}, nil
 }
 
-func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
-	tlsSystemCertPoolString := cmdutil.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
-		tlsSystemCertPoolEnvKey)
-
-	tlsSystemCertPool := false
-
-	if tlsSystemCertPoolString != "" {
-		var err error
-		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
-
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	tlsCACerts := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
-		tlsCACertsEnvKey)
-
-	return tlsutil.GetCertPool(tlsSystemCertPool, tlsCACerts)
-}
-
 func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(didURIFlagName, "", "", didURIFlagUsage)
 	startCmd.Flags().StringP(domainFlagName, "", "", domainFileFlagUsage)
@@ -408,6 +400,8 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().String(signingKeyIDFlagName, "", signingKeyIDFlagUsage)
 	startCmd.Flags().String(nextUpdateKeyIDFlagName, "", nextUpdateKeyIDFlagUsage)
 	startCmd.Flags().String(nextRecoveryKeyIDFlagName, "", nextRecoveryKeyIDFlagUsage)
+
+	common.AddOutputRequestFlag(startCmd)
 }
 
 type keyRetriever struct {
@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package recoverdidcmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +15,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/model"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
 )
 
 const (
@@ -85,6 +91,22 @@ ho+LGIVUXDNaduiNfpLmk5MXS5Q7WQAMgaJBRyRldIvbrNWqph4DH2gdKQ==
     }]
   }
 ]`
+
+	didResolution = `{
+  "@context": "https://w3id.org/did-resolution/v1",
+  "didDocument": {
+    "@context": ["https://www.w3.org/ns/did/v1"],
+    "id": "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+  },
+  "didDocumentMetadata": {
+    "method": {
+      "published": true,
+      "anchorOrigin": "https://orb.domain1.com",
+      "recoveryCommitment": "EiBfOZdMtU6OBw8Pk879QtZ-2J-9FbbjSZyoaA_bqD4zhA",
+      "updateCommitment": "EiDOrcmPtfMHuwIWN6YoihdeIPxOKDHy3D6sdMXu_7CN0w"
+    }
+  }
+}`
 )
 
 func TestMissingArg(t *testing.T) {
@@ -102,6 +124,21 @@ func TestMissingArg(t *testing.T) {
 		require.Contains(t, err.Error(), "Neither did-uri (command line flag) nor "+
 			"ORB_CLI_DID_URI (environment variable) have been set.")
 	})
+
+	t.Run("test invalid did uri", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetRecoverDIDCmd()
+
+		var args []string
+		args = append(args, domainArg()...)
+		args = append(args, flag+didURIFlagName, "did:ex:123")
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting namespace [did:orb]")
+	})
 }
 
 func TestRecoverDID(t *testing.T) {
@@ -110,6 +147,18 @@ func TestRecoverDID(t *testing.T) {
 	}))
 	defer serv.Close()
 
+	var posted bool
+
+	resolutionServ := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posted = true
+		}
+
+		w.Header().Set("Content-Type", "application/did+ld+json")
+		fmt.Fprint(w, didResolution)
+	}))
+	defer resolutionServ.Close()
+
 	file, err := os.CreateTemp("", "*.json")
 	require.NoError(t, err)
 
@@ -200,6 +249,38 @@ func TestRecoverDID(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "ExportPubKeyBytes key failed")
 	})
+
+	t.Run("output request", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetRecoverDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, sidetreeURLArg(resolutionServ.URL)...)
+		args = append(args, flag+sidetreeURLResFlagName, resolutionServ.URL)
+		args = append(args, signingKeyPasswordArg()...)
+		args = append(args, nextRecoveryKeyFileFlagNameArg(file.Name())...)
+		args = append(args, nextUpdateKeyFileFlagNameArg(file.Name())...)
+		args = append(args, signingKeyFileFlagNameArg(privateKeyfile.Name())...)
+		args = append(args, servicesFileArg(servicesFile.Name())...)
+		args = append(args, publicKeyFileArg(publicKeyFile.Name())...)
+		args = append(args, didAnchorOrigin()...)
+		args = append(args, flag+common.OutputRequestFlagName, common.OutputRequestStdout)
+
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		require.False(t, posted)
+
+		req := &model.RecoverRequest{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), req))
+		require.Equal(t, operation.TypeRecover, req.Operation)
+		require.Equal(t, "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A", req.DidSuffix)
+		require.NotEmpty(t, req.SignedData)
+		require.NotNil(t, req.Delta)
+	})
 }
 
 func TestKeyRetriever(t *testing.T) {
@@ -374,7 +455,7 @@ func publicKeyFileArg(value string) []string {
 }
 
 func didURIArg() []string {
-	return []string{flag + didURIFlagName, "did:ex:123"}
+	return []string{flag + didURIFlagName, "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"}
 }
 
 func servicesFileArg(value string) []string {
@@ -0,0 +1,114 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	jsonld "github.com/piprate/json-gold/ld"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	anchorutil "github.com/trustbloc/orb/pkg/anchor/util"
+	"github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+// anchorSummary is a summary of the anchor credential that backs a DID's canonical ID.
+type anchorSummary struct {
+	CanonicalID    string   `json:"canonicalId"`
+	AnchorHash     string   `json:"anchorHash"`
+	Issuer         string   `json:"issuer"`
+	IssuanceDate   string   `json:"issuanceDate,omitempty"`
+	ProofCount     int      `json:"proofCount"`
+	WitnessDomains []string `json:"witnessDomains"`
+}
+
+type anchorSummaryResolver struct {
+	httpClient *http.Client
+	headers    map[string]string
+	casURL     string
+	docLoader  jsonld.DocumentLoader
+}
+
+// resolve retrieves the anchor linkset referenced by the given canonical ID from CAS, verifies that the hash
+// of the anchor linkset matches the CID in the canonical ID, parses the anchor credential and returns a
+// summary of the credential and its proofs.
+func (r *anchorSummaryResolver) resolve(canonicalID string) (*anchorSummary, error) {
+	_, anchorHash, _, err := util.ParseCanonicalDID(canonicalID)
+	if err != nil {
+		return nil, err
+	}
+
+	anchorLinksetBytes, err := common.SendRequest(r.httpClient, nil, r.headers, http.MethodGet,
+		fmt.Sprintf("%s/%s", strings.TrimSuffix(r.casURL, "/"), anchorHash))
+	if err != nil {
+		return nil, fmt.Errorf("get anchor linkset [%s] from %s: %w", anchorHash, r.casURL, err)
+	}
+
+	err = hashlink.VerifyResourceHash(hashlink.GetHashLinkFromResourceHash(anchorHash), anchorLinksetBytes)
+	if err != nil {
+		return nil, fmt.Errorf("verify anchor linkset [%s] from %s: %w", anchorHash, r.casURL, err)
+	}
+
+	anchorLinkset := &linkset.Linkset{}
+
+	err = json.Unmarshal(anchorLinksetBytes, anchorLinkset)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal anchor linkset [%s]: %w", anchorHash, err)
+	}
+
+	vc, err := anchorutil.VerifiableCredentialFromAnchorLink(anchorLinkset.Link(),
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(r.docLoader),
+		verifiable.WithStrictValidation(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get verifiable credential from anchor link [%s]: %w", anchorHash, err)
+	}
+
+	summary := &anchorSummary{
+		CanonicalID:    canonicalID,
+		AnchorHash:     anchorHash,
+		Issuer:         vc.Issuer.ID,
+		ProofCount:     len(vc.Proofs),
+		WitnessDomains: getWitnessDomains(vc.Proofs),
+	}
+
+	if vc.Issued != nil {
+		summary.IssuanceDate = vc.Issued.Time.UTC().Format(time.RFC3339)
+	}
+
+	return summary, nil
+}
+
+// getWitnessDomains returns the distinct domains of the given proofs in the order in which they appear.
+func getWitnessDomains(proofs []verifiable.Proof) []string {
+	domains := []string{}
+	seen := make(map[string]struct{})
+
+	for _, proof := range proofs {
+		domain, ok := proof["domain"].(string)
+		if !ok || domain == "" {
+			continue
+		}
+
+		if _, exists := seen[domain]; exists {
+			continue
+		}
+
+		seen[domain] = struct{}{}
+
+		domains = append(domains, domain)
+	}
+
+	return domains
+}
@@ -0,0 +1,299 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+const (
+	anchorHash  = "uEiCO2KjGi3boKSiSkcLpCeK9em7wL1dVeeSAOIE0OdV_vA"
+	canonicalID = "did:orb:" + anchorHash + ":EiAbvz2BZUmsqc2ZO5Fzhd04kCeuy31fzbZxH4Em_0RZ9Q"
+)
+
+func TestAnchorSummaryResolver(t *testing.T) {
+	docLoader, err := common.NewDocumentLoader()
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		var authHeader string
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/cas/"+anchorHash {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			authHeader = r.Header.Get("Authorization")
+
+			_, err := w.Write([]byte(anchorLinkset))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		r := &anchorSummaryResolver{
+			httpClient: serv.Client(),
+			headers:    map[string]string{"Authorization": "Bearer token"},
+			casURL:     serv.URL + "/cas/",
+			docLoader:  docLoader,
+		}
+
+		summary, err := r.resolve(canonicalID)
+		require.NoError(t, err)
+		require.Equal(t, "Bearer token", authHeader)
+		require.Equal(t, canonicalID, summary.CanonicalID)
+		require.Equal(t, anchorHash, summary.AnchorHash)
+		require.Equal(t, "https://orb2.domain1.com", summary.Issuer)
+		require.Equal(t, "2022-08-25T20:09:09Z", summary.IssuanceDate)
+		require.Equal(t, 2, summary.ProofCount)
+		require.Equal(t, []string{"http://orb.vct:8077/maple2020", "https://orb.domain2.com"}, summary.WitnessDomains)
+	})
+
+	t.Run("invalid canonical ID", func(t *testing.T) {
+		r := &anchorSummaryResolver{httpClient: http.DefaultClient, docLoader: docLoader}
+
+		_, err := r.resolve("did:orb:uAAA:EiAbvz2BZUmsqc2ZO5Fzhd04kCeuy31fzbZxH4Em_0RZ9Q")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "interim DID does not contain a CID")
+	})
+
+	t.Run("anchor linkset not found", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer serv.Close()
+
+		r := &anchorSummaryResolver{httpClient: serv.Client(), casURL: serv.URL, docLoader: docLoader}
+
+		_, err := r.resolve(canonicalID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get anchor linkset ["+anchorHash+"]")
+	})
+
+	t.Run("anchor linkset doesn't match the canonical ID", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Serve a tampered anchor linkset.
+			_, err := w.Write([]byte(strings.Replace(anchorLinkset, "orb2.domain1.com", "orb3.domain1.com", 1)))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		r := &anchorSummaryResolver{httpClient: serv.Client(), casURL: serv.URL, docLoader: docLoader}
+
+		_, err := r.resolve(canonicalID)
+		require.Error(t, err)
+		require.ErrorIs(t, err, hashlink.ErrHashMismatch)
+		require.Contains(t, err.Error(), "verify anchor linkset ["+anchorHash+"]")
+	})
+
+	t.Run("invalid anchor linkset", func(t *testing.T) {
+		const content = "{"
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(content))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		r := &anchorSummaryResolver{httpClient: serv.Client(), casURL: serv.URL, docLoader: docLoader}
+
+		_, err := r.resolve(newCanonicalID(t, content))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal anchor linkset")
+	})
+
+	t.Run("anchor linkset without a credential", func(t *testing.T) {
+		const content = `{"linkset":[{"anchor":"hl:uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg"}]}`
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(content))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		r := &anchorSummaryResolver{httpClient: serv.Client(), casURL: serv.URL, docLoader: docLoader}
+
+		_, err := r.resolve(newCanonicalID(t, content))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get verifiable credential from anchor link")
+	})
+}
+
+func TestPrintAnchorSummary(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(anchorLinkset))
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	t.Run("success", func(t *testing.T) {
+		cmd := GetResolveDIDCmd()
+
+		out := bytes.NewBuffer(nil)
+		cmd.SetOut(out)
+
+		err := printAnchorSummary(cmd, &anchorSummaryResolver{httpClient: serv.Client(), casURL: serv.URL},
+			&docdid.DocResolution{DocumentMetadata: &docdid.DocumentMetadata{CanonicalID: canonicalID}})
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Anchor summary:")
+		require.Contains(t, out.String(), `"proofCount": 2`)
+		require.Contains(t, out.String(), `"issuer": "https://orb2.domain1.com"`)
+	})
+
+	t.Run("not anchored", func(t *testing.T) {
+		cmd := GetResolveDIDCmd()
+
+		out := bytes.NewBuffer(nil)
+		cmd.SetOut(out)
+
+		err := printAnchorSummary(cmd, &anchorSummaryResolver{httpClient: serv.Client(), casURL: serv.URL},
+			&docdid.DocResolution{DocumentMetadata: &docdid.DocumentMetadata{}})
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "the DID has not been anchored yet")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cmd := GetResolveDIDCmd()
+
+		err := printAnchorSummary(cmd, &anchorSummaryResolver{httpClient: serv.Client(), casURL: serv.URL},
+			&docdid.DocResolution{DocumentMetadata: &docdid.DocumentMetadata{CanonicalID: "did:orb:xxx"}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get anchor summary")
+	})
+}
+
+func TestGetAnchorSummaryArgs(t *testing.T) {
+	t.Run("not enabled", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTestCmd(t)
+
+		enabled, casURL, err := getAnchorSummaryArgs(cmd, "https://orb.domain1.com")
+		require.NoError(t, err)
+		require.False(t, enabled)
+		require.Empty(t, casURL)
+	})
+
+	t.Run("CAS URL from domain", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTestCmd(t, flag+anchorSummaryFlagName, "true")
+
+		enabled, casURL, err := getAnchorSummaryArgs(cmd, "https://orb.domain1.com/")
+		require.NoError(t, err)
+		require.True(t, enabled)
+		require.Equal(t, "https://orb.domain1.com/cas", casURL)
+	})
+
+	t.Run("CAS URL from flag", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTestCmd(t, flag+anchorSummaryFlagName, "true", flag+casURLFlagName, "https://orb.domain2.com/cas")
+
+		enabled, casURL, err := getAnchorSummaryArgs(cmd, "https://orb.domain1.com")
+		require.NoError(t, err)
+		require.True(t, enabled)
+		require.Equal(t, "https://orb.domain2.com/cas", casURL)
+	})
+
+	t.Run("no CAS URL or domain", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTestCmd(t, flag+anchorSummaryFlagName, "true")
+
+		_, _, err := getAnchorSummaryArgs(cmd, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "either cas-url or domain must be set")
+	})
+
+	t.Run("invalid CAS URL", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTestCmd(t, flag+anchorSummaryFlagName, "true", flag+casURLFlagName, ":invalid")
+
+		_, _, err := getAnchorSummaryArgs(cmd, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid CAS URL")
+	})
+
+	t.Run("invalid anchor summary value", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTestCmd(t, flag+anchorSummaryFlagName, "xxx")
+
+		_, _, err := getAnchorSummaryArgs(cmd, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), anchorSummaryFlagName)
+	})
+}
+
+// newCanonicalID returns a canonical ID whose CID is the hash of the given anchor linkset.
+func newCanonicalID(t *testing.T, anchorLinkset string) string {
+	t.Helper()
+
+	hash, err := hashlink.New().CreateResourceHash([]byte(anchorLinkset))
+	require.NoError(t, err)
+
+	return "did:orb:" + hash + ":EiAbvz2BZUmsqc2ZO5Fzhd04kCeuy31fzbZxH4Em_0RZ9Q"
+}
+
+func newTestCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := GetResolveDIDCmd()
+	require.NoError(t, cmd.ParseFlags(args))
+
+	return cmd
+}
+
+const anchorLinkset = `{
+  "linkset": [
+    {
+      "anchor": "hl:uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg",
+      "author": [
+        {
+          "href": "https://orb.domain1.com/services/orb"
+        }
+      ],
+      "original": [
+        {
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiCKkDb0aQhWNudvelroKLnBqEMORXuOeQqI_mYeVhGkpQ%22%2C%22author%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Forb.domain1.com%2Fservices%2Forb%22%7D%5D%2C%22item%22%3A%5B%7B%22href%22%3A%22did%3Aorb%3AuAAA%3AEiAbvz2BZUmsqc2ZO5Fzhd04kCeuy31fzbZxH4Em_0RZ9Q%22%7D%5D%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%7D%5D%7D",
+          "type": "application/linkset+json"
+        }
+      ],
+      "profile": [
+        {
+          "href": "https://w3id.org/orb#v0"
+        }
+      ],
+      "related": [
+        {
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg%22%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%2C%22via%22%3A%5B%7B%22href%22%3A%22hl%3AuEiCKkDb0aQhWNudvelroKLnBqEMORXuOeQqI_mYeVhGkpQ%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQ0trRGIwYVFoV051ZHZlbHJvS0xuQnFFTU9SWHVPZVFxSV9tWWVWaEdrcFF4QmlwZnM6Ly9iYWZrcmVpZWtzYTNwaTJpaWt5M29vMzMybGx1Y3Jvb2J2YmJxNHJsM3J6NHF2Y2g2bXlwZm1lbmV1dQ%22%7D%5D%7D%5D%7D",
+          "type": "application/linkset+json"
+        }
+      ],
+      "replies": [
+        {
+          "href": "data:application/json,%7B%22%40context%22%3A%5B%22https%3A%2F%2Fwww.w3.org%2F2018%2Fcredentials%2Fv1%22%2C%22https%3A%2F%2Fw3id.org%2Factivityanchors%2Fv1%22%2C%22https%3A%2F%2Fw3id.org%2Fsecurity%2Fsuites%2Fjws-2020%2Fv1%22%2C%22https%3A%2F%2Fw3id.org%2Fsecurity%2Fsuites%2Fed25519-2020%2Fv1%22%5D%2C%22credentialSubject%22%3A%7B%22anchor%22%3A%22hl%3AuEiCKkDb0aQhWNudvelroKLnBqEMORXuOeQqI_mYeVhGkpQ%22%2C%22href%22%3A%22hl%3AuEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg%22%2C%22profile%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%2C%22rel%22%3A%22linkset%22%2C%22type%22%3A%5B%22AnchorLink%22%5D%7D%2C%22id%22%3A%22https%3A%2F%2Forb2.domain1.com%2Fvc%2F19148c22-9088-4652-bcfa-fcea1279f072%22%2C%22issuanceDate%22%3A%222022-08-25T20%3A09%3A09.480315917Z%22%2C%22issuer%22%3A%22https%3A%2F%2Forb2.domain1.com%22%2C%22proof%22%3A%5B%7B%22created%22%3A%222022-08-25T20%3A09%3A09.52Z%22%2C%22domain%22%3A%22http%3A%2F%2Forb.vct%3A8077%2Fmaple2020%22%2C%22proofPurpose%22%3A%22assertionMethod%22%2C%22proofValue%22%3A%22zjJsKS1B4PrVfrQrsE6JRdWmDpjZosDvT4qk3b7wSpnVfaEk5w6iCu7PwXBQd7QzG9VEYkUTD9sUdCF7VfSEupV7%22%2C%22type%22%3A%22Ed25519Signature2020%22%2C%22verificationMethod%22%3A%22did%3Aweb%3Aorb.domain1.com%2375MDi94rVaJ69DRwHLwaCxBVg-wdEuBKwzgNgyoMbcc%22%7D%2C%7B%22created%22%3A%222022-08-25T20%3A09%3A09.715076709Z%22%2C%22domain%22%3A%22https%3A%2F%2Forb.domain2.com%22%2C%22proofPurpose%22%3A%22assertionMethod%22%2C%22proofValue%22%3A%22z5pJumaR6o4v7cZudXsBQx8NYh4SEJSFzBGNj92cAw7jEUqoTAypHsECGAiRU6TXqSeU2D5azChjXpmkcCNGsBwam%22%2C%22type%22%3A%22Ed25519Signature2020%22%2C%22verificationMethod%22%3A%22did%3Aweb%3Aorb.domain2.com%23LfX08Wr74EkPSoG7CoB3S4OuSrX3LM-_Yd0BvfSonLQ%22%7D%5D%2C%22type%22%3A%5B%22VerifiableCredential%22%2C%22AnchorCredential%22%5D%7D",
+          "type": "application/ld+json"
+        }
+      ]
+    }
+  ]
+}`
@@ -0,0 +1,221 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+
+	verificationPassed       = "passed"
+	verificationNotPerformed = "not-performed"
+	verificationFailed       = "failed"
+)
+
+var errVerificationFailed = errors.New("resolution result failed verification")
+
+// compactResult is a brief summary of a DID resolution result.
+type compactResult struct {
+	ID                string   `json:"id"`
+	Deactivated       bool     `json:"deactivated"`
+	Verification      string   `json:"verification"`
+	VerificationError string   `json:"verificationError,omitempty"`
+	KeyIDs            []string `json:"keyIds"`
+	ServiceIDs        []string `json:"serviceIds"`
+}
+
+type resolveFunc func(vdr didReader) (*docdid.DocResolution, *docdid.DocResolution, error)
+
+// resolveCompact resolves the DID using the given VDR and returns a compact summary of the resolution result
+// along with the resolution result of the anchored did:orb DID. If resolution fails and an unverified VDR is
+// provided then the DID is resolved again without verification. If the DID is then resolved successfully, the
+// summary is returned with the verification error so that the failure may be reported.
+func resolveCompact(resolve resolveFunc, vdr, unverifiedVDR didReader,
+	verifyType orb.VerifyResolutionResultType,
+) (*compactResult, *docdid.DocResolution, error) {
+	didDoc, anchoredDoc, err := resolve(vdr)
+	if err == nil {
+		return newCompactResult(didDoc, getVerificationStatus(anchoredDoc, verifyType)), anchoredDoc, nil
+	}
+
+	if unverifiedVDR == nil {
+		return nil, nil, err
+	}
+
+	didDoc, anchoredDoc, errUnverified := resolve(unverifiedVDR)
+	if errUnverified != nil {
+		// The DID can't be resolved at all so the original error is returned.
+		return nil, nil, err
+	}
+
+	result := newCompactResult(didDoc, verificationFailed)
+	result.VerificationError = err.Error()
+
+	return result, anchoredDoc, nil
+}
+
+func newCompactResult(docResolution *docdid.DocResolution, verification string) *compactResult {
+	result := &compactResult{
+		Verification: verification,
+		KeyIDs:       []string{},
+		ServiceIDs:   []string{},
+	}
+
+	if docResolution.DocumentMetadata != nil {
+		result.Deactivated = docResolution.DocumentMetadata.Deactivated
+	}
+
+	doc := docResolution.DIDDocument
+	if doc == nil {
+		return result
+	}
+
+	result.ID = doc.ID
+	result.KeyIDs = getKeyIDs(doc)
+
+	for i := range doc.Service {
+		result.ServiceIDs = append(result.ServiceIDs, doc.Service[i].ID)
+	}
+
+	return result
+}
+
+// getKeyIDs returns the unique IDs of the verification methods in the document, including those
+// that are embedded in verification relationships.
+func getKeyIDs(doc *docdid.Doc) []string {
+	keyIDs := []string{}
+	ids := make(map[string]struct{})
+
+	add := func(id string) {
+		if _, ok := ids[id]; ok || id == "" {
+			return
+		}
+
+		ids[id] = struct{}{}
+		keyIDs = append(keyIDs, id)
+	}
+
+	for i := range doc.VerificationMethod {
+		add(doc.VerificationMethod[i].ID)
+	}
+
+	for _, verifications := range [][]docdid.Verification{
+		doc.Authentication, doc.AssertionMethod, doc.CapabilityDelegation,
+		doc.CapabilityInvocation, doc.KeyAgreement,
+	} {
+		for i := range verifications {
+			add(verifications[i].VerificationMethod.ID)
+		}
+	}
+
+	return keyIDs
+}
+
+// getVerificationStatus returns 'passed' if the resolution result was verified by the VDR. The VDR verifies
+// the result only if it contains operations of the type(s) selected by the verification type.
+func getVerificationStatus(docResolution *docdid.DocResolution,
+	verifyType orb.VerifyResolutionResultType,
+) string {
+	if docResolution == nil || docResolution.DocumentMetadata == nil || docResolution.DocumentMetadata.Method == nil {
+		return verificationNotPerformed
+	}
+
+	method := docResolution.DocumentMetadata.Method
+
+	switch {
+	case len(method.UnpublishedOperations) > 0 && (verifyType == orb.Unpublished || verifyType == orb.All):
+		return verificationPassed
+	case len(method.PublishedOperations) > 0 && verifyType == orb.All:
+		return verificationPassed
+	default:
+		return verificationNotPerformed
+	}
+}
+
+func printCompactResult(out io.Writer, result *compactResult, output string) error {
+	if output == outputJSON {
+		resultBytes, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("marshal compact result: %w", err)
+		}
+
+		common.Println(out, string(resultBytes))
+
+		return nil
+	}
+
+	status := "active"
+	if result.Deactivated {
+		status = "DEACTIVATED"
+	}
+
+	verification := result.Verification
+	if result.Verification == verificationFailed {
+		verification = "FAILED: " + result.VerificationError
+	}
+
+	common.Printf(out, "ID:           %s\n", result.ID)
+	common.Printf(out, "Status:       %s\n", status)
+	common.Printf(out, "Verification: %s\n", verification)
+	common.Printf(out, "Keys:         %s\n", joinOrNone(result.KeyIDs))
+	common.Printf(out, "Services:     %s\n", joinOrNone(result.ServiceIDs))
+
+	return nil
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func getCompactArgs(cmd *cobra.Command, raw bool) (bool, string, error) {
+	compact, err := cmdutil.GetBool(cmd, compactFlagName, compactEnvKey, false)
+	if err != nil {
+		return false, "", fmt.Errorf("%s: %w", compactFlagName, err)
+	}
+
+	if compact && raw {
+		return false, "", fmt.Errorf("%s and %s may not be used together", compactFlagName, rawFlagName)
+	}
+
+	output := strings.ToLower(cmdutil.GetUserSetOptionalVarFromString(cmd, outputFlagName, outputEnvKey))
+
+	switch output {
+	case "":
+		if compact {
+			return true, outputText, nil
+		}
+
+		return false, outputJSON, nil
+	case outputJSON:
+		return compact, output, nil
+	case outputText:
+		if !compact {
+			return false, "", fmt.Errorf("%s '%s' is only supported with %s", outputFlagName, outputText, compactFlagName)
+		}
+
+		return true, output, nil
+	default:
+		return false, "", fmt.Errorf("unsupported %s '%s'. Values [%s, %s]", outputFlagName, output, outputText, outputJSON)
+	}
+}
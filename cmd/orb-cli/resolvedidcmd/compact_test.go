@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testDID      = "did:orb:uAAA:EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ"
+	errVerifyMsg = "failed to resolve did: check signature: invalid signature"
+)
+
+func TestNewCompactResult(t *testing.T) {
+	docResolution, err := docdid.ParseDocumentResolution([]byte(unpublishedResolutionResult))
+	require.NoError(t, err)
+
+	t.Run("active", func(t *testing.T) {
+		result := newCompactResult(docResolution, verificationPassed)
+		require.Equal(t, testDID, result.ID)
+		require.False(t, result.Deactivated)
+		require.Equal(t, verificationPassed, result.Verification)
+		require.Equal(t, []string{testDID + "#createKey", testDID + "#auth"}, result.KeyIDs)
+		require.Equal(t, []string{testDID + "#didcomm"}, result.ServiceIDs)
+	})
+
+	t.Run("deactivated", func(t *testing.T) {
+		result := newCompactResult(&docdid.DocResolution{
+			DIDDocument:      &docdid.Doc{ID: testDID},
+			DocumentMetadata: &docdid.DocumentMetadata{Deactivated: true},
+		}, verificationNotPerformed)
+		require.Equal(t, testDID, result.ID)
+		require.True(t, result.Deactivated)
+		require.Empty(t, result.KeyIDs)
+		require.Empty(t, result.ServiceIDs)
+	})
+
+	t.Run("no document", func(t *testing.T) {
+		result := newCompactResult(&docdid.DocResolution{}, verificationNotPerformed)
+		require.Empty(t, result.ID)
+		require.NotNil(t, result.KeyIDs)
+		require.NotNil(t, result.ServiceIDs)
+	})
+}
+
+func TestGetVerificationStatus(t *testing.T) {
+	newResolution := func(published, unpublished int) *docdid.DocResolution {
+		return &docdid.DocResolution{
+			DocumentMetadata: &docdid.DocumentMetadata{
+				Method: &docdid.MethodMetadata{
+					PublishedOperations:   make([]*docdid.ProtocolOperation, published),
+					UnpublishedOperations: make([]*docdid.ProtocolOperation, unpublished),
+				},
+			},
+		}
+	}
+
+	require.Equal(t, verificationPassed, getVerificationStatus(newResolution(0, 1), orb.Unpublished))
+	require.Equal(t, verificationPassed, getVerificationStatus(newResolution(0, 1), orb.All))
+	require.Equal(t, verificationPassed, getVerificationStatus(newResolution(1, 0), orb.All))
+	require.Equal(t, verificationNotPerformed, getVerificationStatus(newResolution(1, 0), orb.Unpublished))
+	require.Equal(t, verificationNotPerformed, getVerificationStatus(newResolution(1, 1), orb.None))
+	require.Equal(t, verificationNotPerformed, getVerificationStatus(&docdid.DocResolution{}, orb.All))
+	require.Equal(t, verificationNotPerformed, getVerificationStatus(nil, orb.All))
+}
+
+func TestResolveCompact(t *testing.T) {
+	docResolution, err := docdid.ParseDocumentResolution([]byte(unpublishedResolutionResult))
+	require.NoError(t, err)
+
+	resolve := func(vdr didReader) (*docdid.DocResolution, *docdid.DocResolution, error) {
+		doc, err := vdr.Read(testDID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return doc, doc, nil
+	}
+
+	validReader := &mockDIDReader{results: map[string]*docdid.DocResolution{testDID: docResolution}}
+	verifyErrReader := &mockDIDReader{err: errors.New(errVerifyMsg)}
+
+	t.Run("verified", func(t *testing.T) {
+		result, anchoredDoc, err := resolveCompact(resolve, validReader, validReader, orb.All)
+		require.NoError(t, err)
+		require.Equal(t, docResolution, anchoredDoc)
+		require.Equal(t, verificationPassed, result.Verification)
+		require.Empty(t, result.VerificationError)
+	})
+
+	t.Run("verification failed", func(t *testing.T) {
+		result, anchoredDoc, err := resolveCompact(resolve, verifyErrReader, validReader, orb.All)
+		require.NoError(t, err)
+		require.Equal(t, docResolution, anchoredDoc)
+		require.Equal(t, testDID, result.ID)
+		require.Equal(t, verificationFailed, result.Verification)
+		require.Equal(t, errVerifyMsg, result.VerificationError)
+	})
+
+	t.Run("not resolvable without verification", func(t *testing.T) {
+		_, _, err := resolveCompact(resolve, verifyErrReader, &mockDIDReader{err: errors.New("not found")}, orb.All)
+		require.EqualError(t, err, errVerifyMsg)
+	})
+
+	t.Run("verification disabled", func(t *testing.T) {
+		_, _, err := resolveCompact(resolve, &mockDIDReader{err: errors.New("not found")}, nil, orb.None)
+		require.EqualError(t, err, "not found")
+	})
+}
+
+func TestPrintCompactResult(t *testing.T) {
+	result := &compactResult{
+		ID:                testDID,
+		Deactivated:       true,
+		Verification:      verificationFailed,
+		VerificationError: errVerifyMsg,
+		KeyIDs:            []string{testDID + "#key1", testDID + "#key2"},
+		ServiceIDs:        []string{},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		require.NoError(t, printCompactResult(out, result, outputText))
+		require.Equal(t,
+			"ID:           "+testDID+"\n"+
+				"Status:       DEACTIVATED\n"+
+				"Verification: FAILED: "+errVerifyMsg+"\n"+
+				"Keys:         "+testDID+"#key1, "+testDID+"#key2\n"+
+				"Services:     (none)\n",
+			out.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		require.NoError(t, printCompactResult(out, result, outputJSON))
+		require.Equal(t, 1, bytes.Count(out.Bytes(), []byte("\n")))
+
+		r := &compactResult{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), r))
+		require.Equal(t, result, r)
+	})
+}
+
+func TestGetCompactArgs(t *testing.T) {
+	getArgs := func(t *testing.T, raw bool, args ...string) (bool, string, error) {
+		t.Helper()
+
+		cmd := GetResolveDIDCmd()
+		require.NoError(t, cmd.ParseFlags(args))
+
+		return getCompactArgs(cmd, raw)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		compact, output, err := getArgs(t, false)
+		require.NoError(t, err)
+		require.False(t, compact)
+		require.Equal(t, outputJSON, output)
+
+		compact, output, err = getArgs(t, false, flag+compactFlagName, "true")
+		require.NoError(t, err)
+		require.True(t, compact)
+		require.Equal(t, outputText, output)
+	})
+
+	t.Run("compact json", func(t *testing.T) {
+		compact, output, err := getArgs(t, false, flag+compactFlagName, "true", flag+outputFlagName, "JSON")
+		require.NoError(t, err)
+		require.True(t, compact)
+		require.Equal(t, outputJSON, output)
+	})
+
+	t.Run("text without compact", func(t *testing.T) {
+		_, _, err := getArgs(t, false, flag+outputFlagName, "text")
+		require.EqualError(t, err, "output 'text' is only supported with compact")
+	})
+
+	t.Run("unsupported output", func(t *testing.T) {
+		_, _, err := getArgs(t, false, flag+outputFlagName, "yaml")
+		require.EqualError(t, err, "unsupported output 'yaml'. Values [text, json]")
+	})
+
+	t.Run("invalid compact", func(t *testing.T) {
+		_, _, err := getArgs(t, false, flag+compactFlagName, "xxx")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), compactFlagName)
+	})
+
+	t.Run("compact with raw", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetResolveDIDCmd()
+
+		var args []string
+		args = append(args, domainArg()...)
+		args = append(args, didURIArg()...)
+		args = append(args, verifyTypeArg("none")...)
+		args = append(args, flag+compactFlagName, "true", flag+rawFlagName, "true")
+
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.EqualError(t, err, "compact and raw may not be used together")
+	})
+}
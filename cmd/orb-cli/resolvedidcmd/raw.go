@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/trustbloc/sidetree-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/orbclient/resolutionverifier"
+)
+
+const rawOutputWarning = "DIAGNOSTIC OUTPUT ONLY (--raw): this is the untransformed document and metadata assembled " +
+	"from the DID's operations before the document transformation is applied. It is NOT a spec-compliant DID " +
+	"resolution result."
+
+// rawResolutionResult contains the document and metadata as produced by applying the DID's operations, i.e.
+// before the ID, context, canonical ID, equivalent IDs, etc. are injected by the document transformer.
+type rawResolutionResult struct {
+	Warning  string            `json:"warning"`
+	Document document.Document `json:"document"`
+	Metadata *rawMetadata      `json:"metadata"`
+}
+
+type rawMetadata struct {
+	CreatedTime                uint64      `json:"createdTime,omitempty"`
+	UpdatedTime                uint64      `json:"updatedTime,omitempty"`
+	VersionID                  string      `json:"versionId,omitempty"`
+	UpdateCommitment           string      `json:"updateCommitment,omitempty"`
+	RecoveryCommitment         string      `json:"recoveryCommitment,omitempty"`
+	Deactivated                bool        `json:"deactivated,omitempty"`
+	AnchorOrigin               interface{} `json:"anchorOrigin,omitempty"`
+	CanonicalReference         string      `json:"canonicalReference,omitempty"`
+	EquivalentReferences       []string    `json:"equivalentReferences,omitempty"`
+	PublishedOperationsCount   int         `json:"publishedOperationsCount"`
+	UnpublishedOperationsCount int         `json:"unpublishedOperationsCount"`
+}
+
+type untransformedResolver interface {
+	ResolveUntransformed(input *document.ResolutionResult) (*protocol.ResolutionModel, error)
+}
+
+func newUntransformedResolver(namespace string) (untransformedResolver, error) {
+	return resolutionverifier.New(namespace)
+}
+
+// getRawResolutionResult reconstructs the pre-transformation document from the published and unpublished
+// operations contained in the method metadata of the given resolution result.
+func getRawResolutionResult(resolver untransformedResolver, docResolution *docdid.DocResolution,
+) (*rawResolutionResult, error) {
+	rr, err := toResolutionResult(docResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	rm, err := resolver.ResolveUntransformed(rr)
+	if err != nil {
+		return nil, fmt.Errorf("reconstruct untransformed document (the server must be configured to include "+
+			"published and unpublished operations in the resolution result): %w", err)
+	}
+
+	return &rawResolutionResult{
+		Warning:  rawOutputWarning,
+		Document: rm.Doc,
+		Metadata: &rawMetadata{
+			CreatedTime:                rm.CreatedTime,
+			UpdatedTime:                rm.UpdatedTime,
+			VersionID:                  rm.VersionID,
+			UpdateCommitment:           rm.UpdateCommitment,
+			RecoveryCommitment:         rm.RecoveryCommitment,
+			Deactivated:                rm.Deactivated,
+			AnchorOrigin:               rm.AnchorOrigin,
+			CanonicalReference:         rm.CanonicalReference,
+			EquivalentReferences:       rm.EquivalentReferences,
+			PublishedOperationsCount:   len(rm.PublishedOperations),
+			UnpublishedOperationsCount: len(rm.UnpublishedOperations),
+		},
+	}, nil
+}
+
+func toResolutionResult(docResolution *docdid.DocResolution) (*document.ResolutionResult, error) {
+	if docResolution.DIDDocument == nil {
+		return nil, errors.New("resolution result does not contain a DID document")
+	}
+
+	docBytes, err := docResolution.DIDDocument.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("marshal DID document: %w", err)
+	}
+
+	rr := &document.ResolutionResult{}
+
+	rr.Document, err = document.FromBytes(docBytes)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DID document: %w", err)
+	}
+
+	metadataBytes, err := json.Marshal(docResolution.DocumentMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("marshal document metadata: %w", err)
+	}
+
+	err = json.Unmarshal(metadataBytes, &rr.DocumentMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal document metadata: %w", err)
+	}
+
+	return rr, nil
+}
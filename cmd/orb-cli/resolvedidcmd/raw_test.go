@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+func TestGetOutput(t *testing.T) {
+	docResolution, err := docdid.ParseDocumentResolution([]byte(unpublishedResolutionResult))
+	require.NoError(t, err)
+
+	t.Run("transformed", func(t *testing.T) {
+		expected, err := docResolution.JSONBytes()
+		require.NoError(t, err)
+
+		output, err := getOutput(nil, docResolution, false, common.DefaultDIDNamespace)
+		require.NoError(t, err)
+		require.Equal(t, expected, output)
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		output, err := getOutput(nil, docResolution, true, common.DefaultDIDNamespace)
+		require.NoError(t, err)
+
+		rawResult := &rawResolutionResult{}
+		require.NoError(t, json.Unmarshal(output, rawResult))
+
+		require.Equal(t, rawOutputWarning, rawResult.Warning)
+
+		// The untransformed document doesn't contain the ID or context injected by the transformer.
+		require.Empty(t, rawResult.Document.ID())
+		require.Empty(t, rawResult.Document.Context())
+		require.Len(t, rawResult.Document.PublicKeys(), 2)
+		require.Equal(t, "createKey", rawResult.Document.PublicKeys()[0].ID())
+
+		require.Equal(t, "EiDOeUN2rx3T9-48s-3qrv6bObQqEjJU9mQZOfJ3E3rMEg", rawResult.Metadata.UpdateCommitment)
+		require.Equal(t, "EiBXxlBZ4xsisY5XtBBtC32bxny5sPlwAsQotCWn9mIpFw", rawResult.Metadata.RecoveryCommitment)
+		require.Equal(t, "https://orb.domain1.com", rawResult.Metadata.AnchorOrigin)
+		require.Zero(t, rawResult.Metadata.PublishedOperationsCount)
+		require.Equal(t, 1, rawResult.Metadata.UnpublishedOperationsCount)
+	})
+
+	t.Run("raw - no operations in resolution result", func(t *testing.T) {
+		_, err := getOutput(nil, &docdid.DocResolution{
+			DIDDocument:      docResolution.DIDDocument,
+			DocumentMetadata: &docdid.DocumentMetadata{},
+		}, true, common.DefaultDIDNamespace)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the server must be configured to include published and unpublished operations")
+	})
+
+	t.Run("raw - no DID document", func(t *testing.T) {
+		_, err := getOutput(nil, &docdid.DocResolution{}, true, common.DefaultDIDNamespace)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolution result does not contain a DID document")
+	})
+}
+
+const unpublishedResolutionResult = `{
+  "@context": "https://w3id.org/did-resolution/v1",
+  "didDocument": {
+    "@context": [
+      "https://www.w3.org/ns/did/v1",
+      "https://w3id.org/security/suites/jws-2020/v1",
+      "https://w3id.org/security/suites/ed25519-2018/v1"
+    ],
+    "assertionMethod": [
+      "did:orb:uAAA:EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ#auth"
+    ],
+    "authentication": [
+      "did:orb:uAAA:EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ#createKey"
+    ],
+    "id": "did:orb:uAAA:EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ",
+    "service": [
+      {
+        "id": "did:orb:uAAA:EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ#didcomm",
+        "priority": 0,
+        "recipientKeys": [
+          "JDEByxZ4r86P523S3JEJpYMB5GS6qfeF2JDafJavvhgy"
+        ],
+        "routingKeys": [
+          "2hRNMYoPUFYqf6Wu8vtzWRisoztTnDopcpi618dpD1c8"
+        ],
+        "serviceEndpoint": "https://hub.example.com/.identity/did:example:0123456789abcdef/",
+        "type": "did-communication"
+      }
+    ],
+    "verificationMethod": [
+      {
+        "controller": "did:orb:uAAA:EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ",
+        "id": "did:orb:uAAA:EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ#createKey",
+        "publicKeyJwk": {
+          "crv": "P-256",
+          "kty": "EC",
+          "x": "sV0MyWQ1Z03dLEyVOMffQzp3Z25bQ_hdze7Am9hhgFA",
+          "y": "meAu6OloYAvupdAehPcOFBaRM_4NHU0GanE3P9bp1Rk"
+        },
+        "type": "JsonWebKey2020"
+      },
+      {
+        "controller": "did:orb:uAAA:EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ",
+        "id": "did:orb:uAAA:EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ#auth",
+        "publicKeyBase58": "4V2eee3RE2nXmdf8t59caUJeckQ5ebChh3E7iQ8SFbUM",
+        "type": "Ed25519VerificationKey2018"
+      }
+    ]
+  },
+  "didDocumentMetadata": {
+    "equivalentId": [
+      "did:orb:https:orb.domain4.com:uAAA:EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ"
+    ],
+    "method": {
+      "anchorOrigin": "https://orb.domain1.com",
+      "published": false,
+      "recoveryCommitment": "EiBXxlBZ4xsisY5XtBBtC32bxny5sPlwAsQotCWn9mIpFw",
+      "unpublishedOperations": [
+        {
+          "operation": "eyJkZWx0YSI6eyJwYXRjaGVzIjpbeyJhY3Rpb24iOiJhZGQtc2VydmljZXMiLCJzZXJ2aWNlcyI6W3siaWQiOiJkaWRjb21tIiwicHJpb3JpdHkiOjAsInJlY2lwaWVudEtleXMiOlsiSkRFQnl4WjRyODZQNTIzUzNKRUpwWU1CNUdTNnFmZUYySkRhZkphdnZoZ3kiXSwicm91dGluZ0tleXMiOlsiMmhSTk1Zb1BVRllxZjZXdTh2dHpXUmlzb3p0VG5Eb3BjcGk2MThkcEQxYzgiXSwic2VydmljZUVuZHBvaW50IjoiaHR0cHM6Ly9odWIuZXhhbXBsZS5jb20vLmlkZW50aXR5L2RpZDpleGFtcGxlOjAxMjM0NTY3ODlhYmNkZWYvIiwidHlwZSI6ImRpZC1jb21tdW5pY2F0aW9uIn1dfSx7ImFjdGlvbiI6ImFkZC1wdWJsaWMta2V5cyIsInB1YmxpY0tleXMiOlt7ImlkIjoiY3JlYXRlS2V5IiwicHVibGljS2V5SndrIjp7ImNydiI6IlAtMjU2Iiwia3R5IjoiRUMiLCJ4Ijoic1YwTXlXUTFaMDNkTEV5Vk9NZmZRenAzWjI1YlFfaGR6ZTdBbTloaGdGQSIsInkiOiJtZUF1Nk9sb1lBdnVwZEFlaFBjT0ZCYVJNXzROSFUwR2FuRTNQOWJwMVJrIn0sInB1cnBvc2VzIjpbImF1dGhlbnRpY2F0aW9uIl0sInR5cGUiOiJKc29uV2ViS2V5MjAyMCJ9LHsiaWQiOiJhdXRoIiwicHVibGljS2V5SndrIjp7ImNydiI6IkVkMjU1MTkiLCJrdHkiOiJPS1AiLCJ4IjoiTThFd0p6MHpibFNZSDFhMWVmMFVVcnhBN1Jkb3hsb1BLUFU1Y1lzYWIxbyIsInkiOiIifSwicHVycG9zZXMiOlsiYXNzZXJ0aW9uTWV0aG9kIl0sInR5cGUiOiJFZDI1NTE5VmVyaWZpY2F0aW9uS2V5MjAxOCJ9XX1dLCJ1cGRhdGVDb21taXRtZW50IjoiRWlET2VVTjJyeDNUOS00OHMtM3FydjZiT2JRcUVqSlU5bVFaT2ZKM0Uzck1FZyJ9LCJzdWZmaXhEYXRhIjp7ImFuY2hvck9yaWdpbiI6Imh0dHBzOi8vb3JiLmRvbWFpbjEuY29tIiwiZGVsdGFIYXNoIjoiRWlCZ1VTeHE4Mkd4eFpLaHFkMXpqSWdCdDh2WkxYZHdRdUJrSDBVM05vZTBOZyIsInJlY292ZXJ5Q29tbWl0bWVudCI6IkVpQlh4bEJaNHhzaXNZNVh0QkJ0QzMyYnhueTVzUGx3QXNRb3RDV245bUlwRncifSwidHlwZSI6ImNyZWF0ZSJ9",
+          "protocolVersion": 0,
+          "transactionTime": 1635519155,
+          "type": "create"
+        }
+      ],
+      "updateCommitment": "EiDOeUN2rx3T9-48s-3qrv6bObQqEjJU9mQZOfJ3E3rMEg"
+    }
+  }
+}`
@@ -6,25 +6,29 @@ SPDX-License-Identifier: Apache-2.0
 package resolvedidcmd
 
 import (
-	"crypto/tls"
-	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
+	"net/url"
 	"strings"
 
 	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	vdrweb "github.com/hyperledger/aries-framework-go/pkg/vdr/web"
 	"github.com/spf13/cobra"
 
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
-	"github.com/trustbloc/orb/internal/pkg/tlsutil"
+	"github.com/trustbloc/orb/pkg/document/util"
 )
 
 const (
 	didURIFlagName  = "did-uri"
 	didURIEnvKey    = "ORB_CLI_DID_URI"
-	didURIFlagUsage = "DID URI. " +
+	didURIFlagUsage = "DID URI. A did:web DID which is an alias of a did:orb DID may also be provided, in which case " +
+		"the did:orb DID is discovered from the did:web document's 'alsoKnownAs', resolved through Orb and " +
+		"verified against the did:web document." +
 		" Alternatively, this can be set with the following environment variable: " + didURIEnvKey
 
 	domainFlagName      = "domain"
@@ -57,6 +61,50 @@ const (
 	verifyTypeEnvKey    = "ORB_CLI_VERIFY_RESOLUTION_RESULT_TYPE"
 	verifyTypeFlagUsage = "verify resolution result type. Values [all, none, unpublished] " +
 		" Alternatively, this can be set with the following environment variable: " + verifyTypeEnvKey
+
+	anchorSummaryFlagName  = "anchor-summary"
+	anchorSummaryEnvKey    = "ORB_CLI_ANCHOR_SUMMARY"
+	anchorSummaryFlagUsage = "Set to 'true' to also retrieve the anchor credential referenced by the DID's canonical ID " +
+		"and print a summary of its issuer, witness domains, proof count and issuance date. Defaults to false." +
+		" Alternatively, this can be set with the following environment variable: " + anchorSummaryEnvKey
+
+	casURLFlagName  = "cas-url"
+	casURLEnvKey    = "ORB_CLI_CAS_URL"
+	casURLFlagUsage = "The URL of the CAS endpoint from which the anchor credential is retrieved when " +
+		anchorSummaryFlagName + " is set. Defaults to <domain>/cas if not set." +
+		" Alternatively, this can be set with the following environment variable: " + casURLEnvKey
+
+	rawFlagName  = "raw"
+	rawEnvKey    = "ORB_CLI_RAW"
+	rawFlagUsage = "Set to 'true' to output the untransformed document and metadata, i.e. the document as produced " +
+		"by applying the DID's operations before canonical/equivalent IDs, contexts, etc. are injected by the " +
+		"document transformation. The output is for diagnostics only and is NOT a spec-compliant DID resolution " +
+		"result. The server must include published and unpublished operations in the resolution result. " +
+		"Defaults to false." +
+		" Alternatively, this can be set with the following environment variable: " + rawEnvKey
+
+	compactFlagName  = "compact"
+	compactEnvKey    = "ORB_CLI_COMPACT"
+	compactFlagUsage = "Set to 'true' to output a brief summary of the resolution result (the document ID, key IDs, " +
+		"service IDs, deactivation status and verification status) instead of the full result. If the resolution " +
+		"result fails verification then the summary is still printed (flagged as failed) and the command returns " +
+		"an error. May not be used with " + rawFlagName + ". Defaults to false." +
+		" Alternatively, this can be set with the following environment variable: " + compactEnvKey
+
+	outputFlagName  = "output"
+	outputEnvKey    = "ORB_CLI_OUTPUT"
+	outputFlagUsage = "The output format. Values [text, json]. The full resolution result is always output as JSON. " +
+		"The summary output by " + compactFlagName + " is text by default or a single line of JSON if set to 'json'." +
+		" Alternatively, this can be set with the following environment variable: " + outputEnvKey
+
+	traceFlagName  = "trace"
+	traceEnvKey    = "ORB_CLI_TRACE"
+	traceFlagUsage = "Set to 'true' to print a step-by-step timeline of the resolution (discovery, WebFinger " +
+		"lookups, CAS retrievals, resolution requests, operation extraction and document rendering) along with the " +
+		"duration of each step. The trace is written to stderr, separately from the resolution result, and is " +
+		"printed even if resolution fails. Request headers, bodies and query parameters (other than the WebFinger " +
+		"resource) are not included in the trace. Defaults to false." +
+		" Alternatively, this can be set with the following environment variable: " + traceEnvKey
 )
 
 const (
@@ -78,10 +126,10 @@ func resolveDIDCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:          "resolve",
 		Short:        "Resolve orb DID",
-		Long:         "Resolve orb DID",
+		Long:         "Resolve orb DID or a did:web alias of an orb DID",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			rootCAs, err := getRootCAs(cmd)
+			tlsConfig, err := common.NewTLSConfig(cmd)
 			if err != nil {
 				return err
 			}
@@ -92,6 +140,13 @@ func resolveDIDCmd() *cobra.Command {
 				return err
 			}
 
+			if !isWebDID(didURI) {
+				err = util.ValidateOrbDID(didURI, common.GetDIDNamespace(cmd))
+				if err != nil {
+					return err
+				}
+			}
+
 			authToken := cmdutil.GetUserSetOptionalVarFromString(cmd, authTokenFlagName,
 				authTokenEnvKey)
 
@@ -103,36 +158,272 @@ func resolveDIDCmd() *cobra.Command {
 				return err
 			}
 
-			httpClient := http.Client{Transport: &http.Transport{
-				ForceAttemptHTTP2: true,
-				TLSClientConfig:   &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12},
-			}}
+			anchorSummaryEnabled, casURL, err := getAnchorSummaryArgs(cmd, domain)
+			if err != nil {
+				return err
+			}
+
+			raw, err := cmdutil.GetBool(cmd, rawFlagName, rawEnvKey, false)
+			if err != nil {
+				return fmt.Errorf("%s: %w", rawFlagName, err)
+			}
+
+			compact, output, err := getCompactArgs(cmd, raw)
+			if err != nil {
+				return err
+			}
 
-			vdr, err := orb.New(nil,
-				orb.WithAuthToken(authToken), orb.WithDomain(domain),
-				orb.WithVerifyResolutionResultType(verifyResolutionResultType),
-				orb.WithHTTPClient(&httpClient))
+			tracer, err := getTracer(cmd)
 			if err != nil {
 				return err
 			}
 
-			didDoc, err := vdr.Read(didURI, resolveDIDOption(cmd)...)
+			defer tracer.print(cmd.ErrOrStderr())
+
+			httpClient := http.Client{Transport: tracer.transport(&http.Transport{
+				ForceAttemptHTTP2: true,
+				TLSClientConfig:   tlsConfig,
+			})}
+
+			newVDR := func(verifyType orb.VerifyResolutionResultType) (*orb.VDR, error) {
+				return orb.New(nil,
+					orb.WithAuthToken(authToken), orb.WithDomain(domain),
+					orb.WithVerifyResolutionResultType(verifyType),
+					orb.WithHTTPClient(&httpClient))
+			}
+
+			vdr, err := newVDR(verifyResolutionResultType)
 			if err != nil {
-				return fmt.Errorf("failed to resolve did: %w", err)
+				return err
+			}
+
+			var anchoredDoc *docdid.DocResolution
+
+			if compact {
+				// A DID which fails verification is resolved again without verification so that the
+				// failure may be shown in the summary.
+				var unverifiedVDR didReader
+
+				if verifyResolutionResultType != orb.None {
+					unverifiedVDR, err = newVDR(orb.None)
+					if err != nil {
+						return err
+					}
+				}
+
+				anchoredDoc, err = resolveAndPrintCompact(cmd, tracer, vdr, unverifiedVDR, &httpClient, didURI,
+					verifyResolutionResultType, output)
+			} else {
+				anchoredDoc, err = resolveAndPrint(cmd, tracer, vdr, &httpClient, didURI, raw)
 			}
 
-			docBytes, err := didDoc.JSONBytes()
 			if err != nil {
 				return err
 			}
 
-			fmt.Printf("%s", docBytes)
+			if !anchorSummaryEnabled || anchoredDoc == nil {
+				return nil
+			}
 
-			return nil
+			return printAnchorSummary(cmd, &anchorSummaryResolver{
+				httpClient: &httpClient,
+				headers:    common.NewAuthTokenHeader(cmd),
+				casURL:     casURL,
+			}, anchoredDoc)
 		},
 	}
 }
 
+// resolveAndPrint resolves the given DID, prints the full resolution result and returns the resolution result
+// of the anchored did:orb DID.
+func resolveAndPrint(cmd *cobra.Command, tracer *resolutionTracer, vdr didReader, httpClient *http.Client,
+	didURI string, raw bool,
+) (*docdid.DocResolution, error) {
+	didDoc, anchoredDoc, err := resolveDID(cmd, tracer, vdr, httpClient, didURI, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	docBytes, err := getOutput(tracer, didDoc, raw, common.GetDIDNamespace(cmd))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("%s", docBytes)
+
+	return anchoredDoc, nil
+}
+
+// resolveAndPrintCompact resolves the given DID, prints a compact summary of the resolution result and returns
+// the resolution result of the anchored did:orb DID. An error is returned (after the summary is printed) if the
+// resolution result failed verification.
+func resolveAndPrintCompact(cmd *cobra.Command, tracer *resolutionTracer, vdr, unverifiedVDR didReader,
+	httpClient *http.Client, didURI string, verifyType orb.VerifyResolutionResultType, output string,
+) (*docdid.DocResolution, error) {
+	result, anchoredDoc, err := resolveCompact(
+		func(r didReader) (*docdid.DocResolution, *docdid.DocResolution, error) {
+			return resolveDID(cmd, tracer, r, httpClient, didURI, false)
+		},
+		vdr, unverifiedVDR, verifyType,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tracer.trace(stepRender, "compact "+output, func() error {
+		return printCompactResult(cmd.OutOrStdout(), result, output)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Verification == verificationFailed {
+		return nil, errVerificationFailed
+	}
+
+	return anchoredDoc, nil
+}
+
+// resolveDID resolves the given DID and returns the resolution result along with the resolution result of the
+// anchored did:orb DID. For a did:web alias, the anchored resolution result is nil if the alias isn't mapped to
+// a did:orb DID.
+func resolveDID(cmd *cobra.Command, tracer *resolutionTracer, vdr didReader, httpClient *http.Client,
+	didURI string, raw bool,
+) (*docdid.DocResolution, *docdid.DocResolution, error) {
+	vdr = tracer.reader(vdr)
+
+	if !isWebDID(didURI) {
+		didDoc, err := vdr.Read(didURI, resolveDIDOption(cmd)...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve did: %w", err)
+		}
+
+		return didDoc, didDoc, nil
+	}
+
+	if raw {
+		return nil, nil, fmt.Errorf("%s is not supported for did:web", rawFlagName)
+	}
+
+	result, err := (&webAliasResolver{
+		webVDR:     tracer.reader(vdrweb.New()),
+		orbVDR:     vdr,
+		webOpts:    []vdrapi.DIDMethodOption{vdrapi.WithOption(vdrweb.HTTPClientOpt, httpClient)},
+		orbOpts:    resolveDIDOption(cmd),
+		warningOut: cmd.ErrOrStderr(),
+		tracer:     tracer,
+		namespace:  common.GetDIDNamespace(cmd),
+	}).resolve(didURI)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result.WebResolution, result.OrbResolution, nil
+}
+
+func getOutput(tracer *resolutionTracer, docResolution *docdid.DocResolution, raw bool,
+	namespace string,
+) ([]byte, error) {
+	var docBytes []byte
+
+	if !raw {
+		err := tracer.trace(stepRender, "resolution result", func() error {
+			var e error
+
+			docBytes, e = docResolution.JSONBytes()
+
+			return e
+		})
+
+		return docBytes, err
+	}
+
+	resolver, err := newUntransformedResolver(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("new resolver: %w", err)
+	}
+
+	var rawResult *rawResolutionResult
+
+	err = tracer.trace(stepExtractOperations, "published and unpublished operations", func() error {
+		var e error
+
+		rawResult, e = getRawResolutionResult(resolver, docResolution)
+
+		return e
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tracer.trace(stepRender, "raw resolution result", func() error {
+		var e error
+
+		docBytes, e = json.MarshalIndent(rawResult, "", "  ")
+
+		return e
+	})
+
+	return docBytes, err
+}
+
+func printAnchorSummary(cmd *cobra.Command, resolver *anchorSummaryResolver, docResolution *docdid.DocResolution) error {
+	if docResolution.DocumentMetadata == nil || docResolution.DocumentMetadata.CanonicalID == "" {
+		common.Println(cmd.OutOrStdout(), "\n\nAnchor summary: the DID has not been anchored yet")
+
+		return nil
+	}
+
+	docLoader, err := common.NewDocumentLoader()
+	if err != nil {
+		return fmt.Errorf("new document loader: %w", err)
+	}
+
+	resolver.docLoader = docLoader
+
+	summary, err := resolver.resolve(docResolution.DocumentMetadata.CanonicalID)
+	if err != nil {
+		return fmt.Errorf("failed to get anchor summary: %w", err)
+	}
+
+	summaryBytes, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal anchor summary: %w", err)
+	}
+
+	common.Printf(cmd.OutOrStdout(), "\n\nAnchor summary:\n%s\n", summaryBytes)
+
+	return nil
+}
+
+func getAnchorSummaryArgs(cmd *cobra.Command, domain string) (bool, string, error) {
+	enabled, err := cmdutil.GetBool(cmd, anchorSummaryFlagName, anchorSummaryEnvKey, false)
+	if err != nil {
+		return false, "", fmt.Errorf("%s: %w", anchorSummaryFlagName, err)
+	}
+
+	if !enabled {
+		return false, "", nil
+	}
+
+	casURL := cmdutil.GetUserSetOptionalVarFromString(cmd, casURLFlagName, casURLEnvKey)
+	if casURL == "" {
+		if domain == "" {
+			return false, "", fmt.Errorf("either %s or %s must be set when %s is enabled",
+				casURLFlagName, domainFlagName, anchorSummaryFlagName)
+		}
+
+		casURL = strings.TrimSuffix(domain, "/") + "/cas"
+	}
+
+	_, err = url.Parse(casURL)
+	if err != nil {
+		return false, "", fmt.Errorf("invalid CAS URL %s: %w", casURL, err)
+	}
+
+	return true, casURL, nil
+}
+
 func resolveDIDOption(cmd *cobra.Command) []vdrapi.DIDMethodOption {
 	return getSidetreeURL(cmd)
 }
@@ -150,6 +441,20 @@ func getSidetreeURL(cmd *cobra.Command) []vdrapi.DIDMethodOption {
 	return opts
 }
 
+// getTracer returns a resolution tracer if tracing is enabled, otherwise nil.
+func getTracer(cmd *cobra.Command) (*resolutionTracer, error) {
+	enabled, err := cmdutil.GetBool(cmd, traceFlagName, traceEnvKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", traceFlagName, err)
+	}
+
+	if !enabled {
+		return nil, nil
+	}
+
+	return newResolutionTracer(), nil
+}
+
 func getVerifyResolutionResultType(cmd *cobra.Command) (orb.VerifyResolutionResultType, error) {
 	verifyTypeString, err := cmdutil.GetUserSetVarFromString(cmd, verifyTypeFlagName,
 		verifyTypeEnvKey, false)
@@ -169,27 +474,6 @@ func getVerifyResolutionResultType(cmd *cobra.Command) (orb.VerifyResolutionResu
 	return -1, fmt.Errorf("unsupported %s for verifyResolutionResultType", verifyTypeString)
 }
 
-func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
-	tlsSystemCertPoolString := cmdutil.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
-		tlsSystemCertPoolEnvKey)
-
-	tlsSystemCertPool := false
-
-	if tlsSystemCertPoolString != "" {
-		var err error
-		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
-
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	tlsCACerts := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
-		tlsCACertsEnvKey)
-
-	return tlsutil.GetCertPool(tlsSystemCertPool, tlsCACerts)
-}
-
 func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(didURIFlagName, "", "", didURIFlagUsage)
 	startCmd.Flags().StringP(domainFlagName, "", "", domainFileFlagUsage)
@@ -199,4 +483,10 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(authTokenFlagName, "", "", authTokenFlagUsage)
 	startCmd.Flags().StringArrayP(sidetreeURLResFlagName, "", []string{}, sidetreeURLResFlagUsage)
 	startCmd.Flags().StringP(verifyTypeFlagName, "", "", verifyTypeFlagUsage)
+	startCmd.Flags().StringP(anchorSummaryFlagName, "", "", anchorSummaryFlagUsage)
+	startCmd.Flags().StringP(casURLFlagName, "", "", casURLFlagUsage)
+	startCmd.Flags().StringP(rawFlagName, "", "", rawFlagUsage)
+	startCmd.Flags().StringP(compactFlagName, "", "", compactFlagUsage)
+	startCmd.Flags().StringP(outputFlagName, "", "", outputFlagUsage)
+	startCmd.Flags().StringP(traceFlagName, "", "", traceFlagUsage)
 }
@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
 )
 
 const (
@@ -32,6 +34,39 @@ func TestMissingArg(t *testing.T) {
 			"ORB_CLI_DID_URI (environment variable) have been set.")
 	})
 
+	t.Run("test invalid did uri", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetResolveDIDCmd()
+
+		var args []string
+		args = append(args, domainArg()...)
+		args = append(args, flag+didURIFlagName, "did:ex:123")
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting namespace [did:orb]")
+	})
+
+	t.Run("test did uri in custom namespace", func(t *testing.T) {
+		os.Clearenv()
+		t.Setenv(common.DIDNamespaceEnvKey, "did:ex")
+
+		cmd := GetResolveDIDCmd()
+
+		var args []string
+		args = append(args, domainArg()...)
+		args = append(args, flag+didURIFlagName, "did:ex:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A")
+		args = append(args, verifyTypeArg("invalid")...)
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "expecting namespace")
+	})
+
 	t.Run("test verify type is missing", func(t *testing.T) {
 		os.Clearenv()
 		cmd := GetResolveDIDCmd()
@@ -100,7 +135,7 @@ func domainArg() []string {
 }
 
 func didURIArg() []string {
-	return []string{flag + didURIFlagName, "did:ex:123"}
+	return []string{flag + didURIFlagName, "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"}
 }
 
 func verifyTypeArg(value string) []string {
@@ -0,0 +1,233 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	stepDiscovery         = "discovery"
+	stepWebFinger         = "webfinger"
+	stepCAS               = "cas"
+	stepResolution        = "resolution"
+	stepWebDocument       = "did:web document"
+	stepHTTP              = "http"
+	stepResolve           = "resolve"
+	stepVerifyAlias       = "verify alias"
+	stepExtractOperations = "extract operations"
+	stepRender            = "render"
+
+	traceHeader = "----- Resolution trace -----"
+	traceFooter = "----- End of resolution trace -----"
+)
+
+// traceStep is a single step of a traced DID resolution.
+type traceStep struct {
+	Name     string
+	Detail   string
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// resolutionTracer records the steps of a DID resolution along with their timings. All methods may be invoked
+// on a nil tracer, in which case nothing is recorded.
+type resolutionTracer struct {
+	mutex sync.Mutex
+	start time.Time
+	steps []*traceStep
+	now   func() time.Time
+}
+
+func newResolutionTracer() *resolutionTracer {
+	return &resolutionTracer{
+		start: time.Now(),
+		now:   time.Now,
+	}
+}
+
+// trace invokes the given function and records it as a step with the given name and detail.
+func (t *resolutionTracer) trace(name, detail string, fn func() error) error {
+	if t == nil {
+		return fn()
+	}
+
+	start := t.now()
+
+	err := fn()
+
+	t.record(&traceStep{
+		Name:     name,
+		Detail:   detail,
+		Start:    start,
+		Duration: t.now().Sub(start),
+		Err:      err,
+	})
+
+	return err
+}
+
+func (t *resolutionTracer) record(step *traceStep) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.steps = append(t.steps, step)
+}
+
+// reader returns a DID reader which records each resolution as a step.
+func (t *resolutionTracer) reader(r didReader) didReader {
+	if t == nil {
+		return r
+	}
+
+	return &tracingReader{target: r, tracer: t}
+}
+
+// transport returns an HTTP round tripper which records each request as a step. The step name is derived from
+// the request path (discovery, WebFinger, CAS, etc.)
+func (t *resolutionTracer) transport(rt http.RoundTripper) http.RoundTripper {
+	if t == nil {
+		return rt
+	}
+
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return &tracingTransport{target: rt, tracer: t}
+}
+
+// print writes the recorded steps to the given writer. The trace is delimited by a header and footer so that it
+// is clearly separated from the resolution result.
+func (t *resolutionTracer) print(w io.Writer) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	common.Println(tw, traceHeader)
+	common.Println(tw, "OFFSET\tDURATION\tSTEP\tDETAIL")
+
+	for _, step := range t.steps {
+		detail := step.Detail
+		if step.Err != nil {
+			detail += " - FAILED: " + step.Err.Error()
+		}
+
+		common.Printf(tw, "+%s\t%s\t%s\t%s\n", formatDuration(step.Start.Sub(t.start)),
+			formatDuration(step.Duration), step.Name, detail)
+	}
+
+	common.Printf(tw, "Total: %s\n", formatDuration(t.now().Sub(t.start)))
+	common.Println(tw, traceFooter)
+
+	if err := tw.Flush(); err != nil {
+		panic(err)
+	}
+}
+
+type tracingReader struct {
+	target didReader
+	tracer *resolutionTracer
+}
+
+func (r *tracingReader) Read(did string, opts ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error) {
+	var result *docdid.DocResolution
+
+	err := r.tracer.trace(stepResolve, did, func() error {
+		var e error
+
+		result, e = r.target.Read(did, opts...)
+
+		return e
+	})
+
+	return result, err
+}
+
+type tracingTransport struct {
+	target http.RoundTripper
+	tracer *resolutionTracer
+}
+
+// RoundTrip records the request as a step. The recorded duration is the time until the response headers were
+// received. Request headers, bodies and query parameters are never recorded since they may contain credentials.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.tracer.now()
+
+	resp, err := t.target.RoundTrip(req)
+
+	name, detail := describeRequest(req)
+
+	if err == nil {
+		detail = fmt.Sprintf("%s -> %d", detail, resp.StatusCode)
+	}
+
+	t.tracer.record(&traceStep{
+		Name:     name,
+		Detail:   detail,
+		Start:    start,
+		Duration: t.tracer.now().Sub(start),
+		Err:      err,
+	})
+
+	return resp, err
+}
+
+// describeRequest returns the step name and a sanitized description of the given request. User info, query
+// parameters and fragments are removed from the URL, except for the WebFinger 'resource' parameter which contains
+// the (public) resource being looked up.
+func describeRequest(req *http.Request) (string, string) {
+	u := &url.URL{
+		Scheme: req.URL.Scheme,
+		Host:   req.URL.Host,
+		Path:   req.URL.Path,
+	}
+
+	var name string
+
+	switch p := req.URL.Path; {
+	case strings.HasSuffix(p, "/.well-known/webfinger"):
+		name = stepWebFinger
+
+		if resource := req.URL.Query().Get("resource"); resource != "" {
+			u.RawQuery = url.Values{"resource": []string{resource}}.Encode()
+		}
+	case strings.HasSuffix(p, "/did.json"):
+		name = stepWebDocument
+	case strings.Contains(p, "/.well-known/"):
+		name = stepDiscovery
+	case strings.Contains(p, "/cas/"), strings.HasSuffix(p, "/api/v0/cat"):
+		name = stepCAS
+	case strings.Contains(p, "/identifiers/"):
+		name = stepResolution
+	default:
+		name = stepHTTP
+	}
+
+	return name, req.Method + " " + u.String()
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
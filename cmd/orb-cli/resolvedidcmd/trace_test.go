@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolutionTracer(t *testing.T) {
+	t.Run("Steps are recorded", func(t *testing.T) {
+		tracer := newResolutionTracer()
+
+		require.NoError(t, tracer.trace(stepRender, "resolution result", func() error { return nil }))
+		require.EqualError(t, tracer.trace(stepVerifyAlias, "did:web:x -> did:orb:y", func() error {
+			return errors.New("injected error")
+		}), "injected error")
+
+		out := &bytes.Buffer{}
+
+		tracer.print(out)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 6)
+		require.Equal(t, traceHeader, lines[0])
+		require.Contains(t, lines[2], stepRender)
+		require.Contains(t, lines[2], "resolution result")
+		require.Contains(t, lines[3], stepVerifyAlias)
+		require.Contains(t, lines[3], "FAILED: injected error")
+		require.Contains(t, lines[4], "Total:")
+		require.Equal(t, traceFooter, lines[5])
+	})
+
+	t.Run("Nil tracer", func(t *testing.T) {
+		var tracer *resolutionTracer
+
+		require.EqualError(t, tracer.trace(stepRender, "", func() error {
+			return errors.New("injected error")
+		}), "injected error")
+
+		r := &mockDIDReader{}
+		require.True(t, tracer.reader(r) == r)
+
+		rt := &http.Transport{}
+		require.True(t, tracer.transport(rt) == rt)
+
+		out := &bytes.Buffer{}
+
+		tracer.print(out)
+		require.Empty(t, out.String())
+	})
+
+	t.Run("DID reader", func(t *testing.T) {
+		tracer := newResolutionTracer()
+
+		r := tracer.reader(&mockDIDReader{
+			results: map[string]*docdid.DocResolution{"did:orb:123": {}},
+		})
+
+		_, err := r.Read("did:orb:123")
+		require.NoError(t, err)
+
+		_, err = r.Read("did:orb:456")
+		require.Error(t, err)
+
+		require.Len(t, tracer.steps, 2)
+		require.Equal(t, stepResolve, tracer.steps[0].Name)
+		require.Equal(t, "did:orb:123", tracer.steps[0].Detail)
+		require.NoError(t, tracer.steps[0].Err)
+		require.Equal(t, "did:orb:456", tracer.steps[1].Detail)
+		require.Error(t, tracer.steps[1].Err)
+	})
+
+	t.Run("HTTP requests", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/notfound" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		tracer := newResolutionTracer()
+
+		client := &http.Client{Transport: tracer.transport(nil)}
+
+		paths := []string{
+			"/.well-known/did-orb",
+			"/.well-known/webfinger?resource=https://orb.domain1.com/cas&token=secret",
+			"/cas/uEiAbc",
+			"/sidetree/v1/identifiers/did:orb:123?token=secret",
+			"/.well-known/did.json",
+			"/notfound",
+		}
+
+		for _, p := range paths {
+			req, err := http.NewRequest(http.MethodGet, server.URL+p, http.NoBody)
+			require.NoError(t, err)
+
+			req.Header.Set("Authorization", "Bearer secret")
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+		}
+
+		require.Len(t, tracer.steps, len(paths))
+
+		expected := []struct{ name, detail string }{
+			{stepDiscovery, "GET " + server.URL + "/.well-known/did-orb -> 200"},
+			{stepWebFinger, "GET " + server.URL + "/.well-known/webfinger?resource=https%3A%2F%2Forb.domain1.com%2Fcas -> 200"},
+			{stepCAS, "GET " + server.URL + "/cas/uEiAbc -> 200"},
+			{stepResolution, "GET " + server.URL + "/sidetree/v1/identifiers/did:orb:123 -> 200"},
+			{stepWebDocument, "GET " + server.URL + "/.well-known/did.json -> 200"},
+			{stepHTTP, "GET " + server.URL + "/notfound -> 404"},
+		}
+
+		for i, e := range expected {
+			require.Equal(t, e.name, tracer.steps[i].Name)
+			require.Equal(t, e.detail, tracer.steps[i].Detail)
+		}
+
+		out := &bytes.Buffer{}
+
+		tracer.print(out)
+		require.NotContains(t, out.String(), "secret")
+	})
+}
+
+func TestTraceFlag(t *testing.T) {
+	t.Run("Invalid value", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetResolveDIDCmd()
+
+		var args []string
+		args = append(args, domainArg()...)
+		args = append(args, didURIArg()...)
+		args = append(args, verifyTypeArg("none")...)
+		args = append(args, flag+traceFlagName, "xxx")
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), traceFlagName)
+	})
+
+	t.Run("Trace is printed to stderr on failure", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetResolveDIDCmd()
+
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+
+		var args []string
+		args = append(args, domainArg()...)
+		args = append(args, didURIArg()...)
+		args = append(args, verifyTypeArg("none")...)
+		args = append(args, flag+traceFlagName, "true")
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to resolve did")
+		require.NotContains(t, stdout.String(), traceHeader)
+		require.Contains(t, stderr.String(), traceHeader)
+		require.Contains(t, stderr.String(), stepResolve)
+		require.Contains(t, stderr.String(), "FAILED")
+	})
+}
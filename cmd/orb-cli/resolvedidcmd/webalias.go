@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	diddoctransformer "github.com/trustbloc/orb/pkg/orbclient/doctransformer"
+)
+
+const webDIDPrefix = "did:web:"
+
+type didReader interface {
+	Read(did string, opts ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error)
+}
+
+// webAliasResult contains the resolved did:web document along with the resolution result of the did:orb DID
+// that the did:web DID is an alias of. OrbResolution is nil if the did:web document is not mapped to a did:orb DID.
+type webAliasResult struct {
+	WebResolution *docdid.DocResolution
+	OrbResolution *docdid.DocResolution
+}
+
+// webAliasResolver resolves a did:web DID which is an alias of a did:orb DID.
+type webAliasResolver struct {
+	webVDR     didReader
+	orbVDR     didReader
+	webOpts    []vdrapi.DIDMethodOption
+	orbOpts    []vdrapi.DIDMethodOption
+	warningOut io.Writer
+	tracer     *resolutionTracer
+	namespace  string
+}
+
+func isWebDID(did string) bool {
+	return strings.HasPrefix(did, webDIDPrefix)
+}
+
+// resolve resolves the given did:web DID and looks for the did:orb DID in the document's 'alsoKnownAs'.
+// If found, the did:orb DID is resolved through Orb and the did:web document is verified to be derived from
+// the did:orb document. If the did:web document isn't mapped to a did:orb DID then a warning is written and
+// the plain did:web resolution result is returned.
+func (r *webAliasResolver) resolve(webDID string) (*webAliasResult, error) {
+	webResolution, err := r.webVDR.Read(webDID, r.webOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve did[%s]: %w", webDID, err)
+	}
+
+	if webResolution.DIDDocument == nil {
+		return nil, fmt.Errorf("failed to resolve did[%s]: document not found in resolution result", webDID)
+	}
+
+	orbDID := getOrbAlias(webResolution.DIDDocument, r.namespace)
+	if orbDID == "" {
+		common.Printf(r.warningOut, "WARNING: did[%s] is not mapped to a %s DID. Returning the plain did:web "+
+			"resolution result.\n", webDID, r.namespace)
+
+		return &webAliasResult{WebResolution: webResolution}, nil
+	}
+
+	orbResolution, err := r.orbVDR.Read(orbDID, r.orbOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve did[%s] for alias [%s]: %w", orbDID, webDID, err)
+	}
+
+	err = r.tracer.trace(stepVerifyAlias, webDID+" -> "+orbDID, func() error {
+		return verifyWebAlias(webResolution, orbResolution)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("did[%s] is not derived from did[%s]: %w", webDID, orbDID, err)
+	}
+
+	return &webAliasResult{
+		WebResolution: webResolution,
+		OrbResolution: orbResolution,
+	}, nil
+}
+
+func verifyWebAlias(webResolution, orbResolution *docdid.DocResolution) error {
+	webRR, err := toResolutionResult(webResolution)
+	if err != nil {
+		return err
+	}
+
+	orbRR, err := toResolutionResult(orbResolution)
+	if err != nil {
+		return err
+	}
+
+	return diddoctransformer.VerifyWebDocumentFromOrbDocument(webRR, orbRR)
+}
+
+// getOrbAlias returns the first DID in the document's 'alsoKnownAs' within the given namespace or an empty
+// string if the document doesn't contain an alias in the namespace.
+func getOrbAlias(doc *docdid.Doc, namespace string) string {
+	for _, aka := range doc.AlsoKnownAs {
+		if strings.HasPrefix(aka, namespace+":") {
+			return aka
+		}
+	}
+
+	return ""
+}
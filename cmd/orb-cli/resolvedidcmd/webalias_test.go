@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	diddoctransformer "github.com/trustbloc/orb/pkg/orbclient/doctransformer"
+)
+
+const webDID = "did:web:orb.domain1.com:scid:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+func TestWebAliasResolver(t *testing.T) {
+	orbResolution, err := docdid.ParseDocumentResolution([]byte(unpublishedResolutionResult))
+	require.NoError(t, err)
+
+	orbDID := orbResolution.DIDDocument.ID
+
+	webResolution := newWebResolution(t, orbResolution)
+
+	t.Run("success", func(t *testing.T) {
+		orbVDR := &mockDIDReader{results: map[string]*docdid.DocResolution{orbDID: orbResolution}}
+
+		warnings := &bytes.Buffer{}
+
+		r := &webAliasResolver{
+			namespace:  common.DefaultDIDNamespace,
+			webVDR:     &mockDIDReader{results: map[string]*docdid.DocResolution{webDID: webResolution}},
+			orbVDR:     orbVDR,
+			warningOut: warnings,
+		}
+
+		result, err := r.resolve(webDID)
+		require.NoError(t, err)
+		require.Equal(t, webResolution, result.WebResolution)
+		require.Equal(t, orbResolution, result.OrbResolution)
+		require.Equal(t, []string{orbDID}, orbVDR.dids)
+		require.Empty(t, warnings.String())
+	})
+
+	t.Run("no did:orb mapping -> plain did:web resolution", func(t *testing.T) {
+		doc, err := docdid.ParseDocument([]byte(`{"@context":"https://www.w3.org/ns/did/v1","id":"` + webDID + `"}`))
+		require.NoError(t, err)
+
+		orbVDR := &mockDIDReader{}
+
+		warnings := &bytes.Buffer{}
+
+		r := &webAliasResolver{
+			namespace: common.DefaultDIDNamespace,
+			webVDR: &mockDIDReader{results: map[string]*docdid.DocResolution{
+				webDID: {DIDDocument: doc},
+			}},
+			orbVDR:     orbVDR,
+			warningOut: warnings,
+		}
+
+		result, err := r.resolve(webDID)
+		require.NoError(t, err)
+		require.Equal(t, doc, result.WebResolution.DIDDocument)
+		require.Nil(t, result.OrbResolution)
+		require.Empty(t, orbVDR.dids)
+		require.Contains(t, warnings.String(), "is not mapped to a did:orb DID")
+	})
+
+	t.Run("did:web resolution error", func(t *testing.T) {
+		r := &webAliasResolver{
+			namespace: common.DefaultDIDNamespace,
+			webVDR:    &mockDIDReader{err: errors.New("injected web error")},
+			orbVDR:    &mockDIDReader{},
+		}
+
+		_, err := r.resolve(webDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected web error")
+	})
+
+	t.Run("no did:web document", func(t *testing.T) {
+		r := &webAliasResolver{
+			namespace: common.DefaultDIDNamespace,
+			webVDR:    &mockDIDReader{results: map[string]*docdid.DocResolution{webDID: {}}},
+			orbVDR:    &mockDIDReader{},
+		}
+
+		_, err := r.resolve(webDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "document not found in resolution result")
+	})
+
+	t.Run("did:orb resolution error", func(t *testing.T) {
+		r := &webAliasResolver{
+			namespace: common.DefaultDIDNamespace,
+			webVDR:    &mockDIDReader{results: map[string]*docdid.DocResolution{webDID: webResolution}},
+			orbVDR:    &mockDIDReader{err: errors.New("injected orb error")},
+		}
+
+		_, err := r.resolve(webDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected orb error")
+	})
+
+	t.Run("did:web document not derived from did:orb document", func(t *testing.T) {
+		modifiedResolution, err := docdid.ParseDocumentResolution([]byte(unpublishedResolutionResult))
+		require.NoError(t, err)
+
+		modifiedResolution.DIDDocument.Service = nil
+
+		r := &webAliasResolver{
+			namespace: common.DefaultDIDNamespace,
+			webVDR:    &mockDIDReader{results: map[string]*docdid.DocResolution{webDID: webResolution}},
+			orbVDR:    &mockDIDReader{results: map[string]*docdid.DocResolution{orbDID: modifiedResolution}},
+		}
+
+		_, err = r.resolve(webDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not derived from")
+	})
+}
+
+func TestResolveWebDID(t *testing.T) {
+	t.Run("raw not supported", func(t *testing.T) {
+		_, _, err := resolveDID(GetResolveDIDCmd(), nil, &mockDIDReader{}, nil, webDID, true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "raw is not supported for did:web")
+	})
+}
+
+func newWebResolution(t *testing.T, orbResolution *docdid.DocResolution) *docdid.DocResolution {
+	t.Helper()
+
+	orbRR, err := toResolutionResult(orbResolution)
+	require.NoError(t, err)
+
+	webDoc, err := diddoctransformer.WebDocumentFromOrbDocument(webDID, orbRR)
+	require.NoError(t, err)
+
+	webDocBytes, err := webDoc.Bytes()
+	require.NoError(t, err)
+
+	doc, err := docdid.ParseDocument(webDocBytes)
+	require.NoError(t, err)
+
+	return &docdid.DocResolution{DIDDocument: doc}
+}
+
+type mockDIDReader struct {
+	results map[string]*docdid.DocResolution
+	err     error
+	dids    []string
+}
+
+func (m *mockDIDReader) Read(did string, _ ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error) {
+	m.dids = append(m.dids, did)
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	result, ok := m.results[did]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,350 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package submitdidcmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/model"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+const (
+	requestFileFlagName  = "request-file"
+	requestFileEnvKey    = "ORB_CLI_REQUEST_FILE"
+	requestFileFlagUsage = "The file that contains the operation request generated with the --" +
+		common.OutputRequestFlagName + " flag of the create, update, recover or deactivate command." +
+		" Alternatively, this can be set with the following environment variable: " + requestFileEnvKey
+
+	sidetreeURLOpsFlagName  = "sidetree-url-operation"
+	sidetreeURLOpsFlagUsage = "The sidetree operations URL to which the request is sent." +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeURLOpsEnvKey
+	sidetreeURLOpsEnvKey = "ORB_CLI_SIDETREE_URL_OPERATION"
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "ORB_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "ORB_CLI_TLS_CACERTS"
+
+	sidetreeWriteTokenFlagName  = "sidetree-write-token"
+	sidetreeWriteTokenEnvKey    = "ORB_CLI_SIDETREE_WRITE_TOKEN" //nolint: gosec
+	sidetreeWriteTokenFlagUsage = "The sidetree write token " +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeWriteTokenEnvKey
+
+	maxRetryFlagName  = "max-retry"
+	maxRetryEnvKey    = "ORB_CLI_MAX_RETRY"
+	maxRetryFlagUsage = "The maximum number of attempts to submit the request if the operations endpoint" +
+		" is unavailable or returns a server error. Default value is 3." +
+		" Alternatively, this can be set with the following environment variable: " + maxRetryEnvKey
+
+	waitTimeFlagName  = "wait-time"
+	waitTimeEnvKey    = "ORB_CLI_WAIT_TIME"
+	waitTimeFlagUsage = "wait time between retries default value is 1s" +
+		" Alternatively, this can be set with the following environment variable: " + waitTimeEnvKey
+)
+
+var logger = log.New("orb-cli")
+
+const (
+	defaultMaxRetry = 3
+	defaultWaitTime = 1 * time.Second
+)
+
+// GetSubmitDIDCmd returns the Cobra submit did command.
+func GetSubmitDIDCmd() *cobra.Command {
+	submitDIDCmd := submitDIDCmd()
+
+	createFlags(submitDIDCmd)
+
+	return submitDIDCmd
+}
+
+func submitDIDCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "submit",
+		Short: "Submit a pre-built DID operation request",
+		Long: "Submits a DID operation request that was generated (and signed) with the --" +
+			common.OutputRequestFlagName + " flag of the create, update, recover or deactivate command." +
+			" The request is validated before it is sent and the resolution result returned by the server" +
+			" is printed. For example: did submit --request-file ./request.json" +
+			" --sidetree-url-operation https://orb.domain1.com/sidetree/v1/operations",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeSubmit(cmd)
+		},
+	}
+}
+
+func executeSubmit(cmd *cobra.Command) error {
+	requestFile, err := cmdutil.GetUserSetVarFromString(cmd, requestFileFlagName,
+		requestFileEnvKey, false)
+	if err != nil {
+		return err
+	}
+
+	operationsURL, err := cmdutil.GetUserSetVarFromString(cmd, sidetreeURLOpsFlagName,
+		sidetreeURLOpsEnvKey, false)
+	if err != nil {
+		return err
+	}
+
+	maxRetry, waitTime, err := getRetryArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	request, err := os.ReadFile(requestFile) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("read request file: %w", err)
+	}
+
+	opType, didSuffix, err := validateRequest(request)
+	if err != nil {
+		return fmt.Errorf("invalid operation request in %s: %w", requestFile, err)
+	}
+
+	httpClient, err := common.NewHTTPClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	resp, err := submitWithRetry(cmd.Context(), httpClient, request, newHeaders(cmd), operationsURL,
+		maxRetry, waitTime)
+	if err != nil {
+		return fmt.Errorf("failed to submit %s operation request: %w", opType, err)
+	}
+
+	out := cmd.OutOrStdout()
+
+	if len(bytes.TrimSpace(resp)) == 0 {
+		if didSuffix == "" {
+			common.Printf(out, "successfully submitted %s operation\n", opType)
+		} else {
+			common.Printf(out, "successfully submitted %s operation for DID suffix %s\n", opType, didSuffix)
+		}
+
+		return nil
+	}
+
+	common.Println(out, formatResponse(resp))
+
+	return nil
+}
+
+func getRetryArgs(cmd *cobra.Command) (int, time.Duration, error) {
+	maxRetry := defaultMaxRetry
+
+	maxRetryString := cmdutil.GetUserSetOptionalVarFromString(cmd, maxRetryFlagName,
+		maxRetryEnvKey)
+
+	if maxRetryString != "" {
+		var err error
+
+		maxRetry, err = strconv.Atoi(maxRetryString)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to convert max retry string to an integer: %w", err)
+		}
+
+		if maxRetry < 1 {
+			return 0, 0, fmt.Errorf("%s must be greater than 0", maxRetryFlagName)
+		}
+	}
+
+	waitTime, err := common.GetDuration(cmd, waitTimeFlagName, waitTimeEnvKey, defaultWaitTime)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", waitTimeFlagName, err)
+	}
+
+	return maxRetry, waitTime, nil
+}
+
+// validateRequest ensures that the given request unmarshals to a known Sidetree operation request model
+// and that the required fields of the model are set. The operation type and the DID suffix (which is empty
+// for a create request) are returned.
+func validateRequest(request []byte) (operation.Type, string, error) {
+	opRequest := &struct {
+		Operation operation.Type `json:"type"`
+	}{}
+
+	err := json.Unmarshal(request, opRequest)
+	if err != nil {
+		return "", "", fmt.Errorf("unmarshal request: %w", err)
+	}
+
+	switch opRequest.Operation {
+	case operation.TypeCreate:
+		req := &model.CreateRequest{}
+
+		if err := json.Unmarshal(request, req); err != nil {
+			return "", "", fmt.Errorf("unmarshal create request: %w", err)
+		}
+
+		if req.SuffixData == nil || req.Delta == nil {
+			return "", "", errors.New("create request must contain suffix data and delta")
+		}
+
+		return req.Operation, "", nil
+	case operation.TypeUpdate:
+		req := &model.UpdateRequest{}
+
+		if err := json.Unmarshal(request, req); err != nil {
+			return "", "", fmt.Errorf("unmarshal update request: %w", err)
+		}
+
+		return req.Operation, req.DidSuffix, validateSignedRequest(req.DidSuffix, req.SignedData, req.Delta != nil)
+	case operation.TypeRecover:
+		req := &model.RecoverRequest{}
+
+		if err := json.Unmarshal(request, req); err != nil {
+			return "", "", fmt.Errorf("unmarshal recover request: %w", err)
+		}
+
+		return req.Operation, req.DidSuffix, validateSignedRequest(req.DidSuffix, req.SignedData, req.Delta != nil)
+	case operation.TypeDeactivate:
+		req := &model.DeactivateRequest{}
+
+		if err := json.Unmarshal(request, req); err != nil {
+			return "", "", fmt.Errorf("unmarshal deactivate request: %w", err)
+		}
+
+		return req.Operation, req.DidSuffix, validateSignedRequest(req.DidSuffix, req.SignedData, true)
+	default:
+		return "", "", fmt.Errorf("unsupported operation type [%s]", opRequest.Operation)
+	}
+}
+
+func validateSignedRequest(didSuffix, signedData string, hasDelta bool) error {
+	if didSuffix == "" {
+		return errors.New("missing DID suffix")
+	}
+
+	if signedData == "" {
+		return errors.New("missing signed data")
+	}
+
+	if !hasDelta {
+		return errors.New("missing delta")
+	}
+
+	return nil
+}
+
+// submitWithRetry posts the request to the operations endpoint. The request is retried if the endpoint
+// can't be reached or if it returns a server error (or 'too many requests'). Other errors aren't retried
+// since the request would be rejected again.
+func submitWithRetry(ctx context.Context, httpClient *http.Client, request []byte, headers map[string]string,
+	operationsURL string, maxRetry int, waitTime time.Duration,
+) ([]byte, error) {
+	for i := 1; ; i++ {
+		resp, retryable, err := submit(ctx, httpClient, request, headers, operationsURL)
+		if err == nil {
+			return resp, nil
+		}
+
+		if !retryable || i >= maxRetry {
+			return nil, err
+		}
+
+		select {
+		case <-time.After(waitTime):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", err, ctx.Err())
+		}
+	}
+}
+
+func submit(ctx context.Context, httpClient *http.Client, request []byte, headers map[string]string,
+	operationsURL string,
+) ([]byte, bool, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, operationsURL, bytes.NewReader(request))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create http request: %w", err)
+	}
+
+	for k, v := range headers {
+		httpReq.Header.Add(k, v)
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, ctx.Err() == nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests,
+			fmt.Errorf("got unexpected response from %s status '%d' body %s",
+				operationsURL, resp.StatusCode, respBytes)
+	}
+
+	return respBytes, false, nil
+}
+
+func closeResponseBody(respBody io.Closer) {
+	if err := respBody.Close(); err != nil {
+		log.CloseResponseBodyError(logger, err)
+	}
+}
+
+// formatResponse returns the indented JSON of the given response or the response as is if it isn't JSON.
+func formatResponse(resp []byte) string {
+	formatted := &bytes.Buffer{}
+
+	if err := json.Indent(formatted, resp, "", "  "); err != nil {
+		return string(resp)
+	}
+
+	return formatted.String()
+}
+
+func newHeaders(cmd *cobra.Command) map[string]string {
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	sidetreeWriteToken := cmdutil.GetUserSetOptionalVarFromString(cmd, sidetreeWriteTokenFlagName,
+		sidetreeWriteTokenEnvKey)
+	if sidetreeWriteToken != "" {
+		headers["Authorization"] = "Bearer " + sidetreeWriteToken
+	}
+
+	return headers
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(requestFileFlagName, "", "", requestFileFlagUsage)
+	startCmd.Flags().StringP(sidetreeURLOpsFlagName, "", "", sidetreeURLOpsFlagUsage)
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "",
+		tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(sidetreeWriteTokenFlagName, "", "", sidetreeWriteTokenFlagUsage)
+	startCmd.Flags().StringP(maxRetryFlagName, "", "", maxRetryFlagUsage)
+	startCmd.Flags().StringP(waitTimeFlagName, "", "", waitTimeFlagUsage)
+}
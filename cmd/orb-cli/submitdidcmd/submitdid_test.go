@@ -0,0 +1,249 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package submitdidcmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	flag = "--"
+
+	deactivateRequest = `{"type":"deactivate","didSuffix":"EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A",` +
+		`"revealValue":"EiB2Cvu5ft2kqBJX8wI-6QdFyLHiF6RlXHVNmLrN4bUBFg","signedData":"eyJhbGciOiJFUzI1NiJ9.e30.c2ln"}`
+
+	createRequest = `{"type":"create","suffixData":{"deltaHash":"EiCfDWRnYlcD9EGA3d_5Z1AHu-iYqMbJ9nfiqdz5S8VDbg",` +
+		`"recoveryCommitment":"EiBfOZdMtU6OBw8Pk879QtZ-2J-9FbbjSZyoaA_bqD4zhA","anchorOrigin":"https://orb.domain1.com"},` +
+		`"delta":{"updateCommitment":"EiDOrcmPtfMHuwIWN6YoihdeIPxOKDHy3D6sdMXu_7CN0w","patches":[]}}`
+
+	createResponse = `{"@context":"https://w3id.org/did-resolution/v1","didDocument":` +
+		`{"id":"did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"}}`
+)
+
+func TestSubmitDID(t *testing.T) {
+	var (
+		received []byte
+		authz    string
+	)
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+
+		received, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		authz = r.Header.Get("Authorization")
+
+		if bytes.Equal(received, []byte(createRequest)) {
+			fmt.Fprint(w, createResponse)
+		}
+	}))
+	defer serv.Close()
+
+	requestFile := writeRequestFile(t, deactivateRequest)
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetSubmitDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		cmd.SetArgs([]string{
+			flag + requestFileFlagName, requestFile,
+			flag + sidetreeURLOpsFlagName, serv.URL,
+			flag + sidetreeWriteTokenFlagName, "token",
+		})
+		require.NoError(t, cmd.Execute())
+
+		require.Equal(t, deactivateRequest, string(received))
+		require.Equal(t, "Bearer token", authz)
+		require.Equal(t, "successfully submitted deactivate operation for DID suffix "+
+			"EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A\n", out.String())
+	})
+
+	t.Run("create -> resolution result printed", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetSubmitDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		cmd.SetArgs([]string{
+			flag + requestFileFlagName, writeRequestFile(t, createRequest),
+			flag + sidetreeURLOpsFlagName, serv.URL,
+		})
+		require.NoError(t, cmd.Execute())
+
+		require.Equal(t, createRequest, string(received))
+		require.Contains(t, out.String(), `"id": "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"`)
+	})
+
+	t.Run("invalid request file", func(t *testing.T) {
+		received = nil
+
+		for _, tc := range []struct {
+			request string
+			err     string
+		}{
+			{request: `not json`, err: "unmarshal request"},
+			{request: `{"type":"unknown"}`, err: "unsupported operation type [unknown]"},
+			{request: `{"type":"create","suffixData":{}}`, err: "create request must contain suffix data and delta"},
+			{request: `{"type":"create","suffixData":"invalid"}`, err: "unmarshal create request"},
+			{request: `{"type":"update","didSuffix":"abc","signedData":"jws"}`, err: "missing delta"},
+			{request: `{"type":"recover","signedData":"jws","delta":{}}`, err: "missing DID suffix"},
+			{request: `{"type":"deactivate","didSuffix":"abc"}`, err: "missing signed data"},
+		} {
+			os.Clearenv()
+			cmd := GetSubmitDIDCmd()
+
+			cmd.SetArgs([]string{
+				flag + requestFileFlagName, writeRequestFile(t, tc.request),
+				flag + sidetreeURLOpsFlagName, serv.URL,
+			})
+
+			err := cmd.Execute()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid operation request")
+			require.Contains(t, err.Error(), tc.err)
+		}
+
+		require.Nil(t, received, "invalid requests must not be sent")
+	})
+
+	t.Run("missing request file arg", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetSubmitDIDCmd()
+
+		cmd.SetArgs([]string{flag + sidetreeURLOpsFlagName, serv.URL})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither request-file (command line flag) nor "+
+			"ORB_CLI_REQUEST_FILE (environment variable) have been set.")
+	})
+
+	t.Run("missing operations URL arg", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetSubmitDIDCmd()
+
+		cmd.SetArgs([]string{flag + requestFileFlagName, requestFile})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither sidetree-url-operation (command line flag) nor "+
+			"ORB_CLI_SIDETREE_URL_OPERATION (environment variable) have been set.")
+	})
+
+	t.Run("request file not found", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetSubmitDIDCmd()
+
+		cmd.SetArgs([]string{
+			flag + requestFileFlagName, filepath.Join(t.TempDir(), "missing.json"),
+			flag + sidetreeURLOpsFlagName, serv.URL,
+		})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read request file")
+	})
+
+	t.Run("invalid max retry", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetSubmitDIDCmd()
+
+		cmd.SetArgs([]string{
+			flag + requestFileFlagName, requestFile,
+			flag + sidetreeURLOpsFlagName, serv.URL,
+			flag + maxRetryFlagName, "0",
+		})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "max-retry must be greater than 0")
+	})
+}
+
+func TestSubmitDIDRetry(t *testing.T) {
+	requestFile := writeRequestFile(t, deactivateRequest)
+
+	newServer := func(statusCodes ...int) (*httptest.Server, *int32) {
+		var attempts int32
+
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			i := int(atomic.AddInt32(&attempts, 1)) - 1
+
+			if i < len(statusCodes) {
+				w.WriteHeader(statusCodes[i])
+			}
+		})), &attempts
+	}
+
+	execute := func(u string) error {
+		os.Clearenv()
+		cmd := GetSubmitDIDCmd()
+		cmd.SetOut(&bytes.Buffer{})
+
+		cmd.SetArgs([]string{
+			flag + requestFileFlagName, requestFile,
+			flag + sidetreeURLOpsFlagName, u,
+			flag + maxRetryFlagName, "3",
+			flag + waitTimeFlagName, "10ms",
+		})
+
+		return cmd.Execute()
+	}
+
+	t.Run("server error -> retried and succeeds", func(t *testing.T) {
+		serv, attempts := newServer(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		defer serv.Close()
+
+		require.NoError(t, execute(serv.URL))
+		require.Equal(t, int32(3), atomic.LoadInt32(attempts))
+	})
+
+	t.Run("server error -> max retries exhausted", func(t *testing.T) {
+		serv, attempts := newServer(http.StatusInternalServerError, http.StatusInternalServerError,
+			http.StatusInternalServerError)
+		defer serv.Close()
+
+		err := execute(serv.URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to submit deactivate operation request")
+		require.Contains(t, err.Error(), "status '500'")
+		require.Equal(t, int32(3), atomic.LoadInt32(attempts))
+	})
+
+	t.Run("bad request -> not retried", func(t *testing.T) {
+		serv, attempts := newServer(http.StatusBadRequest)
+		defer serv.Close()
+
+		err := execute(serv.URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "status '400'")
+		require.Equal(t, int32(1), atomic.LoadInt32(attempts))
+	})
+}
+
+func writeRequestFile(t *testing.T, request string) string {
+	t.Helper()
+
+	requestFile := filepath.Join(t.TempDir(), "request.json")
+	require.NoError(t, os.WriteFile(requestFile, []byte(request), 0o600))
+
+	return requestFile
+}
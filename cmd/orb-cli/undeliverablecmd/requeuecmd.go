@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package undeliverablecmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+func newRequeueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "requeue",
+		Short:        "Requeues the given undeliverable messages to the topic to which they were originally published.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeRequeue(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	cmd.Flags().StringArrayP(idFlagName, "", nil, idFlagUsage)
+
+	return cmd
+}
+
+func executeRequeue(cmd *cobra.Command) error {
+	u, err := getURL(cmd)
+	if err != nil {
+		return err
+	}
+
+	ids, err := cmdutil.GetUserSetVarFromArrayString(cmd, idFlagName, idsEnvKey, false)
+	if err != nil {
+		return err
+	}
+
+	reqBytes, err := json.Marshal(&requeueRequest{IDs: ids})
+	if err != nil {
+		return err
+	}
+
+	_, err = common.SendHTTPRequest(cmd, reqBytes, http.MethodPost, u)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d message(s) have been successfully requeued.\n", len(ids))
+
+	return nil
+}
+
+type requeueRequest struct {
+	IDs []string `json:"ids"`
+}
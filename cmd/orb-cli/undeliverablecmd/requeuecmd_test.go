@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package undeliverablecmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	flag = "--"
+)
+
+func TestRequeueCmd(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"requeue"})
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test missing id arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"requeue"}
+		args = append(args, urlArg("localhost:8080")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither id (command line flag) nor ORB_CLI_MESSAGE_IDS (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			req := &requeueRequest{}
+			require.NoError(t, json.Unmarshal(reqBytes, req))
+			require.Equal(t, []string{"msg1", "msg2"}, req.IDs)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"requeue"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, idArg("msg1")...)
+		args = append(args, idArg("msg2")...)
+		args = append(args, authTokenArg("ADMIN_TOKEN")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.NoError(t, err)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"requeue"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, idArg("msg1")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+	})
+}
+
+func urlArg(value string) []string {
+	return []string{flag + urlFlagName, value}
+}
+
+func idArg(value string) []string {
+	return []string{flag + idFlagName, value}
+}
+
+func authTokenArg(value string) []string {
+	return []string{flag + common.AuthTokenFlagName, value}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package undeliverablecmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the undeliverable messages REST endpoint." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	idFlagName  = "id"
+	idFlagUsage = "The ID of an undeliverable message to requeue. Multiple IDs may be specified," +
+		" for example, --id <id1> --id <id2>." +
+		" Alternatively, this can be set with the following environment variable as a comma-separated list of IDs: " +
+		idsEnvKey
+	idsEnvKey = "ORB_CLI_MESSAGE_IDS"
+)
+
+// GetCmd returns the Cobra undeliverable command.
+func GetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "undeliverable",
+		Short:        "Inspects and requeues undeliverable messages.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("expecting subcommand list or requeue")
+		},
+	}
+
+	cmd.AddCommand(
+		newListCmd(),
+		newRequeueCmd(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,188 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package updatedidcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	ariesdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/multiformats/go-multihash"
+	"github.com/spf13/cobra"
+	"github.com/trustbloc/sidetree-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-go/pkg/document"
+	"github.com/trustbloc/sidetree-go/pkg/hashing"
+	"github.com/trustbloc/sidetree-go/pkg/patch"
+	"github.com/trustbloc/sidetree-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/client"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/doccomposer"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/operationparser/patchvalidator"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+// getJSONPatch loads the JSON patch from the file specified on the command-line, if any, and returns it along
+// with the operations endpoint to which the update is submitted. A JSON patch may not be combined with the
+// options which add public keys, services or also-known-as URIs since those options generate their own patches.
+func getJSONPatch(cmd *cobra.Command) (patch.Patch, string, error) {
+	jsonPatchFile := cmdutil.GetUserSetOptionalVarFromString(cmd, jsonPatchFileFlagName, jsonPatchFileEnvKey)
+	if jsonPatchFile == "" {
+		return nil, "", nil
+	}
+
+	for _, flagName := range []string{addPublicKeyFileFlagName, addServiceFileFlagName, didAlsoKnownAsFlagName} {
+		if cmd.Flags().Changed(flagName) {
+			return nil, "", fmt.Errorf("%s may not be used together with %s", jsonPatchFileFlagName, flagName)
+		}
+	}
+
+	operationsURLs := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLOpsFlagName,
+		sidetreeURLOpsEnvKey)
+	if len(operationsURLs) == 0 {
+		return nil, "", fmt.Errorf("%s is required when %s is specified", sidetreeURLOpsFlagName,
+			jsonPatchFileFlagName)
+	}
+
+	p, err := loadJSONPatch(jsonPatchFile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return p, operationsURLs[0], nil
+}
+
+// loadJSONPatch reads the RFC 6902 JSON patch from the given file and returns it as a Sidetree
+// 'ietf-json-patch' patch. The structure of the patch is validated by the CLI (which reports all errors
+// along with a JSON pointer to the offending operation) and the Sidetree patch validator determines
+// which document properties may be modified.
+func loadJSONPatch(jsonPatchFile string) (patch.Patch, error) {
+	data, err := os.ReadFile(filepath.Clean(jsonPatchFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON patch file '%s' : %w", jsonPatchFile, err)
+	}
+
+	if err := common.ValidateJSONPatch(data); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch file '%s': %w", jsonPatchFile, err)
+	}
+
+	p, err := patch.NewJSONPatch(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("create JSON patch: %w", err)
+	}
+
+	if err := patchvalidator.NewJSONValidator().Validate(p); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch file '%s': %w", jsonPatchFile, err)
+	}
+
+	return p, nil
+}
+
+// dryRunJSONPatch applies the JSON patch to the given (current) DID document in order to catch errors,
+// such as replacing a path that doesn't exist, before the update is submitted.
+func dryRunJSONPatch(didDoc *ariesdid.Doc, p patch.Patch) error {
+	docBytes, err := didDoc.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("marshal current document of %s: %w", didDoc.ID, err)
+	}
+
+	doc, err := document.FromBytes(docBytes)
+	if err != nil {
+		return fmt.Errorf("unmarshal current document of %s: %w", didDoc.ID, err)
+	}
+
+	if _, err := doccomposer.New().ApplyPatches(doc, []patch.Patch{p}); err != nil {
+		return fmt.Errorf("JSON patch cannot be applied to the current document of %s: %w", didDoc.ID, err)
+	}
+
+	return nil
+}
+
+type jsonPatchUpdater struct {
+	vdr           *orb.VDR
+	httpClient    *http.Client
+	keyRetriever  *keyRetriever
+	operationsURL string
+	writeToken    string
+}
+
+// update resolves the DID, validates the JSON patch against the current document and then submits
+// an update request containing the JSON patch to the operations endpoint.
+func (u *jsonPatchUpdater) update(ctx context.Context, didURI string, p patch.Patch,
+	opts ...vdrapi.DIDMethodOption,
+) error {
+	docResolution, err := u.vdr.Read(didURI, opts...)
+	if err != nil {
+		return fmt.Errorf("resolve DID: %w", err)
+	}
+
+	if err := dryRunJSONPatch(docResolution.DIDDocument, p); err != nil {
+		return err
+	}
+
+	if docResolution.DocumentMetadata == nil || docResolution.DocumentMetadata.Method == nil {
+		return errors.New("missing method metadata in resolution result")
+	}
+
+	req, err := u.newUpdateRequest(didURI, docResolution.DocumentMetadata.Method.UpdateCommitment, p)
+	if err != nil {
+		return fmt.Errorf("create update request: %w", err)
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	if u.writeToken != "" {
+		headers["Authorization"] = "Bearer " + u.writeToken
+	}
+
+	_, err = common.SendRequestWithContext(ctx, u.httpClient, req, headers, http.MethodPost, u.operationsURL)
+
+	return err
+}
+
+func (u *jsonPatchUpdater) newUpdateRequest(didURI, updateCommitment string, p patch.Patch) ([]byte, error) {
+	signer, err := u.keyRetriever.GetSigner(didURI, orb.Update, updateCommitment)
+	if err != nil {
+		return nil, err
+	}
+
+	nextUpdateKey, err := pubkey.GetPublicKeyJWK(u.keyRetriever.nextUpdateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next update key : %w", err)
+	}
+
+	nextUpdateCommitment, err := commitment.GetCommitment(nextUpdateKey, multihash.SHA2_256)
+	if err != nil {
+		return nil, err
+	}
+
+	multihashCode, err := hashing.GetMultihashCode(updateCommitment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid update commitment: %w", err)
+	}
+
+	revealValue, err := commitment.GetRevealValue(signer.PublicKeyJWK(), uint(multihashCode))
+	if err != nil {
+		return nil, err
+	}
+
+	return client.NewUpdateRequest(&client.UpdateRequestInfo{
+		DidSuffix:        didURI[strings.LastIndex(didURI, ":")+1:],
+		RevealValue:      revealValue,
+		UpdateCommitment: nextUpdateCommitment,
+		UpdateKey:        signer.PublicKeyJWK(),
+		Patches:          []patch.Patch{p},
+		MultihashCode:    multihash.SHA2_256,
+		Signer:           signer,
+	})
+}
@@ -7,11 +7,8 @@ package updatedidcmd
 
 import (
 	"crypto"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
 	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/api"
@@ -25,7 +22,7 @@ import (
 
 	"github.com/trustbloc/orb/cmd/orb-cli/common"
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
-	"github.com/trustbloc/orb/internal/pkg/tlsutil"
+	"github.com/trustbloc/orb/pkg/document/util"
 )
 
 const (
@@ -123,6 +120,14 @@ const (
 	didAlsoKnownAsFlagUsage = "Comma-separated list of also known as uris." +
 		" Alternatively, this can be set with the following environment variable: " + didAlsoKnownAsEnvKey
 	didAlsoKnownAsEnvKey = "ORB_CLI_DID_ALSO_KNOWN_AS"
+
+	jsonPatchFileFlagName  = "json-patch-file"
+	jsonPatchFileEnvKey    = "ORB_CLI_JSON_PATCH_FILE"
+	jsonPatchFileFlagUsage = "The file that contains an RFC 6902 JSON patch (an array of operations) to apply to" +
+		" the DID document. The patch is validated against the current document before it is submitted to the" +
+		" operations endpoint specified by " + sidetreeURLOpsFlagName + ". Public keys and services may not be" +
+		" patched and must be modified with the other options." +
+		" Alternatively, this can be set with the following environment variable: " + jsonPatchFileEnvKey
 )
 
 // GetUpdateDIDCmd returns the Cobra update did command.
@@ -141,7 +146,7 @@ func updateDIDCmd() *cobra.Command { //nolint: funlen,cyclop
 		Long:         "Update Orb DID",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			rootCAs, err := getRootCAs(cmd)
+			tlsConfig, err := common.NewTLSConfig(cmd)
 			if err != nil {
 				return err
 			}
@@ -152,6 +157,16 @@ func updateDIDCmd() *cobra.Command { //nolint: funlen,cyclop
 				return err
 			}
 
+			err = util.ValidateOrbDID(didURI, common.GetDIDNamespace(cmd))
+			if err != nil {
+				return err
+			}
+
+			jsonPatch, operationsURL, err := getJSONPatch(cmd)
+			if err != nil {
+				return err
+			}
+
 			sidetreeWriteToken := cmdutil.GetUserSetOptionalVarFromString(cmd, sidetreeWriteTokenFlagName,
 				sidetreeWriteTokenEnvKey)
 
@@ -165,7 +180,7 @@ func updateDIDCmd() *cobra.Command { //nolint: funlen,cyclop
 
 			httpClient := http.Client{Transport: &http.Transport{
 				ForceAttemptHTTP2: true,
-				TLSClientConfig:   &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12},
+				TLSClientConfig:   tlsConfig,
 			}}
 
 			kmsStoreURL := cmdutil.GetUserSetOptionalVarFromString(cmd, kmsStoreEndpointFlagName,
@@ -218,20 +233,45 @@ func updateDIDCmd() *cobra.Command { //nolint: funlen,cyclop
 				}
 			}
 
-			vdr, err := orb.New(&keyRetriever{
+			vdrHTTPClient := &httpClient
+
+			capture := common.NewRequestCapture(cmd, httpClient.Transport)
+			if capture != nil {
+				vdrHTTPClient = &http.Client{Transport: capture}
+			}
+
+			kr := &keyRetriever{
 				nextUpdateKey:      nextUpdateKey,
 				signingKey:         signingKey,
 				signingKeyID:       signingKeyID,
 				webKmsCryptoClient: webKmsCryptoClient,
 				signingKeyPK:       signingKeyPK,
-			},
+			}
+
+			vdr, err := orb.New(kr,
 				orb.WithAuthToken(sidetreeWriteToken), orb.WithDomain(domain),
-				orb.WithHTTPClient(&httpClient))
+				orb.WithHTTPClient(vdrHTTPClient))
 			if err != nil {
 				return err
 			}
 
-			err = vdr.Update(didDoc, opts...)
+			if jsonPatch != nil {
+				updater := &jsonPatchUpdater{
+					vdr:           vdr,
+					httpClient:    vdrHTTPClient,
+					keyRetriever:  kr,
+					operationsURL: operationsURL,
+					writeToken:    sidetreeWriteToken,
+				}
+
+				err = updater.update(cmd.Context(), didURI, jsonPatch, opts...)
+			} else {
+				err = vdr.Update(didDoc, opts...)
+			}
+			if capture != nil {
+				return capture.Write(cmd.OutOrStdout(), err)
+			}
+
 			if err != nil {
 				return fmt.Errorf("failed to update did: %w", err)
 			}
@@ -318,27 +358,6 @@ func getPublicKeys(cmd *cobra.Command) (*ariesdid.Doc, error) {
 	return &ariesdid.Doc{}, nil
 }
 
-func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
-	tlsSystemCertPoolString := cmdutil.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
-		tlsSystemCertPoolEnvKey)
-
-	tlsSystemCertPool := false
-
-	if tlsSystemCertPoolString != "" {
-		var err error
-		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
-
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	tlsCACerts := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
-		tlsCACertsEnvKey)
-
-	return tlsutil.GetCertPool(tlsSystemCertPool, tlsCACerts)
-}
-
 func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(didURIFlagName, "", "", didURIFlagUsage)
 	startCmd.Flags().StringP(domainFlagName, "", "", domainFileFlagUsage)
@@ -359,6 +378,9 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().String(signingKeyIDFlagName, "", signingKeyIDFlagUsage)
 	startCmd.Flags().String(nextUpdateKeyIDFlagName, "", nextUpdateKeyIDFlagUsage)
 	startCmd.Flags().StringArrayP(didAlsoKnownAsFlagName, "", []string{}, didAlsoKnownAsFlagUsage)
+	startCmd.Flags().StringP(jsonPatchFileFlagName, "", "", jsonPatchFileFlagUsage)
+
+	common.AddOutputRequestFlag(startCmd)
 }
 
 type keyRetriever struct {
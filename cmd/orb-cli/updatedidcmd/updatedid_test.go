@@ -6,13 +6,21 @@ SPDX-License-Identifier: Apache-2.0
 package updatedidcmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-go/pkg/patch"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/model"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
 )
 
 const (
@@ -85,6 +93,22 @@ Q7UGQKWNHeITH8NQlkmcySEKnaI9uyOkcb6TIvklapHCAF8cUf1kCHU10Eo0RTMI
     }]
   }
 ]`
+
+	didResolution = `{
+  "@context": "https://w3id.org/did-resolution/v1",
+  "didDocument": {
+    "@context": ["https://www.w3.org/ns/did/v1"],
+    "id": "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+  },
+  "didDocumentMetadata": {
+    "method": {
+      "published": true,
+      "anchorOrigin": "%[1]s",
+      "recoveryCommitment": "EiBfOZdMtU6OBw8Pk879QtZ-2J-9FbbjSZyoaA_bqD4zhA",
+      "updateCommitment": "EiDOrcmPtfMHuwIWN6YoihdeIPxOKDHy3D6sdMXu_7CN0w"
+    }
+  }
+}`
 )
 
 func TestMissingArg(t *testing.T) {
@@ -102,6 +126,21 @@ func TestMissingArg(t *testing.T) {
 		require.Contains(t, err.Error(), "Neither did-uri (command line flag) nor "+
 			"ORB_CLI_DID_URI (environment variable) have been set.")
 	})
+
+	t.Run("test invalid did uri", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		var args []string
+		args = append(args, domainArg()...)
+		args = append(args, flag+didURIFlagName, "did:ex:123")
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting namespace [did:orb]")
+	})
 }
 
 func TestKeyRetriever(t *testing.T) {
@@ -200,6 +239,28 @@ func TestUpdateDID(t *testing.T) {
 	}))
 	defer serv.Close()
 
+	var posted bool
+
+	var resolutionServ *httptest.Server
+
+	// The update is sent to the operations endpoint discovered from the anchor origin of the DID.
+	resolutionServ = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			posted = true
+		case r.URL.Path == "/.well-known/did-orb":
+			fmt.Fprintf(w, `{"resolutionEndpoint":"%[1]s/sidetree/v1/identifiers",`+
+				`"operationEndpoint":"%[1]s/sidetree/v1/operations"}`, resolutionServ.URL)
+		case r.URL.Path == "/.well-known/webfinger":
+			fmt.Fprintf(w, `{"properties":{"https://trustbloc.dev/ns/min-resolvers":1},`+
+				`"links":[{"rel":"self","href":"%s/sidetree/v1/operations"}]}`, resolutionServ.URL)
+		default:
+			w.Header().Set("Content-Type", "application/did+ld+json")
+			fmt.Fprintf(w, didResolution, resolutionServ.URL)
+		}
+	}))
+	defer resolutionServ.Close()
+
 	privateKeyFile, err := os.CreateTemp("", "*.json")
 	require.NoError(t, err)
 
@@ -288,6 +349,212 @@ func TestUpdateDID(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "ExportPubKeyBytes key failed")
 	})
+
+	t.Run("output request", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, flag+sidetreeURLResFlagName, resolutionServ.URL)
+		args = append(args, signingKeyFileFlagNameArg(privateKeyFile.Name())...)
+		args = append(args, nextUpdateKeyFileFlagNameArg(publicKeyFile.Name())...)
+		args = append(args, addServicesFileArg(servicesFile.Name())...)
+		args = append(args, signingKeyPasswordArg()...)
+		args = append(args, flag+common.OutputRequestFlagName, common.OutputRequestStdout)
+
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		require.False(t, posted)
+
+		req := &model.UpdateRequest{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), req))
+		require.Equal(t, operation.TypeUpdate, req.Operation)
+		require.Equal(t, "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A", req.DidSuffix)
+		require.NotEmpty(t, req.SignedData)
+		require.NotNil(t, req.Delta)
+	})
+}
+
+func TestUpdateDIDWithJSONPatch(t *testing.T) {
+	var postedRequest []byte
+
+	var serv *httptest.Server
+
+	serv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var err error
+
+			postedRequest, err = io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/did+ld+json")
+		fmt.Fprintf(w, didResolution, serv.URL)
+	}))
+	defer serv.Close()
+
+	privateKeyFile := writeTempFile(t, privateKeyPEM)
+	publicKeyFile := writeTempFile(t, pkPEM)
+
+	newArgs := func(jsonPatch string) []string {
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, flag+sidetreeURLResFlagName, serv.URL)
+		args = append(args, sidetreeURLArg(serv.URL+"/sidetree/v1/operations")...)
+		args = append(args, signingKeyFileFlagNameArg(privateKeyFile)...)
+		args = append(args, nextUpdateKeyFileFlagNameArg(publicKeyFile)...)
+		args = append(args, signingKeyPasswordArg()...)
+		args = append(args, jsonPatchFileArg(writeTempFile(t, jsonPatch))...)
+
+		return args
+	}
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+		postedRequest = nil
+
+		cmd := GetUpdateDIDCmd()
+		cmd.SetArgs(newArgs(`[{"op":"add","path":"/alsoKnownAs","value":["https://myblog.example/"]}]`))
+
+		require.NoError(t, cmd.Execute())
+		require.NotEmpty(t, postedRequest)
+
+		req := &model.UpdateRequest{}
+		require.NoError(t, json.Unmarshal(postedRequest, req))
+		require.Equal(t, operation.TypeUpdate, req.Operation)
+		require.Equal(t, "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A", req.DidSuffix)
+		require.NotEmpty(t, req.SignedData)
+		require.NotNil(t, req.Delta)
+		require.Len(t, req.Delta.Patches, 1)
+
+		action, err := req.Delta.Patches[0].GetAction()
+		require.NoError(t, err)
+		require.Equal(t, patch.JSONPatch, action)
+	})
+
+	t.Run("output request", func(t *testing.T) {
+		os.Clearenv()
+		postedRequest = nil
+
+		cmd := GetUpdateDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		args := newArgs(`[{"op":"add","path":"/alsoKnownAs","value":["https://myblog.example/"]}]`)
+		args = append(args, flag+common.OutputRequestFlagName, common.OutputRequestStdout)
+
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		require.Empty(t, postedRequest)
+
+		req := &model.UpdateRequest{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), req))
+		require.Equal(t, operation.TypeUpdate, req.Operation)
+		require.Len(t, req.Delta.Patches, 1)
+	})
+
+	t.Run("patch cannot be applied to current document", func(t *testing.T) {
+		os.Clearenv()
+		postedRequest = nil
+
+		cmd := GetUpdateDIDCmd()
+		cmd.SetArgs(newArgs(`[{"op":"replace","path":"/alsoKnownAs/0","value":"https://myblog.example/"}]`))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "JSON patch cannot be applied to the current document")
+		require.Empty(t, postedRequest)
+	})
+
+	t.Run("success - patch applied to any document property", func(t *testing.T) {
+		os.Clearenv()
+		postedRequest = nil
+
+		cmd := GetUpdateDIDCmd()
+		cmd.SetArgs(newArgs(`[{"op":"add","path":"/customProperty","value":"value"}]`))
+
+		require.NoError(t, cmd.Execute())
+		require.NotEmpty(t, postedRequest)
+	})
+
+	t.Run("services rejected by Sidetree validator", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetUpdateDIDCmd()
+		cmd.SetArgs(newArgs(`[{"op":"remove","path":"/service/0"}]`))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot modify services")
+	})
+
+	t.Run("public keys rejected by Sidetree validator", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetUpdateDIDCmd()
+		cmd.SetArgs(newArgs(`[{"op":"add","path":"/publicKeys/-","value":{"id":"key3"}}]`))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot modify public keys")
+	})
+
+	t.Run("invalid JSON patch file", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetUpdateDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, sidetreeURLArg(serv.URL)...)
+		args = append(args, jsonPatchFileArg("./wrongfile")...)
+
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read JSON patch file")
+	})
+
+	t.Run("missing operations URL", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetUpdateDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, jsonPatchFileArg("./patch.json")...)
+
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sidetree-url-operation is required when json-patch-file is specified")
+	})
+
+	t.Run("combined with other patch options", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetUpdateDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, jsonPatchFileArg("./patch.json")...)
+		args = append(args, didAlsoKnownAsArg("https://blog.example")...)
+
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "json-patch-file may not be used together with did-also-known-as")
+	})
 }
 
 func TestGetPublicKeys(t *testing.T) {
@@ -333,7 +600,7 @@ func sidetreeURLArg(value string) []string {
 }
 
 func didURIArg() []string {
-	return []string{flag + didURIFlagName, "did:ex:123"}
+	return []string{flag + didURIFlagName, "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"}
 }
 
 func addPublicKeyFileArg(value string) []string {
@@ -367,3 +634,23 @@ func kmsStoreEndpointFlagNameArg(value string) []string {
 func didAlsoKnownAsArg(value string) []string {
 	return []string{flag + didAlsoKnownAsFlagName, value}
 }
+
+func jsonPatchFileArg(value string) []string {
+	return []string{flag + jsonPatchFileFlagName, value}
+}
+
+func writeTempFile(t *testing.T, content string) string {
+	t.Helper()
+
+	file, err := os.CreateTemp("", "*.json")
+	require.NoError(t, err)
+
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+
+	require.NoError(t, file.Close())
+
+	t.Cleanup(func() { require.NoError(t, os.Remove(file.Name())) })
+
+	return file.Name()
+}
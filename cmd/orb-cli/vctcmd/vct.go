@@ -9,6 +9,7 @@ package vctcmd
 import (
 	"errors"
 
+	vdrweb "github.com/hyperledger/aries-framework-go/pkg/vdr/web"
 	"github.com/spf13/cobra"
 )
 
@@ -32,6 +33,19 @@ const (
 	vctAuthTokenFlagUsage = "VCT auth token." +
 		" Alternatively, this can be set with the following environment variable: " + vctAuthTokenEnvKey
 	vctAuthTokenEnvKey = "ORB_CLI_VCT_AUTH_TOKEN" //nolint:gosec
+
+	vcFileFlagName  = "vc-file"
+	vcFileEnvKey    = "ORB_CLI_VC_FILE"
+	vcFileFlagUsage = "The path of a file containing either the anchor linkset or the verifiable credential." +
+		" If set then the credential is loaded from the file instead of from CAS." +
+		" Alternatively, this can be set with the following environment variable: " + vcFileEnvKey
+
+	didDocumentFlagName  = "did-document"
+	didDocumentEnvKey    = "ORB_CLI_DID_DOCUMENT"
+	didDocumentFlagUsage = "The path of a file containing the DID document of a witness. This flag may be repeated." +
+		" The public keys used to verify the proofs are taken from these documents. If a DID document isn't provided" +
+		" for a verification method then it is resolved using did:web." +
+		" Alternatively, this can be set with the following environment variable (comma-separated): " + didDocumentEnvKey
 )
 
 // GetCmd returns the Cobra policy command.
@@ -41,12 +55,13 @@ func GetCmd() *cobra.Command {
 		Short:        "Examines the VCT log.",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return errors.New("expecting subcommand: verify")
+			return errors.New("expecting subcommand: verify or verify-proofs")
 		},
 	}
 
 	cmd.AddCommand(
 		newVerifyCmd(&clientProvider{}),
+		newVerifyProofsCmd(vdrweb.New()),
 	)
 
 	return cmd
@@ -17,11 +17,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/hyperledger/aries-framework-go/component/storageutil/cachedstore"
-	ariesmemstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mem"
-	"github.com/hyperledger/aries-framework-go/pkg/doc/ld"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
-	ldstore "github.com/hyperledger/aries-framework-go/pkg/store/ld"
 	jsonld "github.com/piprate/json-gold/ld"
 	"github.com/spf13/cobra"
 	"github.com/trustbloc/vct/pkg/client/vct"
@@ -29,7 +25,6 @@ import (
 
 	"github.com/trustbloc/orb/cmd/orb-cli/common"
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
-	"github.com/trustbloc/orb/internal/pkg/ldcontext"
 	"github.com/trustbloc/orb/pkg/anchor/util"
 	"github.com/trustbloc/orb/pkg/linkset"
 )
@@ -58,7 +53,7 @@ func executeVerify(cmd *cobra.Command, vctClientProvider vctClientProvider) erro
 		return err
 	}
 
-	docLoader, err := newDocumentLoader()
+	docLoader, err := common.NewDocumentLoader()
 	if err != nil {
 		return fmt.Errorf("new document loader: %w", err)
 	}
@@ -235,6 +230,7 @@ func getVC(cmd *cobra.Command, anchorHash, casURL string, docLoader jsonld.Docum
 	vc, err := util.VerifiableCredentialFromAnchorLink(anchorLinkset.Link(),
 		verifiable.WithDisabledProofCheck(),
 		verifiable.WithJSONLDDocumentLoader(docLoader),
+		verifiable.WithStrictValidation(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("get verifiable credential from anchor link: %w", err)
@@ -252,43 +248,6 @@ func getVC(cmd *cobra.Command, anchorHash, casURL string, docLoader jsonld.Docum
 	return vc, nil
 }
 
-func newDocumentLoader() (jsonld.DocumentLoader, error) {
-	ldStorageProvider := cachedstore.NewProvider(ariesmemstorage.NewProvider(), ariesmemstorage.NewProvider())
-
-	contextStore, err := ldstore.NewContextStore(ldStorageProvider)
-	if err != nil {
-		return nil, fmt.Errorf("create JSON-LD context store: %w", err)
-	}
-
-	remoteProviderStore, err := ldstore.NewRemoteProviderStore(ldStorageProvider)
-	if err != nil {
-		return nil, fmt.Errorf("create remote provider store: %w", err)
-	}
-
-	ldStore := &ldStoreProvider{
-		ContextStore:        contextStore,
-		RemoteProviderStore: remoteProviderStore,
-	}
-
-	docLoader, err := createJSONLDDocumentLoader(ldStore)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load Orb contexts: %w", err)
-	}
-
-	return docLoader, nil
-}
-
-func createJSONLDDocumentLoader(ldStore *ldStoreProvider) (jsonld.DocumentLoader, error) {
-	loaderOpts := []ld.DocumentLoaderOpts{ld.WithExtraContexts(ldcontext.MustGetAll()...)}
-
-	loader, err := ld.NewDocumentLoader(ldStore, loaderOpts...)
-	if err != nil {
-		return nil, fmt.Errorf("new document loader: %w", err)
-	}
-
-	return loader, nil
-}
-
 func getVCParameters(proof verifiable.Proof) (domain string, created time.Time, err error) {
 	d, ok := proof["domain"]
 	if !ok {
@@ -308,19 +267,6 @@ func getVCParameters(proof verifiable.Proof) (domain string, created time.Time,
 	return d.(string), createdTime, nil //nolint:forcetypeassert
 }
 
-type ldStoreProvider struct {
-	ContextStore        ldstore.ContextStore
-	RemoteProviderStore ldstore.RemoteProviderStore
-}
-
-func (p *ldStoreProvider) JSONLDContextStore() ldstore.ContextStore {
-	return p.ContextStore
-}
-
-func (p *ldStoreProvider) JSONLDRemoteProviderStore() ldstore.RemoteProviderStore {
-	return p.RemoteProviderStore
-}
-
 type verifyResult struct {
 	Domain    string   `json:"domain"`
 	Found     bool     `json:"found"`
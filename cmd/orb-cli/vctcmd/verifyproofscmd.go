@@ -0,0 +1,299 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vctcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	vdrweb "github.com/hyperledger/aries-framework-go/pkg/vdr/web"
+	jsonld "github.com/piprate/json-gold/ld"
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/anchor/util"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+const (
+	proofStatusPass = "PASS"
+	proofStatusFail = "FAIL"
+
+	didWebPrefix = "did:web:"
+)
+
+func newVerifyProofsCmd(webVDR webVDR) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-proofs",
+		Short: "Re-verifies the proofs of an anchor credential.",
+		Long: `Re-verifies each proof of an anchor credential using the public keys of the witnesses and reports ` +
+			`the result of each proof along with the data covered by the proofs. The credential is loaded either from ` +
+			`a file or from CAS. The public keys are taken from the provided DID documents or, if not provided, ` +
+			`they are resolved using did:web. A running Orb server isn't required if the credential and DID documents ` +
+			`are provided as files. For example: vct verify-proofs --vc-file ./anchor.json ` +
+			`--did-document ./witness1-did.json --did-document ./witness2-did.json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeVerifyProofs(cmd, webVDR)
+		},
+	}
+
+	addVerifyProofsFlags(cmd)
+
+	return cmd
+}
+
+func executeVerifyProofs(cmd *cobra.Command, webVDR webVDR) error {
+	didDocFiles := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, didDocumentFlagName, didDocumentEnvKey)
+
+	verboseStr := cmdutil.GetUserSetOptionalVarFromString(cmd, verboseFlagName, verboseEnvKey)
+	verbose := verboseStr != ""
+
+	docLoader, err := common.NewDocumentLoader()
+	if err != nil {
+		return fmt.Errorf("new document loader: %w", err)
+	}
+
+	vc, err := loadVC(cmd, docLoader, verbose)
+	if err != nil {
+		return err
+	}
+
+	resolver, err := newDIDResolver(cmd, webVDR, didDocFiles)
+	if err != nil {
+		return err
+	}
+
+	result, err := verifyProofs(vc, verifiable.NewVDRKeyResolver(resolver).PublicKeyFetcher(), docLoader)
+	if err != nil {
+		return err
+	}
+
+	resultBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal verify results: %w", err)
+	}
+
+	common.Println(cmd.OutOrStdout(), string(resultBytes))
+
+	var failed int
+
+	for _, p := range result.Proofs {
+		if p.Status != proofStatusPass {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d proof(s) failed verification", failed, len(result.Proofs))
+	}
+
+	return nil
+}
+
+func loadVC(cmd *cobra.Command, docLoader jsonld.DocumentLoader, verbose bool) (*verifiable.Credential, error) {
+	vcFile := cmdutil.GetUserSetOptionalVarFromString(cmd, vcFileFlagName, vcFileEnvKey)
+	if vcFile == "" {
+		casURL, anchorHash, _, _, err := getVerifyArgs(cmd)
+		if err != nil {
+			return nil, err
+		}
+
+		return getVC(cmd, anchorHash, casURL, docLoader, verbose)
+	}
+
+	vcBytes, err := os.ReadFile(vcFile) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("read file %s: %w", vcFile, err)
+	}
+
+	return parseVC(vcBytes, docLoader)
+}
+
+// parseVC parses the given bytes, which may either be an anchor linkset or a verifiable credential.
+// The proofs aren't checked since they are verified individually.
+func parseVC(vcBytes []byte, docLoader jsonld.DocumentLoader) (*verifiable.Credential, error) {
+	opts := []verifiable.CredentialOpt{
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(docLoader),
+	}
+
+	anchorLinkset := &linkset.Linkset{}
+
+	err := json.Unmarshal(vcBytes, anchorLinkset)
+	if err == nil && anchorLinkset.Link() != nil {
+		vc, e := util.VerifiableCredentialFromAnchorLink(anchorLinkset.Link(), opts...)
+		if e != nil {
+			return nil, fmt.Errorf("get verifiable credential from anchor link: %w", e)
+		}
+
+		return vc, nil
+	}
+
+	vc, err := verifiable.ParseCredential(vcBytes, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("parse verifiable credential: %w", err)
+	}
+
+	return vc, nil
+}
+
+// verifyProofs verifies each proof of the given credential separately so that the result
+// of each proof may be reported.
+func verifyProofs(vc *verifiable.Credential, pkf verifiable.PublicKeyFetcher,
+	docLoader jsonld.DocumentLoader,
+) (*verifyProofsResult, error) {
+	unsignedVC := *vc
+	unsignedVC.Proofs = nil
+
+	coveredData, err := unsignedVC.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal VC: %w", err)
+	}
+
+	result := &verifyProofsResult{
+		ID:          vc.ID,
+		CoveredData: coveredData,
+		Proofs:      make([]*proofResult, len(vc.Proofs)),
+	}
+
+	for i, proof := range vc.Proofs {
+		result.Proofs[i] = verifySingleProof(unsignedVC, proof, pkf, docLoader)
+	}
+
+	return result, nil
+}
+
+func verifySingleProof(vc verifiable.Credential, proof verifiable.Proof, pkf verifiable.PublicKeyFetcher,
+	docLoader jsonld.DocumentLoader,
+) *proofResult {
+	result := &proofResult{
+		Type:               getProofField(proof, "type"),
+		VerificationMethod: getProofField(proof, "verificationMethod"),
+		Created:            getProofField(proof, "created"),
+		Domain:             getProofField(proof, "domain"),
+		Status:             proofStatusFail,
+	}
+
+	vc.Proofs = []verifiable.Proof{proof}
+
+	vcBytes, err := vc.MarshalJSON()
+	if err != nil {
+		result.Error = fmt.Errorf("marshal VC: %w", err).Error()
+
+		return result
+	}
+
+	_, err = verifiable.ParseCredential(vcBytes,
+		verifiable.WithPublicKeyFetcher(pkf),
+		verifiable.WithJSONLDDocumentLoader(docLoader),
+	)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	result.Status = proofStatusPass
+
+	return result
+}
+
+func getProofField(proof verifiable.Proof, field string) string {
+	v, ok := proof[field].(string)
+	if !ok {
+		return ""
+	}
+
+	return v
+}
+
+func addVerifyProofsFlags(cmd *cobra.Command) {
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(vcFileFlagName, "", "", vcFileFlagUsage)
+	cmd.Flags().StringP(casURLFlagName, "", "", casURLFlagUsage)
+	cmd.Flags().StringP(anchorHashFlagName, "", "", anchorHashFlagUsage)
+	cmd.Flags().StringArrayP(didDocumentFlagName, "", nil, didDocumentFlagUsage)
+	cmd.Flags().StringP(verboseFlagName, "", "", verboseFlagUsage)
+}
+
+// didResolver resolves DIDs from the DID documents provided on the command line. If a DID document
+// wasn't provided then the DID is resolved using did:web.
+type didResolver struct {
+	docs       map[string]*did.DocResolution
+	webVDR     webVDR
+	httpClient *http.Client
+}
+
+type webVDR interface {
+	Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error)
+}
+
+func newDIDResolver(cmd *cobra.Command, webVDR webVDR, didDocFiles []string) (*didResolver, error) {
+	httpClient, err := common.NewHTTPClient(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("new HTTP client: %w", err)
+	}
+
+	r := &didResolver{
+		docs:       make(map[string]*did.DocResolution),
+		webVDR:     webVDR,
+		httpClient: httpClient,
+	}
+
+	for _, file := range didDocFiles {
+		docBytes, err := os.ReadFile(file) //nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("read DID document file %s: %w", file, err)
+		}
+
+		doc, err := did.ParseDocument(docBytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse DID document file %s: %w", file, err)
+		}
+
+		r.docs[doc.ID] = &did.DocResolution{DIDDocument: doc}
+	}
+
+	return r, nil
+}
+
+func (r *didResolver) Resolve(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if doc, ok := r.docs[didID]; ok {
+		return doc, nil
+	}
+
+	if !strings.HasPrefix(didID, didWebPrefix) {
+		return nil, errors.New("DID document not provided and DID is not a did:web: " + didID)
+	}
+
+	return r.webVDR.Read(didID, append(opts, vdrapi.WithOption(vdrweb.HTTPClientOpt, r.httpClient))...)
+}
+
+type verifyProofsResult struct {
+	ID          string          `json:"id"`
+	CoveredData json.RawMessage `json:"coveredData"`
+	Proofs      []*proofResult  `json:"proofs"`
+}
+
+type proofResult struct {
+	Type               string `json:"type"`
+	VerificationMethod string `json:"verificationMethod"`
+	Created            string `json:"created,omitempty"`
+	Domain             string `json:"domain,omitempty"`
+	Status             string `json:"status"`
+	Error              string `json:"error,omitempty"`
+}
@@ -0,0 +1,435 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vctcmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/models/signature/signer"
+	"github.com/hyperledger/aries-framework-go/component/models/signature/suite"
+	"github.com/hyperledger/aries-framework-go/component/models/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/component/models/signature/suite/jsonwebsignature2020"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose/jwk/jwksupport"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/pkg/vcsigner"
+)
+
+const (
+	witness1DID = "did:web:orb.domain1.com"
+	witness2DID = "did:web:orb.domain2.com"
+	witness1Key = witness1DID + "#key1"
+	witness2Key = witness2DID + "#key2"
+)
+
+func TestVerifyProofsCmd(t *testing.T) {
+	pubKey1, privKey1, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pubKey2, privKey2, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+
+	didDoc1File := writeFile(t, dir, "witness1-did.json", newEd25519DIDDoc(t, witness1DID, witness1Key, pubKey1))
+	didDoc2File := writeFile(t, dir, "witness2-did.json", newJWKDIDDoc(t, witness2DID, witness2Key, pubKey2))
+
+	vcBytes := newSignedVC(t,
+		&testWitness{keyID: witness1Key, privKey: privKey1, sigType: vcsigner.Ed25519Signature2018},
+		&testWitness{keyID: witness2Key, privKey: privKey2, sigType: vcsigner.JSONWebSignature2020},
+	)
+
+	t.Run("success", func(t *testing.T) {
+		vcFile := writeFile(t, dir, "vc.json", vcBytes)
+
+		cmd := newVerifyProofsCmd(&mockWebVDR{})
+
+		var args []string
+		args = append(args, vcFileArg(vcFile)...)
+		args = append(args, didDocumentArg(didDoc1File)...)
+		args = append(args, didDocumentArg(didDoc2File)...)
+		cmd.SetArgs(args)
+
+		b := &bytes.Buffer{}
+		cmd.SetOut(b)
+
+		require.NoError(t, cmd.Execute())
+
+		result := &verifyProofsResult{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), result))
+
+		require.Equal(t, "https://orb.domain1.com/vc/1234", result.ID)
+		require.Contains(t, string(result.CoveredData), "hl:uEiBVxhvvWYBBP31xsXSpVqaNdXuvNqJKRFHTMz8v1CCV9Q")
+		require.NotContains(t, string(result.CoveredData), "proof")
+
+		require.Len(t, result.Proofs, 2)
+
+		require.Equal(t, proofStatusPass, result.Proofs[0].Status)
+		require.Equal(t, witness1Key, result.Proofs[0].VerificationMethod)
+		require.Equal(t, vcsigner.Ed25519Signature2018, result.Proofs[0].Type)
+		require.Equal(t, "https://orb.domain1.com", result.Proofs[0].Domain)
+		require.NotEmpty(t, result.Proofs[0].Created)
+		require.Empty(t, result.Proofs[0].Error)
+
+		require.Equal(t, proofStatusPass, result.Proofs[1].Status)
+		require.Equal(t, witness2Key, result.Proofs[1].VerificationMethod)
+		require.Equal(t, vcsigner.JSONWebSignature2020, result.Proofs[1].Type)
+	})
+
+	t.Run("tampered proof", func(t *testing.T) {
+		vcDoc := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(vcBytes, &vcDoc))
+
+		proofs, ok := vcDoc["proof"].([]interface{})
+		require.True(t, ok)
+
+		proof, ok := proofs[0].(map[string]interface{})
+		require.True(t, ok)
+
+		// Changing the creation time invalidates the signature.
+		proof["created"] = "2020-01-01T00:00:00Z"
+
+		tamperedBytes, err := json.Marshal(vcDoc)
+		require.NoError(t, err)
+
+		vcFile := writeFile(t, dir, "tampered-vc.json", tamperedBytes)
+
+		cmd := newVerifyProofsCmd(&mockWebVDR{})
+
+		var args []string
+		args = append(args, vcFileArg(vcFile)...)
+		args = append(args, didDocumentArg(didDoc1File)...)
+		args = append(args, didDocumentArg(didDoc2File)...)
+		cmd.SetArgs(args)
+
+		b := &bytes.Buffer{}
+		cmd.SetOut(b)
+
+		err = cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t, "1 of 2 proof(s) failed verification", err.Error())
+
+		result := &verifyProofsResult{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), result))
+
+		require.Len(t, result.Proofs, 2)
+
+		require.Equal(t, proofStatusFail, result.Proofs[0].Status)
+		require.Equal(t, "2020-01-01T00:00:00Z", result.Proofs[0].Created)
+		require.Contains(t, result.Proofs[0].Error, "check embedded proof")
+
+		require.Equal(t, proofStatusPass, result.Proofs[1].Status)
+	})
+
+	t.Run("resolve did:web", func(t *testing.T) {
+		didDoc, err := did.ParseDocument(newJWKDIDDoc(t, witness2DID, witness2Key, pubKey2))
+		require.NoError(t, err)
+
+		vcFile := writeFile(t, dir, "vc.json", vcBytes)
+
+		cmd := newVerifyProofsCmd(&mockWebVDR{
+			docs: map[string]*did.Doc{witness2DID: didDoc},
+		})
+
+		var args []string
+		args = append(args, vcFileArg(vcFile)...)
+		args = append(args, didDocumentArg(didDoc1File)...)
+		cmd.SetArgs(args)
+
+		b := &bytes.Buffer{}
+		cmd.SetOut(b)
+
+		require.NoError(t, cmd.Execute())
+
+		result := &verifyProofsResult{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), result))
+
+		require.Len(t, result.Proofs, 2)
+		require.Equal(t, proofStatusPass, result.Proofs[0].Status)
+		require.Equal(t, proofStatusPass, result.Proofs[1].Status)
+	})
+
+	t.Run("DID document not provided", func(t *testing.T) {
+		vcFile := writeFile(t, dir, "vc.json", vcBytes)
+
+		cmd := newVerifyProofsCmd(&mockWebVDR{})
+
+		var args []string
+		args = append(args, vcFileArg(vcFile)...)
+		args = append(args, didDocumentArg(didDoc1File)...)
+		cmd.SetArgs(args)
+
+		b := &bytes.Buffer{}
+		cmd.SetOut(b)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t, "1 of 2 proof(s) failed verification", err.Error())
+
+		result := &verifyProofsResult{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), result))
+
+		require.Len(t, result.Proofs, 2)
+		require.Equal(t, proofStatusPass, result.Proofs[0].Status)
+		require.Equal(t, proofStatusFail, result.Proofs[1].Status)
+		require.Contains(t, result.Proofs[1].Error, "resolve DID "+witness2DID)
+	})
+
+	t.Run("load from CAS", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(anchorLinkset))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		cmd := newVerifyProofsCmd(&mockWebVDR{})
+
+		var args []string
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, anchorHashArg("uEiDuIicNljP8PoHJk6_aA7w1d4U3FAvDMfF7Dsh7fkw3Wg")...)
+		args = append(args, didDocumentArg(didDoc1File)...)
+		cmd.SetArgs(args)
+
+		b := &bytes.Buffer{}
+		cmd.SetOut(b)
+
+		// The DID documents of the witnesses in the anchor linkset aren't provided and can't be resolved.
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed verification")
+
+		result := &verifyProofsResult{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), result))
+		require.NotEmpty(t, result.Proofs)
+	})
+
+	t.Run("missing cas-url arg", func(t *testing.T) {
+		cmd := newVerifyProofsCmd(&mockWebVDR{})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither cas-url (command line flag) nor ORB_CAS_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("VC file not found", func(t *testing.T) {
+		cmd := newVerifyProofsCmd(&mockWebVDR{})
+
+		var args []string
+		args = append(args, vcFileArg(filepath.Join(dir, "invalid.json"))...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read file")
+	})
+
+	t.Run("invalid VC", func(t *testing.T) {
+		cmd := newVerifyProofsCmd(&mockWebVDR{})
+
+		var args []string
+		args = append(args, vcFileArg(writeFile(t, dir, "invalid-vc.json", []byte(`{}`)))...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse verifiable credential")
+	})
+
+	t.Run("invalid DID document", func(t *testing.T) {
+		cmd := newVerifyProofsCmd(&mockWebVDR{})
+
+		var args []string
+		args = append(args, vcFileArg(writeFile(t, dir, "vc.json", vcBytes))...)
+		args = append(args, didDocumentArg(writeFile(t, dir, "invalid-did.json", []byte(`{}`)))...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse DID document file")
+	})
+
+	t.Run("DID document file not found", func(t *testing.T) {
+		cmd := newVerifyProofsCmd(&mockWebVDR{})
+
+		var args []string
+		args = append(args, vcFileArg(writeFile(t, dir, "vc.json", vcBytes))...)
+		args = append(args, didDocumentArg(filepath.Join(dir, "invalid.json"))...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read DID document file")
+	})
+}
+
+func TestDIDResolver(t *testing.T) {
+	r, err := newDIDResolver(GetCmd(), &mockWebVDR{}, nil)
+	require.NoError(t, err)
+
+	_, err = r.Resolve("did:orb:uAAA:1234")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "DID document not provided and DID is not a did:web")
+}
+
+type mockWebVDR struct {
+	docs map[string]*did.Doc
+}
+
+func (m *mockWebVDR) Read(didID string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	doc, ok := m.docs[didID]
+	if !ok {
+		return nil, vdrapi.ErrNotFound
+	}
+
+	return &did.DocResolution{DIDDocument: doc}, nil
+}
+
+type testWitness struct {
+	keyID   string
+	privKey ed25519.PrivateKey
+	sigType string
+}
+
+func newSignedVC(t *testing.T, witnesses ...*testWitness) []byte {
+	t.Helper()
+
+	docLoader, err := common.NewDocumentLoader()
+	require.NoError(t, err)
+
+	vc, err := verifiable.ParseCredential([]byte(testVC),
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(docLoader),
+	)
+	require.NoError(t, err)
+
+	for _, w := range witnesses {
+		s := &ed25519Signer{privKey: w.privKey}
+
+		var signatureSuite signer.SignatureSuite
+
+		if w.sigType == vcsigner.JSONWebSignature2020 {
+			signatureSuite = jsonwebsignature2020.New(suite.WithSigner(s))
+		} else {
+			signatureSuite = ed25519signature2018.New(suite.WithSigner(s))
+		}
+
+		created := time.Now()
+
+		require.NoError(t, vc.AddLinkedDataProof(&verifiable.LinkedDataProofContext{
+			SignatureType:           w.sigType,
+			Suite:                   signatureSuite,
+			SignatureRepresentation: verifiable.SignatureJWS,
+			Created:                 &created,
+			VerificationMethod:      w.keyID,
+			Domain:                  "https://orb.domain1.com",
+			Purpose:                 "assertionMethod",
+		}, jsonld.WithDocumentLoader(docLoader)))
+	}
+
+	vcBytes, err := vc.MarshalJSON()
+	require.NoError(t, err)
+
+	return vcBytes
+}
+
+func newEd25519DIDDoc(t *testing.T, didID, keyID string, pubKey ed25519.PublicKey) []byte {
+	t.Helper()
+
+	vm := did.NewVerificationMethodFromBytes(keyID, "Ed25519VerificationKey2018", didID, pubKey)
+
+	return newDIDDoc(t, didID, vm)
+}
+
+func newJWKDIDDoc(t *testing.T, didID, keyID string, pubKey ed25519.PublicKey) []byte {
+	t.Helper()
+
+	j, err := jwksupport.JWKFromKey(pubKey)
+	require.NoError(t, err)
+
+	vm, err := did.NewVerificationMethodFromJWK(keyID, "JsonWebKey2020", didID, j)
+	require.NoError(t, err)
+
+	return newDIDDoc(t, didID, vm)
+}
+
+func newDIDDoc(t *testing.T, didID string, vm *did.VerificationMethod) []byte {
+	t.Helper()
+
+	doc := &did.Doc{
+		Context:            []string{did.ContextV1},
+		ID:                 didID,
+		VerificationMethod: []did.VerificationMethod{*vm},
+		AssertionMethod:    []did.Verification{*did.NewReferencedVerification(vm, did.AssertionMethod)},
+	}
+
+	docBytes, err := doc.JSONBytes()
+	require.NoError(t, err)
+
+	return docBytes
+}
+
+func writeFile(t *testing.T, dir, name string, content []byte) string {
+	t.Helper()
+
+	file := filepath.Join(dir, name)
+
+	require.NoError(t, os.WriteFile(file, content, 0o600))
+
+	return file
+}
+
+func vcFileArg(value string) []string {
+	return []string{flag + vcFileFlagName, value}
+}
+
+func didDocumentArg(value string) []string {
+	return []string{flag + didDocumentFlagName, value}
+}
+
+type ed25519Signer struct {
+	privKey ed25519.PrivateKey
+}
+
+func (s *ed25519Signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.privKey, data), nil
+}
+
+func (s *ed25519Signer) Alg() string {
+	return "EdDSA"
+}
+
+const testVC = `{
+  "@context": [
+    "https://www.w3.org/2018/credentials/v1",
+    "https://w3id.org/activityanchors/v1",
+    "https://w3id.org/security/suites/jws-2020/v1"
+  ],
+  "credentialSubject": "hl:uEiBVxhvvWYBBP31xsXSpVqaNdXuvNqJKRFHTMz8v1CCV9Q:uoQ-BeEJpcGZzOi8vYmFma3JlaWU0eHBidXZmbTZwdnN4empscXJybm1wcnlobmZrNjdhaHZ5aGJ2eXQ1dXZoeGZmaWR0cGU",
+  "id": "https://orb.domain1.com/vc/1234",
+  "issuanceDate": "2022-07-21T20:59:51.8316399Z",
+  "issuer": "https://orb.domain1.com",
+  "type": [
+    "VerifiableCredential",
+    "AnchorCredential"
+  ]
+}`
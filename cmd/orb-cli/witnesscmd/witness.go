@@ -174,12 +174,13 @@ func cmd() *cobra.Command { //nolint:funlen,gocyclo,cyclop,gocognit
 
 			switch action {
 			case inviteWitnessAction:
-				req := vocab.NewInviteActivity(
-					vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI)),
-					vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(toActor.ID().URL()))),
+				req, e := vocab.NewInviteWitnessActivity(toActor.ID().URL(),
 					vocab.WithActor(actor.ID().URL()),
 					vocab.WithTo(toIRI),
 				)
+				if e != nil {
+					return fmt.Errorf("create 'Invite' activity: %w", e)
+				}
 
 				reqBytes, err = json.Marshal(req)
 				if err != nil {
@@ -187,15 +188,16 @@ func cmd() *cobra.Command { //nolint:funlen,gocyclo,cyclop,gocognit
 				}
 
 			case undoAction:
+				invite, e := vocab.NewInviteWitnessActivity(toActor.ID().URL(),
+					vocab.WithID(inviteWitnessIRI),
+					vocab.WithActor(actor.ID().URL()),
+				)
+				if e != nil {
+					return fmt.Errorf("create 'Invite' activity: %w", e)
+				}
+
 				undo := vocab.NewUndoActivity(
-					vocab.NewObjectProperty(vocab.WithActivity(
-						vocab.NewInviteActivity(
-							vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI)),
-							vocab.WithID(inviteWitnessIRI),
-							vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(toActor.ID().URL()))),
-							vocab.WithActor(actor.ID().URL()),
-						),
-					)),
+					vocab.NewObjectProperty(vocab.WithActivity(invite)),
 					vocab.WithActor(actor.ID().URL()),
 					vocab.WithTo(toIRI),
 				)
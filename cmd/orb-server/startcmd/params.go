@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package startcmd
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net/url"
@@ -19,10 +20,14 @@ import (
 
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
 	aphandler "github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/handler/credential"
 	"github.com/trustbloc/orb/pkg/context/opqueue"
 	"github.com/trustbloc/orb/pkg/datauri"
 	"github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/httpserver"
 	"github.com/trustbloc/orb/pkg/httpserver/auth"
 	"github.com/trustbloc/orb/pkg/observability/tracing"
 )
@@ -111,8 +116,12 @@ const (
 	defaultActivityPubPageSize              = 50
 	defaultNodeInfoRefreshInterval          = 15 * time.Second
 	defaultIPFSTimeout                      = 20 * time.Second
+	defaultIPFSRateLimit                    = 0
+	defaultIPFSRateLimitBurst               = 10
 	defaultDatabaseTimeout                  = 10 * time.Second
 	defaultHTTPDialTimeout                  = 2 * time.Second
+	defaultHTTPTLSHandshakeTimeout          = 5 * time.Second
+	defaultHTTPResponseHeaderTimeout        = 15 * time.Second
 	defaultServerIdleTimeout                = 20 * time.Second
 	defaultServerReadHeaderTimeout          = 20 * time.Second
 	defaultHTTPTimeout                      = 20 * time.Second
@@ -136,6 +145,8 @@ const (
 	mqDefaultPublisherChannelPoolSize       = 25
 	mqDefaultPublisherConfirmDelivery       = true
 	mqDefaultObserverPoolSize               = 5
+	mqDefaultObserverCatchupConcurrency     = 0
+	mqDefaultObserverCatchupThreshold       = 0
 	mqDefaultOutboxPoolSize                 = 5
 	mqDefaultInboxPoolSize                  = 5
 	mqDefaultOpQueuePoolSize                = 5
@@ -149,12 +160,26 @@ const (
 	defaultActivityPubClientCacheExpiration = 10 * time.Minute
 	defaultActivityPubIRICacheSize          = 100
 	defaultActivityPubIRICacheExpiration    = time.Hour
+	defaultActivityPubStrictParsing         = false
+	defaultAnchorCredentialStrictValidation = true
+	defaultObserverValidatePreviousAnchors  = true
+	defaultActivityPubOutboxBatchMaxItems   = 10
+	defaultActivityPubOutboxDeliveryPool    = 0
+	defaultActivityPubOutboxDeliveryBatch   = 1
+	defaultActivityPubOutboxAtomMaxEntries  = 0
+	defaultActivityPubMaxEmbeddedAnchorSize = 64 * 1024
+	defaultActivityPubClientMaxPages        = 10000
+	defaultCircuitBreakerFailureThreshold   = 5
+	defaultCircuitBreakerCooldown           = 30 * time.Second
 	defaultFollowAuthType                   = acceptAllPolicy
 	defaultInviteWitnessAuthType            = acceptAllPolicy
 	defaultWitnessPolicyCacheExpiration     = 30 * time.Second
 	defaultDataURIMediaType                 = datauri.MediaTypeDataURIGzipBase64
+	defaultDataURIAutoThreshold             = datauri.DefaultAutoThreshold
+	defaultAnchorMaxPreviousAnchors         = 0
 	defaultAllowedOriginsCacheExpiration    = time.Minute
 	defaultAnchorRefPendingRecordLifespan   = 24 * time.Hour
+	defaultAnchorRefProcessedRecordTTL      = 30 * 24 * time.Hour
 
 	defaultTracingServiceName = "orb"
 
@@ -240,6 +265,20 @@ const (
 		"Defaults to 30s if not set. " +
 		commonEnvVarUsageText + anchorStatusInProcessGracePeriodEnvKey
 
+	circuitBreakerFailureThresholdFlagName  = "circuit-breaker-failure-threshold"
+	circuitBreakerFailureThresholdEnvKey    = "CIRCUIT_BREAKER_FAILURE_THRESHOLD"
+	circuitBreakerFailureThresholdFlagUsage = "The number of consecutive failures of requests to a remote host " +
+		"(WebCAS and ActivityPub actor fetches) after which requests to the host fail fast for a cooldown period. " +
+		"A value of 0 disables the circuit breaker. Defaults to 5 if not set. " +
+		commonEnvVarUsageText + circuitBreakerFailureThresholdEnvKey
+
+	circuitBreakerCooldownFlagName  = "circuit-breaker-cooldown"
+	circuitBreakerCooldownEnvKey    = "CIRCUIT_BREAKER_COOLDOWN"
+	circuitBreakerCooldownFlagUsage = "The amount of time that requests to a failing remote host fail fast before " +
+		"a single probe request is allowed. If the probe succeeds then requests to the host resume, otherwise " +
+		"the cooldown starts again. Defaults to 30s if not set. " +
+		commonEnvVarUsageText + circuitBreakerCooldownEnvKey
+
 	externalEndpointFlagName      = "external-endpoint"
 	externalEndpointFlagShorthand = "e"
 	externalEndpointFlagUsage     = "External endpoint that clients use to invoke services." +
@@ -268,6 +307,24 @@ const (
 		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
 	tlsCACertsEnvKey = "ORB_TLS_CACERTS"
 
+	tlsMinVersionFlagName  = "tls-min-version"
+	tlsMinVersionFlagUsage = "The minimum TLS version accepted when connecting to other servers." +
+		" Possible values [1.2] [1.3]. Defaults to 1.2 if not set. " + commonEnvVarUsageText + tlsMinVersionEnvKey
+	tlsMinVersionEnvKey = "ORB_TLS_MIN_VERSION"
+
+	tlsCipherSuitesFlagName  = "tls-cipher-suites"
+	tlsCipherSuitesFlagUsage = "Comma-Separated list of cipher suites which may be negotiated with other servers" +
+		" using TLS 1.2, for example TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384. Insecure cipher suites are rejected." +
+		" The TLS 1.3 cipher suites aren't configurable, so this option may not be used if the minimum TLS version" +
+		" is 1.3. If not set then the Go defaults are used. " + commonEnvVarUsageText + tlsCipherSuitesEnvKey
+	tlsCipherSuitesEnvKey = "ORB_TLS_CIPHER_SUITES"
+
+	tlsPeerCACertsFlagName  = "tls-peer-cacerts"
+	tlsPeerCACertsFlagUsage = "Comma-Separated list of paths to CA certificates. If set then the certificates of" +
+		" other servers are verified only against these CAs (the system certificate pool and " + tlsCACertsFlagName +
+		" are ignored), which is useful for a private federation. " + commonEnvVarUsageText + tlsPeerCACertsEnvKey
+	tlsPeerCACertsEnvKey = "ORB_TLS_PEER_CACERTS"
+
 	tlsCertificateFlagName      = "tls-certificate"
 	tlsCertificateFlagShorthand = "y"
 	tlsCertificateFlagUsage     = "TLS certificate for ORB server. " + commonEnvVarUsageText + tlsCertificateLEnvKey
@@ -311,6 +368,27 @@ const (
 		"If the IPFS node is set to ipfs.io, then this setting will be disabled since ipfs.io does not support " +
 		"writes. Supported options: false, true. Defaults to false if not set. " + commonEnvVarUsageText + localCASReplicateInIPFSEnvKey
 
+	casListHashesEnabledFlagName  = "cas-list-hashes-enabled"
+	casListHashesEnabledEnvKey    = "CAS_LIST_HASHES_ENABLED"
+	casListHashesEnabledFlagUsage = `Set to "true" to expose the /services/orb/cas-hashes endpoint which lists the ` +
+		`resource hashes held in the local CAS. The endpoint requires authorization and is only available if the ` +
+		`CAS type is local. Defaults to false. ` + commonEnvVarUsageText + casListHashesEnabledEnvKey
+
+	casWriteEnabledFlagName  = "cas-write-enabled"
+	casWriteEnabledEnvKey    = "CAS_WRITE_ENABLED"
+	casWriteEnabledFlagUsage = `Set to "true" to accept HTTP PUT requests at the WebCAS endpoint, which store the ` +
+		`content in the request body under its resource hash (e.g. in order to populate a mirror). Content is only ` +
+		`stored if its hash matches the hash in the request path. Only bearer token authorization is supported, so ` +
+		`write tokens should be configured for the WebCAS path. Only available if the CAS type is local. ` +
+		`Defaults to false. ` + commonEnvVarUsageText + casWriteEnabledEnvKey
+
+	casStoreRemoteContentFlagName  = "cas-store-remote-content"
+	casStoreRemoteContentEnvKey    = "CAS_STORE_REMOTE_CONTENT"
+	casStoreRemoteContentFlagUsage = "If enabled, content which is retrieved from a remote server (WebCAS or IPFS) " +
+		"is stored in the local CAS so that subsequent requests are served locally. Read-mostly mirror nodes may " +
+		"disable this setting in order to limit disk growth. Supported options: false, true. Defaults to true if not set. " +
+		commonEnvVarUsageText + casStoreRemoteContentEnvKey
+
 	mqURLFlagName      = "mq-url"
 	mqURLFlagShorthand = "q"
 	mqURLEnvKey        = "MQ_URL"
@@ -322,6 +400,20 @@ const (
 	mqObserverPoolFlagUsage     = "The size of the observer queue subscriber pool. If not specified then the default size will be used. " +
 		commonEnvVarUsageText + mqObserverPoolEnvKey
 
+	mqObserverCatchupConcurrencyFlagName  = "mq-observer-catchup-concurrency"
+	mqObserverCatchupConcurrencyEnvKey    = "MQ_OBSERVER_CATCHUP_CONCURRENCY"
+	mqObserverCatchupConcurrencyFlagUsage = "The number of observer messages that may be processed concurrently " +
+		"while catching up on the backlog of messages that accumulated while the server was down. Once the backlog " +
+		"is drained, the observer reverts to the size of the observer subscriber pool. Catch-up mode is disabled " +
+		"if the value is not greater than the observer pool size (default). " +
+		commonEnvVarUsageText + mqObserverCatchupConcurrencyEnvKey
+
+	mqObserverCatchupThresholdFlagName  = "mq-observer-catchup-threshold"
+	mqObserverCatchupThresholdEnvKey    = "MQ_OBSERVER_CATCHUP_THRESHOLD"
+	mqObserverCatchupThresholdFlagUsage = "The number of outstanding observer messages below which the backlog is " +
+		"considered to be drained. If not specified then the observer pool size is used. " +
+		commonEnvVarUsageText + mqObserverCatchupThresholdEnvKey
+
 	mqOutboxPoolFlagName  = "mq-outbox-pool"
 	mqOutboxPoolEnvKey    = "MQ_OUTBOX_POOL"
 	mqOutboxPoolFlagUsage = "The size of the outbox queue subscriber pool. If not specified then the default size is used. " +
@@ -465,6 +557,82 @@ const (
 	anchorCredentialDomainFlagUsage     = "Anchor credential domain (required). " +
 		commonEnvVarUsageText + anchorCredentialDomainEnvKey
 
+	anchorCredentialStrictValidationFlagName  = "anchor-credential-strict-validation"
+	anchorCredentialStrictValidationEnvKey    = "ANCHOR_CREDENTIAL_STRICT_VALIDATION"
+	anchorCredentialStrictValidationFlagUsage = "If enabled then anchor credentials received from other servers are " +
+		"rejected if they contain fields that aren't defined in the JSON-LD context. Disable only to interoperate with " +
+		"servers that produce slightly non-conformant credentials. Defaults to true. " +
+		commonEnvVarUsageText + anchorCredentialStrictValidationEnvKey
+
+	anchorCredentialMaxAgeFlagName  = "anchor-credential-max-age"
+	anchorCredentialMaxAgeEnvKey    = "ANCHOR_CREDENTIAL_MAX_AGE"
+	anchorCredentialMaxAgeFlagUsage = "The maximum age of an anchor credential announced by another server. " +
+		"Newly announced anchors whose credential was issued earlier than this are rejected. Parent anchors that are " +
+		"retrieved in order to process a new anchor are not subject to this check. Defaults to 0 (disabled). " +
+		commonEnvVarUsageText + anchorCredentialMaxAgeEnvKey
+
+	anchorCredentialVerifyLineageFlagName  = "anchor-credential-verify-lineage"
+	anchorCredentialVerifyLineageEnvKey    = "ANCHOR_CREDENTIAL_VERIFY_LINEAGE"
+	anchorCredentialVerifyLineageFlagUsage = "If enabled then, when the parents of an anchor received from another " +
+		"server are processed, the anchor is rejected unless its parents are exactly the previous anchors declared " +
+		"by its items. Defaults to false. " + commonEnvVarUsageText + anchorCredentialVerifyLineageEnvKey
+
+	anchorCredentialParentResolveTimeoutFlagName  = "anchor-credential-parent-resolve-timeout"
+	anchorCredentialParentResolveTimeoutEnvKey    = "ANCHOR_CREDENTIAL_PARENT_RESOLVE_TIMEOUT"
+	anchorCredentialParentResolveTimeoutFlagUsage = "The maximum amount of time to wait for a single parent of an " +
+		"anchor received from another server to be resolved from CAS. For example, '10s' for a 10 second timeout. " +
+		"Defaults to 0 (no timeout). " + commonEnvVarUsageText + anchorCredentialParentResolveTimeoutEnvKey
+
+	anchorCredentialParentTraversalTimeoutFlagName  = "anchor-credential-parent-traversal-timeout"
+	anchorCredentialParentTraversalTimeoutEnvKey    = "ANCHOR_CREDENTIAL_PARENT_TRAVERSAL_TIMEOUT"
+	anchorCredentialParentTraversalTimeoutFlagUsage = "The maximum amount of time to spend resolving all of the " +
+		"unprocessed ancestors (parents, grandparents, etc.) of an anchor received from another server. " +
+		"For example, '1m' for a one minute timeout. Defaults to 0 (no timeout). " +
+		commonEnvVarUsageText + anchorCredentialParentTraversalTimeoutEnvKey
+
+	anchorCredentialPublishQueueSizeFlagName  = "anchor-credential-publish-queue-size"
+	anchorCredentialPublishQueueSizeEnvKey    = "ANCHOR_CREDENTIAL_PUBLISH_QUEUE_SIZE"
+	anchorCredentialPublishQueueSizeFlagUsage = "The maximum number of anchors received from other servers which may " +
+		"be published for processing concurrently. When the limit is reached, an anchor waits for up to the " +
+		"publish queue wait time and, if it's still not published, the anchor event is rejected with a transient " +
+		"error so that it's retried. Defaults to 0 (no limit). " + commonEnvVarUsageText + anchorCredentialPublishQueueSizeEnvKey
+
+	anchorCredentialPublishQueueWaitFlagName  = "anchor-credential-publish-queue-wait"
+	anchorCredentialPublishQueueWaitEnvKey    = "ANCHOR_CREDENTIAL_PUBLISH_QUEUE_WAIT"
+	anchorCredentialPublishQueueWaitFlagUsage = "The maximum amount of time that an anchor waits for a slot in the " +
+		"publish queue (see anchor-credential-publish-queue-size). If 0 (default) then a transient error is returned " +
+		"immediately when the queue is full. If negative then the anchor waits until a slot becomes available. " +
+		commonEnvVarUsageText + anchorCredentialPublishQueueWaitEnvKey
+
+	anchorCredentialAcceptedProfilesFlagName  = "anchor-credential-accepted-profiles"
+	anchorCredentialAcceptedProfilesEnvKey    = "ANCHOR_CREDENTIAL_ACCEPTED_PROFILES"
+	anchorCredentialAcceptedProfilesFlagUsage = "The profiles (anchor formats) of anchors received from other servers " +
+		"which are accepted, for example, https://w3id.org/orb#v0. Each profile must be supported by this server. " +
+		"Anchors with any other profile are rejected. If not specified then all supported profiles are accepted. " +
+		commonEnvVarUsageText + anchorCredentialAcceptedProfilesEnvKey
+
+	anchorCredentialDuplicateParentPolicyFlagName  = "anchor-credential-duplicate-parent-policy"
+	anchorCredentialDuplicateParentPolicyEnvKey    = "ANCHOR_CREDENTIAL_DUPLICATE_PARENT_POLICY"
+	anchorCredentialDuplicateParentPolicyFlagUsage = "The policy for handling an anchor whose parents contain " +
+		"duplicates. Possible values are: dedup (ignore the duplicates), warn (ignore the duplicates and log a warning) " +
+		"and reject (reject the anchor). Defaults to dedup. " +
+		commonEnvVarUsageText + anchorCredentialDuplicateParentPolicyEnvKey
+
+	anchorCredentialFetchPolicyFlagName  = "anchor-credential-fetch-policy"
+	anchorCredentialFetchPolicyEnvKey    = "ANCHOR_CREDENTIAL_FETCH_POLICY"
+	anchorCredentialFetchPolicyFlagUsage = "The policy which determines where the anchor linkset of an anchor event " +
+		"received from another server is taken from. Possible values are: prefer-embedded (use the anchor linkset " +
+		"embedded in the anchor event, if present) and always-fetch (ignore the embedded anchor linkset and always " +
+		"fetch it from CAS). Defaults to prefer-embedded. " +
+		commonEnvVarUsageText + anchorCredentialFetchPolicyEnvKey
+
+	anchorImportEnabledFlagName  = "anchor-import-enabled"
+	anchorImportEnabledEnvKey    = "ANCHOR_IMPORT_ENABLED"
+	anchorImportEnabledFlagUsage = `Set to "true" to expose the /anchors/import endpoint which accepts anchor linksets ` +
+		`produced by another system (e.g. during a migration). An imported anchor is validated in the same way as an ` +
+		`anchor received from another server, stored in the local CAS and processed. The endpoint requires ` +
+		`authorization. Defaults to false. ` + commonEnvVarUsageText + anchorImportEnabledEnvKey
+
 	allowedOriginsFlagName      = "allowed-origins"
 	allowedOriginsEnvKey        = "ALLOWED_ORIGINS"
 	allowedOriginsFlagShorthand = "o"
@@ -494,6 +662,13 @@ const (
 		"maximum witness response time + clock skew time" +
 		"(default 12m). " + commonEnvVarUsageText + witnessStoreExpiryPeriodEnvKey
 
+	witnessOfferLifetimeFlagName  = "witness-offer-lifetime"
+	witnessOfferLifetimeEnvKey    = "WITNESS_OFFER_LIFETIME"
+	witnessOfferLifetimeFlagUsage = "The lifetime of a witness offer which doesn't specify an end time. " +
+		"Expired offers are no longer acted upon and are periodically removed from the witness offer store. " +
+		"Defaults to the maximum witness response time (max-witness-delay). " +
+		commonEnvVarUsageText + witnessOfferLifetimeEnvKey
+
 	signWithLocalWitnessFlagName      = "sign-with-local-witness"
 	signWithLocalWitnessEnvKey        = "SIGN_WITH_LOCAL_WITNESS"
 	signWithLocalWitnessFlagShorthand = "f"
@@ -520,6 +695,41 @@ const (
 	enableDidDiscoveryUsage    = `Set to "true" to enable did discovery. ` +
 		commonEnvVarUsageText + enableDidDiscoveryEnvKey
 
+	enableProcessedAnchorLogFlagName = "enable-processed-anchor-log"
+	enableProcessedAnchorLogEnvKey   = "PROCESSED_ANCHOR_LOG_ENABLED"
+	enableProcessedAnchorLogUsage    = `Set to "true" to enable the processed-anchor log. ` +
+		`If enabled then the hashlink and processing time of each successfully processed anchor is appended to a ` +
+		`persistent log which may be used for auditing and recovery. Defaults to false. ` +
+		commonEnvVarUsageText + enableProcessedAnchorLogEnvKey
+
+	observerNamespacesFlagName  = "observer-namespaces"
+	observerNamespacesEnvKey    = "OBSERVER_NAMESPACES"
+	observerNamespacesFlagUsage = "The DID namespaces that are recognized by the Observer. Anchors for any other " +
+		"namespace are skipped and counted in the skipped-anchor metric. Each namespace must be served by this node. " +
+		"If not set then anchors are processed for all namespaces that are served by this node. " +
+		commonEnvVarUsageText + observerNamespacesEnvKey
+
+	observerValidatePreviousAnchorsFlagName  = "observer-validate-previous-anchors"
+	observerValidatePreviousAnchorsEnvKey    = "OBSERVER_VALIDATE_PREVIOUS_ANCHORS"
+	observerValidatePreviousAnchorsFlagUsage = "If enabled then the Observer rejects an anchor if any of its DID " +
+		"operations references a 'previous' anchor that doesn't contain a prior operation for the same DID suffix. " +
+		"This adds the cost of reading the previous anchors. Defaults to true. " +
+		commonEnvVarUsageText + observerValidatePreviousAnchorsEnvKey
+
+	webCASDigestHeaderEnabledFlagName = "webcas-digest-header-enabled"
+	webCASDigestHeaderEnabledEnvKey   = "WEBCAS_DIGEST_HEADER_ENABLED"
+	webCASDigestHeaderEnabledUsage    = `Set to "false" to disable the Digest header on WebCAS responses. ` +
+		`If enabled then a SHA-256 digest of the content is returned in the Digest header so that ` +
+		`clients may verify the content. Defaults to true. ` +
+		commonEnvVarUsageText + webCASDigestHeaderEnabledEnvKey
+
+	webCASPathFlagName  = "webcas-path"
+	webCASPathEnvKey    = "WEBCAS_PATH"
+	webCASPathFlagUsage = "The path of the WebCAS endpoint, for example /orb/cas if the server is deployed behind " +
+		"a reverse proxy with a path prefix. The path is also used in the CAS URLs advertised via WebFinger. " +
+		"Defaults to /cas. " +
+		commonEnvVarUsageText + webCASPathEnvKey
+
 	enableUnpublishedOperationStoreFlagName = "enable-unpublished-operation-store"
 	enableUnpublishedOperationStoreEnvKey   = "UNPUBLISHED_OPERATION_STORE_ENABLED"
 	enableUnpublishedOperationStoreUsage    = `Set to "true" to enable un-published operation store. ` +
@@ -602,6 +812,19 @@ const (
 	ipfsTimeoutFlagUsage     = "The timeout for IPFS requests. For example, '30s' for a 30 second timeout. " +
 		commonEnvVarUsageText + ipfsTimeoutEnvKey
 
+	ipfsRateLimitFlagName  = "ipfs-rate-limit"
+	ipfsRateLimitEnvKey    = "IPFS_RATE_LIMIT"
+	ipfsRateLimitFlagUsage = "The maximum number of requests per second that are sent to the IPFS node. " +
+		"Requests that exceed the limit are delayed. If the node responds with 429 (Too Many Requests) then " +
+		"the request is retried, honoring the Retry-After header. If 0 (the default) then requests aren't limited. " +
+		commonEnvVarUsageText + ipfsRateLimitEnvKey
+
+	ipfsRateLimitBurstFlagName  = "ipfs-rate-limit-burst"
+	ipfsRateLimitBurstEnvKey    = "IPFS_RATE_LIMIT_BURST"
+	ipfsRateLimitBurstFlagUsage = "The maximum number of requests that may be sent to the IPFS node at once " +
+		"before the rate limit (set by " + ipfsRateLimitFlagName + ") is applied. Defaults to 10. " +
+		commonEnvVarUsageText + ipfsRateLimitBurstEnvKey
+
 	contextProviderFlagName  = "context-provider-url"
 	contextProviderFlagUsage = "Comma-separated list of remote context provider URLs to get JSON-LD contexts from." +
 		commonEnvVarUsageText + contextProviderEnvKey
@@ -653,6 +876,40 @@ const (
 	httpDialTimeoutFlagUsage = "The timeout for http dial. For example, '30s' for a 30 second timeout. " +
 		commonEnvVarUsageText + httpDialTimeoutEnvKey
 
+	httpTLSHandshakeTimeoutFlagName  = "http-tls-handshake-timeout"
+	httpTLSHandshakeTimeoutEnvKey    = "HTTP_TLS_HANDSHAKE_TIMEOUT"
+	httpTLSHandshakeTimeoutFlagUsage = "The timeout for the TLS handshake of an outbound http connection. " +
+		"For example, '10s' for a 10 second timeout. Defaults to 5s. " +
+		commonEnvVarUsageText + httpTLSHandshakeTimeoutEnvKey
+
+	httpResponseHeaderTimeoutFlagName  = "http-response-header-timeout"
+	httpResponseHeaderTimeoutEnvKey    = "HTTP_RESPONSE_HEADER_TIMEOUT"
+	httpResponseHeaderTimeoutFlagUsage = "The maximum amount of time to wait for the response headers of an outbound " +
+		"http request after the request has been sent. This is distinct from (and should be less than) the http-timeout, " +
+		"which applies to the entire request. For example, '10s' for a 10 second timeout. Defaults to 15s. " +
+		commonEnvVarUsageText + httpResponseHeaderTimeoutEnvKey
+
+	httpEnableHTTP2FlagName  = "http-enable-http2"
+	httpEnableHTTP2EnvKey    = "HTTP_ENABLE_HTTP2"
+	httpEnableHTTP2FlagUsage = "If enabled then HTTP/2 is negotiated with peers which support it, so that concurrent " +
+		"outbound requests to the same peer are multiplexed over a single connection. Peers which don't support " +
+		"HTTP/2 fall back to HTTP/1.1. Possible values [true] [false]. Defaults to true. " +
+		commonEnvVarUsageText + httpEnableHTTP2EnvKey
+
+	httpMaxConcurrentStreamsFlagName  = "http-max-concurrent-streams"
+	httpMaxConcurrentStreamsEnvKey    = "HTTP_MAX_CONCURRENT_STREAMS"
+	httpMaxConcurrentStreamsFlagUsage = "The maximum number of concurrent outbound requests to a single peer. With HTTP/2, " +
+		"this caps the number of concurrent streams on the connection to the peer so that a busy peer isn't " +
+		"overwhelmed. Additional requests wait for a stream to become available. Defaults to 0 (no limit). " +
+		commonEnvVarUsageText + httpMaxConcurrentStreamsEnvKey
+
+	httpUserAgentFlagName  = "http-user-agent"
+	httpUserAgentEnvKey    = "HTTP_USER_AGENT"
+	httpUserAgentFlagUsage = "The User-Agent header which is set on outbound HTTP requests, for example 'orb/1.0.0'. " +
+		"Defaults to 'orb/<build version>'. An X-Request-ID header is also set on outbound requests (propagated " +
+		"from the inbound request, if any) so that requests may be correlated in the logs of the nodes. " +
+		commonEnvVarUsageText + httpUserAgentEnvKey
+
 	anchorSyncIntervalFlagName      = "sync-interval"
 	anchorSyncIntervalFlagShorthand = "S"
 	anchorSyncIntervalEnvKey        = "ANCHOR_EVENT_SYNC_INTERVAL"
@@ -688,6 +945,13 @@ const (
 	activityPubClientCacheExpirationFlagUsage = "The expiration time of an ActivityPub service and public key cache. " +
 		commonEnvVarUsageText + activityPubClientCacheExpirationEnvKey
 
+	activityPubClientMaxPagesFlagName  = "apclient-max-pages"
+	activityPubClientMaxPagesEnvKey    = "ACTIVITYPUB_CLIENT_MAX_PAGES"
+	activityPubClientMaxPagesFlagUsage = "The maximum number of pages that are retrieved when iterating over a " +
+		"remote ActivityPub collection. Iteration stops with an error when this limit is reached, which protects " +
+		"against a remote server that serves an endless sequence of pages. Defaults to 10000. " +
+		commonEnvVarUsageText + activityPubClientMaxPagesEnvKey
+
 	activityPubIRICacheSizeFlagName  = "apiri-cache-size"
 	activityPubIRICacheSizeEnvKey    = "ACTIVITYPUB_IRI_CACHE_SIZE"
 	activityPubIRICacheSizeFlagUsage = "The maximum size of an ActivityPub actor IRI cache. " +
@@ -698,6 +962,60 @@ const (
 	activityPubIRICacheExpirationFlagUsage = "The expiration time of an ActivityPub actor IRI cache. " +
 		commonEnvVarUsageText + activityPubIRICacheExpirationEnvKey
 
+	activityPubStrictParsingFlagName  = "activitypub-strict-parsing"
+	activityPubStrictParsingEnvKey    = "ACTIVITYPUB_STRICT_PARSING"
+	activityPubStrictParsingFlagUsage = `Set to "true" to reject incoming activities that contain unknown fields. ` +
+		"This is useful for conformance and interoperability testing. Defaults to false. " +
+		commonEnvVarUsageText + activityPubStrictParsingEnvKey
+
+	activityPubOutboxBatchWindowFlagName  = "activitypub-outbox-batch-window"
+	activityPubOutboxBatchWindowEnvKey    = "ACTIVITYPUB_OUTBOX_BATCH_WINDOW"
+	activityPubOutboxBatchWindowFlagUsage = "The amount of time that announcements of anchor events are held so that " +
+		"they may be coalesced into a single 'Announce' activity, which reduces the number of activities that are " +
+		"sent to followers. Defaults to 0 (batching disabled). " +
+		commonEnvVarUsageText + activityPubOutboxBatchWindowEnvKey
+
+	activityPubOutboxBatchMaxItemsFlagName  = "activitypub-outbox-batch-max-items"
+	activityPubOutboxBatchMaxItemsEnvKey    = "ACTIVITYPUB_OUTBOX_BATCH_MAX_ITEMS"
+	activityPubOutboxBatchMaxItemsFlagUsage = "The maximum number of anchor events in a batched 'Announce' activity. " +
+		"This parameter only applies if activitypub-outbox-batch-window is set. Defaults to 10. " +
+		commonEnvVarUsageText + activityPubOutboxBatchMaxItemsEnvKey
+
+	activityPubOutboxDeliveryPoolSizeFlagName  = "activitypub-outbox-delivery-pool-size"
+	activityPubOutboxDeliveryPoolSizeEnvKey    = "ACTIVITYPUB_OUTBOX_DELIVERY_POOL_SIZE"
+	activityPubOutboxDeliveryPoolSizeFlagUsage = "The number of workers that deliver activities to follower " +
+		"(and witness) inboxes. Defaults to 0, in which case each delivery is handled in its own goroutine. " +
+		commonEnvVarUsageText + activityPubOutboxDeliveryPoolSizeEnvKey
+
+	activityPubOutboxDeliveryBatchSizeFlagName  = "activitypub-outbox-delivery-batch-size"
+	activityPubOutboxDeliveryBatchSizeEnvKey    = "ACTIVITYPUB_OUTBOX_DELIVERY_BATCH_SIZE"
+	activityPubOutboxDeliveryBatchSizeFlagUsage = "The maximum number of activities that a delivery worker " +
+		"delivers back-to-back to the same inbox. If greater than 1 then deliveries to the same inbox are grouped. " +
+		"This parameter only applies if activitypub-outbox-delivery-pool-size is set. Defaults to 1 (no batching). " +
+		commonEnvVarUsageText + activityPubOutboxDeliveryBatchSizeEnvKey
+
+	activityPubOutboxAtomMaxEntriesFlagName  = "activitypub-outbox-atom-max-entries"
+	activityPubOutboxAtomMaxEntriesEnvKey    = "ACTIVITYPUB_OUTBOX_ATOM_MAX_ENTRIES"
+	activityPubOutboxAtomMaxEntriesFlagUsage = "The maximum number of recent public outbox activities that are " +
+		"rendered in the Atom feed served at /services/orb/outbox.atom. Defaults to 0, in which case the Atom feed is disabled. " +
+		commonEnvVarUsageText + activityPubOutboxAtomMaxEntriesEnvKey
+
+	activityPubMaxEmbeddedAnchorSizeFlagName  = "activitypub-max-embedded-anchor-size"
+	activityPubMaxEmbeddedAnchorSizeEnvKey    = "ACTIVITYPUB_MAX_EMBEDDED_ANCHOR_SIZE"
+	activityPubMaxEmbeddedAnchorSizeFlagUsage = "The maximum size (in bytes) of an anchor linkset that is embedded " +
+		"into an activity returned from the outbox or activities endpoint when the client specifies the " +
+		"'embed=true' query parameter. Larger anchor linksets are returned as references. " +
+		"A value of 0 disables embedding. Defaults to 65536. " +
+		commonEnvVarUsageText + activityPubMaxEmbeddedAnchorSizeEnvKey
+
+	activityPubAcceptedActivityTypesFlagName  = "activitypub-accepted-activity-types"
+	activityPubAcceptedActivityTypesEnvKey    = "ACTIVITYPUB_ACCEPTED_ACTIVITY_TYPES"
+	activityPubAcceptedActivityTypesFlagUsage = "Comma-separated list of activity types accepted by the inbox, " +
+		"for example 'Create,Announce' for a resolution-only node. Activities of any other type are rejected " +
+		"with a 403 (Forbidden). Supported types are Create, Announce, Follow, Accept, Reject, Like, Invite, " +
+		"Offer and Undo. Defaults to all supported activity types. " +
+		commonEnvVarUsageText + activityPubAcceptedActivityTypesEnvKey
+
 	serverIdleTimeoutFlagName  = "server-idle-timeout"
 	serverIdleTimeoutEnvKey    = "SERVER_IDLE_TIMEOUT"
 	serverIdleTimeoutFlagUsage = "The timeout for server idle timeout. For example, '30s' for a 30 second timeout. " +
@@ -713,14 +1031,39 @@ const (
 	anchorRefPendingRecordLifespanFlagUsage = "The lifespan of an anchor reference in PENDING state. " +
 		commonEnvVarUsageText + witnessPolicyCacheExpirationEnvKey
 
+	anchorRefProcessedRecordTTLFlagName  = "anchor-ref-processed-record-ttl"
+	anchorRefProcessedRecordTTLEnvKey    = "ANCHOR_REF_PROCESSED_RECORD_TTL"
+	anchorRefProcessedRecordTTLFlagUsage = "The amount of time that a reference to a processed anchor is retained " +
+		"in order to detect duplicate anchors, after which the reference is pruned. This value must be longer than " +
+		"the period in which an anchor may be redelivered. Processed anchor references are also returned as " +
+		"alternate links in WebFinger queries. Set to 0 to disable pruning. Defaults to 720h (30 days). " +
+		commonEnvVarUsageText + anchorRefProcessedRecordTTLEnvKey
+
 	dataURIMediaTypeFlagName  = "anchor-data-uri-media-type"
 	dataURIMediaTypeEnvKey    = "ANCHOR_DATA_URI_MEDIA_TYPE"
 	dataURIMediaTypeFlagUsage = "The media type for data URIs in an anchor Linkset. Possible values are " +
-		"'application/json' and 'application/gzip;base64'. If 'application/json' is specified then the content of the data URIs " +
-		"in the anchor LInkset are encoded as an escaped JSON string. If 'application/gzip;base64' is specified then the content is " +
-		"compressed with gzip and base64 encoded (default is 'application/gzip;base64')." +
+		"'application/json', 'application/json;base64', 'application/gzip;base64' and 'auto'. If 'application/json' is specified " +
+		"then the content of the data URIs in the anchor LInkset are encoded as an escaped JSON string. If " +
+		"'application/json;base64' is specified then the content is base64 encoded. If 'application/gzip;base64' is specified " +
+		"then the content is compressed with gzip and base64 encoded. If 'auto' is specified then content that is smaller than " +
+		"the threshold set by --" + dataURIAutoThresholdFlagName + " is base64 encoded and larger content is compressed " +
+		"with gzip and base64 encoded (default is 'application/gzip;base64')." +
 		commonEnvVarUsageText + dataURIMediaTypeEnvKey
 
+	dataURIAutoThresholdFlagName  = "anchor-data-uri-auto-threshold"
+	dataURIAutoThresholdEnvKey    = "ANCHOR_DATA_URI_AUTO_THRESHOLD"
+	dataURIAutoThresholdFlagUsage = "The size (in bytes) at or above which the content of a data URI in an anchor Linkset " +
+		"is compressed when the data URI media type is 'auto'. Defaults to 512." +
+		commonEnvVarUsageText + dataURIAutoThresholdEnvKey
+
+	anchorMaxPreviousAnchorsFlagName  = "anchor-max-previous-anchors"
+	anchorMaxPreviousAnchorsEnvKey    = "ANCHOR_MAX_PREVIOUS_ANCHORS"
+	anchorMaxPreviousAnchorsFlagUsage = "The maximum number of previous anchors (i.e. operations) that may be included " +
+		"in a single anchor Linkset. An anchor Linkset with more previous anchors is rejected by the builder. " +
+		"The value must not be less than the maximum number of operations per batch of any Sidetree protocol " +
+		"version, otherwise the server fails to start. Defaults to 0 (no maximum)." +
+		commonEnvVarUsageText + anchorMaxPreviousAnchorsEnvKey
+
 	sidetreeProtocolVersionsFlagName = "sidetree-protocol-versions"
 	sidetreeProtocolVersionsEnvKey   = "SIDETREE_PROTOCOL_VERSIONS"
 	sidetreeProtocolVersionsUsage    = `Comma-separated list of sidetree protocol versions. ` +
@@ -779,6 +1122,9 @@ type tlsParameters struct {
 	caCerts        []string
 	serveCertPath  string
 	serveKeyPath   string
+	minVersion     uint16
+	cipherSuites   []uint16
+	peerCACerts    []string
 }
 
 type orbParameters struct {
@@ -787,6 +1133,8 @@ type orbParameters struct {
 	apServiceParams                *apServiceParams
 	discoveryDomain                string
 	dataURIMediaType               datauri.MediaType
+	dataURIAutoThreshold           int
+	anchorMaxPreviousAnchors       int
 	batchWriterTimeout             time.Duration
 	cas                            *casParams
 	mqParams                       *mqParams
@@ -802,6 +1150,11 @@ type orbParameters struct {
 	witnessProof                   *witnessProofParams
 	syncTimeout                    uint64
 	didDiscoveryEnabled            bool
+	processedAnchorLogEnabled      bool
+	observerNamespaces             []string
+	observerValidatePrevious       bool
+	webCASDigestHeaderEnabled      bool
+	webCASPath                     string
 	unpublishedOperations          *unpublishedOperationsStoreParams
 	resolveFromAnchorOrigin        bool
 	verifyLatestFromAnchorOrigin   bool
@@ -816,12 +1169,14 @@ type orbParameters struct {
 	taskMgrCheckInterval           time.Duration
 	vct                            *vctParams
 	anchorStatus                   *anchorStatusParams
+	circuitBreaker                 *circuitBreakerParams
 	witnessPolicyCacheExpiration   time.Duration
 	kmsParams                      *kmsParameters
 	requestTokens                  map[string]string
 	allowedDIDWebDomains           []*url.URL
 	observability                  *observabilityParams
 	anchorRefPendingRecordLifespan time.Duration
+	anchorRefProcessedRecordTTL    time.Duration
 }
 
 type observabilityParams struct {
@@ -850,9 +1205,20 @@ type apServiceParams struct {
 }
 
 type anchorCredentialParams struct {
-	domain string
-	issuer string
-	url    string
+	domain                 string
+	issuer                 string
+	url                    string
+	strictValidation       bool
+	maxAge                 time.Duration
+	verifyLineage          bool
+	parentResolveTimeout   time.Duration
+	parentTraversalTimeout time.Duration
+	publishQueueSize       int
+	publishQueueWait       time.Duration
+	acceptedProfiles       []*url.URL
+	duplicateParentPolicy  credential.DuplicateParentPolicy
+	fetchPolicy            credential.AnchorFetchPolicy
+	importEnabled          bool
 }
 
 type dbParameters struct {
@@ -1025,6 +1391,30 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, err
 	}
 
+	processedAnchorLogEnabled, err := cmdutil.GetBool(cmd, enableProcessedAnchorLogFlagName,
+		enableProcessedAnchorLogEnvKey, defaultProcessedAnchorLogEnabled)
+	if err != nil {
+		return nil, err
+	}
+
+	observerNamespaces, err := cmdutil.GetUserSetVarFromArrayString(cmd, observerNamespacesFlagName,
+		observerNamespacesEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	observerValidatePrevious, err := cmdutil.GetBool(cmd, observerValidatePreviousAnchorsFlagName,
+		observerValidatePreviousAnchorsEnvKey, defaultObserverValidatePreviousAnchors)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", observerValidatePreviousAnchorsFlagName, err)
+	}
+
+	webCASDigestHeaderEnabled, err := cmdutil.GetBool(cmd, webCASDigestHeaderEnabledFlagName,
+		webCASDigestHeaderEnabledEnvKey, defaultWebCASDigestHeaderEnabled)
+	if err != nil {
+		return nil, err
+	}
+
 	enableVCT, err := cmdutil.GetBool(cmd, enableVCTFlagName, enabledVCTEnvKey, defaultVCTEnabled)
 	if err != nil {
 		return nil, err
@@ -1103,6 +1493,21 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		dataURIMediaType = defaultDataURIMediaType
 	}
 
+	dataURIAutoThreshold, err := getDataURIAutoThreshold(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	anchorMaxPreviousAnchors, err := getAnchorMaxPreviousAnchors(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	webCASPath, err := getWebCASPath(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	discoveryParams, err := getDiscoveryParams(cmd)
 	if err != nil {
 		return nil, err
@@ -1151,6 +1556,11 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, err
 	}
 
+	circuitBreakerParams, err := getCircuitBreakerParams(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	witnessPolicyCacheExpiration, err := cmdutil.GetDuration(cmd, witnessPolicyCacheExpirationFlagName,
 		witnessPolicyCacheExpirationEnvKey, defaultWitnessPolicyCacheExpiration)
 	if err != nil {
@@ -1164,7 +1574,11 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, err
 	}
 
-	anchorCredentialParams := getAnchorCredentialParameters(cmd, httpParams.externalEndpoint, apServiceParams.serviceIRI().String())
+	anchorCredentialParams, err := getAnchorCredentialParameters(cmd, httpParams.externalEndpoint,
+		apServiceParams.serviceIRI().String())
+	if err != nil {
+		return nil, err
+	}
 
 	anchorRefPendingRecordLifespan, err := cmdutil.GetDuration(cmd, anchorRefPendingRecordLifespanFlagName,
 		anchorRefPendingRecordLifespanEnvKey, defaultAnchorRefPendingRecordLifespan)
@@ -1172,6 +1586,16 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, fmt.Errorf("%s: %w", anchorRefPendingRecordLifespanFlagName, err)
 	}
 
+	anchorRefProcessedRecordTTL, err := cmdutil.GetDuration(cmd, anchorRefProcessedRecordTTLFlagName,
+		anchorRefProcessedRecordTTLEnvKey, defaultAnchorRefProcessedRecordTTL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", anchorRefProcessedRecordTTLFlagName, err)
+	}
+
+	if anchorRefProcessedRecordTTL < 0 {
+		return nil, fmt.Errorf("value for %s must not be negative", anchorRefProcessedRecordTTLFlagName)
+	}
+
 	return &orbParameters{
 		http:                           httpParams,
 		sidetree:                       sidetreeParams,
@@ -1191,6 +1615,11 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		witnessProof:                   witnessProofParams,
 		syncTimeout:                    syncTimeout,
 		didDiscoveryEnabled:            didDiscoveryEnabled,
+		processedAnchorLogEnabled:      processedAnchorLogEnabled,
+		observerNamespaces:             observerNamespaces,
+		observerValidatePrevious:       observerValidatePrevious,
+		webCASDigestHeaderEnabled:      webCASDigestHeaderEnabled,
+		webCASPath:                     webCASPath,
 		unpublishedOperations:          unpublishedOperationsParams,
 		resolveFromAnchorOrigin:        resolveFromAnchorOrigin,
 		verifyLatestFromAnchorOrigin:   verifyLatestFromAnchorOrigin,
@@ -1205,12 +1634,16 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		taskMgrCheckInterval:           taskMgrCheckInterval,
 		vct:                            vctParams,
 		anchorStatus:                   anchorStatusParams,
+		circuitBreaker:                 circuitBreakerParams,
 		witnessPolicyCacheExpiration:   witnessPolicyCacheExpiration,
 		dataURIMediaType:               dataURIMediaType,
+		dataURIAutoThreshold:           dataURIAutoThreshold,
+		anchorMaxPreviousAnchors:       anchorMaxPreviousAnchors,
 		kmsParams:                      kmsParams,
 		requestTokens:                  requestTokens,
 		observability:                  observabilityParams,
 		anchorRefPendingRecordLifespan: anchorRefPendingRecordLifespan,
+		anchorRefProcessedRecordTTL:    anchorRefProcessedRecordTTL,
 	}, nil
 }
 
@@ -1220,8 +1653,13 @@ type httpParams struct {
 	tls                     *tlsParameters
 	timeout                 time.Duration
 	dialTimeout             time.Duration
+	tlsHandshakeTimeout     time.Duration
+	responseHeaderTimeout   time.Duration
+	enableHTTP2             bool
+	maxConcurrentStreams    int
 	serverIdleTimeout       time.Duration
 	serverReadHeaderTimeout time.Duration
+	userAgent               string
 }
 
 func getHTTPParams(cmd *cobra.Command) (*httpParams, error) {
@@ -1249,6 +1687,32 @@ func getHTTPParams(cmd *cobra.Command) (*httpParams, error) {
 		return nil, fmt.Errorf("%s: %w", httpDialTimeoutFlagName, err)
 	}
 
+	httpTLSHandshakeTimeout, err := cmdutil.GetDuration(cmd, httpTLSHandshakeTimeoutFlagName,
+		httpTLSHandshakeTimeoutEnvKey, defaultHTTPTLSHandshakeTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", httpTLSHandshakeTimeoutFlagName, err)
+	}
+
+	httpResponseHeaderTimeout, err := cmdutil.GetDuration(cmd, httpResponseHeaderTimeoutFlagName,
+		httpResponseHeaderTimeoutEnvKey, defaultHTTPResponseHeaderTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", httpResponseHeaderTimeoutFlagName, err)
+	}
+
+	enableHTTP2, err := cmdutil.GetBool(cmd, httpEnableHTTP2FlagName, httpEnableHTTP2EnvKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", httpEnableHTTP2FlagName, err)
+	}
+
+	maxConcurrentStreams, err := cmdutil.GetInt(cmd, httpMaxConcurrentStreamsFlagName, httpMaxConcurrentStreamsEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", httpMaxConcurrentStreamsFlagName, err)
+	}
+
+	if maxConcurrentStreams < 0 {
+		return nil, fmt.Errorf("%s: value must not be negative", httpMaxConcurrentStreamsFlagName)
+	}
+
 	serverIdleTimeout, err := cmdutil.GetDuration(cmd, serverIdleTimeoutFlagName, serverIdleTimeoutEnvKey, defaultServerIdleTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", serverIdleTimeoutFlagName, err)
@@ -1265,14 +1729,28 @@ func getHTTPParams(cmd *cobra.Command) (*httpParams, error) {
 		return nil, fmt.Errorf("%s: %w", httpTimeoutFlagName, err)
 	}
 
+	userAgent, err := cmdutil.GetUserSetVarFromString(cmd, httpUserAgentFlagName, httpUserAgentEnvKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", httpUserAgentFlagName, err)
+	}
+
+	if userAgent == "" {
+		userAgent = transport.UserAgent("orb", httpserver.BuildVersion)
+	}
+
 	return &httpParams{
 		hostURL:                 hostURL,
 		externalEndpoint:        externalEndpoint,
 		tls:                     tlsParams,
 		timeout:                 httpTimeout,
 		dialTimeout:             httpDialTimeout,
+		tlsHandshakeTimeout:     httpTLSHandshakeTimeout,
+		responseHeaderTimeout:   httpResponseHeaderTimeout,
+		enableHTTP2:             enableHTTP2,
+		maxConcurrentStreams:    maxConcurrentStreams,
 		serverIdleTimeout:       serverIdleTimeout,
 		serverReadHeaderTimeout: serverReadHeaderTimeout,
+		userAgent:               userAgent,
 	}, nil
 }
 
@@ -1317,8 +1795,13 @@ type casParams struct {
 	casType                        string
 	ipfsURL                        string
 	localCASReplicateInIPFSEnabled bool
+	storeRemoteContent             bool
+	listHashesEnabled              bool
+	writeEnabled                   bool
 	cidVersion                     int
 	ipfsTimeout                    time.Duration
+	ipfsRateLimit                  int
+	ipfsRateLimitBurst             int
 }
 
 func getCASParams(cmd *cobra.Command) (*casParams, error) {
@@ -1348,12 +1831,49 @@ func getCASParams(cmd *cobra.Command) (*casParams, error) {
 		return nil, fmt.Errorf("%s: %w", ipfsTimeoutFlagName, err)
 	}
 
+	ipfsRateLimit, err := cmdutil.GetInt(cmd, ipfsRateLimitFlagName, ipfsRateLimitEnvKey, defaultIPFSRateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ipfsRateLimitFlagName, err)
+	}
+
+	if ipfsRateLimit < 0 {
+		return nil, fmt.Errorf("%s: value must not be negative", ipfsRateLimitFlagName)
+	}
+
+	ipfsRateLimitBurst, err := cmdutil.GetInt(cmd, ipfsRateLimitBurstFlagName, ipfsRateLimitBurstEnvKey,
+		defaultIPFSRateLimitBurst)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ipfsRateLimitBurstFlagName, err)
+	}
+
+	if ipfsRateLimitBurst <= 0 {
+		return nil, fmt.Errorf("%s: value must be greater than 0", ipfsRateLimitBurstFlagName)
+	}
+
 	localCASReplicateInIPFSEnabled, err := cmdutil.GetBool(cmd, localCASReplicateInIPFSFlagName, localCASReplicateInIPFSEnvKey,
 		defaultLocalCASReplicateInIPFSEnabled)
 	if err != nil {
 		return nil, err
 	}
 
+	storeRemoteContent, err := cmdutil.GetBool(cmd, casStoreRemoteContentFlagName, casStoreRemoteContentEnvKey,
+		defaultCASStoreRemoteContent)
+	if err != nil {
+		return nil, err
+	}
+
+	listHashesEnabled, err := cmdutil.GetBool(cmd, casListHashesEnabledFlagName, casListHashesEnabledEnvKey,
+		defaultCASListHashesEnabled)
+	if err != nil {
+		return nil, err
+	}
+
+	writeEnabled, err := cmdutil.GetBool(cmd, casWriteEnabledFlagName, casWriteEnabledEnvKey,
+		defaultCASWriteEnabled)
+	if err != nil {
+		return nil, err
+	}
+
 	cidVersionString, err := cmdutil.GetUserSetVarFromString(cmd, cidVersionFlagName, cidVersionEnvKey, true)
 	if err != nil {
 		return nil, err
@@ -1377,7 +1897,12 @@ func getCASParams(cmd *cobra.Command) (*casParams, error) {
 		casType:                        casType,
 		ipfsURL:                        ipfsURL,
 		ipfsTimeout:                    ipfsTimeout,
+		ipfsRateLimit:                  ipfsRateLimit,
+		ipfsRateLimitBurst:             ipfsRateLimitBurst,
 		localCASReplicateInIPFSEnabled: localCASReplicateInIPFSEnabled,
+		storeRemoteContent:             storeRemoteContent,
+		listHashesEnabled:              listHashesEnabled,
+		writeEnabled:                   writeEnabled,
 		cidVersion:                     cidVersion,
 	}, nil
 }
@@ -1387,6 +1912,7 @@ type witnessProofParams struct {
 	maxClockSkew                time.Duration
 	witnessStoreExpiryPeriod    time.Duration
 	proofMonitoringExpiryPeriod time.Duration
+	witnessOfferLifetime        time.Duration
 	signWithLocalWitness        bool
 }
 
@@ -1411,6 +1937,16 @@ func getWitnessProofParams(cmd *cobra.Command) (*witnessProofParams, error) {
 		return nil, fmt.Errorf("witness store expiry period must me greater than maximum witness delay + max clock skew")
 	}
 
+	witnessOfferLifetime, err := cmdutil.GetDuration(cmd, witnessOfferLifetimeFlagName, witnessOfferLifetimeEnvKey,
+		maxWitnessDelay)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", witnessOfferLifetimeFlagName, err)
+	}
+
+	if witnessOfferLifetime <= 0 {
+		return nil, fmt.Errorf("%s: value must be greater than 0", witnessOfferLifetimeFlagName)
+	}
+
 	// default behavior is to always sign with local witness
 	signWithLocalWitness, err := cmdutil.GetBool(cmd, signWithLocalWitnessFlagName, signWithLocalWitnessEnvKey, true)
 	if err != nil {
@@ -1428,6 +1964,7 @@ func getWitnessProofParams(cmd *cobra.Command) (*witnessProofParams, error) {
 		maxClockSkew:                maxClockSkew,
 		witnessStoreExpiryPeriod:    witnessStoreExpiryPeriod,
 		proofMonitoringExpiryPeriod: proofMonitoringExpiryPeriod,
+		witnessOfferLifetime:        witnessOfferLifetime,
 		signWithLocalWitness:        signWithLocalWitness,
 	}, nil
 }
@@ -1645,8 +2182,17 @@ type activityPubParams struct {
 	anchorSyncMaxActivities     int
 	clientCacheSize             int
 	clientCacheExpiration       time.Duration
+	clientMaxPages              int
 	iriCacheSize                int
 	iriCacheExpiration          time.Duration
+	strictParsing               bool
+	acceptedActivityTypes       []vocab.Type
+	outboxBatchWindow           time.Duration
+	outboxBatchMaxItems         int
+	outboxDeliveryPoolSize      int
+	outboxDeliveryBatchSize     int
+	outboxAtomMaxEntries        int
+	maxEmbeddedAnchorSize       int
 }
 
 func getActivityPubParams(cmd *cobra.Command) (*activityPubParams, error) {
@@ -1670,6 +2216,70 @@ func getActivityPubParams(cmd *cobra.Command) (*activityPubParams, error) {
 		return nil, err
 	}
 
+	apClientMaxPages, err := cmdutil.GetInt(cmd, activityPubClientMaxPagesFlagName,
+		activityPubClientMaxPagesEnvKey, defaultActivityPubClientMaxPages)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", activityPubClientMaxPagesFlagName, err)
+	}
+
+	if apClientMaxPages <= 0 {
+		return nil, fmt.Errorf("value for %s must be greater than 0", activityPubClientMaxPagesFlagName)
+	}
+
+	strictParsing, err := cmdutil.GetBool(cmd, activityPubStrictParsingFlagName, activityPubStrictParsingEnvKey,
+		defaultActivityPubStrictParsing)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", activityPubStrictParsingFlagName, err)
+	}
+
+	acceptedActivityTypesStr, err := cmdutil.GetUserSetVarFromArrayString(cmd, activityPubAcceptedActivityTypesFlagName,
+		activityPubAcceptedActivityTypesEnvKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", activityPubAcceptedActivityTypesFlagName, err)
+	}
+
+	acceptedActivityTypes, err := toActivityTypes(acceptedActivityTypesStr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", activityPubAcceptedActivityTypesFlagName, err)
+	}
+
+	outboxBatchWindow, err := cmdutil.GetDuration(cmd, activityPubOutboxBatchWindowFlagName,
+		activityPubOutboxBatchWindowEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", activityPubOutboxBatchWindowFlagName, err)
+	}
+
+	outboxBatchMaxItems, err := cmdutil.GetInt(cmd, activityPubOutboxBatchMaxItemsFlagName,
+		activityPubOutboxBatchMaxItemsEnvKey, defaultActivityPubOutboxBatchMaxItems)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", activityPubOutboxBatchMaxItemsFlagName, err)
+	}
+
+	outboxDeliveryPoolSize, outboxDeliveryBatchSize, err := getActivityPubOutboxDeliveryParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxAtomMaxEntries, err := cmdutil.GetInt(cmd, activityPubOutboxAtomMaxEntriesFlagName,
+		activityPubOutboxAtomMaxEntriesEnvKey, defaultActivityPubOutboxAtomMaxEntries)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", activityPubOutboxAtomMaxEntriesFlagName, err)
+	}
+
+	if outboxAtomMaxEntries < 0 {
+		return nil, fmt.Errorf("value for %s must not be negative", activityPubOutboxAtomMaxEntriesFlagName)
+	}
+
+	maxEmbeddedAnchorSize, err := cmdutil.GetInt(cmd, activityPubMaxEmbeddedAnchorSizeFlagName,
+		activityPubMaxEmbeddedAnchorSizeEnvKey, defaultActivityPubMaxEmbeddedAnchorSize)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", activityPubMaxEmbeddedAnchorSizeFlagName, err)
+	}
+
+	if maxEmbeddedAnchorSize < 0 {
+		return nil, fmt.Errorf("value for %s must not be negative", activityPubMaxEmbeddedAnchorSizeFlagName)
+	}
+
 	return &activityPubParams{
 		pageSize:                    activityPubPageSize,
 		anchorSyncPeriod:            syncPeriod,
@@ -1678,11 +2288,86 @@ func getActivityPubParams(cmd *cobra.Command) (*activityPubParams, error) {
 		anchorSyncMaxActivities:     maxActivities,
 		clientCacheSize:             apClientCacheSize,
 		clientCacheExpiration:       apClientCacheExpiration,
+		clientMaxPages:              apClientMaxPages,
 		iriCacheSize:                apIRICacheSize,
 		iriCacheExpiration:          apIRICacheExpiration,
+		strictParsing:               strictParsing,
+		acceptedActivityTypes:       acceptedActivityTypes,
+		outboxBatchWindow:           outboxBatchWindow,
+		outboxBatchMaxItems:         outboxBatchMaxItems,
+		outboxDeliveryPoolSize:      outboxDeliveryPoolSize,
+		outboxDeliveryBatchSize:     outboxDeliveryBatchSize,
+		outboxAtomMaxEntries:        outboxAtomMaxEntries,
+		maxEmbeddedAnchorSize:       maxEmbeddedAnchorSize,
 	}, nil
 }
 
+func getActivityPubOutboxDeliveryParameters(cmd *cobra.Command) (poolSize, batchSize int, err error) {
+	poolSize, err = cmdutil.GetInt(cmd, activityPubOutboxDeliveryPoolSizeFlagName,
+		activityPubOutboxDeliveryPoolSizeEnvKey, defaultActivityPubOutboxDeliveryPool)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", activityPubOutboxDeliveryPoolSizeFlagName, err)
+	}
+
+	if poolSize < 0 {
+		return 0, 0, fmt.Errorf("value for %s must not be negative", activityPubOutboxDeliveryPoolSizeFlagName)
+	}
+
+	batchSize, err = cmdutil.GetInt(cmd, activityPubOutboxDeliveryBatchSizeFlagName,
+		activityPubOutboxDeliveryBatchSizeEnvKey, defaultActivityPubOutboxDeliveryBatch)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", activityPubOutboxDeliveryBatchSizeFlagName, err)
+	}
+
+	if batchSize <= 0 {
+		return 0, 0, fmt.Errorf("value for %s must be greater than 0", activityPubOutboxDeliveryBatchSizeFlagName)
+	}
+
+	return poolSize, batchSize, nil
+}
+
+// supportedActivityTypes contains the types of activities that are handled by the inbox.
+var supportedActivityTypes = []vocab.Type{
+	vocab.TypeCreate, vocab.TypeAnnounce, vocab.TypeFollow, vocab.TypeAccept, vocab.TypeReject,
+	vocab.TypeLike, vocab.TypeInvite, vocab.TypeOffer, vocab.TypeUndo,
+}
+
+// toActivityTypes splits the given (comma-separated) values into activity types. An error is returned
+// if any of the types isn't a supported activity type.
+func toActivityTypes(values []string) ([]vocab.Type, error) {
+	var types []vocab.Type
+
+	for _, value := range values {
+		for _, str := range strings.Split(value, ",") {
+			str = strings.TrimSpace(str)
+			if str == "" {
+				continue
+			}
+
+			t := vocab.Type(str)
+
+			if !isSupportedActivityType(t) {
+				return nil, fmt.Errorf("unsupported activity type [%s] - supported types are %s",
+					t, supportedActivityTypes)
+			}
+
+			types = append(types, t)
+		}
+	}
+
+	return types, nil
+}
+
+func isSupportedActivityType(t vocab.Type) bool {
+	for _, st := range supportedActivityTypes {
+		if t == st {
+			return true
+		}
+	}
+
+	return false
+}
+
 func getActivityPubClientParameters(cmd *cobra.Command) (int, time.Duration, error) {
 	return getActivityPubCacheParameters(cmd, &cacheParams{
 		sizeFlag:          activityPubClientCacheSizeFlagName,
@@ -1737,6 +2422,84 @@ func getAnchorStatusParams(cmd *cobra.Command) (*anchorStatusParams, error) {
 	}, nil
 }
 
+type circuitBreakerParams struct {
+	failureThreshold int
+	cooldown         time.Duration
+}
+
+func getCircuitBreakerParams(cmd *cobra.Command) (*circuitBreakerParams, error) {
+	failureThreshold, err := cmdutil.GetInt(cmd, circuitBreakerFailureThresholdFlagName,
+		circuitBreakerFailureThresholdEnvKey, defaultCircuitBreakerFailureThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", circuitBreakerFailureThresholdFlagName, err)
+	}
+
+	if failureThreshold < 0 {
+		return nil, fmt.Errorf("value for %s must not be negative", circuitBreakerFailureThresholdFlagName)
+	}
+
+	cooldown, err := cmdutil.GetDuration(cmd, circuitBreakerCooldownFlagName,
+		circuitBreakerCooldownEnvKey, defaultCircuitBreakerCooldown)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", circuitBreakerCooldownFlagName, err)
+	}
+
+	if cooldown <= 0 {
+		return nil, fmt.Errorf("value for %s must be greater than 0", circuitBreakerCooldownFlagName)
+	}
+
+	return &circuitBreakerParams{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+	}, nil
+}
+
+func getDataURIAutoThreshold(cmd *cobra.Command) (int, error) {
+	threshold, err := cmdutil.GetInt(cmd, dataURIAutoThresholdFlagName, dataURIAutoThresholdEnvKey,
+		defaultDataURIAutoThreshold)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", dataURIAutoThresholdFlagName, err)
+	}
+
+	if threshold < 0 {
+		return 0, fmt.Errorf("value for %s must not be negative", dataURIAutoThresholdFlagName)
+	}
+
+	return threshold, nil
+}
+
+func getAnchorMaxPreviousAnchors(cmd *cobra.Command) (int, error) {
+	maxPreviousAnchors, err := cmdutil.GetInt(cmd, anchorMaxPreviousAnchorsFlagName, anchorMaxPreviousAnchorsEnvKey,
+		defaultAnchorMaxPreviousAnchors)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", anchorMaxPreviousAnchorsFlagName, err)
+	}
+
+	if maxPreviousAnchors < 0 {
+		return 0, fmt.Errorf("value for %s must not be negative", anchorMaxPreviousAnchorsFlagName)
+	}
+
+	return maxPreviousAnchors, nil
+}
+
+func getWebCASPath(cmd *cobra.Command) (string, error) {
+	webCASPath := cmdutil.GetUserSetOptionalVarFromString(cmd, webCASPathFlagName, webCASPathEnvKey)
+	if webCASPath == "" {
+		return defaultWebCASPath, nil
+	}
+
+	if !strings.HasPrefix(webCASPath, "/") {
+		return "", fmt.Errorf("value for %s must start with '/'", webCASPathFlagName)
+	}
+
+	webCASPath = strings.TrimSuffix(webCASPath, "/")
+	if webCASPath == "" {
+		return "", fmt.Errorf("value for %s must not be the root path", webCASPathFlagName)
+	}
+
+	return webCASPath, nil
+}
+
 func getAllowedDIDWebDomains(cmd *cobra.Command) ([]*url.URL, error) {
 	allowedDIDWebDomainsArray, err := cmdutil.GetUserSetVarFromArrayString(cmd, allowedDIDWebDomainsFlagName,
 		allowedDIDWebDomainsEnvKey, true)
@@ -1849,17 +2612,106 @@ func getRequestTokens(cmd *cobra.Command) map[string]string {
 	return tokens
 }
 
-func getAnchorCredentialParameters(cmd *cobra.Command, externalEndpoint, serviceIRI string) *anchorCredentialParams {
+func getAnchorCredentialParameters(cmd *cobra.Command, externalEndpoint, serviceIRI string) (*anchorCredentialParams, error) {
 	domain := cmdutil.GetUserSetOptionalVarFromString(cmd, anchorCredentialDomainFlagName, anchorCredentialDomainEnvKey)
 	if domain == "" {
 		domain = externalEndpoint
 	}
 
-	return &anchorCredentialParams{
-		issuer: serviceIRI,
-		url:    fmt.Sprintf("%s/vc", externalEndpoint),
-		domain: domain,
+	strictValidation, err := cmdutil.GetBool(cmd, anchorCredentialStrictValidationFlagName,
+		anchorCredentialStrictValidationEnvKey, defaultAnchorCredentialStrictValidation)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", anchorCredentialStrictValidationFlagName, err)
+	}
+
+	maxAge, err := cmdutil.GetDuration(cmd, anchorCredentialMaxAgeFlagName, anchorCredentialMaxAgeEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", anchorCredentialMaxAgeFlagName, err)
+	}
+
+	verifyLineage, err := cmdutil.GetBool(cmd, anchorCredentialVerifyLineageFlagName,
+		anchorCredentialVerifyLineageEnvKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", anchorCredentialVerifyLineageFlagName, err)
+	}
+
+	parentResolveTimeout, err := cmdutil.GetDuration(cmd, anchorCredentialParentResolveTimeoutFlagName,
+		anchorCredentialParentResolveTimeoutEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", anchorCredentialParentResolveTimeoutFlagName, err)
+	}
+
+	parentTraversalTimeout, err := cmdutil.GetDuration(cmd, anchorCredentialParentTraversalTimeoutFlagName,
+		anchorCredentialParentTraversalTimeoutEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", anchorCredentialParentTraversalTimeoutFlagName, err)
 	}
+
+	publishQueueSize, err := cmdutil.GetInt(cmd, anchorCredentialPublishQueueSizeFlagName,
+		anchorCredentialPublishQueueSizeEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", anchorCredentialPublishQueueSizeFlagName, err)
+	}
+
+	if publishQueueSize < 0 {
+		return nil, fmt.Errorf("%s: value must not be negative", anchorCredentialPublishQueueSizeFlagName)
+	}
+
+	publishQueueWait, err := cmdutil.GetDuration(cmd, anchorCredentialPublishQueueWaitFlagName,
+		anchorCredentialPublishQueueWaitEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", anchorCredentialPublishQueueWaitFlagName, err)
+	}
+
+	acceptedProfiles, err := asURIs(cmdutil.GetUserSetOptionalVarFromArrayString(cmd,
+		anchorCredentialAcceptedProfilesFlagName, anchorCredentialAcceptedProfilesEnvKey)...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", anchorCredentialAcceptedProfilesFlagName, err)
+	}
+
+	duplicateParentPolicy := credential.DuplicateParentsDedup
+
+	duplicateParentPolicyStr := cmdutil.GetUserSetOptionalVarFromString(cmd,
+		anchorCredentialDuplicateParentPolicyFlagName, anchorCredentialDuplicateParentPolicyEnvKey)
+	if duplicateParentPolicyStr != "" {
+		duplicateParentPolicy, err = credential.ParseDuplicateParentPolicy(duplicateParentPolicyStr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", anchorCredentialDuplicateParentPolicyFlagName, err)
+		}
+	}
+
+	fetchPolicy := credential.AnchorFetchPreferEmbedded
+
+	fetchPolicyStr := cmdutil.GetUserSetOptionalVarFromString(cmd,
+		anchorCredentialFetchPolicyFlagName, anchorCredentialFetchPolicyEnvKey)
+	if fetchPolicyStr != "" {
+		fetchPolicy, err = credential.ParseAnchorFetchPolicy(fetchPolicyStr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", anchorCredentialFetchPolicyFlagName, err)
+		}
+	}
+
+	importEnabled, err := cmdutil.GetBool(cmd, anchorImportEnabledFlagName, anchorImportEnabledEnvKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", anchorImportEnabledFlagName, err)
+	}
+
+	return &anchorCredentialParams{
+		issuer:                 serviceIRI,
+		url:                    fmt.Sprintf("%s/vc", externalEndpoint),
+		domain:                 domain,
+		strictValidation:       strictValidation,
+		maxAge:                 maxAge,
+		verifyLineage:          verifyLineage,
+		parentResolveTimeout:   parentResolveTimeout,
+		parentTraversalTimeout: parentTraversalTimeout,
+		publishQueueSize:       publishQueueSize,
+		publishQueueWait:       publishQueueWait,
+		acceptedProfiles:       acceptedProfiles,
+		duplicateParentPolicy:  duplicateParentPolicy,
+		fetchPolicy:            fetchPolicy,
+		importEnabled:          importEnabled,
+	}, nil
 }
 
 func getDBParameters(cmd *cobra.Command) (*dbParameters, error) {
@@ -2028,20 +2880,22 @@ func getActivityPubPageSize(cmd *cobra.Command) (int, error) {
 }
 
 type mqParams struct {
-	endpoint                  string
-	observerPoolSize          int
-	outboxPoolSize            int
-	inboxPoolSize             int
-	opQueuePoolSize           int
-	anchorLinksetPoolSize     int
-	maxConnectionChannels     int
-	publisherChannelPoolSize  int
-	publisherConfirmDelivery  bool
-	maxConnectRetries         int
-	maxRedeliveryAttempts     int
-	redeliveryMultiplier      float64
-	redeliveryInitialInterval time.Duration
-	maxRedeliveryInterval     time.Duration
+	endpoint                   string
+	observerPoolSize           int
+	observerCatchupConcurrency int
+	observerCatchupThreshold   int
+	outboxPoolSize             int
+	inboxPoolSize              int
+	opQueuePoolSize            int
+	anchorLinksetPoolSize      int
+	maxConnectionChannels      int
+	publisherChannelPoolSize   int
+	publisherConfirmDelivery   bool
+	maxConnectRetries          int
+	maxRedeliveryAttempts      int
+	redeliveryMultiplier       float64
+	redeliveryInitialInterval  time.Duration
+	maxRedeliveryInterval      time.Duration
 }
 
 func getMQParameters(cmd *cobra.Command) (*mqParams, error) {
@@ -2055,6 +2909,26 @@ func getMQParameters(cmd *cobra.Command) (*mqParams, error) {
 		return nil, err
 	}
 
+	mqObserverCatchupConcurrency, err := cmdutil.GetInt(cmd, mqObserverCatchupConcurrencyFlagName,
+		mqObserverCatchupConcurrencyEnvKey, mqDefaultObserverCatchupConcurrency)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", mqObserverCatchupConcurrencyFlagName, err)
+	}
+
+	if mqObserverCatchupConcurrency < 0 {
+		return nil, fmt.Errorf("value for %s must not be negative", mqObserverCatchupConcurrencyFlagName)
+	}
+
+	mqObserverCatchupThreshold, err := cmdutil.GetInt(cmd, mqObserverCatchupThresholdFlagName,
+		mqObserverCatchupThresholdEnvKey, mqDefaultObserverCatchupThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", mqObserverCatchupThresholdFlagName, err)
+	}
+
+	if mqObserverCatchupThreshold < 0 {
+		return nil, fmt.Errorf("value for %s must not be negative", mqObserverCatchupThresholdFlagName)
+	}
+
 	mqOutboxPoolSize, err := cmdutil.GetInt(cmd, mqOutboxPoolFlagName, mqOutboxPoolEnvKey, mqDefaultOutboxPoolSize)
 	if err != nil {
 		return nil, err
@@ -2124,20 +2998,22 @@ func getMQParameters(cmd *cobra.Command) (*mqParams, error) {
 	}
 
 	return &mqParams{
-		endpoint:                  mqURL,
-		observerPoolSize:          mqObserverPoolSize,
-		outboxPoolSize:            mqOutboxPoolSize,
-		inboxPoolSize:             mqInboxPoolSize,
-		anchorLinksetPoolSize:     mqAnchorLinksetPoolSize,
-		maxConnectionChannels:     mqMaxConnectionChannels,
-		publisherChannelPoolSize:  mqPublisherChannelPoolSize,
-		publisherConfirmDelivery:  mqPublisherConfirmDelivery,
-		maxConnectRetries:         mqMaxConnectRetries,
-		maxRedeliveryAttempts:     mqMaxRedeliveryAttempts,
-		redeliveryMultiplier:      mqRedeliveryMultiplier,
-		redeliveryInitialInterval: mqRedeliveryInitialInterval,
-		maxRedeliveryInterval:     mqRedeliveryMaxInterval,
-		opQueuePoolSize:           mqOpQueuePoolSize,
+		endpoint:                   mqURL,
+		observerPoolSize:           mqObserverPoolSize,
+		observerCatchupConcurrency: mqObserverCatchupConcurrency,
+		observerCatchupThreshold:   mqObserverCatchupThreshold,
+		outboxPoolSize:             mqOutboxPoolSize,
+		inboxPoolSize:              mqInboxPoolSize,
+		anchorLinksetPoolSize:      mqAnchorLinksetPoolSize,
+		maxConnectionChannels:      mqMaxConnectionChannels,
+		publisherChannelPoolSize:   mqPublisherChannelPoolSize,
+		publisherConfirmDelivery:   mqPublisherConfirmDelivery,
+		maxConnectRetries:          mqMaxConnectRetries,
+		maxRedeliveryAttempts:      mqMaxRedeliveryAttempts,
+		redeliveryMultiplier:       mqRedeliveryMultiplier,
+		redeliveryInitialInterval:  mqRedeliveryInitialInterval,
+		maxRedeliveryInterval:      mqRedeliveryMaxInterval,
+		opQueuePoolSize:            mqOpQueuePoolSize,
 	}, nil
 }
 
@@ -2205,11 +3081,42 @@ func getTLS(cmd *cobra.Command) (*tlsParameters, error) {
 
 	tlsServeKeyPath := cmdutil.GetUserSetOptionalVarFromString(cmd, tlsKeyFlagName, tlsKeyEnvKey)
 
+	tlsMinVersion := uint16(tls.VersionTLS12)
+
+	tlsMinVersionStr := cmdutil.GetUserSetOptionalVarFromString(cmd, tlsMinVersionFlagName, tlsMinVersionEnvKey)
+	if tlsMinVersionStr != "" {
+		tlsMinVersion, err = transport.ParseTLSVersion(tlsMinVersionStr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tlsMinVersionFlagName, err)
+		}
+	}
+
+	var tlsCipherSuites []uint16
+
+	tlsCipherSuiteNames := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, tlsCipherSuitesFlagName,
+		tlsCipherSuitesEnvKey)
+	if len(tlsCipherSuiteNames) > 0 {
+		if tlsMinVersion == tls.VersionTLS13 {
+			return nil, fmt.Errorf("%s: cipher suites may not be configured when %s is 1.3",
+				tlsCipherSuitesFlagName, tlsMinVersionFlagName)
+		}
+
+		tlsCipherSuites, err = transport.ParseCipherSuites(tlsCipherSuiteNames)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tlsCipherSuitesFlagName, err)
+		}
+	}
+
+	tlsPeerCACerts := cmdutil.GetUserSetOptionalVarFromArrayString(cmd, tlsPeerCACertsFlagName, tlsPeerCACertsEnvKey)
+
 	return &tlsParameters{
 		systemCertPool: tlsSystemCertPool,
 		caCerts:        tlsCACerts,
 		serveCertPath:  tlsServeCertPath,
 		serveKeyPath:   tlsServeKeyPath,
+		minVersion:     tlsMinVersion,
+		cipherSuites:   tlsCipherSuites,
+		peerCACerts:    tlsPeerCACerts,
 	}, nil
 }
 
@@ -2396,13 +3303,22 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsKeyFlagName, tlsKeyFlagShorthand, "", tlsKeyFlagUsage)
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
 	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(tlsMinVersionFlagName, "", "", tlsMinVersionFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCipherSuitesFlagName, "", []string{}, tlsCipherSuitesFlagUsage)
+	startCmd.Flags().StringArrayP(tlsPeerCACertsFlagName, "", []string{}, tlsPeerCACertsFlagUsage)
 	startCmd.Flags().StringP(batchWriterTimeoutFlagName, batchWriterTimeoutFlagShorthand, "", batchWriterTimeoutFlagUsage)
 	startCmd.Flags().StringP(maxWitnessDelayFlagName, maxWitnessDelayFlagShorthand, "", maxWitnessDelayFlagUsage)
 	startCmd.Flags().StringP(maxClockSkewFlagName, "", "", maxClockSkewFlagUsage)
 	startCmd.Flags().StringP(witnessStoreExpiryPeriodFlagName, "", "", witnessStoreExpiryPeriodFlagUsage)
+	startCmd.Flags().String(witnessOfferLifetimeFlagName, "", witnessOfferLifetimeFlagUsage)
 	startCmd.Flags().StringP(signWithLocalWitnessFlagName, signWithLocalWitnessFlagShorthand, "", signWithLocalWitnessFlagUsage)
 	startCmd.Flags().StringP(httpSignaturesEnabledFlagName, httpSignaturesEnabledShorthand, "", httpSignaturesEnabledUsage)
 	startCmd.Flags().String(enableDidDiscoveryFlagName, "", enableDidDiscoveryUsage)
+	startCmd.Flags().String(enableProcessedAnchorLogFlagName, "", enableProcessedAnchorLogUsage)
+	startCmd.Flags().StringArrayP(observerNamespacesFlagName, "", []string{}, observerNamespacesFlagUsage)
+	startCmd.Flags().String(observerValidatePreviousAnchorsFlagName, "", observerValidatePreviousAnchorsFlagUsage)
+	startCmd.Flags().String(webCASDigestHeaderEnabledFlagName, "", webCASDigestHeaderEnabledUsage)
+	startCmd.Flags().String(webCASPathFlagName, "", webCASPathFlagUsage)
 	startCmd.Flags().String(enableUnpublishedOperationStoreFlagName, "", enableUnpublishedOperationStoreUsage)
 	startCmd.Flags().String(unpublishedOperationStoreOperationTypesFlagName, "", unpublishedOperationStoreOperationTypesUsage)
 	startCmd.Flags().String(includeUnpublishedOperationsFlagName, "", includeUnpublishedOperationsUsage)
@@ -2412,8 +3328,13 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(casTypeFlagName, casTypeFlagShorthand, "", casTypeFlagUsage)
 	startCmd.Flags().StringP(ipfsURLFlagName, ipfsURLFlagShorthand, "", ipfsURLFlagUsage)
 	startCmd.Flags().StringP(localCASReplicateInIPFSFlagName, "", "false", localCASReplicateInIPFSFlagUsage)
+	startCmd.Flags().String(casStoreRemoteContentFlagName, "", casStoreRemoteContentFlagUsage)
+	startCmd.Flags().String(casListHashesEnabledFlagName, "", casListHashesEnabledFlagUsage)
+	startCmd.Flags().String(casWriteEnabledFlagName, "", casWriteEnabledFlagUsage)
 	startCmd.Flags().StringP(mqURLFlagName, mqURLFlagShorthand, "", mqURLFlagUsage)
 	startCmd.Flags().StringP(mqObserverPoolFlagName, mqObserverPoolFlagShorthand, "", mqObserverPoolFlagUsage)
+	startCmd.Flags().String(mqObserverCatchupConcurrencyFlagName, "", mqObserverCatchupConcurrencyFlagUsage)
+	startCmd.Flags().String(mqObserverCatchupThresholdFlagName, "", mqObserverCatchupThresholdFlagUsage)
 	startCmd.Flags().StringP(mqOutboxPoolFlagName, "", "", mqOutboxPoolFlagUsage)
 	startCmd.Flags().StringP(mqInboxPoolFlagName, "", "", mqInboxPoolFlagUsage)
 	startCmd.Flags().StringP(mqMaxConnectionChannelsFlagName, mqMaxConnectionChannelsFlagShorthand, "", mqMaxConnectionChannelsFlagUsage)
@@ -2438,6 +3359,21 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringArrayP(allowedOriginsFlagName, allowedOriginsFlagShorthand, []string{}, allowedOriginsFlagUsage)
 	startCmd.Flags().StringArrayP(allowedDIDWebDomainsFlagName, "", []string{}, allowedDIDWebDomainsFlagUsage)
 	startCmd.Flags().StringP(anchorCredentialDomainFlagName, anchorCredentialDomainFlagShorthand, "", anchorCredentialDomainFlagUsage)
+	startCmd.Flags().String(anchorCredentialStrictValidationFlagName, "", anchorCredentialStrictValidationFlagUsage)
+	startCmd.Flags().String(anchorCredentialMaxAgeFlagName, "", anchorCredentialMaxAgeFlagUsage)
+	startCmd.Flags().String(anchorCredentialVerifyLineageFlagName, "", anchorCredentialVerifyLineageFlagUsage)
+	startCmd.Flags().String(anchorCredentialParentResolveTimeoutFlagName, "",
+		anchorCredentialParentResolveTimeoutFlagUsage)
+	startCmd.Flags().String(anchorCredentialParentTraversalTimeoutFlagName, "",
+		anchorCredentialParentTraversalTimeoutFlagUsage)
+	startCmd.Flags().String(anchorCredentialPublishQueueSizeFlagName, "", anchorCredentialPublishQueueSizeFlagUsage)
+	startCmd.Flags().String(anchorCredentialPublishQueueWaitFlagName, "", anchorCredentialPublishQueueWaitFlagUsage)
+	startCmd.Flags().StringArrayP(anchorCredentialAcceptedProfilesFlagName, "", []string{},
+		anchorCredentialAcceptedProfilesFlagUsage)
+	startCmd.Flags().String(anchorCredentialDuplicateParentPolicyFlagName, "",
+		anchorCredentialDuplicateParentPolicyFlagUsage)
+	startCmd.Flags().String(anchorCredentialFetchPolicyFlagName, "", anchorCredentialFetchPolicyFlagUsage)
+	startCmd.Flags().String(anchorImportEnabledFlagName, "", anchorImportEnabledFlagUsage)
 	startCmd.Flags().StringP(databaseTypeFlagName, databaseTypeFlagShorthand, "", databaseTypeFlagUsage)
 	startCmd.Flags().StringP(databaseURLFlagName, databaseURLFlagShorthand, "", databaseURLFlagUsage)
 	startCmd.Flags().StringP(databasePrefixFlagName, "", "", databasePrefixFlagUsage)
@@ -2459,6 +3395,8 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().String(enableVCTFlagName, "false", enableVCTFlagUsage)
 	startCmd.Flags().StringP(nodeInfoRefreshIntervalFlagName, nodeInfoRefreshIntervalFlagShorthand, "", nodeInfoRefreshIntervalFlagUsage)
 	startCmd.Flags().StringP(ipfsTimeoutFlagName, ipfsTimeoutFlagShorthand, "", ipfsTimeoutFlagUsage)
+	startCmd.Flags().String(ipfsRateLimitFlagName, "", ipfsRateLimitFlagUsage)
+	startCmd.Flags().String(ipfsRateLimitBurstFlagName, "", ipfsRateLimitBurstFlagUsage)
 	startCmd.Flags().StringArrayP(contextProviderFlagName, "", []string{}, contextProviderFlagUsage)
 	startCmd.Flags().StringP(databaseTimeoutFlagName, "", "", databaseTimeoutFlagUsage)
 	startCmd.Flags().StringP(unpublishedOperationLifespanFlagName, "", "", unpublishedOperationLifespanFlagUsage)
@@ -2468,6 +3406,11 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(inviteWitnessAuthPolicyFlagName, inviteWitnessAuthPolicyFlagShorthand, "", inviteWitnessAuthPolicyFlagUsage)
 	startCmd.Flags().StringP(httpTimeoutFlagName, "", "", httpTimeoutFlagUsage)
 	startCmd.Flags().StringP(httpDialTimeoutFlagName, "", "", httpDialTimeoutFlagUsage)
+	startCmd.Flags().StringP(httpTLSHandshakeTimeoutFlagName, "", "", httpTLSHandshakeTimeoutFlagUsage)
+	startCmd.Flags().StringP(httpResponseHeaderTimeoutFlagName, "", "", httpResponseHeaderTimeoutFlagUsage)
+	startCmd.Flags().StringP(httpEnableHTTP2FlagName, "", "", httpEnableHTTP2FlagUsage)
+	startCmd.Flags().StringP(httpMaxConcurrentStreamsFlagName, "", "", httpMaxConcurrentStreamsFlagUsage)
+	startCmd.Flags().StringP(httpUserAgentFlagName, "", "", httpUserAgentFlagUsage)
 	startCmd.Flags().StringP(anchorSyncIntervalFlagName, anchorSyncIntervalFlagShorthand, "", anchorSyncIntervalFlagUsage)
 	startCmd.Flags().StringP(anchorSyncAcceleratedIntervalFlagName, "", "", anchorSyncNextIntervalFlagUsage)
 	startCmd.Flags().StringP(anchorSyncMaxActivitiesFlagName, "", "", anchorSyncMaxActivitiesFlagUsage)
@@ -2482,14 +3425,28 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(anchorStatusMonitoringIntervalFlagName, "", "", anchorStatusMonitoringIntervalFlagUsage)
 	startCmd.Flags().StringP(anchorStatusMaxRecordsFlagName, "", "", anchorStatusMaxRecordsFlagUsage)
 	startCmd.Flags().StringP(anchorStatusInProcessGracePeriodFlagName, "", "", anchorStatusInProcessGracePeriodFlagUsage)
+	startCmd.Flags().String(circuitBreakerFailureThresholdFlagName, "", circuitBreakerFailureThresholdFlagUsage)
+	startCmd.Flags().String(circuitBreakerCooldownFlagName, "", circuitBreakerCooldownFlagUsage)
 	startCmd.Flags().StringP(witnessPolicyCacheExpirationFlagName, "", "", witnessPolicyCacheExpirationFlagUsage)
 	startCmd.Flags().StringP(activityPubClientCacheSizeFlagName, "", "", activityPubClientCacheSizeFlagUsage)
 	startCmd.Flags().StringP(activityPubIRICacheSizeFlagName, "", "", activityPubIRICacheSizeFlagUsage)
 	startCmd.Flags().StringP(activityPubIRICacheExpirationFlagName, "", "", activityPubIRICacheExpirationFlagUsage)
+	startCmd.Flags().StringP(activityPubStrictParsingFlagName, "", "", activityPubStrictParsingFlagUsage)
+	startCmd.Flags().String(activityPubOutboxBatchWindowFlagName, "", activityPubOutboxBatchWindowFlagUsage)
+	startCmd.Flags().String(activityPubOutboxBatchMaxItemsFlagName, "", activityPubOutboxBatchMaxItemsFlagUsage)
+	startCmd.Flags().String(activityPubOutboxDeliveryPoolSizeFlagName, "", activityPubOutboxDeliveryPoolSizeFlagUsage)
+	startCmd.Flags().String(activityPubOutboxDeliveryBatchSizeFlagName, "", activityPubOutboxDeliveryBatchSizeFlagUsage)
+	startCmd.Flags().String(activityPubOutboxAtomMaxEntriesFlagName, "", activityPubOutboxAtomMaxEntriesFlagUsage)
+	startCmd.Flags().String(activityPubMaxEmbeddedAnchorSizeFlagName, "", activityPubMaxEmbeddedAnchorSizeFlagUsage)
+	startCmd.Flags().StringArrayP(activityPubAcceptedActivityTypesFlagName, "", []string{},
+		activityPubAcceptedActivityTypesFlagUsage)
 	startCmd.Flags().StringP(activityPubClientCacheExpirationFlagName, "", "", activityPubClientCacheExpirationFlagUsage)
+	startCmd.Flags().String(activityPubClientMaxPagesFlagName, "", activityPubClientMaxPagesFlagUsage)
 	startCmd.Flags().StringP(serverIdleTimeoutFlagName, "", "", serverIdleTimeoutFlagUsage)
 	startCmd.Flags().StringP(serverReadHeaderTimeoutFlagName, "", "", serverReadHeaderTimeoutFlagUsage)
 	startCmd.Flags().StringP(dataURIMediaTypeFlagName, "", "", dataURIMediaTypeFlagUsage)
+	startCmd.Flags().String(dataURIAutoThresholdFlagName, "", dataURIAutoThresholdFlagUsage)
+	startCmd.Flags().String(anchorMaxPreviousAnchorsFlagName, "", anchorMaxPreviousAnchorsFlagUsage)
 	startCmd.Flags().String(sidetreeProtocolVersionsFlagName, "", sidetreeProtocolVersionsUsage)
 	startCmd.Flags().String(currentSidetreeProtocolVersionFlagName, "", currentSidetreeProtocolVersionUsage)
 	startCmd.Flags().StringArray(vcSignKeysIDFlagName, []string{}, vcSignKeysIDFlagUsage)
@@ -2497,6 +3454,7 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(allowedOriginsCacheExpirationFlagName, "", "", allowedOriginsCacheExpirationFlagUsage)
 	startCmd.Flags().String(kmsRegionFlagName, "", kmsRegionFlagUsage)
 	startCmd.Flags().String(anchorRefPendingRecordLifespanFlagName, "", anchorRefPendingRecordLifespanFlagUsage)
+	startCmd.Flags().String(anchorRefProcessedRecordTTLFlagName, "", anchorRefProcessedRecordTTLFlagUsage)
 	startCmd.Flags().StringP(metricsProviderFlagName, "", "", allowedMetricsProviderFlagUsage)
 	startCmd.Flags().StringP(promHTTPURLFlagName, "", "", allowedPromHTTPURLFlagNameUsage)
 	startCmd.Flags().StringP(tracingProviderFlagName, "", "", tracingProviderFlagUsage)
@@ -14,7 +14,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -86,6 +85,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/anchor/allowedorigins/allowedoriginsmgr"
 	"github.com/trustbloc/orb/pkg/anchor/allowedorigins/allowedoriginsrest"
+	"github.com/trustbloc/orb/pkg/anchor/anchorimport"
 	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset"
 	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator"
 	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/vcresthandler"
@@ -95,14 +95,18 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/handler/credential"
 	"github.com/trustbloc/orb/pkg/anchor/handler/proof"
 	"github.com/trustbloc/orb/pkg/anchor/linkstore"
+	"github.com/trustbloc/orb/pkg/anchor/witness/pending"
+	"github.com/trustbloc/orb/pkg/anchor/witness/pending/pendingrest"
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
 	policycfg "github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy/inspector"
 	policyhandler "github.com/trustbloc/orb/pkg/anchor/witness/policy/resthandler"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proofrest"
 	"github.com/trustbloc/orb/pkg/anchor/writer"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	ipfscas "github.com/trustbloc/orb/pkg/cas/ipfs"
 	"github.com/trustbloc/orb/pkg/cas/resolver"
+	"github.com/trustbloc/orb/pkg/circuitbreaker"
 	"github.com/trustbloc/orb/pkg/config"
 	configclient "github.com/trustbloc/orb/pkg/config/client"
 	sidetreecontext "github.com/trustbloc/orb/pkg/context"
@@ -137,11 +141,14 @@ import (
 	"github.com/trustbloc/orb/pkg/pubsub/amqp"
 	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
 	"github.com/trustbloc/orb/pkg/pubsub/spi"
+	"github.com/trustbloc/orb/pkg/pubsub/undeliverable"
+	"github.com/trustbloc/orb/pkg/pubsub/undeliverable/undeliverablerest"
 	"github.com/trustbloc/orb/pkg/resolver/resource"
 	"github.com/trustbloc/orb/pkg/resolver/resource/registry"
 	"github.com/trustbloc/orb/pkg/resolver/resource/registry/didanchorinfo"
 	"github.com/trustbloc/orb/pkg/store"
 	anchorlinkstore "github.com/trustbloc/orb/pkg/store/anchorlink"
+	"github.com/trustbloc/orb/pkg/store/anchorlog"
 	"github.com/trustbloc/orb/pkg/store/anchorstatus"
 	casstore "github.com/trustbloc/orb/pkg/store/cas"
 	didanchorstore "github.com/trustbloc/orb/pkg/store/didanchor"
@@ -152,6 +159,7 @@ import (
 	unpublishedopstore "github.com/trustbloc/orb/pkg/store/operation/unpublished"
 	"github.com/trustbloc/orb/pkg/store/publickey"
 	proofstore "github.com/trustbloc/orb/pkg/store/witness"
+	"github.com/trustbloc/orb/pkg/store/witnessoffer"
 	"github.com/trustbloc/orb/pkg/store/wrapper"
 	"github.com/trustbloc/orb/pkg/taskmgr"
 	cryptoutil "github.com/trustbloc/orb/pkg/util"
@@ -177,12 +185,18 @@ const (
 	defaultSyncTimeout                      = 1
 	defaulthttpSignaturesEnabled            = true
 	defaultDidDiscoveryEnabled              = false
+	defaultProcessedAnchorLogEnabled        = false
+	defaultWebCASDigestHeaderEnabled        = true
+	defaultWebCASPath                       = webcas.DefaultPath
 	defaultUnpublishedOperationStoreEnabled = false
 	defaultIncludeUnpublishedOperations     = false
 	defaultIncludePublishedOperations       = false
 	defaultResolveFromAnchorOrigin          = false
 	defaultVerifyLatestFromAnchorOrigin     = false
 	defaultLocalCASReplicateInIPFSEnabled   = false
+	defaultCASStoreRemoteContent            = true
+	defaultCASListHashesEnabled             = false
+	defaultCASWriteEnabled                  = false
 	defaultDevModeEnabled                   = false
 	defaultMaintenanceModeEnabled           = false
 	defaultVCTEnabled                       = false
@@ -203,8 +217,6 @@ const (
 
 	activityPubServicesPath = "/services/orb"
 
-	casPath = "/cas"
-
 	kmsKeyType           = kms.ED25519Type
 	jsonWebSignature2020 = "JsonWebSignature2020"
 	ed25519Signature2020 = "Ed25519Signature2020"
@@ -515,7 +527,7 @@ func startOrbServices(parameters *orbParameters) error {
 		return err
 	}
 
-	casIRI := mustParseURL(parameters.http.externalEndpoint, casPath)
+	casIRI := mustParseURL(parameters.http.externalEndpoint, parameters.webCASPath)
 
 	coreCASClient, err := newCASClient(parameters, storeProviders.provider, casIRI, metrics)
 	if err != nil {
@@ -598,21 +610,33 @@ func startOrbServices(parameters *orbParameters) error {
 		wfclient.WithCacheSize(defaultWebfingerCacheSize),           // TODO: Define parameter.
 	)
 
-	webCASResolver := resolver.NewWebCASResolver(httpTransport, wfClient, webFingerURIScheme)
+	webCASResolver := resolver.NewWebCASResolver(httpTransport, wfClient, webFingerURIScheme,
+		resolver.WithCircuitBreaker(newCircuitBreaker("webcas", parameters.circuitBreaker, metrics)),
+	)
 
 	var ipfsReader *ipfscas.Client
 	var casResolver *resolver.Resolver
 	if parameters.cas.ipfsURL != "" {
-		ipfsReader = ipfscas.New(parameters.cas.ipfsURL, parameters.cas.ipfsTimeout, defaultCasCacheSize, metrics,
-			extendedcasclient.WithCIDVersion(parameters.cas.cidVersion))
-		casResolver = resolver.New(coreCASClient, ipfsReader, webCASResolver, metrics)
+		ipfsReader = newIPFSClient(parameters, metrics)
+		casResolver = resolver.New(coreCASClient, ipfsReader, webCASResolver, metrics,
+			resolver.WithStoreRemoteContent(parameters.cas.storeRemoteContent))
 	} else {
-		casResolver = resolver.New(coreCASClient, nil, webCASResolver, metrics)
+		casResolver = resolver.New(coreCASClient, nil, webCASResolver, metrics,
+			resolver.WithStoreRemoteContent(parameters.cas.storeRemoteContent))
 	}
 
 	generatorRegistry := generator.NewRegistry()
 
-	anchorLinksetBuilder := anchorlinkset.NewBuilder(generatorRegistry)
+	for _, profile := range parameters.anchorCredentialParams.acceptedProfiles {
+		if _, e := generatorRegistry.Get(profile); e != nil {
+			return fmt.Errorf("accepted anchor profile [%s] is not supported: %w", profile, e)
+		}
+	}
+
+	anchorLinksetBuilder := anchorlinkset.NewBuilder(generatorRegistry,
+		anchorlinkset.WithDataURIAutoThreshold(parameters.dataURIAutoThreshold),
+		anchorlinkset.WithMaxPreviousAnchors(parameters.anchorMaxPreviousAnchors),
+	)
 
 	graphProviders := &graph.Providers{
 		CasResolver:          casResolver,
@@ -659,6 +683,12 @@ func startOrbServices(parameters *orbParameters) error {
 		return fmt.Errorf("failed to get protocol client for namespace [%s]: %w", parameters.sidetree.didNamespace, err)
 	}
 
+	for _, ns := range parameters.observerNamespaces {
+		if _, e := pcp.ForNamespace(ns); e != nil {
+			return fmt.Errorf("invalid value for %s: %w", observerNamespacesFlagName, e)
+		}
+	}
+
 	pubKeys, signatureSuiteType, err := getPublicKeys(parameters, km)
 	if err != nil {
 		return err
@@ -707,6 +737,12 @@ func startOrbServices(parameters *orbParameters) error {
 		return fmt.Errorf("failed to create proof store: %s", err.Error())
 	}
 
+	witnessOfferStore, err := witnessoffer.New(storeProviders.provider, expiryService,
+		parameters.witnessProof.witnessOfferLifetime, metrics)
+	if err != nil {
+		return fmt.Errorf("failed to create witness offer store: %w", err)
+	}
+
 	var processorOpts []processor.Option
 	if parameters.unpublishedOperations.enabled {
 		processorOpts = append(processorOpts, processor.WithUnpublishedOperationStore(updateDocumentStore))
@@ -767,6 +803,8 @@ func startOrbServices(parameters *orbParameters) error {
 	apClient := client.New(client.Config{
 		CacheSize:            parameters.activityPub.clientCacheSize,
 		CacheRefreshInterval: parameters.activityPub.clientCacheExpiration,
+		MaxPages:             parameters.activityPub.clientMaxPages,
+		CircuitBreaker:       newCircuitBreaker("activitypub-client", parameters.circuitBreaker, metrics),
 	}, httpTransport, publicKeyFetcher, resourceResolver)
 
 	apSigVerifier := getActivityPubVerifier(parameters, km, cr, apClient)
@@ -820,6 +858,13 @@ func startOrbServices(parameters *orbParameters) error {
 
 	pubSub := newPubSub(parameters)
 
+	undeliverableStore, err := undeliverable.New(pubSub)
+	if err != nil {
+		return fmt.Errorf("create undeliverable message store: %w", err)
+	}
+
+	pendingWitnessAnchors := pending.New(metrics)
+
 	proofHandler := proof.New(
 		&proof.Providers{
 			AnchorLinkStore: alStore,
@@ -829,8 +874,10 @@ func startOrbServices(parameters *orbParameters) error {
 			WitnessStore:    witnessProofStore,
 			WitnessPolicy:   witnessPolicy,
 			Metrics:         metrics,
+			PendingAnchors:  pendingWitnessAnchors,
 		},
 		pubSub, parameters.dataURIMediaType, parameters.witnessProof.maxClockSkew,
+		proof.WithDataURIAutoThreshold(parameters.dataURIAutoThreshold),
 	)
 
 	witness := vct.New(configclient.New(configStore), vcSigner, metrics,
@@ -843,7 +890,9 @@ func startOrbServices(parameters *orbParameters) error {
 	logMonitorHandler := handler.New(logMonitorStore, wfClient)
 
 	anchorLinkStore, err := linkstore.New(storeProviders.provider, expiryService,
-		linkstore.WithPendingRecordLifespan(parameters.anchorRefPendingRecordLifespan))
+		linkstore.WithPendingRecordLifespan(parameters.anchorRefPendingRecordLifespan),
+		linkstore.WithProcessedAnchorTTL(parameters.anchorRefProcessedRecordTTL),
+	)
 	if err != nil {
 		return fmt.Errorf("open store: %w", err)
 	}
@@ -865,10 +914,26 @@ func startOrbServices(parameters *orbParameters) error {
 		MonitoringSvc:          proofMonitoringSvc,
 	}
 
+	var processedAnchorLog *anchorlog.Store
+
+	if parameters.processedAnchorLogEnabled {
+		processedAnchorLog, err = anchorlog.New(storeProviders.provider)
+		if err != nil {
+			return fmt.Errorf("open processed-anchor log: %w", err)
+		}
+
+		providers.ProcessedAnchorLog = processedAnchorLog
+	}
+
 	obsrv, err := observer.New(parameters.apServiceParams.serviceIRI(), providers,
 		observer.WithDiscoveryDomain(parameters.discoveryDomain),
 		observer.WithSubscriberPoolSize(parameters.mqParams.observerPoolSize),
+		observer.WithCatchupConcurrency(parameters.mqParams.observerCatchupConcurrency),
+		observer.WithCatchupThreshold(parameters.mqParams.observerCatchupThreshold),
 		observer.WithProofMonitoringExpiryPeriod(parameters.witnessProof.proofMonitoringExpiryPeriod),
+		observer.WithStrictCredentialValidation(parameters.anchorCredentialParams.strictValidation),
+		observer.WithNamespaces(parameters.observerNamespaces...),
+		observer.WithPreviousAnchorValidation(parameters.observerValidatePrevious),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create observer: %w", err)
@@ -893,6 +958,21 @@ func startOrbServices(parameters *orbParameters) error {
 		return fmt.Errorf("failed to register anchor sync task: %w", err)
 	}
 
+	anchorCredentialHandler := credential.New(
+		obsrv.Publisher(), casResolver, orbDocumentLoader, anchorLinkStore, generatorRegistry,
+		credential.WithStrictValidation(parameters.anchorCredentialParams.strictValidation),
+		credential.WithMaxAnchorAge(parameters.anchorCredentialParams.maxAge),
+		credential.WithLineageVerification(parameters.anchorCredentialParams.verifyLineage),
+		credential.WithParentResolveTimeout(parameters.anchorCredentialParams.parentResolveTimeout),
+		credential.WithParentTraversalTimeout(parameters.anchorCredentialParams.parentTraversalTimeout),
+		credential.WithPublishQueue(parameters.anchorCredentialParams.publishQueueSize,
+			parameters.anchorCredentialParams.publishQueueWait),
+		credential.WithMetrics(metrics),
+		credential.WithAcceptedProfiles(parameters.anchorCredentialParams.acceptedProfiles...),
+		credential.WithDuplicateParentPolicy(parameters.anchorCredentialParams.duplicateParentPolicy),
+		credential.WithAnchorFetchPolicy(parameters.anchorCredentialParams.fetchPolicy),
+	)
+
 	apConfig := &apservice.Config{
 		ServicePath:              parameters.apServiceParams.serviceEndpoint().Path,
 		ServiceIRI:               parameters.apServiceParams.serviceIRI(),
@@ -903,6 +983,10 @@ func startOrbServices(parameters *orbParameters) error {
 		IRICacheExpiration:       parameters.activityPub.iriCacheExpiration,
 		OutboxSubscriberPoolSize: parameters.mqParams.outboxPoolSize,
 		InboxSubscriberPoolSize:  parameters.mqParams.inboxPoolSize,
+		OutboxDeliveryPoolSize:   parameters.activityPub.outboxDeliveryPoolSize,
+		OutboxDeliveryBatchSize:  parameters.activityPub.outboxDeliveryBatchSize,
+		StrictActivityParsing:    parameters.activityPub.strictParsing,
+		AcceptedActivityTypes:    parameters.activityPub.acceptedActivityTypes,
 	}
 
 	activityPubService, err = apservice.New(apConfig,
@@ -911,13 +995,12 @@ func startOrbServices(parameters *orbParameters) error {
 		apspi.WithAcceptFollowHandler(logMonitorHandler),
 		apspi.WithUndoFollowHandler(logMonitorHandler),
 		apspi.WithWitness(witness),
-		apspi.WithAnchorEventHandler(credential.New(
-			obsrv.Publisher(), casResolver, orbDocumentLoader, parameters.witnessProof.maxWitnessDelay,
-			anchorLinkStore, generatorRegistry,
-		)),
+		apspi.WithAnchorEventHandler(anchorCredentialHandler),
 		apspi.WithInviteWitnessAuth(newAcceptRejectHandler(activityhandler.InviteWitnessType, parameters.auth.inviteWitnessPolicy, configStore)),
 		apspi.WithFollowAuth(newAcceptRejectHandler(activityhandler.FollowType, parameters.auth.followPolicy, configStore)),
 		apspi.WithAnchorEventAcknowledgementHandler(anchorEventHandler),
+		apspi.WithOutboxBatching(parameters.activityPub.outboxBatchWindow, parameters.activityPub.outboxBatchMaxItems),
+		apspi.WithWitnessOfferStore(witnessOfferStore),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create ActivityPub service: %s", err.Error())
@@ -1022,6 +1105,8 @@ func startOrbServices(parameters *orbParameters) error {
 		ServiceEndpointURL:     parameters.apServiceParams.serviceEndpoint(),
 		VerifyActorInSignature: parameters.auth.httpSignaturesEnabled,
 		PageSize:               parameters.activityPub.pageSize,
+		AnchorResolver:         casResolver,
+		MaxEmbeddedAnchorSize:  parameters.activityPub.maxEmbeddedAnchorSize,
 	}
 
 	didDiscovery := localdiscovery.New(parameters.sidetree.didNamespace, obsrv.Publisher(), endpointClient)
@@ -1062,6 +1147,11 @@ func startOrbServices(parameters *orbParameters) error {
 	webResolveHandler := webresolver.NewResolveHandler(allowedDIDWebDomains, parameters.sidetree.didNamespace,
 		unpublishedDIDLabel, orbResolveHandler, metrics)
 
+	currentProtocol, err := pc.Current()
+	if err != nil {
+		return fmt.Errorf("get current protocol version: %w", err)
+	}
+
 	// create discovery rest api
 	endpointDiscoveryOp, err := discoveryrest.New(
 		&discoveryrest.Config{
@@ -1069,11 +1159,13 @@ func startOrbServices(parameters *orbParameters) error {
 			HTTPSignPubKeys:           httpSignPubKeys,
 			ResolutionPath:            baseResolvePath,
 			OperationPath:             baseUpdatePath,
-			WebCASPath:                casPath,
+			WebCASPath:                parameters.webCASPath,
 			DiscoveryDomains:          parameters.discovery.domains,
 			DiscoveryMinimumResolvers: parameters.discovery.minimumResolvers,
 			ServiceID:                 parameters.apServiceParams.serviceIRI(),
 			ServiceEndpointURL:        parameters.apServiceParams.serviceEndpoint(),
+			MaxOperationsPerBatch:     int(currentProtocol.Protocol().MaxOperationCount),
+			BatchWriterTimeout:        parameters.batchWriterTimeout,
 		},
 		&discoveryrest.Providers{
 			ResourceRegistry:     resourceRegistry,
@@ -1144,21 +1236,25 @@ func startOrbServices(parameters *orbParameters) error {
 		aphandler.NewInbox(apEndpointCfg, apStore, apSigVerifier, activitypubspi.SortAscending, authTokenManager),
 		aphandler.NewWitnesses(apEndpointCfg, apStore, apSigVerifier, authTokenManager),
 		aphandler.NewWitnessing(apEndpointCfg, apStore, apSigVerifier, authTokenManager),
-		aphandler.NewLiked(apEndpointCfg, apStore, apSigVerifier, authTokenManager),
+		aphandler.NewLiked(apEndpointCfg, apStore, apSigVerifier, activitypubspi.SortAscending, authTokenManager),
 		aphandler.NewLikes(apEndpointCfg, apStore, apSigVerifier, activitypubspi.SortAscending, authTokenManager),
 		aphandler.NewShares(apEndpointCfg, apStore, apSigVerifier, activitypubspi.SortAscending, authTokenManager),
 		aphandler.NewPostOutbox(apEndpointCfg, activityPubService.Outbox(), apStore, apSigVerifier, authTokenManager),
 		aphandler.NewActivity(apEndpointCfg, apStore, apSigVerifier, activitypubspi.SortAscending, authTokenManager),
 		webcas.New(
 			&aphandler.Config{
+				BasePath:               parameters.webCASPath,
 				ObjectIRI:              parameters.apServiceParams.serviceIRI(),
 				VerifyActorInSignature: parameters.auth.httpSignaturesEnabled,
 				PageSize:               parameters.activityPub.pageSize,
 			},
 			apStore, apSigVerifier, coreCASClient, authTokenManager,
+			webcas.WithDigestHeader(parameters.webCASDigestHeaderEnabled),
 		),
 		auth.NewHandlerWrapper(policyhandler.New(policyStore), authTokenManager),
 		auth.NewHandlerWrapper(policyhandler.NewRetriever(policyStore), authTokenManager),
+		auth.NewHandlerWrapper(policyhandler.NewSimulator(policyStore, witnessProofStore), authTokenManager),
+		auth.NewHandlerWrapper(policyhandler.NewSuggester(witnessProofStore), authTokenManager),
 		auth.NewHandlerWrapper(logmonitorhandler.NewUpdateHandler(logMonitorStore), authTokenManager),
 		auth.NewHandlerWrapper(logmonitorhandler.NewRetriever(logMonitorStore), authTokenManager),
 		auth.NewHandlerWrapper(vcthandler.New(configStore, logMonitorStore), authTokenManager),
@@ -1168,6 +1264,19 @@ func startOrbServices(parameters *orbParameters) error {
 		auth.NewHandlerWrapper(vcresthandler.New(vcStore), authTokenManager),
 		auth.NewHandlerWrapper(allowedoriginsrest.NewWriter(allowedOriginsStore), authTokenManager),
 		auth.NewHandlerWrapper(allowedoriginsrest.NewReader(allowedOriginsStore), authTokenManager),
+		auth.NewHandlerWrapper(undeliverablerest.NewReader(undeliverableStore), authTokenManager),
+		auth.NewHandlerWrapper(undeliverablerest.NewRequeuer(undeliverableStore), authTokenManager),
+		auth.NewHandlerWrapper(pendingrest.NewReader(pendingWitnessAnchors), authTokenManager),
+		auth.NewHandlerWrapper(proofrest.NewReader(parameters.sidetree.didNamespace,
+			parameters.apServiceParams.serviceIRI(),
+			&proofrest.Providers{
+				DidAnchors:    didAnchors,
+				AnchorGraph:   anchorGraph,
+				WitnessPolicy: witnessPolicy,
+				ActivityStore: apStore,
+				DocLoader:     orbDocumentLoader,
+			},
+		), authTokenManager),
 		auth.NewHandlerWrapper(loglevels.NewWriteHandler(), authTokenManager),
 		auth.NewHandlerWrapper(loglevels.NewReadHandler(), authTokenManager),
 	)
@@ -1182,7 +1291,53 @@ func startOrbServices(parameters *orbParameters) error {
 		)
 	}
 
-	handlers = append(handlers, healthcheck.NewHandler(pubSub, logEndpoint, storeProviders.provider, km, parameters.enableMaintenanceMode))
+	if parameters.activityPub.outboxAtomMaxEntries > 0 {
+		// The Atom feed only contains public activities so it isn't wrapped with an authorization handler.
+		handlers = append(handlers,
+			aphandler.NewOutboxAtom(apEndpointCfg, apStore, parameters.activityPub.outboxAtomMaxEntries),
+		)
+	}
+
+	if processedAnchorLog != nil {
+		handlers = append(handlers,
+			aphandler.NewProcessedAnchors(apEndpointCfg, apStore, processedAnchorLog, apSigVerifier, authTokenManager),
+		)
+	}
+
+	if parameters.anchorCredentialParams.importEnabled {
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(anchorimport.New(anchorCredentialHandler, anchorLinkStore), authTokenManager),
+		)
+	}
+
+	if parameters.cas.listHashesEnabled {
+		if localCAS, ok := coreCASClient.(*casstore.CAS); ok {
+			handlers = append(handlers,
+				aphandler.NewCASHashes(apEndpointCfg, apStore, localCAS, apSigVerifier, authTokenManager),
+			)
+		} else {
+			logger.Warn("Listing CAS hashes is only supported with a local CAS. The CAS hashes endpoint is disabled.")
+		}
+	}
+
+	if parameters.cas.writeEnabled {
+		if localCAS, ok := coreCASClient.(*casstore.CAS); ok {
+			handlers = append(handlers,
+				webcas.NewWriter(
+					&aphandler.Config{
+						BasePath:  parameters.webCASPath,
+						ObjectIRI: parameters.apServiceParams.serviceIRI(),
+					},
+					apStore, localCAS, authTokenManager,
+				),
+			)
+		} else {
+			logger.Warn("Writing to the WebCAS endpoint is only supported with a local CAS. WebCAS writes are disabled.")
+		}
+	}
+
+	handlers = append(handlers, healthcheck.NewHandler(pubSub, logEndpoint, storeProviders.provider, km,
+		parameters.enableMaintenanceMode, healthcheck.WithObserver(obsrv)))
 
 	httpServer := httpserver.New(
 		parameters.http.hostURL,
@@ -1195,7 +1350,7 @@ func startOrbServices(parameters *orbParameters) error {
 		httpserver.WithHandlers(handlers...),
 	)
 
-	err = run(httpServer, activityPubService, opQueue, obsrv, batchWriter, taskMgr, apClient,
+	err = run(httpServer, undeliverableStore, activityPubService, opQueue, obsrv, batchWriter, taskMgr, apClient,
 		nodeInfoService, newMPLifecycleWrapper(mp), tracerProvider, proofMonitoringSvc,
 		anchorEventStatusStore)
 	if err != nil {
@@ -1223,20 +1378,37 @@ func newHTTPClient(parameters *orbParameters) (*http.Client, error) {
 		}
 	}
 
-	var httpTransport http.RoundTripper = &http.Transport{
-		TLSClientConfig: tlsConfig,
-		DialContext: (&net.Dialer{
-			Timeout:   parameters.http.dialTimeout,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConns:          2000,
-		MaxConnsPerHost:       100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   5 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
+	transportOpts := []transport.ClientOption{
+		transport.WithTLSConfig(tlsConfig),
+		transport.WithDialTimeout(parameters.http.dialTimeout),
+		transport.WithTLSHandshakeTimeout(parameters.http.tlsHandshakeTimeout),
+		transport.WithResponseHeaderTimeout(parameters.http.responseHeaderTimeout),
+		transport.WithMinTLSVersion(parameters.http.tls.minVersion),
+		transport.WithHTTP2(parameters.http.enableHTTP2),
+	}
+
+	if len(parameters.http.tls.cipherSuites) > 0 {
+		transportOpts = append(transportOpts, transport.WithCipherSuites(parameters.http.tls.cipherSuites...))
+	}
+
+	if len(parameters.http.tls.peerCACerts) > 0 {
+		peerCAPool, e := tlsutil.GetCertPool(false, parameters.http.tls.peerCACerts)
+		if e != nil {
+			return nil, fmt.Errorf("load peer CA certificates: %w", e)
+		}
+
+		transportOpts = append(transportOpts, transport.WithPeerCAPool(peerCAPool))
+	}
+
+	httpTr, err := transport.NewHTTPTransport(transportOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("create HTTP transport: %w", err)
 	}
 
+	httpTransport := transport.NewStreamLimiter(
+		transport.NewHeaderInjector(httpTr, parameters.http.userAgent), parameters.http.maxConcurrentStreams,
+	)
+
 	if parameters.observability.tracing.enabled {
 		httpTransport = otelhttp.NewTransport(httpTransport)
 	}
@@ -1247,6 +1419,13 @@ func newHTTPClient(parameters *orbParameters) (*http.Client, error) {
 	}, nil
 }
 
+func newIPFSClient(parameters *orbParameters, metrics metricsProvider.Metrics) *ipfscas.Client {
+	return ipfscas.NewWithOptions(parameters.cas.ipfsURL, parameters.cas.ipfsTimeout, defaultCasCacheSize, metrics,
+		ipfscas.WithCIDFormatOptions(extendedcasclient.WithCIDVersion(parameters.cas.cidVersion)),
+		ipfscas.WithRateLimit(parameters.cas.ipfsRateLimit, parameters.cas.ipfsRateLimitBurst),
+	)
+}
+
 func newCASClient(parameters *orbParameters, p dbProvider, casIRI *url.URL,
 	metrics metricsProvider.Metrics,
 ) (extendedcasclient.Client, error) {
@@ -1254,8 +1433,7 @@ func newCASClient(parameters *orbParameters, p dbProvider, casIRI *url.URL,
 	case strings.EqualFold(parameters.cas.casType, "ipfs"):
 		logger.Info("Initializing Orb CAS with IPFS.")
 
-		return ipfscas.New(parameters.cas.ipfsURL, parameters.cas.ipfsTimeout, defaultCasCacheSize, metrics,
-			extendedcasclient.WithCIDVersion(parameters.cas.cidVersion)), nil
+		return newIPFSClient(parameters, metrics), nil
 	case strings.EqualFold(parameters.cas.casType, "local"):
 		logger.Info("Initializing Orb CAS with local storage provider.")
 
@@ -1263,8 +1441,7 @@ func newCASClient(parameters *orbParameters, p dbProvider, casIRI *url.URL,
 			logger.Info("Local CAS writes will be replicated in IPFS.")
 
 			return casstore.New(p, casIRI.String(),
-				ipfscas.New(parameters.cas.ipfsURL, parameters.cas.ipfsTimeout, defaultCasCacheSize, metrics,
-					extendedcasclient.WithCIDVersion(parameters.cas.cidVersion)),
+				newIPFSClient(parameters, metrics),
 				metrics, defaultCasCacheSize, extendedcasclient.WithCIDVersion(parameters.cas.cidVersion))
 		} else {
 			return casstore.New(p, casIRI.String(), nil,
@@ -1458,6 +1635,11 @@ func getProtocolClientProvider(parameters *orbParameters, casClient casapi.Clien
 		protocolVersions = append(protocolVersions, pv)
 	}
 
+	err := validateAnchorMaxPreviousAnchors(parameters.anchorMaxPreviousAnchors, protocolVersions)
+	if err != nil {
+		return nil, err
+	}
+
 	pcp := orbpcp.New()
 
 	var pcOpts []orbpc.Option
@@ -1475,6 +1657,27 @@ func getProtocolClientProvider(parameters *orbParameters, casClient casapi.Clien
 	return pcp, nil
 }
 
+// validateAnchorMaxPreviousAnchors ensures that the maximum number of previous anchors in an anchor linkset isn't
+// less than the maximum number of operations in a batch for any of the protocol versions. Otherwise a full batch
+// would be rejected by the anchor linkset builder and the batch writer would re-cut the same batch indefinitely.
+func validateAnchorMaxPreviousAnchors(maxPreviousAnchors int, protocolVersions []protocol.Version) error {
+	if maxPreviousAnchors == 0 {
+		return nil
+	}
+
+	for _, pv := range protocolVersions {
+		maxOperationCount := pv.Protocol().MaxOperationCount
+
+		if uint(maxPreviousAnchors) < maxOperationCount {
+			return fmt.Errorf("invalid value for %s: %d is less than the maximum number of operations "+
+				"per batch [%d] for protocol version [%s]", anchorMaxPreviousAnchorsFlagName, maxPreviousAnchors,
+				maxOperationCount, pv.Version())
+		}
+	}
+
+	return nil
+}
+
 func createActivityPubStore(storageProvider dbProvider, serviceEndpoint string) (activitypubspi.Store, error) {
 	switch strings.ToLower(storageProvider.DBType()) {
 	case databaseTypeMongoDBOption:
@@ -1874,6 +2077,16 @@ func asURIs(strs ...string) ([]*url.URL, error) {
 	return uris, nil
 }
 
+func newCircuitBreaker(name string, params *circuitBreakerParams,
+	metrics metricsProvider.Metrics,
+) *circuitbreaker.Breaker {
+	return circuitbreaker.New(name,
+		circuitbreaker.WithFailureThreshold(params.failureThreshold),
+		circuitbreaker.WithCooldown(params.cooldown),
+		circuitbreaker.WithMetrics(metrics),
+	)
+}
+
 func newMetricsProvider(parameters *orbParameters) metricsProvider.Provider {
 	switch parameters.observability.metrics.providerName {
 	case "prometheus":
@@ -19,6 +19,9 @@ import (
 	ariesspi "github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/stretchr/testify/require"
 	awssvc "github.com/trustbloc/kms/pkg/aws"
+	coreprotocol "github.com/trustbloc/sidetree-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-svc-go/pkg/api/protocol"
+	sidetreemocks "github.com/trustbloc/sidetree-svc-go/pkg/mocks"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
@@ -397,3 +400,26 @@ func TestMonitorActivities(t *testing.T) {
 	require.Contains(t, l.getInfos(),
 		"Received activity activityId=https://domain2.com/456 activityType=Accept actorId=https://domain1.com/123")
 }
+
+func TestValidateAnchorMaxPreviousAnchors(t *testing.T) {
+	protocolVersions := []protocol.Version{
+		sidetreemocks.GetProtocolVersion(coreprotocol.Protocol{MaxOperationCount: 10}),
+		sidetreemocks.GetProtocolVersion(coreprotocol.Protocol{MaxOperationCount: 100}),
+	}
+
+	t.Run("Not set", func(t *testing.T) {
+		require.NoError(t, validateAnchorMaxPreviousAnchors(0, protocolVersions))
+	})
+
+	t.Run("Greater than or equal to max operation count", func(t *testing.T) {
+		require.NoError(t, validateAnchorMaxPreviousAnchors(100, protocolVersions))
+		require.NoError(t, validateAnchorMaxPreviousAnchors(1000, protocolVersions))
+	})
+
+	t.Run("Less than max operation count", func(t *testing.T) {
+		err := validateAnchorMaxPreviousAnchors(50, protocolVersions)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for "+anchorMaxPreviousAnchorsFlagName+
+			": 50 is less than the maximum number of operations per batch [100]")
+	})
+}
@@ -145,6 +145,7 @@ const (
 	FieldSource                   = "source"
 	FieldAge                      = "age"
 	FieldMinAge                   = "minAge"
+	FieldMaxAge                   = "maxAge"
 	FieldLogSpec                  = "logSpec"
 	FieldTracingProvider          = "tracingProvider"
 	FieldMaxOperationsToRepost    = "maxOperationsToRepost"
@@ -152,6 +153,15 @@ const (
 	FieldNumActivitiesSynced      = "numActivitiesSynced"
 	FieldNextActivitySyncInterval = "nextActivitySyncInterval"
 	FieldRecordsProcessed         = "recordsProcessed"
+	FieldMaxPages                 = "maxPages"
+	FieldHost                     = "host"
+	FieldCircuitBreaker           = "circuitBreaker"
+	FieldCircuitState             = "circuitState"
+	FieldConcurrency              = "concurrency"
+	FieldThreshold                = "threshold"
+	FieldExpiryTime               = "expiryTime"
+	FieldRequestID                = "requestId"
+	FieldUserAgent                = "userAgent"
 )
 
 // WithMessageID sets the message-id field.
@@ -875,6 +885,11 @@ func WithMinAge(value time.Duration) zap.Field {
 	return zap.Duration(FieldMinAge, value)
 }
 
+// WithMaxAge sets the max-age field.
+func WithMaxAge(value time.Duration) zap.Field {
+	return zap.Duration(FieldMaxAge, value)
+}
+
 // WithLogSpec sets the logSpec field.
 func WithLogSpec(value string) zap.Field {
 	return zap.String(FieldLogSpec, value)
@@ -910,6 +925,51 @@ func WithRecordsProcessed(value int) zap.Field {
 	return zap.Int(FieldRecordsProcessed, value)
 }
 
+// WithMaxPages sets the maxPages field.
+func WithMaxPages(value int) zap.Field {
+	return zap.Int(FieldMaxPages, value)
+}
+
+// WithHost sets the host field.
+func WithHost(value string) zap.Field {
+	return zap.String(FieldHost, value)
+}
+
+// WithCircuitBreaker sets the circuitBreaker field.
+func WithCircuitBreaker(value string) zap.Field {
+	return zap.String(FieldCircuitBreaker, value)
+}
+
+// WithCircuitState sets the circuitState field.
+func WithCircuitState(value fmt.Stringer) zap.Field {
+	return zap.Stringer(FieldCircuitState, value)
+}
+
+// WithConcurrency sets the concurrency field.
+func WithConcurrency(value int) zap.Field {
+	return zap.Int(FieldConcurrency, value)
+}
+
+// WithThreshold sets the threshold field.
+func WithThreshold(value int) zap.Field {
+	return zap.Int(FieldThreshold, value)
+}
+
+// WithExpiryTime sets the expiryTime field.
+func WithExpiryTime(value time.Time) zap.Field {
+	return zap.Time(FieldExpiryTime, value)
+}
+
+// WithRequestID sets the requestId field.
+func WithRequestID(value string) zap.Field {
+	return zap.String(FieldRequestID, value)
+}
+
+// WithUserAgent sets the userAgent field.
+func WithUserAgent(value string) zap.Field {
+	return zap.String(FieldUserAgent, value)
+}
+
 type jsonMarshaller struct {
 	key string
 	obj interface{}
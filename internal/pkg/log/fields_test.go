@@ -239,6 +239,7 @@ func TestStandardFields(t *testing.T) {
 			WithLogMonitor(logMonitor), WithLogMonitors([]*mockObject{logMonitor, logMonitor}),
 			WithMaxTime(time.Hour), WithIndex(3), WithFromIndexUint64(9), WithToIndexUint64(13),
 			WithSource("inbox"), WithAge(time.Minute), WithMinAge(10*time.Minute),
+			WithMaxAge(time.Hour),
 		)
 
 		l := unmarshalLogData(t, stdOut.Bytes())
@@ -287,18 +288,24 @@ func TestStandardFields(t *testing.T) {
 		require.Equal(t, "inbox", l.Source)
 		require.Equal(t, "1m0s", l.Age)
 		require.Equal(t, "10m0s", l.MinAge)
+		require.Equal(t, "1h0m0s", l.MaxAge)
 	})
 
 	t.Run("json fields 4", func(t *testing.T) {
 		const logSpec = "module1=DEBUG:INFO"
 
+		now := time.Now()
+
 		stdOut := newMockWriter()
 
 		logger := log.New(module, log.WithStdOut(stdOut), log.WithEncoding(log.JSON))
 
 		logger.Info("Some message",
 			WithMaxSizeUInt64(30), WithURLString(u1.String()), WithLogURLString(u3.String()), WithIndexUint64(7),
-			WithLogSpec(logSpec),
+			WithLogSpec(logSpec), WithMaxPages(100), WithHost("orb.domain1.com"),
+			WithCircuitBreaker("webcas"), WithCircuitState(mockStringer("open")),
+			WithConcurrency(20), WithThreshold(5), WithExpiryTime(now),
+			WithRequestID("6f0c4a0e-4f5b-4bd2-9a44-2d3b5e1c7f10"), WithUserAgent("orb/1.0.0"),
 		)
 
 		l := unmarshalLogData(t, stdOut.Bytes())
@@ -308,6 +315,15 @@ func TestStandardFields(t *testing.T) {
 		require.Equal(t, u3.String(), l.LogURL)
 		require.Equal(t, 7, l.Index)
 		require.Equal(t, logSpec, l.LogSpec)
+		require.Equal(t, 100, l.MaxPages)
+		require.Equal(t, "orb.domain1.com", l.Host)
+		require.Equal(t, "webcas", l.CircuitBreaker)
+		require.Equal(t, "open", l.CircuitState)
+		require.Equal(t, 20, l.Concurrency)
+		require.Equal(t, 5, l.Threshold)
+		require.Equal(t, now.Format("2006-01-02T15:04:05.000Z0700"), l.ExpiryTime)
+		require.Equal(t, "6f0c4a0e-4f5b-4bd2-9a44-2d3b5e1c7f10", l.RequestID)
+		require.Equal(t, "orb/1.0.0", l.UserAgent)
 	})
 }
 
@@ -316,6 +332,12 @@ type mockObject struct {
 	Field2 int    `json:"field2"`
 }
 
+type mockStringer string
+
+func (s mockStringer) String() string {
+	return string(s)
+}
+
 type logData struct {
 	Level  string `json:"level"`
 	Time   string `json:"time"`
@@ -448,12 +470,22 @@ type logData struct {
 	Source                   string              `json:"source"`
 	Age                      string              `json:"age"`
 	MinAge                   string              `json:"minAge"`
+	MaxAge                   string              `json:"maxAge"`
 	LogSpec                  string              `json:"logSpec"`
 	MaxOperationsToRepost    int                 `json:"maxOperationsToRepost"`
 	MaxActivitiesToSync      int                 `json:"maxActivitiesToSync"`
 	NextActivitySyncInterval string              `json:"nextActivitySyncInterval"`
 	NumActivitiesSynced      int                 `json:"numActivitiesSynced"`
 	RecordsProcessed         int                 `json:"recordsProcessed"`
+	MaxPages                 int                 `json:"maxPages"`
+	Host                     string              `json:"host"`
+	CircuitBreaker           string              `json:"circuitBreaker"`
+	CircuitState             string              `json:"circuitState"`
+	Concurrency              int                 `json:"concurrency"`
+	Threshold                int                 `json:"threshold"`
+	ExpiryTime               string              `json:"expiryTime"`
+	RequestID                string              `json:"requestId"`
+	UserAgent                string              `json:"userAgent"`
 }
 
 func unmarshalLogData(t *testing.T, b []byte) *logData {
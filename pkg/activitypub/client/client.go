@@ -39,10 +39,24 @@ var logger = log.New("activitypub_client")
 const (
 	defaultCacheExpiration    = time.Minute
 	defaultMaxRefreshAttempts = 60
+	defaultMaxPages           = 10000
 )
 
-// ErrNotFound is returned when the object is not found or the iterator has reached the end.
-var ErrNotFound = fmt.Errorf("not found")
+var (
+	// ErrNotFound is returned when the object is not found or the iterator has reached the end.
+	ErrNotFound = fmt.Errorf("not found")
+
+	// ErrActorIDMismatch is returned when the ID of a retrieved actor doesn't match the IRI from which it
+	// was retrieved. This may indicate a redirect or a spoofed actor document.
+	ErrActorIDMismatch = fmt.Errorf("actor ID mismatch")
+
+	// ErrInvalidActor is returned when a retrieved actor document is not self-consistent.
+	ErrInvalidActor = fmt.Errorf("invalid actor")
+
+	// ErrMaxPagesExceeded is returned by an iterator when it has retrieved the maximum number of pages.
+	// This protects against a remote server that serves an endless sequence of pages.
+	ErrMaxPagesExceeded = fmt.Errorf("maximum number of pages exceeded")
+)
 
 // Order is the order in which activities are returned.
 type Order string
@@ -88,6 +102,10 @@ type serviceResolver interface {
 	ResolveHostMetaLink(uri, linkType string) (string, error)
 }
 
+type circuitBreaker interface {
+	Execute(ctx context.Context, host string, fn func() error) error
+}
+
 // Config contains configuration parameters for the client.
 type Config struct {
 	// Deprecated
@@ -96,6 +114,14 @@ type Config struct {
 	CacheRefreshInterval    time.Duration
 	CacheMaxRefreshAttempts int
 	CacheRetryBackoff       time.Duration
+
+	// MaxPages is the maximum number of pages that a reference or activity iterator retrieves
+	// before returning ErrMaxPagesExceeded. Defaults to 10000.
+	MaxPages int
+
+	// CircuitBreaker, if set, causes actor and public key requests to a host which is consistently
+	// failing to fail fast.
+	CircuitBreaker circuitBreaker
 }
 
 type refreshingCache interface {
@@ -115,6 +141,8 @@ type Client struct {
 	fetchPublicKey verifiable.PublicKeyFetcher
 	resolver       serviceResolver
 	tracer         trace.Tracer
+	maxPages       int
+	circuitBreaker circuitBreaker
 }
 
 // New returns a new ActivityPub client.
@@ -128,6 +156,9 @@ func New(cfg Config, t httpTransport, fetchPublicKey verifiable.PublicKeyFetcher
 
 	config := resolveConfig(&cfg)
 
+	c.maxPages = config.MaxPages
+	c.circuitBreaker = config.CircuitBreaker
+
 	cacheOpts := []cache.Opt{
 		cache.WithRefreshInterval(config.CacheRefreshInterval),
 		cache.WithMonitorInterval(config.CacheRefreshInterval / 2),
@@ -195,7 +226,7 @@ func (c *Client) loadActor(actorIRI string) (*vocab.ActorType, error) {
 	ctx, span := c.tracer.Start(context.Background(), "load actor to cache")
 	defer span.End()
 
-	respBytes, err := c.get(ctx, u)
+	respBytes, err := c.getWithCircuitBreaker(ctx, u)
 	if err != nil {
 		return nil, fmt.Errorf("error reading response from %s: %w", actorIRI, err)
 	}
@@ -209,9 +240,51 @@ func (c *Client) loadActor(actorIRI string) (*vocab.ActorType, error) {
 		return nil, fmt.Errorf("invalid actor in response from %s: %w", actorIRI, err)
 	}
 
+	err = c.validateActor(actor, u)
+	if err != nil {
+		return nil, fmt.Errorf("validate actor from %s: %w", actorIRI, err)
+	}
+
 	return actor, nil
 }
 
+// validateActor ensures that the actor's ID matches the IRI from which it was retrieved and that the
+// actor's public key exists and is owned by the actor. Only validated actors are cached.
+func (c *Client) validateActor(actor *vocab.ActorType, actorIRI *url.URL) error {
+	if actor.ID() == nil || actor.ID().String() != actorIRI.String() {
+		return fmt.Errorf("%w: expecting [%s] but got [%s]", ErrActorIDMismatch, actorIRI, actor.ID())
+	}
+
+	pubKey := actor.PublicKey()
+
+	if pubKey.ID() == nil {
+		return fmt.Errorf("%w: actor [%s] has no public key ID", ErrInvalidActor, actorIRI)
+	}
+
+	if pubKey.PublicKeyPem() == "" {
+		// The actor contains a reference to the public key, so make sure the key exists.
+		resolvedKey, err := c.GetPublicKey(pubKey.ID())
+		if err != nil {
+			return fmt.Errorf("%w: resolve public key [%s] of actor [%s]: %s",
+				ErrInvalidActor, pubKey.ID(), actorIRI, err)
+		}
+
+		// The owner of a key resolved from a DID is the DID rather than the actor.
+		if docutil.IsDID(pubKey.ID().String()) {
+			return nil
+		}
+
+		pubKey = resolvedKey
+	}
+
+	if pubKey.Owner() != nil && pubKey.Owner().String() != actorIRI.String() {
+		return fmt.Errorf("%w: owner [%s] of public key [%s] is not actor [%s]",
+			ErrInvalidActor, pubKey.Owner(), pubKey.ID(), actorIRI)
+	}
+
+	return nil
+}
+
 // GetPublicKey retrieves the public key at the given IRI.
 //
 //nolint:interfacer,forcetypeassert
@@ -241,7 +314,7 @@ func (c *Client) loadPublicKey(keyIRI string) (*vocab.PublicKeyType, error) {
 	ctx, span := c.tracer.Start(context.Background(), "load public key to cache")
 	defer span.End()
 
-	respBytes, err := c.get(ctx, keyURL)
+	respBytes, err := c.getWithCircuitBreaker(ctx, keyURL)
 	if err != nil {
 		return nil, fmt.Errorf("error reading response from %s: %w", keyIRI, err)
 	}
@@ -279,7 +352,7 @@ func (c *Client) GetReferences(ctx context.Context, iri *url.URL) (ReferenceIter
 		items[i] = prop.IRI()
 	}
 
-	return newReferenceIterator(ctx, items, firstPage, totalItems, c.get), nil
+	return newReferenceIterator(ctx, items, firstPage, totalItems, c.maxPages, c.get), nil
 }
 
 // GetActivities returns an iterator that reads activities at the given IRI. The IRI may reference a
@@ -320,12 +393,12 @@ func (c *Client) activityIteratorFromCollection(ctx context.Context, collBytes [
 		logger.Debugc(ctx, "Creating forward activity iterator",
 			logfields.WithNextIRI(first), logfields.WithTotal(totalItems))
 
-		return newForwardActivityIterator(ctx, nil, nil, first, totalItems, c.get), nil
+		return newForwardActivityIterator(ctx, nil, nil, first, totalItems, c.maxPages, c.get), nil
 	case Reverse:
 		logger.Debugc(ctx, "Creating reverse activity iterator",
 			logfields.WithNextIRI(last), logfields.WithTotal(totalItems))
 
-		return newReverseActivityIterator(ctx, nil, nil, last, totalItems, c.get), nil
+		return newReverseActivityIterator(ctx, nil, nil, last, totalItems, c.maxPages, c.get), nil
 	default:
 		return nil, fmt.Errorf("invalid order [%s]", order)
 	}
@@ -348,17 +421,41 @@ func (c *Client) activityIteratorFromCollectionPage(ctx context.Context, collByt
 		logger.Debugc(ctx, "Creating forward activity iterator",
 			logfields.WithCurrentIRI(page.current), logfields.WithSize(len(activities)), logfields.WithTotal(page.totalItems))
 
-		return newForwardActivityIterator(ctx, activities, page.current, page.next, page.totalItems, c.get), nil
+		return newForwardActivityIterator(ctx, activities, page.current, page.next, page.totalItems,
+			c.maxPages, c.get), nil
 	case Reverse:
 		logger.Debugc(ctx, "Creating reverse activity iterator",
 			logfields.WithCurrentIRI(page.current), logfields.WithSize(len(activities)), logfields.WithTotal(page.totalItems))
 
-		return newReverseActivityIterator(ctx, activities, page.current, page.prev, page.totalItems, c.get), nil
+		return newReverseActivityIterator(ctx, activities, page.current, page.prev, page.totalItems,
+			c.maxPages, c.get), nil
 	default:
 		return nil, fmt.Errorf("invalid order [%s]", order)
 	}
 }
 
+// getWithCircuitBreaker performs a GET using the circuit breaker (if configured) for the host of the given IRI.
+func (c *Client) getWithCircuitBreaker(ctx context.Context, iri *url.URL) ([]byte, error) {
+	if c.circuitBreaker == nil {
+		return c.get(ctx, iri)
+	}
+
+	var respBytes []byte
+
+	err := c.circuitBreaker.Execute(ctx, iri.Host, func() error {
+		var e error
+
+		respBytes, e = c.get(ctx, iri)
+
+		return e
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return respBytes, nil
+}
+
 func (c *Client) get(ctx context.Context, iri *url.URL) ([]byte, error) {
 	resp, err := c.Get(ctx, transport.NewRequest(iri,
 		transport.WithHeader(transport.AcceptHeader, transport.ActivityStreamsContentType)))
@@ -454,9 +551,12 @@ type referenceIterator struct {
 	currentIndex int
 	nextPage     *url.URL
 	get          getFunc
+	pageCounter  *pageCounter
 }
 
-func newReferenceIterator(ctx context.Context, items []*url.URL, nextPage *url.URL, totalItems int, retrieve getFunc) *referenceIterator {
+func newReferenceIterator(ctx context.Context, items []*url.URL, nextPage *url.URL, totalItems, maxPages int,
+	retrieve getFunc,
+) *referenceIterator {
 	return &referenceIterator{
 		ctx:          ctx,
 		currentItems: items,
@@ -464,6 +564,7 @@ func newReferenceIterator(ctx context.Context, items []*url.URL, nextPage *url.U
 		nextPage:     nextPage,
 		get:          retrieve,
 		currentIndex: 0,
+		pageCounter:  newPageCounter(maxPages),
 	}
 }
 
@@ -491,6 +592,10 @@ func (it *referenceIterator) getNextPage() error {
 		return ErrNotFound
 	}
 
+	if err := it.pageCounter.increment(); err != nil {
+		return fmt.Errorf("get references from %s: %w", it.nextPage, err)
+	}
+
 	logger.Debug("Retrieving next page", logfields.WithNextIRI(it.nextPage))
 
 	respBytes, err := it.get(it.ctx, it.nextPage)
@@ -543,10 +648,11 @@ type activityIterator struct {
 	get            getFunc
 	getNext        getNextIRIFunc
 	appendActivity appendFunc
+	pageCounter    *pageCounter
 }
 
 func newActivityIterator(ctx context.Context,
-	items []*vocab.ActivityType, currentPage, nextPage *url.URL, totalItems int,
+	items []*vocab.ActivityType, currentPage, nextPage *url.URL, totalItems, maxPages int,
 	get getFunc, getNext getNextIRIFunc, appendActivity appendFunc,
 ) *activityIterator {
 	return &activityIterator{
@@ -558,6 +664,7 @@ func newActivityIterator(ctx context.Context,
 		get:            get,
 		getNext:        getNext,
 		appendActivity: appendActivity,
+		pageCounter:    newPageCounter(maxPages),
 	}
 }
 
@@ -622,6 +729,10 @@ func (it *activityIterator) getNextPage() error {
 		return ErrNotFound
 	}
 
+	if err := it.pageCounter.increment(); err != nil {
+		return fmt.Errorf("get activities from %s: %w", it.nextPage, err)
+	}
+
 	logger.Debug("Retrieving next page of activities", logfields.WithNextIRI(it.nextPage))
 
 	respBytes, err := it.get(it.ctx, it.nextPage)
@@ -664,9 +775,9 @@ func (it *activityIterator) getNextPage() error {
 
 func newForwardActivityIterator(ctx context.Context,
 	items []*vocab.ActivityType, currentPage, nextPage *url.URL,
-	totalItems int, retrieve getFunc,
+	totalItems, maxPages int, retrieve getFunc,
 ) *activityIterator {
-	return newActivityIterator(ctx, items, currentPage, nextPage, totalItems, retrieve,
+	return newActivityIterator(ctx, items, currentPage, nextPage, totalItems, maxPages, retrieve,
 		func(next, _ *url.URL) *url.URL {
 			return next
 		},
@@ -678,9 +789,9 @@ func newForwardActivityIterator(ctx context.Context,
 
 func newReverseActivityIterator(ctx context.Context,
 	items []*vocab.ActivityType, currentPage, nextPage *url.URL,
-	totalItems int, retrieve getFunc,
+	totalItems, maxPages int, retrieve getFunc,
 ) *activityIterator {
-	return newActivityIterator(ctx, reverseSort(items), currentPage, nextPage, totalItems, retrieve,
+	return newActivityIterator(ctx, reverseSort(items), currentPage, nextPage, totalItems, maxPages, retrieve,
 		func(_, prev *url.URL) *url.URL {
 			return prev
 		},
@@ -691,6 +802,29 @@ func newReverseActivityIterator(ctx context.Context,
 	)
 }
 
+// pageCounter counts the number of pages retrieved by an iterator and returns an error once
+// the maximum number of pages is exceeded.
+type pageCounter struct {
+	maxPages int
+	count    int
+}
+
+func newPageCounter(maxPages int) *pageCounter {
+	return &pageCounter{maxPages: maxPages}
+}
+
+func (c *pageCounter) increment() error {
+	if c.count >= c.maxPages {
+		logger.Warn("Iterator has retrieved the maximum number of pages", logfields.WithMaxPages(c.maxPages))
+
+		return fmt.Errorf("%w: %d", ErrMaxPagesExceeded, c.maxPages)
+	}
+
+	c.count++
+
+	return nil
+}
+
 func unmarshalCollection(respBytes []byte) (items []*vocab.ObjectProperty, firstPage,
 	lastPage *url.URL, totalCount int, err error,
 ) {
@@ -810,5 +944,9 @@ func resolveConfig(cfg *Config) *Config {
 		c.CacheMaxRefreshAttempts = defaultMaxRefreshAttempts
 	}
 
+	if c.MaxPages <= 0 {
+		c.MaxPages = defaultMaxPages
+	}
+
 	return &c
 }
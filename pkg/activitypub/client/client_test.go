@@ -28,8 +28,11 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/logutil-go/pkg/log"
 
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
 	"github.com/trustbloc/orb/pkg/activitypub/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/circuitbreaker"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/internal/aptestutil"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 )
@@ -132,6 +135,99 @@ func TestClient_GetActor(t *testing.T) {
 		require.NoError(t, result.Body.Close())
 	})
 
+	t.Run("Validation", func(t *testing.T) {
+		newResponse := func(t *testing.T, v interface{}) *http.Response {
+			t.Helper()
+
+			respBytes, err := json.Marshal(v)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+
+			_, err = rw.Write(respBytes)
+			require.NoError(t, err)
+
+			return rw.Result()
+		}
+
+		keyIRI := testutil.NewMockID(actorIRI, "/keys/main-key")
+
+		t.Run("Public key reference -> success", func(t *testing.T) {
+			httpClient := &mocks.HTTPTransport{}
+			httpClient.GetReturnsOnCall(0, newResponse(t, aptestutil.NewMockService(actorIRI,
+				aptestutil.WithPublicKey(vocab.NewPublicKey(vocab.WithID(keyIRI))))), nil)
+			httpClient.GetReturnsOnCall(1, newResponse(t, aptestutil.NewMockPublicKey(actorIRI)), nil)
+
+			c := newMockClient(httpClient)
+
+			actor, err := c.GetActor(actorIRI)
+			require.NoError(t, err)
+			require.NotNil(t, actor)
+			require.Equal(t, keyIRI.String(), actor.PublicKey().ID().String())
+		})
+
+		t.Run("Actor ID doesn't match requested IRI", func(t *testing.T) {
+			httpClient := &mocks.HTTPTransport{}
+			httpClient.GetReturns(newResponse(t,
+				aptestutil.NewMockService(testutil.MustParseURL("https://example.com/services/spoofed"))), nil)
+
+			c := newMockClient(httpClient)
+
+			actor, err := c.GetActor(actorIRI)
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrActorIDMismatch))
+			require.Contains(t, err.Error(), "expecting [https://example.com/services/service1] "+
+				"but got [https://example.com/services/spoofed]")
+			require.Nil(t, actor)
+		})
+
+		t.Run("Missing public key", func(t *testing.T) {
+			httpClient := &mocks.HTTPTransport{}
+			httpClient.GetReturns(newResponse(t, vocab.NewService(actorIRI)), nil)
+
+			c := newMockClient(httpClient)
+
+			actor, err := c.GetActor(actorIRI)
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrInvalidActor))
+			require.Contains(t, err.Error(), "has no public key ID")
+			require.Nil(t, actor)
+		})
+
+		t.Run("Public key owned by another actor", func(t *testing.T) {
+			httpClient := &mocks.HTTPTransport{}
+			httpClient.GetReturns(newResponse(t, aptestutil.NewMockService(actorIRI,
+				aptestutil.WithPublicKey(aptestutil.NewMockPublicKey(
+					testutil.MustParseURL("https://example.com/services/service2"))))), nil)
+
+			c := newMockClient(httpClient)
+
+			actor, err := c.GetActor(actorIRI)
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrInvalidActor))
+			require.Contains(t, err.Error(), "is not actor [https://example.com/services/service1]")
+			require.Nil(t, actor)
+		})
+
+		t.Run("Referenced public key not found", func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			rw.Code = http.StatusNotFound
+
+			httpClient := &mocks.HTTPTransport{}
+			httpClient.GetReturnsOnCall(0, newResponse(t, aptestutil.NewMockService(actorIRI,
+				aptestutil.WithPublicKey(vocab.NewPublicKey(vocab.WithID(keyIRI))))), nil)
+			httpClient.GetReturnsOnCall(1, rw.Result(), nil)
+
+			c := newMockClient(httpClient)
+
+			actor, err := c.GetActor(actorIRI)
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrInvalidActor))
+			require.Contains(t, err.Error(), "resolve public key [https://example.com/services/service1/keys/main-key]")
+			require.Nil(t, actor)
+		})
+	})
+
 	t.Run("Cache expiry", func(t *testing.T) {
 		errExpected := errors.New("not found")
 
@@ -1364,6 +1460,109 @@ func TestClient_GetActivities(t *testing.T) {
 	})
 }
 
+func TestClient_MaxPages(t *testing.T) {
+	const (
+		maxPages   = 3
+		totalItems = 1000
+	)
+
+	serviceIRI := testutil.MustParseURL("https://example.com/services/service1")
+	toIRI := testutil.MustParseURL("https://example.com/services/service2/inbox")
+	collIRI := testutil.NewMockID(serviceIRI, "/outbox")
+
+	pageIRI := func(i int) *url.URL {
+		return testutil.NewMockID(collIRI, fmt.Sprintf("?page=true&page-num=%d", i))
+	}
+
+	// newEndlessTransport returns a transport which serves a collection whose pages always reference
+	// a next page.
+	newEndlessTransport := func(newItem func(i int) *vocab.ObjectProperty) *mocks.HTTPTransport {
+		httpClient := &mocks.HTTPTransport{}
+
+		httpClient.GetStub = func(_ context.Context, req *transport.Request) (*http.Response, error) {
+			var obj interface{}
+
+			if req.URL.String() == collIRI.String() {
+				obj = aptestutil.NewMockCollection(collIRI, pageIRI(0), nil, totalItems)
+			} else {
+				var i int
+
+				_, err := fmt.Sscanf(req.URL.Query().Get("page-num"), "%d", &i)
+				require.NoError(t, err)
+
+				obj = aptestutil.NewMockCollectionPage(pageIRI(i), pageIRI(i+1), nil, collIRI, totalItems,
+					newItem(i))
+			}
+
+			objBytes, err := json.Marshal(obj)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+
+			_, err = rw.Write(objBytes)
+			require.NoError(t, err)
+
+			return rw.Result(), nil
+		}
+
+		return httpClient
+	}
+
+	newClient := func(httpClient httpTransport) *Client {
+		return New(Config{MaxPages: maxPages}, httpClient,
+			func(issuerID, keyID string) (*verifier.PublicKey, error) {
+				return &verifier.PublicKey{}, nil
+			}, &wellKnownResolver{})
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		c := newMockClient(&mocks.HTTPTransport{})
+		require.Equal(t, defaultMaxPages, c.maxPages)
+	})
+
+	t.Run("References", func(t *testing.T) {
+		httpClient := newEndlessTransport(func(i int) *vocab.ObjectProperty {
+			return vocab.NewObjectProperty(vocab.WithIRI(testutil.NewMockID(serviceIRI, fmt.Sprintf("/%d", i))))
+		})
+
+		it, err := newClient(httpClient).GetReferences(context.Background(), collIRI)
+		require.NoError(t, err)
+
+		refs, err := ReadReferences(it, -1)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrMaxPagesExceeded))
+		require.Nil(t, refs)
+
+		// One request for the collection and one for each page.
+		require.Equal(t, maxPages+1, httpClient.GetCallCount())
+	})
+
+	t.Run("Activities", func(t *testing.T) {
+		httpClient := newEndlessTransport(func(i int) *vocab.ObjectProperty {
+			return vocab.NewObjectProperty(vocab.WithActivity(
+				newMockActivity(serviceIRI, toIRI, testutil.NewMockID(serviceIRI, fmt.Sprintf("/%d", i))),
+			))
+		})
+
+		it, err := newClient(httpClient).GetActivities(context.Background(), collIRI, Forward)
+		require.NoError(t, err)
+
+		for i := 0; i < maxPages; i++ {
+			_, err = it.Next()
+			require.NoError(t, err)
+		}
+
+		_, err = it.Next()
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrMaxPagesExceeded))
+
+		_, err = it.NextPage()
+		require.True(t, errors.Is(err, ErrMaxPagesExceeded))
+
+		require.Equal(t, maxPages+1, httpClient.GetCallCount())
+	})
+}
+
 func newMockActivity(service1IRI, toIRI, objID *url.URL) *vocab.ActivityType {
 	return aptestutil.NewMockCreateActivity(service1IRI, toIRI,
 		vocab.NewObjectProperty(
@@ -1381,6 +1580,43 @@ func newMockClient(httpClient httpTransport) *Client {
 		}, &wellKnownResolver{})
 }
 
+func TestClient_CircuitBreaker(t *testing.T) {
+	actorIRI := testutil.MustParseURL("https://example.com/services/service1")
+	keyIRI := testutil.NewMockID(actorIRI, "/keys/main-key")
+
+	errExpected := errors.New("injected HTTP client error")
+
+	httpClient := &mocks.HTTPTransport{}
+	httpClient.GetReturns(nil, errExpected)
+
+	c := New(
+		Config{
+			CircuitBreaker: circuitbreaker.New("activitypub-client", circuitbreaker.WithFailureThreshold(2)),
+		},
+		httpClient,
+		func(issuerID, keyID string) (*verifier.PublicKey, error) {
+			return &verifier.PublicKey{}, nil
+		}, &wellKnownResolver{},
+	)
+
+	_, err := c.loadActor(actorIRI.String())
+	require.ErrorIs(t, err, errExpected)
+	require.True(t, orberrors.IsTransient(err))
+
+	_, err = c.loadPublicKey(keyIRI.String())
+	require.ErrorIs(t, err, errExpected)
+
+	// The circuit for the host is open so neither request should be sent.
+	_, err = c.loadActor(actorIRI.String())
+	require.ErrorIs(t, err, circuitbreaker.ErrOpen)
+	require.True(t, orberrors.IsTransient(err))
+
+	_, err = c.loadPublicKey(keyIRI.String())
+	require.ErrorIs(t, err, circuitbreaker.ErrOpen)
+
+	require.Equal(t, 2, httpClient.GetCallCount())
+}
+
 type wellKnownResolver struct {
 	Err error
 	URI string
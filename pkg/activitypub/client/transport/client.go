@@ -0,0 +1,381 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDialTimeout is the default maximum amount of time to wait for a connection to be established.
+	DefaultDialTimeout = 5 * time.Second
+
+	// DefaultTLSHandshakeTimeout is the default maximum amount of time to wait for a TLS handshake.
+	DefaultTLSHandshakeTimeout = 5 * time.Second
+
+	// DefaultResponseHeaderTimeout is the default maximum amount of time to wait for the response headers
+	// after the request has been written.
+	DefaultResponseHeaderTimeout = 15 * time.Second
+
+	defaultKeepAlive             = 30 * time.Second
+	defaultMaxIdleConns          = 2000
+	defaultMaxConnsPerHost       = 100
+	defaultIdleConnTimeout       = 90 * time.Second
+	defaultExpectContinueTimeout = 1 * time.Second
+)
+
+var (
+	// ErrDialTimeout indicates that a connection could not be established in time.
+	ErrDialTimeout = errors.New("dial timeout")
+
+	// ErrTLSHandshakeTimeout indicates that the TLS handshake did not complete in time.
+	ErrTLSHandshakeTimeout = errors.New("TLS handshake timeout")
+
+	// ErrResponseHeaderTimeout indicates that the response headers were not received in time.
+	ErrResponseHeaderTimeout = errors.New("response header timeout")
+
+	// ErrBodyReadTimeout indicates that the response body could not be read in time.
+	ErrBodyReadTimeout = errors.New("body read timeout")
+
+	// ErrRequestTimeout indicates that the overall request deadline (i.e. the context deadline or the
+	// HTTP client timeout) was exceeded before the response headers were received.
+	ErrRequestTimeout = errors.New("request timeout")
+)
+
+// TimeoutError is returned when a request times out. The Phase is one of the timeout errors above
+// and may be checked using errors.Is, for example, errors.Is(err, transport.ErrDialTimeout).
+type TimeoutError struct {
+	Phase error
+	Err   error
+}
+
+// Error returns the error message.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Phase, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if the target is the phase in which the timeout occurred.
+func (e *TimeoutError) Is(target error) bool {
+	return target == e.Phase
+}
+
+// Timeout always returns true.
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+type clientOptions struct {
+	tlsConfig             *tls.Config
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+	minTLSVersion         uint16
+	cipherSuites          []uint16
+	cipherSuitesSet       bool
+	peerCAPool            *x509.CertPool
+	http2Disabled         bool
+}
+
+// ClientOption is an option for the HTTP transport returned by NewHTTPTransport.
+type ClientOption func(opts *clientOptions)
+
+// WithTLSConfig sets the TLS configuration.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(opts *clientOptions) {
+		opts.tlsConfig = cfg
+	}
+}
+
+// WithDialTimeout sets the maximum amount of time to wait for a connection to be established.
+func WithDialTimeout(value time.Duration) ClientOption {
+	return func(opts *clientOptions) {
+		opts.dialTimeout = value
+	}
+}
+
+// WithTLSHandshakeTimeout sets the maximum amount of time to wait for a TLS handshake.
+func WithTLSHandshakeTimeout(value time.Duration) ClientOption {
+	return func(opts *clientOptions) {
+		opts.tlsHandshakeTimeout = value
+	}
+}
+
+// WithResponseHeaderTimeout sets the maximum amount of time to wait for the response headers after
+// the request has been written. This protects against peers which accept a connection but respond slowly.
+func WithResponseHeaderTimeout(value time.Duration) ClientOption {
+	return func(opts *clientOptions) {
+		opts.responseHeaderTimeout = value
+	}
+}
+
+// WithMinTLSVersion sets the minimum TLS version (tls.VersionTLS12 or tls.VersionTLS13) which is accepted
+// when connecting to a peer. The value overrides the minimum version in the TLS configuration.
+func WithMinTLSVersion(value uint16) ClientOption {
+	return func(opts *clientOptions) {
+		opts.minTLSVersion = value
+	}
+}
+
+// WithCipherSuites sets the cipher suites which may be negotiated with a peer using TLS 1.2. The cipher suites
+// for TLS 1.3 aren't configurable and so may not be specified. The value overrides the cipher suites in
+// the TLS configuration.
+func WithCipherSuites(value ...uint16) ClientOption {
+	return func(opts *clientOptions) {
+		opts.cipherSuites = value
+		opts.cipherSuitesSet = true
+	}
+}
+
+// WithPeerCAPool sets the pool of CA certificates against which the peer certificates are verified. Only
+// peers with a certificate issued by one of these CAs are accepted (the system certificate pool isn't used),
+// which is useful for a private federation of servers. The value overrides the root CAs in the TLS configuration.
+func WithPeerCAPool(pool *x509.CertPool) ClientOption {
+	return func(opts *clientOptions) {
+		opts.peerCAPool = pool
+	}
+}
+
+// WithHTTP2 enables/disables HTTP/2 (enabled by default). If enabled then HTTP/2 is negotiated (using ALPN)
+// with peers which support it, so that concurrent requests to the same peer are multiplexed over a single
+// connection. Peers which don't support HTTP/2 fall back to HTTP/1.1. If disabled then HTTP/1.1 is always used.
+func WithHTTP2(enable bool) ClientOption {
+	return func(opts *clientOptions) {
+		opts.http2Disabled = !enable
+	}
+}
+
+// NewHTTPTransport returns an HTTP transport with connection-level timeouts. These timeouts are distinct
+// from the overall request timeout (which is set on the HTTP client or in the request context). If both are
+// set then the tighter one wins. An error is returned if the TLS options are invalid.
+func NewHTTPTransport(opts ...ClientOption) (*http.Transport, error) {
+	options := &clientOptions{
+		dialTimeout:           DefaultDialTimeout,
+		tlsHandshakeTimeout:   DefaultTLSHandshakeTimeout,
+		responseHeaderTimeout: DefaultResponseHeaderTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	tlsConfig, err := newTLSConfig(options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+
+	tr := &http.Transport{
+		TLSClientConfig: tlsConfig,
+		DialContext: (&net.Dialer{
+			Timeout:   options.dialTimeout,
+			KeepAlive: defaultKeepAlive,
+		}).DialContext,
+		ForceAttemptHTTP2:     !options.http2Disabled,
+		MaxIdleConns:          defaultMaxIdleConns,
+		MaxConnsPerHost:       defaultMaxConnsPerHost,
+		IdleConnTimeout:       defaultIdleConnTimeout,
+		TLSHandshakeTimeout:   options.tlsHandshakeTimeout,
+		ResponseHeaderTimeout: options.responseHeaderTimeout,
+		ExpectContinueTimeout: defaultExpectContinueTimeout,
+	}
+
+	if options.http2Disabled {
+		// A non-nil, empty map disables HTTP/2.
+		tr.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return tr, nil
+}
+
+// newTLSConfig returns a copy of the TLS configuration in the given options with the TLS policy options
+// applied. The TLS policy options are validated so that a misconfiguration results in an error rather than
+// a silent fallback to the defaults.
+func newTLSConfig(options *clientOptions) (*tls.Config, error) {
+	if options.minTLSVersion == 0 && !options.cipherSuitesSet && options.peerCAPool == nil {
+		return options.tlsConfig, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if options.tlsConfig != nil {
+		tlsConfig = options.tlsConfig.Clone()
+	}
+
+	if options.minTLSVersion != 0 {
+		if options.minTLSVersion != tls.VersionTLS12 && options.minTLSVersion != tls.VersionTLS13 {
+			return nil, fmt.Errorf("unsupported minimum TLS version [%s]: must be TLS 1.2 or TLS 1.3",
+				tls.VersionName(options.minTLSVersion))
+		}
+
+		tlsConfig.MinVersion = options.minTLSVersion
+	}
+
+	if options.cipherSuitesSet {
+		if err := validateCipherSuites(tlsConfig.MinVersion, options.cipherSuites); err != nil {
+			return nil, err
+		}
+
+		tlsConfig.CipherSuites = options.cipherSuites
+	}
+
+	if options.peerCAPool != nil {
+		tlsConfig.RootCAs = options.peerCAPool
+	}
+
+	return tlsConfig, nil
+}
+
+func validateCipherSuites(minVersion uint16, cipherSuites []uint16) error {
+	if minVersion == tls.VersionTLS13 {
+		return errors.New("cipher suites may not be configured with a minimum TLS version of TLS 1.3 " +
+			"since the TLS 1.3 cipher suites aren't configurable")
+	}
+
+	if len(cipherSuites) == 0 {
+		return errors.New("at least one cipher suite must be configured when TLS 1.2 connections are allowed")
+	}
+
+	for _, id := range cipherSuites {
+		suite := cipherSuite(id)
+		if suite == nil {
+			return fmt.Errorf("unsupported or insecure cipher suite [%s]", tls.CipherSuiteName(id))
+		}
+
+		if !supportsVersion(suite, tls.VersionTLS12) {
+			return fmt.Errorf("cipher suite [%s] doesn't support TLS 1.2", suite.Name)
+		}
+	}
+
+	return nil
+}
+
+// cipherSuite returns the secure cipher suite with the given ID or nil if the cipher suite
+// is unknown or insecure.
+func cipherSuite(id uint16) *tls.CipherSuite {
+	for _, suite := range tls.CipherSuites() {
+		if suite.ID == id {
+			return suite
+		}
+	}
+
+	return nil
+}
+
+func supportsVersion(suite *tls.CipherSuite, version uint16) bool {
+	for _, v := range suite.SupportedVersions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseTLSVersion parses the given TLS version, which must be either "1.2" or "1.3".
+func ParseTLSVersion(value string) (uint16, error) {
+	switch value {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version [%s]: must be 1.2 or 1.3", value)
+	}
+}
+
+// ParseCipherSuites returns the IDs of the given cipher suites. Each cipher suite must be specified
+// by its standard name, for example TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384. An error is returned
+// if a cipher suite is unknown or insecure.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	ids := make([]uint16, len(names))
+
+	for i, name := range names {
+		id, ok := cipherSuiteID(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unsupported or insecure cipher suite [%s]", name)
+		}
+
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+func cipherSuiteID(name string) (uint16, bool) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+
+	return 0, false
+}
+
+// wrapTimeoutError wraps the given error from the HTTP client in a TimeoutError (if it is a timeout)
+// so that the caller can determine in which phase the timeout occurred.
+func wrapTimeoutError(err error) error {
+	if !isTimeout(err) {
+		return err
+	}
+
+	return &TimeoutError{Phase: timeoutPhase(err), Err: err}
+}
+
+func timeoutPhase(err error) error {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return ErrDialTimeout
+	}
+
+	// The HTTP transport doesn't export the errors for these timeouts so the error text must be checked.
+	switch {
+	case strings.Contains(err.Error(), "TLS handshake timeout"):
+		return ErrTLSHandshakeTimeout
+	case strings.Contains(err.Error(), "timeout awaiting response headers"):
+		return ErrResponseHeaderTimeout
+	default:
+		return ErrRequestTimeout
+	}
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// timeoutReadCloser wraps a response body so that timeout errors while reading the body
+// may be distinguished from timeouts which occur while establishing the connection.
+type timeoutReadCloser struct {
+	io.ReadCloser
+}
+
+func (r *timeoutReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err != nil && isTimeout(err) {
+		return n, &TimeoutError{Phase: ErrBodyReadTimeout, Err: err}
+	}
+
+	return n, err
+}
@@ -0,0 +1,418 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client/mocks"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestNewHTTPTransport(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		tr, err := NewHTTPTransport()
+		require.NoError(t, err)
+		require.NotNil(t, tr)
+		require.Equal(t, DefaultTLSHandshakeTimeout, tr.TLSHandshakeTimeout)
+		require.Equal(t, DefaultResponseHeaderTimeout, tr.ResponseHeaderTimeout)
+		require.NotNil(t, tr.DialContext)
+		require.Nil(t, tr.TLSClientConfig)
+	})
+
+	t.Run("With options", func(t *testing.T) {
+		tr, err := NewHTTPTransport(
+			WithDialTimeout(time.Second),
+			WithTLSHandshakeTimeout(2*time.Second),
+			WithResponseHeaderTimeout(3*time.Second),
+		)
+		require.NoError(t, err)
+		require.NotNil(t, tr)
+		require.Equal(t, 2*time.Second, tr.TLSHandshakeTimeout)
+		require.Equal(t, 3*time.Second, tr.ResponseHeaderTimeout)
+	})
+}
+
+func TestNewHTTPTransport_TLSPolicy(t *testing.T) {
+	t.Run("Minimum TLS version", func(t *testing.T) {
+		tr, err := NewHTTPTransport(WithMinTLSVersion(tls.VersionTLS13))
+		require.NoError(t, err)
+		require.NotNil(t, tr.TLSClientConfig)
+		require.Equal(t, uint16(tls.VersionTLS13), tr.TLSClientConfig.MinVersion)
+	})
+
+	t.Run("Cipher suites", func(t *testing.T) {
+		cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: "orb.domain1.com"}
+
+		tr, err := NewHTTPTransport(
+			WithTLSConfig(cfg),
+			WithCipherSuites(tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384),
+		)
+		require.NoError(t, err)
+		require.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}, tr.TLSClientConfig.CipherSuites)
+		require.Equal(t, "orb.domain1.com", tr.TLSClientConfig.ServerName)
+		require.Empty(t, cfg.CipherSuites, "the provided TLS config should not be modified")
+	})
+
+	t.Run("Peer CA pool", func(t *testing.T) {
+		pool := x509.NewCertPool()
+
+		tr, err := NewHTTPTransport(WithTLSConfig(&tls.Config{RootCAs: x509.NewCertPool()}), WithPeerCAPool(pool))
+		require.NoError(t, err)
+		require.True(t, tr.TLSClientConfig.RootCAs == pool)
+	})
+
+	t.Run("Unsupported minimum TLS version", func(t *testing.T) {
+		_, err := NewHTTPTransport(WithMinTLSVersion(tls.VersionTLS10))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported minimum TLS version [TLS 1.0]")
+	})
+
+	t.Run("Empty cipher suites", func(t *testing.T) {
+		_, err := NewHTTPTransport(WithMinTLSVersion(tls.VersionTLS12), WithCipherSuites())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "at least one cipher suite must be configured")
+	})
+
+	t.Run("Cipher suites with TLS 1.3", func(t *testing.T) {
+		_, err := NewHTTPTransport(WithMinTLSVersion(tls.VersionTLS13),
+			WithCipherSuites(tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cipher suites may not be configured with a minimum TLS version of TLS 1.3")
+	})
+
+	t.Run("Insecure cipher suite", func(t *testing.T) {
+		_, err := NewHTTPTransport(WithCipherSuites(tls.TLS_RSA_WITH_RC4_128_SHA))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported or insecure cipher suite [TLS_RSA_WITH_RC4_128_SHA]")
+	})
+
+	t.Run("TLS 1.3 cipher suite", func(t *testing.T) {
+		_, err := NewHTTPTransport(WithCipherSuites(tls.TLS_AES_128_GCM_SHA256))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cipher suite [TLS_AES_128_GCM_SHA256] doesn't support TLS 1.2")
+	})
+}
+
+func TestNewHTTPTransport_HTTP2(t *testing.T) {
+	t.Run("HTTP/2 peer -> requests multiplexed over one connection", func(t *testing.T) {
+		srv, numConns := newEchoServer(t, true)
+
+		tp := newSigningTestTransport(t, WithTLSConfig(&tls.Config{RootCAs: certPool(srv), MinVersion: tls.VersionTLS12}))
+
+		// Establish the connection before sending concurrent requests.
+		require.Equal(t, "HTTP/2.0 sig-warmup", get(t, tp, srv.URL+"/warmup"))
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				// Each request has its own signature.
+				require.Equal(t, fmt.Sprintf("HTTP/2.0 sig-%d", i), get(t, tp, fmt.Sprintf("%s/%d", srv.URL, i)))
+			}(i)
+		}
+
+		wg.Wait()
+
+		require.Equal(t, int32(1), numConns.Load())
+	})
+
+	t.Run("HTTP/1.1 peer -> fallback to HTTP/1.1", func(t *testing.T) {
+		srv, _ := newEchoServer(t, false)
+
+		tp := newSigningTestTransport(t, WithTLSConfig(&tls.Config{RootCAs: certPool(srv), MinVersion: tls.VersionTLS12}))
+
+		require.Equal(t, "HTTP/1.1 sig-1", get(t, tp, srv.URL+"/1"))
+	})
+
+	t.Run("HTTP/2 disabled", func(t *testing.T) {
+		srv, _ := newEchoServer(t, true)
+
+		tp := newSigningTestTransport(t, WithHTTP2(false),
+			WithTLSConfig(&tls.Config{RootCAs: certPool(srv), MinVersion: tls.VersionTLS12}))
+
+		require.Equal(t, "HTTP/1.1 sig-1", get(t, tp, srv.URL+"/1"))
+	})
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	v, err := ParseTLSVersion("1.2")
+	require.NoError(t, err)
+	require.Equal(t, uint16(tls.VersionTLS12), v)
+
+	v, err = ParseTLSVersion("1.3")
+	require.NoError(t, err)
+	require.Equal(t, uint16(tls.VersionTLS13), v)
+
+	_, err = ParseTLSVersion("1.1")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported TLS version [1.1]")
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	ids, err := ParseCipherSuites([]string{
+		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384", " TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	}, ids)
+
+	_, err = ParseCipherSuites([]string{"TLS_RSA_WITH_RC4_128_SHA"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported or insecure cipher suite [TLS_RSA_WITH_RC4_128_SHA]")
+}
+
+func TestTransport_Timeouts(t *testing.T) {
+	t.Run("TLS handshake timeout", func(t *testing.T) {
+		// The listener accepts connections but never responds to the TLS handshake.
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, listener.Close())
+		}()
+
+		go func() {
+			var conns []net.Conn
+
+			defer func() {
+				for _, conn := range conns {
+					_ = conn.Close()
+				}
+			}()
+
+			for {
+				conn, e := listener.Accept()
+				if e != nil {
+					return
+				}
+
+				conns = append(conns, conn)
+			}
+		}()
+
+		tp := newTestTransport(t, WithTLSHandshakeTimeout(50*time.Millisecond))
+
+		_, err = tp.Get(context.Background(),
+			NewRequest(testutil.MustParseURL(fmt.Sprintf("https://%s", listener.Addr()))))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrTLSHandshakeTimeout), err.Error())
+
+		var timeoutErr *TimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		require.True(t, timeoutErr.Timeout())
+	})
+
+	t.Run("Response header timeout", func(t *testing.T) {
+		srv := newSlowServer(200*time.Millisecond, 0)
+		defer srv.Close()
+
+		tp := newTestTransport(t, WithResponseHeaderTimeout(50*time.Millisecond))
+
+		_, err := tp.Post(context.Background(), NewRequest(testutil.MustParseURL(srv.URL)), []byte("payload"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrResponseHeaderTimeout), err.Error())
+		require.False(t, errors.Is(err, ErrDialTimeout))
+	})
+
+	t.Run("Context deadline is tighter than response header timeout", func(t *testing.T) {
+		srv := newSlowServer(time.Second, 0)
+		defer srv.Close()
+
+		tp := newTestTransport(t, WithResponseHeaderTimeout(5*time.Second))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+
+		_, err := tp.Get(ctx, NewRequest(testutil.MustParseURL(srv.URL)))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrRequestTimeout), err.Error())
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Body read timeout", func(t *testing.T) {
+		srv := newSlowServer(0, 500*time.Millisecond)
+		defer srv.Close()
+
+		tp := newTestTransport(t, WithResponseHeaderTimeout(time.Second))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		resp, err := tp.Get(ctx, NewRequest(testutil.MustParseURL(srv.URL)))
+		require.NoError(t, err)
+
+		_, err = io.ReadAll(resp.Body)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrBodyReadTimeout), err.Error())
+		require.False(t, errors.Is(err, ErrDialTimeout))
+		require.NoError(t, resp.Body.Close())
+	})
+
+	t.Run("Not a timeout", func(t *testing.T) {
+		httpClient := &mocks.HTTPClient{}
+		httpClient.DoReturns(nil, errors.New("injected error"))
+
+		tp := New(httpClient, testutil.MustParseURL(publicKeyID), DefaultSigner(), DefaultSigner(),
+			&mocks.AuthTokenMgr{})
+
+		_, err := tp.Get(context.Background(), NewRequest(testutil.MustParseURL("https://domain1.com")))
+		require.EqualError(t, err, "injected error")
+
+		var timeoutErr *TimeoutError
+		require.False(t, errors.As(err, &timeoutErr))
+	})
+}
+
+func TestWrapTimeoutError(t *testing.T) {
+	t.Run("Dial timeout", func(t *testing.T) {
+		err := wrapTimeoutError(&net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded})
+		require.True(t, errors.Is(err, ErrDialTimeout))
+		require.True(t, errors.Is(err, os.ErrDeadlineExceeded))
+		require.False(t, errors.Is(err, ErrBodyReadTimeout))
+		require.Contains(t, err.Error(), "dial timeout: dial tcp")
+	})
+
+	t.Run("Read timeout", func(t *testing.T) {
+		err := wrapTimeoutError(&net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded})
+		require.True(t, errors.Is(err, ErrRequestTimeout))
+	})
+
+	t.Run("Not a timeout", func(t *testing.T) {
+		errExpected := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+		require.Equal(t, errExpected, wrapTimeoutError(errExpected))
+	})
+}
+
+func newTestTransport(t *testing.T, opts ...ClientOption) *Transport {
+	t.Helper()
+
+	tr, err := NewHTTPTransport(opts...)
+	require.NoError(t, err)
+
+	return New(&http.Client{Transport: tr},
+		testutil.MustParseURL(publicKeyID), DefaultSigner(), DefaultSigner(), &mocks.AuthTokenMgr{})
+}
+
+// newSlowServer returns a test server that waits for headerDelay before sending the response headers
+// and then waits for bodyDelay before sending the response body.
+func newSlowServer(headerDelay, bodyDelay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(headerDelay):
+		case <-r.Context().Done():
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+
+		select {
+		case <-time.After(bodyDelay):
+		case <-r.Context().Done():
+			return
+		}
+
+		_, _ = w.Write([]byte("body"))
+	}))
+}
+
+// newEchoServer returns a TLS test server which responds with the protocol of the request and the signature
+// header. The returned counter holds the number of connections accepted by the server.
+func newEchoServer(t *testing.T, enableHTTP2 bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	numConns := &atomic.Int32{}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s %s", r.Proto, r.Header.Get("Signature"))
+	}))
+
+	srv.EnableHTTP2 = enableHTTP2
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			numConns.Add(1)
+		}
+	}
+
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	return srv, numConns
+}
+
+func certPool(srv *httptest.Server) *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	return pool
+}
+
+// newSigningTestTransport returns a transport which signs each request with a signature derived from
+// the request path.
+func newSigningTestTransport(t *testing.T, opts ...ClientOption) *Transport {
+	t.Helper()
+
+	tr, err := NewHTTPTransport(opts...)
+	require.NoError(t, err)
+
+	signer := &mocks.HTTPSigner{}
+	signer.SignRequestStub = func(_ string, req *http.Request) error {
+		req.Header.Set("Signature", "sig-"+strings.TrimPrefix(req.URL.Path, "/"))
+
+		return nil
+	}
+
+	tm := &mocks.AuthTokenMgr{}
+	tm.IsAuthRequiredReturns(true, nil)
+
+	return New(&http.Client{Transport: tr}, testutil.MustParseURL(publicKeyID), signer, signer, tm)
+}
+
+func get(t *testing.T, tp *Transport, u string) string {
+	t.Helper()
+
+	resp, err := tp.Get(context.Background(), NewRequest(testutil.MustParseURL(u)))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, resp.Body.Close())
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
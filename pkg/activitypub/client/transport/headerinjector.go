@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+import (
+	"net/http"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/requestid"
+)
+
+const userAgentHeader = "User-Agent"
+
+// HeaderInjector is an HTTP round tripper which sets the User-Agent and request ID headers of outbound requests
+// so that a request may be correlated across the nodes which it passes through. The request ID is taken from the
+// request context (see requestid.Middleware) so that the ID of an inbound request is propagated to the outbound
+// requests which are made while handling the inbound request. If the context doesn't carry a request ID then
+// a new ID is generated for each outbound request. Headers which were explicitly set on a request aren't overwritten.
+type HeaderInjector struct {
+	rt        http.RoundTripper
+	userAgent string
+}
+
+// NewHeaderInjector returns a round tripper which sets the User-Agent header (if the given user agent isn't empty)
+// and the request ID header on each outbound request before sending the request using the given round tripper.
+func NewHeaderInjector(rt http.RoundTripper, userAgent string) *HeaderInjector {
+	return &HeaderInjector{
+		rt:        rt,
+		userAgent: userAgent,
+	}
+}
+
+// RoundTrip sets the headers on a copy of the request (since a round tripper must not modify the request)
+// and sends the request using the underlying round tripper.
+func (h *HeaderInjector) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	if h.userAgent != "" && req.Header.Get(userAgentHeader) == "" {
+		req.Header.Set(userAgentHeader, h.userAgent)
+	}
+
+	requestID := req.Header.Get(requestid.HeaderName)
+	if requestID == "" {
+		requestID = requestid.FromContext(req.Context())
+		if requestID == "" {
+			requestID = requestid.New()
+		}
+
+		req.Header.Set(requestid.HeaderName, requestID)
+	}
+
+	logger.Debug("Sending request", logfields.WithRequestID(requestID), logfields.WithHTTPMethod(req.Method),
+		logfields.WithRequestURL(req.URL))
+
+	return h.rt.RoundTrip(req)
+}
+
+// UserAgent returns a User-Agent header value for the given product and version, e.g. orb/1.0.0. If the version
+// is empty then only the product is returned.
+func UserAgent(product, version string) string {
+	if version == "" {
+		return product
+	}
+
+	return product + "/" + version
+}
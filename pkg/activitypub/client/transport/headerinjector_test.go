@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client/mocks"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/requestid"
+)
+
+func TestHeaderInjector(t *testing.T) {
+	var header http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header = req.Header.Clone()
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: NewHeaderInjector(http.DefaultTransport, UserAgent("orb", "1.0.0"))}
+
+	tp := New(httpClient, testutil.MustParseURL(publicKeyID), DefaultSigner(), DefaultSigner(),
+		&mocks.AuthTokenMgr{})
+
+	t.Run("GET without request ID in context -> new request ID", func(t *testing.T) {
+		resp, err := tp.Get(context.Background(), NewRequest(testutil.MustParseURL(server.URL)))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		require.Equal(t, "orb/1.0.0", header.Get(userAgentHeader))
+		require.NotEmpty(t, header.Get(requestid.HeaderName))
+
+		requestID := header.Get(requestid.HeaderName)
+
+		resp, err = tp.Get(context.Background(), NewRequest(testutil.MustParseURL(server.URL)))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		require.NotEqual(t, requestID, header.Get(requestid.HeaderName))
+	})
+
+	t.Run("POST with request ID in context -> propagated", func(t *testing.T) {
+		ctx := requestid.WithContext(context.Background(), "request-1")
+
+		resp, err := tp.Post(ctx, NewRequest(testutil.MustParseURL(server.URL)), []byte("payload"))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		require.Equal(t, "orb/1.0.0", header.Get(userAgentHeader))
+		require.Equal(t, "request-1", header.Get(requestid.HeaderName))
+	})
+
+	t.Run("Headers set on request -> not overwritten", func(t *testing.T) {
+		req := NewRequest(testutil.MustParseURL(server.URL),
+			WithHeader(userAgentHeader, "custom-agent"),
+			WithHeader(requestid.HeaderName, "request-2"),
+		)
+
+		resp, err := tp.Get(requestid.WithContext(context.Background(), "request-1"), req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		require.Equal(t, "custom-agent", header.Get(userAgentHeader))
+		require.Equal(t, "request-2", header.Get(requestid.HeaderName))
+	})
+
+	t.Run("No user agent -> default user agent", func(t *testing.T) {
+		httpClient := &http.Client{Transport: NewHeaderInjector(http.DefaultTransport, "")}
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+		require.NoError(t, err)
+
+		resp, err := httpClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		require.Contains(t, header.Get(userAgentHeader), "Go-http-client")
+		require.NotEmpty(t, header.Get(requestid.HeaderName))
+		require.Empty(t, req.Header.Get(requestid.HeaderName), "original request must not be modified")
+	})
+}
+
+func TestUserAgent(t *testing.T) {
+	require.Equal(t, "orb/1.0.0", UserAgent("orb", "1.0.0"))
+	require.Equal(t, "orb", UserAgent("orb", ""))
+}
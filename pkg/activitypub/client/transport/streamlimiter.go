@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// StreamLimiter is an HTTP round tripper which limits the number of concurrent requests to each host.
+// When HTTP/2 is negotiated with a peer, concurrent requests to the peer are multiplexed as streams over
+// a single connection and so the limit caps the number of concurrent streams on the connection. This avoids
+// overwhelming a busy peer with requests. (For a peer which only supports HTTP/1.1, the limit caps the number
+// of concurrent connections to the peer.) A request waits for a slot until its context is done. A slot is held
+// until the response body is closed (or the request fails).
+type StreamLimiter struct {
+	rt         http.RoundTripper
+	maxStreams int
+	mutex      sync.Mutex
+	hosts      map[string]chan struct{}
+}
+
+// NewStreamLimiter returns a round tripper which limits the number of concurrent requests to each host to the
+// given maximum. If the maximum is zero or negative then the given round tripper is returned as is.
+func NewStreamLimiter(rt http.RoundTripper, maxStreams int) http.RoundTripper {
+	if maxStreams <= 0 {
+		return rt
+	}
+
+	return &StreamLimiter{
+		rt:         rt,
+		maxStreams: maxStreams,
+		hosts:      make(map[string]chan struct{}),
+	}
+}
+
+// RoundTrip waits for a slot for the request's host and then sends the request using the underlying
+// round tripper.
+func (l *StreamLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	slots := l.slotsFor(req.URL.Host)
+
+	select {
+	case slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, fmt.Errorf("wait for stream to %s: %w", req.URL.Host, req.Context().Err())
+	}
+
+	release := func() { <-slots }
+
+	resp, err := l.rt.RoundTrip(req)
+	if err != nil {
+		release()
+
+		return nil, err
+	}
+
+	resp.Body = &releasingReadCloser{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+func (l *StreamLimiter) slotsFor(host string) chan struct{} {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	slots, ok := l.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, l.maxStreams)
+		l.hosts[host] = slots
+	}
+
+	return slots
+}
+
+// releasingReadCloser releases the slot of a request when the response body is closed.
+type releasingReadCloser struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (r *releasingReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+
+	r.once.Do(r.release)
+
+	return err
+}
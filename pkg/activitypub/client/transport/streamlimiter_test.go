@@ -0,0 +1,191 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStreamLimiter(t *testing.T) {
+	rt := &blockingRoundTripper{}
+
+	require.True(t, NewStreamLimiter(rt, 0) == rt)
+	require.IsType(t, &StreamLimiter{}, NewStreamLimiter(rt, 2))
+}
+
+func TestStreamLimiter_RoundTrip(t *testing.T) {
+	t.Run("Concurrent requests per host are limited", func(t *testing.T) {
+		rt := newBlockingRoundTripper()
+
+		l := NewStreamLimiter(rt, 2)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				resp, err := l.RoundTrip(newGetRequest(t, context.Background(), "https://domain1.com/path"))
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+			}()
+		}
+
+		// Requests to another host aren't affected by the limit for domain1.com.
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				resp, err := l.RoundTrip(newGetRequest(t, context.Background(), "https://domain2.com/path"))
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+			}()
+		}
+
+		require.Eventually(t, func() bool { return rt.inFlight("domain1.com") == 2 && rt.inFlight("domain2.com") == 2 },
+			5*time.Second, 5*time.Millisecond)
+
+		time.Sleep(50 * time.Millisecond)
+		require.Equal(t, 2, rt.inFlight("domain1.com"))
+
+		rt.unblock()
+		wg.Wait()
+
+		require.Equal(t, 2, rt.maxInFlight("domain1.com"))
+		require.Equal(t, 8, rt.total())
+	})
+
+	t.Run("Slot is held until the response body is closed", func(t *testing.T) {
+		rt := newBlockingRoundTripper()
+		rt.unblock()
+
+		l := NewStreamLimiter(rt, 1)
+
+		resp, err := l.RoundTrip(newGetRequest(t, context.Background(), "https://domain1.com/path"))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err = l.RoundTrip(newGetRequest(t, ctx, "https://domain1.com/path")) //nolint:bodyclose
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		require.NoError(t, resp.Body.Close())
+		require.NoError(t, resp.Body.Close())
+
+		resp, err = l.RoundTrip(newGetRequest(t, context.Background(), "https://domain1.com/path"))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	})
+
+	t.Run("Round trip error -> slot released", func(t *testing.T) {
+		errExpected := errors.New("injected round trip error")
+
+		rt := newBlockingRoundTripper()
+		rt.err = errExpected
+		rt.unblock()
+
+		l := NewStreamLimiter(rt, 1)
+
+		_, err := l.RoundTrip(newGetRequest(t, context.Background(), "https://domain1.com/path")) //nolint:bodyclose
+		require.ErrorIs(t, err, errExpected)
+
+		_, err = l.RoundTrip(newGetRequest(t, context.Background(), "https://domain1.com/path")) //nolint:bodyclose
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
+func newGetRequest(t *testing.T, ctx context.Context, u string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	require.NoError(t, err)
+
+	return req
+}
+
+// blockingRoundTripper blocks all requests until it's unblocked and records the number of in-flight
+// requests per host.
+type blockingRoundTripper struct {
+	mutex    sync.Mutex
+	blocked  chan struct{}
+	current  map[string]int
+	max      map[string]int
+	numTotal int
+	err      error
+}
+
+func newBlockingRoundTripper() *blockingRoundTripper {
+	return &blockingRoundTripper{
+		blocked: make(chan struct{}),
+		current: make(map[string]int),
+		max:     make(map[string]int),
+	}
+}
+
+func (rt *blockingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mutex.Lock()
+	rt.current[req.URL.Host]++
+
+	if rt.current[req.URL.Host] > rt.max[req.URL.Host] {
+		rt.max[req.URL.Host] = rt.current[req.URL.Host]
+	}
+	rt.mutex.Unlock()
+
+	<-rt.blocked
+
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	rt.current[req.URL.Host]--
+	rt.numTotal++
+
+	if rt.err != nil {
+		return nil, rt.err
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("body"))}, nil
+}
+
+func (rt *blockingRoundTripper) unblock() {
+	close(rt.blocked)
+}
+
+func (rt *blockingRoundTripper) inFlight(host string) int {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	return rt.current[host]
+}
+
+func (rt *blockingRoundTripper) maxInFlight(host string) int {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	return rt.max[host]
+}
+
+func (rt *blockingRoundTripper) total() int {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	return rt.numTotal
+}
@@ -146,7 +146,7 @@ func (t *Transport) Post(ctx context.Context, r *Request, payload []byte) (*http
 		logger.Debug("HTTP signature is not required for HTTP POST", logfields.WithRequestURL(r.URL))
 	}
 
-	return t.client.Do(req)
+	return t.do(req)
 }
 
 // Get sends an HTTP GET. The HTTP request is first signed and the signature is added to the request header.
@@ -176,7 +176,23 @@ func (t *Transport) Get(ctx context.Context, r *Request) (*http.Response, error)
 		logger.Debug("HTTP signature is not required for HTTP GET", logfields.WithRequestURL(r.URL))
 	}
 
-	return t.client.Do(req)
+	return t.do(req)
+}
+
+// do sends the request. If a timeout occurs then a TimeoutError is returned which indicates whether
+// the timeout occurred while connecting or waiting for the response. A timeout while subsequently reading
+// the response body results in a TimeoutError with phase ErrBodyReadTimeout.
+func (t *Transport) do(req *http.Request) (*http.Response, error) {
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, wrapTimeoutError(err)
+	}
+
+	if resp.Body != nil {
+		resp.Body = &timeoutReadCloser{ReadCloser: resp.Body}
+	}
+
+	return resp, nil
 }
 
 // NoOpSigner is a signer that does nothing. This signer should only be used by tests.
@@ -176,20 +176,22 @@ func (h *Activities) handleActivities(rw http.ResponseWriter, _ *http.Request, o
 func (h *Activities) handleActivitiesPage(rw http.ResponseWriter, req *http.Request, objectIRI, id *url.URL,
 	refType spi.ReferenceType,
 ) {
-	var page *vocab.OrderedCollectionPageType
+	var page *collectionPage
 
 	var err error
 
+	embed := h.isEmbedding(req)
+
 	pageNum, ok := h.getPageNum(req)
 	if ok {
-		page, err = h.getPage(objectIRI, id, refType,
-			spi.WithPageSize(h.PageSize),
+		page, err = h.getPage(objectIRI, id, refType, embed,
+			spi.WithPageSize(h.pageSize()),
 			spi.WithPageNum(pageNum),
 			spi.WithSortOrder(h.sortOrder),
 		)
 	} else {
-		page, err = h.getPage(objectIRI, id, refType,
-			spi.WithPageSize(h.PageSize),
+		page, err = h.getPage(objectIRI, id, refType, embed,
+			spi.WithPageSize(h.pageSize()),
 			spi.WithSortOrder(h.sortOrder),
 		)
 	}
@@ -203,17 +205,13 @@ func (h *Activities) handleActivitiesPage(rw http.ResponseWriter, req *http.Requ
 		return
 	}
 
-	pageBytes, err := h.marshal(page)
+	err = h.writePage(rw, page)
 	if err != nil {
 		h.logger.Error("Unable to marshal page for object IRI",
 			logfields.WithObjectIRI(objectIRI), log.WithError(err))
 
 		h.writeResponse(rw, http.StatusInternalServerError, []byte(internalServerErrorResponse))
-
-		return
 	}
-
-	h.writeResponse(rw, http.StatusOK, pageBytes)
 }
 
 func (h *Activities) getActivities(objectIRI, id *url.URL,
@@ -245,7 +243,7 @@ func (h *Activities) getActivities(objectIRI, id *url.URL,
 		return nil, fmt.Errorf("failed to get total items from reference query: %w", err)
 	}
 
-	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.PageSize, h.sortOrder))
+	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.pageSize(), h.sortOrder))
 	if err != nil {
 		return nil, err
 	}
@@ -259,9 +257,9 @@ func (h *Activities) getActivities(objectIRI, id *url.URL,
 	), nil
 }
 
-func (h *Activities) getPage(objectIRI, id *url.URL, refType spi.ReferenceType,
+func (h *Activities) getPage(objectIRI, id *url.URL, refType spi.ReferenceType, embed bool,
 	opts ...spi.QueryOpt,
-) (*vocab.OrderedCollectionPageType, error) {
+) (*collectionPage, error) {
 	it, err := h.activityStore.QueryActivities(
 		spi.NewCriteria(
 			spi.WithReferenceType(refType),
@@ -289,6 +287,13 @@ func (h *Activities) getPage(objectIRI, id *url.URL, refType spi.ReferenceType,
 	items := make([]*vocab.ObjectProperty, len(activities))
 
 	for i, activity := range activities {
+		if embed {
+			activity, err = h.embedAnchors(activity)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		items[i] = vocab.NewObjectProperty(vocab.WithActivity(activity))
 	}
 
@@ -302,7 +307,7 @@ func (h *Activities) getPage(objectIRI, id *url.URL, refType spi.ReferenceType,
 		return nil, err
 	}
 
-	return vocab.NewOrderedCollectionPage(items,
+	return newOrderedCollectionPage(items,
 		vocab.WithContext(vocab.ContextActivityStreams),
 		vocab.WithID(id),
 		vocab.WithPrev(prev),
@@ -376,6 +381,18 @@ func (h *Activity) handle(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 
+	if h.isEmbedding(req) {
+		activity, err = h.embedAnchors(activity)
+		if err != nil {
+			h.logger.Error("Unable to embed anchors into activity", logfields.WithActivityID(activityIRI),
+				log.WithError(err))
+
+			h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+			return
+		}
+	}
+
 	activityBytes, err := h.marshal(activity)
 	if err != nil {
 		h.logger.Error("Unable to marshal activity", logfields.WithActivityID(activityIRI), log.WithError(err))
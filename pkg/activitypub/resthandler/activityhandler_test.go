@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package resthandler
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -673,6 +674,50 @@ func TestShares_PageHandler(t *testing.T) {
 		require.Equal(t, testutil.GetCanonical(t, sharesPage1JSON), testutil.GetCanonical(t, string(respBytes)))
 		require.NoError(t, result.Body.Close())
 	})
+
+	t.Run("Ascending -> Success", func(t *testing.T) {
+		h := NewShares(cfg, activityStore, verifier, spi.SortAscending, &apmocks.AuthTokenMgr{})
+		require.NotNil(t, h)
+
+		restore := setIDParam(id)
+		defer restore()
+
+		pageURL := func(pageNum int) string {
+			return fmt.Sprintf("%s/shares/%s?page=true&page-num=%d", srvcIRI, url.QueryEscape(id), pageNum)
+		}
+
+		t.Run("First page", func(t *testing.T) {
+			page := getActivitiesPage(t, h.handler, h.handle, "")
+			require.Equal(t, pageURL(0), page.ID().String())
+			require.Nil(t, page.Prev())
+			require.Equal(t, pageURL(1), page.Next().String())
+			require.Equal(t, 19, page.TotalItems())
+			requireActivityIDs(t, shares[0:4], page.Items())
+		})
+
+		t.Run("Middle page", func(t *testing.T) {
+			page := getActivitiesPage(t, h.handler, h.handle, "2")
+			require.Equal(t, pageURL(2), page.ID().String())
+			require.Equal(t, pageURL(1), page.Prev().String())
+			require.Equal(t, pageURL(3), page.Next().String())
+			requireActivityIDs(t, shares[8:12], page.Items())
+		})
+
+		t.Run("Last page", func(t *testing.T) {
+			page := getActivitiesPage(t, h.handler, h.handle, "4")
+			require.Equal(t, pageURL(4), page.ID().String())
+			require.Equal(t, pageURL(3), page.Prev().String())
+			require.Nil(t, page.Next())
+			requireActivityIDs(t, shares[16:19], page.Items())
+		})
+
+		t.Run("Page out of range", func(t *testing.T) {
+			page := getActivitiesPage(t, h.handler, h.handle, "5")
+			require.Equal(t, pageURL(4), page.Prev().String())
+			require.Nil(t, page.Next())
+			require.Empty(t, page.Items())
+		})
+	})
 }
 
 func TestLiked_Handler(t *testing.T) {
@@ -697,7 +742,7 @@ func TestLiked_Handler(t *testing.T) {
 	verifier := &mocks.SignatureVerifier{}
 	verifier.VerifyRequestReturns(true, serviceIRI, nil)
 
-	h := NewLiked(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+	h := NewLiked(cfg, activityStore, verifier, spi.SortAscending, &apmocks.AuthTokenMgr{})
 	require.NotNil(t, h)
 
 	t.Run("Main page -> Success", func(t *testing.T) {
@@ -707,6 +752,51 @@ func TestLiked_Handler(t *testing.T) {
 	t.Run("First page -> Success", func(t *testing.T) {
 		handleRequest(t, h.handler, h.handle, "true", "", likedFirstPageJSON)
 	})
+
+	t.Run("Ascending pages -> Success", func(t *testing.T) {
+		pageURL := func(pageNum int) string {
+			return fmt.Sprintf("%s/liked?page=true&page-num=%d", serviceIRI, pageNum)
+		}
+
+		for pageNum, expected := range [][]*vocab.ActivityType{liked[0:5], liked[5:10], liked[10:15], liked[15:19]} {
+			page := getActivitiesPage(t, h.handler, h.handle, fmt.Sprintf("%d", pageNum))
+			require.Equal(t, pageURL(pageNum), page.ID().String())
+			require.Equal(t, 19, page.TotalItems())
+
+			if pageNum == 0 {
+				require.Nil(t, page.Prev())
+			} else {
+				require.Equal(t, pageURL(pageNum-1), page.Prev().String())
+			}
+
+			if pageNum == 3 {
+				require.Nil(t, page.Next())
+			} else {
+				require.Equal(t, pageURL(pageNum+1), page.Next().String())
+			}
+
+			items := page.Items()
+			require.Len(t, items, len(expected))
+
+			for i, a := range expected {
+				require.Equal(t, a.Object().AnchorEvent().URL()[0].String(), items[i].IRI().String())
+			}
+		}
+	})
+
+	t.Run("Descending pages -> Success", func(t *testing.T) {
+		hd := NewLiked(cfg, activityStore, verifier, spi.SortDescending, &apmocks.AuthTokenMgr{})
+
+		page := getActivitiesPage(t, hd.handler, hd.handle, "")
+		require.Equal(t, fmt.Sprintf("%s/liked?page=true&page-num=3", serviceIRI), page.ID().String())
+		require.Nil(t, page.Prev())
+		require.Equal(t, fmt.Sprintf("%s/liked?page=true&page-num=2", serviceIRI), page.Next().String())
+
+		items := page.Items()
+		require.Len(t, items, 5)
+		require.Equal(t, liked[18].Object().AnchorEvent().URL()[0].String(), items[0].IRI().String())
+		require.Equal(t, liked[14].Object().AnchorEvent().URL()[0].String(), items[4].IRI().String())
+	})
 }
 
 func TestNewActivity(t *testing.T) {
@@ -939,7 +1029,7 @@ func TestActivityHandlerGetPage(t *testing.T) {
 
 	activitiesHandler := Activities{handler: &handler{AuthHandler: &AuthHandler{activityStore: &mockActivityStore}}}
 
-	page, err := activitiesHandler.getPage(&url.URL{}, &url.URL{}, spi.Inbox)
+	page, err := activitiesHandler.getPage(&url.URL{}, &url.URL{}, spi.Inbox, false)
 	require.EqualError(t, err, "failed to get total items from activity query: total items error")
 	require.Nil(t, page)
 }
@@ -979,6 +1069,43 @@ func handleActivitiesRequest(t *testing.T, serviceIRI *url.URL, as spi.Store, pa
 	require.Equal(t, testutil.GetCanonical(t, expected), testutil.GetCanonical(t, string(respBytes)))
 }
 
+func getActivitiesPage(t *testing.T, h *handler, handle http.HandlerFunc,
+	pageNum string,
+) *vocab.OrderedCollectionPageType {
+	t.Helper()
+
+	restorePaging := setPaging(h, "true", pageNum)
+	defer restorePaging()
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/services/orb", http.NoBody)
+
+	handle(rw, req)
+
+	result := rw.Result()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	page := &vocab.OrderedCollectionPageType{}
+	require.NoError(t, json.Unmarshal(respBytes, page))
+
+	return page
+}
+
+func requireActivityIDs(t *testing.T, expected []*vocab.ActivityType, items []*vocab.ObjectProperty) {
+	t.Helper()
+
+	require.Len(t, items, len(expected))
+
+	for i, a := range expected {
+		require.NotNil(t, items[i].Activity())
+		require.Equal(t, a.ID().String(), items[i].Activity().ID().String())
+	}
+}
+
 func newMockActivities(t vocab.Type, num int, getURI func(i int) string) []*vocab.ActivityType {
 	activities := make([]*vocab.ActivityType, num)
 
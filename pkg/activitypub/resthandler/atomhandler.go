@@ -0,0 +1,281 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	atomContentType = "application/atom+xml; charset=utf-8"
+
+	// DefaultAtomFeedMaxEntries is the default number of entries in the outbox Atom feed.
+	DefaultAtomFeedMaxEntries = 50
+)
+
+// OutboxAtom implements a REST handler that renders the most recent public activities of
+// a service's outbox as an Atom feed. Each entry ID is the IRI of the activity so that
+// feed readers may de-duplicate entries across polls.
+type OutboxAtom struct {
+	*Config
+
+	endpoint      string
+	activityStore spi.Store
+	maxEntries    int
+	marshal       func(v interface{}) ([]byte, error)
+	logger        *log.Log
+}
+
+// NewOutboxAtom returns a new REST handler that renders a service's public outbox as an Atom feed containing
+// at most maxEntries entries. If maxEntries isn't greater than zero then DefaultAtomFeedMaxEntries is used.
+func NewOutboxAtom(cfg *Config, activityStore spi.Store, maxEntries int) *OutboxAtom {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, OutboxAtomPath)
+
+	if maxEntries <= 0 {
+		maxEntries = DefaultAtomFeedMaxEntries
+	}
+
+	return &OutboxAtom{
+		Config:        cfg,
+		endpoint:      endpoint,
+		activityStore: activityStore,
+		maxEntries:    maxEntries,
+		marshal:       xml.Marshal,
+		logger:        log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *OutboxAtom) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *OutboxAtom) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *OutboxAtom) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *OutboxAtom) handle(w http.ResponseWriter, _ *http.Request) {
+	// Only public activities are included in the feed, so authorization isn't required.
+	activities, err := h.getActivities()
+	if err != nil {
+		h.logger.Error("Error retrieving public outbox activities", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	feedBytes, err := h.marshal(h.newFeed(activities))
+	if err != nil {
+		h.logger.Error("Unable to marshal Atom feed", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", atomContentType)
+
+	writeResponse(h.logger, w, http.StatusOK, append([]byte(xml.Header), feedBytes...))
+}
+
+func (h *OutboxAtom) getActivities() ([]*vocab.ActivityType, error) {
+	it, err := h.activityStore.QueryActivities(
+		spi.NewCriteria(
+			spi.WithReferenceType(spi.PublicOutbox),
+			spi.WithObjectIRI(h.ObjectIRI),
+		),
+		spi.WithPageSize(h.maxEntries),
+		spi.WithSortOrder(spi.SortDescending),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query public outbox: %w", err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			log.CloseIteratorError(h.logger, e)
+		}
+	}()
+
+	return storeutil.ReadActivities(it, h.maxEntries)
+}
+
+func (h *OutboxAtom) newFeed(activities []*vocab.ActivityType) *atomFeed {
+	feed := &atomFeed{
+		ID:    h.ObjectIRI.String(),
+		Title: fmt.Sprintf("Outbox of %s", h.ObjectIRI),
+		Links: []*atomLink{
+			{Href: fmt.Sprintf("%s%s", h.ServiceEndpointURL, OutboxAtomPath), Rel: "self", Type: atomContentType},
+			{Href: fmt.Sprintf("%s%s", h.ServiceEndpointURL, OutboxPath), Rel: "alternate", Type: transport.ActivityStreamsContentType},
+		},
+		Author: &atomAuthor{
+			Name: h.ObjectIRI.String(),
+			URI:  h.ObjectIRI.String(),
+		},
+	}
+
+	var updated time.Time
+
+	for _, activity := range activities {
+		entry := newAtomEntry(activity)
+
+		feed.Entries = append(feed.Entries, entry)
+
+		if entry.updated.After(updated) {
+			updated = entry.updated
+		}
+	}
+
+	if updated.IsZero() {
+		// The feed has no entries with a published time.
+		updated = time.Now()
+	}
+
+	feed.Updated = formatAtomTime(updated)
+
+	return feed
+}
+
+func newAtomEntry(activity *vocab.ActivityType) *atomEntry {
+	entry := &atomEntry{
+		ID:    activity.ID().String(),
+		Title: fmt.Sprintf("%s activity", activity.Type()),
+	}
+
+	if published := activity.Published(); published != nil {
+		entry.updated = *published
+		entry.Published = formatAtomTime(*published)
+	}
+
+	// The 'updated' element is required. If the activity doesn't have a published time then the Unix epoch
+	// is used so that the entry doesn't change across polls.
+	entry.Updated = formatAtomTime(entry.updated)
+
+	if activity.Actor() != nil {
+		entry.Author = &atomAuthor{
+			Name: activity.Actor().String(),
+			URI:  activity.Actor().String(),
+		}
+	}
+
+	anchors := getAnchorURLs(activity)
+
+	for _, anchor := range anchors {
+		entry.Links = append(entry.Links, &atomLink{Href: anchor.String(), Rel: "related"})
+	}
+
+	if len(anchors) > 0 {
+		entry.Summary = fmt.Sprintf("%s activity for anchor(s): %s", activity.Type(), vocab.Urls(anchors))
+	}
+
+	entry.Links = append(entry.Links, &atomLink{
+		Href: activity.ID().String(), Rel: "alternate", Type: transport.ActivityStreamsContentType,
+	})
+
+	return entry
+}
+
+// getAnchorURLs returns the URLs of the anchors referenced by the given activity. A 'Create' activity contains
+// a single anchor event, whereas an 'Announce' activity contains a collection of anchor events. For all other
+// activities the IRI of the object (if any) is returned.
+func getAnchorURLs(activity *vocab.ActivityType) []*url.URL {
+	obj := activity.Object()
+	if obj == nil {
+		return nil
+	}
+
+	var items []*vocab.ObjectProperty
+
+	switch {
+	case obj.Type() == nil:
+		if obj.IRI() != nil {
+			return []*url.URL{obj.IRI()}
+		}
+
+		return nil
+	case obj.Type().Is(vocab.TypeAnchorEvent):
+		items = []*vocab.ObjectProperty{obj}
+	case obj.Collection() != nil:
+		items = obj.Collection().Items()
+	case obj.OrderedCollection() != nil:
+		items = obj.OrderedCollection().Items()
+	}
+
+	var anchors []*url.URL
+
+	for _, item := range items {
+		if item.AnchorEvent() == nil {
+			continue
+		}
+
+		anchors = append(anchors, item.AnchorEvent().URL()...)
+	}
+
+	return anchors
+}
+
+func formatAtomTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Links   []*atomLink  `xml:"link"`
+	Author  *atomAuthor  `xml:"author,omitempty"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Summary   string      `xml:"summary,omitempty"`
+	Links     []*atomLink `xml:"link"`
+
+	updated time.Time
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
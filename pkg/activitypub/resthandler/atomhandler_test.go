@@ -0,0 +1,242 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const outboxAtomURL = "https://example1.com/services/orb/outbox.atom"
+
+func TestNewOutboxAtom(t *testing.T) {
+	cfg := &Config{
+		BasePath:           basePath,
+		ObjectIRI:          serviceIRI,
+		ServiceEndpointURL: serviceIRI,
+	}
+
+	h := NewOutboxAtom(cfg, memstore.New(""), 0)
+	require.NotNil(t, h)
+	require.NotNil(t, h.Handler())
+	require.Equal(t, "/services/orb/outbox.atom", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.Equal(t, DefaultAtomFeedMaxEntries, h.maxEntries)
+
+	h = NewOutboxAtom(cfg, memstore.New(""), 10)
+	require.Equal(t, 10, h.maxEntries)
+}
+
+func TestOutboxAtom_Handler(t *testing.T) {
+	cfg := &Config{
+		BasePath:           basePath,
+		ObjectIRI:          serviceIRI,
+		ServiceEndpointURL: serviceIRI,
+	}
+
+	const (
+		anchor1 = "hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElrNQ"
+		anchor2 = "hl:uEiBUQDRI5ttIzXbe1LZKUaZWb6yFsnMnrgDksAtQ-wCaKw"
+	)
+
+	published := getStaticTime()
+
+	activityStore := memstore.New("")
+
+	var publicActivities []*vocab.ActivityType
+
+	for i := 0; i < 4; i++ {
+		privateActivity := newMockAnchorActivity(vocab.TypeCreate, i, published.Add(time.Duration(i)*time.Minute),
+			anchor1)
+
+		require.NoError(t, activityStore.AddActivity(privateActivity))
+		require.NoError(t, activityStore.AddReference(spi.Outbox, serviceIRI, privateActivity.ID().URL()))
+
+		publicActivity := newMockAnchorActivity(vocab.TypeAnnounce, i+100, published.Add(time.Duration(i)*time.Minute),
+			anchor1, anchor2)
+
+		require.NoError(t, activityStore.AddActivity(publicActivity))
+		require.NoError(t, activityStore.AddReference(spi.Outbox, serviceIRI, publicActivity.ID().URL()))
+		require.NoError(t, activityStore.AddReference(spi.PublicOutbox, serviceIRI, publicActivity.ID().URL()))
+
+		publicActivities = append(publicActivities, publicActivity)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		h := NewOutboxAtom(cfg, activityStore, 3)
+
+		respBytes, contentType := getAtomFeed(t, h)
+		require.Equal(t, atomContentType, contentType)
+
+		feed := &atomFeed{}
+		require.NoError(t, xml.Unmarshal(respBytes, feed))
+
+		require.Equal(t, "http://www.w3.org/2005/Atom", feed.XMLName.Space)
+		require.Equal(t, serviceIRI.String(), feed.ID)
+		require.Equal(t, "2021-01-27T09:33:10Z", feed.Updated)
+		require.Len(t, feed.Links, 2)
+		require.Equal(t, outboxAtomURL, feed.Links[0].Href)
+		require.Equal(t, "self", feed.Links[0].Rel)
+
+		// Only the most recent public activities are included in the feed, newest first.
+		require.Len(t, feed.Entries, 3)
+
+		for i, entry := range feed.Entries {
+			activity := publicActivities[len(publicActivities)-1-i]
+
+			require.Equal(t, activity.ID().String(), entry.ID)
+			require.Equal(t, "Announce activity", entry.Title)
+			require.Equal(t, activity.Published().UTC().Format(time.RFC3339), entry.Updated)
+			require.Equal(t, entry.Updated, entry.Published)
+			require.NotNil(t, entry.Author)
+			require.Equal(t, serviceIRI.String(), entry.Author.URI)
+
+			require.Len(t, entry.Links, 3)
+			require.Equal(t, anchor1, entry.Links[0].Href)
+			require.Equal(t, "related", entry.Links[0].Rel)
+			require.Equal(t, anchor2, entry.Links[1].Href)
+			require.Equal(t, activity.ID().String(), entry.Links[2].Href)
+			require.Equal(t, "alternate", entry.Links[2].Rel)
+		}
+
+		// The feed should be identical across polls so that readers may de-duplicate entries.
+		respBytes2, _ := getAtomFeed(t, h)
+		require.Equal(t, string(respBytes), string(respBytes2))
+	})
+
+	t.Run("Empty outbox", func(t *testing.T) {
+		h := NewOutboxAtom(cfg, memstore.New(""), 3)
+
+		respBytes, _ := getAtomFeed(t, h)
+
+		feed := &atomFeed{}
+		require.NoError(t, xml.Unmarshal(respBytes, feed))
+		require.Empty(t, feed.Entries)
+		require.NotEmpty(t, feed.Updated)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		h := NewOutboxAtom(cfg, &failingActivityStore{Store: activityStore, err: errExpected}, 3)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, outboxAtomURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewOutboxAtom(cfg, activityStore, 3)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, outboxAtomURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+func TestGetAnchorURLs(t *testing.T) {
+	const anchor = "hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElrNQ"
+
+	t.Run("Create", func(t *testing.T) {
+		activity := vocab.NewCreateActivity(
+			vocab.NewObjectProperty(vocab.WithAnchorEvent(vocab.NewAnchorEvent(nil,
+				vocab.WithURL(testutil.MustParseURL(anchor))))),
+		)
+
+		anchors := getAnchorURLs(activity)
+		require.Len(t, anchors, 1)
+		require.Equal(t, anchor, anchors[0].String())
+	})
+
+	t.Run("Object IRI", func(t *testing.T) {
+		activity := vocab.NewLikeActivity(vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL(anchor))))
+
+		anchors := getAnchorURLs(activity)
+		require.Len(t, anchors, 1)
+		require.Equal(t, anchor, anchors[0].String())
+	})
+
+	t.Run("No object", func(t *testing.T) {
+		require.Empty(t, getAnchorURLs(vocab.NewCreateActivity(nil)))
+	})
+}
+
+func getAtomFeed(t *testing.T, h *OutboxAtom) ([]byte, string) {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handle(rw, httptest.NewRequest(http.MethodGet, outboxAtomURL, nil))
+
+	result := rw.Result()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	t.Logf("%s", respBytes)
+
+	return respBytes, result.Header.Get("Content-Type")
+}
+
+func newMockAnchorActivity(t vocab.Type, i int, published time.Time, anchors ...string) *vocab.ActivityType {
+	id := testutil.MustParseURL(fmt.Sprintf("https://example1.com/services/orb/activities/%d", i))
+
+	items := make([]*vocab.ObjectProperty, len(anchors))
+
+	for j, anchor := range anchors {
+		items[j] = vocab.NewObjectProperty(vocab.WithAnchorEvent(vocab.NewAnchorEvent(nil,
+			vocab.WithURL(testutil.MustParseURL(anchor)))))
+	}
+
+	opts := []vocab.Opt{
+		vocab.WithID(id),
+		vocab.WithActor(serviceIRI),
+		vocab.WithTo(vocab.PublicIRI),
+		vocab.WithPublishedTime(&published),
+	}
+
+	if t == vocab.TypeAnnounce {
+		return vocab.NewAnnounceActivity(vocab.NewObjectProperty(vocab.WithCollection(vocab.NewCollection(items))),
+			opts...)
+	}
+
+	return vocab.NewCreateActivity(items[0], opts...)
+}
+
+type failingActivityStore struct {
+	spi.Store
+
+	err error
+}
+
+func (s *failingActivityStore) QueryActivities(*spi.Criteria, ...spi.QueryOpt) (spi.ActivityIterator, error) {
+	return nil, s.err
+}
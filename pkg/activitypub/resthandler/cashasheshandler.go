@@ -0,0 +1,214 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/store/cas"
+)
+
+type casHashLister interface {
+	GetResourceHashes(opts ...storage.QueryOption) (cas.ResourceHashIterator, error)
+}
+
+// CASHashes implements a REST handler that exposes the resource hashes held in the local CAS as an ordered
+// collection of hashlinks (ordered by resource hash). The collection may be used to build a mirror or to
+// verify that content has been replicated to this node.
+type CASHashes struct {
+	*handler
+
+	lister casHashLister
+}
+
+// NewCASHashes returns a new 'cas-hashes' REST handler.
+func NewCASHashes(cfg *Config, activityStore spi.Store, lister casHashLister,
+	verifier signatureVerifier, tm authTokenManager,
+) *CASHashes {
+	h := &CASHashes{
+		lister: lister,
+	}
+
+	h.handler = newHandler(CASHashesPath, cfg, activityStore, h.handle, verifier, spi.SortAscending, tm)
+
+	return h
+}
+
+func (h *CASHashes) handle(w http.ResponseWriter, req *http.Request) {
+	ok, _, err := h.Authorize(req)
+	if err != nil {
+		h.logger.Error("Error authorizing request", log.WithError(err))
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if !ok {
+		h.writeResponse(w, http.StatusUnauthorized, []byte(unauthorizedResponse))
+
+		return
+	}
+
+	id, err := url.Parse(fmt.Sprintf("%s%s", h.ServiceEndpointURL, CASHashesPath))
+	if err != nil {
+		h.logger.Error("Error generating ID", log.WithError(err))
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	var coll interface{}
+
+	if h.isPaging(req) {
+		pageNum, ok := h.getPageNum(req)
+		if !ok {
+			pageNum = -1
+		}
+
+		coll, err = h.getPage(id, pageNum)
+	} else {
+		coll, err = h.getCollection(id)
+	}
+
+	if err != nil {
+		h.logger.Error("Error retrieving CAS hashes", log.WithError(err))
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	collBytes, err := h.marshal(coll)
+	if err != nil {
+		h.logger.Error("Unable to marshal CAS hashes", log.WithError(err))
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, collBytes)
+}
+
+func (h *CASHashes) getCollection(id *url.URL) (*vocab.OrderedCollectionType, error) {
+	it, err := h.lister.GetResourceHashes(storage.WithPageSize(h.pageSize()))
+	if err != nil {
+		return nil, fmt.Errorf("query CAS hashes: %w", err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			log.CloseIteratorError(h.logger, e)
+		}
+	}()
+
+	totalItems, err := it.TotalItems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total items from CAS hashes query: %w", err)
+	}
+
+	firstURL, err := h.getPageURL(id, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.pageSize(), h.sortOrder))
+	if err != nil {
+		return nil, err
+	}
+
+	return vocab.NewOrderedCollection(nil,
+		vocab.WithContext(vocab.ContextActivityStreams),
+		vocab.WithID(id),
+		vocab.WithFirst(firstURL),
+		vocab.WithLast(lastURL),
+		vocab.WithTotalItems(totalItems),
+	), nil
+}
+
+func (h *CASHashes) getPage(id *url.URL, pageNum int) (*vocab.OrderedCollectionPageType, error) {
+	options := &spi.QueryOptions{
+		PageSize:   h.pageSize(),
+		PageNumber: pageNum,
+		SortOrder:  h.sortOrder,
+	}
+
+	queryOpts := []storage.QueryOption{storage.WithPageSize(h.pageSize())}
+
+	if pageNum > 0 {
+		queryOpts = append(queryOpts, storage.WithInitialPageNum(pageNum))
+	}
+
+	it, err := h.lister.GetResourceHashes(queryOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("query CAS hashes: %w", err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			log.CloseIteratorError(h.logger, e)
+		}
+	}()
+
+	items, err := readCASHashes(it, h.pageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	totalItems, err := it.TotalItems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total items from CAS hashes query: %w", err)
+	}
+
+	pageID, prev, next, err := h.getIDPrevNextURL(id, totalItems, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return vocab.NewOrderedCollectionPage(items,
+		vocab.WithContext(vocab.ContextActivityStreams),
+		vocab.WithID(pageID),
+		vocab.WithPrev(prev),
+		vocab.WithNext(next),
+		vocab.WithTotalItems(totalItems),
+	), nil
+}
+
+func readCASHashes(it cas.ResourceHashIterator, maxItems int) ([]*vocab.ObjectProperty, error) {
+	var items []*vocab.ObjectProperty
+
+	for maxItems <= 0 || len(items) < maxItems {
+		resourceHash, err := it.Next()
+		if err != nil {
+			if errors.Is(err, cas.ErrDataNotFound) {
+				break
+			}
+
+			return nil, fmt.Errorf("read CAS hash: %w", err)
+		}
+
+		hl, err := url.Parse(hashlink.GetHashLinkFromResourceHash(resourceHash))
+		if err != nil {
+			return nil, fmt.Errorf("parse hashlink for resource hash [%s]: %w", resourceHash, err)
+		}
+
+		items = append(items, vocab.NewObjectProperty(vocab.WithIRI(hl)))
+	}
+
+	return items, nil
+}
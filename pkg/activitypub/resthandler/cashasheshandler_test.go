@@ -0,0 +1,272 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/store/cas"
+)
+
+const casHashesURL = "https://example1.com/services/orb/cas-hashes"
+
+func TestNewCASHashes(t *testing.T) {
+	cfg := &Config{
+		BasePath:           basePath,
+		ObjectIRI:          serviceIRI,
+		ServiceEndpointURL: serviceIRI,
+		PageSize:           4,
+	}
+
+	h := NewCASHashes(cfg, memstore.New(""), &mockCASHashLister{}, &mocks.SignatureVerifier{}, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h)
+	require.Equal(t, "/services/orb/cas-hashes", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+}
+
+func TestCASHashes_Handler(t *testing.T) {
+	lister := newMockCASHashLister(t, 10)
+
+	cfg := &Config{
+		BasePath:           basePath,
+		ObjectIRI:          serviceIRI,
+		ServiceEndpointURL: serviceIRI,
+		PageSize:           4,
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+	verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+	h := NewCASHashes(cfg, memstore.New(""), lister, verifier, &apmocks.AuthTokenMgr{})
+
+	t.Run("Collection", func(t *testing.T) {
+		coll := &vocab.OrderedCollectionType{}
+
+		handleCASHashesRequest(t, h, casHashesURL, http.StatusOK, coll)
+
+		require.Equal(t, casHashesURL, coll.ID().String())
+		require.Equal(t, 10, coll.TotalItems())
+		require.Equal(t, casHashesURL+"?page=true", coll.First().String())
+		require.Equal(t, casHashesURL+"?page=true&page-num=2", coll.Last().String())
+	})
+
+	t.Run("Pages", func(t *testing.T) {
+		var hashlinks, pageIDs []string
+
+		pageURL := casHashesURL + "?page=true"
+
+		for pageURL != "" {
+			page := &vocab.OrderedCollectionPageType{}
+
+			handleCASHashesRequest(t, h, pageURL, http.StatusOK, page)
+
+			require.Equal(t, 10, page.TotalItems())
+			require.LessOrEqual(t, len(page.Items()), cfg.PageSize)
+
+			pageIDs = append(pageIDs, page.ID().String())
+
+			for _, item := range page.Items() {
+				require.NotNil(t, item.IRI())
+
+				hashlinks = append(hashlinks, item.IRI().String())
+			}
+
+			pageURL = ""
+
+			if page.Next() != nil {
+				pageURL = page.Next().String()
+			}
+		}
+
+		require.Equal(t, lister.hashlinks(), hashlinks)
+		require.Equal(t, []string{
+			casHashesURL + "?page=true&page-num=0",
+			casHashesURL + "?page=true&page-num=1",
+			casHashesURL + "?page=true&page-num=2",
+		}, pageIDs)
+	})
+
+	t.Run("Page", func(t *testing.T) {
+		page := &vocab.OrderedCollectionPageType{}
+
+		handleCASHashesRequest(t, h, casHashesURL+"?page=true&page-num=2", http.StatusOK, page)
+
+		require.Equal(t, casHashesURL+"?page=true&page-num=1", page.Prev().String())
+		require.Nil(t, page.Next())
+		require.Len(t, page.Items(), 2)
+		require.Equal(t, lister.hashlinks()[8], page.Items()[0].IRI().String())
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"read"}, nil)
+
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, nil)
+
+		h := NewCASHashes(cfg, memstore.New(""), lister, v, tm)
+
+		handleCASHashesRequest(t, h, casHashesURL, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("Authorization error", func(t *testing.T) {
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"read"}, nil)
+
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, errors.New("injected verifier error"))
+
+		h := NewCASHashes(cfg, memstore.New(""), lister, v, tm)
+
+		handleCASHashesRequest(t, h, casHashesURL, http.StatusInternalServerError, nil)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		h := NewCASHashes(cfg, memstore.New(""), &mockCASHashLister{err: errors.New("injected query error")},
+			verifier, &apmocks.AuthTokenMgr{})
+
+		handleCASHashesRequest(t, h, casHashesURL, http.StatusInternalServerError, nil)
+		handleCASHashesRequest(t, h, casHashesURL+"?page=true", http.StatusInternalServerError, nil)
+	})
+
+	t.Run("Iterator error", func(t *testing.T) {
+		h := NewCASHashes(cfg, memstore.New(""), &mockCASHashLister{nextErr: errors.New("injected next error")},
+			verifier, &apmocks.AuthTokenMgr{})
+
+		handleCASHashesRequest(t, h, casHashesURL+"?page=true", http.StatusInternalServerError, nil)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewCASHashes(cfg, memstore.New(""), lister, verifier, &apmocks.AuthTokenMgr{})
+
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		handleCASHashesRequest(t, h, casHashesURL, http.StatusInternalServerError, nil)
+	})
+}
+
+func handleCASHashesRequest(t *testing.T, h *CASHashes, reqURL string, expectedStatus int, result interface{}) {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, reqURL, http.NoBody)
+
+	h.handle(rw, req)
+
+	resp := rw.Result()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, expectedStatus, resp.StatusCode, string(respBytes))
+
+	if result != nil {
+		require.NoError(t, json.Unmarshal(respBytes, result))
+	}
+}
+
+// mockCASHashLister holds the resource hashes of a set of blobs (sorted by hash) and supports paging.
+type mockCASHashLister struct {
+	hashes  []string
+	err     error
+	nextErr error
+}
+
+func newMockCASHashLister(t *testing.T, num int) *mockCASHashLister {
+	t.Helper()
+
+	l := &mockCASHashLister{}
+
+	for i := 0; i < num; i++ {
+		rh, err := hashlink.New().CreateResourceHash([]byte(fmt.Sprintf("blob %d", i)))
+		require.NoError(t, err)
+
+		l.hashes = append(l.hashes, rh)
+	}
+
+	sort.Strings(l.hashes)
+
+	return l
+}
+
+func (l *mockCASHashLister) GetResourceHashes(opts ...storage.QueryOption) (cas.ResourceHashIterator, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	options := &storage.QueryOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	start := options.InitialPageNum * options.PageSize
+	if start > len(l.hashes) {
+		start = len(l.hashes)
+	}
+
+	return &mockResourceHashIterator{hashes: l.hashes[start:], totalItems: len(l.hashes), err: l.nextErr}, nil
+}
+
+func (l *mockCASHashLister) hashlinks() []string {
+	hashlinks := make([]string, len(l.hashes))
+
+	for i, rh := range l.hashes {
+		hashlinks[i] = hashlink.GetHashLinkFromResourceHash(rh)
+	}
+
+	return hashlinks
+}
+
+type mockResourceHashIterator struct {
+	hashes     []string
+	totalItems int
+	current    int
+	err        error
+}
+
+func (it *mockResourceHashIterator) TotalItems() (int, error) {
+	return it.totalItems, nil
+}
+
+func (it *mockResourceHashIterator) Next() (string, error) {
+	if it.err != nil {
+		return "", it.err
+	}
+
+	if it.current >= len(it.hashes) {
+		return "", cas.ErrDataNotFound
+	}
+
+	rh := it.hashes[it.current]
+	it.current++
+
+	return rh, nil
+}
+
+func (it *mockResourceHashIterator) Close() error {
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	embedParam = "embed"
+
+	objectProperty       = "object"
+	itemsProperty        = "items"
+	orderedItemsProperty = "orderedItems"
+)
+
+type anchorResolver interface {
+	Resolve(webCASURL *url.URL, hl string, data []byte) ([]byte, string, error)
+}
+
+// isEmbedding returns true if the client requested that anchor events be embedded in the response
+// (using the 'embed=true' query parameter) and an anchor resolver is configured.
+func (h *handler) isEmbedding(req *http.Request) bool {
+	return h.AnchorResolver != nil && h.MaxEmbeddedAnchorSize > 0 && h.paramAsBool(req, embedParam)
+}
+
+// embedAnchors returns a copy of the given activity in which each anchor event that only references
+// its anchor linkset (by URL) includes the anchor linkset content, resolved from CAS. The given activity
+// is not modified. Anchor linksets that can't be resolved or that exceed the maximum embedded size are
+// left as references.
+func (h *handler) embedAnchors(activity *vocab.ActivityType) (*vocab.ActivityType, error) {
+	activityDoc, err := vocab.MarshalToDoc(activity)
+	if err != nil {
+		return nil, fmt.Errorf("marshal activity: %w", err)
+	}
+
+	obj, ok := activityDoc[objectProperty].(map[string]interface{})
+	if !ok {
+		return activity, nil
+	}
+
+	if !h.embedAnchor(obj) {
+		// The object may be a collection of anchor events (e.g. an 'Announce' activity).
+		for _, itemsProp := range []string{itemsProperty, orderedItemsProperty} {
+			items, ok := obj[itemsProp].([]interface{})
+			if !ok {
+				continue
+			}
+
+			for _, item := range items {
+				if itemObj, ok := item.(map[string]interface{}); ok {
+					h.embedAnchor(itemObj)
+				}
+			}
+		}
+	}
+
+	activityBytes, err := json.Marshal(activityDoc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal activity with embedded anchors: %w", err)
+	}
+
+	embedded := &vocab.ActivityType{}
+
+	err = json.Unmarshal(activityBytes, embedded)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal activity with embedded anchors: %w", err)
+	}
+
+	return embedded, nil
+}
+
+// embedAnchor embeds the anchor linkset into the given object if the object is an anchor event without
+// an embedded object. Returns true if the given object is an anchor event.
+func (h *handler) embedAnchor(obj map[string]interface{}) bool {
+	anchorEvent, err := toAnchorEvent(obj)
+	if err != nil || anchorEvent == nil {
+		return false
+	}
+
+	if anchorEvent.Object() != nil || len(anchorEvent.URL()) == 0 {
+		// Either the anchor linkset is already embedded or there's nothing to resolve.
+		return true
+	}
+
+	hl := anchorEvent.URL()[0].String()
+
+	anchorBytes, _, err := h.AnchorResolver.Resolve(nil, hl, nil)
+	if err != nil {
+		h.logger.Warn("Unable to resolve anchor for embedding. The anchor will be returned as a reference.",
+			logfields.WithHashlink(hl), log.WithError(err))
+
+		return true
+	}
+
+	if len(anchorBytes) > h.MaxEmbeddedAnchorSize {
+		h.logger.Debug("Anchor exceeds the maximum embedded size. The anchor will be returned as a reference.",
+			logfields.WithHashlink(hl), logfields.WithSize(len(anchorBytes)),
+			logfields.WithMaxSize(h.MaxEmbeddedAnchorSize))
+
+		return true
+	}
+
+	anchorDoc, err := vocab.UnmarshalToDoc(anchorBytes)
+	if err != nil {
+		h.logger.Warn("Invalid anchor content. The anchor will be returned as a reference.",
+			logfields.WithHashlink(hl), log.WithError(err))
+
+		return true
+	}
+
+	obj[objectProperty] = anchorDoc
+
+	return true
+}
+
+func toAnchorEvent(obj map[string]interface{}) (*vocab.AnchorEventType, error) {
+	objBytes, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	objProp := &vocab.ObjectProperty{}
+
+	err = json.Unmarshal(objBytes, objProp)
+	if err != nil {
+		return nil, err
+	}
+
+	return objProp.AnchorEvent(), nil
+}
@@ -0,0 +1,289 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const (
+	embedHL1 = "hl:uEiAr_xUtbeoALO4iKvN5eIWjqUmIO35wFEPTTzjOaSYgUA"
+	embedHL2 = "hl:uEiA1V3OBfZryXqZXPkKSFpJ09RU7gTAuHCj8uFjEiG73OA"
+	embedHL3 = "hl:uEiALYp_C4wk2WegpfnCSoSTBdKZ1MVdDadn4rdmZl5GKzQ"
+
+	anchorLinksetJSON = `{"linkset":[{"anchor":"hl:uEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA"}]}`
+)
+
+func TestReadOutbox_Embed(t *testing.T) {
+	activityStore := memstore.New("")
+
+	create := newMockEmbedActivity(vocab.TypeCreate, "create", embedHL1)
+	like := newMockEmbedActivity(vocab.TypeLike, "like", embedHL2)
+	announce := newMockEmbedActivity(vocab.TypeAnnounce, "announce", embedHL1, embedHL3)
+
+	for _, activity := range []*vocab.ActivityType{create, like, announce} {
+		require.NoError(t, activityStore.AddActivity(activity))
+		require.NoError(t, activityStore.AddReference(spi.Outbox, serviceIRI, activity.ID().URL()))
+	}
+
+	resolver := newMockAnchorResolver().
+		withContent(embedHL1, anchorLinksetJSON).
+		withContent(embedHL2, anchorLinksetJSON)
+
+	cfg := &Config{
+		BasePath:              basePath,
+		ObjectIRI:             serviceIRI,
+		ServiceEndpointURL:    serviceIRI,
+		PageSize:              10,
+		AnchorResolver:        resolver,
+		MaxEmbeddedAnchorSize: 1000,
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+	verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+	t.Run("Embedded", func(t *testing.T) {
+		h := NewOutbox(cfg, activityStore, verifier, spi.SortAscending, &apmocks.AuthTokenMgr{})
+
+		page := getOutboxPage(t, h, outboxURL+"?page=true&embed=true")
+
+		items := page.Items()
+		require.Len(t, items, 3)
+
+		requireEmbeddedAnchor(t, items[0].Activity().Object())
+		requireEmbeddedAnchor(t, items[1].Activity().Object())
+
+		announceItems := items[2].Activity().Object().Collection().Items()
+		require.Len(t, announceItems, 2)
+
+		requireEmbeddedAnchor(t, announceItems[0])
+
+		// The second anchor in the 'Announce' couldn't be resolved so it's returned as a reference.
+		requireAnchorReference(t, announceItems[1], embedHL3)
+
+		// The activities in the store should not have been modified.
+		stored, err := activityStore.GetActivity(create.ID().URL())
+		require.NoError(t, err)
+		requireAnchorReference(t, stored.Object(), embedHL1)
+	})
+
+	t.Run("Not embedded", func(t *testing.T) {
+		h := NewOutbox(cfg, activityStore, verifier, spi.SortAscending, &apmocks.AuthTokenMgr{})
+
+		for _, u := range []string{outboxURL + "?page=true", outboxURL + "?page=true&embed=false"} {
+			page := getOutboxPage(t, h, u)
+
+			items := page.Items()
+			require.Len(t, items, 3)
+
+			requireAnchorReference(t, items[0].Activity().Object(), embedHL1)
+			requireAnchorReference(t, items[1].Activity().Object(), embedHL2)
+		}
+	})
+
+	t.Run("Anchor too large -> reference", func(t *testing.T) {
+		cfg := *cfg
+		cfg.MaxEmbeddedAnchorSize = len(anchorLinksetJSON) - 1
+
+		h := NewOutbox(&cfg, activityStore, verifier, spi.SortAscending, &apmocks.AuthTokenMgr{})
+
+		page := getOutboxPage(t, h, outboxURL+"?page=true&embed=true")
+
+		items := page.Items()
+		require.Len(t, items, 3)
+
+		requireAnchorReference(t, items[0].Activity().Object(), embedHL1)
+	})
+
+	t.Run("No anchor resolver -> reference", func(t *testing.T) {
+		cfg := *cfg
+		cfg.AnchorResolver = nil
+
+		h := NewOutbox(&cfg, activityStore, verifier, spi.SortAscending, &apmocks.AuthTokenMgr{})
+
+		page := getOutboxPage(t, h, outboxURL+"?page=true&embed=true")
+
+		items := page.Items()
+		require.Len(t, items, 3)
+
+		requireAnchorReference(t, items[0].Activity().Object(), embedHL1)
+	})
+
+	t.Run("Invalid anchor content -> reference", func(t *testing.T) {
+		cfg := *cfg
+		cfg.AnchorResolver = newMockAnchorResolver().withContent(embedHL1, "{invalid")
+
+		h := NewOutbox(&cfg, activityStore, verifier, spi.SortAscending, &apmocks.AuthTokenMgr{})
+
+		page := getOutboxPage(t, h, outboxURL+"?page=true&embed=true")
+
+		items := page.Items()
+		require.Len(t, items, 3)
+
+		requireAnchorReference(t, items[0].Activity().Object(), embedHL1)
+	})
+}
+
+func TestActivity_Embed(t *testing.T) {
+	const id = "abd35f29-032f-4e22-8f52-df00365323bc"
+
+	activityStore := memstore.New("")
+
+	require.NoError(t, activityStore.AddActivity(newMockEmbedActivity(vocab.TypeCreate, id, embedHL1)))
+
+	cfg := &Config{
+		ObjectIRI:             serviceIRI,
+		ServiceEndpointURL:    serviceIRI,
+		BasePath:              basePath,
+		AnchorResolver:        newMockAnchorResolver().withContent(embedHL1, anchorLinksetJSON),
+		MaxEmbeddedAnchorSize: 1000,
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+	verifier.VerifyRequestReturns(true, nil, nil)
+
+	restoreID := setIDParam(id)
+	defer restoreID()
+
+	h := NewActivity(cfg, activityStore, verifier, spi.SortDescending, &apmocks.AuthTokenMgr{})
+
+	t.Run("Embedded", func(t *testing.T) {
+		activity := getActivity(t, h, serviceIRI.String()+"?embed=true")
+
+		requireEmbeddedAnchor(t, activity.Object())
+	})
+
+	t.Run("Not embedded", func(t *testing.T) {
+		activity := getActivity(t, h, serviceIRI.String())
+
+		requireAnchorReference(t, activity.Object(), embedHL1)
+	})
+}
+
+func getOutboxPage(t *testing.T, h *ReadOutbox, u string) *vocab.OrderedCollectionPageType {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handleOutbox(rw, httptest.NewRequest(http.MethodGet, u, http.NoBody))
+
+	page := &vocab.OrderedCollectionPageType{}
+	require.NoError(t, json.Unmarshal(readResponse(t, rw), page))
+
+	return page
+}
+
+func getActivity(t *testing.T, h *Activity, u string) *vocab.ActivityType {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handle(rw, httptest.NewRequest(http.MethodGet, u, http.NoBody))
+
+	activity := &vocab.ActivityType{}
+	require.NoError(t, json.Unmarshal(readResponse(t, rw), activity))
+
+	return activity
+}
+
+func readResponse(t *testing.T, rw *httptest.ResponseRecorder) []byte {
+	t.Helper()
+
+	result := rw.Result()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	return respBytes
+}
+
+func requireEmbeddedAnchor(t *testing.T, obj *vocab.ObjectProperty) {
+	t.Helper()
+
+	anchorEvent := obj.AnchorEvent()
+	require.NotNil(t, anchorEvent)
+	require.NotNil(t, anchorEvent.Object())
+
+	docBytes, err := json.Marshal(anchorEvent.Object().Document())
+	require.NoError(t, err)
+	require.Equal(t, testutil.GetCanonical(t, anchorLinksetJSON), testutil.GetCanonical(t, string(docBytes)))
+}
+
+func requireAnchorReference(t *testing.T, obj *vocab.ObjectProperty, hl string) {
+	t.Helper()
+
+	anchorEvent := obj.AnchorEvent()
+	require.NotNil(t, anchorEvent)
+	require.Nil(t, anchorEvent.Object())
+	require.Len(t, anchorEvent.URL(), 1)
+	require.Equal(t, hl, anchorEvent.URL()[0].String())
+}
+
+func newMockEmbedActivity(t vocab.Type, id string, hashlinks ...string) *vocab.ActivityType {
+	activityID := testutil.NewMockID(serviceIRI, fmt.Sprintf("/activities/%s", id))
+
+	anchorEvents := make([]*vocab.ObjectProperty, len(hashlinks))
+
+	for i, hl := range hashlinks {
+		anchorEvents[i] = vocab.NewObjectProperty(vocab.WithAnchorEvent(
+			vocab.NewAnchorEvent(nil, vocab.WithURL(testutil.MustParseURL(hl))),
+		))
+	}
+
+	switch t {
+	case vocab.TypeAnnounce:
+		return vocab.NewAnnounceActivity(
+			vocab.NewObjectProperty(vocab.WithCollection(vocab.NewCollection(anchorEvents))),
+			vocab.WithID(activityID),
+		)
+	case vocab.TypeLike:
+		return vocab.NewLikeActivity(anchorEvents[0], vocab.WithID(activityID))
+	default:
+		return vocab.NewCreateActivity(anchorEvents[0], vocab.WithID(activityID))
+	}
+}
+
+type mockAnchorResolver struct {
+	content map[string][]byte
+}
+
+func newMockAnchorResolver() *mockAnchorResolver {
+	return &mockAnchorResolver{content: make(map[string][]byte)}
+}
+
+func (m *mockAnchorResolver) withContent(hl, content string) *mockAnchorResolver {
+	m.content[hl] = []byte(content)
+
+	return m
+}
+
+func (m *mockAnchorResolver) Resolve(_ *url.URL, hl string, _ []byte) ([]byte, string, error) {
+	content, ok := m.content[hl]
+	if !ok {
+		return nil, "", errors.New("not found")
+	}
+
+	return content, hl, nil
+}
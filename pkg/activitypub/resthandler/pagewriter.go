@@ -0,0 +1,255 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	// maxPageSize is the maximum number of items in a collection page, regardless of the configured page size.
+	maxPageSize = 500
+
+	itemsKey        = "items"
+	orderedItemsKey = "orderedItems"
+)
+
+// collectionPage holds a collection page (without items) along with the items of the page. The items are
+// marshalled and written to the response one at a time, separately from the rest of the page, so that
+// the entire page doesn't need to be marshalled into a single buffer (see writePage).
+type collectionPage struct {
+	page     interface{}
+	itemsKey string
+	items    []*vocab.ObjectProperty
+}
+
+type createCollectionPageFunc func(items []*vocab.ObjectProperty, opts ...vocab.Opt) *collectionPage
+
+func newOrderedCollectionPage(items []*vocab.ObjectProperty, opts ...vocab.Opt) *collectionPage {
+	return &collectionPage{
+		page:     vocab.NewOrderedCollectionPage(nil, withTotalItems(items, opts)...),
+		itemsKey: orderedItemsKey,
+		items:    items,
+	}
+}
+
+func newUnorderedCollectionPage(items []*vocab.ObjectProperty, opts ...vocab.Opt) *collectionPage {
+	return &collectionPage{
+		page:     vocab.NewCollectionPage(nil, withTotalItems(items, opts)...),
+		itemsKey: itemsKey,
+		items:    items,
+	}
+}
+
+// withTotalItems ensures that the total number of items defaults to the number of items in the page, as it
+// would if the page were created with its items.
+func withTotalItems(items []*vocab.ObjectProperty, opts []vocab.Opt) []vocab.Opt {
+	if vocab.NewOptions(opts...).TotalItems != 0 {
+		return opts
+	}
+
+	return append(opts, vocab.WithTotalItems(len(items)))
+}
+
+// marshalItem marshals an item of a collection page so that it may be written directly to the response. The
+// output is identical to that of the item when the entire page is marshalled, i.e. HTML characters aren't escaped.
+func (h *handler) marshalItem(item *vocab.ObjectProperty) (json.RawMessage, error) {
+	itemBytes, err := h.marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("marshal item: %w", err)
+	}
+
+	return unescapeHTML(itemBytes), nil
+}
+
+// unescapeHTML replaces the escape sequences of the HTML characters (&, < and >) within the strings of the
+// given JSON with the characters themselves. (The value of an item is escaped when it's marshalled on its own
+// since the nested objects are marshalled with json.Marshal.)
+func unescapeHTML(raw []byte) []byte {
+	if !bytes.Contains(raw, []byte(`\u00`)) {
+		return raw
+	}
+
+	const escapeLen = 6 // Length of \u00XX
+
+	out := make([]byte, 0, len(raw))
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' || i+1 >= len(raw) {
+			out = append(out, raw[i])
+
+			continue
+		}
+
+		if i+escapeLen <= len(raw) {
+			if c, ok := htmlEscapes[string(raw[i:i+escapeLen])]; ok {
+				out = append(out, c)
+				i += escapeLen - 1
+
+				continue
+			}
+		}
+
+		// Copy the escape sequence (e.g. \\ or \") as is so that an escaped backslash isn't
+		// mistaken for the start of an escape sequence.
+		out = append(out, raw[i], raw[i+1])
+		i++
+	}
+
+	return out
+}
+
+var htmlEscapes = map[string]byte{
+	`\u0026`: '&',
+	`\u003c`: '<',
+	`\u003e`: '>',
+}
+
+// writePage writes the given collection page to the response. Each item is marshalled and written directly
+// to the response one at a time. The output is identical to that of marshalling the entire page. An error is
+// returned only if the page can't be marshalled before the response is started. Errors encountered while
+// marshalling or writing the items are logged since the status code has already been written at that point.
+func (h *handler) writePage(w http.ResponseWriter, p *collectionPage) error {
+	pageBytes, err := h.marshal(p.page)
+	if err != nil {
+		return fmt.Errorf("marshal page: %w", err)
+	}
+
+	if len(p.items) == 0 {
+		h.writeResponse(w, http.StatusOK, pageBytes)
+
+		return nil
+	}
+
+	doc := make(map[string]json.RawMessage)
+
+	err = json.Unmarshal(pageBytes, &doc)
+	if err != nil {
+		return fmt.Errorf("unmarshal page: %w", err)
+	}
+
+	keys := make([]string, 0, len(doc)+1)
+
+	for key := range doc {
+		keys = append(keys, key)
+	}
+
+	keys = append(keys, p.itemsKey)
+
+	sort.Strings(keys)
+
+	w.WriteHeader(http.StatusOK)
+
+	pw := &pageWriter{w: w}
+
+	pw.writeDelim('{')
+
+	for i, key := range keys {
+		if i > 0 {
+			pw.writeDelim(',')
+		}
+
+		pw.writeKey(key)
+
+		if key == p.itemsKey {
+			pw.writeItems(p.items, h.marshalItem)
+		} else {
+			pw.write(doc[key])
+		}
+	}
+
+	pw.writeDelim('}')
+
+	if pw.err != nil {
+		h.logger.Warn("Unable to write collection page", log.WithError(pw.err))
+
+		return nil
+	}
+
+	h.logger.Debug("Wrote collection page", logfields.WithTotal(len(p.items)))
+
+	return nil
+}
+
+// pageWriter writes the JSON tokens of a collection page. Once an error occurs, all subsequent writes are no-ops.
+type pageWriter struct {
+	w   http.ResponseWriter
+	buf bytes.Buffer
+	err error
+}
+
+func (pw *pageWriter) writeItems(items []*vocab.ObjectProperty,
+	marshalItem func(item *vocab.ObjectProperty) (json.RawMessage, error),
+) {
+	pw.writeDelim('[')
+
+	for i, item := range items {
+		if pw.err != nil {
+			return
+		}
+
+		if i > 0 {
+			pw.writeDelim(',')
+		}
+
+		itemBytes, err := marshalItem(item)
+		if err != nil {
+			pw.err = err
+
+			return
+		}
+
+		pw.write(itemBytes)
+	}
+
+	pw.writeDelim(']')
+}
+
+// writeKey writes the given object key (followed by a colon) without escaping HTML characters.
+func (pw *pageWriter) writeKey(key string) {
+	if pw.err != nil {
+		return
+	}
+
+	pw.buf.Reset()
+
+	encoder := json.NewEncoder(&pw.buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(key); err != nil {
+		pw.err = fmt.Errorf("encode key: %w", err)
+
+		return
+	}
+
+	// Remove the newline which is added by the encoder.
+	pw.write(bytes.TrimSuffix(pw.buf.Bytes(), []byte("\n")))
+	pw.writeDelim(':')
+}
+
+func (pw *pageWriter) writeDelim(delim byte) {
+	pw.write([]byte{delim})
+}
+
+func (pw *pageWriter) write(b []byte) {
+	if pw.err != nil {
+		return
+	}
+
+	if _, err := pw.w.Write(b); err != nil {
+		pw.err = fmt.Errorf("write response: %w", err)
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestWritePage(t *testing.T) {
+	h := newTestPageHandler(t)
+
+	opts := []vocab.Opt{
+		vocab.WithContext(vocab.ContextActivityStreams),
+		vocab.WithID(testutil.MustParseURL("https://example1.com/services/orb/outbox?page=true&page-num=1")),
+		vocab.WithPrev(testutil.MustParseURL("https://example1.com/services/orb/outbox?page=true&page-num=2")),
+		vocab.WithNext(testutil.MustParseURL("https://example1.com/services/orb/outbox?page=true&page-num=0")),
+		vocab.WithTotalItems(100),
+	}
+
+	t.Run("Ordered page of activities -> identical to buffered output", func(t *testing.T) {
+		items := newActivityItems(vocab.TypeLike, 5)
+
+		requireIdenticalOutput(t, h, vocab.NewOrderedCollectionPage(items, opts...),
+			newOrderedCollectionPage(items, opts...))
+	})
+
+	t.Run("Unordered page of IRIs -> identical to buffered output", func(t *testing.T) {
+		items := []*vocab.ObjectProperty{
+			vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example1.com/services/orb?a=1&b=<2>"))),
+			vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example2.com/services/orb"))),
+		}
+
+		requireIdenticalOutput(t, h, vocab.NewCollectionPage(items, opts...),
+			newUnorderedCollectionPage(items, opts...))
+	})
+
+	t.Run("Default total items -> identical to buffered output", func(t *testing.T) {
+		items := newActivityItems(vocab.TypeCreate, 3)
+
+		requireIdenticalOutput(t, h,
+			vocab.NewOrderedCollectionPage(items, vocab.WithContext(vocab.ContextActivityStreams)),
+			newOrderedCollectionPage(items, vocab.WithContext(vocab.ContextActivityStreams)))
+	})
+
+	t.Run("Empty page -> identical to buffered output", func(t *testing.T) {
+		requireIdenticalOutput(t, h, vocab.NewOrderedCollectionPage(nil, opts...),
+			newOrderedCollectionPage(nil, opts...))
+	})
+
+	t.Run("Marshal page error", func(t *testing.T) {
+		h := newTestPageHandler(t)
+
+		errExpected := errors.New("injected marshal error")
+
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errExpected }
+
+		rw := httptest.NewRecorder()
+
+		err := h.writePage(rw, newOrderedCollectionPage(newActivityItems(vocab.TypeCreate, 2), opts...))
+		require.ErrorIs(t, err, errExpected)
+		require.Zero(t, rw.Body.Len())
+	})
+
+	t.Run("Marshal item error", func(t *testing.T) {
+		h := newTestPageHandler(t)
+
+		errExpected := errors.New("injected marshal error")
+
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errExpected }
+
+		_, err := h.marshalItem(newActivityItems(vocab.TypeCreate, 1)[0])
+		require.ErrorIs(t, err, errExpected)
+		require.Contains(t, err.Error(), "marshal item")
+	})
+
+	t.Run("Marshal item error -> response is truncated", func(t *testing.T) {
+		h := newTestPageHandler(t)
+
+		errExpected := errors.New("injected marshal error")
+
+		items := newActivityItems(vocab.TypeCreate, 3)
+
+		h.marshal = func(v interface{}) ([]byte, error) {
+			if v == items[1] {
+				return nil, errExpected
+			}
+
+			return vocab.Marshal(v)
+		}
+
+		rw := httptest.NewRecorder()
+
+		require.NoError(t, h.writePage(rw, newOrderedCollectionPage(items, opts...)))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Contains(t, rw.Body.String(), `"orderedItems":[{`)
+		require.Equal(t, 1, strings.Count(rw.Body.String(), `"type":"Create"`))
+		require.False(t, strings.HasSuffix(rw.Body.String(), "}"))
+	})
+
+	t.Run("Write error -> response is truncated", func(t *testing.T) {
+		rw := &failingResponseWriter{ResponseRecorder: httptest.NewRecorder(), maxWrites: 20}
+
+		require.NoError(t, h.writePage(rw,
+			newOrderedCollectionPage(newActivityItems(vocab.TypeCreate, 5), opts...)))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Contains(t, rw.Body.String(), `"orderedItems":[`)
+		require.False(t, strings.HasSuffix(rw.Body.String(), "}"))
+	})
+}
+
+func TestUnescapeHTML(t *testing.T) {
+	require.Equal(t, `{"a":"x&y<z>"}`, string(unescapeHTML([]byte(`{"a":"x\u0026y\u003cz\u003e"}`))))
+	require.Equal(t, `{"a":"\\u0026"}`, string(unescapeHTML([]byte(`{"a":"\\u0026"}`))))
+	require.Equal(t, `{"a":"\"\u2028"}`, string(unescapeHTML([]byte(`{"a":"\"\u2028"}`))))
+	require.Equal(t, `{"a":"b"}`, string(unescapeHTML([]byte(`{"a":"b"}`))))
+}
+
+func TestHandler_PageSize(t *testing.T) {
+	h := &handler{Config: &Config{PageSize: 4}}
+	require.Equal(t, 4, h.pageSize())
+
+	h = &handler{Config: &Config{PageSize: maxPageSize + 1}}
+	require.Equal(t, maxPageSize, h.pageSize())
+}
+
+func TestActivities_MaxPageSize(t *testing.T) {
+	activityStore := memstore.New("")
+
+	for _, activity := range newMockCreateActivities(maxPageSize + 10) {
+		require.NoError(t, activityStore.AddActivity(activity))
+		require.NoError(t, activityStore.AddReference(spi.Outbox, serviceIRI, activity.ID().URL()))
+	}
+
+	cfg := &Config{
+		ObjectIRI:          serviceIRI,
+		ServiceEndpointURL: serviceIRI,
+		PageSize:           maxPageSize * 2,
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+	verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+	h := NewOutbox(cfg, activityStore, verifier, spi.SortAscending, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h)
+
+	restorePaging := setPaging(h.handler, "true", "0")
+	defer restorePaging()
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, outboxURL, http.NoBody)
+
+	h.handle(rw, req)
+
+	result := rw.Result()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	page := &vocab.OrderedCollectionPageType{}
+	require.NoError(t, vocab.UnmarshalJSON(respBytes, page))
+	require.Len(t, page.Items(), maxPageSize)
+	require.NotNil(t, page.Next())
+}
+
+func requireIdenticalOutput(t *testing.T, h *handler, fullPage interface{}, page *collectionPage) {
+	t.Helper()
+
+	expected, err := vocab.Marshal(fullPage)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+
+	require.NoError(t, h.writePage(rw, page))
+
+	result := rw.Result()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	require.Equal(t, string(expected), string(respBytes))
+}
+
+// failingResponseWriter fails all writes after the given number of writes.
+type failingResponseWriter struct {
+	*httptest.ResponseRecorder
+
+	maxWrites int
+	writes    int
+}
+
+func (w *failingResponseWriter) Write(b []byte) (int, error) {
+	w.writes++
+
+	if w.writes > w.maxWrites {
+		return 0, errors.New("injected write error")
+	}
+
+	return w.ResponseRecorder.Write(b)
+}
+
+func newTestPageHandler(t *testing.T) *handler {
+	t.Helper()
+
+	h := NewOutbox(&Config{ObjectIRI: serviceIRI, ServiceEndpointURL: serviceIRI, PageSize: 4},
+		memstore.New(""), &mocks.SignatureVerifier{}, spi.SortAscending, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h)
+
+	return h.handler
+}
+
+func newActivityItems(activityType vocab.Type, num int) []*vocab.ObjectProperty {
+	activities := newMockActivities(activityType, num, func(i int) string {
+		return fmt.Sprintf("https://example1.com/activities/activity_%d?a=%d&b=<%d>", i, i, i)
+	})
+
+	items := make([]*vocab.ObjectProperty, len(activities))
+
+	for i, activity := range activities {
+		items[i] = vocab.NewObjectProperty(vocab.WithActivity(activity))
+	}
+
+	return items
+}
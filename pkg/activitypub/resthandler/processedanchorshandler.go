@@ -0,0 +1,294 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/store/anchorlog"
+)
+
+const (
+	sinceParam = "since"
+	untilParam = "until"
+)
+
+type processedAnchorLog interface {
+	GetEntriesInRange(from, until time.Time, opts ...storage.QueryOption) (anchorlog.EntryIterator, error)
+}
+
+// ProcessedAnchors implements a REST handler that exposes the log of anchors processed by the observer as an
+// ordered collection of anchor events (in the order in which they were processed). The 'since' and 'until'
+// query parameters (RFC3339 format) may be used to restrict the collection to the anchors processed within
+// the given time range.
+type ProcessedAnchors struct {
+	*handler
+
+	anchorLog processedAnchorLog
+}
+
+// NewProcessedAnchors returns a new 'processed-anchors' REST handler.
+func NewProcessedAnchors(cfg *Config, activityStore spi.Store, anchorLog processedAnchorLog,
+	verifier signatureVerifier, tm authTokenManager,
+) *ProcessedAnchors {
+	h := &ProcessedAnchors{
+		anchorLog: anchorLog,
+	}
+
+	h.handler = newHandler(ProcessedAnchorsPath, cfg, activityStore, h.handle, verifier, spi.SortAscending, tm)
+
+	return h
+}
+
+func (h *ProcessedAnchors) handle(w http.ResponseWriter, req *http.Request) {
+	ok, _, err := h.Authorize(req)
+	if err != nil {
+		h.logger.Error("Error authorizing request", log.WithError(err))
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if !ok {
+		h.writeResponse(w, http.StatusUnauthorized, []byte(unauthorizedResponse))
+
+		return
+	}
+
+	since, until, err := getTimeRange(req)
+	if err != nil {
+		h.logger.Debug("Invalid time range", logfields.WithRequestURL(req.URL), log.WithError(err))
+
+		h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	id, err := h.getID(since, until)
+	if err != nil {
+		h.logger.Error("Error generating ID", log.WithError(err))
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	var coll interface{}
+
+	if h.isPaging(req) {
+		pageNum, ok := h.getPageNum(req)
+		if !ok {
+			pageNum = -1
+		}
+
+		coll, err = h.getPage(id, since, until, pageNum)
+	} else {
+		coll, err = h.getCollection(id, since, until)
+	}
+
+	if err != nil {
+		h.logger.Error("Error retrieving processed anchors", log.WithError(err))
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	collBytes, err := h.marshal(coll)
+	if err != nil {
+		h.logger.Error("Unable to marshal processed anchors", log.WithError(err))
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, collBytes)
+}
+
+func (h *ProcessedAnchors) getCollection(id *url.URL, since, until time.Time) (*vocab.OrderedCollectionType, error) {
+	it, err := h.anchorLog.GetEntriesInRange(since, until, storage.WithPageSize(h.pageSize()))
+	if err != nil {
+		return nil, fmt.Errorf("query processed anchors: %w", err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			log.CloseIteratorError(h.logger, e)
+		}
+	}()
+
+	totalItems, err := it.TotalItems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total items from processed anchors query: %w", err)
+	}
+
+	firstURL, err := h.getPageURL(id, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.pageSize(), h.sortOrder))
+	if err != nil {
+		return nil, err
+	}
+
+	return vocab.NewOrderedCollection(nil,
+		vocab.WithContext(vocab.ContextActivityStreams),
+		vocab.WithID(id),
+		vocab.WithFirst(firstURL),
+		vocab.WithLast(lastURL),
+		vocab.WithTotalItems(totalItems),
+	), nil
+}
+
+func (h *ProcessedAnchors) getPage(id *url.URL, since, until time.Time,
+	pageNum int,
+) (*vocab.OrderedCollectionPageType, error) {
+	options := &spi.QueryOptions{
+		PageSize:   h.pageSize(),
+		PageNumber: pageNum,
+		SortOrder:  h.sortOrder,
+	}
+
+	queryOpts := []storage.QueryOption{storage.WithPageSize(h.pageSize())}
+
+	if pageNum > 0 {
+		queryOpts = append(queryOpts, storage.WithInitialPageNum(pageNum))
+	}
+
+	it, err := h.anchorLog.GetEntriesInRange(since, until, queryOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("query processed anchors: %w", err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			log.CloseIteratorError(h.logger, e)
+		}
+	}()
+
+	items, err := readProcessedAnchors(it, h.pageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	totalItems, err := it.TotalItems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total items from processed anchors query: %w", err)
+	}
+
+	pageID, prev, next, err := h.getIDPrevNextURL(id, totalItems, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return vocab.NewOrderedCollectionPage(items,
+		vocab.WithContext(vocab.ContextActivityStreams),
+		vocab.WithID(pageID),
+		vocab.WithPrev(prev),
+		vocab.WithNext(next),
+		vocab.WithTotalItems(totalItems),
+	), nil
+}
+
+// getID returns the ID of the collection, which includes the time range (if any) so that the
+// 'first', 'last', 'prev' and 'next' URLs preserve the filter.
+func (h *ProcessedAnchors) getID(since, until time.Time) (*url.URL, error) {
+	id := fmt.Sprintf("%s%s", h.ServiceEndpointURL, ProcessedAnchorsPath)
+
+	query := url.Values{}
+
+	if !since.IsZero() {
+		query.Set(sinceParam, since.UTC().Format(time.RFC3339Nano))
+	}
+
+	if !until.IsZero() {
+		query.Set(untilParam, until.UTC().Format(time.RFC3339Nano))
+	}
+
+	if len(query) > 0 {
+		id = fmt.Sprintf("%s?%s", id, query.Encode())
+	}
+
+	return url.Parse(id)
+}
+
+func readProcessedAnchors(it anchorlog.EntryIterator, maxItems int) ([]*vocab.ObjectProperty, error) {
+	var items []*vocab.ObjectProperty
+
+	for maxItems <= 0 || len(items) < maxItems {
+		entry, err := it.Next()
+		if err != nil {
+			if errors.Is(err, anchorlog.ErrDataNotFound) {
+				break
+			}
+
+			return nil, fmt.Errorf("read processed anchor: %w", err)
+		}
+
+		hl, err := url.Parse(entry.Hashlink)
+		if err != nil {
+			return nil, fmt.Errorf("parse hashlink [%s]: %w", entry.Hashlink, err)
+		}
+
+		processedTime := entry.Time().UTC()
+
+		items = append(items, vocab.NewObjectProperty(vocab.WithObject(
+			vocab.NewObject(
+				vocab.WithType(vocab.TypeAnchorEvent),
+				vocab.WithURL(hl),
+				vocab.WithPublishedTime(&processedTime),
+			),
+		)))
+	}
+
+	return items, nil
+}
+
+func getTimeRange(req *http.Request) (time.Time, time.Time, error) {
+	since, err := getTimeParam(req, sinceParam)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	until, err := getTimeParam(req, untilParam)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return time.Time{}, time.Time{}, fmt.Errorf("'%s' must not be before '%s'", untilParam, sinceParam)
+	}
+
+	return since, until, nil
+}
+
+func getTimeParam(req *http.Request, name string) (time.Time, error) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid '%s' parameter [%s]: %w", name, value, err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,342 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/store/anchorlog"
+)
+
+const processedAnchorsURL = "https://example1.com/services/orb/processed-anchors"
+
+func TestNewProcessedAnchors(t *testing.T) {
+	cfg := &Config{
+		BasePath:           basePath,
+		ObjectIRI:          serviceIRI,
+		ServiceEndpointURL: serviceIRI,
+		PageSize:           4,
+	}
+
+	h := NewProcessedAnchors(cfg, memstore.New(""), &mockAnchorLog{}, &mocks.SignatureVerifier{}, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h)
+	require.Equal(t, "/services/orb/processed-anchors", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+}
+
+func TestProcessedAnchors_Handler(t *testing.T) {
+	startTime := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	anchorLog := newMockAnchorLog(startTime, 10)
+
+	cfg := &Config{
+		BasePath:           basePath,
+		ObjectIRI:          serviceIRI,
+		ServiceEndpointURL: serviceIRI,
+		PageSize:           4,
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+	verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+	h := NewProcessedAnchors(cfg, memstore.New(""), anchorLog, verifier, &apmocks.AuthTokenMgr{})
+
+	t.Run("Collection", func(t *testing.T) {
+		coll := &vocab.OrderedCollectionType{}
+
+		handleProcessedAnchorsRequest(t, h, processedAnchorsURL, http.StatusOK, coll)
+
+		require.Equal(t, processedAnchorsURL, coll.ID().String())
+		require.Equal(t, 10, coll.TotalItems())
+		require.Equal(t, processedAnchorsURL+"?page=true", coll.First().String())
+		require.Equal(t, processedAnchorsURL+"?page=true&page-num=2", coll.Last().String())
+	})
+
+	t.Run("Pages follow log order", func(t *testing.T) {
+		var hashlinks, pageIDs []string
+
+		pageURL := processedAnchorsURL + "?page=true"
+
+		for pageURL != "" {
+			page := &vocab.OrderedCollectionPageType{}
+
+			handleProcessedAnchorsRequest(t, h, pageURL, http.StatusOK, page)
+
+			require.Equal(t, 10, page.TotalItems())
+			require.LessOrEqual(t, len(page.Items()), cfg.PageSize)
+
+			pageIDs = append(pageIDs, page.ID().String())
+
+			for _, item := range page.Items() {
+				require.NotNil(t, item.AnchorEvent())
+				require.Len(t, item.AnchorEvent().URL(), 1)
+
+				hashlinks = append(hashlinks, item.AnchorEvent().URL()[0].String())
+			}
+
+			pageURL = ""
+
+			if page.Next() != nil {
+				pageURL = page.Next().String()
+			}
+		}
+
+		require.Equal(t, anchorLog.hashlinks(anchorLog.entries), hashlinks)
+		require.Equal(t, []string{
+			processedAnchorsURL + "?page=true&page-num=0",
+			processedAnchorsURL + "?page=true&page-num=1",
+			processedAnchorsURL + "?page=true&page-num=2",
+		}, pageIDs)
+	})
+
+	t.Run("Page", func(t *testing.T) {
+		page := &vocab.OrderedCollectionPageType{}
+
+		handleProcessedAnchorsRequest(t, h, processedAnchorsURL+"?page=true&page-num=1", http.StatusOK, page)
+
+		require.Equal(t, processedAnchorsURL+"?page=true&page-num=0", page.Prev().String())
+		require.Equal(t, processedAnchorsURL+"?page=true&page-num=2", page.Next().String())
+		require.Len(t, page.Items(), 4)
+
+		item := page.Items()[0].AnchorEvent()
+		require.NotNil(t, item)
+		require.Equal(t, anchorLog.entries[4].Hashlink, item.URL()[0].String())
+		require.NotNil(t, item.Published())
+		require.True(t, anchorLog.entries[4].Time().Equal(*item.Published()))
+	})
+
+	t.Run("Since and until", func(t *testing.T) {
+		since := startTime.Add(2 * time.Minute)
+		until := startTime.Add(8 * time.Minute)
+
+		query := url.Values{}
+		query.Set(sinceParam, since.Format(time.RFC3339))
+		query.Set(untilParam, until.Format(time.RFC3339))
+
+		collURL := fmt.Sprintf("%s?%s", processedAnchorsURL, query.Encode())
+
+		coll := &vocab.OrderedCollectionType{}
+
+		handleProcessedAnchorsRequest(t, h, collURL, http.StatusOK, coll)
+
+		require.Equal(t, collURL, coll.ID().String())
+		require.Equal(t, 7, coll.TotalItems())
+		require.Equal(t, collURL+"&page=true", coll.First().String())
+		require.Equal(t, collURL+"&page=true&page-num=1", coll.Last().String())
+
+		var hashlinks []string
+
+		pageURL := coll.First().String()
+
+		for pageURL != "" {
+			page := &vocab.OrderedCollectionPageType{}
+
+			handleProcessedAnchorsRequest(t, h, pageURL, http.StatusOK, page)
+
+			require.Equal(t, 7, page.TotalItems())
+
+			for _, item := range page.Items() {
+				hashlinks = append(hashlinks, item.AnchorEvent().URL()[0].String())
+			}
+
+			pageURL = ""
+
+			if page.Next() != nil {
+				pageURL = page.Next().String()
+			}
+		}
+
+		require.Equal(t, anchorLog.hashlinks(anchorLog.entries[2:9]), hashlinks)
+	})
+
+	t.Run("Since only", func(t *testing.T) {
+		page := &vocab.OrderedCollectionPageType{}
+
+		handleProcessedAnchorsRequest(t, h,
+			processedAnchorsURL+"?page=true&since="+url.QueryEscape(startTime.Add(7*time.Minute).Format(time.RFC3339)),
+			http.StatusOK, page)
+
+		require.Equal(t, 3, page.TotalItems())
+		require.Nil(t, page.Next())
+		require.Nil(t, page.Prev())
+		require.Len(t, page.Items(), 3)
+		require.Equal(t, anchorLog.entries[7].Hashlink, page.Items()[0].AnchorEvent().URL()[0].String())
+	})
+
+	t.Run("Invalid time range", func(t *testing.T) {
+		handleProcessedAnchorsRequest(t, h, processedAnchorsURL+"?since=yesterday", http.StatusBadRequest, nil)
+		handleProcessedAnchorsRequest(t, h, processedAnchorsURL+"?until=tomorrow", http.StatusBadRequest, nil)
+		handleProcessedAnchorsRequest(t, h,
+			processedAnchorsURL+"?since=2023-06-02T00:00:00Z&until=2023-06-01T00:00:00Z", http.StatusBadRequest, nil)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"read"}, nil)
+
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, nil)
+
+		h := NewProcessedAnchors(cfg, memstore.New(""), anchorLog, v, tm)
+
+		handleProcessedAnchorsRequest(t, h, processedAnchorsURL, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("Authorization error", func(t *testing.T) {
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"read"}, nil)
+
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, errors.New("injected verifier error"))
+
+		h := NewProcessedAnchors(cfg, memstore.New(""), anchorLog, v, tm)
+
+		handleProcessedAnchorsRequest(t, h, processedAnchorsURL, http.StatusInternalServerError, nil)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		h := NewProcessedAnchors(cfg, memstore.New(""), &mockAnchorLog{err: errors.New("injected query error")},
+			verifier, &apmocks.AuthTokenMgr{})
+
+		handleProcessedAnchorsRequest(t, h, processedAnchorsURL, http.StatusInternalServerError, nil)
+		handleProcessedAnchorsRequest(t, h, processedAnchorsURL+"?page=true", http.StatusInternalServerError, nil)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewProcessedAnchors(cfg, memstore.New(""), anchorLog, verifier, &apmocks.AuthTokenMgr{})
+
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		handleProcessedAnchorsRequest(t, h, processedAnchorsURL, http.StatusInternalServerError, nil)
+	})
+}
+
+func handleProcessedAnchorsRequest(t *testing.T, h *ProcessedAnchors, reqURL string, expectedStatus int,
+	result interface{},
+) {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, reqURL, http.NoBody)
+
+	h.handle(rw, req)
+
+	resp := rw.Result()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, expectedStatus, resp.StatusCode, string(respBytes))
+
+	if result != nil {
+		require.NoError(t, json.Unmarshal(respBytes, result))
+	}
+}
+
+// mockAnchorLog is an in-memory processed-anchor log which supports time range queries and paging.
+type mockAnchorLog struct {
+	entries []*anchorlog.Entry
+	err     error
+}
+
+func newMockAnchorLog(startTime time.Time, num int) *mockAnchorLog {
+	l := &mockAnchorLog{}
+
+	for i := 0; i < num; i++ {
+		l.entries = append(l.entries, &anchorlog.Entry{
+			Hashlink:      fmt.Sprintf("hl:uEiA%d", i),
+			ProcessedTime: startTime.Add(time.Duration(i) * time.Minute).UnixMilli(),
+		})
+	}
+
+	return l
+}
+
+func (l *mockAnchorLog) GetEntriesInRange(from, until time.Time,
+	opts ...storage.QueryOption,
+) (anchorlog.EntryIterator, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	options := &storage.QueryOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var matching []*anchorlog.Entry
+
+	for _, e := range l.entries {
+		if e.Time().Before(from) || (!until.IsZero() && e.Time().After(until)) {
+			continue
+		}
+
+		matching = append(matching, e)
+	}
+
+	start := options.InitialPageNum * options.PageSize
+	if start > len(matching) {
+		start = len(matching)
+	}
+
+	return &mockEntryIterator{entries: matching[start:], totalItems: len(matching)}, nil
+}
+
+func (l *mockAnchorLog) hashlinks(entries []*anchorlog.Entry) []string {
+	hashlinks := make([]string, len(entries))
+
+	for i, e := range entries {
+		hashlinks[i] = e.Hashlink
+	}
+
+	return hashlinks
+}
+
+type mockEntryIterator struct {
+	entries    []*anchorlog.Entry
+	totalItems int
+	current    int
+}
+
+func (it *mockEntryIterator) TotalItems() (int, error) {
+	return it.totalItems, nil
+}
+
+func (it *mockEntryIterator) Next() (*anchorlog.Entry, error) {
+	if it.current >= len(it.entries) {
+		return nil, anchorlog.ErrDataNotFound
+	}
+
+	entry := it.entries[it.current]
+	it.current++
+
+	return entry, nil
+}
+
+func (it *mockEntryIterator) Close() error {
+	return nil
+}
@@ -46,8 +46,10 @@ func NewWitnessing(cfg *Config, activityStore spi.Store, verifier signatureVerif
 
 // NewLiked returns a new 'liked' REST handler that retrieves the references of all the anchor events that
 // this service liked.
-func NewLiked(cfg *Config, activityStore spi.Store, verifier signatureVerifier, tm authTokenManager) *Reference {
-	return NewReference(LikedPath, spi.Liked, spi.SortAscending, true, cfg, activityStore,
+func NewLiked(cfg *Config, activityStore spi.Store, verifier signatureVerifier,
+	sortOrder spi.SortOrder, tm authTokenManager,
+) *Reference {
+	return NewReference(LikedPath, spi.Liked, sortOrder, true, cfg, activityStore,
 		getID("liked"), verifier, tm)
 }
 
@@ -63,7 +65,7 @@ type Reference struct {
 
 	refType              spi.ReferenceType
 	createCollection     createCollectionFunc
-	createCollectionPage createCollectionFunc
+	createCollectionPage createCollectionPageFunc
 	getID                getIDFunc
 }
 
@@ -141,17 +143,17 @@ func (h *Reference) handleReference(w http.ResponseWriter, id *url.URL) {
 }
 
 func (h *Reference) handleReferencePage(w http.ResponseWriter, req *http.Request, id *url.URL) {
-	var page interface{}
+	var page *collectionPage
 
 	var err error
 
 	pageNum, ok := h.getPageNum(req)
 	if ok {
 		page, err = h.getPage(id,
-			spi.WithPageSize(h.PageSize), spi.WithPageNum(pageNum), spi.WithSortOrder(h.sortOrder))
+			spi.WithPageSize(h.pageSize()), spi.WithPageNum(pageNum), spi.WithSortOrder(h.sortOrder))
 	} else {
 		page, err = h.getPage(id,
-			spi.WithPageSize(h.PageSize), spi.WithSortOrder(h.sortOrder))
+			spi.WithPageSize(h.pageSize()), spi.WithSortOrder(h.sortOrder))
 	}
 
 	if err != nil {
@@ -162,16 +164,12 @@ func (h *Reference) handleReferencePage(w http.ResponseWriter, req *http.Request
 		return
 	}
 
-	pageBytes, err := h.marshal(page)
+	err = h.writePage(w, page)
 	if err != nil {
 		h.logger.Error("Unable to marshal page for object", logfields.WithObjectIRI(h.ObjectIRI), log.WithError(err))
 
 		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
-
-		return
 	}
-
-	h.writeResponse(w, http.StatusOK, pageBytes)
 }
 
 func (h *Reference) getReference(id *url.URL) (interface{}, error) {
@@ -201,7 +199,7 @@ func (h *Reference) getReference(id *url.URL) (interface{}, error) {
 		return nil, fmt.Errorf("failed to get total items from reference query: %w", err)
 	}
 
-	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.PageSize, h.sortOrder))
+	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.pageSize(), h.sortOrder))
 	if err != nil {
 		return nil, err
 	}
@@ -215,7 +213,7 @@ func (h *Reference) getReference(id *url.URL) (interface{}, error) {
 	), nil
 }
 
-func (h *Reference) getPage(id *url.URL, opts ...spi.QueryOpt) (interface{}, error) {
+func (h *Reference) getPage(id *url.URL, opts ...spi.QueryOpt) (*collectionPage, error) {
 	it, err := h.activityStore.QueryReferences(
 		h.refType,
 		spi.NewCriteria(spi.WithObjectIRI(h.ObjectIRI)),
@@ -276,14 +274,10 @@ func createCollection(ordered bool) createCollectionFunc {
 	}
 }
 
-func createCollectionPage(ordered bool) createCollectionFunc {
+func createCollectionPage(ordered bool) createCollectionPageFunc {
 	if ordered {
-		return func(items []*vocab.ObjectProperty, opts ...vocab.Opt) interface{} {
-			return vocab.NewOrderedCollectionPage(items, opts...)
-		}
+		return newOrderedCollectionPage
 	}
 
-	return func(items []*vocab.ObjectProperty, opts ...vocab.Opt) interface{} {
-		return vocab.NewCollectionPage(items, opts...)
-	}
+	return newUnorderedCollectionPage
 }
@@ -50,6 +50,12 @@ const (
 	ActivitiesPath = "/activities/{id}"
 	// AcceptListPath specifies the endpoint to manage an "accept list" for a service.
 	AcceptListPath = "/acceptlist"
+	// OutboxAtomPath specifies the endpoint for a service's public outbox rendered as an Atom feed.
+	OutboxAtomPath = "/outbox.atom"
+	// ProcessedAnchorsPath specifies the endpoint for the log of anchors processed by the observer.
+	ProcessedAnchorsPath = "/processed-anchors"
+	// CASHashesPath specifies the endpoint for the list of resource hashes held in the local CAS.
+	CASHashesPath = "/cas-hashes"
 )
 
 const (
@@ -74,6 +80,14 @@ type Config struct {
 	ServiceEndpointURL     *url.URL
 	PageSize               int
 	VerifyActorInSignature bool
+
+	// AnchorResolver resolves anchor linksets from CAS. If set then a client may request that anchor
+	// linksets be embedded into the activities returned from the outbox and activity endpoints by
+	// specifying the 'embed=true' query parameter.
+	AnchorResolver anchorResolver
+	// MaxEmbeddedAnchorSize is the maximum size (in bytes) of an anchor linkset that may be embedded into an
+	// activity. Larger anchor linksets are returned as references. Embedding is disabled if zero.
+	MaxEmbeddedAnchorSize int
 }
 
 type handler struct {
@@ -127,6 +141,15 @@ func (h *handler) Handler() common.HTTPRequestHandler {
 	return h.handler
 }
 
+// pageSize returns the configured page size, capped at maxPageSize.
+func (h *handler) pageSize() int {
+	if h.PageSize > maxPageSize {
+		return maxPageSize
+	}
+
+	return h.PageSize
+}
+
 func (h *handler) getPageID(objectIRI fmt.Stringer, pageNum int) string {
 	var delimiter string
 
@@ -8,6 +8,7 @@ package activityhandler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -17,6 +18,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/trustbloc/logutil-go/pkg/log"
 
@@ -1719,6 +1722,199 @@ func TestHandler_HandleOfferActivity(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "object target IRI must be set to https://w3id.org/activityanchors#AnchorWitness")
 	})
+
+	t.Run("Offer expires while witnessing", func(t *testing.T) {
+		witness.WithProof([]byte(proof)).WithDelay(100 * time.Millisecond)
+		defer witness.WithDelay(0)
+
+		startTime := time.Now()
+		endTime := startTime.Add(50 * time.Millisecond)
+
+		offer := vocab.NewOfferActivity(
+			vocab.NewObjectProperty(vocab.WithDocument(anchorLinksetDoc)),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+			vocab.WithTo(service2IRI),
+			vocab.WithStartTime(&startTime),
+			vocab.WithEndTime(&endTime),
+			vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
+		)
+
+		err := h.HandleActivity(context.Background(), nil, offer)
+		require.ErrorIs(t, err, spi.ErrWitnessOfferExpired)
+	})
+}
+
+func TestHandler_AcceptOfferExpiry(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1IRI,
+		ServiceEndpointURL: service1IRI,
+	}
+
+	anchorLink := aptestutil.NewMockAnchorLink(t)
+	anchorLinksetDoc, err := vocab.MarshalToDoc(linkset.New(anchorLink))
+	require.NoError(t, err)
+
+	result, err := vocab.NewObjectWithDocument(vocab.MustUnmarshalToDoc([]byte(proof)))
+	require.NoError(t, err)
+
+	newOffer := func(endTime *time.Time) *vocab.ActivityType {
+		startTime := time.Now()
+
+		opts := []vocab.Opt{
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+			vocab.WithTo(service2IRI),
+			vocab.WithStartTime(&startTime),
+			vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
+		}
+
+		if endTime != nil {
+			opts = append(opts, vocab.WithEndTime(endTime))
+		}
+
+		return vocab.NewOfferActivity(vocab.NewObjectProperty(vocab.WithDocument(anchorLinksetDoc)), opts...)
+	}
+
+	newAcceptOffer := func(offer *vocab.ActivityType) *vocab.ActivityType {
+		startTime := time.Now()
+		endTime := startTime.Add(time.Hour)
+
+		return vocab.NewAcceptActivity(
+			vocab.NewObjectProperty(vocab.WithActivity(vocab.NewOfferActivity(
+				vocab.NewObjectProperty(vocab.WithIRI(anchorLink.Anchor())),
+				vocab.WithID(offer.ID().URL()),
+				vocab.WithActor(offer.Actor()),
+				vocab.WithTo(offer.To()...),
+				vocab.WithTarget(offer.Target()),
+			))),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithTo(offer.Actor(), vocab.PublicIRI),
+			vocab.WithActor(service2IRI),
+			vocab.WithResult(vocab.NewObjectProperty(
+				vocab.WithObject(vocab.NewObject(
+					vocab.WithType(vocab.TypeAnchorReceipt),
+					vocab.WithInReplyTo(anchorLink.Anchor()),
+					vocab.WithStartTime(&startTime),
+					vocab.WithEndTime(&endTime),
+					vocab.WithAttachment(vocab.NewObjectProperty(vocab.WithObject(result))),
+				)),
+			)),
+		)
+	}
+
+	newInbox := func(t *testing.T, opts ...spi.HandlerOpt) *Inbox {
+		t.Helper()
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), &servicemocks.Outbox{}, servicemocks.NewActivitPubClient(),
+			append(opts, spi.WithProofHandler(servicemocks.NewProofHandler()))...)
+
+		h.Start()
+		t.Cleanup(h.Stop)
+
+		return h
+	}
+
+	addToOutbox := func(t *testing.T, h *Inbox, offer *vocab.ActivityType) {
+		t.Helper()
+
+		require.NoError(t, h.store.AddActivity(offer))
+		require.NoError(t, h.store.AddReference(store.Outbox, h.ServiceIRI, offer.ID().URL()))
+	}
+
+	t.Run("Offer expired -> error", func(t *testing.T) {
+		h := newInbox(t)
+
+		endTime := time.Now().Add(-time.Second)
+
+		offer := newOffer(&endTime)
+		addToOutbox(t, h, offer)
+
+		err := h.HandleActivity(context.Background(), nil, newAcceptOffer(offer))
+		require.ErrorIs(t, err, spi.ErrWitnessOfferExpired)
+	})
+
+	t.Run("No end time and no witness offer store -> error", func(t *testing.T) {
+		h := newInbox(t)
+
+		offer := newOffer(nil)
+		addToOutbox(t, h, offer)
+
+		err := h.HandleActivity(context.Background(), nil, newAcceptOffer(offer))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "endTime is required")
+	})
+
+	t.Run("Witness offer store", func(t *testing.T) {
+		offerStore := servicemocks.NewWitnessOfferStore(time.Hour)
+
+		h := newInbox(t, spi.WithWitnessOfferStore(offerStore))
+
+		t.Run("Success", func(t *testing.T) {
+			endTime := time.Now().Add(time.Hour)
+
+			offer := newOffer(&endTime)
+			addToOutbox(t, h, offer)
+			require.NoError(t, offerStore.Put(offer.ID().URL(), offer.EndTime()))
+
+			require.NoError(t, h.HandleActivity(context.Background(), nil, newAcceptOffer(offer)))
+		})
+
+		t.Run("No end time -> default lifetime", func(t *testing.T) {
+			offer := newOffer(nil)
+			addToOutbox(t, h, offer)
+			require.NoError(t, offerStore.Put(offer.ID().URL(), nil))
+
+			require.NoError(t, h.HandleActivity(context.Background(), nil, newAcceptOffer(offer)))
+		})
+
+		t.Run("Offer removed from store -> error", func(t *testing.T) {
+			endTime := time.Now().Add(time.Hour)
+
+			offer := newOffer(&endTime)
+			addToOutbox(t, h, offer)
+			require.NoError(t, offerStore.Put(offer.ID().URL(), offer.EndTime()))
+
+			offerStore.Delete(offer.ID().URL())
+
+			err := h.HandleActivity(context.Background(), nil, newAcceptOffer(offer))
+			require.ErrorIs(t, err, spi.ErrWitnessOfferExpired)
+		})
+
+		t.Run("Stored offer expired -> error", func(t *testing.T) {
+			// The offer has expired but it hasn't been removed from the store yet.
+			offerStore := servicemocks.NewWitnessOfferStore(-time.Second)
+
+			h := newInbox(t, spi.WithWitnessOfferStore(offerStore))
+
+			offer := newOffer(nil)
+			addToOutbox(t, h, offer)
+			require.NoError(t, offerStore.Put(offer.ID().URL(), nil))
+
+			err := h.HandleActivity(context.Background(), nil, newAcceptOffer(offer))
+			require.ErrorIs(t, err, spi.ErrWitnessOfferExpired)
+		})
+
+		t.Run("Store error", func(t *testing.T) {
+			errExpected := errors.New("injected store error")
+
+			offerStore := servicemocks.NewWitnessOfferStore(time.Hour).WithError(errExpected)
+
+			h := newInbox(t, spi.WithWitnessOfferStore(offerStore))
+
+			endTime := time.Now().Add(time.Hour)
+
+			offer := newOffer(&endTime)
+			addToOutbox(t, h, offer)
+
+			err := h.HandleActivity(context.Background(), nil, newAcceptOffer(offer))
+			require.ErrorIs(t, err, errExpected)
+		})
+	})
 }
 
 //nolint:maintidx
@@ -2910,6 +3106,298 @@ func TestHandler_AnnounceAnchorEvent(t *testing.T) {
 	})
 }
 
+func TestHandler_AnnounceAnchorEventBatching(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+	service3IRI := testutil.MustParseURL("http://localhost:8303/services/service3")
+
+	cfg := &Config{
+		ServiceName:        "service2",
+		ServiceIRI:         service2IRI,
+		ServiceEndpointURL: service2IRI,
+	}
+
+	anchorEventHandler := servicemocks.NewAnchorEventHandler()
+
+	newCreate := func(anchorEvent *vocab.AnchorEventType) *vocab.ActivityType {
+		return aptestutil.NewMockCreateActivity(service1IRI, service2IRI,
+			vocab.NewObjectProperty(vocab.WithAnchorEvent(anchorEvent)),
+		)
+	}
+
+	anchorEventURLs := []*url.URL{
+		testutil.MustParseURL("hl:uEiAsiwjaXOYDmOHxmvDl3Mx0TfJ0uCar5YXqumjFJUNIBg"),
+		testutil.MustParseURL("hl:uEiCJWrAzh2AOq5vSP6hb4_i7RC1HE-Dq3b9-6cK3R6ZNuQ"),
+		testutil.MustParseURL("hl:uEiBqQJ4hPZpWN9LNTVhbK4QgcjZk9RIj5ZYdL3C-T3vHEQ"),
+	}
+
+	t.Run("Batch posted when max items reached", func(t *testing.T) {
+		activityStore := memstore.New(cfg.ServiceName)
+		ob := servicemocks.NewOutbox().WithActivityID(testutil.NewMockID(service2IRI, "/activities/123456789"))
+
+		h := NewInbox(cfg, activityStore, ob, servicemocks.NewActivitPubClient(),
+			spi.WithAnchorEventHandler(anchorEventHandler),
+			spi.WithOutboxBatching(time.Minute, len(anchorEventURLs)))
+		require.NotNil(t, h)
+
+		h.Start()
+		defer h.Stop()
+
+		for i, u := range anchorEventURLs {
+			require.Empty(t, ob.Activities().QueryByType(vocab.TypeAnnounce))
+
+			require.NoError(t, h.announceAnchorEventRef(context.Background(),
+				newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(u)))), "item %d", i)
+		}
+
+		announces := ob.Activities().QueryByType(vocab.TypeAnnounce)
+		require.Len(t, announces, 1)
+
+		items := announces[0].Object().Collection().Items()
+		require.Len(t, items, len(anchorEventURLs))
+
+		for i, item := range items {
+			require.True(t, item.Type().Is(vocab.TypeAnchorEvent))
+			require.Equal(t, anchorEventURLs[i].String(), item.AnchorEvent().URL()[0].String())
+
+			it, err := activityStore.QueryReferences(store.Share,
+				store.NewCriteria(store.WithObjectIRI(anchorEventURLs[i])))
+			require.NoError(t, err)
+
+			refs, err := storeutil.ReadReferences(it, -1)
+			require.NoError(t, err)
+			require.Len(t, refs, 1)
+		}
+
+		t.Run("Peer expands batched announce", func(t *testing.T) {
+			peerCfg := &Config{
+				ServiceName:        "service3",
+				ServiceIRI:         service3IRI,
+				ServiceEndpointURL: service3IRI,
+			}
+
+			peer := NewInbox(peerCfg, memstore.New(peerCfg.ServiceName), servicemocks.NewOutbox(),
+				servicemocks.NewActivitPubClient(), spi.WithAnchorEventHandler(servicemocks.NewAnchorEventHandler()))
+
+			peer.Start()
+			defer peer.Stop()
+
+			published := time.Now()
+
+			announce := vocab.NewAnnounceActivity(announces[0].Object(),
+				vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+				vocab.WithActor(service2IRI),
+				vocab.WithTo(service3IRI),
+				vocab.WithPublishedTime(&published),
+			)
+
+			announceBytes, err := canonicalizer.MarshalCanonical(announce)
+			require.NoError(t, err)
+
+			received := &vocab.ActivityType{}
+			require.NoError(t, json.Unmarshal(announceBytes, received))
+
+			numProcessed, err := peer.HandleAnnounceActivity(context.Background(), nil, received)
+			require.NoError(t, err)
+			require.Equal(t, len(anchorEventURLs), numProcessed)
+		})
+	})
+
+	t.Run("Batch posted when window expires", func(t *testing.T) {
+		ob := servicemocks.NewOutbox().WithActivityID(testutil.NewMockID(service2IRI, "/activities/123456789"))
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, servicemocks.NewActivitPubClient(),
+			spi.WithAnchorEventHandler(anchorEventHandler),
+			spi.WithOutboxBatching(50*time.Millisecond, 10))
+		require.NotNil(t, h)
+
+		h.Start()
+		defer h.Stop()
+
+		require.NoError(t, h.announceAnchorEventRef(context.Background(),
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[0])))))
+		require.NoError(t, h.announceAnchorEventRef(context.Background(),
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[1])))))
+
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeAnnounce))
+
+		time.Sleep(200 * time.Millisecond)
+
+		announces := ob.Activities().QueryByType(vocab.TypeAnnounce)
+		require.Len(t, announces, 1)
+		require.Len(t, announces[0].Object().Collection().Items(), 2)
+	})
+
+	t.Run("Flush pending batches", func(t *testing.T) {
+		ob := servicemocks.NewOutbox().WithActivityID(testutil.NewMockID(service2IRI, "/activities/123456789"))
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, servicemocks.NewActivitPubClient(),
+			spi.WithAnchorEventHandler(anchorEventHandler),
+			spi.WithOutboxBatching(time.Minute, 10))
+		require.NotNil(t, h)
+
+		h.Start()
+		defer h.Stop()
+
+		// Embedded anchor events exclude the actor of the 'Create' so they're batched separately
+		// from anchor event references.
+		require.NoError(t, h.announceAnchorEvent(context.Background(),
+			newCreate(aptestutil.NewMockAnchorEvent(t, aptestutil.NewMockAnchorLink(t)))))
+		require.NoError(t, h.announceAnchorEventRef(context.Background(),
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[0])))))
+
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeAnnounce))
+
+		h.FlushAnnouncements()
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAnnounce), 2)
+
+		h.FlushAnnouncements()
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAnnounce), 2)
+	})
+
+	t.Run("Batching disabled", func(t *testing.T) {
+		ob := servicemocks.NewOutbox().WithActivityID(testutil.NewMockID(service2IRI, "/activities/123456789"))
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, servicemocks.NewActivitPubClient(),
+			spi.WithAnchorEventHandler(anchorEventHandler),
+			spi.WithOutboxBatching(time.Minute, 1))
+		require.NotNil(t, h)
+		require.Nil(t, h.batcher)
+
+		h.Start()
+		defer h.Stop()
+
+		require.NoError(t, h.announceAnchorEventRef(context.Background(),
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[0])))))
+		require.NoError(t, h.announceAnchorEventRef(context.Background(),
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[1])))))
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAnnounce), 2)
+
+		h.FlushAnnouncements()
+	})
+
+	t.Run("Outbox error -> error returned and batch requeued", func(t *testing.T) {
+		activityStore := memstore.New(cfg.ServiceName)
+
+		errExpected := errors.New("injected outbox error")
+
+		ob := servicemocks.NewOutbox().WithError(errExpected).
+			WithActivityID(testutil.NewMockID(service2IRI, "/activities/123456789"))
+
+		h := NewInbox(cfg, activityStore, ob, servicemocks.NewActivitPubClient(),
+			spi.WithAnchorEventHandler(anchorEventHandler),
+			spi.WithOutboxBatching(time.Minute, 2))
+		require.NotNil(t, h)
+
+		h.Start()
+		defer h.Stop()
+
+		require.NoError(t, h.announceAnchorEventRef(context.Background(),
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[0])))))
+
+		err := h.announceAnchorEventRef(context.Background(),
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[1]))))
+		require.ErrorIs(t, err, errExpected)
+		require.True(t, orberrors.IsTransient(err))
+
+		ob.WithError(nil)
+
+		// The first item was requeued so it's posted along with the retried item.
+		require.NoError(t, h.announceAnchorEventRef(context.Background(),
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[1])))))
+
+		announces := ob.Activities().QueryByType(vocab.TypeAnnounce)
+		require.Len(t, announces, 1)
+
+		items := announces[0].Object().Collection().Items()
+		require.Len(t, items, 2)
+
+		for i, item := range items {
+			require.Equal(t, anchorEventURLs[i].String(), item.AnchorEvent().URL()[0].String())
+
+			it, err := activityStore.QueryReferences(store.Share,
+				store.NewCriteria(store.WithObjectIRI(anchorEventURLs[i])))
+			require.NoError(t, err)
+
+			refs, err := storeutil.ReadReferences(it, -1)
+			require.NoError(t, err)
+			require.Len(t, refs, 1)
+		}
+	})
+
+	t.Run("Outbox error -> items dropped after max attempts", func(t *testing.T) {
+		activityStore := &servicemocks.ActivityStore{}
+
+		ob := servicemocks.NewOutbox().WithError(errors.New("injected outbox error"))
+
+		h := NewInbox(cfg, activityStore, ob, servicemocks.NewActivitPubClient(),
+			spi.WithAnchorEventHandler(anchorEventHandler),
+			spi.WithOutboxBatching(10*time.Millisecond, 10))
+		require.NotNil(t, h)
+
+		h.Start()
+		defer h.Stop()
+
+		require.NoError(t, h.announceAnchorEventRef(context.Background(),
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[0])))))
+
+		time.Sleep(200 * time.Millisecond)
+
+		h.batcher.mutex.Lock()
+		require.Empty(t, h.batcher.batches)
+		h.batcher.mutex.Unlock()
+
+		require.Zero(t, activityStore.AddReferenceCallCount())
+	})
+
+	t.Run("Batched announce linked to originating spans", func(t *testing.T) {
+		ob := servicemocks.NewOutbox().WithActivityID(testutil.NewMockID(service2IRI, "/activities/123456789"))
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, servicemocks.NewActivitPubClient(),
+			spi.WithAnchorEventHandler(anchorEventHandler),
+			spi.WithOutboxBatching(time.Minute, 2))
+		require.NotNil(t, h)
+
+		recorder := tracetest.NewSpanRecorder()
+
+		tracer := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)).Tracer("test")
+
+		h.batcher.tracer = tracer
+
+		h.Start()
+		defer h.Stop()
+
+		ctx1, span1 := tracer.Start(context.Background(), "create 1")
+
+		require.NoError(t, h.announceAnchorEventRef(ctx1,
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[0])))))
+
+		span1.End()
+
+		ctx2, span2 := tracer.Start(context.Background(), "create 2")
+
+		require.NoError(t, h.announceAnchorEventRef(ctx2,
+			newCreate(vocab.NewAnchorEvent(nil, vocab.WithURL(anchorEventURLs[1])))))
+
+		span2.End()
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 3)
+
+		postSpan := spans[1]
+		require.Equal(t, "outbox post batched announce", postSpan.Name())
+		require.Equal(t, span2.SpanContext().SpanID(), postSpan.Parent().SpanID())
+
+		links := postSpan.Links()
+		require.Len(t, links, 2)
+		require.Equal(t, span1.SpanContext().SpanID(), links[0].SpanContext.SpanID())
+		require.Equal(t, span2.SpanContext().SpanID(), links[1].SpanContext.SpanID())
+	})
+}
+
 func TestHandler_InboxHandleLikeActivity(t *testing.T) {
 	log.SetLevel("activitypub_service", log.DEBUG)
 
@@ -3065,6 +3553,61 @@ func TestHandler_InboxHandleLikeActivity(t *testing.T) {
 	})
 }
 
+func TestHandler_OutboxHandleOfferActivity(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1IRI,
+		ServiceEndpointURL: service1IRI,
+	}
+
+	anchorLinksetDoc, err := vocab.MarshalToDoc(linkset.New(aptestutil.NewMockAnchorLink(t)))
+	require.NoError(t, err)
+
+	startTime := time.Now()
+	endTime := startTime.Add(time.Hour)
+
+	offer := vocab.NewOfferActivity(
+		vocab.NewObjectProperty(vocab.WithDocument(anchorLinksetDoc)),
+		vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+		vocab.WithActor(service1IRI),
+		vocab.WithTo(service2IRI),
+		vocab.WithStartTime(&startTime),
+		vocab.WithEndTime(&endTime),
+		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
+	)
+
+	t.Run("No witness offer store", func(t *testing.T) {
+		h := NewOutbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewActivitPubClient())
+
+		require.NoError(t, h.HandleActivity(context.Background(), nil, offer))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		offerStore := servicemocks.NewWitnessOfferStore(time.Minute)
+
+		h := NewOutbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewActivitPubClient(),
+			spi.WithWitnessOfferStore(offerStore))
+
+		require.NoError(t, h.HandleActivity(context.Background(), nil, offer))
+
+		expiryTime, err := offerStore.GetExpiryTime(offer.ID().URL())
+		require.NoError(t, err)
+		require.True(t, endTime.Equal(expiryTime))
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		h := NewOutbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewActivitPubClient(),
+			spi.WithWitnessOfferStore(servicemocks.NewWitnessOfferStore(time.Minute).WithError(errExpected)))
+
+		require.ErrorIs(t, h.HandleActivity(context.Background(), nil, offer), errExpected)
+	})
+}
+
 func TestHandler_OutboxHandleLikeActivity(t *testing.T) {
 	log.SetLevel("activitypub_service", log.DEBUG)
 
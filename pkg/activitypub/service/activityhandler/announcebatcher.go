@@ -0,0 +1,281 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activityhandler
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/observability/tracing"
+)
+
+// maxPostAttempts is the maximum number of times that a batch is posted before its items are dropped.
+const maxPostAttempts = 3
+
+// announceBatcher coalesces the anchor events that are announced to our followers into batches so that
+// a single 'Announce' activity (containing a collection of anchor events) is posted for multiple anchor events.
+// A batch is posted when the batching window expires or when the batch reaches the maximum number of items.
+// Anchor events are batched separately by the set of actors which are to be excluded from the announcement.
+// If a batch can't be posted then its items are requeued and posted with the next batch (up to maxPostAttempts).
+type announceBatcher struct {
+	window       time.Duration
+	maxItems     int
+	outbox       service.Outbox
+	store        store.Store
+	followersIRI *url.URL
+	tracer       trace.Tracer
+	logger       *log.Log
+
+	mutex   sync.Mutex
+	batches map[string]*announceBatch
+}
+
+type announceBatch struct {
+	key      string
+	items    []*vocab.ObjectProperty
+	refs     []*url.URL
+	links    []trace.Link
+	exclude  []*url.URL
+	attempts int
+	timer    *time.Timer
+}
+
+func newAnnounceBatcher(cfg service.OutboxBatching, outbox service.Outbox, s store.Store,
+	followersIRI *url.URL, tracer trace.Tracer, logger *log.Log,
+) *announceBatcher {
+	return &announceBatcher{
+		window:       cfg.Window,
+		maxItems:     cfg.MaxItems,
+		outbox:       outbox,
+		store:        s,
+		followersIRI: followersIRI,
+		tracer:       tracer,
+		logger:       logger,
+		batches:      make(map[string]*announceBatch),
+	}
+}
+
+// add adds the given item to a pending batch. The optional anchor event URL is added to the 'shares'
+// of the anchor event once the batch is posted. The span in the given context is linked to the span
+// which posts the batch. If adding the item fills the batch then the batch is posted immediately and
+// an error is returned if the post fails, in which case the given item is not retained (since the caller
+// is responsible for retrying it) but the other items in the batch are requeued.
+func (b *announceBatcher) add(ctx context.Context, item *vocab.ObjectProperty, anchorEventURL *url.URL,
+	exclude ...*url.URL,
+) error {
+	b.mutex.Lock()
+
+	batch := b.pendingBatch(exclude)
+
+	batch.items = append(batch.items, item)
+	batch.links = append(batch.links, trace.LinkFromContext(ctx))
+
+	if anchorEventURL != nil {
+		batch.refs = append(batch.refs, anchorEventURL)
+	}
+
+	full := len(batch.items) >= b.maxItems
+	if full {
+		b.removeBatch(batch)
+	}
+
+	b.mutex.Unlock()
+
+	if !full {
+		return nil
+	}
+
+	if err := b.post(ctx, batch); err != nil {
+		batch.items = batch.items[:len(batch.items)-1]
+		batch.links = batch.links[:len(batch.links)-1]
+
+		if anchorEventURL != nil {
+			batch.refs = batch.refs[:len(batch.refs)-1]
+		}
+
+		b.requeue(batch)
+
+		return orberrors.NewTransient(err)
+	}
+
+	return nil
+}
+
+// flush posts all pending batches. Batches which fail to post are not requeued since
+// flush is invoked on shutdown.
+func (b *announceBatcher) flush() {
+	b.mutex.Lock()
+
+	batches := make([]*announceBatch, 0, len(b.batches))
+
+	for _, batch := range b.batches {
+		batches = append(batches, batch)
+	}
+
+	for _, batch := range batches {
+		b.removeBatch(batch)
+	}
+
+	b.mutex.Unlock()
+
+	for _, batch := range batches {
+		if err := b.post(context.Background(), batch); err != nil {
+			b.logger.Error("Unable to post batched 'Announce' activity to our followers. The items are dropped.",
+				logfields.WithTotal(len(batch.items)), log.WithError(err))
+		}
+	}
+}
+
+// flushBatch is invoked when the batching window expires.
+func (b *announceBatcher) flushBatch(batch *announceBatch) {
+	b.mutex.Lock()
+
+	if b.batches[batch.key] != batch {
+		// The batch was already posted.
+		b.mutex.Unlock()
+
+		return
+	}
+
+	b.removeBatch(batch)
+
+	b.mutex.Unlock()
+
+	if err := b.post(context.Background(), batch); err != nil {
+		b.requeue(batch)
+	}
+}
+
+// requeue adds the items of a batch which failed to post to the pending batch for the same
+// set of excluded actors. The items are dropped if the maximum number of attempts has been reached.
+func (b *announceBatcher) requeue(failed *announceBatch) {
+	if len(failed.items) == 0 {
+		return
+	}
+
+	attempts := failed.attempts + 1
+
+	if attempts >= maxPostAttempts {
+		b.logger.Error("Unable to post batched 'Announce' activity to our followers after maximum attempts. "+
+			"The items are dropped.", logfields.WithTotal(len(failed.items)))
+
+		return
+	}
+
+	b.logger.Info("Requeuing items of batched 'Announce' activity", logfields.WithTotal(len(failed.items)))
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	batch := b.pendingBatch(failed.exclude)
+
+	batch.items = append(failed.items, batch.items...)
+	batch.refs = append(failed.refs, batch.refs...)
+	batch.links = append(failed.links, batch.links...)
+
+	if attempts > batch.attempts {
+		batch.attempts = attempts
+	}
+}
+
+// pendingBatch returns the pending batch for the given set of excluded actors, creating it
+// if necessary. The mutex must be held by the caller.
+func (b *announceBatcher) pendingBatch(exclude []*url.URL) *announceBatch {
+	key := excludeKey(exclude)
+
+	batch, ok := b.batches[key]
+	if !ok {
+		batch = &announceBatch{key: key, exclude: exclude}
+
+		b.batches[key] = batch
+
+		batch.timer = time.AfterFunc(b.window, func() {
+			b.flushBatch(batch)
+		})
+	}
+
+	return batch
+}
+
+// removeBatch stops the timer of the given batch and removes it from the pending batches.
+// The mutex must be held by the caller.
+func (b *announceBatcher) removeBatch(batch *announceBatch) {
+	batch.timer.Stop()
+
+	delete(b.batches, batch.key)
+}
+
+func (b *announceBatcher) post(ctx context.Context, batch *announceBatch) error {
+	spanCtx, span := b.tracer.Start(ctx, "outbox post batched announce",
+		trace.WithLinks(batch.links...),
+		trace.WithAttributes(attribute.Int("total", len(batch.items))),
+	)
+
+	err := b.doPost(spanCtx, batch)
+
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (b *announceBatcher) doPost(ctx context.Context, batch *announceBatch) error {
+	published := time.Now()
+
+	announce := vocab.NewAnnounceActivity(
+		vocab.NewObjectProperty(
+			vocab.WithCollection(
+				vocab.NewCollection(batch.items),
+			),
+		),
+		vocab.WithTo(b.followersIRI, vocab.PublicIRI),
+		vocab.WithPublishedTime(&published),
+	)
+
+	b.logger.Debug("Posting batched 'Announce' activity", logfields.WithTotal(len(batch.items)))
+
+	activityID, err := b.outbox.Post(ctx, announce, batch.exclude...)
+	if err != nil {
+		b.logger.Warn("Unable to post batched 'Announce' activity to our followers",
+			logfields.WithTotal(len(batch.items)), log.WithError(err))
+
+		return err
+	}
+
+	for _, anchorEventURL := range batch.refs {
+		if err := b.store.AddReference(store.Share, anchorEventURL, activityID); err != nil {
+			b.logger.Warn("Error adding 'Announce' activity to 'shares' of anchor event",
+				logfields.WithActivityID(activityID), logfields.WithAnchorEventURI(anchorEventURL), log.WithError(err))
+		}
+	}
+
+	return nil
+}
+
+func excludeKey(exclude []*url.URL) string {
+	keys := make([]string, len(exclude))
+
+	for i, u := range exclude {
+		keys[i] = u.String()
+	}
+
+	sort.Strings(keys)
+
+	return strings.Join(keys, " ")
+}
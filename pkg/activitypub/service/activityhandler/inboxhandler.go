@@ -39,6 +39,7 @@ type Inbox struct {
 	outbox       service.Outbox
 	followersIRI *url.URL
 	tracer       trace.Tracer
+	batcher      *announceBatcher
 }
 
 // NewInbox returns a new ActivityPub inbox activity handler.
@@ -78,9 +79,21 @@ func NewInbox(cfg *Config, s store.Store, outbox service.Outbox,
 		h.inboxUndoLike,
 	)
 
+	if options.OutboxBatching.Enabled() {
+		h.batcher = newAnnounceBatcher(options.OutboxBatching, outbox, s, followersIRI, h.tracer, h.logger)
+	}
+
 	return h
 }
 
+// FlushAnnouncements posts any 'Announce' activities that are pending due to outbox batching. This function
+// should be called before the outbox is stopped.
+func (h *Inbox) FlushAnnouncements() {
+	if h.batcher != nil {
+		h.batcher.flush()
+	}
+}
+
 // HandleActivity handles the ActivityPub activity in the inbox.
 //
 //nolint:cyclop
@@ -531,6 +544,11 @@ func (h *Inbox) handleOfferActivity(ctx context.Context, offer *vocab.ActivityTy
 		return fmt.Errorf("error creating result for 'Offer' activity [%s]: %w", offer.ID(), err)
 	}
 
+	// Witnessing may take a while so make sure that the offer hasn't expired in the meantime.
+	if time.Now().After(*offer.EndTime()) {
+		return fmt.Errorf("reply to 'Offer' activity [%s]: %w", offer.ID(), service.ErrWitnessOfferExpired)
+	}
+
 	startTime := time.Now()
 	endTime := startTime.Add(h.MaxWitnessDelay)
 
@@ -579,6 +597,11 @@ func (h *Inbox) handleAcceptOfferActivity(ctx context.Context, accept, offer *vo
 		return fmt.Errorf("invalid 'Accept' offer activity [%s]: %w", accept.ID(), err)
 	}
 
+	offerEndTime, err := h.getOfferExpiryTime(offer)
+	if err != nil {
+		return fmt.Errorf("'Accept' offer activity [%s]: %w", accept.ID(), err)
+	}
+
 	result := accept.Result().Object()
 
 	inReplyTo := result.InReplyTo()
@@ -611,7 +634,7 @@ func (h *Inbox) handleAcceptOfferActivity(ctx context.Context, accept, offer *vo
 		return fmt.Errorf("marshal error of attachment in 'Accept' offer activity [%s]: %w", accept.ID(), err)
 	}
 
-	err = h.ProofHandler.HandleProof(ctx, accept.Actor(), anchorLink.Anchor().String(), *offer.EndTime(), attachmentBytes)
+	err = h.ProofHandler.HandleProof(ctx, accept.Actor(), anchorLink.Anchor().String(), offerEndTime, attachmentBytes)
 	if err != nil {
 		return fmt.Errorf("proof handler returned error for 'Accept' offer activity [%s]: %w", accept.ID(), err)
 	}
@@ -621,6 +644,45 @@ func (h *Inbox) handleAcceptOfferActivity(ctx context.Context, accept, offer *vo
 	return nil
 }
 
+// getOfferExpiryTime returns the time at which the given 'Offer' (posted by this service) expires. An error is
+// returned if the offer has expired. The expiry is checked at the time that the offer is acted upon, rather than
+// relying on the removal of expired offers from the witness offer store, so that an offer which has only just
+// expired isn't honored.
+func (h *Inbox) getOfferExpiryTime(offer *vocab.ActivityType) (time.Time, error) {
+	var expiryTime time.Time
+
+	if offer.EndTime() != nil {
+		expiryTime = *offer.EndTime()
+	}
+
+	if h.WitnessOfferStore != nil {
+		storedExpiryTime, err := h.WitnessOfferStore.GetExpiryTime(offer.ID().URL())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("get expiry time of offer [%s]: %w", offer.ID(), err)
+		}
+
+		if storedExpiryTime.IsZero() {
+			// The offer was either removed from the store after it expired or it was never posted by this service.
+			return time.Time{}, fmt.Errorf("offer [%s] not found in witness offer store: %w",
+				offer.ID(), service.ErrWitnessOfferExpired)
+		}
+
+		if expiryTime.IsZero() {
+			expiryTime = storedExpiryTime
+		}
+	}
+
+	if expiryTime.IsZero() {
+		return time.Time{}, fmt.Errorf("endTime is required in offer [%s]", offer.ID())
+	}
+
+	if time.Now().After(expiryTime) {
+		return time.Time{}, fmt.Errorf("offer [%s]: %w", offer.ID(), service.ErrWitnessOfferExpired)
+	}
+
+	return expiryTime, nil
+}
+
 func (h *Inbox) handleAnchorEvent(ctx context.Context, actor, source *url.URL, anchorEvent *vocab.AnchorEventType) error {
 	anchorRef := anchorEvent.URL()[0]
 
@@ -768,6 +830,11 @@ func (h *Inbox) handleLikeActivity(like *vocab.ActivityType) error {
 func (h *Inbox) announceAnchorEvent(ctx context.Context, create *vocab.ActivityType) error {
 	anchorEvent := create.Object().AnchorEvent()
 
+	if h.batcher != nil {
+		// Announce the activity to our followers but exclude the actor of the Create.
+		return h.batcher.add(ctx, vocab.NewObjectProperty(vocab.WithAnchorEvent(anchorEvent)), nil, create.Actor())
+	}
+
 	published := time.Now()
 
 	announce := vocab.NewAnnounceActivity(
@@ -801,6 +868,11 @@ func (h *Inbox) announceAnchorEventRef(ctx context.Context, create *vocab.Activi
 
 	anchorEventURL := create.Object().AnchorEvent().URL()[0]
 
+	if h.batcher != nil {
+		return h.batcher.add(ctx, vocab.NewObjectProperty(vocab.WithAnchorEvent(create.Object().AnchorEvent())),
+			anchorEventURL)
+	}
+
 	published := time.Now()
 
 	announce := vocab.NewAnnounceActivity(
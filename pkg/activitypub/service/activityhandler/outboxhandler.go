@@ -15,6 +15,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
@@ -26,13 +27,22 @@ import (
 type Outbox struct {
 	*handler
 
-	tracer trace.Tracer
+	tracer            trace.Tracer
+	witnessOfferStore service.WitnessOfferStore
 }
 
-// NewOutbox returns a new ActivityPub outbox activity handler.
-func NewOutbox(cfg *Config, s store.Store, activityPubClient activityPubClient) *Outbox {
+// NewOutbox returns a new ActivityPub outbox activity handler. Of the given options, only the witness offer
+// store is used by the outbox handler.
+func NewOutbox(cfg *Config, s store.Store, activityPubClient activityPubClient, opts ...service.HandlerOpt) *Outbox {
+	options := defaultOptions()
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	h := &Outbox{
-		tracer: tracing.Tracer(tracing.SubsystemActivityPub),
+		tracer:            tracing.Tracer(tracing.SubsystemActivityPub),
+		witnessOfferStore: options.WitnessOfferStore,
 	}
 
 	h.handler = newHandler(cfg, s, activityPubClient,
@@ -74,6 +84,8 @@ func (h *Outbox) HandleActivity(ctx context.Context, source *url.URL, activity *
 		return h.handleUndoActivity(spanCtx, activity)
 	case typeProp.Is(vocab.TypeLike):
 		return h.handleLikeActivity(spanCtx, activity)
+	case typeProp.Is(vocab.TypeOffer):
+		return h.handleOfferActivity(spanCtx, activity)
 	default:
 		// Nothing to do for activity.
 		return nil
@@ -122,6 +134,22 @@ func (h *handler) handleCreateActivity(ctx context.Context, create *vocab.Activi
 	return nil
 }
 
+// handleOfferActivity adds the witness offer to the witness offer store (if configured) so that the offer isn't
+// acted upon after it expires. The offer is added before it is delivered to the witnesses.
+func (h *Outbox) handleOfferActivity(ctx context.Context, offer *vocab.ActivityType) error {
+	if h.witnessOfferStore == nil {
+		return nil
+	}
+
+	h.logger.Debugc(ctx, "Storing witness offer", logfields.WithActivityID(offer.ID()))
+
+	if err := h.witnessOfferStore.Put(offer.ID().URL(), offer.EndTime()); err != nil {
+		return fmt.Errorf("store witness offer [%s]: %w", offer.ID(), err)
+	}
+
+	return nil
+}
+
 func (h *Outbox) undoAddReference(activity *vocab.ActivityType, refType store.ReferenceType, getTargetIRI func() *url.URL) error {
 	if activity.Actor().String() != h.ServiceIRI.String() {
 		return fmt.Errorf("this service is not the actor for the 'Undo'")
@@ -8,6 +8,8 @@ package httpsubscriber
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 
@@ -17,8 +19,10 @@ import (
 	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
 
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/httpserver/auth"
 	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/observability/metrics"
 	"github.com/trustbloc/orb/pkg/pubsub"
 )
 
@@ -35,6 +39,10 @@ const (
 type Config struct {
 	ServiceEndpoint string
 	BufferSize      int
+
+	// AcceptedActivityTypes contains the types of activities that are accepted. Activities of any other
+	// type are rejected with a 403 (Forbidden) status. If empty then all activity types are accepted.
+	AcceptedActivityTypes []vocab.Type
 }
 
 type signatureVerifier interface {
@@ -45,6 +53,20 @@ type authTokenManager interface {
 	RequiredAuthTokens(endpoint, method string) ([]string, error)
 }
 
+type metricsProvider interface {
+	InboxIncrementRejectedCount(activityType, reason string)
+}
+
+// Option is an HTTP subscriber option.
+type Option func(s *Subscriber)
+
+// WithMetrics sets the metrics provider which records, among other things, the activities that were rejected.
+func WithMetrics(m metricsProvider) Option {
+	return func(s *Subscriber) {
+		s.metrics = m
+	}
+}
+
 // Subscriber implements a subscriber for Watermill that handles HTTP requests.
 type Subscriber struct {
 	*lifecycle.Lifecycle
@@ -57,11 +79,12 @@ type Subscriber struct {
 	unmarshalMessage wmhttp.UnmarshalMessageFunc
 	verifier         signatureVerifier
 	tokenVerifier    *auth.TokenVerifier
+	metrics          metricsProvider
 	logger           *log.Log
 }
 
 // New returns a new HTTP subscriber.
-func New(cfg *Config, sigVerifier signatureVerifier, tm authTokenManager) *Subscriber {
+func New(cfg *Config, sigVerifier signatureVerifier, tm authTokenManager, opts ...Option) *Subscriber {
 	if cfg.BufferSize == 0 {
 		cfg.BufferSize = defaultBufferSize
 	}
@@ -75,9 +98,14 @@ func New(cfg *Config, sigVerifier signatureVerifier, tm authTokenManager) *Subsc
 		stopped:          make(chan struct{}),
 		done:             make(chan struct{}),
 		tokenVerifier:    auth.NewTokenVerifier(tm, cfg.ServiceEndpoint, http.MethodPost),
+		metrics:          &noopMetrics{},
 		logger:           log.New(loggerModule, log.WithFields(logfields.WithServiceName(cfg.ServiceEndpoint))),
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
 	s.Lifecycle = lifecycle.New("httpsubscriber-"+cfg.ServiceEndpoint,
 		lifecycle.WithStop(s.stop),
 		lifecycle.WithStart(func() {
@@ -159,6 +187,10 @@ func (s *Subscriber) handleMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !s.isAccepted(ctx, msg, actorIRI, w) {
+		return
+	}
+
 	if actorIRI != nil {
 		msg.Metadata[ActorIRIKey] = actorIRI.String()
 	}
@@ -180,6 +212,54 @@ func (s *Subscriber) handleMessage(w http.ResponseWriter, r *http.Request) {
 	s.respond(msg, w, r)
 }
 
+// isAccepted returns true if the type of the activity in the given message is accepted. If the activity isn't
+// accepted then the reason is written to the response.
+func (s *Subscriber) isAccepted(ctx context.Context, msg *message.Message, actorIRI *url.URL,
+	w http.ResponseWriter,
+) bool {
+	if len(s.AcceptedActivityTypes) == 0 {
+		return true
+	}
+
+	activity := &struct {
+		Type *vocab.TypeProperty `json:"type"`
+	}{}
+
+	if err := json.Unmarshal(msg.Payload, activity); err != nil || activity.Type == nil {
+		s.logger.Warnc(ctx, "Unable to determine the activity type of the message", log.WithError(err),
+			logfields.WithMessageID(msg.UUID), logfields.WithActorIRI(actorIRI))
+
+		w.WriteHeader(http.StatusBadRequest)
+
+		return false
+	}
+
+	if activity.Type.IsAny(s.AcceptedActivityTypes...) {
+		return true
+	}
+
+	s.logger.Infoc(ctx, "Rejecting activity since its type is not accepted",
+		logfields.WithMessageID(msg.UUID), logfields.WithActivityType(activity.Type.String()),
+		logfields.WithActorIRI(actorIRI))
+
+	for _, t := range activity.Type.Types() {
+		s.metrics.InboxIncrementRejectedCount(string(t), metrics.InboxRejectReasonTypeNotAccepted)
+	}
+
+	w.WriteHeader(http.StatusForbidden)
+
+	reason := fmt.Sprintf("activity type [%s] is not accepted by this inbox", activity.Type)
+	if actorIRI != nil {
+		reason = fmt.Sprintf("activity type [%s] from actor [%s] is not accepted by this inbox", activity.Type, actorIRI)
+	}
+
+	if _, err := w.Write([]byte(reason)); err != nil {
+		s.logger.Warnc(ctx, "Error writing response", log.WithError(err))
+	}
+
+	return false
+}
+
 func (s *Subscriber) publish(msg *message.Message) error {
 	if s.State() != lifecycle.StateStarted {
 		return lifecycle.ErrNotStarted
@@ -250,3 +330,7 @@ func (s *Subscriber) stop() {
 
 	s.logger.Info("... HTTP subscriber stopped.")
 }
+
+type noopMetrics struct{}
+
+func (m *noopMetrics) InboxIncrementRejectedCount(string, string) {}
@@ -21,8 +21,10 @@ import (
 
 	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/observability/metrics"
 )
 
 const (
@@ -336,3 +338,110 @@ func TestSubscriber_NoHTTPSignatureRequired(t *testing.T) {
 	require.Equal(t, http.StatusOK, result.StatusCode)
 	require.NoError(t, result.Body.Close())
 }
+
+func TestSubscriber_AcceptedActivityTypes(t *testing.T) {
+	sigVerifier := &mocks.SignatureVerifier{}
+	sigVerifier.VerifyRequestReturns(true, testutil.MustParseURL(serviceURL), nil)
+
+	tm := &apmocks.AuthTokenMgr{}
+	tm.RequiredAuthTokensReturns([]string{"admin"}, nil)
+
+	m := &mockMetrics{}
+
+	s := New(&Config{
+		ServiceEndpoint:       endpoint,
+		AcceptedActivityTypes: []vocab.Type{vocab.TypeCreate, vocab.TypeAnnounce},
+	}, sigVerifier, tm, WithMetrics(m))
+	require.NotNil(t, s)
+
+	defer s.Stop()
+
+	msgChan, err := s.Subscribe(context.Background(), "")
+	require.NoError(t, err)
+	require.NotNil(t, msgChan)
+
+	go func() {
+		for msg := range msgChan {
+			msg.Ack()
+		}
+	}()
+
+	t.Run("Accepted", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, endpoint,
+			bytes.NewBufferString(`{"type":"Create","actor":"https://example.com/services/orb"}`))
+
+		s.handleMessage(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, endpoint,
+			bytes.NewBufferString(`{"type":"Follow","actor":"https://example.com/services/orb"}`))
+
+		s.handleMessage(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusForbidden, result.StatusCode)
+		require.Equal(t,
+			fmt.Sprintf("activity type [Follow] from actor [%s] is not accepted by this inbox", serviceURL),
+			rw.Body.String())
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, 1, m.rejected["Follow|"+metrics.InboxRejectReasonTypeNotAccepted])
+	})
+
+	t.Run("Invalid activity", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(`{}`))
+
+		s.handleMessage(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Invalid HTTP signature", func(t *testing.T) {
+		sigVerifier := &mocks.SignatureVerifier{}
+		sigVerifier.VerifyRequestReturns(false, nil, nil)
+
+		s := New(&Config{
+			ServiceEndpoint:       endpoint,
+			AcceptedActivityTypes: []vocab.Type{vocab.TypeCreate},
+		}, sigVerifier, tm)
+		require.NotNil(t, s)
+
+		defer s.Stop()
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(`{"type":"Follow"}`))
+
+		s.handleMessage(rw, req)
+
+		// The signature must be verified before the activity type is checked.
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+type mockMetrics struct {
+	mutex    sync.Mutex
+	rejected map[string]int
+}
+
+func (m *mockMetrics) InboxIncrementRejectedCount(activityType, reason string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.rejected == nil {
+		m.rejected = make(map[string]int)
+	}
+
+	m.rejected[activityType+"|"+reason]++
+}
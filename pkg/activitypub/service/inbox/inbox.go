@@ -8,7 +8,6 @@ package inbox
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -50,6 +49,7 @@ type signatureVerifier interface {
 
 type metricsProvider interface {
 	InboxHandlerTime(activityType string, value time.Duration)
+	InboxIncrementRejectedCount(activityType, reason string)
 }
 
 type authTokenManager interface {
@@ -63,6 +63,9 @@ type Config struct {
 	Topic                  string
 	VerifyActorInSignature bool
 	SubscriberPoolSize     int
+
+	// StrictActivityParsing indicates that incoming activities with unknown top-level fields should be rejected.
+	StrictActivityParsing bool
 }
 
 // Inbox implements the ActivityPub inbox.
@@ -75,25 +78,41 @@ type Inbox struct {
 	msgChannel             <-chan *message.Message
 	activityHandler        service.ActivityHandler
 	activityStore          store.Store
-	jsonUnmarshal          func(data []byte, v interface{}) error
+	unmarshalActivity      func(raw []byte, opts ...vocab.ParseOpt) (*vocab.ActivityType, error)
 	metrics                metricsProvider
 	verifyActorInSignature bool
+	acceptedActivityTypes  []vocab.Type
 	logger                 *log.Log
 }
 
+// Option is an inbox option.
+type Option func(h *Inbox)
+
+// WithAcceptedActivityTypes sets the types of activities that the inbox accepts. An activity of any other
+// type is rejected with a 403 (Forbidden) status. By default, all activity types are accepted.
+func WithAcceptedActivityTypes(types []vocab.Type) Option {
+	return func(h *Inbox) {
+		h.acceptedActivityTypes = types
+	}
+}
+
 // New returns a new ActivityPub inbox.
 func New(cnfg *Config, s store.Store, pubSub pubSub, activityHandler service.ActivityHandler,
-	sigVerifier signatureVerifier, tm authTokenManager, metrics metricsProvider,
+	sigVerifier signatureVerifier, tm authTokenManager, metrics metricsProvider, opts ...Option,
 ) (*Inbox, error) {
 	cfg := populateConfigDefaults(cnfg)
 
 	h := &Inbox{
-		Config:          &cfg,
-		activityHandler: activityHandler,
-		activityStore:   s,
-		jsonUnmarshal:   json.Unmarshal,
-		metrics:         metrics,
-		logger:          log.New(loggerModule, log.WithFields(logfields.WithServiceName(cfg.ServiceEndpoint))),
+		Config:            &cfg,
+		activityHandler:   activityHandler,
+		activityStore:     s,
+		unmarshalActivity: vocab.UnmarshalActivity,
+		metrics:           metrics,
+		logger:            log.New(loggerModule, log.WithFields(logfields.WithServiceName(cfg.ServiceEndpoint))),
+	}
+
+	for _, opt := range opts {
+		opt(h)
 	}
 
 	h.Lifecycle = lifecycle.New(cfg.ServiceEndpoint,
@@ -108,9 +127,11 @@ func New(cnfg *Config, s store.Store, pubSub pubSub, activityHandler service.Act
 
 	httpSubscriber := httpsubscriber.New(
 		&httpsubscriber.Config{
-			ServiceEndpoint: cfg.ServiceEndpoint,
+			ServiceEndpoint:       cfg.ServiceEndpoint,
+			AcceptedActivityTypes: h.acceptedActivityTypes,
 		},
 		sigVerifier, tm,
+		httpsubscriber.WithMetrics(metrics),
 	)
 
 	router, err := message.NewRouter(message.RouterConfig{}, wmlogger.New())
@@ -267,9 +288,7 @@ func (h *Inbox) handleActivityMsg(msg *message.Message) (*vocab.ActivityType, er
 }
 
 func (h *Inbox) unmarshalAndValidateActivity(msg *message.Message) (*vocab.ActivityType, error) {
-	activity := &vocab.ActivityType{}
-
-	err := h.jsonUnmarshal(msg.Payload, activity)
+	activity, err := h.unmarshalActivity(msg.Payload, vocab.WithStrictActivityParsing(h.StrictActivityParsing))
 	if err != nil {
 		return nil, fmt.Errorf("unmarshal activity: %w", err)
 	}
@@ -342,8 +342,8 @@ func TestInbox_Error(t *testing.T) {
 
 		errExpected := fmt.Errorf("injected unmarshal error")
 
-		ib.jsonUnmarshal = func(data []byte, v interface{}) error {
-			return errExpected
+		ib.unmarshalActivity = func([]byte, ...vocab.ParseOpt) (*vocab.ActivityType, error) {
+			return nil, errExpected
 		}
 
 		ib.Start()
@@ -707,6 +707,29 @@ func TestUnmarshalAndValidateActivity(t *testing.T) {
 		require.Contains(t, err.Error(), "does not match the actor in the HTTP signature")
 		require.Nil(t, a)
 	})
+
+	t.Run("Strict activity parsing", func(t *testing.T) {
+		ib, err := New(&Config{StrictActivityParsing: true}, memstore.New(""), mocks.NewPubSub(),
+			nil, nil, tm, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		activity := vocab.NewCreateActivity(nil, vocab.WithID(activityID), vocab.WithActor(actorIRI))
+
+		activityBytes, err := vocab.MarshalJSON(activity, vocab.Document{"unknownField": "value"})
+		require.NoError(t, err)
+
+		a, err := ib.unmarshalAndValidateActivity(message.NewMessage("msg1", activityBytes))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "activity contains unknown fields [unknownField]")
+		require.Nil(t, a)
+
+		activityBytes, err = json.Marshal(activity)
+		require.NoError(t, err)
+
+		a, err = ib.unmarshalAndValidateActivity(message.NewMessage("msg1", activityBytes))
+		require.NoError(t, err)
+		require.NotNil(t, a)
+	})
 }
 
 func newHTTPRequest(u string, activity *vocab.ActivityType) (*http.Request, error) {
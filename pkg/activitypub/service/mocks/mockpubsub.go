@@ -105,7 +105,7 @@ func (m *MockPubSub) Publish(topic string, messages ...*message.Message) error {
 
 		msgChan <- msg
 
-		go m.check(msg)
+		go m.check(topic, msg)
 	}
 
 	return nil
@@ -148,17 +148,17 @@ func (m *MockPubSub) handleUndeliverable() {
 	m.done <- struct{}{}
 }
 
-func (m *MockPubSub) check(msg *message.Message) {
+func (m *MockPubSub) check(topic string, msg *message.Message) {
 	select {
 	case <-msg.Acked():
 	case <-msg.Nacked():
-		m.postToUndeliverable(msg)
+		m.postToUndeliverable(topic, msg, "message was not acknowledged by the subscriber")
 	case <-time.After(m.Timeout):
-		m.postToUndeliverable(msg)
+		m.postToUndeliverable(topic, msg, "timed out waiting for Ack/Nack")
 	}
 }
 
-func (m *MockPubSub) postToUndeliverable(msg *message.Message) {
+func (m *MockPubSub) postToUndeliverable(topic string, msg *message.Message, reason string) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
@@ -166,5 +166,10 @@ func (m *MockPubSub) postToUndeliverable(msg *message.Message) {
 		return
 	}
 
+	msg = msg.Copy()
+
+	msg.Metadata.Set(spi.MetadataUndeliverableTopic, topic)
+	msg.Metadata.Set(spi.MetadataUndeliverableError, reason)
+
 	m.undeliverableChan <- msg
 }
@@ -8,6 +8,7 @@ package mocks
 
 import (
 	"sync"
+	"time"
 )
 
 // WitnessHandler implements a mock witness handler.
@@ -15,6 +16,7 @@ type WitnessHandler struct {
 	mutex       sync.Mutex
 	err         error
 	proof       []byte
+	delay       time.Duration
 	anchorCreds [][]byte
 }
 
@@ -37,9 +39,18 @@ func (m *WitnessHandler) WithError(err error) *WitnessHandler {
 	return m
 }
 
+// WithDelay sets the amount of time that the witness handler takes to witness an anchor credential.
+func (m *WitnessHandler) WithDelay(delay time.Duration) *WitnessHandler {
+	m.delay = delay
+
+	return m
+}
+
 // Witness adds the anchor credential to a list that can be inspected using the AnchorCreds function
 // and returns the injected proof/error.
 func (m *WitnessHandler) Witness(anchorCred []byte) ([]byte, error) {
+	time.Sleep(m.delay)
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// WitnessOfferStore implements a mock witness offer store.
+type WitnessOfferStore struct {
+	mutex           sync.Mutex
+	offers          map[string]time.Time
+	defaultLifetime time.Duration
+	err             error
+}
+
+// NewWitnessOfferStore returns a mock witness offer store.
+func NewWitnessOfferStore(defaultLifetime time.Duration) *WitnessOfferStore {
+	return &WitnessOfferStore{
+		offers:          make(map[string]time.Time),
+		defaultLifetime: defaultLifetime,
+	}
+}
+
+// WithError injects an error.
+func (m *WitnessOfferStore) WithError(err error) *WitnessOfferStore {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.err = err
+
+	return m
+}
+
+// Put stores the expiry time of the given offer and returns any injected error.
+func (m *WitnessOfferStore) Put(offerID *url.URL, endTime *time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	expiryTime := time.Now().Add(m.defaultLifetime)
+
+	if endTime != nil {
+		expiryTime = *endTime
+	}
+
+	m.offers[offerID.String()] = expiryTime
+
+	return nil
+}
+
+// GetExpiryTime returns the stored expiry time of the given offer (or a zero time if not found) and
+// returns any injected error.
+func (m *WitnessOfferStore) GetExpiryTime(offerID *url.URL) (time.Time, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return time.Time{}, m.err
+	}
+
+	return m.offers[offerID.String()], nil
+}
+
+// Delete removes the given offer, simulating the removal of an expired offer.
+func (m *WitnessOfferStore) Delete(offerID *url.URL) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.offers, offerID.String())
+}
@@ -0,0 +1,189 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outbox
+
+import (
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"go.uber.org/zap"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+// metadataDeliveryTarget is the message metadata key which holds the target inbox of a 'deliver' message.
+const metadataDeliveryTarget = "outbox-delivery-target"
+
+// deliveryPool delivers activities to inboxes using a fixed number of workers. If batching is enabled
+// (batchSize > 1) then deliveries to the same inbox are grouped so that a single worker delivers up to
+// batchSize activities back-to-back to that inbox, while the other workers deliver to other inboxes.
+// Deliveries to the same inbox are therefore not made concurrently when batching is enabled.
+type deliveryPool struct {
+	size      int
+	batchSize int
+	handle    func(msg *message.Message)
+	logger    *log.Log
+
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	pending map[string][]*message.Message
+	ready   []string
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+func newDeliveryPool(size, batchSize int, handle func(msg *message.Message), logger *log.Log) *deliveryPool {
+	p := &deliveryPool{
+		size:      size,
+		batchSize: batchSize,
+		handle:    handle,
+		logger:    logger,
+		pending:   make(map[string][]*message.Message),
+	}
+
+	p.cond = sync.NewCond(&p.mutex)
+
+	return p
+}
+
+func (p *deliveryPool) start() {
+	p.logger.Debug("Starting delivery pool", logfields.WithSize(p.size), zap.Int("batch-size", p.batchSize))
+
+	p.wg.Add(p.size)
+
+	for i := 0; i < p.size; i++ {
+		go p.work()
+	}
+}
+
+// stop stops the workers after their current deliveries complete. Any deliveries that haven't
+// started are nacked so that they are redelivered.
+func (p *deliveryPool) stop() {
+	p.mutex.Lock()
+
+	p.stopped = true
+
+	var remaining []*message.Message
+
+	for _, msgs := range p.pending {
+		remaining = append(remaining, msgs...)
+	}
+
+	p.pending = make(map[string][]*message.Message)
+	p.ready = nil
+
+	p.cond.Broadcast()
+
+	p.mutex.Unlock()
+
+	for _, msg := range remaining {
+		msg.Nack()
+	}
+
+	p.wg.Wait()
+
+	p.logger.Debug("Delivery pool stopped", logfields.WithTotal(len(remaining)))
+}
+
+// submit queues the given 'deliver' message for delivery to the given inbox.
+func (p *deliveryPool) submit(inbox string, msg *message.Message) {
+	key := inbox
+	if p.batchSize <= 1 {
+		// Deliveries aren't grouped by inbox so each message is queued on its own.
+		key = msg.UUID
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.stopped {
+		msg.Nack()
+
+		return
+	}
+
+	msgs, exists := p.pending[key]
+
+	p.pending[key] = append(msgs, msg)
+
+	if !exists {
+		// The inbox isn't queued or being delivered to so add it to the ready queue.
+		p.ready = append(p.ready, key)
+
+		p.cond.Signal()
+	}
+}
+
+func (p *deliveryPool) work() {
+	defer p.wg.Done()
+
+	for {
+		key, batch, ok := p.next()
+		if !ok {
+			return
+		}
+
+		for _, msg := range batch {
+			p.handle(msg)
+		}
+
+		p.done(key)
+	}
+}
+
+// next waits for an inbox with pending deliveries and returns up to batchSize messages for that inbox.
+// The inbox remains in the pending map (so that new deliveries to the inbox are grouped) until done is called.
+func (p *deliveryPool) next() (string, []*message.Message, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for len(p.ready) == 0 && !p.stopped {
+		p.cond.Wait()
+	}
+
+	if p.stopped {
+		return "", nil, false
+	}
+
+	key := p.ready[0]
+	p.ready = p.ready[1:]
+
+	msgs := p.pending[key]
+
+	n := p.batchSize
+	if n < 1 || n > len(msgs) {
+		n = len(msgs)
+	}
+
+	batch := msgs[:n]
+
+	p.pending[key] = msgs[n:]
+
+	return key, batch, true
+}
+
+// done is called after a batch is delivered to the given inbox. If more deliveries were queued for the
+// inbox in the meantime then the inbox is added to the end of the ready queue.
+func (p *deliveryPool) done(key string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.stopped {
+		return
+	}
+
+	if len(p.pending[key]) == 0 {
+		delete(p.pending, key)
+
+		return
+	}
+
+	p.ready = append(p.ready, key)
+
+	p.cond.Signal()
+}
@@ -0,0 +1,180 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outbox
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/logutil-go/pkg/log"
+)
+
+const (
+	inbox1 = "https://domain1.com/services/orb/inbox"
+	inbox2 = "https://domain2.com/services/orb/inbox"
+)
+
+func TestDeliveryPool(t *testing.T) {
+	logger := log.New(loggerModule)
+
+	t.Run("no batching", func(t *testing.T) {
+		h := newMockDeliveryHandler(50 * time.Millisecond)
+
+		p := newDeliveryPool(3, 0, h.handle, logger)
+		p.start()
+
+		for i := 0; i < 3; i++ {
+			p.submit(inbox1, newDeliveryMsg(inbox1))
+		}
+
+		require.Eventually(t, func() bool { return h.total() == 3 }, time.Second, 5*time.Millisecond)
+
+		p.stop()
+
+		// Without batching, deliveries to the same inbox may be made concurrently.
+		require.Equal(t, 3, h.maxConcurrentFor(inbox1))
+	})
+
+	t.Run("batching", func(t *testing.T) {
+		h := newMockDeliveryHandler(20 * time.Millisecond)
+
+		p := newDeliveryPool(2, 3, h.handle, logger)
+		p.start()
+
+		for i := 0; i < 5; i++ {
+			p.submit(inbox1, newDeliveryMsg(inbox1))
+		}
+
+		p.submit(inbox2, newDeliveryMsg(inbox2))
+
+		require.Eventually(t, func() bool { return h.total() == 6 }, time.Second, 5*time.Millisecond)
+
+		p.stop()
+
+		// Deliveries to the same inbox are grouped so they're never made concurrently.
+		require.Equal(t, 1, h.maxConcurrentFor(inbox1))
+		require.Equal(t, 1, h.maxConcurrentFor(inbox2))
+
+		// The delivery to the second inbox shouldn't have to wait for all deliveries to the first inbox.
+		require.Less(t, h.indexOf(inbox2), 5)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		h := newMockDeliveryHandler(100 * time.Millisecond)
+
+		p := newDeliveryPool(1, 1, h.handle, logger)
+		p.start()
+
+		msgs := []*message.Message{newDeliveryMsg(inbox1), newDeliveryMsg(inbox1), newDeliveryMsg(inbox1)}
+
+		for _, msg := range msgs {
+			p.submit(inbox1, msg)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		p.stop()
+
+		require.Equal(t, 1, h.total())
+
+		// Deliveries that didn't start should be nacked.
+		for _, msg := range msgs[1:] {
+			select {
+			case <-msg.Nacked():
+			default:
+				t.Fatalf("expecting message to be nacked")
+			}
+		}
+
+		// Deliveries submitted after the pool is stopped should also be nacked.
+		msg := newDeliveryMsg(inbox1)
+
+		p.submit(inbox1, msg)
+
+		select {
+		case <-msg.Nacked():
+		default:
+			t.Fatalf("expecting message to be nacked")
+		}
+	})
+}
+
+func newDeliveryMsg(inbox string) *message.Message {
+	msg := message.NewMessage(watermill.NewUUID(), nil)
+	msg.Metadata.Set(metadataDeliveryTarget, inbox)
+
+	return msg
+}
+
+type mockDeliveryHandler struct {
+	delay time.Duration
+
+	mutex         sync.Mutex
+	delivered     []string
+	concurrent    map[string]int
+	maxConcurrent map[string]int
+}
+
+func newMockDeliveryHandler(delay time.Duration) *mockDeliveryHandler {
+	return &mockDeliveryHandler{
+		delay:         delay,
+		concurrent:    make(map[string]int),
+		maxConcurrent: make(map[string]int),
+	}
+}
+
+func (m *mockDeliveryHandler) handle(msg *message.Message) {
+	inbox := msg.Metadata.Get(metadataDeliveryTarget)
+
+	m.mutex.Lock()
+	m.concurrent[inbox]++
+
+	if m.concurrent[inbox] > m.maxConcurrent[inbox] {
+		m.maxConcurrent[inbox] = m.concurrent[inbox]
+	}
+	m.mutex.Unlock()
+
+	time.Sleep(m.delay)
+
+	m.mutex.Lock()
+	m.concurrent[inbox]--
+	m.delivered = append(m.delivered, inbox)
+	m.mutex.Unlock()
+
+	msg.Ack()
+}
+
+func (m *mockDeliveryHandler) total() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return len(m.delivered)
+}
+
+func (m *mockDeliveryHandler) maxConcurrentFor(inbox string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.maxConcurrent[inbox]
+}
+
+func (m *mockDeliveryHandler) indexOf(inbox string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i, delivered := range m.delivered {
+		if delivered == inbox {
+			return i
+		}
+	}
+
+	return -1
+}
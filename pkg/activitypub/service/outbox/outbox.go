@@ -66,6 +66,14 @@ type Config struct {
 	CacheSize             int
 	CacheExpiration       time.Duration
 	SubscriberPoolSize    int
+
+	// DeliveryPoolSize is the number of workers that deliver activities to inboxes. If zero (the default)
+	// then each delivery is handled in its own goroutine.
+	DeliveryPoolSize int
+	// DeliveryBatchSize is the maximum number of activities that a worker delivers back-to-back to the same
+	// inbox. If greater than one then deliveries to the same inbox are grouped. This setting only applies
+	// if DeliveryPoolSize is set.
+	DeliveryBatchSize int
 }
 
 type activityPubClient interface {
@@ -97,6 +105,7 @@ type Outbox struct {
 	witnessesPath    string
 	logger           *log.Log
 	tracer           trace.Tracer
+	deliveryPool     *deliveryPool
 }
 
 type httpTransport interface {
@@ -143,6 +152,10 @@ func New(cnfg *Config, s store.Store, pubSub pubSub, t httpTransport, activityHa
 		tracer:           tracing.Tracer(tracing.SubsystemActivityPub),
 	}
 
+	if cfg.DeliveryPoolSize > 0 {
+		h.deliveryPool = newDeliveryPool(cfg.DeliveryPoolSize, cfg.DeliveryBatchSize, h.handle, logger)
+	}
+
 	h.Lifecycle = lifecycle.New(cfg.ServiceName,
 		lifecycle.WithStart(h.start),
 		lifecycle.WithStop(h.stop),
@@ -160,10 +173,18 @@ func New(cnfg *Config, s store.Store, pubSub pubSub, t httpTransport, activityHa
 }
 
 func (h *Outbox) start() {
+	if h.deliveryPool != nil {
+		h.deliveryPool.start()
+	}
+
 	go h.listen()
 }
 
 func (h *Outbox) stop() {
+	if h.deliveryPool != nil {
+		h.deliveryPool.stop()
+	}
+
 	h.logger.Info("Outbox stopped")
 }
 
@@ -173,6 +194,12 @@ func (h *Outbox) listen() {
 	for msg := range h.msgChan {
 		h.logger.Debug("Got new message", logfields.WithMessageID(msg.UUID), logfields.WithData(msg.Payload))
 
+		if target := msg.Metadata.Get(metadataDeliveryTarget); target != "" && h.deliveryPool != nil {
+			h.deliveryPool.submit(target, msg)
+
+			continue
+		}
+
 		go h.handle(msg)
 	}
 
@@ -448,6 +475,10 @@ func (h *Outbox) publishDeliverMessage(ctx context.Context, activity *vocab.Acti
 
 	msg := pubsub.NewMessage(ctx, msgBytes)
 
+	if target != nil {
+		msg.Metadata.Set(metadataDeliveryTarget, target.String())
+	}
+
 	h.logger.Debugc(ctx, "Publishing 'deliver' activity message to topic",
 		logfields.WithMessageID(msg.UUID), logfields.WithActivityID(activity.ID()),
 		log.WithTopic(h.Topic), logfields.WithTargetIRI(target))
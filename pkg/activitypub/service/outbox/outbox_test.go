@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package outbox
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -954,3 +955,113 @@ func (m *wellKnownResolver) ResolveHostMetaLink(uri, _ string) (string, error) {
 
 	return uri, nil
 }
+
+func TestOutbox_ConcurrentDelivery(t *testing.T) {
+	service1URL := testutil.MustParseURL("http://localhost:8002/services/service1")
+
+	followers := []*url.URL{
+		testutil.MustParseURL("http://localhost:8003/services/service2"),
+		testutil.MustParseURL("http://localhost:8003/services/service3"),
+		testutil.MustParseURL("http://localhost:8003/services/service4"),
+		testutil.MustParseURL("http://localhost:8003/services/service5"),
+	}
+
+	activityStore := memstore.New("service1")
+	apClient := mocks.NewActivitPubClient()
+
+	for _, follower := range followers {
+		require.NoError(t, activityStore.AddReference(store.Follower, service1URL, follower))
+
+		apClient.WithActor(aptestutil.NewMockService(follower))
+	}
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1URL,
+		ServiceEndpointURL: service1URL,
+		Topic:              "activities",
+		DeliveryPoolSize:   len(followers),
+	}
+
+	tp := newDelayedTransport(100 * time.Millisecond)
+
+	ob, err := New(cfg, activityStore, mocks.NewPubSub(), tp,
+		&mocks.ActivityHandler{}, apClient, &wellKnownResolver{}, &orbmocks.MetricsProvider{})
+	require.NoError(t, err)
+	require.NotNil(t, ob)
+
+	ob.Start()
+	defer ob.Stop()
+
+	activity := vocab.NewAnnounceActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("http://example.com/transactions/txn1"))),
+		vocab.WithTo(testutil.NewMockID(service1URL, resthandler.FollowersPath)),
+	)
+
+	_, err = ob.Post(context.Background(), activity)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(tp.Received()) == len(followers)
+	}, 2*time.Second, 10*time.Millisecond)
+
+	received := tp.Received()
+
+	for _, follower := range followers {
+		require.Contains(t, received, follower.String()+"/inbox")
+	}
+
+	require.Greater(t, tp.MaxConcurrent(), 1, "activities should be delivered to the followers concurrently")
+}
+
+// delayedTransport is a mock transport which records the targets of all POST requests along with the maximum
+// number of concurrent requests. Each request takes the given amount of time.
+type delayedTransport struct {
+	delay time.Duration
+
+	mutex         sync.Mutex
+	received      []string
+	concurrent    int
+	maxConcurrent int
+}
+
+func newDelayedTransport(delay time.Duration) *delayedTransport {
+	return &delayedTransport{delay: delay}
+}
+
+func (m *delayedTransport) Post(_ context.Context, req *transport.Request, _ []byte) (*http.Response, error) {
+	m.mutex.Lock()
+	m.concurrent++
+
+	if m.concurrent > m.maxConcurrent {
+		m.maxConcurrent = m.concurrent
+	}
+	m.mutex.Unlock()
+
+	time.Sleep(m.delay)
+
+	m.mutex.Lock()
+	m.concurrent--
+	m.received = append(m.received, req.URL.String())
+	m.mutex.Unlock()
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(&bytes.Buffer{})}, nil
+}
+
+func (m *delayedTransport) Get(context.Context, *transport.Request) (*http.Response, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *delayedTransport) Received() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]string{}, m.received...)
+}
+
+func (m *delayedTransport) MaxConcurrent() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.maxConcurrent
+}
@@ -57,6 +57,20 @@ type Config struct {
 	IRICacheExpiration       time.Duration
 	OutboxSubscriberPoolSize int
 	InboxSubscriberPoolSize  int
+
+	// OutboxDeliveryPoolSize is the number of workers that deliver activities to inboxes. If zero then
+	// each delivery is handled in its own goroutine.
+	OutboxDeliveryPoolSize int
+	// OutboxDeliveryBatchSize is the maximum number of activities that a worker delivers back-to-back
+	// to the same inbox.
+	OutboxDeliveryBatchSize int
+
+	// StrictActivityParsing indicates that incoming activities with unknown top-level fields should be rejected.
+	StrictActivityParsing bool
+
+	// AcceptedActivityTypes contains the types of activities accepted by the inbox. If empty then
+	// all activity types are accepted.
+	AcceptedActivityTypes []vocab.Type
 }
 
 // Service implements an ActivityPub service which has an inbox, outbox, and
@@ -94,6 +108,7 @@ type authTokenManager interface {
 
 type metricsProvider interface {
 	InboxHandlerTime(activityType string, value time.Duration)
+	InboxIncrementRejectedCount(activityType, reason string)
 	OutboxPostTime(value time.Duration)
 	OutboxResolveInboxesTime(value time.Duration)
 	OutboxIncrementActivityCount(activityType string)
@@ -111,7 +126,7 @@ func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier si
 			ServiceIRI:         cfg.ServiceIRI,
 			ServiceEndpointURL: cfg.ServiceEndpointURL,
 		},
-		activityStore, activityPubClient)
+		activityStore, activityPubClient, handlerOpts...)
 
 	ob, err := outbox.New(
 		&outbox.Config{
@@ -122,6 +137,8 @@ func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier si
 			CacheSize:          cfg.IRICacheSize,
 			CacheExpiration:    cfg.IRICacheExpiration,
 			SubscriberPoolSize: cfg.OutboxSubscriberPoolSize,
+			DeliveryPoolSize:   cfg.OutboxDeliveryPoolSize,
+			DeliveryBatchSize:  cfg.OutboxDeliveryBatchSize,
 		},
 		activityStore, pubSub,
 		t, outboxHandler, activityPubClient, resourceResolver, m,
@@ -147,9 +164,11 @@ func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier si
 			Topic:                  inboxActivitiesTopic,
 			VerifyActorInSignature: cfg.VerifyActorInSignature,
 			SubscriberPoolSize:     cfg.InboxSubscriberPoolSize,
+			StrictActivityParsing:  cfg.StrictActivityParsing,
 		},
 		activityStore, pubSub,
 		inboxHandler, sigVerifier, tm, m,
+		inbox.WithAcceptedActivityTypes(cfg.AcceptedActivityTypes),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("create inbox failed: %w", err)
@@ -177,6 +196,7 @@ func (s *Service) start() {
 
 func (s *Service) stop() {
 	s.inbox.Stop()
+	s.activityHandler.FlushAnnouncements()
 	s.outbox.Stop()
 	s.activityHandler.Stop()
 }
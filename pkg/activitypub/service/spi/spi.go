@@ -67,6 +67,16 @@ type ProofHandler interface {
 	HandleProof(ctx context.Context, witness *url.URL, anchorID string, endTime time.Time, proof []byte) error
 }
 
+// WitnessOfferStore tracks the witness 'Offer' activities posted by this service until they expire.
+type WitnessOfferStore interface {
+	// Put adds the offer with the given ID. The offer expires at the given end time or, if the end time
+	// is nil, after a default lifetime.
+	Put(offerID *url.URL, endTime *time.Time) error
+	// GetExpiryTime returns the time at which the given offer expires. A zero time is returned if the
+	// offer isn't in the store (for example, if it has expired and was already removed).
+	GetExpiryTime(offerID *url.URL) (time.Time, error)
+}
+
 // AcceptFollowHandler handles accepting follow request.
 type AcceptFollowHandler interface {
 	Accept(actor *url.URL) error
@@ -92,6 +102,9 @@ type ActivityHandler interface {
 // ErrDuplicateAnchorEvent indicates that the anchor event was already processed by the InboxHandler.
 var ErrDuplicateAnchorEvent = errors.New("anchor event already handled")
 
+// ErrWitnessOfferExpired indicates that a witness 'Offer' activity has expired and may no longer be acted upon.
+var ErrWitnessOfferExpired = errors.New("witness offer has expired")
+
 // InboxHandler defines functions for handling Create and Announce activities.
 type InboxHandler interface {
 	HandleCreateActivity(ctx context.Context, source *url.URL, create *vocab.ActivityType, announce bool) error
@@ -113,6 +126,24 @@ type Handlers struct {
 	AnchorAckHandler      AnchorEventAcknowledgementHandler
 	AcceptFollowHandler   AcceptFollowHandler
 	UndoFollowHandler     UndoFollowHandler
+	WitnessOfferStore     WitnessOfferStore
+	OutboxBatching        OutboxBatching
+}
+
+// OutboxBatching contains the parameters for coalescing the 'Announce' activities that are posted to the outbox
+// by the inbox handler. Batching is disabled if Window is zero or MaxItems is less than two.
+type OutboxBatching struct {
+	// Window is the maximum amount of time that an announcement is held before it is posted.
+	Window time.Duration
+
+	// MaxItems is the maximum number of anchor events in a single 'Announce' activity. A batch is posted
+	// as soon as it reaches this size.
+	MaxItems int
+}
+
+// Enabled returns true if batching is enabled.
+func (b OutboxBatching) Enabled() bool {
+	return b.Window > 0 && b.MaxItems > 1
 }
 
 // HandlerOpt sets a specific handler.
@@ -167,6 +198,14 @@ func WithUndoFollowHandler(handler UndoFollowHandler) HandlerOpt {
 	}
 }
 
+// WithWitnessOfferStore sets the store which tracks the witness 'Offer' activities posted by this service.
+// If set then an 'Accept' of an 'Offer' is rejected if the offer has expired or is unknown.
+func WithWitnessOfferStore(s WitnessOfferStore) HandlerOpt {
+	return func(options *Handlers) {
+		options.WitnessOfferStore = s
+	}
+}
+
 // WithAnchorEventAcknowledgementHandler sets the handler for an acknowledgement of a successful anchor event
 // that was processed by another Orb server.
 func WithAnchorEventAcknowledgementHandler(handler AnchorEventAcknowledgementHandler) HandlerOpt {
@@ -175,6 +214,40 @@ func WithAnchorEventAcknowledgementHandler(handler AnchorEventAcknowledgementHan
 	}
 }
 
+// WithOutboxBatching enables the batching of 'Announce' activities. Announcements of anchor events that are
+// received within the given window are coalesced into a single 'Announce' activity containing up to maxItems
+// anchor events. The object of the batched 'Announce' is a collection with one item per anchor event, for example:
+//
+//	{
+//	  "type": "Announce",
+//	  "object": {
+//	    "type": "Collection",
+//	    "totalItems": 2,
+//	    "items": [
+//	      {
+//	        "@context": "https://w3id.org/activityanchors/v1",
+//	        "type": "AnchorEvent",
+//	        "url": "hl:uEiDcTZUOwdcobdMl-rNYU4AJFZo1UaJelZy-5mfIgAJOYw"
+//	      },
+//	      {
+//	        "@context": "https://w3id.org/activityanchors/v1",
+//	        "type": "AnchorEvent",
+//	        "url": "hl:uEiBqQJ4hPZpWN9LNTVhbK4QgcjZk9RIj5ZYdL3C-T3vHEQ"
+//	      }
+//	    ]
+//	  },
+//	  ...
+//	}
+//
+// This is the same shape as a non-batched 'Announce' (which contains a collection with a single item), so peers
+// that don't batch are able to expand it into the individual anchor events. Batching is disabled if the window
+// is zero or maxItems is less than two, in which case an 'Announce' activity is posted for each anchor event.
+func WithOutboxBatching(window time.Duration, maxItems int) HandlerOpt {
+	return func(options *Handlers) {
+		options.OutboxBatching = OutboxBatching{Window: window, MaxItems: maxItems}
+	}
+}
+
 // AcceptList contains the URIs that are to be accepted by an authorization handler
 // for the given type. Known types are "follow" and "invite-witness".
 type AcceptList struct {
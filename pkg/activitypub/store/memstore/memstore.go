@@ -138,14 +138,10 @@ func (s *Store) queryActivitiesByRef(refType spi.ReferenceType, query *spi.Crite
 		return NewActivityIterator(nil, totalItems), nil
 	}
 
-	ait := s.activityStore.query(
-		spi.NewCriteria(spi.WithActivityIRIs(refs...)),
-		spi.WithSortOrder(options.SortOrder))
-
-	// Set 'totalItems' to the 'totalItems' returned in the original reference query, which may be based on paging.
-	ait.totalItems = totalItems
-
-	return ait, nil
+	// The activities are returned in the same order as the references (which are already sorted according to
+	// the query options). Set 'totalItems' to the 'totalItems' returned in the original reference query, which
+	// may be based on paging.
+	return NewActivityIterator(s.activityStore.getAll(refs), totalItems), nil
 }
 
 type activityStore struct {
@@ -182,6 +178,23 @@ func (s *activityStore) get(activityID string) (*vocab.ActivityType, error) {
 	return a, nil
 }
 
+// getAll returns the activities for the given IRIs in the same order as the IRIs. IRIs for which
+// no activity is found are ignored.
+func (s *activityStore) getAll(activityIRIs []*url.URL) []*vocab.ActivityType {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var activities []*vocab.ActivityType
+
+	for _, iri := range activityIRIs {
+		if a, ok := s.activityByID[iri.String()]; ok {
+			activities = append(activities, a)
+		}
+	}
+
+	return activities
+}
+
 func (s *activityStore) query(query *spi.Criteria, opts ...spi.QueryOpt) *ActivityIterator {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -78,6 +78,66 @@ func TestStore_Activity(t *testing.T) {
 	})
 }
 
+func TestStore_QueryActivitiesByReferenceSortOrder(t *testing.T) {
+	s := New("service1")
+	require.NotNil(t, s)
+
+	objectIRI := testutil.MustParseURL("https://example.com/transactions/txn1")
+
+	var activityIDs []*url.URL
+
+	for i := 0; i < 5; i++ {
+		activityIDs = append(activityIDs, testutil.MustParseURL(fmt.Sprintf("https://example.com/activities/%d", i)))
+	}
+
+	// Add the activities in the reverse order of the references to ensure that the results
+	// are sorted according to the reference index and not the order in which the activities were stored.
+	for i := len(activityIDs) - 1; i >= 0; i-- {
+		require.NoError(t, s.AddActivity(vocab.NewAnnounceActivity(vocab.NewObjectProperty(),
+			vocab.WithID(activityIDs[i]))))
+	}
+
+	for _, refType := range []spi.ReferenceType{spi.Share, spi.Like} {
+		for _, activityID := range activityIDs {
+			require.NoError(t, s.AddReference(refType, objectIRI, activityID))
+		}
+
+		criteria := spi.NewCriteria(spi.WithReferenceType(refType), spi.WithObjectIRI(objectIRI))
+
+		t.Run(fmt.Sprintf("%s ascending", refType), func(t *testing.T) {
+			it, err := s.QueryActivities(criteria, spi.WithSortOrder(spi.SortAscending))
+			require.NoError(t, err)
+
+			checkOrderedQueryResults(t, it, activityIDs...)
+
+			it, err = s.QueryActivities(criteria, spi.WithSortOrder(spi.SortAscending),
+				spi.WithPageSize(2), spi.WithPageNum(1))
+			require.NoError(t, err)
+
+			checkOrderedQueryResults(t, it, activityIDs[2], activityIDs[3])
+		})
+
+		t.Run(fmt.Sprintf("%s descending", refType), func(t *testing.T) {
+			it, err := s.QueryActivities(criteria, spi.WithSortOrder(spi.SortDescending))
+			require.NoError(t, err)
+
+			checkOrderedQueryResults(t, it, activityIDs[4], activityIDs[3], activityIDs[2], activityIDs[1], activityIDs[0])
+		})
+	}
+
+	t.Run("Liked ascending", func(t *testing.T) {
+		for _, activityID := range activityIDs {
+			require.NoError(t, s.AddReference(spi.Liked, objectIRI, activityID))
+		}
+
+		it, err := s.QueryReferences(spi.Liked, spi.NewCriteria(spi.WithObjectIRI(objectIRI)),
+			spi.WithSortOrder(spi.SortAscending), spi.WithPageSize(2), spi.WithPageNum(2))
+		require.NoError(t, err)
+
+		checkRefQueryResults(t, it, activityIDs[4])
+	})
+}
+
 func TestStore_Reference(t *testing.T) {
 	s := New("service1")
 	require.NotNil(t, s)
@@ -178,6 +238,23 @@ func checkQueryResults(t *testing.T, it spi.ActivityIterator, expectedTypes ...*
 	require.Nil(t, a)
 }
 
+func checkOrderedQueryResults(t *testing.T, it spi.ActivityIterator, expectedIDs ...*url.URL) {
+	t.Helper()
+
+	require.NotNil(t, it)
+
+	for _, expectedID := range expectedIDs {
+		a, err := it.Next()
+		require.NoError(t, err)
+		require.Equal(t, expectedID.String(), a.ID().String())
+	}
+
+	a, err := it.Next()
+	require.Error(t, err)
+	require.True(t, errors.Is(err, spi.ErrNotFound))
+	require.Nil(t, a)
+}
+
 func checkRefQueryResults(t *testing.T, it spi.ReferenceIterator, expectedIRIs ...*url.URL) {
 	t.Helper()
 
@@ -7,7 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package vocab
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/url"
+	"sort"
+	"strings"
 )
 
 // ActivityType defines an 'activity'.
@@ -78,6 +83,98 @@ func (t *ActivityType) UnmarshalJSON(bytes []byte) error {
 	return UnmarshalJSON(bytes, t.ObjectType, t.activity)
 }
 
+// ParseOpt sets an option for parsing an ActivityPub document.
+type ParseOpt func(opts *parseOptions)
+
+type parseOptions struct {
+	strict bool
+}
+
+// WithStrictActivityParsing indicates whether or not an activity containing unknown top-level fields
+// should be rejected. Strict parsing is disabled by default.
+func WithStrictActivityParsing(strict bool) ParseOpt {
+	return func(opts *parseOptions) {
+		opts.strict = strict
+	}
+}
+
+// UnmarshalActivity unmarshals the given bytes to an activity. If strict parsing is enabled then an error
+// is returned if the activity contains a top-level field that isn't defined by the activity vocabulary.
+// JSON-LD keywords and fields that are expressed as an absolute or compact IRI are always allowed since
+// these may be defined by a JSON-LD context.
+func UnmarshalActivity(raw []byte, opts ...ParseOpt) (*ActivityType, error) {
+	options := &parseOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.strict {
+		if err := checkActivityFields(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	activity := &ActivityType{}
+
+	err := json.Unmarshal(raw, activity)
+	if err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+func checkActivityFields(raw []byte) error {
+	var fields map[string]json.RawMessage
+
+	err := json.Unmarshal(raw, &fields)
+	if err != nil {
+		return err
+	}
+
+	known := activityProperties()
+
+	var unknown []string
+
+	for field := range fields {
+		if _, ok := known[field]; ok || isExtensionProperty(field) {
+			continue
+		}
+
+		unknown = append(unknown, field)
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+
+		return fmt.Errorf("activity contains unknown fields %s", unknown)
+	}
+
+	return nil
+}
+
+func activityProperties() map[string]struct{} {
+	properties := make(map[string]struct{})
+
+	for _, prop := range []string{
+		propertyContext, propertyID, propertyURL, propertyType, propertyTo, propertyPublished,
+		propertyStartTime, propertyEndTime, propertyCID, propertyInReplyTo, propertyAttachment,
+		propertyAttributedTo, propertyGenerator, propertyTag, propertyActor, propertyTarget,
+		propertyObject, propertyResult,
+	} {
+		properties[prop] = struct{}{}
+	}
+
+	return properties
+}
+
+// isExtensionProperty returns true if the given field is a JSON-LD keyword (e.g. "@id") or an absolute
+// or compact IRI (e.g. "https://example.com/vocab#prop" or "ex:prop").
+func isExtensionProperty(field string) bool {
+	return strings.HasPrefix(field, "@") || strings.Contains(field, ":")
+}
+
 // NewCreateActivity returns a new 'Create' activity.
 func NewCreateActivity(obj *ObjectProperty, opts ...Opt) *ActivityType {
 	options := NewOptions(opts...)
@@ -232,6 +329,48 @@ func NewOfferActivity(obj *ObjectProperty, opts ...Opt) *ActivityType {
 	}
 }
 
+// NewWitnessOfferActivity returns a new 'Offer' activity which requests that the given anchor be witnessed.
+// An error is returned if the anchor or the recipients ('to') are missing or if the 'endTime' is
+// before the 'startTime'. If no target is provided then the target defaults to AnchorWitnessTargetIRI.
+func NewWitnessOfferActivity(anchor *ObjectProperty, opts ...Opt) (*ActivityType, error) {
+	if isEmptyObjectProperty(anchor) {
+		return nil, errors.New("anchor object is required for 'Offer' activity")
+	}
+
+	options := NewOptions(opts...)
+
+	if len(options.To) == 0 {
+		return nil, errors.New("at least one recipient ('to') is required for 'Offer' activity")
+	}
+
+	if options.StartTime != nil && options.EndTime != nil && options.EndTime.Before(*options.StartTime) {
+		return nil, errors.New("endTime must not be before startTime for 'Offer' activity")
+	}
+
+	if options.Target == nil {
+		opts = append(opts, WithTarget(NewObjectProperty(WithIRI(AnchorWitnessTargetIRI))))
+	}
+
+	return NewOfferActivity(anchor, opts...), nil
+}
+
+// NewInviteWitnessActivity returns a new 'Invite' activity which invites the given actor to be a witness.
+// An error is returned if the target actor is not provided.
+func NewInviteWitnessActivity(targetActor *url.URL, opts ...Opt) (*ActivityType, error) {
+	if targetActor == nil {
+		return nil, errors.New("target actor is required for 'Invite' witness activity")
+	}
+
+	return NewInviteActivity(
+		NewObjectProperty(WithIRI(AnchorWitnessTargetIRI)),
+		append(opts, WithTarget(NewObjectProperty(WithIRI(targetActor))))...,
+	), nil
+}
+
+func isEmptyObjectProperty(p *ObjectProperty) bool {
+	return p == nil || (p.IRI() == nil && p.Document() == nil && p.Object() == nil && p.AnchorEvent() == nil)
+}
+
 // NewUndoActivity returns a new 'Undo' activity.
 func NewUndoActivity(obj *ObjectProperty, opts ...Opt) *ActivityType {
 	options := NewOptions(opts...)
@@ -665,6 +665,111 @@ func TestOfferTypeMarshal(t *testing.T) {
 	})
 }
 
+func TestNewWitnessOfferActivity(t *testing.T) {
+	to := newMockID(service1, "/witnesses")
+
+	startTime := getStaticTime()
+	endTime := startTime.Add(1 * time.Minute)
+
+	anchorLinksetDoc, err := UnmarshalToDoc([]byte(anchorLinksetJSON))
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		offer, err := NewWitnessOfferActivity(
+			NewObjectProperty(WithDocument(anchorLinksetDoc)),
+			WithID(offerActivityID),
+			WithActor(service1),
+			WithTo(to, PublicIRI),
+			WithStartTime(&startTime),
+			WithEndTime(&endTime),
+		)
+		require.NoError(t, err)
+
+		bytes, err := json.Marshal(offer)
+		require.NoError(t, err)
+
+		a := &ActivityType{}
+		require.NoError(t, json.Unmarshal(bytes, a))
+		require.True(t, a.Type().Is(TypeOffer))
+		require.Equal(t, offerActivityID.String(), a.ID().String())
+		require.Equal(t, service1.String(), a.Actor().String())
+		require.Len(t, a.To(), 2)
+		require.NotNil(t, a.Object().Document())
+		require.NotNil(t, a.Target())
+		require.Equal(t, AnchorWitnessTargetIRI.String(), a.Target().IRI().String())
+		require.Equal(t, startTime, *a.StartTime())
+		require.Equal(t, endTime, *a.EndTime())
+	})
+
+	t.Run("success - no end time", func(t *testing.T) {
+		offer, err := NewWitnessOfferActivity(
+			NewObjectProperty(WithIRI(anchorEventURL1)),
+			WithTo(to),
+		)
+		require.NoError(t, err)
+		require.Nil(t, offer.EndTime())
+		require.Equal(t, AnchorWitnessTargetIRI.String(), offer.Target().IRI().String())
+	})
+
+	t.Run("error - missing anchor", func(t *testing.T) {
+		offer, err := NewWitnessOfferActivity(nil, WithTo(to))
+		require.EqualError(t, err, "anchor object is required for 'Offer' activity")
+		require.Nil(t, offer)
+
+		offer, err = NewWitnessOfferActivity(NewObjectProperty(), WithTo(to))
+		require.EqualError(t, err, "anchor object is required for 'Offer' activity")
+		require.Nil(t, offer)
+	})
+
+	t.Run("error - missing recipients", func(t *testing.T) {
+		offer, err := NewWitnessOfferActivity(NewObjectProperty(WithDocument(anchorLinksetDoc)))
+		require.EqualError(t, err, "at least one recipient ('to') is required for 'Offer' activity")
+		require.Nil(t, offer)
+	})
+
+	t.Run("error - end time before start time", func(t *testing.T) {
+		offer, err := NewWitnessOfferActivity(
+			NewObjectProperty(WithDocument(anchorLinksetDoc)),
+			WithTo(to),
+			WithStartTime(&endTime),
+			WithEndTime(&startTime),
+		)
+		require.EqualError(t, err, "endTime must not be before startTime for 'Offer' activity")
+		require.Nil(t, offer)
+	})
+}
+
+func TestNewInviteWitnessActivity(t *testing.T) {
+	org1Service := testutil.MustParseURL("https://org1.com/services/service1")
+	org2Service := testutil.MustParseURL("https://org1.com/services/service2")
+
+	t.Run("success", func(t *testing.T) {
+		invite, err := NewInviteWitnessActivity(org2Service,
+			WithID(witnessActivityID),
+			WithActor(org1Service),
+			WithTo(org2Service),
+		)
+		require.NoError(t, err)
+
+		bytes, err := canonicalizer.MarshalCanonical(invite)
+		require.NoError(t, err)
+
+		require.Equal(t, testutil.GetCanonical(t, jsonInviteWitness), string(bytes))
+
+		a := &ActivityType{}
+		require.NoError(t, json.Unmarshal(bytes, a))
+		require.True(t, a.Type().Is(TypeInvite))
+		require.Equal(t, AnchorWitnessTargetIRI.String(), a.Object().IRI().String())
+		require.Equal(t, org2Service.String(), a.Target().IRI().String())
+	})
+
+	t.Run("error - missing target actor", func(t *testing.T) {
+		invite, err := NewInviteWitnessActivity(nil, WithActor(org1Service))
+		require.EqualError(t, err, "target actor is required for 'Invite' witness activity")
+		require.Nil(t, invite)
+	})
+}
+
 func TestLikeTypeMarshal(t *testing.T) {
 	actor := testutil.MustParseURL("https://witness1.example.com/services/orb")
 	ref := testutil.MustParseURL("hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElrNQ:uoQ-CeE1odHRwczovL3NhbG" +
@@ -807,6 +912,38 @@ func TestActivityType_Accessors(t *testing.T) {
 	require.Nil(t, a.To())
 }
 
+func TestUnmarshalActivity(t *testing.T) {
+	t.Run("lenient (default)", func(t *testing.T) {
+		a, err := UnmarshalActivity([]byte(jsonFollowWithUnknownFields))
+		require.NoError(t, err)
+		require.True(t, a.Type().Is(TypeFollow))
+		require.Equal(t, "https://org1.com/services/service1", a.Actor().String())
+	})
+
+	t.Run("strict -> success", func(t *testing.T) {
+		for _, raw := range []string{jsonCreate, jsonFollow, jsonAccept, jsonFollowWithExtensionFields} {
+			a, err := UnmarshalActivity([]byte(raw), WithStrictActivityParsing(true))
+			require.NoError(t, err)
+			require.NotNil(t, a)
+		}
+	})
+
+	t.Run("strict -> unknown fields", func(t *testing.T) {
+		a, err := UnmarshalActivity([]byte(jsonFollowWithUnknownFields), WithStrictActivityParsing(true))
+		require.Error(t, err)
+		require.Nil(t, a)
+		require.Contains(t, err.Error(), "activity contains unknown fields [extra1 extra2]")
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := UnmarshalActivity([]byte("{"))
+		require.Error(t, err)
+
+		_, err = UnmarshalActivity([]byte("{"), WithStrictActivityParsing(true))
+		require.Error(t, err)
+	})
+}
+
 func newMockID(serviceIRI fmt.Stringer, path string) *url.URL {
 	return testutil.MustParseURL(fmt.Sprintf("%s%s", serviceIRI, path))
 }
@@ -943,6 +1080,28 @@ const (
 	 "object": "https://org1.com/services/service2"
 	}`
 
+	jsonFollowWithUnknownFields = `{
+	 "@context": "https://www.w3.org/ns/activitystreams",
+	 "id": "https://sally.example.com/services/orb/activities/97b3d005-abb6-422d-a889-18bc1ee84988",
+	 "type": "Follow",
+	 "actor": "https://org1.com/services/service1",
+	 "to": "https://org1.com/services/service2",
+	 "object": "https://org1.com/services/service2",
+	 "extra2": "value2",
+	 "extra1": "value1"
+	}`
+
+	jsonFollowWithExtensionFields = `{
+	 "@context": "https://www.w3.org/ns/activitystreams",
+	 "@id": "https://sally.example.com/services/orb/activities/97b3d005-abb6-422d-a889-18bc1ee84988",
+	 "type": "Follow",
+	 "actor": "https://org1.com/services/service1",
+	 "to": "https://org1.com/services/service2",
+	 "object": "https://org1.com/services/service2",
+	 "ex:note": "value1",
+	 "https://example.com/vocab#note": "value2"
+	}`
+
 	jsonAccept = `{
     "@context": "https://www.w3.org/ns/activitystreams",
     "id": "https://sally.example.com/services/orb/activities/95b3d005-abb6-423d-a889-18bc1ee84989",
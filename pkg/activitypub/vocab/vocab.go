@@ -103,6 +103,9 @@ const (
 	propertyAttachment   = "attachment"
 	propertyIndex        = "index"
 	propertyParent       = "parent"
+	propertyCID          = "cid"
+	propertyGenerator    = "generator"
+	propertyTag          = "tag"
 )
 
 // MediaType defines a type of encoding for content embedded within a document.
@@ -0,0 +1,232 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+var logger = log.New("anchor-import-rest", log.WithFields(logfields.WithServiceEndpoint(importPath)))
+
+const (
+	importPath                  = "/anchors/import"
+	internalServerErrorResponse = "Internal Server Error.\n"
+
+	// MaxAnchorSize is the maximum size (in bytes) of an anchor linkset that may be imported.
+	MaxAnchorSize = 10 * 1024 * 1024
+)
+
+// Status is the outcome of an anchor import.
+type Status string
+
+const (
+	// StatusImported indicates that the anchor was stored in the local CAS and published for processing.
+	StatusImported Status = "imported"
+	// StatusSkipped indicates that the anchor has already been processed (or processing is pending).
+	StatusSkipped Status = "skipped"
+	// StatusRejected indicates that the anchor is invalid.
+	StatusRejected Status = "rejected"
+)
+
+// Result is the response of an anchor import request.
+type Result struct {
+	Hashlink string `json:"hashlink,omitempty"`
+	Status   Status `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type anchorEventHandler interface {
+	HandleAnchorEvent(ctx context.Context, actor, anchorRef, source *url.URL, anchorEvent *vocab.AnchorEventType) error
+}
+
+type anchorLinkStore interface {
+	GetProcessedAndPendingLinks(anchorHash string) ([]*url.URL, error)
+}
+
+// Handler implements a REST handler which imports an anchor linkset that was produced by another system
+// (e.g. during a migration) so that the anchor is processed without being re-anchored. The anchor linkset
+// in the request body is handed to the anchor event handler, which validates the anchor (and its parents)
+// in the same way as an anchor that's announced by another server, stores it in the local CAS and publishes
+// it to the observer.
+type Handler struct {
+	anchorEventHandler anchorEventHandler
+	anchorLinkStore    anchorLinkStore
+	hl                 *hashlink.HashLink
+	marshal            func(v interface{}) ([]byte, error)
+}
+
+// New returns a new anchor import REST handler.
+func New(anchorEventHandler anchorEventHandler, anchorLinkStore anchorLinkStore) *Handler {
+	return &Handler{
+		anchorEventHandler: anchorEventHandler,
+		anchorLinkStore:    anchorLinkStore,
+		hl:                 hashlink.New(),
+		marshal:            json.Marshal,
+	}
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Handler) Method() string {
+	return http.MethodPost
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *Handler) Path() string {
+	return importPath
+}
+
+// Handler returns the handler that should be invoked when an HTTP POST is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handlePost
+}
+
+func (h *Handler) handlePost(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := io.ReadAll(http.MaxBytesReader(w, req.Body, MaxAnchorSize))
+	if err != nil {
+		logger.Info("Error reading request body", log.WithError(err))
+
+		h.writeResult(w, &Result{Status: StatusRejected, Reason: fmt.Sprintf("read anchor: %s", err)})
+
+		return
+	}
+
+	result, err := h.importAnchor(req.Context(), reqBytes)
+	if err != nil {
+		logger.Error("Error importing anchor", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeResult(w, result)
+}
+
+// importAnchor imports the given anchor linkset. An error is returned only if the import failed due to a
+// (possibly transient) server error. An invalid anchor results in a rejected status.
+func (h *Handler) importAnchor(ctx context.Context, anchorBytes []byte) (*Result, error) {
+	anchorLinksetBytes, anchorEvent, err := h.parseAnchor(anchorBytes)
+	if err != nil {
+		logger.Info("Rejected anchor", log.WithError(err))
+
+		return &Result{Status: StatusRejected, Reason: err.Error()}, nil
+	}
+
+	hash, err := h.hl.CreateResourceHash(anchorLinksetBytes)
+	if err != nil {
+		return nil, fmt.Errorf("create resource hash: %w", err)
+	}
+
+	anchorRef := hashlink.GetHashLinkFromResourceHash(hash)
+
+	links, err := h.anchorLinkStore.GetProcessedAndPendingLinks(hash)
+	if err != nil {
+		return nil, fmt.Errorf("get processed and pending links for [%s]: %w", anchorRef, err)
+	}
+
+	if len(links) > 0 {
+		logger.Info("Anchor was already processed or processing is pending", logfields.WithAnchorURIString(anchorRef))
+
+		return &Result{Hashlink: anchorRef, Status: StatusSkipped}, nil
+	}
+
+	anchorRefURL, err := url.Parse(anchorRef)
+	if err != nil {
+		return nil, fmt.Errorf("parse hashlink [%s]: %w", anchorRef, err)
+	}
+
+	err = h.anchorEventHandler.HandleAnchorEvent(ctx, nil, anchorRefURL, nil, anchorEvent)
+	if err != nil {
+		if orberrors.IsTransient(err) {
+			return nil, fmt.Errorf("handle anchor [%s]: %w", anchorRef, err)
+		}
+
+		logger.Info("Rejected anchor", logfields.WithAnchorURIString(anchorRef), log.WithError(err))
+
+		return &Result{Hashlink: anchorRef, Status: StatusRejected, Reason: err.Error()}, nil
+	}
+
+	logger.Info("Imported anchor", logfields.WithAnchorURIString(anchorRef))
+
+	return &Result{Hashlink: anchorRef, Status: StatusImported}, nil
+}
+
+// parseAnchor parses and validates the given anchor linkset and returns the canonicalized anchor linkset
+// along with an anchor event which contains the anchor linkset.
+func (h *Handler) parseAnchor(anchorBytes []byte) ([]byte, *vocab.AnchorEventType, error) {
+	anchorLinksetBytes, err := canonicalizer.MarshalCanonical(anchorBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("canonicalize anchor: %w", err)
+	}
+
+	anchorLinkset := &linkset.Linkset{}
+
+	err = json.Unmarshal(anchorLinksetBytes, anchorLinkset)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshal anchor linkset: %w", err)
+	}
+
+	anchorLink := anchorLinkset.Link()
+	if anchorLink == nil {
+		return nil, nil, fmt.Errorf("anchor linkset is empty")
+	}
+
+	err = anchorLink.Validate()
+	if err != nil {
+		return nil, nil, fmt.Errorf("validate anchor link: %w", err)
+	}
+
+	anchorLinksetDoc, err := vocab.UnmarshalToDoc(anchorLinksetBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshal anchor linkset: %w", err)
+	}
+
+	return anchorLinksetBytes, vocab.NewAnchorEvent(vocab.NewObjectProperty(vocab.WithDocument(anchorLinksetDoc))), nil
+}
+
+func (h *Handler) writeResult(w http.ResponseWriter, result *Result) {
+	resultBytes, err := h.marshal(result)
+	if err != nil {
+		logger.Error("Error marshalling import result", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, resultBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			log.WriteResponseBodyError(logger, err)
+
+			return
+		}
+
+		log.WroteResponse(logger, body)
+	}
+}
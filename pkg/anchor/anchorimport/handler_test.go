@@ -0,0 +1,197 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	handlermocks "github.com/trustbloc/orb/pkg/anchor/handler/mocks"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const importURL = "https://example.com/anchors/import"
+
+func TestNew(t *testing.T) {
+	h := New(mocks.NewAnchorEventHandler(), &handlermocks.AnchorLinkStore{})
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.Equal(t, "/anchors/import", h.Path())
+}
+
+func TestHandler_Import(t *testing.T) {
+	expectedHL := getHashlink(t, sampleAnchorLinkset)
+
+	t.Run("Imported", func(t *testing.T) {
+		anchorEventHandler := mocks.NewAnchorEventHandler()
+
+		h := New(anchorEventHandler, &handlermocks.AnchorLinkStore{})
+
+		result := handleImport(t, h, []byte(sampleAnchorLinkset), http.StatusOK)
+		require.Equal(t, StatusImported, result.Status)
+		require.Equal(t, expectedHL, result.Hashlink)
+		require.Empty(t, result.Reason)
+
+		anchorEvent, ok := anchorEventHandler.AnchorEvent(expectedHL)
+		require.True(t, ok)
+		require.NotNil(t, anchorEvent.Object().Document())
+	})
+
+	t.Run("Skipped", func(t *testing.T) {
+		anchorEventHandler := mocks.NewAnchorEventHandler()
+
+		anchorLinkStore := &handlermocks.AnchorLinkStore{}
+		anchorLinkStore.GetProcessedAndPendingLinksReturns([]*url.URL{testutil.MustParseURL(expectedHL)}, nil)
+
+		h := New(anchorEventHandler, anchorLinkStore)
+
+		result := handleImport(t, h, []byte(sampleAnchorLinkset), http.StatusOK)
+		require.Equal(t, StatusSkipped, result.Status)
+		require.Equal(t, expectedHL, result.Hashlink)
+
+		_, ok := anchorEventHandler.AnchorEvent(expectedHL)
+		require.False(t, ok)
+	})
+
+	t.Run("Invalid JSON -> rejected", func(t *testing.T) {
+		h := New(mocks.NewAnchorEventHandler(), &handlermocks.AnchorLinkStore{})
+
+		result := handleImport(t, h, []byte("{"), http.StatusOK)
+		require.Equal(t, StatusRejected, result.Status)
+		require.Contains(t, result.Reason, "canonicalize anchor")
+	})
+
+	t.Run("Empty linkset -> rejected", func(t *testing.T) {
+		h := New(mocks.NewAnchorEventHandler(), &handlermocks.AnchorLinkStore{})
+
+		result := handleImport(t, h, []byte(`{"linkset":[]}`), http.StatusOK)
+		require.Equal(t, StatusRejected, result.Status)
+		require.Contains(t, result.Reason, "anchor linkset is empty")
+	})
+
+	t.Run("Invalid anchor link -> rejected", func(t *testing.T) {
+		h := New(mocks.NewAnchorEventHandler(), &handlermocks.AnchorLinkStore{})
+
+		result := handleImport(t, h, []byte(`{"linkset":[{"anchor":"hl:uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg"}]}`),
+			http.StatusOK)
+		require.Equal(t, StatusRejected, result.Status)
+		require.Contains(t, result.Reason, "validate anchor link")
+	})
+
+	t.Run("Anchor event handler error -> rejected", func(t *testing.T) {
+		h := New(mocks.NewAnchorEventHandler().WithError(errors.New("invalid credential")),
+			&handlermocks.AnchorLinkStore{})
+
+		result := handleImport(t, h, []byte(sampleAnchorLinkset), http.StatusOK)
+		require.Equal(t, StatusRejected, result.Status)
+		require.Equal(t, expectedHL, result.Hashlink)
+		require.Contains(t, result.Reason, "invalid credential")
+	})
+
+	t.Run("Anchor event handler transient error -> internal server error", func(t *testing.T) {
+		h := New(mocks.NewAnchorEventHandler().WithError(orberrors.NewTransient(errors.New("injected error"))),
+			&handlermocks.AnchorLinkStore{})
+
+		handleImport(t, h, []byte(sampleAnchorLinkset), http.StatusInternalServerError)
+	})
+
+	t.Run("Anchor link store error -> internal server error", func(t *testing.T) {
+		anchorLinkStore := &handlermocks.AnchorLinkStore{}
+		anchorLinkStore.GetProcessedAndPendingLinksReturns(nil, errors.New("injected store error"))
+
+		h := New(mocks.NewAnchorEventHandler(), anchorLinkStore)
+
+		handleImport(t, h, []byte(sampleAnchorLinkset), http.StatusInternalServerError)
+	})
+
+	t.Run("Marshal error -> internal server error", func(t *testing.T) {
+		h := New(mocks.NewAnchorEventHandler(), &handlermocks.AnchorLinkStore{})
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		handleImport(t, h, []byte(sampleAnchorLinkset), http.StatusInternalServerError)
+	})
+}
+
+func handleImport(t *testing.T, h *Handler, anchorBytes []byte, expectedStatus int) *Result {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handlePost(rw, httptest.NewRequest(http.MethodPost, importURL, bytes.NewBuffer(anchorBytes)))
+
+	resp := rw.Result()
+	require.Equal(t, expectedStatus, resp.StatusCode)
+
+	respBytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	if expectedStatus != http.StatusOK {
+		return nil
+	}
+
+	result := &Result{}
+	require.NoError(t, json.Unmarshal(respBytes, result))
+
+	return result
+}
+
+func getHashlink(t *testing.T, anchorLinkset string) string {
+	t.Helper()
+
+	hash, err := hashlink.New().CreateResourceHash([]byte(testutil.GetCanonical(t, anchorLinkset)))
+	require.NoError(t, err)
+
+	return hashlink.GetHashLinkFromResourceHash(hash)
+}
+
+const sampleAnchorLinkset = `{
+  "linkset": [
+    {
+      "anchor": "hl:uEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg",
+      "author": [
+        {
+          "href": "https://orb.domain1.com/services/orb"
+        }
+      ],
+      "original": [
+        {
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiCKkDb0aQhWNudvelroKLnBqEMORXuOeQqI_mYeVhGkpQ%22%2C%22author%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Forb.domain1.com%2Fservices%2Forb%22%7D%5D%2C%22item%22%3A%5B%7B%22href%22%3A%22did%3Aorb%3AuAAA%3AEiAbvz2BZUmsqc2ZO5Fzhd04kCeuy31fzbZxH4Em_0RZ9Q%22%7D%5D%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%7D%5D%7D",
+          "type": "application/linkset+json"
+        }
+      ],
+      "profile": [
+        {
+          "href": "https://w3id.org/orb#v0"
+        }
+      ],
+      "related": [
+        {
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg%22%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%2C%22via%22%3A%5B%7B%22href%22%3A%22hl%3AuEiCKkDb0aQhWNudvelroKLnBqEMORXuOeQqI_mYeVhGkpQ%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQ0trRGIwYVFoV051ZHZlbHJvS0xuQnFFTU9SWHVPZVFxSV9tWWVWaEdrcFF4QmlwZnM6Ly9iYWZrcmVpZWtzYTNwaTJpaWt5M29vMzMybGx1Y3Jvb2J2YmJxNHJsM3J6NHF2Y2g2bXlwZm1lbmV1dQ%22%7D%5D%7D%5D%7D",
+          "type": "application/linkset+json"
+        }
+      ],
+      "replies": [
+        {
+          "href": "data:application/json,%7B%22%40context%22%3A%5B%22https%3A%2F%2Fwww.w3.org%2F2018%2Fcredentials%2Fv1%22%2C%22https%3A%2F%2Fw3id.org%2Factivityanchors%2Fv1%22%2C%22https%3A%2F%2Fw3id.org%2Fsecurity%2Fsuites%2Fjws-2020%2Fv1%22%2C%22https%3A%2F%2Fw3id.org%2Fsecurity%2Fsuites%2Fed25519-2020%2Fv1%22%5D%2C%22credentialSubject%22%3A%7B%22anchor%22%3A%22hl%3AuEiCKkDb0aQhWNudvelroKLnBqEMORXuOeQqI_mYeVhGkpQ%22%2C%22href%22%3A%22hl%3AuEiCPVDy4aJ4jCaTKzVbnIR99LC5F4cGBolxq6yYXUjrNfg%22%2C%22profile%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%2C%22rel%22%3A%22linkset%22%2C%22type%22%3A%5B%22AnchorLink%22%5D%7D%2C%22id%22%3A%22https%3A%2F%2Forb2.domain1.com%2Fvc%2F19148c22-9088-4652-bcfa-fcea1279f072%22%2C%22issuanceDate%22%3A%222022-08-25T20%3A09%3A09.480315917Z%22%2C%22issuer%22%3A%22https%3A%2F%2Forb2.domain1.com%22%2C%22proof%22%3A%5B%7B%22created%22%3A%222022-08-25T20%3A09%3A09.52Z%22%2C%22domain%22%3A%22http%3A%2F%2Forb.vct%3A8077%2Fmaple2020%22%2C%22proofPurpose%22%3A%22assertionMethod%22%2C%22proofValue%22%3A%22zjJsKS1B4PrVfrQrsE6JRdWmDpjZosDvT4qk3b7wSpnVfaEk5w6iCu7PwXBQd7QzG9VEYkUTD9sUdCF7VfSEupV7%22%2C%22type%22%3A%22Ed25519Signature2020%22%2C%22verificationMethod%22%3A%22did%3Aweb%3Aorb.domain1.com%2375MDi94rVaJ69DRwHLwaCxBVg-wdEuBKwzgNgyoMbcc%22%7D%2C%7B%22created%22%3A%222022-08-25T20%3A09%3A09.715076709Z%22%2C%22domain%22%3A%22https%3A%2F%2Forb.domain2.com%22%2C%22proofPurpose%22%3A%22assertionMethod%22%2C%22proofValue%22%3A%22z5pJumaR6o4v7cZudXsBQx8NYh4SEJSFzBGNj92cAw7jEUqoTAypHsECGAiRU6TXqSeU2D5azChjXpmkcCNGsBwam%22%2C%22type%22%3A%22Ed25519Signature2020%22%2C%22verificationMethod%22%3A%22did%3Aweb%3Aorb.domain2.com%23LfX08Wr74EkPSoG7CoB3S4OuSrX3LM-_Yd0BvfSonLQ%22%7D%5D%2C%22type%22%3A%5B%22VerifiableCredential%22%2C%22AnchorCredential%22%5D%7D",
+          "type": "application/ld+json"
+        }
+      ]
+    }
+  ]
+}`
@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package anchorlinkset
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
+	"sort"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/trustbloc/logutil-go/pkg/log"
@@ -21,18 +23,51 @@ import (
 	"github.com/trustbloc/orb/pkg/datauri"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/linkset"
+	"github.com/trustbloc/orb/pkg/vcsigner"
 )
 
 var logger = log.New("anchorevent")
 
+// ErrMaxPreviousAnchorsExceeded is returned by the builder when the number of previous anchors (i.e. operations)
+// in a payload exceeds the configured maximum.
+var ErrMaxPreviousAnchorsExceeded = errors.New("maximum number of previous anchors exceeded")
+
 // Builder constructs an anchor linkset.
 type Builder struct {
-	registry generatorRegistry
+	registry           generatorRegistry
+	dataURIOpts        []datauri.Opt
+	maxPreviousAnchors int
+}
+
+// Opt is an anchor linkset builder option.
+type Opt func(b *Builder)
+
+// WithDataURIAutoThreshold sets the size (in bytes) at or above which the content of a data URI is compressed
+// when the data URI media type is datauri.MediaTypeDataURIAuto. The default is datauri.DefaultAutoThreshold.
+func WithDataURIAutoThreshold(value int) Opt {
+	return func(b *Builder) {
+		b.dataURIOpts = append(b.dataURIOpts, datauri.WithAutoThreshold(value))
+	}
+}
+
+// WithMaxPreviousAnchors sets the maximum number of previous anchors (i.e. operations) that may be included
+// in a single anchor linkset. A value of zero (the default) means that there is no maximum.
+func WithMaxPreviousAnchors(value int) Opt {
+	return func(b *Builder) {
+		b.maxPreviousAnchors = value
+	}
 }
 
-// NewBuilder returns a new anchor linkset builder.
-func NewBuilder(registry generatorRegistry) *Builder {
-	return &Builder{registry: registry}
+// NewBuilder returns a new anchor linkset builder. The given registry provides the content object
+// generator for the namespace and version of each payload (see generator.NewRegistry).
+func NewBuilder(registry generatorRegistry, opts ...Opt) *Builder {
+	b := &Builder{registry: registry}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
 }
 
 // ContentObject wraps a content object payload and includes the ID of the generator used to generate the payload.
@@ -41,20 +76,57 @@ type ContentObject struct {
 	Payload vocab.Document
 }
 
-// VCBuilder constructs a verifiable credential.
+// VCBuilder constructs a verifiable credential. It is invoked with the hashlink of the anchor
+// (i.e. the hashlink of the generated content object) and the hashlink of the Sidetree core index file.
+// The returned credential is embedded in the 'replies' of the anchor link and should therefore be signed.
 type VCBuilder func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error)
 
+// CredentialBuilder builds an (unsigned) anchor credential, for example builder.Builder.
+type CredentialBuilder interface {
+	Build(profile *url.URL, anchorHashlink, coreIndexHashlink string, context []string) (*verifiable.Credential, error)
+}
+
+// CredentialSigner signs a verifiable credential, for example vcsigner.Signer.
+type CredentialSigner interface {
+	Sign(vc *verifiable.Credential, opts ...vcsigner.Opt) (*verifiable.Credential, error)
+	Context() []string
+}
+
 type generatorRegistry interface {
 	Get(id *url.URL) (generator.Generator, error)
 	GetByNamespaceAndVersion(ns string, ver uint64) (generator.Generator, error)
 }
 
-// BuildAnchorLink builds an anchor Link from the given payload.
+// BuildAnchorLink builds an anchor Link from the given payload. The payload must contain the core index
+// and namespace. The 'original', 'related' and 'replies' references are encoded as data URIs
+// using the given media type. If datauri.MediaTypeDataURIAuto is specified then the media type of each data URI
+// is chosen according to the size of its content (see datauri.SelectMediaType).
+// The given VCBuilder is invoked to create the anchor credential which is embedded in the 'replies'.
+// The previous anchors (i.e. the operations) in the payload are ordered deterministically (see
+// withOrderedPreviousAnchors) so that the same set of operations always results in the same anchor.
+// ErrMaxPreviousAnchorsExceeded is returned if the payload contains more previous anchors than the
+// configured maximum (see WithMaxPreviousAnchors).
+// The anchor link and the bytes of the anchor credential are returned.
 //
 //nolint:cyclop
 func (b *Builder) BuildAnchorLink(payload *subject.Payload,
 	dataURIMediaType datauri.MediaType, buildVC VCBuilder,
 ) (anchorLink *linkset.Link, vcBytes []byte, err error) {
+	if err = validate(payload, dataURIMediaType); err != nil {
+		return nil, nil, fmt.Errorf("invalid input: %w", err)
+	}
+
+	if buildVC == nil {
+		return nil, nil, errors.New("invalid input: VC builder is required")
+	}
+
+	if b.maxPreviousAnchors > 0 && len(payload.PreviousAnchors) > b.maxPreviousAnchors {
+		return nil, nil, fmt.Errorf("%w: payload contains %d previous anchors but the maximum is %d",
+			ErrMaxPreviousAnchorsExceeded, len(payload.PreviousAnchors), b.maxPreviousAnchors)
+	}
+
+	payload = withOrderedPreviousAnchors(payload)
+
 	contentObj, err := b.buildContentObject(payload)
 	if err != nil {
 		return nil, nil, fmt.Errorf("build content object: %w", err)
@@ -65,7 +137,8 @@ func (b *Builder) BuildAnchorLink(payload *subject.Payload,
 		return nil, nil, fmt.Errorf("marshal content object: %w", err)
 	}
 
-	anchorURI, originalRef, err := linkset.NewAnchorRef(originalBytes, dataURIMediaType, linkset.TypeLinkset)
+	anchorURI, originalRef, err := linkset.NewAnchorRef(originalBytes, dataURIMediaType, linkset.TypeLinkset,
+		b.dataURIOpts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("build 'original' reference: %w", err)
 	}
@@ -85,7 +158,7 @@ func (b *Builder) BuildAnchorLink(payload *subject.Payload,
 		return nil, nil, fmt.Errorf("marshal verifiable credential: %w", err)
 	}
 
-	repliesDataURI, err := datauri.New(vcBytes, dataURIMediaType)
+	repliesDataURI, err := datauri.New(vcBytes, dataURIMediaType, b.dataURIOpts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("create 'replies' data URI: %w", err)
 	}
@@ -102,7 +175,7 @@ func (b *Builder) BuildAnchorLink(payload *subject.Payload,
 		),
 	)
 
-	relatedDataURI, err := datauri.MarshalCanonical(relatedLinkset, dataURIMediaType)
+	relatedDataURI, err := datauri.MarshalCanonical(relatedLinkset, dataURIMediaType, b.dataURIOpts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("create related Linkset data URI: %w", err)
 	}
@@ -120,6 +193,46 @@ func (b *Builder) BuildAnchorLink(payload *subject.Payload,
 	return anchorLink, vcBytes, nil
 }
 
+// BuildSignedAnchorLinkset is a convenience function which builds an anchor linkset from the given payload.
+// The anchor credential is created by the given credential builder and is then signed by the given signer.
+func (b *Builder) BuildSignedAnchorLinkset(payload *subject.Payload, dataURIMediaType datauri.MediaType,
+	credentialBuilder CredentialBuilder, signer CredentialSigner,
+) (*linkset.Linkset, error) {
+	if credentialBuilder == nil {
+		return nil, errors.New("invalid input: credential builder is required")
+	}
+
+	if signer == nil {
+		return nil, errors.New("invalid input: credential signer is required")
+	}
+
+	anchorLink, _, err := b.BuildAnchorLink(payload, dataURIMediaType,
+		func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error) {
+			gen, err := b.registry.GetByNamespaceAndVersion(payload.Namespace, payload.Version)
+			if err != nil {
+				return nil, fmt.Errorf("get generator: %w", err)
+			}
+
+			vc, err := credentialBuilder.Build(gen.ID(), anchorHashlink, coreIndexHashlink, signer.Context())
+			if err != nil {
+				return nil, fmt.Errorf("build anchor credential: %w", err)
+			}
+
+			vc, err = signer.Sign(vc)
+			if err != nil {
+				return nil, fmt.Errorf("sign anchor credential: %w", err)
+			}
+
+			return vc, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return linkset.New(anchorLink), nil
+}
+
 func (b *Builder) buildContentObject(payload *subject.Payload) (*ContentObject, error) {
 	gen, err := b.registry.GetByNamespaceAndVersion(payload.Namespace, payload.Version)
 	if err != nil {
@@ -177,6 +290,50 @@ func (b *Builder) GetPayloadFromAnchorLink(anchorLink *linkset.Link) (*subject.P
 	return payload, nil
 }
 
+func validate(payload *subject.Payload, dataURIMediaType datauri.MediaType) error {
+	if payload == nil {
+		return errors.New("payload is required")
+	}
+
+	if payload.CoreIndex == "" {
+		return errors.New("payload is missing core index")
+	}
+
+	if payload.Namespace == "" {
+		return errors.New("payload is missing namespace")
+	}
+
+	switch dataURIMediaType {
+	case datauri.MediaTypeDataURIJSON, datauri.MediaTypeDataURIGzipBase64, datauri.MediaTypeDataURIBase64,
+		datauri.MediaTypeDataURIAuto:
+		return nil
+	default:
+		return fmt.Errorf("unsupported data URI media type [%s]", dataURIMediaType)
+	}
+}
+
+// withOrderedPreviousAnchors returns a copy of the given payload in which the previous anchors are sorted by suffix
+// and then by previous anchor. The order of the operations in a batch depends on the order in which the operations
+// were received, so the operations are sorted in order for two nodes that anchor the same set of operations to
+// produce identical anchor content (and therefore the same anchor hash). The given payload is not modified.
+func withOrderedPreviousAnchors(payload *subject.Payload) *subject.Payload {
+	previousAnchors := make([]*subject.SuffixAnchor, len(payload.PreviousAnchors))
+	copy(previousAnchors, payload.PreviousAnchors)
+
+	sort.SliceStable(previousAnchors, func(i, j int) bool {
+		if previousAnchors[i].Suffix != previousAnchors[j].Suffix {
+			return previousAnchors[i].Suffix < previousAnchors[j].Suffix
+		}
+
+		return previousAnchors[i].Anchor < previousAnchors[j].Anchor
+	})
+
+	orderedPayload := *payload
+	orderedPayload.PreviousAnchors = previousAnchors
+
+	return &orderedPayload
+}
+
 func resolveParents(previousAnchors []*subject.SuffixAnchor) []*url.URL {
 	var previous []string
 
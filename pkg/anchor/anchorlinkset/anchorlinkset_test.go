@@ -8,16 +8,23 @@ package anchorlinkset
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator"
+	"github.com/trustbloc/orb/pkg/anchor/builder"
 	"github.com/trustbloc/orb/pkg/anchor/subject"
 	"github.com/trustbloc/orb/pkg/datauri"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/linkset"
+	"github.com/trustbloc/orb/pkg/vcsigner"
 )
 
 const (
@@ -105,6 +112,313 @@ func TestBuildAnchorLink(t *testing.T) {
 		require.Nil(t, contentObj)
 		require.Contains(t, err.Error(), "generator not found for namespace [did:other] and version [0]")
 	})
+
+	t.Run("error - invalid input", func(t *testing.T) {
+		buildVC := func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error) {
+			return &verifiable.Credential{}, nil
+		}
+
+		newPayload := func() *subject.Payload {
+			return &subject.Payload{
+				CoreIndex:       coreIndex,
+				Namespace:       namespace,
+				AnchorOrigin:    anchorOrigin,
+				PreviousAnchors: previousAnchors,
+			}
+		}
+
+		_, _, err := builder.BuildAnchorLink(nil, datauri.MediaTypeDataURIGzipBase64, buildVC)
+		require.EqualError(t, err, "invalid input: payload is required")
+
+		payload := newPayload()
+		payload.CoreIndex = ""
+
+		_, _, err = builder.BuildAnchorLink(payload, datauri.MediaTypeDataURIGzipBase64, buildVC)
+		require.EqualError(t, err, "invalid input: payload is missing core index")
+
+		payload = newPayload()
+		payload.Namespace = ""
+
+		_, _, err = builder.BuildAnchorLink(payload, datauri.MediaTypeDataURIGzipBase64, buildVC)
+		require.EqualError(t, err, "invalid input: payload is missing namespace")
+
+		_, _, err = builder.BuildAnchorLink(newPayload(), "application/xml", buildVC)
+		require.EqualError(t, err, "invalid input: unsupported data URI media type [application/xml]")
+
+		_, _, err = builder.BuildAnchorLink(newPayload(), datauri.MediaTypeDataURIGzipBase64, nil)
+		require.EqualError(t, err, "invalid input: VC builder is required")
+	})
+}
+
+func TestBuildSignedAnchorLinkset(t *testing.T) {
+	payload := &subject.Payload{
+		CoreIndex:    coreIndex,
+		Namespace:    namespace,
+		AnchorOrigin: anchorOrigin,
+		PreviousAnchors: []*subject.SuffixAnchor{
+			{Suffix: createSuffix},
+			{Suffix: updateSuffix, Anchor: updatePrevAnchor},
+		},
+	}
+
+	anchorLinksetBuilder := NewBuilder(generator.NewRegistry())
+
+	credentialBuilder, err := builder.New(builder.Params{
+		Issuer: "https://orb.domain1.com",
+		URL:    "https://orb.domain1.com/vc",
+	})
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		signer := &mockSigner{}
+
+		for _, mediaType := range []datauri.MediaType{datauri.MediaTypeDataURIJSON, datauri.MediaTypeDataURIGzipBase64} {
+			anchorLinkset, err := anchorLinksetBuilder.BuildSignedAnchorLinkset(payload, mediaType,
+				credentialBuilder, signer)
+			require.NoError(t, err)
+
+			anchorLink := anchorLinkset.Link()
+			require.NotNil(t, anchorLink)
+			require.Equal(t, anchorOrigin, anchorLink.Author().String())
+			require.NotNil(t, anchorLink.Original())
+			require.NotNil(t, anchorLink.Related())
+			require.NotNil(t, anchorLink.Replies())
+
+			vcBytes, err := anchorLink.Replies().Content()
+			require.NoError(t, err)
+
+			vc, err := verifiable.ParseCredential(vcBytes,
+				verifiable.WithDisabledProofCheck(),
+				verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+			)
+			require.NoError(t, err)
+			require.Len(t, vc.Proofs, 1)
+			require.Equal(t, "https://orb.domain1.com", vc.Issuer.ID)
+
+			// The credential subject references the anchor.
+			vcJSON, err := json.Marshal(vc.Subject)
+			require.NoError(t, err)
+			require.Contains(t, string(vcJSON), anchorLink.Anchor().String())
+		}
+	})
+
+	t.Run("error - invalid payload", func(t *testing.T) {
+		invalidPayload := *payload
+		invalidPayload.CoreIndex = ""
+
+		_, err := anchorLinksetBuilder.BuildSignedAnchorLinkset(&invalidPayload, datauri.MediaTypeDataURIJSON,
+			credentialBuilder, &mockSigner{})
+		require.EqualError(t, err, "invalid input: payload is missing core index")
+	})
+
+	t.Run("error - missing credential builder", func(t *testing.T) {
+		_, err := anchorLinksetBuilder.BuildSignedAnchorLinkset(payload, datauri.MediaTypeDataURIJSON,
+			nil, &mockSigner{})
+		require.EqualError(t, err, "invalid input: credential builder is required")
+	})
+
+	t.Run("error - missing signer", func(t *testing.T) {
+		_, err := anchorLinksetBuilder.BuildSignedAnchorLinkset(payload, datauri.MediaTypeDataURIJSON,
+			credentialBuilder, nil)
+		require.EqualError(t, err, "invalid input: credential signer is required")
+	})
+
+	t.Run("error - sign error", func(t *testing.T) {
+		_, err := anchorLinksetBuilder.BuildSignedAnchorLinkset(payload, datauri.MediaTypeDataURIJSON,
+			credentialBuilder, &mockSigner{err: errors.New("injected sign error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sign anchor credential: injected sign error")
+	})
+}
+
+func TestBuildAnchorLink_AutoMediaType(t *testing.T) {
+	payload := &subject.Payload{
+		CoreIndex:    coreIndex,
+		Namespace:    namespace,
+		AnchorOrigin: anchorOrigin,
+		PreviousAnchors: []*subject.SuffixAnchor{
+			{Suffix: createSuffix},
+			{Suffix: updateSuffix, Anchor: updatePrevAnchor},
+		},
+	}
+
+	buildVC := func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error) {
+		return &verifiable.Credential{}, nil
+	}
+
+	getMediaType := func(t *testing.T, ref *linkset.Reference) string {
+		t.Helper()
+
+		u := ref.HRef().String()
+		require.True(t, strings.HasPrefix(u, "data:"))
+
+		return u[len("data:"):strings.Index(u, ",")]
+	}
+
+	t.Run("Below threshold -> base64", func(t *testing.T) {
+		b := NewBuilder(generator.NewRegistry(), WithDataURIAutoThreshold(100000))
+
+		anchorLink, _, err := b.BuildAnchorLink(payload, datauri.MediaTypeDataURIAuto, buildVC)
+		require.NoError(t, err)
+
+		require.Equal(t, datauri.MediaTypeDataURIBase64, getMediaType(t, anchorLink.Original()))
+		require.Equal(t, datauri.MediaTypeDataURIBase64, getMediaType(t, anchorLink.Related()))
+		require.Equal(t, datauri.MediaTypeDataURIBase64, getMediaType(t, anchorLink.Replies()))
+
+		contentObjBytes, err := anchorLink.Original().Content()
+		require.NoError(t, err)
+		require.Equal(t, testutil.GetCanonical(t, jsonContentObj), string(contentObjBytes))
+
+		relatedLinkset, err := anchorLink.Related().Linkset()
+		require.NoError(t, err)
+		require.NotNil(t, relatedLinkset.Link())
+
+		_, err = anchorLink.Replies().Content()
+		require.NoError(t, err)
+	})
+
+	t.Run("Above threshold -> gzip", func(t *testing.T) {
+		b := NewBuilder(generator.NewRegistry(), WithDataURIAutoThreshold(1))
+
+		anchorLink, _, err := b.BuildAnchorLink(payload, datauri.MediaTypeDataURIAuto, buildVC)
+		require.NoError(t, err)
+
+		require.Equal(t, datauri.MediaTypeDataURIGzipBase64, getMediaType(t, anchorLink.Original()))
+		require.Equal(t, datauri.MediaTypeDataURIGzipBase64, getMediaType(t, anchorLink.Related()))
+		require.Equal(t, datauri.MediaTypeDataURIGzipBase64, getMediaType(t, anchorLink.Replies()))
+
+		contentObjBytes, err := anchorLink.Original().Content()
+		require.NoError(t, err)
+		require.Equal(t, testutil.GetCanonical(t, jsonContentObj), string(contentObjBytes))
+
+		relatedLinkset, err := anchorLink.Related().Linkset()
+		require.NoError(t, err)
+		require.NotNil(t, relatedLinkset.Link())
+	})
+
+	t.Run("Same anchor regardless of media type", func(t *testing.T) {
+		anchorLink1, _, err := NewBuilder(generator.NewRegistry(), WithDataURIAutoThreshold(1)).
+			BuildAnchorLink(payload, datauri.MediaTypeDataURIAuto, buildVC)
+		require.NoError(t, err)
+
+		anchorLink2, _, err := NewBuilder(generator.NewRegistry(), WithDataURIAutoThreshold(100000)).
+			BuildAnchorLink(payload, datauri.MediaTypeDataURIAuto, buildVC)
+		require.NoError(t, err)
+
+		require.Equal(t, anchorLink1.Anchor().String(), anchorLink2.Anchor().String())
+	})
+}
+
+func TestBuildAnchorLink_DeterministicOrder(t *testing.T) {
+	const numOperations = 20
+
+	var previousAnchors []*subject.SuffixAnchor
+
+	for i := 0; i < numOperations; i++ {
+		suffix := fmt.Sprintf("uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsj%04d", i)
+
+		if i%2 == 0 {
+			previousAnchors = append(previousAnchors, &subject.SuffixAnchor{Suffix: suffix})
+		} else {
+			previousAnchors = append(previousAnchors, &subject.SuffixAnchor{Suffix: suffix, Anchor: updatePrevAnchor})
+		}
+	}
+
+	newPayload := func(previousAnchors []*subject.SuffixAnchor) *subject.Payload {
+		return &subject.Payload{
+			CoreIndex:       coreIndex,
+			Namespace:       namespace,
+			AnchorOrigin:    anchorOrigin,
+			PreviousAnchors: previousAnchors,
+			OperationCount:  uint64(len(previousAnchors)),
+		}
+	}
+
+	buildVC := func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error) {
+		return &verifiable.Credential{}, nil
+	}
+
+	builder := NewBuilder(generator.NewRegistry())
+
+	expectedAnchorLink, _, err := builder.BuildAnchorLink(newPayload(previousAnchors),
+		datauri.MediaTypeDataURIGzipBase64, buildVC)
+	require.NoError(t, err)
+
+	expectedContent, err := expectedAnchorLink.Original().Content()
+	require.NoError(t, err)
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec
+
+	for i := 0; i < 10; i++ {
+		shuffled := make([]*subject.SuffixAnchor, len(previousAnchors))
+		copy(shuffled, previousAnchors)
+
+		rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		payload := newPayload(shuffled)
+
+		anchorLink, _, err := builder.BuildAnchorLink(payload, datauri.MediaTypeDataURIGzipBase64, buildVC)
+		require.NoError(t, err)
+
+		content, err := anchorLink.Original().Content()
+		require.NoError(t, err)
+
+		require.Equal(t, string(expectedContent), string(content))
+		require.Equal(t, expectedAnchorLink.Anchor().String(), anchorLink.Anchor().String())
+
+		// The given payload should not have been modified.
+		require.Equal(t, shuffled, payload.PreviousAnchors)
+	}
+}
+
+func TestBuildAnchorLink_MaxPreviousAnchors(t *testing.T) {
+	const maxPreviousAnchors = 5
+
+	newPayload := func(numOperations int) *subject.Payload {
+		var previousAnchors []*subject.SuffixAnchor
+
+		for i := 0; i < numOperations; i++ {
+			previousAnchors = append(previousAnchors, &subject.SuffixAnchor{
+				Suffix: fmt.Sprintf("uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsj%04d", i),
+			})
+		}
+
+		return &subject.Payload{
+			CoreIndex:       coreIndex,
+			Namespace:       namespace,
+			AnchorOrigin:    anchorOrigin,
+			PreviousAnchors: previousAnchors,
+			OperationCount:  uint64(numOperations),
+		}
+	}
+
+	buildVC := func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error) {
+		return &verifiable.Credential{}, nil
+	}
+
+	builder := NewBuilder(generator.NewRegistry(), WithMaxPreviousAnchors(maxPreviousAnchors))
+
+	t.Run("At the maximum -> success", func(t *testing.T) {
+		anchorLink, _, err := builder.BuildAnchorLink(newPayload(maxPreviousAnchors),
+			datauri.MediaTypeDataURIGzipBase64, buildVC)
+		require.NoError(t, err)
+		require.NotNil(t, anchorLink)
+	})
+
+	t.Run("Over the maximum -> error", func(t *testing.T) {
+		anchorLink, _, err := builder.BuildAnchorLink(newPayload(maxPreviousAnchors+1),
+			datauri.MediaTypeDataURIGzipBase64, buildVC)
+		require.ErrorIs(t, err, ErrMaxPreviousAnchorsExceeded)
+		require.Contains(t, err.Error(), "payload contains 6 previous anchors but the maximum is 5")
+		require.Nil(t, anchorLink)
+	})
+
+	t.Run("No maximum", func(t *testing.T) {
+		anchorLink, _, err := NewBuilder(generator.NewRegistry()).BuildAnchorLink(newPayload(maxPreviousAnchors+1),
+			datauri.MediaTypeDataURIGzipBase64, buildVC)
+		require.NoError(t, err)
+		require.NotNil(t, anchorLink)
+	})
 }
 
 func TestGetPayloadFromActivity(t *testing.T) {
@@ -139,7 +453,8 @@ func TestGetPayloadFromActivity(t *testing.T) {
 		require.Equal(t, inPayload.Version, outPayload.Version)
 		require.Equal(t, inPayload.AnchorOrigin, outPayload.AnchorOrigin)
 		require.Equal(t, inPayload.CoreIndex, outPayload.CoreIndex)
-		require.Equal(t, inPayload.PreviousAnchors, outPayload.PreviousAnchors)
+		// The previous anchors are ordered by suffix in the anchor.
+		require.Equal(t, withOrderedPreviousAnchors(inPayload).PreviousAnchors, outPayload.PreviousAnchors)
 		require.Equal(t, inPayload.OperationCount, outPayload.OperationCount)
 	})
 
@@ -257,14 +572,14 @@ const (
         }
       ],
       "item": [
-        {
-          "href": "did:orb:uAAA:uEiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
-        },
         {
           "href": "did:orb:uEiAsiwjaXOYDmOHxmvDl3Mx0TfJ0uCar5YXqumjFJUNIBg:uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA",
           "previous": [
             "hl:uEiAsiwjaXOYDmOHxmvDl3Mx0TfJ0uCar5YXqumjFJUNIBg"
           ]
+        },
+        {
+          "href": "did:orb:uAAA:uEiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
         }
       ],
       "profile": [
@@ -276,3 +591,26 @@ const (
   ]
 }`
 )
+
+type mockSigner struct {
+	err error
+}
+
+func (m *mockSigner) Sign(vc *verifiable.Credential, _ ...vcsigner.Opt) (*verifiable.Credential, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	vc.Proofs = append(vc.Proofs, verifiable.Proof{
+		"type":               "Ed25519Signature2020",
+		"proofPurpose":       "assertionMethod",
+		"verificationMethod": "did:web:orb.domain1.com#key1",
+		"proofValue":         "z4Fr5uwhDfF",
+	})
+
+	return vc, nil
+}
+
+func (m *mockSigner) Context() []string {
+	return []string{"https://w3id.org/security/suites/ed25519-2020/v1"}
+}
@@ -39,7 +39,7 @@ const (
 	relLinkset = "linkset"
 
 	multihashPrefix  = "did:orb"
-	unpublishedLabel = "uAAA"
+	unpublishedLabel = util.UnpublishedDIDLabel
 
 	separator     = ":"
 	hashlinkParts = 3
@@ -315,3 +315,6 @@ func (m *metricsProvider) CASIncrementCacheHitCount() {
 
 func (m *metricsProvider) CASReadTime(casType string, value time.Duration) {
 }
+
+func (m *metricsProvider) CASBytesStored(value int) {
+}
@@ -9,6 +9,7 @@ package credential
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"time"
@@ -26,11 +27,82 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/util"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/linkset"
+	"github.com/trustbloc/orb/pkg/observability/metrics/noop"
 	"github.com/trustbloc/orb/pkg/observability/tracing"
 )
 
 var logger = log.New("anchor-credential-handler")
 
+// ErrHashMismatch is returned when the resource hash of an anchor hashlink doesn't match the hash
+// of the anchor content.
+var ErrHashMismatch = hashlink.ErrHashMismatch
+
+// ErrAnchorTooOld is returned when a newly announced anchor was issued longer ago than the maximum allowed age.
+var ErrAnchorTooOld = errors.New("anchor exceeds the maximum age")
+
+// ErrParentResolveTimeout is returned when a parent anchor isn't resolved within the parent resolve timeout.
+var ErrParentResolveTimeout = errors.New("parent anchor resolve timeout")
+
+// ErrParentTraversalTimeout is returned when the unprocessed ancestors of an anchor aren't resolved within
+// the parent traversal timeout.
+var ErrParentTraversalTimeout = errors.New("parent anchor traversal timeout")
+
+// ErrProfileNotAccepted is returned when the profile of an anchor is known but isn't one of the accepted profiles.
+var ErrProfileNotAccepted = errors.New("anchor profile not accepted")
+
+// ErrLineageMismatch is returned when the parents of an anchor don't match the previous anchors declared
+// by the items in the anchor.
+var ErrLineageMismatch = errors.New("anchor lineage mismatch")
+
+// ErrDuplicateParents is returned when the parents of an anchor contain duplicates and the duplicate-parent
+// policy is DuplicateParentsReject.
+var ErrDuplicateParents = errors.New("anchor contains duplicate parents")
+
+// DuplicateParentPolicy specifies how the handler deals with an anchor whose parents contain duplicates.
+type DuplicateParentPolicy string
+
+const (
+	// DuplicateParentsDedup silently ignores the duplicate parents of an anchor (default).
+	DuplicateParentsDedup DuplicateParentPolicy = "dedup"
+	// DuplicateParentsWarn ignores the duplicate parents of an anchor but logs a warning.
+	DuplicateParentsWarn DuplicateParentPolicy = "warn"
+	// DuplicateParentsReject rejects an anchor whose parents contain duplicates with ErrDuplicateParents.
+	DuplicateParentsReject DuplicateParentPolicy = "reject"
+)
+
+// ParseDuplicateParentPolicy parses the given duplicate-parent policy (dedup, warn or reject).
+func ParseDuplicateParentPolicy(value string) (DuplicateParentPolicy, error) {
+	switch policy := DuplicateParentPolicy(value); policy {
+	case DuplicateParentsDedup, DuplicateParentsWarn, DuplicateParentsReject:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unsupported duplicate-parent policy [%s]: must be dedup, warn or reject", value)
+	}
+}
+
+// AnchorFetchPolicy specifies whether the handler uses the anchor linkset which is embedded in an anchor event
+// or whether the anchor linkset is always fetched from CAS.
+type AnchorFetchPolicy string
+
+const (
+	// AnchorFetchPreferEmbedded uses the anchor linkset which is embedded in an anchor event, if present. The anchor
+	// linkset is fetched from CAS only if it isn't embedded (default).
+	AnchorFetchPreferEmbedded AnchorFetchPolicy = "prefer-embedded"
+	// AnchorFetchAlways ignores the anchor linkset which is embedded in an anchor event received from another server
+	// and always fetches the anchor linkset from CAS.
+	AnchorFetchAlways AnchorFetchPolicy = "always-fetch"
+)
+
+// ParseAnchorFetchPolicy parses the given anchor fetch policy (prefer-embedded or always-fetch).
+func ParseAnchorFetchPolicy(value string) (AnchorFetchPolicy, error) {
+	switch policy := AnchorFetchPolicy(value); policy {
+	case AnchorFetchPreferEmbedded, AnchorFetchAlways:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unsupported anchor fetch policy [%s]: must be prefer-embedded or always-fetch", value)
+	}
+}
+
 type anchorLinkStore interface {
 	GetProcessedAndPendingLinks(anchorHash string) ([]*url.URL, error)
 	PutPendingLinks(links []*url.URL) error
@@ -41,41 +113,203 @@ type generatorRegistry interface {
 	Get(id *url.URL) (generator.Generator, error)
 }
 
+type metricsProvider interface {
+	ParentAnchorTraversalDepth(depth int)
+	ParentAnchorTraversalResolvedCount(count int)
+}
+
 // AnchorEventHandler handles a new, published anchor credential.
 type AnchorEventHandler struct {
-	anchorPublisher   anchorPublisher
-	casResolver       casResolver
-	maxDelay          time.Duration
-	documentLoader    ld.DocumentLoader
-	anchorLinkStore   anchorLinkStore
-	unmarshal         func(data []byte, v interface{}) error
-	generatorRegistry generatorRegistry
-	tracer            trace.Tracer
+	anchorPublisher        anchorPublisher
+	casResolver            casResolver
+	documentLoader         ld.DocumentLoader
+	anchorLinkStore        anchorLinkStore
+	unmarshal              func(data []byte, v interface{}) error
+	generatorRegistry      generatorRegistry
+	tracer                 trace.Tracer
+	metrics                metricsProvider
+	acceptedProfiles       map[string]struct{}
+	strictValidation       bool
+	maxAnchorAge           time.Duration
+	verifyLineage          bool
+	parentResolveTimeout   time.Duration
+	parentTraversalTimeout time.Duration
+	duplicateParentPolicy  DuplicateParentPolicy
+	anchorFetchPolicy      AnchorFetchPolicy
+}
+
+type options struct {
+	strictValidation       bool
+	maxAnchorAge           time.Duration
+	verifyLineage          bool
+	parentResolveTimeout   time.Duration
+	parentTraversalTimeout time.Duration
+	publishQueueSize       int
+	publishQueueWait       time.Duration
+	metrics                metricsProvider
+	acceptedProfiles       map[string]struct{}
+	duplicateParentPolicy  DuplicateParentPolicy
+	anchorFetchPolicy      AnchorFetchPolicy
+}
+
+// Opt is an anchor event handler option.
+type Opt func(opts *options)
+
+// WithStrictValidation enables/disables strict JSON-LD validation of anchor credentials (enabled by default).
+// Strict validation rejects credentials that contain fields which aren't defined in the JSON-LD context.
+func WithStrictValidation(enable bool) Opt {
+	return func(opts *options) {
+		opts.strictValidation = enable
+	}
+}
+
+// WithMaxAnchorAge sets the maximum age of a newly announced anchor, based on the issuance date of the anchor
+// credential. Announced anchors that are older than this are rejected with ErrAnchorTooOld. The check doesn't
+// apply to the parents of an anchor which are processed in order to complete the anchor's lineage.
+// If zero (default) then the age of an anchor isn't checked.
+func WithMaxAnchorAge(d time.Duration) Opt {
+	return func(opts *options) {
+		opts.maxAnchorAge = d
+	}
+}
+
+// WithLineageVerification enables/disables verification of the lineage of an anchor (disabled by default).
+// If enabled then, while traversing the parents of an anchor, each parent must be declared as the previous
+// anchor of at least one item in the child anchor and each previous anchor declared by an item must be one
+// of the parents. Since the content of a parent is verified against the hash in its hashlink, this ensures
+// that an anchor can't claim a forged lineage. ErrLineageMismatch is returned if verification fails.
+func WithLineageVerification(enable bool) Opt {
+	return func(opts *options) {
+		opts.verifyLineage = enable
+	}
+}
+
+// WithParentResolveTimeout sets the maximum amount of time to wait for a single parent anchor to be resolved
+// from CAS. ErrParentResolveTimeout is returned if the timeout is exceeded. If zero (default) then the
+// resolution of a parent isn't bounded (other than by the timeouts of the CAS resolver).
+func WithParentResolveTimeout(d time.Duration) Opt {
+	return func(opts *options) {
+		opts.parentResolveTimeout = d
+	}
+}
+
+// WithParentTraversalTimeout sets the maximum amount of time to spend resolving all of the unprocessed
+// ancestors (parents, grandparents, etc.) of an anchor. ErrParentTraversalTimeout is returned if the timeout
+// is exceeded. This bounds the time spent on a deep chain of parents, whereas the parent resolve timeout
+// bounds the time spent on a single slow parent. If zero (default) then the traversal isn't bounded.
+func WithParentTraversalTimeout(d time.Duration) Opt {
+	return func(opts *options) {
+		opts.parentTraversalTimeout = d
+	}
+}
+
+// WithPublishQueue limits the number of anchors which may be published concurrently to the given size, so that
+// a burst of anchors doesn't overwhelm the downstream publisher. When the queue is full, a publish waits up to
+// the given amount of time for a slot to become available. If a slot doesn't become available in time then
+// a transient ErrPublishQueueFull error is returned so that the anchor event is retried. If the wait time is
+// zero then the error is returned immediately and, if negative, the publish waits until a slot is available
+// (or the context is done). If the size is zero (default) then the number of concurrent publishes isn't limited.
+func WithPublishQueue(size int, wait time.Duration) Opt {
+	return func(opts *options) {
+		opts.publishQueueSize = size
+		opts.publishQueueWait = wait
+	}
+}
+
+// WithMetrics sets the metrics provider which records the depth of each traversal of the unprocessed ancestors
+// of an anchor and the number of parents resolved in the traversal. By default, metrics aren't recorded.
+func WithMetrics(p metricsProvider) Opt {
+	return func(opts *options) {
+		opts.metrics = p
+	}
+}
+
+// WithAcceptedProfiles restricts the profiles (i.e. anchor formats) of the anchors which are accepted by the handler
+// to the given subset of the profiles in the generator registry, for example, during a migration from one profile
+// to another. Anchors (including parent anchors) with a profile which isn't in the set are rejected with
+// ErrProfileNotAccepted. If not set (default) then all of the profiles in the generator registry are accepted.
+func WithAcceptedProfiles(profiles ...*url.URL) Opt {
+	return func(opts *options) {
+		opts.acceptedProfiles = make(map[string]struct{}, len(profiles))
+
+		for _, profile := range profiles {
+			opts.acceptedProfiles[profile.String()] = struct{}{}
+		}
+	}
+}
+
+// WithDuplicateParentPolicy sets the policy for handling an anchor whose parents contain duplicates. With
+// DuplicateParentsDedup (default) the duplicates are silently ignored, with DuplicateParentsWarn the duplicates
+// are ignored and a warning is logged and with DuplicateParentsReject the anchor is rejected with
+// ErrDuplicateParents.
+func WithDuplicateParentPolicy(policy DuplicateParentPolicy) Opt {
+	return func(opts *options) {
+		opts.duplicateParentPolicy = policy
+	}
+}
+
+// WithAnchorFetchPolicy sets the policy which determines where the anchor linkset of an anchor event is taken from.
+// With AnchorFetchPreferEmbedded (default) the anchor linkset embedded in the anchor event is used, if present, and
+// with AnchorFetchAlways the embedded anchor linkset is ignored and the anchor linkset is always fetched from CAS,
+// for operators who don't want to trust inline content from other servers. The policy only applies to anchor events
+// which are received from another server (i.e. which have an actor). The embedded content of anchors that are
+// submitted locally, for example, via the anchor import endpoint, is always used.
+func WithAnchorFetchPolicy(policy AnchorFetchPolicy) Opt {
+	return func(opts *options) {
+		opts.anchorFetchPolicy = policy
+	}
 }
 
 type casResolver interface {
 	Resolve(webCASURL *url.URL, cid string, data []byte) ([]byte, string, error)
 }
 
+// contextCASResolver is implemented by a CAS resolver which stops resolving when the given context is done.
+type contextCASResolver interface {
+	ResolveContext(ctx context.Context, webCASURL *url.URL, cid string, data []byte) ([]byte, string, error)
+}
+
 type anchorPublisher interface {
 	PublishAnchor(ctx context.Context, anchorInfo *anchorinfo.AnchorInfo) error
 }
 
 // New creates new credential handler.
 func New(anchorPublisher anchorPublisher, casResolver casResolver,
-	documentLoader ld.DocumentLoader,
-	maxDelay time.Duration, anchorLinkStore anchorLinkStore,
-	registry generatorRegistry,
+	documentLoader ld.DocumentLoader, anchorLinkStore anchorLinkStore,
+	registry generatorRegistry, opts ...Opt,
 ) *AnchorEventHandler {
+	options := &options{
+		strictValidation:      true,
+		metrics:               &noop.NoOptMetrics{},
+		duplicateParentPolicy: DuplicateParentsDedup,
+		anchorFetchPolicy:     AnchorFetchPreferEmbedded,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.publishQueueSize > 0 {
+		anchorPublisher = newBoundedPublisher(anchorPublisher, options.publishQueueSize, options.publishQueueWait)
+	}
+
 	return &AnchorEventHandler{
-		anchorPublisher:   anchorPublisher,
-		maxDelay:          maxDelay,
-		casResolver:       casResolver,
-		documentLoader:    documentLoader,
-		anchorLinkStore:   anchorLinkStore,
-		generatorRegistry: registry,
-		unmarshal:         json.Unmarshal,
-		tracer:            tracing.Tracer(tracing.SubsystemAnchor),
+		anchorPublisher:        anchorPublisher,
+		casResolver:            casResolver,
+		documentLoader:         documentLoader,
+		anchorLinkStore:        anchorLinkStore,
+		generatorRegistry:      registry,
+		unmarshal:              json.Unmarshal,
+		tracer:                 tracing.Tracer(tracing.SubsystemAnchor),
+		metrics:                options.metrics,
+		acceptedProfiles:       options.acceptedProfiles,
+		strictValidation:       options.strictValidation,
+		maxAnchorAge:           options.maxAnchorAge,
+		verifyLineage:          options.verifyLineage,
+		parentResolveTimeout:   options.parentResolveTimeout,
+		parentTraversalTimeout: options.parentTraversalTimeout,
+		duplicateParentPolicy:  options.duplicateParentPolicy,
+		anchorFetchPolicy:      options.anchorFetchPolicy,
 	}
 }
 
@@ -98,6 +332,13 @@ func (h *AnchorEventHandler) HandleAnchorEvent(ctx context.Context, actor, ancho
 		return nil
 	}
 
+	if anchorEvent != nil && actor != nil && h.anchorFetchPolicy == AnchorFetchAlways {
+		logger.Debugc(ctx, "Ignoring the embedded anchor linkset since the anchor fetch policy is always-fetch",
+			logfields.WithActorIRI(actor), logfields.WithAnchorURI(anchorRef))
+
+		anchorEvent = nil
+	}
+
 	var anchorLinksetBytes []byte
 
 	if anchorEvent != nil {
@@ -107,13 +348,28 @@ func (h *AnchorEventHandler) HandleAnchorEvent(ctx context.Context, actor, ancho
 		if e != nil {
 			return fmt.Errorf("marshal anchor linkset: %w", e)
 		}
+
+		// Ensure that the embedded content corresponds to the hashlink before it is stored in the CAS.
+		e = verifyAnchorHash(anchorRef, anchorLinksetBytes)
+		if e != nil {
+			return e
+		}
 	}
 
-	anchorLinksetBytes, localHL, err := h.casResolver.Resolve(nil, anchorRef.String(), anchorLinksetBytes)
+	resolvedBytes, localHL, err := h.casResolver.Resolve(nil, anchorRef.String(), anchorLinksetBytes)
 	if err != nil {
 		return fmt.Errorf("failed to resolve anchor [%s]: %w", anchorRef, err)
 	}
 
+	if anchorLinksetBytes == nil {
+		err = verifyAnchorHash(anchorRef, resolvedBytes)
+		if err != nil {
+			return err
+		}
+	}
+
+	anchorLinksetBytes = resolvedBytes
+
 	anchorLinkset := &linkset.Linkset{}
 
 	err = h.unmarshal(anchorLinksetBytes, anchorLinkset)
@@ -131,19 +387,11 @@ func (h *AnchorEventHandler) HandleAnchorEvent(ctx context.Context, actor, ancho
 		return fmt.Errorf("validate anchor link: %w", err)
 	}
 
-	// Make sure that all parents/grandparents of this anchor event are processed.
-	err = h.ensureParentAnchorsAreProcessed(ctx, anchorRef, anchorLink)
-	if err != nil {
-		return fmt.Errorf("ensure unprocessed parents are processed for %s: %w", anchorRef, err)
-	}
-
 	var attributedTo string
 	if actor != nil {
 		attributedTo = actor.String()
 	}
 
-	logger.Infoc(ctx, "Processing anchor", logfields.WithAnchorURI(anchorRef))
-
 	var alternateSources []string
 
 	if source != nil {
@@ -153,16 +401,35 @@ func (h *AnchorEventHandler) HandleAnchorEvent(ctx context.Context, actor, ancho
 		alternateSources = []string{source.String()}
 	}
 
-	// Now process the latest anchor event.
-	err = h.processAnchorEvent(ctx, &anchorInfo{
-		anchorLink: anchorLink,
-		AnchorInfo: &anchorinfo.AnchorInfo{
-			Hashlink:         anchorRef.String(),
-			LocalHashlink:    localHL,
-			AttributedTo:     attributedTo,
-			AlternateSources: alternateSources,
-		},
+	// The profile is checked (and the anchor credential is parsed) before the parents are processed so that
+	// the parents of an anchor which is going to be rejected aren't fetched.
+	ai, err := h.newAnchorInfo(anchorLink, &anchorinfo.AnchorInfo{
+		Hashlink:         anchorRef.String(),
+		LocalHashlink:    localHL,
+		AttributedTo:     attributedTo,
+		AlternateSources: alternateSources,
 	})
+	if err != nil {
+		return fmt.Errorf("anchor [%s]: %w", anchorRef, err)
+	}
+
+	// The age check is done before the parents are processed so that a stale anchor doesn't result in its
+	// (even older) parents being fetched. The parents themselves aren't subject to the age check.
+	err = h.checkAnchorAge(anchorRef, ai.vc)
+	if err != nil {
+		return err
+	}
+
+	// Make sure that all parents/grandparents of this anchor event are processed.
+	err = h.ensureParentAnchorsAreProcessed(ctx, anchorRef, anchorLink)
+	if err != nil {
+		return fmt.Errorf("ensure unprocessed parents are processed for %s: %w", anchorRef, err)
+	}
+
+	logger.Infoc(ctx, "Processing anchor", logfields.WithAnchorURI(anchorRef))
+
+	// Now process the latest anchor event.
+	err = h.processAnchorEvent(ctx, ai)
 	if err != nil {
 		return fmt.Errorf("process anchor %s: %w", anchorRef, err)
 	}
@@ -170,6 +437,26 @@ func (h *AnchorEventHandler) HandleAnchorEvent(ctx context.Context, actor, ancho
 	return nil
 }
 
+// newAnchorInfo ensures that the profile of the given anchor link is accepted and parses the anchor credential
+// so that it isn't parsed again when the anchor is processed.
+func (h *AnchorEventHandler) newAnchorInfo(anchorLink *linkset.Link, info *anchorinfo.AnchorInfo) (*anchorInfo, error) {
+	err := h.checkProfile(anchorLink)
+	if err != nil {
+		return nil, err
+	}
+
+	vc, err := h.getCredential(anchorLink)
+	if err != nil {
+		return nil, err
+	}
+
+	return &anchorInfo{
+		AnchorInfo: info,
+		anchorLink: anchorLink,
+		vc:         vc,
+	}, nil
+}
+
 func (h *AnchorEventHandler) processAnchorEvent(ctx context.Context, anchorInfo *anchorInfo) error {
 	anchorLink := anchorInfo.anchorLink
 
@@ -178,21 +465,12 @@ func (h *AnchorEventHandler) processAnchorEvent(ctx context.Context, anchorInfo
 		return fmt.Errorf("get content from original: %w", err)
 	}
 
-	vc, err := util.VerifiableCredentialFromAnchorLink(anchorLink,
-		verifiable.WithDisabledProofCheck(),
-		verifiable.WithJSONLDDocumentLoader(h.documentLoader),
-		verifiable.WithStrictValidation(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed get verifiable credential from anchor link: %w", err)
-	}
-
 	gen, err := h.generatorRegistry.Get(anchorLink.Profile())
 	if err != nil {
 		return fmt.Errorf("resolve generator for profile [%s]: %w", anchorLink.Profile(), err)
 	}
 
-	err = gen.ValidateAnchorCredential(vc, contentBytes)
+	err = gen.ValidateAnchorCredential(anchorInfo.vc, contentBytes)
 	if err != nil {
 		return fmt.Errorf("validate credential subject for anchor [%s]: %w", anchorLink.Anchor(), err)
 	}
@@ -238,10 +516,79 @@ func (h *AnchorEventHandler) processAnchorEvent(ctx context.Context, anchorInfo
 	return nil
 }
 
+// checkProfile returns ErrProfileNotAccepted if accepted profiles are configured and the profile of the given
+// anchor isn't one of them.
+func (h *AnchorEventHandler) checkProfile(anchorLink *linkset.Link) error {
+	if len(h.acceptedProfiles) == 0 {
+		return nil
+	}
+
+	if anchorLink.Profile() != nil {
+		if _, ok := h.acceptedProfiles[anchorLink.Profile().String()]; ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: [%s]", ErrProfileNotAccepted, anchorLink.Profile())
+}
+
+func (h *AnchorEventHandler) getCredential(anchorLink *linkset.Link) (*verifiable.Credential, error) {
+	parseOpts := []verifiable.CredentialOpt{
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(h.documentLoader),
+	}
+
+	if h.strictValidation {
+		parseOpts = append(parseOpts, verifiable.WithStrictValidation())
+	}
+
+	vc, err := util.VerifiableCredentialFromAnchorLink(anchorLink, parseOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed get verifiable credential from anchor link: %w", err)
+	}
+
+	return vc, nil
+}
+
+// checkAnchorAge returns ErrAnchorTooOld if the anchor credential was issued longer ago than the maximum
+// anchor age. The check is skipped if no maximum age is configured or if the credential has no issuance date.
+func (h *AnchorEventHandler) checkAnchorAge(anchorRef *url.URL, vc *verifiable.Credential) error {
+	if h.maxAnchorAge <= 0 {
+		return nil
+	}
+
+	if vc.Issued == nil {
+		logger.Debug("Anchor credential has no issuance date. Skipping age check.", logfields.WithAnchorURI(anchorRef))
+
+		return nil
+	}
+
+	age := time.Since(vc.Issued.Time)
+
+	if age > h.maxAnchorAge {
+		logger.Warn("Rejecting anchor since it exceeds the maximum age", logfields.WithAnchorURI(anchorRef),
+			logfields.WithAge(age), logfields.WithMaxAge(h.maxAnchorAge))
+
+		return fmt.Errorf("anchor [%s] was issued at %s which is older than the maximum age of %s: %w",
+			anchorRef, vc.Issued.Time.Format(time.RFC3339), h.maxAnchorAge, ErrAnchorTooOld)
+	}
+
+	return nil
+}
+
 // ensureParentAnchorsAreProcessed checks all ancestors (parents, grandparents, etc.) of the given anchor event
 // and processes all that have not yet been processed.
 func (h *AnchorEventHandler) ensureParentAnchorsAreProcessed(ctx context.Context, anchorRef *url.URL, anchorLink *linkset.Link) error {
-	unprocessedParents, err := h.getUnprocessedParentAnchors(anchorRef.String(), anchorLink)
+	traversalCtx := ctx
+
+	if h.parentTraversalTimeout > 0 {
+		var cancel context.CancelFunc
+
+		traversalCtx, cancel = context.WithTimeoutCause(ctx, h.parentTraversalTimeout, ErrParentTraversalTimeout)
+		defer cancel()
+	}
+
+	unprocessedParents, err := h.getUnprocessedParentAnchors(traversalCtx, anchorRef.String(), anchorLink)
 	if err != nil {
 		return fmt.Errorf("get unprocessed parent anchors for [%s]: %w", anchorRef, err)
 	}
@@ -269,11 +616,35 @@ func (h *AnchorEventHandler) ensureParentAnchorsAreProcessed(ctx context.Context
 	return nil
 }
 
+// traversal holds the statistics of a traversal of the unprocessed ancestors of an anchor.
+type traversal struct {
+	// depth is the number of generations of unprocessed ancestors, e.g. 2 if a parent and grandparent
+	// were unprocessed.
+	depth int
+	// resolved is the number of unprocessed ancestors which were resolved.
+	resolved int
+}
+
 // getUnprocessedParentAnchors returns all unprocessed ancestors (parents, grandparents, etc.) of the given
-// anchor event, sorted by oldest to newest.
-//
+// anchor event, sorted by oldest to newest. The depth of the traversal and the number of resolved parents
+// are recorded in the metrics.
+func (h *AnchorEventHandler) getUnprocessedParentAnchors(ctx context.Context, hl string,
+	anchorLink *linkset.Link,
+) (anchorInfoSlice, error) {
+	t := &traversal{}
+
+	defer func() {
+		h.metrics.ParentAnchorTraversalDepth(t.depth)
+		h.metrics.ParentAnchorTraversalResolvedCount(t.resolved)
+	}()
+
+	return h.getUnprocessedAncestors(ctx, hl, anchorLink, 1, t)
+}
+
 //nolint:cyclop
-func (h *AnchorEventHandler) getUnprocessedParentAnchors(hl string, anchorLink *linkset.Link) (anchorInfoSlice, error) {
+func (h *AnchorEventHandler) getUnprocessedAncestors(ctx context.Context, hl string,
+	anchorLink *linkset.Link, generation int, t *traversal,
+) (anchorInfoSlice, error) {
 	logger.Debug("Getting unprocessed parents of anchor", logfields.WithAnchorURIString(hl))
 
 	if anchorLink.Related() == nil {
@@ -295,6 +666,18 @@ func (h *AnchorEventHandler) getUnprocessedParentAnchors(hl string, anchorLink *
 			relatedLink.Anchor(), hl)
 	}
 
+	err = h.checkDuplicateParents(hl, relatedLink.Up())
+	if err != nil {
+		return nil, err
+	}
+
+	if h.verifyLineage {
+		err = verifyLineage(hl, anchorLink, relatedLink.Up())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var unprocessed []*anchorInfo
 
 	for _, parentHL := range relatedLink.Up() {
@@ -305,7 +688,7 @@ func (h *AnchorEventHandler) getUnprocessedParentAnchors(hl string, anchorLink *
 			continue
 		}
 
-		processedOrPending, info, err := h.getUnprocessedParentAnchor(hl, parentHL)
+		processedOrPending, info, err := h.getUnprocessedParentAnchor(ctx, hl, parentHL)
 		if err != nil {
 			return nil, err
 		}
@@ -317,10 +700,16 @@ func (h *AnchorEventHandler) getUnprocessedParentAnchors(hl string, anchorLink *
 		logger.Debug("Adding parent of anchor event to the unprocessed list",
 			logfields.WithAnchorURIString(hl), logfields.WithParentURI(parentHL))
 
+		t.resolved++
+
+		if generation > t.depth {
+			t.depth = generation
+		}
+
 		// Add the parent to the head of the list since it needs to be processed first.
 		unprocessed = append([]*anchorInfo{info}, unprocessed...)
 
-		ancestorAnchors, err := h.getUnprocessedParentAnchors(parentHL.String(), info.anchorLink)
+		ancestorAnchors, err := h.getUnprocessedAncestors(ctx, parentHL.String(), info.anchorLink, generation+1, t)
 		if err != nil {
 			return nil, fmt.Errorf("get unprocessed anchors for parent [%s]: %w", parentHL, err)
 		}
@@ -331,10 +720,16 @@ func (h *AnchorEventHandler) getUnprocessedParentAnchors(hl string, anchorLink *
 	return unprocessed, nil
 }
 
-func (h *AnchorEventHandler) getUnprocessedParentAnchor(hl string, parentHL *url.URL) (bool, *anchorInfo, error) {
+func (h *AnchorEventHandler) getUnprocessedParentAnchor(ctx context.Context, hl string,
+	parentHL *url.URL,
+) (bool, *anchorInfo, error) {
 	logger.Debug("Checking parent of anchor to see if it has been processed",
 		logfields.WithAnchorURIString(hl), logfields.WithParentURI(parentHL))
 
+	if ctx.Err() != nil {
+		return false, nil, fmt.Errorf("check parent [%s]: %w", parentHL, context.Cause(ctx))
+	}
+
 	isProcessed, err := h.isAnchorProcessed(parentHL)
 	if err != nil {
 		return false, nil, fmt.Errorf("is anchor processed [%s]: %w", parentHL, err)
@@ -347,11 +742,16 @@ func (h *AnchorEventHandler) getUnprocessedParentAnchor(hl string, parentHL *url
 		return true, nil, nil
 	}
 
-	anchorLinksetBytes, localHL, err := h.casResolver.Resolve(nil, parentHL.String(), nil)
+	anchorLinksetBytes, localHL, err := h.resolveParent(ctx, parentHL)
 	if err != nil {
 		return false, nil, fmt.Errorf("resolve anchor [%s]: %w", parentHL, err)
 	}
 
+	err = verifyAnchorHash(parentHL, anchorLinksetBytes)
+	if err != nil {
+		return false, nil, err
+	}
+
 	parentAnchorLinkset := &linkset.Linkset{}
 
 	err = h.unmarshal(anchorLinksetBytes, parentAnchorLinkset)
@@ -365,13 +765,120 @@ func (h *AnchorEventHandler) getUnprocessedParentAnchor(hl string, parentHL *url
 		return false, nil, fmt.Errorf("parent Linkset [%s] is empty", parentHL)
 	}
 
-	return false, &anchorInfo{
-		anchorLink: parentAnchorLink,
-		AnchorInfo: &anchorinfo.AnchorInfo{
-			Hashlink:      parentHL.String(),
-			LocalHashlink: localHL,
-		},
-	}, nil
+	ai, err := h.newAnchorInfo(parentAnchorLink, &anchorinfo.AnchorInfo{
+		Hashlink:      parentHL.String(),
+		LocalHashlink: localHL,
+	})
+	if err != nil {
+		return false, nil, fmt.Errorf("parent anchor [%s]: %w", parentHL, err)
+	}
+
+	return false, ai, nil
+}
+
+// verifyLineage ensures that the previous anchors declared by the items in the given anchor are the same
+// as the given parents of the anchor.
+func verifyLineage(hl string, anchorLink *linkset.Link, parents []*url.URL) error {
+	previousHashes, err := getPreviousAnchorHashes(anchorLink)
+	if err != nil {
+		return fmt.Errorf("get previous anchors of [%s]: %w", hl, err)
+	}
+
+	parentHashes := make(map[string]struct{})
+
+	for _, parentHL := range parents {
+		parentHash, err := hashlink.GetResourceHashFromHashLink(parentHL.String())
+		if err != nil {
+			return fmt.Errorf("get resource hash from parent hashlink [%s]: %w", parentHL, err)
+		}
+
+		if _, ok := previousHashes[parentHash]; !ok {
+			return fmt.Errorf("parent [%s] of anchor [%s] isn't declared as a previous anchor by any item: %w",
+				parentHL, hl, ErrLineageMismatch)
+		}
+
+		parentHashes[parentHash] = struct{}{}
+	}
+
+	for previousHash, itemHRef := range previousHashes {
+		if _, ok := parentHashes[previousHash]; !ok {
+			return fmt.Errorf("previous anchor [%s%s] of item [%s] in anchor [%s] isn't a parent of the anchor: %w",
+				hashlink.HLPrefix, previousHash, itemHRef, hl, ErrLineageMismatch)
+		}
+	}
+
+	logger.Debug("Verified lineage of anchor", logfields.WithAnchorURIString(hl), logfields.WithTotal(len(parents)))
+
+	return nil
+}
+
+// getPreviousAnchorHashes returns the resource hashes of the previous anchors declared by the items in the
+// original content of the given anchor, mapped to the first item which declares each previous anchor.
+func getPreviousAnchorHashes(anchorLink *linkset.Link) (map[string]string, error) {
+	original := anchorLink.Original()
+	if original == nil {
+		return nil, errors.New("anchor has no original content")
+	}
+
+	contentLinkset, err := original.Linkset()
+	if err != nil {
+		return nil, fmt.Errorf("invalid original Linkset: %w", err)
+	}
+
+	previousHashes := make(map[string]string)
+
+	for _, item := range contentLinkset.Link().Items() {
+		if item.Previous() == nil {
+			// The item is a create operation so it has no previous anchor.
+			continue
+		}
+
+		previousHash, err := hashlink.GetResourceHashFromHashLink(item.Previous().String())
+		if err != nil {
+			return nil, fmt.Errorf("get resource hash from previous anchor [%s] of item [%s]: %w",
+				item.Previous(), item.HRef(), err)
+		}
+
+		if _, ok := previousHashes[previousHash]; !ok {
+			previousHashes[previousHash] = item.HRef().String()
+		}
+	}
+
+	return previousHashes, nil
+}
+
+// resolveParent resolves the given parent anchor from CAS. The resolution is bounded by the parent resolve timeout
+// and by the deadline of the given context (i.e. the parent traversal timeout). If the CAS resolver supports
+// a context then the context is passed down so that the remote fetches (and the write to the local CAS) are
+// cancelled when the timeout is exceeded.
+func (h *AnchorEventHandler) resolveParent(ctx context.Context, parentHL *url.URL) ([]byte, string, error) {
+	if h.parentResolveTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeoutCause(ctx, h.parentResolveTimeout, ErrParentResolveTimeout)
+		defer cancel()
+	}
+
+	var (
+		content []byte
+		localHL string
+		err     error
+	)
+
+	if r, ok := h.casResolver.(contextCASResolver); ok {
+		content, localHL, err = r.ResolveContext(ctx, nil, parentHL.String(), nil)
+	} else {
+		content, localHL, err = h.casResolver.Resolve(nil, parentHL.String(), nil)
+	}
+
+	if ctx.Err() != nil {
+		logger.Warn("Timed out resolving parent anchor", logfields.WithParentURI(parentHL),
+			log.WithError(context.Cause(ctx)))
+
+		return nil, "", context.Cause(ctx)
+	}
+
+	return content, localHL, err
 }
 
 func prependAnchors(existingAnchors, newAnchors []*anchorInfo) []*anchorInfo {
@@ -387,6 +894,45 @@ func prependAnchors(existingAnchors, newAnchors []*anchorInfo) []*anchorInfo {
 	return resultingAnchors
 }
 
+// checkDuplicateParents applies the duplicate-parent policy to the given parents of an anchor. (With the dedup
+// and warn policies, the duplicates are skipped while traversing the parents.)
+func (h *AnchorEventHandler) checkDuplicateParents(hl string, parents []*url.URL) error {
+	if h.duplicateParentPolicy == DuplicateParentsDedup {
+		return nil
+	}
+
+	duplicates := getDuplicateParents(parents)
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	if h.duplicateParentPolicy == DuplicateParentsReject {
+		return fmt.Errorf("parents %s of anchor [%s]: %w", duplicates, hl, ErrDuplicateParents)
+	}
+
+	logger.Warn("Anchor contains duplicate parents. The duplicates will be ignored.",
+		logfields.WithAnchorURIString(hl), logfields.WithParents(duplicates))
+
+	return nil
+}
+
+func getDuplicateParents(parents []*url.URL) []string {
+	var duplicates []string
+
+	seen := make(map[string]bool, len(parents))
+
+	for _, parentHL := range parents {
+		duplicate, ok := seen[parentHL.String()]
+		if ok && !duplicate {
+			duplicates = append(duplicates, parentHL.String())
+		}
+
+		seen[parentHL.String()] = ok
+	}
+
+	return duplicates
+}
+
 func containsAnchor(existingAnchors []*anchorInfo, hl string) bool {
 	for _, anchor := range existingAnchors {
 		if anchor.Hashlink == hl {
@@ -417,9 +963,21 @@ func (h *AnchorEventHandler) isAnchorProcessed(hl *url.URL) (bool, error) {
 	return false, nil
 }
 
+// verifyAnchorHash ensures that the resource hash in the given anchor hashlink matches the hash of the
+// given (canonicalized) anchor content. ErrHashMismatch is returned if the hashes don't match.
+func verifyAnchorHash(anchorRef *url.URL, content []byte) error {
+	err := hashlink.VerifyResourceHash(anchorRef.String(), content)
+	if err != nil {
+		return fmt.Errorf("verify hash of anchor [%s]: %w", anchorRef, err)
+	}
+
+	return nil
+}
+
 type anchorInfo struct {
 	*anchorinfo.AnchorInfo
 	anchorLink *linkset.Link
+	vc         *verifiable.Credential
 }
 
 type anchorInfoSlice []*anchorInfo
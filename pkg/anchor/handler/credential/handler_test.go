@@ -13,11 +13,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"runtime"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/logutil-go/pkg/log"
 
@@ -28,12 +32,18 @@ import (
 	servicemocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset"
 	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator"
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator/didorbgenerator"
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator/didorbtestgenerator"
+	"github.com/trustbloc/orb/pkg/anchor/builder"
 	"github.com/trustbloc/orb/pkg/anchor/handler/mocks"
 	"github.com/trustbloc/orb/pkg/anchor/info"
 	anchormocks "github.com/trustbloc/orb/pkg/anchor/mocks"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	casresolver "github.com/trustbloc/orb/pkg/cas/resolver"
+	"github.com/trustbloc/orb/pkg/datauri"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/linkset"
@@ -108,6 +118,121 @@ func TestAnchorCredentialHandler(t *testing.T) {
 		require.NoError(t, json.Unmarshal([]byte(sampleGrandparentAnchorEvent), anchorEvent))
 		require.NoError(t, handler.HandleAnchorEvent(context.Background(), actor, anchorEvent.URL()[0], actor, anchorEvent))
 	})
+
+	t.Run("Hashlink mismatch - embedded anchor Linkset", func(t *testing.T) {
+		casStore := createInMemoryCAS(t)
+
+		handler := newAnchorEventHandler(t, casStore)
+
+		anchorEvent := &vocab.AnchorEventType{}
+		require.NoError(t, json.Unmarshal([]byte(sampleGrandparentAnchorEvent), anchorEvent))
+
+		// The URL of the parent anchor event doesn't correspond to the embedded (grandparent) content.
+		parentAnchorEvent := &vocab.AnchorEventType{}
+		require.NoError(t, json.Unmarshal([]byte(sampleParentAnchorEvent), parentAnchorEvent))
+
+		err := handler.HandleAnchorEvent(context.Background(), actor, parentAnchorEvent.URL()[0], actor, anchorEvent)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrHashMismatch))
+		require.Contains(t, err.Error(), "expected resource hash [uEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw]")
+		require.Contains(t, err.Error(), "computed [uEiBbrGQaKfwyeY294rBhw43j0JxUIZZR9VTsxH2iG9riqg]")
+
+		// The content must not have been stored in the CAS.
+		_, err = casStore.Read("uEiBbrGQaKfwyeY294rBhw43j0JxUIZZR9VTsxH2iG9riqg")
+		require.Error(t, err)
+	})
+
+	t.Run("Hashlink mismatch - fetched anchor Linkset", func(t *testing.T) {
+		casResolver := &mocks2.CASResolver{}
+		casResolver.ResolveReturns([]byte(testutil.GetCanonical(t, sampleGrandparentAnchorLinkset)), "", nil)
+
+		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, generator.NewRegistry())
+
+		parentAnchorEvent := &vocab.AnchorEventType{}
+		require.NoError(t, json.Unmarshal([]byte(sampleParentAnchorEvent), parentAnchorEvent))
+
+		err := handler.HandleAnchorEvent(context.Background(), actor, parentAnchorEvent.URL()[0], nil, nil)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrHashMismatch))
+		require.Contains(t, err.Error(), "expected resource hash [uEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw]")
+	})
+}
+
+func TestAnchorCredentialHandler_AnchorFetchPolicy(t *testing.T) {
+	actor := testutil.MustParseURL("https://domain1.com/services/orb")
+
+	anchorEvent := &vocab.AnchorEventType{}
+	require.NoError(t, json.Unmarshal([]byte(sampleGrandparentAnchorEvent), anchorEvent))
+
+	anchorLinksetBytes := []byte(testutil.GetCanonical(t, sampleGrandparentAnchorLinkset))
+
+	newCASResolver := func() *mocks2.CASResolver {
+		casResolver := &mocks2.CASResolver{}
+		casResolver.ResolveReturns(anchorLinksetBytes, "", nil)
+
+		return casResolver
+	}
+
+	t.Run("Prefer embedded (default) -> embedded anchor linkset is used", func(t *testing.T) {
+		casResolver := newCASResolver()
+		publisher := &anchormocks.AnchorPublisher{}
+
+		handler := New(publisher, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, generator.NewRegistry())
+		require.Equal(t, AnchorFetchPreferEmbedded, handler.anchorFetchPolicy)
+
+		require.NoError(t, handler.HandleAnchorEvent(context.Background(), actor, anchorEvent.URL()[0], actor, anchorEvent))
+		require.Equal(t, 1, casResolver.ResolveCallCount())
+		require.Equal(t, 1, publisher.PublishAnchorCallCount())
+
+		_, _, data := casResolver.ResolveArgsForCall(0)
+		require.Equal(t, anchorLinksetBytes, data)
+	})
+
+	t.Run("Always fetch -> embedded anchor linkset is ignored", func(t *testing.T) {
+		casResolver := newCASResolver()
+		publisher := &anchormocks.AnchorPublisher{}
+
+		handler := New(publisher, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, generator.NewRegistry(), WithAnchorFetchPolicy(AnchorFetchAlways))
+
+		require.NoError(t, handler.HandleAnchorEvent(context.Background(), actor, anchorEvent.URL()[0], actor, anchorEvent))
+		require.Equal(t, 1, casResolver.ResolveCallCount())
+		require.Equal(t, 1, publisher.PublishAnchorCallCount())
+
+		_, hl, data := casResolver.ResolveArgsForCall(0)
+		require.Equal(t, anchorEvent.URL()[0].String(), hl)
+		require.Nil(t, data)
+	})
+
+	t.Run("Always fetch -> fetched anchor linkset is verified", func(t *testing.T) {
+		casResolver := &mocks2.CASResolver{}
+		casResolver.ResolveReturns([]byte(testutil.GetCanonical(t, sampleParentAnchorLinkset)), "", nil)
+
+		publisher := &anchormocks.AnchorPublisher{}
+
+		handler := New(publisher, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, generator.NewRegistry(), WithAnchorFetchPolicy(AnchorFetchAlways))
+
+		err := handler.HandleAnchorEvent(context.Background(), actor, anchorEvent.URL()[0], actor, anchorEvent)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrHashMismatch)
+		require.Zero(t, publisher.PublishAnchorCallCount())
+	})
+
+	t.Run("Always fetch -> locally submitted anchor uses embedded anchor linkset", func(t *testing.T) {
+		casResolver := newCASResolver()
+
+		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, generator.NewRegistry(), WithAnchorFetchPolicy(AnchorFetchAlways))
+
+		require.NoError(t, handler.HandleAnchorEvent(context.Background(), nil, anchorEvent.URL()[0], nil, anchorEvent))
+		require.Equal(t, 1, casResolver.ResolveCallCount())
+
+		_, _, data := casResolver.ResolveArgsForCall(0)
+		require.Equal(t, anchorLinksetBytes, data)
+	})
 }
 
 func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
@@ -123,8 +248,10 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 		casResolver := &mocks2.CASResolver{}
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
+		metrics := &traversalMetrics{}
+
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, registry)
+			anchorLinkStore, registry, WithMetrics(metrics))
 		require.NotNil(t, handler)
 
 		anchorEvent := &vocab.AnchorEventType{}
@@ -140,9 +267,11 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 		require.NoError(t, vocab.UnmarshalFromDoc(anchorLinksetDoc, anchorLinkset))
 		require.NotNil(t, anchorLinkset.Link())
 
-		parents, err := handler.getUnprocessedParentAnchors(hl, anchorLinkset.Link())
+		parents, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLinkset.Link())
 		require.NoError(t, err)
 		require.Empty(t, parents)
+		require.Equal(t, []int{0}, metrics.depths)
+		require.Equal(t, []int{0}, metrics.resolvedCounts)
 	})
 
 	t.Run("Two parents unprocessed -> Success", func(t *testing.T) {
@@ -156,8 +285,10 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 		casResolver.ResolveReturnsOnCall(1, []byte(testutil.GetCanonical(t, sampleGrandparentAnchorLinkset)),
 			grandparentHL, nil)
 
+		metrics := &traversalMetrics{}
+
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, registry)
+			anchorLinkStore, registry, WithMetrics(metrics))
 		require.NotNil(t, handler)
 
 		anchorEvent := &vocab.AnchorEventType{}
@@ -171,19 +302,25 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 		require.NoError(t, vocab.UnmarshalFromDoc(anchorLinksetDoc, anchorLinkset))
 		require.NotNil(t, anchorLinkset.Link())
 
-		parents, err := handler.getUnprocessedParentAnchors(hl, anchorLinkset.Link())
+		parents, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLinkset.Link())
 		require.NoError(t, err)
 		require.Len(t, parents, 2)
 		require.Equal(t, grandparentHL, parents[0].Hashlink)
 		require.Equal(t, parentHL, parents[1].Hashlink)
+
+		// The parent and grandparent were resolved, i.e. two generations of ancestors.
+		require.Equal(t, []int{2}, metrics.depths)
+		require.Equal(t, []int{2}, metrics.resolvedCounts)
 	})
 
 	t.Run("Duplicate parents -> Success", func(t *testing.T) {
 		casResolver := &mocks2.CASResolver{}
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
+		metrics := &traversalMetrics{}
+
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, registry)
+			anchorLinkStore, registry, WithMetrics(metrics))
 		require.NotNil(t, handler)
 
 		anchorLinkStore.GetProcessedAndPendingLinksReturns(nil, nil)
@@ -193,9 +330,156 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 		anchorLinkset := &linkset.Linkset{}
 		require.NoError(t, json.Unmarshal([]byte(sampleAnchorLinksetDuplicateParents), anchorLinkset))
 
-		parents, err := handler.getUnprocessedParentAnchors(hl, anchorLinkset.Link())
+		parents, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLinkset.Link())
 		require.NoError(t, err)
 		require.Len(t, parents, 1)
+
+		// The duplicate parent is only resolved once.
+		require.Equal(t, []int{1}, metrics.depths)
+		require.Equal(t, []int{1}, metrics.resolvedCounts)
+	})
+
+	t.Run("Duplicate parents, warn policy -> Success", func(t *testing.T) {
+		casResolver := &mocks2.CASResolver{}
+		anchorLinkStore := &mocks.AnchorLinkStore{}
+
+		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
+			anchorLinkStore, registry, WithDuplicateParentPolicy(DuplicateParentsWarn))
+		require.NotNil(t, handler)
+
+		anchorLinkStore.GetProcessedAndPendingLinksReturns(nil, nil)
+
+		casResolver.ResolveReturns([]byte(testutil.GetCanonical(t, sampleGrandparentAnchorLinkset)), grandparentHL, nil)
+
+		anchorLinkset := &linkset.Linkset{}
+		require.NoError(t, json.Unmarshal([]byte(sampleAnchorLinksetDuplicateParents), anchorLinkset))
+
+		parents, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLinkset.Link())
+		require.NoError(t, err)
+		require.Len(t, parents, 1)
+		require.Equal(t, 1, casResolver.ResolveCallCount())
+	})
+
+	t.Run("Duplicate parents, reject policy -> Error", func(t *testing.T) {
+		casResolver := &mocks2.CASResolver{}
+		anchorLinkStore := &mocks.AnchorLinkStore{}
+
+		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
+			anchorLinkStore, registry, WithDuplicateParentPolicy(DuplicateParentsReject))
+		require.NotNil(t, handler)
+
+		anchorLinkset := &linkset.Linkset{}
+		require.NoError(t, json.Unmarshal([]byte(sampleAnchorLinksetDuplicateParents), anchorLinkset))
+
+		_, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLinkset.Link())
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrDuplicateParents)
+		require.Contains(t, err.Error(), grandparentHL)
+
+		// The anchor is rejected before any of the parents are resolved.
+		require.Zero(t, casResolver.ResolveCallCount())
+	})
+
+	t.Run("No duplicate parents, reject policy -> Success", func(t *testing.T) {
+		casResolver := &mocks2.CASResolver{}
+		anchorLinkStore := &mocks.AnchorLinkStore{}
+
+		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
+			anchorLinkStore, registry, WithDuplicateParentPolicy(DuplicateParentsReject))
+		require.NotNil(t, handler)
+
+		anchorLinkStore.GetProcessedAndPendingLinksReturns(nil, nil)
+
+		casResolver.ResolveReturns([]byte(testutil.GetCanonical(t, sampleGrandparentAnchorLinkset)), grandparentHL, nil)
+
+		anchorLinkset := &linkset.Linkset{}
+		require.NoError(t, json.Unmarshal([]byte(sampleParentAnchorLinkset), anchorLinkset))
+
+		parents, err := handler.getUnprocessedParentAnchors(context.Background(), parentHL, anchorLinkset.Link())
+		require.NoError(t, err)
+		require.Len(t, parents, 1)
+	})
+
+	t.Run("Parent hashlink mismatch -> Error", func(t *testing.T) {
+		casResolver := &mocks2.CASResolver{}
+		anchorLinkStore := &mocks.AnchorLinkStore{}
+
+		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
+			anchorLinkStore, registry)
+		require.NotNil(t, handler)
+
+		anchorLinkStore.GetProcessedAndPendingLinksReturns(nil, nil)
+
+		casResolver.ResolveReturns([]byte(testutil.GetCanonical(t, sampleParentAnchorLinkset)), grandparentHL, nil)
+
+		anchorLinkset := &linkset.Linkset{}
+		require.NoError(t, json.Unmarshal([]byte(sampleAnchorLinksetDuplicateParents), anchorLinkset))
+
+		_, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLinkset.Link())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrHashMismatch))
+	})
+
+	t.Run("Lineage verification -> Success", func(t *testing.T) {
+		casResolver := &mocks2.CASResolver{}
+		anchorLinkStore := &mocks.AnchorLinkStore{}
+
+		anchorLinkStore.GetProcessedAndPendingLinksReturns(nil, nil)
+
+		casResolver.ResolveReturnsOnCall(0, []byte(testutil.GetCanonical(t, sampleParentAnchorLinkset)),
+			parentHL, nil)
+		casResolver.ResolveReturnsOnCall(1, []byte(testutil.GetCanonical(t, sampleGrandparentAnchorLinkset)),
+			grandparentHL, nil)
+
+		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
+			anchorLinkStore, registry, WithLineageVerification(true))
+		require.NotNil(t, handler)
+
+		anchorEvent := &vocab.AnchorEventType{}
+
+		require.NoError(t, json.Unmarshal([]byte(sampleAnchorEvent), anchorEvent))
+
+		anchorLinkset := &linkset.Linkset{}
+		require.NoError(t, vocab.UnmarshalFromDoc(anchorEvent.Object().Document(), anchorLinkset))
+
+		parents, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLinkset.Link())
+		require.NoError(t, err)
+		require.Len(t, parents, 2)
+	})
+
+	t.Run("Item declares wrong previous anchor", func(t *testing.T) {
+		anchorLinkset := &linkset.Linkset{}
+		require.NoError(t, json.Unmarshal([]byte(sampleAnchorLinksetWrongPrevious), anchorLinkset))
+
+		t.Run("Lineage verification enabled -> Error", func(t *testing.T) {
+			casResolver := &mocks2.CASResolver{}
+			anchorLinkStore := &mocks.AnchorLinkStore{}
+
+			handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
+				anchorLinkStore, registry, WithLineageVerification(true))
+			require.NotNil(t, handler)
+
+			_, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLinkset.Link())
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLineageMismatch)
+			require.Contains(t, err.Error(), "parent ["+parentHL+"]")
+			require.Equal(t, 0, casResolver.ResolveCallCount())
+		})
+
+		t.Run("Lineage verification disabled -> Success", func(t *testing.T) {
+			casResolver := &mocks2.CASResolver{}
+			anchorLinkStore := &mocks.AnchorLinkStore{}
+
+			anchorLinkStore.GetProcessedAndPendingLinksReturns([]*url.URL{vocab.MustParseURL(parentHL)}, nil)
+
+			handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
+				anchorLinkStore, registry)
+			require.NotNil(t, handler)
+
+			parents, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLinkset.Link())
+			require.NoError(t, err)
+			require.Empty(t, parents)
+		})
 	})
 
 	t.Run("Unmarshal -> Error", func(t *testing.T) {
@@ -203,7 +487,7 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, registry)
+			anchorLinkStore, registry)
 		require.NotNil(t, handler)
 
 		errExpected := errors.New("injected unmarshal error")
@@ -225,9 +509,9 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 
 		anchorLinkStore.GetProcessedAndPendingLinksReturns(nil, nil)
 
-		casResolver.ResolveReturns([]byte(testutil.GetCanonical(t, sampleAnchorEvent)), grandparentHL, nil)
+		casResolver.ResolveReturns([]byte(testutil.GetCanonical(t, sampleGrandparentAnchorLinkset)), grandparentHL, nil)
 
-		_, err := handler.getUnprocessedParentAnchors(hl, anchorLink)
+		_, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLink)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), errExpected.Error())
 	})
@@ -237,7 +521,7 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, registry)
+			anchorLinkStore, registry)
 		require.NotNil(t, handler)
 
 		anchorLinkset := &linkset.Linkset{}
@@ -245,7 +529,7 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 
 		anchorLinkStore.GetProcessedAndPendingLinksReturns(nil, nil)
 
-		_, err := handler.getUnprocessedParentAnchors(parentHL, anchorLinkset.Link())
+		_, err := handler.getUnprocessedParentAnchors(context.Background(), parentHL, anchorLinkset.Link())
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "must start with 'hl:' prefix")
 	})
@@ -255,7 +539,7 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, registry)
+			anchorLinkStore, registry)
 		require.NotNil(t, handler)
 
 		errExpected := errors.New("injected GetLinks error")
@@ -265,7 +549,7 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 		anchorLinkset := &linkset.Linkset{}
 		require.NoError(t, json.Unmarshal([]byte(sampleParentAnchorLinkset), anchorLinkset))
 
-		_, err := handler.getUnprocessedParentAnchors(parentHL, anchorLinkset.Link())
+		_, err := handler.getUnprocessedParentAnchors(context.Background(), parentHL, anchorLinkset.Link())
 		require.Error(t, err)
 		require.Contains(t, err.Error(), errExpected.Error())
 	})
@@ -275,7 +559,7 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, registry)
+			anchorLinkStore, registry)
 		require.NotNil(t, handler)
 
 		anchorLinkset := &linkset.Linkset{}
@@ -285,19 +569,191 @@ func TestGetUnprocessedParentAnchorEvents(t *testing.T) {
 
 		casResolver.ResolveReturns(nil, "", errExpected)
 
-		_, err := handler.getUnprocessedParentAnchors(parentHL, anchorLinkset.Link())
+		_, err := handler.getUnprocessedParentAnchors(context.Background(), parentHL, anchorLinkset.Link())
 		require.Error(t, err)
 		require.Contains(t, err.Error(), errExpected.Error())
 	})
 }
 
+func TestVerifyLineage(t *testing.T) {
+	const (
+		hl            = "hl:uEiAWJO75bnXrNTn3QWUj4ey1iTV_yYI4FuqxSlbCU0dAfQ"
+		parentHL      = "hl:uEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw"
+		grandparentHL = "hl:uEiBbrGQaKfwyeY294rBhw43j0JxUIZZR9VTsxH2iG9riqg"
+	)
+
+	anchorEvent := &vocab.AnchorEventType{}
+	require.NoError(t, json.Unmarshal([]byte(sampleAnchorEvent), anchorEvent))
+
+	anchorLinkset := &linkset.Linkset{}
+	require.NoError(t, vocab.UnmarshalFromDoc(anchorEvent.Object().Document(), anchorLinkset))
+
+	anchorLink := anchorLinkset.Link()
+
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, verifyLineage(hl, anchorLink, []*url.URL{vocab.MustParseURL(parentHL)}))
+	})
+
+	t.Run("Undeclared parent -> Error", func(t *testing.T) {
+		err := verifyLineage(hl, anchorLink, []*url.URL{
+			vocab.MustParseURL(parentHL), vocab.MustParseURL(grandparentHL),
+		})
+		require.ErrorIs(t, err, ErrLineageMismatch)
+		require.Contains(t, err.Error(), "parent ["+grandparentHL+"]")
+	})
+
+	t.Run("Previous anchor isn't a parent -> Error", func(t *testing.T) {
+		err := verifyLineage(hl, anchorLink, nil)
+		require.ErrorIs(t, err, ErrLineageMismatch)
+		require.Contains(t, err.Error(), "previous anchor ["+parentHL+"]")
+	})
+
+	t.Run("Invalid parent hashlink -> Error", func(t *testing.T) {
+		err := verifyLineage(hl, anchorLink, []*url.URL{vocab.MustParseURL("https://domain1.com")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get resource hash from parent hashlink")
+	})
+
+	t.Run("No original content -> Error", func(t *testing.T) {
+		err := verifyLineage(hl, linkset.NewAnchorLink(vocab.MustParseURL(hl), nil, nil, nil), nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor has no original content")
+	})
+}
+
+func TestAnchorCredentialHandler_ParentTimeouts(t *testing.T) {
+	const (
+		hl            = "hl:uEiAWJO75bnXrNTn3QWUj4ey1iTV_yYI4FuqxSlbCU0dAfQ:uoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQVdKTzc1Ym5Yck5UbjNRV1VqNGV5MWlUVl95WUk0RnVxeFNsYkNVMGRBZlF4QmlwZnM6Ly9iYWZrcmVpYXdldHhwczN0djVtMnR0NTJibXVyNmQzZnZyZTJ4N3NtY2hhbG92bWtrazNiZmdyMmFwdQ"
+		parentHL      = "hl:uEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw:uoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQUNqaXZlNzdoZmJpRmVWMld6MzU2TllpS00yN1MzMUZyRGxTQ2xiaEFCSHd4QmlwZnM6Ly9iYWZrcmVpYWNyeXY1NTM1eWw1eGNjeHN4bXd6NTdodW5taXJpenc1dXc3a2Z2cTR2ZWNzdzRlYWJkNA"
+		grandparentHL = "hl:uEiBbrGQaKfwyeY294rBhw43j0JxUIZZR9VTsxH2iG9riqg:uoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQmJyR1FhS2Z3eWVZMjk0ckJodzQzajBKeFVJWlpSOVZUc3hIMmlHOXJpcWd4QmlwZnM6Ly9iYWZrcmVpYzN2cnNidWtwNGdqNHkzcHBjd2JxNGhkcGQyY29maWltd2toMnZqM2dlcHdyYnh3eGN2aQ"
+	)
+
+	registry := generator.NewRegistry()
+
+	anchorRef, err := url.Parse(hl)
+	require.NoError(t, err)
+
+	anchorEvent := &vocab.AnchorEventType{}
+	require.NoError(t, json.Unmarshal([]byte(sampleAnchorEvent), anchorEvent))
+
+	anchorLinkset := &linkset.Linkset{}
+	require.NoError(t, vocab.UnmarshalFromDoc(anchorEvent.Object().Document(), anchorLinkset))
+	require.NotNil(t, anchorLinkset.Link())
+
+	// newSlowCASResolver returns a CAS resolver which resolves the parent and then the grandparent,
+	// taking the given amount of time for each.
+	newSlowCASResolver := func(delay time.Duration) *mocks2.CASResolver {
+		casResolver := &mocks2.CASResolver{}
+
+		casResolver.ResolveStub = func(*url.URL, string, []byte) ([]byte, string, error) {
+			time.Sleep(delay)
+
+			if casResolver.ResolveCallCount() == 1 {
+				return []byte(testutil.GetCanonical(t, sampleParentAnchorLinkset)), parentHL, nil
+			}
+
+			return []byte(testutil.GetCanonical(t, sampleGrandparentAnchorLinkset)), grandparentHL, nil
+		}
+
+		return casResolver
+	}
+
+	t.Run("Within timeouts -> Success", func(t *testing.T) {
+		anchorLinkStore := &mocks.AnchorLinkStore{}
+
+		handler := New(&anchormocks.AnchorPublisher{}, newSlowCASResolver(10*time.Millisecond),
+			testutil.GetLoader(t), anchorLinkStore, registry,
+			WithParentResolveTimeout(time.Second),
+			WithParentTraversalTimeout(2*time.Second),
+		)
+
+		parents, err := handler.getUnprocessedParentAnchors(context.Background(), hl, anchorLinkset.Link())
+		require.NoError(t, err)
+		require.Len(t, parents, 2)
+	})
+
+	t.Run("Parent resolve timeout -> Error", func(t *testing.T) {
+		handler := New(&anchormocks.AnchorPublisher{}, newSlowCASResolver(500*time.Millisecond),
+			testutil.GetLoader(t), &mocks.AnchorLinkStore{}, registry,
+			WithParentResolveTimeout(50*time.Millisecond),
+			WithParentTraversalTimeout(5*time.Second),
+		)
+
+		err := handler.ensureParentAnchorsAreProcessed(context.Background(), anchorRef, anchorLinkset.Link())
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrParentResolveTimeout)
+		require.NotErrorIs(t, err, ErrParentTraversalTimeout)
+	})
+
+	t.Run("Parent traversal timeout -> Error", func(t *testing.T) {
+		// Each parent resolves within the parent resolve timeout but, together, the parent and grandparent
+		// exceed the traversal timeout.
+		casResolver := newSlowCASResolver(200 * time.Millisecond)
+
+		handler := New(&anchormocks.AnchorPublisher{}, casResolver,
+			testutil.GetLoader(t), &mocks.AnchorLinkStore{}, registry,
+			WithParentResolveTimeout(time.Second),
+			WithParentTraversalTimeout(300*time.Millisecond),
+		)
+
+		err := handler.ensureParentAnchorsAreProcessed(context.Background(), anchorRef, anchorLinkset.Link())
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrParentTraversalTimeout)
+		require.NotErrorIs(t, err, ErrParentResolveTimeout)
+		require.Equal(t, 2, casResolver.ResolveCallCount())
+	})
+
+	t.Run("Parent resolve timeout -> resolution is cancelled", func(t *testing.T) {
+		casResolver := &blockingCASResolver{}
+
+		handler := New(&anchormocks.AnchorPublisher{}, casResolver,
+			testutil.GetLoader(t), &mocks.AnchorLinkStore{}, registry,
+			WithParentResolveTimeout(50*time.Millisecond),
+		)
+
+		numGoroutines := runtime.NumGoroutine()
+
+		err := handler.ensureParentAnchorsAreProcessed(context.Background(), anchorRef, anchorLinkset.Link())
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrParentResolveTimeout)
+		require.Equal(t, int32(1), atomic.LoadInt32(&casResolver.cancelled))
+
+		// Poll (rather than use require.Eventually, which runs the condition in its own goroutine) to ensure
+		// that the resolution didn't leave a goroutine running.
+		for i := 0; runtime.NumGoroutine() > numGoroutines && i < 100; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		require.LessOrEqual(t, runtime.NumGoroutine(), numGoroutines)
+	})
+}
+
+// blockingCASResolver blocks until the context passed to ResolveContext is done.
+type blockingCASResolver struct {
+	cancelled int32
+}
+
+func (r *blockingCASResolver) Resolve(*url.URL, string, []byte) ([]byte, string, error) {
+	return nil, "", errors.New("not expected to be called")
+}
+
+func (r *blockingCASResolver) ResolveContext(ctx context.Context, _ *url.URL, _ string,
+	_ []byte,
+) ([]byte, string, error) {
+	<-ctx.Done()
+
+	atomic.AddInt32(&r.cancelled, 1)
+
+	return nil, "", ctx.Err()
+}
+
 func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		casResolver := &mocks2.CASResolver{}
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, generator.NewRegistry())
+			anchorLinkStore, generator.NewRegistry())
 		require.NotNil(t, handler)
 
 		anchorLinkset := &linkset.Linkset{}
@@ -306,13 +762,13 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 		ls, err := anchorLinkset.Link().Original().Linkset()
 		require.NoError(t, err)
 
-		err = handler.processAnchorEvent(context.Background(), &anchorInfo{
-			AnchorInfo: &info.AnchorInfo{
-				Hashlink: ls.Link().Anchor().String(),
-			},
-			anchorLink: anchorLinkset.Link(),
+		ai, err := handler.newAnchorInfo(anchorLinkset.Link(), &info.AnchorInfo{
+			Hashlink: ls.Link().Anchor().String(),
 		})
 		require.NoError(t, err)
+
+		err = handler.processAnchorEvent(context.Background(), ai)
+		require.NoError(t, err)
 	})
 
 	t.Run("already processed -> success", func(t *testing.T) {
@@ -320,7 +776,7 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, generator.NewRegistry())
+			anchorLinkStore, generator.NewRegistry())
 		require.NotNil(t, handler)
 
 		anchorLinkset := &linkset.Linkset{}
@@ -333,13 +789,13 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 
 		anchorLinkStore.GetProcessedAndPendingLinksReturns([]*url.URL{anchorRef}, nil)
 
-		err = handler.processAnchorEvent(context.Background(), &anchorInfo{
-			AnchorInfo: &info.AnchorInfo{
-				Hashlink: anchorRef.String(),
-			},
-			anchorLink: anchorLinkset.Link(),
+		ai, err := handler.newAnchorInfo(anchorLinkset.Link(), &info.AnchorInfo{
+			Hashlink: anchorRef.String(),
 		})
 		require.NoError(t, err)
+
+		err = handler.processAnchorEvent(context.Background(), ai)
+		require.NoError(t, err)
 	})
 
 	t.Run("is processed -> error", func(t *testing.T) {
@@ -347,7 +803,7 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, generator.NewRegistry())
+			anchorLinkStore, generator.NewRegistry())
 		require.NotNil(t, handler)
 
 		anchorLinkset := &linkset.Linkset{}
@@ -362,12 +818,12 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 
 		anchorLinkStore.GetProcessedAndPendingLinksReturns(nil, errExpected)
 
-		err = handler.processAnchorEvent(context.Background(), &anchorInfo{
-			AnchorInfo: &info.AnchorInfo{
-				Hashlink: anchorRef.String(),
-			},
-			anchorLink: anchorLinkset.Link(),
+		ai, err := handler.newAnchorInfo(anchorLinkset.Link(), &info.AnchorInfo{
+			Hashlink: anchorRef.String(),
 		})
+		require.NoError(t, err)
+
+		err = handler.processAnchorEvent(context.Background(), ai)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), errExpected.Error())
 	})
@@ -377,16 +833,13 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, generator.NewRegistry())
+			anchorLinkStore, generator.NewRegistry())
 		require.NotNil(t, handler)
 
 		anchorLinkset := &linkset.Linkset{}
 		require.NoError(t, json.Unmarshal([]byte(anchorLinksetNoReplies), anchorLinkset))
 
-		err := handler.processAnchorEvent(context.Background(), &anchorInfo{
-			AnchorInfo: &info.AnchorInfo{},
-			anchorLink: anchorLinkset.Link(),
-		})
+		_, err := handler.newAnchorInfo(anchorLinkset.Link(), &info.AnchorInfo{})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no replies in anchor link")
 	})
@@ -396,16 +849,13 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, generator.NewRegistry())
+			anchorLinkStore, generator.NewRegistry())
 		require.NotNil(t, handler)
 
 		anchorLinkset := &linkset.Linkset{}
 		require.NoError(t, json.Unmarshal([]byte(anchorLinksetInvalidContent), anchorLinkset))
 
-		err := handler.processAnchorEvent(context.Background(), &anchorInfo{
-			AnchorInfo: &info.AnchorInfo{},
-			anchorLink: anchorLinkset.Link(),
-		})
+		_, err := handler.newAnchorInfo(anchorLinkset.Link(), &info.AnchorInfo{})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "unsupported media type")
 	})
@@ -415,16 +865,16 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, generator.NewRegistry())
+			anchorLinkStore, generator.NewRegistry())
 		require.NotNil(t, handler)
 
 		anchorLinkset := &linkset.Linkset{}
 		require.NoError(t, json.Unmarshal([]byte(anchorLinksetUnsupportedProfile), anchorLinkset))
 
-		err := handler.processAnchorEvent(context.Background(), &anchorInfo{
-			AnchorInfo: &info.AnchorInfo{},
-			anchorLink: anchorLinkset.Link(),
-		})
+		ai, err := handler.newAnchorInfo(anchorLinkset.Link(), &info.AnchorInfo{})
+		require.NoError(t, err)
+
+		err = handler.processAnchorEvent(context.Background(), ai)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "generator not found")
 	})
@@ -434,16 +884,16 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 		anchorLinkStore := &mocks.AnchorLinkStore{}
 
 		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, generator.NewRegistry())
+			anchorLinkStore, generator.NewRegistry())
 		require.NotNil(t, handler)
 
 		anchorLinkset := &linkset.Linkset{}
 		require.NoError(t, json.Unmarshal([]byte(anchorLinksetInvalidVC), anchorLinkset))
 
-		err := handler.processAnchorEvent(context.Background(), &anchorInfo{
-			AnchorInfo: &info.AnchorInfo{},
-			anchorLink: anchorLinkset.Link(),
-		})
+		ai, err := handler.newAnchorInfo(anchorLinkset.Link(), &info.AnchorInfo{})
+		require.NoError(t, err)
+
+		err = handler.processAnchorEvent(context.Background(), ai)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "validate credential subject for anchor")
 	})
@@ -459,7 +909,7 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 		publisher.PublishAnchorReturns(errExpected)
 
 		handler := New(publisher, casResolver, testutil.GetLoader(t),
-			time.Second, anchorLinkStore, generator.NewRegistry())
+			anchorLinkStore, generator.NewRegistry())
 		require.NotNil(t, handler)
 
 		anchorLinkset := &linkset.Linkset{}
@@ -468,18 +918,251 @@ func TestAnchorEventHandler_processAnchorEvent(t *testing.T) {
 		ls, err := anchorLinkset.Link().Original().Linkset()
 		require.NoError(t, err)
 
-		err = handler.processAnchorEvent(context.Background(), &anchorInfo{
-			AnchorInfo: &info.AnchorInfo{
-				Hashlink: ls.Link().Anchor().String(),
-			},
-			anchorLink: anchorLinkset.Link(),
+		ai, err := handler.newAnchorInfo(anchorLinkset.Link(), &info.AnchorInfo{
+			Hashlink: ls.Link().Anchor().String(),
 		})
+		require.NoError(t, err)
+
+		err = handler.processAnchorEvent(context.Background(), ai)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), errExpected.Error())
 	})
 }
 
-func newAnchorEventHandler(t *testing.T, client extendedcasclient.Client) *AnchorEventHandler {
+func TestAnchorCredentialHandler_StrictValidation(t *testing.T) {
+	payload := &subject.Payload{
+		OperationCount:  1,
+		CoreIndex:       "hl:uEiBqkaTRFZScQsXTw8IDBSpVxiKGqjJCDUcgiwpcd2frLw",
+		Namespace:       "did:orb",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "suffix"}},
+	}
+
+	anchorLink, _, err := anchorlinkset.NewBuilder(
+		generator.NewRegistry()).BuildAnchorLink(payload, datauri.MediaTypeDataURIGzipBase64,
+		func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error) {
+			return &verifiable.Credential{
+				Types:   []string{"VerifiableCredential", "AnchorCredential"},
+				Context: []string{vocab.ContextCredentials, vocab.ContextActivityAnchors},
+				Subject: &builder.CredentialSubject{
+					HRef:    anchorHashlink,
+					Type:    []string{"AnchorLink"},
+					Profile: "https://w3id.org/orb#v0",
+					Anchor:  coreIndexHashlink,
+					Rel:     "linkset",
+				},
+				Issuer: verifiable.Issuer{
+					ID: "https://domain1.com/services/orb",
+				},
+				Issued: &util.TimeWrapper{Time: time.Now()},
+				// This field isn't defined in any of the JSON-LD contexts.
+				CustomFields: verifiable.CustomFields{"undefinedField": "value"},
+			}, nil
+		},
+	)
+	require.NoError(t, err)
+
+	anchorInfo := &info.AnchorInfo{Hashlink: "hl:uEiAtvFg7Ti4-0MquG-sFMGRDcGUwz22JpCmOksomNTQGXw"}
+
+	t.Run("Strict (default) -> error", func(t *testing.T) {
+		handler := newAnchorEventHandler(t, createInMemoryCAS(t))
+		require.True(t, handler.strictValidation)
+
+		_, err := handler.newAnchorInfo(anchorLink, anchorInfo)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed get verifiable credential from anchor link")
+	})
+
+	t.Run("Lax -> success", func(t *testing.T) {
+		handler := newAnchorEventHandler(t, createInMemoryCAS(t), WithStrictValidation(false))
+		require.False(t, handler.strictValidation)
+
+		ai, err := handler.newAnchorInfo(anchorLink, anchorInfo)
+		require.NoError(t, err)
+
+		require.NoError(t, handler.processAnchorEvent(context.Background(), ai))
+	})
+}
+
+func TestAnchorCredentialHandler_MaxAnchorAge(t *testing.T) {
+	actor := testutil.MustParseURL("https://domain1.com/services/orb")
+
+	newAnchor := func(t *testing.T, issued time.Time) (*url.URL, *linkset.Link, *mocks2.CASResolver) {
+		t.Helper()
+
+		anchorLink := newTestAnchorLink(t, issued)
+
+		anchorLinksetBytes, err := json.Marshal(linkset.New(anchorLink))
+		require.NoError(t, err)
+
+		hl, err := hashlink.New().CreateHashLink(anchorLinksetBytes, nil)
+		require.NoError(t, err)
+
+		casResolver := &mocks2.CASResolver{}
+		casResolver.ResolveReturns(anchorLinksetBytes, "", nil)
+
+		return testutil.MustParseURL(hl), anchorLink, casResolver
+	}
+
+	t.Run("Not configured (default) -> success", func(t *testing.T) {
+		anchorRef, _, casResolver := newAnchor(t, time.Now().Add(-24*time.Hour))
+
+		publisher := &anchormocks.AnchorPublisher{}
+
+		handler := New(publisher, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, generator.NewRegistry())
+		require.Zero(t, handler.maxAnchorAge)
+
+		require.NoError(t, handler.HandleAnchorEvent(context.Background(), actor, anchorRef, nil, nil))
+		require.Equal(t, 1, publisher.PublishAnchorCallCount())
+	})
+
+	t.Run("Recent anchor -> success", func(t *testing.T) {
+		anchorRef, _, casResolver := newAnchor(t, time.Now().Add(-time.Minute))
+
+		publisher := &anchormocks.AnchorPublisher{}
+
+		handler := New(publisher, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, generator.NewRegistry(), WithMaxAnchorAge(time.Hour))
+
+		require.NoError(t, handler.HandleAnchorEvent(context.Background(), actor, anchorRef, nil, nil))
+		require.Equal(t, 1, publisher.PublishAnchorCallCount())
+	})
+
+	t.Run("Announced anchor too old -> error", func(t *testing.T) {
+		anchorRef, _, casResolver := newAnchor(t, time.Now().Add(-2*time.Hour))
+
+		publisher := &anchormocks.AnchorPublisher{}
+		anchorLinkStore := &mocks.AnchorLinkStore{}
+
+		handler := New(publisher, casResolver, testutil.GetLoader(t),
+			anchorLinkStore, generator.NewRegistry(), WithMaxAnchorAge(time.Hour))
+
+		err := handler.HandleAnchorEvent(context.Background(), actor, anchorRef, nil, nil)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrAnchorTooOld))
+		require.Contains(t, err.Error(), "older than the maximum age of 1h0m0s")
+
+		require.Zero(t, publisher.PublishAnchorCallCount())
+		require.Zero(t, anchorLinkStore.PutPendingLinksCallCount())
+	})
+
+	t.Run("Old parent anchor -> success", func(t *testing.T) {
+		// Parents are processed to complete the lineage of an anchor and therefore aren't subject to the age check.
+		anchorRef, anchorLink, casResolver := newAnchor(t, time.Now().Add(-2*time.Hour))
+
+		publisher := &anchormocks.AnchorPublisher{}
+
+		handler := New(publisher, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, generator.NewRegistry(), WithMaxAnchorAge(time.Hour))
+
+		ai, err := handler.newAnchorInfo(anchorLink, &info.AnchorInfo{Hashlink: anchorRef.String()})
+		require.NoError(t, err)
+
+		require.NoError(t, handler.processAnchorEvent(context.Background(), ai))
+		require.Equal(t, 1, publisher.PublishAnchorCallCount())
+	})
+}
+
+func TestAnchorCredentialHandler_AcceptedProfiles(t *testing.T) {
+	actor := testutil.MustParseURL("https://domain1.com/services/orb")
+
+	// The anchor has the https://w3id.org/orb#v0 profile.
+	anchorLink := newTestAnchorLink(t, time.Now())
+
+	anchorLinksetBytes, err := json.Marshal(linkset.New(anchorLink))
+	require.NoError(t, err)
+
+	hl, err := hashlink.New().CreateHashLink(anchorLinksetBytes, nil)
+	require.NoError(t, err)
+
+	anchorRef := testutil.MustParseURL(hl)
+
+	casResolver := &mocks2.CASResolver{}
+	casResolver.ResolveReturns(anchorLinksetBytes, "", nil)
+
+	t.Run("Profile accepted -> success", func(t *testing.T) {
+		publisher := &anchormocks.AnchorPublisher{}
+
+		handler := New(publisher, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, generator.NewRegistry(),
+			WithAcceptedProfiles(
+				testutil.MustParseURL(didorbtestgenerator.ID), testutil.MustParseURL(didorbgenerator.ID),
+			),
+		)
+
+		require.NoError(t, handler.HandleAnchorEvent(context.Background(), actor, anchorRef, nil, nil))
+		require.Equal(t, 1, publisher.PublishAnchorCallCount())
+	})
+
+	t.Run("Registered profile not accepted -> error", func(t *testing.T) {
+		registry := generator.NewRegistry()
+
+		// The profile is known to the registry but it's not in the accepted set.
+		_, err := registry.Get(anchorLink.Profile())
+		require.NoError(t, err)
+
+		publisher := &anchormocks.AnchorPublisher{}
+
+		handler := New(publisher, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, registry,
+			WithAcceptedProfiles(testutil.MustParseURL(didorbtestgenerator.ID)),
+		)
+
+		err = handler.HandleAnchorEvent(context.Background(), actor, anchorRef, nil, nil)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrProfileNotAccepted)
+		require.Contains(t, err.Error(), didorbgenerator.ID)
+		require.Zero(t, publisher.PublishAnchorCallCount())
+	})
+
+	t.Run("Parent profile not accepted -> error", func(t *testing.T) {
+		handler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
+			&mocks.AnchorLinkStore{}, generator.NewRegistry(),
+			WithAcceptedProfiles(testutil.MustParseURL(didorbtestgenerator.ID)),
+		)
+
+		// Parent anchors are checked when their anchor info is created during the traversal of the lineage.
+		_, err := handler.newAnchorInfo(anchorLink, &info.AnchorInfo{Hashlink: hl})
+		require.ErrorIs(t, err, ErrProfileNotAccepted)
+	})
+}
+
+func newTestAnchorLink(t *testing.T, issued time.Time) *linkset.Link {
+	t.Helper()
+
+	payload := &subject.Payload{
+		OperationCount:  1,
+		CoreIndex:       "hl:uEiBqkaTRFZScQsXTw8IDBSpVxiKGqjJCDUcgiwpcd2frLw",
+		Namespace:       "did:orb",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "suffix"}},
+	}
+
+	anchorLink, _, err := anchorlinkset.NewBuilder(
+		generator.NewRegistry()).BuildAnchorLink(payload, datauri.MediaTypeDataURIGzipBase64,
+		func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error) {
+			return &verifiable.Credential{
+				Types:   []string{"VerifiableCredential", "AnchorCredential"},
+				Context: []string{vocab.ContextCredentials, vocab.ContextActivityAnchors},
+				Subject: &builder.CredentialSubject{
+					HRef:    anchorHashlink,
+					Type:    []string{"AnchorLink"},
+					Profile: "https://w3id.org/orb#v0",
+					Anchor:  coreIndexHashlink,
+					Rel:     "linkset",
+				},
+				Issuer: verifiable.Issuer{
+					ID: "https://domain1.com/services/orb",
+				},
+				Issued: &util.TimeWrapper{Time: issued},
+			}, nil
+		},
+	)
+	require.NoError(t, err)
+
+	return anchorLink
+}
+
+func newAnchorEventHandler(t *testing.T, client extendedcasclient.Client, opts ...Opt) *AnchorEventHandler {
 	t.Helper()
 
 	casResolver := casresolver.New(client, nil,
@@ -492,7 +1175,7 @@ func newAnchorEventHandler(t *testing.T, client extendedcasclient.Client) *Ancho
 	anchorLinkStore := &mocks.AnchorLinkStore{}
 
 	anchorEventHandler := New(&anchormocks.AnchorPublisher{}, casResolver, testutil.GetLoader(t),
-		time.Second, anchorLinkStore, generator.NewRegistry())
+		anchorLinkStore, generator.NewRegistry(), opts...)
 	require.NotNil(t, anchorEventHandler)
 
 	return anchorEventHandler
@@ -735,7 +1418,7 @@ const sampleAnchorLinksetDuplicateParents = `{
       ],
       "related": [
         {
-          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiBpFIScGjmr9GEs2-WIQ-SYZZdfsN_iePnO4kxtRR9A5Q%22%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%2C%22up%22%3A%5B%7B%22href%22%3A%22hl%3AuEiDB-Nh4kxP2UIzjC-oLbEaW4bHCYaFC9WgvPtbtMtvMuA%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpREItTmg0a3hQMlVJempDLW9MYkVhVzRiSENZYUZDOVdndlB0YnRNdHZNdUF4QmlwZnM6Ly9iYWZrcmVpZ2I3ZG1ocmV5dDZ6aWl6eXlsNWlmd3lydXc0Z3k0ZXluYmlsMndxbHo2MjN3dGZ3Nm14YQ%22%7D%2C%7B%22href%22%3A%22hl%3AuEiDB-Nh4kxP2UIzjC-oLbEaW4bHCYaFC9WgvPtbtMtvMuA%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpREItTmg0a3hQMlVJempDLW9MYkVhVzRiSENZYUZDOVdndlB0YnRNdHZNdUF4QmlwZnM6Ly9iYWZrcmVpZ2I3ZG1ocmV5dDZ6aWl6eXlsNWlmd3lydXc0Z3k0ZXluYmlsMndxbHo2MjN3dGZ3Nm14YQ%22%7D%5D%2C%22via%22%3A%5B%7B%22href%22%3A%22hl%3AuEiBRe-7-dP9BuarMgsnh0ORnGWi6moc4GmQet-pQUeJjLQ%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQlJlLTctZFA5QnVhck1nc25oME9SbkdXaTZtb2M0R21RZXQtcFFVZUpqTFF4QmlwZnM6Ly9iYWZrcmVpY3JwcHhwNDVoN2lnNDJ2dGVjemhxNWJ6ZGhkZnVsdmd1aGhhbmdpaHZ4NWppZmR5dGRmdQ%22%7D%5D%7D%5D%7D",
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiBpFIScGjmr9GEs2-WIQ-SYZZdfsN_iePnO4kxtRR9A5Q%22%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%2C%22up%22%3A%5B%7B%22href%22%3A%22hl%3AuEiBbrGQaKfwyeY294rBhw43j0JxUIZZR9VTsxH2iG9riqg%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQmJyR1FhS2Z3eWVZMjk0ckJodzQzajBKeFVJWlpSOVZUc3hIMmlHOXJpcWd4QmlwZnM6Ly9iYWZrcmVpYzN2cnNidWtwNGdqNHkzcHBjd2JxNGhkcGQyY29maWltd2toMnZqM2dlcHdyYnh3eGN2aQ%22%7D%2C%7B%22href%22%3A%22hl%3AuEiBbrGQaKfwyeY294rBhw43j0JxUIZZR9VTsxH2iG9riqg%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQmJyR1FhS2Z3eWVZMjk0ckJodzQzajBKeFVJWlpSOVZUc3hIMmlHOXJpcWd4QmlwZnM6Ly9iYWZrcmVpYzN2cnNidWtwNGdqNHkzcHBjd2JxNGhkcGQyY29maWltd2toMnZqM2dlcHdyYnh3eGN2aQ%22%7D%5D%2C%22via%22%3A%5B%7B%22href%22%3A%22hl%3AuEiBRe-7-dP9BuarMgsnh0ORnGWi6moc4GmQet-pQUeJjLQ%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQlJlLTctZFA5QnVhck1nc25oME9SbkdXaTZtb2M0R21RZXQtcFFVZUpqTFF4QmlwZnM6Ly9iYWZrcmVpY3JwcHhwNDVoN2lnNDJ2dGVjemhxNWJ6ZGhkZnVsdmd1aGhhbmdpaHZ4NWppZmR5dGRmdQ%22%7D%5D%7D%5D%7D",
           "type": "application/linkset+json"
         }
       ],
@@ -749,6 +1432,38 @@ const sampleAnchorLinksetDuplicateParents = `{
   ]
 }`
 
+// sampleAnchorLinksetWrongPrevious is an anchor whose parent (in "up") is the parent anchor but whose item
+// declares the grandparent anchor as its previous anchor.
+const sampleAnchorLinksetWrongPrevious = `{
+  "linkset": [
+    {
+      "anchor": "hl:uEiB3AqvXGpKzq4Bt3DmHvgz60GlyvT48N7jFHaWrwMgfXg",
+      "profile": [
+        {
+          "href": "https://w3id.org/orb#v0"
+        }
+      ],
+      "author": [
+        {
+          "href": "https://orb.domain1.com/services/orb"
+        }
+      ],
+      "original": [
+        {
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiD11DddgN59q5AeAl-HVBC-Jo5T9qx-EuH8XtH3rNJoIg%22%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%2C%22author%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Forb.domain1.com%2Fservices%2Forb%22%7D%5D%2C%22item%22%3A%5B%7B%22href%22%3A%22did%3Aorb%3AuEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw%3AEiAbvz2BZUmsqc2ZO5Fzhd04kCeuy31fzbZxH4Em_0RZ9Q%22%2C%22previous%22%3A%5B%22hl%3AuEiBbrGQaKfwyeY294rBhw43j0JxUIZZR9VTsxH2iG9riqg%22%5D%7D%5D%7D%5D%7D",
+          "type": "application/linkset+json"
+        }
+      ],
+      "related": [
+        {
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiB3AqvXGpKzq4Bt3DmHvgz60GlyvT48N7jFHaWrwMgfXg%22%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%2C%22up%22%3A%5B%7B%22href%22%3A%22hl%3AuEiACjive77hfbiFeV2Wz356NYiKM27S31FrDlSClbhABHw%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQUNqaXZlNzdoZmJpRmVWMld6MzU2TllpS00yN1MzMUZyRGxTQ2xiaEFCSHd4QmlwZnM6Ly9iYWZrcmVpYWNyeXY1NTM1eWw1eGNjeHN4bXd6NTdodW5taXJpenc1dXc3a2Z2cTR2ZWNzdzRlYWJkNA%22%7D%5D%2C%22via%22%3A%5B%7B%22href%22%3A%22hl%3AuEiD11DddgN59q5AeAl-HVBC-Jo5T9qx-EuH8XtH3rNJoIg%22%7D%5D%7D%5D%7D",
+          "type": "application/linkset+json"
+        }
+      ]
+    }
+  ]
+}`
+
 const sampleAnchorLinksetInvalidParent = `{
   "linkset": [
     {
@@ -922,3 +1637,41 @@ const anchorLinksetInvalidVC = `{
     }
   ]
 }`
+
+// traversalMetrics records the parent anchor traversal metrics.
+type traversalMetrics struct {
+	depths         []int
+	resolvedCounts []int
+}
+
+func (m *traversalMetrics) ParentAnchorTraversalDepth(depth int) {
+	m.depths = append(m.depths, depth)
+}
+
+func (m *traversalMetrics) ParentAnchorTraversalResolvedCount(count int) {
+	m.resolvedCounts = append(m.resolvedCounts, count)
+}
+
+func TestParseAnchorFetchPolicy(t *testing.T) {
+	for _, value := range []string{"prefer-embedded", "always-fetch"} {
+		policy, err := ParseAnchorFetchPolicy(value)
+		require.NoError(t, err)
+		require.Equal(t, AnchorFetchPolicy(value), policy)
+	}
+
+	_, err := ParseAnchorFetchPolicy("never-fetch")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported anchor fetch policy [never-fetch]")
+}
+
+func TestParseDuplicateParentPolicy(t *testing.T) {
+	for _, value := range []string{"dedup", "warn", "reject"} {
+		policy, err := ParseDuplicateParentPolicy(value)
+		require.NoError(t, err)
+		require.Equal(t, DuplicateParentPolicy(value), policy)
+	}
+
+	_, err := ParseDuplicateParentPolicy("ignore")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported duplicate-parent policy [ignore]")
+}
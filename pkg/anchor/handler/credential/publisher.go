@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package credential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+// ErrPublishQueueFull is returned (as a transient error) when an anchor can't be published because the publish
+// queue is full and a slot didn't become available within the configured wait time.
+var ErrPublishQueueFull = errors.New("anchor publish queue is full")
+
+// boundedPublisher wraps an anchor publisher and limits the number of anchors which may be published concurrently.
+// When the limit is reached, a publish either waits for a slot to become available or fails with a transient
+// error so that the anchor event is retried later. An anchor is never dropped.
+type boundedPublisher struct {
+	target anchorPublisher
+	slots  chan struct{}
+	wait   time.Duration
+}
+
+func newBoundedPublisher(target anchorPublisher, size int, wait time.Duration) *boundedPublisher {
+	return &boundedPublisher{
+		target: target,
+		slots:  make(chan struct{}, size),
+		wait:   wait,
+	}
+}
+
+// PublishAnchor publishes the given anchor to the target publisher once a slot in the queue is available.
+func (p *boundedPublisher) PublishAnchor(ctx context.Context, anchorInfo *anchorinfo.AnchorInfo) error {
+	if err := p.acquire(ctx); err != nil {
+		return err
+	}
+
+	defer p.release()
+
+	return p.target.PublishAnchor(ctx, anchorInfo)
+}
+
+func (p *boundedPublisher) acquire(ctx context.Context) error {
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if p.wait == 0 {
+		return orberrors.NewTransient(ErrPublishQueueFull)
+	}
+
+	// A negative wait time means that we wait until a slot is available or the context is done.
+	var timeout <-chan time.Time
+
+	if p.wait > 0 {
+		timer := time.NewTimer(p.wait)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-timeout:
+		return orberrors.NewTransient(ErrPublishQueueFull)
+	case <-ctx.Done():
+		return orberrors.NewTransient(fmt.Errorf("wait for slot in publish queue: %w", ctx.Err()))
+	}
+}
+
+func (p *boundedPublisher) release() {
+	<-p.slots
+}
@@ -0,0 +1,246 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package credential
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator"
+	"github.com/trustbloc/orb/pkg/anchor/handler/mocks"
+	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
+	anchormocks "github.com/trustbloc/orb/pkg/anchor/mocks"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/linkset"
+	mocks2 "github.com/trustbloc/orb/pkg/protocolversion/mocks"
+)
+
+func TestBoundedPublisher(t *testing.T) {
+	t.Run("Queue full, no wait -> transient error", func(t *testing.T) {
+		target := newSlowPublisher()
+
+		p := newBoundedPublisher(target, 2, 0)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				require.NoError(t, p.PublishAnchor(context.Background(), newAnchorInfo(i)))
+			}(i)
+		}
+
+		target.waitForInFlight(t, 2)
+
+		err := p.PublishAnchor(context.Background(), newAnchorInfo(2))
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.ErrorIs(t, err, ErrPublishQueueFull)
+
+		target.unblock()
+		wg.Wait()
+
+		// The anchor is published when it's retried.
+		require.NoError(t, p.PublishAnchor(context.Background(), newAnchorInfo(2)))
+
+		require.ElementsMatch(t, []string{"hl:0", "hl:1", "hl:2"}, target.publishedAnchors())
+	})
+
+	t.Run("Queue full, wait for slot -> success", func(t *testing.T) {
+		const numAnchors = 10
+
+		target := newSlowPublisher()
+
+		p := newBoundedPublisher(target, 2, 5*time.Second)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < numAnchors; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				require.NoError(t, p.PublishAnchor(context.Background(), newAnchorInfo(i)))
+			}(i)
+		}
+
+		target.waitForInFlight(t, 2)
+
+		// The queue is full so none of the other anchors may be published until a slot is released.
+		time.Sleep(50 * time.Millisecond)
+		require.Equal(t, 2, target.maxInFlight())
+
+		target.unblock()
+		wg.Wait()
+
+		require.Len(t, target.publishedAnchors(), numAnchors)
+		require.Equal(t, 2, target.maxInFlight())
+	})
+
+	t.Run("Queue full, wait timeout -> transient error", func(t *testing.T) {
+		target := newSlowPublisher()
+		defer target.unblock()
+
+		p := newBoundedPublisher(target, 1, 50*time.Millisecond)
+
+		go func() {
+			require.NoError(t, p.PublishAnchor(context.Background(), newAnchorInfo(0)))
+		}()
+
+		target.waitForInFlight(t, 1)
+
+		err := p.PublishAnchor(context.Background(), newAnchorInfo(1))
+		require.True(t, orberrors.IsTransient(err))
+		require.ErrorIs(t, err, ErrPublishQueueFull)
+	})
+
+	t.Run("Queue full, wait until context done -> transient error", func(t *testing.T) {
+		target := newSlowPublisher()
+		defer target.unblock()
+
+		p := newBoundedPublisher(target, 1, -1)
+
+		go func() {
+			require.NoError(t, p.PublishAnchor(context.Background(), newAnchorInfo(0)))
+		}()
+
+		target.waitForInFlight(t, 1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := p.PublishAnchor(ctx, newAnchorInfo(1))
+		require.True(t, orberrors.IsTransient(err))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Target error -> slot released", func(t *testing.T) {
+		errExpected := errors.New("injected publish error")
+
+		target := &anchormocks.AnchorPublisher{}
+		target.PublishAnchorReturns(errExpected)
+
+		p := newBoundedPublisher(target, 1, 0)
+
+		require.ErrorIs(t, p.PublishAnchor(context.Background(), newAnchorInfo(0)), errExpected)
+		require.ErrorIs(t, p.PublishAnchor(context.Background(), newAnchorInfo(1)), errExpected)
+	})
+}
+
+func TestAnchorCredentialHandler_PublishQueue(t *testing.T) {
+	anchorLinkset := &linkset.Linkset{}
+	require.NoError(t, json.Unmarshal([]byte(sampleGrandparentAnchorLinkset), anchorLinkset))
+
+	ls, err := anchorLinkset.Link().Original().Linkset()
+	require.NoError(t, err)
+
+	target := newSlowPublisher()
+	anchorLinkStore := &mocks.AnchorLinkStore{}
+
+	handler := New(target, &mocks2.CASResolver{}, testutil.GetLoader(t), anchorLinkStore,
+		generator.NewRegistry(), WithPublishQueue(1, 0))
+
+	ai, err := handler.newAnchorInfo(anchorLinkset.Link(), &anchorinfo.AnchorInfo{
+		Hashlink: ls.Link().Anchor().String(),
+	})
+	require.NoError(t, err)
+
+	go func() {
+		require.NoError(t, handler.processAnchorEvent(context.Background(), ai))
+	}()
+
+	target.waitForInFlight(t, 1)
+
+	err = handler.processAnchorEvent(context.Background(), ai)
+	require.Error(t, err)
+	require.True(t, orberrors.IsTransient(err))
+	require.ErrorIs(t, err, ErrPublishQueueFull)
+
+	// The pending link must be deleted so that the anchor is processed when the anchor event is retried.
+	require.Equal(t, 1, anchorLinkStore.DeletePendingLinksCallCount())
+
+	target.unblock()
+}
+
+// slowPublisher blocks all publishes until it's unblocked.
+type slowPublisher struct {
+	mutex     sync.Mutex
+	published []string
+	inFlight  int
+	max       int
+	blocked   chan struct{}
+	once      sync.Once
+}
+
+func newSlowPublisher() *slowPublisher {
+	return &slowPublisher{blocked: make(chan struct{})}
+}
+
+func (p *slowPublisher) PublishAnchor(_ context.Context, anchorInfo *anchorinfo.AnchorInfo) error {
+	p.mutex.Lock()
+	p.inFlight++
+
+	if p.inFlight > p.max {
+		p.max = p.inFlight
+	}
+	p.mutex.Unlock()
+
+	<-p.blocked
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.inFlight--
+	p.published = append(p.published, anchorInfo.Hashlink)
+
+	return nil
+}
+
+func (p *slowPublisher) unblock() {
+	p.once.Do(func() { close(p.blocked) })
+}
+
+func (p *slowPublisher) waitForInFlight(t *testing.T, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+
+		return p.inFlight == n
+	}, 5*time.Second, 5*time.Millisecond)
+}
+
+func (p *slowPublisher) maxInFlight() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.max
+}
+
+func (p *slowPublisher) publishedAnchors() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]string{}, p.published...)
+}
+
+func newAnchorInfo(i int) *anchorinfo.AnchorInfo {
+	return &anchorinfo.AnchorInfo{Hashlink: fmt.Sprintf("hl:%d", i)}
+}
@@ -49,14 +49,40 @@ type metricsProvider interface {
 	WitnessAnchorCredentialTime(duration time.Duration)
 }
 
+// Opt is a proof handler option.
+type Opt func(h *WitnessProofHandler)
+
+// WithDataURIAutoThreshold sets the size (in bytes) at or above which the content of a data URI is compressed
+// when the data URI media type is datauri.MediaTypeDataURIAuto.
+func WithDataURIAutoThreshold(value int) Opt {
+	return func(h *WitnessProofHandler) {
+		h.dataURIOpts = append(h.dataURIOpts, datauri.WithAutoThreshold(value))
+	}
+}
+
 // New creates new proof handler.
-func New(providers *Providers, pubSub pubSub, dataURIMediaType datauri.MediaType, maxClockSkew time.Duration) *WitnessProofHandler {
-	return &WitnessProofHandler{
+func New(providers *Providers, pubSub pubSub, dataURIMediaType datauri.MediaType, maxClockSkew time.Duration,
+	opts ...Opt,
+) *WitnessProofHandler {
+	var pending pendingAnchors = &noopPendingAnchors{}
+
+	if providers.PendingAnchors != nil {
+		pending = providers.PendingAnchors
+	}
+
+	h := &WitnessProofHandler{
 		Providers:        providers,
 		publisher:        vcpubsub.NewPublisher(pubSub),
 		dataURIMediaType: dataURIMediaType,
 		maxClockSkew:     maxClockSkew,
+		pendingAnchors:   pending,
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 // Providers contains the providers required by the handler.
@@ -68,6 +94,9 @@ type Providers struct {
 	MonitoringSvc   monitoringSvc
 	DocLoader       ld.DocumentLoader
 	Metrics         metricsProvider
+
+	// PendingAnchors (optional) tracks the anchors that are waiting for sufficient witness proofs.
+	PendingAnchors pendingAnchors
 }
 
 // WitnessProofHandler handles an anchor credential witness proof.
@@ -75,7 +104,9 @@ type WitnessProofHandler struct {
 	*Providers
 	publisher        anchorLinkPublisher
 	dataURIMediaType vocab.MediaType
+	dataURIOpts      []datauri.Opt
 	maxClockSkew     time.Duration
+	pendingAnchors   pendingAnchors
 }
 
 type witnessStore interface {
@@ -100,6 +131,11 @@ type witnessPolicy interface {
 	Evaluate(witnesses []*proofapi.WitnessProof) (bool, error)
 }
 
+type pendingAnchors interface {
+	Update(anchorID string, proofCount int)
+	Remove(anchorID string)
+}
+
 // HandleProof handles proof.
 func (h *WitnessProofHandler) HandleProof(ctx context.Context, witness *url.URL, anchor string, endTime time.Time, proof []byte) error {
 	logger.Debug("Received proof for anchor from witness", logfields.WithAnchorURIString(anchor),
@@ -160,6 +196,8 @@ func (h *WitnessProofHandler) HandleProof(ctx context.Context, witness *url.URL,
 			logfields.WithActorIRI(witness), logfields.WithProof(proof))
 
 		// witness policy has been satisfied and witness proofs added to verifiable credential - nothing to do
+		h.pendingAnchors.Remove(anchor)
+
 		return nil
 	}
 
@@ -207,9 +245,13 @@ func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLin
 		logger.Info("Witness policy has not been satisfied for anchor. Waiting for other proofs.",
 			logfields.WithAnchorURIString(anchorID))
 
+		h.pendingAnchors.Update(anchorID, countWitnessDomains(witnessProofs))
+
 		return nil
 	}
 
+	h.pendingAnchors.Remove(anchorID)
+
 	// Witness policy has been satisfied so add witness proofs to anchor, set 'complete' status for anchor
 	// publish witnessed anchor to batch writer channel for further processing
 	logger.Info("Witness policy has been satisfied for anchor", logfields.WithAnchorURIString(anchorID),
@@ -246,7 +288,7 @@ func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLin
 		return fmt.Errorf("create new object with document: %w", err)
 	}
 
-	vcDataURI, err := datauri.New(vcBytes, h.dataURIMediaType)
+	vcDataURI, err := datauri.New(vcBytes, h.dataURIMediaType, h.dataURIOpts...)
 	if err != nil {
 		return fmt.Errorf("create data URI from VC: %w", err)
 	}
@@ -279,6 +321,21 @@ func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLin
 	return nil
 }
 
+// countWitnessDomains returns the number of distinct witness domains from which a proof has been received.
+func countWitnessDomains(witnessProofs []*proofapi.WitnessProof) int {
+	domains := make(map[string]struct{})
+
+	for _, wp := range witnessProofs {
+		if wp.Proof == nil || wp.Witness == nil || wp.URI == nil || wp.URI.URL() == nil {
+			continue
+		}
+
+		domains[wp.URI.URL().Host] = struct{}{}
+	}
+
+	return len(domains)
+}
+
 func addProofs(vc *verifiable.Credential, proofs []*proofapi.WitnessProof) (*verifiable.Credential, error) {
 	for _, p := range proofs {
 		if p.Proof != nil {
@@ -338,3 +395,9 @@ func add(values []string, value string) []string {
 
 	return append(values, value)
 }
+
+type noopPendingAnchors struct{}
+
+func (p *noopPendingAnchors) Update(string, int) {}
+
+func (p *noopPendingAnchors) Remove(string) {}
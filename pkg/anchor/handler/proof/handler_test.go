@@ -19,6 +19,7 @@ import (
 
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/anchor/handler/mocks"
+	"github.com/trustbloc/orb/pkg/anchor/witness/pending"
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
 	policymocks "github.com/trustbloc/orb/pkg/anchor/witness/policy/mocks"
 	proofapi "github.com/trustbloc/orb/pkg/anchor/witness/proof"
@@ -56,6 +57,10 @@ func TestNew(t *testing.T) {
 
 	c := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew)
 	require.NotNil(t, c)
+
+	c = New(providers, ps, datauri.MediaTypeDataURIAuto, defaultClockSkew, WithDataURIAutoThreshold(1000))
+	require.NotNil(t, c)
+	require.Len(t, c.dataURIOpts, 1)
 }
 
 //nolint:maintidx
@@ -218,6 +223,77 @@ func TestWitnessProofHandler(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("success - pending anchors", func(t *testing.T) {
+		aeStore, err := anchorlinkstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		als := &linkset.Linkset{}
+		require.NoError(t, json.Unmarshal([]byte(anchorLinksetTwoProofs), als))
+
+		al := als.Link()
+		require.NotNil(t, al)
+
+		err = aeStore.Put(al)
+		require.NoError(t, err)
+
+		statusStore, err := anchorstatus.New(mem.NewProvider(), testutil.GetTaskMgr(t), testutil.GetExpiryService(t), time.Minute)
+		require.NoError(t, err)
+
+		err = statusStore.AddStatus(al.Anchor().String(), proofapi.AnchorIndexStatusInProcess)
+		require.NoError(t, err)
+
+		witnessStore := &mocks.WitnessStore{}
+		witnessStore.GetReturns(
+			[]*proofapi.WitnessProof{
+				{
+					Witness: &proofapi.Witness{
+						Type:   proofapi.WitnessTypeSystem,
+						URI:    vocab.NewURLProperty(witness1IRI),
+						HasLog: true,
+					},
+					Proof: []byte(witnessProofJSONWebSignature),
+				},
+				{
+					Witness: &proofapi.Witness{
+						Type: proofapi.WitnessTypeBatch,
+						URI:  vocab.NewURLProperty(witness2IRI),
+					},
+				},
+			}, nil)
+
+		witnessPolicy := &mockWitnessPolicy{eval: false}
+		pendingAnchors := pending.New(&orbmocks.MetricsProvider{})
+
+		providers := &Providers{
+			AnchorLinkStore: aeStore,
+			StatusStore:     statusStore,
+			WitnessStore:    witnessStore,
+			WitnessPolicy:   witnessPolicy,
+			Metrics:         &orbmocks.MetricsProvider{},
+			DocLoader:       testutil.GetLoader(t),
+			PendingAnchors:  pendingAnchors,
+		}
+
+		proofHandler := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew)
+
+		err = proofHandler.HandleProof(context.Background(), witness1IRI, al.Anchor().String(), expiryTime,
+			[]byte(witnessProofJSONWebSignature))
+		require.NoError(t, err)
+
+		anchors := pendingAnchors.List()
+		require.Len(t, anchors, 1)
+		require.Equal(t, al.Anchor().String(), anchors[0].AnchorID)
+		require.Equal(t, 1, anchors[0].ProofCount)
+
+		witnessPolicy.eval = true
+
+		err = proofHandler.HandleProof(context.Background(), witness1IRI, al.Anchor().String(), expiryTime,
+			[]byte(witnessProofJSONWebSignature))
+		require.NoError(t, err)
+
+		require.Empty(t, pendingAnchors.List())
+	})
+
 	t.Run("success - status is completed", func(t *testing.T) {
 		aeStore, err := anchorlinkstore.New(mem.NewProvider())
 		require.NoError(t, err)
@@ -892,6 +968,21 @@ type mockWitnessPolicy struct {
 	Err  error
 }
 
+func TestCountWitnessDomains(t *testing.T) {
+	witness1IRI := testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	witness2IRI := testutil.MustParseURL("https://orb.domain2.com/services/orb")
+	witness3IRI := testutil.MustParseURL("https://orb.domain1.com/services/orb2")
+
+	require.Equal(t, 0, countWitnessDomains(nil))
+	require.Equal(t, 2, countWitnessDomains([]*proofapi.WitnessProof{
+		{Witness: &proofapi.Witness{URI: vocab.NewURLProperty(witness1IRI)}, Proof: []byte("proof1")},
+		{Witness: &proofapi.Witness{URI: vocab.NewURLProperty(witness2IRI)}, Proof: []byte("proof2")},
+		{Witness: &proofapi.Witness{URI: vocab.NewURLProperty(witness3IRI)}, Proof: []byte("proof3")},
+		{Witness: &proofapi.Witness{URI: vocab.NewURLProperty(witness2IRI)}},
+		{Proof: []byte("proof4")},
+	}))
+}
+
 func (wp *mockWitnessPolicy) Evaluate(_ []*proofapi.WitnessProof) (bool, error) {
 	if wp.Err != nil {
 		return false, wp.Err
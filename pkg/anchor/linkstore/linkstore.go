@@ -30,6 +30,11 @@ const (
 	expiryTimeTag = "expiryTime"
 
 	defaultPendingRecordLifespan = 24 * time.Hour
+
+	// defaultProcessedAnchorTTL is the default amount of time that a processed anchor reference is retained. This
+	// value is much longer than any plausible redelivery window (see defaultPendingRecordLifespan and the message
+	// queue redelivery settings) so that an anchor is never reprocessed because its record was pruned.
+	defaultProcessedAnchorTTL = 30 * 24 * time.Hour
 )
 
 var logger = log.New("anchor-ref-store")
@@ -40,6 +45,7 @@ type dataExpiryService interface {
 
 type options struct {
 	pendingRecordLifespan time.Duration
+	processedAnchorTTL    time.Duration
 }
 
 // Opt is a link store option.
@@ -53,10 +59,22 @@ func WithPendingRecordLifespan(value time.Duration) Opt {
 	}
 }
 
+// WithProcessedAnchorTTL sets the amount of time that a processed anchor reference is retained, after which
+// it is pruned by the data expiry service. Processed anchor references are used to determine whether or not
+// an anchor was already processed, so the TTL must be longer than the period in which an anchor may be
+// redelivered. Note that processed anchor references are also returned as alternate links in WebFinger
+// queries. If set to 0 then processed anchor references are never pruned.
+func WithProcessedAnchorTTL(value time.Duration) Opt {
+	return func(opts *options) {
+		opts.processedAnchorTTL = value
+	}
+}
+
 // New creates a new anchor link store.
 func New(provider storage.Provider, expiryService dataExpiryService, opts ...Opt) (*Store, error) {
 	options := &options{
 		pendingRecordLifespan: defaultPendingRecordLifespan,
+		processedAnchorTTL:    defaultProcessedAnchorTTL,
 	}
 
 	for _, opt := range opts {
@@ -135,7 +153,8 @@ func (s *Store) putLinks(links []*url.URL, status anchorStatus) error {
 
 		var expiryTime int64
 
-		if status == statusPending {
+		switch {
+		case status == statusPending:
 			expiryTime = time.Now().Add(s.pendingRecordLifespan).Unix()
 
 			tags = append(tags,
@@ -148,6 +167,15 @@ func (s *Store) putLinks(links []*url.URL, status anchorStatus) error {
 					Value: status,
 				},
 			)
+		case s.processedAnchorTTL > 0:
+			expiryTime = time.Now().Add(s.processedAnchorTTL).Unix()
+
+			tags = append(tags,
+				storage.Tag{
+					Name:  expiryTimeTag,
+					Value: fmt.Sprintf("%d", expiryTime),
+				},
+			)
 		}
 
 		linkBytes, err := s.marshal(&anchorLinkRef{
@@ -306,6 +334,8 @@ func (s *Store) getLinks(anchorHash, query string) ([]*url.URL, error) {
 func (s *Store) HandleExpiredKeys(keys ...string) ([]string, error) {
 	var keysToDelete []string
 
+	now := time.Now().Unix()
+
 	for _, key := range keys {
 		ref, err := s.getLink(key)
 		if err != nil {
@@ -314,12 +344,20 @@ func (s *Store) HandleExpiredKeys(keys ...string) ([]string, error) {
 			return nil, err
 		}
 
-		if ref.Status != statusProcessed {
+		switch {
+		case ref.Status != statusProcessed:
 			logger.Info("Anchor reference in PENDING state will be deleted", logfields.WithAnchorHash(key))
 
 			keysToDelete = append(keysToDelete, key)
-		} else {
-			logger.Info("Anchor reference will not be deleted since it's not in PENDING state", logfields.WithAnchorHash(key))
+		case ref.ExpiryTime > 0 && ref.ExpiryTime <= now:
+			// The processed anchor reference is older than the configured TTL.
+			logger.Info("Processed anchor reference has expired and will be deleted", logfields.WithAnchorHash(key))
+
+			keysToDelete = append(keysToDelete, key)
+		default:
+			// The reference was processed after the PENDING record expired (or the TTL was extended).
+			logger.Info("Anchor reference will not be deleted since it's not in PENDING state and it hasn't expired",
+				logfields.WithAnchorHash(key))
 		}
 	}
 
@@ -107,6 +107,48 @@ func TestStore_PutLinks(t *testing.T) {
 	})
 }
 
+func TestStore_PutLinksWithProcessedAnchorTTL(t *testing.T) {
+	const hash1 = "uEiALYp_C4wk2WegpfnCSoSTBdKZ1MVdDadn4rdmZl5GKzQ"
+
+	link1 := testutil.MustParseURL(fmt.Sprintf("hl:%s:uoQ-BeEtodmdEa3NBdFEtd0NhS3c", hash1))
+
+	t.Run("Default TTL", func(t *testing.T) {
+		provider := storage.NewMockStoreProvider()
+
+		s, err := New(provider, &mocks.DataExpiryService{})
+		require.NoError(t, err)
+
+		require.NoError(t, s.PutLinks([]*url.URL{link1}))
+
+		ref, err := s.getLink(getID(link1))
+		require.NoError(t, err)
+		require.Equal(t, statusProcessed, ref.Status)
+		require.InDelta(t, time.Now().Add(defaultProcessedAnchorTTL).Unix(), ref.ExpiryTime, 5)
+
+		entry := provider.Store.Store[getID(link1)]
+		require.Len(t, entry.Tags, 2)
+		require.Equal(t, expiryTimeTag, entry.Tags[1].Name)
+		require.Equal(t, fmt.Sprintf("%d", ref.ExpiryTime), entry.Tags[1].Value)
+	})
+
+	t.Run("Pruning disabled", func(t *testing.T) {
+		provider := storage.NewMockStoreProvider()
+
+		s, err := New(provider, &mocks.DataExpiryService{}, WithProcessedAnchorTTL(0))
+		require.NoError(t, err)
+
+		require.NoError(t, s.PutLinks([]*url.URL{link1}))
+
+		ref, err := s.getLink(getID(link1))
+		require.NoError(t, err)
+		require.Zero(t, ref.ExpiryTime)
+
+		entry := provider.Store.Store[getID(link1)]
+		require.Len(t, entry.Tags, 1)
+		require.Equal(t, hashTag, entry.Tags[0].Name)
+	})
+}
+
 func TestStore_GetLinks(t *testing.T) {
 	const (
 		hash1 = "uEiALYp_C4wk2WegpfnCSoSTBdKZ1MVdDadn4rdmZl5GKzQ"
@@ -288,6 +330,32 @@ func TestStore_HandleExpiredKeys(t *testing.T) {
 		require.Equal(t, []string{key1, key3}, keys)
 	})
 
+	t.Run("Processed anchor TTL", func(t *testing.T) {
+		s, err := New(storage.NewMockStoreProvider(), &mocks.DataExpiryService{},
+			WithProcessedAnchorTTL(time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, s)
+
+		// Processed record which is older than the TTL.
+		require.NoError(t, s.store.Put(key1, testutil.MarshalCanonical(t, &anchorLinkRef{
+			Status:     statusProcessed,
+			ExpiryTime: time.Now().Add(-time.Minute).Unix(),
+		})))
+
+		// Processed record which is within the retention window. (The PENDING record may have
+		// expired just as the anchor was processed.)
+		require.NoError(t, s.store.Put(key2, testutil.MarshalCanonical(t, &anchorLinkRef{
+			Status:     statusProcessed,
+			ExpiryTime: time.Now().Add(time.Hour).Unix(),
+		})))
+
+		require.NoError(t, s.store.Put(key3, testutil.MarshalCanonical(t, &anchorLinkRef{Status: statusPending})))
+
+		keys, err := s.HandleExpiredKeys(key1, key2, key3)
+		require.NoError(t, err)
+		require.Equal(t, []string{key1, key3}, keys)
+	})
+
 	t.Run("Store error", func(t *testing.T) {
 		provider := storage.NewMockStoreProvider()
 		provider.Store.ErrGet = errors.New("injected get error")
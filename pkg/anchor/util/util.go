@@ -17,6 +17,8 @@ import (
 )
 
 // VerifiableCredentialFromAnchorLink validates the AnchorEvent and returns the embedded verifiable credential.
+// Strict JSON-LD validation of the credential is only performed if verifiable.WithStrictValidation is
+// included in the given options.
 func VerifiableCredentialFromAnchorLink(anchorLink *linkset.Link, opts ...verifiable.CredentialOpt) (*verifiable.Credential, error) {
 	if err := anchorLink.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid anchor link: %w", err)
@@ -31,7 +33,7 @@ func VerifiableCredentialFromAnchorLink(anchorLink *linkset.Link, opts ...verifi
 		return nil, fmt.Errorf("unmarshal reply: %w", err)
 	}
 
-	vc, err := verifiable.ParseCredential(vcBytes, append(opts, verifiable.WithStrictValidation())...)
+	vc, err := verifiable.ParseCredential(vcBytes, opts...)
 	if err != nil {
 		if strings.Contains(err.Error(), "http request unsuccessful") ||
 			strings.Contains(err.Error(), "http server returned status code") ||
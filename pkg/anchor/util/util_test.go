@@ -130,4 +130,51 @@ func TestVerifiableCredentialFromAnchorEvent(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "embedded proof is not JSON")
 	})
+
+	t.Run("undefined field -> passes lax but fails strict validation", func(t *testing.T) {
+		payload := &subject.Payload{
+			OperationCount:  1,
+			CoreIndex:       "hl:uEiBqkaTRFZScQsXTw8IDBSpVxiKGqjJCDUcgiwpcd2frLw",
+			Namespace:       "did:orb",
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "suffix"}},
+		}
+
+		al, _, err := anchorlinkset.NewBuilder(
+			generator.NewRegistry()).BuildAnchorLink(payload, datauri.MediaTypeDataURIGzipBase64,
+			func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error) {
+				return &verifiable.Credential{
+					Types:   []string{"VerifiableCredential", "AnchorCredential"},
+					Context: defVCContext,
+					Subject: &builder.CredentialSubject{
+						HRef:    anchorHashlink,
+						Type:    []string{"AnchorLink"},
+						Profile: "https://w3id.org/orb#v0",
+						Anchor:  "hl:uEiAtvFg7Ti4-0MquG-sFMGRDcGUwz22JpCmOksomNTQGXw",
+						Rel:     "linkset",
+					},
+					Issuer: verifiable.Issuer{
+						ID: "http://peer1.com",
+					},
+					Issued: &util.TimeWrapper{Time: time.Now()},
+					// This field isn't defined in any of the JSON-LD contexts.
+					CustomFields: verifiable.CustomFields{"undefinedField": "value"},
+				}, nil
+			},
+		)
+		require.NoError(t, err)
+
+		vc, err := VerifiableCredentialFromAnchorLink(al,
+			verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "value", vc.CustomFields["undefinedField"])
+
+		vc, err = VerifiableCredentialFromAnchorLink(al,
+			verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+			verifiable.WithStrictValidation(),
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse credential")
+		require.Nil(t, vc)
+	})
 }
@@ -0,0 +1,179 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pending
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+var logger = log.New("pending-witness-anchors")
+
+const defaultMaxAnchors = 1000
+
+type metricsProvider interface {
+	WitnessPendingAnchorCount(count int)
+}
+
+// Anchor contains the details of an anchor that is waiting for sufficient witness proofs.
+type Anchor struct {
+	AnchorID    string    `json:"anchor"`
+	ProofCount  int       `json:"proofCount"`
+	FirstSeen   time.Time `json:"firstSeen"`
+	LastUpdated time.Time `json:"lastUpdated"`
+
+	// WaitingSeconds is the number of seconds that the anchor has been waiting for witness proofs. It is
+	// calculated at the time that the anchor is listed.
+	WaitingSeconds int64 `json:"waitingSeconds"`
+}
+
+type options struct {
+	maxAnchors int
+}
+
+// Opt is a pending anchors option.
+type Opt func(opts *options)
+
+// WithMaxAnchors sets the maximum number of pending anchors that are tracked. If the maximum is reached
+// then the anchor that has been waiting the longest is dropped.
+func WithMaxAnchors(value int) Opt {
+	return func(opts *options) {
+		opts.maxAnchors = value
+	}
+}
+
+// Anchors tracks (in memory) the anchors that have received witness proofs but that have not yet accumulated
+// enough proofs to satisfy the witness policy. Anchors are only tracked on the node that handled the proofs
+// and they are lost when the node is restarted.
+type Anchors struct {
+	*options
+
+	metrics metricsProvider
+	mutex   sync.RWMutex
+	anchors map[string]*Anchor
+	now     func() time.Time
+}
+
+// New returns a new pending anchors tracker.
+func New(metrics metricsProvider, opts ...Opt) *Anchors {
+	options := &options{
+		maxAnchors: defaultMaxAnchors,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &Anchors{
+		options: options,
+		metrics: metrics,
+		anchors: make(map[string]*Anchor),
+		now:     time.Now,
+	}
+}
+
+// Update adds the given anchor (if it isn't already tracked) and sets its current proof count.
+func (p *Anchors) Update(anchorID string, proofCount int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+
+	a, ok := p.anchors[anchorID]
+	if !ok {
+		if len(p.anchors) >= p.maxAnchors {
+			p.evictOldest()
+		}
+
+		a = &Anchor{
+			AnchorID:  anchorID,
+			FirstSeen: now,
+		}
+
+		p.anchors[anchorID] = a
+	}
+
+	a.ProofCount = proofCount
+	a.LastUpdated = now
+
+	p.metrics.WitnessPendingAnchorCount(len(p.anchors))
+}
+
+// Remove removes the given anchor. This function should be called when the anchor's witness policy
+// has been satisfied.
+func (p *Anchors) Remove(anchorID string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, ok := p.anchors[anchorID]; !ok {
+		return
+	}
+
+	delete(p.anchors, anchorID)
+
+	p.metrics.WitnessPendingAnchorCount(len(p.anchors))
+}
+
+// List returns the pending anchors, ordered by the time that they were first seen (oldest first).
+func (p *Anchors) List() []*Anchor {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	now := p.now()
+
+	anchors := make([]*Anchor, 0, len(p.anchors))
+
+	for _, a := range p.anchors {
+		anchor := *a
+		anchor.WaitingSeconds = int64(now.Sub(a.FirstSeen).Seconds())
+
+		anchors = append(anchors, &anchor)
+	}
+
+	sort.Slice(anchors, func(i, j int) bool {
+		if anchors[i].FirstSeen.Equal(anchors[j].FirstSeen) {
+			return anchors[i].AnchorID < anchors[j].AnchorID
+		}
+
+		return anchors[i].FirstSeen.Before(anchors[j].FirstSeen)
+	})
+
+	return anchors
+}
+
+// Count returns the number of pending anchors.
+func (p *Anchors) Count() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return len(p.anchors)
+}
+
+// evictOldest removes the anchor that has been waiting the longest. The caller must hold the lock.
+func (p *Anchors) evictOldest() {
+	var oldest *Anchor
+
+	for _, a := range p.anchors {
+		if oldest == nil || a.FirstSeen.Before(oldest.FirstSeen) {
+			oldest = a
+		}
+	}
+
+	if oldest == nil {
+		return
+	}
+
+	logger.Warn("Maximum number of pending anchors reached. Dropping the anchor that has been waiting the longest.",
+		logfields.WithAnchorURIString(oldest.AnchorID), logfields.WithMaxSize(p.maxAnchors))
+
+	delete(p.anchors, oldest.AnchorID)
+}
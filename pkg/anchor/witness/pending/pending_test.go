@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pending
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnchors(t *testing.T) {
+	t.Run("update and remove", func(t *testing.T) {
+		metrics := &mockMetrics{}
+
+		p := New(metrics)
+		require.NotNil(t, p)
+
+		now := time.Now()
+		p.now = func() time.Time { return now }
+
+		p.Update("anchor1", 1)
+
+		now = now.Add(time.Minute)
+
+		p.Update("anchor2", 1)
+		p.Update("anchor1", 2)
+
+		require.Equal(t, 2, p.Count())
+		require.Equal(t, 2, metrics.count)
+
+		now = now.Add(time.Minute)
+
+		anchors := p.List()
+		require.Len(t, anchors, 2)
+
+		require.Equal(t, "anchor1", anchors[0].AnchorID)
+		require.Equal(t, 2, anchors[0].ProofCount)
+		require.Equal(t, int64(120), anchors[0].WaitingSeconds)
+		require.True(t, anchors[0].LastUpdated.After(anchors[0].FirstSeen))
+
+		require.Equal(t, "anchor2", anchors[1].AnchorID)
+		require.Equal(t, 1, anchors[1].ProofCount)
+		require.Equal(t, int64(60), anchors[1].WaitingSeconds)
+
+		p.Remove("anchor1")
+
+		require.Equal(t, 1, p.Count())
+		require.Equal(t, 1, metrics.count)
+
+		p.Remove("anchor3")
+
+		require.Equal(t, 1, p.Count())
+
+		p.Remove("anchor2")
+
+		require.Empty(t, p.List())
+		require.Equal(t, 0, metrics.count)
+	})
+
+	t.Run("max anchors", func(t *testing.T) {
+		metrics := &mockMetrics{}
+
+		p := New(metrics, WithMaxAnchors(2))
+
+		now := time.Now()
+		p.now = func() time.Time {
+			now = now.Add(time.Second)
+
+			return now
+		}
+
+		p.Update("anchor1", 1)
+		p.Update("anchor2", 1)
+		p.Update("anchor3", 1)
+
+		require.Equal(t, 2, p.Count())
+		require.Equal(t, 2, metrics.count)
+
+		anchors := p.List()
+		require.Len(t, anchors, 2)
+		require.Equal(t, "anchor2", anchors[0].AnchorID)
+		require.Equal(t, "anchor3", anchors[1].AnchorID)
+
+		// Updating an existing anchor shouldn't evict any anchors.
+		p.Update("anchor2", 2)
+
+		require.Equal(t, 2, p.Count())
+	})
+}
+
+type mockMetrics struct {
+	count int
+}
+
+func (m *mockMetrics) WitnessPendingAnchorCount(count int) {
+	m.count = count
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pendingrest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/pending"
+)
+
+var logger = log.New("pending-witness-rest", log.WithFields(logfields.WithServiceEndpoint(pendingPath)))
+
+const (
+	pendingPath                 = "/witness/pending"
+	internalServerErrorResponse = "Internal Server Error.\n"
+)
+
+type pendingAnchors interface {
+	List() []*pending.Anchor
+}
+
+// Reader implements a REST handler which lists the anchors that are waiting for sufficient witness proofs.
+type Reader struct {
+	anchors pendingAnchors
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new REST handler which lists the anchors that are waiting for sufficient witness proofs.
+func NewReader(anchors pendingAnchors) *Reader {
+	return &Reader{
+		anchors: anchors,
+		marshal: json.Marshal,
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *Reader) Path() string {
+	return pendingPath
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *Reader) handleGet(w http.ResponseWriter, _ *http.Request) {
+	anchors := h.anchors.List()
+	if anchors == nil {
+		anchors = []*pending.Anchor{}
+	}
+
+	anchorsBytes, err := h.marshal(anchors)
+	if err != nil {
+		logger.Error("Error marshalling pending anchors", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, anchorsBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			log.WriteResponseBodyError(logger, err)
+
+			return
+		}
+
+		log.WroteResponse(logger, body)
+	}
+}
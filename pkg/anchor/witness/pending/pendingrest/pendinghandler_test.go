@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pendingrest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/pending"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+const pendingURL = "https://example.com/witness/pending"
+
+func TestNewReader(t *testing.T) {
+	r := NewReader(pending.New(&orbmocks.MetricsProvider{}))
+	require.NotNil(t, r.Handler())
+	require.Equal(t, http.MethodGet, r.Method())
+	require.Equal(t, "/witness/pending", r.Path())
+}
+
+func TestReader_Handler(t *testing.T) {
+	t.Run("No pending anchors", func(t *testing.T) {
+		h := NewReader(pending.New(&orbmocks.MetricsProvider{}))
+
+		rw := httptest.NewRecorder()
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, pendingURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, "[]", string(respBytes))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		anchors := pending.New(&orbmocks.MetricsProvider{})
+		anchors.Update("hl:uEiA1", 1)
+		anchors.Update("hl:uEiA2", 2)
+
+		h := NewReader(anchors)
+
+		rw := httptest.NewRecorder()
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, pendingURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		var pendingAnchors []*pending.Anchor
+		require.NoError(t, json.Unmarshal(respBytes, &pendingAnchors))
+		require.Len(t, pendingAnchors, 2)
+		require.Equal(t, "hl:uEiA1", pendingAnchors[0].AnchorID)
+		require.Equal(t, 1, pendingAnchors[0].ProofCount)
+		require.Equal(t, "hl:uEiA2", pendingAnchors[1].AnchorID)
+		require.Equal(t, 2, pendingAnchors[1].ProofCount)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewReader(pending.New(&orbmocks.MetricsProvider{}))
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		rw := httptest.NewRecorder()
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, pendingURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
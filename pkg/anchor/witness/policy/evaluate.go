@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+// WitnessInfo contains information about a witness that is used in a policy dry-run (see EvaluatePolicy).
+type WitnessInfo struct {
+	URI    string            `json:"uri"`
+	Type   proof.WitnessType `json:"type"`
+	HasLog bool              `json:"hasLog"`
+	// Available indicates whether or not the witness is expected to provide a proof. Witnesses that aren't
+	// available are still counted towards the total number of witnesses of their type.
+	Available bool `json:"available"`
+}
+
+// EvaluatePolicy performs a dry-run of the given witness policy against the given witnesses, without
+// anchoring anything. It returns true if the policy would be satisfied by the proofs of the available
+// witnesses. If the policy would not be satisfied then the returned reason explains which of the
+// conditions aren't met. An error is returned if the policy is malformed.
+func EvaluatePolicy(policy string, availableWitnesses []WitnessInfo) (bool, string, error) {
+	cfg, err := config.Parse(policy)
+	if err != nil {
+		return false, "", fmt.Errorf("parse policy [%s]: %w", policy, err)
+	}
+
+	batch := newConditionResult(config.RoleBatch, cfg.MinNumberBatch, cfg.MinPercentBatch)
+	system := newConditionResult(config.RoleSystem, cfg.MinNumberSystem, cfg.MinPercentSystem)
+
+	for _, w := range availableWitnesses {
+		switch w.Type {
+		case proof.WitnessTypeBatch:
+			batch.add(w, cfg.LogRequired)
+		case proof.WitnessTypeSystem:
+			system.add(w, cfg.LogRequired)
+		}
+	}
+
+	if cfg.OperatorFnc(batch.satisfied(), system.satisfied()) {
+		return true, "", nil
+	}
+
+	var reasons []string
+
+	for _, c := range []*conditionResult{batch, system} {
+		if !c.satisfied() {
+			reasons = append(reasons, c.reason(cfg.LogRequired))
+		}
+	}
+
+	if cfg.Operator == config.OR {
+		return false, "neither condition is satisfied: " + strings.Join(reasons, "; "), nil
+	}
+
+	return false, strings.Join(reasons, "; "), nil
+}
+
+// conditionResult accumulates the witnesses of a given role (batch or system).
+type conditionResult struct {
+	role       string
+	minNumber  int
+	minPercent int
+
+	total       int
+	collected   int
+	unavailable int
+	noLog       int
+}
+
+func newConditionResult(role string, minNumber, minPercent int) *conditionResult {
+	return &conditionResult{
+		role:       role,
+		minNumber:  minNumber,
+		minPercent: minPercent,
+	}
+}
+
+func (c *conditionResult) add(w WitnessInfo, logRequired bool) {
+	c.total++
+
+	switch {
+	case !w.Available:
+		c.unavailable++
+	case !checkLog(logRequired, w.HasLog):
+		c.noLog++
+	default:
+		c.collected++
+	}
+}
+
+func (c *conditionResult) satisfied() bool {
+	return evaluate(c.collected, c.total, c.minNumber, c.minPercent)
+}
+
+func (c *conditionResult) reason(logRequired bool) string {
+	var required string
+
+	if c.minNumber != 0 {
+		required = fmt.Sprintf("at least %d witness(es) or %d%% are required", c.minNumber, c.minPercent)
+	} else {
+		required = fmt.Sprintf("%d%% are required", c.minPercent)
+	}
+
+	reason := fmt.Sprintf("%s witnesses: %d of %d would provide a proof but %s (%d unavailable",
+		c.role, c.collected, c.total, required, c.unavailable)
+
+	if logRequired {
+		reason += fmt.Sprintf(", %d without a log", c.noLog)
+	}
+
+	return reason + ")"
+}
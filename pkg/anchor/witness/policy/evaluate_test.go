@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+func TestEvaluatePolicy(t *testing.T) {
+	batch1 := WitnessInfo{URI: "https://batch1.com/services/orb", Type: proof.WitnessTypeBatch, Available: true}
+	batch2 := WitnessInfo{URI: "https://batch2.com/services/orb", Type: proof.WitnessTypeBatch, HasLog: true}
+	system1 := WitnessInfo{
+		URI: "https://system1.com/services/orb", Type: proof.WitnessTypeSystem, HasLog: true, Available: true,
+	}
+	system2 := WitnessInfo{URI: "https://system2.com/services/orb", Type: proof.WitnessTypeSystem, Available: true}
+	system3 := WitnessInfo{URI: "https://system3.com/services/orb", Type: proof.WitnessTypeSystem}
+
+	t.Run("satisfied", func(t *testing.T) {
+		satisfied, reason, err := EvaluatePolicy("", []WitnessInfo{batch1, system1, system2})
+		require.NoError(t, err)
+		require.True(t, satisfied)
+		require.Empty(t, reason)
+
+		satisfied, reason, err = EvaluatePolicy("OutOf(1,batch) AND OutOf(1,system)",
+			[]WitnessInfo{batch1, batch2, system1, system2})
+		require.NoError(t, err)
+		require.True(t, satisfied)
+		require.Empty(t, reason)
+
+		satisfied, reason, err = EvaluatePolicy("MinPercent(100,batch) OR MinPercent(50,system) LogRequired",
+			[]WitnessInfo{batch1, batch2, system1, system2})
+		require.NoError(t, err)
+		require.True(t, satisfied)
+		require.Empty(t, reason)
+	})
+
+	t.Run("no witnesses", func(t *testing.T) {
+		satisfied, reason, err := EvaluatePolicy("OutOf(1,batch)", nil)
+		require.NoError(t, err)
+		require.True(t, satisfied, "a role with no witnesses satisfies the percentage rule")
+		require.Empty(t, reason)
+	})
+
+	t.Run("unsatisfied", func(t *testing.T) {
+		satisfied, reason, err := EvaluatePolicy("", []WitnessInfo{batch1, batch2, system1})
+		require.NoError(t, err)
+		require.False(t, satisfied)
+		require.Equal(t, "batch witnesses: 1 of 2 would provide a proof but 100% are required (1 unavailable)", reason)
+
+		satisfied, reason, err = EvaluatePolicy("OutOf(2,batch) AND MinPercent(100,system) LogRequired",
+			[]WitnessInfo{batch1, batch2, system1, system2})
+		require.NoError(t, err)
+		require.False(t, satisfied)
+		require.Equal(t, "batch witnesses: 0 of 2 would provide a proof but at least 2 witness(es) or 100% "+
+			"are required (1 unavailable, 1 without a log); system witnesses: 1 of 2 would provide a proof "+
+			"but 100% are required (0 unavailable, 1 without a log)", reason)
+
+		satisfied, reason, err = EvaluatePolicy("OutOf(2,batch) OR OutOf(3,system)",
+			[]WitnessInfo{batch1, batch2, system1, system2, system3})
+		require.NoError(t, err)
+		require.False(t, satisfied)
+		require.Equal(t, "neither condition is satisfied: batch witnesses: 1 of 2 would provide a proof but "+
+			"at least 2 witness(es) or 100% are required (1 unavailable); system witnesses: 2 of 3 would "+
+			"provide a proof but at least 3 witness(es) or 100% are required (1 unavailable)", reason)
+	})
+
+	t.Run("malformed policy", func(t *testing.T) {
+		satisfied, reason, err := EvaluatePolicy("OutOf(x,batch)", []WitnessInfo{batch1})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse policy [OutOf(x,batch)]")
+		require.False(t, satisfied)
+		require.Empty(t, reason)
+
+		_, _, err = EvaluatePolicy("XOR", []WitnessInfo{batch1})
+		require.EqualError(t, err, "parse policy [XOR]: rule not supported: XOR")
+	})
+}
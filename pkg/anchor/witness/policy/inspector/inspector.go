@@ -112,15 +112,17 @@ func (c *Inspector) postOfferActivity(ctx context.Context, anchorLink *linkset.L
 	startTime := time.Now()
 	endTime := startTime.Add(c.maxWitnessDelay)
 
-	offer := vocab.NewOfferActivity(
+	offer, err := vocab.NewWitnessOfferActivity(
 		vocab.NewObjectProperty(
 			vocab.WithDocument(anchorLinksetDoc),
 		),
 		vocab.WithTo(witnessesIRI...),
 		vocab.WithStartTime(&startTime),
 		vocab.WithEndTime(&endTime),
-		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
 	)
+	if err != nil {
+		return fmt.Errorf("create additional offer for anchor[%s]: %w", anchorLink.Anchor(), err)
+	}
 
 	activityID, err := c.Outbox().Post(ctx, offer)
 	if err != nil {
@@ -88,6 +88,10 @@ func (wp *WitnessPolicy) Evaluate(witnesses []*proof.WitnessProof) (bool, error)
 		return false, err
 	}
 
+	return evaluateConfig(cfg, witnesses), nil
+}
+
+func evaluateConfig(cfg *config.WitnessPolicyConfig, witnesses []*proof.WitnessProof) bool {
 	totalSystemWitnesses := 0
 	collectedSystemWitnesses := 0
 
@@ -123,7 +127,7 @@ func (wp *WitnessPolicy) Evaluate(witnesses []*proof.WitnessProof) (bool, error)
 		withPolicyConfigField(cfg), withEvaluatedField(evaluated), withBatchConditionField(batchCondition),
 		withSystemConditionField(systemCondition), withWitnessProofsField(witnesses))
 
-	return evaluated, nil
+	return evaluated
 }
 
 func (wp *WitnessPolicy) loadWitnessPolicy(interface{}) (interface{}, *time.Duration, error) {
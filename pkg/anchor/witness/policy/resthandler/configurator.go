@@ -96,7 +96,7 @@ func (pc *PolicyConfigurator) handle(w http.ResponseWriter, req *http.Request) {
 }
 
 func writeResponse(w http.ResponseWriter, status int, body []byte) {
-	if len(body) > 0 {
+	if len(body) > 0 && w.Header().Get("Content-Type") == "" {
 		w.Header().Set("Content-Type", "text/plain")
 	}
 
@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 
 package resthandler
 
+import "github.com/trustbloc/orb/pkg/anchor/witness/policy"
+
 // swagger:parameters policyGetReq
 type policyGetReq struct { //nolint: unused
 }
@@ -45,3 +47,62 @@ type policyPostResp struct { //nolint: unused
 //	200: policyPostResp
 func postPolicy() { //nolint: unused
 }
+
+// swagger:parameters policySimulateReq
+type policySimulateReq struct { //nolint: unused
+	// The maximum number of recent anchors to evaluate.
+	//
+	// in: query
+	MaxAnchors int `json:"max-anchors"`
+
+	// in: body
+	Body string
+}
+
+// swagger:response policySimulateResp
+type policySimulateResp struct { //nolint: unused
+	// in: body
+	Body policy.SimulationResult
+}
+
+// simulatePolicy swagger:route POST /policy/simulate policy policySimulateReq
+//
+// Evaluates the proposed witness policy (provided in the body) against the witness proofs of recent anchors and
+// compares the results with the current witness policy.
+//
+// Responses:
+//
+//	200: policySimulateResp
+func simulatePolicy() { //nolint: unused
+}
+
+// swagger:parameters policySuggestReq
+type policySuggestReq struct { //nolint: unused
+	// The maximum number of recent anchors to analyze.
+	//
+	// in: query
+	MaxAnchors int `json:"max-anchors"`
+
+	// The minimum reliability (percentage of requested anchors that were signed) of a witness
+	// for the witness to be considered reliable.
+	//
+	// in: query
+	MinReliability float64 `json:"min-reliability"`
+}
+
+// swagger:response policySuggestResp
+type policySuggestResp struct { //nolint: unused
+	// in: body
+	Body policy.PolicySuggestion
+}
+
+// suggestPolicy swagger:route GET /policy/suggest policy policySuggestReq
+//
+// Computes the reliability of each witness from the witness proofs of recent anchors and suggests a witness
+// policy which requires proofs from the reliable witnesses. The suggestion is advisory and is not applied.
+//
+// Responses:
+//
+//	200: policySuggestResp
+func suggestPolicy() { //nolint: unused
+}
@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+const (
+	simulateEndpoint = endpoint + "/simulate"
+
+	maxAnchorsParam = "max-anchors"
+
+	defaultMaxAnchors = 1000
+)
+
+type witnessStore interface {
+	GetRecentAnchors(maxAnchors int) ([]string, error)
+	Get(anchorID string) ([]*proof.WitnessProof, error)
+}
+
+// PolicySimulator evaluates a proposed witness policy against the witness proofs of recent anchors
+// and compares the results with the current witness policy.
+type PolicySimulator struct {
+	policyStore  policyStore
+	witnessStore witnessStore
+}
+
+// Path returns the HTTP REST endpoint for the policy simulator.
+func (ps *PolicySimulator) Path() string {
+	return simulateEndpoint
+}
+
+// Method returns the HTTP REST method for the policy simulator.
+func (ps *PolicySimulator) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the PolicySimulator service.
+func (ps *PolicySimulator) Handler() common.HTTPRequestHandler {
+	return ps.handle
+}
+
+// NewSimulator returns a new PolicySimulator.
+func NewSimulator(policyStore policyStore, witnessStore witnessStore) *PolicySimulator {
+	return &PolicySimulator{
+		policyStore:  policyStore,
+		witnessStore: witnessStore,
+	}
+}
+
+func (ps *PolicySimulator) handle(w http.ResponseWriter, req *http.Request) {
+	policyBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		logger.Error("Error reading request body", log.WithError(err))
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	proposedPolicy := string(policyBytes)
+
+	_, err = config.Parse(proposedPolicy)
+	if err != nil {
+		logger.Error("Invalid witness policy", log.WithError(err), logfields.WithWitnessPolicy(proposedPolicy))
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	maxAnchors, err := getMaxAnchors(req)
+	if err != nil {
+		logger.Error("Invalid parameter", logfields.WithParameter(maxAnchorsParam), log.WithError(err))
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	currentPolicy, err := ps.policyStore.GetPolicy()
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		logger.Error("Error retrieving witness policy", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	anchors, err := getRecentAnchors(ps.witnessStore, maxAnchors)
+	if err != nil {
+		logger.Error("Error retrieving recent anchors", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	result, err := policy.Simulate(currentPolicy, proposedPolicy, anchors)
+	if err != nil {
+		logger.Error("Error simulating witness policy", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		logger.Error("Error marshalling simulation result", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Debug("Simulated witness policy", logfields.WithWitnessPolicy(proposedPolicy),
+		logfields.WithTotal(result.Sampled))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, resultBytes)
+}
+
+// getRecentAnchors returns the witness proofs of the most recent anchors (anchor ID -> witness proofs).
+func getRecentAnchors(witnessStore witnessStore, maxAnchors int) (map[string][]*proof.WitnessProof, error) {
+	anchorIDs, err := witnessStore.GetRecentAnchors(maxAnchors)
+	if err != nil {
+		return nil, err
+	}
+
+	anchors := make(map[string][]*proof.WitnessProof, len(anchorIDs))
+
+	for _, anchorID := range anchorIDs {
+		witnessProofs, e := witnessStore.Get(anchorID)
+		if e != nil {
+			// The witness data for the anchor may have expired in the meantime. The anchor is still counted
+			// so that it's reported as excluded due to insufficient proof data.
+			logger.Debug("Unable to retrieve witness proofs for anchor", logfields.WithAnchorURIString(anchorID),
+				log.WithError(e))
+		}
+
+		anchors[anchorID] = witnessProofs
+	}
+
+	return anchors, nil
+}
+
+func getMaxAnchors(req *http.Request) (int, error) {
+	values := req.URL.Query()[maxAnchorsParam]
+	if len(values) == 0 || values[0] == "" {
+		return defaultMaxAnchors, nil
+	}
+
+	maxAnchors, err := strconv.Atoi(values[0])
+	if err != nil {
+		return 0, err
+	}
+
+	if maxAnchors <= 0 {
+		return 0, errors.New("value must be greater than 0")
+	}
+
+	return maxAnchors, nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/mocks"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+//go:generate counterfeiter -o ../mocks/anchorwitnessstore.gen.go --fake-name AnchorWitnessStore . witnessStore
+
+func TestNewSimulator(t *testing.T) {
+	simulator := NewSimulator(&mocks.PolicyStore{}, &mocks.AnchorWitnessStore{})
+	require.NotNil(t, simulator)
+	require.Equal(t, simulateEndpoint, simulator.Path())
+	require.Equal(t, http.MethodPost, simulator.Method())
+	require.NotNil(t, simulator.Handler())
+}
+
+func TestSimulatorHandler(t *testing.T) {
+	witnessURL, err := url.Parse("https://domain.com/service")
+	require.NoError(t, err)
+
+	witnessProofs := []*proof.WitnessProof{
+		{
+			Witness: &proof.Witness{Type: proof.WitnessTypeBatch, URI: vocab.NewURLProperty(witnessURL)},
+			Proof:   []byte("proof"),
+		},
+		{
+			Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witnessURL)},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		policyStore := &mocks.PolicyStore{}
+		policyStore.GetPolicyReturns("", storage.ErrDataNotFound)
+
+		witnessStore := &mocks.AnchorWitnessStore{}
+		witnessStore.GetRecentAnchorsReturns([]string{"anchor1", "anchor2", "anchor3"}, nil)
+		witnessStore.GetReturnsOnCall(0, witnessProofs, nil)
+		witnessStore.GetReturnsOnCall(1, witnessProofs, nil)
+		witnessStore.GetReturnsOnCall(2, nil, errors.New("not found"))
+
+		simulator := NewSimulator(policyStore, witnessStore)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, simulateEndpoint+"?max-anchors=3",
+			bytes.NewBufferString("OutOf(1,batch) OR OutOf(1,system)"))
+
+		simulator.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, "application/json", result.Header.Get("Content-Type"))
+		require.Equal(t, 3, witnessStore.GetRecentAnchorsArgsForCall(0))
+
+		respBytes, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		simResult := &policy.SimulationResult{}
+		require.NoError(t, json.Unmarshal(respBytes, simResult))
+		require.Equal(t, 3, simResult.Sampled)
+		require.Equal(t, 2, simResult.Evaluated)
+		require.Equal(t, 1, simResult.Excluded)
+		require.Equal(t, 0, simResult.SatisfiedCurrent)
+		require.Equal(t, 2, simResult.SatisfiedProposed)
+	})
+
+	t.Run("success - default max anchors", func(t *testing.T) {
+		witnessStore := &mocks.AnchorWitnessStore{}
+
+		simulator := NewSimulator(&mocks.PolicyStore{}, witnessStore)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, simulateEndpoint, bytes.NewBufferString(testPolicy))
+
+		simulator.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, defaultMaxAnchors, witnessStore.GetRecentAnchorsArgsForCall(0))
+	})
+
+	t.Run("error - reader error", func(t *testing.T) {
+		simulator := NewSimulator(&mocks.PolicyStore{}, &mocks.AnchorWitnessStore{})
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, simulateEndpoint, errReader(0))
+
+		simulator.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("error - invalid policy", func(t *testing.T) {
+		simulator := NewSimulator(&mocks.PolicyStore{}, &mocks.AnchorWitnessStore{})
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, simulateEndpoint, bytes.NewBufferString("InvalidPolicy"))
+
+		simulator.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("error - invalid max anchors", func(t *testing.T) {
+		simulator := NewSimulator(&mocks.PolicyStore{}, &mocks.AnchorWitnessStore{})
+
+		for _, value := range []string{"xxx", "0", "-1"} {
+			rw := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, simulateEndpoint+"?max-anchors="+value,
+				bytes.NewBufferString(testPolicy))
+
+			simulator.handle(rw, req)
+
+			result := rw.Result()
+			require.Equal(t, http.StatusBadRequest, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+		}
+	})
+
+	t.Run("error - policy store error", func(t *testing.T) {
+		policyStore := &mocks.PolicyStore{}
+		policyStore.GetPolicyReturns("", errors.New("injected store error"))
+
+		simulator := NewSimulator(policyStore, &mocks.AnchorWitnessStore{})
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, simulateEndpoint, bytes.NewBufferString(testPolicy))
+
+		simulator.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("error - witness store error", func(t *testing.T) {
+		witnessStore := &mocks.AnchorWitnessStore{}
+		witnessStore.GetRecentAnchorsReturns(nil, errors.New("injected store error"))
+
+		simulator := NewSimulator(&mocks.PolicyStore{}, witnessStore)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, simulateEndpoint, bytes.NewBufferString(testPolicy))
+
+		simulator.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("error - invalid current policy", func(t *testing.T) {
+		policyStore := &mocks.PolicyStore{}
+		policyStore.GetPolicyReturns("InvalidPolicy", nil)
+
+		simulator := NewSimulator(policyStore, &mocks.AnchorWitnessStore{})
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, simulateEndpoint, bytes.NewBufferString(testPolicy))
+
+		simulator.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
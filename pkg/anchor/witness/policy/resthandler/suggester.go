@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+)
+
+const (
+	suggestEndpoint = endpoint + "/suggest"
+
+	minReliabilityParam = "min-reliability"
+)
+
+// PolicySuggester suggests a witness policy from the observed reliability of the witnesses of recent anchors.
+// The suggested policy is advisory only and is not applied.
+type PolicySuggester struct {
+	witnessStore witnessStore
+}
+
+// Path returns the HTTP REST endpoint for the policy suggester.
+func (ps *PolicySuggester) Path() string {
+	return suggestEndpoint
+}
+
+// Method returns the HTTP REST method for the policy suggester.
+func (ps *PolicySuggester) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the PolicySuggester service.
+func (ps *PolicySuggester) Handler() common.HTTPRequestHandler {
+	return ps.handle
+}
+
+// NewSuggester returns a new PolicySuggester.
+func NewSuggester(witnessStore witnessStore) *PolicySuggester {
+	return &PolicySuggester{
+		witnessStore: witnessStore,
+	}
+}
+
+func (ps *PolicySuggester) handle(w http.ResponseWriter, req *http.Request) {
+	maxAnchors, err := getMaxAnchors(req)
+	if err != nil {
+		logger.Error("Invalid parameter", logfields.WithParameter(maxAnchorsParam), log.WithError(err))
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	minReliability, err := getMinReliability(req)
+	if err != nil {
+		logger.Error("Invalid parameter", logfields.WithParameter(minReliabilityParam), log.WithError(err))
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	anchors, err := getRecentAnchors(ps.witnessStore, maxAnchors)
+	if err != nil {
+		logger.Error("Error retrieving recent anchors", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	result, err := policy.Suggest(anchors, minReliability)
+	if err != nil {
+		logger.Error("Error suggesting witness policy", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		logger.Error("Error marshalling policy suggestion", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Debug("Suggested witness policy", logfields.WithWitnessPolicy(result.SuggestedPolicy),
+		logfields.WithTotal(result.Sampled))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, resultBytes)
+}
+
+func getMinReliability(req *http.Request) (float64, error) {
+	values := req.URL.Query()[minReliabilityParam]
+	if len(values) == 0 || values[0] == "" {
+		return policy.DefaultMinReliability, nil
+	}
+
+	minReliability, err := strconv.ParseFloat(values[0], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	// The condition is negated so that NaN is also rejected.
+	if !(minReliability >= 0 && minReliability <= 100) {
+		return 0, errors.New("value must be between 0 and 100")
+	}
+
+	return minReliability, nil
+}
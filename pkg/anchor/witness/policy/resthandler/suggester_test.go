@@ -0,0 +1,155 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/mocks"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+func TestNewSuggester(t *testing.T) {
+	suggester := NewSuggester(&mocks.AnchorWitnessStore{})
+	require.NotNil(t, suggester)
+	require.Equal(t, suggestEndpoint, suggester.Path())
+	require.Equal(t, http.MethodGet, suggester.Method())
+	require.NotNil(t, suggester.Handler())
+}
+
+func TestSuggesterHandler(t *testing.T) {
+	batchWitnessURL, err := url.Parse("https://batch.com/service")
+	require.NoError(t, err)
+
+	systemWitnessURL, err := url.Parse("https://system.com/service")
+	require.NoError(t, err)
+
+	witnessProofs := []*proof.WitnessProof{
+		{
+			Witness: &proof.Witness{
+				Type: proof.WitnessTypeBatch, URI: vocab.NewURLProperty(batchWitnessURL), Selected: true,
+			},
+			Proof: []byte("proof"),
+		},
+		{
+			Witness: &proof.Witness{
+				Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(systemWitnessURL), Selected: true,
+			},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		witnessStore := &mocks.AnchorWitnessStore{}
+		witnessStore.GetRecentAnchorsReturns([]string{"anchor1", "anchor2", "anchor3"}, nil)
+		witnessStore.GetReturnsOnCall(0, witnessProofs, nil)
+		witnessStore.GetReturnsOnCall(1, witnessProofs, nil)
+		witnessStore.GetReturnsOnCall(2, nil, errors.New("not found"))
+
+		suggester := NewSuggester(witnessStore)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, suggestEndpoint+"?max-anchors=3&min-reliability=90", http.NoBody)
+
+		suggester.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, "application/json", result.Header.Get("Content-Type"))
+		require.Equal(t, 3, witnessStore.GetRecentAnchorsArgsForCall(0))
+
+		respBytes, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		suggestion := &policy.PolicySuggestion{}
+		require.NoError(t, json.Unmarshal(respBytes, suggestion))
+		require.True(t, suggestion.Advisory)
+		require.Equal(t, "OutOf(1,batch) AND OutOf(0,system)", suggestion.SuggestedPolicy)
+		require.Equal(t, float64(90), suggestion.MinReliability)
+		require.Equal(t, 3, suggestion.Sampled)
+		require.Equal(t, 2, suggestion.Evaluated)
+		require.Equal(t, 1, suggestion.Excluded)
+		require.Len(t, suggestion.Witnesses, 2)
+	})
+
+	t.Run("success - defaults", func(t *testing.T) {
+		witnessStore := &mocks.AnchorWitnessStore{}
+
+		suggester := NewSuggester(witnessStore)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, suggestEndpoint, http.NoBody)
+
+		suggester.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, defaultMaxAnchors, witnessStore.GetRecentAnchorsArgsForCall(0))
+
+		respBytes, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		suggestion := &policy.PolicySuggestion{}
+		require.NoError(t, json.Unmarshal(respBytes, suggestion))
+		require.Equal(t, float64(policy.DefaultMinReliability), suggestion.MinReliability)
+	})
+
+	t.Run("error - invalid max anchors", func(t *testing.T) {
+		suggester := NewSuggester(&mocks.AnchorWitnessStore{})
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, suggestEndpoint+"?max-anchors=0", http.NoBody)
+
+		suggester.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("error - invalid min reliability", func(t *testing.T) {
+		suggester := NewSuggester(&mocks.AnchorWitnessStore{})
+
+		for _, value := range []string{"xxx", "-1", "101", "NaN"} {
+			rw := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, suggestEndpoint+"?min-reliability="+value, http.NoBody)
+
+			suggester.handle(rw, req)
+
+			result := rw.Result()
+			require.Equal(t, http.StatusBadRequest, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+		}
+	})
+
+	t.Run("error - witness store error", func(t *testing.T) {
+		witnessStore := &mocks.AnchorWitnessStore{}
+		witnessStore.GetRecentAnchorsReturns(nil, errors.New("injected store error"))
+
+		suggester := NewSuggester(witnessStore)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, suggestEndpoint, http.NoBody)
+
+		suggester.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
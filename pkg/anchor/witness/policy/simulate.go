@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"fmt"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+// SimulationResult contains the results of evaluating the current and a proposed witness policy
+// against a sample of anchors.
+type SimulationResult struct {
+	CurrentPolicy  string `json:"currentPolicy"`
+	ProposedPolicy string `json:"proposedPolicy"`
+
+	// Sampled is the total number of anchors in the sample.
+	Sampled int `json:"sampled"`
+	// Evaluated is the number of anchors that were evaluated against both policies.
+	Evaluated int `json:"evaluated"`
+	// Excluded is the number of anchors that were excluded from evaluation due to insufficient proof data.
+	Excluded int `json:"excluded"`
+
+	SatisfiedCurrent  int `json:"satisfiedCurrent"`
+	SatisfiedProposed int `json:"satisfiedProposed"`
+
+	// CurrentPercent is the percentage of evaluated anchors that satisfied the current policy.
+	CurrentPercent float64 `json:"currentPercent"`
+	// ProposedPercent is the percentage of evaluated anchors that would have satisfied the proposed policy.
+	ProposedPercent float64 `json:"proposedPercent"`
+}
+
+// Simulate evaluates the current and the proposed witness policies against the witness proofs of the given
+// anchors (anchor ID -> witness proofs). Anchors with no recorded witness data are excluded from the percentages
+// and counted in the Excluded field of the result.
+func Simulate(currentPolicy, proposedPolicy string, anchors map[string][]*proof.WitnessProof) (*SimulationResult, error) {
+	currentCfg, err := config.Parse(currentPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("parse current policy [%s]: %w", currentPolicy, err)
+	}
+
+	proposedCfg, err := config.Parse(proposedPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("parse proposed policy [%s]: %w", proposedPolicy, err)
+	}
+
+	result := &SimulationResult{
+		CurrentPolicy:  currentPolicy,
+		ProposedPolicy: proposedPolicy,
+		Sampled:        len(anchors),
+	}
+
+	for _, witnessProofs := range anchors {
+		if !hasSufficientProofData(witnessProofs) {
+			result.Excluded++
+
+			continue
+		}
+
+		result.Evaluated++
+
+		if evaluateConfig(currentCfg, witnessProofs) {
+			result.SatisfiedCurrent++
+		}
+
+		if evaluateConfig(proposedCfg, witnessProofs) {
+			result.SatisfiedProposed++
+		}
+	}
+
+	if result.Evaluated > 0 {
+		result.CurrentPercent = percent(result.SatisfiedCurrent, result.Evaluated)
+		result.ProposedPercent = percent(result.SatisfiedProposed, result.Evaluated)
+	}
+
+	return result, nil
+}
+
+func hasSufficientProofData(witnessProofs []*proof.WitnessProof) bool {
+	if len(witnessProofs) == 0 {
+		return false
+	}
+
+	for _, wp := range witnessProofs {
+		if wp == nil || wp.Witness == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func percent(n, total int) float64 {
+	return float64(n) * maxPercent / float64(total)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+func TestSimulate(t *testing.T) {
+	batchWitnessURL, err := url.Parse("https://batch.com/service")
+	require.NoError(t, err)
+
+	systemWitnessURL, err := url.Parse("https://system.com/service")
+	require.NoError(t, err)
+
+	allProofs := []*proof.WitnessProof{
+		{
+			Witness: &proof.Witness{Type: proof.WitnessTypeBatch, URI: vocab.NewURLProperty(batchWitnessURL)},
+			Proof:   []byte("proof"),
+		},
+		{
+			Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(systemWitnessURL)},
+			Proof:   []byte("proof"),
+		},
+	}
+
+	batchOnlyProofs := []*proof.WitnessProof{
+		{
+			Witness: &proof.Witness{Type: proof.WitnessTypeBatch, URI: vocab.NewURLProperty(batchWitnessURL)},
+			Proof:   []byte("proof"),
+		},
+		{
+			Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(systemWitnessURL)},
+		},
+	}
+
+	anchors := map[string][]*proof.WitnessProof{
+		"anchor1": allProofs,
+		"anchor2": batchOnlyProofs,
+		"anchor3": batchOnlyProofs,
+		"anchor4": allProofs,
+		"anchor5": nil,
+		"anchor6": {{Proof: []byte("proof")}},
+	}
+
+	t.Run("success - proposed policy is looser", func(t *testing.T) {
+		result, err := Simulate("", "OutOf(1,batch) OR OutOf(1,system)", anchors)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+
+		require.Equal(t, 6, result.Sampled)
+		require.Equal(t, 4, result.Evaluated)
+		require.Equal(t, 2, result.Excluded)
+		require.Equal(t, 2, result.SatisfiedCurrent)
+		require.Equal(t, 4, result.SatisfiedProposed)
+		require.Equal(t, float64(50), result.CurrentPercent)
+		require.Equal(t, float64(100), result.ProposedPercent)
+	})
+
+	t.Run("success - proposed policy is stricter", func(t *testing.T) {
+		result, err := Simulate("OutOf(1,batch) OR OutOf(1,system)", "LogRequired", anchors)
+		require.NoError(t, err)
+		require.Equal(t, 4, result.SatisfiedCurrent)
+		require.Equal(t, 0, result.SatisfiedProposed)
+		require.Equal(t, float64(0), result.ProposedPercent)
+	})
+
+	t.Run("success - no anchors", func(t *testing.T) {
+		result, err := Simulate("", "OutOf(1,batch)", nil)
+		require.NoError(t, err)
+		require.Equal(t, 0, result.Sampled)
+		require.Equal(t, 0, result.Evaluated)
+		require.Equal(t, float64(0), result.CurrentPercent)
+	})
+
+	t.Run("error - invalid current policy", func(t *testing.T) {
+		result, err := Simulate("InvalidPolicy", "OutOf(1,batch)", anchors)
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "parse current policy")
+	})
+
+	t.Run("error - invalid proposed policy", func(t *testing.T) {
+		result, err := Simulate("", "InvalidPolicy", anchors)
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "parse proposed policy")
+	})
+}
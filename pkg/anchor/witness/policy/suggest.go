@@ -0,0 +1,201 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+// DefaultMinReliability is the default minimum reliability (percentage of requested anchors that were signed)
+// of a witness for the witness to be considered reliable when suggesting a witness policy.
+const DefaultMinReliability = 95
+
+// WitnessReliability contains the observed reliability of a witness.
+type WitnessReliability struct {
+	URI  string            `json:"uri"`
+	Type proof.WitnessType `json:"type"`
+
+	// Requested is the number of evaluated anchors for which the witness was asked to provide a proof.
+	Requested int `json:"requested"`
+	// Signed is the number of evaluated anchors for which the witness provided a proof.
+	Signed int `json:"signed"`
+	// Reliability is the percentage of requested anchors that were signed by the witness.
+	Reliability float64 `json:"reliability"`
+	// Reliable indicates whether the reliability of the witness meets the minimum reliability.
+	Reliable bool `json:"reliable"`
+}
+
+// PolicySuggestion contains a witness policy which is suggested from the observed reliability of the witnesses
+// of recent anchors, along with the statistics that the suggestion is based on. The suggestion is advisory only.
+// It isn't applied and should be reviewed (and possibly simulated) before it is adopted.
+type PolicySuggestion struct {
+	// Advisory is always true and indicates that the suggested policy has not been applied.
+	Advisory bool `json:"advisory"`
+
+	SuggestedPolicy string  `json:"suggestedPolicy"`
+	MinReliability  float64 `json:"minReliability"`
+
+	// Sampled is the total number of anchors in the sample.
+	Sampled int `json:"sampled"`
+	// Evaluated is the number of anchors from which the witness statistics were computed.
+	Evaluated int `json:"evaluated"`
+	// Excluded is the number of anchors that were excluded due to insufficient proof data.
+	Excluded int `json:"excluded"`
+
+	// SatisfiedSuggested is the number of evaluated anchors that would have satisfied the suggested policy.
+	SatisfiedSuggested int `json:"satisfiedSuggested"`
+	// SuggestedPercent is the percentage of evaluated anchors that would have satisfied the suggested policy.
+	SuggestedPercent float64 `json:"suggestedPercent"`
+
+	// Witnesses contains the statistics of each witness, ordered by reliability (most reliable first).
+	Witnesses []*WitnessReliability `json:"witnesses"`
+}
+
+// Suggest computes the reliability of each witness from the witness proofs of the given anchors (anchor ID ->
+// witness proofs) and suggests a witness policy which requires proofs from the witnesses that are reliable,
+// i.e. whose reliability is at least minReliability percent. For each witness type, the suggested policy
+// requires as many proofs as there are reliable witnesses of that type. Anchors with no recorded witness
+// data are excluded and counted in the Excluded field of the result.
+func Suggest(anchors map[string][]*proof.WitnessProof, minReliability float64) (*PolicySuggestion, error) {
+	if minReliability < 0 || minReliability > maxPercent {
+		return nil, fmt.Errorf("minimum reliability must be between 0 and %d", maxPercent)
+	}
+
+	result := &PolicySuggestion{
+		Advisory:       true,
+		MinReliability: minReliability,
+		Sampled:        len(anchors),
+	}
+
+	stats := make(map[string]*WitnessReliability)
+
+	var evaluated [][]*proof.WitnessProof
+
+	for _, witnessProofs := range anchors {
+		if !hasSufficientProofData(witnessProofs) {
+			result.Excluded++
+
+			continue
+		}
+
+		evaluated = append(evaluated, witnessProofs)
+
+		for _, wp := range witnessProofs {
+			addWitnessStats(stats, wp)
+		}
+	}
+
+	result.Evaluated = len(evaluated)
+	result.Witnesses = sortedWitnessStats(stats, minReliability)
+	result.SuggestedPolicy = suggestPolicy(result.Witnesses)
+
+	if result.Evaluated == 0 {
+		return result, nil
+	}
+
+	cfg, err := config.Parse(result.SuggestedPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("parse suggested policy [%s]: %w", result.SuggestedPolicy, err)
+	}
+
+	for _, witnessProofs := range evaluated {
+		if evaluateConfig(cfg, witnessProofs) {
+			result.SatisfiedSuggested++
+		}
+	}
+
+	result.SuggestedPercent = percent(result.SatisfiedSuggested, result.Evaluated)
+
+	return result, nil
+}
+
+// addWitnessStats adds the given witness proof to the statistics of the witness. A witness is considered to have
+// been requested if it was selected for the anchor or if it provided a proof (since a witness only provides
+// a proof if it was requested).
+func addWitnessStats(stats map[string]*WitnessReliability, wp *proof.WitnessProof) {
+	signed := wp.Proof != nil
+
+	if !wp.Selected && !signed {
+		return
+	}
+
+	uri := wp.URI.String()
+	key := string(wp.Type) + "|" + uri
+
+	s, ok := stats[key]
+	if !ok {
+		s = &WitnessReliability{URI: uri, Type: wp.Type}
+
+		stats[key] = s
+	}
+
+	s.Requested++
+
+	if signed {
+		s.Signed++
+	}
+}
+
+func sortedWitnessStats(stats map[string]*WitnessReliability, minReliability float64) []*WitnessReliability {
+	witnesses := make([]*WitnessReliability, 0, len(stats))
+
+	for _, s := range stats {
+		s.Reliability = percent(s.Signed, s.Requested)
+		s.Reliable = s.Reliability >= minReliability
+
+		witnesses = append(witnesses, s)
+	}
+
+	sort.Slice(witnesses, func(i, j int) bool {
+		if witnesses[i].Reliability != witnesses[j].Reliability {
+			return witnesses[i].Reliability > witnesses[j].Reliability
+		}
+
+		if witnesses[i].Type != witnesses[j].Type {
+			return witnesses[i].Type < witnesses[j].Type
+		}
+
+		return witnesses[i].URI < witnesses[j].URI
+	})
+
+	return witnesses
+}
+
+// suggestPolicy returns a policy which, for each witness type that was observed, requires the number of proofs
+// that the reliable witnesses of that type are expected to provide. A type without any reliable witnesses
+// doesn't require any proofs.
+func suggestPolicy(witnesses []*WitnessReliability) string {
+	var rules []string
+
+	for _, witnessType := range []proof.WitnessType{proof.WitnessTypeBatch, proof.WitnessTypeSystem} {
+		observed := false
+		reliable := 0
+
+		for _, w := range witnesses {
+			if w.Type != witnessType {
+				continue
+			}
+
+			observed = true
+
+			if w.Reliable {
+				reliable++
+			}
+		}
+
+		if observed {
+			rules = append(rules, fmt.Sprintf("%s(%d,%s)", config.OutOf, reliable, witnessType))
+		}
+	}
+
+	return strings.Join(rules, " "+config.AND+" ")
+}
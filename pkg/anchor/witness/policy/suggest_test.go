@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+func TestSuggest(t *testing.T) {
+	const (
+		batch1  = "https://batch1.com/service"
+		batch2  = "https://batch2.com/service"
+		system1 = "https://system1.com/service"
+		system2 = "https://system2.com/service"
+	)
+
+	newProof := func(witnessType proof.WitnessType, uri string, selected, signed bool) *proof.WitnessProof {
+		wp := &proof.WitnessProof{
+			Witness: &proof.Witness{
+				Type:     witnessType,
+				URI:      vocab.NewURLProperty(vocab.MustParseURL(uri)),
+				Selected: selected,
+			},
+		}
+
+		if signed {
+			wp.Proof = []byte("proof")
+		}
+
+		return wp
+	}
+
+	// batch1 and system1 sign every anchor. batch2 signs half of the anchors and system2 is never selected.
+	allSigned := []*proof.WitnessProof{
+		newProof(proof.WitnessTypeBatch, batch1, true, true),
+		newProof(proof.WitnessTypeBatch, batch2, true, true),
+		newProof(proof.WitnessTypeSystem, system1, true, true),
+		newProof(proof.WitnessTypeSystem, system2, false, false),
+	}
+
+	batch2Missing := []*proof.WitnessProof{
+		newProof(proof.WitnessTypeBatch, batch1, true, true),
+		newProof(proof.WitnessTypeBatch, batch2, true, false),
+		newProof(proof.WitnessTypeSystem, system1, true, true),
+		newProof(proof.WitnessTypeSystem, system2, false, false),
+	}
+
+	anchors := map[string][]*proof.WitnessProof{
+		"anchor1": allSigned,
+		"anchor2": batch2Missing,
+		"anchor3": allSigned,
+		"anchor4": batch2Missing,
+		"anchor5": nil,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		result, err := Suggest(anchors, DefaultMinReliability)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+
+		require.True(t, result.Advisory)
+		require.Equal(t, "OutOf(1,batch) AND OutOf(1,system)", result.SuggestedPolicy)
+		require.Equal(t, float64(DefaultMinReliability), result.MinReliability)
+		require.Equal(t, 5, result.Sampled)
+		require.Equal(t, 4, result.Evaluated)
+		require.Equal(t, 1, result.Excluded)
+		require.Equal(t, 4, result.SatisfiedSuggested)
+		require.Equal(t, float64(100), result.SuggestedPercent)
+
+		require.Len(t, result.Witnesses, 3)
+
+		require.Equal(t, &WitnessReliability{
+			URI: batch1, Type: proof.WitnessTypeBatch, Requested: 4, Signed: 4, Reliability: 100, Reliable: true,
+		}, result.Witnesses[0])
+		require.Equal(t, &WitnessReliability{
+			URI: system1, Type: proof.WitnessTypeSystem, Requested: 4, Signed: 4, Reliability: 100, Reliable: true,
+		}, result.Witnesses[1])
+		require.Equal(t, &WitnessReliability{
+			URI: batch2, Type: proof.WitnessTypeBatch, Requested: 4, Signed: 2, Reliability: 50, Reliable: false,
+		}, result.Witnesses[2])
+	})
+
+	t.Run("success - lower minimum reliability", func(t *testing.T) {
+		result, err := Suggest(anchors, 50)
+		require.NoError(t, err)
+		require.Equal(t, "OutOf(2,batch) AND OutOf(1,system)", result.SuggestedPolicy)
+		require.Equal(t, 2, result.SatisfiedSuggested)
+		require.Equal(t, float64(50), result.SuggestedPercent)
+		require.True(t, result.Witnesses[2].Reliable)
+	})
+
+	t.Run("success - no reliable witnesses of a type", func(t *testing.T) {
+		result, err := Suggest(map[string][]*proof.WitnessProof{
+			"anchor1": {
+				newProof(proof.WitnessTypeBatch, batch1, true, false),
+				newProof(proof.WitnessTypeSystem, system1, true, true),
+			},
+		}, DefaultMinReliability)
+		require.NoError(t, err)
+		require.Equal(t, "OutOf(0,batch) AND OutOf(1,system)", result.SuggestedPolicy)
+		require.Equal(t, 1, result.SatisfiedSuggested)
+	})
+
+	t.Run("success - no anchors", func(t *testing.T) {
+		result, err := Suggest(nil, DefaultMinReliability)
+		require.NoError(t, err)
+		require.True(t, result.Advisory)
+		require.Empty(t, result.SuggestedPolicy)
+		require.Zero(t, result.Evaluated)
+		require.Zero(t, result.SuggestedPercent)
+		require.Empty(t, result.Witnesses)
+	})
+
+	t.Run("error - invalid minimum reliability", func(t *testing.T) {
+		for _, value := range []float64{-1, 101} {
+			result, err := Suggest(anchors, value)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "minimum reliability must be between 0 and 100")
+			require.Nil(t, result)
+		}
+	})
+}
@@ -0,0 +1,374 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package proofrest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/piprate/json-gold/ld"
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-go/pkg/docutil"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/util"
+	proofapi "github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	"github.com/trustbloc/orb/pkg/didanchor"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+var logger = log.New("witness-proof-rest")
+
+const (
+	didPathVariable = "did"
+
+	didWebPrefix = "did:web:"
+
+	statusNotFoundResponse      = "DID not found.\n"
+	internalServerErrorResponse = "Internal Server Error.\n"
+
+	proofVerificationMethod = "verificationMethod"
+	proofCreated            = "created"
+	proofDomain             = "domain"
+)
+
+// Proof contains information about a single witness proof.
+type Proof struct {
+	// Witness is the host of the witness which signed the proof.
+	Witness string `json:"witness"`
+	// Type is the type of witness (batch or system).
+	Type proofapi.WitnessType `json:"type"`
+	// Domain is the domain of the VCT log in which the witness recorded the anchor. This field
+	// is empty if the witness doesn't have a log.
+	Domain             string `json:"domain,omitempty"`
+	VerificationMethod string `json:"verificationMethod"`
+	Created            string `json:"created,omitempty"`
+}
+
+// Response contains the witness proofs for the current anchor of a DID.
+type Response struct {
+	DID    string   `json:"did"`
+	Anchor string   `json:"anchor"`
+	Proofs []*Proof `json:"proofs"`
+	// PolicySatisfied indicates whether or not the proofs satisfy the currently configured witness policy.
+	PolicySatisfied bool `json:"policySatisfied"`
+}
+
+type didAnchors interface {
+	Get(suffix string) (string, error)
+}
+
+type anchorGraph interface {
+	Read(hl string) (*linkset.Linkset, error)
+}
+
+type witnessPolicy interface {
+	Evaluate(witnesses []*proofapi.WitnessProof) (bool, error)
+}
+
+type activityStore interface {
+	QueryReferences(refType spi.ReferenceType, query *spi.Criteria, opts ...spi.QueryOpt) (spi.ReferenceIterator, error)
+}
+
+// Providers contains the providers required by the witness proof REST handler.
+type Providers struct {
+	DidAnchors    didAnchors
+	AnchorGraph   anchorGraph
+	WitnessPolicy witnessPolicy
+	ActivityStore activityStore
+	DocLoader     ld.DocumentLoader
+}
+
+// Reader implements a REST handler which returns the witness proofs that the node has recorded
+// for the current anchor of a DID.
+type Reader struct {
+	*Providers
+
+	namespace  string
+	serviceIRI *url.URL
+	marshal    func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new REST handler which returns the witness proofs for the current anchor of a DID.
+func NewReader(namespace string, serviceIRI *url.URL, providers *Providers) *Reader {
+	return &Reader{
+		Providers:  providers,
+		namespace:  namespace,
+		serviceIRI: serviceIRI,
+		marshal:    json.Marshal,
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *Reader) Path() string {
+	return fmt.Sprintf("/did/{%s}/proofs", didPathVariable)
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *Reader) handleGet(w http.ResponseWriter, req *http.Request) {
+	did := mux.Vars(req)[didPathVariable]
+
+	resp, err := h.getProofs(did)
+	if err != nil {
+		if errors.Is(err, didanchor.ErrDataNotFound) {
+			logger.Debug("Anchor not found for DID", logfields.WithDID(did), log.WithError(err))
+
+			writeResponse(w, http.StatusNotFound, []byte(statusNotFoundResponse))
+
+			return
+		}
+
+		logger.Error("Error retrieving witness proofs for DID", logfields.WithDID(did), log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := h.marshal(resp)
+	if err != nil {
+		logger.Error("Error marshalling witness proofs", logfields.WithDID(did), log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (h *Reader) getProofs(did string) (*Response, error) {
+	suffix, err := h.getSuffix(did)
+	if err != nil {
+		return nil, err
+	}
+
+	anchor, err := h.DidAnchors.Get(suffix)
+	if err != nil {
+		return nil, fmt.Errorf("get anchor for suffix [%s]: %w", suffix, err)
+	}
+
+	vc, err := h.getCredential(anchor)
+	if err != nil {
+		return nil, err
+	}
+
+	systemWitnesses, err := h.getSystemWitnessesIRI()
+	if err != nil {
+		return nil, err
+	}
+
+	proofs, witnessProofs := toProofs(vc.Proofs, systemWitnesses)
+
+	// Use the same policy evaluation as the server does when it collects proofs for an anchor.
+	satisfied, err := h.WitnessPolicy.Evaluate(witnessProofs)
+	if err != nil {
+		return nil, fmt.Errorf("evaluate witness policy: %w", err)
+	}
+
+	return &Response{
+		DID:             did,
+		Anchor:          anchor,
+		Proofs:          proofs,
+		PolicySatisfied: satisfied,
+	}, nil
+}
+
+func (h *Reader) getSuffix(did string) (string, error) {
+	if !strings.HasPrefix(did, h.namespace+docutil.NamespaceDelimiter) {
+		return "", fmt.Errorf("DID [%s] is not in namespace [%s]: %w", did, h.namespace, didanchor.ErrDataNotFound)
+	}
+
+	suffix := did[strings.LastIndex(did, docutil.NamespaceDelimiter)+1:]
+	if suffix == "" {
+		return "", fmt.Errorf("DID suffix is empty: %w", didanchor.ErrDataNotFound)
+	}
+
+	return suffix, nil
+}
+
+func (h *Reader) getCredential(anchor string) (*verifiable.Credential, error) {
+	anchorLinkset, err := h.AnchorGraph.Read(anchor)
+	if err != nil {
+		return nil, fmt.Errorf("read anchor [%s]: %w", anchor, err)
+	}
+
+	anchorLink := anchorLinkset.Link()
+	if anchorLink == nil {
+		return nil, fmt.Errorf("anchor [%s] contains no links", anchor)
+	}
+
+	vc, err := util.VerifiableCredentialFromAnchorLink(anchorLink,
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(h.DocLoader),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get verifiable credential from anchor [%s]: %w", anchor, err)
+	}
+
+	return vc, nil
+}
+
+func (h *Reader) getSystemWitnessesIRI() ([]*url.URL, error) {
+	it, err := h.ActivityStore.QueryReferences(spi.Witness,
+		spi.NewCriteria(
+			spi.WithObjectIRI(h.serviceIRI),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query references for system witnesses: %w", err)
+	}
+
+	defer func() {
+		err = it.Close()
+		if err != nil {
+			log.CloseIteratorError(logger, err)
+		}
+	}()
+
+	systemWitnessesIRI, err := storeutil.ReadReferences(it, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read system witnesses from iterator: %w", err)
+	}
+
+	return systemWitnessesIRI, nil
+}
+
+// toProofs converts the given credential proofs to witness proofs. A proof is considered to be from a system
+// witness if the host in the proof's verification method matches the host of a configured system witness,
+// otherwise the proof is from a batch witness. All configured system witnesses are included in the returned
+// witness proofs (without a proof if the credential doesn't contain one) so that the witness policy is
+// evaluated against the total number of system witnesses, as it is when proofs are collected.
+func toProofs(vcProofs []verifiable.Proof, systemWitnesses []*url.URL) ([]*Proof, []*proofapi.WitnessProof) {
+	systemWitnessProofs := make(map[string]*proofapi.WitnessProof)
+
+	var witnessProofs []*proofapi.WitnessProof
+
+	for _, systemWitness := range systemWitnesses {
+		wp := &proofapi.WitnessProof{
+			Witness: &proofapi.Witness{
+				Type: proofapi.WitnessTypeSystem,
+				URI:  vocab.NewURLProperty(systemWitness),
+			},
+		}
+
+		systemWitnessProofs[systemWitness.Host] = wp
+		witnessProofs = append(witnessProofs, wp)
+	}
+
+	proofs := make([]*Proof, 0, len(vcProofs))
+
+	for _, vcProof := range vcProofs {
+		p := &Proof{
+			VerificationMethod: stringValue(vcProof[proofVerificationMethod]),
+			Created:            stringValue(vcProof[proofCreated]),
+			Domain:             stringValue(vcProof[proofDomain]),
+		}
+
+		p.Witness = hostFromVerificationMethod(p.VerificationMethod)
+
+		proofBytes, err := json.Marshal(vcProof)
+		if err != nil {
+			// Shouldn't happen since the proof was unmarshalled from JSON.
+			logger.Warn("Error marshalling proof", log.WithError(err))
+
+			continue
+		}
+
+		wp, ok := systemWitnessProofs[p.Witness]
+		if ok && wp.Proof == nil {
+			p.Type = proofapi.WitnessTypeSystem
+		} else {
+			p.Type = proofapi.WitnessTypeBatch
+
+			wp = &proofapi.WitnessProof{
+				Witness: &proofapi.Witness{
+					Type: proofapi.WitnessTypeBatch,
+				},
+			}
+
+			witnessProofs = append(witnessProofs, wp)
+		}
+
+		wp.HasLog = p.Domain != ""
+		wp.Proof = proofBytes
+
+		proofs = append(proofs, p)
+	}
+
+	return proofs, witnessProofs
+}
+
+// hostFromVerificationMethod returns the host from a did:web verification method,
+// e.g. "did:web:orb.domain1.com#key1" returns "orb.domain1.com".
+func hostFromVerificationMethod(verificationMethod string) string {
+	if !strings.HasPrefix(verificationMethod, didWebPrefix) {
+		return ""
+	}
+
+	host := strings.TrimPrefix(verificationMethod, didWebPrefix)
+
+	if i := strings.Index(host, "#"); i >= 0 {
+		host = host[:i]
+	}
+
+	// A did:web may also contain a path (separated by ':').
+	if i := strings.Index(host, docutil.NamespaceDelimiter); i >= 0 {
+		host = host[:i]
+	}
+
+	// The port is percent-encoded in a did:web.
+	decodedHost, err := url.PathUnescape(host)
+	if err != nil {
+		return host
+	}
+
+	return decodedHost
+}
+
+func stringValue(v interface{}) string {
+	s, ok := v.(string)
+	if !ok {
+		return ""
+	}
+
+	return s
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			log.WriteResponseBodyError(logger, err)
+
+			return
+		}
+
+		log.WroteResponse(logger, body)
+	}
+}
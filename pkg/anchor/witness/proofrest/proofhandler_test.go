@@ -0,0 +1,266 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package proofrest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	policymocks "github.com/trustbloc/orb/pkg/anchor/witness/policy/mocks"
+	proofapi "github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	"github.com/trustbloc/orb/pkg/didanchor"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+const (
+	namespace = "did:orb"
+	suffix    = "EiBASbC8BstzmFwGyFVPY4ToGh_75G74WHKpqNNXwQ7RaA"
+	did       = namespace + ":uEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg:" + suffix
+	anchorHL  = "hl:uEiABbKSeh3rb4MOjS1Era2_62bBPwP9EytPSg5tIkNYiSQ"
+)
+
+var (
+	serviceIRI  = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	service2IRI = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+	service3IRI = testutil.MustParseURL("https://orb.domain3.com/services/orb")
+)
+
+func TestNewReader(t *testing.T) {
+	r := NewReader(namespace, serviceIRI, &Providers{})
+	require.NotNil(t, r.Handler())
+	require.Equal(t, http.MethodGet, r.Method())
+	require.Equal(t, "/did/{did}/proofs", r.Path())
+}
+
+func TestReader_Handler(t *testing.T) {
+	anchorLinkset := &linkset.Linkset{}
+	require.NoError(t, json.Unmarshal([]byte(anchorLinksetJSON), anchorLinkset))
+
+	didAnchors := &mockDIDAnchors{anchors: map[string]string{suffix: anchorHL}}
+	graph := &mockAnchorGraph{linksets: map[string]*linkset.Linkset{anchorHL: anchorLinkset}}
+
+	newProviders := func(t *testing.T) *Providers {
+		t.Helper()
+
+		wp, err := policy.New(&policymocks.PolicyStore{}, 0)
+		require.NoError(t, err)
+
+		return &Providers{
+			DidAnchors:    didAnchors,
+			AnchorGraph:   graph,
+			WitnessPolicy: wp,
+			ActivityStore: memstore.New(""),
+			DocLoader:     testutil.GetLoader(t),
+		}
+	}
+
+	t.Run("Policy satisfied", func(t *testing.T) {
+		providers := newProviders(t)
+
+		activityStore := memstore.New("")
+		require.NoError(t, activityStore.AddReference(spi.Witness, serviceIRI, service2IRI))
+
+		providers.ActivityStore = activityStore
+
+		resp := getProofs(t, NewReader(namespace, serviceIRI, providers), did, http.StatusOK)
+		require.Equal(t, did, resp.DID)
+		require.Equal(t, anchorHL, resp.Anchor)
+		require.True(t, resp.PolicySatisfied)
+		require.Len(t, resp.Proofs, 2)
+
+		require.Equal(t, "orb.domain1.com", resp.Proofs[0].Witness)
+		require.Equal(t, proofapi.WitnessTypeBatch, resp.Proofs[0].Type)
+		require.Equal(t, "http://orb.vct:8077/maple2020", resp.Proofs[0].Domain)
+		require.Equal(t, "did:web:orb.domain1.com#orb1key2", resp.Proofs[0].VerificationMethod)
+		require.Equal(t, "2022-03-15T21:21:54.631Z", resp.Proofs[0].Created)
+
+		require.Equal(t, "orb.domain2.com", resp.Proofs[1].Witness)
+		require.Equal(t, proofapi.WitnessTypeSystem, resp.Proofs[1].Type)
+		require.Equal(t, "https://orb.domain2.com", resp.Proofs[1].Domain)
+		require.Equal(t, "did:web:orb.domain2.com#orb2key", resp.Proofs[1].VerificationMethod)
+	})
+
+	t.Run("Policy not satisfied", func(t *testing.T) {
+		providers := newProviders(t)
+
+		// The default policy requires a proof from all system witnesses.
+		activityStore := memstore.New("")
+		require.NoError(t, activityStore.AddReference(spi.Witness, serviceIRI, service2IRI))
+		require.NoError(t, activityStore.AddReference(spi.Witness, serviceIRI, service3IRI))
+
+		providers.ActivityStore = activityStore
+
+		resp := getProofs(t, NewReader(namespace, serviceIRI, providers), did, http.StatusOK)
+		require.False(t, resp.PolicySatisfied)
+		require.Len(t, resp.Proofs, 2)
+	})
+
+	t.Run("Unknown DID -> not found", func(t *testing.T) {
+		getProofs(t, NewReader(namespace, serviceIRI, newProviders(t)), namespace+":uAAA:EiDunknown",
+			http.StatusNotFound)
+	})
+
+	t.Run("Invalid namespace -> not found", func(t *testing.T) {
+		getProofs(t, NewReader(namespace, serviceIRI, newProviders(t)), "did:other:"+suffix, http.StatusNotFound)
+	})
+
+	t.Run("Empty suffix -> not found", func(t *testing.T) {
+		getProofs(t, NewReader(namespace, serviceIRI, newProviders(t)), namespace+":", http.StatusNotFound)
+	})
+
+	t.Run("DID anchors error", func(t *testing.T) {
+		providers := newProviders(t)
+		providers.DidAnchors = &mockDIDAnchors{err: errors.New("injected DID anchors error")}
+
+		getProofs(t, NewReader(namespace, serviceIRI, providers), did, http.StatusInternalServerError)
+	})
+
+	t.Run("Anchor graph error", func(t *testing.T) {
+		providers := newProviders(t)
+		providers.AnchorGraph = &mockAnchorGraph{}
+
+		getProofs(t, NewReader(namespace, serviceIRI, providers), did, http.StatusInternalServerError)
+	})
+
+	t.Run("Witness policy error", func(t *testing.T) {
+		providers := newProviders(t)
+		providers.WitnessPolicy = &mockWitnessPolicy{err: errors.New("injected witness policy error")}
+
+		getProofs(t, NewReader(namespace, serviceIRI, providers), did, http.StatusInternalServerError)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewReader(namespace, serviceIRI, newProviders(t))
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		getProofs(t, h, did, http.StatusInternalServerError)
+	})
+}
+
+func TestHostFromVerificationMethod(t *testing.T) {
+	require.Equal(t, "orb.domain1.com", hostFromVerificationMethod("did:web:orb.domain1.com#key1"))
+	require.Equal(t, "orb.domain1.com:8080", hostFromVerificationMethod("did:web:orb.domain1.com%3A8080#key1"))
+	require.Equal(t, "orb.domain1.com", hostFromVerificationMethod("did:web:orb.domain1.com:services:orb#key1"))
+	require.Empty(t, hostFromVerificationMethod("https://orb.domain1.com/keys/key1"))
+}
+
+func getProofs(t *testing.T, h *Reader, did string, expectedStatus int) *Response {
+	t.Helper()
+
+	req := mux.SetURLVars(
+		httptest.NewRequest(http.MethodGet, "https://orb.domain1.com/did/"+did+"/proofs", nil),
+		map[string]string{didPathVariable: did},
+	)
+
+	rw := httptest.NewRecorder()
+
+	h.handleGet(rw, req)
+
+	result := rw.Result()
+	require.Equal(t, expectedStatus, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	if expectedStatus != http.StatusOK {
+		return nil
+	}
+
+	resp := &Response{}
+	require.NoError(t, json.Unmarshal(respBytes, resp))
+
+	return resp
+}
+
+type mockDIDAnchors struct {
+	anchors map[string]string
+	err     error
+}
+
+func (m *mockDIDAnchors) Get(suffix string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+
+	anchor, ok := m.anchors[suffix]
+	if !ok {
+		return "", didanchor.ErrDataNotFound
+	}
+
+	return anchor, nil
+}
+
+type mockWitnessPolicy struct {
+	err error
+}
+
+func (m *mockWitnessPolicy) Evaluate([]*proofapi.WitnessProof) (bool, error) {
+	return false, m.err
+}
+
+type mockAnchorGraph struct {
+	linksets map[string]*linkset.Linkset
+}
+
+func (m *mockAnchorGraph) Read(hl string) (*linkset.Linkset, error) {
+	ls, ok := m.linksets[hl]
+	if !ok {
+		return nil, errors.New("anchor not found")
+	}
+
+	return ls, nil
+}
+
+const anchorLinksetJSON = `{
+  "linkset": [
+    {
+      "anchor": "hl:uEiABbKSeh3rb4MOjS1Era2_62bBPwP9EytPSg5tIkNYiSQ",
+      "author": [
+        {
+          "href": "https://orb.domain1.com/services/orb"
+        }
+      ],
+      "original": [
+        {
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiC6PTR6rRVbrvx2g06lYRwBDwWvO-8ZZdqBuvXUvYgBWg%22%2C%22author%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Forb.domain1.com%2Fservices%2Forb%22%7D%5D%2C%22item%22%3A%5B%7B%22href%22%3A%22did%3Aorb%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%3AEiBASbC8BstzmFwGyFVPY4ToGh_75G74WHKpqNNXwQ7RaA%22%2C%22previous%22%3A%22hl%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%22%7D%2C%7B%22href%22%3A%22did%3Aorb%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%3AEiDXvAb7xkkj8QleSnrt1sWah5lGT7MlGIYLNOmeILCoNA%22%2C%22previous%22%3A%22hl%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%22%7D%2C%7B%22href%22%3A%22did%3Aorb%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%3AEiDljSIyFmQfONMeWRuXaAK7Veh0FDUsqtMu_FuWRes72g%22%2C%22previous%22%3A%22hl%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%22%7D%2C%7B%22href%22%3A%22did%3Aorb%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%3AEiDJ0RDNSlRAe-X00jInBus3srtOwKDjkPhBScsCocAomQ%22%2C%22previous%22%3A%22hl%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%22%7D%2C%7B%22href%22%3A%22did%3Aorb%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%3AEiAcIEwYOvzu9JeDgi3tZPDvx4NOH5mgRKDax1o199_9QA%22%2C%22previous%22%3A%22hl%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%22%7D%2C%7B%22href%22%3A%22did%3Aorb%3AuEiCWKM6q1fGqlpW4HjpXYP5KbM8bLRQv_wZkDwyV_rp_JQ%3AEiB9lWJFoXkUFyak38-hhjp8DK3ceNVtkhdTm_PvoR8JdA%22%2C%22previous%22%3A%22hl%3AuEiCWKM6q1fGqlpW4HjpXYP5KbM8bLRQv_wZkDwyV_rp_JQ%22%7D%2C%7B%22href%22%3A%22did%3Aorb%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%3AEiDfKmNhXjZBT9pi_ddpLRSp85p8jCTgMcHwEsW8C6xBVQ%22%2C%22previous%22%3A%22hl%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%22%7D%2C%7B%22href%22%3A%22did%3Aorb%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%3AEiBVjbmP2rO3zo0Dha94KivlGuBUINdyWvrpwHdC3xgGAA%22%2C%22previous%22%3A%22hl%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%22%7D%2C%7B%22href%22%3A%22did%3Aorb%3AuEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA%3AEiBK9-TmD1pxSCBNfBYV5Ww6YZbQHH1ZZo5go2WpQ2_2GA%22%2C%22previous%22%3A%22hl%3AuEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA%22%7D%2C%7B%22href%22%3A%22did%3Aorb%3AuEiCWKM6q1fGqlpW4HjpXYP5KbM8bLRQv_wZkDwyV_rp_JQ%3AEiBS7BB7sgLlHkgX1wSQVYShaOPumObH2xieRnYA3CpIjA%22%2C%22previous%22%3A%22hl%3AuEiCWKM6q1fGqlpW4HjpXYP5KbM8bLRQv_wZkDwyV_rp_JQ%22%7D%2C%7B%22href%22%3A%22did%3Aorb%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%3AEiCmKxvTAtorz91jOPl-jCHMdCU2C_C96fqgc5nR3bbS4g%22%2C%22previous%22%3A%22hl%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%22%7D%5D%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%7D%5D%7D",
+          "type": "application/linkset+json"
+        }
+      ],
+      "profile": [
+        {
+          "href": "https://w3id.org/orb#v0"
+        }
+      ],
+      "related": [
+        {
+          "href": "data:application/json,%7B%22linkset%22%3A%5B%7B%22anchor%22%3A%22hl%3AuEiBqkaTRFZScQsXTw8IDBSpVxiKGqjJCDUcgiwpcd2frLw%22%2C%22profile%22%3A%5B%7B%22href%22%3A%22https%3A%2F%2Fw3id.org%2Forb%23v0%22%7D%5D%2C%22up%22%3A%5B%7B%22href%22%3A%22hl%3AuEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQzNRNFNGM2JQLXFiMGk5TUl6X2tfbi1yS2ktQmhTZ2NPazhxb0tWY0pxcmd4QmlwZnM6Ly9iYWZrcmVpZnhpb2NpbHhudDcydTMyaXh1eWl6NzR0N2g3a3prZjZheWtrYTRoamhzdmlmZmxxdGt2eQ%22%7D%2C%7B%22href%22%3A%22hl%3AuEiCWKM6q1fGqlpW4HjpXYP5KbM8bLRQv_wZkDwyV_rp_JQ%3AuoQ-BeEtodHRwczovL29yYi5kb21haW4yLmNvbS9jYXMvdUVpQ1dLTTZxMWZHcWxwVzRIanBYWVA1S2JNOGJMUlF2X3daa0R3eVZfcnBfSlE%22%7D%2C%7B%22href%22%3A%22hl%3AuEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA%3AuoQ-BeEtodHRwczovL29yYi5kb21haW4yLmNvbS9jYXMvdUVpQ18xN0I3d0dHUTYxU1ppMlFEUU1wUWNCLWNxTFp6MW1kQk9QY1QzY0FaQkE%22%7D%5D%2C%22via%22%3A%5B%7B%22href%22%3A%22hl%3AuEiC6PTR6rRVbrvx2g06lYRwBDwWvO-8ZZdqBuvXUvYgBWg%3AuoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQzZQVFI2clJWYnJ2eDJnMDZsWVJ3QkR3V3ZPLThaWmRxQnV2WFV2WWdCV2d4QmlwZnM6Ly9iYWZrcmVpZjJodTJodmxpdmxveHB5NXVkajJzd2NoYWJiNGMyNm83cGRmczV2YW4yNnhrbDNjYWJsaQ%22%7D%5D%7D%5D%7D",
+          "type": "application/linkset+json"
+        }
+      ],
+      "replies": [
+        {
+          "href": "data:application/json,%7B%22%40context%22%3A%5B%22https%3A%2F%2Fwww.w3.org%2F2018%2Fcredentials%2Fv1%22%2C%22https%3A%2F%2Fw3id.org%2Fsecurity%2Fsuites%2Fed25519-2020%2Fv1%22%5D%2C%22credentialSubject%22%3A%22hl%3AuEiBqkaTRFZScQsXTw8IDBSpVxiKGqjJCDUcgiwpcd2frLw%22%2C%22id%22%3A%22https%3A%2F%2Forb.domain1.com%2Fvc%2Fd53b1df9-1acf-4389-a006-0f88496afe46%22%2C%22issuanceDate%22%3A%222022-03-15T21%3A21%3A54.62437567Z%22%2C%22issuer%22%3A%22https%3A%2F%2Forb.domain1.com%22%2C%22proof%22%3A%5B%7B%22created%22%3A%222022-03-15T21%3A21%3A54.631Z%22%2C%22domain%22%3A%22http%3A%2F%2Forb.vct%3A8077%2Fmaple2020%22%2C%22proofPurpose%22%3A%22assertionMethod%22%2C%22proofValue%22%3A%22gRPF8XAA4iYMwl26RmFGUoN99wuUnD_igmvIlzzDpPRLVDtmA8wrNbUdJIAKKhyMJFju8OjciSGYMY_bDRjBAw%22%2C%22type%22%3A%22Ed25519Signature2020%22%2C%22verificationMethod%22%3A%22did%3Aweb%3Aorb.domain1.com%23orb1key2%22%7D%2C%7B%22created%22%3A%222022-03-15T21%3A21%3A54.744899145Z%22%2C%22domain%22%3A%22https%3A%2F%2Forb.domain2.com%22%2C%22proofPurpose%22%3A%22assertionMethod%22%2C%22proofValue%22%3A%22FX58osRrwU11IrUfhVTi0ucrNEq05Cv94CQNvd8SdoY66fAjwU2--m8plvxwVnXmxnlV23i6htkq4qI8qrDgAA%22%2C%22type%22%3A%22Ed25519Signature2020%22%2C%22verificationMethod%22%3A%22did%3Aweb%3Aorb.domain2.com%23orb2key%22%7D%5D%2C%22type%22%3A%22VerifiableCredential%22%7D",
+          "type": "application/ld+json"
+        }
+      ]
+    }
+  ]
+}`
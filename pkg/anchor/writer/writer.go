@@ -645,13 +645,15 @@ func (c *Writer) postOfferActivity(ctx context.Context, anchorLink *linkset.Link
 		return fmt.Errorf("marshal anchor linkset: %w", err)
 	}
 
-	offer := vocab.NewOfferActivity(
+	offer, err := vocab.NewWitnessOfferActivity(
 		vocab.NewObjectProperty(vocab.WithDocument(anchorLinksetDoc)),
 		vocab.WithTo(selectedWitnessesIRIs...),
 		vocab.WithStartTime(&startTime),
 		vocab.WithEndTime(&endTime),
-		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
 	)
+	if err != nil {
+		return fmt.Errorf("create offer for anchor[%s]: %w", anchorLink.Anchor(), err)
+	}
 
 	activityID, err := c.Outbox.Post(ctx, offer)
 	if err != nil {
@@ -27,6 +27,9 @@ import (
 	"github.com/trustbloc/sidetree-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-go/pkg/document"
 	svcoperation "github.com/trustbloc/sidetree-svc-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-svc-go/pkg/batch/cutter"
+	"github.com/trustbloc/sidetree-svc-go/pkg/batch/opqueue"
+	sidetreemocks "github.com/trustbloc/sidetree-svc-go/pkg/mocks"
 
 	apclientmocks "github.com/trustbloc/orb/pkg/activitypub/client/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
@@ -1041,6 +1044,180 @@ func TestWriter_WriteAnchor(t *testing.T) {
 	})
 }
 
+func TestWriter_WriteAnchor_MaxPreviousAnchors(t *testing.T) {
+	ps := mempubsub.New(mempubsub.Config{})
+	defer ps.Stop()
+
+	casClient, err := cas.New(mem.NewProvider(), casURL, nil, &mocks.MetricsProvider{}, 100)
+	require.NoError(t, err)
+
+	anchorGraph := graph.New(&graph.Providers{
+		CasWriter: casClient,
+		CasResolver: casresolver.New(casClient, nil,
+			casresolver.NewWebCASResolver(
+				transport.New(&http.Client{}, testutil.MustParseURL("https://example.com/keys/public-key"),
+					transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+				wfclient.New(), "https"), &mocks.MetricsProvider{}),
+	})
+
+	apServiceIRI, err := url.Parse(activityPubURL)
+	require.NoError(t, err)
+
+	casIRI, err := url.Parse(casURL)
+	require.NoError(t, err)
+
+	hostMetaResponseBytes, err := json.Marshal(discoveryrest.JRD{
+		Links: []discoveryrest.Link{
+			{
+				Type: discoveryrest.ActivityJSONType,
+				Href: apServiceIRI.String(),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	testServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write(hostMetaResponseBytes)
+			require.NoError(t, err)
+		}))
+	defer testServer.Close()
+
+	wfClient := wfclient.New(wfclient.WithHTTPClient(httpMock(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewBufferString(webfingerPayload)),
+			StatusCode: http.StatusOK,
+		}, nil
+	})))
+
+	newWriter := func(t *testing.T, maxPreviousAnchors int) *Writer {
+		t.Helper()
+
+		anchorEventStore, err := anchorlinkstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		statusStore, err := anchorstatus.New(mem.NewProvider(), testutil.GetTaskMgr(t), testutil.GetExpiryService(t), time.Minute)
+		require.NoError(t, err)
+
+		providers := &Providers{
+			AnchorGraph:            anchorGraph,
+			DidAnchors:             memdidanchor.New(),
+			AnchorBuilder:          &mockTxnBuilder{},
+			OpProcessor:            &mockOpProcessor{},
+			Outbox:                 &mockOutbox{},
+			Signer:                 &mockSigner{},
+			MonitoringSvc:          &mockMonitoring{},
+			WitnessStore:           &mockWitnessStore{},
+			ActivityStore:          memstore.New(""),
+			AnchorLinkStore:        anchorEventStore,
+			AnchorEventStatusStore: statusStore,
+			WFClient:               wfClient,
+			WitnessPolicy:          &mockWitnessPolicy{},
+			ProofHandler:           servicemocks.NewProofHandler(),
+			GeneratorRegistry:      generator.NewRegistry(),
+			AnchorLinkBuilder: anchorlinkset.NewBuilder(generator.NewRegistry(),
+				anchorlinkset.WithMaxPreviousAnchors(maxPreviousAnchors)),
+		}
+
+		c, err := New(namespace, apServiceIRI, apServiceIRI, casIRI, vocab.JSONMediaType, providers,
+			&anchormocks.AnchorPublisher{}, ps, testMaxWitnessDelay, false,
+			resourceresolver.New(http.DefaultClient, nil, &mocks.DomainResolver{}),
+			5, &mocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		return c
+	}
+
+	// The Sidetree batch writer cuts batches of at most MaxOperationCount operations (2 for the mock protocol
+	// client) and Nacks (i.e. re-queues) the batch if WriteAnchor returns an error.
+	newCutter := func(t *testing.T, numOps int) *cutter.BatchCutter {
+		t.Helper()
+
+		bc := cutter.New(sidetreemocks.NewMockProtocolClient(), &opqueue.MemQueue{})
+
+		for i := 0; i < numOps; i++ {
+			_, err := bc.Add(&svcoperation.QueuedOperation{
+				Type:         operation.TypeCreate,
+				UniqueSuffix: fmt.Sprintf("did-%d", i),
+				Namespace:    namespace,
+				AnchorOrigin: fmt.Sprintf("%s/services/orb", testServer.URL),
+			}, 0)
+			require.NoError(t, err)
+		}
+
+		return bc
+	}
+
+	writeBatch := func(c *Writer, bc *cutter.BatchCutter) (bool, error) {
+		result, err := bc.Cut(true)
+		require.NoError(t, err)
+
+		if len(result.Operations) == 0 {
+			return false, nil
+		}
+
+		opRefs := make([]*svcoperation.Reference, len(result.Operations))
+
+		for i, op := range result.Operations {
+			opRefs[i] = &svcoperation.Reference{
+				UniqueSuffix: op.UniqueSuffix,
+				Type:         op.Type,
+				AnchorOrigin: op.AnchorOrigin,
+			}
+		}
+
+		err = c.WriteAnchor(fmt.Sprintf("%d.hl:uEiBqkaTRFZScQsXTw8IDBSpVxiKGqjJCDUcgiwpcd2frLw", len(opRefs)),
+			nil, opRefs, result.ProtocolVersion)
+		if err != nil {
+			result.Nack(err)
+
+			return true, err
+		}
+
+		result.Ack()
+
+		return true, nil
+	}
+
+	t.Run("max previous anchors not less than max operations per batch", func(t *testing.T) {
+		c := newWriter(t, 2)
+		bc := newCutter(t, 5)
+
+		numBatches := 0
+
+		for {
+			written, err := writeBatch(c, bc)
+			require.NoError(t, err)
+
+			if !written {
+				break
+			}
+
+			numBatches++
+
+			require.LessOrEqual(t, numBatches, 3, "batch was re-cut")
+		}
+
+		require.Equal(t, 3, numBatches)
+
+		result, err := bc.Cut(true)
+		require.NoError(t, err)
+		require.Zero(t, result.Pending)
+	})
+
+	t.Run("max previous anchors less than max operations per batch", func(t *testing.T) {
+		// The server rejects this configuration at startup since the same batch would be re-cut indefinitely.
+		c := newWriter(t, 1)
+		bc := newCutter(t, 2)
+
+		for i := 0; i < 3; i++ {
+			written, err := writeBatch(c, bc)
+			require.True(t, written)
+			require.ErrorIs(t, err, anchorlinkset.ErrMaxPreviousAnchorsExceeded)
+		}
+	})
+}
+
 //nolint:maintidx
 func TestWriter_handle(t *testing.T) {
 	ps := mempubsub.New(mempubsub.Config{})
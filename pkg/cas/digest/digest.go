@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package digest computes and parses the HTTP Digest header (RFC 3230) for CAS content.
+package digest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
+)
+
+const (
+	// Header is the name of the HTTP header that contains the digest of the response body.
+	Header = "Digest"
+
+	algorithmSHA256 = "sha-256"
+)
+
+// ErrMismatch is returned when the digest does not match the content.
+var ErrMismatch = errors.New("content digest mismatch")
+
+// Compute returns the value of the Digest header for the given content, for example, "sha-256=<base64>".
+func Compute(content []byte) string {
+	d := sha256.Sum256(content)
+
+	return fmt.Sprintf("%s=%s", algorithmSHA256, base64.StdEncoding.EncodeToString(d[:]))
+}
+
+// ParseSHA256 returns the SHA-256 digest from the given Digest header value. The header may contain multiple
+// comma-separated digests, in which case only the SHA-256 digest is returned. False is returned if the header
+// doesn't contain a SHA-256 digest.
+func ParseSHA256(headerValue string) ([]byte, bool, error) {
+	for _, instance := range strings.Split(headerValue, ",") {
+		alg, value, found := strings.Cut(strings.TrimSpace(instance), "=")
+		if !found || !strings.EqualFold(alg, algorithmSHA256) {
+			continue
+		}
+
+		d, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, false, fmt.Errorf("decode %s digest: %w", algorithmSHA256, err)
+		}
+
+		if len(d) != sha256.Size {
+			return nil, false, fmt.Errorf("invalid %s digest length: %d", algorithmSHA256, len(d))
+		}
+
+		return d, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// Verify verifies that the given Digest header value matches the content and also the multibase-encoded
+// multihash resource hash (if the resource hash uses SHA2-256). ErrMismatch is returned if either check fails.
+// Nil is returned if the header doesn't contain a SHA-256 digest.
+func Verify(headerValue string, content []byte, resourceHash string) error {
+	expected, ok, err := ParseSHA256(headerValue)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return nil
+	}
+
+	actual := sha256.Sum256(content)
+
+	if !bytes.Equal(expected, actual[:]) {
+		return fmt.Errorf("%w: digest header does not match the digest of the content", ErrMismatch)
+	}
+
+	if resourceHash == "" {
+		return nil
+	}
+
+	resourceDigest, ok := sha256FromResourceHash(resourceHash)
+	if ok && !bytes.Equal(expected, resourceDigest) {
+		return fmt.Errorf("%w: digest header does not match resource hash [%s]", ErrMismatch, resourceHash)
+	}
+
+	return nil
+}
+
+// sha256FromResourceHash returns the digest from the multibase-encoded multihash if the multihash uses SHA2-256.
+func sha256FromResourceHash(resourceHash string) ([]byte, bool) {
+	_, mhBytes, err := multibase.Decode(resourceHash)
+	if err != nil {
+		return nil, false
+	}
+
+	decoded, err := mh.Decode(mhBytes)
+	if err != nil || decoded.Code != mh.SHA2_256 {
+		return nil, false
+	}
+
+	return decoded.Digest, true
+}
@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package digest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+const content = `{"field":"value"}`
+
+func TestCompute(t *testing.T) {
+	require.Equal(t, "sha-256=7XACtDnprIRfIjV9giusFERzD722AW0+yUMil7nsn3M=", Compute([]byte("content")))
+}
+
+func TestParseSHA256(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		d, ok, err := ParseSHA256(Compute([]byte(content)))
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Len(t, d, 32)
+	})
+
+	t.Run("Multiple digests", func(t *testing.T) {
+		d, ok, err := ParseSHA256("md5=HUXZLQLMuI/KZ5KDcJPcOA==, SHA-256=" + Compute([]byte(content))[8:])
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Len(t, d, 32)
+	})
+
+	t.Run("No SHA-256 digest", func(t *testing.T) {
+		d, ok, err := ParseSHA256("md5=HUXZLQLMuI/KZ5KDcJPcOA==")
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Nil(t, d)
+	})
+
+	t.Run("Invalid base64", func(t *testing.T) {
+		_, _, err := ParseSHA256("sha-256=%%%")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode sha-256 digest")
+	})
+
+	t.Run("Invalid length", func(t *testing.T) {
+		_, _, err := ParseSHA256("sha-256=YWJj")
+		require.EqualError(t, err, "invalid sha-256 digest length: 3")
+	})
+}
+
+func TestVerify(t *testing.T) {
+	resourceHash, err := hashlink.New().CreateResourceHash([]byte(content))
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, Verify(Compute([]byte(content)), []byte(content), resourceHash))
+		require.NoError(t, Verify(Compute([]byte(content)), []byte(content), ""))
+	})
+
+	t.Run("No SHA-256 digest", func(t *testing.T) {
+		require.NoError(t, Verify("md5=HUXZLQLMuI/KZ5KDcJPcOA==", []byte(content), resourceHash))
+	})
+
+	t.Run("Resource hash is not a SHA2-256 multihash", func(t *testing.T) {
+		require.NoError(t, Verify(Compute([]byte(content)), []byte(content), "invalid"))
+	})
+
+	t.Run("Content mismatch", func(t *testing.T) {
+		err := Verify(Compute([]byte(content)), []byte("other"), resourceHash)
+		require.True(t, errors.Is(err, ErrMismatch))
+		require.Contains(t, err.Error(), "does not match the digest of the content")
+	})
+
+	t.Run("Resource hash mismatch", func(t *testing.T) {
+		err := Verify(Compute([]byte("other")), []byte("other"), resourceHash)
+		require.True(t, errors.Is(err, ErrMismatch))
+		require.Contains(t, err.Error(), "does not match resource hash")
+	})
+
+	t.Run("Invalid header", func(t *testing.T) {
+		err := Verify("sha-256=YWJj", []byte(content), resourceHash)
+		require.Error(t, err)
+		require.False(t, errors.Is(err, ErrMismatch))
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -54,13 +55,72 @@ type Client struct {
 	metrics metricsProvider
 }
 
+type options struct {
+	cidFormatOpts  []extendedcasclient.CIDFormatOption
+	ratePerSecond  int
+	rateLimitBurst int
+}
+
+// Option is an IPFS client option.
+type Option func(opts *options)
+
+// WithCIDFormatOptions sets the CID format options used when writing content.
+func WithCIDFormatOptions(opts ...extendedcasclient.CIDFormatOption) Option {
+	return func(options *options) {
+		options.cidFormatOpts = append(options.cidFormatOpts, opts...)
+	}
+}
+
+// WithRateLimit paces requests to the IPFS node (or gateway) so that no more than perSecond requests
+// are sent per second, with bursts of up to burst requests. The limit is shared by all concurrent callers
+// of the client. If the node responds with 429 (Too Many Requests) then the request is retried after
+// the delay in the Retry-After header (or after an exponential backoff if the header isn't provided).
+// Rate limiting is disabled if perSecond is zero (the default).
+func WithRateLimit(perSecond, burst int) Option {
+	return func(options *options) {
+		options.ratePerSecond = perSecond
+		options.rateLimitBurst = burst
+	}
+}
+
 // New creates cas client.
 // If no CID version is specified, then v1 will be used by default.
-func New(url string, timeout time.Duration, cacheSize int, metrics metricsProvider, opts ...extendedcasclient.CIDFormatOption) *Client {
-	ipfs := shell.NewShell(url)
+func New(url string, timeout time.Duration, cacheSize int, metrics metricsProvider,
+	opts ...extendedcasclient.CIDFormatOption,
+) *Client {
+	return NewWithOptions(url, timeout, cacheSize, metrics, WithCIDFormatOptions(opts...))
+}
+
+// NewWithOptions creates cas client with the given options (CID format, rate limiting, etc.).
+// If no CID version is specified, then v1 will be used by default.
+func NewWithOptions(url string, timeout time.Duration, cacheSize int, metrics metricsProvider,
+	opts ...Option,
+) *Client {
+	options := &options{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var ipfs *shell.Shell
+
+	if options.ratePerSecond > 0 {
+		ipfs = shell.NewShellWithClient(url, &http.Client{
+			Transport: newRateLimitedTransport(
+				&http.Transport{
+					Proxy:             http.ProxyFromEnvironment,
+					DisableKeepAlives: true,
+				},
+				options.ratePerSecond, options.rateLimitBurst,
+			),
+		})
+	} else {
+		ipfs = shell.NewShell(url)
+	}
+
 	ipfs.SetTimeout(timeout)
 
-	return newClient(ipfs, cacheSize, metrics, opts...)
+	return newClient(ipfs, cacheSize, metrics, options.cidFormatOpts...)
 }
 
 func newClient(ipfs ipfsClient, cacheSize int, metrics metricsProvider, opts ...extendedcasclient.CIDFormatOption) *Client {
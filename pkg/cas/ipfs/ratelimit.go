@@ -0,0 +1,200 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ipfs
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+)
+
+// rateLimiter is a token bucket rate limiter which allows up to 'burst' requests at once and
+// then paces subsequent requests at the given rate. The limiter is safe for concurrent use.
+type rateLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	burst    int
+	// tat is the theoretical arrival time of the next request if requests were evenly paced.
+	tat time.Time
+}
+
+func newRateLimiter(perSecond, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		interval: time.Second / time.Duration(perSecond),
+		burst:    burst,
+	}
+}
+
+// wait blocks until a request is allowed or until the context is done, in which case
+// the context error is returned.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+
+		return ctx.Err()
+	}
+}
+
+// reserve reserves a token and returns the amount of time to wait before the token may be used.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+
+	if l.tat.Before(now) {
+		l.tat = now
+	}
+
+	delay := l.tat.Sub(now) - time.Duration(l.burst-1)*l.interval
+
+	l.tat = l.tat.Add(l.interval)
+
+	return delay
+}
+
+// cancel returns a reserved token which wasn't used.
+func (l *rateLimiter) cancel() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.tat = l.tat.Add(-l.interval)
+}
+
+// rateLimitedTransport paces requests using a rate limiter that is shared by all callers of the transport.
+// If the server responds with 429 (Too Many Requests) then the request is retried after the delay in the
+// Retry-After header or, if not provided, after an exponential backoff.
+type rateLimitedTransport struct {
+	base           http.RoundTripper
+	limiter        *rateLimiter
+	maxRetries     int
+	initialBackoff time.Duration
+}
+
+func newRateLimitedTransport(base http.RoundTripper, perSecond, burst int) *rateLimitedTransport {
+	return &rateLimitedTransport{
+		base:           base,
+		limiter:        newRateLimiter(perSecond, burst),
+		maxRetries:     defaultMaxRetries,
+		initialBackoff: defaultInitialBackoff,
+	}
+}
+
+// RoundTrip waits for the rate limiter and then sends the request, retrying if the server is throttling requests.
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	backoff := t.initialBackoff
+
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= t.maxRetries {
+			return resp, err
+		}
+
+		// A request with a body may only be retried if the body can be recreated.
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, nil
+		}
+
+		delay, ok := retryAfter(resp.Header.Get("Retry-After"))
+		if !ok {
+			delay = backoff
+			backoff *= 2
+		}
+
+		drainAndClose(resp.Body)
+
+		logger.Debug("IPFS request was throttled. Retrying after delay.", logfields.WithURL(req.URL),
+			logfields.WithBackoff(delay))
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, ctx.Err()
+		}
+
+		if req.GetBody != nil {
+			body, e := req.GetBody()
+			if e != nil {
+				return nil, e
+			}
+
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+	}
+}
+
+// retryAfter parses the value of a Retry-After header, which may be either a number of seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	delay := time.Until(t)
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay, true
+}
+
+func drainAndClose(body io.ReadCloser) {
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		logger.Debug("Error draining response body", log.WithError(err))
+	}
+
+	closeAndLog(body)
+}
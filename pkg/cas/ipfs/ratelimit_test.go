@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ipfs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("burst then pace", func(t *testing.T) {
+		l := newRateLimiter(20, 2)
+
+		start := time.Now()
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, l.wait(context.Background()))
+		}
+
+		// Two requests are allowed immediately and the next two are paced at 50ms intervals.
+		require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	})
+
+	t.Run("shared by concurrent callers", func(t *testing.T) {
+		l := newRateLimiter(50, 1)
+
+		start := time.Now()
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				require.NoError(t, l.wait(context.Background()))
+			}()
+		}
+
+		wg.Wait()
+
+		require.GreaterOrEqual(t, time.Since(start), 75*time.Millisecond)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		l := newRateLimiter(1, 1)
+
+		require.NoError(t, l.wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := l.wait(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// The cancelled reservation should have been returned.
+		require.LessOrEqual(t, l.reserve(), time.Second)
+	})
+}
+
+func TestRateLimitedTransport(t *testing.T) {
+	t.Run("retry after 429", func(t *testing.T) {
+		var attempts int32
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&attempts, 1) < 3 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+
+				return
+			}
+
+			fmt.Fprint(w, "{}")
+		}))
+		defer serv.Close()
+
+		cas := NewWithOptions(serv.URL, 20*time.Second, 0, &orbmocks.MetricsProvider{}, WithRateLimit(100, 10))
+		require.NotNil(t, cas)
+
+		read, err := cas.Read("uEiAWradITyYpRGT3pMhcKfPL8kpJBGePjFjZOlS0zqAUqw")
+		require.NoError(t, err)
+		require.NotNil(t, read)
+		require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		var attempts int32
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer serv.Close()
+
+		tr := newRateLimitedTransport(http.DefaultTransport, 100, 10)
+		tr.initialBackoff = time.Millisecond
+
+		req, err := http.NewRequest(http.MethodGet, serv.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := tr.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		require.Equal(t, int32(defaultMaxRetries+1), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("request body is resent", func(t *testing.T) {
+		var attempts int32
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, "content", string(body))
+
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		}))
+		defer serv.Close()
+
+		tr := newRateLimitedTransport(http.DefaultTransport, 100, 10)
+		tr.initialBackoff = time.Millisecond
+
+		req, err := http.NewRequest(http.MethodPost, serv.URL, strings.NewReader("content"))
+		require.NoError(t, err)
+
+		resp, err := tr.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("context cancelled during backoff", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "10")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer serv.Close()
+
+		tr := newRateLimitedTransport(http.DefaultTransport, 100, 10)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, serv.URL, nil)
+		require.NoError(t, err)
+
+		_, err = tr.RoundTrip(req) //nolint:bodyclose
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := retryAfter("")
+	require.False(t, ok)
+	require.Zero(t, d)
+
+	d, ok = retryAfter("2")
+	require.True(t, ok)
+	require.Equal(t, 2*time.Second, d)
+
+	_, ok = retryAfter("-1")
+	require.False(t, ok)
+
+	_, ok = retryAfter("invalid")
+	require.False(t, ok)
+
+	d, ok = retryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	require.True(t, ok)
+	require.Greater(t, d, 50*time.Second)
+
+	d, ok = retryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	require.True(t, ok)
+	require.Zero(t, d)
+}
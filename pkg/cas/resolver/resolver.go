@@ -13,17 +13,24 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"path"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
 	"github.com/trustbloc/logutil-go/pkg/log"
+	"go.opentelemetry.io/otel/trace"
 
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/cas/digest"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/multihash"
+	"github.com/trustbloc/orb/pkg/observability/tracing"
 	webfingerclient "github.com/trustbloc/orb/pkg/webfinger/client"
 )
 
@@ -33,6 +40,16 @@ const (
 	ipfsPrefix  = "ipfs://"
 
 	cidWithPossibleHintNumPartsWithDomainPort = 4
+
+	defaultGreylistDuration = 5 * time.Minute
+)
+
+// Sources from which CAS data may be resolved. The source is added to the resolve span.
+const (
+	sourceRequest = "request"
+	sourceLocal   = "local"
+	sourceWebCAS  = "webcas"
+	sourceIPFS    = "ipfs"
 )
 
 const logModule = "cas-resolver"
@@ -47,13 +64,42 @@ type metricsProvider interface {
 	CASResolveTime(value time.Duration)
 }
 
+type circuitBreaker interface {
+	Execute(ctx context.Context, host string, fn func() error) error
+}
+
 // Resolver represents a resolver that can resolve data in a CAS based on a CID (with possible hint) and a WebCAS URL.
 type Resolver struct {
-	localCAS       extendedcasclient.Client
-	ipfsReader     ipfsReader
-	webCASResolver WebCASResolver
-	metrics        metricsProvider
-	hl             *hashlink.HashLink
+	localCAS               extendedcasclient.Client
+	ipfsReader             ipfsReader
+	webCASResolver         WebCASResolver
+	metrics                metricsProvider
+	hl                     *hashlink.HashLink
+	verifyIPFSResourceHash bool
+	storeRemoteContent     bool
+	tracer                 trace.Tracer
+}
+
+// Option is a resolver option.
+type Option func(opts *Resolver)
+
+// WithStoreRemoteContent enables/disables storing content retrieved from a remote server (WebCAS or IPFS) in
+// the local CAS. If enabled (the default) then the content is stored locally so that subsequent requests are
+// served from the local CAS. If disabled then the content is verified against the resource hash and returned
+// without being stored (and the returned local hashlink is empty).
+func WithStoreRemoteContent(enable bool) Option {
+	return func(opts *Resolver) {
+		opts.storeRemoteContent = enable
+	}
+}
+
+// WithIPFSResourceHashVerification enables/disables the verification of data retrieved from IPFS. If enabled
+// (the default) then the data retrieved from IPFS must hash to the expected resource hash before it is
+// stored or returned.
+func WithIPFSResourceHashVerification(enable bool) Option {
+	return func(opts *Resolver) {
+		opts.verifyIPFSResourceHash = enable
+	}
 }
 
 type ipfsReader interface {
@@ -62,17 +108,33 @@ type ipfsReader interface {
 
 // New returns a new Resolver.
 // ipfsReader is optional. If not provided (is nil), CIDs with IPFS hints won't be resolvable.
-func New(casClient extendedcasclient.Client, ipfsReader ipfsReader, webCASResolver WebCASResolver, metrics metricsProvider) *Resolver {
-	return &Resolver{
-		localCAS:       casClient,
-		ipfsReader:     ipfsReader,
-		webCASResolver: webCASResolver,
-		metrics:        metrics,
-		hl:             hashlink.New(),
+func New(casClient extendedcasclient.Client, ipfsReader ipfsReader, webCASResolver WebCASResolver,
+	metrics metricsProvider, opts ...Option,
+) *Resolver {
+	r := &Resolver{
+		localCAS:               casClient,
+		ipfsReader:             ipfsReader,
+		webCASResolver:         webCASResolver,
+		metrics:                metrics,
+		hl:                     hashlink.New(),
+		verifyIPFSResourceHash: true,
+		storeRemoteContent:     true,
+		tracer:                 tracing.Tracer(tracing.SubsystemCAS),
+	}
+
+	for _, opt := range opts {
+		opt(r)
 	}
+
+	return r
 }
 
-// Resolve does the following:
+// Resolve resolves the data for the given CID (with possible hint). See ResolveContext.
+func (h *Resolver) Resolve(webCASURL *url.URL, hashWithPossibleHint string, data []byte) ([]byte, string, error) {
+	return h.ResolveContext(context.Background(), webCASURL, hashWithPossibleHint, data)
+}
+
+// ResolveContext does the following:
 //
 // 1. If data is provided (not nil), then it will be stored via the local CAS. That data passed in will then simply be
 // returned back to the caller, along with the hashlink of the stored data.
@@ -82,22 +144,34 @@ func New(casClient extendedcasclient.Client, ipfsReader ipfsReader, webCASResolv
 // Finally, the data is returned to the caller, along with the hashlink of the stored data.
 // In both cases above, the CID produced by the local CAS will be checked against the cid passed in to ensure they are
 // the same.
-func (h *Resolver) Resolve(_ *url.URL, hashWithPossibleHint string, data []byte) ([]byte, string, error) { //nolint:cyclop
+//
+// The resolution is traced with a span (which is tagged with the resource hash and the source of the data) along
+// with a child span for each read from the local CAS, WebFinger discovery, WebCAS fetch and IPFS fetch.
+func (h *Resolver) ResolveContext(ctx context.Context, _ *url.URL, hashWithPossibleHint string, //nolint:cyclop
+	data []byte,
+) ([]byte, string, error) {
 	startTime := time.Now()
 
 	defer func() { h.metrics.CASResolveTime(time.Since(startTime)) }()
 
+	ctx, span := h.tracer.Start(ctx, "resolve CAS resource")
+	defer span.End()
+
 	resourceHash, domain, links, err := h.getResourceHashWithPossibleDomainAndLinks(hashWithPossibleHint)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get resource hash from[%s]: %w", hashWithPossibleHint, err)
 	}
 
+	span.SetAttributes(tracing.ResourceHashAttribute(resourceHash))
+
 	if data != nil {
 		localHL, e := h.storeLocallyAndVerifyHash(data, resourceHash)
 		if e != nil {
 			return nil, "", fmt.Errorf("failed to store the data in the local CAS: %w", e)
 		}
 
+		span.SetAttributes(tracing.CASSourceAttribute(sourceRequest))
+
 		return data, localHL, nil
 	}
 
@@ -107,45 +181,171 @@ func (h *Resolver) Resolve(_ *url.URL, hashWithPossibleHint string, data []byte)
 	casLinks, ipfsLinks := separateLinks(links)
 
 	if h.localCAS.GetPrimaryWriterType() == "ipfs" && len(ipfsLinks) > 0 {
-		cid := ipfsLinks[0][len(ipfsPrefix):]
-
-		data, e := h.localCAS.Read(cid)
+		data, e := h.readFromIPFSCAS(ctx, ipfsLinks[0][len(ipfsPrefix):], resourceHash)
 		if e != nil {
-			return nil, "", fmt.Errorf("read from IPFS: %w", e)
+			return nil, "", e
 		}
 
+		span.SetAttributes(tracing.CASSourceAttribute(sourceIPFS))
+
 		return data, "", nil
 	}
 
 	// Ensure we have the data stored in the local CAS.
-	dataFromLocal, err := h.localCAS.Read(resourceHash)
-	if err != nil { //nolint: nestif // Breaking this up seems worse than leaving the nested ifs
+	dataFromLocal, err := h.readLocal(ctx, resourceHash)
+	if err != nil {
 		if errors.Is(err, orberrors.ErrContentNotFound) {
-			if len(casLinks) > 0 {
-				dataFromRemote, localHL, errGetAndStoreRemoteData := h.getAndStoreDataFromWebCASEndpoints(casLinks, resourceHash)
-				if errGetAndStoreRemoteData != nil {
-					return nil, "", fmt.Errorf("failure while getting and storing data from the remote "+
-						"WebCAS endpoints: %w", errGetAndStoreRemoteData)
-				}
-
-				return dataFromRemote, localHL, nil
-			}
-
-			if h.ipfsReader != nil && len(ipfsLinks) > 0 {
-				return h.getAndStoreDataFromIPFS(ipfsLinks[0][len(ipfsPrefix):], resourceHash)
-			}
-
-			if domain != "" {
-				return h.getAndStoreDataFromDomain(domain, resourceHash)
-			}
+			return h.resolveRemote(ctx, hashWithPossibleHint, resourceHash, domain, casLinks, ipfsLinks, err)
 		}
 
 		return nil, "", fmt.Errorf("failed to get data stored at %s from the local CAS: %w", resourceHash, err)
 	}
 
+	span.SetAttributes(tracing.CASSourceAttribute(sourceLocal))
+
 	return dataFromLocal, "", nil
 }
 
+// ResolveFrom resolves the data for the given hash (which may include a hint). The local CAS is checked first.
+// If the data isn't found locally then the WebCAS of each of the given preferred domains (discovered via WebFinger)
+// is queried, in order. If none of the preferred domains has the data then the links in the hashlink metadata
+// (or the domain in the hint) are used, as in Resolve. This is useful when it's known which domain is likely
+// to hold the content, since generic link traversal is avoided.
+func (h *Resolver) ResolveFrom(hashWithPossibleHint string, preferredDomains []string) ([]byte, string, error) {
+	startTime := time.Now()
+
+	defer func() { h.metrics.CASResolveTime(time.Since(startTime)) }()
+
+	ctx, span := h.tracer.Start(context.Background(), "resolve CAS resource")
+	defer span.End()
+
+	resourceHash, domain, links, err := h.getResourceHashWithPossibleDomainAndLinks(hashWithPossibleHint)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get resource hash from[%s]: %w", hashWithPossibleHint, err)
+	}
+
+	span.SetAttributes(tracing.ResourceHashAttribute(resourceHash))
+
+	dataFromLocal, err := h.readLocal(ctx, resourceHash)
+	if err == nil {
+		span.SetAttributes(tracing.CASSourceAttribute(sourceLocal))
+
+		return dataFromLocal, "", nil
+	}
+
+	if !errors.Is(err, orberrors.ErrContentNotFound) {
+		return nil, "", fmt.Errorf("failed to get data stored at %s from the local CAS: %w", resourceHash, err)
+	}
+
+	var errPreferred error
+
+	for _, preferredDomain := range preferredDomains {
+		data, localHL, e := h.getAndStoreDataFromDomain(ctx, preferredDomain, resourceHash)
+		if e == nil {
+			span.SetAttributes(tracing.CASSourceAttribute(sourceWebCAS))
+
+			return data, localHL, nil
+		}
+
+		logger.Debug("Unable to resolve data from preferred domain", logfields.WithHash(resourceHash),
+			logfields.WithDomain(preferredDomain), log.WithError(e))
+
+		errPreferred = e
+	}
+
+	casLinks, ipfsLinks := separateLinks(links)
+
+	if errPreferred != nil && len(casLinks) == 0 && len(ipfsLinks) == 0 && domain == "" {
+		return nil, "", fmt.Errorf("failed to resolve data for %s from the preferred domains: %w",
+			resourceHash, errPreferred)
+	}
+
+	return h.resolveRemote(ctx, hashWithPossibleHint, resourceHash, domain, casLinks, ipfsLinks, err)
+}
+
+// resolveRemote resolves data which wasn't found in the local CAS from the given WebCAS links, IPFS links
+// or domain (in that order). The given error is returned if there's nowhere to resolve the data from.
+// The source of the data is added to the span in the given context.
+func (h *Resolver) resolveRemote(ctx context.Context, hashWithPossibleHint, resourceHash, domain string,
+	casLinks, ipfsLinks []string, errNotFound error,
+) ([]byte, string, error) {
+	span := trace.SpanFromContext(ctx)
+
+	if len(casLinks) > 0 {
+		dataFromRemote, localHL, err := h.getAndStoreDataFromWebCASEndpoints(ctx, casLinks, resourceHash)
+		if err != nil {
+			return nil, "", fmt.Errorf("failure while getting and storing data from the remote "+
+				"WebCAS endpoints: %w", err)
+		}
+
+		span.SetAttributes(tracing.CASSourceAttribute(sourceWebCAS))
+
+		return dataFromRemote, localHL, nil
+	}
+
+	if len(ipfsLinks) > 0 {
+		if h.ipfsReader == nil {
+			return nil, "", fmt.Errorf("data for %s was not found in the local CAS and cannot be "+
+				"resolved from IPFS since no IPFS client is configured: %w", hashWithPossibleHint, errNotFound)
+		}
+
+		data, localHL, err := h.getAndStoreDataFromIPFS(ctx, ipfsLinks[0][len(ipfsPrefix):], resourceHash)
+		if err != nil {
+			return nil, "", err
+		}
+
+		span.SetAttributes(tracing.CASSourceAttribute(sourceIPFS))
+
+		return data, localHL, nil
+	}
+
+	if domain != "" {
+		data, localHL, err := h.getAndStoreDataFromDomain(ctx, domain, resourceHash)
+		if err != nil {
+			return nil, "", err
+		}
+
+		span.SetAttributes(tracing.CASSourceAttribute(sourceWebCAS))
+
+		return data, localHL, nil
+	}
+
+	return nil, "", fmt.Errorf("failed to get data stored at %s from the local CAS: %w", resourceHash, errNotFound)
+}
+
+// readLocal reads the data for the given resource hash from the local CAS.
+func (h *Resolver) readLocal(ctx context.Context, resourceHash string) ([]byte, error) {
+	_, span := h.tracer.Start(ctx, "read from local CAS",
+		trace.WithAttributes(tracing.ResourceHashAttribute(resourceHash)))
+
+	data, err := h.localCAS.Read(resourceHash)
+
+	tracing.EndSpan(span, err)
+
+	return data, err
+}
+
+// readFromIPFSCAS reads the data for the given CID from the local CAS, which is backed by IPFS.
+func (h *Resolver) readFromIPFSCAS(ctx context.Context, cid, resourceHash string) ([]byte, error) {
+	_, span := h.tracer.Start(ctx, "fetch from IPFS",
+		trace.WithAttributes(tracing.ResourceHashAttribute(resourceHash)))
+
+	data, err := h.localCAS.Read(cid)
+	if err != nil {
+		err = fmt.Errorf("read from IPFS: %w", err)
+	} else {
+		err = h.verifyIPFSData(data, resourceHash)
+	}
+
+	tracing.EndSpan(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
 func (h *Resolver) getResourceHashWithPossibleDomainAndLinks(hashWithPossibleHint string) (string, string, []string, error) {
 	var domain string
 
@@ -155,7 +355,28 @@ func (h *Resolver) getResourceHashWithPossibleDomainAndLinks(hashWithPossibleHin
 
 	hashWithPossibleHintParts := strings.Split(hashWithPossibleHint, ":")
 	if len(hashWithPossibleHintParts) == 1 {
-		return resourceHash, "", nil, nil
+		cid, err := hashlink.NormalizeCID(hashWithPossibleHint)
+		if err != nil {
+			// Not a CID, so it must be a resource hash.
+			return resourceHash, "", nil, nil
+		}
+
+		if cid != hashWithPossibleHint && h.ipfsReader == nil {
+			// A bare V0 CID can't be distinguished from a legacy resource hash and, without an IPFS client,
+			// can't be resolved from IPFS, so it's treated as a resource hash.
+			return resourceHash, "", nil, nil
+		}
+
+		// A bare V0 or V1 CID (without a hint) is converted to a resource hash (since that's how the local CAS
+		// stores the data) and an IPFS link with the normalized CID is added so that the data may be resolved
+		// from IPFS. Both CID versions therefore map to the same resource hash.
+		rh, err := multihash.CIDToMultihash(hashWithPossibleHint)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("CID[%s] cannot be converted to a resource hash: %w",
+				hashWithPossibleHint, err)
+		}
+
+		return rh, "", []string{ipfsPrefix + cid}, nil
 	}
 
 	switch hashWithPossibleHintParts[0] {
@@ -180,15 +401,13 @@ func (h *Resolver) getResourceHashWithPossibleDomainAndLinks(hashWithPossibleHin
 		links = hlInfo.Links
 
 	case "ipfs":
-		resourceHash = hashWithPossibleHintParts[1]
+		var err error
 
-		cid, err := multihash.ToV1CID(resourceHash)
+		resourceHash, links, err = getResourceHashAndLinksFromIPFSHint(hashWithPossibleHintParts[1])
 		if err != nil {
-			return "", "", nil, fmt.Errorf("resource hash[%s] cannot be converted to V1 CID: %w", resourceHash, err)
+			return "", "", nil, err
 		}
 
-		links = []string{ipfsPrefix + cid}
-
 	default:
 		return "", "", nil, fmt.Errorf("hint '%s' not supported", hashWithPossibleHintParts[0])
 	}
@@ -196,6 +415,39 @@ func (h *Resolver) getResourceHashWithPossibleDomainAndLinks(hashWithPossibleHin
 	return resourceHash, domain, links, nil
 }
 
+// getResourceHashAndLinksFromIPFSHint returns the resource hash and IPFS link for the value of an 'ipfs' hint,
+// which may either be a resource hash or a V0/V1 CID.
+func getResourceHashAndLinksFromIPFSHint(value string) (string, []string, error) {
+	if cid, err := hashlink.NormalizeCID(value); err == nil {
+		resourceHash, e := multihash.CIDToMultihash(value)
+		if e != nil {
+			return "", nil, fmt.Errorf("CID[%s] cannot be converted to a resource hash: %w", value, e)
+		}
+
+		return resourceHash, []string{ipfsPrefix + cid}, nil
+	}
+
+	cid, err := multihash.ToV1CID(value)
+	if err != nil {
+		return "", nil, fmt.Errorf("resource hash[%s] cannot be converted to V1 CID: %w", value, err)
+	}
+
+	return value, []string{ipfsPrefix + cid}, nil
+}
+
+// normalizeIPFSLink converts the CID in the given IPFS link to its canonical (V1) form so that
+// links with either CID version are resolved in the same way.
+func normalizeIPFSLink(link string) string {
+	cid, err := hashlink.NormalizeCID(link[len(ipfsPrefix):])
+	if err != nil {
+		logger.Debug("Unable to normalize CID in IPFS link", logfields.WithLink(link), log.WithError(err))
+
+		return link
+	}
+
+	return ipfsPrefix + cid
+}
+
 func separateLinks(links []string) ([]string, []string) {
 	var webcasLinks []string
 
@@ -206,7 +458,7 @@ func separateLinks(links []string) ([]string, []string) {
 		case strings.HasPrefix(link, httpsPrefix) || strings.HasPrefix(link, httpPrefix):
 			webcasLinks = append(webcasLinks, link)
 		case strings.HasPrefix(link, ipfsPrefix):
-			ipfsLinks = append(ipfsLinks, link)
+			ipfsLinks = append(ipfsLinks, normalizeIPFSLink(link))
 		default:
 			logger.Debug("Ignoring metadata link during CAS resolution", logfields.WithLink(link))
 		}
@@ -215,12 +467,30 @@ func separateLinks(links []string) ([]string, []string) {
 	return webcasLinks, ipfsLinks
 }
 
-func (h *Resolver) getAndStoreDataFromDomain(domain, resourceHash string) ([]byte, string, error) {
-	dataFromRemote, err := h.webCASResolver.Resolve(domain, resourceHash)
+// contextError returns an error if the given context is done (i.e. the caller has given up on the resolution)
+// so that remote data is neither fetched nor stored in the local CAS.
+func contextError(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+
+	return fmt.Errorf("resolution cancelled: %w", context.Cause(ctx))
+}
+
+func (h *Resolver) getAndStoreDataFromDomain(ctx context.Context, domain, resourceHash string) ([]byte, string, error) {
+	dataFromRemote, err := h.webCASResolver.resolve(ctx, domain, resourceHash)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to resolve domain and resource hash via WebCAS: %w", err)
 	}
 
+	if !h.storeRemoteContent {
+		return verifiedRemoteData(dataFromRemote, resourceHash, "the remote WebCAS endpoint")
+	}
+
+	if err := contextError(ctx); err != nil {
+		return nil, "", err
+	}
+
 	localHL, errStoreLocallyAndVerifyHash := h.storeLocallyAndVerifyHash(dataFromRemote, resourceHash)
 	if errStoreLocallyAndVerifyHash != nil {
 		return nil, "", fmt.Errorf("failure while storing data retrieved from the remote "+
@@ -233,7 +503,9 @@ func (h *Resolver) getAndStoreDataFromDomain(domain, resourceHash string) ([]byt
 	return dataFromRemote, localHL, nil
 }
 
-func (h *Resolver) getAndStoreDataFromWebCASEndpoints(webCASEndpoints []string, cid string) ([]byte, string, error) {
+func (h *Resolver) getAndStoreDataFromWebCASEndpoints(ctx context.Context, webCASEndpoints []string,
+	cid string,
+) ([]byte, string, error) {
 	if len(webCASEndpoints) == 0 {
 		return nil, "", fmt.Errorf("must provide at least one cas endpoint in order to retrieve data")
 	}
@@ -243,7 +515,11 @@ func (h *Resolver) getAndStoreDataFromWebCASEndpoints(webCASEndpoints []string,
 	var errMsgs []string
 
 	for _, webCASEndpoint := range webCASEndpoints {
-		data, localHL, err := h.getAndStoreDataFromWebCASEndpoint(webCASEndpoint, cid)
+		if err := contextError(ctx); err != nil {
+			return nil, "", err
+		}
+
+		data, localHL, err := h.getAndStoreDataFromWebCASEndpoint(ctx, webCASEndpoint, cid)
 		if err != nil {
 			errMsg := fmt.Sprintf("endpoint[%s]: %s", webCASEndpoint, err.Error())
 
@@ -265,17 +541,25 @@ func (h *Resolver) getAndStoreDataFromWebCASEndpoints(webCASEndpoints []string,
 	return nil, "", err
 }
 
-func (h *Resolver) getAndStoreDataFromWebCASEndpoint(webCASEndpoint, cid string) ([]byte, string, error) {
+func (h *Resolver) getAndStoreDataFromWebCASEndpoint(ctx context.Context, webCASEndpoint, cid string) ([]byte, string, error) {
 	webCASEndpointLink, err := url.Parse(webCASEndpoint)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to parse webcas endpoint: %w", err)
 	}
 
-	dataFromRemote, err := h.webCASResolver.GetDataViaWebCASEndpoint(webCASEndpointLink)
+	dataFromRemote, err := h.webCASResolver.getDataViaWebCASEndpoint(ctx, webCASEndpointLink)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get data via WebCAS endpoint: %w", err)
 	}
 
+	if !h.storeRemoteContent {
+		return verifiedRemoteData(dataFromRemote, cid, "the remote WebCAS endpoint")
+	}
+
+	if err := contextError(ctx); err != nil {
+		return nil, "", err
+	}
+
 	localHL, errStoreLocallyAndVerifyCID := h.storeLocallyAndVerifyHash(dataFromRemote, cid)
 	if errStoreLocallyAndVerifyCID != nil {
 		return nil, "", fmt.Errorf("failure while storing data retrieved from the remote "+
@@ -285,10 +569,37 @@ func (h *Resolver) getAndStoreDataFromWebCASEndpoint(webCASEndpoint, cid string)
 	return dataFromRemote, localHL, nil
 }
 
-func (h *Resolver) getAndStoreDataFromIPFS(cid, resourceHash string) ([]byte, string, error) {
+func (h *Resolver) getAndStoreDataFromIPFS(ctx context.Context, cid, resourceHash string) ([]byte, string, error) {
+	if err := contextError(ctx); err != nil {
+		return nil, "", err
+	}
+
+	_, span := h.tracer.Start(ctx, "fetch from IPFS",
+		trace.WithAttributes(tracing.ResourceHashAttribute(resourceHash)))
+
 	resp, err := h.ipfsReader.Read(cid)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read cid[%s] from ipfs: %w", cid, err)
+		err = fmt.Errorf("failed to read cid[%s] from ipfs: %w", cid, err)
+	} else {
+		err = h.verifyIPFSData(resp, resourceHash)
+	}
+
+	tracing.EndSpan(span, err)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !h.storeRemoteContent {
+		if h.verifyIPFSResourceHash {
+			return resp, "", nil
+		}
+
+		return verifiedRemoteData(resp, resourceHash, "IPFS")
+	}
+
+	if err := contextError(ctx); err != nil {
+		return nil, "", err
 	}
 
 	localHL, err := h.storeLocallyAndVerifyHash(resp, resourceHash)
@@ -300,6 +611,62 @@ func (h *Resolver) getAndStoreDataFromIPFS(cid, resourceHash string) ([]byte, st
 	return resp, localHL, nil
 }
 
+// verifyIPFSData ensures that the data retrieved from IPFS hashes to the given resource hash, since
+// a misconfigured IPFS gateway may return the wrong content.
+func (h *Resolver) verifyIPFSData(data []byte, resourceHash string) error {
+	if !h.verifyIPFSResourceHash {
+		return nil
+	}
+
+	return verifyResourceHash(data, resourceHash, "IPFS")
+}
+
+// verifiedRemoteData returns the given data (which is not stored locally) if it hashes to the given resource hash.
+func verifiedRemoteData(data []byte, resourceHash, source string) ([]byte, string, error) {
+	err := verifyResourceHash(data, resourceHash, source)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, "", nil
+}
+
+func verifyResourceHash(data []byte, resourceHash, source string) error {
+	hl := hashlink.New()
+
+	// Use the same hash algorithm as the expected resource hash.
+	code, err := multihashCode(resourceHash)
+	if err == nil {
+		hl = hashlink.New(hashlink.WithMultihashCode(code))
+	}
+
+	newResourceHash, err := hl.CreateResourceHash(data)
+	if err != nil {
+		return fmt.Errorf("failed to calculate the resource hash of the data retrieved from %s: %w", source, err)
+	}
+
+	if newResourceHash != resourceHash {
+		return fmt.Errorf("the resource hash of the data retrieved from %s (%s) does not match "+
+			"the resource hash from the original request (%s)", source, newResourceHash, resourceHash)
+	}
+
+	return nil
+}
+
+func multihashCode(resourceHash string) (uint, error) {
+	_, mhBytes, err := multibase.Decode(resourceHash)
+	if err != nil {
+		return 0, err
+	}
+
+	decoded, err := mh.Decode(mhBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(decoded.Code), nil
+}
+
 func (h *Resolver) storeLocallyAndVerifyHash(data []byte, resourceHash string) (string, error) {
 	newHLFromLocalCAS, err := h.localCAS.Write(data)
 	if err != nil {
@@ -330,12 +697,51 @@ type WebCASResolver struct {
 	httpClient         httpClient
 	webFingerClient    *webfingerclient.Client
 	webFingerURIScheme string
+	greylist           *greylist
+	circuitBreaker     circuitBreaker
+	tracer             trace.Tracer
+}
+
+// WebCASResolverOption is an option for the WebCAS resolver.
+type WebCASResolverOption func(opts *webCASResolverOptions)
+
+type webCASResolverOptions struct {
+	greylistDuration time.Duration
+	circuitBreaker   circuitBreaker
+}
+
+// WithGreylistDuration sets the amount of time that a WebCAS source is ignored after it has served content
+// which doesn't match the expected digest.
+func WithGreylistDuration(value time.Duration) WebCASResolverOption {
+	return func(opts *webCASResolverOptions) {
+		opts.greylistDuration = value
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker which causes requests to a WebCAS source to fail fast
+// after the source has consistently failed.
+func WithCircuitBreaker(value circuitBreaker) WebCASResolverOption {
+	return func(opts *webCASResolverOptions) {
+		opts.circuitBreaker = value
+	}
 }
 
 // NewWebCASResolver returns a new WebCASResolver.
-func NewWebCASResolver(httpClient httpClient, webFingerClient *webfingerclient.Client, webFingerURIScheme string) WebCASResolver {
+func NewWebCASResolver(httpClient httpClient, webFingerClient *webfingerclient.Client, webFingerURIScheme string,
+	opts ...WebCASResolverOption,
+) WebCASResolver {
+	options := &webCASResolverOptions{
+		greylistDuration: defaultGreylistDuration,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	return WebCASResolver{
 		httpClient: httpClient, webFingerClient: webFingerClient, webFingerURIScheme: webFingerURIScheme,
+		greylist: newGreylist(options.greylistDuration), circuitBreaker: options.circuitBreaker,
+		tracer: tracing.Tracer(tracing.SubsystemCAS),
 	}
 }
 
@@ -343,12 +749,21 @@ func NewWebCASResolver(httpClient httpClient, webFingerClient *webfingerclient.C
 // First, a WebFinger is done at domain in order to determine the WebCAS URL.
 // Then the data is retrieved using the WebCAS URL.
 func (w *WebCASResolver) Resolve(domain, cid string) ([]byte, error) {
+	return w.resolve(context.Background(), domain, cid)
+}
+
+func (w *WebCASResolver) resolve(ctx context.Context, domain, cid string) ([]byte, error) {
+	_, span := w.tracer.Start(ctx, "WebFinger discovery", trace.WithAttributes(tracing.ResourceHashAttribute(cid)))
+
 	webCASURL, err := w.webFingerClient.GetWebCASURL(fmt.Sprintf("%s://%s", w.webFingerURIScheme, domain), cid)
+
+	tracing.EndSpan(span, err)
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine WebCAS URL via WebFinger: %w", err)
 	}
 
-	data, err := w.GetDataViaWebCASEndpoint(webCASURL)
+	data, err := w.getDataViaWebCASEndpoint(ctx, webCASURL)
 	if err != nil {
 		return nil, fmt.Errorf("failure while getting and storing data from the remote "+
 			"WebCAS endpoint: %w", err)
@@ -360,9 +775,53 @@ func (w *WebCASResolver) Resolve(domain, cid string) ([]byte, error) {
 	return data, nil
 }
 
-// GetDataViaWebCASEndpoint retrieves data from the given webCASEndpoint and returns it.
+// GetDataViaWebCASEndpoint retrieves data from the given webCASEndpoint and returns it. If the response contains
+// a Digest header then the digest is verified against the response body and the resource hash in the endpoint URL.
+// A source which serves mismatched content is greylisted for a period of time. If a circuit breaker is
+// configured then requests to a source which is consistently failing fail fast.
 func (w *WebCASResolver) GetDataViaWebCASEndpoint(webCASEndpoint *url.URL) ([]byte, error) {
-	resp, err := w.httpClient.Get(context.Background(), transport.NewRequest(webCASEndpoint,
+	return w.getDataViaWebCASEndpoint(context.Background(), webCASEndpoint)
+}
+
+func (w *WebCASResolver) getDataViaWebCASEndpoint(ctx context.Context, webCASEndpoint *url.URL) ([]byte, error) {
+	ctx, span := w.tracer.Start(ctx, "fetch from WebCAS",
+		trace.WithAttributes(tracing.ResourceHashAttribute(path.Base(webCASEndpoint.Path))))
+
+	data, err := w.getDataWithCircuitBreaker(ctx, webCASEndpoint)
+
+	tracing.EndSpan(span, err)
+
+	return data, err
+}
+
+func (w *WebCASResolver) getDataWithCircuitBreaker(ctx context.Context, webCASEndpoint *url.URL) ([]byte, error) {
+	if w.greylist.contains(webCASEndpoint.Host) {
+		return nil, fmt.Errorf("WebCAS source [%s] is greylisted due to a previous content mismatch",
+			webCASEndpoint.Host)
+	}
+
+	if w.circuitBreaker == nil {
+		return w.getData(ctx, webCASEndpoint)
+	}
+
+	var data []byte
+
+	err := w.circuitBreaker.Execute(ctx, webCASEndpoint.Host, func() error {
+		var e error
+
+		data, e = w.getData(ctx, webCASEndpoint)
+
+		return e
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (w *WebCASResolver) getData(ctx context.Context, webCASEndpoint *url.URL) ([]byte, error) {
+	resp, err := w.httpClient.Get(ctx, transport.NewRequest(webCASEndpoint,
 		transport.WithHeader(transport.AcceptHeader, transport.LDPlusJSONContentType)))
 	if err != nil {
 		return nil, orberrors.NewTransientf("failed to execute GET call on %s: %w",
@@ -392,5 +851,68 @@ func (w *WebCASResolver) GetDataViaWebCASEndpoint(webCASEndpoint *url.URL) ([]by
 		return nil, err
 	}
 
+	if digestHeader := resp.Header.Get(digest.Header); digestHeader != "" {
+		err = digest.Verify(digestHeader, responseBody, path.Base(webCASEndpoint.Path))
+		if err != nil {
+			if errors.Is(err, digest.ErrMismatch) {
+				logger.Warn("Greylisting WebCAS source due to content mismatch",
+					logfields.WithRequestURL(webCASEndpoint), log.WithError(err))
+
+				w.greylist.add(webCASEndpoint.Host)
+			}
+
+			return nil, fmt.Errorf("verify content from %s: %w", webCASEndpoint.String(), err)
+		}
+	}
+
 	return responseBody, nil
 }
+
+// greylist contains the hosts which are to be ignored until the greylist duration expires.
+type greylist struct {
+	duration time.Duration
+	mutex    sync.RWMutex
+	hosts    map[string]time.Time
+}
+
+func newGreylist(duration time.Duration) *greylist {
+	return &greylist{
+		duration: duration,
+		hosts:    make(map[string]time.Time),
+	}
+}
+
+func (g *greylist) add(host string) {
+	if g == nil {
+		return
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.hosts[host] = time.Now().Add(g.duration)
+}
+
+func (g *greylist) contains(host string) bool {
+	if g == nil {
+		return false
+	}
+
+	g.mutex.RLock()
+	expiry, ok := g.hosts[host]
+	g.mutex.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	if time.Now().Before(expiry) {
+		return true
+	}
+
+	g.mutex.Lock()
+	delete(g.hosts, host)
+	g.mutex.Unlock()
+
+	return false
+}
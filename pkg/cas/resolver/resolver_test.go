@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package resolver
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
@@ -22,6 +24,10 @@ import (
 	ariesstorage "github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/logutil-go/pkg/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	apclientmocks "github.com/trustbloc/orb/pkg/activitypub/client/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
@@ -29,14 +35,18 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/cas/digest"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	"github.com/trustbloc/orb/pkg/cas/ipfs"
 	resolvermocks "github.com/trustbloc/orb/pkg/cas/resolver/mocks"
+	"github.com/trustbloc/orb/pkg/circuitbreaker"
 	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/multihash"
+	"github.com/trustbloc/orb/pkg/observability/tracing"
 	"github.com/trustbloc/orb/pkg/store/cas"
 	"github.com/trustbloc/orb/pkg/webcas"
 	webfingerclient "github.com/trustbloc/orb/pkg/webfinger/client"
@@ -366,6 +376,196 @@ func TestResolver_Resolve(t *testing.T) {
 		require.NotEmpty(t, localHL)
 	})
 
+	t.Run("Had to retrieve data from ipfs via bare V1 CID", func(t *testing.T) {
+		ipfsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, sampleData)
+		}))
+		defer ipfsServer.Close()
+
+		resourceHash, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+		require.NoError(t, err)
+
+		cid, err := multihash.ToV1CID(resourceHash)
+		require.NoError(t, err)
+
+		ipfsClient := ipfs.New(ipfsServer.URL, 5*time.Second, 0, &orbmocks.MetricsProvider{})
+		require.NotNil(t, ipfsClient)
+
+		resolver := createNewResolver(t, createInMemoryCAS(t), ipfsClient)
+
+		data, localHL, err := resolver.Resolve(nil, cid, nil)
+		require.NoError(t, err)
+		require.Equal(t, string(data), sampleData)
+		require.NotEmpty(t, localHL)
+
+		// The data should now be resolvable from the local CAS.
+		data, localHL, err = resolver.Resolve(nil, cid, nil)
+		require.NoError(t, err)
+		require.Equal(t, string(data), sampleData)
+		require.Empty(t, localHL)
+	})
+
+	t.Run("Context cancelled -> data is neither fetched nor stored", func(t *testing.T) {
+		var requested bool
+
+		ipfsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = true
+
+			fmt.Fprint(w, sampleData)
+		}))
+		defer ipfsServer.Close()
+
+		resourceHash, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+		require.NoError(t, err)
+
+		cid, err := multihash.ToV1CID(resourceHash)
+		require.NoError(t, err)
+
+		casClient := createInMemoryCAS(t)
+
+		resolver := createNewResolver(t, casClient,
+			ipfs.New(ipfsServer.URL, 5*time.Second, 0, &orbmocks.MetricsProvider{}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		data, localHL, err := resolver.ResolveContext(ctx, nil, cid, nil)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Contains(t, err.Error(), "resolution cancelled")
+		require.Nil(t, data)
+		require.Empty(t, localHL)
+		require.False(t, requested)
+
+		_, err = casClient.Read(resourceHash)
+		require.ErrorIs(t, err, orberrors.ErrContentNotFound)
+	})
+
+	t.Run("V0 and V1 CIDs resolve to the same content", func(t *testing.T) {
+		var (
+			mutex     sync.Mutex
+			requested []string
+		)
+
+		ipfsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			requested = append(requested, r.URL.Query().Get("arg"))
+			mutex.Unlock()
+
+			fmt.Fprint(w, sampleData)
+		}))
+		defer ipfsServer.Close()
+
+		resourceHash, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+		require.NoError(t, err)
+
+		cidV0, err := multihash.ToV0CID(resourceHash)
+		require.NoError(t, err)
+
+		normalizedCID, err := hashlink.NormalizeCID(cidV0)
+		require.NoError(t, err)
+
+		ipfsClient := ipfs.New(ipfsServer.URL, 5*time.Second, 0, &orbmocks.MetricsProvider{})
+		require.NotNil(t, ipfsClient)
+
+		casClient := createInMemoryCAS(t)
+
+		resolver := createNewResolver(t, casClient, ipfsClient)
+
+		data, localHL, err := resolver.Resolve(nil, cidV0, nil)
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+		require.NotEmpty(t, localHL)
+
+		// The normalized (V1) CID should have been requested from IPFS.
+		require.Equal(t, []string{normalizedCID}, requested)
+
+		// The content is stored locally under the resource hash, so the V1 form of the
+		// CID resolves from the local CAS.
+		data, localHL, err = resolver.Resolve(nil, normalizedCID, nil)
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+		require.Empty(t, localHL)
+
+		data, err = casClient.Read(resourceHash)
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+
+		require.Len(t, requested, 1)
+	})
+
+	t.Run("V0 CID in IPFS hint and link is normalized", func(t *testing.T) {
+		var (
+			mutex     sync.Mutex
+			requested []string
+		)
+
+		ipfsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			requested = append(requested, r.URL.Query().Get("arg"))
+			mutex.Unlock()
+
+			fmt.Fprint(w, sampleData)
+		}))
+		defer ipfsServer.Close()
+
+		resourceHash, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+		require.NoError(t, err)
+
+		cidV0, err := multihash.ToV0CID(resourceHash)
+		require.NoError(t, err)
+
+		normalizedCID, err := hashlink.NormalizeCID(cidV0)
+		require.NoError(t, err)
+
+		// A new IPFS client is used for each request since the client caches content.
+		newIPFSClient := func() *ipfs.Client {
+			return ipfs.New(ipfsServer.URL, 5*time.Second, 0, &orbmocks.MetricsProvider{})
+		}
+
+		data, _, err := createNewResolver(t, createInMemoryCAS(t), newIPFSClient()).Resolve(nil, "ipfs:"+cidV0, nil)
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+
+		hl, err := hashlink.New().CreateHashLink([]byte(sampleData), []string{"ipfs://" + cidV0})
+		require.NoError(t, err)
+
+		data, _, err = createNewResolver(t, createInMemoryCAS(t), newIPFSClient()).Resolve(nil, hl, nil)
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+
+		require.Equal(t, []string{normalizedCID, normalizedCID}, requested)
+	})
+
+	t.Run("error - bare V1 CID with no IPFS client configured", func(t *testing.T) {
+		resourceHash, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+		require.NoError(t, err)
+
+		cid, err := multihash.ToV1CID(resourceHash)
+		require.NoError(t, err)
+
+		resolver := createNewResolver(t, createInMemoryCAS(t), nil)
+
+		data, localHL, err := resolver.Resolve(nil, cid, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot be resolved from IPFS since no IPFS client is configured")
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+		require.Nil(t, data)
+		require.Empty(t, localHL)
+	})
+
+	t.Run("bare V0 CID with no IPFS client configured is treated as a resource hash", func(t *testing.T) {
+		const v0CID = "QmRQB1fQpB4ahvV1fsbjE3fKkT4U9oPjinRofjgS3B9ZEQ"
+
+		resolver := createNewResolver(t, createInMemoryCAS(t), nil)
+
+		data, localHL, err := resolver.Resolve(nil, v0CID, nil)
+		require.Error(t, err)
+		require.EqualError(t, err, "failed to get data stored at "+v0CID+" from the local CAS: content not found")
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+		require.Nil(t, data)
+		require.Empty(t, localHL)
+	})
+
 	t.Run("Retrieve from IPFS using links", func(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
 			data := []byte(sampleData)
@@ -420,6 +620,61 @@ func TestResolver_Resolve(t *testing.T) {
 		})
 	})
 
+	t.Run("IPFS resource hash mismatch", func(t *testing.T) {
+		resourceHash, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+		require.NoError(t, err)
+
+		t.Run("IPFS reader", func(t *testing.T) {
+			ipfsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "wrong content")
+			}))
+			defer ipfsServer.Close()
+
+			casClient := createInMemoryCAS(t)
+
+			resolver := createNewResolver(t, casClient,
+				ipfs.New(ipfsServer.URL, 5*time.Second, 0, &orbmocks.MetricsProvider{}))
+
+			data, localHL, err := resolver.Resolve(nil, "ipfs:"+resourceHash, nil)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "the resource hash of the data retrieved from IPFS "+
+				"(uEiBilpQUYh9_n9NUWLCfHrtGr0k_FCCxsA05_5aJeeYLVw) does not match the resource hash from "+
+				"the original request (uEiCIOcbw1KEQ7neFh6F4GqB-KyhsRhJAGhXpL3kqy4oYVA)")
+			require.Nil(t, data)
+			require.Empty(t, localHL)
+
+			// The wrong content must not have been stored in the local CAS.
+			_, err = casClient.Read("uEiBilpQUYh9_n9NUWLCfHrtGr0k_FCCxsA05_5aJeeYLVw")
+			require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+		})
+
+		t.Run("IPFS local CAS", func(t *testing.T) {
+			casClient := &resolvermocks.CASClient{}
+			casClient.GetPrimaryWriterTypeReturns("ipfs")
+			casClient.ReadReturns([]byte("wrong content"), nil)
+
+			hl, err := hashlink.New().CreateHashLink([]byte(sampleData), []string{"ipfs://xxxx"})
+			require.NoError(t, err)
+
+			resolver := createNewResolver(t, casClient, nil)
+
+			data, localHL, err := resolver.Resolve(nil, hl, nil)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "does not match the resource hash from the original request")
+			require.Nil(t, data)
+			require.Empty(t, localHL)
+
+			t.Run("Verification disabled", func(t *testing.T) {
+				resolver := New(casClient, nil, resolver.webCASResolver, &orbmocks.MetricsProvider{},
+					WithIPFSResourceHashVerification(false))
+
+				data, _, err := resolver.Resolve(nil, hl, nil)
+				require.NoError(t, err)
+				require.Equal(t, "wrong content", string(data))
+			})
+		})
+	})
+
 	t.Run("error - failed to retrieve data from two servers", func(t *testing.T) {
 		casClient := createInMemoryCAS(t)
 
@@ -490,7 +745,7 @@ func TestResolver_Resolve(t *testing.T) {
 		require.EqualError(t, err, "failed to store the data in the local CAS: "+
 			"successfully stored data into the local CAS, but the resource hash produced by the local CAS "+
 			"(uEiCIOcbw1KEQ7neFh6F4GqB-KyhsRhJAGhXpL3kqy4oYVA) does not match the resource hash from the original request "+
-			"(bafkrwihwsnuregfeqh263vgdathcprnbvatyat6h6mu7ipjhhodcdbyhoy)")
+			"(uGyD2k2kSGKSB9e3UwwTOJ8WhqCeAT8fzKfQ9JzuGIYcHdg)")
 		require.Nil(t, data)
 		require.Empty(t, localHL)
 	})
@@ -588,7 +843,7 @@ func TestResolver_Resolve(t *testing.T) {
 
 		data, localHL, err := resolver.Resolve(id, sampleDataCIDv1, nil)
 		require.EqualError(t, err, "failed to get data stored at "+
-			"bafkreibvw52uqclnundfkpu3pi57w57vsshgc3fu5m7eph2jyzgbaxa3ce from the local CAS: "+
+			"uEiA1t3VICW2jRlU-m3o7-3f1lI5hbLTrPkefScZMEFwbEQ from the local CAS: "+
 			"failed to get content from the local CAS provider: get error")
 		require.True(t, orberrors.IsTransient(err))
 		require.Nil(t, data)
@@ -776,6 +1031,568 @@ func TestResolver_Resolve(t *testing.T) {
 	})
 }
 
+func TestResolver_ResolveFrom(t *testing.T) {
+	rh, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+	require.NoError(t, err)
+
+	preferredServer := newMockWebCASServer(t, true)
+	defer preferredServer.Close()
+
+	linkServer := newMockWebCASServer(t, true)
+	defer linkServer.Close()
+
+	emptyServer := newMockWebCASServer(t, false)
+	defer emptyServer.Close()
+
+	md, err := hashlink.New().CreateMetadataFromLinks([]string{fmt.Sprintf("%s/cas/%s", linkServer.URL, rh)})
+	require.NoError(t, err)
+
+	hl := hashlink.GetHashLink(rh, md)
+
+	newResolver := func(casClient extendedcasclient.Client) *Resolver {
+		resolver := createNewResolver(t, casClient, nil)
+		resolver.webCASResolver.webFingerURIScheme = httpScheme
+
+		return resolver
+	}
+
+	t.Run("Preferred domain is queried before links", func(t *testing.T) {
+		preferredServer.reset()
+		linkServer.reset()
+		emptyServer.reset()
+
+		data, localHL, err := newResolver(createInMemoryCAS(t)).ResolveFrom(hl,
+			[]string{emptyServer.domain(), preferredServer.domain()})
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+		require.NotEmpty(t, localHL)
+
+		require.NotZero(t, emptyServer.numRequests())
+		require.NotZero(t, preferredServer.numRequests())
+		require.Zero(t, linkServer.numRequests())
+	})
+
+	t.Run("Found locally -> no remote requests", func(t *testing.T) {
+		preferredServer.reset()
+		linkServer.reset()
+
+		casClient := createInMemoryCAS(t)
+
+		_, err := casClient.Write([]byte(sampleData))
+		require.NoError(t, err)
+
+		data, localHL, err := newResolver(casClient).ResolveFrom(hl, []string{preferredServer.domain()})
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+		require.Empty(t, localHL)
+
+		require.Zero(t, preferredServer.numRequests())
+		require.Zero(t, linkServer.numRequests())
+	})
+
+	t.Run("Preferred domain doesn't have the data -> fall back to links", func(t *testing.T) {
+		emptyServer.reset()
+		linkServer.reset()
+
+		data, localHL, err := newResolver(createInMemoryCAS(t)).ResolveFrom(hl, []string{emptyServer.domain()})
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+		require.NotEmpty(t, localHL)
+
+		require.NotZero(t, emptyServer.numRequests())
+		require.NotZero(t, linkServer.numRequests())
+	})
+
+	t.Run("No preferred domains -> links", func(t *testing.T) {
+		linkServer.reset()
+
+		data, _, err := newResolver(createInMemoryCAS(t)).ResolveFrom(hl, nil)
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+
+		require.NotZero(t, linkServer.numRequests())
+	})
+
+	t.Run("Preferred domain doesn't have the data and no links -> error", func(t *testing.T) {
+		data, _, err := newResolver(createInMemoryCAS(t)).ResolveFrom(rh, []string{emptyServer.domain()})
+		require.Error(t, err)
+		require.Nil(t, data)
+		require.Contains(t, err.Error(), "failed to resolve data for "+rh+" from the preferred domains")
+		require.Contains(t, err.Error(), "resource not found")
+	})
+
+	t.Run("Invalid hint -> error", func(t *testing.T) {
+		_, _, err := newResolver(createInMemoryCAS(t)).ResolveFrom("xyz:"+rh, []string{preferredServer.domain()})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "hint 'xyz' not supported")
+	})
+
+	t.Run("Local CAS error", func(t *testing.T) {
+		errExpected := errors.New("injected read error")
+
+		casClient := &resolvermocks.CASClient{}
+		casClient.ReadReturns(nil, errExpected)
+
+		_, _, err := newResolver(casClient).ResolveFrom(hl, []string{preferredServer.domain()})
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
+func TestResolver_StoreRemoteContent(t *testing.T) {
+	rh, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+	require.NoError(t, err)
+
+	newLocalCAS := func() *resolvermocks.CASClient {
+		casClient := &resolvermocks.CASClient{}
+		casClient.GetPrimaryWriterTypeReturns("local")
+		casClient.ReadReturns(nil, orberrors.ErrContentNotFound)
+
+		return casClient
+	}
+
+	newResolver := func(t *testing.T, casClient extendedcasclient.Client, ipfsReader ipfsReader) *Resolver {
+		t.Helper()
+
+		return New(casClient, ipfsReader, createNewResolver(t, casClient, nil).webCASResolver,
+			&orbmocks.MetricsProvider{}, WithStoreRemoteContent(false))
+	}
+
+	newWebCASServer := func(t *testing.T, content []byte) *httptest.Server {
+		t.Helper()
+
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, e := w.Write(content)
+			require.NoError(t, e)
+		}))
+	}
+
+	t.Run("WebCAS endpoint", func(t *testing.T) {
+		testServer := newWebCASServer(t, []byte(sampleData))
+		defer testServer.Close()
+
+		md, err := hashlink.New().CreateMetadataFromLinks([]string{fmt.Sprintf("%s/cas/%s", testServer.URL, rh)})
+		require.NoError(t, err)
+
+		casClient := newLocalCAS()
+
+		data, localHL, err := newResolver(t, casClient, nil).Resolve(nil, hashlink.GetHashLink(rh, md), nil)
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+		require.Empty(t, localHL)
+		require.Zero(t, casClient.WriteCallCount())
+	})
+
+	t.Run("WebCAS endpoint - resource hash mismatch", func(t *testing.T) {
+		testServer := newWebCASServer(t, []byte("wrong content"))
+		defer testServer.Close()
+
+		md, err := hashlink.New().CreateMetadataFromLinks([]string{fmt.Sprintf("%s/cas/%s", testServer.URL, rh)})
+		require.NoError(t, err)
+
+		casClient := newLocalCAS()
+
+		data, localHL, err := newResolver(t, casClient, nil).Resolve(nil, hashlink.GetHashLink(rh, md), nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the resource hash of the data retrieved from the remote WebCAS endpoint")
+		require.Nil(t, data)
+		require.Empty(t, localHL)
+		require.Zero(t, casClient.WriteCallCount())
+	})
+
+	t.Run("IPFS", func(t *testing.T) {
+		ipfsServer := newWebCASServer(t, []byte(sampleData))
+		defer ipfsServer.Close()
+
+		casClient := newLocalCAS()
+
+		resolver := newResolver(t, casClient, ipfs.New(ipfsServer.URL, 5*time.Second, 0, &orbmocks.MetricsProvider{}))
+
+		data, localHL, err := resolver.Resolve(nil, "ipfs:"+rh, nil)
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+		require.Empty(t, localHL)
+		require.Zero(t, casClient.WriteCallCount())
+	})
+
+	t.Run("Data passed in is still stored", func(t *testing.T) {
+		casClient := createInMemoryCAS(t)
+
+		data, localHL, err := newResolver(t, casClient, nil).Resolve(nil, rh, []byte(sampleData))
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+		require.NotEmpty(t, localHL)
+	})
+}
+
+func TestResolver_Tracing(t *testing.T) {
+	rh, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+	require.NoError(t, err)
+
+	t.Run("Local CAS", func(t *testing.T) {
+		casClient := createInMemoryCAS(t)
+
+		_, err := casClient.Write([]byte(sampleData))
+		require.NoError(t, err)
+
+		resolver, recorder := newTracedResolver(t, casClient, nil)
+
+		_, _, err = resolver.Resolve(nil, rh, nil)
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		require.Equal(t, []string{"read from local CAS", "resolve CAS resource"}, spanNames(spans))
+		requireSpanAttribute(t, spans[1], tracing.AttributeResourceHash, rh)
+		requireSpanAttribute(t, spans[1], tracing.AttributeCASSource, sourceLocal)
+	})
+
+	t.Run("WebFinger discovery and WebCAS fetch", func(t *testing.T) {
+		server := newMockWebCASServer(t, true)
+		defer server.Close()
+
+		resolver, recorder := newTracedResolver(t, createInMemoryCAS(t), nil)
+		resolver.webCASResolver.webFingerURIScheme = httpScheme
+
+		_, _, err := resolver.Resolve(nil, "https:"+server.domain()+":"+rh, nil)
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		require.Equal(t, []string{
+			"read from local CAS", "WebFinger discovery", "fetch from WebCAS", "resolve CAS resource",
+		}, spanNames(spans))
+		require.Equal(t, codes.Error, spans[0].Status().Code)
+		requireSpanAttribute(t, spans[2], tracing.AttributeResourceHash, rh)
+		requireSpanAttribute(t, spans[3], tracing.AttributeResourceHash, rh)
+		requireSpanAttribute(t, spans[3], tracing.AttributeCASSource, sourceWebCAS)
+	})
+
+	t.Run("IPFS fetch", func(t *testing.T) {
+		ipfsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, e := w.Write([]byte(sampleData))
+			require.NoError(t, e)
+		}))
+		defer ipfsServer.Close()
+
+		resolver, recorder := newTracedResolver(t, createInMemoryCAS(t),
+			ipfs.New(ipfsServer.URL, 5*time.Second, 0, &orbmocks.MetricsProvider{}))
+
+		_, _, err := resolver.Resolve(nil, "ipfs:"+rh, nil)
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		require.Equal(t, []string{"read from local CAS", "fetch from IPFS", "resolve CAS resource"}, spanNames(spans))
+		requireSpanAttribute(t, spans[2], tracing.AttributeCASSource, sourceIPFS)
+	})
+
+	t.Run("Spans are children of the span in the context", func(t *testing.T) {
+		casClient := createInMemoryCAS(t)
+
+		_, err := casClient.Write([]byte(sampleData))
+		require.NoError(t, err)
+
+		resolver, recorder := newTracedResolver(t, casClient, nil)
+
+		ctx, parentSpan := resolver.tracer.Start(context.Background(), "parent")
+
+		_, _, err = resolver.ResolveContext(ctx, nil, rh, nil)
+		require.NoError(t, err)
+
+		parentSpan.End()
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 3)
+		require.Equal(t, spans[2].SpanContext().SpanID(), spans[1].Parent().SpanID())
+		require.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	})
+}
+
+func TestWebCASResolver_GetDataViaWebCASEndpoint_Digest(t *testing.T) {
+	resourceHash, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+	require.NoError(t, err)
+
+	newTestServer := func(digestHeader string, content []byte) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if digestHeader != "" {
+				w.Header().Set(digest.Header, digestHeader)
+			}
+
+			_, e := w.Write(content)
+			require.NoError(t, e)
+		}))
+	}
+
+	newWebCASResolver := func(opts ...WebCASResolverOption) WebCASResolver {
+		return NewWebCASResolver(
+			transport.New(&http.Client{},
+				testutil.MustParseURL("https://example.com/keys/public-key"),
+				transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+			webfingerclient.New(), httpScheme, opts...)
+	}
+
+	t.Run("Digest matches", func(t *testing.T) {
+		testServer := newTestServer(digest.Compute([]byte(sampleData)), []byte(sampleData))
+		defer testServer.Close()
+
+		webCASResolver := newWebCASResolver()
+
+		data, err := webCASResolver.GetDataViaWebCASEndpoint(
+			testutil.MustParseURL(testServer.URL + "/cas/" + resourceHash))
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+	})
+
+	t.Run("No digest header", func(t *testing.T) {
+		testServer := newTestServer("", []byte(sampleData))
+		defer testServer.Close()
+
+		webCASResolver := newWebCASResolver()
+
+		data, err := webCASResolver.GetDataViaWebCASEndpoint(
+			testutil.MustParseURL(testServer.URL + "/cas/" + resourceHash))
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+	})
+
+	t.Run("Digest doesn't match content -> source is greylisted", func(t *testing.T) {
+		testServer := newTestServer(digest.Compute([]byte(sampleData)), []byte("tampered"))
+		defer testServer.Close()
+
+		webCASResolver := newWebCASResolver()
+
+		webCASURL := testutil.MustParseURL(testServer.URL + "/cas/" + resourceHash)
+
+		data, err := webCASResolver.GetDataViaWebCASEndpoint(webCASURL)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, digest.ErrMismatch))
+		require.False(t, orberrors.IsTransient(err))
+		require.Nil(t, data)
+
+		data, err = webCASResolver.GetDataViaWebCASEndpoint(webCASURL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is greylisted due to a previous content mismatch")
+		require.Nil(t, data)
+	})
+
+	t.Run("Digest doesn't match resource hash", func(t *testing.T) {
+		content := []byte("other content")
+
+		testServer := newTestServer(digest.Compute(content), content)
+		defer testServer.Close()
+
+		webCASResolver := newWebCASResolver()
+
+		data, err := webCASResolver.GetDataViaWebCASEndpoint(
+			testutil.MustParseURL(testServer.URL + "/cas/" + resourceHash))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, digest.ErrMismatch))
+		require.Contains(t, err.Error(), "does not match resource hash")
+		require.Nil(t, data)
+	})
+
+	t.Run("Greylist expires", func(t *testing.T) {
+		testServer := newTestServer(digest.Compute([]byte("tampered")), []byte(sampleData))
+		defer testServer.Close()
+
+		webCASResolver := newWebCASResolver(WithGreylistDuration(50 * time.Millisecond))
+
+		webCASURL := testutil.MustParseURL(testServer.URL + "/cas/" + resourceHash)
+
+		_, err := webCASResolver.GetDataViaWebCASEndpoint(webCASURL)
+		require.True(t, errors.Is(err, digest.ErrMismatch))
+
+		time.Sleep(100 * time.Millisecond)
+
+		_, err = webCASResolver.GetDataViaWebCASEndpoint(webCASURL)
+		require.True(t, errors.Is(err, digest.ErrMismatch))
+	})
+}
+
+func TestWebCASResolver_GetDataViaWebCASEndpoint_CircuitBreaker(t *testing.T) {
+	resourceHash, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+	require.NoError(t, err)
+
+	var (
+		mutex    sync.Mutex
+		status   = http.StatusServiceUnavailable
+		requests int
+	)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		requests++
+
+		w.WriteHeader(status)
+
+		_, e := w.Write([]byte(sampleData))
+		require.NoError(t, e)
+	}))
+	defer testServer.Close()
+
+	webCASResolver := NewWebCASResolver(
+		transport.New(&http.Client{},
+			testutil.MustParseURL("https://example.com/keys/public-key"),
+			transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+		webfingerclient.New(), httpScheme,
+		WithCircuitBreaker(circuitbreaker.New("webcas",
+			circuitbreaker.WithFailureThreshold(2), circuitbreaker.WithCooldown(50*time.Millisecond),
+		)),
+	)
+
+	webCASURL := testutil.MustParseURL(testServer.URL + "/cas/" + resourceHash)
+
+	for i := 0; i < 2; i++ {
+		_, err := webCASResolver.GetDataViaWebCASEndpoint(webCASURL)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "Response status code: 503")
+	}
+
+	// The circuit is open so the request should fail without reaching the server.
+	_, err = webCASResolver.GetDataViaWebCASEndpoint(webCASURL)
+	require.ErrorIs(t, err, circuitbreaker.ErrOpen)
+	require.True(t, orberrors.IsTransient(err))
+
+	mutex.Lock()
+	require.Equal(t, 2, requests)
+	status = http.StatusOK
+	mutex.Unlock()
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := webCASResolver.GetDataViaWebCASEndpoint(webCASURL)
+	require.NoError(t, err)
+	require.Equal(t, sampleData, string(data))
+}
+
+func TestWebCASResolver_GetDataViaWebCASEndpoint_CircuitBreakerCallerContext(t *testing.T) {
+	resourceHash, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+	require.NoError(t, err)
+
+	var (
+		mutex sync.Mutex
+		slow  = true
+	)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		isSlow := slow
+		mutex.Unlock()
+
+		if isSlow {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+
+			return
+		}
+
+		_, e := w.Write([]byte(sampleData))
+		require.NoError(t, e)
+	}))
+	defer testServer.Close()
+
+	breaker := circuitbreaker.New("webcas", circuitbreaker.WithFailureThreshold(2))
+
+	webCASResolver := NewWebCASResolver(
+		transport.New(&http.Client{},
+			testutil.MustParseURL("https://example.com/keys/public-key"),
+			transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+		webfingerclient.New(), httpScheme,
+		WithCircuitBreaker(breaker),
+	)
+
+	webCASURL := testutil.MustParseURL(testServer.URL + "/cas/" + resourceHash)
+
+	// The caller gives up on each request, which mustn't open the circuit for the (healthy) host.
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+
+		_, err := webCASResolver.getDataViaWebCASEndpoint(ctx, webCASURL)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		cancel()
+	}
+
+	require.Equal(t, circuitbreaker.StateClosed, breaker.State(webCASURL.Host))
+
+	mutex.Lock()
+	slow = false
+	mutex.Unlock()
+
+	data, err := webCASResolver.GetDataViaWebCASEndpoint(webCASURL)
+	require.NoError(t, err)
+	require.Equal(t, sampleData, string(data))
+}
+
+// mockWebCASServer is a remote Orb server which serves WebFinger and WebCAS requests and counts
+// the number of requests.
+type mockWebCASServer struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	requests int
+}
+
+func newMockWebCASServer(t *testing.T, hasData bool) *mockWebCASServer {
+	t.Helper()
+
+	casClient := createInMemoryCAS(t)
+
+	if hasData {
+		_, err := casClient.Write([]byte(sampleData))
+		require.NoError(t, err)
+	}
+
+	webCAS := webcas.New(&resthandler.Config{}, memstore.New(""), &mocks.SignatureVerifier{},
+		casClient, &apmocks.AuthTokenMgr{})
+
+	s := &mockWebCASServer{}
+
+	router := mux.NewRouter()
+
+	router.HandleFunc(webCAS.Path(), webCAS.Handler())
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		s.requests++
+		s.mutex.Unlock()
+
+		router.ServeHTTP(w, r)
+	}))
+
+	operations, err := restapi.New(
+		&restapi.Config{ServiceEndpointURL: testutil.MustParseURL(s.URL), WebCASPath: "/cas"},
+		&restapi.Providers{CAS: casClient, AnchorLinkStore: &orbmocks.AnchorLinkStore{}})
+	require.NoError(t, err)
+
+	router.HandleFunc(operations.GetRESTHandlers()[1].Path(), operations.GetRESTHandlers()[1].Handler())
+
+	return s
+}
+
+func (s *mockWebCASServer) domain() string {
+	return testutil.MustParseURL(s.URL).Host
+}
+
+func (s *mockWebCASServer) numRequests() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.requests
+}
+
+func (s *mockWebCASServer) reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests = 0
+}
+
 func createNewResolver(t *testing.T, casClient extendedcasclient.Client, ipfsReader ipfsReader) *Resolver {
 	t.Helper()
 
@@ -808,3 +1625,43 @@ func createInMemoryCASWithLink(t *testing.T, casLink string) extendedcasclient.C
 
 	return casClient
 }
+
+func newTracedResolver(t *testing.T, casClient extendedcasclient.Client,
+	ipfsReader ipfsReader,
+) (*Resolver, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	resolver := createNewResolver(t, casClient, ipfsReader)
+	resolver.tracer = tracer
+	resolver.webCASResolver.tracer = tracer
+
+	return resolver, recorder
+}
+
+func spanNames(spans []sdktrace.ReadOnlySpan) []string {
+	names := make([]string, len(spans))
+
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+
+	return names
+}
+
+func requireSpanAttribute(t *testing.T, span sdktrace.ReadOnlySpan, key attribute.Key, value string) {
+	t.Helper()
+
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			require.Equal(t, value, attr.Value.AsString())
+
+			return
+		}
+	}
+
+	require.Failf(t, "attribute not found", "span [%s] doesn't have attribute [%s]", span.Name(), key)
+}
@@ -0,0 +1,287 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("circuit-breaker")
+
+const (
+	// DefaultFailureThreshold is the default number of consecutive failures after which the circuit is opened.
+	DefaultFailureThreshold = 5
+	// DefaultCooldown is the default amount of time that a circuit remains open before a probe request is allowed.
+	DefaultCooldown = 30 * time.Second
+)
+
+// ErrOpen is returned (wrapped in a transient error) when a request is rejected because the circuit is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a circuit.
+type State int
+
+const (
+	// StateClosed indicates that requests are allowed.
+	StateClosed State = iota
+	// StateOpen indicates that requests fail fast until the cooldown period has elapsed.
+	StateOpen
+	// StateHalfOpen indicates that a single probe request is allowed in order to determine
+	// whether the circuit may be closed.
+	StateHalfOpen
+)
+
+// String returns the string value of the state.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+type metricsProvider interface {
+	CircuitBreakerStateChanged(name, host, state string)
+}
+
+// Breaker is a circuit breaker which maintains a separate circuit for each host. A circuit is opened after
+// a number of consecutive transient failures, after which requests to the host fail fast until the cooldown
+// period has elapsed. A single probe request is then allowed through (half-open). If the probe succeeds then
+// the circuit is closed, otherwise it is opened again for another cooldown period.
+//
+// Only transient errors (see pkg/errors) are counted as failures since other errors (for example,
+// 'not found') indicate that the host is reachable. Errors that occur after the caller's context is done
+// (cancelled or deadline exceeded) aren't counted either since the caller gave up on the request.
+type Breaker struct {
+	name             string
+	failureThreshold int
+	cooldown         time.Duration
+	metrics          metricsProvider
+	now              func() time.Time
+
+	mutex    sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// Opt is a circuit breaker option.
+type Opt func(b *Breaker)
+
+// WithFailureThreshold sets the number of consecutive failures after which the circuit for a host is opened.
+// A value of zero (or less) disables the circuit breaker.
+func WithFailureThreshold(value int) Opt {
+	return func(b *Breaker) {
+		b.failureThreshold = value
+	}
+}
+
+// WithCooldown sets the amount of time that a circuit remains open before a probe request is allowed.
+func WithCooldown(value time.Duration) Opt {
+	return func(b *Breaker) {
+		b.cooldown = value
+	}
+}
+
+// WithMetrics sets the metrics provider which is notified of circuit state transitions.
+func WithMetrics(value metricsProvider) Opt {
+	return func(b *Breaker) {
+		b.metrics = value
+	}
+}
+
+// New returns a new circuit breaker. The given name identifies the breaker in logs and metrics.
+func New(name string, opts ...Opt) *Breaker {
+	b := &Breaker{
+		name:             name,
+		failureThreshold: DefaultFailureThreshold,
+		cooldown:         DefaultCooldown,
+		metrics:          &noopMetrics{},
+		now:              time.Now,
+		circuits:         make(map[string]*circuit),
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Execute invokes the given function if the circuit for the given host allows it, otherwise a transient
+// error wrapping ErrOpen is returned without invoking the function. The result of the function is used
+// to update the state of the circuit, unless the given context is done after the function returns an error.
+func (b *Breaker) Execute(ctx context.Context, host string, fn func() error) error {
+	if b == nil || b.failureThreshold <= 0 {
+		return fn()
+	}
+
+	if !b.allow(host) {
+		return orberrors.NewTransient(fmt.Errorf("%w for host [%s]", ErrOpen, host))
+	}
+
+	err := fn()
+
+	switch {
+	case err == nil:
+		b.success(host)
+	case ctx.Err() != nil:
+		// The caller gave up on the request, which says nothing about the health of the host.
+		b.abandon(host)
+	case orberrors.IsTransient(err):
+		b.failure(host)
+	default:
+		b.success(host)
+	}
+
+	return err
+}
+
+// State returns the state of the circuit for the given host.
+func (b *Breaker) State(host string) State {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, ok := b.circuits[host]
+	if !ok {
+		return StateClosed
+	}
+
+	return c.state
+}
+
+func (b *Breaker) allow(host string) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, ok := b.circuits[host]
+	if !ok {
+		return true
+	}
+
+	switch c.state {
+	case StateOpen:
+		if b.now().Sub(c.openedAt) < b.cooldown {
+			return false
+		}
+
+		b.transition(host, c, StateHalfOpen)
+
+		c.probing = true
+
+		return true
+	case StateHalfOpen:
+		// Only a single probe request is allowed while the circuit is half-open.
+		if c.probing {
+			return false
+		}
+
+		c.probing = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) success(host string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, ok := b.circuits[host]
+	if !ok {
+		return
+	}
+
+	if c.state != StateClosed {
+		b.transition(host, c, StateClosed)
+	}
+
+	// A closed circuit without failures is equivalent to no circuit, so remove it in order to
+	// keep the map from growing.
+	delete(b.circuits, host)
+}
+
+// abandon leaves the failure count of the circuit unchanged. If the abandoned request was the probe of
+// a half-open circuit then another probe is allowed.
+func (b *Breaker) abandon(host string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, ok := b.circuits[host]
+	if !ok {
+		return
+	}
+
+	if c.state == StateHalfOpen {
+		c.probing = false
+	}
+}
+
+func (b *Breaker) failure(host string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{state: StateClosed}
+
+		b.circuits[host] = c
+	}
+
+	switch c.state {
+	case StateHalfOpen:
+		b.open(host, c)
+	case StateClosed:
+		c.failures++
+
+		if c.failures >= b.failureThreshold {
+			b.open(host, c)
+		}
+	case StateOpen:
+		// A request that was started before the circuit was opened has failed. Nothing to do.
+	}
+}
+
+func (b *Breaker) open(host string, c *circuit) {
+	c.openedAt = b.now()
+	c.probing = false
+
+	b.transition(host, c, StateOpen)
+}
+
+func (b *Breaker) transition(host string, c *circuit, state State) {
+	logger.Info("Circuit state changed", logfields.WithCircuitBreaker(b.name), logfields.WithHost(host),
+		logfields.WithCircuitState(state))
+
+	c.state = state
+
+	b.metrics.CircuitBreakerStateChanged(b.name, host, state.String())
+}
+
+type noopMetrics struct{}
+
+func (m *noopMetrics) CircuitBreakerStateChanged(string, string, string) {}
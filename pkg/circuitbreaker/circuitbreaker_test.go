@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	host1 = "orb.domain1.com"
+	host2 = "orb.domain2.com"
+)
+
+func TestBreaker(t *testing.T) {
+	errTransient := orberrors.NewTransient(errors.New("injected transient error"))
+
+	failFn := func() error { return errTransient }
+	successFn := func() error { return nil }
+
+	t.Run("Opens after threshold and closes after successful probe", func(t *testing.T) {
+		m := &mockMetrics{}
+		now := time.Now()
+
+		b := New("test", WithFailureThreshold(2), WithCooldown(time.Minute), WithMetrics(m))
+		b.now = func() time.Time { return now }
+
+		require.ErrorIs(t, b.Execute(context.Background(), host1, failFn), errTransient)
+		require.Equal(t, StateClosed, b.State(host1))
+
+		require.ErrorIs(t, b.Execute(context.Background(), host1, failFn), errTransient)
+		require.Equal(t, StateOpen, b.State(host1))
+
+		// Other hosts aren't affected.
+		require.NoError(t, b.Execute(context.Background(), host2, successFn))
+		require.Equal(t, StateClosed, b.State(host2))
+
+		invoked := false
+
+		err := b.Execute(context.Background(), host1, func() error {
+			invoked = true
+
+			return nil
+		})
+		require.ErrorIs(t, err, ErrOpen)
+		require.True(t, orberrors.IsTransient(err))
+		require.False(t, invoked)
+
+		now = now.Add(time.Minute)
+
+		require.NoError(t, b.Execute(context.Background(), host1, successFn))
+		require.Equal(t, StateClosed, b.State(host1))
+
+		require.Equal(t, []string{"open", "half-open", "closed"}, m.states(host1))
+		require.Empty(t, m.states(host2))
+	})
+
+	t.Run("Failed probe re-opens the circuit", func(t *testing.T) {
+		m := &mockMetrics{}
+		now := time.Now()
+
+		b := New("test", WithFailureThreshold(1), WithCooldown(time.Minute), WithMetrics(m))
+		b.now = func() time.Time { return now }
+
+		require.ErrorIs(t, b.Execute(context.Background(), host1, failFn), errTransient)
+		require.Equal(t, StateOpen, b.State(host1))
+
+		now = now.Add(time.Minute)
+
+		require.ErrorIs(t, b.Execute(context.Background(), host1, failFn), errTransient)
+		require.Equal(t, StateOpen, b.State(host1))
+
+		// The cooldown starts again.
+		require.ErrorIs(t, b.Execute(context.Background(), host1, successFn), ErrOpen)
+
+		require.Equal(t, []string{"open", "half-open", "open"}, m.states(host1))
+	})
+
+	t.Run("Only a single probe while half-open", func(t *testing.T) {
+		now := time.Now()
+
+		b := New("test", WithFailureThreshold(1), WithCooldown(time.Minute))
+		b.now = func() time.Time { return now }
+
+		require.ErrorIs(t, b.Execute(context.Background(), host1, failFn), errTransient)
+
+		now = now.Add(time.Minute)
+
+		err := b.Execute(context.Background(), host1, func() error {
+			require.Equal(t, StateHalfOpen, b.State(host1))
+			require.ErrorIs(t, b.Execute(context.Background(), host1, successFn), ErrOpen)
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, StateClosed, b.State(host1))
+	})
+
+	t.Run("Non-transient errors and successes reset the failure count", func(t *testing.T) {
+		b := New("test", WithFailureThreshold(2))
+
+		require.ErrorIs(t, b.Execute(context.Background(), host1, failFn), errTransient)
+
+		errNotFound := errors.New("not found")
+
+		require.ErrorIs(t, b.Execute(context.Background(), host1, func() error { return errNotFound }), errNotFound)
+
+		require.ErrorIs(t, b.Execute(context.Background(), host1, failFn), errTransient)
+		require.Equal(t, StateClosed, b.State(host1))
+	})
+
+	t.Run("Failures after the caller's context is done aren't counted", func(t *testing.T) {
+		b := New("test", WithFailureThreshold(1))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := b.Execute(ctx, host1, func() error {
+			return orberrors.NewTransient(ctx.Err())
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, StateClosed, b.State(host1))
+
+		ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		<-ctx.Done()
+
+		err = b.Execute(ctx, host1, func() error {
+			return orberrors.NewTransient(ctx.Err())
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, StateClosed, b.State(host1))
+
+		// A failure with a live context is still counted.
+		require.ErrorIs(t, b.Execute(context.Background(), host1, failFn), errTransient)
+		require.Equal(t, StateOpen, b.State(host1))
+	})
+
+	t.Run("Abandoned probe allows another probe", func(t *testing.T) {
+		now := time.Now()
+
+		b := New("test", WithFailureThreshold(1), WithCooldown(time.Minute))
+		b.now = func() time.Time { return now }
+
+		require.ErrorIs(t, b.Execute(context.Background(), host1, failFn), errTransient)
+
+		now = now.Add(time.Minute)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := b.Execute(ctx, host1, func() error {
+			return orberrors.NewTransient(ctx.Err())
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, StateHalfOpen, b.State(host1))
+
+		require.NoError(t, b.Execute(context.Background(), host1, successFn))
+		require.Equal(t, StateClosed, b.State(host1))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		b := New("test", WithFailureThreshold(0))
+
+		for i := 0; i < 10; i++ {
+			require.ErrorIs(t, b.Execute(context.Background(), host1, failFn), errTransient)
+		}
+
+		require.Equal(t, StateClosed, b.State(host1))
+
+		var nilBreaker *Breaker
+
+		require.ErrorIs(t, nilBreaker.Execute(context.Background(), host1, failFn), errTransient)
+	})
+}
+
+func TestState_String(t *testing.T) {
+	require.Equal(t, "closed", StateClosed.String())
+	require.Equal(t, "open", StateOpen.String())
+	require.Equal(t, "half-open", StateHalfOpen.String())
+	require.Equal(t, "unknown(7)", State(7).String())
+}
+
+type mockMetrics struct {
+	mutex       sync.Mutex
+	transitions map[string][]string
+}
+
+func (m *mockMetrics) CircuitBreakerStateChanged(_, host, state string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.transitions == nil {
+		m.transitions = make(map[string][]string)
+	}
+
+	m.transitions[host] = append(m.transitions[host], state)
+}
+
+func (m *mockMetrics) states(host string) []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.transitions[host]
+}
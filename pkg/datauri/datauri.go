@@ -27,13 +27,41 @@ const (
 	MediaTypeDataURIJSON MediaType = "application/json"
 	// MediaTypeDataURIGzipBase64 indicates that the contents of the data URL is compressed with gzip and base64-encoded.
 	MediaTypeDataURIGzipBase64 MediaType = "application/gzip;base64"
+	// MediaTypeDataURIBase64 indicates that the contents of the data URL is base64-encoded (without compression).
+	MediaTypeDataURIBase64 MediaType = "application/json;base64"
+	// MediaTypeDataURIAuto indicates that the media type should be chosen according to the size of the content.
+	// Content that is smaller than the auto threshold is base64-encoded without compression (since gzip adds
+	// overhead for small content) and larger content is compressed with gzip and base64-encoded. The chosen
+	// media type is recorded in the data URI so, when decoding, the content is decoded using the chosen codec.
+	MediaTypeDataURIAuto MediaType = "auto"
 )
 
+// DefaultAutoThreshold is the default size (in bytes) at or above which content is compressed
+// when the media type is MediaTypeDataURIAuto.
+const DefaultAutoThreshold = 512
+
 const numDataURISegments = 2
 
+type options struct {
+	autoThreshold int
+}
+
+// Opt is a data URI option.
+type Opt func(opts *options)
+
+// WithAutoThreshold sets the size (in bytes) at or above which content is compressed when the
+// media type is MediaTypeDataURIAuto. The default is DefaultAutoThreshold.
+func WithAutoThreshold(value int) Opt {
+	return func(opts *options) {
+		opts.autoThreshold = value
+	}
+}
+
 // New encodes the given content using the given media type and returns
 // a data URI with the encoded data. For example: 'data:application/gzip;base64,H4sIAbAAvAAA...'.
-func New(content []byte, dataType MediaType) (*url.URL, error) {
+func New(content []byte, dataType MediaType, opts ...Opt) (*url.URL, error) {
+	dataType = SelectMediaType(dataType, len(content), opts...)
+
 	encodedData, err := encode(content, dataType)
 	if err != nil {
 		return nil, err
@@ -49,13 +77,34 @@ func New(content []byte, dataType MediaType) (*url.URL, error) {
 
 // MarshalCanonical marshals and encodes the given object using the given media type and returns
 // a data URI with the encoded data. For example: 'data:application/gzip;base64,H4sIAbAAvAAA...'.
-func MarshalCanonical(obj interface{}, dataType MediaType) (*url.URL, error) {
+func MarshalCanonical(obj interface{}, dataType MediaType, opts ...Opt) (*url.URL, error) {
 	content, err := canonicalizer.MarshalCanonical(obj)
 	if err != nil {
 		return nil, fmt.Errorf("marshal canonical: %w", err)
 	}
 
-	return New(content, dataType)
+	return New(content, dataType, opts...)
+}
+
+// SelectMediaType returns the media type that is used to encode content of the given size. If the given media type
+// is MediaTypeDataURIAuto then MediaTypeDataURIBase64 is returned if the size is less than the auto threshold,
+// otherwise MediaTypeDataURIGzipBase64 is returned. Any other media type is returned as is.
+func SelectMediaType(dataType MediaType, size int, opts ...Opt) MediaType {
+	if dataType != MediaTypeDataURIAuto {
+		return dataType
+	}
+
+	options := &options{autoThreshold: DefaultAutoThreshold}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if size < options.autoThreshold {
+		return MediaTypeDataURIBase64
+	}
+
+	return MediaTypeDataURIGzipBase64
 }
 
 // Decode decodes the given data URI and returns the decoded bytes.
@@ -79,6 +128,8 @@ func encode(content []byte, mediaType MediaType) (string, error) {
 	switch mediaType {
 	case MediaTypeDataURIGzipBase64:
 		return GzipCompress(content)
+	case MediaTypeDataURIBase64:
+		return base64.StdEncoding.EncodeToString(content), nil
 	case MediaTypeDataURIJSON:
 		return url.QueryEscape(string(content)), nil
 	case "":
@@ -93,6 +144,13 @@ func decode(content string, mediaType MediaType) ([]byte, error) {
 	switch mediaType {
 	case MediaTypeDataURIGzipBase64:
 		return GzipDecompress(content)
+	case MediaTypeDataURIBase64:
+		decodedBytes, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, fmt.Errorf("base64 decode content: %w", err)
+		}
+
+		return decodedBytes, nil
 	case MediaTypeDataURIJSON:
 		c, err := url.QueryUnescape(content)
 		if err != nil {
@@ -8,6 +8,7 @@ package datauri
 
 import (
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -82,6 +83,25 @@ func TestDataURI(t *testing.T) {
 		require.Equal(t, content, string(contentBytes))
 	})
 
+	t.Run("base64 -> success", func(t *testing.T) {
+		u, err := New([]byte(content), MediaTypeDataURIBase64)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(u.String(), "data:application/json;base64,"))
+
+		contentBytes, err := Decode(u)
+		require.NoError(t, err)
+		require.Equal(t, content, string(contentBytes))
+	})
+
+	t.Run("base64 decode -> error", func(t *testing.T) {
+		u, err := url.Parse("data:application/json;base64,sfsdf")
+		require.NoError(t, err)
+
+		_, err = Decode(u)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "illegal base64 data")
+	})
+
 	t.Run("gzip decompress -> error", func(t *testing.T) {
 		_, err := GzipDecompress("sfsdf")
 		require.Error(t, err)
@@ -89,6 +109,47 @@ func TestDataURI(t *testing.T) {
 	})
 }
 
+func TestAuto(t *testing.T) {
+	smallContent := []byte(`{"field1":"value1"}`)
+	largeContent := []byte(`{"field1":"` + strings.Repeat("value1", 200) + `"}`)
+
+	t.Run("Default threshold", func(t *testing.T) {
+		require.Equal(t, MediaTypeDataURIBase64, SelectMediaType(MediaTypeDataURIAuto, len(smallContent)))
+		require.Equal(t, MediaTypeDataURIGzipBase64, SelectMediaType(MediaTypeDataURIAuto, len(largeContent)))
+	})
+
+	t.Run("Below threshold -> base64", func(t *testing.T) {
+		u, err := New(smallContent, MediaTypeDataURIAuto, WithAutoThreshold(len(smallContent)+1))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(u.String(), "data:"+MediaTypeDataURIBase64+","))
+
+		contentBytes, err := Decode(u)
+		require.NoError(t, err)
+		require.Equal(t, smallContent, contentBytes)
+	})
+
+	t.Run("Above threshold -> gzip", func(t *testing.T) {
+		u, err := New(largeContent, MediaTypeDataURIAuto, WithAutoThreshold(len(smallContent)+1))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(u.String(), "data:"+MediaTypeDataURIGzipBase64+","))
+
+		contentBytes, err := Decode(u)
+		require.NoError(t, err)
+		require.Equal(t, largeContent, contentBytes)
+	})
+
+	t.Run("Non-auto media type is not changed", func(t *testing.T) {
+		require.Equal(t, MediaTypeDataURIJSON, SelectMediaType(MediaTypeDataURIJSON, len(largeContent)))
+		require.Equal(t, MediaTypeDataURIGzipBase64, SelectMediaType(MediaTypeDataURIGzipBase64, len(smallContent)))
+	})
+
+	t.Run("Marshal canonical", func(t *testing.T) {
+		u, err := MarshalCanonical(map[string]string{"field1": "value1"}, MediaTypeDataURIAuto, WithAutoThreshold(0))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(u.String(), "data:"+MediaTypeDataURIGzipBase64+","))
+	})
+}
+
 func TestMarshalCanonical(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		data := struct {
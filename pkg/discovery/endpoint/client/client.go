@@ -39,8 +39,10 @@ import (
 var logger = log.New("endpoint-client")
 
 const (
-	minResolvers         = "https://trustbloc.dev/ns/min-resolvers"
-	anchorOriginProperty = "https://trustbloc.dev/ns/anchor-origin"
+	minResolvers          = "https://trustbloc.dev/ns/min-resolvers"
+	anchorOriginProperty  = "https://trustbloc.dev/ns/anchor-origin"
+	maxOperationsPerBatch = "https://trustbloc.dev/ns/max-operations-per-batch"
+	batchWriterTimeout    = "https://trustbloc.dev/ns/batch-writer-timeout"
 
 	serviceTypeLinkedDomains = "LinkedDomains"
 
@@ -243,6 +245,8 @@ func (cs *Client) getEndpoint(uri string) (*models.Endpoint, error) { //nolint:c
 		endpoint.OperationEndpoints = append(endpoint.OperationEndpoints, v.Href)
 	}
 
+	populateBatchConfig(endpoint, &jrd)
+
 	logger.Debug("... resolved endpoint from URI", logfields.WithURIString(uri), logfields.WithAnchorOriginEndpoint(endpoint))
 
 	return endpoint, nil
@@ -409,6 +413,18 @@ func (cs *Client) populateResolutionEndpoint(webFingerURL string) (*models.Endpo
 	return endpoint, nil
 }
 
+// populateBatchConfig sets the batch configuration that's (optionally) advertised by the server
+// in the WebFinger response for the operation endpoint.
+func populateBatchConfig(endpoint *models.Endpoint, jrd *restapi.JRD) {
+	if maxOps, ok := jrd.Properties[maxOperationsPerBatch].(float64); ok {
+		endpoint.MaxOperationsPerBatch = int(maxOps)
+	}
+
+	if timeoutMillis, ok := jrd.Properties[batchWriterTimeout].(float64); ok {
+		endpoint.BatchWriterTimeout = time.Duration(timeoutMillis) * time.Millisecond
+	}
+}
+
 func (cs *Client) getEndpointAnchorOrigin(didURI string) (*models.Endpoint, error) {
 	cid, suffix, err := cs.getCIDAndSuffix(didURI)
 	if err != nil {
@@ -328,6 +328,10 @@ func TestConfigService_GetEndpoint(t *testing.T) { //nolint: gocyclo,gocognit,cy
 					if strings.Contains(req.URL.Path, ".well-known/webfinger") &&
 						strings.Contains(req.URL.RawQuery, "op") {
 						b, err := json.Marshal(restapi.JRD{
+							Properties: map[string]interface{}{
+								maxOperationsPerBatch: float64(100),
+								batchWriterTimeout:    float64(2000),
+							},
 							Links: []restapi.Link{{Href: "https://localhost/op1"}, {Href: "https://localhost/op2"}},
 						})
 						require.NoError(t, err)
@@ -380,6 +384,8 @@ func TestConfigService_GetEndpoint(t *testing.T) { //nolint: gocyclo,gocognit,cy
 		require.Equal(t, endpoint.ResolutionEndpoints, []string{"https://localhost/resolve1", "https://localhost/resolve2"})
 		require.Equal(t, endpoint.OperationEndpoints, []string{"https://localhost/op1", "https://localhost/op2"})
 		require.Equal(t, endpoint.MinResolvers, 2)
+		require.Equal(t, 100, endpoint.MaxOperationsPerBatch)
+		require.Equal(t, 2*time.Second, endpoint.BatchWriterTimeout)
 
 		endpoint, err = cs.GetEndpoint("did:web:example.com:services:orb")
 		require.NoError(t, err)
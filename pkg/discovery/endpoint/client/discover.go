@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+)
+
+const (
+	wellKnownDIDOrbPath = "/.well-known/did-orb"
+	webFingerPath       = "/.well-known/webfinger"
+)
+
+// Endpoints contains the resolution and operation endpoints of an Orb domain.
+type Endpoints struct {
+	ResolutionEndpoint string
+	OperationEndpoint  string
+}
+
+// DiscoverEndpoints discovers the resolution and operation endpoints of the Orb domain at the given base URL
+// (for example, https://orb.domain1.com). The endpoints are first retrieved from /.well-known/did-orb and then
+// each endpoint is resolved using WebFinger.
+func DiscoverEndpoints(baseURL string, httpClient httpClient) (*Endpoints, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	var wellKnown restapi.WellKnownResponse
+
+	err := getJSON(httpClient, baseURL+wellKnownDIDOrbPath, &wellKnown)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", wellKnownDIDOrbPath, err)
+	}
+
+	if wellKnown.ResolutionEndpoint == "" || wellKnown.OperationEndpoint == "" {
+		return nil, fmt.Errorf("missing resolution or operation endpoint in %s response from %s",
+			wellKnownDIDOrbPath, baseURL)
+	}
+
+	resolutionEndpoint, err := resolveWebFingerLink(httpClient, baseURL, wellKnown.ResolutionEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("resolve resolution endpoint: %w", err)
+	}
+
+	operationEndpoint, err := resolveWebFingerLink(httpClient, baseURL, wellKnown.OperationEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("resolve operation endpoint: %w", err)
+	}
+
+	return &Endpoints{
+		ResolutionEndpoint: resolutionEndpoint,
+		OperationEndpoint:  operationEndpoint,
+	}, nil
+}
+
+func resolveWebFingerLink(httpClient httpClient, baseURL, resource string) (string, error) {
+	var jrd restapi.JRD
+
+	err := getJSON(httpClient,
+		fmt.Sprintf("%s%s?resource=%s", baseURL, webFingerPath, url.QueryEscape(resource)), &jrd)
+	if err != nil {
+		return "", err
+	}
+
+	if len(jrd.Links) == 0 || jrd.Links[0].Href == "" {
+		return "", fmt.Errorf("no links in WebFinger response for resource [%s]", resource)
+	}
+
+	return jrd.Links[0].Href, nil
+}
+
+func getJSON(httpClient httpClient, endpointURL string, respObj interface{}) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, endpointURL, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create http request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	responseBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response : %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got unexpected response from %s status '%d' body %s",
+			endpointURL, resp.StatusCode, responseBytes)
+	}
+
+	err = json.Unmarshal(responseBytes, respObj)
+	if err != nil {
+		return fmt.Errorf("invalid response from %s: %w", endpointURL, err)
+	}
+
+	return nil
+}
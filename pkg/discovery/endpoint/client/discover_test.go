@@ -0,0 +1,132 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+)
+
+func TestDiscoverEndpoints(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		serv := newStubOrbServer(t, nil, nil)
+		defer serv.Close()
+
+		endpoints, err := DiscoverEndpoints(serv.URL+"/", http.DefaultClient)
+		require.NoError(t, err)
+		require.NotNil(t, endpoints)
+		require.Equal(t, serv.URL+"/sidetree/v1/identifiers", endpoints.ResolutionEndpoint)
+		require.Equal(t, serv.URL+"/sidetree/v1/operations", endpoints.OperationEndpoint)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		serv := newStubOrbServer(t, nil, nil)
+		serv.Close()
+
+		_, err := DiscoverEndpoints(serv.URL, http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get /.well-known/did-orb: failed to send request")
+	})
+
+	t.Run("well-known error status", func(t *testing.T) {
+		serv := httptest.NewServer(http.NotFoundHandler())
+		defer serv.Close()
+
+		_, err := DiscoverEndpoints(serv.URL, http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "status '404'")
+	})
+
+	t.Run("invalid well-known response", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte("{"))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		_, err := DiscoverEndpoints(serv.URL, http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid response from")
+	})
+
+	t.Run("missing endpoint in well-known response", func(t *testing.T) {
+		serv := newStubOrbServer(t, &restapi.WellKnownResponse{ResolutionEndpoint: "https://orb.domain1.com"}, nil)
+		defer serv.Close()
+
+		_, err := DiscoverEndpoints(serv.URL, http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing resolution or operation endpoint")
+	})
+
+	t.Run("no WebFinger links", func(t *testing.T) {
+		serv := newStubOrbServer(t, nil, &restapi.JRD{})
+		defer serv.Close()
+
+		_, err := DiscoverEndpoints(serv.URL, http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve resolution endpoint: no links in WebFinger response")
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		_, err := DiscoverEndpoints(":invalid", http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to create http request")
+	})
+}
+
+// newStubOrbServer returns a stub server which serves /.well-known/did-orb and WebFinger. If wellKnown or jrd
+// are provided then they are returned instead of the default responses.
+func newStubOrbServer(t *testing.T, wellKnown *restapi.WellKnownResponse, jrd *restapi.JRD) *httptest.Server {
+	t.Helper()
+
+	var serv *httptest.Server
+
+	serv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp interface{}
+
+		switch r.URL.Path {
+		case wellKnownDIDOrbPath:
+			if wellKnown != nil {
+				resp = wellKnown
+			} else {
+				resp = &restapi.WellKnownResponse{
+					ResolutionEndpoint: serv.URL + "/sidetree/v1/identifiers",
+					OperationEndpoint:  serv.URL + "/sidetree/v1/operations",
+				}
+			}
+		case webFingerPath:
+			if jrd != nil {
+				resp = jrd
+			} else {
+				resource := r.URL.Query().Get("resource")
+
+				resp = &restapi.JRD{
+					Subject: resource,
+					Links:   []restapi.Link{{Rel: self, Href: resource}},
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		respBytes, err := json.Marshal(resp)
+		require.NoError(t, err)
+
+		_, err = w.Write(respBytes)
+		require.NoError(t, err)
+	}))
+
+	return serv
+}
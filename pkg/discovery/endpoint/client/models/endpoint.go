@@ -15,9 +15,32 @@ type Endpoint struct {
 	MaxAge              uint `json:"-"`
 	AnchorOrigin        string
 	AnchorURI           string
+
+	// MaxOperationsPerBatch is the maximum number of operations in a batch as advertised by the server.
+	// It's zero if the server doesn't advertise its batch configuration.
+	MaxOperationsPerBatch int
+	// BatchWriterTimeout is the maximum time in-between the server cutting batches as advertised by the server.
+	// It's zero if the server doesn't advertise its batch configuration.
+	BatchWriterTimeout time.Duration
 }
 
 // CacheLifetime returns the cache lifetime of the endpoint config file before it needs to be checked for an update.
 func (c *Endpoint) CacheLifetime() (time.Duration, error) {
 	return time.Duration(c.MaxAge) * time.Second, nil
 }
+
+// SubmissionInterval returns the interval at which a client should submit operations so that (at most)
+// maxOperationsPerBatch of its operations are included in each batch cut by the server. The given value is
+// capped by the server's advertised maximum. Zero is returned (i.e. submit at full rate) if the server
+// doesn't advertise its batch configuration or if maxOperationsPerBatch isn't greater than zero.
+func (c *Endpoint) SubmissionInterval(maxOperationsPerBatch int) time.Duration {
+	if c.BatchWriterTimeout <= 0 || maxOperationsPerBatch <= 0 {
+		return 0
+	}
+
+	if c.MaxOperationsPerBatch > 0 && maxOperationsPerBatch > c.MaxOperationsPerBatch {
+		maxOperationsPerBatch = c.MaxOperationsPerBatch
+	}
+
+	return c.BatchWriterTimeout / time.Duration(maxOperationsPerBatch)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoint_SubmissionInterval(t *testing.T) {
+	t.Run("Batch config not advertised -> full rate", func(t *testing.T) {
+		e := &Endpoint{}
+
+		require.Zero(t, e.SubmissionInterval(10))
+	})
+
+	t.Run("Max operations not specified -> full rate", func(t *testing.T) {
+		e := &Endpoint{MaxOperationsPerBatch: 100, BatchWriterTimeout: 2 * time.Second}
+
+		require.Zero(t, e.SubmissionInterval(0))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		e := &Endpoint{MaxOperationsPerBatch: 100, BatchWriterTimeout: 2 * time.Second}
+
+		require.Equal(t, 200*time.Millisecond, e.SubmissionInterval(10))
+	})
+
+	t.Run("Capped by server maximum", func(t *testing.T) {
+		e := &Endpoint{MaxOperationsPerBatch: 100, BatchWriterTimeout: 2 * time.Second}
+
+		require.Equal(t, 20*time.Millisecond, e.SubmissionInterval(1000))
+	})
+
+	t.Run("Server maximum not advertised", func(t *testing.T) {
+		e := &Endpoint{BatchWriterTimeout: 2 * time.Second}
+
+		require.Equal(t, 2*time.Millisecond, e.SubmissionInterval(1000))
+	})
+}
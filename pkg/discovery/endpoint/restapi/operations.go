@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	ariesmodel "github.com/hyperledger/aries-framework-go/pkg/common/model"
@@ -65,6 +66,8 @@ const (
 
 const (
 	minResolvers             = "https://trustbloc.dev/ns/min-resolvers"
+	maxOperationsPerBatch    = "https://trustbloc.dev/ns/max-operations-per-batch"
+	batchWriterTimeout       = "https://trustbloc.dev/ns/batch-writer-timeout"
 	contextDID               = "https://w3id.org/did/v1"
 	contextDIDConfig         = "https://identity.foundation/.well-known/did-configuration/v1"
 	serviceTypeLinkedDomains = "LinkedDomains"
@@ -118,6 +121,8 @@ func New(c *Config, p *Providers) (*Operation, error) {
 		webCASPath:                c.WebCASPath,
 		baseURL:                   fmt.Sprintf("%s://%s", c.ServiceEndpointURL.Scheme, c.ServiceEndpointURL.Host),
 		discoveryMinimumResolvers: c.DiscoveryMinimumResolvers,
+		maxOperationsPerBatch:     c.MaxOperationsPerBatch,
+		batchWriterTimeout:        c.BatchWriterTimeout,
 		discoveryDomains:          c.DiscoveryDomains,
 		serviceEndpointURL:        c.ServiceEndpointURL,
 		serviceID:                 serviceID,
@@ -151,6 +156,8 @@ type Operation struct {
 	baseURL                   string
 	discoveryDomains          []string
 	discoveryMinimumResolvers int
+	maxOperationsPerBatch     int
+	batchWriterTimeout        time.Duration
 	cas                       cas
 	anchorStore               anchorLinkStore
 	wfClient                  webfingerClient
@@ -171,6 +178,12 @@ type Config struct {
 	DiscoveryMinimumResolvers int
 	ServiceID                 *url.URL
 	ServiceEndpointURL        *url.URL
+
+	// MaxOperationsPerBatch and BatchWriterTimeout are advertised to clients (in the WebFinger response for the
+	// operation endpoint) so that clients may pace their submissions according to the batch configuration.
+	// These properties are omitted if they're not set.
+	MaxOperationsPerBatch int
+	BatchWriterTimeout    time.Duration
 }
 
 // Providers defines the providers for discovery operations.
@@ -407,7 +420,8 @@ func (o *Operation) writeResponseForResourceRequest(rw http.ResponseWriter, reso
 		writeResponse(rw, resp)
 	case resource == fmt.Sprintf("%s%s", o.baseURL, o.operationPath):
 		resp := &JRD{
-			Subject: resource,
+			Subject:    resource,
+			Properties: o.batchProperties(),
 			Links: []Link{
 				{Rel: selfRelation, Href: resource},
 			},
@@ -431,6 +445,26 @@ func (o *Operation) writeResponseForResourceRequest(rw http.ResponseWriter, reso
 	}
 }
 
+// batchProperties returns the batch configuration properties that are advertised for the operation endpoint.
+// The batch writer timeout is expressed in milliseconds.
+func (o *Operation) batchProperties() map[string]interface{} {
+	if o.maxOperationsPerBatch <= 0 && o.batchWriterTimeout <= 0 {
+		return nil
+	}
+
+	properties := make(map[string]interface{})
+
+	if o.maxOperationsPerBatch > 0 {
+		properties[maxOperationsPerBatch] = o.maxOperationsPerBatch
+	}
+
+	if o.batchWriterTimeout > 0 {
+		properties[batchWriterTimeout] = o.batchWriterTimeout.Milliseconds()
+	}
+
+	return properties
+}
+
 func (o *Operation) handleDIDOrbQuery(rw http.ResponseWriter, resource string) {
 	anchorInfo, err := o.GetAnchorInfo(resource)
 	if err != nil {
@@ -565,7 +599,7 @@ func (o *Operation) handleWebCASQuery(rw http.ResponseWriter, resource string) {
 
 	// Add the references from the configured discovery domains.
 	for _, v := range o.discoveryDomains {
-		refs = append(refs, fmt.Sprintf("%s/cas/%s", v, cid))
+		refs = append(refs, fmt.Sprintf("%s%s/%s", v, o.webCASPath, cid))
 	}
 
 	// Add references from the anchor link storage.
@@ -17,6 +17,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	ariesdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
@@ -238,6 +239,33 @@ func TestWebFinger(t *testing.T) {
 		require.Empty(t, w.Properties)
 	})
 
+	t.Run("test operation resource with batch config", func(t *testing.T) {
+		c, err := restapi.New(&restapi.Config{
+			OperationPath:             "/op",
+			ResolutionPath:            "/resolve",
+			WebCASPath:                "/cas",
+			ServiceEndpointURL:        testutil.MustParseURL("http://base/services/orb"),
+			DiscoveryMinimumResolvers: 2,
+			MaxOperationsPerBatch:     100,
+			BatchWriterTimeout:        2 * time.Second,
+		}, &restapi.Providers{})
+		require.NoError(t, err)
+
+		handler := getHandler(t, c, restapi.WebFingerEndpoint)
+
+		rr := serveHTTP(t, handler.Handler(), http.MethodGet, restapi.WebFingerEndpoint+"?resource=http://base/op",
+			nil, nil, false)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var w restapi.JRD
+
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &w))
+		require.Len(t, w.Links, 1)
+		require.Equal(t, float64(100), w.Properties["https://trustbloc.dev/ns/max-operations-per-batch"])
+		require.Equal(t, float64(2000), w.Properties["https://trustbloc.dev/ns/batch-writer-timeout"])
+	})
+
 	t.Run("test vct resource", func(t *testing.T) {
 		const webfingerPayload = `{"properties":{"https://trustbloc.dev/ns/ledger-type":"vct-v1"}}`
 
@@ -476,6 +504,44 @@ func TestWebFinger(t *testing.T) {
 		})
 	})
 
+	t.Run("test WebCAS resource with path prefix", func(t *testing.T) {
+		casClient := &mocks.CASClient{}
+		casClient.ReadReturns(nil, nil)
+
+		c, err := restapi.New(&restapi.Config{
+			OperationPath:             "/op",
+			ResolutionPath:            "/resolve",
+			WebCASPath:                "/orb/cas",
+			ServiceEndpointURL:        testutil.MustParseURL("http://base/services/orb"),
+			DiscoveryDomains:          []string{"http://domain1"},
+			DiscoveryMinimumResolvers: 2,
+		}, &restapi.Providers{
+			CAS:             casClient,
+			AnchorLinkStore: &orbmocks.AnchorLinkStore{},
+		})
+		require.NoError(t, err)
+
+		handler := getHandler(t, c, restapi.WebFingerEndpoint)
+
+		rr := serveHTTP(t, handler.Handler(), http.MethodGet, restapi.WebFingerEndpoint+
+			"?resource=http://base/orb/cas/uEiATVQNQqGgchMhhqsLltEAWHCszo-TzAqxoDKW2ht5I3g", nil, nil, false)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var w restapi.JRD
+
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &w))
+		require.Len(t, w.Links, 2)
+		require.Equal(t, "http://base/orb/cas/uEiATVQNQqGgchMhhqsLltEAWHCszo-TzAqxoDKW2ht5I3g", w.Links[0].Href)
+		require.Equal(t, "http://domain1/orb/cas/uEiATVQNQqGgchMhhqsLltEAWHCszo-TzAqxoDKW2ht5I3g", w.Links[1].Href)
+
+		// The default path isn't matched as a WebCAS resource.
+		rr = serveHTTP(t, handler.Handler(), http.MethodGet, restapi.WebFingerEndpoint+
+			"?resource=http://base/cas/uEiATVQNQqGgchMhhqsLltEAWHCszo-TzAqxoDKW2ht5I3g", nil, nil, false)
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
 	t.Run("test did:orb resource", func(t *testing.T) {
 		const anchorURI = "hl:uEiALYp_C4wk2WegpfnCSoSTBdKZ1MVdDadn4rdmZl5GKzQ:uoQ-BeDVpcGZzOi8vUW1jcTZKV0RVa3l4ZWhxN1JWWmtQM052aUU0SHFSdW5SalgzOXZ1THZFSGFRTg"
 
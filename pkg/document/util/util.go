@@ -10,16 +10,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"path"
 	"strings"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/trustbloc/sidetree-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-go/pkg/document"
 	"github.com/trustbloc/sidetree-go/pkg/docutil"
+
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/multihash"
 )
 
-// MinOrbIdentifierParts is minimum number of parts in Orb identifier.
-const MinOrbIdentifierParts = 4
+const (
+	// MinOrbIdentifierParts is minimum number of parts in Orb identifier.
+	MinOrbIdentifierParts = 4
+
+	// UnpublishedDIDLabel is the label used in place of the CID in an interim (unpublished) DID.
+	UnpublishedDIDLabel = "uAAA"
+
+	// maxHintParts is the maximum number of parts in a DID hint, e.g. https:orb.domain1.com.
+	maxHintParts = 2
+
+	cidAndSuffixParts = 2
+
+	hashLinkHint = "hl"
+)
 
 // GetSuffix returns suffix from id.
 func GetSuffix(id string) (string, error) {
@@ -35,6 +51,173 @@ func GetSuffix(id string) (string, error) {
 	return suffix, nil
 }
 
+// ParseCanonicalDID parses the given canonical DID (e.g. did:orb:uEiA...:EiD...) and returns the namespace
+// (e.g. did:orb), the CID and the suffix. An error is returned if the DID is malformed or if it's an interim
+// DID (i.e. it doesn't contain a CID).
+func ParseCanonicalDID(id string) (namespace, cid, suffix string, err error) {
+	if !IsDID(id) {
+		return "", "", "", fmt.Errorf("invalid canonical DID [%s]: missing did: prefix", id)
+	}
+
+	parts := strings.Split(id, docutil.NamespaceDelimiter)
+
+	if len(parts) != MinOrbIdentifierParts {
+		return "", "", "", fmt.Errorf("invalid canonical DID [%s]: expecting %d parts but got %d",
+			id, MinOrbIdentifierParts, len(parts))
+	}
+
+	if parts[1] == "" {
+		return "", "", "", fmt.Errorf("invalid canonical DID [%s]: empty method", id)
+	}
+
+	cid = parts[2]
+
+	if cid == "" {
+		return "", "", "", fmt.Errorf("invalid canonical DID [%s]: empty CID", id)
+	}
+
+	if cid == UnpublishedDIDLabel {
+		return "", "", "", fmt.Errorf("invalid canonical DID [%s]: interim DID does not contain a CID", id)
+	}
+
+	suffix = parts[3]
+
+	if suffix == "" {
+		return "", "", "", fmt.Errorf("invalid canonical DID [%s]: empty suffix", id)
+	}
+
+	namespace = parts[0] + docutil.NamespaceDelimiter + parts[1]
+
+	return namespace, cid, suffix, nil
+}
+
+// ValidateOrbDID validates the structure of the given DID, which must be of the form
+// <namespace>[:<hint>]:<CID or uAAA>:<suffix>, where the optional hint is either a single part
+// (e.g. ipfs) or a scheme and domain (e.g. https:orb.domain1.com). A DID may also contain a hashlink
+// (hl:<resource hash>:<metadata>) in place of the hint and CID, in which case the links in the metadata
+// must reference the resource hash. An error describing the problem is returned if the DID is invalid.
+func ValidateOrbDID(id, namespace string) error {
+	if namespace == "" {
+		return fmt.Errorf("invalid DID [%s]: namespace is required", id)
+	}
+
+	prefix := namespace + docutil.NamespaceDelimiter
+
+	if !strings.HasPrefix(id, prefix) {
+		return fmt.Errorf("invalid DID [%s]: expecting namespace [%s]", id, namespace)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(id, prefix), docutil.NamespaceDelimiter)
+
+	if len(parts) < cidAndSuffixParts {
+		return fmt.Errorf("invalid DID [%s]: expecting CID and suffix after namespace [%s]", id, namespace)
+	}
+
+	hintParts := parts[:len(parts)-cidAndSuffixParts]
+
+	if len(hintParts) > maxHintParts {
+		return fmt.Errorf("invalid DID [%s]: too many parts in hint [%s]", id,
+			strings.Join(hintParts, docutil.NamespaceDelimiter))
+	}
+
+	for _, p := range hintParts {
+		if p == "" {
+			return fmt.Errorf("invalid DID [%s]: empty part in hint", id)
+		}
+	}
+
+	cid := parts[len(parts)-cidAndSuffixParts]
+
+	if cid == "" {
+		return fmt.Errorf("invalid DID [%s]: empty CID", id)
+	}
+
+	if !isEncodedValue(cid) {
+		return fmt.Errorf("invalid DID [%s]: CID [%s] contains invalid characters", id, cid)
+	}
+
+	suffix := parts[len(parts)-1]
+
+	if suffix == "" {
+		return fmt.Errorf("invalid DID [%s]: empty suffix", id)
+	}
+
+	if !isEncodedValue(suffix) {
+		return fmt.Errorf("invalid DID [%s]: suffix [%s] contains invalid characters", id, suffix)
+	}
+
+	if len(hintParts) > 0 && hintParts[0] == hashLinkHint {
+		if err := validateHashLink(hintParts, cid); err != nil {
+			return fmt.Errorf("invalid DID [%s]: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// validateHashLink validates the hashlink in a DID with an hl hint. The hashlink is either hl:<resource hash>
+// (in which case the CID part of the DID is the resource hash) or hl:<resource hash>:<metadata> (in which case
+// the CID part of the DID is the metadata). The WebCAS and IPFS links in the metadata must reference the
+// resource hash.
+func validateHashLink(hintParts []string, cidOrMetadata string) error {
+	if len(hintParts) == 1 {
+		_, err := hashlink.New().ParseHashLink(hashlink.GetHashLinkFromResourceHash(cidOrMetadata))
+
+		return err
+	}
+
+	hlInfo, err := hashlink.New().ParseHashLink(hashlink.GetHashLink(hintParts[1], cidOrMetadata))
+	if err != nil {
+		return err
+	}
+
+	for _, link := range hlInfo.Links {
+		resourceHash, err := getResourceHashFromLink(link)
+		if err != nil {
+			return fmt.Errorf("hashlink link [%s]: %w", link, err)
+		}
+
+		if resourceHash != "" && resourceHash != hlInfo.ResourceHash {
+			return fmt.Errorf("hashlink link [%s] does not reference resource hash [%s]",
+				link, hlInfo.ResourceHash)
+		}
+	}
+
+	return nil
+}
+
+// getResourceHashFromLink returns the resource hash referenced by the given WebCAS or IPFS link, or an
+// empty string if the link is of some other type.
+func getResourceHashFromLink(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("parse link: %w", err)
+	}
+
+	switch u.Scheme {
+	case "https", "http":
+		return path.Base(u.Path), nil
+	case "ipfs":
+		return multihash.CIDToMultihash(u.Host)
+	default:
+		return "", nil
+	}
+}
+
+// isEncodedValue returns true if the given value contains only characters from the base64url
+// alphabet (which is a superset of the base32 and base58 alphabets used for CIDs).
+func isEncodedValue(value string) bool {
+	for _, c := range value {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
 // GetHint returns hint from id.
 func GetHint(id, namespace, suffix string) (string, error) {
 	posSuffix := strings.LastIndex(id, suffix)
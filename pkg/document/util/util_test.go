@@ -13,6 +13,8 @@ import (
 	"github.com/trustbloc/sidetree-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-go/pkg/document"
 	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/doctransformer/metadata"
+
+	"github.com/trustbloc/orb/pkg/hashlink"
 )
 
 func TestGetSuffix(t *testing.T) {
@@ -30,6 +32,141 @@ func TestGetSuffix(t *testing.T) {
 	})
 }
 
+func TestParseCanonicalDID(t *testing.T) {
+	const (
+		cid    = "uEiDaapVGORRDxn3MzRqJDEq6sDXLPNL2ZjxpjgEAsXEd-g"
+		suffix = "EiBXhcf1g9tixDzkdDG6rD3mh7BN4KJuOFT3LtpRW2l8uw"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		namespace, c, s, err := ParseCanonicalDID("did:orb:" + cid + ":" + suffix)
+		require.NoError(t, err)
+		require.Equal(t, "did:orb", namespace)
+		require.Equal(t, cid, c)
+		require.Equal(t, suffix, s)
+	})
+
+	t.Run("error - interim DID", func(t *testing.T) {
+		_, _, _, err := ParseCanonicalDID("did:orb:uAAA:" + suffix)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "interim DID does not contain a CID")
+	})
+
+	t.Run("error - malformed DID", func(t *testing.T) {
+		tests := map[string]string{
+			"orb:" + cid + ":" + suffix:                           "missing did: prefix",
+			"did:orb:" + suffix:                                   "expecting 4 parts but got 3",
+			"did:orb:https:orb.domain1.com:" + cid + ":" + suffix: "expecting 4 parts but got 6",
+			"did::" + cid + ":" + suffix:                          "empty method",
+			"did:orb::" + suffix:                                  "empty CID",
+			"did:orb:" + cid + ":":                                "empty suffix",
+		}
+
+		for id, errMsg := range tests {
+			namespace, c, s, err := ParseCanonicalDID(id)
+			require.Error(t, err, id)
+			require.Contains(t, err.Error(), errMsg, id)
+			require.Empty(t, namespace)
+			require.Empty(t, c)
+			require.Empty(t, s)
+		}
+	})
+}
+
+func TestValidateOrbDID(t *testing.T) {
+	const (
+		namespace = "did:orb"
+		cid       = "uEiDaapVGORRDxn3MzRqJDEq6sDXLPNL2ZjxpjgEAsXEd-g"
+		ipfsCID   = "bafkreie4a6z6hosibbfz2dd7vhsukojw3gwmldh5jvy5uh3mjfrubiq5hi"
+		suffix    = "EiBXhcf1g9tixDzkdDG6rD3mh7BN4KJuOFT3LtpRW2l8uw"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		for _, id := range []string{
+			"did:orb:uAAA:" + suffix,                              // interim
+			"did:orb:" + cid + ":" + suffix,                       // canonical
+			"did:orb:" + ipfsCID + ":" + suffix,                   // canonical (IPFS CID)
+			"did:orb:ipfs:" + ipfsCID + ":" + suffix,              // hinted
+			"did:orb:https:orb.domain1.com:" + cid + ":" + suffix, // hinted with domain
+			"did:orb:https:localhost%3A48326:uAAA:" + suffix,      // hinted interim
+		} {
+			require.NoError(t, ValidateOrbDID(id, namespace), id)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		tests := map[string]string{
+			"did:web:" + cid + ":" + suffix:                         "expecting namespace [did:orb]",
+			"did:orbx:" + cid + ":" + suffix:                        "expecting namespace [did:orb]",
+			"did:orb:" + suffix:                                     "expecting CID and suffix",
+			"did:orb:https:orb.domain1.com:x:" + cid + ":" + suffix: "too many parts in hint [https:orb.domain1.com:x]",
+			"did:orb:https::" + cid + ":" + suffix:                  "empty part in hint",
+			"did:orb::" + suffix:                                    "empty CID",
+			"did:orb:uEiD$:" + suffix:                               "CID [uEiD$] contains invalid characters",
+			"did:orb:" + cid + ":":                                  "empty suffix",
+			"did:orb:" + cid + ":EiB/x":                             "suffix [EiB/x] contains invalid characters",
+		}
+
+		for id, errMsg := range tests {
+			err := ValidateOrbDID(id, namespace)
+			require.Error(t, err, id)
+			require.Contains(t, err.Error(), errMsg, id)
+		}
+	})
+
+	t.Run("hashlink", func(t *testing.T) {
+		hl := hashlink.New()
+
+		newHLDID := func(t *testing.T, resourceHash string, links ...string) string {
+			t.Helper()
+
+			metadata, err := hl.CreateMetadataFromLinks(links)
+			require.NoError(t, err)
+
+			return "did:orb:hl:" + resourceHash + ":" + metadata + ":" + suffix
+		}
+
+		// resourceHashCID is the IPFS CID of the resource with hash resourceHash.
+		const (
+			resourceHash    = "uEiAZPHwtTJ7-rG0nBeD6nqyL3Xsg1IA2BX1n9iGlv5yBJQ"
+			resourceHashCID = "bafkreiazhr6c2te672wg2jyf4d5j5lel3v5sbveagycx2z7wegs37hebeu"
+			webCASLink      = "https://orb.domain1.com/cas/" + resourceHash
+			ipfsLink        = "ipfs://" + resourceHashCID
+			otherWebCASLink = "https://orb.domain1.com/cas/" + cid
+			otherIPFSLink   = "ipfs://" + ipfsCID
+		)
+
+		require.NoError(t, ValidateOrbDID(newHLDID(t, resourceHash, webCASLink, ipfsLink), namespace))
+		require.NoError(t, ValidateOrbDID("did:orb:hl:"+resourceHash+":"+suffix, namespace))
+
+		err := ValidateOrbDID(newHLDID(t, resourceHash, webCASLink, otherWebCASLink), namespace)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "link ["+otherWebCASLink+"] does not reference resource hash ["+resourceHash+"]")
+
+		err = ValidateOrbDID(newHLDID(t, resourceHash, otherIPFSLink), namespace)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "link ["+otherIPFSLink+"] does not reference resource hash ["+resourceHash+"]")
+
+		err = ValidateOrbDID(newHLDID(t, cid, webCASLink), namespace)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not reference resource hash ["+cid+"]")
+
+		err = ValidateOrbDID(newHLDID(t, "uEiDaapVG", "https://orb.domain1.com/cas/uEiDaapVG"), namespace)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not a valid multihash")
+
+		err = ValidateOrbDID("did:orb:hl:"+cid+":uoQ:"+suffix, namespace)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get links from metadata")
+	})
+
+	t.Run("error - no namespace", func(t *testing.T) {
+		err := ValidateOrbDID("did:orb:"+cid+":"+suffix, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "namespace is required")
+	})
+}
+
 func TestBetweenStrings(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		str, err := BetweenStrings("did:orb:uAAA:suffix", "did:orb:", ":suffix")
@@ -14,6 +14,8 @@ import (
 	"strings"
 
 	"github.com/fxamacker/cbor/v2"
+	gocid "github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
 	"github.com/multiformats/go-multihash"
 	"github.com/trustbloc/sidetree-go/pkg/hashing"
 )
@@ -32,6 +34,9 @@ const (
 	HLPrefix = hl + separator
 )
 
+// ErrHashMismatch is returned when the resource hash of a hashlink doesn't match the hash of the content.
+var ErrHashMismatch = errors.New("hashlink does not match the content")
+
 // Encoder defines encoding function.
 type Encoder func(content []byte) string
 
@@ -235,6 +240,64 @@ func GetResourceHashFromHashLink(hashLink string) (string, error) {
 	return parts[1], nil
 }
 
+// VerifyResourceHash ensures that the resource hash in the given hashlink matches the hash of the given
+// content. The content is hashed using the same multihash algorithm as the resource hash. ErrHashMismatch
+// is returned if the hashes don't match.
+func VerifyResourceHash(hashLink string, content []byte) error {
+	expectedHash, err := GetResourceHashFromHashLink(hashLink)
+	if err != nil {
+		return fmt.Errorf("parse hashlink [%s]: %w", hashLink, err)
+	}
+
+	_, mhBytes, err := multibase.Decode(expectedHash)
+	if err != nil {
+		return fmt.Errorf("decode resource hash [%s]: %w", expectedHash, err)
+	}
+
+	decoded, err := multihash.Decode(mhBytes)
+	if err != nil {
+		return fmt.Errorf("decode multihash of resource hash [%s]: %w", expectedHash, err)
+	}
+
+	computedHash, err := New(WithMultihashCode(uint(decoded.Code))).CreateResourceHash(content)
+	if err != nil {
+		return fmt.Errorf("compute resource hash of content for hashlink [%s]: %w", hashLink, err)
+	}
+
+	if computedHash != expectedHash {
+		return fmt.Errorf("%w: expected resource hash [%s] but computed [%s]", ErrHashMismatch,
+			expectedHash, computedHash)
+	}
+
+	return nil
+}
+
+// NormalizeCID converts the given V0 or V1 CID to its canonical form, which is a V1 CID encoded with the
+// default (base32) multibase encoding. A V0 CID is converted to the equivalent V1 CID (with the dag-pb codec)
+// so that the V0 and V1 forms of the same CID normalize to the same value. An error is returned if the given
+// value is not a CID. (Note that a multibase-encoded multihash, i.e. a resource hash, is not considered a CID.)
+func NormalizeCID(s string) (string, error) {
+	cid, err := gocid.Decode(s)
+	if err != nil {
+		return "", fmt.Errorf("decode CID [%s]: %w", s, err)
+	}
+
+	switch cid.Version() {
+	case 0:
+		// A multibase-encoded multihash may also be decoded as a V0 CID, so ensure that
+		// the given value is actually in V0 CID form.
+		if cid.String() != s {
+			return "", fmt.Errorf("value [%s] is not a CID", s)
+		}
+
+		return gocid.NewCidV1(gocid.DagProtobuf, cid.Hash()).String(), nil
+	case 1:
+		return cid.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported CID version [%d] for CID [%s]", cid.Version(), s)
+	}
+}
+
 // StringArray is utility function to return string array from interface.
 func toStringArray(obj interface{}) ([]string, error) {
 	if obj == nil {
@@ -348,6 +348,72 @@ func TestGetResourceHashFromHashLink(t *testing.T) {
 	})
 }
 
+func TestNormalizeCID(t *testing.T) {
+	const (
+		cidV0         = "QmSvPd3sHK7iWgZuW47fyLy4CaZQe2DwxvRhrJ39VpBVMK"
+		cidV1         = "bafybeicecnx2gvntm6fbcrvnc336qze6st5u7qq7457igegamd3bzkx7ri"
+		cidV1Raw      = "bafkreicecnx2gvntm6fbcrvnc336qze6st5u7qq7457igegamd3bzkx7ri"
+		cidV1RawB58   = "zb2rhbE2775XANjTsRTV9sxfFMWxrGuMWYgshDn9xvjG69fZ3"
+		resourceHash  = "uEiBEE2-jVbNnihFGrRb36GSelPtPwh_nfoMQwGD2HKr_ig"
+		invalidCIDStr = "invalid"
+	)
+
+	t.Run("V0 and V1 CIDs normalize to the same value", func(t *testing.T) {
+		normalizedV0, err := NormalizeCID(cidV0)
+		require.NoError(t, err)
+		require.Equal(t, cidV1, normalizedV0)
+
+		normalizedV1, err := NormalizeCID(cidV1)
+		require.NoError(t, err)
+		require.Equal(t, cidV1, normalizedV1)
+	})
+
+	t.Run("V1 CID with non-default base", func(t *testing.T) {
+		normalized, err := NormalizeCID(cidV1RawB58)
+		require.NoError(t, err)
+		require.Equal(t, cidV1Raw, normalized)
+	})
+
+	t.Run("Resource hash -> error", func(t *testing.T) {
+		_, err := NormalizeCID(resourceHash)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not a CID")
+	})
+
+	t.Run("Invalid CID -> error", func(t *testing.T) {
+		_, err := NormalizeCID(invalidCIDStr)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode CID")
+	})
+}
+
+func TestVerifyResourceHash(t *testing.T) {
+	hl, err := New().CreateHashLink([]byte(exampleContent), nil)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, VerifyResourceHash(hl, []byte(exampleContent)))
+	})
+
+	t.Run("Hash mismatch -> error", func(t *testing.T) {
+		err := VerifyResourceHash(hl, []byte("corrupted content"))
+		require.ErrorIs(t, err, ErrHashMismatch)
+		require.Contains(t, err.Error(), "expected resource hash")
+	})
+
+	t.Run("Invalid hashlink -> error", func(t *testing.T) {
+		err := VerifyResourceHash("uEiBEE2-jVbNnihFGrRb36GSelPtPwh_nfoMQwGD2HKr_ig", []byte(exampleContent))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse hashlink")
+	})
+
+	t.Run("Invalid resource hash -> error", func(t *testing.T) {
+		err := VerifyResourceHash("hl:xyz", []byte(exampleContent))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode resource hash")
+	})
+}
+
 func TestToString(t *testing.T) {
 	const (
 		hl1 = "hl:uEiC8e7XhtySK1lYVLTIiAi66FAEmmxdiu2_EwVkJYTlsLw:uoQ-CeEtodHRwczovL29yYi5kb21haW4xLmNvbS9jYXMvdUVpQzhlN1hodHlTSzFsWVZMVElpQWk2NkZBRW1teGRpdTJfRXdWa0pZVGxzTHd4QmlwZnM6Ly9iYWZrcmVpZjRwbzI2ZG56ZXJsbGZtZmpuZ2lyYWVsdjJjcWFzbmd5eG1rNXc3cmdibGVld2NvbG1mNA"
@@ -37,9 +37,20 @@ type Handler struct {
 	vct             vctService
 	db              db
 	keyManager      keyManager
+	observer        observer
 	maintenanceMode bool
 }
 
+// Option is a health check handler option.
+type Option func(h *Handler)
+
+// WithObserver sets the observer whose subscription to the message queue is included in the health check.
+func WithObserver(o observer) Option {
+	return func(h *Handler) {
+		h.observer = o
+	}
+}
+
 type pubSub interface {
 	IsConnected() bool
 }
@@ -56,15 +67,27 @@ type keyManager interface {
 	HealthCheck() error
 }
 
+type observer interface {
+	HealthCheck() error
+}
+
 // NewHandler returns a new health check handler.
-func NewHandler(pubSub pubSub, vctService vctService, db db, keyManager keyManager, maintenanceMode bool) *Handler {
-	return &Handler{
+func NewHandler(pubSub pubSub, vctService vctService, db db, keyManager keyManager, maintenanceMode bool,
+	opts ...Option,
+) *Handler {
+	h := &Handler{
 		pubSub:          pubSub,
 		vct:             vctService,
 		db:              db,
 		keyManager:      keyManager,
 		maintenanceMode: maintenanceMode,
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 // Method returns the HTTP method, which is always POST.
@@ -84,17 +107,18 @@ func (h *Handler) Handler() common.HTTPRequestHandler {
 }
 
 type response struct {
-	MQStatus    string    `json:"mqStatus,omitempty"`
-	VCTStatus   string    `json:"vctStatus,omitempty"`
-	DBStatus    string    `json:"dbStatus,omitempty"`
-	KMSStatus   string    `json:"kmsStatus,omitempty"`
-	Status      string    `json:"status,omitempty"`
-	CurrentTime time.Time `json:"currentTime,omitempty"`
-	Version     string    `json:"version,omitempty"`
+	MQStatus       string    `json:"mqStatus,omitempty"`
+	VCTStatus      string    `json:"vctStatus,omitempty"`
+	DBStatus       string    `json:"dbStatus,omitempty"`
+	KMSStatus      string    `json:"kmsStatus,omitempty"`
+	ObserverStatus string    `json:"observerStatus,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	CurrentTime    time.Time `json:"currentTime,omitempty"`
+	Version        string    `json:"version,omitempty"`
 }
 
-func (h *Handler) checkHealth(rw http.ResponseWriter, _ *http.Request) {
-	var mqStatus, vctStatus, dbStatus, kmsStatus string
+func (h *Handler) checkHealth(rw http.ResponseWriter, _ *http.Request) { //nolint:cyclop
+	var mqStatus, vctStatus, dbStatus, kmsStatus, observerStatus string
 
 	returnStatusServiceUnavailable := false
 
@@ -118,6 +142,11 @@ func (h *Handler) checkHealth(rw http.ResponseWriter, _ *http.Request) {
 		returnStatusServiceUnavailable = true
 	}
 
+	unavailable, observerStatus = h.observerHealthCheck()
+	if unavailable {
+		returnStatusServiceUnavailable = true
+	}
+
 	status := http.StatusOK
 
 	if returnStatusServiceUnavailable {
@@ -125,13 +154,14 @@ func (h *Handler) checkHealth(rw http.ResponseWriter, _ *http.Request) {
 	}
 
 	hc := &response{
-		MQStatus:    mqStatus,
-		VCTStatus:   vctStatus,
-		DBStatus:    dbStatus,
-		KMSStatus:   kmsStatus,
-		CurrentTime: time.Now(),
-		Status:      "OK",
-		Version:     httpserver.BuildVersion,
+		MQStatus:       mqStatus,
+		VCTStatus:      vctStatus,
+		DBStatus:       dbStatus,
+		KMSStatus:      kmsStatus,
+		ObserverStatus: observerStatus,
+		CurrentTime:    time.Now(),
+		Status:         "OK",
+		Version:        httpserver.BuildVersion,
 	}
 
 	if h.maintenanceMode {
@@ -215,6 +245,19 @@ func (h *Handler) kmsHealthCheck() (bool, string) {
 	return true, toStatus(err)
 }
 
+func (h *Handler) observerHealthCheck() (bool, string) {
+	if h.observer == nil {
+		return false, ""
+	}
+
+	err := h.observer.HealthCheck()
+	if err == nil {
+		return false, success
+	}
+
+	return true, toStatus(err)
+}
+
 func toStatus(err error) string {
 	if err.Error() != "" {
 		return err.Error()
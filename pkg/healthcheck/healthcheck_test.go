@@ -111,6 +111,32 @@ func TestServer_Start(t *testing.T) {
 		require.Equal(t, "success", resp.MQStatus)
 	})
 
+	t.Run("observer unhealthy - health check", func(t *testing.T) {
+		h := NewHandler(
+			&mockService{},
+			&mockService{},
+			&mockService{},
+			&mockService{},
+			false,
+			WithObserver(&mockService{healthCheckErr: fmt.Errorf("unable to resubscribe")}),
+		)
+
+		b := httptest.NewRecorder()
+		h.checkHealth(b, nil)
+
+		result := b.Result()
+
+		require.Equal(t, http.StatusServiceUnavailable, result.StatusCode)
+
+		resp := &response{}
+
+		require.NoError(t, json.NewDecoder(result.Body).Decode(resp))
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, "unable to resubscribe", resp.ObserverStatus)
+		require.Equal(t, "success", resp.MQStatus)
+	})
+
 	t.Run("Unknown error - health check", func(t *testing.T) {
 		h := NewHandler(
 			&mockService{isConnectedErr: fmt.Errorf("")},
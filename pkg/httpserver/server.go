@@ -23,6 +23,7 @@ import (
 	"golang.org/x/net/http2/h2c"
 
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/requestid"
 )
 
 const (
@@ -129,6 +130,8 @@ func New(url string, opts ...Opt) *Server {
 		router.Use(otelmux.Middleware(options.tracingServiceName))
 	}
 
+	router.Use(requestid.Middleware)
+
 	for _, handler := range options.handlers {
 		logger.Info("Registering handler", logfields.WithServiceEndpoint(handler.Path()))
 
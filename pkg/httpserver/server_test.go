@@ -18,6 +18,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/requestid"
 )
 
 const (
@@ -47,6 +49,18 @@ func TestServer_Start(t *testing.T) {
 		require.NotNil(t, resp)
 	})
 
+	t.Run("success - request ID is returned", func(t *testing.T) {
+		httpReq, err := http.NewRequest(http.MethodGet, clientURL+samplePath+"/id", http.NoBody)
+		require.NoError(t, err)
+
+		httpReq.Header.Set(requestid.HeaderName, "request-1")
+
+		resp, err := http.DefaultClient.Do(httpReq)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, "request-1", resp.Header.Get(requestid.HeaderName))
+	})
+
 	t.Run("Stop", func(t *testing.T) {
 		require.NoError(t, s.Stop(context.Background()))
 		require.Error(t, s.Stop(context.Background()))
@@ -0,0 +1,268 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linkset
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// LinksetDiff contains the structural differences between two linksets. Links are keyed by their anchor hashlink.
+type LinksetDiff struct {
+	// OnlyInA contains the anchors of the links that are only in the first linkset.
+	OnlyInA []string `json:"onlyInA,omitempty"`
+	// OnlyInB contains the anchors of the links that are only in the second linkset.
+	OnlyInB []string `json:"onlyInB,omitempty"`
+	// Common contains the links that are in both linksets along with the differences in their items and parents.
+	Common []*LinkDiff `json:"common,omitempty"`
+}
+
+// HasDifferences returns true if the two linksets are different.
+func (d *LinksetDiff) HasDifferences() bool {
+	if len(d.OnlyInA) > 0 || len(d.OnlyInB) > 0 {
+		return true
+	}
+
+	for _, ld := range d.Common {
+		if ld.HasDifferences() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LinkDiff contains the differences between two links with the same anchor.
+type LinkDiff struct {
+	Anchor string `json:"anchor"`
+	// ItemsOnlyInA contains the items (DIDs) that are only in the link of the first linkset.
+	ItemsOnlyInA []string `json:"itemsOnlyInA,omitempty"`
+	// ItemsOnlyInB contains the items (DIDs) that are only in the link of the second linkset.
+	ItemsOnlyInB []string `json:"itemsOnlyInB,omitempty"`
+	// ItemsChanged contains the items that are in both links but with different previous anchors.
+	ItemsChanged []*ItemDiff `json:"itemsChanged,omitempty"`
+	// ParentsOnlyInA contains the parents that are only in the link of the first linkset.
+	ParentsOnlyInA []string `json:"parentsOnlyInA,omitempty"`
+	// ParentsOnlyInB contains the parents that are only in the link of the second linkset.
+	ParentsOnlyInB []string `json:"parentsOnlyInB,omitempty"`
+}
+
+// HasDifferences returns true if the items or parents of the two links are different.
+func (d *LinkDiff) HasDifferences() bool {
+	return len(d.ItemsOnlyInA) > 0 || len(d.ItemsOnlyInB) > 0 || len(d.ItemsChanged) > 0 ||
+		len(d.ParentsOnlyInA) > 0 || len(d.ParentsOnlyInB) > 0
+}
+
+// ItemDiff contains the previous anchors of an item which differs between two links.
+type ItemDiff struct {
+	HRef      string `json:"href"`
+	PreviousA string `json:"previousA,omitempty"`
+	PreviousB string `json:"previousB,omitempty"`
+}
+
+// Diff returns the structural differences between the two given linksets. The links of each linkset are keyed
+// by their anchor hashlink. For each anchor that is in both linksets, the items (DIDs along with their previous
+// anchors) and the parents of the two links are compared. The items and parents are taken from the link itself
+// or, for an anchor link, from the embedded 'original' and 'related' linksets. Duplicate parents are ignored.
+// An error is returned if either linkset is nil, if a link has no anchor, if an anchor appears more than once
+// in the same linkset, or if an embedded linkset is invalid.
+func Diff(a, b *Linkset) (*LinksetDiff, error) {
+	if a == nil || b == nil {
+		return nil, errors.New("linkset is nil")
+	}
+
+	linksA, anchorsA, err := linksByAnchor(a)
+	if err != nil {
+		return nil, fmt.Errorf("linkset A: %w", err)
+	}
+
+	linksB, anchorsB, err := linksByAnchor(b)
+	if err != nil {
+		return nil, fmt.Errorf("linkset B: %w", err)
+	}
+
+	diff := &LinksetDiff{}
+
+	for _, anchor := range anchorsA {
+		linkB, ok := linksB[anchor]
+		if !ok {
+			diff.OnlyInA = append(diff.OnlyInA, anchor)
+
+			continue
+		}
+
+		ld, err := diffLinks(anchor, linksA[anchor], linkB)
+		if err != nil {
+			return nil, fmt.Errorf("anchor [%s]: %w", anchor, err)
+		}
+
+		diff.Common = append(diff.Common, ld)
+	}
+
+	for _, anchor := range anchorsB {
+		if _, ok := linksA[anchor]; !ok {
+			diff.OnlyInB = append(diff.OnlyInB, anchor)
+		}
+	}
+
+	return diff, nil
+}
+
+func linksByAnchor(ls *Linkset) (map[string]*Link, []string, error) {
+	links := make(map[string]*Link)
+
+	var anchors []string
+
+	for _, l := range ls.Linkset {
+		if l.Anchor() == nil {
+			return nil, nil, errors.New("link has no anchor")
+		}
+
+		anchor := l.Anchor().String()
+
+		if _, exists := links[anchor]; exists {
+			return nil, nil, fmt.Errorf("duplicate anchor [%s]", anchor)
+		}
+
+		links[anchor] = l
+		anchors = append(anchors, anchor)
+	}
+
+	return links, anchors, nil
+}
+
+func diffLinks(anchor string, a, b *Link) (*LinkDiff, error) {
+	itemsA, err := itemsOf(a)
+	if err != nil {
+		return nil, fmt.Errorf("items of link A: %w", err)
+	}
+
+	itemsB, err := itemsOf(b)
+	if err != nil {
+		return nil, fmt.Errorf("items of link B: %w", err)
+	}
+
+	parentsA, err := parentsOf(a)
+	if err != nil {
+		return nil, fmt.Errorf("parents of link A: %w", err)
+	}
+
+	parentsB, err := parentsOf(b)
+	if err != nil {
+		return nil, fmt.Errorf("parents of link B: %w", err)
+	}
+
+	ld := &LinkDiff{
+		Anchor:         anchor,
+		ItemsOnlyInA:   onlyIn(itemsA, itemsB),
+		ItemsOnlyInB:   onlyIn(itemsB, itemsA),
+		ParentsOnlyInA: onlyIn(parentsA, parentsB),
+		ParentsOnlyInB: onlyIn(parentsB, parentsA),
+	}
+
+	for _, href := range sortedKeys(itemsA) {
+		previousB, ok := itemsB[href]
+		if !ok || itemsA[href] == previousB {
+			continue
+		}
+
+		ld.ItemsChanged = append(ld.ItemsChanged, &ItemDiff{
+			HRef:      href,
+			PreviousA: itemsA[href],
+			PreviousB: previousB,
+		})
+	}
+
+	return ld, nil
+}
+
+// itemsOf returns the previous anchor of each item in the given link, keyed by the item's href. If the link
+// contains no items then the items are taken from the embedded 'original' linkset (if any).
+func itemsOf(l *Link) (map[string]string, error) {
+	items := l.Items()
+
+	if len(items) == 0 && l.Original() != nil && l.Original().Type() == TypeLinkset {
+		original, err := l.Original().Linkset()
+		if err != nil {
+			return nil, fmt.Errorf("original: %w", err)
+		}
+
+		items = original.Link().Items()
+	}
+
+	m := make(map[string]string, len(items))
+
+	for _, item := range items {
+		if item.HRef() == nil {
+			continue
+		}
+
+		m[item.HRef().String()] = urlString(item.Previous())
+	}
+
+	return m, nil
+}
+
+// parentsOf returns the set of parents of the given link. If the link contains no parents then the parents
+// are taken from the embedded 'related' linkset (if any).
+func parentsOf(l *Link) (map[string]string, error) {
+	parents := l.Up()
+
+	if len(parents) == 0 && l.Related() != nil {
+		related, err := l.Related().Linkset()
+		if err != nil {
+			return nil, fmt.Errorf("related: %w", err)
+		}
+
+		parents = related.Link().Up()
+	}
+
+	m := make(map[string]string, len(parents))
+
+	for _, p := range parents {
+		if p != nil {
+			m[p.String()] = ""
+		}
+	}
+
+	return m, nil
+}
+
+// onlyIn returns the (sorted) keys in m1 which are not in m2.
+func onlyIn(m1, m2 map[string]string) []string {
+	var keys []string
+
+	for _, k := range sortedKeys(m1) {
+		if _, ok := m2[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func urlString(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	return u.String()
+}
@@ -0,0 +1,191 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linkset
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/datauri"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestDiff(t *testing.T) {
+	profile := testutil.MustParseURL("https://w3id.org/orb#v0")
+	author := testutil.MustParseURL("https://orb.domain2.com/services/orb")
+	via := testutil.MustParseURL("hl:uEiCVVS-n0wx0OfeEXBM9jcGNOcMEArYYWPIxk5D_l96ySg")
+
+	did1 := testutil.MustParseURL("did:orb:uAAA:EiBfWqeAJfENeHLABsYIYmsIqtk-bsmvJmoR6IgISd6ZcA")
+	did2 := testutil.MustParseURL("did:orb:uAAA:EiBfWqeAJfENeHLABsYIYmsIqtk-bsmvJmoR6IgISd6AdB")
+	did3 := testutil.MustParseURL("did:orb:uAAA:EiBfWqeAJfENeHLABsYIYmsIqtk-bsmvJmoR6IgISd6AdC")
+
+	parent1 := testutil.MustParseURL("hl:uEiAVAPhjewMUvawD0gl-MYMzvTPVuUA1DpO1SmBqcGbhvw")
+	parent2 := testutil.MustParseURL("hl:uEiAVAQhjewMUvawD0gl-LYMzvTPVuUA1DpO1SmBqcGbhvw")
+	parent3 := testutil.MustParseURL("hl:uEiAVAQhjewMUvawD0gl-LYMzvTPVuUA1DpO1SmBqcGbabc")
+
+	anchor1 := testutil.MustParseURL("hl:uEiB4Hm-DuYbEhq1uyc8e1AcuaPPW0UmO6N2C49mCxwMi6g")
+	anchor2 := testutil.MustParseURL("hl:uEiDJ2zp6YLkEmDXDvC6IcTSbqLzbdZiFQqBVqDWNU0N3Fg")
+	anchor3 := testutil.MustParseURL("hl:uEiDEKcU5UJDS0TSiMOj_uCyNHm4uwOPzYOmOPk1Dv8yoig")
+
+	// newAnchorLink returns an anchor link in which the items and parents are embedded in the
+	// 'original' and 'related' linksets.
+	newAnchorLink := func(t *testing.T, items []*Item, parents ...*url.URL) *Link {
+		t.Helper()
+
+		originalBytes := testutil.MarshalCanonical(t, New(NewAnchorLink(via, author, profile, items)))
+
+		anchor, originalRef, err := NewAnchorRef(originalBytes, datauri.MediaTypeDataURIGzipBase64, TypeLinkset)
+		require.NoError(t, err)
+
+		relatedBytes := testutil.MarshalCanonical(t, New(NewRelatedLink(anchor, profile, via, parents...)))
+
+		_, relatedRef, err := NewAnchorRef(relatedBytes, datauri.MediaTypeDataURIGzipBase64, TypeLinkset)
+		require.NoError(t, err)
+
+		return NewLink(anchor, author, profile, originalRef, relatedRef, nil)
+	}
+
+	t.Run("Identical", func(t *testing.T) {
+		ls := New(
+			NewAnchorLink(anchor1, author, profile, []*Item{NewItem(did1, parent1), NewItem(did2, nil)}),
+			NewAnchorLink(anchor2, author, profile, []*Item{NewItem(did3, nil)}),
+		)
+
+		diff, err := Diff(ls, ls)
+		require.NoError(t, err)
+		require.False(t, diff.HasDifferences())
+		require.Empty(t, diff.OnlyInA)
+		require.Empty(t, diff.OnlyInB)
+		require.Len(t, diff.Common, 2)
+		require.Equal(t, anchor1.String(), diff.Common[0].Anchor)
+		require.Equal(t, anchor2.String(), diff.Common[1].Anchor)
+	})
+
+	t.Run("Only in A, only in B and common", func(t *testing.T) {
+		a := New(
+			NewAnchorLink(anchor1, author, profile, []*Item{NewItem(did1, nil)}),
+			NewAnchorLink(anchor2, author, profile, []*Item{NewItem(did2, nil)}),
+		)
+
+		b := New(
+			NewAnchorLink(anchor2, author, profile, []*Item{NewItem(did2, nil)}),
+			NewAnchorLink(anchor3, author, profile, []*Item{NewItem(did3, nil)}),
+		)
+
+		diff, err := Diff(a, b)
+		require.NoError(t, err)
+		require.True(t, diff.HasDifferences())
+		require.Equal(t, []string{anchor1.String()}, diff.OnlyInA)
+		require.Equal(t, []string{anchor3.String()}, diff.OnlyInB)
+		require.Len(t, diff.Common, 1)
+		require.Equal(t, anchor2.String(), diff.Common[0].Anchor)
+		require.False(t, diff.Common[0].HasDifferences())
+	})
+
+	t.Run("Different items", func(t *testing.T) {
+		a := New(NewAnchorLink(anchor1, author, profile,
+			[]*Item{NewItem(did1, parent1), NewItem(did2, nil)},
+		))
+
+		b := New(NewAnchorLink(anchor1, author, profile,
+			[]*Item{NewItem(did1, parent2), NewItem(did3, nil)},
+		))
+
+		diff, err := Diff(a, b)
+		require.NoError(t, err)
+		require.True(t, diff.HasDifferences())
+		require.Len(t, diff.Common, 1)
+
+		ld := diff.Common[0]
+		require.Equal(t, []string{did2.String()}, ld.ItemsOnlyInA)
+		require.Equal(t, []string{did3.String()}, ld.ItemsOnlyInB)
+		require.Len(t, ld.ItemsChanged, 1)
+		require.Equal(t, did1.String(), ld.ItemsChanged[0].HRef)
+		require.Equal(t, parent1.String(), ld.ItemsChanged[0].PreviousA)
+		require.Equal(t, parent2.String(), ld.ItemsChanged[0].PreviousB)
+	})
+
+	t.Run("Anchor links with embedded items and parents", func(t *testing.T) {
+		items := []*Item{NewItem(did1, parent1), NewItem(did2, parent2)}
+
+		linkA := newAnchorLink(t, items, parent1, parent2)
+		linkB := newAnchorLink(t, items, parent1, parent3)
+
+		// Use the same anchor for both links so that the parents are compared.
+		linkB.link.Anchor = linkA.link.Anchor
+
+		diff, err := Diff(New(linkA), New(linkB))
+		require.NoError(t, err)
+		require.True(t, diff.HasDifferences())
+		require.Len(t, diff.Common, 1)
+
+		ld := diff.Common[0]
+		require.Empty(t, ld.ItemsOnlyInA)
+		require.Empty(t, ld.ItemsOnlyInB)
+		require.Empty(t, ld.ItemsChanged)
+		require.Equal(t, []string{parent2.String()}, ld.ParentsOnlyInA)
+		require.Equal(t, []string{parent3.String()}, ld.ParentsOnlyInB)
+	})
+
+	t.Run("Duplicate parents", func(t *testing.T) {
+		items := []*Item{NewItem(did1, parent1), NewItem(did2, parent2)}
+
+		linkA := newAnchorLink(t, items, parent1, parent2, parent1)
+		linkB := newAnchorLink(t, items, parent2, parent1)
+
+		linkB.link.Anchor = linkA.link.Anchor
+
+		diff, err := Diff(New(linkA), New(linkB))
+		require.NoError(t, err)
+		require.False(t, diff.HasDifferences())
+		require.Len(t, diff.Common, 1)
+		require.Empty(t, diff.Common[0].ParentsOnlyInA)
+		require.Empty(t, diff.Common[0].ParentsOnlyInB)
+	})
+
+	t.Run("Nil linkset -> error", func(t *testing.T) {
+		_, err := Diff(nil, New())
+		require.EqualError(t, err, "linkset is nil")
+
+		_, err = Diff(New(), nil)
+		require.EqualError(t, err, "linkset is nil")
+	})
+
+	t.Run("Duplicate anchor -> error", func(t *testing.T) {
+		ls := New(
+			NewAnchorLink(anchor1, author, profile, []*Item{NewItem(did1, nil)}),
+			NewAnchorLink(anchor1, author, profile, []*Item{NewItem(did2, nil)}),
+		)
+
+		_, err := Diff(ls, New())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "linkset A: duplicate anchor")
+
+		_, err = Diff(New(), ls)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "linkset B: duplicate anchor")
+	})
+
+	t.Run("Link without anchor -> error", func(t *testing.T) {
+		_, err := Diff(New(&Link{link: &link{}}), New())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "link has no anchor")
+	})
+
+	t.Run("Invalid embedded linkset -> error", func(t *testing.T) {
+		_, invalidRef, err := NewAnchorRef([]byte("{"), datauri.MediaTypeDataURIGzipBase64, TypeLinkset)
+		require.NoError(t, err)
+
+		ls := New(NewLink(anchor1, author, profile, invalidRef, nil, nil))
+
+		_, err = Diff(ls, ls)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "items of link A: original")
+	})
+}
@@ -267,7 +267,9 @@ func NewRelatedLink(anchor, profile, via *url.URL, up ...*url.URL) *Link {
 
 // NewAnchorRef creates a data URI Reference of the given content type and returns the anchor URI
 // (which is a hashlink of the data).
-func NewAnchorRef(data []byte, uriMediaType datauri.MediaType, contentType LinkType) (*url.URL, *Reference, error) {
+func NewAnchorRef(data []byte, uriMediaType datauri.MediaType, contentType LinkType,
+	opts ...datauri.Opt,
+) (*url.URL, *Reference, error) {
 	anchorHL, err := hashlink.New().CreateHashLink(data, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("create hashlink of data: %w", err)
@@ -278,7 +280,7 @@ func NewAnchorRef(data []byte, uriMediaType datauri.MediaType, contentType LinkT
 		return nil, nil, fmt.Errorf("parse anchorURI hashlink: %w", err)
 	}
 
-	dataURI, err := datauri.New(data, uriMediaType)
+	dataURI, err := datauri.New(data, uriMediaType, opts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("create data URI: %w", err)
 	}
@@ -556,3 +558,121 @@ func (p *urlCollectionProperty) UnmarshalJSON(bytes []byte) error {
 
 	return nil
 }
+
+// ExtractCASReferences returns the CAS references (hashlinks and IPFS URLs) found in the original, related,
+// replies, up, via and previous fields of all of the links in the given linkset. Embedded linksets (i.e. data
+// URIs of type application/linkset+json) are searched recursively. Other data URIs and non-CAS URLs are
+// ignored since they can't be resolved from CAS. The returned references are deduplicated (hashlinks by
+// resource hash, in which case the hashlink that contains metadata is preferred) and are returned in the
+// order in which they were found. The references may be resolved in parallel in order to warm the CAS
+// before a linkset is processed.
+func ExtractCASReferences(ls *Linkset) ([]string, error) {
+	c := &casRefCollector{
+		hl:   hashlink.New(),
+		refs: make(map[string]string),
+	}
+
+	if err := c.addLinkset(ls); err != nil {
+		return nil, err
+	}
+
+	refs := make([]string, len(c.keys))
+
+	for i, key := range c.keys {
+		refs[i] = c.refs[key]
+	}
+
+	return refs, nil
+}
+
+type casRefCollector struct {
+	hl   *hashlink.HashLink
+	keys []string
+	refs map[string]string
+}
+
+func (c *casRefCollector) addLinkset(ls *Linkset) error {
+	if ls == nil {
+		return nil
+	}
+
+	for _, l := range ls.Linkset {
+		if l == nil || l.link == nil {
+			continue
+		}
+
+		for _, refs := range [][]*Reference{l.link.Original, l.link.Related, l.link.Replies, l.link.Up, l.link.Via} {
+			for _, ref := range refs {
+				if err := c.addReference(ref); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, item := range l.link.Item {
+			if item == nil || item.item == nil {
+				continue
+			}
+
+			for _, u := range item.item.Previous.URLs() {
+				if err := c.addURL(u); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *casRefCollector) addReference(ref *Reference) error {
+	if ref == nil || ref.HRef() == nil {
+		return nil
+	}
+
+	if ref.HRef().Scheme != "data" {
+		return c.addURL(ref.HRef())
+	}
+
+	if ref.Type() != TypeLinkset {
+		// The content is embedded so there's nothing to resolve from CAS.
+		return nil
+	}
+
+	embedded, err := ref.Linkset()
+	if err != nil {
+		return fmt.Errorf("embedded linkset: %w", err)
+	}
+
+	return c.addLinkset(embedded)
+}
+
+func (c *casRefCollector) addURL(u *url.URL) error {
+	if u == nil {
+		return nil
+	}
+
+	switch u.Scheme {
+	case "hl":
+		info, err := c.hl.ParseHashLink(u.String())
+		if err != nil {
+			return fmt.Errorf("invalid hashlink [%s]: %w", u, err)
+		}
+
+		c.add(info.ResourceHash, u.String(), len(info.Links) > 0)
+	case "ipfs":
+		c.add(u.String(), u.String(), false)
+	}
+
+	return nil
+}
+
+func (c *casRefCollector) add(key, ref string, preferred bool) {
+	if _, exists := c.refs[key]; !exists {
+		c.keys = append(c.keys, key)
+	} else if !preferred {
+		return
+	}
+
+	c.refs[key] = ref
+}
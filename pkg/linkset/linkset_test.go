@@ -524,6 +524,81 @@ func TestReference(t *testing.T) {
 	})
 }
 
+func TestExtractCASReferences(t *testing.T) {
+	profile := testutil.MustParseURL("https://w3id.org/orb#v0")
+	author := testutil.MustParseURL("https://orb.domain2.com/services/orb")
+
+	sidetreeIndexHL := testutil.MustParseURL("hl:uEiCVVS-n0wx0OfeEXBM9jcGNOcMEArYYWPIxk5D_l96ySg")
+	viaHL := testutil.MustParseURL("hl:uEiCVVS-n0wx0OfeEXBM9jcGNOcMEArYYWPIxk5D_l96ySg:uoQ-BeEtodHRwczovL29yYi5kb21haW4yLmNvbS9jYXMvdUVpQVZBUGhqZXdNVXZhd0QwZ2wtTVlNenZUUFZ1VUExRHBPMVNtQnFjR2Jodnc") //nolint:lll
+	prevHRef1 := testutil.MustParseURL("hl:uEiAVAPhjewMUvawD0gl-MYMzvTPVuUA1DpO1SmBqcGbhvw")
+	prevHRef2 := testutil.MustParseURL("ipfs://bafkreiatkubvbkdidscmqynkyls3iqawdqvthi7e6mbky2amuw3inxsi3y")
+	upHRef1 := testutil.MustParseURL("hl:uEiAVAPhjewMUvawD0gl-MYMzvTPVuUA1DpO1SmBqcGbhvw:uoQ-BeEtodHRwczovL29yYi5kb21haW4yLmNvbS9jYXMvdUVpQVZBUGhqZXdNVXZhd0QwZ2wtTVlNenZUUFZ1VUExRHBPMVNtQnFjR2Jodnc") //nolint:lll
+	upHRef2 := testutil.MustParseURL("hl:uEiAVAQhjewMUvawD0gl-LYMzvTPVuUA1DpO1SmBqcGbhvw")
+
+	originalLSBytes := testutil.MarshalCanonical(t, New(
+		NewAnchorLink(sidetreeIndexHL, author, profile,
+			[]*Item{
+				NewItem(testutil.MustParseURL("did:orb:uAAA:EiBfWqeAJfENeHLABsYIYmsIqtk-bsmvJmoR6IgISd6ZcA"), prevHRef1),
+				NewItem(testutil.MustParseURL("did:orb:uAAA:EiBfWqeAJfENeHLABsYIYmsIqtk-bsmvJmoR6IgISd6AdB"), prevHRef2),
+				NewItem(testutil.MustParseURL("did:orb:uAAA:EiBfWqeAJfENeHLABsYIYmsIqtk-bsmvJmoR6IgISd6AdC"), nil),
+			},
+		),
+	))
+
+	anchor, originalRef, err := NewAnchorRef(originalLSBytes, datauri.MediaTypeDataURIGzipBase64, TypeLinkset)
+	require.NoError(t, err)
+
+	relatedLSBytes := testutil.MarshalCanonical(t, New(NewRelatedLink(anchor, profile, viaHL, upHRef1, upHRef2)))
+
+	_, relatedRef, err := NewAnchorRef(relatedLSBytes, datauri.MediaTypeDataURIGzipBase64, TypeLinkset)
+	require.NoError(t, err)
+
+	_, repliesRef, err := NewAnchorRef([]byte(replyJSONData), datauri.MediaTypeDataURIGzipBase64, TypeJSONLD)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		ls := New(NewLink(anchor, author, profile, originalRef, relatedRef, repliesRef))
+
+		refs, err := ExtractCASReferences(ls)
+		require.NoError(t, err)
+		require.Equal(t, []string{upHRef1.String(), prevHRef2.String(), upHRef2.String(), viaHL.String()}, refs)
+	})
+
+	t.Run("nil linkset", func(t *testing.T) {
+		refs, err := ExtractCASReferences(nil)
+		require.NoError(t, err)
+		require.Empty(t, refs)
+	})
+
+	t.Run("non-CAS references are ignored", func(t *testing.T) {
+		ls := New(NewLink(anchor, author, profile,
+			NewReference(testutil.MustParseURL("https://orb.domain2.com/cas/xxx"), TypeLinkset), nil, repliesRef))
+
+		refs, err := ExtractCASReferences(ls)
+		require.NoError(t, err)
+		require.Empty(t, refs)
+	})
+
+	t.Run("invalid hashlink", func(t *testing.T) {
+		ls := New(NewRelatedLink(anchor, profile, testutil.MustParseURL("hl:xxx")))
+
+		_, err := ExtractCASReferences(ls)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid hashlink [hl:xxx]")
+	})
+
+	t.Run("invalid embedded linkset", func(t *testing.T) {
+		_, invalidRef, err := NewAnchorRef([]byte("{"), datauri.MediaTypeDataURIGzipBase64, TypeLinkset)
+		require.NoError(t, err)
+
+		ls := New(NewLink(anchor, author, profile, invalidRef, nil, nil))
+
+		_, err = ExtractCASReferences(ls)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "embedded linkset")
+	})
+}
+
 const (
 	originalLinksetJSON = `{
   "linkset": [
@@ -23,6 +23,16 @@ func (m *MetricsProvider) OutboxResolveInboxesTime(value time.Duration) {
 func (m *MetricsProvider) InboxHandlerTime(activityType string, value time.Duration) {
 }
 
+// InboxIncrementRejectedCount increments the number of activities of the given type rejected by the inbox
+// for the given reason.
+func (m *MetricsProvider) InboxIncrementRejectedCount(activityType, reason string) {
+}
+
+// WitnessOfferIncrementExpiredCount increments the number of expired witness offers that were removed
+// from the witness offer store.
+func (m *MetricsProvider) WitnessOfferIncrementExpiredCount(count int) {
+}
+
 // WriteAnchorTime records the time it takes to write an anchor credential and post an 'Offer' activity.
 func (m *MetricsProvider) WriteAnchorTime(value time.Duration) {
 }
@@ -100,6 +110,18 @@ func (m *MetricsProvider) ProcessAnchorTime(value time.Duration) {
 func (m *MetricsProvider) ProcessDIDTime(value time.Duration) {
 }
 
+// ObserverIncrementReconnectCount increments the number of times the Observer has resubscribed to a topic.
+func (m *MetricsProvider) ObserverIncrementReconnectCount() {
+}
+
+// ObserverIncrementDroppedAnchorEventCount increments the number of processed-anchor events that were dropped.
+func (m *MetricsProvider) ObserverIncrementDroppedAnchorEventCount() {
+}
+
+// ObserverIncrementSkippedAnchorCount increments the number of anchors skipped due to an unrecognized namespace.
+func (m *MetricsProvider) ObserverIncrementSkippedAnchorCount() {
+}
+
 // CASWriteTime records the time it takes to write a document to CAS.
 func (m *MetricsProvider) CASWriteTime(value time.Duration) {
 }
@@ -114,6 +136,18 @@ func (m *MetricsProvider) CASResolveTime(value time.Duration) {
 func (m *MetricsProvider) WitnessAnchorCredentialTime(value time.Duration) {
 }
 
+// WitnessPendingAnchorCount records the number of anchors that are waiting for sufficient witness proofs.
+func (m *MetricsProvider) WitnessPendingAnchorCount(count int) {
+}
+
+// ParentAnchorTraversalDepth records the depth of a traversal of the unprocessed ancestors of an anchor.
+func (m *MetricsProvider) ParentAnchorTraversalDepth(depth int) {
+}
+
+// ParentAnchorTraversalResolvedCount records the number of parent anchors resolved in a traversal.
+func (m *MetricsProvider) ParentAnchorTraversalResolvedCount(count int) {
+}
+
 // DocumentCreateUpdateTime records the time it takes the REST handler to process a create/update operation.
 func (m *MetricsProvider) DocumentCreateUpdateTime(value time.Duration) {
 }
@@ -138,6 +172,10 @@ func (m *MetricsProvider) CASIncrementCacheHitCount() {
 func (m *MetricsProvider) CASReadTime(casType string, value time.Duration) {
 }
 
+// CASBytesStored records the number of bytes written to local CAS storage.
+func (m *MetricsProvider) CASBytesStored(value int) {
+}
+
 // BatchSize records the size of an operation batch.
 func (m *MetricsProvider) BatchSize(float64) {
 }
@@ -47,6 +47,9 @@ func (nm NoOptMetrics) CASWriteTime(value time.Duration) {}
 // CASReadTime records the time it takes to read a document from CAS storage.
 func (nm NoOptMetrics) CASReadTime(casType string, value time.Duration) {}
 
+// CASBytesStored records the number of bytes written to local CAS storage.
+func (nm NoOptMetrics) CASBytesStored(value int) {}
+
 // PutPublishedOperations records the time to store published operations.
 func (nm NoOptMetrics) PutPublishedOperations(duration time.Duration) {}
 
@@ -79,6 +82,15 @@ func (nm NoOptMetrics) SignerAddLinkedDataProof(value time.Duration) {}
 // and the end time is the time that the witness policy is satisfied.
 func (nm NoOptMetrics) WitnessAnchorCredentialTime(duration time.Duration) {}
 
+// WitnessPendingAnchorCount records the number of anchors that are waiting for sufficient witness proofs.
+func (nm NoOptMetrics) WitnessPendingAnchorCount(count int) {}
+
+// ParentAnchorTraversalDepth records the depth of a traversal of the unprocessed ancestors of an anchor.
+func (nm NoOptMetrics) ParentAnchorTraversalDepth(depth int) {}
+
+// ParentAnchorTraversalResolvedCount records the number of parent anchors resolved in a traversal.
+func (nm NoOptMetrics) ParentAnchorTraversalResolvedCount(count int) {}
+
 // WitnessAddProofVctNil records vct witness.
 func (nm NoOptMetrics) WitnessAddProofVctNil(value time.Duration) {}
 
@@ -106,9 +118,26 @@ func (nm NoOptMetrics) ProcessAnchorTime(value time.Duration) {}
 // ProcessDIDTime records the time it takes for the Observer to process a DID.
 func (nm NoOptMetrics) ProcessDIDTime(value time.Duration) {}
 
+// ObserverIncrementReconnectCount increments the number of times the Observer has resubscribed to a topic.
+func (nm NoOptMetrics) ObserverIncrementReconnectCount() {}
+
+// ObserverIncrementDroppedAnchorEventCount increments the number of processed-anchor events that were dropped.
+func (nm NoOptMetrics) ObserverIncrementDroppedAnchorEventCount() {}
+
+// ObserverIncrementSkippedAnchorCount increments the number of anchors skipped due to an unrecognized namespace.
+func (nm NoOptMetrics) ObserverIncrementSkippedAnchorCount() {}
+
 // InboxHandlerTime records the time it takes to handle an activity posted to the inbox.
 func (nm NoOptMetrics) InboxHandlerTime(activityType string, value time.Duration) {}
 
+// InboxIncrementRejectedCount increments the number of activities of the given type rejected by the inbox
+// for the given reason.
+func (nm NoOptMetrics) InboxIncrementRejectedCount(activityType, reason string) {}
+
+// WitnessOfferIncrementExpiredCount increments the number of expired witness offers that were removed
+// from the witness offer store.
+func (nm NoOptMetrics) WitnessOfferIncrementExpiredCount(count int) {}
+
 // OutboxPostTime records the time it takes to post a message to the outbox.
 func (nm NoOptMetrics) OutboxPostTime(value time.Duration) {}
 
@@ -288,3 +317,6 @@ func (nm NoOptMetrics) DBBatchTime(dbType string, duration time.Duration) {}
 
 // CASWriteSize the size (in bytes) of the data written to CAS for the given model type.
 func (nm NoOptMetrics) CASWriteSize(dataType string, size int) {}
+
+// CircuitBreakerStateChanged records a state transition of the circuit for the given host.
+func (nm NoOptMetrics) CircuitBreakerStateChanged(name, host, state string) {}
@@ -83,6 +83,8 @@ type PromMetrics struct {
 	apOutboxResolveInboxesTime prometheus.Histogram
 	apInboxHandlerTimes        map[string]prometheus.Histogram
 	apOutboxActivityCounts     map[string]prometheus.Counter
+	apInboxRejectedCounts      map[string]map[string]prometheus.Counter
+	apExpiredWitnessOfferCount prometheus.Counter
 
 	anchorWriteTime                          prometheus.Histogram
 	anchorWitnessTime                        prometheus.Histogram
@@ -99,6 +101,9 @@ type PromMetrics struct {
 	anchorWriteStoreTime                     prometheus.Histogram
 	anchorWriteSignLocalWatchTime            prometheus.Histogram
 	anchorWriteResolveHostMetaLinkTime       prometheus.Histogram
+	anchorPendingWitnessCount                prometheus.Gauge
+	anchorParentTraversalDepth               prometheus.Histogram
+	anchorParentTraversalResolvedCount       prometheus.Histogram
 
 	opqueueAddOperationTime  prometheus.Histogram
 	opqueueBatchCutTime      prometheus.Histogram
@@ -107,11 +112,15 @@ type PromMetrics struct {
 
 	observerProcessAnchorTime prometheus.Histogram
 	observerProcessDIDTime    prometheus.Histogram
+	observerReconnectCount    prometheus.Counter
+	observerDroppedEventCount prometheus.Counter
+	observerSkippedCount      prometheus.Counter
 
 	casWriteTime     prometheus.Histogram
 	casResolveTime   prometheus.Histogram
 	casCacheHitCount prometheus.Counter
 	casReadTimes     map[string]prometheus.Histogram
+	casBytesStored   prometheus.Counter
 
 	docCreateUpdateTime prometheus.Histogram
 	docResolveTime      prometheus.Histogram
@@ -173,11 +182,15 @@ type PromMetrics struct {
 	awsExportPublicKeyTime  prometheus.Histogram
 	awsVerifyCount          prometheus.Counter
 	awsVerifyTime           prometheus.Histogram
+
+	circuitBreakerStateTransitions *prometheus.CounterVec
+	circuitBreakerOpen             *prometheus.GaugeVec
 }
 
 // newMetrics creates instance of prometheus metrics.
 func newMetrics() metrics.Metrics {
 	activityTypes := []string{"Create", "Announce", "Offer", "Like", "Follow", "InviteWitness", "Accept", "Reject"}
+	inboxRejectReasons := []string{metrics.InboxRejectReasonTypeNotAccepted}
 	dbTypes := []string{"CouchDB", "MongoDB"}
 	modelTypes := []string{"core index", "core proof", "provisional proof", "chunk", "provisional index"}
 
@@ -192,6 +205,9 @@ func newMetrics() metrics.Metrics {
 		anchorWriteGetPreviousAnchorsGetBulkTime:     newAnchorWriteGetPreviousAnchorsGetBulkTime(),
 		anchorWriteGetPreviousAnchorsTime:            newAnchorWriteGetPreviousAnchorsTime(),
 		anchorWitnessTime:                            newAnchorWitnessTime(),
+		anchorPendingWitnessCount:                    newAnchorPendingWitnessCount(),
+		anchorParentTraversalDepth:                   newAnchorParentTraversalDepth(),
+		anchorParentTraversalResolvedCount:           newAnchorParentTraversalResolvedCount(),
 		anchorProcessWitnessedTime:                   newAnchorProcessWitnessedTime(),
 		anchorWriteSignWithLocalWitnessTime:          newAnchorWriteSignWithLocalWitnessTime(),
 		anchorWriteSignWithServerKeyTime:             newAnchorWriteSignWithServerKeyTime(),
@@ -205,14 +221,20 @@ func newMetrics() metrics.Metrics {
 		opqueueBatchSize:                             newOpQueueBatchSize(),
 		observerProcessAnchorTime:                    newObserverProcessAnchorTime(),
 		observerProcessDIDTime:                       newObserverProcessDIDTime(),
+		observerReconnectCount:                       newObserverReconnectCount(),
+		observerDroppedEventCount:                    newObserverDroppedAnchorEventCount(),
+		observerSkippedCount:                         newObserverSkippedAnchorCount(),
 		casWriteTime:                                 newCASWriteTime(),
 		casResolveTime:                               newCASResolveTime(),
 		casReadTimes:                                 newCASReadTimes(),
 		casCacheHitCount:                             newCASCacheHitCount(),
+		casBytesStored:                               newCASBytesStored(),
 		docCreateUpdateTime:                          newDocCreateUpdateTime(),
 		docResolveTime:                               newDocResolveTime(),
 		apInboxHandlerTimes:                          newInboxHandlerTimes(activityTypes),
 		apOutboxActivityCounts:                       newOutboxActivityCounts(activityTypes),
+		apInboxRejectedCounts:                        newInboxRejectedCounts(activityTypes, inboxRejectReasons),
+		apExpiredWitnessOfferCount:                   newExpiredWitnessOfferCount(),
 		dbPutTimes:                                   newDBPutTime(dbTypes),
 		dbGetTimes:                                   newDBGetTime(dbTypes),
 		dbGetTagsTimes:                               newDBGetTagsTime(dbTypes),
@@ -263,6 +285,8 @@ func newMetrics() metrics.Metrics {
 		awsExportPublicKeyTime:                       newAWSExportPublicKeyTime(),
 		awsVerifyCount:                               newAWSVerifyCount(),
 		awsVerifyTime:                                newAWSVerifyTime(),
+		circuitBreakerStateTransitions:               newCircuitBreakerStateTransitions(),
+		circuitBreakerOpen:                           newCircuitBreakerOpen(),
 	}
 
 	registerMetrics(pm)
@@ -272,15 +296,17 @@ func newMetrics() metrics.Metrics {
 
 func registerMetrics(pm *PromMetrics) { //nolint:cyclop
 	prometheus.MustRegister(
-		pm.apOutboxPostTime, pm.apOutboxResolveInboxesTime,
+		pm.apOutboxPostTime, pm.apOutboxResolveInboxesTime, pm.apExpiredWitnessOfferCount,
 		pm.anchorWriteTime, pm.anchorWitnessTime, pm.anchorProcessWitnessedTime, pm.anchorWriteBuildCredTime,
 		pm.anchorWriteGetWitnessesTime, pm.anchorWriteSignCredTime, pm.anchorWritePostOfferActivityTime,
 		pm.anchorWriteGetPreviousAnchorsGetBulkTime, pm.anchorWriteGetPreviousAnchorsTime,
 		pm.anchorWriteSignWithLocalWitnessTime, pm.anchorWriteSignWithServerKeyTime, pm.anchorWriteSignLocalWitnessLogTime,
-		pm.anchorWriteStoreTime, pm.anchorWriteSignLocalWatchTime,
+		pm.anchorWriteStoreTime, pm.anchorWriteSignLocalWatchTime, pm.anchorPendingWitnessCount,
+		pm.anchorParentTraversalDepth, pm.anchorParentTraversalResolvedCount,
 		pm.opqueueAddOperationTime, pm.opqueueBatchCutTime, pm.opqueueBatchRollbackTime,
-		pm.opqueueBatchSize, pm.observerProcessAnchorTime, pm.observerProcessDIDTime,
-		pm.casWriteTime, pm.casResolveTime, pm.casCacheHitCount,
+		pm.opqueueBatchSize, pm.observerProcessAnchorTime, pm.observerProcessDIDTime, pm.observerReconnectCount,
+		pm.observerDroppedEventCount, pm.observerSkippedCount,
+		pm.casWriteTime, pm.casResolveTime, pm.casCacheHitCount, pm.casBytesStored,
 		pm.docCreateUpdateTime, pm.docResolveTime,
 		pm.vctWitnessAddProofVCTNilTimes, pm.vctWitnessAddVCTimes, pm.vctWitnessAddProofTimes,
 		pm.vctWitnessAddWebFingerTimes, pm.vctWitnessVerifyVCTimes, pm.vctAddProofParseCredentialTimes,
@@ -338,6 +364,12 @@ func registerMetrics(pm *PromMetrics) { //nolint:cyclop
 		prometheus.MustRegister(c)
 	}
 
+	for _, counters := range pm.apInboxRejectedCounts {
+		for _, c := range counters {
+			prometheus.MustRegister(c)
+		}
+	}
+
 	for _, c := range pm.casReadTimes {
 		prometheus.MustRegister(c)
 	}
@@ -345,6 +377,8 @@ func registerMetrics(pm *PromMetrics) { //nolint:cyclop
 	for _, c := range pm.coreCASWriteSize {
 		prometheus.MustRegister(c)
 	}
+
+	prometheus.MustRegister(pm.circuitBreakerStateTransitions, pm.circuitBreakerOpen)
 }
 
 // OutboxPostTime records the time it takes to post a message to the outbox.
@@ -370,6 +404,14 @@ func (pm *PromMetrics) InboxHandlerTime(activityType string, value time.Duration
 	logger.Debug("InboxHandler time for activity", logfields.WithActivityType(activityType), log.WithDuration(value))
 }
 
+// InboxIncrementRejectedCount increments the number of activities of the given type rejected by the inbox
+// for the given reason.
+func (pm *PromMetrics) InboxIncrementRejectedCount(activityType, reason string) {
+	if c, ok := pm.apInboxRejectedCounts[reason][activityType]; ok {
+		c.Inc()
+	}
+}
+
 // OutboxIncrementActivityCount increments the number of activities of the given type posted to the outbox.
 func (pm *PromMetrics) OutboxIncrementActivityCount(activityType string) {
 	if c, ok := pm.apOutboxActivityCounts[activityType]; ok {
@@ -377,6 +419,12 @@ func (pm *PromMetrics) OutboxIncrementActivityCount(activityType string) {
 	}
 }
 
+// WitnessOfferIncrementExpiredCount increments the number of expired witness offers that were removed
+// from the witness offer store.
+func (pm *PromMetrics) WitnessOfferIncrementExpiredCount(count int) {
+	pm.apExpiredWitnessOfferCount.Add(float64(count))
+}
+
 // WriteAnchorTime records the time it takes to write an anchor credential and post an 'Offer' activity.
 func (pm *PromMetrics) WriteAnchorTime(value time.Duration) {
 	pm.anchorWriteTime.Observe(value.Seconds())
@@ -477,6 +525,24 @@ func (pm *PromMetrics) WitnessAnchorCredentialTime(value time.Duration) {
 	logger.Debug("WitnessAnchorCredential time", log.WithDuration(value))
 }
 
+// WitnessPendingAnchorCount records the number of anchors that are waiting for sufficient witness proofs
+// in order to satisfy the witness policy.
+func (pm *PromMetrics) WitnessPendingAnchorCount(count int) {
+	pm.anchorPendingWitnessCount.Set(float64(count))
+}
+
+// ParentAnchorTraversalDepth records the depth of a traversal of the unprocessed ancestors (parents,
+// grandparents, etc.) of an anchor received from another server.
+func (pm *PromMetrics) ParentAnchorTraversalDepth(depth int) {
+	pm.anchorParentTraversalDepth.Observe(float64(depth))
+}
+
+// ParentAnchorTraversalResolvedCount records the number of parent anchors which were resolved in a traversal
+// of the unprocessed ancestors of an anchor received from another server.
+func (pm *PromMetrics) ParentAnchorTraversalResolvedCount(count int) {
+	pm.anchorParentTraversalResolvedCount.Observe(float64(count))
+}
+
 // ProcessWitnessedAnchorCredentialTime records the time it takes to process a witnessed anchor credential
 // by publishing it to the Observer and posting a 'Create' activity.
 func (pm *PromMetrics) ProcessWitnessedAnchorCredentialTime(value time.Duration) {
@@ -530,6 +596,24 @@ func (pm *PromMetrics) ProcessDIDTime(value time.Duration) {
 	logger.Debug("ProcessDID time", log.WithDuration(value))
 }
 
+// ObserverIncrementReconnectCount increments the number of times the Observer has resubscribed to a topic
+// after the subscription was closed unexpectedly.
+func (pm *PromMetrics) ObserverIncrementReconnectCount() {
+	pm.observerReconnectCount.Inc()
+}
+
+// ObserverIncrementDroppedAnchorEventCount increments the number of processed-anchor events that were dropped
+// because the event sink buffer was full.
+func (pm *PromMetrics) ObserverIncrementDroppedAnchorEventCount() {
+	pm.observerDroppedEventCount.Inc()
+}
+
+// ObserverIncrementSkippedAnchorCount increments the number of anchors that were skipped by the Observer
+// because the namespace of the anchor isn't recognized.
+func (pm *PromMetrics) ObserverIncrementSkippedAnchorCount() {
+	pm.observerSkippedCount.Inc()
+}
+
 // CASWriteTime records the time it takes to write a document to CAS.
 func (pm *PromMetrics) CASWriteTime(value time.Duration) {
 	pm.casWriteTime.Observe(value.Seconds())
@@ -556,6 +640,11 @@ func (pm *PromMetrics) CASReadTime(casType string, value time.Duration) {
 	}
 }
 
+// CASBytesStored records the number of bytes written to local CAS storage.
+func (pm *PromMetrics) CASBytesStored(value int) {
+	pm.casBytesStored.Add(float64(value))
+}
+
 // DocumentCreateUpdateTime records the time it takes the REST handler to process a create/update operation.
 func (pm *PromMetrics) DocumentCreateUpdateTime(value time.Duration) {
 	pm.docCreateUpdateTime.Observe(value.Seconds())
@@ -922,6 +1011,18 @@ func (pm *PromMetrics) VerifyTime(value time.Duration) {
 	logger.Debug("aws verify time", log.WithDuration(value))
 }
 
+// CircuitBreakerStateChanged records a state transition of the circuit for the given host.
+func (pm *PromMetrics) CircuitBreakerStateChanged(name, host, state string) {
+	pm.circuitBreakerStateTransitions.WithLabelValues(name, host, state).Inc()
+
+	open := float64(0)
+	if state != metrics.CircuitBreakerStateClosed {
+		open = 1
+	}
+
+	pm.circuitBreakerOpen.WithLabelValues(name, host).Set(open)
+}
+
 func newCounter(subsystem, name, help string, labels prometheus.Labels) prometheus.Counter {
 	return prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace:   metrics.Namespace,
@@ -952,6 +1053,19 @@ func newHistogram(subsystem, name, help string, labels prometheus.Labels) promet
 	})
 }
 
+// newCountHistogram returns a histogram for counts (as opposed to durations) with exponential buckets
+// from 1 to 512.
+func newCountHistogram(subsystem, name, help string, labels prometheus.Labels) prometheus.Histogram {
+	return prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace:   metrics.Namespace,
+		Subsystem:   subsystem,
+		Name:        name,
+		Help:        help,
+		ConstLabels: labels,
+		Buckets:     prometheus.ExponentialBuckets(1, 2, 10),
+	})
+}
+
 func newOutboxPostTime() prometheus.Histogram {
 	return newHistogram(
 		metrics.ActivityPub, metrics.ApPostTimeMetric,
@@ -982,6 +1096,32 @@ func newInboxHandlerTimes(activityTypes []string) map[string]prometheus.Histogra
 	return counters
 }
 
+func newInboxRejectedCounts(activityTypes, reasons []string) map[string]map[string]prometheus.Counter {
+	counters := make(map[string]map[string]prometheus.Counter)
+
+	for _, reason := range reasons {
+		counters[reason] = make(map[string]prometheus.Counter)
+
+		for _, activityType := range activityTypes {
+			counters[reason][activityType] = newCounter(
+				metrics.ActivityPub, metrics.ApInboxRejectedCounterMetric,
+				"The number of activities rejected by the inbox.",
+				prometheus.Labels{"type": activityType, "reason": reason},
+			)
+		}
+	}
+
+	return counters
+}
+
+func newExpiredWitnessOfferCount() prometheus.Counter {
+	return newCounter(
+		metrics.ActivityPub, metrics.ApExpiredWitnessOfferMetric,
+		"The number of expired witness offers that were removed from the witness offer store.",
+		nil,
+	)
+}
+
 func newOutboxActivityCounts(activityTypes []string) map[string]prometheus.Counter {
 	counters := make(map[string]prometheus.Counter)
 
@@ -1014,6 +1154,32 @@ func newAnchorWitnessTime() prometheus.Histogram {
 	)
 }
 
+func newAnchorPendingWitnessCount() prometheus.Gauge {
+	return newGauge(
+		metrics.Anchor, metrics.AnchorPendingWitnessCountMetric,
+		"The number of anchors that are waiting for sufficient witness proofs in order to satisfy the witness policy.",
+		nil,
+	)
+}
+
+func newAnchorParentTraversalDepth() prometheus.Histogram {
+	return newCountHistogram(
+		metrics.Anchor, metrics.AnchorParentTraversalDepthMetric,
+		"The depth of a traversal of the unprocessed ancestors (parents, grandparents, etc.) of an anchor "+
+			"received from another server, i.e. the number of generations of ancestors which needed to be processed.",
+		nil,
+	)
+}
+
+func newAnchorParentTraversalResolvedCount() prometheus.Histogram {
+	return newCountHistogram(
+		metrics.Anchor, metrics.AnchorParentTraversalResolvedCountMetric,
+		"The number of parent anchors which were resolved in a traversal of the unprocessed ancestors of an anchor "+
+			"received from another server.",
+		nil,
+	)
+}
+
 func newAnchorProcessWitnessedTime() prometheus.Histogram {
 	return newHistogram(
 		metrics.Anchor, metrics.AnchorProcessWitnessedMetric,
@@ -1169,6 +1335,30 @@ func newObserverProcessDIDTime() prometheus.Histogram {
 	)
 }
 
+func newObserverDroppedAnchorEventCount() prometheus.Counter {
+	return newCounter(
+		metrics.Observer, metrics.ObserverDroppedAnchorEventCountMetric,
+		"The number of processed-anchor events that were dropped because the event sink buffer was full.",
+		nil,
+	)
+}
+
+func newObserverSkippedAnchorCount() prometheus.Counter {
+	return newCounter(
+		metrics.Observer, metrics.ObserverSkippedAnchorCountMetric,
+		"The number of anchors that were skipped because the namespace of the anchor isn't recognized.",
+		nil,
+	)
+}
+
+func newObserverReconnectCount() prometheus.Counter {
+	return newCounter(
+		metrics.Observer, metrics.ObserverReconnectCountMetric,
+		"The number of times the Observer has resubscribed to a topic after the subscription was closed unexpectedly.",
+		nil,
+	)
+}
+
 func newCASWriteTime() prometheus.Histogram {
 	return newHistogram(
 		metrics.Cas, metrics.CasWriteTimeMetric,
@@ -1207,6 +1397,14 @@ func newCASReadTimes() map[string]prometheus.Histogram {
 	return times
 }
 
+func newCASBytesStored() prometheus.Counter {
+	return newCounter(
+		metrics.Cas, metrics.CasBytesStoredMetric,
+		"The number of bytes written to local CAS storage.",
+		nil,
+	)
+}
+
 func newDocCreateUpdateTime() prometheus.Histogram {
 	return newHistogram(
 		metrics.Document, metrics.DocCreateUpdateTimeMetric,
@@ -1670,3 +1868,21 @@ func newAWSVerifyTime() prometheus.Histogram {
 		nil,
 	)
 }
+
+func newCircuitBreakerStateTransitions() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.CircuitBreaker,
+		Name:      metrics.CircuitBreakerStateTransitionsMetric,
+		Help:      "The number of circuit breaker state transitions for a remote host.",
+	}, []string{"name", "host", "state"})
+}
+
+func newCircuitBreakerOpen() *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.CircuitBreaker,
+		Name:      metrics.CircuitBreakerOpenMetric,
+		Help:      "Indicates whether the circuit for a remote host is open or half-open (1) or closed (0).",
+	}, []string{"name", "host"})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
+	"github.com/trustbloc/orb/pkg/observability/metrics"
 	"github.com/trustbloc/orb/pkg/observability/metrics/prometheus/mocks"
 )
 
@@ -34,6 +35,8 @@ func TestMetrics(t *testing.T) {
 
 	t.Run("ActivityPub", func(t *testing.T) {
 		require.NotPanics(t, func() { m.InboxHandlerTime("Create", time.Second) })
+		require.NotPanics(t, func() { m.InboxIncrementRejectedCount("Follow", metrics.InboxRejectReasonTypeNotAccepted) })
+		require.NotPanics(t, func() { m.WitnessOfferIncrementExpiredCount(2) })
 		require.NotPanics(t, func() { m.OutboxPostTime(time.Second) })
 		require.NotPanics(t, func() { m.OutboxResolveInboxesTime(time.Second) })
 		require.NotPanics(t, func() { m.WriteAnchorTime(time.Second) })
@@ -46,6 +49,9 @@ func TestMetrics(t *testing.T) {
 		require.NotPanics(t, func() { m.WriteAnchorSignWithLocalWitnessTime(time.Second) })
 		require.NotPanics(t, func() { m.WriteAnchorSignWithServerKeyTime(time.Second) })
 		require.NotPanics(t, func() { m.WitnessAnchorCredentialTime(time.Second) })
+		require.NotPanics(t, func() { m.WitnessPendingAnchorCount(3) })
+		require.NotPanics(t, func() { m.ParentAnchorTraversalDepth(2) })
+		require.NotPanics(t, func() { m.ParentAnchorTraversalResolvedCount(3) })
 		require.NotPanics(t, func() { m.WriteAnchorSignLocalWitnessLogTime(time.Second) })
 		require.NotPanics(t, func() { m.WriteAnchorStoreTime(time.Second) })
 		require.NotPanics(t, func() { m.WriteAnchorSignLocalWatchTime(time.Second) })
@@ -57,10 +63,14 @@ func TestMetrics(t *testing.T) {
 		require.NotPanics(t, func() { m.BatchSize(float64(500)) })
 		require.NotPanics(t, func() { m.ProcessAnchorTime(time.Second) })
 		require.NotPanics(t, func() { m.ProcessDIDTime(time.Second) })
+		require.NotPanics(t, func() { m.ObserverIncrementReconnectCount() })
+		require.NotPanics(t, func() { m.ObserverIncrementDroppedAnchorEventCount() })
+		require.NotPanics(t, func() { m.ObserverIncrementSkippedAnchorCount() })
 		require.NotPanics(t, func() { m.CASWriteTime(time.Second) })
 		require.NotPanics(t, func() { m.CASResolveTime(time.Second) })
 		require.NotPanics(t, func() { m.CASIncrementCacheHitCount() })
 		require.NotPanics(t, func() { m.CASReadTime("local", time.Second) })
+		require.NotPanics(t, func() { m.CASBytesStored(1000) })
 		require.NotPanics(t, func() { m.DocumentCreateUpdateTime(time.Second) })
 		require.NotPanics(t, func() { m.DocumentResolveTime(time.Second) })
 		require.NotPanics(t, func() { m.OutboxIncrementActivityCount("Create") })
@@ -115,6 +125,12 @@ func TestMetrics(t *testing.T) {
 		require.NotPanics(t, func() { m.VerifyCount() })
 		require.NotPanics(t, func() { m.VerifyTime(time.Second) })
 	})
+
+	t.Run("Circuit breaker", func(t *testing.T) {
+		require.NotPanics(t, func() { m.CircuitBreakerStateChanged("webcas", "orb.domain1.com", "open") })
+		require.NotPanics(t, func() { m.CircuitBreakerStateChanged("webcas", "orb.domain1.com", "half-open") })
+		require.NotPanics(t, func() { m.CircuitBreakerStateChanged("webcas", "orb.domain1.com", "closed") })
+	})
 }
 
 func TestNewGauge(t *testing.T) {
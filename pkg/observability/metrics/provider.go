@@ -26,6 +26,11 @@ const (
 	ApResolveInboxesTimeMetric    = "outbox_resolve_inboxes_seconds"
 	ApInboxHandlerTimeMetric      = "inbox_handler_seconds"
 	ApOutboxActivityCounterMetric = "outbox_count"
+	ApInboxRejectedCounterMetric  = "inbox_rejected_count"
+	ApExpiredWitnessOfferMetric   = "expired_witness_offer_count"
+
+	// InboxRejectReasonTypeNotAccepted is the reject reason for an activity whose type isn't accepted by the inbox.
+	InboxRejectReasonTypeNotAccepted = "type_not_accepted"
 
 	// Anchor Anchor.
 	Anchor                                         = "anchor"
@@ -44,6 +49,9 @@ const (
 	AnchorWriteSignLocalWitnessLogTimeMetric       = "write_sign_local_witness_log_seconds"
 	AnchorWriteSignLocalWatchTimeMetric            = "write_sign_local_watch_seconds"
 	AnchorWriteResolveHostMetaLinkTimeMetric       = "write_resolve_host_meta_link_seconds"
+	AnchorPendingWitnessCountMetric                = "pending_witness_count"
+	AnchorParentTraversalDepthMetric               = "parent_traversal_depth"
+	AnchorParentTraversalResolvedCountMetric       = "parent_traversal_resolved_count"
 
 	// OperationQueue Operation queue.
 	OperationQueue                 = "opqueue"
@@ -53,9 +61,12 @@ const (
 	OpQueueBatchSizeMetric         = "batch_size"
 
 	// Observer Observer.
-	Observer                        = "observer"
-	ObserverProcessAnchorTimeMetric = "process_anchor_seconds"
-	ObserverProcessDIDTimeMetric    = "process_did_seconds"
+	Observer                              = "observer"
+	ObserverProcessAnchorTimeMetric       = "process_anchor_seconds"
+	ObserverProcessDIDTimeMetric          = "process_did_seconds"
+	ObserverReconnectCountMetric          = "reconnect_count"
+	ObserverDroppedAnchorEventCountMetric = "dropped_anchor_event_count"
+	ObserverSkippedAnchorCountMetric      = "skipped_anchor_count"
 
 	// Cas CAS.
 	Cas                    = "cas"
@@ -63,6 +74,7 @@ const (
 	CasResolveTimeMetric   = "resolve_seconds"
 	CasCacheHitCountMetric = "cache_hit_count"
 	CasReadTimeMetric      = "read_seconds"
+	CasBytesStoredMetric   = "bytes_stored"
 
 	// Document handler.
 	Document                  = "document"
@@ -150,6 +162,14 @@ const (
 	AwsExportPublicKeyTimeMetric  = "export_publickey_seconds"
 	AwsVerifyCountMetric          = "Verify_count"
 	AwsVerifyTimeMetric           = "Verify_seconds"
+
+	// CircuitBreaker Circuit breaker.
+	CircuitBreaker                       = "circuitbreaker"
+	CircuitBreakerStateTransitionsMetric = "state_transitions_count"
+	CircuitBreakerOpenMetric             = "open"
+
+	// CircuitBreakerStateClosed is the state of a circuit which allows requests.
+	CircuitBreakerStateClosed = "closed"
 )
 
 // Provider is an interface for metrics provider.
@@ -169,6 +189,7 @@ type Metrics interface {
 	CASIncrementCacheHitCount()
 	CASWriteTime(value time.Duration)
 	CASReadTime(casType string, value time.Duration)
+	CASBytesStored(value int)
 	PutPublishedOperations(duration time.Duration)
 	GetPublishedOperations(duration time.Duration)
 	CASResolveTime(value time.Duration)
@@ -179,6 +200,9 @@ type Metrics interface {
 	SignerGetKey(value time.Duration)
 	SignerAddLinkedDataProof(value time.Duration)
 	WitnessAnchorCredentialTime(duration time.Duration)
+	WitnessPendingAnchorCount(count int)
+	ParentAnchorTraversalDepth(depth int)
+	ParentAnchorTraversalResolvedCount(count int)
 	WitnessAddProofVctNil(value time.Duration)
 	WitnessAddVC(value time.Duration)
 	WitnessAddProof(value time.Duration)
@@ -188,10 +212,15 @@ type Metrics interface {
 	AddProofSign(value time.Duration)
 	ProcessAnchorTime(value time.Duration)
 	ProcessDIDTime(value time.Duration)
+	ObserverIncrementReconnectCount()
+	ObserverIncrementDroppedAnchorEventCount()
+	ObserverIncrementSkippedAnchorCount()
 	InboxHandlerTime(activityType string, value time.Duration)
+	InboxIncrementRejectedCount(activityType, reason string)
 	OutboxPostTime(value time.Duration)
 	OutboxResolveInboxesTime(value time.Duration)
 	OutboxIncrementActivityCount(activityType string)
+	WitnessOfferIncrementExpiredCount(count int)
 	WriteAnchorTime(value time.Duration)
 	WriteAnchorBuildCredentialTime(value time.Duration)
 	WriteAnchorGetWitnessesTime(value time.Duration)
@@ -247,4 +276,5 @@ type Metrics interface {
 	DBDeleteTime(dbType string, duration time.Duration)
 	DBBatchTime(dbType string, duration time.Duration)
 	CASWriteSize(dataType string, size int)
+	CircuitBreakerStateChanged(name, host, state string)
 }
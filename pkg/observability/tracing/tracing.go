@@ -14,6 +14,7 @@ import (
 	"github.com/trustbloc/logutil-go/pkg/log"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/jaeger"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -39,6 +40,8 @@ const (
 	SubsystemDocument       Subsystem = "document"
 	SubsystemOperationQueue Subsystem = "context/opqueue"
 	SubsystemAMQP           Subsystem = "pubsub/amqp"
+	SubsystemCAS            Subsystem = "cas"
+	SubsystemObserver       Subsystem = "observer"
 )
 
 // Tracing attributes.
@@ -49,6 +52,8 @@ const (
 	AttributeOutboxMessageType attribute.Key = "orb.outboxMessageType"
 	AttributeAnchorEventURI    attribute.Key = "orb.anchorEventURI"
 	AttributeDIDSuffix         attribute.Key = "orb.didSuffix"
+	AttributeResourceHash      attribute.Key = "orb.resourceHash"
+	AttributeCASSource         attribute.Key = "orb.casSource"
 )
 
 const tracerRootName = "github.com/trustbloc/orb"
@@ -142,6 +147,16 @@ func DIDSuffixAttribute(value string) attribute.KeyValue {
 	return attribute.KeyValue{Key: AttributeDIDSuffix, Value: attribute.StringValue(value)}
 }
 
+// ResourceHashAttribute returns the orb.resourceHash tracing attribute.
+func ResourceHashAttribute(value string) attribute.KeyValue {
+	return attribute.KeyValue{Key: AttributeResourceHash, Value: attribute.StringValue(value)}
+}
+
+// CASSourceAttribute returns the orb.casSource tracing attribute.
+func CASSourceAttribute(value string) attribute.KeyValue {
+	return attribute.KeyValue{Key: AttributeCASSource, Value: attribute.StringValue(value)}
+}
+
 // Span is a wrapper around a trace.Span that ensures it is started only once
 // and ended only if it was started.
 type Span struct {
@@ -173,6 +188,15 @@ func (s *Span) End(opts ...trace.SpanEndOption) {
 	}
 }
 
+// EndSpan ends the given span. If the given error isn't nil then the status of the span is set to error.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
 // newJaegerTracerProvider returns an OpenTelemetry Provider configured to use
 // the Jaeger exporter that will send spans to the provided url. The returned
 // Provider will also use a Resource configured with all the information
@@ -8,10 +8,14 @@ package tracing
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestInitialize(t *testing.T) {
@@ -47,6 +51,8 @@ func TestAttributes(t *testing.T) {
 		messageType  = "deliver"
 		eventURI     = "https://localhost/event"
 		suffix       = "scsdcsdcsd"
+		resourceHash = "uEiAbc"
+		casSource    = "ipfs"
 	)
 
 	require.Equal(t, messageUUID, MessageUUIDAttribute(messageUUID).Value.AsString())
@@ -55,6 +61,8 @@ func TestAttributes(t *testing.T) {
 	require.Equal(t, messageType, OutboxMessageTypeAttribute(messageType).Value.AsString())
 	require.Equal(t, eventURI, AnchorEventURIAttribute(eventURI).Value.AsString())
 	require.Equal(t, suffix, DIDSuffixAttribute(suffix).Value.AsString())
+	require.Equal(t, resourceHash, ResourceHashAttribute(resourceHash).Value.AsString())
+	require.Equal(t, casSource, CASSourceAttribute(casSource).Value.AsString())
 }
 
 func TestSpan(t *testing.T) {
@@ -89,3 +97,21 @@ func TestSpan(t *testing.T) {
 		})
 	})
 }
+
+func TestEndSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	tracer := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "span1")
+	EndSpan(span, nil)
+
+	_, span = tracer.Start(context.Background(), "span2")
+	EndSpan(span, errors.New("injected error"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+	require.Equal(t, "injected error", spans[1].Status().Description)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+const (
+	defaultAnchorHookPoolSize = 5
+	defaultAnchorHookTimeout  = 10 * time.Second
+)
+
+// AnchorProcessedHook is invoked after an anchor was successfully processed. It allows external systems
+// (e.g. indexers, analytics or notification systems) to be informed of processed anchors. Hooks are invoked
+// asynchronously on a bounded worker pool, so there are no ordering guarantees. An error returned by a hook
+// is logged and doesn't affect the processing of the anchor. The given context is cancelled when the hook
+// times out.
+type AnchorProcessedHook func(ctx context.Context, event *ProcessedAnchorEvent) error
+
+type hookInvocation struct {
+	ctx   context.Context
+	hook  AnchorProcessedHook
+	event *ProcessedAnchorEvent
+}
+
+// anchorHookDispatcher invokes the anchor-processed hooks on a pool of workers so that a slow hook can't stall
+// the observer. Invocations are buffered and, if the buffer is full (i.e. the hooks are too slow), the invocation
+// is dropped and the dropped-event metric is incremented.
+type anchorHookDispatcher struct {
+	hooks       []AnchorProcessedHook
+	metrics     droppedEventMetrics
+	poolSize    int
+	timeout     time.Duration
+	invocations chan *hookInvocation
+	done        chan struct{}
+	wg          sync.WaitGroup
+	stopped     sync.Once
+}
+
+func newAnchorHookDispatcher(hooks []AnchorProcessedHook, poolSize, bufferSize int, timeout time.Duration,
+	metrics droppedEventMetrics,
+) *anchorHookDispatcher {
+	if poolSize <= 0 {
+		poolSize = defaultAnchorHookPoolSize
+	}
+
+	if bufferSize <= 0 {
+		bufferSize = defaultAnchorEventBufferSize
+	}
+
+	if timeout <= 0 {
+		timeout = defaultAnchorHookTimeout
+	}
+
+	return &anchorHookDispatcher{
+		hooks:       hooks,
+		metrics:     metrics,
+		poolSize:    poolSize,
+		timeout:     timeout,
+		invocations: make(chan *hookInvocation, bufferSize),
+		done:        make(chan struct{}),
+	}
+}
+
+func (d *anchorHookDispatcher) start() {
+	d.wg.Add(d.poolSize)
+
+	for i := 0; i < d.poolSize; i++ {
+		go d.work()
+	}
+}
+
+func (d *anchorHookDispatcher) stop() {
+	d.stopped.Do(func() {
+		close(d.done)
+
+		d.wg.Wait()
+	})
+}
+
+// emit queues an invocation of each hook with the given event. If the buffer is full then the invocation is
+// dropped. The hooks are given a context which isn't cancelled when the given context is cancelled, since
+// the hooks are invoked after the message has been processed.
+func (d *anchorHookDispatcher) emit(ctx context.Context, event *ProcessedAnchorEvent) {
+	ctx = context.WithoutCancel(ctx)
+
+	for _, hook := range d.hooks {
+		select {
+		case d.invocations <- &hookInvocation{ctx: ctx, hook: hook, event: event}:
+		default:
+			logger.Warn("Anchor-processed hook buffer is full. Dropping event.",
+				logfields.WithAnchorEventURIString(event.Hashlink))
+
+			d.metrics.ObserverIncrementDroppedAnchorEventCount()
+		}
+	}
+}
+
+func (d *anchorHookDispatcher) work() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case inv := <-d.invocations:
+			d.invoke(inv)
+		case <-d.done:
+			logger.Debug("Anchor-processed hook worker stopped")
+
+			return
+		}
+	}
+}
+
+func (d *anchorHookDispatcher) invoke(inv *hookInvocation) {
+	ctx, cancel := context.WithTimeout(inv.ctx, d.timeout)
+	defer cancel()
+
+	if err := inv.hook(ctx, inv.event); err != nil {
+		logger.Warnc(ctx, "Error returned from anchor-processed hook",
+			logfields.WithAnchorEventURIString(inv.event.Hashlink), log.WithError(err))
+	}
+}
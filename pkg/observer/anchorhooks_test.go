@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
+)
+
+func TestObserver_AnchorProcessedHooks(t *testing.T) {
+	hook1 := &mockAnchorHook{}
+	hook2 := &mockAnchorHook{err: errors.New("injected hook error")}
+
+	providers, cid := newProcessedAnchorTestProviders(t)
+	providers.AnchorProcessedHooks = []AnchorProcessedHook{hook1.handle, hook2.handle}
+
+	o, err := New(serviceIRI, providers, WithAnchorProcessedHookPoolSize(2),
+		WithAnchorProcessedHookTimeout(time.Second))
+	require.NoError(t, err)
+
+	o.Start()
+	defer o.Stop()
+
+	require.NoError(t, o.pubSub.PublishAnchor(context.Background(), &anchorinfo.AnchorInfo{Hashlink: cid}))
+
+	require.Eventually(t, func() bool {
+		return len(hook1.getEvents()) == 1 && len(hook2.getEvents()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// An error returned from a hook shouldn't affect the other hooks.
+	for _, events := range [][]*ProcessedAnchorEvent{hook1.getEvents(), hook2.getEvents()} {
+		require.Equal(t, cid, events[0].Hashlink)
+		require.Equal(t, testNamespace, events[0].Namespace)
+		require.Equal(t, []string{"did1", "did2"}, events[0].Suffixes)
+		require.False(t, events[0].Timestamp.IsZero())
+		require.Positive(t, events[0].Duration)
+	}
+}
+
+func TestAnchorHookDispatcher(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		d := newAnchorHookDispatcher([]AnchorProcessedHook{(&mockAnchorHook{}).handle}, 0, 0, 0,
+			&mockDroppedEventMetrics{})
+		require.Equal(t, defaultAnchorHookPoolSize, d.poolSize)
+		require.Equal(t, defaultAnchorEventBufferSize, cap(d.invocations))
+		require.Equal(t, defaultAnchorHookTimeout, d.timeout)
+	})
+
+	t.Run("Slow hook doesn't block other hooks", func(t *testing.T) {
+		release := make(chan struct{})
+
+		slowHook := &mockAnchorHook{block: release}
+		hook := &mockAnchorHook{}
+		metrics := &mockDroppedEventMetrics{}
+
+		d := newAnchorHookDispatcher([]AnchorProcessedHook{slowHook.handle, hook.handle}, 3, 10, time.Second,
+			metrics)
+
+		d.start()
+		defer d.stop()
+
+		d.emit(context.Background(), &ProcessedAnchorEvent{Hashlink: "hl:1"})
+		d.emit(context.Background(), &ProcessedAnchorEvent{Hashlink: "hl:2"})
+
+		require.Eventually(t, func() bool { return len(hook.getEvents()) == 2 }, time.Second, 10*time.Millisecond)
+		require.Empty(t, slowHook.getEvents())
+
+		close(release)
+
+		require.Eventually(t, func() bool { return len(slowHook.getEvents()) == 2 }, time.Second, 10*time.Millisecond)
+		require.Zero(t, metrics.getDropped())
+	})
+
+	t.Run("Hook times out", func(t *testing.T) {
+		hook := &mockAnchorHook{block: make(chan struct{})}
+
+		d := newAnchorHookDispatcher([]AnchorProcessedHook{hook.handle}, 1, 1, 50*time.Millisecond,
+			&mockDroppedEventMetrics{})
+
+		d.start()
+		defer d.stop()
+
+		// A cancelled context must not be passed on to the hook.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		d.emit(ctx, &ProcessedAnchorEvent{Hashlink: "hl:1"})
+
+		require.Eventually(t, func() bool { return len(hook.getEvents()) == 1 }, time.Second, 10*time.Millisecond)
+		require.ErrorIs(t, hook.getErrors()[0], context.DeadlineExceeded)
+	})
+
+	t.Run("Invocations dropped when buffer is full", func(t *testing.T) {
+		release := make(chan struct{})
+
+		hook := &mockAnchorHook{block: release}
+		metrics := &mockDroppedEventMetrics{}
+
+		d := newAnchorHookDispatcher([]AnchorProcessedHook{hook.handle}, 1, 2, time.Minute, metrics)
+
+		d.start()
+
+		// The first invocation is taken from the buffer and blocks in the hook.
+		d.emit(context.Background(), &ProcessedAnchorEvent{Hashlink: "hl:1"})
+
+		require.Eventually(t, func() bool { return len(d.invocations) == 0 }, time.Second, 10*time.Millisecond)
+
+		// The next two invocations fill the buffer and the remaining invocations are dropped.
+		for i := 0; i < 5; i++ {
+			d.emit(context.Background(), &ProcessedAnchorEvent{Hashlink: "hl:x"})
+		}
+
+		require.Equal(t, 3, metrics.getDropped())
+
+		close(release)
+
+		require.Eventually(t, func() bool { return len(hook.getEvents()) == 3 }, time.Second, 10*time.Millisecond)
+
+		d.stop()
+		d.stop()
+	})
+}
+
+type mockAnchorHook struct {
+	mutex  sync.Mutex
+	events []*ProcessedAnchorEvent
+	errs   []error
+	err    error
+	block  chan struct{}
+}
+
+func (m *mockAnchorHook) handle(ctx context.Context, event *ProcessedAnchorEvent) error {
+	err := m.err
+
+	if m.block != nil {
+		select {
+		case <-m.block:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.events = append(m.events, event)
+	m.errs = append(m.errs, err)
+
+	return err
+}
+
+func (m *mockAnchorHook) getEvents() []*ProcessedAnchorEvent {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.events
+}
+
+func (m *mockAnchorHook) getErrors() []error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.errs
+}
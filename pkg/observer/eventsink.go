@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"sync"
+	"time"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+const defaultAnchorEventBufferSize = 100
+
+// ProcessedAnchorEvent is emitted to the processed-anchor sink and the anchor-processed hooks after an anchor
+// was successfully processed.
+type ProcessedAnchorEvent struct {
+	Hashlink  string
+	Namespace string
+	// Suffixes contains the suffixes of the DIDs that were affected by the anchor.
+	Suffixes  []string
+	Timestamp time.Time
+	// Duration is the time that it took to process the anchor.
+	Duration time.Duration
+}
+
+// ProcessedAnchorSink receives an event after each anchor is successfully processed. The sink is invoked
+// from a single goroutine in the order in which anchors were processed.
+type ProcessedAnchorSink func(event *ProcessedAnchorEvent)
+
+type droppedEventMetrics interface {
+	ObserverIncrementDroppedAnchorEventCount()
+}
+
+// anchorEventDispatcher delivers processed-anchor events to the sink without blocking the observer. Events
+// are buffered and, if the buffer is full (i.e. the sink is too slow), the event is dropped and the
+// dropped-event metric is incremented.
+type anchorEventDispatcher struct {
+	sink    ProcessedAnchorSink
+	metrics droppedEventMetrics
+	events  chan *ProcessedAnchorEvent
+	done    chan struct{}
+	wg      sync.WaitGroup
+	stopped sync.Once
+}
+
+func newAnchorEventDispatcher(sink ProcessedAnchorSink, bufferSize int,
+	metrics droppedEventMetrics,
+) *anchorEventDispatcher {
+	if bufferSize <= 0 {
+		bufferSize = defaultAnchorEventBufferSize
+	}
+
+	return &anchorEventDispatcher{
+		sink:    sink,
+		metrics: metrics,
+		events:  make(chan *ProcessedAnchorEvent, bufferSize),
+		done:    make(chan struct{}),
+	}
+}
+
+func (d *anchorEventDispatcher) start() {
+	d.wg.Add(1)
+
+	go d.dispatch()
+}
+
+func (d *anchorEventDispatcher) stop() {
+	d.stopped.Do(func() {
+		close(d.done)
+
+		d.wg.Wait()
+	})
+}
+
+// emit adds the event to the buffer. If the buffer is full then the event is dropped.
+func (d *anchorEventDispatcher) emit(event *ProcessedAnchorEvent) {
+	select {
+	case d.events <- event:
+	default:
+		logger.Warn("Processed-anchor event buffer is full. Dropping event.",
+			logfields.WithAnchorEventURIString(event.Hashlink))
+
+		d.metrics.ObserverIncrementDroppedAnchorEventCount()
+	}
+}
+
+func (d *anchorEventDispatcher) dispatch() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case event := <-d.events:
+			d.sink(event)
+		case <-d.done:
+			logger.Debug("Processed-anchor event dispatcher stopped")
+
+			return
+		}
+	}
+}
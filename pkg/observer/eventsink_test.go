@@ -0,0 +1,290 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-svc-go/pkg/mocks"
+
+	apclientmocks "github.com/trustbloc/orb/pkg/activitypub/client/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset"
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator"
+	"github.com/trustbloc/orb/pkg/anchor/graph"
+	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	casresolver "github.com/trustbloc/orb/pkg/cas/resolver"
+	"github.com/trustbloc/orb/pkg/didanchor/memdidanchor"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	obsmocks "github.com/trustbloc/orb/pkg/observer/mocks"
+	protomocks "github.com/trustbloc/orb/pkg/protocolversion/mocks"
+	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
+	"github.com/trustbloc/orb/pkg/store/cas"
+	webfingerclient "github.com/trustbloc/orb/pkg/webfinger/client"
+)
+
+const testNamespace = "did:orb"
+
+func TestObserver_ProcessedAnchorSink(t *testing.T) {
+	sink := &mockAnchorSink{}
+
+	providers, cid := newProcessedAnchorTestProviders(t)
+	providers.ProcessedAnchorSink = sink.handle
+
+	o, err := New(serviceIRI, providers, WithProcessedAnchorEventBufferSize(10))
+	require.NoError(t, err)
+
+	o.Start()
+	defer o.Stop()
+
+	require.NoError(t, o.pubSub.PublishAnchor(context.Background(), &anchorinfo.AnchorInfo{Hashlink: cid}))
+
+	time.Sleep(200 * time.Millisecond)
+
+	events := sink.getEvents()
+	require.Len(t, events, 1)
+	require.Equal(t, cid, events[0].Hashlink)
+	require.Equal(t, testNamespace, events[0].Namespace)
+	require.Equal(t, []string{"did1", "did2"}, events[0].Suffixes)
+	require.False(t, events[0].Timestamp.IsZero())
+}
+
+func TestObserver_ProcessedAnchorLog(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		anchorLog := &mockProcessedAnchorLog{}
+
+		providers, cid := newProcessedAnchorTestProviders(t)
+		providers.ProcessedAnchorLog = anchorLog
+
+		o, err := New(serviceIRI, providers)
+		require.NoError(t, err)
+
+		o.Start()
+		defer o.Stop()
+
+		require.NoError(t, o.pubSub.PublishAnchor(context.Background(), &anchorinfo.AnchorInfo{Hashlink: cid}))
+
+		require.Eventually(t, func() bool { return len(anchorLog.getEntries()) == 1 }, time.Second, 10*time.Millisecond)
+
+		entry := anchorLog.getEntries()[0]
+		require.Equal(t, cid, entry.hashlink)
+		require.Equal(t, testNamespace, entry.namespace)
+		require.False(t, entry.processedTime.IsZero())
+	})
+
+	t.Run("Append error -> ignored", func(t *testing.T) {
+		anchorLog := &mockProcessedAnchorLog{err: errors.New("injected append error")}
+		sink := &mockAnchorSink{}
+
+		providers, cid := newProcessedAnchorTestProviders(t)
+		providers.ProcessedAnchorLog = anchorLog
+		providers.ProcessedAnchorSink = sink.handle
+
+		o, err := New(serviceIRI, providers)
+		require.NoError(t, err)
+
+		o.Start()
+		defer o.Stop()
+
+		require.NoError(t, o.pubSub.PublishAnchor(context.Background(), &anchorinfo.AnchorInfo{Hashlink: cid}))
+
+		// The anchor is still processed and the processed-anchor event is still emitted.
+		require.Eventually(t, func() bool { return len(sink.getEvents()) == 1 }, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestAnchorEventDispatcher(t *testing.T) {
+	t.Run("Events delivered", func(t *testing.T) {
+		sink := &mockAnchorSink{}
+		metrics := &mockDroppedEventMetrics{}
+
+		d := newAnchorEventDispatcher(sink.handle, 0, metrics)
+		require.Equal(t, defaultAnchorEventBufferSize, cap(d.events))
+
+		d.start()
+		defer d.stop()
+
+		d.emit(&ProcessedAnchorEvent{Hashlink: "hl:1"})
+		d.emit(&ProcessedAnchorEvent{Hashlink: "hl:2"})
+
+		require.Eventually(t, func() bool { return len(sink.getEvents()) == 2 }, time.Second, 10*time.Millisecond)
+
+		events := sink.getEvents()
+		require.Equal(t, "hl:1", events[0].Hashlink)
+		require.Equal(t, "hl:2", events[1].Hashlink)
+		require.Zero(t, metrics.getDropped())
+	})
+
+	t.Run("Events dropped when buffer is full", func(t *testing.T) {
+		release := make(chan struct{})
+
+		sink := &mockAnchorSink{block: release}
+		metrics := &mockDroppedEventMetrics{}
+
+		d := newAnchorEventDispatcher(sink.handle, 2, metrics)
+
+		d.start()
+
+		// The first event is taken from the buffer and blocks in the sink.
+		d.emit(&ProcessedAnchorEvent{Hashlink: "hl:1"})
+
+		require.Eventually(t, func() bool { return len(d.events) == 0 }, time.Second, 10*time.Millisecond)
+
+		// The next two events fill the buffer and the remaining events are dropped.
+		for i := 0; i < 5; i++ {
+			d.emit(&ProcessedAnchorEvent{Hashlink: "hl:x"})
+		}
+
+		require.Equal(t, 3, metrics.getDropped())
+
+		close(release)
+
+		require.Eventually(t, func() bool { return len(sink.getEvents()) == 3 }, time.Second, 10*time.Millisecond)
+
+		d.stop()
+		d.stop()
+	})
+}
+
+type mockAnchorSink struct {
+	mutex  sync.Mutex
+	events []*ProcessedAnchorEvent
+	block  chan struct{}
+}
+
+func (m *mockAnchorSink) handle(event *ProcessedAnchorEvent) {
+	if m.block != nil {
+		<-m.block
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.events = append(m.events, event)
+}
+
+func (m *mockAnchorSink) getEvents() []*ProcessedAnchorEvent {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]*ProcessedAnchorEvent{}, m.events...)
+}
+
+type mockDroppedEventMetrics struct {
+	mutex   sync.Mutex
+	dropped int
+}
+
+func (m *mockDroppedEventMetrics) ObserverIncrementDroppedAnchorEventCount() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.dropped++
+}
+
+func (m *mockDroppedEventMetrics) getDropped() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.dropped
+}
+
+func newProcessedAnchorTestProviders(t *testing.T) (*Providers, string) {
+	t.Helper()
+
+	tp := &mocks.TxnProcessor{}
+
+	pc := mocks.NewMockProtocolClient()
+	pc.Versions[0].TransactionProcessorReturns(tp)
+	pc.Versions[0].ProtocolReturns(pc.Protocol)
+
+	casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+	require.NoError(t, err)
+
+	anchorGraph := graph.New(&graph.Providers{
+		CasWriter: casClient,
+		CasResolver: casresolver.New(casClient, nil,
+			casresolver.NewWebCASResolver(
+				transport.New(&http.Client{}, testutil.MustParseURL("https://example.com/keys/public-key"),
+					transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+				webfingerclient.New(), "https"), &orbmocks.MetricsProvider{}),
+		DocLoader: testutil.GetLoader(t),
+	})
+
+	cid, err := anchorGraph.Add(newMockAnchorLinkset(t, &subject.Payload{
+		Namespace:       testNamespace,
+		Version:         0,
+		CoreIndex:       "hl:uEiBGozN2uP1HBNNZtL-oeg2ifE0NuKY8Bg3miVMJtVZvYQ",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}, {Suffix: "did2"}},
+	}))
+	require.NoError(t, err)
+
+	casResolver := &protomocks.CASResolver{}
+	casResolver.ResolveReturns([]byte(anchorEvent), "", nil)
+
+	return &Providers{
+		ProtocolClientProvider: mocks.NewMockProtocolClientProvider().WithProtocolClient(testNamespace, pc),
+		AnchorGraph:            anchorGraph,
+		DidAnchors:             memdidanchor.New(),
+		PubSub:                 mempubsub.New(mempubsub.DefaultConfig()),
+		Metrics:                &orbmocks.MetricsProvider{},
+		Outbox:                 func() Outbox { return apmocks.NewOutbox() },
+		HostMetaLinkResolver:   &apmocks.WebFingerResolver{},
+		CASResolver:            casResolver,
+		DocLoader:              testutil.GetLoader(t),
+		Pkf:                    pubKeyFetcherFnc,
+		AnchorLinkStore:        &orbmocks.AnchorLinkStore{},
+		MonitoringSvc:          &obsmocks.MonitoringService{},
+		AnchorLinksetBuilder:   anchorlinkset.NewBuilder(generator.NewRegistry()),
+	}, cid
+}
+
+type processedAnchorLogEntry struct {
+	hashlink      string
+	namespace     string
+	processedTime time.Time
+}
+
+type mockProcessedAnchorLog struct {
+	mutex   sync.Mutex
+	entries []*processedAnchorLogEntry
+	err     error
+}
+
+func (m *mockProcessedAnchorLog) Append(hl, namespace string, processedTime time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.entries = append(m.entries, &processedAnchorLogEntry{
+		hashlink:      hl,
+		namespace:     namespace,
+		processedTime: processedTime,
+	})
+
+	return nil
+}
+
+func (m *mockProcessedAnchorLog) getEntries() []*processedAnchorLogEntry {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]*processedAnchorLogEntry{}, m.entries...)
+}
@@ -9,6 +9,17 @@ import (
 )
 
 type MonitoringService struct {
+	CancelStub        func(string) error
+	cancelMutex       sync.RWMutex
+	cancelArgsForCall []struct {
+		arg1 string
+	}
+	cancelReturns struct {
+		result1 error
+	}
+	cancelReturnsOnCall map[int]struct {
+		result1 error
+	}
 	WatchStub        func(*verifiable.Credential, time.Time, string, time.Time) error
 	watchMutex       sync.RWMutex
 	watchArgsForCall []struct {
@@ -27,6 +38,67 @@ type MonitoringService struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *MonitoringService) Cancel(arg1 string) error {
+	fake.cancelMutex.Lock()
+	ret, specificReturn := fake.cancelReturnsOnCall[len(fake.cancelArgsForCall)]
+	fake.cancelArgsForCall = append(fake.cancelArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CancelStub
+	fakeReturns := fake.cancelReturns
+	fake.recordInvocation("Cancel", []interface{}{arg1})
+	fake.cancelMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *MonitoringService) CancelCallCount() int {
+	fake.cancelMutex.RLock()
+	defer fake.cancelMutex.RUnlock()
+	return len(fake.cancelArgsForCall)
+}
+
+func (fake *MonitoringService) CancelCalls(stub func(string) error) {
+	fake.cancelMutex.Lock()
+	defer fake.cancelMutex.Unlock()
+	fake.CancelStub = stub
+}
+
+func (fake *MonitoringService) CancelArgsForCall(i int) string {
+	fake.cancelMutex.RLock()
+	defer fake.cancelMutex.RUnlock()
+	argsForCall := fake.cancelArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MonitoringService) CancelReturns(result1 error) {
+	fake.cancelMutex.Lock()
+	defer fake.cancelMutex.Unlock()
+	fake.CancelStub = nil
+	fake.cancelReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MonitoringService) CancelReturnsOnCall(i int, result1 error) {
+	fake.cancelMutex.Lock()
+	defer fake.cancelMutex.Unlock()
+	fake.CancelStub = nil
+	if fake.cancelReturnsOnCall == nil {
+		fake.cancelReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.cancelReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *MonitoringService) Watch(arg1 *verifiable.Credential, arg2 time.Time, arg3 string, arg4 time.Time) error {
 	fake.watchMutex.Lock()
 	ret, specificReturn := fake.watchReturnsOnCall[len(fake.watchArgsForCall)]
@@ -94,6 +166,8 @@ func (fake *MonitoringService) WatchReturnsOnCall(i int, result1 error) {
 func (fake *MonitoringService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.cancelMutex.RLock()
+	defer fake.cancelMutex.RUnlock()
 	fake.watchMutex.RLock()
 	defer fake.watchMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
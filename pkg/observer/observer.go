@@ -21,6 +21,7 @@ import (
 	"github.com/trustbloc/sidetree-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-svc-go/pkg/api/protocol"
 	txnapi "github.com/trustbloc/sidetree-svc-go/pkg/api/txn"
+	"go.opentelemetry.io/otel/trace"
 
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
@@ -32,6 +33,7 @@ import (
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/linkset"
+	"github.com/trustbloc/orb/pkg/observability/tracing"
 	"github.com/trustbloc/orb/pkg/pubsub/spi"
 )
 
@@ -81,6 +83,9 @@ type pubSub interface {
 type metricsProvider interface {
 	ProcessAnchorTime(value time.Duration)
 	ProcessDIDTime(value time.Duration)
+	ObserverIncrementReconnectCount()
+	ObserverIncrementDroppedAnchorEventCount()
+	ObserverIncrementSkippedAnchorCount()
 }
 
 // Outbox defines an ActivityPub outbox.
@@ -112,6 +117,11 @@ type anchorLinksetBuilder interface {
 
 type monitoringSvc interface {
 	Watch(vc *verifiable.Credential, endTime time.Time, domain string, created time.Time) error
+	Cancel(vcID string) error
+}
+
+type processedAnchorLog interface {
+	Append(hl, namespace string, processedTime time.Time) error
 }
 
 type outboxProvider func() Outbox
@@ -120,6 +130,14 @@ type options struct {
 	discoveryDomain          string
 	subscriberPoolSize       int
 	proofMonitoringSvcExpiry time.Duration
+	anchorEventBufferSize    int
+	strictValidation         bool
+	namespaces               []string
+	validatePreviousAnchors  bool
+	catchupConcurrency       int
+	catchupThreshold         int
+	anchorHookPoolSize       int
+	anchorHookTimeout        time.Duration
 }
 
 // Option is an option for observer.
@@ -139,6 +157,24 @@ func WithSubscriberPoolSize(value int) Option {
 	}
 }
 
+// WithCatchupConcurrency sets the number of messages that may be processed concurrently while the observer is
+// catching up on the backlog of messages that accumulated while the server was down. Once the backlog is drained,
+// the observer reverts to the steady-state concurrency (see WithSubscriberPoolSize). Messages for the same DID
+// are still processed in order. Catch-up mode is disabled if the value is not greater than the subscriber pool size.
+func WithCatchupConcurrency(value int) Option {
+	return func(opts *options) {
+		opts.catchupConcurrency = value
+	}
+}
+
+// WithCatchupThreshold sets the number of outstanding messages below which the observer considers the backlog
+// to be drained and reverts to the steady-state concurrency. If not set then the subscriber pool size is used.
+func WithCatchupThreshold(value int) Option {
+	return func(opts *options) {
+		opts.catchupThreshold = value
+	}
+}
+
 // WithProofMonitoringExpiryPeriod sets expiry period for proof monitoring service.
 func WithProofMonitoringExpiryPeriod(value time.Duration) Option {
 	return func(opts *options) {
@@ -146,6 +182,56 @@ func WithProofMonitoringExpiryPeriod(value time.Duration) Option {
 	}
 }
 
+// WithProcessedAnchorEventBufferSize sets the size of the buffer which holds processed-anchor events that have
+// not yet been delivered to the processed-anchor sink (or to the anchor-processed hooks). If the buffer is full
+// then new events are dropped.
+func WithProcessedAnchorEventBufferSize(value int) Option {
+	return func(opts *options) {
+		opts.anchorEventBufferSize = value
+	}
+}
+
+// WithAnchorProcessedHookPoolSize sets the number of workers which invoke the anchor-processed hooks.
+func WithAnchorProcessedHookPoolSize(value int) Option {
+	return func(opts *options) {
+		opts.anchorHookPoolSize = value
+	}
+}
+
+// WithAnchorProcessedHookTimeout sets the maximum time that an anchor-processed hook may take. The context
+// passed to the hook is cancelled after the timeout.
+func WithAnchorProcessedHookTimeout(value time.Duration) Option {
+	return func(opts *options) {
+		opts.anchorHookTimeout = value
+	}
+}
+
+// WithStrictCredentialValidation enables/disables strict JSON-LD validation of anchor credentials (enabled by default).
+// Strict validation rejects credentials that contain fields which aren't defined in the JSON-LD context. It should
+// only be disabled in order to interoperate with peers that produce slightly non-conformant credentials.
+func WithStrictCredentialValidation(enable bool) Option {
+	return func(opts *options) {
+		opts.strictValidation = enable
+	}
+}
+
+// WithNamespaces sets the namespaces that are recognized by the observer. Anchors for any other namespace
+// are skipped. If no namespaces are set then an anchor is processed if there is a protocol client for its namespace.
+func WithNamespaces(namespaces ...string) Option {
+	return func(opts *options) {
+		opts.namespaces = namespaces
+	}
+}
+
+// WithPreviousAnchorValidation enables/disables validation of the 'previous' anchors referenced by the
+// DID operations in an anchor. If enabled then each referenced 'previous' anchor must contain a prior operation
+// for the same DID suffix, otherwise the anchor is rejected. Enabled by default.
+func WithPreviousAnchorValidation(enable bool) Option {
+	return func(opts *options) {
+		opts.validatePreviousAnchors = enable
+	}
+}
+
 // Providers contains all of the providers required by the observer.
 type Providers struct {
 	ProtocolClientProvider protocol.ClientProvider
@@ -161,6 +247,9 @@ type Providers struct {
 	AnchorLinkStore      anchorLinkStore
 	MonitoringSvc        monitoringSvc
 	AnchorLinksetBuilder anchorLinksetBuilder
+	ProcessedAnchorSink  ProcessedAnchorSink   // Optional
+	ProcessedAnchorLog   processedAnchorLog    // Optional
+	AnchorProcessedHooks []AnchorProcessedHook // Optional
 }
 
 // Observer receives transactions over a channel and processes them by storing them to an operation store.
@@ -171,12 +260,20 @@ type Observer struct {
 	pubSub              *PubSub
 	discoveryDomain     string
 	monitoringSvcExpiry time.Duration
+	anchorEvents        *anchorEventDispatcher
+	anchorHooks         *anchorHookDispatcher
+	strictValidation    bool
+	namespaces          map[string]struct{}
+	validatePrevious    bool
+	tracer              trace.Tracer
 }
 
 // New returns a new observer.
 func New(serviceIRI *url.URL, providers *Providers, opts ...Option) (*Observer, error) {
 	optns := &options{
 		proofMonitoringSvcExpiry: defaultMonitoringSvcExpiry,
+		strictValidation:         true,
+		validatePreviousAnchors:  true,
 	}
 
 	for _, opt := range opts {
@@ -188,6 +285,27 @@ func New(serviceIRI *url.URL, providers *Providers, opts ...Option) (*Observer,
 		Providers:           providers,
 		discoveryDomain:     optns.discoveryDomain,
 		monitoringSvcExpiry: optns.proofMonitoringSvcExpiry,
+		strictValidation:    optns.strictValidation,
+		validatePrevious:    optns.validatePreviousAnchors,
+		tracer:              tracing.Tracer(tracing.SubsystemObserver),
+	}
+
+	if len(optns.namespaces) > 0 {
+		o.namespaces = make(map[string]struct{}, len(optns.namespaces))
+
+		for _, ns := range optns.namespaces {
+			o.namespaces[ns] = struct{}{}
+		}
+	}
+
+	if providers.ProcessedAnchorSink != nil {
+		o.anchorEvents = newAnchorEventDispatcher(providers.ProcessedAnchorSink,
+			optns.anchorEventBufferSize, providers.Metrics)
+	}
+
+	if len(providers.AnchorProcessedHooks) > 0 {
+		o.anchorHooks = newAnchorHookDispatcher(providers.AnchorProcessedHooks, optns.anchorHookPoolSize,
+			optns.anchorEventBufferSize, optns.anchorHookTimeout, providers.Metrics)
 	}
 
 	subscriberPoolSize := optns.subscriberPoolSize
@@ -195,7 +313,9 @@ func New(serviceIRI *url.URL, providers *Providers, opts ...Option) (*Observer,
 		subscriberPoolSize = defaultSubscriberPoolSize
 	}
 
-	ps, err := NewPubSub(providers.PubSub, o.handleAnchor, o.processDID, subscriberPoolSize)
+	ps, err := NewPubSub(providers.PubSub, o.handleAnchor, o.processDID, subscriberPoolSize,
+		WithReconnectMetrics(providers.Metrics),
+		WithCatchupMode(optns.catchupConcurrency, optns.catchupThreshold))
 	if err != nil {
 		return nil, err
 	}
@@ -207,12 +327,33 @@ func New(serviceIRI *url.URL, providers *Providers, opts ...Option) (*Observer,
 
 // Start starts observer routines.
 func (o *Observer) Start() {
+	if o.anchorEvents != nil {
+		o.anchorEvents.start()
+	}
+
+	if o.anchorHooks != nil {
+		o.anchorHooks.start()
+	}
+
 	o.pubSub.Start()
 }
 
 // Stop stops the observer.
 func (o *Observer) Stop() {
 	o.pubSub.Stop()
+
+	if o.anchorEvents != nil {
+		o.anchorEvents.stop()
+	}
+
+	if o.anchorHooks != nil {
+		o.anchorHooks.stop()
+	}
+}
+
+// HealthCheck returns an error if the observer is unable to receive anchors and DIDs from the message queue.
+func (o *Observer) HealthCheck() error {
+	return o.pubSub.HealthCheck()
 }
 
 // Publisher returns the publisher that adds anchors and DIDs to a message queue for processing.
@@ -221,6 +362,17 @@ func (o *Observer) Publisher() Publisher {
 }
 
 func (o *Observer) handleAnchor(ctx context.Context, anchor *anchorinfo.AnchorInfo) error {
+	ctx, span := o.tracer.Start(ctx, "observer handle anchor",
+		trace.WithAttributes(tracing.AnchorEventURIAttribute(anchor.Hashlink)))
+
+	err := o.observeAnchor(ctx, anchor)
+
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (o *Observer) observeAnchor(ctx context.Context, anchor *anchorinfo.AnchorInfo) error {
 	logger.Debug("Observing anchor", logfields.WithAnchorEventURIString(anchor.Hashlink),
 		logfields.WithLocalHashlink(anchor.LocalHashlink), logfields.WithAttributedTo(anchor.AttributedTo))
 
@@ -230,7 +382,7 @@ func (o *Observer) handleAnchor(ctx context.Context, anchor *anchorinfo.AnchorIn
 		o.Metrics.ProcessAnchorTime(time.Since(startTime))
 	}()
 
-	anchorLinkset, err := o.AnchorGraph.Read(anchor.Hashlink)
+	anchorLinkset, err := o.readAnchorLinkset(ctx, anchor.Hashlink)
 	if err != nil {
 		logger.Warn("Failed to get anchor Linkset from anchor graph",
 			logfields.WithAnchorEventURIString(anchor.Hashlink), log.WithError(err))
@@ -240,6 +392,8 @@ func (o *Observer) handleAnchor(ctx context.Context, anchor *anchorinfo.AnchorIn
 
 	logger.Debug("Successfully read anchor Linkset from anchor graph", logfields.WithAnchorEventURIString(anchor.Hashlink))
 
+	o.waitForTurn(ctx, anchorLinkset)
+
 	for _, anchorLink := range anchorLinkset.Linkset {
 		if err := o.processAnchor(ctx, anchor, anchorLink); err != nil {
 			logger.Warn("Error processing anchor", logfields.WithAnchorEventURIString(anchor.Hashlink), log.WithError(err))
@@ -263,6 +417,16 @@ func (o *Observer) handleAnchor(ctx context.Context, anchor *anchorinfo.AnchorIn
 }
 
 func (o *Observer) processDID(ctx context.Context, did string) error {
+	ctx, span := o.tracer.Start(ctx, "observer process DID")
+
+	err := o.observeDID(ctx, did)
+
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (o *Observer) observeDID(ctx context.Context, did string) error {
 	logger.Debug("Processing out-of-system DID", logfields.WithDID(did))
 
 	startTime := time.Now()
@@ -278,7 +442,11 @@ func (o *Observer) processDID(ctx context.Context, did string) error {
 		return err
 	}
 
-	anchors, err := o.AnchorGraph.GetDidAnchors(cidWithHint, suffix)
+	trace.SpanFromContext(ctx).SetAttributes(tracing.DIDSuffixAttribute(suffix))
+
+	waitForTurn(ctx, suffix)
+
+	anchors, err := o.getDIDAnchors(ctx, cidWithHint, suffix)
 	if err != nil {
 		logger.Warn("Process DID failed", logfields.WithDID(did), log.WithError(err))
 
@@ -307,6 +475,50 @@ func (o *Observer) processDID(ctx context.Context, did string) error {
 	return nil
 }
 
+func (o *Observer) readAnchorLinkset(ctx context.Context, hl string) (*linkset.Linkset, error) {
+	_, span := o.tracer.Start(ctx, "read anchor graph", trace.WithAttributes(tracing.AnchorEventURIAttribute(hl)))
+
+	anchorLinkset, err := o.AnchorGraph.Read(hl)
+
+	tracing.EndSpan(span, err)
+
+	return anchorLinkset, err
+}
+
+func (o *Observer) getDIDAnchors(ctx context.Context, cidWithHint, suffix string) ([]graph.Anchor, error) {
+	_, span := o.tracer.Start(ctx, "get DID anchors", trace.WithAttributes(tracing.DIDSuffixAttribute(suffix)))
+
+	anchors, err := o.AnchorGraph.GetDidAnchors(cidWithHint, suffix)
+
+	tracing.EndSpan(span, err)
+
+	return anchors, err
+}
+
+// waitForTurn waits until all earlier messages (in catch-up mode) which refer to any of the DIDs
+// in the given anchor linkset have been processed.
+func (o *Observer) waitForTurn(ctx context.Context, anchorLinkset *linkset.Linkset) {
+	if !hasTicket(ctx) {
+		return
+	}
+
+	var suffixes []string
+
+	for _, anchorLink := range anchorLinkset.Linkset {
+		anchorPayload, err := o.AnchorLinksetBuilder.GetPayloadFromAnchorLink(anchorLink)
+		if err != nil {
+			// The error is handled when the anchor is processed.
+			continue
+		}
+
+		for _, op := range anchorPayload.PreviousAnchors {
+			suffixes = append(suffixes, op.Suffix)
+		}
+	}
+
+	waitForTurn(ctx, suffixes...)
+}
+
 func getDidParts(did string) (cid, suffix string, err error) {
 	const delimiter = ":"
 
@@ -326,16 +538,33 @@ func (o *Observer) processAnchor(ctx context.Context,
 	logger.Debug("Processing anchor", logfields.WithAnchorEventURIString(anchor.Hashlink),
 		logfields.WithAttributedTo(anchor.AttributedTo), logfields.WithSuffixes(suffixes...))
 
+	startTime := time.Now()
+
 	anchorPayload, err := o.AnchorLinksetBuilder.GetPayloadFromAnchorLink(anchorLink)
 	if err != nil {
 		return fmt.Errorf("failed to extract anchor payload from anchor[%s]: %w", anchor.Hashlink, err)
 	}
 
+	if !o.isNamespaceRecognized(anchorPayload.Namespace) {
+		logger.Info("Skipping anchor since the namespace isn't recognized",
+			logfields.WithAnchorEventURIString(anchor.Hashlink), logfields.WithNamespace(anchorPayload.Namespace))
+
+		o.Metrics.ObserverIncrementSkippedAnchorCount()
+
+		return nil
+	}
+
 	pc, err := o.ProtocolClientProvider.ForNamespace(anchorPayload.Namespace)
 	if err != nil {
 		return fmt.Errorf("failed to get protocol client for namespace [%s]: %w", anchorPayload.Namespace, err)
 	}
 
+	if o.validatePrevious {
+		if err := o.validatePreviousAnchors(anchor.Hashlink, anchorPayload); err != nil {
+			return err
+		}
+	}
+
 	v, err := pc.Get(anchorPayload.Version)
 	if err != nil {
 		return fmt.Errorf("failed to get protocol version for transaction time [%d]: %w",
@@ -355,11 +584,7 @@ func (o *Observer) processAnchor(ctx context.Context,
 		equivalentRefs = append(equivalentRefs, "https:"+o.discoveryDomain+":"+canonicalID)
 	}
 
-	vc, err := util.VerifiableCredentialFromAnchorLink(anchorLink,
-		verifiable.WithPublicKeyFetcher(o.Pkf),
-		verifiable.WithJSONLDDocumentLoader(o.DocLoader),
-		verifiable.WithStrictValidation(),
-	)
+	vc, err := util.VerifiableCredentialFromAnchorLink(anchorLink, o.parseCredentialOpts()...)
 	if err != nil {
 		return fmt.Errorf("get verifiable credential from anchor link: %w", err)
 	}
@@ -379,7 +604,13 @@ func (o *Observer) processAnchor(ctx context.Context,
 	logger.Debug("Processing anchor", logfields.WithAnchorEventURIString(anchor.Hashlink),
 		logfields.WithCoreIndex(anchorPayload.CoreIndex))
 
+	_, span := o.tracer.Start(ctx, "process transaction",
+		trace.WithAttributes(tracing.AnchorEventURIAttribute(anchor.Hashlink)))
+
 	numProcessed, err := v.TransactionProcessor().Process(sidetreeTxn, suffixes...)
+
+	tracing.EndSpan(span, err)
+
 	if err != nil {
 		return fmt.Errorf("failed to process anchor[%s] core index[%s]: %w",
 			anchor.Hashlink, anchorPayload.CoreIndex, err)
@@ -418,6 +649,19 @@ func (o *Observer) processAnchor(ctx context.Context,
 	logger.Info("Successfully processed DIDs in anchor", logfields.WithTotal(int(anchorPayload.OperationCount)),
 		logfields.WithAnchorEventURIString(anchor.Hashlink), logfields.WithCoreIndex(anchorPayload.CoreIndex))
 
+	o.cancelSupersededProofMonitoring(anchor.Hashlink, anchorPayload)
+
+	processedTime := time.Now()
+
+	o.appendToProcessedAnchorLog(anchor.Hashlink, anchorPayload.Namespace, processedTime)
+	o.emitProcessedAnchorEvent(ctx, &ProcessedAnchorEvent{
+		Hashlink:  anchor.Hashlink,
+		Namespace: anchorPayload.Namespace,
+		Suffixes:  acSuffixes,
+		Timestamp: processedTime,
+		Duration:  processedTime.Sub(startTime),
+	})
+
 	// Post a 'Like' activity to the originator of the anchor credential.
 	err = o.saveAnchorLinkAndPostLikeActivity(ctx, anchor)
 	if err != nil {
@@ -429,6 +673,40 @@ func (o *Observer) processAnchor(ctx context.Context,
 	return nil
 }
 
+// isNamespaceRecognized returns true if no namespaces were configured or if the given namespace
+// is one of the configured namespaces.
+func (o *Observer) isNamespaceRecognized(namespace string) bool {
+	if o.namespaces == nil {
+		return true
+	}
+
+	_, ok := o.namespaces[namespace]
+
+	return ok
+}
+
+func (o *Observer) emitProcessedAnchorEvent(ctx context.Context, event *ProcessedAnchorEvent) {
+	if o.anchorEvents != nil {
+		o.anchorEvents.emit(event)
+	}
+
+	if o.anchorHooks != nil {
+		o.anchorHooks.emit(ctx, event)
+	}
+}
+
+func (o *Observer) appendToProcessedAnchorLog(hl, namespace string, processedTime time.Time) {
+	if o.ProcessedAnchorLog == nil {
+		return
+	}
+
+	// The anchor has already been processed, so a failure to write to the log is not fatal.
+	if err := o.ProcessedAnchorLog.Append(hl, namespace, processedTime); err != nil {
+		logger.Warn("Error appending anchor to processed-anchor log",
+			logfields.WithAnchorEventURIString(hl), log.WithError(err))
+	}
+}
+
 func (o *Observer) setupProofMonitoring(vc *verifiable.Credential) {
 	expiryTime := time.Now().Add(o.monitoringSvcExpiry)
 
@@ -542,6 +820,19 @@ func (o *Observer) doPostLikeActivity(ctx context.Context, to []*url.URL, refURL
 	return nil
 }
 
+func (o *Observer) parseCredentialOpts() []verifiable.CredentialOpt {
+	opts := []verifiable.CredentialOpt{
+		verifiable.WithPublicKeyFetcher(o.Pkf),
+		verifiable.WithJSONLDDocumentLoader(o.DocLoader),
+	}
+
+	if o.strictValidation {
+		opts = append(opts, verifiable.WithStrictValidation())
+	}
+
+	return opts
+}
+
 func (o *Observer) resolveActorFromHashlink(anchorRef string) (actorID string, err error) {
 	anchorLinksetBytes, _, err := o.CASResolver.Resolve(nil, anchorRef, nil)
 	if err != nil {
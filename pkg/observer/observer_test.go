@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -21,6 +22,10 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/sidetree-svc-go/pkg/mocks"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	apclientmocks "github.com/trustbloc/orb/pkg/activitypub/client/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
@@ -32,6 +37,7 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/graph"
 	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
 	"github.com/trustbloc/orb/pkg/anchor/subject"
+	anchorutil "github.com/trustbloc/orb/pkg/anchor/util"
 	casresolver "github.com/trustbloc/orb/pkg/cas/resolver"
 	"github.com/trustbloc/orb/pkg/datauri"
 	"github.com/trustbloc/orb/pkg/didanchor/memdidanchor"
@@ -39,6 +45,7 @@ import (
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/linkset"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/observability/tracing"
 	obsmocks "github.com/trustbloc/orb/pkg/observer/mocks"
 	protomocks "github.com/trustbloc/orb/pkg/protocolversion/mocks"
 	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
@@ -201,7 +208,9 @@ func TestStartObserver(t *testing.T) {
 		o, err := New(serviceIRI, providers,
 			WithDiscoveryDomain("webcas:shared.domain.com"),
 			WithProofMonitoringExpiryPeriod(20*time.Second),
-			WithSubscriberPoolSize(3))
+			WithSubscriberPoolSize(3),
+			WithCatchupConcurrency(6),
+			WithCatchupThreshold(2))
 		require.NotNil(t, o)
 		require.NoError(t, err)
 
@@ -217,6 +226,135 @@ func TestStartObserver(t *testing.T) {
 		require.Equal(t, 2, tp.ProcessCallCount())
 	})
 
+	t.Run("success - unrecognized namespace skipped", func(t *testing.T) {
+		tp := &mocks.TxnProcessor{}
+
+		pc := mocks.NewMockProtocolClient()
+		pc.Versions[0].TransactionProcessorReturns(tp)
+		pc.Versions[0].ProtocolReturns(pc.Protocol)
+
+		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		anchorGraph := graph.New(&graph.Providers{
+			CasWriter: casClient,
+			CasResolver: casresolver.New(casClient, nil,
+				casresolver.NewWebCASResolver(
+					transport.New(&http.Client{}, testutil.MustParseURL("https://example.com/keys/public-key"),
+						transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+					webfingerclient.New(), "https"), &orbmocks.MetricsProvider{}),
+			DocLoader: testutil.GetLoader(t),
+		})
+
+		hl1, err := anchorGraph.Add(newMockAnchorLinkset(t, &subject.Payload{
+			Namespace:       namespace1,
+			CoreIndex:       "hl:uEiBGozN2uP1HBNNZtL-oeg2ifE0NuKY8Bg3miVMJtVZvYQ",
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}},
+		}))
+		require.NoError(t, err)
+
+		hl2, err := anchorGraph.Add(newMockAnchorLinkset(t, &subject.Payload{
+			Namespace:       namespace2,
+			Version:         1,
+			CoreIndex:       "hl:uEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg",
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did2"}},
+		}))
+		require.NoError(t, err)
+
+		casResolver := &protomocks.CASResolver{}
+		casResolver.ResolveReturns([]byte(anchorEvent), "", nil)
+
+		metrics := &skippedAnchorMetrics{MetricsProvider: &orbmocks.MetricsProvider{}}
+
+		providers := &Providers{
+			// There's a protocol client for both namespaces but the observer only recognizes namespace1.
+			ProtocolClientProvider: mocks.NewMockProtocolClientProvider().
+				WithProtocolClient(namespace1, pc).
+				WithProtocolClient(namespace2, pc),
+			AnchorGraph:          anchorGraph,
+			DidAnchors:           memdidanchor.New(),
+			PubSub:               mempubsub.New(mempubsub.DefaultConfig()),
+			Metrics:              metrics,
+			Outbox:               func() Outbox { return apmocks.NewOutbox() },
+			HostMetaLinkResolver: &apmocks.WebFingerResolver{},
+			CASResolver:          casResolver,
+			DocLoader:            testutil.GetLoader(t),
+			Pkf:                  pubKeyFetcherFnc,
+			AnchorLinkStore:      &orbmocks.AnchorLinkStore{},
+			MonitoringSvc:        &obsmocks.MonitoringService{},
+			AnchorLinksetBuilder: anchorlinkset.NewBuilder(generator.NewRegistry()),
+		}
+
+		o, err := New(serviceIRI, providers, WithNamespaces(namespace1))
+		require.NoError(t, err)
+
+		o.Start()
+		defer o.Stop()
+
+		require.NoError(t, o.pubSub.PublishAnchor(context.Background(), &anchorinfo.AnchorInfo{Hashlink: hl1}))
+		require.NoError(t, o.pubSub.PublishAnchor(context.Background(), &anchorinfo.AnchorInfo{Hashlink: hl2}))
+
+		time.Sleep(200 * time.Millisecond)
+
+		require.Equal(t, 1, tp.ProcessCallCount())
+		require.Equal(t, int32(1), atomic.LoadInt32(&metrics.skippedCount))
+	})
+
+	t.Run("error - no protocol client for namespace", func(t *testing.T) {
+		tp := &mocks.TxnProcessor{}
+
+		pc := mocks.NewMockProtocolClient()
+		pc.Versions[0].TransactionProcessorReturns(tp)
+		pc.Versions[0].ProtocolReturns(pc.Protocol)
+
+		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		anchorGraph := graph.New(&graph.Providers{
+			CasWriter: casClient,
+			CasResolver: casresolver.New(casClient, nil,
+				casresolver.NewWebCASResolver(
+					transport.New(&http.Client{}, testutil.MustParseURL("https://example.com/keys/public-key"),
+						transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+					webfingerclient.New(), "https"), &orbmocks.MetricsProvider{}),
+			DocLoader: testutil.GetLoader(t),
+		})
+
+		hl, err := anchorGraph.Add(newMockAnchorLinkset(t, &subject.Payload{
+			Namespace:       namespace2,
+			Version:         1,
+			CoreIndex:       "hl:uEiC3Q4SF3bP-qb0i9MIz_k_n-rKi-BhSgcOk8qoKVcJqrg",
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did2"}},
+		}))
+		require.NoError(t, err)
+
+		metrics := &skippedAnchorMetrics{MetricsProvider: &orbmocks.MetricsProvider{}}
+
+		providers := &Providers{
+			ProtocolClientProvider: mocks.NewMockProtocolClientProvider().WithProtocolClient(namespace1, pc),
+			AnchorGraph:            anchorGraph,
+			DidAnchors:             memdidanchor.New(),
+			PubSub:                 mempubsub.New(mempubsub.DefaultConfig()),
+			Metrics:                metrics,
+			AnchorLinkStore:        &orbmocks.AnchorLinkStore{},
+			AnchorLinksetBuilder:   anchorlinkset.NewBuilder(generator.NewRegistry()),
+		}
+
+		o, err := New(serviceIRI, providers)
+		require.NoError(t, err)
+
+		o.Start()
+		defer o.Stop()
+
+		require.NoError(t, o.pubSub.PublishAnchor(context.Background(), &anchorinfo.AnchorInfo{Hashlink: hl}))
+
+		time.Sleep(200 * time.Millisecond)
+
+		// The anchor failed to process (and will be retried) so it's not counted as skipped.
+		require.Equal(t, 0, tp.ProcessCallCount())
+		require.Zero(t, atomic.LoadInt32(&metrics.skippedCount))
+	})
+
 	t.Run("success - process did (multiple, just create)", func(t *testing.T) {
 		tp := &mocks.TxnProcessor{}
 
@@ -924,6 +1062,44 @@ func TestResolveActorFromHashlink(t *testing.T) {
 	})
 }
 
+func TestStrictCredentialValidation(t *testing.T) {
+	payload := &subject.Payload{
+		Namespace:       "did:orb",
+		CoreIndex:       "hl:uEiBGozN2uP1HBNNZtL-oeg2ifE0NuKY8Bg3miVMJtVZvYQ",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}},
+	}
+
+	// The credential contains a field that isn't defined in the JSON-LD context.
+	anchorLinkset := newMockAnchorLinkset(t, payload, func(vc *verifiable.Credential) {
+		vc.CustomFields = verifiable.CustomFields{"undefinedField": "value"}
+	})
+
+	providers := &Providers{
+		PubSub:    mempubsub.New(mempubsub.DefaultConfig()),
+		DocLoader: testutil.GetLoader(t),
+		Pkf:       pubKeyFetcherFnc,
+	}
+
+	t.Run("Strict (default) -> error", func(t *testing.T) {
+		o, err := New(serviceIRI, providers)
+		require.NoError(t, err)
+		require.True(t, o.strictValidation)
+
+		_, err = anchorutil.VerifiableCredentialFromAnchorLink(anchorLinkset.Link(), o.parseCredentialOpts()...)
+		require.Error(t, err)
+	})
+
+	t.Run("Lax -> success", func(t *testing.T) {
+		o, err := New(serviceIRI, providers, WithStrictCredentialValidation(false))
+		require.NoError(t, err)
+		require.False(t, o.strictValidation)
+
+		vc, err := anchorutil.VerifiableCredentialFromAnchorLink(anchorLinkset.Link(), o.parseCredentialOpts()...)
+		require.NoError(t, err)
+		require.Equal(t, "value", vc.CustomFields["undefinedField"])
+	})
+}
+
 func TestSetupProofMonitoring(t *testing.T) {
 	vc, err := verifiable.ParseCredential([]byte(testVC),
 		verifiable.WithDisabledProofCheck(),
@@ -1003,7 +1179,144 @@ func TestSetupProofMonitoring(t *testing.T) {
 	})
 }
 
-func newMockAnchorLinkset(t *testing.T, payload *subject.Payload) *linkset.Linkset {
+func TestObserver_Tracing(t *testing.T) {
+	const namespace = "did:orb"
+
+	tp := &mocks.TxnProcessor{}
+
+	pc := mocks.NewMockProtocolClient()
+	pc.Versions[0].TransactionProcessorReturns(tp)
+	pc.Versions[0].ProtocolReturns(pc.Protocol)
+
+	casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+	require.NoError(t, err)
+
+	anchorGraph := graph.New(&graph.Providers{
+		CasWriter: casClient,
+		CasResolver: casresolver.New(casClient, nil,
+			casresolver.NewWebCASResolver(
+				transport.New(&http.Client{}, testutil.MustParseURL("https://example.com/keys/public-key"),
+					transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+				webfingerclient.New(), "https"), &orbmocks.MetricsProvider{}),
+		DocLoader:            testutil.GetLoader(t),
+		AnchorLinksetBuilder: anchorlinkset.NewBuilder(generator.NewRegistry()),
+	})
+
+	hl, err := anchorGraph.Add(newMockAnchorLinkset(t, &subject.Payload{
+		Namespace:       namespace,
+		CoreIndex:       "hl:uEiBGozN2uP1HBNNZtL-oeg2ifE0NuKY8Bg3miVMJtVZvYQ",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}},
+	}))
+	require.NoError(t, err)
+
+	casResolver := &protomocks.CASResolver{}
+	casResolver.ResolveReturns([]byte(anchorEvent), "", nil)
+
+	providers := &Providers{
+		ProtocolClientProvider: mocks.NewMockProtocolClientProvider().WithProtocolClient(namespace, pc),
+		AnchorGraph:            anchorGraph,
+		DidAnchors:             memdidanchor.New(),
+		PubSub:                 mempubsub.New(mempubsub.DefaultConfig()),
+		Metrics:                &orbmocks.MetricsProvider{},
+		Outbox:                 func() Outbox { return apmocks.NewOutbox() },
+		HostMetaLinkResolver:   &apmocks.WebFingerResolver{},
+		CASResolver:            casResolver,
+		DocLoader:              testutil.GetLoader(t),
+		Pkf:                    pubKeyFetcherFnc,
+		AnchorLinkStore:        &orbmocks.AnchorLinkStore{},
+		MonitoringSvc:          &obsmocks.MonitoringService{},
+		AnchorLinksetBuilder:   anchorlinkset.NewBuilder(generator.NewRegistry()),
+	}
+
+	newObserver := func(t *testing.T) (*Observer, *tracetest.SpanRecorder) {
+		t.Helper()
+
+		o, err := New(serviceIRI, providers)
+		require.NoError(t, err)
+
+		recorder := tracetest.NewSpanRecorder()
+
+		o.tracer = tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)).Tracer("test")
+
+		return o, recorder
+	}
+
+	t.Run("Processed anchor", func(t *testing.T) {
+		o, recorder := newObserver(t)
+
+		ctx, parentSpan := o.tracer.Start(context.Background(), "parent")
+
+		require.NoError(t, o.handleAnchor(ctx, &anchorinfo.AnchorInfo{Hashlink: hl}))
+
+		parentSpan.End()
+
+		spans := recorder.Ended()
+		require.Equal(t, []string{"read anchor graph", "process transaction", "observer handle anchor", "parent"},
+			spanNames(spans))
+
+		for _, span := range spans[:2] {
+			require.Equal(t, spans[2].SpanContext().SpanID(), span.Parent().SpanID())
+			requireSpanAttribute(t, span, tracing.AttributeAnchorEventURI, hl)
+		}
+
+		require.Equal(t, spans[3].SpanContext().SpanID(), spans[2].Parent().SpanID())
+		requireSpanAttribute(t, spans[2], tracing.AttributeAnchorEventURI, hl)
+	})
+
+	t.Run("Processed DID", func(t *testing.T) {
+		o, recorder := newObserver(t)
+
+		require.NoError(t, o.processDID(context.Background(), hl+":did1"))
+
+		spans := recorder.Ended()
+		require.Equal(t, []string{"get DID anchors", "process transaction", "observer process DID"}, spanNames(spans))
+		requireSpanAttribute(t, spans[0], tracing.AttributeDIDSuffix, "did1")
+		requireSpanAttribute(t, spans[2], tracing.AttributeDIDSuffix, "did1")
+	})
+
+	t.Run("Transaction processor error", func(t *testing.T) {
+		tp.ProcessReturns(0, errors.New("injected processor error"))
+		defer tp.ProcessReturns(0, nil)
+
+		o, recorder := newObserver(t)
+
+		require.Error(t, o.handleAnchor(context.Background(), &anchorinfo.AnchorInfo{Hashlink: hl}))
+
+		spans := recorder.Ended()
+		require.Equal(t, []string{"read anchor graph", "process transaction", "observer handle anchor"},
+			spanNames(spans))
+		require.Equal(t, codes.Error, spans[1].Status().Code)
+		require.Equal(t, codes.Error, spans[2].Status().Code)
+	})
+}
+
+func spanNames(spans []tracesdk.ReadOnlySpan) []string {
+	names := make([]string, len(spans))
+
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+
+	return names
+}
+
+func requireSpanAttribute(t *testing.T, span tracesdk.ReadOnlySpan, key attribute.Key, value string) {
+	t.Helper()
+
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			require.Equal(t, value, attr.Value.AsString())
+
+			return
+		}
+	}
+
+	require.Failf(t, "attribute not found", "span [%s] doesn't have attribute [%s]", span.Name(), key)
+}
+
+func newMockAnchorLinkset(t *testing.T, payload *subject.Payload,
+	updateVC ...func(vc *verifiable.Credential),
+) *linkset.Linkset {
 	t.Helper()
 
 	vc := &verifiable.Credential{
@@ -1022,6 +1335,10 @@ func newMockAnchorLinkset(t *testing.T, payload *subject.Payload) *linkset.Links
 		Issued: &util.TimeWrapper{Time: time.Now()},
 	}
 
+	for _, update := range updateVC {
+		update(vc)
+	}
+
 	al, _, err := anchorlinkset.NewBuilder(
 		generator.NewRegistry()).BuildAnchorLink(payload, datauri.MediaTypeDataURIGzipBase64,
 		func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error) {
@@ -1037,6 +1354,16 @@ var pubKeyFetcherFnc = func(issuerID, keyID string) (*verifier.PublicKey, error)
 	return nil, nil //nolint:nilnil
 }
 
+type skippedAnchorMetrics struct {
+	*orbmocks.MetricsProvider
+
+	skippedCount int32
+}
+
+func (m *skippedAnchorMetrics) ObserverIncrementSkippedAnchorCount() {
+	atomic.AddInt32(&m.skippedCount, 1)
+}
+
 type mockDidAnchor struct {
 	Err error
 }
@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"fmt"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+)
+
+// InvalidPreviousAnchorError is returned when the 'previous' anchor referenced by a DID operation
+// doesn't contain a prior operation for the same DID suffix.
+type InvalidPreviousAnchorError struct {
+	Anchor   string
+	Suffix   string
+	Previous string
+}
+
+// Error returns the error string.
+func (e *InvalidPreviousAnchorError) Error() string {
+	return fmt.Sprintf("anchor [%s]: previous anchor [%s] for suffix [%s] does not contain an operation for the suffix",
+		e.Anchor, e.Previous, e.Suffix)
+}
+
+// validatePreviousAnchors ensures that, for each DID suffix in the given anchor payload, the referenced
+// 'previous' anchor contains a prior operation for the same suffix. Each previous anchor is read only once.
+func (o *Observer) validatePreviousAnchors(hl string, payload *subject.Payload) error {
+	previousSuffixes := make(map[string]map[string]struct{})
+
+	for _, sa := range payload.PreviousAnchors {
+		if sa.Anchor == "" {
+			// Create operation.
+			continue
+		}
+
+		suffixes, ok := previousSuffixes[sa.Anchor]
+		if !ok {
+			var err error
+
+			suffixes, err = o.getAnchorSuffixes(sa.Anchor)
+			if err != nil {
+				return fmt.Errorf("validate previous anchor [%s] for suffix [%s]: %w", sa.Anchor, sa.Suffix, err)
+			}
+
+			previousSuffixes[sa.Anchor] = suffixes
+		}
+
+		if _, ok := suffixes[sa.Suffix]; !ok {
+			return &InvalidPreviousAnchorError{Anchor: hl, Suffix: sa.Suffix, Previous: sa.Anchor}
+		}
+	}
+
+	logger.Debug("Validated previous anchors", logfields.WithAnchorEventURIString(hl),
+		logfields.WithTotal(len(previousSuffixes)))
+
+	return nil
+}
+
+// getAnchorSuffixes returns the DID suffixes for which the given anchor contains an operation.
+func (o *Observer) getAnchorSuffixes(hl string) (map[string]struct{}, error) {
+	anchorLinkset, err := o.AnchorGraph.Read(hl)
+	if err != nil {
+		return nil, fmt.Errorf("read anchor: %w", err)
+	}
+
+	suffixes := make(map[string]struct{})
+
+	for _, anchorLink := range anchorLinkset.Linkset {
+		payload, err := o.AnchorLinksetBuilder.GetPayloadFromAnchorLink(anchorLink)
+		if err != nil {
+			return nil, fmt.Errorf("get payload from anchor link: %w", err)
+		}
+
+		for _, sa := range payload.PreviousAnchors {
+			suffixes[sa.Suffix] = struct{}{}
+		}
+	}
+
+	return suffixes, nil
+}
@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-svc-go/pkg/mocks"
+
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset"
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator"
+	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	"github.com/trustbloc/orb/pkg/didanchor/memdidanchor"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/linkset"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
+)
+
+const (
+	anchorHL   = "hl:uEiAr_xUtbeoALO4iKvN5eIWjqUmIO35wFEPTTzjOaSYgUA"
+	previousHL = "hl:uEiA1V3OBfZryXqZXPkKSFpJ09RU7gTAuHCj8uFjEiG73OA:uoQ-BeEtodHRwczovL29yYi5kb21haW40LmNvbS9jYXMvdUVpQTFWM09CZlpyeVhxWlhQa0tTRnBKMDlSVTdnVEF1SENqOHVGakVpRzczT0E" //nolint:lll
+	unrelated  = "hl:uEiALYp_C4wk2WegpfnCSoSTBdKZ1MVdDadn4rdmZl5GKzQ:uoQ-BeDVpcGZzOi8vUW1jcTZKV0RVa3l4ZWhxN1JWWmtQM052aUU0SHFSdW5SalgzOXZ1THZFSGFRTg"                              //nolint:lll
+)
+
+func TestValidatePreviousAnchors(t *testing.T) {
+	linksets := map[string]*linkset.Linkset{
+		previousHL: newMockAnchorLinkset(t, &subject.Payload{
+			Namespace:       testNamespace,
+			CoreIndex:       "hl:uEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA",
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}, {Suffix: "did2"}},
+		}),
+		unrelated: newMockAnchorLinkset(t, &subject.Payload{
+			Namespace:       testNamespace,
+			CoreIndex:       "hl:uEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA",
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did3"}},
+		}),
+	}
+
+	newObserver := func(t *testing.T, anchorGraph AnchorGraph) *Observer {
+		t.Helper()
+
+		o, err := New(serviceIRI, &Providers{
+			AnchorGraph:          anchorGraph,
+			PubSub:               mempubsub.New(mempubsub.DefaultConfig()),
+			AnchorLinksetBuilder: anchorlinkset.NewBuilder(generator.NewRegistry()),
+		})
+		require.NoError(t, err)
+
+		return o
+	}
+
+	newAnchorGraph := func() *orbmocks.AnchorGraph {
+		anchorGraph := &orbmocks.AnchorGraph{}
+		anchorGraph.ReadCalls(func(hl string) (*linkset.Linkset, error) {
+			ls, ok := linksets[hl]
+			if !ok {
+				return nil, orberrors.ErrContentNotFound
+			}
+
+			return ls, nil
+		})
+
+		return anchorGraph
+	}
+
+	t.Run("success", func(t *testing.T) {
+		anchorGraph := newAnchorGraph()
+
+		o := newObserver(t, anchorGraph)
+
+		require.NoError(t, o.validatePreviousAnchors(anchorHL, &subject.Payload{
+			PreviousAnchors: []*subject.SuffixAnchor{
+				{Suffix: "did1", Anchor: previousHL},
+				{Suffix: "did2", Anchor: previousHL},
+				{Suffix: "did3", Anchor: unrelated},
+				{Suffix: "did4"},
+			},
+		}))
+
+		// Each previous anchor should be read only once.
+		require.Equal(t, 2, anchorGraph.ReadCallCount())
+	})
+
+	t.Run("creates only", func(t *testing.T) {
+		anchorGraph := newAnchorGraph()
+
+		o := newObserver(t, anchorGraph)
+
+		require.NoError(t, o.validatePreviousAnchors(anchorHL, &subject.Payload{
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}, {Suffix: "did2"}},
+		}))
+
+		require.Zero(t, anchorGraph.ReadCallCount())
+	})
+
+	t.Run("previous anchor doesn't contain suffix", func(t *testing.T) {
+		o := newObserver(t, newAnchorGraph())
+
+		err := o.validatePreviousAnchors(anchorHL, &subject.Payload{
+			PreviousAnchors: []*subject.SuffixAnchor{
+				{Suffix: "did1", Anchor: previousHL},
+				{Suffix: "did2", Anchor: unrelated},
+			},
+		})
+		require.Error(t, err)
+		require.False(t, orberrors.IsTransient(err))
+
+		invalidErr := &InvalidPreviousAnchorError{}
+		require.True(t, errors.As(err, &invalidErr))
+		require.Equal(t, anchorHL, invalidErr.Anchor)
+		require.Equal(t, "did2", invalidErr.Suffix)
+		require.Equal(t, unrelated, invalidErr.Previous)
+		require.Contains(t, err.Error(), "does not contain an operation for the suffix")
+	})
+
+	t.Run("read previous anchor error", func(t *testing.T) {
+		anchorGraph := &orbmocks.AnchorGraph{}
+		anchorGraph.ReadReturns(nil, orberrors.NewTransientf("injected read error"))
+
+		o := newObserver(t, anchorGraph)
+
+		err := o.validatePreviousAnchors(anchorHL, &subject.Payload{
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1", Anchor: previousHL}},
+		})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected read error")
+	})
+
+	t.Run("invalid previous anchor linkset", func(t *testing.T) {
+		anchorGraph := &orbmocks.AnchorGraph{}
+		anchorGraph.ReadReturns(linkset.New(&linkset.Link{}), nil)
+
+		o := newObserver(t, anchorGraph)
+
+		err := o.validatePreviousAnchors(anchorHL, &subject.Payload{
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1", Anchor: previousHL}},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get payload from anchor link")
+	})
+}
+
+func TestPreviousAnchorValidationOption(t *testing.T) {
+	anchorGraph := &orbmocks.AnchorGraph{}
+	anchorGraph.ReadReturns(newMockAnchorLinkset(t, &subject.Payload{
+		Namespace:       testNamespace,
+		CoreIndex:       "hl:uEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did3"}},
+	}), nil)
+
+	// The anchor references a previous anchor which doesn't contain the DID.
+	anchorLink := newMockAnchorLinkset(t, &subject.Payload{
+		Namespace:       testNamespace,
+		CoreIndex:       "hl:uEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1", Anchor: unrelated}},
+	}).Link()
+
+	newProviders := func(tp *mocks.TxnProcessor) *Providers {
+		pc := mocks.NewMockProtocolClient()
+		pc.Versions[0].TransactionProcessorReturns(tp)
+		pc.Versions[0].ProtocolReturns(pc.Protocol)
+
+		return &Providers{
+			ProtocolClientProvider: mocks.NewMockProtocolClientProvider().WithProtocolClient(testNamespace, pc),
+			AnchorGraph:            anchorGraph,
+			DidAnchors:             memdidanchor.New(),
+			PubSub:                 mempubsub.New(mempubsub.DefaultConfig()),
+			Metrics:                &orbmocks.MetricsProvider{},
+			DocLoader:              testutil.GetLoader(t),
+			Pkf:                    pubKeyFetcherFnc,
+			AnchorLinkStore:        &orbmocks.AnchorLinkStore{},
+			AnchorLinksetBuilder:   anchorlinkset.NewBuilder(generator.NewRegistry()),
+		}
+	}
+
+	t.Run("Enabled (default) -> error", func(t *testing.T) {
+		tp := &mocks.TxnProcessor{}
+
+		o, err := New(serviceIRI, newProviders(tp))
+		require.NoError(t, err)
+		require.True(t, o.validatePrevious)
+
+		err = o.processAnchor(context.Background(), &anchorinfo.AnchorInfo{Hashlink: anchorHL}, anchorLink)
+		require.Error(t, err)
+
+		invalidErr := &InvalidPreviousAnchorError{}
+		require.True(t, errors.As(err, &invalidErr))
+		require.Equal(t, "did1", invalidErr.Suffix)
+		require.Equal(t, unrelated, invalidErr.Previous)
+
+		require.Zero(t, tp.ProcessCallCount())
+	})
+
+	t.Run("Disabled -> success", func(t *testing.T) {
+		tp := &mocks.TxnProcessor{}
+		tp.ProcessReturns(1, nil)
+
+		o, err := New(serviceIRI, newProviders(tp), WithPreviousAnchorValidation(false))
+		require.NoError(t, err)
+		require.False(t, o.validatePrevious)
+
+		require.NoError(t, o.processAnchor(context.Background(),
+			&anchorinfo.AnchorInfo{Hashlink: anchorHL}, anchorLink))
+
+		require.Equal(t, 1, tp.ProcessCallCount())
+	})
+}
@@ -10,8 +10,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/cenkalti/backoff/v4"
 	"github.com/trustbloc/logutil-go/pkg/log"
 	"go.uber.org/zap"
 
@@ -26,6 +29,14 @@ import (
 const (
 	anchorTopic = "orb.anchor"
 	didTopic    = "orb.did"
+
+	defaultReconnectInitialInterval = 500 * time.Millisecond
+	defaultReconnectMaxInterval     = 30 * time.Second
+
+	// defaultMaxReconnectAttempts is the number of consecutive failed attempts to resubscribe to a topic
+	// after which the subscriber is reported as unhealthy. Attempts continue (with backoff) until the
+	// subscription is re-established or the subscriber is stopped.
+	defaultMaxReconnectAttempts = 10
 )
 
 type (
@@ -38,38 +49,126 @@ type messagePublisher interface {
 	Close() error
 }
 
+type reconnectMetrics interface {
+	ObserverIncrementReconnectCount()
+}
+
+// PubSubOpt is a publisher/subscriber option.
+type PubSubOpt func(ps *PubSub)
+
+// WithReconnectMetrics sets the metrics provider that records the number of times that the
+// subscriber has reconnected to a topic.
+func WithReconnectMetrics(metrics reconnectMetrics) PubSubOpt {
+	return func(ps *PubSub) {
+		ps.metrics = metrics
+	}
+}
+
+// WithCatchupMode sets the number of messages that may be processed concurrently while the subscriber is
+// catching up on the backlog of messages that accumulated in the queue while the server was down. The subscriber
+// starts in catch-up mode and reverts to the steady-state concurrency (the pool size) the first time that the
+// number of outstanding messages (i.e. messages received from the queue which have not yet been processed) drops
+// below the given threshold. If the threshold is zero then the pool size is used as the threshold. Catch-up mode
+// is disabled if the given concurrency is not greater than the pool size.
+//
+// While catch-up mode is enabled, messages that refer to the same DID are processed in the order in which
+// they were received.
+func WithCatchupMode(concurrency, threshold int) PubSubOpt {
+	return func(ps *PubSub) {
+		ps.catchupConcurrency = concurrency
+		ps.catchupThreshold = threshold
+	}
+}
+
 // PubSub implements a publisher/subscriber that publishes anchors and DIDs to a queue and processes
 // anchors and DIDs published to the queue.
 type PubSub struct {
 	*lifecycle.Lifecycle
 
-	publisher      messagePublisher
-	anchorCredChan <-chan *message.Message
-	didChan        <-chan *message.Message
-	processAnchors anchorProcessor
-	processDID     didProcessor
-	jsonUnmarshal  func(data []byte, v interface{}) error
-	jsonMarshal    func(v interface{}) ([]byte, error)
+	pubSub             pubSub
+	poolSize           int
+	catchupConcurrency int
+	catchupThreshold   int
+	limiter            *concurrencyLimiter
+	sequencer          *sequencer
+	anchorCredChan     <-chan *message.Message
+	didChan            <-chan *message.Message
+	processAnchors     anchorProcessor
+	processDID         didProcessor
+	jsonUnmarshal      func(data []byte, v interface{}) error
+	jsonMarshal        func(v interface{}) ([]byte, error)
+	metrics            reconnectMetrics
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	reconnectInitialInterval time.Duration
+	reconnectMaxInterval     time.Duration
+	maxReconnectAttempts     int
+
+	mutex      sync.RWMutex
+	healthErrs map[string]error
 }
 
 // NewPubSub returns a new publisher/subscriber.
-func NewPubSub(pubSub pubSub, anchorProcessor anchorProcessor, didProcessor didProcessor, poolSize int) (*PubSub, error) {
+func NewPubSub(pubSub pubSub, anchorProcessor anchorProcessor, didProcessor didProcessor, poolSize int,
+	opts ...PubSubOpt,
+) (*PubSub, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	h := &PubSub{
-		publisher:      pubSub,
-		processAnchors: anchorProcessor,
-		processDID:     didProcessor,
-		jsonUnmarshal:  json.Unmarshal,
-		jsonMarshal:    json.Marshal,
+		pubSub:                   pubSub,
+		poolSize:                 poolSize,
+		processAnchors:           anchorProcessor,
+		processDID:               didProcessor,
+		jsonUnmarshal:            json.Unmarshal,
+		jsonMarshal:              json.Marshal,
+		metrics:                  &noopReconnectMetrics{},
+		ctx:                      ctx,
+		cancel:                   cancel,
+		reconnectInitialInterval: defaultReconnectInitialInterval,
+		reconnectMaxInterval:     defaultReconnectMaxInterval,
+		maxReconnectAttempts:     defaultMaxReconnectAttempts,
+		healthErrs:               make(map[string]error),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	subscriberPoolSize := poolSize
+
+	if h.catchupConcurrency > poolSize {
+		threshold := h.catchupThreshold
+		if threshold <= 0 {
+			threshold = poolSize
+		}
+
+		logger.Info("Catch-up mode is enabled", logfields.WithSubscriberPoolSize(poolSize),
+			logfields.WithConcurrency(h.catchupConcurrency), logfields.WithThreshold(threshold))
+
+		h.limiter = newConcurrencyLimiter(poolSize, h.catchupConcurrency, threshold)
+		h.sequencer = newSequencer()
+
+		// Subscribe with enough subscribers to receive messages at the catch-up concurrency. The number
+		// of messages that are processed concurrently is restricted by the limiter.
+		subscriberPoolSize = h.catchupConcurrency
 	}
 
+	h.poolSize = subscriberPoolSize
+
 	h.Lifecycle = lifecycle.New("observer-pubsub",
 		lifecycle.WithStart(h.start),
+		lifecycle.WithStop(h.stop),
 	)
 
-	logger.Info("Subscribing to topic", log.WithTopic(anchorTopic), logfields.WithSubscriberPoolSize(poolSize))
+	logger.Info("Subscribing to topic", log.WithTopic(anchorTopic), logfields.WithSubscriberPoolSize(subscriberPoolSize))
 
-	anchorCredChan, err := pubSub.SubscribeWithOpts(context.Background(), anchorTopic, spi.WithPool(poolSize))
+	anchorCredChan, err := pubSub.SubscribeWithOpts(context.Background(), anchorTopic,
+		spi.WithPool(subscriberPoolSize))
 	if err != nil {
+		cancel()
+
 		return nil, fmt.Errorf("subscribe to topic [%s]: %w", anchorTopic, err)
 	}
 
@@ -77,8 +176,10 @@ func NewPubSub(pubSub pubSub, anchorProcessor anchorProcessor, didProcessor didP
 
 	logger.Info("Subscribing to topic", log.WithTopic(didTopic))
 
-	didChan, err := pubSub.SubscribeWithOpts(context.Background(), didTopic, spi.WithPool(poolSize))
+	didChan, err := pubSub.SubscribeWithOpts(context.Background(), didTopic, spi.WithPool(subscriberPoolSize))
 	if err != nil {
+		cancel()
+
 		return nil, fmt.Errorf("subscribe to topic [%s]: %w", didTopic, err)
 	}
 
@@ -103,7 +204,7 @@ func (h *PubSub) PublishAnchor(ctx context.Context, anchorInfo *anchorinfo.Ancho
 	logger.Debugc(ctx, "Publishing anchors message to queue", logfields.WithMessageID(msg.UUID),
 		log.WithTopic(anchorTopic), logfields.WithData(msg.Payload))
 
-	err = h.publisher.Publish(anchorTopic, msg)
+	err = h.pubSub.Publish(anchorTopic, msg)
 	if err != nil {
 		logger.Warnc(ctx, "Error publishing anchors message to queue", log.WithTopic(anchorTopic),
 			logfields.WithData(msg.Payload), log.WithError(err))
@@ -132,48 +233,188 @@ func (h *PubSub) PublishDID(ctx context.Context, did string) error {
 
 	logger.Debugc(ctx, "Publishing DIDs to queue", log.WithTopic(didTopic), logfields.WithDID(did))
 
-	return h.publisher.Publish(didTopic, msg)
+	return h.pubSub.Publish(didTopic, msg)
+}
+
+// HealthCheck returns an error if the subscriber was unable to re-establish a subscription to a topic
+// after repeated attempts. The health of each topic is tracked separately and the error for the first
+// unhealthy topic is returned.
+func (h *PubSub) HealthCheck() error {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for _, topic := range []string{anchorTopic, didTopic} {
+		if err := h.healthErrs[topic]; err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (h *PubSub) start() {
-	// Start the message listener
-	go h.listen()
+	// Start a message listener for each topic so that resubscribing to one topic doesn't
+	// block the processing of messages from the other.
+	go h.listen(anchorTopic, h.anchorCredChan, h.handleAnchorCredentialMessage)
+	go h.listen(didTopic, h.didChan, h.handleDIDMessage)
+}
+
+func (h *PubSub) stop() {
+	h.cancel()
+
+	if h.limiter != nil {
+		h.limiter.close()
+	}
 }
 
-func (h *PubSub) listen() {
-	logger.Debug("Starting message listener")
+// listen dispatches the messages received on the given channel. If the channel is closed unexpectedly
+// then the topic is resubscribed in this listener's loop.
+func (h *PubSub) listen(topic string, msgChan <-chan *message.Message,
+	handle func(ctx context.Context, msg *message.Message),
+) {
+	logger.Debug("Starting message listener", log.WithTopic(topic))
 
 	for {
-		select {
-		case msg, ok := <-h.anchorCredChan:
-			if !ok {
-				logger.Debug("Message listener stopped")
+		msg, ok := <-msgChan
+		if !ok {
+			msgChan = h.resubscribe(topic)
+			if msgChan == nil {
+				logger.Debug("Message listener stopped", log.WithTopic(topic))
 
 				return
 			}
 
-			logger.Debug("Got new anchor credential message", logfields.WithMessageID(msg.UUID),
-				logfields.WithMetadata(msg.Metadata), logfields.WithData(msg.Payload))
+			continue
+		}
+
+		logger.Debug("Got new message", log.WithTopic(topic), logfields.WithMessageID(msg.UUID),
+			logfields.WithMetadata(msg.Metadata), logfields.WithData(msg.Payload))
+
+		if !h.dispatch(msg, handle) {
+			logger.Debug("Message listener stopped", log.WithTopic(topic))
+
+			return
+		}
+	}
+}
+
+// dispatch processes the given message in a new goroutine. If catch-up mode is enabled then dispatch blocks until
+// the message may be processed according to the current concurrency limit, and a ticket is issued for the message
+// so that messages for the same DID are processed in order. False is returned if the subscriber was stopped while
+// waiting, in which case the message is not processed (and will be redelivered).
+func (h *PubSub) dispatch(msg *message.Message, handle func(ctx context.Context, msg *message.Message)) bool {
+	ctx := pubsub.ContextFromMessage(msg)
+
+	if h.limiter == nil {
+		go handle(ctx, msg)
+
+		return true
+	}
+
+	if !h.limiter.acquire() {
+		msg.Nack()
+
+		return false
+	}
+
+	t := h.sequencer.issue()
+
+	go func() {
+		defer h.limiter.release()
+		defer t.done()
+
+		handle(withTicket(ctx, t), msg)
+	}()
+
+	return true
+}
+
+// resubscribe re-establishes the subscription to the given topic after the subscription channel was closed.
+// Attempts are retried with exponential backoff until the subscription succeeds or the subscriber is stopped,
+// in which case nil is returned.
+func (h *PubSub) resubscribe(topic string) <-chan *message.Message {
+	if h.ctx.Err() != nil {
+		// The channel was closed because the subscriber is stopping.
+		return nil
+	}
+
+	logger.Warn("Subscription channel was closed unexpectedly. Resubscribing to topic...", log.WithTopic(topic))
+
+	var (
+		msgChan  <-chan *message.Message
+		attempts int
+	)
+
+	err := backoff.RetryNotify(
+		func() error {
+			c, err := h.pubSub.SubscribeWithOpts(context.Background(), topic, spi.WithPool(h.poolSize))
+			if err != nil {
+				return err
+			}
+
+			msgChan = c
 
-			go h.handleAnchorCredentialMessage(msg)
+			return nil
+		},
+		backoff.WithContext(h.newReconnectBackOff(), h.ctx),
+		func(err error, duration time.Duration) {
+			attempts++
 
-		case msg, ok := <-h.didChan:
-			if !ok {
-				logger.Debug("Message listener stopped")
+			if attempts < h.maxReconnectAttempts {
+				logger.Warn("Error resubscribing to topic. Will retry with backoff...", log.WithTopic(topic),
+					logfields.WithBackoff(duration), log.WithError(err))
 
 				return
 			}
 
-			logger.Debug("Got new DID message", logfields.WithMessageID(msg.UUID), logfields.WithData(msg.Payload))
+			logger.Error("Error resubscribing to topic after repeated attempts. The subscriber is unhealthy. "+
+				"Will continue to retry with backoff...", log.WithTopic(topic), logfields.WithTotal(attempts),
+				logfields.WithBackoff(duration), log.WithError(err))
 
-			go h.handleDIDMessage(msg)
-		}
+			h.setHealthError(topic, fmt.Errorf("unable to resubscribe to topic [%s] after %d attempts: %w",
+				topic, attempts, err))
+		},
+	)
+	if err != nil {
+		logger.Debug("Stopped resubscribing to topic", log.WithTopic(topic), log.WithError(err))
+
+		return nil
 	}
+
+	h.setHealthError(topic, nil)
+	h.metrics.ObserverIncrementReconnectCount()
+
+	logger.Info("Successfully resubscribed to topic", log.WithTopic(topic))
+
+	return msgChan
 }
 
-func (h *PubSub) handleAnchorCredentialMessage(msg *message.Message) {
-	ctx := pubsub.ContextFromMessage(msg)
+func (h *PubSub) newReconnectBackOff() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = h.reconnectInitialInterval
+	b.MaxInterval = h.reconnectMaxInterval
+	// Never give up. Retries stop only when the subscriber is stopped.
+	b.MaxElapsedTime = 0
+
+	b.Reset()
+
+	return b
+}
 
+func (h *PubSub) setHealthError(topic string, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err == nil {
+		delete(h.healthErrs, topic)
+
+		return
+	}
+
+	h.healthErrs[topic] = err
+}
+
+func (h *PubSub) handleAnchorCredentialMessage(ctx context.Context, msg *message.Message) {
 	logger.Debug("Handling message", logfields.WithMessageID(msg.UUID), logfields.WithData(msg.Payload))
 
 	anchorInfo := &anchorinfo.AnchorInfo{}
@@ -188,13 +429,11 @@ func (h *PubSub) handleAnchorCredentialMessage(msg *message.Message) {
 		return
 	}
 
-	h.ackNackMessage(msg, h.processAnchors(ctx, anchorInfo), logfields.WithAnchorEventURIString(anchorInfo.Hashlink),
+	h.ackNackMessage(ctx, msg, h.processAnchors(ctx, anchorInfo), logfields.WithAnchorEventURIString(anchorInfo.Hashlink),
 		logfields.WithAttributedTo(anchorInfo.AttributedTo), logfields.WithLocalHashlink(anchorInfo.LocalHashlink))
 }
 
-func (h *PubSub) handleDIDMessage(msg *message.Message) {
-	ctx := pubsub.ContextFromMessage(msg)
-
+func (h *PubSub) handleDIDMessage(ctx context.Context, msg *message.Message) {
 	logger.Debugc(ctx, "Handling message", logfields.WithMessageID(msg.UUID), logfields.WithData(msg.Payload))
 
 	var did string
@@ -209,12 +448,10 @@ func (h *PubSub) handleDIDMessage(msg *message.Message) {
 		return
 	}
 
-	h.ackNackMessage(msg, h.processDID(ctx, did), logfields.WithDID(did))
+	h.ackNackMessage(ctx, msg, h.processDID(ctx, did), logfields.WithDID(did))
 }
 
-func (h *PubSub) ackNackMessage(msg *message.Message, err error, logFields ...zap.Field) {
-	ctx := pubsub.ContextFromMessage(msg)
-
+func (h *PubSub) ackNackMessage(ctx context.Context, msg *message.Message, err error, logFields ...zap.Field) {
 	switch {
 	case err == nil:
 		logger.Debugc(ctx, "Acking message", append(logFields, logfields.WithMessageID(msg.UUID))...)
@@ -234,3 +471,90 @@ func (h *PubSub) ackNackMessage(msg *message.Message, err error, logFields ...za
 		msg.Ack()
 	}
 }
+
+// concurrencyLimiter limits the number of messages that are processed concurrently. The limit is the catch-up
+// concurrency while in catch-up mode and the steady-state concurrency otherwise. Catch-up mode ends (permanently)
+// the first time that the number of outstanding messages drops below the threshold.
+type concurrencyLimiter struct {
+	mutex      sync.Mutex
+	cond       *sync.Cond
+	steady     int
+	catchup    int
+	threshold  int
+	catchingUp bool
+	waiting    int
+	active     int
+	closed     bool
+}
+
+func newConcurrencyLimiter(steady, catchup, threshold int) *concurrencyLimiter {
+	l := &concurrencyLimiter{
+		steady:     steady,
+		catchup:    catchup,
+		threshold:  threshold,
+		catchingUp: true,
+	}
+
+	l.cond = sync.NewCond(&l.mutex)
+
+	return l
+}
+
+// acquire blocks until a message may be processed. False is returned if the limiter was closed.
+func (l *concurrencyLimiter) acquire() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.waiting++
+
+	for !l.closed && l.active >= l.limit() {
+		l.cond.Wait()
+	}
+
+	l.waiting--
+
+	if l.closed {
+		return false
+	}
+
+	l.active++
+
+	return true
+}
+
+func (l *concurrencyLimiter) release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.active--
+
+	if l.catchingUp && l.active+l.waiting < l.threshold {
+		logger.Info("Finished catching up on the message backlog. Reverting to steady-state concurrency.",
+			logfields.WithConcurrency(l.steady))
+
+		l.catchingUp = false
+	}
+
+	l.cond.Broadcast()
+}
+
+func (l *concurrencyLimiter) close() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.closed = true
+
+	l.cond.Broadcast()
+}
+
+func (l *concurrencyLimiter) limit() int {
+	if l.catchingUp {
+		return l.catchup
+	}
+
+	return l.steady
+}
+
+type noopReconnectMetrics struct{}
+
+func (m *noopReconnectMetrics) ObserverIncrementReconnectCount() {}
@@ -9,10 +9,16 @@ package observer
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/stretchr/testify/require"
 
 	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
@@ -20,6 +26,7 @@ import (
 	"github.com/trustbloc/orb/pkg/lifecycle"
 	"github.com/trustbloc/orb/pkg/mocks"
 	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
+	"github.com/trustbloc/orb/pkg/pubsub/spi"
 )
 
 //go:generate counterfeiter -o ../mocks/pubsub.gen.go --fake-name PubSub . pubSub
@@ -76,6 +83,389 @@ func TestPubSub(t *testing.T) {
 	mutex.RUnlock()
 }
 
+func TestPubSub_Reconnect(t *testing.T) {
+	const numFailures = 3
+
+	var (
+		mutex        sync.Mutex
+		anchorChans  []chan *message.Message
+		didChans     []chan *message.Message
+		anchorErrors int
+	)
+
+	errExpected := errors.New("injected subscribe error")
+
+	p := &mocks.PubSub{}
+	p.SubscribeWithOptsStub = func(_ context.Context, topic string, _ ...spi.Option) (<-chan *message.Message, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if topic == didTopic {
+			c := make(chan *message.Message)
+			didChans = append(didChans, c)
+
+			return c, nil
+		}
+
+		// Fail the first few attempts to resubscribe to the anchor topic.
+		if len(anchorChans) > 0 && anchorErrors < numFailures {
+			anchorErrors++
+
+			return nil, errExpected
+		}
+
+		c := make(chan *message.Message, 1)
+		anchorChans = append(anchorChans, c)
+
+		return c, nil
+	}
+
+	gotAnchors := make(chan *anchorinfo.AnchorInfo, 1)
+
+	metrics := &mockReconnectMetrics{}
+
+	ps, err := NewPubSub(p,
+		func(_ context.Context, anchor *anchorinfo.AnchorInfo) error {
+			gotAnchors <- anchor
+
+			return nil
+		},
+		func(_ context.Context, did string) error { return nil },
+		5,
+		WithReconnectMetrics(metrics),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, ps)
+
+	ps.reconnectInitialInterval = 10 * time.Millisecond
+	ps.reconnectMaxInterval = 50 * time.Millisecond
+	ps.maxReconnectAttempts = numFailures - 1
+
+	ps.Start()
+	defer ps.Stop()
+
+	require.NoError(t, ps.HealthCheck())
+
+	// Simulate a dropped connection to the message broker.
+	mutex.Lock()
+	close(anchorChans[0])
+	mutex.Unlock()
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return anchorErrors >= numFailures-1
+	}, time.Second, 5*time.Millisecond)
+
+	require.Eventually(t, func() bool { return metrics.count() == 1 }, 2*time.Second, 10*time.Millisecond)
+	require.NoError(t, ps.HealthCheck())
+
+	// Messages on the new subscription should be processed.
+	mutex.Lock()
+	require.Len(t, anchorChans, 2)
+	anchorChans[1] <- message.NewMessage("123", []byte(`{"hashlink":"abcdefg"}`))
+	mutex.Unlock()
+
+	select {
+	case anchor := <-gotAnchors:
+		require.Equal(t, "abcdefg", anchor.Hashlink)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for anchor")
+	}
+
+}
+
+func TestPubSub_ReconnectUnhealthy(t *testing.T) {
+	var (
+		mutex      sync.Mutex
+		anchorChan chan *message.Message
+	)
+
+	errExpected := errors.New("injected subscribe error")
+
+	p := &mocks.PubSub{}
+	p.SubscribeWithOptsStub = func(_ context.Context, topic string, _ ...spi.Option) (<-chan *message.Message, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if topic == didTopic {
+			return make(chan *message.Message), nil
+		}
+
+		if anchorChan != nil {
+			// Never succeed in resubscribing.
+			return nil, errExpected
+		}
+
+		anchorChan = make(chan *message.Message)
+
+		return anchorChan, nil
+	}
+
+	ps, err := NewPubSub(p,
+		func(_ context.Context, anchor *anchorinfo.AnchorInfo) error { return nil },
+		func(_ context.Context, did string) error { return nil },
+		5,
+	)
+	require.NoError(t, err)
+
+	ps.reconnectInitialInterval = 5 * time.Millisecond
+	ps.reconnectMaxInterval = 10 * time.Millisecond
+	ps.maxReconnectAttempts = 2
+
+	ps.Start()
+
+	mutex.Lock()
+	close(anchorChan)
+	mutex.Unlock()
+
+	require.Eventually(t, func() bool { return ps.HealthCheck() != nil }, time.Second, 5*time.Millisecond)
+	require.ErrorIs(t, ps.HealthCheck(), errExpected)
+	require.Contains(t, ps.HealthCheck().Error(), "unable to resubscribe to topic [orb.anchor]")
+
+	// Stopping the subscriber should stop the retries.
+	ps.Stop()
+
+	callCount := p.SubscribeWithOptsCallCount()
+
+	time.Sleep(50 * time.Millisecond)
+
+	require.LessOrEqual(t, p.SubscribeWithOptsCallCount(), callCount+1)
+}
+
+func TestPubSub_ReconnectIndependentTopics(t *testing.T) {
+	var (
+		mutex       sync.Mutex
+		anchorChan  chan *message.Message
+		didChans    []chan *message.Message
+		failAnchors = true
+	)
+
+	errExpected := errors.New("injected subscribe error")
+
+	p := &mocks.PubSub{}
+	p.SubscribeWithOptsStub = func(_ context.Context, topic string, _ ...spi.Option) (<-chan *message.Message, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if topic == didTopic {
+			c := make(chan *message.Message, 1)
+			didChans = append(didChans, c)
+
+			return c, nil
+		}
+
+		if anchorChan != nil && failAnchors {
+			return nil, errExpected
+		}
+
+		anchorChan = make(chan *message.Message)
+
+		return anchorChan, nil
+	}
+
+	gotDIDs := make(chan string, 2)
+
+	ps, err := NewPubSub(p,
+		func(_ context.Context, anchor *anchorinfo.AnchorInfo) error { return nil },
+		func(_ context.Context, did string) error {
+			gotDIDs <- did
+
+			return nil
+		},
+		5,
+	)
+	require.NoError(t, err)
+
+	ps.reconnectInitialInterval = 5 * time.Millisecond
+	ps.reconnectMaxInterval = 10 * time.Millisecond
+	ps.maxReconnectAttempts = 2
+
+	ps.Start()
+	defer ps.Stop()
+
+	// Drop the anchor subscription and keep failing to resubscribe.
+	mutex.Lock()
+	close(anchorChan)
+	mutex.Unlock()
+
+	require.Eventually(t, func() bool { return ps.HealthCheck() != nil }, time.Second, 5*time.Millisecond)
+	require.Contains(t, ps.HealthCheck().Error(), "unable to resubscribe to topic [orb.anchor]")
+
+	// DID messages must still be processed while the anchor topic is being resubscribed.
+	mutex.Lock()
+	didChans[0] <- message.NewMessage("did-1", []byte(`"did:orb:123"`))
+	mutex.Unlock()
+
+	select {
+	case did := <-gotDIDs:
+		require.Equal(t, "did:orb:123", did)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for DID while anchor topic was being resubscribed")
+	}
+
+	// Dropping and re-establishing the DID subscription doesn't clear the anchor topic's health error.
+	mutex.Lock()
+	close(didChans[0])
+	mutex.Unlock()
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return len(didChans) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	mutex.Lock()
+	didChans[1] <- message.NewMessage("did-2", []byte(`"did:orb:456"`))
+	mutex.Unlock()
+
+	select {
+	case did := <-gotDIDs:
+		require.Equal(t, "did:orb:456", did)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for DID on new subscription")
+	}
+
+	require.Error(t, ps.HealthCheck())
+
+	// The subscriber becomes healthy once the anchor topic is resubscribed.
+	mutex.Lock()
+	failAnchors = false
+	mutex.Unlock()
+
+	require.Eventually(t, func() bool { return ps.HealthCheck() == nil }, time.Second, 5*time.Millisecond)
+}
+
+func TestPubSub_CatchupMode(t *testing.T) {
+	const (
+		steadyConcurrency  = 2
+		catchupConcurrency = 6
+		numMessages        = 30
+	)
+
+	anchorChan := make(chan *message.Message, numMessages)
+	didChan := make(chan *message.Message, numMessages)
+
+	// Simulate a backlog of messages that accumulated while the server was down.
+	for i := 0; i < numMessages; i++ {
+		anchorChan <- message.NewMessage(fmt.Sprintf("anchor-%d", i), []byte(`{"hashlink":"abcdefg"}`))
+		didChan <- message.NewMessage(fmt.Sprintf("did-%d", i), []byte(fmt.Sprintf(`"did-%d:%d"`, i%3, i)))
+	}
+
+	p := &mocks.PubSub{}
+	p.SubscribeWithOptsStub = func(_ context.Context, topic string, _ ...spi.Option) (<-chan *message.Message, error) {
+		if topic == didTopic {
+			return didChan, nil
+		}
+
+		return anchorChan, nil
+	}
+
+	var (
+		mutex          sync.Mutex
+		active         int
+		maxActive      int
+		processed      int
+		didOrder       = make(map[string][]int)
+		activeSuffixes = make(map[string]bool)
+		overlapped     bool
+	)
+
+	process := func(ctx context.Context, suffix string, seq int) {
+		if suffix == "" {
+			waitForTurn(ctx)
+		} else {
+			waitForTurn(ctx, suffix)
+		}
+
+		mutex.Lock()
+		active++
+
+		if suffix != "" {
+			didOrder[suffix] = append(didOrder[suffix], seq)
+		}
+
+		if active > maxActive {
+			maxActive = active
+		}
+
+		if suffix != "" {
+			if activeSuffixes[suffix] {
+				overlapped = true
+			}
+
+			activeSuffixes[suffix] = true
+		}
+		mutex.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mutex.Lock()
+		active--
+		processed++
+
+		if suffix != "" {
+			activeSuffixes[suffix] = false
+		}
+		mutex.Unlock()
+	}
+
+	ps, err := NewPubSub(p,
+		func(ctx context.Context, anchor *anchorinfo.AnchorInfo) error {
+			process(ctx, "", 0)
+
+			return nil
+		},
+		func(ctx context.Context, did string) error {
+			parts := strings.Split(did, ":")
+
+			seq, err := strconv.Atoi(parts[1])
+			if err != nil {
+				return err
+			}
+
+			process(ctx, parts[0], seq)
+
+			return nil
+		},
+		steadyConcurrency,
+		WithCatchupMode(catchupConcurrency, 0),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, ps)
+	require.NotNil(t, ps.limiter)
+
+	ps.Start()
+	defer ps.Stop()
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return processed == 2*numMessages
+	}, 5*time.Second, 10*time.Millisecond)
+
+	ps.limiter.mutex.Lock()
+	require.False(t, ps.limiter.catchingUp)
+	require.Equal(t, steadyConcurrency, ps.limiter.limit())
+	ps.limiter.mutex.Unlock()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	require.Greater(t, maxActive, steadyConcurrency)
+	require.LessOrEqual(t, maxActive, catchupConcurrency)
+	require.False(t, overlapped, "messages for the same DID must not be processed concurrently")
+	require.Len(t, didOrder, 3)
+
+	for suffix, order := range didOrder {
+		require.Len(t, order, numMessages/3)
+		require.True(t, sort.IntsAreSorted(order), "messages for DID [%s] were processed out of order", suffix)
+	}
+}
+
 func TestPubSub_Error(t *testing.T) {
 	t.Run("Subscribe anchor error", func(t *testing.T) {
 		errExpected := errors.New("injected pub/sub error")
@@ -220,3 +610,15 @@ func TestPubSub_Error(t *testing.T) {
 		require.EqualError(t, ps.PublishDID(context.Background(), "123456"), lifecycle.ErrNotStarted.Error())
 	})
 }
+
+type mockReconnectMetrics struct {
+	reconnects int32
+}
+
+func (m *mockReconnectMetrics) ObserverIncrementReconnectCount() {
+	atomic.AddInt32(&m.reconnects, 1)
+}
+
+func (m *mockReconnectMetrics) count() int {
+	return int(atomic.LoadInt32(&m.reconnects))
+}
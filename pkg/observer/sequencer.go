@@ -0,0 +1,129 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"context"
+	"sync"
+)
+
+// sequencer ensures that messages which refer to the same DID suffix are processed in the order in which they
+// were received, even though messages are processed concurrently. A ticket is issued (in order) for each message
+// as it is received. Before a message is processed, its handler declares the suffixes of the message and waits
+// until every earlier message either has completed or has declared a disjoint set of suffixes.
+type sequencer struct {
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	nextSeq uint64
+	pending map[uint64]*ticket
+}
+
+type ticket struct {
+	s        *sequencer
+	seq      uint64
+	keys     map[string]struct{}
+	declared bool
+}
+
+func newSequencer() *sequencer {
+	s := &sequencer{pending: make(map[uint64]*ticket)}
+	s.cond = sync.NewCond(&s.mutex)
+
+	return s
+}
+
+// issue returns a new ticket. Tickets must be issued in the order in which messages are received.
+func (s *sequencer) issue() *ticket {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	t := &ticket{s: s, seq: s.nextSeq}
+
+	s.nextSeq++
+	s.pending[t.seq] = t
+
+	return t
+}
+
+// wait declares the given keys for the ticket and blocks until no earlier ticket has undeclared keys
+// or any key in common with the given keys.
+func (t *ticket) wait(keys ...string) {
+	s := t.s
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	t.keys = make(map[string]struct{}, len(keys))
+
+	for _, k := range keys {
+		t.keys[k] = struct{}{}
+	}
+
+	t.declared = true
+
+	// Other tickets may be waiting for this ticket to declare its keys.
+	s.cond.Broadcast()
+
+	for s.isBlocked(t) {
+		s.cond.Wait()
+	}
+}
+
+// done releases the ticket. It must be called once the message has been processed (whether or not wait was called).
+func (t *ticket) done() {
+	s := t.s
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.pending, t.seq)
+
+	s.cond.Broadcast()
+}
+
+func (s *sequencer) isBlocked(t *ticket) bool {
+	for seq, other := range s.pending {
+		if seq >= t.seq {
+			continue
+		}
+
+		if !other.declared {
+			return true
+		}
+
+		for k := range t.keys {
+			if _, ok := other.keys[k]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type ticketKey struct{}
+
+func withTicket(ctx context.Context, t *ticket) context.Context {
+	return context.WithValue(ctx, ticketKey{}, t)
+}
+
+func hasTicket(ctx context.Context) bool {
+	_, ok := ctx.Value(ticketKey{}).(*ticket)
+
+	return ok
+}
+
+// waitForTurn waits until the message being processed with the given context may process the given DID suffixes.
+// Nothing is done if the message wasn't issued a ticket (i.e. sequencing is disabled).
+func waitForTurn(ctx context.Context, suffixes ...string) {
+	t, ok := ctx.Value(ticketKey{}).(*ticket)
+	if !ok {
+		return
+	}
+
+	t.wait(suffixes...)
+}
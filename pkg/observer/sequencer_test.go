@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequencer(t *testing.T) {
+	t.Run("Same key -> processed in order", func(t *testing.T) {
+		s := newSequencer()
+
+		t1 := s.issue()
+		t2 := s.issue()
+
+		waited := make(chan struct{})
+
+		go func() {
+			t2.wait("suffix1")
+			close(waited)
+		}()
+
+		t1.wait("suffix1")
+
+		select {
+		case <-waited:
+			t.Fatal("second ticket should wait until the first ticket is done")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		t1.done()
+
+		select {
+		case <-waited:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for second ticket")
+		}
+
+		t2.done()
+	})
+
+	t.Run("Different keys -> processed concurrently", func(t *testing.T) {
+		s := newSequencer()
+
+		t1 := s.issue()
+		t2 := s.issue()
+
+		t1.wait("suffix1")
+
+		waited := make(chan struct{})
+
+		go func() {
+			t2.wait("suffix2")
+			close(waited)
+		}()
+
+		select {
+		case <-waited:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for second ticket")
+		}
+
+		t1.done()
+		t2.done()
+	})
+
+	t.Run("Earlier ticket has not declared its keys -> wait", func(t *testing.T) {
+		s := newSequencer()
+
+		t1 := s.issue()
+		t2 := s.issue()
+
+		waited := make(chan struct{})
+
+		go func() {
+			t2.wait("suffix2")
+			close(waited)
+		}()
+
+		select {
+		case <-waited:
+			t.Fatal("second ticket should wait until the first ticket declares its keys")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		t1.wait("suffix1")
+
+		select {
+		case <-waited:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for second ticket")
+		}
+
+		t1.done()
+		t2.done()
+	})
+
+	t.Run("No ticket in context -> no wait", func(t *testing.T) {
+		s := newSequencer()
+
+		t1 := s.issue()
+		defer t1.done()
+
+		t1.wait("suffix1")
+
+		require.False(t, hasTicket(context.Background()))
+
+		waitForTurn(context.Background(), "suffix1")
+
+		ctx := withTicket(context.Background(), s.issue())
+		require.True(t, hasTicket(ctx))
+	})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	"github.com/trustbloc/orb/pkg/anchor/util"
+)
+
+// cancelSupersededProofMonitoring cancels proof monitoring for each previous anchor of the given anchor payload
+// which has been superseded, i.e. the payload contains a newer operation for every DID suffix in the previous
+// anchor. A failure to cancel monitoring isn't fatal (the monitoring service eventually gives up on its own)
+// so errors are only logged.
+func (o *Observer) cancelSupersededProofMonitoring(hl string, payload *subject.Payload) {
+	for previousHL, suffixes := range getSuffixesByPreviousAnchor(payload) {
+		err := o.cancelProofMonitoringIfSuperseded(previousHL, suffixes)
+		if err != nil {
+			logger.Warn("Error cancelling proof monitoring for previous anchor",
+				logfields.WithAnchorEventURIString(hl), logfields.WithHashlink(previousHL), log.WithError(err))
+		}
+	}
+}
+
+// cancelProofMonitoringIfSuperseded cancels proof monitoring for the given anchor if all of its DID suffixes
+// are contained in the given set of superseded suffixes.
+func (o *Observer) cancelProofMonitoringIfSuperseded(hl string, supersededSuffixes map[string]struct{}) error {
+	anchorLinkset, err := o.AnchorGraph.Read(hl)
+	if err != nil {
+		return fmt.Errorf("read anchor: %w", err)
+	}
+
+	var vcIDs []string
+
+	for _, anchorLink := range anchorLinkset.Linkset {
+		payload, err := o.AnchorLinksetBuilder.GetPayloadFromAnchorLink(anchorLink)
+		if err != nil {
+			return fmt.Errorf("get payload from anchor link: %w", err)
+		}
+
+		for _, sa := range payload.PreviousAnchors {
+			if _, ok := supersededSuffixes[sa.Suffix]; !ok {
+				logger.Debug("Not cancelling proof monitoring for previous anchor since it contains DIDs "+
+					"which have not been superseded", logfields.WithHashlink(hl), logfields.WithSuffix(sa.Suffix))
+
+				return nil
+			}
+		}
+
+		// The credential was verified when the anchor was originally processed so there's no need to check the proof.
+		vc, err := util.VerifiableCredentialFromAnchorLink(anchorLink,
+			verifiable.WithDisabledProofCheck(),
+			verifiable.WithJSONLDDocumentLoader(o.DocLoader),
+		)
+		if err != nil {
+			return fmt.Errorf("get verifiable credential from anchor link: %w", err)
+		}
+
+		if vc.ID != "" {
+			vcIDs = append(vcIDs, vc.ID)
+		}
+	}
+
+	for _, vcID := range vcIDs {
+		if err := o.MonitoringSvc.Cancel(vcID); err != nil {
+			return err
+		}
+
+		logger.Debug("Cancelled proof monitoring for superseded anchor", logfields.WithHashlink(hl),
+			logfields.WithVerifiableCredentialID(vcID))
+	}
+
+	return nil
+}
+
+// getSuffixesByPreviousAnchor returns the DID suffixes in the given payload grouped by previous anchor.
+// Suffixes without a previous anchor (create operations) are ignored.
+func getSuffixesByPreviousAnchor(payload *subject.Payload) map[string]map[string]struct{} {
+	suffixesByAnchor := make(map[string]map[string]struct{})
+
+	for _, sa := range payload.PreviousAnchors {
+		if sa.Anchor == "" {
+			continue
+		}
+
+		suffixes, ok := suffixesByAnchor[sa.Anchor]
+		if !ok {
+			suffixes = make(map[string]struct{})
+			suffixesByAnchor[sa.Anchor] = suffixes
+		}
+
+		suffixes[sa.Suffix] = struct{}{}
+	}
+
+	return suffixesByAnchor
+}
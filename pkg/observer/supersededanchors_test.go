@@ -0,0 +1,221 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-svc-go/pkg/mocks"
+
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset"
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator"
+	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	"github.com/trustbloc/orb/pkg/didanchor/memdidanchor"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/linkset"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	obsmocks "github.com/trustbloc/orb/pkg/observer/mocks"
+	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
+)
+
+const (
+	previousVCID  = "https://orb.domain1.com/vc/0b5b8bb8-6ae3-4a6e-8e23-4b5fa0b50f4a"
+	unrelatedVCID = "https://orb.domain1.com/vc/6c6d64a4-8d6a-4e2a-a4d2-56f5d9f2f9cb"
+)
+
+func TestCancelSupersededProofMonitoring(t *testing.T) {
+	linksets := map[string]*linkset.Linkset{
+		previousHL: newMockAnchorLinkset(t, &subject.Payload{
+			Namespace:       testNamespace,
+			CoreIndex:       "hl:uEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA",
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}, {Suffix: "did2"}},
+		}, withVCID(previousVCID)),
+		unrelated: newMockAnchorLinkset(t, &subject.Payload{
+			Namespace:       testNamespace,
+			CoreIndex:       "hl:uEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA",
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did3"}},
+		}, withVCID(unrelatedVCID)),
+	}
+
+	newAnchorGraph := func() *orbmocks.AnchorGraph {
+		anchorGraph := &orbmocks.AnchorGraph{}
+		anchorGraph.ReadCalls(func(hl string) (*linkset.Linkset, error) {
+			ls, ok := linksets[hl]
+			if !ok {
+				return nil, orberrors.ErrContentNotFound
+			}
+
+			return ls, nil
+		})
+
+		return anchorGraph
+	}
+
+	newObserver := func(t *testing.T, anchorGraph AnchorGraph, monitoringSvc monitoringSvc) *Observer {
+		t.Helper()
+
+		o, err := New(serviceIRI, &Providers{
+			AnchorGraph:          anchorGraph,
+			PubSub:               mempubsub.New(mempubsub.DefaultConfig()),
+			AnchorLinksetBuilder: anchorlinkset.NewBuilder(generator.NewRegistry()),
+			DocLoader:            testutil.GetLoader(t),
+			MonitoringSvc:        monitoringSvc,
+		})
+		require.NoError(t, err)
+
+		return o
+	}
+
+	t.Run("All DIDs superseded -> cancelled", func(t *testing.T) {
+		monitoringSvc := &obsmocks.MonitoringService{}
+
+		o := newObserver(t, newAnchorGraph(), monitoringSvc)
+
+		o.cancelSupersededProofMonitoring(anchorHL, &subject.Payload{
+			PreviousAnchors: []*subject.SuffixAnchor{
+				{Suffix: "did1", Anchor: previousHL},
+				{Suffix: "did2", Anchor: previousHL},
+				{Suffix: "did5"},
+			},
+		})
+
+		require.Equal(t, 1, monitoringSvc.CancelCallCount())
+		require.Equal(t, previousVCID, monitoringSvc.CancelArgsForCall(0))
+	})
+
+	t.Run("Some DIDs superseded -> only the fully superseded anchor is cancelled", func(t *testing.T) {
+		monitoringSvc := &obsmocks.MonitoringService{}
+
+		o := newObserver(t, newAnchorGraph(), monitoringSvc)
+
+		o.cancelSupersededProofMonitoring(anchorHL, &subject.Payload{
+			PreviousAnchors: []*subject.SuffixAnchor{
+				{Suffix: "did1", Anchor: previousHL},
+				{Suffix: "did3", Anchor: unrelated},
+			},
+		})
+
+		require.Equal(t, 1, monitoringSvc.CancelCallCount())
+		require.Equal(t, unrelatedVCID, monitoringSvc.CancelArgsForCall(0))
+	})
+
+	t.Run("Creates only -> nothing to cancel", func(t *testing.T) {
+		anchorGraph := newAnchorGraph()
+		monitoringSvc := &obsmocks.MonitoringService{}
+
+		o := newObserver(t, anchorGraph, monitoringSvc)
+
+		o.cancelSupersededProofMonitoring(anchorHL, &subject.Payload{
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}, {Suffix: "did2"}},
+		})
+
+		require.Zero(t, anchorGraph.ReadCallCount())
+		require.Zero(t, monitoringSvc.CancelCallCount())
+	})
+
+	t.Run("Read previous anchor error -> not cancelled", func(t *testing.T) {
+		anchorGraph := &orbmocks.AnchorGraph{}
+		anchorGraph.ReadReturns(nil, orberrors.NewTransientf("injected read error"))
+
+		monitoringSvc := &obsmocks.MonitoringService{}
+
+		o := newObserver(t, anchorGraph, monitoringSvc)
+
+		o.cancelSupersededProofMonitoring(anchorHL, &subject.Payload{
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1", Anchor: previousHL}},
+		})
+
+		require.Equal(t, 1, anchorGraph.ReadCallCount())
+		require.Zero(t, monitoringSvc.CancelCallCount())
+	})
+
+	t.Run("Invalid previous anchor linkset -> not cancelled", func(t *testing.T) {
+		anchorGraph := &orbmocks.AnchorGraph{}
+		anchorGraph.ReadReturns(linkset.New(&linkset.Link{}), nil)
+
+		monitoringSvc := &obsmocks.MonitoringService{}
+
+		o := newObserver(t, anchorGraph, monitoringSvc)
+
+		require.ErrorContains(t, o.cancelProofMonitoringIfSuperseded(previousHL, map[string]struct{}{"did1": {}}),
+			"get payload from anchor link")
+		require.Zero(t, monitoringSvc.CancelCallCount())
+	})
+
+	t.Run("Cancel error", func(t *testing.T) {
+		monitoringSvc := &obsmocks.MonitoringService{}
+		monitoringSvc.CancelReturns(errors.New("injected cancel error"))
+
+		o := newObserver(t, newAnchorGraph(), monitoringSvc)
+
+		require.ErrorContains(t, o.cancelProofMonitoringIfSuperseded(unrelated, map[string]struct{}{"did3": {}}),
+			"injected cancel error")
+
+		// The error is not fatal.
+		o.cancelSupersededProofMonitoring(anchorHL, &subject.Payload{
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did3", Anchor: unrelated}},
+		})
+
+		require.Equal(t, 2, monitoringSvc.CancelCallCount())
+	})
+}
+
+func TestProcessAnchor_CancelsSupersededProofMonitoring(t *testing.T) {
+	anchorGraph := &orbmocks.AnchorGraph{}
+	anchorGraph.ReadReturns(newMockAnchorLinkset(t, &subject.Payload{
+		Namespace:       testNamespace,
+		CoreIndex:       "hl:uEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}},
+	}, withVCID(previousVCID)), nil)
+
+	anchorLink := newMockAnchorLinkset(t, &subject.Payload{
+		Namespace:       testNamespace,
+		CoreIndex:       "hl:uEiC_17B7wGGQ61SZi2QDQMpQcB-cqLZz1mdBOPcT3cAZBA",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1", Anchor: previousHL}},
+	}).Link()
+
+	tp := &mocks.TxnProcessor{}
+	tp.ProcessReturns(1, nil)
+
+	pc := mocks.NewMockProtocolClient()
+	pc.Versions[0].TransactionProcessorReturns(tp)
+	pc.Versions[0].ProtocolReturns(pc.Protocol)
+
+	monitoringSvc := &obsmocks.MonitoringService{}
+
+	o, err := New(serviceIRI, &Providers{
+		ProtocolClientProvider: mocks.NewMockProtocolClientProvider().WithProtocolClient(testNamespace, pc),
+		AnchorGraph:            anchorGraph,
+		DidAnchors:             memdidanchor.New(),
+		PubSub:                 mempubsub.New(mempubsub.DefaultConfig()),
+		Metrics:                &orbmocks.MetricsProvider{},
+		DocLoader:              testutil.GetLoader(t),
+		Pkf:                    pubKeyFetcherFnc,
+		AnchorLinkStore:        &orbmocks.AnchorLinkStore{},
+		AnchorLinksetBuilder:   anchorlinkset.NewBuilder(generator.NewRegistry()),
+		MonitoringSvc:          monitoringSvc,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, o.processAnchor(context.Background(), &anchorinfo.AnchorInfo{Hashlink: anchorHL}, anchorLink))
+
+	require.Equal(t, 1, tp.ProcessCallCount())
+	require.Equal(t, 1, monitoringSvc.CancelCallCount())
+	require.Equal(t, previousVCID, monitoringSvc.CancelArgsForCall(0))
+}
+
+func withVCID(id string) func(vc *verifiable.Credential) {
+	return func(vc *verifiable.Credential) {
+		vc.ID = id
+	}
+}
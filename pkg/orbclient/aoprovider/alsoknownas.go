@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aoprovider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-go/pkg/document"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	diddoctransformer "github.com/trustbloc/orb/pkg/orbclient/doctransformer"
+)
+
+const webDIDPrefix = "did:web:"
+
+type didResolver interface {
+	ResolveDocument(id string, opts ...document.ResolutionOption) (*document.ResolutionResult, error)
+}
+
+// AlsoKnownAsResult contains the verification result for a single did:web found in 'alsoKnownAs'.
+type AlsoKnownAsResult struct {
+	WebDID   string `json:"webDID"`
+	Verified bool   `json:"verified"`
+	Error    string `json:"error,omitempty"`
+}
+
+// AlsoKnownAsReport contains the verification results for all did:web DIDs found in 'alsoKnownAs'
+// of the resolved did:orb document.
+type AlsoKnownAsReport struct {
+	OrbDID  string               `json:"orbDID"`
+	Results []*AlsoKnownAsResult `json:"results"`
+}
+
+// AllVerified returns true if the report contains at least one did:web and all of them were verified.
+func (r *AlsoKnownAsReport) AllVerified() bool {
+	if len(r.Results) == 0 {
+		return false
+	}
+
+	for _, result := range r.Results {
+		if !result.Verified {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WithDIDResolver sets the DID resolver used to resolve did:orb and did:web documents.
+func WithDIDResolver(resolver didResolver) Option {
+	return func(opts *OrbClient) {
+		opts.didResolver = resolver
+	}
+}
+
+// ResolveAndVerifyAlsoKnownAs resolves the given did:orb document, extracts the did:web DIDs from 'alsoKnownAs',
+// resolves each of them and verifies that the did:web document is derived from the did:orb document.
+// An error is returned only if the did:orb document can't be resolved. Failures for individual did:web DIDs
+// are reported in the returned report.
+func (c *OrbClient) ResolveAndVerifyAlsoKnownAs(orbDID string) (*AlsoKnownAsReport, error) {
+	if c.didResolver == nil {
+		return nil, fmt.Errorf("DID resolver is not configured")
+	}
+
+	orbRR, err := c.didResolver.ResolveDocument(orbDID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID[%s]: %w", orbDID, err)
+	}
+
+	if orbRR.Document == nil {
+		return nil, fmt.Errorf("resolve DID[%s]: document not found in resolution result", orbDID)
+	}
+
+	alsoKnownAs, err := getAlsoKnownAs(orbRR.Document)
+	if err != nil {
+		return nil, fmt.Errorf("DID[%s]: %w", orbDID, err)
+	}
+
+	report := &AlsoKnownAsReport{OrbDID: orbDID}
+
+	for _, uri := range alsoKnownAs {
+		if !strings.HasPrefix(uri, webDIDPrefix) {
+			continue
+		}
+
+		result := &AlsoKnownAsResult{WebDID: uri}
+
+		if err := c.verifyWebDID(uri, orbRR); err != nil {
+			logger.Debug("did:web document is not derived from did:orb document", logfields.WithDID(uri),
+				log.WithError(err))
+
+			result.Error = err.Error()
+		} else {
+			result.Verified = true
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+func (c *OrbClient) verifyWebDID(webDID string, orbRR *document.ResolutionResult) error {
+	webRR, err := c.didResolver.ResolveDocument(webDID)
+	if err != nil {
+		return fmt.Errorf("resolve DID[%s]: %w", webDID, err)
+	}
+
+	if webRR.Document == nil {
+		return fmt.Errorf("resolve DID[%s]: document not found in resolution result", webDID)
+	}
+
+	return diddoctransformer.VerifyWebDocumentFromOrbDocument(webRR, orbRR)
+}
+
+func getAlsoKnownAs(doc document.Document) ([]string, error) {
+	alsoKnownAsObj, ok := doc[document.AlsoKnownAs]
+	if !ok || alsoKnownAsObj == nil {
+		return nil, nil
+	}
+
+	switch alsoKnownAs := alsoKnownAsObj.(type) {
+	case []interface{}:
+		return document.StringArray(alsoKnownAs), nil
+	case []string:
+		return alsoKnownAs, nil
+	default:
+		return nil, fmt.Errorf("unexpected interface '%T' for also known as", alsoKnownAsObj)
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aoprovider
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-go/pkg/document"
+	svcmocks "github.com/trustbloc/sidetree-svc-go/pkg/mocks"
+
+	"github.com/trustbloc/orb/pkg/document/webresolver/mocks"
+	diddoctransformer "github.com/trustbloc/orb/pkg/orbclient/doctransformer"
+)
+
+const (
+	akaOrbDID       = "did:orb:uAAA:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA"
+	akaLinkedWebDID = "did:web:orb.domain1.com:scid:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA"
+	akaOtherWebDID  = "did:web:other.com:scid:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA"
+)
+
+func TestResolveAndVerifyAlsoKnownAs(t *testing.T) {
+	orbRR := getAKAResolutionResult(t, akaOrbResolutionResult)
+
+	linkedWebDoc, err := diddoctransformer.WebDocumentFromOrbDocument(akaLinkedWebDID,
+		getAKAResolutionResult(t, akaOrbResolutionResult))
+	require.NoError(t, err)
+
+	linkedWebRR := &document.ResolutionResult{Document: linkedWebDoc}
+	unlinkedWebRR := getAKAResolutionResult(t, akaUnlinkedWebResolutionResult)
+
+	t.Run("success - linked and unlinked did:web", func(t *testing.T) {
+		resolver := newAKAResolver(map[string]*document.ResolutionResult{
+			akaOrbDID:       orbRR,
+			akaLinkedWebDID: linkedWebRR,
+			akaOtherWebDID:  unlinkedWebRR,
+		})
+
+		client, err := New("did:orb", svcmocks.NewMockCasClient(nil), WithDIDResolver(resolver))
+		require.NoError(t, err)
+
+		report, err := client.ResolveAndVerifyAlsoKnownAs(akaOrbDID)
+		require.NoError(t, err)
+		require.NotNil(t, report)
+		require.Equal(t, akaOrbDID, report.OrbDID)
+		require.Len(t, report.Results, 2)
+		require.False(t, report.AllVerified())
+
+		require.Equal(t, akaLinkedWebDID, report.Results[0].WebDID)
+		require.True(t, report.Results[0].Verified)
+		require.Empty(t, report.Results[0].Error)
+
+		require.Equal(t, akaOtherWebDID, report.Results[1].WebDID)
+		require.False(t, report.Results[1].Verified)
+		require.Contains(t, report.Results[1].Error, "do not match")
+	})
+
+	t.Run("success - all verified", func(t *testing.T) {
+		rr := getAKAResolutionResult(t, akaOrbResolutionResult)
+		rr.Document[document.AlsoKnownAs] = []string{"https://myblog.example/", akaLinkedWebDID}
+
+		webDoc, err := diddoctransformer.WebDocumentFromOrbDocument(akaLinkedWebDID, rr)
+		require.NoError(t, err)
+
+		resolver := newAKAResolver(map[string]*document.ResolutionResult{
+			akaOrbDID:       rr,
+			akaLinkedWebDID: {Document: webDoc},
+		})
+
+		client, err := New("did:orb", svcmocks.NewMockCasClient(nil), WithDIDResolver(resolver))
+		require.NoError(t, err)
+
+		report, err := client.ResolveAndVerifyAlsoKnownAs(akaOrbDID)
+		require.NoError(t, err)
+		require.Len(t, report.Results, 1)
+		require.True(t, report.AllVerified())
+	})
+
+	t.Run("success - no did:web in also known as", func(t *testing.T) {
+		rr := getAKAResolutionResult(t, akaOrbResolutionResult)
+		delete(rr.Document, document.AlsoKnownAs)
+
+		resolver := newAKAResolver(map[string]*document.ResolutionResult{akaOrbDID: rr})
+
+		client, err := New("did:orb", svcmocks.NewMockCasClient(nil), WithDIDResolver(resolver))
+		require.NoError(t, err)
+
+		report, err := client.ResolveAndVerifyAlsoKnownAs(akaOrbDID)
+		require.NoError(t, err)
+		require.Empty(t, report.Results)
+		require.False(t, report.AllVerified())
+	})
+
+	t.Run("did:web resolution error", func(t *testing.T) {
+		resolver := newAKAResolver(map[string]*document.ResolutionResult{
+			akaOrbDID:       orbRR,
+			akaLinkedWebDID: linkedWebRR,
+		})
+
+		client, err := New("did:orb", svcmocks.NewMockCasClient(nil), WithDIDResolver(resolver))
+		require.NoError(t, err)
+
+		report, err := client.ResolveAndVerifyAlsoKnownAs(akaOrbDID)
+		require.NoError(t, err)
+		require.Len(t, report.Results, 2)
+		require.True(t, report.Results[0].Verified)
+		require.False(t, report.Results[1].Verified)
+		require.Contains(t, report.Results[1].Error, "not found")
+	})
+
+	t.Run("error - DID resolver not configured", func(t *testing.T) {
+		client, err := New("did:orb", svcmocks.NewMockCasClient(nil))
+		require.NoError(t, err)
+
+		report, err := client.ResolveAndVerifyAlsoKnownAs(akaOrbDID)
+		require.Error(t, err)
+		require.Nil(t, report)
+		require.Contains(t, err.Error(), "DID resolver is not configured")
+	})
+
+	t.Run("error - did:orb resolution error", func(t *testing.T) {
+		client, err := New("did:orb", svcmocks.NewMockCasClient(nil),
+			WithDIDResolver(newAKAResolver(nil)))
+		require.NoError(t, err)
+
+		report, err := client.ResolveAndVerifyAlsoKnownAs(akaOrbDID)
+		require.Error(t, err)
+		require.Nil(t, report)
+		require.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("error - invalid also known as", func(t *testing.T) {
+		rr := getAKAResolutionResult(t, akaOrbResolutionResult)
+		rr.Document[document.AlsoKnownAs] = 123
+
+		client, err := New("did:orb", svcmocks.NewMockCasClient(nil),
+			WithDIDResolver(newAKAResolver(map[string]*document.ResolutionResult{akaOrbDID: rr})))
+		require.NoError(t, err)
+
+		report, err := client.ResolveAndVerifyAlsoKnownAs(akaOrbDID)
+		require.Error(t, err)
+		require.Nil(t, report)
+		require.Contains(t, err.Error(), "unexpected interface 'int' for also known as")
+	})
+}
+
+func newAKAResolver(results map[string]*document.ResolutionResult) *mocks.OrbResolver {
+	resolver := &mocks.OrbResolver{}
+	resolver.ResolveDocumentStub = func(id string, _ ...document.ResolutionOption) (*document.ResolutionResult, error) {
+		rr, ok := results[id]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+
+		return rr, nil
+	}
+
+	return resolver
+}
+
+func getAKAResolutionResult(t *testing.T, str string) *document.ResolutionResult {
+	t.Helper()
+
+	rr := &document.ResolutionResult{}
+	require.NoError(t, json.Unmarshal([]byte(str), rr))
+
+	return rr
+}
+
+const akaOrbResolutionResult = `{
+  "@context": "https://w3id.org/did-resolution/v1",
+  "didDocument": {
+    "@context": ["https://www.w3.org/ns/did/v1", "https://w3id.org/security/suites/jws-2020/v1"],
+    "alsoKnownAs": [
+      "https://myblog.example/",
+      "did:web:orb.domain1.com:scid:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA",
+      "did:web:other.com:scid:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA"
+    ],
+    "authentication": ["did:orb:uAAA:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA#createKey"],
+    "id": "did:orb:uAAA:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA",
+    "verificationMethod": [
+      {
+        "controller": "did:orb:uAAA:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA",
+        "id": "did:orb:uAAA:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA#createKey",
+        "publicKeyJwk": {
+          "crv": "P-256",
+          "kty": "EC",
+          "x": "k2WMSkwqKWZR6imfF1Nv-OLJLhylNJMX1n8_dRGlYuE",
+          "y": "2ES0qDhNfbMe9CimiYj69zU60mhrXVwVlcwKwhW_DVs"
+        },
+        "type": "JsonWebKey2020"
+      }
+    ]
+  },
+  "didDocumentMetadata": {
+    "canonicalId": "did:orb:uEiCxFGCzgd0gTkoLhnEzP6AOwvM8FHn4QTTb5YjlW4uQHQ:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA",
+    "equivalentId": [
+      "did:orb:uEiCxFGCzgd0gTkoLhnEzP6AOwvM8FHn4QTTb5YjlW4uQHQ:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA"
+    ]
+  }
+}`
+
+const akaUnlinkedWebResolutionResult = `{
+  "@context": "https://w3id.org/did-resolution/v1",
+  "didDocument": {
+    "@context": ["https://www.w3.org/ns/did/v1", "https://w3id.org/security/suites/jws-2020/v1"],
+    "authentication": ["did:web:other.com:scid:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA#otherKey"],
+    "id": "did:web:other.com:scid:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA",
+    "verificationMethod": [
+      {
+        "controller": "did:web:other.com:scid:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA",
+        "id": "did:web:other.com:scid:EiAWS5rfPpy3KK_3l7_aPb4sZsCraVCxFM-SeEqWrVi0RA#otherKey",
+        "publicKeyJwk": {
+          "crv": "P-256",
+          "kty": "EC",
+          "x": "PUymIqdtF_qxaAqPABSw-C-owT1KYYQbsMKFM-L9fJA",
+          "y": "nM84jDHCMOTGTh_ZdHq4dBBdo4Z5PkEOW9jA8z8IsGc"
+        },
+        "type": "JsonWebKey2020"
+      }
+    ]
+  }
+}`
@@ -25,6 +25,7 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/util"
 	"github.com/trustbloc/orb/pkg/config"
 	"github.com/trustbloc/orb/pkg/context/common"
+	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/linkset"
 	"github.com/trustbloc/orb/pkg/orbclient/protocol/nsprovider"
 	"github.com/trustbloc/orb/pkg/orbclient/protocol/verprovider"
@@ -46,6 +47,7 @@ type OrbClient struct {
 	casReader            common.CASReader
 	anchorLinksetBuilder anchorLinksetBuilder
 	disableProofCheck    bool
+	didResolver          didResolver
 }
 
 type namespaceProvider interface {
@@ -137,9 +139,69 @@ func New(namespace string, cas common.CASReader, opts ...Option) (*OrbClient, er
 // GetAnchorOrigin will retrieve anchor credential based on CID, parse Sidetree core index file referenced in anchor
 // credential and return anchor origin.
 func (c *OrbClient) GetAnchorOrigin(cid, suffix string) (interface{}, error) {
+	suffixOp, _, err := c.getAnchoredOperationForSuffix(cid, suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	if suffixOp.Type != operation.TypeCreate && suffixOp.Type != operation.TypeRecover {
+		return nil, fmt.Errorf("anchor origin is only available for 'create' and 'recover' operations")
+	}
+
+	return suffixOp.AnchorOrigin, nil
+}
+
+// GetAnchorOriginChain returns the anchor origins of the given suffix, starting at the anchor with the given CID and
+// following the previous anchor of the suffix up the parent lineage until the anchor of the 'create' operation is
+// reached. The anchor origin is only available for 'create' and 'recover' operations, so anchors which contain
+// other operations are traversed without contributing an origin. The origins are deduplicated and ordered from
+// most-recent to earliest.
+func (c *OrbClient) GetAnchorOriginChain(cid, suffix string) ([]string, error) {
+	var chain []string
+
+	visited := make(map[string]bool)
+
+	for cid != "" {
+		if visited[cid] {
+			return nil, fmt.Errorf("cycle detected in the anchor lineage of suffix[%s] at anchor[%s]", suffix, cid)
+		}
+
+		visited[cid] = true
+
+		suffixOp, anchorPayload, err := c.getAnchoredOperationForSuffix(cid, suffix)
+		if err != nil {
+			return nil, err
+		}
+
+		if suffixOp.Type == operation.TypeCreate || suffixOp.Type == operation.TypeRecover {
+			origin, ok := suffixOp.AnchorOrigin.(string)
+			if !ok {
+				return nil, fmt.Errorf("anchor origin for suffix[%s] in anchor[%s] is not a string", suffix, cid)
+			}
+
+			if !contains(chain, origin) {
+				chain = append(chain, origin)
+			}
+		}
+
+		previousCID, err := getPreviousAnchor(anchorPayload, suffix)
+		if err != nil {
+			return nil, fmt.Errorf("get previous anchor for suffix[%s] in anchor[%s]: %w", suffix, cid, err)
+		}
+
+		cid = previousCID
+	}
+
+	logger.Debug("Resolved anchor origin chain", logfields.WithSuffix(suffix), logfields.WithTotal(len(chain)))
+
+	return chain, nil
+}
+
+func (c *OrbClient) getAnchoredOperationForSuffix(cid, suffix string,
+) (*operation.AnchoredOperation, *subject.Payload, error) {
 	anchorLinksetBytes, err := c.casReader.Read(cid)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read anchor[%s] from CAS: %w", cid, err)
+		return nil, nil, fmt.Errorf("unable to read anchor[%s] from CAS: %w", cid, err)
 	}
 
 	logger.Debug("Got anchor linkset", logfields.WithCID(cid), logfields.WithAnchorLinkset(anchorLinksetBytes))
@@ -148,33 +210,31 @@ func (c *OrbClient) GetAnchorOrigin(cid, suffix string) (interface{}, error) {
 
 	err = json.Unmarshal(anchorLinksetBytes, anchorLinkset)
 	if err != nil {
-		return nil, fmt.Errorf("unmarshal anchor from CID[%s] from CAS: %w", cid, err)
+		return nil, nil, fmt.Errorf("unmarshal anchor from CID[%s] from CAS: %w", cid, err)
 	}
 
 	anchorLink := anchorLinkset.Link()
 	if anchorLink == nil {
-		return nil, fmt.Errorf("empty anchor Linkset [%s]", cid)
+		return nil, nil, fmt.Errorf("empty anchor Linkset [%s]", cid)
 	}
 
 	vc, err := util.VerifiableCredentialFromAnchorLink(anchorLink, c.getParseCredentialOpts()...)
 	if err != nil {
-		return nil, fmt.Errorf("get verifiable credential from anchor for CID[%s]: %w", cid, err)
+		return nil, nil, fmt.Errorf("get verifiable credential from anchor for CID[%s]: %w", cid, err)
 	}
 
-	suffixOp, err := c.getAnchoredOperation(anchorinfo.AnchorInfo{Hashlink: cid}, anchorLink, vc, suffix)
+	suffixOp, anchorPayload, err := c.getAnchoredOperation(anchorinfo.AnchorInfo{Hashlink: cid}, anchorLink, vc, suffix)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get anchored operation for suffix[%s] in anchor[%s]: %w", suffix, cid, err)
+		return nil, nil, fmt.Errorf("failed to get anchored operation for suffix[%s] in anchor[%s]: %w",
+			suffix, cid, err)
 	}
 
-	if suffixOp.Type != operation.TypeCreate && suffixOp.Type != operation.TypeRecover {
-		return nil, fmt.Errorf("anchor origin is only available for 'create' and 'recover' operations")
-	}
-
-	return suffixOp.AnchorOrigin, nil
+	return suffixOp, anchorPayload, nil
 }
 
 func (c *OrbClient) getParseCredentialOpts() []verifiable.CredentialOpt {
-	var opts []verifiable.CredentialOpt
+	opts := []verifiable.CredentialOpt{verifiable.WithStrictValidation()}
+
 	if c.publicKeyFetcher != nil {
 		opts = append(opts, verifiable.WithPublicKeyFetcher(c.publicKeyFetcher))
 	}
@@ -190,20 +250,20 @@ func (c *OrbClient) getParseCredentialOpts() []verifiable.CredentialOpt {
 	return opts
 }
 
-func (c *OrbClient) getAnchoredOperation(anchor anchorinfo.AnchorInfo, anchorLink *linkset.Link, vc *verifiable.Credential, suffix string) (*operation.AnchoredOperation, error) { //nolint:lll
+func (c *OrbClient) getAnchoredOperation(anchor anchorinfo.AnchorInfo, anchorLink *linkset.Link, vc *verifiable.Credential, suffix string) (*operation.AnchoredOperation, *subject.Payload, error) { //nolint:lll
 	anchorPayload, err := c.anchorLinksetBuilder.GetPayloadFromAnchorLink(anchorLink)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract anchor payload from anchor[%s]: %w", anchor.Hashlink, err)
+		return nil, nil, fmt.Errorf("failed to extract anchor payload from anchor[%s]: %w", anchor.Hashlink, err)
 	}
 
 	pc, err := c.nsProvider.ForNamespace(anchorPayload.Namespace)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get client versions for namespace [%s]: %w", anchorPayload.Namespace, err)
+		return nil, nil, fmt.Errorf("failed to get client versions for namespace [%s]: %w", anchorPayload.Namespace, err)
 	}
 
 	v, err := pc.Get(anchorPayload.Version)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get client version for version[%d]: %w", anchorPayload.Version, err)
+		return nil, nil, fmt.Errorf("failed to get client version for version[%d]: %w", anchorPayload.Version, err)
 	}
 
 	ad := &util.AnchorData{OperationCount: anchorPayload.OperationCount, CoreIndexFileURI: anchorPayload.CoreIndex}
@@ -221,10 +281,15 @@ func (c *OrbClient) getAnchoredOperation(anchor anchorinfo.AnchorInfo, anchorLin
 
 	txnOps, err := v.OperationProvider().GetTxnOperations(&sidetreeTxn)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve operations for anchor string[%s]: %w", sidetreeTxn.AnchorString, err)
+		return nil, nil, fmt.Errorf("failed to retrieve operations for anchor string[%s]: %w", sidetreeTxn.AnchorString, err)
+	}
+
+	suffixOp, err := getSuffixOp(txnOps, suffix)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return getSuffixOp(txnOps, suffix)
+	return suffixOp, anchorPayload, nil
 }
 
 func getSuffixOp(txnOps []*operation.AnchoredOperation, suffix string) (*operation.AnchoredOperation, error) {
@@ -236,3 +301,31 @@ func getSuffixOp(txnOps []*operation.AnchoredOperation, suffix string) (*operati
 
 	return nil, fmt.Errorf("suffix[%s] not found in anchored operations", suffix)
 }
+
+// getPreviousAnchor returns the CID of the previous anchor of the given suffix in the given payload or an empty
+// string if the suffix has no previous anchor (i.e. the payload contains the 'create' operation).
+func getPreviousAnchor(payload *subject.Payload, suffix string) (string, error) {
+	for _, prev := range payload.PreviousAnchors {
+		if prev.Suffix != suffix {
+			continue
+		}
+
+		if prev.Anchor == "" {
+			return "", nil
+		}
+
+		return hashlink.GetResourceHashFromHashLink(prev.Anchor)
+	}
+
+	return "", nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
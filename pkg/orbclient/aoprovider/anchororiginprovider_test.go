@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/stretchr/testify/require"
 	stoperation "github.com/trustbloc/sidetree-go/pkg/api/operation"
+	txnapi "github.com/trustbloc/sidetree-svc-go/pkg/api/txn"
 	svcmocks "github.com/trustbloc/sidetree-svc-go/pkg/mocks"
 
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
@@ -24,13 +25,18 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/builder"
 	"github.com/trustbloc/orb/pkg/anchor/subject"
 	"github.com/trustbloc/orb/pkg/datauri"
+	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/linkset"
 	"github.com/trustbloc/orb/pkg/orbclient/mocks"
 	"github.com/trustbloc/orb/pkg/orbclient/protocol/nsprovider"
 )
 
-const testDID = "did"
+const (
+	testDID              = "did"
+	testHashlinkMetadata = "uoQ-CeEdodHRwczovL2V4YW1wbGUuY29tL2Nhcy91RWlBc2l3amFYT1lEbU9IeG12RGwzTXgwVGZKMHVDYXI1WVhxdW1q" +
+		"RkpVTklCZ3g1aXBmczovL1FtVUI5TnI3UnBxTllRcHloNFc5cjNSUU50dGlQUTZCUTlpUUxrdzlMenRKRno"
+)
 
 func TestNew(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
@@ -288,6 +294,160 @@ func TestGetAnchorOrigin(t *testing.T) {
 	})
 }
 
+func TestGetAnchorOriginChain(t *testing.T) {
+	const (
+		origin1 = "https://orb.domain1.com"
+		origin2 = "https://orb.domain2.com"
+	)
+
+	t.Run("success - origins of grandparent (create) and parent (recover)", func(t *testing.T) {
+		casClient := svcmocks.NewMockCasClient(nil)
+
+		grandparentCID := writeMockAnchorLinkset(t, casClient, "")
+		parentCID := writeMockAnchorLinkset(t, casClient, grandparentCID)
+		childCID := writeMockAnchorLinkset(t, casClient, parentCID)
+
+		client := newMockOrbClient(t, casClient, map[string]*stoperation.AnchoredOperation{
+			grandparentCID: {Type: stoperation.TypeCreate, UniqueSuffix: testDID, AnchorOrigin: origin1},
+			parentCID:      {Type: stoperation.TypeRecover, UniqueSuffix: testDID, AnchorOrigin: origin2},
+			childCID:       {Type: stoperation.TypeUpdate, UniqueSuffix: testDID},
+		})
+
+		chain, err := client.GetAnchorOriginChain(childCID, testDID)
+		require.NoError(t, err)
+		require.Equal(t, []string{origin2, origin1}, chain)
+
+		chain, err = client.GetAnchorOriginChain(parentCID, testDID)
+		require.NoError(t, err)
+		require.Equal(t, []string{origin2, origin1}, chain)
+
+		chain, err = client.GetAnchorOriginChain(grandparentCID, testDID)
+		require.NoError(t, err)
+		require.Equal(t, []string{origin1}, chain)
+	})
+
+	t.Run("success - duplicate origins", func(t *testing.T) {
+		casClient := svcmocks.NewMockCasClient(nil)
+
+		grandparentCID := writeMockAnchorLinkset(t, casClient, "")
+		parentCID := writeMockAnchorLinkset(t, casClient, grandparentCID)
+		childCID := writeMockAnchorLinkset(t, casClient, parentCID)
+
+		client := newMockOrbClient(t, casClient, map[string]*stoperation.AnchoredOperation{
+			grandparentCID: {Type: stoperation.TypeCreate, UniqueSuffix: testDID, AnchorOrigin: origin1},
+			parentCID:      {Type: stoperation.TypeRecover, UniqueSuffix: testDID, AnchorOrigin: origin2},
+			childCID:       {Type: stoperation.TypeRecover, UniqueSuffix: testDID, AnchorOrigin: origin1},
+		})
+
+		chain, err := client.GetAnchorOriginChain(childCID, testDID)
+		require.NoError(t, err)
+		require.Equal(t, []string{origin1, origin2}, chain)
+	})
+
+	t.Run("error - parent not found", func(t *testing.T) {
+		casClient := svcmocks.NewMockCasClient(nil)
+
+		childCID := writeMockAnchorLinkset(t, casClient, "uEiDuIicNljP8PoHJk6_aA7w1d4U3FAvDMfF7Dsh7fkw3Wg")
+
+		client := newMockOrbClient(t, casClient, map[string]*stoperation.AnchoredOperation{
+			childCID: {Type: stoperation.TypeUpdate, UniqueSuffix: testDID},
+		})
+
+		chain, err := client.GetAnchorOriginChain(childCID, testDID)
+		require.Error(t, err)
+		require.Empty(t, chain)
+		require.Contains(t, err.Error(), "unable to read anchor[uEiDuIicNljP8PoHJk6_aA7w1d4U3FAvDMfF7Dsh7fkw3Wg]")
+	})
+
+	t.Run("error - anchor origin is not a string", func(t *testing.T) {
+		casClient := svcmocks.NewMockCasClient(nil)
+
+		cid := writeMockAnchorLinkset(t, casClient, "")
+
+		client := newMockOrbClient(t, casClient, map[string]*stoperation.AnchoredOperation{
+			cid: {Type: stoperation.TypeCreate, UniqueSuffix: testDID, AnchorOrigin: 1},
+		})
+
+		chain, err := client.GetAnchorOriginChain(cid, testDID)
+		require.Error(t, err)
+		require.Empty(t, chain)
+		require.Contains(t, err.Error(), "is not a string")
+	})
+
+	t.Run("error - suffix not found in anchor", func(t *testing.T) {
+		casClient := svcmocks.NewMockCasClient(nil)
+
+		cid := writeMockAnchorLinkset(t, casClient, "")
+
+		client := newMockOrbClient(t, casClient, nil)
+
+		chain, err := client.GetAnchorOriginChain(cid, testDID)
+		require.Error(t, err)
+		require.Empty(t, chain)
+		require.Contains(t, err.Error(), "suffix[did] not found in anchored operations")
+	})
+}
+
+// writeMockAnchorLinkset writes an anchor linkset for the test DID to the given CAS and returns the CID. If
+// previousCID is not empty then it's set as the previous anchor of the test DID.
+func writeMockAnchorLinkset(t *testing.T, casClient *svcmocks.MockCasClient, previousCID string) string {
+	t.Helper()
+
+	previousAnchor := &subject.SuffixAnchor{Suffix: testDID}
+
+	if previousCID != "" {
+		previousAnchor.Anchor = hashlink.GetHashLinkFromResourceHash(previousCID) + ":" + testHashlinkMetadata
+	}
+
+	payload := subject.Payload{
+		OperationCount:  1,
+		CoreIndex:       "hl:uEiCHyWu0mRjSGe1OH6y545ALCHakBKr6E5vdVk4Re4qgdg",
+		Namespace:       "did:orb",
+		Version:         0,
+		PreviousAnchors: []*subject.SuffixAnchor{previousAnchor},
+	}
+
+	cid, err := casClient.Write(testutil.MarshalCanonical(t, newMockAnchorLinkset(t, &payload)))
+	require.NoError(t, err)
+
+	return cid
+}
+
+// newMockOrbClient returns a client whose operation provider returns the given operation for an anchor (keyed by CID).
+func newMockOrbClient(t *testing.T, casClient *svcmocks.MockCasClient,
+	ops map[string]*stoperation.AnchoredOperation,
+) *OrbClient {
+	t.Helper()
+
+	client, err := New("did:orb", casClient,
+		WithDisableProofCheck(true),
+		WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+	require.NoError(t, err)
+
+	opsProvider := &svcmocks.OperationProvider{}
+	opsProvider.GetTxnOperationsStub = func(sidetreeTxn *txnapi.SidetreeTxn) ([]*stoperation.AnchoredOperation, error) {
+		op, ok := ops[sidetreeTxn.CanonicalReference]
+		if !ok {
+			return nil, nil
+		}
+
+		return []*stoperation.AnchoredOperation{op}, nil
+	}
+
+	clientVer := &svcmocks.ProtocolVersion{}
+	clientVer.OperationProviderReturns(opsProvider)
+
+	clientVerProvider := &mocks.ClientVersionProvider{}
+	clientVerProvider.GetReturns(clientVer, nil)
+
+	nsProvider := nsprovider.New()
+	nsProvider.Add("did:orb", clientVerProvider)
+
+	client.nsProvider = nsProvider
+
+	return client
+}
+
 func newMockAnchorLinkset(t *testing.T, payload *subject.Payload) *linkset.Linkset {
 	t.Helper()
 
@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didhint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/trustbloc/sidetree-go/pkg/docutil"
+
+	"github.com/trustbloc/orb/pkg/document/util"
+)
+
+const (
+	// didParts is the number of parts in a DID without a hint, i.e. did:<method>:<CID or uAAA>:<suffix>.
+	didParts = 4
+
+	// maxHintParts is the maximum number of parts in a hint, e.g. https:orb.domain1.com.
+	maxHintParts = 2
+)
+
+// BuildHintedDID inserts the given hint into the given canonical or interim DID and returns the hinted
+// (equivalent) DID. For example, the canonical DID did:orb:uEiA...:EiD... with the hint https:orb.domain1.com
+// results in did:orb:https:orb.domain1.com:uEiA...:EiD... The hint is either a single part (e.g. ipfs) or a
+// scheme and domain (e.g. https:orb.domain1.com).
+//
+// An interim DID (which contains uAAA in place of the CID) has only one equivalent ID, which is the DID
+// hinted with the domain of the server at which the DID was created, so the hint for an interim DID must
+// contain a domain. A canonical (published) DID may be hinted with either a domain (WebCAS) or ipfs.
+//
+// An error is returned if the DID or the hint is malformed or if the DID already contains a hint.
+func BuildHintedDID(canonicalOrInterimDID, hint string) (string, error) {
+	if !util.IsDID(canonicalOrInterimDID) {
+		return "", fmt.Errorf("invalid DID [%s]: missing did: prefix", canonicalOrInterimDID)
+	}
+
+	parts := strings.Split(canonicalOrInterimDID, docutil.NamespaceDelimiter)
+
+	if len(parts) > didParts && len(parts) <= didParts+maxHintParts {
+		return "", fmt.Errorf("invalid DID [%s]: DID already contains a hint", canonicalOrInterimDID)
+	}
+
+	if len(parts) != didParts {
+		return "", fmt.Errorf("invalid DID [%s]: expecting %d parts but got %d",
+			canonicalOrInterimDID, didParts, len(parts))
+	}
+
+	if parts[1] == "" {
+		return "", fmt.Errorf("invalid DID [%s]: empty method", canonicalOrInterimDID)
+	}
+
+	hintParts, err := parseHint(hint)
+	if err != nil {
+		return "", err
+	}
+
+	if parts[2] == util.UnpublishedDIDLabel && len(hintParts) != maxHintParts {
+		return "", fmt.Errorf("invalid hint [%s] for interim DID [%s]: expecting a scheme and domain, "+
+			"e.g. https:orb.domain1.com", hint, canonicalOrInterimDID)
+	}
+
+	namespace := strings.Join(parts[:2], docutil.NamespaceDelimiter)
+
+	hintedDID := strings.Join([]string{namespace, hint, parts[2], parts[3]}, docutil.NamespaceDelimiter)
+
+	if err := util.ValidateOrbDID(hintedDID, namespace); err != nil {
+		return "", err
+	}
+
+	return hintedDID, nil
+}
+
+func parseHint(hint string) ([]string, error) {
+	if hint == "" {
+		return nil, errors.New("hint is required")
+	}
+
+	hintParts := strings.Split(hint, docutil.NamespaceDelimiter)
+
+	if len(hintParts) > maxHintParts {
+		return nil, fmt.Errorf("invalid hint [%s]: too many parts", hint)
+	}
+
+	for _, p := range hintParts {
+		if p == "" {
+			return nil, fmt.Errorf("invalid hint [%s]: empty part", hint)
+		}
+	}
+
+	if len(hintParts) == maxHintParts && hintParts[0] != "https" && hintParts[0] != "http" {
+		return nil, fmt.Errorf("invalid hint [%s]: unsupported scheme [%s]", hint, hintParts[0])
+	}
+
+	return hintParts, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didhint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	suffix       = "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+	cid          = "uEiAWJO75bnXrNTn3QWUj4ey1iTV_yYI4FuqxSlbCU0dAfQ"
+	canonicalDID = "did:orb:" + cid + ":" + suffix
+	interimDID   = "did:orb:uAAA:" + suffix
+)
+
+func TestBuildHintedDID(t *testing.T) {
+	t.Run("Canonical DID", func(t *testing.T) {
+		t.Run("Domain hint", func(t *testing.T) {
+			hintedDID, err := BuildHintedDID(canonicalDID, "https:orb.domain1.com")
+			require.NoError(t, err)
+			require.Equal(t, "did:orb:https:orb.domain1.com:"+cid+":"+suffix, hintedDID)
+		})
+
+		t.Run("IPFS hint", func(t *testing.T) {
+			hintedDID, err := BuildHintedDID(canonicalDID, "ipfs")
+			require.NoError(t, err)
+			require.Equal(t, "did:orb:ipfs:"+cid+":"+suffix, hintedDID)
+		})
+	})
+
+	t.Run("Interim DID", func(t *testing.T) {
+		t.Run("Domain hint", func(t *testing.T) {
+			hintedDID, err := BuildHintedDID(interimDID, "https:orb.domain1.com")
+			require.NoError(t, err)
+			require.Equal(t, "did:orb:https:orb.domain1.com:uAAA:"+suffix, hintedDID)
+		})
+
+		t.Run("IPFS hint -> error", func(t *testing.T) {
+			_, err := BuildHintedDID(interimDID, "ipfs")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "expecting a scheme and domain")
+		})
+	})
+
+	t.Run("Invalid DID -> error", func(t *testing.T) {
+		tests := []struct {
+			did string
+			err string
+		}{
+			{did: "orb:" + cid + ":" + suffix, err: "missing did: prefix"},
+			{did: "did:orb:" + suffix, err: "expecting 4 parts but got 3"},
+			{did: "did:orb:ipfs:" + cid + ":" + suffix, err: "DID already contains a hint"},
+			{did: "did:orb:https:orb.domain1.com:" + cid + ":" + suffix, err: "DID already contains a hint"},
+			{did: "did:orb:a:b:c:" + cid + ":" + suffix, err: "expecting 4 parts but got 7"},
+			{did: "did::" + cid + ":" + suffix, err: "empty method"},
+			{did: "did:orb::" + suffix, err: "empty CID"},
+			{did: "did:orb:" + cid + ":", err: "empty suffix"},
+			{did: "did:orb:" + cid + ":Ei/D", err: "contains invalid characters"},
+		}
+
+		for _, test := range tests {
+			_, err := BuildHintedDID(test.did, "ipfs")
+			require.Error(t, err, test.did)
+			require.Contains(t, err.Error(), test.err, test.did)
+		}
+	})
+
+	t.Run("Invalid hint -> error", func(t *testing.T) {
+		tests := []struct {
+			hint string
+			err  string
+		}{
+			{hint: "", err: "hint is required"},
+			{hint: "https:orb.domain1.com:443", err: "too many parts"},
+			{hint: "https:", err: "empty part"},
+			{hint: "ftp:orb.domain1.com", err: "unsupported scheme [ftp]"},
+		}
+
+		for _, test := range tests {
+			_, err := BuildHintedDID(canonicalDID, test.hint)
+			require.Error(t, err, test.hint)
+			require.Contains(t, err.Error(), test.err, test.hint)
+		}
+	})
+}
@@ -9,8 +9,11 @@ package resolutionverifier
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/piprate/json-gold/ld"
 	"github.com/trustbloc/sidetree-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
@@ -41,6 +44,11 @@ type ResolutionVerifier struct {
 	methodContexts []string
 	anchorOrigins  []string
 	enableBase     bool
+
+	trustedWitnesses    map[string]struct{}
+	minTrustedWitnesses int
+	publicKeyFetcher    verifiable.PublicKeyFetcher
+	docLoader           ld.DocumentLoader
 }
 
 // operationProcessor is an interface which resolves the document based on operations provided.
@@ -56,8 +64,9 @@ func New(namespace string, opts ...Option) (*ResolutionVerifier, error) {
 	opStore := &noopOperationStore{}
 
 	rv := &ResolutionVerifier{
-		namespace: namespace,
-		versions:  []string{v1},
+		namespace:           namespace,
+		versions:            []string{v1},
+		minTrustedWitnesses: 1,
 	}
 
 	// apply options
@@ -70,6 +79,19 @@ func New(namespace string, opts ...Option) (*ResolutionVerifier, error) {
 		return nil, fmt.Errorf("failed to create protocol client provider: %w", err)
 	}
 
+	if rv.minTrustedWitnesses < 1 {
+		return nil, errors.New("minimum number of trusted witnesses must be at least 1")
+	}
+
+	if len(rv.trustedWitnesses) > 0 && rv.minTrustedWitnesses > len(rv.trustedWitnesses) {
+		return nil, fmt.Errorf("minimum number of trusted witnesses [%d] exceeds the number of trusted witnesses [%d]",
+			rv.minTrustedWitnesses, len(rv.trustedWitnesses))
+	}
+
+	if len(rv.trustedWitnesses) > 0 && (rv.publicKeyFetcher == nil || rv.docLoader == nil) {
+		return nil, errors.New("public key fetcher and document loader are required to verify trusted witnesses")
+	}
+
 	rv.protocol = pc
 
 	rv.processor = processor.New(namespace, opStore, pc)
@@ -113,6 +135,45 @@ func WithEnableBase(enabled bool) Option {
 	}
 }
 
+// WithTrustedWitnesses sets the optional set of trusted witness domains (e.g. https://orb.domain1.com or
+// orb.domain1.com). If set then the resolution result must be verified using VerifyWithAnchorLinkset and
+// the anchor credential must contain proofs from at least the minimum number of trusted witnesses
+// (see WithMinTrustedWitnesses). The witness proofs are verified using the public key fetcher and document loader
+// (see WithPublicKeyFetcher and WithJSONLDDocumentLoader), which must also be set.
+func WithTrustedWitnesses(domains []string) Option {
+	return func(opts *ResolutionVerifier) {
+		opts.trustedWitnesses = make(map[string]struct{})
+
+		for _, domain := range domains {
+			opts.trustedWitnesses[witnessHost(domain)] = struct{}{}
+		}
+	}
+}
+
+// WithMinTrustedWitnesses sets the minimum number of distinct trusted witnesses which must have provided
+// a proof on the anchor credential. Defaults to 1.
+func WithMinTrustedWitnesses(n int) Option {
+	return func(opts *ResolutionVerifier) {
+		opts.minTrustedWitnesses = n
+	}
+}
+
+// WithPublicKeyFetcher sets the public key fetcher which is used to verify the witness proofs on the
+// anchor credential. Required if trusted witnesses are configured.
+func WithPublicKeyFetcher(pkf verifiable.PublicKeyFetcher) Option {
+	return func(opts *ResolutionVerifier) {
+		opts.publicKeyFetcher = pkf
+	}
+}
+
+// WithJSONLDDocumentLoader sets the document loader which is used to verify the witness proofs on the
+// anchor credential. Required if trusted witnesses are configured.
+func WithJSONLDDocumentLoader(docLoader ld.DocumentLoader) Option {
+	return func(opts *ResolutionVerifier) {
+		opts.docLoader = docLoader
+	}
+}
+
 func getProtocolClient(namespace string, versions []string, currentVersion string, methodContexts []string, enableBase bool) (svcprotocol.Client, error) { //nolint:lll
 	registry := clientregistry.New()
 
@@ -150,7 +211,18 @@ func getProtocolClient(namespace string, versions []string, currentVersion strin
 
 // Verify will verify provided resolution result against resolution result that is assembled
 // from published and unpublished operations in provided resolution result.
+//
+// If trusted witnesses are configured (see WithTrustedWitnesses) then ErrAnchorLinksetRequired is returned
+// since the witness proofs can't be checked without the anchor linkset. Use VerifyWithAnchorLinkset instead.
 func (r *ResolutionVerifier) Verify(input *document.ResolutionResult) error {
+	if len(r.trustedWitnesses) > 0 {
+		return ErrAnchorLinksetRequired
+	}
+
+	return r.verify(input)
+}
+
+func (r *ResolutionVerifier) verify(input *document.ResolutionResult) error {
 	// get operations from document metadata
 	operations, err := getOperations(input.DocumentMetadata)
 	if err != nil {
@@ -172,6 +244,83 @@ func (r *ResolutionVerifier) Verify(input *document.ResolutionResult) error {
 	return nil
 }
 
+// ResolveUntransformed assembles the document from the published and unpublished operations in the provided
+// resolution result and returns the internal resolution model, i.e. the document and metadata as produced by
+// applying the operations but before the document transformer is applied (so the ID, context, canonical ID,
+// equivalent IDs, etc. are not injected). This is intended for diagnostics only.
+func (r *ResolutionVerifier) ResolveUntransformed(input *document.ResolutionResult) (*protocol.ResolutionModel, error) {
+	operations, err := getOperations(input.DocumentMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("resolution result for [%s] does not contain any published or unpublished operations",
+			input.Document.ID())
+	}
+
+	suffix, err := util.GetSuffix(input.Document.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	internalResult, err := r.processor.Resolve(suffix, document.WithAdditionalOperations(operations))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve document with provided operations: %w", err)
+	}
+
+	return internalResult, nil
+}
+
+// ReplayStep contains the (untransformed) state of a document after applying a single operation.
+type ReplayStep struct {
+	Operation *operation.AnchoredOperation
+	Published bool
+	Result    *protocol.ResolutionModel
+}
+
+// Replay applies the published and unpublished operations in the provided resolution result one at a time
+// (in the order in which they appear in the method metadata) and returns the untransformed state of the
+// document after each operation. This is intended for diagnostics only.
+func (r *ResolutionVerifier) Replay(input *document.ResolutionResult) ([]*ReplayStep, error) {
+	publishedOps, unpublishedOps, err := getPublishedAndUnpublishedOperations(input.DocumentMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []*operation.AnchoredOperation
+
+	operations = append(operations, publishedOps...)
+	operations = append(operations, unpublishedOps...)
+
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("resolution result for [%s] does not contain any published or unpublished operations",
+			input.Document.ID())
+	}
+
+	suffix, err := util.GetSuffix(input.Document.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]*ReplayStep, len(operations))
+
+	for i, op := range operations {
+		rm, err := r.processor.Resolve(suffix, document.WithAdditionalOperations(operations[:i+1]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay operation %d (%s): %w", i+1, op.Type, err)
+		}
+
+		steps[i] = &ReplayStep{
+			Operation: op,
+			Published: i < len(publishedOps),
+			Result:    rm,
+		}
+	}
+
+	return steps, nil
+}
+
 func (r *ResolutionVerifier) resolveDocument(id string, ops ...*operation.AnchoredOperation) (*document.ResolutionResult, error) {
 	pv, err := r.protocol.Current()
 	if err != nil {
@@ -204,22 +353,32 @@ func (r *ResolutionVerifier) resolveDocument(id string, ops ...*operation.Anchor
 }
 
 func getOperations(metadata document.Metadata) ([]*operation.AnchoredOperation, error) {
-	methodMetadata, err := util.GetMethodMetadata(metadata)
+	publishedOps, unpublishedOps, err := getPublishedAndUnpublishedOperations(metadata)
 	if err != nil {
 		return nil, err
 	}
 
+	return append(publishedOps, unpublishedOps...), nil
+}
+
+func getPublishedAndUnpublishedOperations(metadata document.Metadata,
+) ([]*operation.AnchoredOperation, []*operation.AnchoredOperation, error) {
+	methodMetadata, err := util.GetMethodMetadata(metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	unpublishedOps, err := getOperationsByKey(methodMetadata, document.UnpublishedOperationsProperty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get unpublished operations: %w", err)
+		return nil, nil, fmt.Errorf("failed to get unpublished operations: %w", err)
 	}
 
 	publishedOps, err := getOperationsByKey(methodMetadata, document.PublishedOperationsProperty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get published operations: %w", err)
+		return nil, nil, fmt.Errorf("failed to get published operations: %w", err)
 	}
 
-	return append(publishedOps, unpublishedOps...), nil
+	return publishedOps, unpublishedOps, nil
 }
 
 func getOperationsByKey(methodMetadata map[string]interface{}, key string) ([]*operation.AnchoredOperation, error) {
@@ -208,6 +208,205 @@ func TestResolveVerifier_Verify(t *testing.T) {
 	})
 }
 
+func TestResolveVerifier_ResolveUntransformed(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var rr document.ResolutionResult
+		err := json.Unmarshal([]byte(publishedAndUnpublishedRR), &rr)
+		require.NoError(t, err)
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		rm, err := handler.ResolveUntransformed(&rr)
+		require.NoError(t, err)
+		require.NotNil(t, rm.Doc)
+		require.Empty(t, rm.Doc.ID())
+		require.Empty(t, rm.Doc.Context())
+		require.NotEmpty(t, rm.UpdateCommitment)
+		require.NotEmpty(t, rm.RecoveryCommitment)
+		require.Len(t, rm.PublishedOperations, 1)
+		require.Len(t, rm.UnpublishedOperations, 1)
+	})
+
+	t.Run("error - no operations", func(t *testing.T) {
+		doc := make(document.Document)
+		doc["id"] = "did:orb:hash:suffix"
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		_, err = handler.ResolveUntransformed(&document.ResolutionResult{
+			Document:         doc,
+			DocumentMetadata: document.Metadata{document.MethodProperty: map[string]interface{}{}},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not contain any published or unpublished operations")
+	})
+
+	t.Run("error - failed to unmarshal published operations", func(t *testing.T) {
+		methodMetadata := make(map[string]interface{})
+		methodMetadata[document.PublishedOperationsProperty] = "published-ops"
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		_, err = handler.ResolveUntransformed(&document.ResolutionResult{
+			DocumentMetadata: document.Metadata{document.MethodProperty: methodMetadata},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get published operations: failed to unmarshal")
+	})
+
+	t.Run("error - invalid document ID (unable to get suffix)", func(t *testing.T) {
+		methodMetadata := make(map[string]interface{})
+		methodMetadata[document.PublishedOperationsProperty] = []metadata.PublishedOperation{
+			{Type: operation.TypeUpdate, CanonicalReference: "abc"},
+		}
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		_, err = handler.ResolveUntransformed(&document.ResolutionResult{
+			DocumentMetadata: document.Metadata{document.MethodProperty: methodMetadata},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid number of parts[1] for Orb identifier")
+	})
+
+	t.Run("error - resolver error (create operation missing)", func(t *testing.T) {
+		methodMetadata := make(map[string]interface{})
+		methodMetadata[document.PublishedOperationsProperty] = []metadata.PublishedOperation{
+			{Type: operation.TypeUpdate, CanonicalReference: "abc"},
+		}
+
+		doc := make(document.Document)
+		doc["id"] = "did:orb:hash:suffix"
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		_, err = handler.ResolveUntransformed(&document.ResolutionResult{
+			Document:         doc,
+			DocumentMetadata: document.Metadata{document.MethodProperty: methodMetadata},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to resolve document with provided operations: create operation not found")
+	})
+}
+
+func TestResolveVerifier_Replay(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var rr document.ResolutionResult
+		require.NoError(t, json.Unmarshal([]byte(multiplePublishedAndUnpublishedRR), &rr))
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		steps, err := handler.Replay(&rr)
+		require.NoError(t, err)
+		require.Len(t, steps, 4)
+
+		require.Equal(t, operation.TypeCreate, steps[0].Operation.Type)
+		require.True(t, steps[0].Published)
+		require.True(t, steps[2].Published)
+		require.False(t, steps[3].Published)
+
+		for i := 1; i < len(steps); i++ {
+			require.NotEqual(t, steps[i-1].Result.UpdateCommitment, steps[i].Result.UpdateCommitment)
+		}
+
+		// The state after the last operation is the same as the untransformed resolution result.
+		rm, err := handler.ResolveUntransformed(&rr)
+		require.NoError(t, err)
+		require.Equal(t, rm.Doc, steps[3].Result.Doc)
+		require.Equal(t, rm.UpdateCommitment, steps[3].Result.UpdateCommitment)
+	})
+
+	t.Run("success - deactivated document", func(t *testing.T) {
+		var rr document.ResolutionResult
+		require.NoError(t, json.Unmarshal([]byte(deactivatedRR), &rr))
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		steps, err := handler.Replay(&rr)
+		require.NoError(t, err)
+		require.NotEmpty(t, steps)
+
+		last := steps[len(steps)-1]
+		require.Equal(t, operation.TypeDeactivate, last.Operation.Type)
+		require.True(t, last.Result.Deactivated)
+		require.Empty(t, last.Result.Doc)
+
+		require.False(t, steps[0].Result.Deactivated)
+	})
+
+	t.Run("error - no operations", func(t *testing.T) {
+		doc := make(document.Document)
+		doc["id"] = "did:orb:hash:suffix"
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		_, err = handler.Replay(&document.ResolutionResult{
+			Document:         doc,
+			DocumentMetadata: document.Metadata{document.MethodProperty: map[string]interface{}{}},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not contain any published or unpublished operations")
+	})
+
+	t.Run("error - failed to unmarshal published operations", func(t *testing.T) {
+		methodMetadata := make(map[string]interface{})
+		methodMetadata[document.PublishedOperationsProperty] = "published-ops"
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		_, err = handler.Replay(&document.ResolutionResult{
+			DocumentMetadata: document.Metadata{document.MethodProperty: methodMetadata},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get published operations: failed to unmarshal")
+	})
+
+	t.Run("error - invalid document ID (unable to get suffix)", func(t *testing.T) {
+		methodMetadata := make(map[string]interface{})
+		methodMetadata[document.PublishedOperationsProperty] = []metadata.PublishedOperation{
+			{Type: operation.TypeUpdate, CanonicalReference: "abc"},
+		}
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		_, err = handler.Replay(&document.ResolutionResult{
+			DocumentMetadata: document.Metadata{document.MethodProperty: methodMetadata},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid number of parts[1] for Orb identifier")
+	})
+
+	t.Run("error - resolver error (create operation missing)", func(t *testing.T) {
+		methodMetadata := make(map[string]interface{})
+		methodMetadata[document.PublishedOperationsProperty] = []metadata.PublishedOperation{
+			{Type: operation.TypeUpdate, CanonicalReference: "abc"},
+		}
+
+		doc := make(document.Document)
+		doc["id"] = "did:orb:hash:suffix"
+
+		handler, err := New("did:orb")
+		require.NoError(t, err)
+
+		_, err = handler.Replay(&document.ResolutionResult{
+			Document:         doc,
+			DocumentMetadata: document.Metadata{document.MethodProperty: methodMetadata},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to replay operation 1 (update): create operation not found")
+	})
+}
+
 func TestCheckResponses(t *testing.T) {
 	doc := make(document.Document)
 
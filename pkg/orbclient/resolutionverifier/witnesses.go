@@ -0,0 +1,235 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutionverifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/trustbloc/sidetree-go/pkg/document"
+	"github.com/trustbloc/sidetree-go/pkg/docutil"
+
+	anchorutil "github.com/trustbloc/orb/pkg/anchor/util"
+	"github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+const didWebPrefix = "did:web:"
+
+var (
+	// ErrAnchorLinksetRequired is returned when trusted witnesses are configured but the anchor linkset
+	// which backs the resolution result wasn't provided to the verifier.
+	ErrAnchorLinksetRequired = errors.New("anchor linkset is required to verify trusted witnesses")
+
+	// ErrUntrustedWitnesses is returned when the anchor credential doesn't contain proofs from the minimum
+	// number of trusted witnesses.
+	ErrUntrustedWitnesses = errors.New("anchor credential doesn't contain enough proofs from trusted witnesses")
+
+	// ErrAnchorMismatch is returned when the anchor linkset (or the anchor credential embedded in it) doesn't
+	// match the canonical anchor of the resolution result.
+	ErrAnchorMismatch = errors.New("anchor linkset doesn't match the anchor of the resolution result")
+)
+
+// VerifyWithAnchorLinkset verifies the provided resolution result (see Verify) and, if trusted witnesses are
+// configured, also verifies the witnesses of the anchor which backs the resolution result. The given anchor
+// linkset must be the content of the canonical anchor, i.e. its hash must match the CID in the canonical ID.
+// The anchor credential is taken from the replies of the anchor linkset and must contain valid proofs from at
+// least the configured minimum number of trusted witnesses. The witness of a proof is derived from the did:web
+// verification method of the proof. Each proof from a trusted witness is verified individually and an error is
+// returned if any of these proofs is invalid.
+func (r *ResolutionVerifier) VerifyWithAnchorLinkset(input *document.ResolutionResult, anchorLinksetBytes []byte) error {
+	if err := r.verify(input); err != nil {
+		return err
+	}
+
+	if len(r.trustedWitnesses) == 0 {
+		return nil
+	}
+
+	if len(anchorLinksetBytes) == 0 {
+		return ErrAnchorLinksetRequired
+	}
+
+	anchorLink, err := anchorLinkFor(input, anchorLinksetBytes)
+	if err != nil {
+		return err
+	}
+
+	vc, err := r.anchorCredential(anchorLink)
+	if err != nil {
+		return err
+	}
+
+	return r.verifyTrustedWitnesses(vc)
+}
+
+// anchorLinkFor ensures that the hash of the given anchor linkset matches the canonical anchor (i.e. the CID
+// in the canonical ID) of the resolution result and returns the anchor link.
+func anchorLinkFor(input *document.ResolutionResult, anchorLinksetBytes []byte) (*linkset.Link, error) {
+	canonicalID, ok := input.DocumentMetadata[document.CanonicalIDProperty].(string)
+	if !ok || canonicalID == "" {
+		return nil, fmt.Errorf("resolution result for [%s] doesn't contain a canonical ID: %w",
+			input.Document.ID(), ErrAnchorMismatch)
+	}
+
+	_, cid, _, err := util.ParseCanonicalDID(canonicalID)
+	if err != nil {
+		return nil, fmt.Errorf("parse canonical ID: %w", err)
+	}
+
+	err = hashlink.VerifyResourceHash(hashlink.GetHashLinkFromResourceHash(cid), anchorLinksetBytes)
+	if err != nil {
+		return nil, fmt.Errorf("anchor linkset for canonical ID [%s]: %w: %w", canonicalID, ErrAnchorMismatch, err)
+	}
+
+	anchorLinkset := &linkset.Linkset{}
+
+	if err := json.Unmarshal(anchorLinksetBytes, anchorLinkset); err != nil {
+		return nil, fmt.Errorf("unmarshal anchor linkset: %w", err)
+	}
+
+	anchorLink := anchorLinkset.Link()
+	if anchorLink == nil {
+		return nil, errors.New("anchor linkset is empty")
+	}
+
+	return anchorLink, nil
+}
+
+// anchorCredential returns the anchor credential from the replies of the given anchor link. The anchor
+// (href) in the subject of the credential must refer to the anchor of the anchor link. The proofs aren't
+// checked here since they're verified individually (see verifyTrustedWitnesses).
+func (r *ResolutionVerifier) anchorCredential(anchorLink *linkset.Link) (*verifiable.Credential, error) {
+	vc, err := anchorutil.VerifiableCredentialFromAnchorLink(anchorLink,
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(r.docLoader),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("anchor credential from anchor linkset: %w", err)
+	}
+
+	anchor, err := anchorFromSubject(vc)
+	if err != nil {
+		return nil, fmt.Errorf("anchor credential [%s]: %w", vc.ID, err)
+	}
+
+	if anchorLink.Anchor() == nil || anchor != anchorLink.Anchor().String() {
+		return nil, fmt.Errorf("anchor [%s] in credential [%s] doesn't match the anchor of the anchor linkset [%s]: %w",
+			anchor, vc.ID, anchorLink.Anchor(), ErrAnchorMismatch)
+	}
+
+	return vc, nil
+}
+
+// anchorFromSubject returns the anchor hashlink (href) from the subject of the given anchor credential.
+func anchorFromSubject(vc *verifiable.Credential) (string, error) {
+	subjects, ok := vc.Subject.([]verifiable.Subject)
+	if !ok || len(subjects) == 0 {
+		return "", errors.New("invalid credential subject")
+	}
+
+	anchor, ok := subjects[0].CustomFields["href"].(string)
+	if !ok || anchor == "" {
+		return "", errors.New(`missing field "href" in the credential subject`)
+	}
+
+	return anchor, nil
+}
+
+func (r *ResolutionVerifier) verifyTrustedWitnesses(vc *verifiable.Credential) error {
+	witnesses := make(map[string]struct{})
+
+	for _, proof := range vc.Proofs {
+		verificationMethod, ok := proof["verificationMethod"].(string)
+		if !ok {
+			continue
+		}
+
+		host := hostFromVerificationMethod(verificationMethod)
+		if host == "" {
+			continue
+		}
+
+		if _, ok := r.trustedWitnesses[host]; !ok {
+			continue
+		}
+
+		if err := r.verifyProof(vc, proof); err != nil {
+			return fmt.Errorf("verify proof of witness [%s] on anchor credential [%s]: %w", host, vc.ID, err)
+		}
+
+		witnesses[host] = struct{}{}
+	}
+
+	if len(witnesses) < r.minTrustedWitnesses {
+		return fmt.Errorf("anchor credential [%s] has proofs from %d trusted witness(es) but at least %d are required: %w",
+			vc.ID, len(witnesses), r.minTrustedWitnesses, ErrUntrustedWitnesses)
+	}
+
+	return nil
+}
+
+// verifyProof verifies the given proof on its own by parsing the credential with only that proof attached.
+func (r *ResolutionVerifier) verifyProof(vc *verifiable.Credential, proof verifiable.Proof) error {
+	vcWithProof := *vc
+	vcWithProof.Proofs = []verifiable.Proof{proof}
+
+	vcBytes, err := vcWithProof.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("marshal credential: %w", err)
+	}
+
+	_, err = verifiable.ParseCredential(vcBytes,
+		verifiable.WithPublicKeyFetcher(r.publicKeyFetcher),
+		verifiable.WithJSONLDDocumentLoader(r.docLoader),
+	)
+
+	return err
+}
+
+// witnessHost returns the host of the given trusted witness domain, e.g. "https://orb.domain1.com" and
+// "orb.domain1.com" both return "orb.domain1.com".
+func witnessHost(domain string) string {
+	u, err := url.Parse(domain)
+	if err == nil && u.Host != "" {
+		return u.Host
+	}
+
+	return domain
+}
+
+// hostFromVerificationMethod returns the host from a did:web verification method,
+// e.g. "did:web:orb.domain1.com#key1" returns "orb.domain1.com".
+func hostFromVerificationMethod(verificationMethod string) string {
+	if !strings.HasPrefix(verificationMethod, didWebPrefix) {
+		return ""
+	}
+
+	host := strings.TrimPrefix(verificationMethod, didWebPrefix)
+
+	if i := strings.Index(host, "#"); i >= 0 {
+		host = host[:i]
+	}
+
+	// A did:web may also contain a path (separated by ':').
+	if i := strings.Index(host, docutil.NamespaceDelimiter); i >= 0 {
+		host = host[:i]
+	}
+
+	// The port is percent-encoded in a did:web.
+	decodedHost, err := url.PathUnescape(host)
+	if err != nil {
+		return host
+	}
+
+	return decodedHost
+}
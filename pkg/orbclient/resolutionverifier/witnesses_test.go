@@ -0,0 +1,332 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutionverifier
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	afgoutil "github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util/signature"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset"
+	"github.com/trustbloc/orb/pkg/anchor/anchorlinkset/generator"
+	"github.com/trustbloc/orb/pkg/anchor/builder"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	"github.com/trustbloc/orb/pkg/datauri"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+const (
+	// fixtureCID is the CID of the canonical anchor in the publishedAndUnpublishedRR fixture.
+	fixtureCID    = "uEiDqBBHMNEZQgdo1jRxvezEHAc3U1kQQjdrT7y5ybFgl_A"
+	otherHashlink = "hl:uEiBZm6-HLtOzfk8S8-ZwdqaZPYwP0hjs8plqIOJWJKkD4A"
+)
+
+func TestResolveVerifier_VerifyWithAnchorLinkset(t *testing.T) {
+	docLoader := testutil.GetLoader(t)
+
+	keys := newWitnessKeys(t,
+		"did:web:orb.domain1.com#orb1key2",
+		"did:web:orb.domain2.com#orb2key",
+		"did:web:orb.domain2.com#orb2key2",
+		"did:web:orb.domain3.com%3A8443#orb3key",
+	)
+
+	anchorLinksetBytes := newAnchorLinkset(t, func(vc *verifiable.Credential) {
+		addProofs(t, vc, keys,
+			"did:web:orb.domain1.com#orb1key2",
+			"did:web:orb.domain2.com#orb2key",
+			"did:web:orb.domain2.com#orb2key2",
+			"did:web:orb.domain3.com%3A8443#orb3key",
+		)
+
+		// Proofs from witnesses which aren't trusted are ignored.
+		vc.Proofs = append(vc.Proofs,
+			verifiable.Proof{
+				"type":               "Ed25519Signature2018",
+				"verificationMethod": "did:key:z6MkjRagNiMu91DduvCvgEsqLZDVzrJzFrwahc4tXLt9DoHd",
+			},
+			verifiable.Proof{"type": "Ed25519Signature2018", "proofValue": "no verification method"},
+		)
+	})
+
+	rr := newResolutionResult(t, anchorLinksetBytes)
+
+	witnessOpts := func(witnesses []string, minWitnesses int) []Option {
+		return []Option{
+			WithTrustedWitnesses(witnesses),
+			WithMinTrustedWitnesses(minWitnesses),
+			WithPublicKeyFetcher(keys.publicKeyFetcher),
+			WithJSONLDDocumentLoader(docLoader),
+		}
+	}
+
+	t.Run("success - no trusted witnesses", func(t *testing.T) {
+		v, err := New("did:orb")
+		require.NoError(t, err)
+
+		require.NoError(t, v.VerifyWithAnchorLinkset(rr, nil))
+		require.NoError(t, v.VerifyWithAnchorLinkset(rr, anchorLinksetBytes))
+	})
+
+	t.Run("success - trusted witnesses", func(t *testing.T) {
+		v, err := New("did:orb",
+			witnessOpts([]string{"https://orb.domain2.com", "orb.domain3.com:8443", "orb.domain4.com"}, 2)...,
+		)
+		require.NoError(t, err)
+
+		require.NoError(t, v.VerifyWithAnchorLinkset(rr, anchorLinksetBytes))
+	})
+
+	t.Run("error - not enough trusted witnesses", func(t *testing.T) {
+		v, err := New("did:orb", witnessOpts([]string{"https://orb.domain2.com", "https://orb.domain4.com"}, 2)...)
+		require.NoError(t, err)
+
+		// The two proofs from orb.domain2.com count as one witness.
+		err = v.VerifyWithAnchorLinkset(rr, anchorLinksetBytes)
+		require.ErrorIs(t, err, ErrUntrustedWitnesses)
+		require.Contains(t, err.Error(), "has proofs from 1 trusted witness(es) but at least 2 are required")
+	})
+
+	t.Run("error - no trusted witnesses", func(t *testing.T) {
+		v, err := New("did:orb", witnessOpts([]string{"https://orb.domain4.com"}, 1)...)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, v.VerifyWithAnchorLinkset(rr, anchorLinksetBytes), ErrUntrustedWitnesses)
+	})
+
+	t.Run("error - anchor linkset not provided", func(t *testing.T) {
+		v, err := New("did:orb", witnessOpts([]string{"https://orb.domain2.com"}, 1)...)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, v.VerifyWithAnchorLinkset(rr, nil), ErrAnchorLinksetRequired)
+		require.ErrorIs(t, v.Verify(rr), ErrAnchorLinksetRequired)
+	})
+
+	t.Run("error - invalid resolution result", func(t *testing.T) {
+		invalidRR := newResolutionResult(t, anchorLinksetBytes)
+
+		invalidRR.Document["id"] = "did:orb:invalid"
+
+		v, err := New("did:orb", witnessOpts([]string{"https://orb.domain2.com"}, 1)...)
+		require.NoError(t, err)
+
+		err = v.VerifyWithAnchorLinkset(invalidRR, anchorLinksetBytes)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrUntrustedWitnesses)
+	})
+
+	t.Run("error - forged proof", func(t *testing.T) {
+		v, err := New("did:orb", witnessOpts([]string{"https://orb.domain2.com", "orb.domain3.com:8443"}, 2)...)
+		require.NoError(t, err)
+
+		// The second proof claims to be from orb.domain3.com but is signed with the key of orb.domain2.com.
+		forgedLinksetBytes := newAnchorLinkset(t, func(vc *verifiable.Credential) {
+			addProofs(t, vc, keys, "did:web:orb.domain2.com#orb2key")
+			addProof(t, vc, keys["did:web:orb.domain2.com#orb2key"].privateKey, "did:web:orb.domain3.com%3A8443#orb3key")
+		})
+
+		err = v.VerifyWithAnchorLinkset(newResolutionResult(t, forgedLinksetBytes), forgedLinksetBytes)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "verify proof of witness [orb.domain3.com:8443]")
+	})
+
+	t.Run("error - anchor linkset doesn't match canonical ID", func(t *testing.T) {
+		v, err := New("did:orb", witnessOpts([]string{"https://orb.domain2.com"}, 1)...)
+		require.NoError(t, err)
+
+		otherLinksetBytes := newAnchorLinkset(t, func(vc *verifiable.Credential) {
+			addProofs(t, vc, keys, "did:web:orb.domain2.com#orb2key")
+		})
+
+		err = v.VerifyWithAnchorLinkset(rr, otherLinksetBytes)
+		require.ErrorIs(t, err, ErrAnchorMismatch)
+		require.ErrorIs(t, err, hashlink.ErrHashMismatch)
+	})
+
+	t.Run("error - anchor credential doesn't match anchor linkset", func(t *testing.T) {
+		v, err := New("did:orb", witnessOpts([]string{"https://orb.domain2.com"}, 1)...)
+		require.NoError(t, err)
+
+		// The credential (with valid proofs) was issued for another anchor.
+		mismatchedLinksetBytes := newAnchorLinkset(t, func(vc *verifiable.Credential) {
+			vc.Subject.(*builder.CredentialSubject).HRef = otherHashlink
+
+			addProofs(t, vc, keys, "did:web:orb.domain2.com#orb2key")
+		})
+
+		err = v.VerifyWithAnchorLinkset(newResolutionResult(t, mismatchedLinksetBytes), mismatchedLinksetBytes)
+		require.ErrorIs(t, err, ErrAnchorMismatch)
+		require.Contains(t, err.Error(), "doesn't match the anchor of the anchor linkset")
+	})
+
+	t.Run("error - resolution result not anchored", func(t *testing.T) {
+		var interimRR document.ResolutionResult
+		require.NoError(t, json.Unmarshal([]byte(unpublishedRR), &interimRR))
+
+		v, err := New("did:orb", witnessOpts([]string{"https://orb.domain2.com"}, 1)...)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, v.VerifyWithAnchorLinkset(&interimRR, anchorLinksetBytes), ErrAnchorMismatch)
+	})
+
+	t.Run("error - empty anchor linkset", func(t *testing.T) {
+		v, err := New("did:orb", witnessOpts([]string{"https://orb.domain2.com"}, 1)...)
+		require.NoError(t, err)
+
+		emptyLinksetBytes := []byte(`{"linkset":[]}`)
+
+		err = v.VerifyWithAnchorLinkset(newResolutionResult(t, emptyLinksetBytes), emptyLinksetBytes)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor linkset is empty")
+	})
+
+	t.Run("error - minimum exceeds number of trusted witnesses", func(t *testing.T) {
+		v, err := New("did:orb", witnessOpts([]string{"https://orb.domain2.com"}, 2)...)
+		require.Error(t, err)
+		require.Nil(t, v)
+		require.Contains(t, err.Error(), "minimum number of trusted witnesses [2] exceeds the number of trusted witnesses [1]")
+
+		v, err = New("did:orb", WithMinTrustedWitnesses(0))
+		require.Error(t, err)
+		require.Nil(t, v)
+		require.Contains(t, err.Error(), "minimum number of trusted witnesses must be at least 1")
+	})
+
+	t.Run("error - public key fetcher and document loader required", func(t *testing.T) {
+		v, err := New("did:orb", WithTrustedWitnesses([]string{"https://orb.domain2.com"}))
+		require.Error(t, err)
+		require.Nil(t, v)
+		require.Contains(t, err.Error(), "public key fetcher and document loader are required")
+	})
+}
+
+type witnessKey struct {
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+}
+
+type witnessKeys map[string]*witnessKey
+
+func newWitnessKeys(t *testing.T, verificationMethods ...string) witnessKeys {
+	t.Helper()
+
+	keys := make(witnessKeys)
+
+	for _, vm := range verificationMethods {
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		keys[vm] = &witnessKey{publicKey: pubKey, privateKey: privKey}
+	}
+
+	return keys
+}
+
+func (k witnessKeys) publicKeyFetcher(issuerID, keyID string) (*verifier.PublicKey, error) {
+	key, ok := k[issuerID+keyID]
+	if !ok {
+		return nil, fmt.Errorf("key not found [%s%s]", issuerID, keyID)
+	}
+
+	return &verifier.PublicKey{Type: "Ed25519VerificationKey2018", Value: key.publicKey}, nil
+}
+
+// newAnchorLinkset builds an anchor linkset using the anchor linkset builder. The given function is invoked
+// to add proofs to the anchor credential before it's embedded into the linkset.
+func newAnchorLinkset(t *testing.T, addWitnessProofs func(vc *verifiable.Credential)) []byte {
+	t.Helper()
+
+	payload := &subject.Payload{
+		OperationCount:  1,
+		CoreIndex:       "hl:uEiBqkaTRFZScQsXTw8IDBSpVxiKGqjJCDUcgiwpcd2frLw",
+		Namespace:       "did:orb",
+		Version:         0,
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "EiBuGL29EHeenW7172iGkib_9dIKrAzK7jazgEQjhFCRkQ"}},
+	}
+
+	anchorLink, _, err := anchorlinkset.NewBuilder(generator.NewRegistry()).BuildAnchorLink(payload,
+		datauri.MediaTypeDataURIGzipBase64,
+		func(anchorHashlink, coreIndexHashlink string) (*verifiable.Credential, error) {
+			vc := &verifiable.Credential{
+				ID:      "https://orb.domain1.com/vc/d53b1df9-1acf-4389-a006-0f88496afe46",
+				Types:   []string{"VerifiableCredential", "AnchorCredential"},
+				Context: []string{vocab.ContextCredentials, vocab.ContextActivityAnchors},
+				Subject: &builder.CredentialSubject{
+					HRef:    anchorHashlink,
+					Type:    []string{"AnchorLink"},
+					Profile: "https://w3id.org/orb#v0",
+					Anchor:  coreIndexHashlink,
+					Rel:     "linkset",
+				},
+				Issuer: verifiable.Issuer{ID: "https://orb.domain1.com"},
+				Issued: &afgoutil.TimeWrapper{Time: time.Now()},
+			}
+
+			addWitnessProofs(vc)
+
+			return vc, nil
+		},
+	)
+	require.NoError(t, err)
+
+	anchorLinksetBytes, err := json.Marshal(linkset.New(anchorLink))
+	require.NoError(t, err)
+
+	return anchorLinksetBytes
+}
+
+// newResolutionResult returns the publishedAndUnpublishedRR fixture with the canonical anchor replaced by
+// the hash of the given anchor linkset.
+func newResolutionResult(t *testing.T, anchorLinksetBytes []byte) *document.ResolutionResult {
+	t.Helper()
+
+	cid, err := hashlink.New().CreateResourceHash(anchorLinksetBytes)
+	require.NoError(t, err)
+
+	rr := &document.ResolutionResult{}
+	require.NoError(t, json.Unmarshal([]byte(strings.ReplaceAll(publishedAndUnpublishedRR, fixtureCID, cid)), rr))
+
+	return rr
+}
+
+func addProofs(t *testing.T, vc *verifiable.Credential, keys witnessKeys, verificationMethods ...string) {
+	t.Helper()
+
+	for _, vm := range verificationMethods {
+		addProof(t, vc, keys[vm].privateKey, vm)
+	}
+}
+
+func addProof(t *testing.T, vc *verifiable.Credential, privKey ed25519.PrivateKey, verificationMethod string) {
+	t.Helper()
+
+	signer := signature.GetEd25519Signer(privKey, privKey.Public().(ed25519.PublicKey))
+
+	require.NoError(t, vc.AddLinkedDataProof(&verifiable.LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2018",
+		Suite:                   ed25519signature2018.New(suite.WithSigner(signer)),
+		SignatureRepresentation: verifiable.SignatureJWS,
+		VerificationMethod:      verificationMethod,
+	}, jsonld.WithDocumentLoader(testutil.GetLoader(t))))
+}
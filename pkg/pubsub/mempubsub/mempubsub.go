@@ -8,6 +8,7 @@ package mempubsub
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -59,7 +60,7 @@ type PubSub struct {
 	msgChansByTopic map[string][]chan *message.Message
 	mutex           sync.RWMutex
 	publishChan     chan *entry
-	ackChan         chan *message.Message
+	ackChan         chan *ackEntry
 	doneChan        chan struct{}
 }
 
@@ -68,13 +69,18 @@ type entry struct {
 	messages []*message.Message
 }
 
+type ackEntry struct {
+	topic string
+	msg   *message.Message
+}
+
 // New returns a new publisher/subscriber.
 func New(cfg Config) *PubSub {
 	m := &PubSub{
 		Config:          cfg,
 		msgChansByTopic: make(map[string][]chan *message.Message),
 		publishChan:     make(chan *entry, cfg.BufferSize),
-		ackChan:         make(chan *message.Message, cfg.Concurrency),
+		ackChan:         make(chan *ackEntry, cfg.Concurrency),
 		doneChan:        make(chan struct{}),
 	}
 
@@ -191,8 +197,8 @@ func (p *PubSub) processMessages() {
 }
 
 func (p *PubSub) processAcks() {
-	for msg := range p.ackChan {
-		go p.check(msg)
+	for e := range p.ackChan {
+		go p.check(e.topic, e.msg)
 	}
 }
 
@@ -215,12 +221,12 @@ func (p *PubSub) publish(entry *entry) {
 			logger.Debug("Publishing message", logfields.WithMessageID(msg.UUID))
 
 			msgChan <- msg
-			p.ackChan <- msg
+			p.ackChan <- &ackEntry{topic: entry.topic, msg: msg}
 		}
 	}
 }
 
-func (p *PubSub) check(msg *message.Message) {
+func (p *PubSub) check(topic string, msg *message.Message) {
 	logger.Debug("Checking for Ack/Nack on message", logfields.WithMessageID(msg.UUID))
 
 	select {
@@ -231,17 +237,22 @@ func (p *PubSub) check(msg *message.Message) {
 		logger.Info("Message was not successfully acknowledged. Posting to undeliverable queue",
 			logfields.WithMessageID(msg.UUID))
 
-		p.postToUndeliverable(msg)
+		p.postToUndeliverable(topic, msg, "message was not acknowledged by the subscriber")
 
 	case <-time.After(p.Timeout):
 		logger.Warn("Timed out waiting for Ack/Nack. Posting to undeliverable queue",
 			logfields.WithTimeout(p.Timeout), logfields.WithMessageID(msg.UUID))
 
-		p.postToUndeliverable(msg)
+		p.postToUndeliverable(topic, msg, fmt.Sprintf("timed out after %s waiting for Ack/Nack", p.Timeout))
 	}
 }
 
-func (p *PubSub) postToUndeliverable(msg *message.Message) {
+func (p *PubSub) postToUndeliverable(topic string, msg *message.Message, reason string) {
+	msg = msg.Copy()
+
+	msg.Metadata.Set(spi.MetadataUndeliverableTopic, topic)
+	msg.Metadata.Set(spi.MetadataUndeliverableError, reason)
+
 	p.mutex.RLock()
 	msgChans := p.msgChansByTopic[spi.UndeliverableTopic]
 	p.mutex.RUnlock()
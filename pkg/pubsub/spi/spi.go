@@ -11,6 +11,16 @@ import "time"
 // UndeliverableTopic is the topic to which to post undeliverable messages.
 const UndeliverableTopic = "orb.undeliverable.activities"
 
+const (
+	// MetadataUndeliverableTopic is the metadata key of an undeliverable message which holds the
+	// topic to which the message was originally published.
+	MetadataUndeliverableTopic = "orb-undeliverable-topic"
+
+	// MetadataUndeliverableError is the metadata key of an undeliverable message which holds the
+	// reason why the message could not be delivered.
+	MetadataUndeliverableError = "orb-undeliverable-error"
+)
+
 // Options contains publisher/subscriber options.
 type Options struct {
 	PoolSize      int
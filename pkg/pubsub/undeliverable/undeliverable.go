@@ -0,0 +1,242 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package undeliverable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/pubsub/spi"
+)
+
+var logger = log.New("undeliverable")
+
+const defaultMaxMessages = 1000
+
+// ErrMessageNotFound is returned from Requeue if a message with the given ID is not in the store.
+var ErrMessageNotFound = errors.New("undeliverable message not found")
+
+type pubSub interface {
+	Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error)
+	Publish(topic string, messages ...*message.Message) error
+}
+
+// Message contains the details of an undeliverable message.
+type Message struct {
+	ID       string            `json:"id"`
+	Topic    string            `json:"topic,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Received time.Time         `json:"received"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Payload  string            `json:"payload,omitempty"`
+}
+
+type options struct {
+	maxMessages int
+}
+
+// Opt is an undeliverable message store option.
+type Opt func(opts *options)
+
+// WithMaxMessages sets the maximum number of undeliverable messages that are held by the store.
+// If the maximum is reached then the oldest message is dropped.
+func WithMaxMessages(value int) Opt {
+	return func(opts *options) {
+		opts.maxMessages = value
+	}
+}
+
+type entry struct {
+	info *Message
+	msg  *message.Message
+}
+
+// Store subscribes to the undeliverable topic and holds the undeliverable messages in memory so that
+// they may be inspected and requeued to their original topic. Messages are only held by the store on
+// the node that received them and they are lost when the node is restarted.
+type Store struct {
+	*lifecycle.Lifecycle
+	*options
+
+	pubSub   pubSub
+	msgChan  <-chan *message.Message
+	mutex    sync.RWMutex
+	messages []*entry
+}
+
+// New returns a new undeliverable message store.
+func New(pubSub pubSub, opts ...Opt) (*Store, error) {
+	options := &options{
+		maxMessages: defaultMaxMessages,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	msgChan, err := pubSub.Subscribe(context.Background(), spi.UndeliverableTopic)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to topic [%s]: %w", spi.UndeliverableTopic, err)
+	}
+
+	s := &Store{
+		options: options,
+		pubSub:  pubSub,
+		msgChan: msgChan,
+	}
+
+	s.Lifecycle = lifecycle.New("undeliverable", lifecycle.WithStart(s.start))
+
+	return s, nil
+}
+
+func (s *Store) start() {
+	go s.listen()
+}
+
+func (s *Store) listen() {
+	logger.Debug("Starting undeliverable message listener")
+
+	for msg := range s.msgChan {
+		s.add(msg)
+
+		msg.Ack()
+	}
+
+	logger.Debug("Undeliverable message listener stopped")
+}
+
+func (s *Store) add(msg *message.Message) {
+	e := &entry{
+		info: &Message{
+			ID:       msg.UUID,
+			Topic:    msg.Metadata.Get(spi.MetadataUndeliverableTopic),
+			Error:    msg.Metadata.Get(spi.MetadataUndeliverableError),
+			Received: time.Now(),
+			Metadata: copyMetadata(msg.Metadata),
+			Payload:  string(msg.Payload),
+		},
+		msg: msg,
+	}
+
+	logger.Info("Adding undeliverable message", logfields.WithMessageID(msg.UUID), log.WithTopic(e.info.Topic))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.messages) >= s.maxMessages {
+		dropped := s.messages[0]
+
+		logger.Warn("Maximum number of undeliverable messages reached. The oldest message will be dropped.",
+			logfields.WithMaxSize(s.maxMessages), logfields.WithMessageID(dropped.info.ID))
+
+		s.messages = s.messages[1:]
+	}
+
+	s.messages = append(s.messages, e)
+}
+
+// List returns the undeliverable messages, oldest first.
+func (s *Store) List() []*Message {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	messages := make([]*Message, len(s.messages))
+
+	for i, e := range s.messages {
+		info := *e.info
+		info.Metadata = copyMetadata(e.info.Metadata)
+
+		messages[i] = &info
+	}
+
+	return messages
+}
+
+// Requeue publishes the messages with the given IDs back to the topic to which they were originally
+// published and removes them from the store. ErrMessageNotFound is returned (and no message is requeued)
+// if any of the given IDs isn't in the store.
+func (s *Store) Requeue(ids ...string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entries := make([]*entry, len(ids))
+
+	for i, id := range ids {
+		e, ok := s.get(id)
+		if !ok {
+			return fmt.Errorf("message [%s]: %w", id, ErrMessageNotFound)
+		}
+
+		if e.info.Topic == "" {
+			return orberrors.NewBadRequest(fmt.Errorf("message [%s] cannot be requeued since its topic is unknown", id))
+		}
+
+		entries[i] = e
+	}
+
+	for _, e := range entries {
+		msg := e.msg.Copy()
+
+		delete(msg.Metadata, spi.MetadataUndeliverableTopic)
+		delete(msg.Metadata, spi.MetadataUndeliverableError)
+
+		if err := s.pubSub.Publish(e.info.Topic, msg); err != nil {
+			return orberrors.NewTransient(fmt.Errorf("requeue message [%s] to topic [%s]: %w",
+				e.info.ID, e.info.Topic, err))
+		}
+
+		logger.Info("Requeued undeliverable message", logfields.WithMessageID(e.info.ID), log.WithTopic(e.info.Topic))
+
+		s.remove(e.info.ID)
+	}
+
+	return nil
+}
+
+func (s *Store) get(id string) (*entry, bool) {
+	for _, e := range s.messages {
+		if e.info.ID == id {
+			return e, true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *Store) remove(id string) {
+	for i, e := range s.messages {
+		if e.info.ID == id {
+			s.messages = append(s.messages[:i], s.messages[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func copyMetadata(metadata message.Metadata) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, len(metadata))
+
+	for k, v := range metadata {
+		m[k] = v
+	}
+
+	return m
+}
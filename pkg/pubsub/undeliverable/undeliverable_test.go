@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package undeliverable
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/pubsub/spi"
+)
+
+const topic = "orb.test.topic"
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := New(apmocks.NewPubSub(), WithMaxMessages(10))
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		require.Equal(t, 10, s.maxMessages)
+	})
+
+	t.Run("Subscribe error", func(t *testing.T) {
+		errExpected := errors.New("injected subscribe error")
+
+		s, err := New(apmocks.NewPubSub().WithError(errExpected))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+		require.Nil(t, s)
+	})
+}
+
+func TestStore_ListAndRequeue(t *testing.T) {
+	ps := apmocks.NewPubSub()
+	defer ps.Stop()
+
+	s, err := New(ps)
+	require.NoError(t, err)
+
+	s.Start()
+	defer s.Stop()
+
+	msgChan, err := ps.Subscribe(context.Background(), topic)
+	require.NoError(t, err)
+
+	msg1 := message.NewMessage(watermill.NewUUID(), []byte(`{"id":"msg1"}`))
+	msg1.Metadata.Set("custom", "value1")
+
+	msg2 := message.NewMessage(watermill.NewUUID(), []byte(`{"id":"msg2"}`))
+
+	require.NoError(t, ps.Publish(topic, msg1, msg2))
+
+	// Nack both messages so that they're posted to the undeliverable topic.
+	for i := 0; i < 2; i++ {
+		(<-msgChan).Nack()
+	}
+
+	require.Eventually(t, func() bool { return len(s.List()) == 2 }, time.Second, 10*time.Millisecond)
+
+	t.Run("List", func(t *testing.T) {
+		messages := s.List()
+		require.Len(t, messages, 2)
+
+		m1 := findMessage(messages, msg1.UUID)
+		require.NotNil(t, m1)
+		require.Equal(t, topic, m1.Topic)
+		require.Equal(t, "message was not acknowledged by the subscriber", m1.Error)
+		require.Equal(t, `{"id":"msg1"}`, m1.Payload)
+		require.Equal(t, "value1", m1.Metadata["custom"])
+		require.False(t, m1.Received.IsZero())
+
+		// Modifying the returned message shouldn't modify the stored message.
+		m1.Metadata["custom"] = "modified"
+
+		require.Equal(t, "value1", findMessage(s.List(), msg1.UUID).Metadata["custom"])
+	})
+
+	t.Run("Requeue not found", func(t *testing.T) {
+		err := s.Requeue(msg1.UUID, "invalid-id")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrMessageNotFound))
+
+		require.Len(t, s.List(), 2, "no messages should have been requeued")
+	})
+
+	t.Run("Requeue", func(t *testing.T) {
+		require.NoError(t, s.Requeue(msg1.UUID))
+
+		select {
+		case msg := <-msgChan:
+			require.Equal(t, msg1.UUID, msg.UUID)
+			require.Equal(t, msg1.Payload, msg.Payload)
+			require.Equal(t, "value1", msg.Metadata.Get("custom"))
+			require.Empty(t, msg.Metadata.Get(spi.MetadataUndeliverableTopic))
+			require.Empty(t, msg.Metadata.Get(spi.MetadataUndeliverableError))
+
+			msg.Ack()
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for requeued message")
+		}
+
+		messages := s.List()
+		require.Len(t, messages, 1)
+		require.Equal(t, msg2.UUID, messages[0].ID)
+	})
+}
+
+func TestStore_Requeue(t *testing.T) {
+	t.Run("Unknown topic", func(t *testing.T) {
+		s, err := New(apmocks.NewPubSub())
+		require.NoError(t, err)
+
+		msg := message.NewMessage(watermill.NewUUID(), nil)
+
+		s.add(msg)
+
+		err = s.Requeue(msg.UUID)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "topic is unknown")
+	})
+
+	t.Run("Publish error", func(t *testing.T) {
+		ps := apmocks.NewPubSub()
+
+		s, err := New(ps)
+		require.NoError(t, err)
+
+		msg := message.NewMessage(watermill.NewUUID(), nil)
+		msg.Metadata.Set(spi.MetadataUndeliverableTopic, topic)
+
+		s.add(msg)
+
+		errExpected := errors.New("injected publish error")
+
+		ps.WithError(errExpected)
+
+		err = s.Requeue(msg.UUID)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+
+		require.Len(t, s.List(), 1)
+	})
+}
+
+func TestStore_MaxMessages(t *testing.T) {
+	s, err := New(apmocks.NewPubSub(), WithMaxMessages(2))
+	require.NoError(t, err)
+
+	msg1 := message.NewMessage(watermill.NewUUID(), nil)
+	msg2 := message.NewMessage(watermill.NewUUID(), nil)
+	msg3 := message.NewMessage(watermill.NewUUID(), nil)
+
+	s.add(msg1)
+	s.add(msg2)
+	s.add(msg3)
+
+	messages := s.List()
+	require.Len(t, messages, 2)
+	require.Equal(t, msg2.UUID, messages[0].ID)
+	require.Equal(t, msg3.UUID, messages[1].ID)
+}
+
+func findMessage(messages []*Message, id string) *Message {
+	for _, m := range messages {
+		if m.ID == id {
+			return m
+		}
+	}
+
+	return nil
+}
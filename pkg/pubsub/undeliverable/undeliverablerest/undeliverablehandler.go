@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package undeliverablerest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/pubsub/undeliverable"
+)
+
+var logger = log.New("undeliverable-rest", log.WithFields(logfields.WithServiceEndpoint(undeliverablePath)))
+
+const (
+	undeliverablePath           = "/undeliverable"
+	internalServerErrorResponse = "Internal Server Error.\n"
+)
+
+type undeliverableStore interface {
+	List() []*undeliverable.Message
+	Requeue(ids ...string) error
+}
+
+// Reader implements a REST handler which lists the undeliverable messages.
+type Reader struct {
+	store   undeliverableStore
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new REST handler which lists the undeliverable messages.
+func NewReader(store undeliverableStore) *Reader {
+	return &Reader{
+		store:   store,
+		marshal: json.Marshal,
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *Reader) Path() string {
+	return undeliverablePath
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *Reader) handleGet(w http.ResponseWriter, _ *http.Request) {
+	messages := h.store.List()
+	if messages == nil {
+		messages = []*undeliverable.Message{}
+	}
+
+	messagesBytes, err := h.marshal(messages)
+	if err != nil {
+		logger.Error("Error marshalling undeliverable messages", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, messagesBytes)
+}
+
+// Requeuer implements a REST handler which requeues undeliverable messages to their original topic.
+type Requeuer struct {
+	store   undeliverableStore
+	readAll func(r io.Reader) ([]byte, error)
+}
+
+// NewRequeuer returns a new REST handler which requeues undeliverable messages to their original topic.
+func NewRequeuer(store undeliverableStore) *Requeuer {
+	return &Requeuer{
+		store:   store,
+		readAll: io.ReadAll,
+	}
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Requeuer) Method() string {
+	return http.MethodPost
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *Requeuer) Path() string {
+	return undeliverablePath
+}
+
+// Handler returns the handler that should be invoked when an HTTP POST is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *Requeuer) Handler() common.HTTPRequestHandler {
+	return h.handlePost
+}
+
+func (h *Requeuer) handlePost(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		logger.Error("Error reading request body", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Debug("Got request to requeue undeliverable messages", logfields.WithRequestBody(reqBytes))
+
+	request, err := unmarshalAndValidateRequest(reqBytes)
+	if err != nil {
+		logger.Info("Error validating request", log.WithError(err))
+
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	err = h.store.Requeue(request.IDs...)
+	if err != nil {
+		switch {
+		case errors.Is(err, undeliverable.ErrMessageNotFound):
+			writeResponse(w, http.StatusNotFound, []byte(err.Error()))
+		case orberrors.IsBadRequest(err):
+			writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+		default:
+			logger.Error("Error requeuing undeliverable messages", log.WithError(err))
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+		}
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			log.WriteResponseBodyError(logger, err)
+
+			return
+		}
+
+		log.WroteResponse(logger, body)
+	}
+}
+
+type requeueRequest struct {
+	IDs []string `json:"ids"`
+}
+
+func unmarshalAndValidateRequest(reqBytes []byte) (*requeueRequest, error) {
+	request := &requeueRequest{}
+
+	if err := json.Unmarshal(reqBytes, request); err != nil {
+		return nil, fmt.Errorf("invalid requeue request: %w", err)
+	}
+
+	if len(request.IDs) == 0 {
+		return nil, fmt.Errorf("invalid requeue request: no message IDs specified")
+	}
+
+	return request, nil
+}
@@ -0,0 +1,246 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package undeliverablerest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/pubsub/undeliverable"
+)
+
+const (
+	undeliverableURL = "https://example.com/undeliverable"
+	topic            = "orb.test.topic"
+)
+
+func TestNew(t *testing.T) {
+	s, err := undeliverable.New(apmocks.NewPubSub())
+	require.NoError(t, err)
+
+	r := NewReader(s)
+	require.NotNil(t, r.Handler())
+	require.Equal(t, http.MethodGet, r.Method())
+	require.Equal(t, "/undeliverable", r.Path())
+
+	w := NewRequeuer(s)
+	require.NotNil(t, w.Handler())
+	require.Equal(t, http.MethodPost, w.Method())
+	require.Equal(t, "/undeliverable", w.Path())
+}
+
+func TestReader_Handler(t *testing.T) {
+	t.Run("No messages", func(t *testing.T) {
+		s, err := undeliverable.New(apmocks.NewPubSub())
+		require.NoError(t, err)
+
+		h := NewReader(s)
+
+		rw := httptest.NewRecorder()
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, undeliverableURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, "[]", string(respBytes))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		ps, s, msgChan := newStore(t)
+
+		msg := publishUndeliverable(t, ps, s, msgChan)
+
+		h := NewReader(s)
+
+		rw := httptest.NewRecorder()
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, undeliverableURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		var messages []*undeliverable.Message
+		require.NoError(t, json.Unmarshal(respBytes, &messages))
+		require.Len(t, messages, 1)
+		require.Equal(t, msg.UUID, messages[0].ID)
+		require.Equal(t, topic, messages[0].Topic)
+		require.NotEmpty(t, messages[0].Error)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		s, err := undeliverable.New(apmocks.NewPubSub())
+		require.NoError(t, err)
+
+		h := NewReader(s)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		rw := httptest.NewRecorder()
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, undeliverableURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+func TestRequeuer_Handler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ps, s, msgChan := newStore(t)
+
+		msg := publishUndeliverable(t, ps, s, msgChan)
+
+		h := NewRequeuer(s)
+
+		rw := httptest.NewRecorder()
+
+		h.handlePost(rw, httptest.NewRequest(http.MethodPost, undeliverableURL,
+			bytes.NewBufferString(fmt.Sprintf(`{"ids":["%s"]}`, msg.UUID))))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+
+		select {
+		case m := <-msgChan:
+			require.Equal(t, msg.UUID, m.UUID)
+
+			m.Ack()
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for requeued message")
+		}
+
+		require.Empty(t, s.List())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		_, s, _ := newStore(t)
+
+		h := NewRequeuer(s)
+
+		rw := httptest.NewRecorder()
+
+		h.handlePost(rw, httptest.NewRequest(http.MethodPost, undeliverableURL,
+			bytes.NewBufferString(`{"ids":["invalid"]}`)))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusNotFound, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		_, s, _ := newStore(t)
+
+		h := NewRequeuer(s)
+
+		for _, body := range []string{`}`, `{}`} {
+			rw := httptest.NewRecorder()
+
+			h.handlePost(rw, httptest.NewRequest(http.MethodPost, undeliverableURL, bytes.NewBufferString(body)))
+
+			result := rw.Result()
+			require.Equal(t, http.StatusBadRequest, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+		}
+	})
+
+	t.Run("Read request error", func(t *testing.T) {
+		_, s, _ := newStore(t)
+
+		h := NewRequeuer(s)
+		h.readAll = func(r io.Reader) ([]byte, error) { return nil, errors.New("injected read error") }
+
+		rw := httptest.NewRecorder()
+
+		h.handlePost(rw, httptest.NewRequest(http.MethodPost, undeliverableURL, bytes.NewBufferString(`{}`)))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Publish error", func(t *testing.T) {
+		ps, s, msgChan := newStore(t)
+
+		msg := publishUndeliverable(t, ps, s, msgChan)
+
+		ps.WithError(errors.New("injected publish error"))
+		defer ps.WithError(nil)
+
+		h := NewRequeuer(s)
+
+		rw := httptest.NewRecorder()
+
+		h.handlePost(rw, httptest.NewRequest(http.MethodPost, undeliverableURL,
+			bytes.NewBufferString(fmt.Sprintf(`{"ids":["%s"]}`, msg.UUID))))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+
+		require.Len(t, s.List(), 1)
+	})
+}
+
+func newStore(t *testing.T) (*apmocks.MockPubSub, *undeliverable.Store, <-chan *message.Message) {
+	t.Helper()
+
+	ps := apmocks.NewPubSub()
+
+	s, err := undeliverable.New(ps)
+	require.NoError(t, err)
+
+	s.Start()
+
+	msgChan, err := ps.Subscribe(context.Background(), topic)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		s.Stop()
+		ps.Stop()
+	})
+
+	return ps, s, msgChan
+}
+
+// publishUndeliverable publishes a message and nacks it so that it's posted to the undeliverable topic.
+func publishUndeliverable(t *testing.T, ps *apmocks.MockPubSub, s *undeliverable.Store,
+	msgChan <-chan *message.Message,
+) *message.Message {
+	t.Helper()
+
+	msg := message.NewMessage(watermill.NewUUID(), []byte(`{}`))
+
+	require.NoError(t, ps.Publish(topic, msg))
+
+	(<-msgChan).Nack()
+
+	require.Eventually(t, func() bool { return len(s.List()) == 1 }, time.Second, 10*time.Millisecond)
+
+	return msg
+}
@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+var logger = log.New("request-id")
+
+const (
+	// HeaderName is the HTTP header which carries the request (correlation) ID.
+	HeaderName = "X-Request-ID"
+
+	// maxLength is the maximum length of a request ID which is accepted from an inbound request.
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// New returns a new, random request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// WithContext returns a copy of the given context which carries the given request ID.
+func WithContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, requestID)
+}
+
+// FromContext returns the request ID carried by the given context or an empty string if the context
+// doesn't carry a request ID.
+func FromContext(ctx context.Context) string {
+	requestID, ok := ctx.Value(contextKey{}).(string)
+	if !ok {
+		return ""
+	}
+
+	return requestID
+}
+
+// Middleware returns an HTTP handler which adds the request ID of an inbound request to the request context
+// (so that it's propagated to any outbound requests which are made while handling the request) and to the
+// response header. The request ID is taken from the request header or, if the request doesn't contain a valid
+// request ID, a new request ID is generated.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestID := req.Header.Get(HeaderName)
+		if !isValid(requestID) {
+			requestID = New()
+		}
+
+		w.Header().Set(HeaderName, requestID)
+
+		logger.Debug("Received request", logfields.WithRequestID(requestID), logfields.WithHTTPMethod(req.Method),
+			logfields.WithRequestURL(req.URL), logfields.WithUserAgent(req.UserAgent()))
+
+		next.ServeHTTP(w, req.WithContext(WithContext(req.Context(), requestID)))
+	})
+}
+
+// isValid returns true if the given request ID isn't empty, isn't too long and contains only
+// printable ASCII characters (so that it may be safely logged and echoed in the response).
+func isValid(requestID string) bool {
+	if requestID == "" || len(requestID) > maxLength {
+		return false
+	}
+
+	for _, c := range requestID {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContext(t *testing.T) {
+	require.Empty(t, FromContext(context.Background()))
+
+	requestID := New()
+	require.NotEmpty(t, requestID)
+	require.NotEqual(t, requestID, New())
+
+	require.Equal(t, requestID, FromContext(WithContext(context.Background(), requestID)))
+}
+
+func TestMiddleware(t *testing.T) {
+	var requestIDFromContext string
+
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestIDFromContext = FromContext(req.Context())
+	}))
+
+	t.Run("Request ID in request -> propagated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "https://orb.domain1.com/services/orb", http.NoBody)
+		req.Header.Set(HeaderName, "request-1")
+
+		rw := httptest.NewRecorder()
+
+		h.ServeHTTP(rw, req)
+
+		require.Equal(t, "request-1", requestIDFromContext)
+		require.Equal(t, "request-1", rw.Header().Get(HeaderName))
+	})
+
+	t.Run("No request ID in request -> generated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "https://orb.domain1.com/services/orb", http.NoBody)
+
+		rw := httptest.NewRecorder()
+
+		h.ServeHTTP(rw, req)
+
+		require.NotEmpty(t, requestIDFromContext)
+		require.Equal(t, requestIDFromContext, rw.Header().Get(HeaderName))
+	})
+
+	t.Run("Invalid request ID in request -> replaced", func(t *testing.T) {
+		for _, requestID := range []string{"request 1", "request\t1", strings.Repeat("x", maxLength+1)} {
+			req := httptest.NewRequest(http.MethodGet, "https://orb.domain1.com/services/orb", http.NoBody)
+			req.Header.Set(HeaderName, requestID)
+
+			rw := httptest.NewRecorder()
+
+			h.ServeHTTP(rw, req)
+
+			require.NotEmpty(t, requestIDFromContext)
+			require.NotEqual(t, requestID, requestIDFromContext)
+			require.Equal(t, requestIDFromContext, rw.Header().Get(HeaderName))
+		}
+	})
+}
@@ -0,0 +1,205 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
+)
+
+const (
+	nameSpace = "processed-anchor-log"
+
+	processedTimeTagName = "processedTime"
+
+	defaultPageSize = 500
+)
+
+var logger = log.New("processed-anchor-log")
+
+// ErrDataNotFound is returned when there are no more entries in the iterator.
+var ErrDataNotFound = errors.New("data not found")
+
+// Option is an option for the processed-anchor log.
+type Option func(opts *Store)
+
+// WithPageSize sets the page size used when iterating over the log.
+func WithPageSize(pageSize int) Option {
+	return func(opts *Store) {
+		opts.pageSize = pageSize
+	}
+}
+
+// Store is an append-only log of processed anchors which is backed by a storage provider.
+type Store struct {
+	store    storage.Store
+	pageSize int
+}
+
+// Entry is an entry in the processed-anchor log.
+type Entry struct {
+	Hashlink  string `json:"hashlink"`
+	Namespace string `json:"namespace,omitempty"`
+
+	// ProcessedTime is the time (in milliseconds since epoch) that the anchor was processed. The field
+	// name must match the tag name since MongoDB uses document fields for queries.
+	ProcessedTime int64 `json:"processedTime"`
+}
+
+// Time returns the time that the anchor was processed.
+func (e *Entry) Time() time.Time {
+	return time.UnixMilli(e.ProcessedTime)
+}
+
+// EntryIterator defines the query results iterator for processed-anchor log queries.
+type EntryIterator interface {
+	// TotalItems returns the total number of items as a result of the query.
+	TotalItems() (int, error)
+	// Next returns the next entry or an ErrDataNotFound error if there are no more items.
+	Next() (*Entry, error)
+	// Close closes the iterator.
+	Close() error
+}
+
+// New returns a new processed-anchor log.
+func New(provider storage.Provider, opts ...Option) (*Store, error) {
+	s, err := store.Open(provider, nameSpace,
+		store.NewTagGroup(processedTimeTagName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open processed-anchor log: %w", err)
+	}
+
+	anchorLog := &Store{
+		store:    s,
+		pageSize: defaultPageSize,
+	}
+
+	for _, opt := range opts {
+		opt(anchorLog)
+	}
+
+	return anchorLog, nil
+}
+
+// Append adds the given anchor to the log.
+func (s *Store) Append(hl, namespace string, processedTime time.Time) error {
+	if hl == "" {
+		return errors.New("missing hashlink")
+	}
+
+	entry := &Entry{
+		Hashlink:      hl,
+		Namespace:     namespace,
+		ProcessedTime: processedTime.UnixMilli(),
+	}
+
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal processed-anchor log entry: %w", err)
+	}
+
+	err = s.store.Put(uuid.New().String(), entryBytes,
+		storage.Tag{
+			Name:  processedTimeTagName,
+			Value: strconv.FormatInt(entry.ProcessedTime, 10),
+		},
+	)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to append to processed-anchor log: %w", err))
+	}
+
+	logger.Debug("Appended anchor to processed-anchor log", logfields.WithAnchorEventURIString(hl))
+
+	return nil
+}
+
+// GetEntries returns an iterator over all entries in the log, ordered by processed time.
+func (s *Store) GetEntries() (EntryIterator, error) {
+	return s.GetEntriesFrom(time.UnixMilli(0))
+}
+
+// GetEntriesFrom returns an iterator over the entries that were processed at or after the given time,
+// ordered by processed time.
+func (s *Store) GetEntriesFrom(from time.Time) (EntryIterator, error) {
+	return s.GetEntriesInRange(from, time.Time{})
+}
+
+// GetEntriesInRange returns an iterator over the entries that were processed within the given time range
+// (inclusive), ordered by processed time. If 'until' is zero then the range has no upper bound. Additional
+// query options, such as the page size and initial page number, may be provided in order to page through
+// the results.
+func (s *Store) GetEntriesInRange(from, until time.Time, opts ...storage.QueryOption) (EntryIterator, error) {
+	query := fmt.Sprintf("%s>=%d", processedTimeTagName, from.UnixMilli())
+
+	if !until.IsZero() {
+		query = fmt.Sprintf("%s&&%s<=%d", query, processedTimeTagName, until.UnixMilli())
+	}
+
+	queryOpts := append([]storage.QueryOption{
+		storage.WithSortOrder(&storage.SortOptions{
+			Order:   storage.SortAscending,
+			TagName: processedTimeTagName,
+		}),
+		storage.WithPageSize(s.pageSize),
+	}, opts...)
+
+	iterator, err := s.store.Query(query, queryOpts...)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to query processed-anchor log: %w", err))
+	}
+
+	return &entryIterator{ariesIterator: iterator}, nil
+}
+
+type entryIterator struct {
+	ariesIterator storage.Iterator
+}
+
+func (e *entryIterator) TotalItems() (int, error) {
+	return e.ariesIterator.TotalItems()
+}
+
+func (e *entryIterator) Next() (*Entry, error) {
+	exists, err := e.ariesIterator.Next()
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to determine if there are more results: %w", err))
+	}
+
+	if !exists {
+		return nil, ErrDataNotFound
+	}
+
+	entryBytes, err := e.ariesIterator.Value()
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to get value: %w", err))
+	}
+
+	entry := &Entry{}
+
+	err = json.Unmarshal(entryBytes, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal entry bytes: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (e *entryIterator) Close() error {
+	return e.ariesIterator.Close()
+}
@@ -0,0 +1,256 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorlog
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/storage/mongodb"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mock"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/mongodbtestutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	hl1 = "hl:uEiAsiwjaXOYDmOHxmvDl3Mx0TfJ0uCar5YXqumjFJUNIBg"
+	hl2 = "hl:uEiCJWrAzh2AOq5vSP6hb4_i7RC1HE-Dq3b9-6cK3R6ZNuQ"
+	hl3 = "hl:uEiBqQJ4hPZpWN9LNTVhbK4QgcjZk9RIj5ZYdL3C-T3vHEQ"
+
+	namespace = "did:orb"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), WithPageSize(10))
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		require.Equal(t, 10, s.pageSize)
+	})
+
+	t.Run("error - open store fails", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, fmt.Errorf("open store error"))
+
+		s, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open store error")
+		require.Nil(t, s)
+	})
+}
+
+func TestStore_Append(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Append(hl1, namespace, time.Now()))
+		require.NoError(t, s.Append(hl1, namespace, time.Now()))
+	})
+
+	t.Run("error - missing hashlink", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		err = s.Append("", namespace, time.Now())
+		require.EqualError(t, err, "missing hashlink")
+	})
+
+	t.Run("error - store error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.PutReturns(fmt.Errorf("put error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		err = s.Append(hl1, namespace, time.Now())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "put error")
+	})
+}
+
+func TestStore_GetEntries(t *testing.T) {
+	t.Run("success - ordered by processed time", func(t *testing.T) {
+		mongoDBConnString, stopMongo := mongodbtestutil.StartMongoDB(t)
+		defer stopMongo()
+
+		mongoDBProvider, err := mongodb.NewProvider(mongoDBConnString)
+		require.NoError(t, err)
+
+		s, err := New(mongoDBProvider, WithPageSize(2))
+		require.NoError(t, err)
+
+		now := time.Now()
+
+		// Append out of order to ensure that entries are sorted by processed time.
+		require.NoError(t, s.Append(hl2, namespace, now.Add(-time.Minute)))
+		require.NoError(t, s.Append(hl3, namespace, now))
+		require.NoError(t, s.Append(hl1, namespace, now.Add(-time.Hour)))
+
+		it, err := s.GetEntries()
+		require.NoError(t, err)
+
+		n, err := it.TotalItems()
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+
+		for _, expected := range []string{hl1, hl2, hl3} {
+			entry, e := it.Next()
+			require.NoError(t, e)
+			require.Equal(t, expected, entry.Hashlink)
+			require.Equal(t, namespace, entry.Namespace)
+		}
+
+		_, err = it.Next()
+		require.True(t, errors.Is(err, ErrDataNotFound))
+
+		require.NoError(t, it.Close())
+
+		it, err = s.GetEntriesFrom(now.Add(-time.Minute))
+		require.NoError(t, err)
+
+		for _, expected := range []string{hl2, hl3} {
+			entry, e := it.Next()
+			require.NoError(t, e)
+			require.Equal(t, expected, entry.Hashlink)
+		}
+
+		_, err = it.Next()
+		require.True(t, errors.Is(err, ErrDataNotFound))
+
+		require.NoError(t, it.Close())
+	})
+
+	t.Run("sort option", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(&mock.Iterator{}, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		from := time.Now()
+
+		it, err := s.GetEntriesFrom(from)
+		require.NoError(t, err)
+		require.NotNil(t, it)
+
+		query, opts := store.QueryArgsForCall(0)
+		require.Equal(t, fmt.Sprintf("processedTime>=%d", from.UnixMilli()), query)
+
+		queryOptions := &storage.QueryOptions{}
+		for _, opt := range opts {
+			opt(queryOptions)
+		}
+
+		require.Equal(t, defaultPageSize, queryOptions.PageSize)
+		require.NotNil(t, queryOptions.SortOptions)
+		require.Equal(t, storage.SortAscending, queryOptions.SortOptions.Order)
+		require.Equal(t, processedTimeTagName, queryOptions.SortOptions.TagName)
+	})
+
+	t.Run("range query", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(&mock.Iterator{}, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		from := time.Now().Add(-time.Hour)
+		until := time.Now()
+
+		it, err := s.GetEntriesInRange(from, until, storage.WithPageSize(5), storage.WithInitialPageNum(2))
+		require.NoError(t, err)
+		require.NotNil(t, it)
+
+		query, opts := store.QueryArgsForCall(0)
+		require.Equal(t, fmt.Sprintf("processedTime>=%d&&processedTime<=%d", from.UnixMilli(), until.UnixMilli()), query)
+
+		queryOptions := &storage.QueryOptions{}
+		for _, opt := range opts {
+			opt(queryOptions)
+		}
+
+		require.Equal(t, 5, queryOptions.PageSize)
+		require.Equal(t, 2, queryOptions.InitialPageNum)
+		require.NotNil(t, queryOptions.SortOptions)
+		require.Equal(t, storage.SortAscending, queryOptions.SortOptions.Order)
+	})
+
+	t.Run("error - query fails", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		it, err := s.GetEntries()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+		require.Nil(t, it)
+	})
+}
+
+func TestEntryIterator(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		entry := &Entry{Hashlink: hl1, Namespace: namespace, ProcessedTime: 1000}
+
+		iterator := entryIterator{ariesIterator: &mock.Iterator{
+			NextReturn:  true,
+			ValueReturn: []byte(fmt.Sprintf(`{"hashlink":"%s","namespace":"%s","processedTime":1000}`, hl1, namespace)),
+		}}
+
+		e, err := iterator.Next()
+		require.NoError(t, err)
+		require.Equal(t, entry, e)
+		require.Equal(t, time.UnixMilli(1000), e.Time())
+	})
+
+	t.Run("error - next fails", func(t *testing.T) {
+		iterator := entryIterator{ariesIterator: &mock.Iterator{ErrNext: fmt.Errorf("next error")}}
+
+		entry, err := iterator.Next()
+		require.EqualError(t, err, "failed to determine if there are more results: next error")
+		require.Nil(t, entry)
+
+		iterator = entryIterator{ariesIterator: &mock.Iterator{
+			NextReturn: true, ErrValue: fmt.Errorf("value error"),
+		}}
+
+		entry, err = iterator.Next()
+		require.EqualError(t, err, "failed to get value: value error")
+		require.Nil(t, entry)
+	})
+
+	t.Run("error - unmarshal fails", func(t *testing.T) {
+		iterator := entryIterator{ariesIterator: &mock.Iterator{
+			NextReturn: true, ValueReturn: []byte("{"),
+		}}
+
+		entry, err := iterator.Next()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal entry bytes")
+		require.Nil(t, entry)
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/trustbloc/orb/pkg/cas/ipfs"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/observability/metrics/noop"
 )
 
 var logger = log.New("cas-store")
@@ -28,11 +29,30 @@ const (
 	dbName           = "cas"
 	defaultCacheSize = 1000
 	casType          = "local"
+
+	resourceHashTagName = "resourceHash"
 )
 
-type metricsProvider interface {
+// ErrDataNotFound is returned when there are no more resource hashes in the iterator.
+var ErrDataNotFound = errors.New("data not found")
+
+// ResourceHashIterator defines the query results iterator for resource hash queries.
+type ResourceHashIterator interface {
+	// TotalItems returns the total number of items as a result of the query.
+	TotalItems() (int, error)
+	// Next returns the next resource hash or an ErrDataNotFound error if there are no more items.
+	Next() (string, error)
+	// Close closes the iterator.
+	Close() error
+}
+
+// MetricsProvider records CAS metrics. Any metrics backend may be plugged into the CAS by implementing
+// this interface. If no metrics provider is passed to New then a no-op provider is used.
+type MetricsProvider interface {
 	CASIncrementCacheHitCount()
+	CASWriteTime(value time.Duration)
 	CASReadTime(casType string, value time.Duration)
+	CASBytesStored(value int)
 }
 
 // CAS represents a content-addressable storage provider.
@@ -41,7 +61,7 @@ type CAS struct {
 	ipfsClient *ipfs.Client
 	opts       []extendedcasclient.CIDFormatOption
 	cache      gcache.Cache
-	metrics    metricsProvider
+	metrics    MetricsProvider
 	casLink    string
 	hl         *hashlink.HashLink
 }
@@ -50,7 +70,7 @@ type CAS struct {
 // ipfsClient is optional, but if provided (not nil), then writes will go to IPFS in addition to the passed in provider.
 // Reads are always done on only the passed in provider.
 // If no CID version is specified, then v1 will be used by default.
-func New(provider ariesstorage.Provider, casLink string, ipfsClient *ipfs.Client, metrics metricsProvider,
+func New(provider ariesstorage.Provider, casLink string, ipfsClient *ipfs.Client, metrics MetricsProvider,
 	cacheSize int, opts ...extendedcasclient.CIDFormatOption,
 ) (*CAS, error) {
 	cas, err := provider.OpenStore(dbName)
@@ -58,10 +78,19 @@ func New(provider ariesstorage.Provider, casLink string, ipfsClient *ipfs.Client
 		return nil, fmt.Errorf("failed to open store in underlying storage provider: %w", err)
 	}
 
+	err = provider.SetStoreConfig(dbName, ariesstorage.StoreConfiguration{TagNames: []string{resourceHashTagName}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration in underlying storage provider: %w", err)
+	}
+
 	if cacheSize == 0 {
 		cacheSize = defaultCacheSize
 	}
 
+	if metrics == nil {
+		metrics = noop.NewProvider().Metrics()
+	}
+
 	c := &CAS{
 		cas:        cas,
 		ipfsClient: ipfsClient,
@@ -104,6 +133,8 @@ func (p *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFo
 		return "", errors.New("empty content")
 	}
 
+	startTime := time.Now()
+
 	resourceHash, err := p.hl.CreateResourceHash(content)
 	if err != nil {
 		return "", fmt.Errorf("failed to create resource hash from content: %w", err)
@@ -112,11 +143,14 @@ func (p *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFo
 	logger.Debug("Writing to CAS store. Content (base64-encoded)",
 		logfields.WithHash(resourceHash), logfields.WithCASData(content))
 
-	err = p.cas.Put(resourceHash, content)
+	// The content is tagged with its resource hash so that the locally-held resource hashes may be listed.
+	err = p.cas.Put(resourceHash, content, ariesstorage.Tag{Name: resourceHashTagName, Value: resourceHash})
 	if err != nil {
 		return "", orberrors.NewTransient(fmt.Errorf("failed to put content into underlying storage provider: %w", err))
 	}
 
+	p.metrics.CASBytesStored(len(content))
+
 	// add cas link
 	links := []string{p.casLink + "/" + resourceHash}
 
@@ -143,6 +177,8 @@ func (p *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFo
 		return "", fmt.Errorf("failed to create resource hash from content: %w", err)
 	}
 
+	p.metrics.CASWriteTime(time.Since(startTime))
+
 	return hashlink.GetHashLink(resourceHash, metadata), nil
 }
 
@@ -169,7 +205,9 @@ func (p *CAS) Read(address string) ([]byte, error) {
 func (p *CAS) get(address string) ([]byte, error) {
 	startTime := time.Now()
 
-	defer p.metrics.CASReadTime(casType, time.Since(startTime))
+	defer func() {
+		p.metrics.CASReadTime(casType, time.Since(startTime))
+	}()
 
 	content, err := p.cas.Get(address)
 	if err != nil {
@@ -182,3 +220,52 @@ func (p *CAS) get(address string) ([]byte, error) {
 
 	return content, nil
 }
+
+// GetResourceHashes returns an iterator over the resource hashes of the content held in the local CAS, ordered
+// by resource hash. Query options, such as the page size and initial page number, may be provided in order to page
+// through the results. Note that content which was written before resource hashes were tagged is not returned.
+func (p *CAS) GetResourceHashes(opts ...ariesstorage.QueryOption) (ResourceHashIterator, error) {
+	queryOpts := append([]ariesstorage.QueryOption{
+		ariesstorage.WithSortOrder(&ariesstorage.SortOptions{
+			Order:   ariesstorage.SortAscending,
+			TagName: resourceHashTagName,
+		}),
+	}, opts...)
+
+	it, err := p.cas.Query(resourceHashTagName, queryOpts...)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to query resource hashes: %w", err))
+	}
+
+	return &resourceHashIterator{ariesIterator: it}, nil
+}
+
+type resourceHashIterator struct {
+	ariesIterator ariesstorage.Iterator
+}
+
+func (it *resourceHashIterator) TotalItems() (int, error) {
+	return it.ariesIterator.TotalItems()
+}
+
+func (it *resourceHashIterator) Next() (string, error) {
+	exists, err := it.ariesIterator.Next()
+	if err != nil {
+		return "", orberrors.NewTransient(fmt.Errorf("failed to determine if there are more results: %w", err))
+	}
+
+	if !exists {
+		return "", ErrDataNotFound
+	}
+
+	key, err := it.ariesIterator.Key()
+	if err != nil {
+		return "", orberrors.NewTransient(fmt.Errorf("failed to get key: %w", err))
+	}
+
+	return key, nil
+}
+
+func (it *resourceHashIterator) Close() error {
+	return it.ariesIterator.Close()
+}
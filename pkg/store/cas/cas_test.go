@@ -8,10 +8,13 @@ package cas_test
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/hyperledger/aries-framework-go-ext/component/storage/mongodb"
 	ariesmemstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	ariesmockstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
 	ariesstorage "github.com/hyperledger/aries-framework-go/spi/storage"
@@ -24,6 +27,7 @@ import (
 	"github.com/trustbloc/orb/pkg/cas/ipfs"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/internal/testutil/mongodbtestutil"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
 	localcas "github.com/trustbloc/orb/pkg/store/cas"
 )
@@ -45,6 +49,95 @@ func TestNew(t *testing.T) {
 		require.EqualError(t, err, "failed to open store in underlying storage provider: open store error")
 		require.Nil(t, provider)
 	})
+	t.Run("Fail to set store configuration", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn:   &ariesmockstorage.Store{},
+			ErrSetStoreConfig: errors.New("set config error"),
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+
+		require.EqualError(t, err,
+			"failed to set store configuration in underlying storage provider: set config error")
+		require.Nil(t, provider)
+	})
+	t.Run("No metrics provider -> no-op metrics", func(t *testing.T) {
+		provider, err := localcas.New(ariesmemstorage.NewProvider(), casLink, nil, nil, 0)
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+
+		hl, err := provider.Write([]byte("content"))
+		require.NoError(t, err)
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		content, err := provider.Read(rh)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+	})
+}
+
+func TestProvider_Metrics(t *testing.T) {
+	t.Run("Write and read", func(t *testing.T) {
+		metrics := &mockCASMetrics{}
+
+		provider, err := localcas.New(ariesmemstorage.NewProvider(), casLink, nil, metrics, 0)
+		require.NoError(t, err)
+
+		content := []byte("content")
+
+		hl, err := provider.Write(content)
+		require.NoError(t, err)
+
+		require.Len(t, metrics.writeTimes, 1)
+		require.Greater(t, metrics.writeTimes[0], time.Duration(0))
+		require.Less(t, metrics.writeTimes[0], time.Minute)
+		require.Equal(t, []int{len(content)}, metrics.bytesStored)
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		// Content was cached on write so the read is a cache hit and the local store isn't read.
+		_, err = provider.Read(rh)
+		require.NoError(t, err)
+		require.Equal(t, 1, metrics.cacheHits)
+		require.Empty(t, metrics.readTimes)
+	})
+
+	t.Run("Read from store", func(t *testing.T) {
+		metrics := &mockCASMetrics{}
+
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{
+				GetReturn: []byte("content"),
+			},
+		}, casLink, nil, metrics, 0)
+		require.NoError(t, err)
+
+		_, err = provider.Read("uEiDat0G2KJ59zMHtQjMMrhrMwrdVzoB5ws1dS1Nmyfdppg")
+		require.NoError(t, err)
+
+		require.Zero(t, metrics.cacheHits)
+		require.Len(t, metrics.readTimes, 1)
+		require.Less(t, metrics.readTimes[0], time.Minute)
+		require.Equal(t, []string{"local"}, metrics.readTypes)
+	})
+
+	t.Run("Write error -> no write metrics", func(t *testing.T) {
+		metrics := &mockCASMetrics{}
+
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{
+				ErrPut: errors.New("put error"),
+			},
+		}, casLink, nil, metrics, 0)
+		require.NoError(t, err)
+
+		_, err = provider.Write([]byte("content"))
+		require.Error(t, err)
+
+		require.Empty(t, metrics.writeTimes)
+		require.Empty(t, metrics.bytesStored)
+	})
 }
 
 func TestProvider_Write_Read(t *testing.T) {
@@ -193,6 +286,128 @@ func TestProvider_Write_Read(t *testing.T) {
 	})
 }
 
+type mockCASMetrics struct {
+	cacheHits   int
+	writeTimes  []time.Duration
+	readTimes   []time.Duration
+	readTypes   []string
+	bytesStored []int
+}
+
+func (m *mockCASMetrics) CASIncrementCacheHitCount() {
+	m.cacheHits++
+}
+
+func (m *mockCASMetrics) CASWriteTime(value time.Duration) {
+	m.writeTimes = append(m.writeTimes, value)
+}
+
+func (m *mockCASMetrics) CASReadTime(casType string, value time.Duration) {
+	m.readTypes = append(m.readTypes, casType)
+	m.readTimes = append(m.readTimes, value)
+}
+
+func (m *mockCASMetrics) CASBytesStored(value int) {
+	m.bytesStored = append(m.bytesStored, value)
+}
+
+func TestProvider_GetResourceHashes(t *testing.T) {
+	t.Run("Success - paged", func(t *testing.T) {
+		mongoDBConnString, stopMongo := mongodbtestutil.StartMongoDB(t)
+		defer stopMongo()
+
+		mongoDBProvider, err := mongodb.NewProvider(mongoDBConnString)
+		require.NoError(t, err)
+
+		provider, err := localcas.New(mongoDBProvider, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		var expected []string
+
+		for i := 0; i < 5; i++ {
+			hl, e := provider.Write([]byte(fmt.Sprintf("content %d", i)))
+			require.NoError(t, e)
+
+			rh, e := hashlink.GetResourceHashFromHashLink(hl)
+			require.NoError(t, e)
+
+			expected = append(expected, rh)
+		}
+
+		sort.Strings(expected)
+
+		var got []string
+
+		for pageNum := 0; pageNum < 3; pageNum++ {
+			it, e := provider.GetResourceHashes(ariesstorage.WithPageSize(2), ariesstorage.WithInitialPageNum(pageNum))
+			require.NoError(t, e)
+
+			total, e := it.TotalItems()
+			require.NoError(t, e)
+			require.Equal(t, 5, total)
+
+			for i := 0; i < 2; i++ {
+				rh, e := it.Next()
+				if errors.Is(e, localcas.ErrDataNotFound) {
+					break
+				}
+
+				require.NoError(t, e)
+
+				got = append(got, rh)
+			}
+
+			require.NoError(t, it.Close())
+		}
+
+		require.Equal(t, expected, got)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{
+				ErrQuery: errors.New("query error"),
+			},
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		_, err = provider.GetResourceHashes()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "query error")
+	})
+
+	t.Run("Iterator error", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{
+				QueryReturn: &ariesmockstorage.Iterator{ErrNext: errors.New("next error")},
+			},
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		it, err := provider.GetResourceHashes()
+		require.NoError(t, err)
+
+		_, err = it.Next()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "next error")
+
+		provider, err = localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{
+				QueryReturn: &ariesmockstorage.Iterator{NextReturn: true, ErrKey: errors.New("key error")},
+			},
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		it, err = provider.GetResourceHashes()
+		require.NoError(t, err)
+
+		_, err = it.Next()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key error")
+	})
+}
+
 func startIPFSDockerContainer(t *testing.T) (*dctest.Pool, *dctest.Resource) {
 	t.Helper()
 
@@ -0,0 +1,114 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"container/heap"
+	"sort"
+)
+
+type anchorTime struct {
+	anchorID string
+	time     int64
+	index    int
+}
+
+// recentAnchors keeps track of the most recent anchors (by time). If a maximum is specified then only the
+// most recent maxAnchors anchors are retained, using a min-heap so that the least recent anchor is evicted
+// when a more recent anchor is added.
+type recentAnchors struct {
+	maxAnchors int
+	items      anchorTimeHeap
+	anchors    map[string]*anchorTime
+}
+
+func newRecentAnchors(maxAnchors int) *recentAnchors {
+	return &recentAnchors{
+		maxAnchors: maxAnchors,
+		anchors:    make(map[string]*anchorTime),
+	}
+}
+
+// add adds the given anchor. If the anchor is already being tracked then its time is updated
+// if the given time is more recent.
+func (r *recentAnchors) add(anchorID string, t int64) {
+	if item, ok := r.anchors[anchorID]; ok {
+		if t > item.time {
+			item.time = t
+
+			heap.Fix(&r.items, item.index)
+		}
+
+		return
+	}
+
+	if r.maxAnchors > 0 && len(r.items) >= r.maxAnchors {
+		if t <= r.items[0].time {
+			// Less recent than all of the retained anchors.
+			return
+		}
+
+		evicted := heap.Pop(&r.items).(*anchorTime) //nolint:forcetypeassert
+
+		delete(r.anchors, evicted.anchorID)
+	}
+
+	item := &anchorTime{anchorID: anchorID, time: t}
+
+	heap.Push(&r.items, item)
+
+	r.anchors[anchorID] = item
+}
+
+// sorted returns the IDs of the retained anchors, ordered from the most recent to the least recent.
+func (r *recentAnchors) sorted() []string {
+	items := make([]*anchorTime, len(r.items))
+	copy(items, r.items)
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].time > items[j].time
+	})
+
+	anchorIDs := make([]string, len(items))
+
+	for i, item := range items {
+		anchorIDs[i] = item.anchorID
+	}
+
+	return anchorIDs
+}
+
+// anchorTimeHeap implements heap.Interface. The least recent anchor is at the root.
+type anchorTimeHeap []*anchorTime
+
+func (h anchorTimeHeap) Len() int { return len(h) }
+
+func (h anchorTimeHeap) Less(i, j int) bool { return h[i].time < h[j].time }
+
+func (h anchorTimeHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *anchorTimeHeap) Push(x interface{}) {
+	item := x.(*anchorTime) //nolint:forcetypeassert
+
+	item.index = len(*h)
+
+	*h = append(*h, item)
+}
+
+func (h *anchorTimeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+
+	return item
+}
@@ -38,6 +38,8 @@ const (
 	queryExpr = "%s:%s&&%s:%s"
 
 	iteratorErrMsgFormat = "iterator error for anchorID[%s] : %w"
+
+	recentAnchorsPageSize = 500
 )
 
 var logger = log.New("witness-store")
@@ -280,6 +282,68 @@ func (s *Store) getProofs(anchorID string) (proofs, error) {
 	return proofs, nil
 }
 
+// GetRecentAnchors returns up to maxAnchors IDs of anchors for which witnesses are stored, ordered from the most
+// recently stored to the least recently stored. The witnesses are paged through and only the most recent maxAnchors
+// anchors are retained (in a bounded heap) so memory usage doesn't grow with the size of the store. If maxAnchors
+// is not greater than zero then all anchors are returned.
+func (s *Store) GetRecentAnchors(maxAnchors int) ([]string, error) {
+	query := fmt.Sprintf("%s:%s", typeTagName, witnessInfoType)
+
+	iter, err := s.store.Query(query, storage.WithPageSize(recentAnchorsPageSize))
+	if err != nil {
+		return nil, orberrors.NewTransientf("failed to query witnesses: %w", err)
+	}
+
+	defer store.CloseIterator(iter)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return nil, orberrors.NewTransientf("iterator error: %w", err)
+	}
+
+	recent := newRecentAnchors(maxAnchors)
+
+	for ok {
+		value, e := iter.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("failed to get witness from iterator value: %w", e)
+		}
+
+		entry := &Entry{}
+
+		e = json.Unmarshal(value, entry)
+		if e != nil {
+			return nil, fmt.Errorf("failed to unmarshal anchor witness from store value: %w", e)
+		}
+
+		// The expiry time is set relative to the time the witness was stored, so the latest expiry
+		// time is the most recent.
+		recent.add(entry.AnchorID, entry.ExpiryTime)
+
+		ok, e = iter.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("iterator error: %w", e)
+		}
+	}
+
+	encodedAnchorIDs := recent.sorted()
+
+	anchorIDs := make([]string, len(encodedAnchorIDs))
+
+	for i, encodedAnchorID := range encodedAnchorIDs {
+		anchorID, e := base64.RawURLEncoding.DecodeString(encodedAnchorID)
+		if e != nil {
+			return nil, fmt.Errorf("decode anchor ID [%s]: %w", encodedAnchorID, e)
+		}
+
+		anchorIDs[i] = string(anchorID)
+	}
+
+	logger.Debug("Retrieved recent anchors", logfields.WithTotal(len(anchorIDs)))
+
+	return anchorIDs, nil
+}
+
 // AddProof adds proof for anchor id and witness.
 func (s *Store) AddProof(anchorID string, witness *url.URL, p []byte) error {
 	anchorIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(anchorID))
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package witness
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -372,6 +373,149 @@ func TestStore_AddProof(t *testing.T) {
 	})
 }
 
+func TestStore_GetRecentAnchors(t *testing.T) {
+	testWitnessURL, err := url.Parse("http://domain.com/service")
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put("anchor1", []*proof.Witness{getTestWitness(testWitnessURL)}))
+
+		s.expiryPeriod = 2 * expiryTime
+		require.NoError(t, s.Put("anchor2", []*proof.Witness{getTestWitness(testWitnessURL)}))
+
+		s.expiryPeriod = 3 * expiryTime
+		require.NoError(t, s.Put("anchor3", []*proof.Witness{getTestWitness(testWitnessURL)}))
+		require.NoError(t, s.AddProof("anchor3", testWitnessURL, []byte(proofJSON)))
+
+		anchors, err := s.GetRecentAnchors(0)
+		require.NoError(t, err)
+		require.Equal(t, []string{"anchor3", "anchor2", "anchor1"}, anchors)
+
+		anchors, err = s.GetRecentAnchors(2)
+		require.NoError(t, err)
+		require.Equal(t, []string{"anchor3", "anchor2"}, anchors)
+	})
+
+	t.Run("success - only most recent anchors retained", func(t *testing.T) {
+		// Entries are returned in arbitrary order, with multiple witnesses per anchor.
+		entries := []struct {
+			anchorID   string
+			expiryTime int64
+		}{
+			{"anchor1", 100},
+			{"anchor5", 500},
+			{"anchor2", 200},
+			{"anchor1", 700}, // A later witness makes anchor1 the most recent.
+			{"anchor4", 400},
+			{"anchor3", 300},
+			{"anchor6", 50},
+			{"anchor4", 350},
+		}
+
+		iterator := &mocks.Iterator{}
+
+		for i, e := range entries {
+			value, err := json.Marshal(&Entry{
+				EntryType:  witnessInfoType,
+				AnchorID:   base64.RawURLEncoding.EncodeToString([]byte(e.anchorID)),
+				ExpiryTime: e.expiryTime,
+			})
+			require.NoError(t, err)
+
+			iterator.NextReturnsOnCall(i, true, nil)
+			iterator.ValueReturnsOnCall(i, value, nil)
+		}
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		anchors, err := s.GetRecentAnchors(3)
+		require.NoError(t, err)
+		require.Equal(t, []string{"anchor1", "anchor5", "anchor4"}, anchors)
+
+		_, opts := store.QueryArgsForCall(0)
+		require.Len(t, opts, 1)
+
+		queryOpts := &storage.QueryOptions{}
+		opts[0](queryOpts)
+		require.Equal(t, recentAnchorsPageSize, queryOpts.PageSize)
+	})
+
+	t.Run("success - no anchors", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		anchors, err := s.GetRecentAnchors(10)
+		require.NoError(t, err)
+		require.Empty(t, anchors)
+	})
+
+	t.Run("error - query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		anchors, err := s.GetRecentAnchors(10)
+		require.Error(t, err)
+		require.Nil(t, anchors)
+		require.Contains(t, err.Error(), "query error")
+	})
+
+	t.Run("error - iterator value() error", func(t *testing.T) {
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(true, nil)
+		iterator.ValueReturns(nil, fmt.Errorf("iterator value() error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		anchors, err := s.GetRecentAnchors(10)
+		require.Error(t, err)
+		require.Nil(t, anchors)
+		require.Contains(t, err.Error(), "iterator value() error")
+	})
+
+	t.Run("error - unmarshal error", func(t *testing.T) {
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(true, nil)
+		iterator.ValueReturns([]byte("not-json"), nil)
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		anchors, err := s.GetRecentAnchors(10)
+		require.Error(t, err)
+		require.Nil(t, anchors)
+		require.Contains(t, err.Error(), "failed to unmarshal anchor witness from store value")
+	})
+}
+
 func TestStore_UpdateWitnessSelection(t *testing.T) {
 	testWitnessURL, err := url.Parse("http://domain.com/service")
 	require.NoError(t, err)
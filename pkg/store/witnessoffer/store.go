@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witnessoffer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+const (
+	namespace = "witness-offer"
+
+	expiryTimeTagName = "expiryTime"
+)
+
+var logger = log.New("witness-offer-store")
+
+type metricsProvider interface {
+	WitnessOfferIncrementExpiredCount(count int)
+}
+
+// Store tracks the witness 'Offer' activities posted by this service until they expire. Expired offers are
+// removed from the store by the expiry service.
+type Store struct {
+	store           storage.Store
+	defaultLifetime time.Duration
+	metrics         metricsProvider
+}
+
+// New returns a new witness offer store. The given default lifetime applies to offers that don't specify
+// an end time.
+func New(provider storage.Provider, expiryService *expiry.Service, defaultLifetime time.Duration,
+	metrics metricsProvider,
+) (*Store, error) {
+	s, err := store.Open(provider, namespace, store.NewTagGroup(expiryTimeTagName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open witness offer store: %w", err)
+	}
+
+	ws := &Store{
+		store:           s,
+		defaultLifetime: defaultLifetime,
+		metrics:         metrics,
+	}
+
+	expiryService.Register(s, expiryTimeTagName, namespace, expiry.WithExpiryHandler(ws))
+
+	return ws, nil
+}
+
+type offerEntry struct {
+	OfferID    string `json:"offerID"`
+	ExpiryTime int64  `json:"expiryTime"`
+}
+
+// Put adds the witness offer with the given ID. The offer expires at the given end time or, if the end time
+// is nil, after the default lifetime.
+func (s *Store) Put(offerID *url.URL, endTime *time.Time) error {
+	expiryTime := time.Now().Add(s.defaultLifetime)
+
+	if endTime != nil {
+		expiryTime = *endTime
+	}
+
+	entry := &offerEntry{
+		OfferID:    offerID.String(),
+		ExpiryTime: expiryTime.Unix(),
+	}
+
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal witness offer [%s]: %w", offerID, err)
+	}
+
+	err = s.store.Put(offerID.String(), entryBytes,
+		storage.Tag{Name: expiryTimeTagName, Value: fmt.Sprintf("%d", entry.ExpiryTime)},
+	)
+	if err != nil {
+		return orberrors.NewTransientf("store witness offer [%s]: %w", offerID, err)
+	}
+
+	logger.Debug("Stored witness offer", logfields.WithActivityID(offerID), logfields.WithExpiryTime(expiryTime))
+
+	return nil
+}
+
+// GetExpiryTime returns the time at which the given witness offer expires. A zero time is returned if the
+// offer isn't in the store (for example, if it has expired and was already removed).
+func (s *Store) GetExpiryTime(offerID *url.URL) (time.Time, error) {
+	entryBytes, err := s.store.Get(offerID.String())
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, orberrors.NewTransientf("get witness offer [%s]: %w", offerID, err)
+	}
+
+	entry := &offerEntry{}
+
+	err = json.Unmarshal(entryBytes, entry)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unmarshal witness offer [%s]: %w", offerID, err)
+	}
+
+	return time.Unix(entry.ExpiryTime, 0), nil
+}
+
+// HandleExpiredKeys is invoked by the expiry service before the expired witness offers are deleted.
+func (s *Store) HandleExpiredKeys(keys ...string) ([]string, error) {
+	if len(keys) == 0 {
+		return keys, nil
+	}
+
+	logger.Debug("Removing expired witness offers", logfields.WithTotal(len(keys)))
+
+	s.metrics.WitnessOfferIncrementExpiredCount(len(keys))
+
+	return keys, nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witnessoffer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/storage/mongodb"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil/mongodbtestutil"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const defaultLifetime = time.Minute
+
+var (
+	offerID1 = testutil.MustParseURL("https://orb.domain1.com/services/orb/activities/offer1")
+	offerID2 = testutil.MustParseURL("https://orb.domain1.com/services/orb/activities/offer2")
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), defaultLifetime, &mockMetrics{})
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("open store error"))
+
+		s, err := New(provider, testutil.GetExpiryService(t), defaultLifetime, &mockMetrics{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open store error")
+		require.Nil(t, s)
+	})
+}
+
+func TestStore_PutGet(t *testing.T) {
+	s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), defaultLifetime, &mockMetrics{})
+	require.NoError(t, err)
+
+	t.Run("With end time", func(t *testing.T) {
+		endTime := time.Now().Add(5 * time.Minute)
+
+		require.NoError(t, s.Put(offerID1, &endTime))
+
+		expiryTime, err := s.GetExpiryTime(offerID1)
+		require.NoError(t, err)
+		require.Equal(t, endTime.Unix(), expiryTime.Unix())
+	})
+
+	t.Run("Default lifetime", func(t *testing.T) {
+		before := time.Now()
+
+		require.NoError(t, s.Put(offerID2, nil))
+
+		expiryTime, err := s.GetExpiryTime(offerID2)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, expiryTime.Unix(), before.Add(defaultLifetime).Unix())
+		require.LessOrEqual(t, expiryTime.Unix(), time.Now().Add(defaultLifetime).Unix())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		expiryTime, err := s.GetExpiryTime(testutil.MustParseURL("https://orb.domain1.com/activities/unknown"))
+		require.NoError(t, err)
+		require.True(t, expiryTime.IsZero())
+	})
+}
+
+func TestStore_Errors(t *testing.T) {
+	store := &mocks.Store{}
+
+	provider := &mocks.Provider{}
+	provider.OpenStoreReturns(store, nil)
+
+	s, err := New(provider, testutil.GetExpiryService(t), defaultLifetime, &mockMetrics{})
+	require.NoError(t, err)
+
+	t.Run("Put error", func(t *testing.T) {
+		store.PutReturns(errors.New("injected put error"))
+
+		err := s.Put(offerID1, nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected put error")
+	})
+
+	t.Run("Get error", func(t *testing.T) {
+		store.GetReturns(nil, errors.New("injected get error"))
+
+		_, err := s.GetExpiryTime(offerID1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected get error")
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store.GetReturns([]byte("{"), nil)
+
+		_, err := s.GetExpiryTime(offerID1)
+		require.Error(t, err)
+		require.False(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "unmarshal witness offer")
+	})
+}
+
+func TestStore_HandleExpiredKeys(t *testing.T) {
+	t.Run("Metrics", func(t *testing.T) {
+		metrics := &mockMetrics{}
+
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), defaultLifetime, metrics)
+		require.NoError(t, err)
+
+		keys, err := s.HandleExpiredKeys()
+		require.NoError(t, err)
+		require.Empty(t, keys)
+		require.Zero(t, metrics.getExpired())
+
+		keys, err = s.HandleExpiredKeys("key1", "key2")
+		require.NoError(t, err)
+		require.Equal(t, []string{"key1", "key2"}, keys)
+		require.Equal(t, 2, metrics.getExpired())
+	})
+
+	t.Run("Expired offers are removed", func(t *testing.T) {
+		mongoDBConnString, stopMongo := mongodbtestutil.StartMongoDB(t)
+		defer stopMongo()
+
+		mongoDBProvider, err := mongodb.NewProvider(mongoDBConnString)
+		require.NoError(t, err)
+
+		taskMgr := testutil.GetTaskMgr(t)
+
+		metrics := &mockMetrics{}
+
+		s, err := New(mongoDBProvider, expiry.NewService(taskMgr, time.Second), defaultLifetime, metrics)
+		require.NoError(t, err)
+
+		endTime := time.Now().Add(time.Second)
+
+		require.NoError(t, s.Put(offerID1, &endTime))
+		require.NoError(t, s.Put(offerID2, nil))
+
+		taskMgr.Start()
+		defer taskMgr.Stop()
+
+		require.Eventually(t, func() bool {
+			expiryTime, e := s.GetExpiryTime(offerID1)
+			require.NoError(t, e)
+
+			return expiryTime.IsZero()
+		}, 10*time.Second, 100*time.Millisecond)
+
+		require.Equal(t, 1, metrics.getExpired())
+
+		expiryTime, err := s.GetExpiryTime(offerID2)
+		require.NoError(t, err)
+		require.False(t, expiryTime.IsZero())
+	})
+}
+
+type mockMetrics struct {
+	mutex   sync.Mutex
+	expired int
+}
+
+func (m *mockMetrics) WitnessOfferIncrementExpiredCount(count int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.expired += count
+}
+
+func (m *mockMetrics) getExpired() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.expired
+}
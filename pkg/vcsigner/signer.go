@@ -19,7 +19,10 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2020"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/piprate/json-gold/ld"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
 )
 
 const (
@@ -41,6 +44,25 @@ const (
 	AssertionMethod = "assertionMethod"
 )
 
+// ErrKeyNotFound indicates that the signing key referenced by the verification method doesn't exist.
+// This error is permanent, i.e. retrying the signing operation won't succeed.
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// CredentialSigner signs verifiable credentials (such as anchor credentials) on behalf of this node.
+// The default implementation, Signer, signs with a key held by the configured KMS. Deployments may provide
+// an alternate implementation (for example, one that's backed by an HSM) without changing the anchoring code.
+//
+// Implementations should return an error that wraps ErrKeyNotFound if the key referenced by the verification
+// method doesn't exist, and a transient error (see orberrors.NewTransient) if the signing backend is temporarily
+// unavailable so that the caller may retry.
+type CredentialSigner interface {
+	// Sign adds a linked data proof to the given credential. The signature suite and verification method
+	// may be overridden with the WithSignatureSuite and WithVerificationMethod options.
+	Sign(vc *verifiable.Credential, opts ...Opt) (*verifiable.Credential, error)
+	// Context returns the JSON-LD contexts required by the default signature suite.
+	Context() []string
+}
+
 type metricsProvider interface {
 	SignerSign(value time.Duration)
 	SignerGetKey(value time.Duration)
@@ -98,33 +120,59 @@ func verifySigningParams(params SigningParams) error {
 	return nil
 }
 
-// Signer to sign verifiable credential.
+// Signer is the default, KMS-backed implementation of CredentialSigner.
 type Signer struct {
 	*Providers
 	params SigningParams
 }
 
 // Opt represents option for Sign fn.
-type Opt func(*verifiable.LinkedDataProofContext)
+type Opt func(*SigningOptions)
+
+// SigningOptions contains the options that are passed to CredentialSigner.Sign. A CredentialSigner
+// implementation applies the options to a SigningOptions that's populated with its defaults.
+type SigningOptions struct {
+	VerificationMethod      string
+	SignatureSuite          string
+	Domain                  string
+	Created                 *time.Time
+	SignatureRepresentation *verifiable.SignatureRepresentation
+}
 
 // WithCreated allows providing time when signing credentials.
 func WithCreated(t time.Time) Opt {
-	return func(ctx *verifiable.LinkedDataProofContext) {
-		ctx.Created = &t
+	return func(opts *SigningOptions) {
+		opts.Created = &t
 	}
 }
 
 // WithSignatureRepresentation allows providing signature representation when signing credentials.
 func WithSignatureRepresentation(signature verifiable.SignatureRepresentation) Opt {
-	return func(ctx *verifiable.LinkedDataProofContext) {
-		ctx.SignatureRepresentation = signature
+	return func(opts *SigningOptions) {
+		opts.SignatureRepresentation = &signature
 	}
 }
 
 // WithDomain allows providing domain when signing credentials.
 func WithDomain(domain string) Opt {
-	return func(ctx *verifiable.LinkedDataProofContext) {
-		ctx.Domain = domain
+	return func(opts *SigningOptions) {
+		opts.Domain = domain
+	}
+}
+
+// WithVerificationMethod overrides the verification method (and therefore the signing key) that's used
+// when signing credentials.
+func WithVerificationMethod(verificationMethod string) Opt {
+	return func(opts *SigningOptions) {
+		opts.VerificationMethod = verificationMethod
+	}
+}
+
+// WithSignatureSuite overrides the signature suite that's used when signing credentials. Note that the
+// caller is responsible for adding the JSON-LD context of the suite (see SuiteContext) to the credential.
+func WithSignatureSuite(signatureSuite string) Opt {
+	return func(opts *SigningOptions) {
+		opts.SignatureSuite = signatureSuite
 	}
 }
 
@@ -149,7 +197,12 @@ func (s *Signer) Sign(vc *verifiable.Credential, opts ...Opt) (*verifiable.Crede
 
 // Context return context.
 func (s *Signer) Context() []string {
-	switch s.params.SignatureSuite {
+	return SuiteContext(s.params.SignatureSuite)
+}
+
+// SuiteContext returns the JSON-LD context(s) required by the given signature suite.
+func SuiteContext(signatureSuite string) []string {
+	switch signatureSuite {
 	case JSONWebSignature2020:
 		return []string{CtxJWS}
 	case Ed25519Signature2018:
@@ -162,7 +215,17 @@ func (s *Signer) Context() []string {
 }
 
 func (s *Signer) getLinkedDataProofContext(opts ...Opt) (*verifiable.LinkedDataProofContext, error) {
-	kmsSigner, err := s.getKMSSigner()
+	options := &SigningOptions{
+		VerificationMethod: s.params.VerificationMethod,
+		SignatureSuite:     s.params.SignatureSuite,
+		Domain:             s.params.Domain,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	kmsSigner, err := s.getKMSSigner(options.VerificationMethod)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +234,7 @@ func (s *Signer) getLinkedDataProofContext(opts ...Opt) (*verifiable.LinkedDataP
 
 	var signatureRepresentation verifiable.SignatureRepresentation
 
-	switch s.params.SignatureSuite {
+	switch options.SignatureSuite {
 	case Ed25519Signature2018:
 		signatureSuite = ed25519signature2018.New(suite.WithSigner(kmsSigner))
 		signatureRepresentation = verifiable.SignatureProofValue
@@ -182,31 +245,33 @@ func (s *Signer) getLinkedDataProofContext(opts ...Opt) (*verifiable.LinkedDataP
 		signatureSuite = jsonwebsignature2020.New(suite.WithSigner(kmsSigner))
 		signatureRepresentation = verifiable.SignatureJWS
 	default:
-		return nil, fmt.Errorf("signature type not supported: %s", s.params.SignatureSuite)
+		return nil, fmt.Errorf("signature type not supported: %s", options.SignatureSuite)
 	}
 
-	now := time.Now()
-
-	signingCtx := &verifiable.LinkedDataProofContext{
-		Domain:                  s.params.Domain,
-		VerificationMethod:      s.params.VerificationMethod,
-		SignatureRepresentation: signatureRepresentation,
-		SignatureType:           s.params.SignatureSuite,
-		Suite:                   signatureSuite,
-		Purpose:                 AssertionMethod,
-		Created:                 &now,
+	if options.SignatureRepresentation != nil {
+		signatureRepresentation = *options.SignatureRepresentation
 	}
 
-	for _, opt := range opts {
-		opt(signingCtx)
+	created := time.Now()
+
+	if options.Created != nil {
+		created = *options.Created
 	}
 
-	return signingCtx, nil
+	return &verifiable.LinkedDataProofContext{
+		Domain:                  options.Domain,
+		VerificationMethod:      options.VerificationMethod,
+		SignatureRepresentation: signatureRepresentation,
+		SignatureType:           options.SignatureSuite,
+		Suite:                   signatureSuite,
+		Purpose:                 AssertionMethod,
+		Created:                 &created,
+	}, nil
 }
 
 // getKMSSigner returns new KMS signer based on verification method.
-func (s *Signer) getKMSSigner() (signer, error) {
-	kmsSigner, err := newKMSSigner(s.Providers.KeyManager, s.Providers.Crypto, s.params.VerificationMethod,
+func (s *Signer) getKMSSigner(verificationMethod string) (signer, error) {
+	kmsSigner, err := newKMSSigner(s.Providers.KeyManager, s.Providers.Crypto, verificationMethod,
 		s.Providers.Metrics)
 	if err != nil {
 		return nil, err
@@ -251,7 +316,12 @@ func newKMSSigner(keyManager keyManager, c crypto, verificationMethod string, me
 
 	keyHandler, err := keyManager.Get(keyID)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, kms.ErrKeyNotFound) {
+			return nil, fmt.Errorf("%w: key ID [%s]", ErrKeyNotFound, keyID)
+		}
+
+		// Any other error is assumed to be caused by the KMS being (temporarily) unavailable.
+		return nil, orberrors.NewTransient(fmt.Errorf("get key [%s]: %w", keyID, err))
 	}
 
 	metrics.SignerGetKey(time.Since(getKeyStartTime))
@@ -266,7 +336,7 @@ func (ks *kmsSigner) Sign(data []byte) ([]byte, error) {
 
 	v, err := ks.crypto.Sign(data, ks.keyHandle)
 	if err != nil {
-		return nil, err
+		return nil, orberrors.NewTransient(err)
 	}
 
 	return v, nil
@@ -7,19 +7,24 @@ SPDX-License-Identifier: Apache-2.0
 package vcsigner
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	cryptomock "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	"github.com/stretchr/testify/require"
 
+	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/mocks"
 )
 
+var _ CredentialSigner = (*Signer)(nil)
+
 func TestSigner_New(t *testing.T) {
 	providers := &Providers{
 		KeyManager: &mockkms.KeyManager{},
@@ -93,6 +98,22 @@ func TestSigner_Sign(t *testing.T) {
 		require.Equal(t, now.Format(time.RFC3339Nano), signedVC.Proofs[0]["created"])
 	})
 
+	t.Run("success - with signature suite and verification method", func(t *testing.T) {
+		s, err := New(providers, signingParams)
+		require.NoError(t, err)
+
+		signedVC, err := s.Sign(
+			&verifiable.Credential{ID: "https://example.edu/credentials/1872"},
+			WithSignatureSuite(Ed25519Signature2020),
+			WithVerificationMethod("did:abc:123#key2"),
+		)
+		require.NoError(t, err)
+		require.Len(t, signedVC.Proofs, 1)
+		require.Equal(t, Ed25519Signature2020, signedVC.Proofs[0]["type"])
+		require.Equal(t, "did:abc:123#key2", signedVC.Proofs[0]["verificationMethod"])
+		require.Nil(t, signedVC.Proofs[0]["jws"])
+	})
+
 	t.Run("success - Ed25519Signature2018", func(t *testing.T) {
 		signingParamsWithED25519 := SigningParams{
 			VerificationMethod: "did:abc:123#key1",
@@ -169,6 +190,44 @@ func TestSigner_Sign(t *testing.T) {
 		signedVC, err := c.Sign(&verifiable.Credential{ID: "http://example.edu/credentials/1872"})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to sign vc")
+		require.True(t, orberrors.IsTransient(err))
+		require.Nil(t, signedVC)
+	})
+
+	t.Run("error - key not found", func(t *testing.T) {
+		providersWithKMSErr := &Providers{
+			KeyManager: &mockkms.KeyManager{GetKeyErr: fmt.Errorf("get key: %w", kms.ErrKeyNotFound)},
+			Crypto:     &cryptomock.Crypto{},
+			DocLoader:  testutil.GetLoader(t),
+			Metrics:    &mocks.MetricsProvider{},
+		}
+
+		c, err := New(providersWithKMSErr, signingParams)
+		require.NoError(t, err)
+
+		signedVC, err := c.Sign(&verifiable.Credential{ID: "http://example.edu/credentials/1872"})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+		require.False(t, orberrors.IsTransient(err))
+		require.Nil(t, signedVC)
+	})
+
+	t.Run("error - KMS unavailable", func(t *testing.T) {
+		providersWithKMSErr := &Providers{
+			KeyManager: &mockkms.KeyManager{GetKeyErr: errors.New("connection refused")},
+			Crypto:     &cryptomock.Crypto{},
+			DocLoader:  testutil.GetLoader(t),
+			Metrics:    &mocks.MetricsProvider{},
+		}
+
+		c, err := New(providersWithKMSErr, signingParams)
+		require.NoError(t, err)
+
+		signedVC, err := c.Sign(&verifiable.Credential{ID: "http://example.edu/credentials/1872"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection refused")
+		require.True(t, orberrors.IsTransient(err))
+		require.False(t, errors.Is(err, ErrKeyNotFound))
 		require.Nil(t, signedVC)
 	})
 }
@@ -250,6 +309,11 @@ func TestSigner_Context(t *testing.T) {
 		require.Contains(t, s.Context(), CtxEd25519Signature2020)
 	})
 
+	t.Run("SuiteContext", func(t *testing.T) {
+		require.Equal(t, []string{CtxEd25519Signature2020}, SuiteContext(Ed25519Signature2020))
+		require.Empty(t, SuiteContext("invalid"))
+	})
+
 	t.Run("Not supported", func(t *testing.T) {
 		s, err := New(&Providers{}, SigningParams{
 			SignatureSuite:     "xxx",
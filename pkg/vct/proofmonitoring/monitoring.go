@@ -261,13 +261,20 @@ func (c *Client) handleEntities() (time.Duration, error) {
 			continue
 		}
 
+		// Monitoring may have been cancelled after the query was executed.
+		if _, err = c.store.Get(key(vc.ID)); errors.Is(err, storage.ErrDataNotFound) {
+			logger.Debug("Proof monitoring was cancelled for credential", logfields.WithVerifiableCredentialID(vc.ID))
+
+			continue
+		}
+
 		err = c.exist(e)
 		if err == nil {
 			logger.Info("Credential existence in the ledger is confirmed",
 				logfields.WithVerifiableCredentialID(vc.ID), logfields.WithDomain(e.Domain))
 
 			// removes the entity from the store bc we confirmed that credential is in MT (log above).
-			if err = c.store.Delete(key(vc.ID)); err != nil {
+			if err = c.delete(vc.ID); err != nil {
 				logger.Error("Error deleting credential from queue",
 					logfields.WithVerifiableCredentialID(vc.ID), log.WithError(err))
 			}
@@ -286,7 +293,7 @@ func (c *Client) handleEntities() (time.Duration, error) {
 			logfields.WithVerifiableCredentialID(vc.ID), logfields.WithDomain(e.Domain))
 
 		// removes entity from the store bc we failed our promise (log above).
-		if err = c.store.Delete(key(vc.ID)); err != nil {
+		if err = c.delete(vc.ID); err != nil {
 			logger.Error("Error deleting credential from queue",
 				logfields.WithVerifiableCredentialID(vc.ID), log.WithError(err))
 		}
@@ -325,6 +332,29 @@ func (c *Client) Watch(vc *verifiable.Credential, endTime time.Time, domain stri
 	return c.checkExistenceInLedger(vc, domain, created, endTime)
 }
 
+// Cancel stops monitoring the proof of the credential with the given ID. This is done when the anchor
+// for the credential is superseded by a newer anchor. It is not an error to cancel monitoring for a
+// credential that isn't being monitored (for example, if the proof was already confirmed or if
+// monitoring was already cancelled).
+func (c *Client) Cancel(vcID string) error {
+	if err := c.delete(vcID); err != nil {
+		return fmt.Errorf("cancel proof monitoring for credential [%s]: %w", vcID, err)
+	}
+
+	logger.Debug("Cancelled proof monitoring for credential", logfields.WithVerifiableCredentialID(vcID))
+
+	return nil
+}
+
+func (c *Client) delete(vcID string) error {
+	err := c.store.Delete(key(vcID))
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return err
+	}
+
+	return nil
+}
+
 func (c *Client) checkExistenceInLedger(vc *verifiable.Credential, domain string, created, endTime time.Time) error {
 	raw, err := vc.MarshalJSON()
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -752,6 +753,131 @@ func TestClient_Watch(t *testing.T) { //nolint:cyclop,maintidx
 	})
 }
 
+func TestClient_Cancel(t *testing.T) {
+	wfClient := wfclient.New(wfclient.WithHTTPClient(httpMock(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewBufferString(webfingerPayload)),
+			StatusCode: http.StatusOK,
+		}, nil
+	})))
+
+	var numVCTRequests int32
+
+	// The VCT server never returns a proof so that the credential stays in the queue.
+	vctHTTPClient := httpMock(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&numVCTRequests, 1)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+			StatusCode: http.StatusInternalServerError,
+		}, nil
+	})
+
+	newVC := func() *verifiable.Credential {
+		id := "https://orb.domain.com/" + uuid.New().String()
+
+		return &verifiable.Credential{
+			ID:      id,
+			Context: []string{"https://www.w3.org/2018/credentials/v1"},
+			Subject: id,
+			Issuer:  verifiable.Issuer{ID: id},
+			Issued:  &util.TimeWrapper{},
+			Types:   []string{"VerifiableCredential"},
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		db := mem.NewProvider()
+
+		taskMgr := mocks.NewTaskManager("vct-monitor")
+
+		client, err := New(db, testutil.GetLoader(t), wfClient, vctHTTPClient, taskMgr,
+			WithMonitoringInterval(time.Second))
+		require.NoError(t, err)
+
+		vc1 := newVC()
+		vc2 := newVC()
+
+		require.NoError(t, client.Watch(vc1, time.Now().Add(time.Minute), "https://vct.com", time.Now()))
+		require.NoError(t, client.Watch(vc2, time.Now().Add(time.Minute), "https://vct.com", time.Now()))
+
+		checkQueue(t, db, 2)
+
+		require.NoError(t, client.Cancel(vc1.ID))
+
+		checkQueue(t, db, 1)
+
+		// Cancellation is idempotent.
+		require.NoError(t, client.Cancel(vc1.ID))
+
+		// Credential isn't being monitored.
+		require.NoError(t, client.Cancel("https://orb.domain.com/"+uuid.New().String()))
+
+		checkQueue(t, db, 1)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		db := newDBMock(t)
+		db.mockStore.errDelete = func() error {
+			return errors.New("injected delete error")
+		}
+
+		client, err := New(db, testutil.GetLoader(t), wfClient, vctHTTPClient, mocks.NewTaskManager("vct-monitor"),
+			WithMonitoringInterval(time.Second))
+		require.NoError(t, err)
+
+		err = client.Cancel("https://orb.domain.com/" + uuid.New().String())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cancel proof monitoring")
+		require.Contains(t, err.Error(), "injected delete error")
+	})
+
+	t.Run("Cancelled while the worker is processing the queue", func(t *testing.T) {
+		db := newDBMock(t)
+
+		taskMgr := mocks.NewTaskManager("vct-monitor").WithInterval(100 * time.Millisecond)
+
+		client, err := New(db, testutil.GetLoader(t), wfClient, vctHTTPClient, taskMgr,
+			WithMonitoringInterval(100*time.Millisecond))
+		require.NoError(t, err)
+
+		vc := newVC()
+
+		require.NoError(t, client.Watch(vc, time.Now().Add(time.Minute), "https://vct.com", time.Now()))
+
+		checkQueue(t, db, 1)
+
+		cancelled := make(chan struct{})
+
+		// Cancel monitoring after the worker has queried the credentials to be checked.
+		db.mockStore.setAfterQuery(func() {
+			db.mockStore.setAfterQuery(nil)
+
+			require.NoError(t, client.Cancel(vc.ID))
+
+			close(cancelled)
+		})
+
+		numRequests := atomic.LoadInt32(&numVCTRequests)
+
+		taskMgr.Start()
+		defer taskMgr.Stop()
+
+		select {
+		case <-cancelled:
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for the worker")
+		}
+
+		time.Sleep(300 * time.Millisecond)
+
+		checkQueue(t, db, 0)
+
+		// The VCT server should not have been contacted for the cancelled credential.
+		require.Equal(t, numRequests, atomic.LoadInt32(&numVCTRequests))
+	})
+}
+
 func checkQueue(t *testing.T, db storage.Provider, expected int) {
 	t.Helper()
 
@@ -789,9 +915,17 @@ func newDBMock(t *testing.T) *dbMock {
 
 type dbMockStore struct {
 	storage.Store
-	mu        *sync.Mutex
-	errQuery  func() error
-	errDelete func() error
+	mu         *sync.Mutex
+	errQuery   func() error
+	errDelete  func() error
+	afterQuery func()
+}
+
+func (m *dbMockStore) setAfterQuery(fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.afterQuery = fn
 }
 
 func (m *dbMockStore) Delete(key string) error {
@@ -807,13 +941,26 @@ func (m *dbMockStore) Delete(key string) error {
 
 func (m *dbMockStore) Query(expression string, options ...storage.QueryOption) (storage.Iterator, error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 
 	if m.errQuery != nil {
-		return nil, m.errQuery()
+		err := m.errQuery()
+
+		m.mu.Unlock()
+
+		return nil, err
+	}
+
+	it, err := m.Store.Query(expression, options...)
+
+	afterQuery := m.afterQuery
+
+	m.mu.Unlock()
+
+	if afterQuery != nil {
+		afterQuery()
 	}
 
-	return m.Store.Query(expression, options...)
+	return it, err
 }
 
 func (m *dbMock) OpenStore(_ string) (storage.Store, error) {
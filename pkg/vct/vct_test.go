@@ -369,15 +369,15 @@ func (m *mockSigner) Sign(vc *verifiable.Credential, opts ...vcsigner.Opt) (*ver
 		return nil, m.Err
 	}
 
-	ctx := &verifiable.LinkedDataProofContext{}
+	options := &vcsigner.SigningOptions{}
 
 	for _, opt := range opts {
-		opt(ctx)
+		opt(options)
 	}
 
 	vc.Proofs = append(vc.Proofs, map[string]interface{}{
-		"created": ctx.Created.Format(time.RFC3339Nano),
-		"domain":  ctx.Domain,
+		"created": options.Created.Format(time.RFC3339Nano),
+		"domain":  options.Domain,
 	})
 
 	return vc, nil
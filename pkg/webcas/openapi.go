@@ -26,3 +26,28 @@ type casGetResp struct { //nolint: unused
 // 200: casGetResp
 func casGetRequest() { //nolint: unused
 }
+
+// swagger:parameters casPutReq
+type casPutReq struct { //nolint: unused
+	// in: path
+	ID string `json:"id"`
+
+	// in: body
+	Body string
+}
+
+// swagger:response casPutResp
+type casPutResp struct { //nolint: unused
+	Body string
+}
+
+// handlePut swagger:route PUT /cas/{id} CAS casPutReq
+//
+// Stores content in the local Content Addressable Storage (CAS). The ID must be the hash of the content. This endpoint
+// is only available if CAS writes are enabled.
+//
+// Responses:
+//
+// 200: casPutResp
+func casPutRequest() { //nolint: unused
+}
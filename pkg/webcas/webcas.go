@@ -20,9 +20,13 @@ import (
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/cas/digest"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 )
 
+// DefaultPath is the path of the WebCAS endpoint if no base path is configured.
+const DefaultPath = "/cas"
+
 const (
 	loggerModule = "webcas"
 
@@ -37,13 +41,27 @@ type signatureVerifier interface {
 type WebCAS struct {
 	*resthandler.AuthHandler
 
-	casClient casapi.Client
-	logger    *log.Log
+	casClient     casapi.Client
+	basePath      string
+	logger        *log.Log
+	digestEnabled bool
+}
+
+// Option is an option for the WebCAS handler.
+type Option func(w *WebCAS)
+
+// WithDigestHeader enables/disables the Digest header (RFC 3230) in responses. The header contains
+// the SHA-256 digest of the response body so that clients may verify the integrity of the content.
+// The header is enabled by default.
+func WithDigestHeader(enabled bool) Option {
+	return func(w *WebCAS) {
+		w.digestEnabled = enabled
+	}
 }
 
 // Path returns the HTTP REST endpoint for the WebCAS service.
 func (w *WebCAS) Path() string {
-	return fmt.Sprintf("/cas/{%s}", cidPathVariable)
+	return fmt.Sprintf("%s/{%s}", w.basePath, cidPathVariable)
 }
 
 // Method returns the HTTP REST method for the WebCAS service.
@@ -61,19 +79,33 @@ type authTokenManager interface {
 }
 
 // New returns a new WebCAS, which contains a REST handler that implements WebCAS as defined in
-// https://trustbloc.github.io/did-method-orb/#webcas.
+// https://trustbloc.github.io/did-method-orb/#webcas. The WebCAS endpoint is served at the BasePath
+// of the given config (e.g. /orb/cas for a deployment behind a reverse proxy with a path prefix)
+// or at DefaultPath if the BasePath is empty.
 func New(authCfg *resthandler.Config, s spi.Store, verifier signatureVerifier,
-	casClient casapi.Client, tm authTokenManager,
+	casClient casapi.Client, tm authTokenManager, opts ...Option,
 ) *WebCAS {
+	cfg := *authCfg
+
+	if cfg.BasePath == "" {
+		cfg.BasePath = DefaultPath
+	}
+
 	h := &WebCAS{
-		casClient: casClient,
+		casClient:     casClient,
+		basePath:      cfg.BasePath,
+		digestEnabled: true,
+	}
+
+	for _, opt := range opts {
+		opt(h)
 	}
 
 	h.logger = log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(h.Path())))
 
-	h.AuthHandler = resthandler.NewAuthHandler(authCfg, "/cas/{%s}", http.MethodGet, s, verifier, tm,
+	h.AuthHandler = resthandler.NewAuthHandler(&cfg, "/{%s}", http.MethodGet, s, verifier, tm,
 		func(actorIRI *url.URL) (bool, error) {
-			// TODO: Does the actor need to be authorized? If so, how? A witness needs access to the /cas endpoint
+			// TODO: Does the actor need to be authorized? If so, how? A witness needs access to the CAS endpoint
 			// but does not need to be part of an actor's 'followers' or 'witnessing' collections (e.g. the case where
 			// an offer is sent to a non-system witness).
 			// So, for now, let all actors through.
@@ -139,6 +171,10 @@ func (w *WebCAS) handler(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if w.digestEnabled {
+		rw.Header().Set(digest.Header, digest.Compute(content))
+	}
+
 	_, err = rw.Write(content)
 	if err != nil {
 		log.WriteResponseBodyError(w.logger, err)
@@ -30,7 +30,7 @@ const casLink = "https://domain.com/cas"
 type failingResponseWriter struct{}
 
 func (f *failingResponseWriter) Header() http.Header {
-	return nil
+	return make(http.Header)
 }
 
 func (f *failingResponseWriter) Write([]byte) (int, error) {
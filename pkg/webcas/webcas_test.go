@@ -21,6 +21,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/cas/digest"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
@@ -80,6 +81,13 @@ func TestNew(t *testing.T) {
 	require.Equal(t, "/cas/{cid}", webCAS.Path())
 	require.Equal(t, http.MethodGet, webCAS.Method())
 	require.NotNil(t, webCAS.Handler())
+
+	t.Run("Configured base path", func(t *testing.T) {
+		webCAS := webcas.New(&resthandler.Config{BasePath: "/orb/cas"}, memstore.New(""), &mocks.SignatureVerifier{},
+			casClient, &apmocks.AuthTokenMgr{})
+		require.NotNil(t, webCAS)
+		require.Equal(t, "/orb/cas/{cid}", webCAS.Path())
+	})
 }
 
 func TestHandler(t *testing.T) {
@@ -117,6 +125,74 @@ func TestHandler(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, response.StatusCode)
 		require.Equal(t, sampleAnchorCredential, string(responseBody))
+		require.Equal(t, digest.Compute(responseBody), response.Header.Get(digest.Header))
+		require.NoError(t, digest.Verify(response.Header.Get(digest.Header), responseBody, rh))
+	})
+
+	t.Run("Content found with configured base path", func(t *testing.T) {
+		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		hl, err := casClient.Write([]byte(sampleAnchorCredential))
+		require.NoError(t, err)
+
+		webCAS := webcas.New(&resthandler.Config{BasePath: "/orb/cas"}, memstore.New(""), &mocks.SignatureVerifier{},
+			casClient, &apmocks.AuthTokenMgr{})
+		require.NotNil(t, webCAS)
+
+		router := mux.NewRouter()
+
+		router.HandleFunc(webCAS.Path(), webCAS.Handler())
+
+		testServer := httptest.NewServer(router)
+		defer testServer.Close()
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		response, err := http.DefaultClient.Get(testServer.URL + "/orb/cas/" + rh)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, response.Body.Close())
+		}()
+
+		responseBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, sampleAnchorCredential, string(responseBody))
+	})
+	t.Run("Digest header disabled", func(t *testing.T) {
+		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		hl, err := casClient.Write([]byte(sampleAnchorCredential))
+		require.NoError(t, err)
+
+		webCAS := webcas.New(&resthandler.Config{}, memstore.New(""), &mocks.SignatureVerifier{}, casClient,
+			&apmocks.AuthTokenMgr{}, webcas.WithDigestHeader(false))
+		require.NotNil(t, webCAS)
+
+		router := mux.NewRouter()
+
+		router.HandleFunc(webCAS.Path(), webCAS.Handler())
+
+		testServer := httptest.NewServer(router)
+		defer testServer.Close()
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		response, err := http.DefaultClient.Get(testServer.URL + "/cas/" + rh)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, response.Body.Close())
+		}()
+
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Empty(t, response.Header.Get(digest.Header))
 	})
 	t.Run("Content not found", func(t *testing.T) {
 		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webcas
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+// MaxWriteContentSize is the maximum size (in bytes) of content that may be written to the WebCAS endpoint.
+const MaxWriteContentSize = 10 * 1024 * 1024
+
+type casWriter interface {
+	Write(content []byte) (string, error)
+}
+
+// Writer is a WebCAS handler which stores the content in the body of a PUT request, e.g. in order to populate
+// a mirror. The content is only stored if its hash matches the resource hash in the request path, so content
+// can't be stored under an arbitrary hash. Only bearer token authorization is supported for writes.
+type Writer struct {
+	*resthandler.AuthHandler
+
+	casClient casWriter
+	basePath  string
+	hl        *hashlink.HashLink
+	logger    *log.Log
+}
+
+// Path returns the HTTP REST endpoint for the WebCAS writer.
+func (w *Writer) Path() string {
+	return fmt.Sprintf("%s/{%s}", w.basePath, cidPathVariable)
+}
+
+// Method returns the HTTP REST method for the WebCAS writer.
+func (w *Writer) Method() string {
+	return http.MethodPut
+}
+
+// Handler returns the HTTP REST handler for the WebCAS writer.
+func (w *Writer) Handler() common.HTTPRequestHandler {
+	return w.handler
+}
+
+// NewWriter returns a new WebCAS writer. The endpoint is served at the BasePath of the given config or at
+// DefaultPath if the BasePath is empty. The resource hashes of the content are computed in the same way as
+// the local CAS, so the writer should only be used with a local CAS.
+func NewWriter(authCfg *resthandler.Config, s spi.Store, casClient casWriter, tm authTokenManager) *Writer {
+	cfg := *authCfg
+
+	if cfg.BasePath == "" {
+		cfg.BasePath = DefaultPath
+	}
+
+	h := &Writer{
+		casClient: casClient,
+		basePath:  cfg.BasePath,
+		hl:        hashlink.New(),
+	}
+
+	h.logger = log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(h.Path())))
+
+	// HTTP signatures aren't accepted for writes, so no signature verifier is provided.
+	h.AuthHandler = resthandler.NewAuthHandler(&cfg, "/{%s}", http.MethodPut, s, nil, tm, nil)
+
+	return h
+}
+
+func (w *Writer) handler(rw http.ResponseWriter, req *http.Request) {
+	ok, _, err := w.Authorize(req)
+	if err != nil {
+		w.logger.Error("Error authorizing request", logfields.WithRequestURL(req.URL), log.WithError(err))
+
+		w.writeResponse(rw, http.StatusInternalServerError, "Internal Server Error.\n")
+
+		return
+	}
+
+	if !ok {
+		w.logger.Info("Request is unauthorized", logfields.WithRequestURL(req.URL))
+
+		w.writeResponse(rw, http.StatusUnauthorized, "Unauthorized.\n")
+
+		return
+	}
+
+	cid := mux.Vars(req)[cidPathVariable]
+
+	content, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, MaxWriteContentSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+
+		if errors.As(err, &maxBytesErr) {
+			w.writeResponse(rw, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("content exceeds the maximum size of %d bytes", MaxWriteContentSize))
+
+			return
+		}
+
+		w.writeResponse(rw, http.StatusBadRequest, fmt.Sprintf("failed to read content: %s", err))
+
+		return
+	}
+
+	if len(content) == 0 {
+		w.writeResponse(rw, http.StatusBadRequest, "content is empty")
+
+		return
+	}
+
+	resourceHash, err := w.hl.CreateResourceHash(content)
+	if err != nil {
+		w.writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("failed to create resource hash: %s", err))
+
+		return
+	}
+
+	if resourceHash != cid {
+		w.logger.Info("Resource hash of content doesn't match the hash in the request",
+			logfields.WithRequestURL(req.URL), logfields.WithHash(resourceHash))
+
+		w.writeResponse(rw, http.StatusBadRequest,
+			fmt.Sprintf("resource hash of content [%s] doesn't match [%s]", resourceHash, cid))
+
+		return
+	}
+
+	hl, err := w.casClient.Write(content)
+	if err != nil {
+		w.logger.Error("Error writing content to CAS", logfields.WithHash(cid), log.WithError(err))
+
+		w.writeResponse(rw, http.StatusInternalServerError,
+			fmt.Sprintf("failure while writing content at %s: %s", cid, err))
+
+		return
+	}
+
+	w.logger.Debug("Wrote content to CAS", logfields.WithHash(cid), logfields.WithHashlink(hl))
+
+	w.writeResponse(rw, http.StatusOK, hl)
+}
+
+func (w *Writer) writeResponse(rw http.ResponseWriter, status int, body string) {
+	rw.WriteHeader(status)
+
+	if _, err := rw.Write([]byte(body)); err != nil {
+		log.WriteResponseBodyError(w.logger, err)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webcas_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/store/cas"
+	"github.com/trustbloc/orb/pkg/webcas"
+)
+
+func TestNewWriter(t *testing.T) {
+	casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+	require.NoError(t, err)
+
+	w := webcas.NewWriter(&resthandler.Config{}, memstore.New(""), casClient, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, w)
+	require.Equal(t, "/cas/{cid}", w.Path())
+	require.Equal(t, http.MethodPut, w.Method())
+	require.NotNil(t, w.Handler())
+
+	t.Run("Configured base path", func(t *testing.T) {
+		w := webcas.NewWriter(&resthandler.Config{BasePath: "/orb/cas"}, memstore.New(""), casClient,
+			&apmocks.AuthTokenMgr{})
+		require.Equal(t, "/orb/cas/{cid}", w.Path())
+	})
+}
+
+func TestWriter_Handler(t *testing.T) {
+	content := []byte(sampleAnchorCredential)
+
+	rh, err := hashlink.New().CreateResourceHash(content)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		serverURL := startWriter(t, webcas.NewWriter(&resthandler.Config{}, memstore.New(""), casClient,
+			&apmocks.AuthTokenMgr{}))
+
+		status, body := put(t, serverURL+"/cas/"+rh, content, "")
+		require.Equal(t, http.StatusOK, status)
+
+		hl, err := hashlink.New().ParseHashLink(body)
+		require.NoError(t, err)
+		require.Equal(t, rh, hl.ResourceHash)
+
+		storedContent, err := casClient.Read(rh)
+		require.NoError(t, err)
+		require.Equal(t, content, storedContent)
+	})
+
+	t.Run("Hash mismatch", func(t *testing.T) {
+		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		serverURL := startWriter(t, webcas.NewWriter(&resthandler.Config{}, memstore.New(""), casClient,
+			&apmocks.AuthTokenMgr{}))
+
+		status, body := put(t, serverURL+"/cas/"+rh, []byte("some other content"), "")
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Contains(t, body, "doesn't match")
+
+		_, err = casClient.Read(rh)
+		require.Error(t, err)
+	})
+
+	t.Run("Empty content", func(t *testing.T) {
+		serverURL := startWriter(t, webcas.NewWriter(&resthandler.Config{}, memstore.New(""), &mockCASWriter{},
+			&apmocks.AuthTokenMgr{}))
+
+		status, body := put(t, serverURL+"/cas/"+rh, nil, "")
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Contains(t, body, "content is empty")
+	})
+
+	t.Run("Content too large", func(t *testing.T) {
+		serverURL := startWriter(t, webcas.NewWriter(&resthandler.Config{}, memstore.New(""), &mockCASWriter{},
+			&apmocks.AuthTokenMgr{}))
+
+		status, _ := put(t, serverURL+"/cas/"+rh, make([]byte, webcas.MaxWriteContentSize+1), "")
+		require.Equal(t, http.StatusRequestEntityTooLarge, status)
+	})
+
+	t.Run("CAS write error", func(t *testing.T) {
+		serverURL := startWriter(t, webcas.NewWriter(&resthandler.Config{}, memstore.New(""),
+			&mockCASWriter{err: errors.New("injected write error")}, &apmocks.AuthTokenMgr{}))
+
+		status, body := put(t, serverURL+"/cas/"+rh, content, "")
+		require.Equal(t, http.StatusInternalServerError, status)
+		require.Contains(t, body, "injected write error")
+	})
+
+	t.Run("Authorization", func(t *testing.T) {
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"ADMIN_TOKEN"}, nil)
+
+		serverURL := startWriter(t, webcas.NewWriter(&resthandler.Config{}, memstore.New(""), &mockCASWriter{},
+			tm))
+
+		status, _ := put(t, serverURL+"/cas/"+rh, content, "")
+		require.Equal(t, http.StatusUnauthorized, status)
+
+		status, _ = put(t, serverURL+"/cas/"+rh, content, "ADMIN_TOKEN")
+		require.Equal(t, http.StatusOK, status)
+	})
+}
+
+func startWriter(t *testing.T, w *webcas.Writer) string {
+	t.Helper()
+
+	router := mux.NewRouter()
+
+	router.HandleFunc(w.Path(), w.Handler()).Methods(w.Method())
+
+	testServer := httptest.NewServer(router)
+	t.Cleanup(testServer.Close)
+
+	return testServer.URL
+}
+
+func put(t *testing.T, u string, content []byte, authToken string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPut, u, bytes.NewReader(content))
+	require.NoError(t, err)
+
+	if authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+authToken)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, resp.Body.Close())
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, string(body)
+}
+
+type mockCASWriter struct {
+	err error
+}
+
+func (m *mockCASWriter) Write([]byte) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+
+	return "hl:xxx", nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bdd
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// anchorOriginResolver returns the anchor origin to use for an operation that is sent to the given URL.
+type anchorOriginResolver func(targetURL string) (string, error)
+
+// anchorOriginConfig holds the anchor origin that's configured for each host so that operations sent
+// to different domains are stamped with the correct origin.
+type anchorOriginConfig struct {
+	mutex   sync.RWMutex
+	origins map[string]string
+}
+
+func newAnchorOriginConfig() *anchorOriginConfig {
+	return &anchorOriginConfig{
+		origins: make(map[string]string),
+	}
+}
+
+// set sets the anchor origin for operations that are sent to the given host (e.g. orb.domain1.com).
+func (c *anchorOriginConfig) set(host, anchorOrigin string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.origins[host] = anchorOrigin
+}
+
+// resolve returns the anchor origin that's configured for the host of the given URL. If no anchor origin
+// is configured for the host then the scheme and host of the URL are used. The function may be used as
+// an anchorOriginResolver.
+func (c *anchorOriginConfig) resolve(targetURL string) (string, error) {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return "", fmt.Errorf("parse target URL [%s]: %w", targetURL, err)
+	}
+
+	if u.Host == "" {
+		return "", fmt.Errorf("target URL [%s] has no host", targetURL)
+	}
+
+	c.mutex.RLock()
+	origin, ok := c.origins[u.Host]
+	c.mutex.RUnlock()
+
+	if !ok {
+		origin = fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+
+		logger.Infof("Anchor origin not configured for %s. Using %s", u.Host, origin)
+	} else {
+		logger.Infof("Using anchor origin %s for %s", origin, u.Host)
+	}
+
+	return origin, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bdd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/model"
+)
+
+func TestAnchorOriginConfig(t *testing.T) {
+	c := newAnchorOriginConfig()
+	c.set("orb.domain1.com", "https://orb.domain1.com/services/orb")
+	c.set("orb.domain2.com:8443", "ipns://k51qzi5uqu5dl3ua2aal8pj9x4ahbygb1e0u3lr4ea5ihf0dm6oelkt8t6ygvo")
+
+	t.Run("configured host", func(t *testing.T) {
+		origin, err := c.resolve("https://orb.domain1.com/sidetree/v1/operations")
+		require.NoError(t, err)
+		require.Equal(t, "https://orb.domain1.com/services/orb", origin)
+
+		origin, err = c.resolve("https://orb.domain2.com:8443/sidetree/v1/operations")
+		require.NoError(t, err)
+		require.Equal(t, "ipns://k51qzi5uqu5dl3ua2aal8pj9x4ahbygb1e0u3lr4ea5ihf0dm6oelkt8t6ygvo", origin)
+	})
+
+	t.Run("host not configured", func(t *testing.T) {
+		origin, err := c.resolve("https://orb.domain3.com/sidetree/v1/operations")
+		require.NoError(t, err)
+		require.Equal(t, "https://orb.domain3.com", origin)
+
+		// The port is part of the host.
+		origin, err = c.resolve("https://orb.domain1.com:8443/sidetree/v1/operations")
+		require.NoError(t, err)
+		require.Equal(t, "https://orb.domain1.com:8443", origin)
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		_, err := c.resolve(":invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse target URL")
+
+		_, err = c.resolve("orb.domain1.com")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "has no host")
+	})
+}
+
+func TestGetCreateRequest_AnchorOrigin(t *testing.T) {
+	c := newAnchorOriginConfig()
+	c.set("orb.domain1.com", "https://orb.domain1.com/services/orb")
+	c.set("orb.domain2.com", "https://orb.domain2.com/services/orb")
+
+	for targetURL, expectedOrigin := range map[string]string{
+		"https://orb.domain1.com/sidetree/v1/operations": "https://orb.domain1.com/services/orb",
+		"https://orb.domain2.com/sidetree/v1/operations": "https://orb.domain2.com/services/orb",
+		"https://orb.domain3.com/sidetree/v1/operations": "https://orb.domain3.com",
+	} {
+		_, _, reqBytes, err := getCreateRequest(targetURL, []byte(`{}`), nil, c.resolve)
+		require.NoError(t, err)
+
+		req := &model.CreateRequest{}
+		require.NoError(t, json.Unmarshal(reqBytes, req))
+		require.NotNil(t, req.SuffixData)
+		require.Equal(t, expectedOrigin, req.SuffixData.AnchorOrigin, "unexpected anchor origin for %s", targetURL)
+	}
+
+	t.Run("resolver error", func(t *testing.T) {
+		_, _, _, err := getCreateRequest("orb.domain1.com", []byte(`{}`), nil, c.resolve)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "has no host")
+	})
+}
@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bdd
+
+import (
+	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
+	"github.com/trustbloc/sidetree-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/model"
+)
+
+// Canonicalizer marshals an object to its canonical form. Nodes that use a different canonicalization
+// scheme may be targeted by supplying a different canonicalizer.
+type Canonicalizer func(obj interface{}) ([]byte, error)
+
+// defaultCanonicalizer is the JCS-style canonical marshaller used by Sidetree.
+var defaultCanonicalizer Canonicalizer = canonicalizer.MarshalCanonical
+
+// DIDStepsOpt is an option for the DID steps.
+type DIDStepsOpt func(d *DIDOrbSteps)
+
+// WithCanonicalizer sets the canonicalizer that is used to encode the initial state of a create request.
+func WithCanonicalizer(c Canonicalizer) DIDStepsOpt {
+	return func(d *DIDOrbSteps) {
+		d.canonicalize = c
+	}
+}
+
+// encodeInitialState returns the encoded initial state (suffix data and delta) of the given create request.
+func encodeInitialState(req *model.CreateRequest, canonicalize Canonicalizer) (string, error) {
+	createReq := &model.CreateRequest{
+		Delta:      req.Delta,
+		SuffixData: req.SuffixData,
+	}
+
+	bytes, err := canonicalize(createReq)
+	if err != nil {
+		return "", err
+	}
+
+	return encoder.EncodeToString(bytes), nil
+}
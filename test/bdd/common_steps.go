@@ -44,6 +44,9 @@ const (
 	databaseTypeEnvVar = "DATABASE_TYPE"
 	databaseURLEnvVar  = "DATABASE_URL"
 	kmsEndpointEnvVar  = "ORB_KMS_ENDPOINT"
+	greylistFileEnvVar = "GREYLIST_FILE"
+
+	latencyAwareSelectionEnvVar = "LATENCY_AWARE_SELECTION"
 )
 
 var domains = map[string]string{
@@ -34,25 +34,25 @@ import (
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/ld"
 	ldstore "github.com/hyperledger/aries-framework-go/pkg/store/ld"
-	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
 	"github.com/trustbloc/sidetree-go/pkg/commitment"
 	"github.com/trustbloc/sidetree-go/pkg/document"
 	"github.com/trustbloc/sidetree-go/pkg/docutil"
-	"github.com/trustbloc/sidetree-go/pkg/encoder"
 	"github.com/trustbloc/sidetree-go/pkg/hashing"
 	"github.com/trustbloc/sidetree-go/pkg/patch"
-	"github.com/trustbloc/sidetree-go/pkg/util/ecsigner"
 	"github.com/trustbloc/sidetree-go/pkg/util/pubkey"
 	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/client"
 	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/model"
 
 	"github.com/trustbloc/orb/internal/pkg/ldcontext"
 	"github.com/trustbloc/orb/pkg/cas/ipfs"
-	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+	endpointclient "github.com/trustbloc/orb/pkg/discovery/endpoint/client"
+	"github.com/trustbloc/orb/pkg/document/util"
 	"github.com/trustbloc/orb/pkg/mocks"
 	"github.com/trustbloc/orb/pkg/orbclient/aoprovider"
+	"github.com/trustbloc/orb/pkg/orbclient/didhint"
 	"github.com/trustbloc/orb/pkg/orbclient/doctransformer"
 	"github.com/trustbloc/orb/pkg/orbclient/resolutionverifier"
+	"github.com/trustbloc/orb/test/bdd/internal/resolution"
 )
 
 var logger = logrus.New()
@@ -67,6 +67,9 @@ const (
 	anchorTimeDelta = 300
 
 	testCID = "bafkreie4a6z6hosibbfz2dd7vhsukojw3gwmldh5jvy5uh3mjfrubiq5hi"
+
+	// maxErrorMessageSize is the maximum number of bytes of an error response which are read.
+	maxErrorMessageSize = 4096
 )
 
 const addPublicKeysTemplate = `[
@@ -147,6 +150,7 @@ type DIDOrbSteps struct {
 	updateResponses          []*updateDIDResponse
 	httpClient               *httpClient
 	didPrintEnabled          bool
+	canonicalize             Canonicalizer
 }
 
 type didResponse interface {
@@ -215,8 +219,10 @@ func (r *createAndUpdateResponses) UpdateResponses() []*updateDIDResponse {
 }
 
 // NewDIDSideSteps
-func NewDIDSideSteps(context *BDDContext, state *state, httpClient *httpClient, namespace string) *DIDOrbSteps {
-	return &DIDOrbSteps{
+func NewDIDSideSteps(context *BDDContext, state *state, httpClient *httpClient, namespace string,
+	opts ...DIDStepsOpt,
+) *DIDOrbSteps {
+	d := &DIDOrbSteps{
 		bddContext:               context,
 		state:                    state,
 		namespace:                namespace,
@@ -224,54 +230,24 @@ func NewDIDSideSteps(context *BDDContext, state *state, httpClient *httpClient,
 		didPrintEnabled:          true,
 		createResponses:          newResponses[*createDIDResponse](),
 		createAndUpdateResponses: newCreateAndUpdateResponses(),
-	}
-}
-
-func (d *DIDOrbSteps) discoverEndpoints() error {
-	resp, err := d.httpClient.Get("https://localhost:48326/.well-known/did-orb")
-	if err != nil {
-		return err
+		canonicalize:             defaultCanonicalizer,
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("received status code %d: %s", resp.StatusCode, resp.ErrorMsg)
+	for _, opt := range opts {
+		opt(d)
 	}
 
-	var w restapi.WellKnownResponse
-	if err := json.Unmarshal(resp.Payload, &w); err != nil {
-		return err
-	}
-
-	resp, err = d.httpClient.Get(
-		fmt.Sprintf("https://localhost:48326/.well-known/webfinger?resource=%s",
-			url.PathEscape(w.ResolutionEndpoint)))
-	if err != nil {
-		return err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("received status code %d: %s", resp.StatusCode, resp.ErrorMsg)
-	}
-
-	var webFingerResponse restapi.JRD
-	if err := json.Unmarshal(resp.Payload, &webFingerResponse); err != nil {
-		return err
-	}
-
-	d.resolutionEndpoint = strings.ReplaceAll(webFingerResponse.Links[0].Href, "orb.domain1.com", "localhost:48326")
+	return d
+}
 
-	resp, err = d.httpClient.Get(
-		fmt.Sprintf("https://localhost:48326/.well-known/webfinger?resource=%s",
-			url.PathEscape(w.OperationEndpoint)))
+func (d *DIDOrbSteps) discoverEndpoints() error {
+	endpoints, err := endpointclient.DiscoverEndpoints("https://localhost:48326", d.httpClient)
 	if err != nil {
 		return err
 	}
 
-	if err := json.Unmarshal(resp.Payload, &webFingerResponse); err != nil {
-		return err
-	}
-
-	d.operationEndpoint = strings.ReplaceAll(webFingerResponse.Links[0].Href, "orb.domain1.com", "localhost:48326")
+	d.resolutionEndpoint = strings.ReplaceAll(endpoints.ResolutionEndpoint, "orb.domain1.com", "localhost:48326")
+	d.operationEndpoint = strings.ReplaceAll(endpoints.OperationEndpoint, "orb.domain1.com", "localhost:48326")
 
 	return nil
 }
@@ -297,7 +273,7 @@ func (d *DIDOrbSteps) clientRequestsAnchorOrigin(url string) error {
 		return nil
 	}
 
-	cid, suffix, err := extractCIDAndSuffix(d.canonicalDID)
+	_, cid, suffix, err := util.ParseCanonicalDID(d.canonicalDID)
 	if err != nil {
 		return err
 	}
@@ -414,16 +390,6 @@ func (d *DIDOrbSteps) clientFailsToVerifyResolvedDocument() error {
 	return fmt.Errorf("unexpected error for mis-configured client: %w", err)
 }
 
-func extractCIDAndSuffix(canonicalID string) (string, string, error) {
-	parts := strings.Split(canonicalID, ":")
-
-	if len(parts) != 4 {
-		return "", "", fmt.Errorf("expected 4 got %d parts for canonical ID", len(parts))
-	}
-
-	return parts[2], parts[3], nil
-}
-
 func (d *DIDOrbSteps) createDIDDocumentSaveIDToVar(url, varName string) error {
 	if err := d.createDIDDocument(url); err != nil {
 		return err
@@ -529,7 +495,7 @@ func (d *DIDOrbSteps) createDIDDocument(url string) error {
 		return err
 	}
 
-	recoveryKey, updateKey, reqBytes, err := getCreateRequest(d.sidetreeURL, opaqueDoc, nil, d.state)
+	recoveryKey, updateKey, reqBytes, err := getCreateRequest(d.sidetreeURL, opaqueDoc, nil, d.state.getAnchorOrigin)
 	if err != nil {
 		return err
 	}
@@ -951,9 +917,10 @@ func (d *DIDOrbSteps) resolveDIDDocumentWithCanonicalDIDAndVersionTime(url, vers
 }
 
 func (d *DIDOrbSteps) resolveDIDDocumentWithHint(url, hint string) error {
-	cannonicalDIDParts := strings.SplitAfter(d.canonicalDID, d.namespace)
-
-	didWithHint := cannonicalDIDParts[0] + ":" + hint + cannonicalDIDParts[1]
+	didWithHint, err := didhint.BuildHintedDID(d.canonicalDID, hint)
+	if err != nil {
+		return err
+	}
 
 	logger.Infof("resolving did with hint: %s", didWithHint)
 
@@ -963,9 +930,10 @@ func (d *DIDOrbSteps) resolveDIDDocumentWithHint(url, hint string) error {
 }
 
 func (d *DIDOrbSteps) resolveInterimDIDDocumentWithHint(url, hint string) error {
-	interimDIDParts := strings.SplitAfter(d.interimDID, d.namespace)
-
-	interimDidWithHint := interimDIDParts[0] + ":" + hint + interimDIDParts[1]
+	interimDidWithHint, err := didhint.BuildHintedDID(d.interimDID, hint)
+	if err != nil {
+		return err
+	}
 
 	logger.Infof("resolving interim did with hint: %s", interimDidWithHint)
 
@@ -1065,20 +1033,11 @@ func (d *DIDOrbSteps) resolveDIDDocumentWithInitialValue(url string) error {
 }
 
 func (d *DIDOrbSteps) getInitialState() (string, error) {
-	createReq := &model.CreateRequest{
-		Delta:      d.createRequest.Delta,
-		SuffixData: d.createRequest.SuffixData,
-	}
-
-	bytes, err := canonicalizer.MarshalCanonical(createReq)
-	if err != nil {
-		return "", err
-	}
-
-	return encoder.EncodeToString(bytes), nil
+	return encodeInitialState(d.createRequest, d.canonicalize)
 }
 
-func getCreateRequest(strUrl string, doc []byte, patches []patch.Patch, state *state) (*ecdsa.PrivateKey, *ecdsa.PrivateKey, []byte, error) {
+func getCreateRequest(strUrl string, doc []byte, patches []patch.Patch,
+	resolveAnchorOrigin anchorOriginResolver) (*ecdsa.PrivateKey, *ecdsa.PrivateKey, []byte, error) {
 	recoveryKey, recoveryCommitment, err := generateKeyAndCommitment()
 	if err != nil {
 		return nil, nil, nil, err
@@ -1089,7 +1048,7 @@ func getCreateRequest(strUrl string, doc []byte, patches []patch.Patch, state *s
 		return nil, nil, nil, err
 	}
 
-	origin, err := state.getAnchorOrigin(strUrl)
+	origin, err := resolveAnchorOrigin(strUrl)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -1110,8 +1069,18 @@ func getCreateRequest(strUrl string, doc []byte, patches []patch.Patch, state *s
 }
 
 func (d *DIDOrbSteps) getRecoverRequest(doc []byte, patches []patch.Patch, uniqueSuffix string) ([]byte, *ecdsa.PrivateKey, *ecdsa.PrivateKey, error) {
-	currentRecoveryKey := d.getLatestRecoveryKey()
+	origin, err := d.state.getAnchorOrigin(d.sidetreeURL)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
+	// sign with the old recovery key
+	return getRecoverRequest(uniqueSuffix, newECDSAOperationSigner(d.getLatestRecoveryKey()), doc, patches, origin)
+}
+
+func getRecoverRequest(uniqueSuffix string, signer operationSigner, doc []byte, patches []patch.Patch,
+	origin string,
+) ([]byte, *ecdsa.PrivateKey, *ecdsa.PrivateKey, error) {
 	nextRecoveryKey, nextRecoveryCommitment, err := generateKeyAndCommitment()
 	if err != nil {
 		return nil, nil, nil, err
@@ -1123,7 +1092,7 @@ func (d *DIDOrbSteps) getRecoverRequest(doc []byte, patches []patch.Patch, uniqu
 	}
 
 	// recovery key and signer passed in are generated during previous operations
-	recoveryPubKey, err := pubkey.GetPublicKeyJWK(&currentRecoveryKey.PublicKey)
+	recoveryPubKey, err := signer.PublicKeyJWK()
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -1135,11 +1104,6 @@ func (d *DIDOrbSteps) getRecoverRequest(doc []byte, patches []patch.Patch, uniqu
 
 	now := time.Now().Unix()
 
-	origin, err := d.state.getAnchorOrigin(d.sidetreeURL)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
 	recoverRequest, err := client.NewRecoverRequest(&client.RecoverRequestInfo{
 		DidSuffix:          uniqueSuffix,
 		RevealValue:        revealValue,
@@ -1149,7 +1113,7 @@ func (d *DIDOrbSteps) getRecoverRequest(doc []byte, patches []patch.Patch, uniqu
 		RecoveryCommitment: nextRecoveryCommitment,
 		UpdateCommitment:   nextUpdateCommitment,
 		MultihashCode:      sha2_256,
-		Signer:             ecsigner.New(currentRecoveryKey, "ES256", ""), // sign with old signer
+		Signer:             signer,
 		AnchorFrom:         now,
 		AnchorUntil:        now + anchorTimeDelta,
 		AnchorOrigin:       origin,
@@ -1170,10 +1134,12 @@ func getUniqueSuffix(suffixData *model.SuffixDataModel) (string, error) {
 }
 
 func (d *DIDOrbSteps) getDeactivateRequest(did string) ([]byte, error) {
-	currentRecoveryKey := d.getLatestRecoveryKey()
+	return getDeactivateRequest(did, newECDSAOperationSigner(d.getLatestRecoveryKey()))
+}
 
+func getDeactivateRequest(did string, signer operationSigner) ([]byte, error) {
 	// recovery key and signer passed in are generated during previous operations
-	recoveryPubKey, err := pubkey.GetPublicKeyJWK(&currentRecoveryKey.PublicKey)
+	recoveryPubKey, err := signer.PublicKeyJWK()
 	if err != nil {
 		return nil, err
 	}
@@ -1187,7 +1153,7 @@ func (d *DIDOrbSteps) getDeactivateRequest(did string) ([]byte, error) {
 		DidSuffix:   did,
 		RevealValue: revealValue,
 		RecoveryKey: recoveryPubKey,
-		Signer:      ecsigner.New(currentRecoveryKey, "ES256", ""),
+		Signer:      signer,
 		AnchorFrom:  time.Now().Unix(),
 	})
 }
@@ -1201,17 +1167,17 @@ func (d *DIDOrbSteps) getLatestUpdateKey() *ecdsa.PrivateKey {
 }
 
 func (d *DIDOrbSteps) getUpdateRequest(did string, patches []patch.Patch) ([]byte, *ecdsa.PrivateKey, error) {
-	return getUpdateRequest(did, d.getLatestUpdateKey(), patches)
+	return getUpdateRequest(did, newECDSAOperationSigner(d.getLatestUpdateKey()), patches)
 }
 
-func getUpdateRequest(did string, currentUpdateKey *ecdsa.PrivateKey, patches []patch.Patch) ([]byte, *ecdsa.PrivateKey, error) {
+func getUpdateRequest(did string, signer operationSigner, patches []patch.Patch) ([]byte, *ecdsa.PrivateKey, error) {
 	nextUpdateKey, nextUpdateCommitment, err := generateKeyAndCommitment()
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// update key and signer passed in are generated during previous operations
-	updatePubKey, err := pubkey.GetPublicKeyJWK(&currentUpdateKey.PublicKey)
+	updatePubKey, err := signer.PublicKeyJWK()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1230,7 +1196,7 @@ func getUpdateRequest(did string, currentUpdateKey *ecdsa.PrivateKey, patches []
 		UpdateKey:        updatePubKey,
 		Patches:          patches,
 		MultihashCode:    sha2_256,
-		Signer:           ecsigner.New(currentUpdateKey, "ES256", ""),
+		Signer:           signer,
 		AnchorFrom:       now,
 		AnchorUntil:      now + anchorTimeDelta,
 	})
@@ -1761,7 +1727,11 @@ func (d *DIDOrbSteps) verifyDIDDocuments(strURLs string) error {
 }
 
 func (d *DIDOrbSteps) verifyDIDDocumentsFromFile(strURLs, file, strAttempts string) error {
-	if err := d.state.resolveVarsInExpression(&strURLs, &file, &strAttempts); err != nil {
+	return d.verifyDIDDocumentsFromFileConcurrently(strURLs, file, strAttempts, "1")
+}
+
+func (d *DIDOrbSteps) verifyDIDDocumentsFromFileConcurrently(strURLs, file, strAttempts, strConcurrency string) error {
+	if err := d.state.resolveVarsInExpression(&strURLs, &file, &strAttempts, &strConcurrency); err != nil {
 		return err
 	}
 
@@ -1770,42 +1740,109 @@ func (d *DIDOrbSteps) verifyDIDDocumentsFromFile(strURLs, file, strAttempts stri
 		return fmt.Errorf("invalid value for attempts: %w", err)
 	}
 
+	concurrency, err := strconv.Atoi(strConcurrency)
+	if err != nil {
+		return fmt.Errorf("invalid value for concurrency: %w", err)
+	}
+
+	if concurrency < 1 {
+		return fmt.Errorf("invalid value for concurrency: %d", concurrency)
+	}
+
 	reader, err := d.newReader(file)
 	if err != nil {
 		return fmt.Errorf("get DID file from [%s]: %w", file, err)
 	}
 
-	logger.Infof("Verifying created DIDs from file [%s] at %s with %d retry attempt(s)",
-		file, strURLs, attempts)
+	logger.Infof("Verifying created DIDs from file [%s] at %s with %d retry attempt(s) using %d concurrent request(s)",
+		file, strURLs, attempts, concurrency)
+
+	urls := strings.Split(strURLs, ",")
+
+	var firstErr error
+
+	failed := 0
+
+	// Handle each response as it's received so that the resolution results aren't held in memory.
+	p := NewStreamingWorkerPool[string](concurrency,
+		func(resp *Response[string]) {
+			if resp.Err == nil {
+				return
+			}
+
+			logger.Errorf("Failed to verify DID from %s: %s", resp.Request.URL(), resp.Err)
+
+			if firstErr == nil {
+				firstErr = resp.Err
+			}
+
+			failed++
+		},
+		WithTaskDescription("verify DIDs from file"),
+	)
+
+	p.Start()
 
+	// Submit each DID as it is read from the file so that the list of DIDs (and the resolution
+	// results) don't need to be held in memory. Submit blocks while all workers are busy.
 	scanner := bufio.NewScanner(reader)
 
-	var dids []string
+	total := 0
 
 	for scanner.Scan() {
-		dids = append(dids, scanner.Text())
+		p.Submit(&verifyDIDRequest{
+			steps:    d,
+			url:      fmt.Sprintf("%s/sidetree/v1/identifiers", urls[mrand.Intn(len(urls))]),
+			did:      scanner.Text(),
+			attempts: attempts,
+		})
+
+		total++
 	}
 
+	p.Stop()
+
 	if err := scanner.Err(); err != nil {
 		logger.Errorf("Error verifying created DIDs from file [%s]: %s", file, err)
 
 		return err
 	}
 
-	urls := strings.Split(strURLs, ",")
+	if p.NumResponses() != total {
+		return fmt.Errorf("expecting %d responses but got %d", total, p.NumResponses())
+	}
 
-	for i, did := range dids {
-		_, e := d.verifyDID(fmt.Sprintf("%s/sidetree/v1/identifiers", urls[mrand.Intn(len(urls))]), did, attempts)
-		if e != nil {
-			return e
-		}
+	if failed > 0 {
+		return fmt.Errorf("failed to verify %d of %d DIDs from file [%s]: %w", failed, total, file, firstErr)
+	}
 
-		if (i+1)%100 == 0 {
-			logger.Warnf("... verified %d out of %d DIDs", i+1, len(dids))
-		}
+	logger.Warnf("... verified %d DIDs from file [%s]", total, file)
+
+	return nil
+}
+
+func (d *DIDOrbSteps) verifyDIDDocumentsFromFileExpectingFailures(strURLs, file, strAttempts, strConcurrency,
+	strFailures string,
+) error {
+	if err := d.state.resolveVarsInExpression(&strFailures); err != nil {
+		return err
+	}
+
+	failures, err := strconv.Atoi(strFailures)
+	if err != nil {
+		return fmt.Errorf("invalid value for failures: %w", err)
+	}
+
+	err = d.verifyDIDDocumentsFromFileConcurrently(strURLs, file, strAttempts, strConcurrency)
+	if err == nil {
+		return fmt.Errorf("expecting %d DID(s) to fail verification but all DIDs were verified", failures)
+	}
+
+	if !strings.Contains(err.Error(), fmt.Sprintf("failed to verify %d of", failures)) {
+		return fmt.Errorf("expecting %d DID(s) to fail verification but got: %w", failures, err)
 	}
 
-	logger.Warnf("... verified %d DIDs from file [%s]", len(dids), file)
+	logger.Infof("Got expected error: %s", err)
 
 	return nil
 }
@@ -1872,54 +1909,38 @@ func (d *DIDOrbSteps) doVerifyUpdatedDIDContainsKeyID(urls []string, did, keyID
 func (d *DIDOrbSteps) verifyDID(url, did string, attempts int) (canonicalID string, err error) {
 	logger.Infof("verifying DID %s from %s", did, url)
 
-	resp, err := d.httpClient.GetWithRetryFunc(url+"/"+did, attempts,
-		func(resp *httpResponse) bool {
-			if resp.StatusCode == http.StatusNotFound {
-				return true
-			}
-
+	err = d.httpClient.GetStreamWithRetryFunc(url+"/"+did, attempts,
+		func(resp *http.Response) (bool, error) {
 			if resp.StatusCode != http.StatusOK {
-				return resp.StatusCode >= http.StatusInternalServerError
+				errMsg, e := io.ReadAll(io.LimitReader(resp.Body, maxErrorMessageSize))
+				if e != nil {
+					return false, fmt.Errorf("read response body: %w", e)
+				}
+
+				return resp.StatusCode == http.StatusNotFound || resp.StatusCode >= http.StatusInternalServerError,
+					fmt.Errorf("failed to resolve DID [%s] - Status code %d: %s", did, resp.StatusCode, errMsg)
 			}
 
-			var rr document.ResolutionResult
-			err = json.Unmarshal(resp.Payload, &rr)
-			if err != nil {
-				return false
+			// The resolution result is decoded directly from the response body so that it isn't held in memory.
+			cID, e := resolution.DecodeCanonicalID(resp.Body)
+			if e != nil {
+				return false, fmt.Errorf("failed to resolve DID [%s]: %w", did, e)
 			}
 
-			_, ok := rr.DocumentMetadata["canonicalId"]
-			if !ok {
+			if cID == "" {
 				// The DID is not anchored yet. Retry until it's anchored.
-				logger.Infof("Document metadata is missing field 'canonicalId'. Retrying.")
-
-				return true
+				return true, fmt.Errorf("document metadata for DID [%s] is missing field 'canonicalId'", did)
 			}
 
-			return false
+			canonicalID = cID
+
+			return false, nil
 		},
 	)
-	if err != nil {
-		return "", fmt.Errorf("failed to resolve DID[%s]: %w", did, err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to resolve DID [%s] - Status code %d: %s", did, resp.StatusCode, resp.ErrorMsg)
-	}
-
-	var rr document.ResolutionResult
-	err = json.Unmarshal(resp.Payload, &rr)
 	if err != nil {
 		return "", err
 	}
 
-	cID, ok := rr.DocumentMetadata["canonicalId"]
-	if !ok {
-		return "", fmt.Errorf("document metadata is missing field 'canonicalId': %s", resp.Payload)
-	}
-
-	canonicalID = cID.(string)
-
 	logger.Infof(".. successfully verified DID %s from %s", canonicalID, url)
 
 	return canonicalID, nil
@@ -2046,6 +2067,7 @@ type createDIDRequest struct {
 	maxAttempts int
 	backoff     time.Duration
 	greylist    *greylist
+	selector    *endpointSelector
 	state       *state
 }
 
@@ -2063,13 +2085,31 @@ func (r *createDIDResponse) DID() string {
 func newCreateDIDRequest(state *state, httpClient *httpClient, urls []string, attempts int, greylistDuration time.Duration,
 	shouldRetry func(*httpResponse, error) bool,
 ) *createDIDRequest {
+	var greylistOpts []greylistOpt
+
+	if greylistFile := os.Getenv(greylistFileEnvVar); greylistFile != "" {
+		greylistOpts = append(greylistOpts, withGreylistFile(greylistFile))
+	}
+
+	var selectorOpts []EndpointSelectorOpt
+
+	if latencyAware := os.Getenv(latencyAwareSelectionEnvVar); latencyAware != "" {
+		enable, err := strconv.ParseBool(latencyAware)
+		if err != nil {
+			logger.Warnf("Invalid value for %s: %s", latencyAwareSelectionEnvVar, err)
+		}
+
+		selectorOpts = append(selectorOpts, WithLatencyAwareSelection(enable))
+	}
+
 	return &createDIDRequest{
 		urls:        urls,
 		httpClient:  httpClient,
 		shouldRetry: shouldRetry,
 		maxAttempts: attempts,
 		backoff:     3 * time.Second,
-		greylist:    newGreylist(greylistDuration),
+		greylist:    newGreylist(greylistDuration, greylistOpts...),
+		selector:    newEndpointSelector(urls, selectorOpts...),
 		state:       state,
 	}
 }
@@ -2091,24 +2131,14 @@ func (r *createDIDRequest) Invoke() (*createDIDResponse, error) {
 		maxAttempts = 1
 	}
 
-	index := mrand.Intn(len(r.urls))
-
 	for i := 0; i < maxAttempts; i++ {
-		if index >= len(r.urls) {
-			index = 0
-		}
-
-		u := r.urls[index]
-
-		if len(r.urls) > 0 && r.greylist.IsGreylisted(u) {
+		u := r.selector.Select(r.greylist.IsGreylisted)
+		if u == "" {
 			continue
 		}
 
 		r.url = u
 
-		// A retry will be performed on a different URL.
-		index++
-
 		logger.Infof("creating DID document at %s", u)
 
 		opaqueDoc, err := getOpaqueDocument("key1")
@@ -2116,14 +2146,19 @@ func (r *createDIDRequest) Invoke() (*createDIDResponse, error) {
 			return nil, err
 		}
 
-		recoveryKey, updateKey, reqBytes, err = getCreateRequest(u, opaqueDoc, nil, r.state)
+		recoveryKey, updateKey, reqBytes, err = getCreateRequest(u, opaqueDoc, nil, r.state.getAnchorOrigin)
 		if err != nil {
 			return nil, err
 		}
 
 		logger.Infof("Posting request to [%s] on attempt #%d", u, i+1)
 
+		start := time.Now()
+
 		resp, respErr = r.httpClient.Post(u, reqBytes, "application/json")
+
+		r.selector.Record(u, time.Since(start), respErr != nil || resp.StatusCode >= http.StatusInternalServerError)
+
 		if respErr != nil {
 			if r.shouldRetry(nil, respErr) {
 				logger.Warnf("Error posting request to [%s] on attempt %d: %s. Retrying in %s",
@@ -2206,7 +2241,7 @@ func (r *updateDIDRequest) Invoke() (*updateDIDResponse, error) {
 
 	logger.Infof("updating DID [%s] document at %s", uniqueSuffix, r.url)
 
-	reqBytes, newxtUpdate, err := getUpdateRequest(r.suffix, r.updateKey, r.patches)
+	reqBytes, newxtUpdate, err := getUpdateRequest(r.suffix, newECDSAOperationSigner(r.updateKey), r.patches)
 	if err != nil {
 		return nil, err
 	}
@@ -2229,6 +2264,21 @@ func (r *updateDIDRequest) Invoke() (*updateDIDResponse, error) {
 	}, nil
 }
 
+type verifyDIDRequest struct {
+	steps    *DIDOrbSteps
+	url      string
+	did      string
+	attempts int
+}
+
+func (r *verifyDIDRequest) URL() string {
+	return r.url
+}
+
+func (r *verifyDIDRequest) Invoke() (string, error) {
+	return r.steps.verifyDID(r.url, r.did, r.attempts)
+}
+
 type createAndUpdateDIDRequest struct {
 	*createDIDRequest
 
@@ -2340,6 +2390,8 @@ func (d *DIDOrbSteps) RegisterSteps(s *godog.Suite) {
 	s.Step(`^we wait up to "([^"]*)" for (\d+) DID documents to be created$`, d.waitForCreateDIDDocuments)
 	s.Step(`^we wait up to "([^"]*)" for (\d+) DID documents to be created and updated$`, d.waitForCreateAndUpdateDIDDocuments)
 	s.Step(`^client sends request to domains "([^"]*)" to verify the DID documents that were created from file "([^"]*)" with a maximum of "([^"]*)" attempts$`, d.verifyDIDDocumentsFromFile)
+	s.Step(`^client sends request to domains "([^"]*)" to verify the DID documents that were created from file "([^"]*)" with a maximum of "([^"]*)" attempts using "([^"]*)" concurrent requests$`, d.verifyDIDDocumentsFromFileConcurrently)
+	s.Step(`^client sends request to domains "([^"]*)" to verify the DID documents from file "([^"]*)" with a maximum of "([^"]*)" attempts using "([^"]*)" concurrent requests and "([^"]*)" DIDs fail verification$`, d.verifyDIDDocumentsFromFileExpectingFailures)
 	s.Step(`^client sends request to "([^"]*)" to update the DID documents that were created with public key ID "([^"]*)" using (\d+) concurrent requests$`, d.updateDIDDocuments)
 	s.Step(`^client sends request to "([^"]*)" to verify the DID documents that were updated with key "([^"]*)"$`, d.verifyUpdatedDIDDocuments)
 	s.Step(`^client sends request to "([^"]*)" to verify the DID documents that were created and updated with key "([^"]*)"$`, d.verifyCreatedAndUpdatedDIDDocuments)
@@ -0,0 +1,207 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bdd
+
+import (
+	mrand "math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// latencyIncreaseWeight is the weight of a new latency sample which is higher than the average. It's higher
+	// than latencyDecreaseWeight so that an endpoint which suddenly becomes slow is quickly deprioritized.
+	latencyIncreaseWeight = 0.5
+	// latencyDecreaseWeight is the weight of a new latency sample which is lower than the average.
+	latencyDecreaseWeight = 0.2
+	// latencyProbeRate is the fraction of selections which choose an endpoint at random (regardless of latency)
+	// so that a slow endpoint is occasionally probed in order to detect that it has recovered.
+	latencyProbeRate = 0.1
+	// failureLatency is the latency which is recorded for a failed request.
+	failureLatency = 10 * time.Second
+	// minLatency avoids a division by zero when calculating the weight of an endpoint.
+	minLatency = time.Millisecond
+)
+
+// endpointLatencies holds the average latency of each endpoint. It's shared by all selectors since a selector
+// is created per request.
+var endpointLatencies = &latencyTracker{latencies: make(map[string]time.Duration)}
+
+// latencyTracker maintains an exponentially-weighted moving average of the latency of each endpoint.
+type latencyTracker struct {
+	mutex     sync.RWMutex
+	latencies map[string]time.Duration
+}
+
+func (t *latencyTracker) record(u string, latency time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	avg, ok := t.latencies[u]
+	if !ok {
+		t.latencies[u] = latency
+
+		return
+	}
+
+	weight := latencyDecreaseWeight
+	if latency > avg {
+		weight = latencyIncreaseWeight
+	}
+
+	t.latencies[u] = avg + time.Duration(weight*float64(latency-avg))
+}
+
+func (t *latencyTracker) get(u string) (time.Duration, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	latency, ok := t.latencies[u]
+
+	return latency, ok
+}
+
+// EndpointSelectorOpt is an endpoint selector option.
+type EndpointSelectorOpt func(s *endpointSelector)
+
+// WithLatencyAwareSelection enables/disables latency-aware selection. If enabled then endpoints with a lower
+// average latency are preferred. Otherwise, endpoints are selected in rotation.
+func WithLatencyAwareSelection(enable bool) EndpointSelectorOpt {
+	return func(s *endpointSelector) {
+		s.latencyAware = enable
+	}
+}
+
+// endpointSelector selects the endpoint to which a request is sent. By default, endpoints are selected in
+// rotation, starting at a random endpoint. With latency-aware selection, an endpoint is selected at random
+// with a probability which is inversely proportional to its average latency, except for a small fraction of
+// selections which choose an endpoint uniformly at random (probes). An endpoint without any latency history
+// is given the weight of the fastest endpoint so that a newly-added endpoint gets a fair chance.
+type endpointSelector struct {
+	urls         []string
+	index        int
+	latencyAware bool
+	latencies    *latencyTracker
+}
+
+func newEndpointSelector(urls []string, opts ...EndpointSelectorOpt) *endpointSelector {
+	s := &endpointSelector{
+		urls:      urls,
+		latencies: endpointLatencies,
+	}
+
+	if len(urls) > 0 {
+		s.index = mrand.Intn(len(urls))
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Select returns the next endpoint which isn't excluded or an empty string if all endpoints are excluded.
+func (s *endpointSelector) Select(isExcluded func(u string) bool) string {
+	if !s.latencyAware {
+		return s.selectNext(isExcluded)
+	}
+
+	var candidates []string
+
+	for _, u := range s.urls {
+		if !isExcluded(u) {
+			candidates = append(candidates, u)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	if mrand.Float64() < latencyProbeRate {
+		return candidates[mrand.Intn(len(candidates))]
+	}
+
+	return s.selectByLatency(candidates)
+}
+
+// Record records the latency of a request to the given endpoint. A failed request is recorded with
+// a high latency so that the endpoint is deprioritized.
+func (s *endpointSelector) Record(u string, latency time.Duration, failed bool) {
+	if failed && latency < failureLatency {
+		latency = failureLatency
+	}
+
+	s.latencies.record(u, latency)
+}
+
+func (s *endpointSelector) selectNext(isExcluded func(u string) bool) string {
+	for i := 0; i < len(s.urls); i++ {
+		index := (s.index + i) % len(s.urls)
+
+		if !isExcluded(s.urls[index]) {
+			// A retry will be performed on a different URL.
+			s.index = index + 1
+
+			return s.urls[index]
+		}
+	}
+
+	return ""
+}
+
+func (s *endpointSelector) selectByLatency(candidates []string) string {
+	weights := make([]float64, len(candidates))
+
+	var maxWeight float64
+
+	for i, u := range candidates {
+		latency, ok := s.latencies.get(u)
+		if !ok {
+			continue
+		}
+
+		if latency < minLatency {
+			latency = minLatency
+		}
+
+		weights[i] = 1 / latency.Seconds()
+
+		if weights[i] > maxWeight {
+			maxWeight = weights[i]
+		}
+	}
+
+	if maxWeight == 0 {
+		// No latency history for any of the endpoints.
+		maxWeight = 1
+	}
+
+	var total float64
+
+	for i := range weights {
+		if weights[i] == 0 {
+			// Optimistic weight for an endpoint without latency history.
+			weights[i] = maxWeight
+		}
+
+		total += weights[i]
+	}
+
+	r := mrand.Float64() * total
+
+	for i, w := range weights {
+		r -= w
+
+		if r < 0 {
+			return candidates[i]
+		}
+	}
+
+	return candidates[len(candidates)-1]
+}
@@ -45,3 +45,5 @@ Feature:
 
     Then client sends request to domains "https://orb.domain1.com" to create "50" DID documents using "5" concurrent requests and a maximum of "2" attempts, storing the dids to file "./fixtures/dids.txt"
     And client sends request to domains "https://orb.domain1.com" to verify the DID documents that were created from file "./fixtures/dids.txt" with a maximum of "25" attempts
+    And client sends request to domains "https://orb.domain1.com,https://orb.domain2.com" to verify the DID documents that were created from file "./fixtures/dids.txt" with a maximum of "25" attempts using "5" concurrent requests
+    And client sends request to domains "https://orb.domain1.com,https://orb.domain2.com" to verify the DID documents from file "./fixtures/testdata/unknown_dids.txt" with a maximum of "1" attempts using "2" concurrent requests and "3" DIDs fail verification
@@ -159,6 +159,71 @@ func (c *httpClient) GetWithRetryFunc(url string, attempts int, shouldRetry func
 	return resp, nil
 }
 
+// GetStreamWithRetryFunc sends a GET request to the given URL and passes the response to the given handler, which
+// reads the response body as a stream (rather than the body being read into memory up front). The handler returns
+// true if the request should be retried along with an error describing why the response wasn't accepted. The
+// response body is closed after the handler returns. The error from the last attempt is returned.
+func (c *httpClient) GetStreamWithRetryFunc(url string, attempts int,
+	handle func(resp *http.Response) (bool, error),
+) error {
+	logger.Infof("resolving: %s", url)
+
+	remainingAttempts := attempts
+	for {
+		retry, err := c.getStream(url, handle)
+		if !retry {
+			return err
+		}
+
+		logger.Infof("%s - %s - remaining attempts: %d", err, url, remainingAttempts)
+
+		remainingAttempts--
+		if remainingAttempts == 0 {
+			return err
+		}
+
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func (c *httpClient) getStream(url string, handle func(resp *http.Response) (bool, error)) (bool, error) {
+	defer c.client.CloseIdleConnections()
+
+	httpReq, err := http.NewRequest(http.MethodGet, c.resolveURL(url), http.NoBody)
+	if err != nil {
+		return false, err
+	}
+
+	c.setAuthTokenHeader(httpReq)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Warnf("Error closing HTTP response from [%s]: %s", url, err)
+		}
+	}()
+
+	return handle(resp)
+}
+
+// Do sends the given request, applying host mappings and the auth token header.
+func (c *httpClient) Do(req *http.Request) (*http.Response, error) {
+	resolvedURL, err := req.URL.Parse(c.resolveURL(req.URL.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.URL = resolvedURL
+
+	c.setAuthTokenHeader(req)
+
+	return c.client.Do(req)
+}
+
 func (c *httpClient) Post(url string, data []byte, contentType string) (*httpResponse, error) {
 	return c.PostWithSignature(url, data, contentType, "")
 }
@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolution
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const documentMetadataField = "didDocumentMetadata"
+
+// DecodeCanonicalID stream-decodes the resolution result in the given reader and returns the canonical ID from
+// the document metadata (or an empty string if the DID isn't anchored yet). Only the document metadata is decoded.
+// The other fields (including the potentially large DID document) are skipped token by token so that memory use
+// is bounded by the size of the largest token rather than by the size of the resolution result.
+func DecodeCanonicalID(r io.Reader) (string, error) {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return "", fmt.Errorf("decode resolution result: %w", err)
+	}
+
+	var canonicalID string
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("decode resolution result: %w", err)
+		}
+
+		if t != documentMetadataField {
+			if err := skipValue(dec); err != nil {
+				return "", fmt.Errorf("decode resolution result: %w", err)
+			}
+
+			continue
+		}
+
+		var metadata struct {
+			CanonicalID string `json:"canonicalId"`
+		}
+
+		if err := dec.Decode(&metadata); err != nil {
+			return "", fmt.Errorf("decode document metadata: %w", err)
+		}
+
+		canonicalID = metadata.CanonicalID
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return "", fmt.Errorf("decode resolution result: %w", err)
+	}
+
+	return canonicalID, nil
+}
+
+func expectDelim(dec *json.Decoder, expected json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if t != expected {
+		return fmt.Errorf("expecting '%s' but got '%v'", expected, t)
+	}
+
+	return nil
+}
+
+// skipValue reads (and discards) the next value from the decoder without holding the value in memory.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+
+			return err
+		}
+
+		if delim, ok := t.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
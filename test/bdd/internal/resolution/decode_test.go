@@ -0,0 +1,126 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolution
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const canonicalID = "did:orb:uEiDJbYmWqU4Q3y9mYFzrIdI0YbSXgsnkT2LL_Q4jTY6JXQ:EiDyOQbbZAa3aiRzeCkV7LOx3SERjjH93EXoIM3UoN4oWg"
+
+func TestDecodeCanonicalID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cID, err := DecodeCanonicalID(strings.NewReader(`{"@context":"https://w3id.org/did-resolution/v1",` +
+			`"didDocument":{"id":"did:orb:uAAA:123","service":[{"id":"svc","nested":[[1,2],{"a":null}]}]},` +
+			`"didDocumentMetadata":{"canonicalId":"` + canonicalID + `","method":{"published":true}},` +
+			`"didResolutionMetadata":{}}`))
+		require.NoError(t, err)
+		require.Equal(t, canonicalID, cID)
+	})
+
+	t.Run("not anchored", func(t *testing.T) {
+		cID, err := DecodeCanonicalID(strings.NewReader(`{"didDocument":{},"didDocumentMetadata":{}}`))
+		require.NoError(t, err)
+		require.Empty(t, cID)
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+		_, err := DecodeCanonicalID(strings.NewReader(`["didDocumentMetadata"]`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting '{'")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		_, err := DecodeCanonicalID(strings.NewReader(`{"didDocument":{"id":"did:orb:uAAA:123"`))
+		require.Error(t, err)
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		_, err := DecodeCanonicalID(strings.NewReader(`{"didDocumentMetadata":{"canonicalId":123}}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode document metadata")
+	})
+}
+
+func TestDecodeCanonicalID_BoundedMemory(t *testing.T) {
+	const (
+		numResults  = 5
+		numServices = 50000 // Each DID document is roughly 5MB.
+		maxReadSize = 64 << 10
+	)
+
+	for i := 0; i < numResults; i++ {
+		r := newResolutionResultReader(numServices)
+
+		cID, err := DecodeCanonicalID(r)
+		require.NoError(t, err)
+		require.Equal(t, canonicalID, cID)
+
+		// The decoder reads into the free space of its buffer, which it only grows when a value doesn't fit.
+		// So the size of the largest read is bounded by the buffer size, which would grow to the size of the
+		// resolution result if the decoder held the whole result in memory.
+		require.Greater(t, r.size, int64(numServices*90), "the resolution result should be large")
+		require.Less(t, r.maxReadSize, maxReadSize,
+			"read of %d bytes while decoding a resolution result of %d bytes", r.maxReadSize, r.size)
+	}
+}
+
+// resolutionResultReader generates a resolution result with a large DID document on the fly (so that the test
+// itself doesn't hold the document in memory) and records the size of the largest read.
+type resolutionResultReader struct {
+	numServices int
+	next        int
+	pending     string
+	maxReadSize int
+	size        int64
+}
+
+func newResolutionResultReader(numServices int) *resolutionResultReader {
+	return &resolutionResultReader{
+		numServices: numServices,
+		next:        -1,
+	}
+}
+
+func (r *resolutionResultReader) Read(p []byte) (int, error) {
+	if len(p) > r.maxReadSize {
+		r.maxReadSize = len(p)
+	}
+
+	for r.pending == "" {
+		switch {
+		case r.next < 0:
+			r.pending = `{"@context":"https://w3id.org/did-resolution/v1","didDocument":{"id":"did:orb:uAAA:123",` +
+				`"service":[`
+		case r.next < r.numServices:
+			r.pending = fmt.Sprintf(`{"id":"#service-%08d","type":"LinkedDomains",`+
+				`"serviceEndpoint":"https://orb.domain1.com"}`, r.next)
+
+			if r.next < r.numServices-1 {
+				r.pending += ","
+			}
+		case r.next == r.numServices:
+			r.pending = `]},"didDocumentMetadata":{"canonicalId":"` + canonicalID + `"}}`
+		default:
+			return 0, io.EOF
+		}
+
+		r.next++
+	}
+
+	n := copy(p, r.pending)
+
+	r.pending = r.pending[n:]
+	r.size += int64(n)
+
+	return n, nil
+}
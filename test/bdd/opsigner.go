@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bdd
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/trustbloc/sidetree-go/pkg/jws"
+	"github.com/trustbloc/sidetree-go/pkg/util/ecsigner"
+	"github.com/trustbloc/sidetree-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/client"
+)
+
+// operationSigner signs update, recover and deactivate requests. The signing key doesn't need to be held
+// in memory so, for example, an HSM or KMS-backed signer may be used.
+type operationSigner interface {
+	client.Signer
+
+	// PublicKeyJWK returns the public key of the signing key.
+	PublicKeyJWK() (*jws.JWK, error)
+}
+
+// ecdsaOperationSigner is the default operation signer which signs with an in-memory ECDSA key.
+type ecdsaOperationSigner struct {
+	client.Signer
+
+	key *ecdsa.PrivateKey
+}
+
+func newECDSAOperationSigner(key *ecdsa.PrivateKey) *ecdsaOperationSigner {
+	return &ecdsaOperationSigner{
+		Signer: ecsigner.New(key, "ES256", ""),
+		key:    key,
+	}
+}
+
+func (s *ecdsaOperationSigner) PublicKeyJWK() (*jws.JWK, error) {
+	return pubkey.GetPublicKeyJWK(&s.key.PublicKey)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bdd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-go/pkg/jws"
+	"github.com/trustbloc/sidetree-go/pkg/jwsutil"
+	"github.com/trustbloc/sidetree-go/pkg/patch"
+	"github.com/trustbloc/sidetree-go/pkg/util/edsigner"
+	"github.com/trustbloc/sidetree-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-go/pkg/versions/1_0/model"
+)
+
+const testSuffix = "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+func TestOperationSigner(t *testing.T) {
+	t.Run("update", func(t *testing.T) {
+		signer := newMockOperationSigner(t)
+
+		ptch, err := getAddPublicKeysPatch("key1")
+		require.NoError(t, err)
+
+		reqBytes, nextUpdateKey, err := getUpdateRequest(testSuffix, signer, []patch.Patch{ptch})
+		require.NoError(t, err)
+		require.NotNil(t, nextUpdateKey)
+		require.Equal(t, 1, signer.numSigned)
+
+		req := &model.UpdateRequest{}
+		require.NoError(t, json.Unmarshal(reqBytes, req))
+
+		signedData := &model.UpdateSignedDataModel{}
+		verifySignedData(t, req.SignedData, signer, signedData)
+		require.Equal(t, signer.jwk, signedData.UpdateKey)
+	})
+
+	t.Run("recover", func(t *testing.T) {
+		signer := newMockOperationSigner(t)
+
+		reqBytes, nextRecoveryKey, nextUpdateKey, err := getRecoverRequest(testSuffix, signer,
+			[]byte(`{}`), nil, "https://orb.domain1.com")
+		require.NoError(t, err)
+		require.NotNil(t, nextRecoveryKey)
+		require.NotNil(t, nextUpdateKey)
+		require.Equal(t, 1, signer.numSigned)
+
+		req := &model.RecoverRequest{}
+		require.NoError(t, json.Unmarshal(reqBytes, req))
+
+		signedData := &model.RecoverSignedDataModel{}
+		verifySignedData(t, req.SignedData, signer, signedData)
+		require.Equal(t, signer.jwk, signedData.RecoveryKey)
+		require.Equal(t, "https://orb.domain1.com", signedData.AnchorOrigin)
+	})
+
+	t.Run("deactivate", func(t *testing.T) {
+		signer := newMockOperationSigner(t)
+
+		reqBytes, err := getDeactivateRequest(testSuffix, signer)
+		require.NoError(t, err)
+		require.Equal(t, 1, signer.numSigned)
+
+		req := &model.DeactivateRequest{}
+		require.NoError(t, json.Unmarshal(reqBytes, req))
+
+		signedData := &model.DeactivateSignedDataModel{}
+		verifySignedData(t, req.SignedData, signer, signedData)
+		require.Equal(t, signer.jwk, signedData.RecoveryKey)
+		require.Equal(t, testSuffix, signedData.DidSuffix)
+	})
+}
+
+func verifySignedData(t *testing.T, compactJWS string, signer *mockOperationSigner, signedData interface{}) {
+	t.Helper()
+
+	sig, err := jwsutil.VerifyJWS(compactJWS, signer.jwk)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(sig.Payload, signedData))
+
+	// The signature mustn't verify with a different key.
+	_, err = jwsutil.VerifyJWS(compactJWS, newMockOperationSigner(t).jwk)
+	require.Error(t, err)
+}
+
+// mockOperationSigner is an Ed25519 operation signer that counts the number of signatures.
+type mockOperationSigner struct {
+	*edsigner.Signer
+
+	jwk       *jws.JWK
+	numSigned int
+}
+
+func newMockOperationSigner(t *testing.T) *mockOperationSigner {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	jwk, err := pubkey.GetPublicKeyJWK(publicKey)
+	require.NoError(t, err)
+
+	return &mockOperationSigner{
+		Signer: edsigner.New(privateKey, "EdDSA", ""),
+		jwk:    jwk,
+	}
+}
+
+func (s *mockOperationSigner) Sign(data []byte) ([]byte, error) {
+	s.numSigned++
+
+	return s.Signer.Sign(data)
+}
+
+func (s *mockOperationSigner) PublicKeyJWK() (*jws.JWK, error) {
+	return s.jwk, nil
+}
@@ -37,7 +37,7 @@ type state struct {
 	vars              map[string]string
 	responseValue     string
 	authTokenMap      map[httpPath]map[httpMethod]authToken
-	anchorOrigins     map[string]string
+	anchorOrigins     *anchorOriginConfig
 }
 
 func newState() *state {
@@ -53,7 +53,7 @@ func newState() *state {
 		vars:              vars,
 		authTokenMap:      make(map[httpPath]map[httpMethod]authToken),
 		dockerComposeFile: "docker-compose.yml",
-		anchorOrigins:     make(map[string]string),
+		anchorOrigins:     newAnchorOriginConfig(),
 	}
 }
 
@@ -69,7 +69,7 @@ func (s *state) clear() {
 
 	s.authTokenMap = make(map[httpPath]map[httpMethod]authToken)
 	s.responseValue = ""
-	s.anchorOrigins = make(map[string]string)
+	s.anchorOrigins = newAnchorOriginConfig()
 }
 
 // clearResponse clears the query response
@@ -406,27 +406,11 @@ func (s *state) evaluateGetDomainFunc(expression, prefix string) (string, error)
 }
 
 func (s *state) setAnchorOrigin(host, anchorOrigin string) {
-	s.anchorOrigins[host] = anchorOrigin
+	s.anchorOrigins.set(host, anchorOrigin)
 }
 
 func (s *state) getAnchorOrigin(strUrl string) (string, error) {
-	u, err := url.Parse(strUrl)
-	if err != nil {
-		return "", err
-	}
-
-	domain := u.Host
-
-	origin, ok := s.anchorOrigins[domain]
-	if !ok {
-		origin = fmt.Sprintf("%s://%s", u.Scheme, u.Host)
-
-		logger.Infof("Anchor origin not configured for %s. Using %s", domain, origin)
-	} else {
-		logger.Infof("Using anchor origin %s for %s", origin, domain)
-	}
-
-	return origin, nil
+	return s.anchorOrigins.resolve(strUrl)
 }
 
 func getDomainFromDID(id string) (string, error) {
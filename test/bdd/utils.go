@@ -9,10 +9,13 @@ package bdd
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"sync"
 	"time"
 )
 
@@ -72,17 +75,49 @@ func readZipFile(zf *zip.File) ([]byte, error) {
 type greylist struct {
 	entries map[string]time.Time
 	backoff time.Duration
+	file    string
 }
 
-func newGreylist(backoff time.Duration) *greylist {
-	return &greylist{
+// greylistFileMutex serializes access to greylist files since a greylist is created per request.
+var greylistFileMutex sync.Mutex
+
+type greylistOpt func(g *greylist)
+
+// withGreylistFile persists the greylist to the given file so that greylisted endpoints survive a restart.
+// Entries whose greylist period has expired are dropped when the file is loaded.
+func withGreylistFile(file string) greylistOpt {
+	return func(g *greylist) {
+		g.file = file
+	}
+}
+
+func newGreylist(backoff time.Duration, opts ...greylistOpt) *greylist {
+	g := &greylist{
 		entries: make(map[string]time.Time),
 		backoff: backoff,
 	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if g.file != "" {
+		if err := g.load(); err != nil {
+			logger.Warnf("Unable to load greylist from %s: %s", g.file, err)
+		}
+	}
+
+	return g
 }
 
 func (g *greylist) Add(u string) {
 	g.entries[u] = time.Now().Add(g.backoff)
+
+	if g.file != "" {
+		if err := g.save(); err != nil {
+			logger.Warnf("Unable to save greylist to %s: %s", g.file, err)
+		}
+	}
 }
 
 func (g *greylist) IsGreylisted(u string) bool {
@@ -99,3 +134,70 @@ func (g *greylist) IsGreylisted(u string) bool {
 
 	return false
 }
+
+func (g *greylist) load() error {
+	greylistFileMutex.Lock()
+	defer greylistFileMutex.Unlock()
+
+	entries, err := readGreylistFile(g.file)
+	if err != nil {
+		return err
+	}
+
+	for u, expiry := range entries {
+		g.entries[u] = expiry
+	}
+
+	return nil
+}
+
+// save merges the greylist with the entries in the file (which may have been added by other requests)
+// and writes the result back to the file.
+func (g *greylist) save() error {
+	greylistFileMutex.Lock()
+	defer greylistFileMutex.Unlock()
+
+	entries, err := readGreylistFile(g.file)
+	if err != nil {
+		return err
+	}
+
+	for u, expiry := range g.entries {
+		entries[u] = expiry
+	}
+
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(g.file, b, 0o600)
+}
+
+// readGreylistFile returns the unexpired entries in the given greylist file.
+func readGreylistFile(file string) (map[string]time.Time, error) {
+	entries := make(map[string]time.Time)
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entries, nil
+		}
+
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	for u, expiry := range entries {
+		if !now.Before(expiry) {
+			delete(entries, u)
+		}
+	}
+
+	return entries, nil
+}
@@ -23,16 +23,23 @@ type Response[T any] struct {
 	Err  error
 }
 
-// WorkerPool manages a pool of workers that processes requests concurrently and, at the end, gathers the responses
+// ResponseHandler handles an individual response. The handler is invoked from a single goroutine so it doesn't
+// need to be thread safe.
+type ResponseHandler[T any] func(resp *Response[T])
+
+// WorkerPool manages a pool of workers that processes requests concurrently and either gathers the responses
+// or passes each response to a handler as soon as it's received
 type WorkerPool[T any] struct {
 	*workerPoolOptions
 
-	workers   []*worker[T]
-	reqChan   chan Request[T]
-	respChan  chan *Response[T]
-	wgResp    sync.WaitGroup
-	wg        *sync.WaitGroup
-	responses []*Response[T]
+	workers      []*worker[T]
+	reqChan      chan Request[T]
+	respChan     chan *Response[T]
+	wgResp       sync.WaitGroup
+	wg           *sync.WaitGroup
+	handle       ResponseHandler[T]
+	numResponses int
+	responses    []*Response[T]
 }
 
 type workerPoolOptions struct {
@@ -47,8 +54,25 @@ func WithTaskDescription(desc string) Opt {
 	}
 }
 
-// NewWorkerPool returns a new worker pool with the given number of workers
+// NewWorkerPool returns a new worker pool with the given number of workers. The responses are gathered
+// and may be retrieved with Responses after the pool is stopped.
 func NewWorkerPool[T any](num int, opts ...Opt) *WorkerPool[T] {
+	p := newWorkerPool[T](num, opts...)
+	p.handle = p.addResponse
+
+	return p
+}
+
+// NewStreamingWorkerPool returns a new worker pool with the given number of workers. Each response is passed
+// to the given handler as soon as it's received and isn't retained by the pool, so Responses always returns nil.
+func NewStreamingWorkerPool[T any](num int, handler ResponseHandler[T], opts ...Opt) *WorkerPool[T] {
+	p := newWorkerPool[T](num, opts...)
+	p.handle = handler
+
+	return p
+}
+
+func newWorkerPool[T any](num int, opts ...Opt) *WorkerPool[T] {
 	options := &workerPoolOptions{}
 
 	for _, opt := range opts {
@@ -116,15 +140,26 @@ func (p *WorkerPool[T]) Responses() []*Response[T] {
 	return p.responses
 }
 
+// NumResponses returns the number of responses that were received after the pool is stopped
+func (p *WorkerPool[T]) NumResponses() int {
+	return p.numResponses
+}
+
+func (p *WorkerPool[T]) addResponse(resp *Response[T]) {
+	p.responses = append(p.responses, resp)
+}
+
 func (p *WorkerPool[T]) listen() {
 	for resp := range p.respChan {
-		p.responses = append(p.responses, resp)
+		p.handle(resp)
+
+		p.numResponses++
 
-		if len(p.responses)%100 == 0 {
+		if p.numResponses%100 == 0 {
 			if p.taskDescription != "" {
-				logger.Debugf("Got %d responses for task [%s]", len(p.responses), p.taskDescription)
+				logger.Debugf("Got %d responses for task [%s]", p.numResponses, p.taskDescription)
 			} else {
-				logger.Debugf("Got %d responses", len(p.responses))
+				logger.Debugf("Got %d responses", p.numResponses)
 			}
 		}
 	}
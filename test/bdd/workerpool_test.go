@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bdd
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool(t *testing.T) {
+	const (
+		numWorkers  = 4
+		numRequests = 250
+	)
+
+	t.Run("gather responses", func(t *testing.T) {
+		p := NewWorkerPool[int](numWorkers, WithTaskDescription("gather"))
+
+		p.Start()
+
+		for i := 0; i < numRequests; i++ {
+			p.Submit(newTestRequest(i, nil))
+		}
+
+		p.Stop()
+
+		require.Equal(t, numRequests, p.NumResponses())
+		require.Len(t, p.Responses(), numRequests)
+
+		sum := 0
+
+		for _, resp := range p.Responses() {
+			require.NoError(t, resp.Err)
+
+			sum += resp.Resp
+		}
+
+		require.Equal(t, numRequests*(numRequests-1)/2, sum)
+	})
+
+	t.Run("stream responses", func(t *testing.T) {
+		errExpected := errors.New("injected error")
+
+		var (
+			numInFlight int32
+			maxInFlight int32
+			numHandled  int
+			numErrors   int
+		)
+
+		p := NewStreamingWorkerPool[int](numWorkers,
+			func(resp *Response[int]) {
+				atomic.AddInt32(&numInFlight, -1)
+
+				numHandled++
+
+				if resp.Err != nil {
+					require.ErrorIs(t, resp.Err, errExpected)
+
+					numErrors++
+				}
+			},
+		)
+
+		p.Start()
+
+		for i := 0; i < numRequests; i++ {
+			var err error
+
+			if i%10 == 0 {
+				err = errExpected
+			}
+
+			// The number of requests that were submitted but not yet handled is bounded by the number of workers,
+			// plus the response being received by the handler and the request being submitted.
+			if n := atomic.AddInt32(&numInFlight, 1); n > maxInFlight {
+				maxInFlight = n
+			}
+
+			p.Submit(newTestRequest(i, err))
+		}
+
+		p.Stop()
+
+		require.Equal(t, numRequests, numHandled)
+		require.Equal(t, numRequests/10, numErrors)
+		require.Equal(t, numRequests, p.NumResponses())
+		require.Nil(t, p.Responses())
+		require.LessOrEqual(t, int(maxInFlight), numWorkers+2)
+	})
+}
+
+type testRequest struct {
+	value int
+	err   error
+}
+
+func newTestRequest(value int, err error) *testRequest {
+	return &testRequest{value: value, err: err}
+}
+
+func (r *testRequest) Invoke() (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	return r.value, nil
+}
+
+func (r *testRequest) URL() string {
+	return fmt.Sprintf("https://example.com/%d", r.value)
+}